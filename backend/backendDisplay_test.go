@@ -2,6 +2,8 @@ package backend_test
 
 import (
 	"net/url"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
@@ -10,6 +12,7 @@ import (
 	"gosynctasks/backend/git"
 	"gosynctasks/backend/nextcloud"
 	"gosynctasks/backend/sqlite"
+	"gosynctasks/internal/utils"
 )
 
 // Helper function to parse URLs in tests
@@ -254,3 +257,132 @@ func TestTaskList_StringWithWidthAndBackend_Truncation(t *testing.T) {
 		t.Errorf("StringWithWidthAndBackend() should not be empty")
 	}
 }
+
+// TestTaskList_HeaderEscapesControlCharacters tests that remote list names cannot
+// inject escape sequences or tabs into the header
+func TestTaskList_HeaderEscapesControlCharacters(t *testing.T) {
+	taskList := backend.TaskList{
+		ID:          "list-1",
+		Name:        "Evil\x1b[2J\tList",
+		Description: "ring\a",
+	}
+
+	output := taskList.StringWithWidth(80)
+
+	if !strings.Contains(output, `Evil\x1b[2J List - ring\x07`) {
+		t.Errorf("StringWithWidth() should show escaped name and description, got: %q", output)
+	}
+	if strings.Contains(output, "\x1b[2J") || strings.ContainsAny(output, "\t\a") {
+		t.Errorf("StringWithWidth() should not contain raw control characters, got: %q", output)
+	}
+}
+
+// TestTask_FormatEscapesControlCharacters tests the default task line rendering
+func TestTask_FormatEscapesControlCharacters(t *testing.T) {
+	task := backend.Task{Summary: "Pay\x1b]0;pwned\a rent", Description: "a\x1b[0mb", Status: "NEEDS-ACTION"}
+
+	output := task.FormatWithView("default", nil, "2006-01-02")
+
+	if !strings.Contains(output, `Pay\x1b]0;pwned\x07 rent`) || !strings.Contains(output, `a\x1b[0mb`) {
+		t.Errorf("FormatWithView() should escape control characters, got: %q", output)
+	}
+}
+
+// TestTaskList_HeaderGeometryGolden renders headers at several widths, with
+// wide characters in the title, and checks that every line spans the same
+// columns as the bottom border with and without colors. The colored output
+// is compared with testdata/golden/list_header.golden; set UPDATE_GOLDEN=1 to
+// rewrite it.
+func TestTaskList_HeaderGeometryGolden(t *testing.T) {
+	sbBackend := &sqlite.SQLiteBackend{Config: backend.BackendConfig{DBPath: "/home/user/tasks.db"}}
+	lists := []backend.TaskList{
+		{Name: "Work"},
+		{Name: "Café 日本語", Description: "Tâches ✓ 🚀"},
+		{Name: "A Very Long Task List Name That Should Be Truncated", Description: "With an even longer description that won't fit"},
+	}
+
+	var sb strings.Builder
+	for _, width := range []int{30, 50, 80, 120} {
+		bottom := utils.DisplayWidth(lists[0].BottomBorderWithWidth(width))
+		for _, list := range lists {
+			for _, header := range []string{list.StringWithWidth(width), list.StringWithWidthAndBackend(width, sbBackend)} {
+				header = strings.Trim(header, "\n")
+				plain := utils.StripANSI(header)
+				if got := utils.DisplayWidth(header); got != bottom || utils.DisplayWidth(plain) != bottom {
+					t.Errorf("width %d: header %q spans %d columns, want %d like the bottom border", width, plain, got, bottom)
+				}
+				if !strings.HasPrefix(plain, "┌") || !strings.HasSuffix(plain, "┐") {
+					t.Errorf("width %d: header %q is not closed by its corners", width, plain)
+				}
+				sb.WriteString(header + "\n")
+			}
+		}
+	}
+	got := sb.String()
+
+	path := filepath.Join("testdata", "golden", "list_header.golden")
+	if os.Getenv("UPDATE_GOLDEN") != "" {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(got), 0644); err != nil {
+			t.Fatalf("Failed to write golden file: %v", err)
+		}
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read golden file: %v", err)
+	}
+	if got != string(want) {
+		t.Errorf("Output does not match %s\ngot:\n%s\nwant:\n%s", path, got, want)
+	}
+}
+
+// pendingBackend is a sync cache with queued changes
+type pendingBackend struct {
+	*backend.MockBackend
+	counts map[string]backend.PendingSync
+}
+
+func (b pendingBackend) CountPendingSync() (map[string]backend.PendingSync, error) {
+	return b.counts, nil
+}
+
+// TestTaskList_HeaderPendingSync tests the marker of lists with changes not
+// pushed yet: yellow, red when some keep failing, and absent without any
+func TestTaskList_HeaderPendingSync(t *testing.T) {
+	mb := backend.NewMockBackend()
+	mb.Name = "cache"
+	tm := pendingBackend{mb, map[string]backend.PendingSync{
+		"work":  {Operations: 3},
+		"home":  {Operations: 1, Failing: true},
+		"empty": {},
+	}}
+
+	tests := []struct {
+		listID string
+		want   string
+	}{
+		{"work", " \033[33m↑3 pending\033[0m\033[1;36m [mock:cache]┐"},
+		{"home", " \033[31m↑1 pending\033[0m\033[1;36m [mock:cache]┐"},
+		{"empty", "─[mock:cache]┐"},
+		{"other", "─[mock:cache]┐"},
+	}
+	for _, tt := range tests {
+		list := backend.TaskList{ID: tt.listID, Name: "Tasks"}
+		header := list.StringWithWidthAndBackend(80, tm)
+		if !strings.Contains(header, tt.want) {
+			t.Errorf("list %s: header %q does not end with %q", tt.listID, header, tt.want)
+		}
+		if got, want := utils.DisplayWidth(strings.Trim(header, "\n")), utils.DisplayWidth(list.BottomBorderWithWidth(80)); got != want {
+			t.Errorf("list %s: header spans %d columns, want %d", tt.listID, got, want)
+		}
+	}
+
+	// Without room for the marker, the backend info stays
+	mb.Name = "nextcloud-cache"
+	list := backend.TaskList{ID: "work", Name: "Tasks"}
+	if header := utils.StripANSI(list.StringWithWidthAndBackend(30, tm)); strings.Contains(header, "pending") || !strings.Contains(header, "[mock:nextcloud-cache]") {
+		t.Errorf("narrow header = %q, want the backend info without the marker", header)
+	}
+}
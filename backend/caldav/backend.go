@@ -0,0 +1,1351 @@
+package caldav
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"gosynctasks/backend"
+	"gosynctasks/backend/statuskit"
+	"gosynctasks/internal/credentials"
+	"gosynctasks/internal/ical"
+)
+
+func init() {
+	// Register the CalDAV backend for config type "caldav"
+	backend.RegisterType("caldav", func(bc backend.BackendConfig) (backend.TaskManager, error) {
+		return NewFromBackendConfig(bc, true)
+	})
+}
+
+// Backend is a task backend on a CalDAV server. Its calendars are at
+// Nextcloud's /remote.php/dav/calendars/<user>/ unless Discover is set, when
+// the calendar home is found from the server's well-known URL.
+type Backend struct {
+	Connector      backend.ConnectorConfig
+	BackendName    string // Backend name for credential resolution
+	ConfigHost     string // Host from config (for credential resolution)
+	ConfigUsername string // Username from config (for credential resolution)
+
+	// Discover finds the calendar home by RFC 6764 discovery, for servers
+	// other than Nextcloud; the backend is then of type caldav
+	Discover bool
+
+	// connMu guards the connection settings below, set on first use
+	connMu   sync.Mutex
+	username string
+	password string
+	baseURL  string
+	client   *http.Client
+
+	// homeMu guards homeURL, the discovered calendar home; a failed
+	// discovery is tried again by the next request
+	homeMu  sync.Mutex
+	homeURL string
+
+	// fetchedUIDs records VTODO UIDs seen by GetTasks per list with their
+	// ETags, so deletes of tasks fetched in the same operation can skip
+	// resource verification, and writes can send If-Match
+	fetchedMu   sync.Mutex
+	fetchedUIDs map[string]map[string]string
+}
+
+func (b *Backend) getClient() *http.Client {
+	b.connMu.Lock()
+	defer b.connMu.Unlock()
+	if b.client == nil {
+		b.client = &http.Client{
+			Transport: &http.Transport{
+				TLSClientConfig:     &tls.Config{InsecureSkipVerify: b.Connector.InsecureSkipVerify},
+				MaxIdleConns:        10,
+				MaxIdleConnsPerHost: 4, // As many as the pushes sent at once by default
+				IdleConnTimeout:     30 * time.Second,
+			},
+			Timeout: 30 * time.Second,
+		}
+	}
+	return b.client
+}
+
+func (b *Backend) getUsername() string {
+	b.connMu.Lock()
+	defer b.connMu.Unlock()
+	if b.username == "" {
+		// Try credential resolver first (keyring > env > URL)
+		if b.BackendName != "" {
+			resolver := credentials.NewResolver()
+			creds, err := resolver.Resolve(b.BackendName, b.ConfigUsername, b.ConfigHost, b.Connector.URL)
+			if err == nil && creds.Username != "" {
+				b.username = creds.Username
+				b.password = creds.Password // Cache both
+				return b.username
+			}
+		}
+
+		// Fallback to URL (backward compatible)
+		if b.Connector.URL != nil && b.Connector.URL.User != nil {
+			b.username = b.Connector.URL.User.Username()
+		}
+	}
+	return b.username
+}
+
+func (b *Backend) getPassword() string {
+	b.connMu.Lock()
+	defer b.connMu.Unlock()
+	if b.password == "" {
+		// Try credential resolver first
+		if b.BackendName != "" {
+			resolver := credentials.NewResolver()
+			creds, err := resolver.Resolve(b.BackendName, b.ConfigUsername, b.ConfigHost, b.Connector.URL)
+			if err == nil && creds.Password != "" {
+				b.password = creds.Password
+				b.username = creds.Username // Cache both
+				return b.password
+			}
+		}
+
+		// Fallback to URL (backward compatible)
+		if b.Connector.URL != nil && b.Connector.URL.User != nil {
+			b.password, _ = b.Connector.URL.User.Password()
+		}
+	}
+	return b.password
+}
+
+func (b *Backend) getBaseURL() string {
+	b.connMu.Lock()
+	defer b.connMu.Unlock()
+	if b.baseURL == "" {
+		if b.Connector.URL != nil {
+			// SECURITY: Always use HTTPS by default
+			// HTTP is only allowed if explicitly configured via AllowHTTP flag
+			protocol := "https"
+			if b.usesHTTP() {
+				protocol = "http"
+			}
+
+			b.baseURL = fmt.Sprintf("%s://%s", protocol, b.Connector.URL.Host)
+		}
+	}
+	return b.baseURL
+}
+
+// usesHTTP reports whether the server is reached over plain HTTP, which
+// takes AllowHTTP: for Nextcloud when the port is 80, 8000 or 8080, for
+// other servers when the URL is an http one
+func (b *Backend) usesHTTP() bool {
+	if !b.Connector.AllowHTTP || b.Connector.URL == nil {
+		return false
+	}
+	if b.Discover {
+		return b.Connector.URL.Scheme == "http"
+	}
+	host := b.Connector.URL.Host
+	return strings.Contains(host, ":80") || strings.Contains(host, ":8080") || strings.Contains(host, ":8000")
+}
+
+// buildCalendarURL returns the URL of the calendar home, the collection
+// holding the calendars
+func (b *Backend) buildCalendarURL() (string, error) {
+	if !b.Discover {
+		return fmt.Sprintf("%s/remote.php/dav/calendars/%s/", b.getBaseURL(), b.getUsername()), nil
+	}
+
+	b.homeMu.Lock()
+	defer b.homeMu.Unlock()
+	if b.homeURL == "" {
+		home, err := b.discoverCalendarHome()
+		if err != nil {
+			return "", err
+		}
+		b.homeURL = home
+	}
+	return b.homeURL, nil
+}
+
+// buildListURL constructs the CalDAV URL for a specific task list
+func (b *Backend) buildListURL(listID string) (string, error) {
+	home, err := b.buildCalendarURL()
+	if err != nil {
+		return "", err
+	}
+	return home + listID + "/", nil
+}
+
+// buildTaskURL constructs the CalDAV URL for a specific task
+func (b *Backend) buildTaskURL(listID, taskUID string) (string, error) {
+	home, err := b.buildCalendarURL()
+	if err != nil {
+		return "", err
+	}
+	return home + listID + "/" + taskUID + ".ics", nil
+}
+
+// DescribeRequests returns the CalDAV requests a write of task makes
+func (b *Backend) DescribeRequests(method, listID string, task backend.Task) []string {
+	uid := task.UID
+	if uid == "" || strings.HasPrefix(uid, "pending-") {
+		uid = "<new UID>"
+	}
+	taskURL, err := b.buildTaskURL(listID, uid)
+	if err != nil {
+		return nil
+	}
+	switch method {
+	case "AddTask", "UpdateTask":
+		return []string{"PUT " + taskURL}
+	case "DeleteTask":
+		if b.wasFetched(listID, task.UID) {
+			return []string{"DELETE " + taskURL}
+		}
+		return []string{"GET " + taskURL, "DELETE " + taskURL}
+	case "ForceDeleteTask":
+		return []string{"DELETE " + taskURL}
+	}
+	return nil
+}
+
+// makeAuthenticatedRequest creates and executes an authenticated HTTP request
+func (b *Backend) makeAuthenticatedRequest(method, url string, body io.Reader, headers map[string]string) (*http.Response, error) {
+	req, err := http.NewRequest(method, url, body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	// Set basic auth
+	req.SetBasicAuth(b.getUsername(), b.getPassword())
+
+	// Set custom headers
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	// Send request
+	client := b.getClient()
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+
+	return resp, nil
+}
+
+// checkHTTPResponse checks HTTP response status and returns appropriate errors
+func (b *Backend) checkHTTPResponse(resp *http.Response, operation string, allowedStatuses ...int) error {
+	// If specific statuses are allowed, check against them
+	if len(allowedStatuses) > 0 {
+		for _, status := range allowedStatuses {
+			if resp.StatusCode == status {
+				return nil
+			}
+		}
+	} else {
+		// Default: allow 2xx status codes
+		if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+			return nil
+		}
+	}
+
+	// Read response body for error details
+	body, _ := io.ReadAll(resp.Body)
+
+	// Common error cases
+	switch resp.StatusCode {
+	case 401, 403:
+		return backend.NewBackendError(operation, resp.StatusCode, "Authentication failed. Please check your username and password in the config file").
+			WithBody(string(body))
+	case 404:
+		return backend.NewBackendError(operation, resp.StatusCode, "Resource not found. Please check your configuration").
+			WithBody(string(body))
+	case 405:
+		return backend.NewBackendError(operation, resp.StatusCode, "Operation not allowed or resource already exists").
+			WithBody(string(body))
+	default:
+		return backend.NewBackendError(operation, resp.StatusCode, resp.Status).
+			WithBody(string(body))
+	}
+}
+
+// buildCalendarQuery builds the REPORT body for GetTasks. Sibling prop-filters
+// are ANDed (RFC 4791 section 9.7), so only a single status can be expressed
+// on the server; multiple statuses are left to the client-side filter in GetTasks.
+func (b *Backend) buildCalendarQuery(filter *backend.TaskFilter) string {
+	query := `<?xml version="1.0" encoding="utf-8" ?>
+<c:calendar-query xmlns:d="DAV:" xmlns:c="urn:ietf:params:xml:ns:caldav">
+  <d:prop>
+    <d:getetag />
+    <c:calendar-data />
+  </d:prop>
+  <c:filter>
+    <c:comp-filter name="VCALENDAR">
+      <c:comp-filter name="VTODO">`
+
+	if filter != nil {
+		// Statuses are already in CalDAV format from BuildFilter
+		if filter.Statuses != nil && len(*filter.Statuses) == 1 {
+			status := (*filter.Statuses)[0]
+			if status == "NEEDS-ACTION" {
+				// Open tasks may omit STATUS, so match on the missing completion time
+				query += `
+        <c:prop-filter name="COMPLETED">
+          <c:is-not-defined/>
+        </c:prop-filter>`
+			} else {
+				query += fmt.Sprintf(`
+        <c:prop-filter name="STATUS">
+          <c:text-match collation="i;octet"><![CDATA[%s]]></c:text-match>
+        </c:prop-filter>`, status)
+			}
+		}
+
+		// A prop-filter holds at most one time-range, carrying both bounds
+		if filter.DueAfter != nil || filter.DueBefore != nil {
+			timeRange := ""
+			if filter.DueAfter != nil {
+				timeRange += fmt.Sprintf(` start="%s"`, filter.DueAfter.UTC().Format("20060102T150405Z"))
+			}
+			if filter.DueBefore != nil {
+				timeRange += fmt.Sprintf(` end="%s"`, filter.DueBefore.UTC().Format("20060102T150405Z"))
+			}
+			query += fmt.Sprintf(`
+        <c:prop-filter name="DUE">
+          <c:time-range%s/>
+        </c:prop-filter>`, timeRange)
+		}
+
+		// A substring match (RFC 4791 section 9.7.5), unicode-casemap ignoring case
+		if filter.SummaryContains != nil && *filter.SummaryContains != "" {
+			var text strings.Builder
+			_ = xml.EscapeText(&text, []byte(*filter.SummaryContains))
+			query += fmt.Sprintf(`
+        <c:prop-filter name="SUMMARY">
+          <c:text-match collation="i;unicode-casemap">%s</c:text-match>
+        </c:prop-filter>`, text.String())
+		}
+	}
+
+	query += `
+      </c:comp-filter>
+    </c:comp-filter>
+  </c:filter>
+</c:calendar-query>`
+
+	return query
+}
+
+func (b *Backend) GetTasks(listID string, taskFilter *backend.TaskFilter) ([]backend.Task, error) {
+	// Credentials can come from URL, keyring, or environment variables
+	// Only require URL.User if we're not using keyring/env (i.e., no BackendName)
+	if b.BackendName == "" && b.Connector.URL.User == nil {
+		return nil, fmt.Errorf("no user credentials in URL and no backend name for credential resolution")
+	}
+
+	// Build request body
+	queryBody := b.buildCalendarQuery(taskFilter)
+
+	// Make authenticated request
+	headers := map[string]string{
+		"Content-Type": "application/xml",
+		"Depth":        "1",
+	}
+	listURL, err := b.buildListURL(listID)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := b.makeAuthenticatedRequest("REPORT", listURL, strings.NewReader(queryBody), headers)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	// Check response status
+	if err := b.checkHTTPResponse(resp, "GetTasks"); err != nil {
+		return nil, err
+	}
+
+	// Parse response
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	tasks, err := b.parseVTODOs(string(respBody))
+	if err != nil {
+		return nil, err
+	}
+
+	b.rememberFetched(listID, tasks)
+
+	if taskFilter == nil {
+		return tasks, nil
+	}
+
+	// Apply status, modified/completed-time, parent, priority, overdue and
+	// summary filters client-side: the query can only narrow by one status and
+	// not exclude any, LAST-MODIFIED, RELATED-TO and PRIORITY prop-filters are
+	// not reliably supported across servers, and some servers match SUMMARY
+	// text exactly rather than as a substring
+	filtered := make([]backend.Task, 0, len(tasks))
+	for _, task := range tasks {
+		status := task.Status
+		if status == "" {
+			status = "NEEDS-ACTION" // RFC 5545 default
+		}
+		if !taskFilter.MatchesStatus(status) || !taskFilter.MatchesModified(task.Modified) ||
+			!taskFilter.MatchesCompleted(task.Completed) || !taskFilter.MatchesParent(task.ParentUID) ||
+			!taskFilter.MatchesPriority(task.Priority) || !taskFilter.MatchesOverdue(status, task.DueDate, task.AllDay) ||
+			!taskFilter.MatchesSummary(task.Summary) {
+			continue
+		}
+		filtered = append(filtered, task)
+	}
+
+	return filtered, nil
+}
+
+func (b *Backend) FindTasksBySummary(listID string, summary string) ([]backend.Task, error) {
+	// The server narrows the tasks down; a server refusing the text-match gets
+	// the whole list asked for instead
+	allTasks, err := b.GetTasks(listID, &backend.TaskFilter{SummaryContains: &summary})
+	var backendErr *backend.BackendError
+	if errors.As(err, &backendErr) && slices.Contains([]int{400, 412, 501}, backendErr.StatusCode) {
+		allTasks, err = b.GetTasks(listID, nil)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Filter by summary (case-insensitive partial match), as servers may not
+	// have matched a substring
+	var matches []backend.Task
+	summaryLower := strings.ToLower(summary)
+
+	for _, task := range allTasks {
+		taskSummaryLower := strings.ToLower(task.Summary)
+
+		// Include both exact and partial matches
+		if strings.Contains(taskSummaryLower, summaryLower) {
+			matches = append(matches, task)
+		}
+	}
+	backend.ExactSummaryMatchesFirst(matches, summary)
+
+	return matches, nil
+}
+
+// GetTask fetches one task with a GET of its calendar object
+func (b *Backend) GetTask(listID, taskUID string) (*backend.Task, error) {
+	if err := backend.ValidateTaskRef("GetTask", listID, taskUID); err != nil {
+		return nil, err
+	}
+
+	taskURL, err := b.buildTaskURL(listID, taskUID)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := b.makeAuthenticatedRequest("GET", taskURL, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode == 404 {
+		return nil, backend.NewBackendError("GetTask", 404, "task not found").
+			WithTaskUID(taskUID).
+			WithListID(listID)
+	}
+	if err := b.checkHTTPResponse(resp, "GetTask", 200); err != nil {
+		if backendErr, ok := err.(*backend.BackendError); ok {
+			return nil, backendErr.WithTaskUID(taskUID).WithListID(listID)
+		}
+		return nil, err
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	for _, block := range extractVTODOBlocks(ical.Unfold(string(body))) {
+		if task, err := parseVTODO(block); err == nil && task.UID == taskUID {
+			task.ETag = resp.Header.Get("ETag")
+			b.rememberFetched(listID, []backend.Task{task})
+			return &task, nil
+		}
+	}
+
+	return nil, backend.NewBackendError("GetTask", 404, "resource does not contain the task").
+		WithTaskUID(taskUID).
+		WithListID(listID)
+}
+
+// taskListsPropfind asks only for the properties a task list is built from
+const taskListsPropfind = `<?xml version="1.0" encoding="utf-8" ?>
+<d:propfind xmlns:d="DAV:" xmlns:cs="http://calendarserver.org/ns/" xmlns:c="urn:ietf:params:xml:ns:caldav" xmlns:ic="http://apple.com/ns/ical/">
+  <d:prop>
+    <d:resourcetype />
+    <d:displayname />
+    <cs:getctag />
+    <d:sync-token />
+    <c:supported-calendar-component-set />
+    <ic:calendar-color />
+  </d:prop>
+</d:propfind>`
+
+func (b *Backend) GetTaskLists() ([]backend.TaskList, error) {
+	calendarURL, err := b.buildCalendarURL()
+	if err != nil {
+		return nil, err
+	}
+
+	// Make authenticated request; with return=minimal the server leaves out
+	// the properties a collection doesn't have, which most of the calendar
+	// home's non-calendar collections are made of
+	headers := map[string]string{
+		"Content-Type": "application/xml",
+		"Depth":        "1",
+		"Prefer":       "return=minimal",
+	}
+	resp, err := b.makeAuthenticatedRequest("PROPFIND", calendarURL, strings.NewReader(taskListsPropfind), headers)
+	if err != nil {
+		return nil, fmt.Errorf("HTTP request failed: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	// Check response status
+	if err := b.checkHTTPResponse(resp, "GetTaskLists"); err != nil {
+		return nil, err
+	}
+
+	// Parse response
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	return b.parseTaskLists(string(respBody), calendarURL)
+}
+
+func (b *Backend) GetDeletedTaskLists() ([]backend.TaskList, error) {
+	// Only Nextcloud keeps deleted calendars in a trash
+	if b.Discover {
+		return []backend.TaskList{}, nil
+	}
+
+	calendarURL, err := b.buildCalendarURL()
+	if err != nil {
+		return nil, err
+	}
+
+	// Build request body (same as GetTaskLists)
+	propfindBody := `<?xml version="1.0" encoding="utf-8" ?>
+<d:propfind xmlns:d="DAV:" xmlns:cs="http://calendarserver.org/ns/" xmlns:c="urn:ietf:params:xml:ns:caldav" xmlns:ic="http://apple.com/ns/ical/" xmlns:nc="http://nextcloud.com/ns">
+  <d:prop>
+    <d:resourcetype />
+    <d:displayname />
+    <cs:getctag />
+    <c:supported-calendar-component-set />
+    <ic:calendar-color />
+    <nc:deleted-at />
+  </d:prop>
+</d:propfind>`
+
+	// Make authenticated request
+	headers := map[string]string{
+		"Content-Type": "application/xml",
+		"Depth":        "1",
+	}
+	resp, err := b.makeAuthenticatedRequest("PROPFIND", calendarURL, strings.NewReader(propfindBody), headers)
+	if err != nil {
+		return nil, fmt.Errorf("HTTP request failed: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	// Check response status
+	if err := b.checkHTTPResponse(resp, "GetDeletedTaskLists"); err != nil {
+		return nil, err
+	}
+
+	// Parse response
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	return b.parseDeletedTaskLists(string(respBody), calendarURL)
+}
+
+// generateTaskUID returns a UID for a new task; the random part keeps tasks
+// added within the same second apart
+func generateTaskUID() string {
+	randomBytes := make([]byte, 4)
+	_, _ = rand.Read(randomBytes)
+	return fmt.Sprintf("task-%d-%s", time.Now().Unix(), hex.EncodeToString(randomBytes))
+}
+
+func (b *Backend) AddTask(listID string, task backend.Task) (string, error) {
+	// Set defaults
+	if task.UID == "" || strings.HasPrefix(task.UID, "pending-") {
+		// Generate a new UID if empty or if it's a pending UID from cache
+		task.UID = generateTaskUID()
+	}
+	if err := backend.ValidateTaskRef("AddTask", listID, task.UID); err != nil {
+		return "", err
+	}
+	if task.Created.IsZero() {
+		task.Created = time.Now()
+	}
+	if task.Status == "" {
+		task.Status = "NEEDS-ACTION"
+	}
+
+	// Build the iCalendar content
+	icalContent := b.buildICalContent(task)
+
+	// Make authenticated request
+	headers := map[string]string{
+		"Content-Type": "text/calendar; charset=utf-8",
+	}
+	taskURL, err := b.buildTaskURL(listID, task.UID)
+	if err != nil {
+		return "", err
+	}
+	resp, err := b.makeAuthenticatedRequest("PUT", taskURL, bytes.NewBufferString(icalContent), headers)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	// Check response status
+	if err := b.checkHTTPResponse(resp, "AddTask"); err != nil {
+		if backendErr, ok := err.(*backend.BackendError); ok {
+			return "", backendErr.WithTaskUID(task.UID).WithListID(listID)
+		}
+		return "", err
+	}
+
+	return task.UID, nil
+}
+
+func (b *Backend) UpdateTask(listID string, task backend.Task) error {
+	if err := backend.ValidateTaskRef("UpdateTask", listID, task.UID); err != nil {
+		return err
+	}
+
+	// Set modified time to now, and raise SEQUENCE so other clients take
+	// this version as newer than theirs
+	task.Modified = time.Now()
+	task.Sequence++
+
+	// If status is COMPLETED and Completed time not set, set it now
+	if task.Status == "COMPLETED" && task.Completed == nil {
+		now := time.Now()
+		task.Completed = &now
+	}
+
+	// Build the iCalendar content
+	icalContent := b.buildICalContent(task)
+
+	// Make authenticated request (CalDAV uses PUT for both create and update),
+	// only replacing the version that was read when its ETag is known
+	headers := map[string]string{
+		"Content-Type": "text/calendar; charset=utf-8",
+	}
+	etag := task.ETag
+	if etag == "" {
+		etag = b.knownETag(listID, task.UID)
+	}
+	if etag != "" {
+		headers["If-Match"] = etag
+	}
+	taskURL, err := b.buildTaskURL(listID, task.UID)
+	if err != nil {
+		return err
+	}
+	resp, err := b.makeAuthenticatedRequest("PUT", taskURL, bytes.NewBufferString(icalContent), headers)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode == http.StatusPreconditionFailed && etag != "" {
+		return conflictError("UpdateTask", listID, task.UID, resp)
+	}
+
+	// Check response status
+	if err := b.checkHTTPResponse(resp, "UpdateTask"); err != nil {
+		if backendErr, ok := err.(*backend.BackendError); ok {
+			return backendErr.WithTaskUID(task.UID).WithListID(listID)
+		}
+		return err
+	}
+
+	// The version written is the one a next update replaces; servers that
+	// don't return its ETag leave it unknown
+	task.ETag = resp.Header.Get("ETag")
+	b.rememberFetched(listID, []backend.Task{task})
+
+	return nil
+}
+
+func (b *Backend) DeleteTask(listID string, taskUID string) error {
+	if err := backend.ValidateTaskRef("DeleteTask", listID, taskUID); err != nil {
+		return err
+	}
+
+	// Verify the target is our VTODO unless it was just fetched from this list
+	if !b.wasFetched(listID, taskUID) {
+		if err := b.verifyTaskResource(listID, taskUID); err != nil {
+			return err
+		}
+	}
+
+	return b.ForceDeleteTask(listID, taskUID)
+}
+
+// ForceDeleteTask deletes a task resource without verifying it first
+func (b *Backend) ForceDeleteTask(listID string, taskUID string) error {
+	if err := backend.ValidateTaskRef("DeleteTask", listID, taskUID); err != nil {
+		return err
+	}
+
+	// Make authenticated DELETE request, of the version that was read when
+	// its ETag is known
+	// 204 No Content is the typical success status for DELETE
+	var headers map[string]string
+	etag := b.knownETag(listID, taskUID)
+	if etag != "" {
+		headers = map[string]string{"If-Match": etag}
+	}
+	taskURL, err := b.buildTaskURL(listID, taskUID)
+	if err != nil {
+		return err
+	}
+	resp, err := b.makeAuthenticatedRequest("DELETE", taskURL, nil, headers)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode == http.StatusPreconditionFailed && etag != "" {
+		return conflictError("DeleteTask", listID, taskUID, resp)
+	}
+
+	// Check response status - handle 404 specifically for task not found
+	if resp.StatusCode == 404 {
+		return backend.NewBackendError("DeleteTask", 404, "task not found (may have been already deleted)").
+			WithTaskUID(taskUID).
+			WithListID(listID)
+	}
+
+	// Check for other errors
+	if err := b.checkHTTPResponse(resp, "DeleteTask", 200, 204); err != nil {
+		if backendErr, ok := err.(*backend.BackendError); ok {
+			return backendErr.WithTaskUID(taskUID).WithListID(listID)
+		}
+		return err
+	}
+
+	return nil
+}
+
+// verifyTaskResource checks that the resource at the task URL is a calendar
+// object containing a VTODO with the expected UID. This guards against deleting
+// an event or another object when a UID is stale or collides in a mixed calendar.
+func (b *Backend) verifyTaskResource(listID, taskUID string) error {
+	taskURL, err := b.buildTaskURL(listID, taskUID)
+	if err != nil {
+		return err
+	}
+	resp, err := b.makeAuthenticatedRequest("GET", taskURL, nil, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode == 404 {
+		return backend.NewBackendError("DeleteTask", 404, "task not found (may have been already deleted)").
+			WithTaskUID(taskUID).
+			WithListID(listID)
+	}
+	if err := b.checkHTTPResponse(resp, "DeleteTask", 200); err != nil {
+		if backendErr, ok := err.(*backend.BackendError); ok {
+			return backendErr.WithTaskUID(taskUID).WithListID(listID)
+		}
+		return err
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	contentType := resp.Header.Get("Content-Type")
+	if contentType != "" && !strings.Contains(strings.ToLower(contentType), "text/calendar") {
+		return backend.NewBackendError("DeleteTask", 409,
+			fmt.Sprintf("refusing to delete: resource is %s, not a calendar object", contentType)).
+			WithTaskUID(taskUID).
+			WithListID(listID)
+	}
+
+	blocks := extractVTODOBlocks(ical.Unfold(string(body)))
+	if len(blocks) == 0 {
+		return backend.NewBackendError("DeleteTask", 409, "refusing to delete: resource does not contain a VTODO").
+			WithTaskUID(taskUID).
+			WithListID(listID)
+	}
+	for _, block := range blocks {
+		if task, err := parseVTODO(block); err == nil && task.UID == taskUID {
+			return nil
+		}
+	}
+
+	return backend.NewBackendError("DeleteTask", 409, "refusing to delete: VTODO UID does not match").
+		WithTaskUID(taskUID).
+		WithListID(listID)
+}
+
+// rememberFetched records the UIDs and ETags returned by GetTasks for a list
+func (b *Backend) rememberFetched(listID string, tasks []backend.Task) {
+	b.fetchedMu.Lock()
+	defer b.fetchedMu.Unlock()
+
+	if b.fetchedUIDs == nil {
+		b.fetchedUIDs = make(map[string]map[string]string)
+	}
+	if b.fetchedUIDs[listID] == nil {
+		b.fetchedUIDs[listID] = make(map[string]string)
+	}
+	for _, task := range tasks {
+		b.fetchedUIDs[listID][task.UID] = task.ETag
+	}
+}
+
+// wasFetched reports whether GetTasks returned taskUID for listID in this process
+func (b *Backend) wasFetched(listID, taskUID string) bool {
+	b.fetchedMu.Lock()
+	defer b.fetchedMu.Unlock()
+
+	_, fetched := b.fetchedUIDs[listID][taskUID]
+	return fetched
+}
+
+// knownETag returns the ETag taskUID had when last read or written in this
+// process, or "" when it is unknown
+func (b *Backend) knownETag(listID, taskUID string) string {
+	b.fetchedMu.Lock()
+	defer b.fetchedMu.Unlock()
+
+	return b.fetchedUIDs[listID][taskUID]
+}
+
+// conflictError is the error of a write refused by an If-Match, the task
+// having changed on the server since it was read
+func conflictError(operation, listID, taskUID string, resp *http.Response) *backend.BackendError {
+	body, _ := io.ReadAll(resp.Body)
+	return backend.NewBackendError(operation, resp.StatusCode, "the task changed on the server since it was read").
+		WithTaskUID(taskUID).
+		WithListID(listID).
+		WithBody(string(body))
+}
+
+// CreateTaskList creates a calendar for tasks. A calendar outside the trash
+// already displaying name is reported as a conflict: a creation retried after
+// a timeout may have gone through the first time, and the timestamp in the
+// ID would otherwise make a second calendar showing the same name.
+func (b *Backend) CreateTaskList(name, description, color string) (string, error) {
+	lists, err := b.GetTaskLists()
+	if err != nil {
+		return "", err
+	}
+	for _, list := range lists {
+		if list.DeletedAt == "" && strings.EqualFold(strings.TrimSpace(list.Name), strings.TrimSpace(name)) {
+			return "", backend.NewBackendError("CreateTaskList", 409,
+				fmt.Sprintf("list '%s' already exists (ID: %s)", list.Name, list.ID)).WithListID(list.ID)
+		}
+	}
+
+	// Generate a unique list ID from the name (lowercase, replace spaces with dashes)
+	listID := strings.ToLower(strings.ReplaceAll(name, " ", "-"))
+	// Add timestamp to ensure uniqueness
+	listID = fmt.Sprintf("%s-%d", listID, time.Now().Unix())
+
+	// Build the MKCALENDAR request body
+	mkcolBody := `<?xml version="1.0" encoding="utf-8" ?>
+<d:mkcol xmlns:d="DAV:" xmlns:c="urn:ietf:params:xml:ns:caldav" xmlns:ic="http://apple.com/ns/ical/">
+  <d:set>
+    <d:prop>
+      <d:resourcetype>
+        <d:collection/>
+        <c:calendar/>
+      </d:resourcetype>
+      <d:displayname>` + name + `</d:displayname>
+      <c:supported-calendar-component-set>
+        <c:comp name="VTODO"/>
+      </c:supported-calendar-component-set>`
+
+	if description != "" {
+		mkcolBody += `
+      <c:calendar-description>` + description + `</c:calendar-description>`
+	}
+
+	if color != "" {
+		mkcolBody += `
+      <ic:calendar-color>` + color + `</ic:calendar-color>`
+	}
+
+	mkcolBody += `
+    </d:prop>
+  </d:set>
+</d:mkcol>`
+
+	// Make authenticated request
+	// 201 Created is the success status for MKCOL
+	headers := map[string]string{
+		"Content-Type": "application/xml; charset=utf-8",
+	}
+	listURL, err := b.buildListURL(listID)
+	if err != nil {
+		return "", err
+	}
+	resp, err := b.makeAuthenticatedRequest("MKCOL", listURL, bytes.NewBufferString(mkcolBody), headers)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	// Check response status - handle 405 specifically for list already exists
+	if resp.StatusCode == 405 {
+		body, _ := io.ReadAll(resp.Body)
+		return "", backend.NewBackendError("CreateTaskList", 405, "list already exists or name conflict").
+			WithBody(string(body))
+	}
+
+	// Check for other errors
+	if err := b.checkHTTPResponse(resp, "CreateTaskList", 201); err != nil {
+		return "", err
+	}
+
+	return listID, nil
+}
+
+func (b *Backend) DeleteTaskList(listID string) error {
+	// Make authenticated DELETE request
+	// 204 No Content is the success status for DELETE
+	listURL, err := b.buildListURL(listID)
+	if err != nil {
+		return err
+	}
+	resp, err := b.makeAuthenticatedRequest("DELETE", listURL, nil, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	// Check response status - handle 404 specifically for list not found
+	if resp.StatusCode == 404 {
+		return backend.NewBackendError("DeleteTaskList", 404, "list not found").
+			WithListID(listID)
+	}
+
+	// Check for other errors
+	if err := b.checkHTTPResponse(resp, "DeleteTaskList", 200, 204); err != nil {
+		if backendErr, ok := err.(*backend.BackendError); ok {
+			return backendErr.WithListID(listID)
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (b *Backend) RenameTaskList(listID, newName string) error {
+	// Build the PROPPATCH request body to update displayname
+	proppatchBody := `<?xml version="1.0" encoding="utf-8" ?>
+<d:propertyupdate xmlns:d="DAV:">
+  <d:set>
+    <d:prop>
+      <d:displayname>` + newName + `</d:displayname>
+    </d:prop>
+  </d:set>
+</d:propertyupdate>`
+
+	// Make authenticated request
+	// 207 Multi-Status is the typical success status for PROPPATCH
+	headers := map[string]string{
+		"Content-Type": "application/xml; charset=utf-8",
+	}
+	listURL, err := b.buildListURL(listID)
+	if err != nil {
+		return err
+	}
+	resp, err := b.makeAuthenticatedRequest("PROPPATCH", listURL, bytes.NewBufferString(proppatchBody), headers)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	// Check response status - handle 404 specifically for list not found
+	if resp.StatusCode == 404 {
+		return backend.NewBackendError("RenameTaskList", 404, "list not found").
+			WithListID(listID)
+	}
+
+	// Check for other errors
+	if err := b.checkHTTPResponse(resp, "RenameTaskList", 200, 207); err != nil {
+		if backendErr, ok := err.(*backend.BackendError); ok {
+			return backendErr.WithListID(listID)
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (b *Backend) RestoreTaskList(listID string) error {
+	if b.Discover {
+		return fmt.Errorf("restoring lists is only supported on Nextcloud (the CalDAV server has no trash)")
+	}
+
+	// Build the MOVE request to restore from trash
+	// Nextcloud uses MOVE method to restore deleted calendars
+	// Source: deleted calendar URL, Destination: restored calendar URL
+
+	// Build source URL (current location in trash)
+	sourceURL, err := b.buildListURL(listID)
+	if err != nil {
+		return err
+	}
+
+	// Build destination URL (where to restore - use the deleted-suffix format)
+	// Nextcloud appends a suffix to deleted calendars, we need to remove it
+	restoredListID := strings.TrimSuffix(listID, "-deleted")
+	if restoredListID == listID {
+		// If no -deleted suffix, try restoring to same location
+		restoredListID = listID
+	}
+	destURL, err := b.buildListURL(restoredListID)
+	if err != nil {
+		return err
+	}
+
+	// Make authenticated MOVE request
+	headers := map[string]string{
+		"Destination": destURL,
+		"Overwrite":   "F", // Don't overwrite existing calendars
+	}
+	resp, err := b.makeAuthenticatedRequest("MOVE", sourceURL, nil, headers)
+	if err != nil {
+		return fmt.Errorf("failed to restore list: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	// Check response status - 201 Created or 204 No Content are success statuses
+	if resp.StatusCode == 404 {
+		return backend.NewBackendError("RestoreTaskList", 404, "list not found in trash").
+			WithListID(listID)
+	}
+
+	if err := b.checkHTTPResponse(resp, "RestoreTaskList", 201, 204); err != nil {
+		if backendErr, ok := err.(*backend.BackendError); ok {
+			return backendErr.WithListID(listID)
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (b *Backend) PermanentlyDeleteTaskList(listID string) error {
+	// Build DELETE request with special header to permanently delete from trash
+	// For Nextcloud, we need to delete the calendar completely
+
+	// Make authenticated DELETE request
+	listURL, err := b.buildListURL(listID)
+	if err != nil {
+		return err
+	}
+	resp, err := b.makeAuthenticatedRequest("DELETE", listURL, nil, nil)
+	if err != nil {
+		return fmt.Errorf("failed to permanently delete list: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	// Check response status - handle 404 specifically for list not found
+	if resp.StatusCode == 404 {
+		return backend.NewBackendError("PermanentlyDeleteTaskList", 404, "list not found in trash").
+			WithListID(listID)
+	}
+
+	// Check for other errors - 200 OK or 204 No Content are success statuses
+	if err := b.checkHTTPResponse(resp, "PermanentlyDeleteTaskList", 200, 204); err != nil {
+		if backendErr, ok := err.(*backend.BackendError); ok {
+			return backendErr.WithListID(listID)
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (b *Backend) buildICalContent(task backend.Task) string {
+	var icalContent bytes.Buffer
+
+	// DTSTAMP is when this VTODO was written, so it is new on every write
+	dtstamp := time.Now().UTC().Format("20060102T150405Z")
+
+	icalContent.WriteString("BEGIN:VCALENDAR\r\n")
+	icalContent.WriteString("VERSION:2.0\r\n")
+	icalContent.WriteString("PRODID:-//Go CalDAV Client//EN\r\n")
+	icalContent.WriteString("BEGIN:VTODO\r\n")
+	icalContent.WriteString(fmt.Sprintf("UID:%s\r\n", task.UID))
+	icalContent.WriteString(fmt.Sprintf("DTSTAMP:%s\r\n", dtstamp))
+
+	// CREATED is kept as read; a task without it does not get one made up
+	if !task.Created.IsZero() {
+		created := task.Created.UTC().Format("20060102T150405Z")
+		icalContent.WriteString(fmt.Sprintf("CREATED:%s\r\n", created))
+	}
+	if task.Sequence > 0 {
+		icalContent.WriteString(fmt.Sprintf("SEQUENCE:%d\r\n", task.Sequence))
+	}
+
+	// Add LAST-MODIFIED if task has been modified
+	if !task.Modified.IsZero() {
+		modified := task.Modified.UTC().Format("20060102T150405Z")
+		icalContent.WriteString(fmt.Sprintf("LAST-MODIFIED:%s\r\n", modified))
+	}
+
+	icalContent.WriteString(fmt.Sprintf("SUMMARY:%s\r\n", ical.EscapeText(task.Summary)))
+
+	if task.Description != "" {
+		icalContent.WriteString(fmt.Sprintf("DESCRIPTION:%s\r\n", ical.EscapeText(task.Description)))
+	}
+
+	icalContent.WriteString(fmt.Sprintf("STATUS:%s\r\n", task.Status))
+
+	if task.Priority > 0 {
+		icalContent.WriteString(fmt.Sprintf("PRIORITY:%d\r\n", task.Priority))
+	}
+
+	if task.DueDate != nil {
+		icalContent.WriteString(ical.FormatDate("DUE", *task.DueDate, task.AllDay) + "\r\n")
+	}
+
+	if task.StartDate != nil {
+		icalContent.WriteString(ical.FormatDate("DTSTART", *task.StartDate, task.AllDay) + "\r\n")
+	}
+
+	if task.Recurrence != "" {
+		icalContent.WriteString(fmt.Sprintf("RRULE:%s\r\n", task.Recurrence))
+	}
+
+	// Add COMPLETED timestamp if status is COMPLETED
+	if task.Status == "COMPLETED" && task.Completed != nil {
+		completed := task.Completed.UTC().Format("20060102T150405Z")
+		icalContent.WriteString(fmt.Sprintf("COMPLETED:%s\r\n", completed))
+	}
+
+	if len(task.Categories) > 0 {
+		categories := make([]string, len(task.Categories))
+		for i, category := range task.Categories {
+			categories[i] = ical.EscapeText(category)
+		}
+		icalContent.WriteString(fmt.Sprintf("CATEGORIES:%s\r\n", strings.Join(categories, ",")))
+	}
+
+	// Add RELATED-TO for parent-child relationships
+	if task.ParentUID != "" {
+		icalContent.WriteString(fmt.Sprintf("RELATED-TO:%s\r\n", task.ParentUID))
+	}
+
+	icalContent.WriteString("END:VTODO\r\n")
+	icalContent.WriteString("END:VCALENDAR\r\n")
+
+	return ical.Fold(icalContent.String())
+}
+
+func (b *Backend) SortTasks(tasks []backend.Task) {
+	// Nextcloud priority: 1 is highest, 0 is undefined (goes last)
+	sort.Slice(tasks, func(i, j int) bool {
+		pi, pj := tasks[i].Priority, tasks[j].Priority
+
+		// Priority 0 (undefined) goes to the end
+		if pi == 0 && pj != 0 {
+			return false
+		}
+		if pj == 0 && pi != 0 {
+			return true
+		}
+
+		// Otherwise sort ascending (1, 2, 3, ...)
+		return pi < pj
+	})
+}
+
+func (b *Backend) ParseStatusFlag(statusFlag string) (string, error) {
+	return statuskit.CalDAV.ParseStatusFlag(statusFlag)
+}
+
+func (b *Backend) StatusToDisplayName(backendStatus string) string {
+	return statuskit.CalDAV.StatusToDisplayName(backendStatus)
+}
+
+func (b *Backend) GetPriorityColor(priority int) string {
+	return statuskit.DefaultPalette.Color(priority)
+}
+
+func (b *Backend) GetBackendDisplayName() string {
+	username := b.getUsername()
+	host := ""
+	if b.Connector.URL != nil {
+		host = b.Connector.URL.Host
+	}
+	return fmt.Sprintf("[%s:%s@%s]", b.GetBackendType(), username, host)
+}
+
+// GetBackendType returns caldav for discovered servers, and nextcloud
+// otherwise
+func (b *Backend) GetBackendType() string {
+	if b.Discover {
+		return "caldav"
+	}
+	return "nextcloud"
+}
+
+// WritesConcurrently reports that tasks may be written concurrently, each
+// write being its own CalDAV request
+func (b *Backend) WritesConcurrently() bool {
+	return true
+}
+
+// ReadsConcurrently reports that lists may be read concurrently, each read
+// being its own CalDAV request
+func (b *Backend) ReadsConcurrently() bool {
+	return true
+}
+
+func (b *Backend) GetBackendContext() string {
+	username := b.getUsername()
+	host := ""
+	if b.Connector.URL != nil {
+		host = b.Connector.URL.Host
+	}
+	return fmt.Sprintf("%s@%s", username, host)
+}
+
+// New creates a backend for the server of connectorConfig. It does not
+// connect: BasicValidation and discovery happen on first operation, after
+// the factory has set BackendName (needed for keyring credentials). Security
+// notices are reported by the factory too, so they can name the backend.
+func New(connectorConfig backend.ConnectorConfig, discover bool) *Backend {
+	return &Backend{
+		Connector: connectorConfig,
+		Discover:  discover,
+	}
+}
+
+// reportSecurityNotices warns about insecure connection settings that are
+// not suppressed in the config
+func (b *Backend) reportSecurityNotices() {
+	if b.Connector.URL == nil {
+		return
+	}
+	name := b.BackendName
+	if name == "" {
+		name = b.GetBackendType()
+	}
+
+	if !b.Connector.SuppressHTTPWarning && b.usesHTTP() {
+		backend.ReportSecurityNotice(backend.SecurityNotice{
+			Backend: name,
+			Kind:    "http",
+			Title:   "HTTP sends your data and password in PLAINTEXT",
+			Detail: []string{
+				"Only use HTTP for local testing with trusted networks.",
+				"For production, use HTTPS with valid certificates.",
+			},
+		})
+	}
+
+	if b.Connector.InsecureSkipVerify && !b.Connector.SuppressSSLWarning {
+		backend.ReportSecurityNotice(backend.SecurityNotice{
+			Backend: name,
+			Kind:    "tls",
+			Title:   "TLS certificate verification is DISABLED",
+			Detail: []string{
+				"This makes you vulnerable to man-in-the-middle attacks.",
+				"Only use this for development with self-signed certificates.",
+				"For production, use properly signed certificates or add your",
+				"CA certificate to the system trust store.",
+			},
+		})
+	}
+}
+
+// NewFromBackendConfig creates a backend from a config entry: the URL, or
+// else the host, of the server, and the credentials to resolve. With
+// discover the URL is the server's base URL, such as
+// https://dav.example.com/ or http://localhost:5232/user/, its path being
+// where discovery starts.
+func NewFromBackendConfig(bc backend.BackendConfig, discover bool) (backend.TaskManager, error) {
+	// Parse URL if provided, otherwise construct from host
+	var u *url.URL
+	var err error
+
+	scheme := "nextcloud"
+	if discover {
+		scheme = "https"
+	}
+	if bc.URL != "" {
+		u, err = url.Parse(bc.URL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid URL for %s backend: %w", bc.Type, err)
+		}
+	} else if bc.Host != "" {
+		// Construct URL from host (without credentials)
+		u, _ = url.Parse(fmt.Sprintf("%s://%s", scheme, bc.Host))
+	}
+
+	connConfig := backend.ConnectorConfig{
+		URL:                 u,
+		InsecureSkipVerify:  bc.InsecureSkipVerify,
+		SuppressSSLWarning:  bc.SuppressSSLWarning,
+		AllowHTTP:           bc.AllowHTTP,
+		SuppressHTTPWarning: bc.SuppressHTTPWarning,
+	}
+
+	// Set backend name and config fields for credential resolution
+	b := New(connConfig, discover)
+	b.BackendName = bc.Name
+	b.ConfigHost = bc.Host
+	b.ConfigUsername = bc.Username
+	b.reportSecurityNotices()
+
+	return b, nil
+}
+
+func (b *Backend) BasicValidation() error {
+	if b.Connector.URL == nil {
+		return fmt.Errorf("connector URL is nil")
+	}
+
+	// Credentials can come from URL, keyring, or environment variables
+	// Only require URL.User if we're not using keyring/env (i.e., no BackendName)
+	if b.BackendName == "" && b.Connector.URL.User == nil {
+		return fmt.Errorf("no user credentials in URL and no backend name for credential resolution")
+	}
+	return nil
+}
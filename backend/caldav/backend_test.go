@@ -1,13 +1,17 @@
-package nextcloud
+package caldav
 
 import (
+	"errors"
 	"gosynctasks/backend"
+	"gosynctasks/internal/ical"
 	"io"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"reflect"
 	"strings"
 	"testing"
+	"time"
 )
 
 // Helper function to create test URL (keeps http:// scheme for httptest server)
@@ -32,8 +36,8 @@ func createTestConnector(t *testing.T, serverURL string) backend.ConnectorConfig
 }
 
 // Helper function to create test backend with proper HTTP URL
-func createTestBackend(t *testing.T, serverURL string) *NextcloudBackend {
-	nb := &NextcloudBackend{
+func createTestBackend(t *testing.T, serverURL string) *Backend {
+	nb := &Backend{
 		Connector: createTestConnector(t, serverURL),
 	}
 	// Override baseURL to use HTTP for httptest server
@@ -48,6 +52,7 @@ const mockTaskListsResponse = `<?xml version="1.0"?>
         <d:href>/remote.php/dav/calendars/testuser/tasks/</d:href>
         <d:propstat>
             <d:prop>
+                <d:resourcetype><d:collection/><cal:calendar/></d:resourcetype>
                 <d:displayname>My Tasks</d:displayname>
                 <cal:supported-calendar-component-set>
                     <cal:comp name="VTODO"/>
@@ -62,6 +67,7 @@ const mockTaskListsResponse = `<?xml version="1.0"?>
         <d:href>/remote.php/dav/calendars/testuser/work/</d:href>
         <d:propstat>
             <d:prop>
+                <d:resourcetype><d:collection/><cal:calendar/></d:resourcetype>
                 <d:displayname>Work Tasks</d:displayname>
                 <cal:supported-calendar-component-set>
                     <cal:comp name="VTODO"/>
@@ -321,6 +327,138 @@ func TestNextcloudBackend_GetTasks_WithFilter(t *testing.T) {
 	}
 }
 
+func TestNextcloudBackend_GetTasks_MultipleStatusesWithDueRange(t *testing.T) {
+	var capturedRequestBody string
+	vtodo := func(uid, status string) string {
+		statusLine := ""
+		if status != "" {
+			statusLine = "STATUS:" + status + "\n"
+		}
+		return `<d:response>
+        <d:href>/remote.php/dav/calendars/testuser/tasks/` + uid + `.ics</d:href>
+        <d:propstat>
+            <d:prop>
+                <cal:calendar-data>BEGIN:VCALENDAR
+VERSION:2.0
+BEGIN:VTODO
+UID:` + uid + `
+SUMMARY:` + uid + `
+` + statusLine + `DUE:20230110T120000Z
+END:VTODO
+END:VCALENDAR</cal:calendar-data>
+            </d:prop>
+            <d:status>HTTP/1.1 200 OK</d:status>
+        </d:propstat>
+    </d:response>`
+	}
+
+	// The server applies the date range only; statuses are left to the client
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		buf, _ := io.ReadAll(r.Body)
+		capturedRequestBody = string(buf)
+
+		w.Header().Set("Content-Type", "application/xml; charset=utf-8")
+		w.WriteHeader(http.StatusMultiStatus)
+		w.Write([]byte(`<?xml version="1.0"?>
+<d:multistatus xmlns:d="DAV:" xmlns:cal="urn:ietf:params:xml:ns:caldav">` +
+			vtodo("open", "NEEDS-ACTION") + vtodo("no-status", "") + vtodo("started", "IN-PROCESS") +
+			vtodo("done", "COMPLETED") + vtodo("dropped", "CANCELLED") + `
+</d:multistatus>`))
+	}))
+	defer server.Close()
+
+	nb := createTestBackend(t, server.URL)
+
+	dueAfter := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	dueBefore := time.Date(2023, 2, 1, 0, 0, 0, 0, time.UTC)
+	filter := &backend.TaskFilter{
+		Statuses:  &[]string{"NEEDS-ACTION", "IN-PROCESS"},
+		DueAfter:  &dueAfter,
+		DueBefore: &dueBefore,
+	}
+
+	tasks, err := nb.GetTasks("/calendars/testuser/tasks/", filter)
+	if err != nil {
+		t.Fatalf("GetTasks failed: %v", err)
+	}
+
+	// Sibling prop-filters are ANDed, so no status may be pushed to the server
+	if strings.Contains(capturedRequestBody, `name="STATUS"`) || strings.Contains(capturedRequestBody, `name="COMPLETED"`) {
+		t.Errorf("Expected no status prop-filter for several statuses, got: %s", capturedRequestBody)
+	}
+	if n := strings.Count(capturedRequestBody, "<c:time-range"); n != 1 {
+		t.Errorf("Expected a single time-range, got %d in: %s", n, capturedRequestBody)
+	}
+	if !strings.Contains(capturedRequestBody, `<c:time-range start="20230101T000000Z" end="20230201T000000Z"/>`) {
+		t.Errorf("Expected time-range with both bounds, got: %s", capturedRequestBody)
+	}
+
+	var uids []string
+	for _, task := range tasks {
+		uids = append(uids, task.UID)
+	}
+	if want := []string{"open", "no-status", "started"}; !reflect.DeepEqual(uids, want) {
+		t.Errorf("Expected tasks %v, got %v", want, uids)
+	}
+}
+
+func TestNextcloudBackend_BuildCalendarQuery(t *testing.T) {
+	nb := &Backend{}
+	dueBefore := time.Date(2023, 2, 1, 0, 0, 0, 0, time.UTC)
+	summary, empty := "Q&A <draft>", ""
+
+	tests := []struct {
+		name     string
+		filter   *backend.TaskFilter
+		contains []string
+		excludes []string
+	}{
+		{
+			name:     "single non-default status",
+			filter:   &backend.TaskFilter{Statuses: &[]string{"IN-PROCESS"}},
+			contains: []string{`name="STATUS"`, "IN-PROCESS"},
+			excludes: []string{`name="COMPLETED"`},
+		},
+		{
+			name:     "several statuses",
+			filter:   &backend.TaskFilter{Statuses: &[]string{"NEEDS-ACTION", "COMPLETED"}},
+			excludes: []string{`name="STATUS"`, `name="COMPLETED"`},
+		},
+		{
+			name:     "due before only",
+			filter:   &backend.TaskFilter{DueBefore: &dueBefore},
+			contains: []string{`<c:time-range end="20230201T000000Z"/>`},
+			excludes: []string{"start="},
+		},
+		{
+			name:     "summary text, escaped",
+			filter:   &backend.TaskFilter{SummaryContains: &summary},
+			contains: []string{`<c:prop-filter name="SUMMARY">`, `<c:text-match collation="i;unicode-casemap">Q&amp;A &lt;draft&gt;</c:text-match>`},
+		},
+		{
+			name:     "empty summary text",
+			filter:   &backend.TaskFilter{SummaryContains: &empty},
+			excludes: []string{`name="SUMMARY"`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query := nb.buildCalendarQuery(tt.filter)
+			for _, s := range tt.contains {
+				if !strings.Contains(query, s) {
+					t.Errorf("Expected query to contain %q, got: %s", s, query)
+				}
+			}
+			for _, s := range tt.excludes {
+				if strings.Contains(query, s) {
+					t.Errorf("Expected query not to contain %q, got: %s", s, query)
+				}
+			}
+		})
+	}
+}
+
 func TestNextcloudBackend_FindTasksBySummary(t *testing.T) {
 	// Create mock CalDAV server
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -381,6 +519,52 @@ func TestNextcloudBackend_FindTasksBySummary(t *testing.T) {
 	}
 }
 
+func TestNextcloudBackend_FindTasksBySummary_ServerSide(t *testing.T) {
+	var bodies []string
+	refuse := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+		if refuse && strings.Contains(string(body), "text-match") {
+			w.WriteHeader(http.StatusPreconditionFailed)
+			return
+		}
+		w.Header().Set("Content-Type", "application/xml; charset=utf-8")
+		w.WriteHeader(http.StatusMultiStatus)
+		w.Write([]byte(mockTasksResponse))
+	}))
+	defer server.Close()
+
+	nb := createTestBackend(t, server.URL)
+
+	// The REPORT asks the server for the matches, and what it sends back is
+	// matched again
+	matches, err := nb.FindTasksBySummary("/calendars/testuser/tasks/", "groceries")
+	if err != nil {
+		t.Fatalf("FindTasksBySummary failed: %v", err)
+	}
+	if len(bodies) != 1 || !strings.Contains(bodies[0], `<c:prop-filter name="SUMMARY">
+          <c:text-match collation="i;unicode-casemap">groceries</c:text-match>`) {
+		t.Errorf("REPORT bodies = %q, want one with a SUMMARY text-match", bodies)
+	}
+	if len(matches) != 1 || matches[0].UID != "task1" {
+		t.Errorf("FindTasksBySummary() = %+v, want task1 only", matches)
+	}
+
+	// A server refusing the text-match is asked for the whole list
+	bodies, refuse = nil, true
+	matches, err = nb.FindTasksBySummary("/calendars/testuser/tasks/", "groceries")
+	if err != nil {
+		t.Fatalf("FindTasksBySummary with the text-match refused: %v", err)
+	}
+	if len(bodies) != 2 || strings.Contains(bodies[1], "text-match") {
+		t.Errorf("REPORT bodies = %q, want a second one without text-match", bodies)
+	}
+	if len(matches) != 1 || matches[0].UID != "task1" {
+		t.Errorf("FindTasksBySummary() = %+v, want task1 only", matches)
+	}
+}
+
 func TestNextcloudBackend_AddTask(t *testing.T) {
 	var capturedMethod string
 	var capturedBody string
@@ -570,8 +754,174 @@ func TestNextcloudBackend_UpdateTask(t *testing.T) {
 	}
 }
 
+// TestNextcloudBackend_UpdateTaskSequence round-trips tasks through
+// UpdateTask: SEQUENCE goes up by one on every update, from what was read,
+// CREATED is written as read and DTSTAMP is the time of the write
+func TestNextcloudBackend_UpdateTaskSequence(t *testing.T) {
+	var capturedBody string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		buf, _ := io.ReadAll(r.Body)
+		capturedBody = string(buf)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+	nb := createTestBackend(t, server.URL)
+
+	created := time.Date(2024, 5, 1, 9, 30, 0, 0, time.UTC)
+	// SEQUENCE raised to 7 by another client since we last wrote the task
+	remoteBump := "BEGIN:VTODO\nUID:task-1\nSUMMARY:Call\nCREATED:20240501T093000Z\nSEQUENCE:7\nEND:VTODO\n"
+	bumped, err := parseVTODO(remoteBump)
+	if err != nil {
+		t.Fatalf("parseVTODO failed: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		task backend.Task
+		want int
+	}{
+		{"never updated", backend.Task{UID: "task-1", Summary: "Call", Status: "NEEDS-ACTION", Created: created}, 1},
+		{"updated three times", backend.Task{UID: "task-1", Summary: "Call", Status: "NEEDS-ACTION", Created: created, Sequence: 3}, 4},
+		{"bumped remotely", bumped, 8},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before := time.Now().UTC().Truncate(time.Second)
+			if err := nb.UpdateTask("/calendars/testuser/tasks/", tt.task); err != nil {
+				t.Fatalf("UpdateTask failed: %v", err)
+			}
+
+			written, err := parseVTODO(strings.ReplaceAll(capturedBody, "\r\n", "\n"))
+			if err != nil {
+				t.Fatalf("written VTODO does not parse: %v", err)
+			}
+			if written.Sequence != tt.want {
+				t.Errorf("SEQUENCE = %d, want %d", written.Sequence, tt.want)
+			}
+			if !written.Created.Equal(created) {
+				t.Errorf("CREATED = %v, want %v as read", written.Created, created)
+			}
+
+			var dtstamp string
+			for _, line := range strings.Split(capturedBody, "\r\n") {
+				if value, ok := strings.CutPrefix(line, "DTSTAMP:"); ok {
+					dtstamp = value
+				}
+			}
+			if stamp, _, err := ical.ParseTime(dtstamp, nil); err != nil || stamp.Before(before) {
+				t.Errorf("DTSTAMP = %q, want the time of the write", dtstamp)
+			}
+
+			// Writing what was read back raises it again, never lowers it
+			if err := nb.UpdateTask("/calendars/testuser/tasks/", written); err != nil {
+				t.Fatalf("UpdateTask failed: %v", err)
+			}
+			if again, _ := parseVTODO(strings.ReplaceAll(capturedBody, "\r\n", "\n")); again.Sequence != tt.want+1 {
+				t.Errorf("second update SEQUENCE = %d, want %d", again.Sequence, tt.want+1)
+			}
+		})
+	}
+}
+
+func TestNextcloudBackend_ETags(t *testing.T) {
+	const report = `<?xml version="1.0"?>
+<d:multistatus xmlns:d="DAV:" xmlns:cal="urn:ietf:params:xml:ns:caldav">
+    <d:response>
+        <d:href>/remote.php/dav/calendars/testuser/tasks/task1.ics</d:href>
+        <d:propstat>
+            <d:prop>
+                <d:getetag>&quot;etag-1&quot;</d:getetag>
+                <cal:calendar-data>BEGIN:VCALENDAR
+BEGIN:VTODO
+UID:task1
+SUMMARY:Buy groceries
+END:VTODO
+END:VCALENDAR</cal:calendar-data>
+            </d:prop>
+        </d:propstat>
+    </d:response>
+    <d:response>
+        <d:href>/remote.php/dav/calendars/testuser/tasks/task2.ics</d:href>
+        <d:propstat>
+            <d:prop>
+                <d:getetag>&quot;etag-2&quot;</d:getetag>
+                <cal:calendar-data>BEGIN:VCALENDAR
+BEGIN:VTODO
+UID:task2
+SUMMARY:Finish report
+END:VTODO
+END:VCALENDAR</cal:calendar-data>
+            </d:prop>
+        </d:propstat>
+    </d:response>
+</d:multistatus>`
+
+	var ifMatch []string
+	current := map[string]string{"task1": `"etag-1"`, "task2": `"etag-2"`}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "REPORT" {
+			w.WriteHeader(http.StatusMultiStatus)
+			w.Write([]byte(report))
+			return
+		}
+		uid := strings.TrimSuffix(r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:], ".ics")
+		ifMatch = append(ifMatch, r.Method+" "+uid+" "+r.Header.Get("If-Match"))
+		if match := r.Header.Get("If-Match"); match != "" && match != current[uid] {
+			w.WriteHeader(http.StatusPreconditionFailed)
+			return
+		}
+		if r.Method == "PUT" {
+			current[uid] = `"etag-` + uid + `-new"`
+			w.Header().Set("ETag", current[uid])
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	nb := createTestBackend(t, server.URL)
+	listID := "/calendars/testuser/tasks/"
+	tasks, err := nb.GetTasks(listID, nil)
+	if err != nil {
+		t.Fatalf("GetTasks failed: %v", err)
+	}
+	if len(tasks) != 2 || tasks[0].ETag != `"etag-1"` || tasks[1].ETag != `"etag-2"` {
+		t.Fatalf("GetTasks() = %+v, want the ETag of each task", tasks)
+	}
+
+	// Updates send the ETag of the task, or the one last seen, which the
+	// update then replaces
+	if err := nb.UpdateTask(listID, tasks[0]); err != nil {
+		t.Fatalf("UpdateTask failed: %v", err)
+	}
+	if err := nb.UpdateTask(listID, backend.Task{UID: "task1", Summary: "Buy groceries"}); err != nil {
+		t.Fatalf("second UpdateTask failed: %v", err)
+	}
+
+	// Another client changed task2: the update and delete are refused
+	current["task2"] = `"etag-other"`
+	err = nb.UpdateTask(listID, tasks[1])
+	var backendErr *backend.BackendError
+	if !errors.As(err, &backendErr) || !backendErr.IsConflict() {
+		t.Errorf("UpdateTask of a changed task error = %v, want a conflict", err)
+	}
+	err = nb.DeleteTask(listID, "task2")
+	if !errors.As(err, &backendErr) || !backendErr.IsConflict() {
+		t.Errorf("DeleteTask of a changed task error = %v, want a conflict", err)
+	}
+
+	want := []string{
+		`PUT task1 "etag-1"`,
+		`PUT task1 "etag-task1-new"`,
+		`PUT task2 "etag-2"`,
+		`DELETE task2 "etag-2"`,
+	}
+	if !reflect.DeepEqual(ifMatch, want) {
+		t.Errorf("requests = %q, want %q", ifMatch, want)
+	}
+}
+
 func TestNextcloudBackend_SortTasks(t *testing.T) {
-	nb := &NextcloudBackend{}
+	nb := &Backend{}
 
 	tasks := []backend.Task{
 		{Summary: "backend.Task A", Priority: 0}, // Undefined (should be last)
@@ -593,7 +943,7 @@ func TestNextcloudBackend_SortTasks(t *testing.T) {
 }
 
 func TestNextcloudBackend_GetPriorityColor(t *testing.T) {
-	nb := &NextcloudBackend{}
+	nb := &Backend{}
 
 	tests := []struct {
 		priority int
@@ -618,7 +968,7 @@ func TestNextcloudBackend_GetPriorityColor(t *testing.T) {
 }
 
 func TestNextcloudBackend_ParseStatusFlag(t *testing.T) {
-	nb := &NextcloudBackend{}
+	nb := &Backend{}
 
 	tests := []struct {
 		name        string
@@ -678,7 +1028,7 @@ func TestNextcloudBackend_ParseStatusFlag(t *testing.T) {
 }
 
 func TestNextcloudBackend_StatusToDisplayName(t *testing.T) {
-	nb := &NextcloudBackend{}
+	nb := &Backend{}
 
 	tests := []struct {
 		name     string
@@ -762,6 +1112,18 @@ func TestNextcloudBackend_DeleteTask(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			// Create mock server
 			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				// Answer the pre-delete verification with the task resource
+				if r.Method == "GET" {
+					if tt.responseStatus >= 400 {
+						w.WriteHeader(tt.responseStatus)
+						w.Write([]byte(tt.responseBody))
+						return
+					}
+					w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+					w.Write([]byte(vtodoResource(tt.taskUID)))
+					return
+				}
+
 				// Verify request method
 				if r.Method != "DELETE" {
 					t.Errorf("Expected DELETE request, got %s", r.Method)
@@ -801,6 +1163,173 @@ func TestNextcloudBackend_DeleteTask(t *testing.T) {
 		})
 	}
 }
+
+// vtodoResource returns a minimal calendar object containing one VTODO
+func vtodoResource(uid string) string {
+	return "BEGIN:VCALENDAR\r\nVERSION:2.0\r\nBEGIN:VTODO\r\nUID:" + uid +
+		"\r\nSUMMARY:Task\r\nEND:VTODO\r\nEND:VCALENDAR\r\n"
+}
+
+func TestNextcloudBackend_DeleteTask_Verification(t *testing.T) {
+	tests := []struct {
+		name          string
+		contentType   string
+		getStatus     int
+		getBody       string
+		expectDelete  bool
+		errorContains string
+	}{
+		{
+			name:         "matching VTODO is deleted",
+			contentType:  "text/calendar; charset=utf-8",
+			getStatus:    200,
+			getBody:      vtodoResource("task-1"),
+			expectDelete: true,
+		},
+		{
+			name:          "mismatched UID aborts",
+			contentType:   "text/calendar",
+			getStatus:     200,
+			getBody:       vtodoResource("other-task"),
+			errorContains: "UID does not match",
+		},
+		{
+			name:          "event resource aborts",
+			contentType:   "text/calendar",
+			getStatus:     200,
+			getBody:       "BEGIN:VCALENDAR\r\nBEGIN:VEVENT\r\nUID:task-1\r\nEND:VEVENT\r\nEND:VCALENDAR\r\n",
+			errorContains: "does not contain a VTODO",
+		},
+		{
+			name:          "non-calendar content aborts",
+			contentType:   "text/html",
+			getStatus:     200,
+			getBody:       "<html></html>",
+			errorContains: "not a calendar object",
+		},
+		{
+			name:          "missing resource aborts",
+			getStatus:     404,
+			errorContains: "task not found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			deleted := false
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch r.Method {
+				case "GET":
+					if tt.contentType != "" {
+						w.Header().Set("Content-Type", tt.contentType)
+					}
+					w.WriteHeader(tt.getStatus)
+					w.Write([]byte(tt.getBody))
+				case "DELETE":
+					deleted = true
+					w.WriteHeader(204)
+				default:
+					t.Errorf("Unexpected %s request", r.Method)
+				}
+			}))
+			defer server.Close()
+
+			nb := createTestBackend(t, server.URL)
+			err := nb.DeleteTask("test-list", "task-1")
+
+			if deleted != tt.expectDelete {
+				t.Errorf("DELETE issued = %v, want %v", deleted, tt.expectDelete)
+			}
+			if tt.errorContains == "" {
+				if err != nil {
+					t.Errorf("DeleteTask() unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.errorContains) {
+				t.Errorf("DeleteTask() error = %v, want error containing %q", err, tt.errorContains)
+			}
+		})
+	}
+}
+
+func TestNextcloudBackend_GetTask(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "GET" {
+			t.Errorf("Unexpected %s request", r.Method)
+		}
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/test-list/task-1.ics"):
+			w.Header().Set("Content-Type", "text/calendar")
+			w.Write([]byte(vtodoResource("task-1")))
+		default:
+			w.WriteHeader(404)
+		}
+	}))
+	defer server.Close()
+
+	nb := createTestBackend(t, server.URL)
+	task, err := nb.GetTask("test-list", "task-1")
+	if err != nil {
+		t.Fatalf("GetTask() error = %v", err)
+	}
+	if task.UID != "task-1" || task.Summary != "Task" {
+		t.Errorf("GetTask() = %+v", task)
+	}
+
+	_, err = nb.GetTask("test-list", "task-2")
+	if backendErr, ok := err.(*backend.BackendError); !ok || !backendErr.IsNotFound() {
+		t.Errorf("GetTask(task-2) error = %v, want a not found error", err)
+	}
+}
+
+func TestNextcloudBackend_DeleteTask_SkipsVerification(t *testing.T) {
+	var methods []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		methods = append(methods, r.Method)
+		switch r.Method {
+		case "REPORT":
+			w.WriteHeader(207)
+			w.Write([]byte(`<d:multistatus xmlns:d="DAV:"><d:response><d:propstat><d:prop><cal:calendar-data>
+BEGIN:VTODO
+UID:task-1
+SUMMARY:Fetched
+END:VTODO
+</cal:calendar-data></d:prop></d:propstat></d:response></d:multistatus>`))
+		case "DELETE":
+			w.WriteHeader(204)
+		default:
+			t.Errorf("Unexpected %s request", r.Method)
+		}
+	}))
+	defer server.Close()
+
+	t.Run("force delete", func(t *testing.T) {
+		methods = nil
+		nb := createTestBackend(t, server.URL)
+		if err := nb.ForceDeleteTask("test-list", "task-1"); err != nil {
+			t.Fatalf("ForceDeleteTask() error = %v", err)
+		}
+		if strings.Join(methods, ",") != "DELETE" {
+			t.Errorf("Requests = %v, want only DELETE", methods)
+		}
+	})
+
+	t.Run("task fetched in same operation", func(t *testing.T) {
+		methods = nil
+		nb := createTestBackend(t, server.URL)
+		if _, err := nb.GetTasks("test-list", nil); err != nil {
+			t.Fatalf("GetTasks() error = %v", err)
+		}
+		if err := nb.DeleteTask("test-list", "task-1"); err != nil {
+			t.Fatalf("DeleteTask() error = %v", err)
+		}
+		if strings.Join(methods, ",") != "REPORT,DELETE" {
+			t.Errorf("Requests = %v, want REPORT then DELETE", methods)
+		}
+	})
+}
+
 func TestNextcloudBackend_CreateTaskList(t *testing.T) {
 	tests := []struct {
 		name           string
@@ -856,6 +1385,13 @@ func TestNextcloudBackend_CreateTaskList(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			// Create mock server
 			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				// The lists are read first, to find one with the same name
+				if r.Method == "PROPFIND" {
+					w.WriteHeader(http.StatusMultiStatus)
+					_, _ = w.Write([]byte(`<?xml version="1.0"?><d:multistatus xmlns:d="DAV:"></d:multistatus>`))
+					return
+				}
+
 				// Verify request method
 				if r.Method != "MKCOL" {
 					t.Errorf("Expected MKCOL request, got %s", r.Method)
@@ -1153,7 +1689,7 @@ func TestHTTPSEnforcement(t *testing.T) {
 			}
 
 			// Create backend
-			nb := &NextcloudBackend{
+			nb := &Backend{
 				Connector: config,
 			}
 
@@ -1185,7 +1721,7 @@ func TestHTTPSEnforcementDefault(t *testing.T) {
 		// AllowHTTP is not set, defaults to false
 	}
 
-	nb := &NextcloudBackend{
+	nb := &Backend{
 		Connector: config,
 	}
 
@@ -1196,3 +1732,72 @@ func TestHTTPSEnforcementDefault(t *testing.T) {
 		t.Errorf("Expected HTTPS by default, got %s", baseURL)
 	}
 }
+
+// TestNextcloudBackend_RejectsEmptyIdentifiers tests that empty UIDs and list IDs
+// are rejected before any request reaches the server
+func TestNextcloudBackend_RejectsEmptyIdentifiers(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	nb := createTestBackend(t, server.URL)
+
+	tests := []struct {
+		name string
+		call func() error
+	}{
+		{"update empty uid", func() error { return nb.UpdateTask("test-list", backend.Task{UID: "", Summary: "x"}) }},
+		{"update whitespace uid", func() error { return nb.UpdateTask("test-list", backend.Task{UID: "  ", Summary: "x"}) }},
+		{"update empty list", func() error { return nb.UpdateTask("", backend.Task{UID: "task-1", Summary: "x"}) }},
+		{"delete empty uid", func() error { return nb.DeleteTask("test-list", "") }},
+		{"delete whitespace list", func() error { return nb.DeleteTask(" ", "task-1") }},
+		{"force delete empty uid", func() error { return nb.ForceDeleteTask("test-list", "\t") }},
+		{"add whitespace uid", func() error {
+			_, err := nb.AddTask("test-list", backend.Task{UID: " ", Summary: "x"})
+			return err
+		}},
+		{"add empty list", func() error {
+			_, err := nb.AddTask("", backend.Task{Summary: "x"})
+			return err
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.call()
+			backendErr, ok := err.(*backend.BackendError)
+			if !ok || !backendErr.IsValidation() {
+				t.Errorf("expected validation BackendError, got %v", err)
+			}
+		})
+	}
+
+	if requests != 0 {
+		t.Errorf("expected no HTTP requests, got %d", requests)
+	}
+}
+
+// TestDescribeRequests tests the requests --explain shows for Nextcloud writes
+func TestDescribeRequests(t *testing.T) {
+	nb := createTestBackend(t, "https://cloud.example.com")
+	taskURL := "https://cloud.example.com/remote.php/dav/calendars/testuser/tasks/"
+
+	tests := []struct {
+		method string
+		task   backend.Task
+		want   []string
+	}{
+		{method: "AddTask", task: backend.Task{Summary: "New"}, want: []string{"PUT " + taskURL + "<new UID>.ics"}},
+		{method: "UpdateTask", task: backend.Task{UID: "t1"}, want: []string{"PUT " + taskURL + "t1.ics"}},
+		{method: "DeleteTask", task: backend.Task{UID: "t1"}, want: []string{"GET " + taskURL + "t1.ics", "DELETE " + taskURL + "t1.ics"}},
+		{method: "ForceDeleteTask", task: backend.Task{UID: "t1"}, want: []string{"DELETE " + taskURL + "t1.ics"}},
+	}
+	for _, tt := range tests {
+		if got := nb.DescribeRequests(tt.method, "tasks", tt.task); strings.Join(got, "|") != strings.Join(tt.want, "|") {
+			t.Errorf("DescribeRequests(%s) = %v, want %v", tt.method, got, tt.want)
+		}
+	}
+}
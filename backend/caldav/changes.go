@@ -0,0 +1,160 @@
+package caldav
+
+import (
+	"encoding/xml"
+	"fmt"
+	"gosynctasks/backend"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+var _ backend.ChangeTracker = (*Backend)(nil)
+
+var (
+	// propstatPattern matches the propstat of a response; responses of
+	// deleted members have a bare 404 status instead
+	propstatPattern = regexp.MustCompile(`<(?:\w+:)?propstat[\s>]`)
+	statusPattern   = regexp.MustCompile(`<(?:\w+:)?status>\s*HTTP/\d(?:\.\d)?\s+(\d{3})`)
+)
+
+// buildSyncCollection builds the body of a sync-collection REPORT (RFC 6578)
+// asking for the members changed since syncToken
+func buildSyncCollection(syncToken string) string {
+	var token strings.Builder
+	_ = xml.EscapeText(&token, []byte(syncToken))
+	return `<?xml version="1.0" encoding="utf-8" ?>
+<d:sync-collection xmlns:d="DAV:">
+  <d:sync-token>` + token.String() + `</d:sync-token>
+  <d:sync-level>1</d:sync-level>
+  <d:prop>
+    <d:getetag />
+  </d:prop>
+</d:sync-collection>`
+}
+
+// GetChanges lists the calendar objects added, modified or deleted in a list
+// since syncToken with a sync-collection REPORT, which only carries hrefs and
+// ETags. A token the server no longer accepts is a 403 BackendError.
+func (b *Backend) GetChanges(listID, syncToken string) (*backend.ListChanges, error) {
+	headers := map[string]string{
+		"Content-Type": "application/xml",
+		"Depth":        "0",
+	}
+	listURL, err := b.buildListURL(listID)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := b.makeAuthenticatedRequest("REPORT", listURL, strings.NewReader(buildSyncCollection(syncToken)), headers)
+	if err != nil {
+		return nil, fmt.Errorf("HTTP request failed: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode == http.StatusForbidden && strings.Contains(string(respBody), "valid-sync-token") {
+		return nil, backend.NewBackendError("GetChanges", resp.StatusCode, "sync token no longer valid").
+			WithListID(listID).
+			WithBody(string(respBody))
+	}
+	if resp.StatusCode != http.StatusMultiStatus {
+		resp.Body = io.NopCloser(strings.NewReader(string(respBody)))
+		if err := b.checkHTTPResponse(resp, "GetChanges", http.StatusMultiStatus); err != nil {
+			return nil, err
+		}
+	}
+
+	return parseSyncCollection(string(respBody), listURL)
+}
+
+// parseSyncCollection reads the changed and deleted members and the new sync
+// token of a sync-collection response. A list too large to report at once is
+// an error, so the caller fetches it in full.
+func parseSyncCollection(xmlData, listURL string) (*backend.ListChanges, error) {
+	changes := &backend.ListChanges{}
+	for _, response := range responsePattern.FindAllString(xmlData, -1) {
+		href := extractXMLValue(response, "href")
+		if href == "" {
+			continue
+		}
+		status := ""
+		if match := statusPattern.FindStringSubmatch(response); match != nil {
+			status = match[1]
+		}
+		if strings.HasSuffix(href, "/") && strings.HasSuffix(listURL, href) {
+			if status == "507" {
+				return nil, fmt.Errorf("too many changes to report since the sync token")
+			}
+			continue // The list itself
+		}
+		if !propstatPattern.MatchString(response) && status == "404" {
+			changes.Deleted = append(changes.Deleted, href)
+		} else {
+			changes.Changed = append(changes.Changed, href)
+		}
+	}
+
+	// The token follows the responses, outside of them
+	rest := responsePattern.ReplaceAllString(xmlData, "")
+	changes.SyncToken = extractXMLValue(rest, "sync-token")
+	if changes.SyncToken == "" {
+		return nil, fmt.Errorf("no sync token in the sync-collection response")
+	}
+	return changes, nil
+}
+
+// GetTasksAt fetches the calendar objects at hrefs with a calendar-multiget
+// REPORT
+func (b *Backend) GetTasksAt(listID string, hrefs []string) ([]backend.Task, error) {
+	if len(hrefs) == 0 {
+		return nil, nil
+	}
+
+	var body strings.Builder
+	body.WriteString(`<?xml version="1.0" encoding="utf-8" ?>
+<c:calendar-multiget xmlns:d="DAV:" xmlns:c="urn:ietf:params:xml:ns:caldav">
+  <d:prop>
+    <d:getetag />
+    <c:calendar-data />
+  </d:prop>
+`)
+	for _, href := range hrefs {
+		body.WriteString("  <d:href>")
+		_ = xml.EscapeText(&body, []byte(href))
+		body.WriteString("</d:href>\n")
+	}
+	body.WriteString("</c:calendar-multiget>")
+
+	headers := map[string]string{
+		"Content-Type": "application/xml",
+		"Depth":        "1",
+	}
+	listURL, err := b.buildListURL(listID)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := b.makeAuthenticatedRequest("REPORT", listURL, strings.NewReader(body.String()), headers)
+	if err != nil {
+		return nil, fmt.Errorf("HTTP request failed: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if err := b.checkHTTPResponse(resp, "GetTasksAt"); err != nil {
+		return nil, err
+	}
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	tasks, err := b.parseVTODOs(string(respBody))
+	if err != nil {
+		return nil, err
+	}
+	b.rememberFetched(listID, tasks)
+	return tasks, nil
+}
@@ -0,0 +1,115 @@
+package caldav
+
+import (
+	"gosynctasks/backend"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestNextcloudBackend_GetChanges(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if r.Method != "REPORT" {
+			t.Errorf("Expected REPORT, got %s", r.Method)
+		}
+		switch {
+		case strings.Contains(string(body), "sync-collection"):
+			if r.Header.Get("Depth") != "0" {
+				t.Errorf("Expected Depth: 0 for sync-collection, got %q", r.Header.Get("Depth"))
+			}
+			if strings.Contains(string(body), "<d:sync-token>expired</d:sync-token>") {
+				w.WriteHeader(http.StatusForbidden)
+				w.Write([]byte(`<?xml version="1.0"?><d:error xmlns:d="DAV:"><d:valid-sync-token/></d:error>`))
+				return
+			}
+			if !strings.Contains(string(body), "<d:sync-token>http://sabre.io/ns/sync/3</d:sync-token>") {
+				t.Errorf("Expected the stored token in the request, got %s", body)
+			}
+			w.WriteHeader(http.StatusMultiStatus)
+			w.Write([]byte(`<?xml version="1.0"?>
+<d:multistatus xmlns:d="DAV:">
+  <d:response>
+    <d:href>/remote.php/dav/calendars/testuser/tasks/new.ics</d:href>
+    <d:propstat><d:prop><d:getetag>"e1"</d:getetag></d:prop><d:status>HTTP/1.1 200 OK</d:status></d:propstat>
+  </d:response>
+  <d:response>
+    <d:href>/remote.php/dav/calendars/testuser/tasks/gone.ics</d:href>
+    <d:status>HTTP/1.1 404 Not Found</d:status>
+  </d:response>
+  <d:sync-token>http://sabre.io/ns/sync/5</d:sync-token>
+</d:multistatus>`))
+		case strings.Contains(string(body), "calendar-multiget"):
+			if !strings.Contains(string(body), "<d:href>/remote.php/dav/calendars/testuser/tasks/new.ics</d:href>") {
+				t.Errorf("Expected the changed href in the multiget, got %s", body)
+			}
+			w.WriteHeader(http.StatusMultiStatus)
+			w.Write([]byte(`<?xml version="1.0"?>
+<d:multistatus xmlns:d="DAV:" xmlns:cal="urn:ietf:params:xml:ns:caldav">
+  <d:response>
+    <d:href>/remote.php/dav/calendars/testuser/tasks/new.ics</d:href>
+    <d:propstat><d:prop><d:getetag>"e1"</d:getetag><cal:calendar-data>BEGIN:VCALENDAR
+BEGIN:VTODO
+UID:new
+SUMMARY:New task
+STATUS:NEEDS-ACTION
+END:VTODO
+END:VCALENDAR</cal:calendar-data></d:prop><d:status>HTTP/1.1 200 OK</d:status></d:propstat>
+  </d:response>
+</d:multistatus>`))
+		default:
+			t.Errorf("Unexpected REPORT %s", body)
+		}
+	}))
+	defer server.Close()
+
+	nb := createTestBackend(t, server.URL)
+
+	changes, err := nb.GetChanges("tasks", "http://sabre.io/ns/sync/3")
+	if err != nil {
+		t.Fatalf("GetChanges() error = %v", err)
+	}
+	if !slices.Equal(changes.Changed, []string{"/remote.php/dav/calendars/testuser/tasks/new.ics"}) {
+		t.Errorf("Changed = %v, want new.ics", changes.Changed)
+	}
+	if !slices.Equal(changes.Deleted, []string{"/remote.php/dav/calendars/testuser/tasks/gone.ics"}) {
+		t.Errorf("Deleted = %v, want gone.ics", changes.Deleted)
+	}
+	if changes.SyncToken != "http://sabre.io/ns/sync/5" {
+		t.Errorf("SyncToken = %q, want the new token", changes.SyncToken)
+	}
+
+	tasks, err := nb.GetTasksAt("tasks", changes.Changed)
+	if err != nil {
+		t.Fatalf("GetTasksAt() error = %v", err)
+	}
+	if len(tasks) != 1 || tasks[0].UID != "new" || tasks[0].ETag != `"e1"` {
+		t.Errorf("GetTasksAt() = %+v, want task new with its ETag", tasks)
+	}
+
+	_, err = nb.GetChanges("tasks", "expired")
+	if backendErr, ok := err.(*backend.BackendError); !ok || backendErr.StatusCode != http.StatusForbidden {
+		t.Errorf("GetChanges(expired) error = %v, want a 403 BackendError", err)
+	}
+}
+
+func TestParseSyncCollectionTruncated(t *testing.T) {
+	response := `<?xml version="1.0"?>
+<d:multistatus xmlns:d="DAV:">
+  <d:response>
+    <d:href>/remote.php/dav/calendars/testuser/tasks/a.ics</d:href>
+    <d:propstat><d:prop><d:getetag>"e1"</d:getetag></d:prop><d:status>HTTP/1.1 200 OK</d:status></d:propstat>
+  </d:response>
+  <d:response>
+    <d:href>/remote.php/dav/calendars/testuser/tasks/</d:href>
+    <d:status>HTTP/1.1 507 Insufficient Storage</d:status>
+  </d:response>
+  <d:sync-token>http://sabre.io/ns/sync/9</d:sync-token>
+</d:multistatus>`
+	if _, err := parseSyncCollection(response, "https://cloud.example.com/remote.php/dav/calendars/testuser/tasks/"); err == nil {
+		t.Error("parseSyncCollection() of a truncated response succeeded, want an error")
+	}
+}
@@ -0,0 +1,209 @@
+package caldav
+
+import (
+	"fmt"
+	"gosynctasks/backend"
+	"gosynctasks/backend/conformance"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// fakeCalendar is a calendar collection on fakeCalDAV
+type fakeCalendar struct {
+	name    string
+	ctag    int
+	objects map[string]string // Resource name -> iCalendar data
+}
+
+// fakeCalDAV is an in-memory CalDAV server answering the requests
+// Backend makes, for tests that need state across requests
+type fakeCalDAV struct {
+	mu        sync.Mutex
+	calendars map[string]*fakeCalendar
+}
+
+const fakeCalDAVRoot = "/remote.php/dav/calendars/testuser/"
+
+var displayNamePattern = regexp.MustCompile(`(?s)<d:displayname>(.*?)</d:displayname>`)
+
+// newFakeCalDAVBackend starts a fakeCalDAV server and returns a backend using it
+func newFakeCalDAVBackend(t *testing.T) *Backend {
+	t.Helper()
+	fake := &fakeCalDAV{calendars: make(map[string]*fakeCalendar)}
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+	return createTestBackend(t, server.URL)
+}
+
+func (f *fakeCalDAV) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if !strings.HasPrefix(r.URL.Path, fakeCalDAVRoot) {
+		http.NotFound(w, r)
+		return
+	}
+	parts := strings.Split(strings.TrimPrefix(r.URL.Path, fakeCalDAVRoot), "/")
+	body, _ := io.ReadAll(r.Body)
+
+	switch {
+	case parts[0] == "" && r.Method == "PROPFIND":
+		f.listCalendars(w)
+	case len(parts) == 2 && parts[1] == "":
+		f.serveCalendar(w, r.Method, parts[0], string(body))
+	case len(parts) == 2:
+		f.serveObject(w, r.Method, parts[0], parts[1], string(body))
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (f *fakeCalDAV) listCalendars(w http.ResponseWriter) {
+	ids := make([]string, 0, len(f.calendars))
+	for id := range f.calendars {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	var sb strings.Builder
+	sb.WriteString(`<?xml version="1.0"?>` + "\n" + `<d:multistatus xmlns:d="DAV:" xmlns:cal="urn:ietf:params:xml:ns:caldav" xmlns:cs="http://calendarserver.org/ns/">` + "\n")
+	for _, id := range ids {
+		calendar := f.calendars[id]
+		fmt.Fprintf(&sb, `    <d:response>
+        <d:href>%s%s/</d:href>
+        <d:propstat>
+            <d:prop>
+                <d:resourcetype><d:collection/><cal:calendar/></d:resourcetype>
+                <d:displayname>%s</d:displayname>
+                <cal:supported-calendar-component-set>
+                    <cal:comp name="VTODO"/>
+                </cal:supported-calendar-component-set>
+                <cs:getctag>%d</cs:getctag>
+            </d:prop>
+            <d:status>HTTP/1.1 200 OK</d:status>
+        </d:propstat>
+    </d:response>
+`, fakeCalDAVRoot, id, xmlEscape(calendar.name), calendar.ctag)
+	}
+	sb.WriteString("</d:multistatus>")
+	w.WriteHeader(http.StatusMultiStatus)
+	_, _ = io.WriteString(w, sb.String())
+}
+
+func (f *fakeCalDAV) serveCalendar(w http.ResponseWriter, method, id, body string) {
+	calendar := f.calendars[id]
+
+	switch method {
+	case "MKCOL":
+		if calendar != nil {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		name := id
+		if match := displayNamePattern.FindStringSubmatch(body); match != nil {
+			name = match[1]
+		}
+		f.calendars[id] = &fakeCalendar{name: name, objects: make(map[string]string)}
+		w.WriteHeader(http.StatusCreated)
+		return
+	}
+
+	if calendar == nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	switch method {
+	case "REPORT":
+		names := make([]string, 0, len(calendar.objects))
+		for name := range calendar.objects {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		var sb strings.Builder
+		sb.WriteString(`<?xml version="1.0"?>` + "\n" + `<d:multistatus xmlns:d="DAV:" xmlns:cal="urn:ietf:params:xml:ns:caldav">` + "\n")
+		for _, name := range names {
+			fmt.Fprintf(&sb, `    <d:response>
+        <d:href>%s%s/%s</d:href>
+        <d:propstat>
+            <d:prop>
+                <cal:calendar-data>%s</cal:calendar-data>
+            </d:prop>
+            <d:status>HTTP/1.1 200 OK</d:status>
+        </d:propstat>
+    </d:response>
+`, fakeCalDAVRoot, id, name, xmlEscape(calendar.objects[name]))
+		}
+		sb.WriteString("</d:multistatus>")
+		w.WriteHeader(http.StatusMultiStatus)
+		_, _ = io.WriteString(w, sb.String())
+	case "PROPPATCH":
+		if match := displayNamePattern.FindStringSubmatch(body); match != nil {
+			calendar.name = match[1]
+		}
+		w.WriteHeader(http.StatusMultiStatus)
+	case "DELETE":
+		delete(f.calendars, id)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func (f *fakeCalDAV) serveObject(w http.ResponseWriter, method, id, name, body string) {
+	calendar := f.calendars[id]
+	if calendar == nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	switch method {
+	case "PUT":
+		_, exists := calendar.objects[name]
+		calendar.objects[name] = body
+		calendar.ctag++
+		if exists {
+			w.WriteHeader(http.StatusNoContent)
+		} else {
+			w.WriteHeader(http.StatusCreated)
+		}
+	case "GET":
+		data, exists := calendar.objects[name]
+		if !exists {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+		_, _ = io.WriteString(w, data)
+	case "DELETE":
+		if _, exists := calendar.objects[name]; !exists {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		delete(calendar.objects, name)
+		calendar.ctag++
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+// xmlEscape escapes markup characters, leaving line endings as servers send them
+var xmlEscape = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace
+
+// TestConformance runs the backend-agnostic TaskManager suite against an in-memory CalDAV server
+func TestConformance(t *testing.T) {
+	nb := newFakeCalDAVBackend(t)
+	conformance.Run(t, func() backend.TaskManager { return nb }, conformance.Capabilities{
+		StableListIDs: true,
+		Hierarchy:     true,
+		Categories:    true,
+	})
+}
@@ -0,0 +1,49 @@
+package caldav
+
+import (
+	"fmt"
+	"gosynctasks/backend"
+	"io"
+	"strings"
+)
+
+var _ backend.CTagProber = (*Backend)(nil)
+
+// listCTagPropfind asks for nothing but the CTag of a calendar
+const listCTagPropfind = `<?xml version="1.0" encoding="utf-8" ?>
+<d:propfind xmlns:d="DAV:" xmlns:cs="http://calendarserver.org/ns/">
+  <d:prop>
+    <cs:getctag />
+  </d:prop>
+</d:propfind>`
+
+// GetListCTag reads the CTag of one calendar with a Depth: 0 PROPFIND, a
+// request and response of a few hundred bytes
+func (b *Backend) GetListCTag(listID string) (string, error) {
+	headers := map[string]string{
+		"Content-Type": "application/xml",
+		"Depth":        "0",
+	}
+	listURL, err := b.buildListURL(listID)
+	if err != nil {
+		return "", err
+	}
+	resp, err := b.makeAuthenticatedRequest("PROPFIND", listURL, strings.NewReader(listCTagPropfind), headers)
+	if err != nil {
+		return "", fmt.Errorf("HTTP request failed: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode == 404 {
+		return "", backend.NewBackendError("GetListCTag", 404, "task list not found").WithListID(listID)
+	}
+	if err := b.checkHTTPResponse(resp, "GetListCTag"); err != nil {
+		return "", err
+	}
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read response: %w", err)
+	}
+	return extractXMLValue(string(respBody), "getctag"), nil
+}
@@ -0,0 +1,50 @@
+package caldav
+
+import (
+	"gosynctasks/backend"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestNextcloudBackend_GetListCTag(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "PROPFIND" || r.Header.Get("Depth") != "0" {
+			t.Errorf("Expected PROPFIND with Depth: 0, got %s with Depth: %s", r.Method, r.Header.Get("Depth"))
+		}
+		body, _ := io.ReadAll(r.Body)
+		if !strings.Contains(string(body), "getctag") || strings.Contains(string(body), "displayname") {
+			t.Errorf("Expected a CTag-only PROPFIND, got %s", body)
+		}
+		if strings.HasSuffix(r.URL.Path, "/missing/") {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusMultiStatus)
+		w.Write([]byte(`<?xml version="1.0"?>
+<d:multistatus xmlns:d="DAV:" xmlns:cs="http://calendarserver.org/ns/">
+  <d:response>
+    <d:href>/remote.php/dav/calendars/testuser/tasks/</d:href>
+    <d:propstat><d:prop><cs:getctag>http://sabre.io/ns/sync/42</cs:getctag></d:prop></d:propstat>
+  </d:response>
+</d:multistatus>`))
+	}))
+	defer server.Close()
+
+	nb := createTestBackend(t, server.URL)
+
+	ctag, err := nb.GetListCTag("tasks")
+	if err != nil {
+		t.Fatalf("GetListCTag() error = %v", err)
+	}
+	if ctag != "http://sabre.io/ns/sync/42" {
+		t.Errorf("GetListCTag() = %q, want the sync token", ctag)
+	}
+
+	_, err = nb.GetListCTag("missing")
+	if backendErr, ok := err.(*backend.BackendError); !ok || !backendErr.IsNotFound() {
+		t.Errorf("GetListCTag(missing) error = %v, want a not-found BackendError", err)
+	}
+}
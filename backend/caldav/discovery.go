@@ -0,0 +1,149 @@
+package caldav
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// currentUserPrincipalPropfind asks for the principal of the user the
+// request is authenticated as (RFC 5397)
+const currentUserPrincipalPropfind = `<?xml version="1.0" encoding="utf-8" ?>
+<d:propfind xmlns:d="DAV:">
+  <d:prop>
+    <d:current-user-principal />
+  </d:prop>
+</d:propfind>`
+
+// calendarHomeSetPropfind asks a principal for its calendar home (RFC 4791
+// section 6.2.1)
+const calendarHomeSetPropfind = `<?xml version="1.0" encoding="utf-8" ?>
+<d:propfind xmlns:d="DAV:" xmlns:c="urn:ietf:params:xml:ns:caldav">
+  <d:prop>
+    <c:calendar-home-set />
+  </d:prop>
+</d:propfind>`
+
+// discoverCalendarHome finds the calendar home of the user as RFC 6764
+// section 6 describes: the context path is the path of the configured URL,
+// or else where the server's /.well-known/caldav leads; its
+// current-user-principal is the user's principal, and the principal's
+// calendar-home-set the home. Servers without current-user-principal are
+// asked for the home at the context path.
+func (b *Backend) discoverCalendarHome() (string, error) {
+	if b.Connector.URL == nil {
+		return "", fmt.Errorf("connector URL is nil")
+	}
+
+	contextURL, err := b.contextURL()
+	if err != nil {
+		return "", err
+	}
+
+	principalURL, err := b.propfindHref(contextURL, currentUserPrincipalPropfind, "current-user-principal")
+	if err != nil {
+		return "", err
+	}
+	if principalURL == "" {
+		principalURL = contextURL
+	}
+
+	homeURL, err := b.propfindHref(principalURL, calendarHomeSetPropfind, "calendar-home-set")
+	if err != nil {
+		return "", err
+	}
+	if homeURL == "" {
+		return "", fmt.Errorf("no calendar home found for %s at %s", b.getUsername(), principalURL)
+	}
+	if !strings.HasSuffix(homeURL, "/") {
+		homeURL += "/"
+	}
+	return homeURL, nil
+}
+
+// contextURL returns the URL discovery starts from: the configured URL when
+// it has a path, else the target of the server's /.well-known/caldav
+// redirect, else the root of the server
+func (b *Backend) contextURL() (string, error) {
+	baseURL := b.getBaseURL()
+	if path := b.Connector.URL.Path; path != "" && path != "/" {
+		return resolveHref(baseURL, path)
+	}
+
+	// The redirect is followed by hand: the client would turn the PROPFIND
+	// into a GET
+	wellKnown := baseURL + "/.well-known/caldav"
+	req, err := http.NewRequest("PROPFIND", wellKnown, strings.NewReader(currentUserPrincipalPropfind))
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+	req.SetBasicAuth(b.getUsername(), b.getPassword())
+	req.Header.Set("Content-Type", "application/xml")
+	req.Header.Set("Depth", "0")
+
+	client := *b.getClient()
+	client.CheckRedirect = func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to send request: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	switch {
+	case resp.StatusCode >= 300 && resp.StatusCode < 400 && resp.Header.Get("Location") != "":
+		return resolveHref(wellKnown, resp.Header.Get("Location"))
+	case resp.StatusCode == http.StatusMultiStatus:
+		return wellKnown, nil
+	case resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden:
+		return "", b.checkHTTPResponse(resp, "Discover")
+	default:
+		return baseURL + "/", nil
+	}
+}
+
+// propfindHref asks the resource at resourceURL for property, and returns
+// the URL of the href it holds, or "" when the resource does not have it
+func (b *Backend) propfindHref(resourceURL, propfind, property string) (string, error) {
+	headers := map[string]string{
+		"Content-Type": "application/xml",
+		"Depth":        "0",
+	}
+	resp, err := b.makeAuthenticatedRequest("PROPFIND", resourceURL, strings.NewReader(propfind), headers)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if err := b.checkHTTPResponse(resp, "Discover"); err != nil {
+		return "", err
+	}
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read response: %w", err)
+	}
+
+	href := strings.TrimSpace(extractXMLElement(extractXMLElement(string(respBody), property), "href"))
+	if href == "" {
+		return "", nil
+	}
+	return resolveHref(resourceURL, href)
+}
+
+// resolveHref returns the URL of href, which may be a path, relative to the
+// URL of the resource it was read from
+func resolveHref(resourceURL, href string) (string, error) {
+	base, err := url.Parse(resourceURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid URL %q: %w", resourceURL, err)
+	}
+	ref, err := url.Parse(href)
+	if err != nil {
+		return "", fmt.Errorf("invalid href %q: %w", href, err)
+	}
+	return base.ResolveReference(ref).String(), nil
+}
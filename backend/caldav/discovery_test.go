@@ -0,0 +1,180 @@
+package caldav
+
+import (
+	"gosynctasks/backend"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+// newDiscoveryServer serves a CalDAV server whose calendar home can only be
+// found by discovery, with a calendar holding one task
+func newDiscoveryServer(t *testing.T) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if user, pass, ok := r.BasicAuth(); !ok || user != "u" || pass != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		switch {
+		case r.URL.Path == "/.well-known/caldav":
+			http.Redirect(w, r, "/dav/", http.StatusMovedPermanently)
+		case r.URL.Path == "/dav/" && strings.Contains(string(body), "current-user-principal"):
+			w.WriteHeader(http.StatusMultiStatus)
+			w.Write([]byte(`<?xml version="1.0"?>
+<D:multistatus xmlns:D="DAV:">
+  <D:response>
+    <D:href>/dav/</D:href>
+    <D:propstat><D:prop><D:current-user-principal><D:href>/dav/principals/u/</D:href></D:current-user-principal></D:prop></D:propstat>
+  </D:response>
+</D:multistatus>`))
+		case r.URL.Path == "/dav/principals/u/":
+			w.WriteHeader(http.StatusMultiStatus)
+			w.Write([]byte(`<?xml version="1.0"?>
+<D:multistatus xmlns:D="DAV:" xmlns:C="urn:ietf:params:xml:ns:caldav">
+  <D:response>
+    <D:href>/dav/principals/u/</D:href>
+    <D:propstat><D:prop>
+      <D:current-user-principal><D:href>/dav/principals/u/</D:href></D:current-user-principal>
+      <C:calendar-home-set><D:href>/dav/calendars/u</D:href></C:calendar-home-set>
+    </D:prop></D:propstat>
+  </D:response>
+</D:multistatus>`))
+		case r.URL.Path == "/dav/calendars/u/" && r.Method == "PROPFIND":
+			w.WriteHeader(http.StatusMultiStatus)
+			w.Write([]byte(`<?xml version="1.0"?>
+<multistatus xmlns="DAV:" xmlns:C="urn:ietf:params:xml:ns:caldav" xmlns:CS="http://calendarserver.org/ns/">
+  <response>
+    <href>/dav/calendars/u/chores/</href>
+    <propstat><prop>
+      <resourcetype><collection/><C:calendar/></resourcetype>
+      <displayname>Chores</displayname>
+      <C:supported-calendar-component-set><C:comp name="VTODO"/></C:supported-calendar-component-set>
+      <CS:getctag>"7"</CS:getctag>
+    </prop><status>HTTP/1.1 200 OK</status></propstat>
+  </response>
+</multistatus>`))
+		case r.URL.Path == "/dav/calendars/u/chores/" && r.Method == "REPORT":
+			w.WriteHeader(http.StatusMultiStatus)
+			w.Write([]byte(mockTasksResponse))
+		default:
+			t.Errorf("unexpected %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+}
+
+// newDiscoveringBackend returns a caldav backend for the server at rawURL,
+// over plain HTTP
+func newDiscoveringBackend(t *testing.T, rawURL string) *Backend {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return New(backend.ConnectorConfig{URL: u, AllowHTTP: true, SuppressHTTPWarning: true}, true)
+}
+
+func TestDiscoverCalendarHome(t *testing.T) {
+	server := newDiscoveryServer(t)
+	defer server.Close()
+
+	b := newDiscoveringBackend(t, strings.Replace(server.URL, "http://", "http://u:secret@", 1))
+	if got := b.GetBackendType(); got != "caldav" {
+		t.Errorf("GetBackendType() = %q, want caldav", got)
+	}
+
+	home, err := b.buildCalendarURL()
+	if err != nil {
+		t.Fatalf("buildCalendarURL() error = %v", err)
+	}
+	if want := server.URL + "/dav/calendars/u/"; home != want {
+		t.Errorf("buildCalendarURL() = %q, want %q", home, want)
+	}
+
+	lists, err := b.GetTaskLists()
+	if err != nil {
+		t.Fatalf("GetTaskLists() error = %v", err)
+	}
+	if len(lists) != 1 || lists[0].ID != "chores" || lists[0].Name != "Chores" {
+		t.Fatalf("GetTaskLists() = %+v, want the Chores calendar", lists)
+	}
+	tasks, err := b.GetTasks("chores", nil)
+	if err != nil {
+		t.Fatalf("GetTasks() error = %v", err)
+	}
+	if len(tasks) == 0 {
+		t.Error("GetTasks() returned no tasks")
+	}
+
+	// No trash on servers other than Nextcloud
+	if deleted, err := b.GetDeletedTaskLists(); err != nil || len(deleted) != 0 {
+		t.Errorf("GetDeletedTaskLists() = %v, %v, want none", deleted, err)
+	}
+	if err := b.RestoreTaskList("chores"); err == nil {
+		t.Error("RestoreTaskList() succeeded, want an error")
+	}
+}
+
+func TestDiscoverFromPath(t *testing.T) {
+	server := newDiscoveryServer(t)
+	defer server.Close()
+
+	// A URL with a path is the context path; /.well-known/caldav is not asked
+	b := newDiscoveringBackend(t, strings.Replace(server.URL, "http://", "http://u:secret@", 1)+"/dav/principals/u/")
+	home, err := b.buildCalendarURL()
+	if err != nil {
+		t.Fatalf("buildCalendarURL() error = %v", err)
+	}
+	if want := server.URL + "/dav/calendars/u/"; home != want {
+		t.Errorf("buildCalendarURL() = %q, want %q", home, want)
+	}
+}
+
+func TestDiscoverErrors(t *testing.T) {
+	server := newDiscoveryServer(t)
+	defer server.Close()
+
+	// Wrong credentials
+	b := newDiscoveringBackend(t, strings.Replace(server.URL, "http://", "http://u:wrong@", 1))
+	if _, err := b.GetTaskLists(); err == nil {
+		t.Error("GetTaskLists() with wrong credentials succeeded, want an error")
+	}
+
+	// No calendar home: the context path has no current-user-principal
+	// and no calendar-home-set
+	noHome := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusMultiStatus)
+		w.Write([]byte(`<?xml version="1.0"?><d:multistatus xmlns:d="DAV:"><d:response><d:href>/</d:href></d:response></d:multistatus>`))
+	}))
+	defer noHome.Close()
+	b = newDiscoveringBackend(t, strings.Replace(noHome.URL, "http://", "http://u:secret@", 1)+"/dav/")
+	if _, err := b.buildCalendarURL(); err == nil || !strings.Contains(err.Error(), "no calendar home") {
+		t.Errorf("buildCalendarURL() error = %v, want no calendar home", err)
+	}
+}
+
+func TestUsesHTTP(t *testing.T) {
+	tests := []struct {
+		rawURL    string
+		discover  bool
+		allowHTTP bool
+		want      bool
+	}{
+		{"http://dav.example.com/", true, true, true},
+		{"http://dav.example.com/", true, false, false},
+		{"https://dav.example.com:8080/", true, true, false},
+		{"nextcloud://cloud.example.com:8080", false, true, true},
+		{"nextcloud://cloud.example.com", false, true, false},
+	}
+	for _, tt := range tests {
+		u, _ := url.Parse(tt.rawURL)
+		b := New(backend.ConnectorConfig{URL: u, AllowHTTP: tt.allowHTTP}, tt.discover)
+		if got := b.usesHTTP(); got != tt.want {
+			t.Errorf("usesHTTP() for %s (discover %v, allow HTTP %v) = %v, want %v", tt.rawURL, tt.discover, tt.allowHTTP, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package caldav
+
+import (
+	"errors"
+	"gosynctasks/backend"
+	"gosynctasks/internal/operations"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestDuplicateListsMerge simulates the calendars left by a list creation
+// retried after a timeout, merges them keeping the subtasks under their
+// parent, and checks that creating the list again is refused
+func TestDuplicateListsMerge(t *testing.T) {
+	fake := &fakeCalDAV{calendars: map[string]*fakeCalendar{
+		"work-1719900000": {name: "Work", objects: make(map[string]string)},
+		"work-1719900031": {name: "Work", objects: make(map[string]string)},
+		"home-1719800000": {name: "Home", objects: make(map[string]string)},
+	}}
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+	nb := createTestBackend(t, server.URL)
+
+	mustAdd := func(listID string, task backend.Task) string {
+		t.Helper()
+		uid, err := nb.AddTask(listID, task)
+		if err != nil {
+			t.Fatalf("AddTask(%s, %q) error = %v", listID, task.Summary, err)
+		}
+		return uid
+	}
+	mustAdd("work-1719900000", backend.Task{Summary: "Kept task", Status: "NEEDS-ACTION"})
+	parent := mustAdd("work-1719900031", backend.Task{Summary: "Release", Status: "NEEDS-ACTION"})
+	mustAdd("work-1719900031", backend.Task{Summary: "Changelog", Status: "NEEDS-ACTION", ParentUID: parent})
+	mustAdd("work-1719900031", backend.Task{Summary: "Tag", Status: "COMPLETED", ParentUID: parent})
+
+	lists, err := nb.GetTaskLists()
+	if err != nil {
+		t.Fatalf("GetTaskLists() error = %v", err)
+	}
+	duplicates := operations.DuplicateLists(lists)
+	if len(duplicates) != 1 || len(duplicates[0]) != 2 || duplicates[0][0].Name != "Work" {
+		t.Fatalf("DuplicateLists() = %+v, want the two Work lists", duplicates)
+	}
+	if _, err := operations.FindListByNameFull(lists, "Work"); err == nil {
+		t.Error("FindListByNameFull(Work) error = nil, want the lists to be ambiguous")
+	}
+
+	named := operations.ListsNamed(lists, "work")
+	var into backend.TaskList
+	var from []backend.TaskList
+	for _, list := range named {
+		if list.ID == "work-1719900000" {
+			into = list
+		} else {
+			from = append(from, list)
+		}
+	}
+	moved, err := operations.MergeLists(nb, into, from)
+	if err != nil || moved != 3 {
+		t.Fatalf("MergeLists() = %d, %v, want 3 tasks moved", moved, err)
+	}
+
+	left, err := nb.GetTasks("work-1719900031", nil)
+	if err != nil || len(left) != 0 {
+		t.Fatalf("tasks left in the duplicate = %d, %v, want none", len(left), err)
+	}
+	tasks, err := nb.GetTasks("work-1719900000", nil)
+	if err != nil {
+		t.Fatalf("GetTasks() error = %v", err)
+	}
+	bySummary := make(map[string]backend.Task)
+	for _, task := range tasks {
+		bySummary[task.Summary] = task
+	}
+	release, ok := bySummary["Release"]
+	if len(tasks) != 4 || !ok || release.UID == parent {
+		t.Fatalf("tasks after merge = %+v, want 4 with Release under a new UID", tasks)
+	}
+	for _, summary := range []string{"Changelog", "Tag"} {
+		if got := bySummary[summary].ParentUID; got != release.UID {
+			t.Errorf("%s parent = %q, want the moved Release %q", summary, got, release.UID)
+		}
+	}
+
+	if err := nb.DeleteTaskList("work-1719900031"); err != nil {
+		t.Fatalf("DeleteTaskList() error = %v", err)
+	}
+	lists, _ = nb.GetTaskLists()
+	if duplicates := operations.DuplicateLists(lists); len(duplicates) != 0 {
+		t.Errorf("DuplicateLists() after merging = %+v, want none", duplicates)
+	}
+
+	// Creating the list again finds it instead of adding a third calendar
+	_, err = nb.CreateTaskList("work", "", "")
+	var backendErr *backend.BackendError
+	if !errors.As(err, &backendErr) || backendErr.StatusCode != 409 || backendErr.ListID != "work-1719900000" {
+		t.Errorf("CreateTaskList(work) error = %v, want a conflict naming work-1719900000", err)
+	}
+	if len(fake.calendars) != 2 {
+		t.Errorf("calendars = %d, want 2", len(fake.calendars))
+	}
+	if _, err := nb.CreateTaskList("Errands", "", ""); err != nil {
+		t.Errorf("CreateTaskList(Errands) error = %v", err)
+	}
+}
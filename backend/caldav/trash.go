@@ -0,0 +1,163 @@
+package caldav
+
+import (
+	"fmt"
+	"gosynctasks/backend"
+	"gosynctasks/internal/ical"
+	"io"
+	"path"
+	"strings"
+	"time"
+)
+
+var _ backend.TaskTrash = (*Backend)(nil)
+
+// trashObjectsPropfind asks for the deleted calendar objects with the
+// calendar each one was deleted from
+const trashObjectsPropfind = `<?xml version="1.0" encoding="utf-8" ?>
+<d:propfind xmlns:d="DAV:" xmlns:c="urn:ietf:params:xml:ns:caldav" xmlns:nc="http://nextcloud.com/ns">
+  <d:prop>
+    <c:calendar-data />
+    <nc:calendar-uri />
+    <nc:deleted-at />
+  </d:prop>
+</d:propfind>`
+
+// trashedObject is a deleted task in the Nextcloud trash bin
+type trashedObject struct {
+	name string // Resource name in the trashbin/objects collection
+	task backend.DeletedTask
+}
+
+// buildTrashObjectsURL constructs the URL of the collection of deleted calendar objects
+func (b *Backend) buildTrashObjectsURL() string {
+	return fmt.Sprintf("%s/remote.php/dav/calendars/%s/trashbin/objects/", b.getBaseURL(), b.getUsername())
+}
+
+// buildTrashRestoreURL constructs the URL a deleted object is moved to for restoring it
+func (b *Backend) buildTrashRestoreURL(name string) string {
+	return fmt.Sprintf("%s/remote.php/dav/calendars/%s/trashbin/restore/%s", b.getBaseURL(), b.getUsername(), name)
+}
+
+// GetDeletedTasks returns the tasks of a list that are in the Nextcloud trash bin
+func (b *Backend) GetDeletedTasks(listID string) ([]backend.DeletedTask, error) {
+	objects, err := b.getTrashedObjects(listID)
+	if err != nil {
+		return nil, err
+	}
+	tasks := make([]backend.DeletedTask, len(objects))
+	for i, object := range objects {
+		tasks[i] = object.task
+	}
+	return tasks, nil
+}
+
+// RestoreTask moves a task from the Nextcloud trash bin back into its list
+func (b *Backend) RestoreTask(listID string, taskUID string) error {
+	if err := backend.ValidateTaskRef("RestoreTask", listID, taskUID); err != nil {
+		return err
+	}
+
+	objects, err := b.getTrashedObjects(listID)
+	if err != nil {
+		return err
+	}
+	var name string
+	for _, object := range objects {
+		if object.task.UID == taskUID {
+			name = object.name
+			break
+		}
+	}
+	if name == "" {
+		return backend.NewBackendError("RestoreTask", 404, "task not found in trash").
+			WithTaskUID(taskUID).WithListID(listID)
+	}
+
+	// Nextcloud restores a deleted object when it is moved to trashbin/restore
+	headers := map[string]string{
+		"Destination": b.buildTrashRestoreURL(name),
+		"Overwrite":   "F", // Don't replace a task that was recreated meanwhile
+	}
+	resp, err := b.makeAuthenticatedRequest("MOVE", b.buildTrashObjectsURL()+name, nil, headers)
+	if err != nil {
+		return fmt.Errorf("failed to restore task: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode == 404 {
+		return backend.NewBackendError("RestoreTask", 404, "task not found in trash").
+			WithTaskUID(taskUID).WithListID(listID)
+	}
+	if err := b.checkHTTPResponse(resp, "RestoreTask", 201, 204); err != nil {
+		if backendErr, ok := err.(*backend.BackendError); ok {
+			return backendErr.WithTaskUID(taskUID).WithListID(listID)
+		}
+		return err
+	}
+
+	return nil
+}
+
+// getTrashedObjects lists the deleted tasks of a list in the trash bin
+func (b *Backend) getTrashedObjects(listID string) ([]trashedObject, error) {
+	// Only Nextcloud keeps deleted calendar objects
+	if b.Discover {
+		return nil, fmt.Errorf("the %s backend does not keep deleted tasks", b.GetBackendType())
+	}
+
+	headers := map[string]string{
+		"Content-Type": "application/xml",
+		"Depth":        "1",
+	}
+	resp, err := b.makeAuthenticatedRequest("PROPFIND", b.buildTrashObjectsURL(), strings.NewReader(trashObjectsPropfind), headers)
+	if err != nil {
+		return nil, fmt.Errorf("HTTP request failed: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if err := b.checkHTTPResponse(resp, "GetDeletedTasks"); err != nil {
+		return nil, err
+	}
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	return parseTrashedObjects(string(respBody), listID), nil
+}
+
+// parseTrashedObjects extracts the deleted VTODOs that belonged to listID from
+// a trashbin/objects PROPFIND response; deleted events are skipped
+func parseTrashedObjects(xmlData, listID string) []trashedObject {
+	var objects []trashedObject
+
+	for _, response := range extractResponses(xmlData) {
+		if !strings.Contains(response, "HTTP/1.1 200 OK") {
+			continue
+		}
+		if extractXMLValue(response, "calendar-uri") != listID {
+			continue
+		}
+		blocks := extractVTODOBlocks(ical.Unfold(calendarData(response)))
+		if len(blocks) == 0 {
+			continue
+		}
+		task, err := parseVTODO(blocks[0])
+		if err != nil || task.UID == "" {
+			continue
+		}
+
+		object := trashedObject{
+			name: path.Base(strings.TrimSuffix(extractXMLValue(response, "href"), "/")),
+			task: backend.DeletedTask{Task: task},
+		}
+		if deletedAt, err := time.Parse(time.RFC3339, extractXMLValue(response, "deleted-at")); err == nil {
+			object.task.DeletedAt = deletedAt
+		}
+		objects = append(objects, object)
+	}
+
+	return objects
+}
@@ -0,0 +1,149 @@
+package caldav
+
+import (
+	"errors"
+	"gosynctasks/backend"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+const mockTrashObjectsResponse = `<?xml version="1.0"?>
+<d:multistatus xmlns:d="DAV:" xmlns:cal="urn:ietf:params:xml:ns:caldav" xmlns:nc="http://nextcloud.com/ns">
+    <d:response>
+        <d:href>/remote.php/dav/calendars/testuser/trashbin/objects/7.0.task-1.ics</d:href>
+        <d:propstat>
+            <d:prop>
+                <cal:calendar-data>BEGIN:VCALENDAR
+VERSION:2.0
+BEGIN:VTODO
+UID:task-1
+SUMMARY:Release v2
+STATUS:NEEDS-ACTION
+END:VTODO
+END:VCALENDAR</cal:calendar-data>
+                <nc:calendar-uri>tasks</nc:calendar-uri>
+                <nc:deleted-at>2026-10-14T09:30:00+00:00</nc:deleted-at>
+            </d:prop>
+            <d:status>HTTP/1.1 200 OK</d:status>
+        </d:propstat>
+    </d:response>
+    <d:response>
+        <d:href>/remote.php/dav/calendars/testuser/trashbin/objects/8.0.task-2.ics</d:href>
+        <d:propstat>
+            <d:prop>
+                <cal:calendar-data>BEGIN:VCALENDAR
+VERSION:2.0
+BEGIN:VTODO
+UID:task-2
+SUMMARY:Other list
+END:VTODO
+END:VCALENDAR</cal:calendar-data>
+                <nc:calendar-uri>personal</nc:calendar-uri>
+                <nc:deleted-at>2026-10-14T09:30:00+00:00</nc:deleted-at>
+            </d:prop>
+            <d:status>HTTP/1.1 200 OK</d:status>
+        </d:propstat>
+    </d:response>
+    <d:response>
+        <d:href>/remote.php/dav/calendars/testuser/trashbin/objects/7.0.meeting.ics</d:href>
+        <d:propstat>
+            <d:prop>
+                <cal:calendar-data>BEGIN:VCALENDAR
+VERSION:2.0
+BEGIN:VEVENT
+UID:meeting
+SUMMARY:Meeting
+END:VEVENT
+END:VCALENDAR</cal:calendar-data>
+                <nc:calendar-uri>tasks</nc:calendar-uri>
+            </d:prop>
+            <d:status>HTTP/1.1 200 OK</d:status>
+        </d:propstat>
+    </d:response>
+</d:multistatus>`
+
+func TestParseTrashedObjects(t *testing.T) {
+	objects := parseTrashedObjects(mockTrashObjectsResponse, "tasks")
+	if len(objects) != 1 {
+		t.Fatalf("parseTrashedObjects() returned %d objects, want 1", len(objects))
+	}
+	object := objects[0]
+	if object.name != "7.0.task-1.ics" {
+		t.Errorf("name = %q, want 7.0.task-1.ics", object.name)
+	}
+	if object.task.UID != "task-1" || object.task.Summary != "Release v2" {
+		t.Errorf("task = %+v", object.task.Task)
+	}
+	if want := time.Date(2026, 10, 14, 9, 30, 0, 0, time.UTC); !object.task.DeletedAt.Equal(want) {
+		t.Errorf("DeletedAt = %v, want %v", object.task.DeletedAt, want)
+	}
+}
+
+// newTrashServer serves the trash PROPFIND and records the restore MOVE
+func newTrashServer(t *testing.T, moves *[]*http.Request) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "PROPFIND" && r.URL.Path == "/remote.php/dav/calendars/testuser/trashbin/objects/":
+			body, _ := io.ReadAll(r.Body)
+			if r.Header.Get("Depth") != "1" || !strings.Contains(string(body), "calendar-uri") {
+				t.Errorf("trash PROPFIND depth = %q, body = %s", r.Header.Get("Depth"), body)
+			}
+			w.WriteHeader(http.StatusMultiStatus)
+			_, _ = io.WriteString(w, mockTrashObjectsResponse)
+		case r.Method == "MOVE":
+			*moves = append(*moves, r)
+			w.WriteHeader(http.StatusCreated)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestNextcloudGetDeletedTasks(t *testing.T) {
+	var moves []*http.Request
+	nb := createTestBackend(t, newTrashServer(t, &moves).URL)
+
+	tasks, err := nb.GetDeletedTasks("tasks")
+	if err != nil {
+		t.Fatalf("GetDeletedTasks() error = %v", err)
+	}
+	if len(tasks) != 1 || tasks[0].UID != "task-1" {
+		t.Errorf("GetDeletedTasks() = %+v, want only task-1", tasks)
+	}
+}
+
+func TestNextcloudRestoreTask(t *testing.T) {
+	var moves []*http.Request
+	server := newTrashServer(t, &moves)
+	nb := createTestBackend(t, server.URL)
+
+	if err := nb.RestoreTask("tasks", "task-1"); err != nil {
+		t.Fatalf("RestoreTask() error = %v", err)
+	}
+	if len(moves) != 1 {
+		t.Fatalf("RestoreTask() made %d MOVE requests, want 1", len(moves))
+	}
+	move := moves[0]
+	if move.URL.Path != "/remote.php/dav/calendars/testuser/trashbin/objects/7.0.task-1.ics" {
+		t.Errorf("MOVE source = %s", move.URL.Path)
+	}
+	if want := server.URL + "/remote.php/dav/calendars/testuser/trashbin/restore/7.0.task-1.ics"; move.Header.Get("Destination") != want {
+		t.Errorf("Destination = %q, want %q", move.Header.Get("Destination"), want)
+	}
+	if move.Header.Get("Overwrite") != "F" {
+		t.Errorf("Overwrite = %q, want F", move.Header.Get("Overwrite"))
+	}
+
+	err := nb.RestoreTask("tasks", "task-2") // In the trash, but from another list
+	var backendErr *backend.BackendError
+	if !errors.As(err, &backendErr) || !backendErr.IsNotFound() {
+		t.Errorf("RestoreTask() of a task from another list error = %v, want not found", err)
+	}
+}
@@ -0,0 +1,410 @@
+package caldav
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"gosynctasks/backend"
+	"gosynctasks/internal/ical"
+	"gosynctasks/internal/utils"
+)
+
+var (
+	// responsePattern matches each response of a multistatus, whatever the
+	// prefix of the DAV: namespace
+	responsePattern = regexp.MustCompile(`(?s)<(?:\w+:)?response[\s>].*?</(?:\w+:)?response>`)
+	etagPattern     = regexp.MustCompile(`<(?:\w+:)?getetag>\s*([^<]*?)\s*</`)
+	// calendarDataPattern matches the calendar-data property of a response
+	calendarDataPattern = regexp.MustCompile(`(?s)<(?:\w+:)?calendar-data(?:\s[^>]*)?>(.*?)</(?:\w+:)?calendar-data>`)
+)
+
+func (b *Backend) parseVTODOs(xmlData string) ([]backend.Task, error) {
+	var tasks []backend.Task
+
+	// Each response holds a calendar object and its ETag
+	responses := responsePattern.FindAllString(xmlData, -1)
+	if len(responses) == 0 {
+		responses = []string{xmlData}
+	}
+
+	for _, response := range responses {
+		etag := ""
+		if match := etagPattern.FindStringSubmatch(response); match != nil {
+			etag = html.UnescapeString(match[1])
+		}
+		for _, vtodo := range extractVTODOBlocks(ical.Unfold(calendarData(response))) {
+			task, err := parseVTODO(vtodo)
+			if err != nil {
+				continue // Skip invalid tasks
+			}
+			task.ETag = etag
+			tasks = append(tasks, task)
+		}
+	}
+
+	return tasks, nil
+}
+
+// calendarData returns the iCalendar text of a multistatus response: its
+// calendar-data property with the XML entities decoded, or the response as
+// is when it has none
+func calendarData(response string) string {
+	match := calendarDataPattern.FindStringSubmatch(response)
+	if match == nil {
+		return response
+	}
+	data := strings.TrimSpace(match[1])
+	if cdata, ok := strings.CutPrefix(data, "<![CDATA["); ok {
+		return strings.TrimSuffix(cdata, "]]>")
+	}
+	return html.UnescapeString(data)
+}
+
+// extractVTODOBlocks returns the VTODO components among unfolded content
+// lines, each as its lines joined by LF
+func extractVTODOBlocks(lines []string) []string {
+	var blocks []string
+	var currentBlock strings.Builder
+	inVTODO := false
+
+	for _, line := range lines {
+		// Trailing spaces belong to values, a description may end with one
+		line = strings.TrimLeft(strings.TrimRight(line, "\r"), " \t")
+
+		if strings.HasPrefix(line, "BEGIN:VTODO") {
+			inVTODO = true
+			currentBlock.Reset()
+			currentBlock.WriteString(line + "\n")
+		} else if strings.HasPrefix(line, "END:VTODO") && inVTODO {
+			currentBlock.WriteString(line + "\n")
+			blocks = append(blocks, currentBlock.String())
+			inVTODO = false
+		} else if inVTODO {
+			currentBlock.WriteString(line + "\n")
+		}
+	}
+
+	return blocks
+}
+
+func parseVTODO(vtodo string) (backend.Task, error) {
+	task := backend.Task{
+		Status:   "NEEDS-ACTION",
+		Priority: 0,
+		Created:  time.Now(),
+		Modified: time.Now(),
+	}
+
+	lines := strings.SplitSeq(vtodo, "\n")
+
+	for line := range lines {
+		line = strings.TrimLeft(strings.TrimRight(line, "\r"), " \t")
+		if line == "" {
+			continue
+		}
+
+		prop, err := ical.ParseProperty(line)
+		if err != nil {
+			continue
+		}
+
+		switch value := prop.Value; prop.Name {
+		case "UID":
+			task.UID = value
+		case "SUMMARY":
+			task.Summary = ical.UnescapeText(value)
+		case "DESCRIPTION":
+			task.Description = ical.UnescapeText(value)
+		case "STATUS":
+			task.Status = value
+		case "PRIORITY":
+			if p := parseInt(value); p >= 0 && p <= 9 {
+				task.Priority = p
+			}
+		case "CREATED":
+			if t, _, err := ical.ParseTime(value, prop.Params); err == nil {
+				task.Created = t
+			}
+		case "LAST-MODIFIED":
+			if t, _, err := ical.ParseTime(value, prop.Params); err == nil {
+				task.Modified = t
+			}
+		case "DUE", "DTSTART":
+			_ = ical.SetDate(&task, prop) // Invalid dates are left out
+		case "COMPLETED":
+			if t, _, err := ical.ParseTime(value, prop.Params); err == nil {
+				task.Completed = &t
+			}
+		case "CATEGORIES":
+			task.Categories = append(task.Categories, ical.SplitCategories(value)...)
+		case "RELATED-TO":
+			task.ParentUID = value
+		case "RRULE":
+			task.Recurrence = value
+		case "SEQUENCE":
+			if n := parseInt(value); n > 0 {
+				task.Sequence = n
+			}
+		}
+	}
+
+	if task.UID == "" {
+		return task, fmt.Errorf("missing UID")
+	}
+
+	return task, nil
+}
+
+func parseInt(s string) int {
+	if i, err := strconv.Atoi(s); err == nil {
+		return i
+	}
+	return 0
+}
+
+func (b *Backend) parseTaskLists(xmlData, baseURL string) ([]backend.TaskList, error) {
+	return parseCalendarCollections(xmlData, baseURL, false), nil
+}
+
+func (b *Backend) parseDeletedTaskLists(xmlData, baseURL string) ([]backend.TaskList, error) {
+	return parseCalendarCollections(xmlData, baseURL, true), nil
+}
+
+// parseCalendarCollections returns the task lists in a PROPFIND response of
+// the calendar home: the calendars supporting VTODO, either the live ones or
+// the ones in the trash. Each entry is checked on its own, so a malformed one
+// is skipped with a warning instead of failing the whole list.
+func parseCalendarCollections(xmlData, baseURL string, deleted bool) []backend.TaskList {
+	var taskLists []backend.TaskList
+	var malformed []string
+
+	for _, response := range extractResponses(xmlData) {
+		taskList, ok, err := parseCalendarEntry(response, baseURL)
+		if err != nil {
+			malformed = append(malformed, err.Error())
+			continue
+		}
+		if ok && isDeletedCalendar(response) == deleted {
+			taskLists = append(taskLists, taskList)
+		}
+	}
+
+	if len(malformed) > 0 {
+		utils.Warnf("Skipped %d malformed calendar entries: %s", len(malformed), strings.Join(malformed, "; "))
+	}
+
+	return taskLists
+}
+
+// parseCalendarEntry parses one response of the calendar home. ok is false
+// for collections that are not task lists: inbox, outbox, the trash bin,
+// subscriptions, calendars without VTODO support and unnamed collections.
+func parseCalendarEntry(response, baseURL string) (taskList backend.TaskList, ok bool, err error) {
+	if !responseEndPattern.MatchString(response) {
+		return backend.TaskList{}, false, fmt.Errorf("truncated entry %q", truncateForError(response))
+	}
+
+	taskList = parseTaskListResponse(response, baseURL)
+	if taskList.ID == "" {
+		return backend.TaskList{}, false, fmt.Errorf("entry without href %q", truncateForError(response))
+	}
+
+	// Only properties returned with 200 OK describe the collection
+	if !strings.Contains(response, "HTTP/1.1 200 OK") {
+		return taskList, false, nil
+	}
+
+	// Skip trashbin, inbox, outbox, and other special collections
+	if taskList.ID == "trashbin" || taskList.ID == "inbox" || taskList.ID == "outbox" {
+		return taskList, false, nil
+	}
+
+	if !calendarTypePattern.MatchString(extractXMLElement(response, "resourcetype")) {
+		return taskList, false, nil
+	}
+	if !containsVTODO(response) {
+		return taskList, false, nil
+	}
+	if taskList.Name == "" {
+		return taskList, false, nil
+	}
+
+	return taskList, true, nil
+}
+
+var (
+	// responseEndPattern matches the closing tag of a complete response
+	responseEndPattern = regexp.MustCompile(`</(?:\w+:)?response>\s*$`)
+
+	// calendarTypePattern matches the calendar element of a resourcetype,
+	// but not schedule-inbox, subscribed or deleted-calendar
+	calendarTypePattern = regexp.MustCompile(`<(?:\w+:)?calendar(?:\s[^>]*)?/?>`)
+
+	// vtodoComponentPattern matches VTODO in a supported-calendar-component-set
+	vtodoComponentPattern = regexp.MustCompile(`<(?:\w+:)?comp\s+name=["']VTODO["']`)
+)
+
+// extractXMLElement returns the content of the first element named tag, with
+// any namespace prefix, or "" when there is none
+func extractXMLElement(xml, tag string) string {
+	pattern := regexp.MustCompile(`(?s)<(?:\w+:)?` + regexp.QuoteMeta(tag) + `(?:\s[^>]*)?>(.*?)</(?:\w+:)?` + regexp.QuoteMeta(tag) + `>`)
+	if match := pattern.FindStringSubmatch(xml); match != nil {
+		return match[1]
+	}
+	return ""
+}
+
+// truncateForError shortens a response for an error message
+func truncateForError(response string) string {
+	response = strings.Join(strings.Fields(response), " ")
+	if len(response) > 80 {
+		return response[:80] + "..."
+	}
+	return response
+}
+
+// isDeletedCalendar checks if a calendar response contains the deleted-calendar resourcetype
+func isDeletedCalendar(response string) bool {
+	// Check for the deleted-calendar element in resourcetype
+	// The element can appear in several forms:
+	// 1. <deleted-calendar xmlns="http://nextcloud.com/ns"/>
+	// 2. <nc:deleted-calendar/>
+	// 3. Within a resourcetype: <d:resourcetype>...<deleted-calendar/>...</d:resourcetype>
+
+	// First, try to find the resourcetype section
+	resourceTypeStart := strings.Index(response, "<resourcetype>")
+	if resourceTypeStart == -1 {
+		resourceTypeStart = strings.Index(response, "<d:resourcetype>")
+	}
+
+	if resourceTypeStart != -1 {
+		// Find the end of resourcetype
+		resourceTypeEnd := strings.Index(response[resourceTypeStart:], "</resourcetype>")
+		if resourceTypeEnd == -1 {
+			resourceTypeEnd = strings.Index(response[resourceTypeStart:], "</d:resourcetype>")
+		}
+
+		if resourceTypeEnd != -1 {
+			// Extract the resourcetype content
+			resourceTypeContent := response[resourceTypeStart : resourceTypeStart+resourceTypeEnd]
+
+			// Check if deleted-calendar is present in resourcetype
+			return strings.Contains(resourceTypeContent, "deleted-calendar")
+		}
+	}
+
+	// Fallback: check anywhere in response (less accurate but safer)
+	return strings.Contains(response, "deleted-calendar")
+}
+
+// containsVTODO reports whether a calendar response lists VTODO among its
+// supported components
+func containsVTODO(response string) bool {
+	return vtodoComponentPattern.MatchString(extractXMLElement(response, "supported-calendar-component-set"))
+}
+
+func extractResponses(xmlData string) []string {
+	var responses []string
+
+	// Try different response tag patterns
+	patterns := []string{
+		"<d:response>",
+		"<response>",
+		"<D:response>",
+	}
+
+	for _, startTag := range patterns {
+		endTag := strings.Replace(startTag, "<", "</", 1)
+
+		data := xmlData
+		for {
+			start := strings.Index(data, startTag)
+			if start == -1 {
+				break
+			}
+
+			end := strings.Index(data[start:], endTag)
+			if end == -1 {
+				// Keep the truncated last entry, so it is reported as malformed
+				responses = append(responses, data[start:])
+				break
+			}
+
+			// A response missing its end tag runs into the next one; cut it
+			// there so the next response is still parsed on its own
+			if next := strings.Index(data[start+len(startTag):start+end], startTag); next != -1 {
+				responses = append(responses, data[start:start+len(startTag)+next])
+				data = data[start+len(startTag)+next:]
+				continue
+			}
+
+			response := data[start : start+end+len(endTag)]
+			responses = append(responses, response)
+			data = data[start+end+len(endTag):]
+		}
+
+		if len(responses) > 0 {
+			break
+		}
+	}
+
+	// fmt.Printf("extractResponses found %d responses\n", len(responses))
+	return responses
+}
+
+func parseTaskListResponse(response, baseURL string) backend.TaskList {
+	taskList := backend.TaskList{}
+
+	// Extract href (calendar ID)
+	if href := extractXMLValue(response, "href"); href != "" {
+		// Extract calendar ID from href path
+		parts := strings.Split(strings.Trim(href, "/"), "/")
+		if len(parts) > 0 {
+			taskList.ID = parts[len(parts)-1]
+		}
+		taskList.URL = href
+	}
+
+	// Extract displayname
+	taskList.Name = extractXMLValue(response, "displayname")
+
+	// Extract ctag
+	taskList.CTags = extractXMLValue(response, "getctag")
+	taskList.SyncToken = extractXMLValue(response, "sync-token")
+
+	// Extract color
+	taskList.Color = extractXMLValue(response, "calendar-color")
+
+	// Extract deleted-at timestamp (Nextcloud trash)
+	taskList.DeletedAt = extractXMLValue(response, "deleted-at")
+
+	return taskList
+}
+
+func extractXMLValue(xml, tag string) string {
+	// Try without namespace prefix first
+	if start := strings.Index(xml, fmt.Sprintf("<%s>", tag)); start != -1 {
+		start += len(tag) + 2
+		if end := strings.Index(xml[start:], fmt.Sprintf("</%s>", tag)); end != -1 {
+			return strings.TrimSpace(xml[start : start+end])
+		}
+	}
+
+	// Try with namespace prefixes
+	for _, prefix := range []string{"d:", "cs:", "ic:", "nc:"} {
+		fullTag := prefix + tag
+		if start := strings.Index(xml, fmt.Sprintf("<%s>", fullTag)); start != -1 {
+			start += len(fullTag) + 2
+			if end := strings.Index(xml[start:], fmt.Sprintf("</%s>", fullTag)); end != -1 {
+				return strings.TrimSpace(xml[start : start+end])
+			}
+		}
+	}
+
+	// Other servers pick their own prefixes, such as Radicale's CS: and ICAL:
+	return strings.TrimSpace(extractXMLElement(xml, tag))
+}
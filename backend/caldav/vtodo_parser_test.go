@@ -1,126 +1,21 @@
-package nextcloud
+package caldav
 
 import (
+	"encoding/xml"
+	"fmt"
 	"gosynctasks/backend"
+	"gosynctasks/internal/ical"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
 	"time"
+	"unicode/utf8"
 )
 
-func TestParseICalTime(t *testing.T) {
-	tests := []struct {
-		name      string
-		input     string
-		wantError bool
-		expected  string // Expected time in RFC3339 format for comparison
-	}{
-		{
-			name:      "UTC time format",
-			input:     "20240315T143000Z",
-			wantError: false,
-			expected:  "2024-03-15T14:30:00Z",
-		},
-		{
-			name:      "Local time format",
-			input:     "20240315T143000",
-			wantError: false,
-			expected:  "2024-03-15T14:30:00Z",
-		},
-		{
-			name:      "Date only format",
-			input:     "20240315",
-			wantError: false,
-			expected:  "2024-03-15T00:00:00Z",
-		},
-		{
-			name:      "Invalid format",
-			input:     "2024-03-15",
-			wantError: true,
-		},
-		{
-			name:      "Empty string",
-			input:     "",
-			wantError: true,
-		},
-		{
-			name:      "Malformed date",
-			input:     "20241332T143000Z",
-			wantError: true,
-		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			result, err := parseICalTime(tt.input)
-
-			if (err != nil) != tt.wantError {
-				t.Errorf("parseICalTime(%q) error = %v, wantError %v", tt.input, err, tt.wantError)
-				return
-			}
-
-			if !tt.wantError {
-				expected, _ := time.Parse(time.RFC3339, tt.expected)
-				if !result.Equal(expected) {
-					t.Errorf("parseICalTime(%q) = %v, want %v", tt.input, result, expected)
-				}
-			}
-		})
-	}
-}
-
-func TestUnescapeText(t *testing.T) {
-	tests := []struct {
-		name     string
-		input    string
-		expected string
-	}{
-		{
-			name:     "newline escape",
-			input:    "Line 1\\nLine 2",
-			expected: "Line 1\nLine 2",
-		},
-		{
-			name:     "comma escape",
-			input:    "Item 1\\, Item 2",
-			expected: "Item 1, Item 2",
-		},
-		{
-			name:     "semicolon escape",
-			input:    "Part 1\\; Part 2",
-			expected: "Part 1; Part 2",
-		},
-		{
-			name:     "backslash escape",
-			input:    "Path\\\\to\\\\file",
-			expected: "Path\\to\\file",
-		},
-		{
-			name:     "multiple escapes",
-			input:    "Text\\nwith\\, multiple\\; escapes\\\\here",
-			expected: "Text\nwith, multiple; escapes\\here",
-		},
-		{
-			name:     "no escapes",
-			input:    "Plain text",
-			expected: "Plain text",
-		},
-		{
-			name:     "empty string",
-			input:    "",
-			expected: "",
-		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			result := unescapeText(tt.input)
-			if result != tt.expected {
-				t.Errorf("unescapeText(%q) = %q, want %q", tt.input, result, tt.expected)
-			}
-		})
-	}
-}
-
 func TestParseInt(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -235,7 +130,7 @@ END:VCALENDAR`,
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := extractVTODOBlocks(tt.input)
+			result := extractVTODOBlocks(strings.Split(tt.input, "\n"))
 
 			if len(result) != tt.expected {
 				t.Errorf("extractVTODOBlocks() returned %d blocks, want %d", len(result), tt.expected)
@@ -321,8 +216,8 @@ END:VTODO`,
 			name: "VTODO with escaped text",
 			input: `BEGIN:VTODO
 UID:escaped-task
-SUMMARY:backend.Task\\nwith\\, escapes
-DESCRIPTION:Line 1\\nLine 2\\; etc
+SUMMARY:backend.Task\nwith\, escapes
+DESCRIPTION:Line 1\nLine 2\; etc
 END:VTODO`,
 			wantError: false,
 			checkFunc: func(t *testing.T, task backend.Task) {
@@ -366,6 +261,22 @@ END:VTODO`,
 				}
 			},
 		},
+		{
+			name: "VTODO with escaped comma and several CATEGORIES lines",
+			input: `BEGIN:VTODO
+UID:cat-task-2
+SUMMARY:Categorized task
+CATEGORIES:Work,Q1\, Q2
+CATEGORIES:Home
+END:VTODO`,
+			wantError: false,
+			checkFunc: func(t *testing.T, task backend.Task) {
+				want := []string{"Work", "Q1, Q2", "Home"}
+				if !reflect.DeepEqual(task.Categories, want) {
+					t.Errorf("Categories = %q, want %q", task.Categories, want)
+				}
+			},
+		},
 		{
 			name: "VTODO with parent (subtask)",
 			input: `BEGIN:VTODO
@@ -380,6 +291,21 @@ END:VTODO`,
 				}
 			},
 		},
+		{
+			name: "VTODO with recurrence",
+			input: `BEGIN:VTODO
+UID:chore-1
+SUMMARY:Water plants
+DUE:20240320T180000Z
+RRULE:FREQ=WEEKLY;BYDAY=MO,TH;COUNT=10
+END:VTODO`,
+			wantError: false,
+			checkFunc: func(t *testing.T, task backend.Task) {
+				if task.Recurrence != "FREQ=WEEKLY;BYDAY=MO,TH;COUNT=10" {
+					t.Errorf("Recurrence = %q, want the RRULE value", task.Recurrence)
+				}
+			},
+		},
 		{
 			name: "VTODO missing UID",
 			input: `BEGIN:VTODO
@@ -601,3 +527,259 @@ func TestParseTaskListResponse(t *testing.T) {
 		})
 	}
 }
+
+// TestGetTaskListsMixedCollections tests that of a calendar home with 20
+// collections (events, inbox, trash, subscriptions, deleted, unnamed and
+// malformed entries) only the three VTODO calendars are returned
+func TestGetTaskListsMixedCollections(t *testing.T) {
+	fixture, err := os.ReadFile(filepath.Join("testdata", "calendar_home.xml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := len(extractResponses(string(fixture))); n != 20 {
+		t.Fatalf("fixture has %d entries, want 20", n)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Prefer") != "return=minimal" {
+			t.Errorf("Prefer header = %q, want return=minimal", r.Header.Get("Prefer"))
+		}
+		w.WriteHeader(http.StatusMultiStatus)
+		_, _ = w.Write(fixture)
+	}))
+	defer server.Close()
+
+	lists, err := createTestBackend(t, server.URL).GetTaskLists()
+	if err != nil {
+		t.Fatalf("GetTaskLists() error = %v", err)
+	}
+
+	want := map[string]string{"tasks": "Tasks", "work_shared_by_bob": "Work (Bob)", "shopping": "Shopping"}
+	if len(lists) != len(want) {
+		t.Fatalf("GetTaskLists() returned %d lists, want %d: %+v", len(lists), len(want), lists)
+	}
+	for _, list := range lists {
+		if want[list.ID] != list.Name {
+			t.Errorf("list %q named %q, want %q", list.ID, list.Name, want[list.ID])
+		}
+	}
+	if lists[2].CTags != "http://sabre.io/ns/sync/42" {
+		t.Errorf("shopping CTag = %q", lists[2].CTags)
+	}
+
+	deleted := parseCalendarCollections(string(fixture), server.URL, true)
+	if len(deleted) != 1 || deleted[0].ID != "old-tasks-1700000000" {
+		t.Errorf("deleted lists = %+v, want only old-tasks-1700000000", deleted)
+	}
+}
+
+func TestParseCalendarEntryMalformed(t *testing.T) {
+	truncated := `<d:response><d:href>/calendars/alice/broken/</d:href><d:propstat>`
+	if _, _, err := parseCalendarEntry(truncated, ""); err == nil {
+		t.Error("truncated entry parsed without error")
+	}
+
+	noHref := `<d:response><d:propstat><d:prop><d:displayname>X</d:displayname></d:prop></d:propstat></d:response>`
+	if _, _, err := parseCalendarEntry(noHref, ""); err == nil {
+		t.Error("entry without href parsed without error")
+	}
+}
+
+// TestBuildICalContentRecurrence tests that the RRULE of a task is written
+// and reads back
+func TestBuildICalContentRecurrence(t *testing.T) {
+	nb := &Backend{}
+	task := backend.Task{UID: "chore-1", Summary: "Water plants", Status: "NEEDS-ACTION", Recurrence: "FREQ=MONTHLY;BYMONTHDAY=1,15"}
+
+	content := nb.buildICalContent(task)
+	if !strings.Contains(content, "\r\nRRULE:FREQ=MONTHLY;BYMONTHDAY=1,15\r\n") {
+		t.Errorf("buildICalContent() has no RRULE:\n%s", content)
+	}
+	parsed, err := parseVTODO(strings.ReplaceAll(content, "\r\n", "\n"))
+	if err != nil {
+		t.Fatalf("parseVTODO() error = %v", err)
+	}
+	if parsed.Recurrence != task.Recurrence {
+		t.Errorf("Recurrence = %q, want %q", parsed.Recurrence, task.Recurrence)
+	}
+
+	task.Recurrence = ""
+	if content := nb.buildICalContent(task); strings.Contains(content, "RRULE") {
+		t.Errorf("buildICalContent() of a task without recurrence has an RRULE:\n%s", content)
+	}
+}
+
+func TestParseVTODODates(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("no time zone database: %v", err)
+	}
+	tests := []struct {
+		name       string
+		lines      string
+		wantDue    time.Time
+		wantAllDay bool
+	}{
+		{
+			name:       "date",
+			lines:      "DUE;VALUE=DATE:20260320\n",
+			wantDue:    time.Date(2026, 3, 20, 0, 0, 0, 0, time.Local),
+			wantAllDay: true,
+		},
+		{
+			name:       "date and start date",
+			lines:      "DTSTART;VALUE=DATE:20260318\nDUE;VALUE=DATE:20260320\n",
+			wantDue:    time.Date(2026, 3, 20, 0, 0, 0, 0, time.Local),
+			wantAllDay: true,
+		},
+		{
+			name:    "date and start time",
+			lines:   "DTSTART:20260318T090000Z\nDUE;VALUE=DATE:20260320\n",
+			wantDue: time.Date(2026, 3, 20, 0, 0, 0, 0, time.Local),
+		},
+		{
+			name:    "TZID",
+			lines:   "DUE;TZID=America/New_York:20260320T180000\n",
+			wantDue: time.Date(2026, 3, 20, 18, 0, 0, 0, newYork),
+		},
+		{
+			name:    "prefixed TZID",
+			lines:   "DUE;TZID=/citadel.org/20070103_1/America/New_York:20260320T180000\n",
+			wantDue: time.Date(2026, 3, 20, 18, 0, 0, 0, newYork),
+		},
+		{
+			name:    "floating",
+			lines:   "DUE:20260320T180000\n",
+			wantDue: time.Date(2026, 3, 20, 18, 0, 0, 0, time.Local),
+		},
+		{
+			name:    "UTC",
+			lines:   "DUE:20260320T180000Z\n",
+			wantDue: time.Date(2026, 3, 20, 18, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vtodo := "BEGIN:VTODO\nUID:t\nSUMMARY:Dated\n" + tt.lines + "END:VTODO"
+			task, err := parseVTODO(vtodo)
+			if err != nil {
+				t.Fatalf("parseVTODO() error = %v", err)
+			}
+			if task.DueDate == nil || !task.DueDate.Equal(tt.wantDue) {
+				t.Errorf("DueDate = %v, want %v", task.DueDate, tt.wantDue)
+			}
+			if task.AllDay != tt.wantAllDay {
+				t.Errorf("AllDay = %v, want %v", task.AllDay, tt.wantAllDay)
+			}
+		})
+	}
+}
+
+func TestBuildICalContentAllDay(t *testing.T) {
+	nb := &Backend{}
+	due := time.Date(2026, 3, 20, 0, 0, 0, 0, time.Local)
+	task := backend.Task{UID: "day-1", Summary: "Day off", Status: "NEEDS-ACTION", DueDate: &due, AllDay: true}
+
+	content := nb.buildICalContent(task)
+	if !strings.Contains(content, "\r\nDUE;VALUE=DATE:20260320\r\n") {
+		t.Errorf("buildICalContent() has no DATE due:\n%s", content)
+	}
+	parsed, err := parseVTODO(strings.ReplaceAll(content, "\r\n", "\n"))
+	if err != nil {
+		t.Fatalf("parseVTODO() error = %v", err)
+	}
+	if !parsed.AllDay || parsed.DueDate == nil || !parsed.DueDate.Equal(due) {
+		t.Errorf("parsed due %v, all day %v, want %v all day", parsed.DueDate, parsed.AllDay, due)
+	}
+
+	task.AllDay = false
+	if content := nb.buildICalContent(task); strings.Contains(content, "VALUE=DATE") {
+		t.Errorf("buildICalContent() of a timed task has a DATE:\n%s", content)
+	}
+}
+
+// TestICalTextRoundTrip tests that summaries and descriptions with special
+// characters, multi-byte runes and long lines survive buildICalContent and
+// parsing, both as a plain object and inside a multistatus response
+func TestICalTextRoundTrip(t *testing.T) {
+	paragraph := strings.Repeat("Lorem ipsum dolor sit amet, consectetur adipiscing elit; sed do eiusmod. ", 8)
+	tests := []struct {
+		name        string
+		summary     string
+		description string
+	}{
+		{"newlines", "Shopping", "milk\neggs\r\nbread\n\nend"},
+		{"commas and semicolons", "Call a, b; c", "one, two; three"},
+		{"backslashes", `C:\new\table`, `a literal \n and \\`},
+		{"emoji", "Birthday 🎂🎉", strings.Repeat("🎂", 40)},
+		{"accents across folds", strings.Repeat("é", 50), strings.Repeat("日本語のテキスト", 12)},
+		{"long paragraph", "Read " + paragraph, paragraph + "\n" + paragraph},
+		{"markup", "Q&A <draft>", `"quoted" & <tagged>`},
+	}
+
+	nb := &Backend{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			task := backend.Task{UID: "task-1", Summary: tt.summary, Description: tt.description, Status: "NEEDS-ACTION"}
+			content := nb.buildICalContent(task)
+
+			for line := range strings.SplitSeq(strings.TrimSuffix(content, "\r\n"), "\r\n") {
+				if len(line) > 75 {
+					t.Errorf("line of %d octets: %q", len(line), line)
+				}
+				if !utf8.ValidString(line) {
+					t.Errorf("line split inside a rune: %q", line)
+				}
+			}
+
+			var escaped strings.Builder
+			_ = xml.EscapeText(&escaped, []byte(content))
+			multistatus := `<?xml version="1.0"?>
+<d:multistatus xmlns:d="DAV:" xmlns:cal="urn:ietf:params:xml:ns:caldav">
+  <d:response>
+    <d:href>/remote.php/dav/calendars/testuser/tasks/task-1.ics</d:href>
+    <d:propstat><d:prop><d:getetag>"e1"</d:getetag><cal:calendar-data>` + escaped.String() + `</cal:calendar-data></d:prop><d:status>HTTP/1.1 200 OK</d:status></d:propstat>
+  </d:response>
+</d:multistatus>`
+
+			for source, parse := range map[string]func() (backend.Task, error){
+				"object": func() (backend.Task, error) {
+					blocks := extractVTODOBlocks(ical.Unfold(content))
+					if len(blocks) != 1 {
+						return backend.Task{}, fmt.Errorf("%d VTODO blocks", len(blocks))
+					}
+					return parseVTODO(blocks[0])
+				},
+				"multistatus": func() (backend.Task, error) {
+					tasks, err := nb.parseVTODOs(multistatus)
+					if err != nil || len(tasks) != 1 {
+						return backend.Task{}, fmt.Errorf("%d tasks, error %v", len(tasks), err)
+					}
+					return tasks[0], nil
+				},
+			} {
+				parsed, err := parse()
+				if err != nil {
+					t.Fatalf("%s: %v", source, err)
+				}
+				if parsed.Summary != tt.summary {
+					t.Errorf("%s: Summary = %q, want %q", source, parsed.Summary, tt.summary)
+				}
+				if want := strings.ReplaceAll(tt.description, "\r\n", "\n"); parsed.Description != want {
+					t.Errorf("%s: Description = %q, want %q", source, parsed.Description, want)
+				}
+			}
+		})
+	}
+}
+
+func TestCalendarDataCDATA(t *testing.T) {
+	response := `<d:response><cal:calendar-data><![CDATA[BEGIN:VTODO
+UID:x
+SUMMARY:Q&amp;A
+END:VTODO]]></cal:calendar-data></d:response>`
+	if got := calendarData(response); !strings.Contains(got, "SUMMARY:Q&amp;A") {
+		t.Errorf("calendarData() = %q, want CDATA content as is", got)
+	}
+}
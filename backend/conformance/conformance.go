@@ -0,0 +1,488 @@
+// Package conformance checks that a backend.TaskManager honors the contract
+// documented on the interface. Each backend runs the same suite from its own
+// tests, declaring the features it does not support:
+//
+//	func TestConformance(t *testing.T) {
+//		conformance.Run(t, func() backend.TaskManager { return newTestBackend(t) },
+//			conformance.Capabilities{StableListIDs: true, Hierarchy: true, Categories: true})
+//	}
+package conformance
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"gosynctasks/backend"
+	"gosynctasks/backend/statuskit"
+)
+
+// Capabilities declares the optional features a backend supports. Sections
+// exercising a feature the backend lacks are skipped.
+type Capabilities struct {
+	// StableListIDs means RenameTaskList keeps the list ID
+	StableListIDs bool
+	// Hierarchy means ParentUID is stored and returned
+	Hierarchy bool
+	// Categories means task categories are stored and returned
+	Categories bool
+}
+
+// displayStatuses are the display names every backend maps its statuses to
+var displayStatuses = []string{"TODO", "DONE", "PROCESSING", "CANCELLED"}
+
+// Run exercises the TaskManager returned by factory. factory is called once
+// per section, and each section works in a list of its own, so the backend
+// may be shared between calls.
+func Run(t *testing.T, factory func() backend.TaskManager, caps Capabilities) {
+	t.Run("Lists", func(t *testing.T) { testLists(t, factory(), caps) })
+	t.Run("Tasks", func(t *testing.T) { testTasks(t, factory()) })
+	t.Run("GetTask", func(t *testing.T) { testGetTask(t, factory()) })
+	t.Run("Filters", func(t *testing.T) { testFilters(t, factory()) })
+	t.Run("SummarySearch", func(t *testing.T) { testSummarySearch(t, factory()) })
+	t.Run("Hierarchy", func(t *testing.T) {
+		if !caps.Hierarchy {
+			t.Skip("backend does not support subtasks")
+		}
+		testHierarchy(t, factory())
+	})
+	t.Run("Categories", func(t *testing.T) {
+		if !caps.Categories {
+			t.Skip("backend does not support categories")
+		}
+		testCategories(t, factory())
+	})
+	t.Run("StatusParsing", func(t *testing.T) { testStatusParsing(t, factory()) })
+	t.Run("Unicode", func(t *testing.T) { testUnicode(t, factory()) })
+	t.Run("NotFound", func(t *testing.T) { testNotFound(t, factory()) })
+}
+
+// newList creates a list for one section and deletes it when the section ends
+func newList(t *testing.T, tm backend.TaskManager, section string) string {
+	t.Helper()
+	name := fmt.Sprintf("conformance %s %d", section, time.Now().UnixNano())
+	listID, err := tm.CreateTaskList(name, "", "")
+	if err != nil {
+		t.Fatalf("CreateTaskList(%q) error = %v", name, err)
+	}
+	if listID == "" {
+		t.Fatalf("CreateTaskList(%q) returned an empty ID", name)
+	}
+	t.Cleanup(func() { _ = tm.DeleteTaskList(listID) })
+	return listID
+}
+
+// status parses a display status, failing the test if the backend rejects it
+func status(t *testing.T, tm backend.TaskManager, display string) string {
+	t.Helper()
+	s, err := tm.ParseStatusFlag(display)
+	if err != nil {
+		t.Fatalf("ParseStatusFlag(%q) error = %v", display, err)
+	}
+	return s
+}
+
+// addTask adds a task and returns its UID
+func addTask(t *testing.T, tm backend.TaskManager, listID string, task backend.Task) string {
+	t.Helper()
+	uid, err := tm.AddTask(listID, task)
+	if err != nil {
+		t.Fatalf("AddTask(%q) error = %v", task.Summary, err)
+	}
+	if uid == "" {
+		t.Fatalf("AddTask(%q) returned an empty UID", task.Summary)
+	}
+	return uid
+}
+
+// getTask returns the task with the UID, or nil when the list does not contain it
+func getTask(t *testing.T, tm backend.TaskManager, listID, uid string) *backend.Task {
+	t.Helper()
+	tasks, err := tm.GetTasks(listID, nil)
+	if err != nil {
+		t.Fatalf("GetTasks() error = %v", err)
+	}
+	for i := range tasks {
+		if tasks[i].UID == uid {
+			return &tasks[i]
+		}
+	}
+	return nil
+}
+
+// mustGetTask is getTask failing the test when the task is missing
+func mustGetTask(t *testing.T, tm backend.TaskManager, listID, uid string) backend.Task {
+	t.Helper()
+	task := getTask(t, tm, listID, uid)
+	if task == nil {
+		t.Fatalf("task %s not returned by GetTasks", uid)
+	}
+	return *task
+}
+
+func findList(lists []backend.TaskList, match func(backend.TaskList) bool) *backend.TaskList {
+	for i := range lists {
+		if match(lists[i]) {
+			return &lists[i]
+		}
+	}
+	return nil
+}
+
+func testLists(t *testing.T, tm backend.TaskManager, caps Capabilities) {
+	name := fmt.Sprintf("conformance lists %d", time.Now().UnixNano())
+	listID, err := tm.CreateTaskList(name, "", "")
+	if err != nil {
+		t.Fatalf("CreateTaskList() error = %v", err)
+	}
+
+	lists, err := tm.GetTaskLists()
+	if err != nil {
+		t.Fatalf("GetTaskLists() error = %v", err)
+	}
+	created := findList(lists, func(l backend.TaskList) bool { return l.ID == listID })
+	if created == nil {
+		t.Fatalf("created list %s not returned by GetTaskLists", listID)
+	}
+	if created.Name != name {
+		t.Errorf("list name = %q, want %q", created.Name, name)
+	}
+
+	renamed := name + " renamed"
+	if err := tm.RenameTaskList(listID, renamed); err != nil {
+		t.Fatalf("RenameTaskList() error = %v", err)
+	}
+	lists, err = tm.GetTaskLists()
+	if err != nil {
+		t.Fatalf("GetTaskLists() error = %v", err)
+	}
+	after := findList(lists, func(l backend.TaskList) bool { return l.Name == renamed })
+	if after == nil {
+		t.Fatalf("renamed list %q not returned by GetTaskLists", renamed)
+	}
+	if findList(lists, func(l backend.TaskList) bool { return l.Name == name }) != nil {
+		t.Errorf("list is still returned under its old name %q", name)
+	}
+	if caps.StableListIDs && after.ID != listID {
+		t.Errorf("list ID after rename = %q, want %q", after.ID, listID)
+	}
+
+	if err := tm.DeleteTaskList(after.ID); err != nil {
+		t.Fatalf("DeleteTaskList() error = %v", err)
+	}
+	lists, err = tm.GetTaskLists()
+	if err != nil {
+		t.Fatalf("GetTaskLists() error = %v", err)
+	}
+	if findList(lists, func(l backend.TaskList) bool { return l.ID == after.ID }) != nil {
+		t.Errorf("deleted list %s still returned by GetTaskLists", after.ID)
+	}
+}
+
+func testTasks(t *testing.T, tm backend.TaskManager) {
+	listID := newList(t, tm, "tasks")
+	todo := status(t, tm, "TODO")
+	done := status(t, tm, "DONE")
+
+	due := time.Date(2030, 1, 15, 12, 0, 0, 0, time.UTC)
+	uid := addTask(t, tm, listID, backend.Task{
+		Summary:     "Write conformance suite",
+		Description: "Covers every backend",
+		Status:      todo,
+		Priority:    3,
+		DueDate:     &due,
+	})
+
+	got := mustGetTask(t, tm, listID, uid)
+	if got.Summary != "Write conformance suite" || got.Description != "Covers every backend" {
+		t.Errorf("added task = %q / %q, want the summary and description given", got.Summary, got.Description)
+	}
+	if got.Priority != 3 {
+		t.Errorf("Priority = %d, want 3", got.Priority)
+	}
+	if tm.StatusToDisplayName(got.Status) != "TODO" {
+		t.Errorf("Status = %q, want a TODO status", got.Status)
+	}
+	if got.DueDate == nil || got.DueDate.UTC().Format("2006-01-02") != "2030-01-15" {
+		t.Errorf("DueDate = %v, want 2030-01-15", got.DueDate)
+	}
+
+	other := addTask(t, tm, listID, backend.Task{Summary: "Second task", Status: todo})
+	if other == uid {
+		t.Errorf("tasks added without a UID share UID %s", uid)
+	}
+
+	got.Summary = "Write the conformance suite"
+	got.Priority = 1
+	got.Status = done
+	if err := tm.UpdateTask(listID, got); err != nil {
+		t.Fatalf("UpdateTask() error = %v", err)
+	}
+	updated := mustGetTask(t, tm, listID, uid)
+	if updated.Summary != "Write the conformance suite" || updated.Priority != 1 {
+		t.Errorf("updated task = %q priority %d, want the new summary and priority 1", updated.Summary, updated.Priority)
+	}
+	if tm.StatusToDisplayName(updated.Status) != "DONE" {
+		t.Errorf("Status after update = %q, want a DONE status", updated.Status)
+	}
+
+	if err := tm.DeleteTask(listID, uid); err != nil {
+		t.Fatalf("DeleteTask() error = %v", err)
+	}
+	if getTask(t, tm, listID, uid) != nil {
+		t.Errorf("deleted task %s still returned by GetTasks", uid)
+	}
+	if getTask(t, tm, listID, other) == nil {
+		t.Errorf("deleting %s also removed %s", uid, other)
+	}
+}
+
+func testGetTask(t *testing.T, tm backend.TaskManager) {
+	listID := newList(t, tm, "gettask")
+	uid := addTask(t, tm, listID, backend.Task{Summary: "Fetch me alone", Description: "Just this one", Status: status(t, tm, "TODO")})
+	addTask(t, tm, listID, backend.Task{Summary: "Not me", Status: status(t, tm, "TODO")})
+
+	got, err := tm.GetTask(listID, uid)
+	if err != nil {
+		t.Fatalf("GetTask() error = %v", err)
+	}
+	if got.UID != uid || got.Summary != "Fetch me alone" || got.Description != "Just this one" {
+		t.Errorf("GetTask() = %s %q / %q, want the task added", got.UID, got.Summary, got.Description)
+	}
+
+	// Completed tasks are returned too
+	got.Status = status(t, tm, "DONE")
+	if err := tm.UpdateTask(listID, *got); err != nil {
+		t.Fatalf("UpdateTask() error = %v", err)
+	}
+	done, err := tm.GetTask(listID, uid)
+	if err != nil {
+		t.Fatalf("GetTask(completed) error = %v", err)
+	}
+	if tm.StatusToDisplayName(done.Status) != "DONE" {
+		t.Errorf("GetTask(completed) Status = %q, want a DONE status", done.Status)
+	}
+
+	var backendErr *backend.BackendError
+	if _, err := tm.GetTask(listID, "conformance-missing-task"); !errors.As(err, &backendErr) || !backendErr.IsNotFound() {
+		t.Errorf("GetTask(missing UID) error = %v, want a BackendError with IsNotFound()", err)
+	}
+}
+
+func testFilters(t *testing.T, tm backend.TaskManager) {
+	listID := newList(t, tm, "filters")
+	todo := status(t, tm, "TODO")
+	done := status(t, tm, "DONE")
+	processing := status(t, tm, "PROCESSING")
+
+	todoUID := addTask(t, tm, listID, backend.Task{Summary: "Open", Status: todo})
+	doneUID := addTask(t, tm, listID, backend.Task{Summary: "Finished", Status: done})
+	processingUID := addTask(t, tm, listID, backend.Task{Summary: "Started", Status: processing})
+
+	tests := []struct {
+		name     string
+		statuses []string
+		want     []string
+	}{
+		{"single status", []string{done}, []string{doneUID}},
+		{"several statuses", []string{todo, processing}, []string{todoUID, processingUID}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tasks, err := tm.GetTasks(listID, &backend.TaskFilter{Statuses: &tt.statuses})
+			if err != nil {
+				t.Fatalf("GetTasks() error = %v", err)
+			}
+			var uids []string
+			for _, task := range tasks {
+				uids = append(uids, task.UID)
+			}
+			slices.Sort(uids)
+			want := slices.Clone(tt.want)
+			slices.Sort(want)
+			if !slices.Equal(uids, want) {
+				t.Errorf("GetTasks(Statuses=%v) = %v, want %v", tt.statuses, uids, want)
+			}
+		})
+	}
+
+	all, err := tm.GetTasks(listID, nil)
+	if err != nil {
+		t.Fatalf("GetTasks(nil) error = %v", err)
+	}
+	if len(all) != 3 {
+		t.Errorf("GetTasks(nil) returned %d tasks, want all 3", len(all))
+	}
+}
+
+func testSummarySearch(t *testing.T, tm backend.TaskManager) {
+	listID := newList(t, tm, "search")
+	todo := status(t, tm, "TODO")
+
+	addTask(t, tm, listID, backend.Task{Summary: "Write report draft", Status: todo})
+	exactUID := addTask(t, tm, listID, backend.Task{Summary: "report", Status: todo})
+	addTask(t, tm, listID, backend.Task{Summary: "Quarterly REPORT review", Status: todo})
+	addTask(t, tm, listID, backend.Task{Summary: "Unrelated", Status: todo})
+
+	matches, err := tm.FindTasksBySummary(listID, "Report")
+	if err != nil {
+		t.Fatalf("FindTasksBySummary() error = %v", err)
+	}
+	if len(matches) != 3 {
+		t.Fatalf("FindTasksBySummary(\"Report\") returned %d tasks, want 3", len(matches))
+	}
+	if matches[0].UID != exactUID {
+		t.Errorf("first match = %q, want the exact match %q first", matches[0].Summary, "report")
+	}
+
+	none, err := tm.FindTasksBySummary(listID, "no such task")
+	if err != nil {
+		t.Fatalf("FindTasksBySummary() error = %v", err)
+	}
+	if len(none) != 0 {
+		t.Errorf("FindTasksBySummary(\"no such task\") returned %d tasks, want none", len(none))
+	}
+}
+
+func testHierarchy(t *testing.T, tm backend.TaskManager) {
+	listID := newList(t, tm, "hierarchy")
+	todo := status(t, tm, "TODO")
+
+	parentUID := addTask(t, tm, listID, backend.Task{Summary: "Parent", Status: todo})
+	childUID := addTask(t, tm, listID, backend.Task{Summary: "Child", Status: todo, ParentUID: parentUID})
+
+	child := mustGetTask(t, tm, listID, childUID)
+	if child.ParentUID != parentUID {
+		t.Fatalf("child ParentUID = %q, want %q", child.ParentUID, parentUID)
+	}
+
+	parent := mustGetTask(t, tm, listID, parentUID)
+	parent.Summary = "Parent renamed"
+	if err := tm.UpdateTask(listID, parent); err != nil {
+		t.Fatalf("UpdateTask(parent) error = %v", err)
+	}
+	if got := mustGetTask(t, tm, listID, childUID).ParentUID; got != parentUID {
+		t.Errorf("child ParentUID after updating the parent = %q, want %q", got, parentUID)
+	}
+
+	root := ""
+	for _, tt := range []struct {
+		parentUID string
+		want      string
+	}{{parentUID, childUID}, {root, parentUID}} {
+		tasks, err := tm.GetTasks(listID, &backend.TaskFilter{ParentUID: &tt.parentUID})
+		if err != nil {
+			t.Fatalf("GetTasks(ParentUID=%q) error = %v", tt.parentUID, err)
+		}
+		if len(tasks) != 1 || tasks[0].UID != tt.want {
+			t.Errorf("GetTasks(ParentUID=%q) returned %d tasks, want only %s", tt.parentUID, len(tasks), tt.want)
+		}
+	}
+
+	child.ParentUID = ""
+	if err := tm.UpdateTask(listID, child); err != nil {
+		t.Fatalf("UpdateTask(child) error = %v", err)
+	}
+	if got := mustGetTask(t, tm, listID, childUID).ParentUID; got != "" {
+		t.Errorf("child ParentUID after detaching = %q, want none", got)
+	}
+}
+
+func testCategories(t *testing.T, tm backend.TaskManager) {
+	listID := newList(t, tm, "categories")
+	uid := addTask(t, tm, listID, backend.Task{Summary: "Tagged", Status: status(t, tm, "TODO"), Categories: []string{"work", "urgent"}})
+
+	task := mustGetTask(t, tm, listID, uid)
+	if got := sortedCopy(task.Categories); !slices.Equal(got, []string{"urgent", "work"}) {
+		t.Errorf("Categories = %v, want [urgent work]", got)
+	}
+
+	task.Categories = []string{"home"}
+	if err := tm.UpdateTask(listID, task); err != nil {
+		t.Fatalf("UpdateTask() error = %v", err)
+	}
+	if got := mustGetTask(t, tm, listID, uid).Categories; !slices.Equal(got, []string{"home"}) {
+		t.Errorf("Categories after update = %v, want [home]", got)
+	}
+}
+
+func sortedCopy(values []string) []string {
+	sorted := slices.Clone(values)
+	slices.Sort(sorted)
+	return sorted
+}
+
+func testStatusParsing(t *testing.T, tm backend.TaskManager) {
+	for _, display := range displayStatuses {
+		s := status(t, tm, display)
+		if got := tm.StatusToDisplayName(s); got != display {
+			t.Errorf("StatusToDisplayName(ParseStatusFlag(%q)) = %q, want %q", display, got, display)
+		}
+		if abbreviated := status(t, tm, display[:1]); abbreviated != s {
+			t.Errorf("ParseStatusFlag(%q) = %q, want %q like %q", display[:1], abbreviated, s, display)
+		}
+		if lower := status(t, tm, strings.ToLower(display)); lower != s {
+			t.Errorf("ParseStatusFlag(%q) = %q, want %q", strings.ToLower(display), lower, s)
+		}
+	}
+
+	// Every backend reads the canonical inputs, CalDAV names included, as
+	// statuskit does
+	for _, canonical := range statuskit.Statuses {
+		for _, input := range []string{string(canonical), canonical.Abbreviation(), canonical.CalDAVName()} {
+			if got := tm.StatusToDisplayName(status(t, tm, input)); got != string(canonical) {
+				t.Errorf("StatusToDisplayName(ParseStatusFlag(%q)) = %q, want %q", input, got, canonical)
+			}
+		}
+	}
+
+	if _, err := tm.ParseStatusFlag("not-a-status"); err == nil {
+		t.Error("ParseStatusFlag(\"not-a-status\") expected an error")
+	}
+}
+
+func testUnicode(t *testing.T, tm backend.TaskManager) {
+	listID := newList(t, tm, "unicode")
+	summary := "Café ☕ 日本語 — naïve résumé 🚀"
+	description := "Première ligne\nZweite Zeile, mit Komma; und Semikolon\nΤρίτη γραμμή"
+
+	uid := addTask(t, tm, listID, backend.Task{Summary: summary, Description: description, Status: status(t, tm, "TODO")})
+	got := mustGetTask(t, tm, listID, uid)
+	if got.Summary != summary {
+		t.Errorf("Summary = %q, want %q", got.Summary, summary)
+	}
+	if got.Description != description {
+		t.Errorf("Description = %q, want %q", got.Description, description)
+	}
+
+	matches, err := tm.FindTasksBySummary(listID, "日本語")
+	if err != nil {
+		t.Fatalf("FindTasksBySummary() error = %v", err)
+	}
+	if len(matches) != 1 || matches[0].UID != uid {
+		t.Errorf("FindTasksBySummary(\"日本語\") = %d tasks, want the unicode task", len(matches))
+	}
+}
+
+func testNotFound(t *testing.T, tm backend.TaskManager) {
+	listID := newList(t, tm, "notfound")
+
+	assertNotFound := func(name string, err error) {
+		t.Helper()
+		var backendErr *backend.BackendError
+		if !errors.As(err, &backendErr) || !backendErr.IsNotFound() {
+			t.Errorf("%s error = %v, want a BackendError with IsNotFound()", name, err)
+		}
+	}
+
+	assertNotFound("DeleteTask(missing UID)", tm.DeleteTask(listID, "conformance-missing-task"))
+
+	uid := addTask(t, tm, listID, backend.Task{Summary: "Delete me twice", Status: status(t, tm, "TODO")})
+	if err := tm.DeleteTask(listID, uid); err != nil {
+		t.Fatalf("DeleteTask() error = %v", err)
+	}
+	assertNotFound("DeleteTask(deleted UID)", tm.DeleteTask(listID, uid))
+}
@@ -0,0 +1,214 @@
+package deck
+
+import (
+	"bytes"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"gosynctasks/backend"
+)
+
+// apiPath is the path of the Deck REST API below the server URL
+const apiPath = "/index.php/apps/deck/api/v1.0"
+
+// Board is a Deck board
+type Board struct {
+	ID        int     `json:"id"`
+	Title     string  `json:"title"`
+	Color     string  `json:"color"` // Hex without "#"
+	Archived  bool    `json:"archived"`
+	DeletedAt int64   `json:"deletedAt"` // Unix time, 0 unless deleted
+	Labels    []Label `json:"labels"`
+	ETag      string  `json:"ETag"` // Changes with any change on the board
+}
+
+// Stack is a column of a board
+type Stack struct {
+	ID      int    `json:"id"`
+	Title   string `json:"title"`
+	BoardID int    `json:"boardId"`
+	Order   int    `json:"order"`
+	Cards   []Card `json:"cards"`
+	ETag    string `json:"ETag"`
+}
+
+// Card is a card of a stack
+type Card struct {
+	ID           int     `json:"id"`
+	Title        string  `json:"title"`
+	Description  string  `json:"description"`
+	StackID      int     `json:"stackId"`
+	Order        int     `json:"order"`
+	Archived     bool    `json:"archived"`
+	Done         *string `json:"done"`    // RFC 3339, set when marked done (Deck 1.12+)
+	DueDate      *string `json:"duedate"` // RFC 3339
+	Labels       []Label `json:"labels"`
+	CreatedAt    int64   `json:"createdAt"`    // Unix time
+	LastModified int64   `json:"lastModified"` // Unix time
+	DeletedAt    int64   `json:"deletedAt"`
+}
+
+// Label is a board label, attached to cards
+type Label struct {
+	ID    int    `json:"id"`
+	Title string `json:"title"`
+	Color string `json:"color"`
+}
+
+// reorderRequest moves a card within or between stacks
+type reorderRequest struct {
+	Order   int `json:"order"`
+	StackID int `json:"stackId"`
+}
+
+// cachedResponse is a GET response kept for If-None-Match
+type cachedResponse struct {
+	etag string
+	body []byte
+}
+
+// APIClient talks to the Deck REST API. GET responses carrying an ETag are
+// kept, and revalidated with If-None-Match, so unchanged boards cost a 304.
+type APIClient struct {
+	baseURL    string
+	username   string
+	password   string
+	httpClient *http.Client
+
+	cacheMu sync.Mutex
+	cache   map[string]cachedResponse // By URL
+}
+
+// NewAPIClient returns a client for the Deck API of the server at serverURL
+func NewAPIClient(serverURL, username, password string, insecureSkipVerify bool) *APIClient {
+	return &APIClient{
+		baseURL:  strings.TrimSuffix(serverURL, "/") + apiPath,
+		username: username,
+		password: password,
+		httpClient: &http.Client{
+			Transport: &http.Transport{
+				TLSClientConfig: &tls.Config{InsecureSkipVerify: insecureSkipVerify},
+			},
+			Timeout: 30 * time.Second,
+		},
+		cache: make(map[string]cachedResponse),
+	}
+}
+
+// get decodes the response to a GET of endpoint into v
+func (c *APIClient) get(operation, endpoint string, v any) error {
+	url := c.baseURL + endpoint
+
+	c.cacheMu.Lock()
+	cached, hasCached := c.cache[url]
+	c.cacheMu.Unlock()
+
+	headers := map[string]string{}
+	if hasCached {
+		headers["If-None-Match"] = cached.etag
+	}
+	resp, err := c.do(http.MethodGet, url, nil, headers)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	var body []byte
+	switch {
+	case resp.StatusCode == http.StatusNotModified && hasCached:
+		body = cached.body
+	case resp.StatusCode == http.StatusOK:
+		body, err = io.ReadAll(resp.Body)
+		if err != nil {
+			return fmt.Errorf("failed to read response: %w", err)
+		}
+		if etag := resp.Header.Get("ETag"); etag != "" {
+			c.cacheMu.Lock()
+			c.cache[url] = cachedResponse{etag: etag, body: body}
+			c.cacheMu.Unlock()
+		}
+	default:
+		return responseError(operation, resp)
+	}
+
+	if err := json.Unmarshal(body, v); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
+
+// put sends v as the JSON body of a PUT to endpoint
+func (c *APIClient) put(operation, endpoint string, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("failed to marshal request body: %w", err)
+	}
+	resp, err := c.do(http.MethodPut, c.baseURL+endpoint, bytes.NewReader(data), map[string]string{"Content-Type": "application/json"})
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return responseError(operation, resp)
+	}
+	return nil
+}
+
+// do sends an authenticated request
+func (c *APIClient) do(method, url string, body io.Reader, headers map[string]string) (*http.Response, error) {
+	req, err := http.NewRequest(method, url, body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.SetBasicAuth(c.username, c.password)
+	req.Header.Set("OCS-APIRequest", "true")
+	req.Header.Set("Accept", "application/json")
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+	return resp, nil
+}
+
+// responseError returns the BackendError of an unexpected response
+func responseError(operation string, resp *http.Response) error {
+	body, _ := io.ReadAll(resp.Body)
+	return backend.NewBackendError(operation, resp.StatusCode, http.StatusText(resp.StatusCode)).WithBody(string(body))
+}
+
+// GetBoards returns the boards of the user, archived and deleted ones included
+func (c *APIClient) GetBoards() ([]Board, error) {
+	var boards []Board
+	err := c.get("GetBoards", "/boards", &boards)
+	return boards, err
+}
+
+// GetStacks returns the stacks of a board with their active cards
+func (c *APIClient) GetStacks(boardID int) ([]Stack, error) {
+	var stacks []Stack
+	err := c.get("GetStacks", fmt.Sprintf("/boards/%d/stacks", boardID), &stacks)
+	return stacks, err
+}
+
+// GetArchivedStacks returns the stacks of a board with their archived cards
+func (c *APIClient) GetArchivedStacks(boardID int) ([]Stack, error) {
+	var stacks []Stack
+	err := c.get("GetArchivedStacks", fmt.Sprintf("/boards/%d/stacks/archived", boardID), &stacks)
+	return stacks, err
+}
+
+// MoveCard moves a card to the top of another stack of its board
+func (c *APIClient) MoveCard(boardID, stackID, cardID, toStackID int) error {
+	endpoint := fmt.Sprintf("/boards/%d/stacks/%d/cards/%d/reorder", boardID, stackID, cardID)
+	return c.put("MoveCard", endpoint, reorderRequest{Order: 0, StackID: toStackID})
+}
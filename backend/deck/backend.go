@@ -0,0 +1,410 @@
+// Package deck is a read-only backend showing Nextcloud Deck cards as tasks:
+// boards are task lists, or each stack of a board with deck_stack_lists, and
+// cards are tasks. Completing a card, which moves it to the done stack, is
+// the only write.
+package deck
+
+import (
+	"cmp"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"gosynctasks/backend"
+	"gosynctasks/backend/statuskit"
+	"gosynctasks/internal/credentials"
+)
+
+func init() {
+	// Register Deck backend for config type "deck"
+	backend.RegisterType("deck", newDeckBackendWrapper)
+}
+
+// newDeckBackendWrapper wraps NewDeckBackend to match BackendConfigConstructor signature
+func newDeckBackendWrapper(config backend.BackendConfig) (backend.TaskManager, error) {
+	return NewDeckBackend(config)
+}
+
+// DefaultDoneStack is the stack done cards are moved to, unless deck_done_stack says otherwise
+const DefaultDoneStack = "Done"
+
+// DeckBackend implements backend.TaskManager for Nextcloud Deck
+type DeckBackend struct {
+	config    backend.BackendConfig
+	serverURL string
+	username  string
+	client    *APIClient
+}
+
+// NewDeckBackend creates a Deck backend for the Nextcloud server of config.
+// Credentials are resolved like the Nextcloud backend's, from the keyring,
+// the environment or the URL, under the name of the backend given by
+// credentials_from when set.
+func NewDeckBackend(config backend.BackendConfig) (*DeckBackend, error) {
+	serverURL, configURL, err := serverURLOf(config)
+	if err != nil {
+		return nil, err
+	}
+
+	credentialsName := config.Name
+	if config.CredentialsFrom != "" {
+		credentialsName = config.CredentialsFrom
+	}
+	var username, password string
+	if creds, err := credentials.NewResolver().Resolve(credentialsName, config.Username, config.Host, configURL); err == nil {
+		username, password = creds.Username, creds.Password
+	} else if configURL != nil && configURL.User != nil {
+		username = configURL.User.Username()
+		password, _ = configURL.User.Password()
+	} else {
+		return nil, fmt.Errorf("no credentials for deck backend %q: %w", config.Name, err)
+	}
+
+	return &DeckBackend{
+		config:    config,
+		serverURL: serverURL,
+		username:  username,
+		client:    NewAPIClient(serverURL, username, password, config.InsecureSkipVerify),
+	}, nil
+}
+
+// serverURLOf returns the server URL of config, without credentials, and the
+// parsed config URL. HTTPS is used unless allow_http is set.
+func serverURLOf(config backend.BackendConfig) (string, *url.URL, error) {
+	var configURL *url.URL
+	host := config.Host
+	if config.URL != "" {
+		var err error
+		configURL, err = url.Parse(config.URL)
+		if err != nil {
+			return "", nil, fmt.Errorf("invalid URL for deck backend: %w", err)
+		}
+		host = configURL.Host + strings.TrimSuffix(configURL.Path, "/")
+	}
+	if host == "" {
+		return "", nil, fmt.Errorf("deck backend %q needs a url or host", config.Name)
+	}
+
+	scheme := "https"
+	if config.AllowHTTP && configURL != nil && configURL.Scheme == "http" {
+		scheme = "http"
+	}
+	return scheme + "://" + host, configURL, nil
+}
+
+// unsupported is the error of every write but completing a card
+func unsupported(operation string) error {
+	return backend.NewUnsupportedError(operation, "the deck backend is read-only; cards can only be completed")
+}
+
+// doneStack returns the name of the stack done cards are moved to
+func (db *DeckBackend) doneStack() string {
+	if db.config.DeckDoneStack != "" {
+		return db.config.DeckDoneStack
+	}
+	return DefaultDoneStack
+}
+
+// isDoneStack reports whether stack holds done cards
+func (db *DeckBackend) isDoneStack(stack Stack) bool {
+	return strings.EqualFold(strings.TrimSpace(stack.Title), db.doneStack())
+}
+
+// boardURL returns the web URL of a board
+func (db *DeckBackend) boardURL(boardID int) string {
+	return fmt.Sprintf("%s/index.php/apps/deck/#/board/%d", db.serverURL, boardID)
+}
+
+// GetTaskLists returns the boards, or their stacks with deck_stack_lists.
+// Archived and deleted boards are left out.
+func (db *DeckBackend) GetTaskLists() ([]backend.TaskList, error) {
+	boards, err := db.client.GetBoards()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get boards: %w", err)
+	}
+
+	var lists []backend.TaskList
+	for _, board := range boards {
+		if board.Archived || board.DeletedAt > 0 {
+			continue
+		}
+		if !db.config.DeckStackLists {
+			lists = append(lists, boardToTaskList(board, db.boardURL(board.ID)))
+			continue
+		}
+
+		stacks, err := db.client.GetStacks(board.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get stacks of board %s: %w", board.Title, err)
+		}
+		slices.SortStableFunc(stacks, func(a, b Stack) int { return cmp.Compare(a.Order, b.Order) })
+		for _, stack := range stacks {
+			lists = append(lists, stackToTaskList(board, stack, db.boardURL(board.ID)))
+		}
+	}
+	return lists, nil
+}
+
+// placedCard is a card with the stack it is in
+type placedCard struct {
+	card  Card
+	stack Stack
+}
+
+// cards returns the cards of a list, active and archived, and the active
+// stacks of its board
+func (db *DeckBackend) cards(listID string) ([]placedCard, []Stack, error) {
+	ref, err := parseListID(listID)
+	if err != nil {
+		return nil, nil, err
+	}
+	stacks, err := db.client.GetStacks(ref.boardID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get cards: %w", err)
+	}
+	archived, err := db.client.GetArchivedStacks(ref.boardID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get archived cards: %w", err)
+	}
+
+	var cards []placedCard
+	for _, stack := range slices.Concat(stacks, archived) {
+		if ref.stackID != 0 && stack.ID != ref.stackID {
+			continue
+		}
+		for _, card := range stack.Cards {
+			if card.DeletedAt == 0 {
+				cards = append(cards, placedCard{card: card, stack: stack})
+			}
+		}
+	}
+	return cards, stacks, nil
+}
+
+// tasks returns the cards of a list as tasks
+func (db *DeckBackend) tasks(listID string) ([]backend.Task, error) {
+	cards, _, err := db.cards(listID)
+	if err != nil {
+		return nil, err
+	}
+	tasks := make([]backend.Task, len(cards))
+	for i, placed := range cards {
+		tasks[i] = cardToTask(placed.card, db.isDoneStack(placed.stack))
+	}
+	return tasks, nil
+}
+
+// GetTasks returns the cards of a list, archived ones included
+func (db *DeckBackend) GetTasks(listID string, filter *backend.TaskFilter) ([]backend.Task, error) {
+	all, err := db.tasks(listID)
+	if err != nil {
+		return nil, err
+	}
+	var tasks []backend.Task
+	for _, task := range all {
+		if matchesFilter(task, filter) {
+			tasks = append(tasks, task)
+		}
+	}
+	db.SortTasks(tasks)
+	return tasks, nil
+}
+
+// matchesFilter checks if a task matches the given filter
+func matchesFilter(task backend.Task, filter *backend.TaskFilter) bool {
+	if filter == nil {
+		return true
+	}
+	if !filter.MatchesStatus(task.Status) || !filter.MatchesParent(task.ParentUID) ||
+		!filter.MatchesModified(task.Modified) || !filter.MatchesCompleted(task.Completed) ||
+		!filter.MatchesPriority(task.Priority) || !filter.MatchesOverdue(task.Status, task.DueDate, task.AllDay) ||
+		!filter.MatchesSummary(task.Summary) {
+		return false
+	}
+	if task.DueDate != nil {
+		if filter.DueAfter != nil && task.DueDate.Before(*filter.DueAfter) {
+			return false
+		}
+		if filter.DueBefore != nil && task.DueDate.After(*filter.DueBefore) {
+			return false
+		}
+	}
+	if filter.CreatedAfter != nil && task.Created.Before(*filter.CreatedAfter) {
+		return false
+	}
+	if filter.CreatedBefore != nil && task.Created.After(*filter.CreatedBefore) {
+		return false
+	}
+	return true
+}
+
+// FindTasksBySummary searches the cards of a list by title
+func (db *DeckBackend) FindTasksBySummary(listID string, summary string) ([]backend.Task, error) {
+	all, err := db.tasks(listID)
+	if err != nil {
+		return nil, err
+	}
+	var matches []backend.Task
+	for _, task := range all {
+		if strings.Contains(strings.ToLower(task.Summary), strings.ToLower(summary)) {
+			matches = append(matches, task)
+		}
+	}
+	backend.ExactSummaryMatchesFirst(matches, summary)
+	return matches, nil
+}
+
+// GetTask returns one card of a list
+func (db *DeckBackend) GetTask(listID string, taskUID string) (*backend.Task, error) {
+	return backend.ScanForTask(db, listID, taskUID)
+}
+
+// AddTask is not supported
+func (db *DeckBackend) AddTask(listID string, task backend.Task) (string, error) {
+	return "", unsupported("AddTask")
+}
+
+// UpdateTask completes a card by moving it to the done stack of its board.
+// Any other change is not supported.
+func (db *DeckBackend) UpdateTask(listID string, task backend.Task) error {
+	cards, stacks, err := db.cards(listID)
+	if err != nil {
+		return err
+	}
+	i := slices.IndexFunc(cards, func(placed placedCard) bool { return cardToTask(placed.card, false).UID == task.UID })
+	if i < 0 {
+		return backend.NewNotFoundError("UpdateTask", listID, task.UID)
+	}
+	placed := cards[i]
+	current := cardToTask(placed.card, db.isDoneStack(placed.stack))
+
+	if changesMoreThanStatus(current, task) || (task.Status != current.Status && task.Status != statusDone) {
+		return unsupported("UpdateTask")
+	}
+	if task.Status == current.Status {
+		return nil
+	}
+
+	done := slices.IndexFunc(stacks, db.isDoneStack)
+	if done < 0 {
+		ref, _ := parseListID(listID)
+		return backend.NewUnsupportedError("UpdateTask",
+			fmt.Sprintf("board %d has no %q stack to move done cards to (set deck_done_stack)", ref.boardID, db.doneStack()))
+	}
+	if err := db.client.MoveCard(stacks[done].BoardID, placed.stack.ID, placed.card.ID, stacks[done].ID); err != nil {
+		return fmt.Errorf("failed to move card to %s: %w", stacks[done].Title, err)
+	}
+	return nil
+}
+
+// changesMoreThanStatus reports whether updated changes a card in a way
+// other than its status and completion time
+func changesMoreThanStatus(current, updated backend.Task) bool {
+	return current.Summary != updated.Summary ||
+		current.Description != updated.Description ||
+		current.Priority != updated.Priority ||
+		current.ParentUID != updated.ParentUID ||
+		!sameTime(current.DueDate, updated.DueDate) ||
+		!sameTime(current.StartDate, updated.StartDate) ||
+		!sameLabels(current.Categories, updated.Categories)
+}
+
+func sameTime(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
+
+// sameLabels reports whether both hold the same labels, in any order
+func sameLabels(a, b []string) bool {
+	return slices.Equal(slices.Sorted(slices.Values(a)), slices.Sorted(slices.Values(b)))
+}
+
+// DeleteTask is not supported
+func (db *DeckBackend) DeleteTask(listID string, taskUID string) error {
+	return unsupported("DeleteTask")
+}
+
+// CreateTaskList is not supported
+func (db *DeckBackend) CreateTaskList(name, description, color string) (string, error) {
+	return "", unsupported("CreateTaskList")
+}
+
+// DeleteTaskList is not supported
+func (db *DeckBackend) DeleteTaskList(listID string) error {
+	return unsupported("DeleteTaskList")
+}
+
+// RenameTaskList is not supported
+func (db *DeckBackend) RenameTaskList(listID, newName string) error {
+	return unsupported("RenameTaskList")
+}
+
+// GetDeletedTaskLists returns no lists: deleted boards are not shown
+func (db *DeckBackend) GetDeletedTaskLists() ([]backend.TaskList, error) {
+	return []backend.TaskList{}, nil
+}
+
+// RestoreTaskList is not supported
+func (db *DeckBackend) RestoreTaskList(listID string) error {
+	return unsupported("RestoreTaskList")
+}
+
+// PermanentlyDeleteTaskList is not supported
+func (db *DeckBackend) PermanentlyDeleteTaskList(listID string) error {
+	return unsupported("PermanentlyDeleteTaskList")
+}
+
+// ParseStatusFlag converts user input to a CalDAV status
+func (db *DeckBackend) ParseStatusFlag(statusFlag string) (string, error) {
+	return statuskit.CalDAV.ParseStatusFlag(statusFlag)
+}
+
+// StatusToDisplayName converts a CalDAV status to its display name
+func (db *DeckBackend) StatusToDisplayName(backendStatus string) string {
+	return statuskit.CalDAV.StatusToDisplayName(backendStatus)
+}
+
+// SortTasks sorts cards by due date, cards without one last, then by creation
+func (db *DeckBackend) SortTasks(tasks []backend.Task) {
+	slices.SortStableFunc(tasks, func(a, b backend.Task) int {
+		switch {
+		case a.DueDate == nil && b.DueDate != nil:
+			return 1
+		case a.DueDate != nil && b.DueDate == nil:
+			return -1
+		case a.DueDate != nil && !a.DueDate.Equal(*b.DueDate):
+			return a.DueDate.Compare(*b.DueDate)
+		}
+		return a.Created.Compare(b.Created)
+	})
+}
+
+// GetPriorityColor returns the color of a priority; cards have none
+func (db *DeckBackend) GetPriorityColor(priority int) string {
+	return statuskit.DefaultPalette.Color(priority)
+}
+
+// GetBackendDisplayName returns formatted display name
+func (db *DeckBackend) GetBackendDisplayName() string {
+	return fmt.Sprintf("[deck:%s]", db.GetBackendContext())
+}
+
+// GetBackendType returns the backend type identifier
+func (db *DeckBackend) GetBackendType() string {
+	return "deck"
+}
+
+// MaxSummaryLength returns the most characters Deck accepts in a card title
+func (db *DeckBackend) MaxSummaryLength() int {
+	return 255
+}
+
+// GetBackendContext returns the user and server
+func (db *DeckBackend) GetBackendContext() string {
+	host := strings.TrimPrefix(strings.TrimPrefix(db.serverURL, "https://"), "http://")
+	return db.username + "@" + host
+}
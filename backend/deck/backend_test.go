@@ -0,0 +1,243 @@
+package deck
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"gosynctasks/backend"
+)
+
+// fakeDeck serves the fixtures as the Deck API of one user, with ETags
+type fakeDeck struct {
+	mu           sync.Mutex
+	requests     int
+	notModified  int
+	reorders     []string // "path body" of each reorder request
+	withoutStack string   // Title of a stack to leave out, to test boards without it
+}
+
+func (f *fakeDeck) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.requests++
+
+	user, pass, _ := r.BasicAuth()
+	if user != "alice" || pass != "secret" || r.Header.Get("OCS-APIRequest") != "true" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	path := strings.TrimPrefix(r.URL.Path, apiPath)
+	if r.Method == http.MethodPut && strings.HasSuffix(path, "/reorder") {
+		var body reorderRequest
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		data, _ := json.Marshal(body)
+		f.reorders = append(f.reorders, path+" "+string(data))
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	fixtures := map[string]string{
+		"/boards":                   "boards.json",
+		"/boards/1/stacks":          "stacks.json",
+		"/boards/1/stacks/archived": "stacks_archived.json",
+	}
+	name, ok := fixtures[path]
+	if !ok || r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	data, _ := os.ReadFile(filepath.Join("testdata", name))
+	if f.withoutStack != "" && name == "stacks.json" {
+		var stacks []Stack
+		_ = json.Unmarshal(data, &stacks)
+		var kept []Stack
+		for _, stack := range stacks {
+			if stack.Title != f.withoutStack {
+				kept = append(kept, stack)
+			}
+		}
+		data, _ = json.Marshal(kept)
+	}
+
+	etag := `"` + name + `"`
+	if r.Header.Get("If-None-Match") == etag {
+		f.notModified++
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	w.Header().Set("ETag", etag)
+	_, _ = w.Write(data)
+}
+
+// isKind reports whether err is a BackendError for which the predicate holds
+func isKind(err error, is func(*backend.BackendError) bool) bool {
+	var backendErr *backend.BackendError
+	return errors.As(err, &backendErr) && is(backendErr)
+}
+
+func newTestDeck(t *testing.T, config backend.BackendConfig) (*DeckBackend, *fakeDeck) {
+	t.Helper()
+	fake := &fakeDeck{}
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+
+	config.Name = "deck-test"
+	config.URL = strings.Replace(server.URL, "http://", "http://alice:secret@", 1)
+	config.AllowHTTP = true
+	db, err := NewDeckBackend(config)
+	if err != nil {
+		t.Fatalf("NewDeckBackend() error = %v", err)
+	}
+	return db, fake
+}
+
+func TestGetTaskLists(t *testing.T) {
+	db, _ := newTestDeck(t, backend.BackendConfig{})
+	lists, err := db.GetTaskLists()
+	if err != nil {
+		t.Fatalf("GetTaskLists() error = %v", err)
+	}
+	if len(lists) != 1 || lists[0].ID != "1" || lists[0].Name != "Work" {
+		t.Fatalf("GetTaskLists() = %+v, want only the active board", lists)
+	}
+	if !strings.HasSuffix(lists[0].URL, "/index.php/apps/deck/#/board/1") {
+		t.Errorf("board URL = %s", lists[0].URL)
+	}
+
+	db, _ = newTestDeck(t, backend.BackendConfig{DeckStackLists: true})
+	lists, err = db.GetTaskLists()
+	if err != nil {
+		t.Fatalf("GetTaskLists() error = %v", err)
+	}
+	var paths []string
+	for _, list := range lists {
+		paths = append(paths, list.ID+" "+list.Path)
+	}
+	if got := strings.Join(paths, ", "); got != "1/10 Work/To do, 1/11 Work/Done" {
+		t.Errorf("stack lists = %s, want them in stack order", got)
+	}
+}
+
+func TestGetTasks(t *testing.T) {
+	db, fake := newTestDeck(t, backend.BackendConfig{})
+
+	tasks, err := db.GetTasks("1", nil)
+	if err != nil {
+		t.Fatalf("GetTasks() error = %v", err)
+	}
+	statuses := map[string]string{}
+	for _, task := range tasks {
+		statuses[task.Summary] = task.Status
+	}
+	want := map[string]string{
+		"Write report":  statusOpen,
+		"Review report": statusDone,
+		"Ship release":  statusDone,
+		"Old idea":      statusDone,
+	}
+	if len(statuses) != len(want) {
+		t.Fatalf("GetTasks() = %v, want %v (deleted cards left out)", statuses, want)
+	}
+	for summary, status := range want {
+		if statuses[summary] != status {
+			t.Errorf("%s: status = %s, want %s", summary, statuses[summary], status)
+		}
+	}
+	if tasks[0].Summary != "Write report" {
+		t.Errorf("first task = %s, want the one with a due date", tasks[0].Summary)
+	}
+
+	open, err := db.GetTasks("1", &backend.TaskFilter{Statuses: &[]string{statusOpen}})
+	if err != nil || len(open) != 1 {
+		t.Errorf("GetTasks(open) = %d tasks, %v, want 1", len(open), err)
+	}
+
+	stack, err := db.GetTasks("1/11", nil)
+	if err != nil || len(stack) != 1 || stack[0].Summary != "Ship release" {
+		t.Errorf("GetTasks(stack) = %+v, %v, want the cards of the stack", stack, err)
+	}
+
+	// Unchanged responses are revalidated with their ETag and reused
+	if fake.notModified == 0 {
+		t.Error("expected repeated requests to be answered 304 Not Modified")
+	}
+
+	if _, err := db.GetTask("1", "999"); !isKind(err, (*backend.BackendError).IsNotFound) {
+		t.Errorf("GetTask(missing) error = %v, want not found", err)
+	}
+	matches, err := db.FindTasksBySummary("1", "report")
+	if err != nil || len(matches) != 2 {
+		t.Errorf("FindTasksBySummary() = %d tasks, %v, want 2", len(matches), err)
+	}
+}
+
+func TestCompleteCard(t *testing.T) {
+	db, fake := newTestDeck(t, backend.BackendConfig{})
+
+	task, err := db.GetTask("1", "100")
+	if err != nil {
+		t.Fatalf("GetTask() error = %v", err)
+	}
+	task.Status = statusDone
+	if err := db.UpdateTask("1", *task); err != nil {
+		t.Fatalf("UpdateTask(done) error = %v", err)
+	}
+	want := `/boards/1/stacks/10/cards/100/reorder {"order":0,"stackId":11}`
+	if len(fake.reorders) != 1 || fake.reorders[0] != want {
+		t.Errorf("reorders = %v, want %s", fake.reorders, want)
+	}
+
+	// Completing a done card changes nothing
+	done, _ := db.GetTask("1", "102")
+	if err := db.UpdateTask("1", *done); err != nil || len(fake.reorders) != 1 {
+		t.Errorf("UpdateTask(already done) = %v, %d reorders", err, len(fake.reorders))
+	}
+
+	// A board with another done stack is configured with deck_done_stack
+	db, fake = newTestDeck(t, backend.BackendConfig{DeckDoneStack: "Shipped"})
+	fake.withoutStack = "Done"
+	if err := db.UpdateTask("1", *task); !isKind(err, (*backend.BackendError).IsUnsupported) {
+		t.Errorf("UpdateTask() without a done stack error = %v, want unsupported", err)
+	}
+}
+
+func TestWritesAreUnsupported(t *testing.T) {
+	db, fake := newTestDeck(t, backend.BackendConfig{})
+
+	task, err := db.GetTask("1", "100")
+	if err != nil {
+		t.Fatalf("GetTask() error = %v", err)
+	}
+	renamed := *task
+	renamed.Summary = "Renamed"
+	reopened, _ := db.GetTask("1", "102")
+	reopened.Status = statusOpen
+
+	_, addErr := db.AddTask("1", backend.Task{Summary: "New"})
+	_, createErr := db.CreateTaskList("New board", "", "")
+	errs := map[string]error{
+		"AddTask":         addErr,
+		"UpdateTask":      db.UpdateTask("1", renamed),
+		"UpdateTask open": db.UpdateTask("1", *reopened),
+		"DeleteTask":      db.DeleteTask("1", "100"),
+		"CreateTaskList":  createErr,
+		"DeleteTaskList":  db.DeleteTaskList("1"),
+		"RenameTaskList":  db.RenameTaskList("1", "Other"),
+	}
+	for operation, err := range errs {
+		if !isKind(err, (*backend.BackendError).IsUnsupported) {
+			t.Errorf("%s error = %v, want unsupported", operation, err)
+		}
+	}
+	if len(fake.reorders) != 0 {
+		t.Errorf("unsupported writes moved cards: %v", fake.reorders)
+	}
+}
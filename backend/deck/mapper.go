@@ -0,0 +1,112 @@
+package deck
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"gosynctasks/backend"
+)
+
+// Task statuses, as CalDAV names like the Nextcloud backend
+const (
+	statusOpen = "NEEDS-ACTION"
+	statusDone = "COMPLETED"
+)
+
+// listRef is what a list ID stands for: a board, or one stack of it
+type listRef struct {
+	boardID int
+	stackID int // 0 for the whole board
+}
+
+// boardListID returns the list ID of a board
+func boardListID(boardID int) string {
+	return strconv.Itoa(boardID)
+}
+
+// stackListID returns the list ID of a stack, "board/stack"
+func stackListID(boardID, stackID int) string {
+	return fmt.Sprintf("%d/%d", boardID, stackID)
+}
+
+// parseListID reads a list ID made by boardListID or stackListID
+func parseListID(listID string) (listRef, error) {
+	boardPart, stackPart, hasStack := strings.Cut(listID, "/")
+	boardID, err := strconv.Atoi(boardPart)
+	if err != nil {
+		return listRef{}, fmt.Errorf("invalid Deck list ID %q", listID)
+	}
+	ref := listRef{boardID: boardID}
+	if hasStack {
+		if ref.stackID, err = strconv.Atoi(stackPart); err != nil {
+			return listRef{}, fmt.Errorf("invalid Deck list ID %q", listID)
+		}
+	}
+	return ref, nil
+}
+
+// boardToTaskList converts a board to the list of all its cards
+func boardToTaskList(board Board, boardURL string) backend.TaskList {
+	list := backend.TaskList{
+		ID:    boardListID(board.ID),
+		Name:  board.Title,
+		URL:   boardURL,
+		CTags: board.ETag,
+	}
+	if board.Color != "" {
+		list.Color = "#" + board.Color
+	}
+	return list
+}
+
+// stackToTaskList converts a stack to a list nested in its board's, named
+// "Board/Stack"
+func stackToTaskList(board Board, stack Stack, boardURL string) backend.TaskList {
+	list := boardToTaskList(board, boardURL)
+	list.ID = stackListID(board.ID, stack.ID)
+	list.Name = stack.Title
+	list.Path = board.Title + "/" + stack.Title
+	list.CTags = stack.ETag
+	return list
+}
+
+// cardToTask converts a card. A card is done when marked done, archived, or
+// in the done stack.
+func cardToTask(card Card, inDoneStack bool) backend.Task {
+	task := backend.Task{
+		UID:         strconv.Itoa(card.ID),
+		Summary:     card.Title,
+		Description: card.Description,
+		Status:      statusOpen,
+		DueDate:     parseTime(card.DueDate),
+		Created:     time.Unix(card.CreatedAt, 0),
+		Modified:    time.Unix(card.LastModified, 0),
+	}
+	for _, label := range card.Labels {
+		task.Categories = append(task.Categories, label.Title)
+	}
+
+	if done := parseTime(card.Done); done != nil {
+		task.Status = statusDone
+		task.Completed = done
+	} else if card.Archived || inDoneStack {
+		task.Status = statusDone
+		completed := task.Modified
+		task.Completed = &completed
+	}
+	return task
+}
+
+// parseTime parses an optional RFC 3339 time of the API
+func parseTime(value *string) *time.Time {
+	if value == nil || *value == "" {
+		return nil
+	}
+	t, err := time.Parse(time.RFC3339, *value)
+	if err != nil {
+		return nil
+	}
+	return &t
+}
@@ -0,0 +1,107 @@
+package deck
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+// loadFixture decodes a JSON fixture of testdata into v
+func loadFixture(t *testing.T, name string, v any) {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatalf("failed to read fixture: %v", err)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		t.Fatalf("failed to decode %s: %v", name, err)
+	}
+}
+
+func TestBoardAndStackParsing(t *testing.T) {
+	var boards []Board
+	loadFixture(t, "boards.json", &boards)
+	if len(boards) != 3 || !boards[1].Archived || boards[2].DeletedAt == 0 {
+		t.Fatalf("unexpected boards: %+v", boards)
+	}
+
+	list := boardToTaskList(boards[0], "https://cloud.example.com/index.php/apps/deck/#/board/1")
+	if list.ID != "1" || list.Name != "Work" || list.Color != "#0082c9" || list.CTags != "b1-etag" {
+		t.Errorf("boardToTaskList() = %+v", list)
+	}
+
+	var stacks []Stack
+	loadFixture(t, "stacks.json", &stacks)
+	stackList := stackToTaskList(boards[0], stacks[1], list.URL)
+	if stackList.ID != "1/10" || stackList.Name != "To do" || stackList.Path != "Work/To do" || stackList.CTags != "s10-etag" {
+		t.Errorf("stackToTaskList() = %+v", stackList)
+	}
+}
+
+func TestCardToTask(t *testing.T) {
+	var stacks []Stack
+	loadFixture(t, "stacks.json", &stacks)
+	var archived []Stack
+	loadFixture(t, "stacks_archived.json", &archived)
+	todo, done := stacks[1], stacks[0]
+
+	report := cardToTask(todo.Cards[0], false)
+	if report.UID != "100" || report.Summary != "Write report" || report.Description != "Quarterly numbers" {
+		t.Errorf("cardToTask() = %+v", report)
+	}
+	if report.Status != statusOpen || report.Completed != nil {
+		t.Errorf("open card status = %s, completed %v", report.Status, report.Completed)
+	}
+	if report.DueDate == nil || report.DueDate.UTC().Format("2006-01-02 15:04") != "2026-03-01 12:00" {
+		t.Errorf("due date = %v", report.DueDate)
+	}
+	if !slices.Equal(report.Categories, []string{"urgent"}) {
+		t.Errorf("categories = %v, want the labels", report.Categories)
+	}
+
+	tests := []struct {
+		name          string
+		card          Card
+		inDoneStack   bool
+		wantCompleted string
+	}{
+		{"marked done", todo.Cards[1], false, "2026-02-01 09:30"},
+		{"in done stack", done.Cards[0], true, "2023-11-16 02:00"},
+		{"archived", archived[0].Cards[0], false, "2023-07-22 04:35"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			task := cardToTask(tt.card, tt.inDoneStack)
+			if task.Status != statusDone {
+				t.Errorf("status = %s, want %s", task.Status, statusDone)
+			}
+			if task.Completed == nil || task.Completed.UTC().Format("2006-01-02 15:04") != tt.wantCompleted {
+				t.Errorf("completed = %v, want %s", task.Completed, tt.wantCompleted)
+			}
+		})
+	}
+}
+
+func TestParseListID(t *testing.T) {
+	tests := []struct {
+		listID  string
+		want    listRef
+		wantErr bool
+	}{
+		{listID: "12", want: listRef{boardID: 12}},
+		{listID: "12/34", want: listRef{boardID: 12, stackID: 34}},
+		{listID: "work", wantErr: true},
+		{listID: "12/done", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := parseListID(tt.listID)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("parseListID(%q) = %+v, %v", tt.listID, got, err)
+		}
+	}
+	if boardListID(12) != "12" || stackListID(12, 34) != "12/34" {
+		t.Error("list IDs do not round trip")
+	}
+}
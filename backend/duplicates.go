@@ -0,0 +1,56 @@
+package backend
+
+import "fmt"
+
+// DuplicateUIDs returns the UIDs shared by more than one task, with the
+// number of tasks sharing each. It returns nil when all UIDs are unique.
+func DuplicateUIDs(tasks []Task) map[string]int {
+	counts := make(map[string]int, len(tasks))
+	for _, task := range tasks {
+		counts[task.UID]++
+	}
+
+	var duplicates map[string]int
+	for uid, count := range counts {
+		if count > 1 {
+			if duplicates == nil {
+				duplicates = make(map[string]int)
+			}
+			duplicates[uid] = count
+		}
+	}
+	return duplicates
+}
+
+// MarkDuplicateUIDs returns a copy of tasks in which every task sharing its
+// UID with another has an occurrence marker appended to its summary, e.g.
+// "Buy milk [uid 2/2]", so all copies stay visible and can be told apart.
+// The marked tasks are for display only and must not be written back.
+func MarkDuplicateUIDs(tasks []Task) []Task {
+	duplicates := DuplicateUIDs(tasks)
+	if duplicates == nil {
+		return tasks
+	}
+
+	marked := make([]Task, len(tasks))
+	seen := make(map[string]int, len(duplicates))
+	for i, task := range tasks {
+		if count, ok := duplicates[task.UID]; ok {
+			seen[task.UID]++
+			task.Summary = fmt.Sprintf("%s [uid %d/%d]", task.Summary, seen[task.UID], count)
+		}
+		marked[i] = task
+	}
+	return marked
+}
+
+// NewDuplicateUIDError reports a write refused because several tasks share the UID
+func NewDuplicateUIDError(operation, listID, taskUID string, count int) *BackendError {
+	return &BackendError{
+		Operation: operation,
+		Message:   fmt.Sprintf("%d tasks share UID %s; resolve the duplicates first", count, taskUID),
+		TaskUID:   taskUID,
+		ListID:    listID,
+		Kind:      KindDuplicateUID,
+	}
+}
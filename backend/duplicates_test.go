@@ -0,0 +1,44 @@
+package backend
+
+import "testing"
+
+func TestDuplicateUIDs(t *testing.T) {
+	if got := DuplicateUIDs([]Task{{UID: "a"}, {UID: "b"}}); got != nil {
+		t.Errorf("expected nil for unique UIDs, got %v", got)
+	}
+
+	got := DuplicateUIDs([]Task{{UID: "a"}, {UID: "b"}, {UID: "a"}, {UID: "a"}, {UID: "c"}, {UID: "c"}})
+	if len(got) != 2 || got["a"] != 3 || got["c"] != 2 {
+		t.Errorf("unexpected duplicates: %v", got)
+	}
+}
+
+func TestMarkDuplicateUIDs(t *testing.T) {
+	tasks := []Task{
+		{UID: "a", Summary: "Buy milk"},
+		{UID: "b", Summary: "Unique"},
+		{UID: "a", Summary: "Buy milk"},
+	}
+
+	marked := MarkDuplicateUIDs(tasks)
+
+	want := []string{"Buy milk [uid 1/2]", "Unique", "Buy milk [uid 2/2]"}
+	for i, task := range marked {
+		if task.Summary != want[i] {
+			t.Errorf("task %d: got %q, want %q", i, task.Summary, want[i])
+		}
+	}
+	if tasks[0].Summary != "Buy milk" {
+		t.Errorf("input was modified: %q", tasks[0].Summary)
+	}
+}
+
+func TestDuplicateUIDError(t *testing.T) {
+	err := NewDuplicateUIDError("UpdateTask", "list-1", "a", 2)
+	if !err.IsDuplicateUID() || err.IsValidation() {
+		t.Errorf("unexpected kind: %q", err.Kind)
+	}
+	if err.TaskUID != "a" || err.ListID != "list-1" {
+		t.Errorf("missing context: %+v", err)
+	}
+}
@@ -1,18 +1,44 @@
 package backend
 
-import "fmt"
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+)
+
+// ErrorKind classifies a BackendError beyond its HTTP status
+type ErrorKind string
+
+const (
+	// KindValidation marks errors caught before any request was made
+	KindValidation ErrorKind = "validation"
+
+	// KindDuplicateUID marks writes refused because several tasks share the UID
+	KindDuplicateUID ErrorKind = "duplicate_uid"
+
+	// KindNotFound marks lookups of a task or list that does not exist, for
+	// backends that have no HTTP status to report
+	KindNotFound ErrorKind = "not_found"
+
+	// KindUnsupported marks operations the backend cannot perform, such as
+	// writes to a read-only backend
+	KindUnsupported ErrorKind = "unsupported"
+)
 
 // BackendError represents an error from a backend operation
 // It provides structured error information including HTTP status codes,
 // operation context, and the underlying error message
 type BackendError struct {
-	Operation  string // e.g., "DeleteTask", "GetTasks", "UpdateTask"
-	StatusCode int    // HTTP status code (0 if not an HTTP error)
-	Message    string // Human-readable error message
-	TaskUID    string // Optional: affected task UID
-	ListID     string // Optional: affected list ID
-	Body       string // Optional: response body for debugging
-	Err        error  // Optional: underlying error
+	Operation  string    // e.g., "DeleteTask", "GetTasks", "UpdateTask"
+	StatusCode int       // HTTP status code (0 if not an HTTP error)
+	Message    string    // Human-readable error message
+	TaskUID    string    // Optional: affected task UID
+	ListID     string    // Optional: affected list ID
+	Body       string    // Optional: response body for debugging
+	Err        error     // Optional: underlying error
+	Kind       ErrorKind // Optional: error classification
 }
 
 // Error implements the error interface
@@ -28,9 +54,9 @@ func (e *BackendError) Unwrap() error {
 	return e.Err
 }
 
-// IsNotFound returns true if the error is a 404 Not Found
+// IsNotFound returns true if the error is a 404 Not Found, or of kind KindNotFound
 func (e *BackendError) IsNotFound() bool {
-	return e.StatusCode == 404
+	return e.StatusCode == 404 || e.Kind == KindNotFound
 }
 
 // IsUnauthorized returns true if the error is a 401 Unauthorized or 403 Forbidden
@@ -38,11 +64,38 @@ func (e *BackendError) IsUnauthorized() bool {
 	return e.StatusCode == 401 || e.StatusCode == 403
 }
 
+// IsConflict returns true if the write was refused because the resource
+// changed since it was read (412 Precondition Failed on an If-Match)
+func (e *BackendError) IsConflict() bool {
+	return e.StatusCode == 412
+}
+
 // IsServerError returns true if the error is a 5xx server error
 func (e *BackendError) IsServerError() bool {
 	return e.StatusCode >= 500 && e.StatusCode < 600
 }
 
+// IsValidation returns true if the error was raised by input validation
+func (e *BackendError) IsValidation() bool {
+	return e.Kind == KindValidation
+}
+
+// IsDuplicateUID returns true if the write was refused because the task UID is not unique
+func (e *BackendError) IsDuplicateUID() bool {
+	return e.Kind == KindDuplicateUID
+}
+
+// IsUnsupported returns true if the backend cannot perform the operation
+func (e *BackendError) IsUnsupported() bool {
+	return e.Kind == KindUnsupported
+}
+
+// NewUnsupportedError returns the error of an operation the backend cannot
+// perform, with the reason given by message
+func NewUnsupportedError(operation, message string) *BackendError {
+	return &BackendError{Operation: operation, Message: message, Kind: KindUnsupported}
+}
+
 // NewBackendError creates a new BackendError
 func NewBackendError(operation string, statusCode int, message string) *BackendError {
 	return &BackendError{
@@ -75,3 +128,72 @@ func (e *BackendError) WithError(err error) *BackendError {
 	e.Err = err
 	return e
 }
+
+// IsUnreachable reports whether err means the backend could not be reached or
+// is temporarily unavailable (network failure, timeout or a 5xx response),
+// rather than that it rejected the request
+func IsUnreachable(err error) bool {
+	var backendErr *BackendError
+	if errors.As(err, &backendErr) && backendErr.StatusCode != 0 {
+		return backendErr.IsServerError()
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) || errors.Is(err, context.DeadlineExceeded)
+}
+
+// UnreachableReason summarizes why a backend is unreachable, e.g. "timeout"
+func UnreachableReason(err error) string {
+	var backendErr *BackendError
+	if errors.As(err, &backendErr) && backendErr.StatusCode != 0 {
+		return fmt.Sprintf("HTTP %d", backendErr.StatusCode)
+	}
+	var netErr net.Error
+	if errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout()) {
+		return "timeout"
+	}
+	var opErr *net.OpError
+	if errors.As(err, &opErr) && opErr.Err != nil {
+		return opErr.Err.Error()
+	}
+	return err.Error()
+}
+
+// NewValidationError creates a BackendError for invalid input
+func NewValidationError(operation string, message string) *BackendError {
+	return &BackendError{
+		Operation: operation,
+		Message:   message,
+		Kind:      KindValidation,
+	}
+}
+
+// NewNotFoundError creates a BackendError for a task absent from a list
+func NewNotFoundError(operation, listID, taskUID string) *BackendError {
+	return &BackendError{
+		Operation: operation,
+		Message:   fmt.Sprintf("task %s not found in list %s", taskUID, listID),
+		TaskUID:   taskUID,
+		ListID:    listID,
+		Kind:      KindNotFound,
+	}
+}
+
+// ValidateTaskRef rejects empty or whitespace-only list IDs and task UIDs,
+// which would otherwise address the collection instead of a single task
+func ValidateTaskRef(operation, listID, taskUID string) error {
+	if strings.TrimSpace(listID) == "" {
+		return NewValidationError(operation, "list ID cannot be empty").WithTaskUID(taskUID)
+	}
+	if strings.TrimSpace(taskUID) == "" {
+		return NewValidationError(operation, "task UID cannot be empty").WithListID(listID)
+	}
+	return nil
+}
+
+// ValidateListID rejects empty or whitespace-only list IDs
+func ValidateListID(operation, listID string) error {
+	if strings.TrimSpace(listID) == "" {
+		return NewValidationError(operation, "list ID cannot be empty")
+	}
+	return nil
+}
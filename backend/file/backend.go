@@ -2,9 +2,9 @@ package file
 
 import (
 	"gosynctasks/backend"
+	"gosynctasks/backend/statuskit"
 	"fmt"
 	"net/url"
-	"strings"
 )
 
 func init() {
@@ -46,8 +46,14 @@ func (fB *FileBackend) FindTasksBySummary(listID string, summary string) ([]back
 	return nil, nil
 }
 
+func (fB *FileBackend) GetTask(listID string, taskUID string) (*backend.Task, error) {
+	return backend.ScanForTask(fB, listID, taskUID)
+}
+
+// AddTask returns the UID of the task, as the TaskManager contract asks of
+// every backend
 func (fB *FileBackend) AddTask(listID string, task backend.Task) (string, error) {
-	return "", nil
+	return task.UID, nil
 }
 
 func (fB *FileBackend) UpdateTask(listID string, task backend.Task) error {
@@ -84,43 +90,12 @@ func (fB *FileBackend) PermanentlyDeleteTaskList(listID string) error {
 }
 
 func (fB *FileBackend) ParseStatusFlag(statusFlag string) (string, error) {
-	if statusFlag == "" {
-		return "", fmt.Errorf("status flag cannot be empty")
-	}
-
-	upperStatus := strings.ToUpper(statusFlag)
-
 	// FileBackend uses display names directly (TODO, DONE, PROCESSING, CANCELLED)
-	switch upperStatus {
-	case "T", "TODO":
-		return "TODO", nil
-	case "D", "DONE":
-		return "DONE", nil
-	case "P", "PROCESSING":
-		return "PROCESSING", nil
-	case "C", "CANCELLED":
-		return "CANCELLED", nil
-	default:
-		return "", fmt.Errorf("invalid status: %s (valid: TODO/T, DONE/D, PROCESSING/P, CANCELLED/C)", statusFlag)
-	}
+	return statuskit.AppNames.ParseStatusFlag(statusFlag)
 }
 
 func (fB *FileBackend) StatusToDisplayName(backendStatus string) string {
-	// FileBackend uses canonical display names internally
-	// Normalize to ensure we return one of the canonical names
-	switch strings.ToUpper(backendStatus) {
-	case "TODO":
-		return "TODO"
-	case "DONE":
-		return "DONE"
-	case "PROCESSING":
-		return "PROCESSING"
-	case "CANCELLED":
-		return "CANCELLED"
-	default:
-		// Unknown status, return as-is (uppercase)
-		return strings.ToUpper(backendStatus)
-	}
+	return statuskit.AppNames.StatusToDisplayName(backendStatus)
 }
 
 func (fB *FileBackend) SortTasks(tasks []backend.Task) {
@@ -128,11 +103,7 @@ func (fB *FileBackend) SortTasks(tasks []backend.Task) {
 }
 
 func (fB *FileBackend) GetPriorityColor(priority int) string {
-	// File backend: default color scheme
-	if priority >= 1 && priority <= 4 {
-		return "\033[31m"
-	}
-	return ""
+	return statuskit.DefaultPalette.Color(priority)
 }
 
 func (fB *FileBackend) GetBackendDisplayName() string {
@@ -2,6 +2,7 @@ package git
 
 import (
 	"gosynctasks/backend"
+	"gosynctasks/backend/statuskit"
 	"crypto/rand"
 	"encoding/hex"
 	"fmt"
@@ -30,13 +31,18 @@ type GitBackend struct {
 	RepoPath     string            // Absolute path to git repository root
 	FilePath     string            // Absolute path to task file (e.g., TODO.md)
 	taskLists    map[string][]backend.Task // Tasks organized by list name (## headers)
+	listOrder    []string          // List names in file order
 	fileModTime  time.Time         // Last modification time of file
 	detectedInfo string            // Human-readable detection info
+	blame        *blameCache       // Commit time of each line, for task timestamps
 }
 
 const (
 	// Marker that must be present in markdown file to enable gosynctasks
 	gitBackendMarker = "<!-- gosynctasks:enabled -->"
+
+	// Start of the message of commits made by auto-commit
+	autoCommitPrefix = "gosynctasks:"
 )
 
 // NewGitBackend creates a new Git backend instance.
@@ -168,13 +174,21 @@ func (gb *GitBackend) loadFile() error {
 	}
 
 	gb.taskLists = taskLists
+	gb.listOrder = parser.listOrder
+	gb.fillTimestamps(parser.taskLines)
+
+	// Sync needs every task to keep its UID between reads, so write the
+	// ones generated for hand-written tasks back to the file
+	if gb.assignMissingUIDs() {
+		return gb.saveFile()
+	}
 	return nil
 }
 
 // saveFile writes tasks back to the markdown file.
 func (gb *GitBackend) saveFile() error {
 	writer := NewMarkdownWriter()
-	content := writer.Write(gb.taskLists)
+	content := writer.WriteOrdered(gb.taskLists, gb.listOrder)
 
 	// Check if file was modified externally
 	if info, err := os.Stat(gb.FilePath); err == nil {
@@ -219,7 +233,7 @@ func (gb *GitBackend) commitChanges() error {
 	}
 
 	// Commit
-	commitMsg := fmt.Sprintf("gosynctasks: Update tasks in %s", filepath.Base(gb.FilePath))
+	commitMsg := fmt.Sprintf("%s Update tasks in %s", autoCommitPrefix, filepath.Base(gb.FilePath))
 	cmd = exec.Command("git", "commit", "-m", commitMsg)
 	cmd.Dir = gb.RepoPath
 	if err := cmd.Run(); err != nil {
@@ -266,11 +280,12 @@ func (gb *GitBackend) GetTaskLists() ([]backend.TaskList, error) {
 	}
 
 	var lists []backend.TaskList
-	for name := range gb.taskLists {
+	for _, name := range listNames(gb.taskLists, gb.listOrder) {
 		lists = append(lists, backend.TaskList{
 			ID:          name,
 			Name:        name,
 			Description: fmt.Sprintf("%d tasks", len(gb.taskLists[name])),
+			CTags:       listCTag(gb.taskLists[name]),
 		})
 	}
 
@@ -339,6 +354,13 @@ func (gb *GitBackend) filterTasks(tasks []backend.Task, filter *backend.TaskFilt
 			}
 		}
 
+		// Check modified, completed, parent, priority, overdue and summary filters
+		if !filter.MatchesModified(task.Modified) || !filter.MatchesCompleted(task.Completed) || !filter.MatchesParent(task.ParentUID) ||
+			!filter.MatchesPriority(task.Priority) || !filter.MatchesOverdue(task.Status, task.DueDate, task.AllDay) ||
+			!filter.MatchesSummary(task.Summary) {
+			continue
+		}
+
 		filtered = append(filtered, task)
 	}
 
@@ -352,18 +374,35 @@ func (gb *GitBackend) FindTasksBySummary(listID string, summary string) ([]backe
 		return nil, err
 	}
 
-	summary = strings.ToLower(summary)
+	search := strings.ToLower(summary)
 	var matches []backend.Task
 
 	for _, task := range tasks {
-		if strings.Contains(strings.ToLower(task.Summary), summary) {
+		if strings.Contains(strings.ToLower(task.Summary), search) {
 			matches = append(matches, task)
 		}
 	}
+	backend.ExactSummaryMatchesFirst(matches, summary)
 
 	return matches, nil
 }
 
+// GetTask retrieves one task of the specified list by UID.
+func (gb *GitBackend) GetTask(listID string, taskUID string) (*backend.Task, error) {
+	// Reload file to get latest changes
+	if err := gb.loadFile(); err != nil {
+		return nil, err
+	}
+
+	for _, task := range gb.taskLists[listID] {
+		if task.UID == taskUID {
+			return &task, nil
+		}
+	}
+
+	return nil, backend.NewNotFoundError("GetTask", listID, taskUID)
+}
+
 // AddTask creates a new task in the specified list.
 func (gb *GitBackend) AddTask(listID string, task backend.Task) (string, error) {
 	// Reload file to get latest changes
@@ -377,12 +416,17 @@ func (gb *GitBackend) AddTask(listID string, task backend.Task) (string, error)
 	}
 
 	// Set timestamps
+	now := time.Now().Truncate(time.Second)
 	if task.Created.IsZero() {
-		task.Created = time.Now()
+		task.Created = now
 	}
-	task.Modified = time.Now()
+	task.Modified = now
+	task.Status = toGitStatus(task.Status)
 
 	// Add task to list
+	if _, exists := gb.taskLists[listID]; !exists {
+		gb.listOrder = append(gb.listOrder, listID)
+	}
 	gb.taskLists[listID] = append(gb.taskLists[listID], task)
 
 	// Save file
@@ -409,7 +453,8 @@ func (gb *GitBackend) UpdateTask(listID string, task backend.Task) error {
 	found := false
 	for i, t := range tasks {
 		if t.UID == task.UID {
-			task.Modified = time.Now()
+			task.Modified = time.Now().Truncate(time.Second)
+			task.Status = toGitStatus(task.Status)
 			tasks[i] = task
 			found = true
 			break
@@ -472,6 +517,7 @@ func (gb *GitBackend) CreateTaskList(name, description, color string) (string, e
 
 	// Create empty list
 	gb.taskLists[name] = []backend.Task{}
+	gb.listOrder = append(gb.listOrder, name)
 
 	// Save file
 	if err := gb.saveFile(); err != nil {
@@ -518,9 +564,14 @@ func (gb *GitBackend) RenameTaskList(listID, newName string) error {
 		return fmt.Errorf("task list %q already exists", newName)
 	}
 
-	// Rename by deleting old and creating new
+	// Rename by deleting old and creating new, keeping the list's place in the file
 	delete(gb.taskLists, listID)
 	gb.taskLists[newName] = tasks
+	for i, name := range gb.listOrder {
+		if name == listID {
+			gb.listOrder[i] = newName
+		}
+	}
 
 	// Save file
 	return gb.saveFile()
@@ -529,33 +580,12 @@ func (gb *GitBackend) RenameTaskList(listID, newName string) error {
 // ParseStatusFlag converts user input to backend status format.
 func (gb *GitBackend) ParseStatusFlag(statusFlag string) (string, error) {
 	// Git backend uses app-style status names
-	upper := strings.ToUpper(statusFlag)
-
-	// Handle abbreviations
-	switch upper {
-	case "T":
-		return "TODO", nil
-	case "D":
-		return "DONE", nil
-	case "P":
-		return "PROCESSING", nil
-	case "C":
-		return "CANCELLED", nil
-	}
-
-	// Handle full names
-	switch upper {
-	case "TODO", "DONE", "PROCESSING", "CANCELLED":
-		return upper, nil
-	}
-
-	return "", fmt.Errorf("invalid status flag: %s (use TODO/T, DONE/D, PROCESSING/P, CANCELLED/C)", statusFlag)
+	return statuskit.AppNames.ParseStatusFlag(statusFlag)
 }
 
 // StatusToDisplayName converts backend status to display name.
 func (gb *GitBackend) StatusToDisplayName(backendStatus string) string {
-	// Git backend already uses display names
-	return backendStatus
+	return statuskit.AppNames.StatusToDisplayName(backendStatus)
 }
 
 // SortTasks sorts tasks by priority (1=highest) and creation date.
@@ -588,17 +618,7 @@ func (gb *GitBackend) SortTasks(tasks []backend.Task) {
 
 // GetPriorityColor returns ANSI color code for priority.
 func (gb *GitBackend) GetPriorityColor(priority int) string {
-	// Similar to Nextcloud coloring
-	switch {
-	case priority >= 1 && priority <= 4:
-		return "\033[31m" // Red (high priority)
-	case priority == 5:
-		return "\033[33m" // Yellow (medium priority)
-	case priority >= 6 && priority <= 9:
-		return "\033[34m" // Blue (low priority)
-	default:
-		return "" // No color (undefined priority)
-	}
+	return statuskit.DefaultPalette.Color(priority)
 }
 
 // GetBackendDisplayName returns a formatted string for display in task list headers.
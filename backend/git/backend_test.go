@@ -216,6 +216,63 @@ func TestMarkdownRoundTrip(t *testing.T) {
 	}
 }
 
+// TestMarkdownSyncTags tests the tags sync relies on and that list order is kept
+func TestMarkdownSyncTags(t *testing.T) {
+	modified := time.Date(2025, 3, 1, 14, 30, 5, 0, time.UTC)
+	original := `<!-- gosynctasks:enabled -->
+
+## Zeta
+- [ ] Child @uid:c1 @parent:p1 @tags:phone,home+office @modified:2025-03-01T14:30:05Z
+
+## Alpha
+- [ ] Parent @uid:p1
+`
+	parser := NewMarkdownParser()
+	taskLists, err := parser.Parse(original)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	child := taskLists["Zeta"][0]
+	if child.ParentUID != "p1" || strings.Join(child.Categories, "|") != "phone|home office" || !child.Modified.Equal(modified) {
+		t.Errorf("parsed child = %+v", child)
+	}
+	if strings.Join(parser.listOrder, ",") != "Zeta,Alpha" {
+		t.Errorf("listOrder = %v", parser.listOrder)
+	}
+	if parser.taskLines["Alpha"][0] != 7 {
+		t.Errorf("parent line = %d, want 7", parser.taskLines["Alpha"][0])
+	}
+
+	written := NewMarkdownWriter().WriteOrdered(taskLists, parser.listOrder)
+	if strings.Index(written, "## Zeta") > strings.Index(written, "## Alpha") {
+		t.Errorf("list order not kept:\n%s", written)
+	}
+	if !strings.Contains(written, "@parent:p1 @tags:phone,home+office @modified:2025-03-01T14:30:05Z") {
+		t.Errorf("sync tags not written:\n%s", written)
+	}
+}
+
+// TestParseBlame tests reading line commit times from git blame
+func TestParseBlame(t *testing.T) {
+	output := "1111111111111111111111111111111111111111 1 1 2\n" +
+		"author Someone\ncommitter-time 1700000000\nsummary Edit tasks by hand\nfilename TODO.md\n\t<!-- gosynctasks:enabled -->\n" +
+		"1111111111111111111111111111111111111111 2 2\n\t\n" +
+		"2222222222222222222222222222222222222222 3 3 1\n" +
+		"committer-time 1700000100\nsummary gosynctasks: Update tasks in TODO.md\nfilename TODO.md\n\t- [ ] Ours\n" +
+		uncommittedSHA + " 4 4 1\ncommitter-time 1700000200\nsummary Version of TODO.md from TODO.md\nfilename TODO.md\n\t- [ ] Pending\n"
+
+	lines := parseBlame([]byte(output))
+	if got := lines[2]; !got.committed.Equal(time.Unix(1700000000, 0)) || got.ours {
+		t.Errorf("line 2 = %+v, want a hand edit", got)
+	}
+	if got := lines[3]; !got.ours {
+		t.Errorf("line 3 = %+v, want an auto-commit", got)
+	}
+	if got, ok := lines[4]; !ok || !got.committed.IsZero() {
+		t.Errorf("line 4 = %+v, want not committed", got)
+	}
+}
+
 // TestGitBackendFindRepo tests git repository detection
 func TestGitBackendFindRepo(t *testing.T) {
 	// This test requires being run in a git repository
@@ -0,0 +1,31 @@
+package git
+
+import (
+	"gosynctasks/backend"
+	"gosynctasks/backend/conformance"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestConformance runs the backend-agnostic TaskManager suite against a task
+// file in a scratch repository. Auto-commit stays off, so git is not needed.
+func TestConformance(t *testing.T) {
+	repo := t.TempDir()
+	if err := os.Mkdir(filepath.Join(repo, ".git"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(repo, "TODO.md"), []byte(gitBackendMarker+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	t.Chdir(repo)
+
+	gb, err := NewGitBackend(backend.BackendConfig{Type: "git", Enabled: true})
+	if err != nil {
+		t.Fatalf("NewGitBackend() error = %v", err)
+	}
+
+	// List IDs are the list headers, so renaming a list changes its ID
+	conformance.Run(t, func() backend.TaskManager { return gb },
+		conformance.Capabilities{Hierarchy: true, Categories: true})
+}
@@ -3,6 +3,7 @@ package git
 import (
 	"gosynctasks/backend"
 	"fmt"
+	"net/url"
 	"regexp"
 	"strings"
 	"time"
@@ -13,6 +14,10 @@ type MarkdownParser struct {
 	// Regex patterns for parsing
 	checkboxPattern *regexp.Regexp
 	tagPattern      *regexp.Regexp
+
+	// Set by Parse
+	listOrder []string         // List names in file order
+	taskLines map[string][]int // 1-based line of each task, by list
 }
 
 // NewMarkdownParser creates a new markdown parser.
@@ -29,6 +34,8 @@ func NewMarkdownParser() *MarkdownParser {
 func (p *MarkdownParser) Parse(content string) (map[string][]backend.Task, error) {
 	lines := strings.Split(content, "\n")
 	taskLists := make(map[string][]backend.Task)
+	p.listOrder = nil
+	p.taskLines = make(map[string][]int)
 	currentList := "Default"
 	var currentTask *backend.Task
 	var descriptionLines []string
@@ -49,6 +56,11 @@ func (p *MarkdownParser) Parse(content string) (map[string][]backend.Task, error
 			if currentList == "" {
 				currentList = fmt.Sprintf("List-%d", i)
 			}
+			// Keep lists without tasks, such as one just created
+			if _, exists := taskLists[currentList]; !exists {
+				taskLists[currentList] = []backend.Task{}
+				p.listOrder = append(p.listOrder, currentList)
+			}
 			continue
 		}
 
@@ -64,10 +76,9 @@ func (p *MarkdownParser) Parse(content string) (map[string][]backend.Task, error
 			statusChar := matches[1]
 			rest := matches[2]
 
+			// Timestamps missing from the tags are filled in by the backend
 			task := backend.Task{
-				Status:   p.parseStatus(statusChar),
-				Created:  time.Now(),
-				Modified: time.Now(),
+				Status: p.parseStatus(statusChar),
 			}
 
 			// Extract tags and summary
@@ -97,13 +108,25 @@ func (p *MarkdownParser) Parse(content string) (map[string][]backend.Task, error
 					if t, err := time.Parse("2006-01-02", value); err == nil {
 						task.Completed = &t
 					}
+				case "modified":
+					if t, err := time.Parse(time.RFC3339, value); err == nil {
+						task.Modified = t
+					}
+				case "parent":
+					task.ParentUID = value
+				case "tags":
+					task.Categories = parseCategories(value)
 				case "status":
 					task.Status = value
 				}
 			}
 
 			// Add to current list
+			if _, exists := taskLists[currentList]; !exists {
+				p.listOrder = append(p.listOrder, currentList)
+			}
 			taskLists[currentList] = append(taskLists[currentList], task)
+			p.taskLines[currentList] = append(p.taskLines[currentList], i+1)
 			currentTask = &taskLists[currentList][len(taskLists[currentList])-1]
 			continue
 		}
@@ -167,3 +190,18 @@ func (p *MarkdownParser) extractTags(text string) (string, map[string]string) {
 
 	return summary, tags
 }
+
+// parseCategories splits a comma-separated @tags value, unescaping each category
+func parseCategories(value string) []string {
+	var categories []string
+	for _, part := range strings.Split(value, ",") {
+		category, err := url.QueryUnescape(part)
+		if err != nil {
+			category = part
+		}
+		if category != "" {
+			categories = append(categories, category)
+		}
+	}
+	return categories
+}
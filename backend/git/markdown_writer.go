@@ -3,7 +3,10 @@ package git
 import (
 	"gosynctasks/backend"
 	"fmt"
+	"net/url"
+	"sort"
 	"strings"
+	"time"
 )
 
 // MarkdownWriter writes backend.Task structures back to markdown format.
@@ -14,53 +17,83 @@ func NewMarkdownWriter() *MarkdownWriter {
 	return &MarkdownWriter{}
 }
 
-// Write converts task lists to markdown format.
+// Write converts task lists to markdown format, with the lists sorted by name.
 func (w *MarkdownWriter) Write(taskLists map[string][]backend.Task) string {
+	return w.WriteOrdered(taskLists, nil)
+}
+
+// WriteOrdered converts task lists to markdown format. Lists named in order
+// are written first, in that order, so rewriting a file keeps its layout and
+// diffs stay small; the others follow sorted by name.
+func (w *MarkdownWriter) WriteOrdered(taskLists map[string][]backend.Task, order []string) string {
 	var builder strings.Builder
 
 	// Write marker at the top
 	builder.WriteString(gitBackendMarker)
 	builder.WriteString("\n\n")
 
-	// Write each task list
-	// Note: iteration order is non-deterministic, but that's okay for now
-	for listName, tasks := range taskLists {
-		// Write list header
-		builder.WriteString(fmt.Sprintf("## %s\n", listName))
-
-		// Write each task
-		for _, task := range tasks {
-			// Write checkbox with status
-			checkbox := w.formatCheckbox(task.Status)
-			builder.WriteString(fmt.Sprintf("- %s %s", checkbox, task.Summary))
-
-			// Write tags
-			tags := w.formatTags(task)
-			if tags != "" {
-				builder.WriteString(" " + tags)
-			}
+	for _, listName := range listNames(taskLists, order) {
+		w.writeList(&builder, listName, taskLists[listName])
+	}
 
-			builder.WriteString("\n")
+	return builder.String()
+}
 
-			// Write description if present
-			if task.Description != "" {
-				// Indent description lines
-				descLines := strings.Split(task.Description, "\n")
-				for _, line := range descLines {
-					builder.WriteString(fmt.Sprintf("  %s\n", line))
-				}
-			}
+// listNames returns the names of taskLists, those in order first
+func listNames(taskLists map[string][]backend.Task, order []string) []string {
+	var names []string
+	seen := make(map[string]bool)
+	for _, name := range order {
+		if _, exists := taskLists[name]; exists && !seen[name] {
+			names = append(names, name)
+			seen[name] = true
+		}
+	}
+	var rest []string
+	for name := range taskLists {
+		if !seen[name] {
+			rest = append(rest, name)
+		}
+	}
+	sort.Strings(rest)
+	return append(names, rest...)
+}
+
+// writeList writes the header and tasks of one list
+func (w *MarkdownWriter) writeList(builder *strings.Builder, listName string, tasks []backend.Task) {
+	// Write list header
+	builder.WriteString(fmt.Sprintf("## %s\n", listName))
+
+	// Write each task
+	for _, task := range tasks {
+		// Write checkbox with status
+		checkbox := w.formatCheckbox(task.Status)
+		builder.WriteString(fmt.Sprintf("- %s %s", checkbox, task.Summary))
+
+		// Write tags
+		tags := w.formatTags(task)
+		if tags != "" {
+			builder.WriteString(" " + tags)
 		}
 
 		builder.WriteString("\n")
+
+		// Write description if present
+		if task.Description != "" {
+			// Indent description lines
+			descLines := strings.Split(task.Description, "\n")
+			for _, line := range descLines {
+				builder.WriteString(fmt.Sprintf("  %s\n", line))
+			}
+		}
 	}
 
-	return builder.String()
+	builder.WriteString("\n")
 }
 
 // formatCheckbox converts task status to markdown checkbox.
 func (w *MarkdownWriter) formatCheckbox(status string) string {
-	switch status {
+	switch toGitStatus(status) {
 	case "DONE":
 		return "[x]"
 	case "PROCESSING":
@@ -109,5 +142,22 @@ func (w *MarkdownWriter) formatTags(task backend.Task) string {
 		}
 	}
 
+	if task.ParentUID != "" {
+		tags = append(tags, fmt.Sprintf("@parent:%s", task.ParentUID))
+	}
+
+	if len(task.Categories) > 0 {
+		escaped := make([]string, len(task.Categories))
+		for i, category := range task.Categories {
+			escaped[i] = url.QueryEscape(category)
+		}
+		tags = append(tags, fmt.Sprintf("@tags:%s", strings.Join(escaped, ",")))
+	}
+
+	// Full timestamp, so sync can tell which side changed a task last
+	if !task.Modified.IsZero() {
+		tags = append(tags, fmt.Sprintf("@modified:%s", task.Modified.UTC().Format(time.RFC3339)))
+	}
+
 	return strings.Join(tags, " ")
 }
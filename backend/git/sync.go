@@ -0,0 +1,183 @@
+package git
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"gosynctasks/backend"
+)
+
+// blameLine is what git blame reports about one line of the task file
+type blameLine struct {
+	committed time.Time // Commit time; zero when the line is not committed
+	ours      bool      // Last changed by a gosynctasks auto-commit
+}
+
+// blameCache holds git blame for the task file at the modification time it
+// was computed at
+type blameCache struct {
+	modTime time.Time
+	lines   map[int]blameLine
+}
+
+// fillTimestamps sets the Modified time of tasks from git when their
+// @modified tag is missing or outdated. Tasks written by hand without a tag
+// get the time of the commit that last changed their line, or the file
+// modification time when it is not committed. With auto-commit every change
+// gosynctasks makes is committed, so a tagged line changed by another commit,
+// or not committed at all, was edited by hand after its tag was written.
+// Created defaults to Modified, so both stay the same between reads.
+func (gb *GitBackend) fillTimestamps(taskLines map[string][]int) {
+	var blame map[int]blameLine
+	for listName, tasks := range gb.taskLists {
+		lines := taskLines[listName]
+		for i := range tasks {
+			task := &tasks[i]
+			if task.Modified.IsZero() || gb.config.AutoCommit {
+				if blame == nil {
+					blame = gb.blameLines()
+				}
+				line, known := blameLine{}, false
+				if i < len(lines) {
+					line, known = blame[lines[i]]
+				}
+
+				switch {
+				case task.Modified.IsZero():
+					task.Modified = line.committed
+					if task.Modified.IsZero() {
+						task.Modified = gb.fileModTime
+					}
+				case known && !line.ours:
+					edited := line.committed
+					if edited.IsZero() {
+						edited = gb.fileModTime
+					}
+					if edited.After(task.Modified) {
+						task.Modified = edited
+					}
+				}
+			}
+			if task.Created.IsZero() {
+				task.Created = task.Modified
+			}
+		}
+	}
+}
+
+// blameLines returns git blame for each line of the task file, by 1-based
+// line number. It is empty when git is not available or the file is not
+// tracked.
+func (gb *GitBackend) blameLines() map[int]blameLine {
+	if gb.blame != nil && gb.blame.modTime.Equal(gb.fileModTime) {
+		return gb.blame.lines
+	}
+
+	lines := make(map[int]blameLine)
+	relPath, err := filepath.Rel(gb.RepoPath, gb.FilePath)
+	if err == nil {
+		cmd := exec.Command("git", "blame", "--porcelain", "--", relPath)
+		cmd.Dir = gb.RepoPath
+		if output, err := cmd.Output(); err == nil {
+			lines = parseBlame(output)
+		}
+	}
+
+	gb.blame = &blameCache{modTime: gb.fileModTime, lines: lines}
+	return lines
+}
+
+// uncommittedSHA is the commit git blame reports for lines not committed yet
+const uncommittedSHA = "0000000000000000000000000000000000000000"
+
+// parseBlame extracts each line's commit time, and whether gosynctasks made
+// that commit, from 'git blame --porcelain' output
+func parseBlame(output []byte) map[int]blameLine {
+	lines := make(map[int]blameLine)
+	commits := make(map[string]blameLine)
+	var sha string
+	var line int
+
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		text := scanner.Text()
+		if strings.HasPrefix(text, "\t") {
+			// Content of the line the last header described
+			if sha == uncommittedSHA {
+				lines[line] = blameLine{}
+			} else {
+				lines[line] = commits[sha]
+			}
+			continue
+		}
+
+		fields := strings.Fields(text)
+		if len(fields) >= 3 && len(fields[0]) == len(uncommittedSHA) {
+			if n, err := strconv.Atoi(fields[2]); err == nil {
+				sha, line = fields[0], n
+				continue
+			}
+		}
+		commit := commits[sha]
+		switch {
+		case len(fields) == 2 && fields[0] == "committer-time":
+			if unix, err := strconv.ParseInt(fields[1], 10, 64); err == nil {
+				commit.committed = time.Unix(unix, 0)
+			}
+		case strings.HasPrefix(text, "summary "):
+			commit.ours = strings.HasPrefix(strings.TrimPrefix(text, "summary "), autoCommitPrefix)
+		}
+		commits[sha] = commit
+	}
+	return lines
+}
+
+// assignMissingUIDs gives a UID to tasks written without one, returning
+// whether any was assigned
+func (gb *GitBackend) assignMissingUIDs() bool {
+	assigned := false
+	for _, tasks := range gb.taskLists {
+		for i := range tasks {
+			if tasks[i].UID == "" {
+				tasks[i].UID = gb.generateUID()
+				assigned = true
+			}
+		}
+	}
+	return assigned
+}
+
+// listCTag returns a tag that changes whenever a task of the list changes,
+// so sync skips lists that were not edited since the last pull
+func listCTag(tasks []backend.Task) string {
+	var builder strings.Builder
+	NewMarkdownWriter().writeList(&builder, "", tasks)
+	for _, task := range tasks {
+		builder.WriteString(strconv.FormatInt(task.Modified.Unix(), 10))
+	}
+	sum := sha256.Sum256([]byte(builder.String()))
+	return hex.EncodeToString(sum[:8])
+}
+
+// toGitStatus converts CalDAV status names, as a synced cache stores them,
+// to the names used in the task file
+func toGitStatus(status string) string {
+	switch strings.ToUpper(status) {
+	case "NEEDS-ACTION":
+		return "TODO"
+	case "COMPLETED":
+		return "DONE"
+	case "IN-PROCESS":
+		return "PROCESSING"
+	default:
+		return status
+	}
+}
@@ -0,0 +1,259 @@
+package github
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"gosynctasks/backend"
+)
+
+// DefaultAPIURL is the REST API of github.com; GitHub Enterprise servers
+// serve it at https://<host>/api/v3
+const DefaultAPIURL = "https://api.github.com"
+
+// perPage is the page size asked for in listings, the most the API allows
+const perPage = 100
+
+// Repository is a GitHub repository
+type Repository struct {
+	FullName    string `json:"full_name"` // "owner/repo"
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	HTMLURL     string `json:"html_url"`
+	Archived    bool   `json:"archived"`
+	HasIssues   bool   `json:"has_issues"`
+}
+
+// Issue is an issue of a repository. Pull requests, which the issues
+// listing includes, have PullRequest set.
+type Issue struct {
+	Number      int        `json:"number"`
+	Title       string     `json:"title"`
+	Body        string     `json:"body"`
+	State       string     `json:"state"`        // "open" or "closed"
+	StateReason string     `json:"state_reason"` // "completed", "not_planned" or "reopened"
+	Labels      []Label    `json:"labels"`
+	Milestone   *Milestone `json:"milestone"`
+	HTMLURL     string     `json:"html_url"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	ClosedAt    *time.Time `json:"closed_at"`
+	PullRequest *struct{}  `json:"pull_request"`
+}
+
+// Label is a repository label, attached to issues
+type Label struct {
+	Name string `json:"name"`
+}
+
+// Milestone is a repository milestone; its due date is the due date of its
+// issues
+type Milestone struct {
+	Number int        `json:"number"`
+	Title  string     `json:"title"`
+	State  string     `json:"state"`
+	DueOn  *time.Time `json:"due_on"`
+}
+
+// issueRequest creates or edits an issue. Milestone is a number, or null to
+// remove it, and is left out when unchanged.
+type issueRequest struct {
+	Title       string   `json:"title"`
+	Body        string   `json:"body"`
+	State       string   `json:"state,omitempty"`
+	StateReason string   `json:"state_reason,omitempty"`
+	Labels      []string `json:"labels"`
+	Milestone   any      `json:"milestone,omitempty"`
+}
+
+// nextLinkPattern finds the URL of the next page in a Link header
+var nextLinkPattern = regexp.MustCompile(`<([^>]+)>;\s*rel="next"`)
+
+// APIClient talks to the GitHub REST API with a personal access token
+type APIClient struct {
+	baseURL    string
+	token      string
+	httpClient *http.Client
+}
+
+// NewAPIClient returns a client for the REST API at baseURL
+func NewAPIClient(baseURL, token string) *APIClient {
+	return &APIClient{
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		token:      token,
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// getAll decodes every page of the listing at endpoint into items, following
+// the Link headers of the responses. A next page outside the API is refused,
+// since the token would be sent there.
+func getAll[T any](c *APIClient, operation, endpoint string) ([]T, error) {
+	var items []T
+	next := c.baseURL + endpoint
+	for next != "" {
+		resp, err := c.do(http.MethodGet, next, nil)
+		if err != nil {
+			return nil, err
+		}
+		var page []T
+		err = decodeResponse(operation, resp, http.StatusOK, &page)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, page...)
+
+		next = ""
+		if match := nextLinkPattern.FindStringSubmatch(resp.Header.Get("Link")); match != nil {
+			if !c.sameOrigin(match[1]) {
+				return nil, fmt.Errorf("refusing to follow the next page of %s to %s, outside %s", operation, match[1], c.baseURL)
+			}
+			next = match[1]
+		}
+	}
+	return items, nil
+}
+
+// sameOrigin reports whether link has the scheme and host of the API
+func (c *APIClient) sameOrigin(link string) bool {
+	base, err := url.Parse(c.baseURL)
+	if err != nil {
+		return false
+	}
+	target, err := url.Parse(link)
+	return err == nil && target.Scheme == base.Scheme && strings.EqualFold(target.Host, base.Host)
+}
+
+// send sends v as the JSON body of a request to endpoint, and decodes the
+// response into out
+func (c *APIClient) send(operation, method, endpoint string, v any, wantStatus int, out any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("failed to marshal request body: %w", err)
+	}
+	resp, err := c.do(method, c.baseURL+endpoint, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	return decodeResponse(operation, resp, wantStatus, out)
+}
+
+// do sends an authenticated request
+func (c *APIClient) do(method, url string, body io.Reader) (*http.Response, error) {
+	req, err := http.NewRequest(method, url, body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+	return resp, nil
+}
+
+// decodeResponse closes resp after decoding its body into out, or returns
+// the BackendError of a status other than wantStatus
+func decodeResponse(operation string, resp *http.Response, wantStatus int, out any) error {
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != wantStatus {
+		body, _ := io.ReadAll(resp.Body)
+		return backend.NewBackendError(operation, resp.StatusCode, http.StatusText(resp.StatusCode)).WithBody(string(body))
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
+
+// repoPath returns the API path of a repository, "owner/repo"
+func repoPath(repo string) string {
+	owner, name, _ := strings.Cut(repo, "/")
+	return "/repos/" + url.PathEscape(owner) + "/" + url.PathEscape(name)
+}
+
+// GetUserRepositories returns the repositories the user owns or works on
+func (c *APIClient) GetUserRepositories() ([]Repository, error) {
+	return getAll[Repository](c, "GetUserRepositories", fmt.Sprintf("/user/repos?per_page=%d", perPage))
+}
+
+// GetRepository returns one repository, "owner/repo"
+func (c *APIClient) GetRepository(repo string) (*Repository, error) {
+	resp, err := c.do(http.MethodGet, c.baseURL+repoPath(repo), nil)
+	if err != nil {
+		return nil, err
+	}
+	var repository Repository
+	if err := decodeResponse("GetRepository", resp, http.StatusOK, &repository); err != nil {
+		return nil, err
+	}
+	return &repository, nil
+}
+
+// GetIssues returns the open and closed issues of a repository, with label
+// only those having it. Pull requests are left out.
+func (c *APIClient) GetIssues(repo, label string) ([]Issue, error) {
+	query := url.Values{"state": {"all"}, "per_page": {fmt.Sprint(perPage)}}
+	if label != "" {
+		query.Set("labels", label)
+	}
+	all, err := getAll[Issue](c, "GetIssues", repoPath(repo)+"/issues?"+query.Encode())
+	if err != nil {
+		return nil, err
+	}
+	issues := make([]Issue, 0, len(all))
+	for _, issue := range all {
+		if issue.PullRequest == nil {
+			issues = append(issues, issue)
+		}
+	}
+	return issues, nil
+}
+
+// GetIssue returns one issue of a repository, which may be a pull request
+func (c *APIClient) GetIssue(repo string, number int) (*Issue, error) {
+	resp, err := c.do(http.MethodGet, fmt.Sprintf("%s%s/issues/%d", c.baseURL, repoPath(repo), number), nil)
+	if err != nil {
+		return nil, err
+	}
+	var issue Issue
+	if err := decodeResponse("GetIssue", resp, http.StatusOK, &issue); err != nil {
+		return nil, err
+	}
+	return &issue, nil
+}
+
+// GetMilestones returns the open and closed milestones of a repository
+func (c *APIClient) GetMilestones(repo string) ([]Milestone, error) {
+	return getAll[Milestone](c, "GetMilestones", repoPath(repo)+fmt.Sprintf("/milestones?state=all&per_page=%d", perPage))
+}
+
+// CreateIssue creates an issue and returns it
+func (c *APIClient) CreateIssue(repo string, req issueRequest) (*Issue, error) {
+	var issue Issue
+	if err := c.send("CreateIssue", http.MethodPost, repoPath(repo)+"/issues", req, http.StatusCreated, &issue); err != nil {
+		return nil, err
+	}
+	return &issue, nil
+}
+
+// UpdateIssue edits an issue
+func (c *APIClient) UpdateIssue(repo string, number int, req issueRequest) error {
+	return c.send("UpdateIssue", http.MethodPatch, fmt.Sprintf("%s/issues/%d", repoPath(repo), number), req, http.StatusOK, nil)
+}
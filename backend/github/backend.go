@@ -0,0 +1,452 @@
+// Package github is a backend showing GitHub issues as tasks: each
+// repository is a task list, or the issues of a repository having a label
+// with an "owner/repo:label" entry in github_repos, and each issue a task.
+// Closing an issue completes its task. The due date of a task is the due
+// date of the issue's milestone; priorities, start dates, subtasks and
+// recurrence are not kept.
+package github
+
+import (
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"gosynctasks/backend"
+	"gosynctasks/backend/statuskit"
+	"gosynctasks/internal/credentials"
+)
+
+func init() {
+	// Register GitHub backend for config type "github"
+	backend.RegisterType("github", newGitHubBackendWrapper)
+}
+
+// newGitHubBackendWrapper wraps NewGitHubBackend to match BackendConfigConstructor signature
+func newGitHubBackendWrapper(config backend.BackendConfig) (backend.TaskManager, error) {
+	return NewGitHubBackend(config)
+}
+
+// GitHubBackend implements backend.TaskManager for GitHub issues
+type GitHubBackend struct {
+	config backend.BackendConfig
+	apiURL string
+	client *APIClient
+}
+
+// NewGitHubBackend creates a GitHub backend. The personal access token is
+// resolved like the Todoist backend's API token, from the keyring, the
+// environment or api_token; url points it at a GitHub Enterprise server.
+func NewGitHubBackend(config backend.BackendConfig) (*GitHubBackend, error) {
+	for _, repo := range config.GitHubRepos {
+		if _, err := parseListID(repo); err != nil {
+			return nil, fmt.Errorf("github backend %q: %w", config.Name, err)
+		}
+	}
+
+	apiURL, err := apiURLOf(config)
+	if err != nil {
+		return nil, err
+	}
+	token, err := apiToken(config)
+	if err != nil {
+		return nil, err
+	}
+
+	return &GitHubBackend{
+		config: config,
+		apiURL: apiURL,
+		client: NewAPIClient(apiURL, token),
+	}, nil
+}
+
+// apiURLOf returns the API URL of config, DefaultAPIURL unless url is set.
+// HTTPS is used unless allow_http is set.
+func apiURLOf(config backend.BackendConfig) (string, error) {
+	if config.URL == "" {
+		return DefaultAPIURL, nil
+	}
+	u, err := url.Parse(config.URL)
+	if err != nil || u.Host == "" {
+		return "", fmt.Errorf("invalid URL for github backend: %q", config.URL)
+	}
+	scheme := "https"
+	if config.AllowHTTP && u.Scheme == "http" {
+		scheme = "http"
+	}
+	return scheme + "://" + u.Host + strings.TrimSuffix(u.Path, "/"), nil
+}
+
+// apiToken returns the personal access token of config, from the keyring or
+// the environment under the name of the backend, else from api_token
+func apiToken(config backend.BackendConfig) (string, error) {
+	if config.Name != "" {
+		username := config.Username
+		if username == "" {
+			username = "token" // Default username hint for API tokens
+		}
+		creds, err := credentials.NewResolver().Resolve(config.Name, username, "", nil)
+		if err == nil && creds.Password != "" {
+			return creds.Password, nil
+		}
+	}
+	if config.APIToken != "" {
+		return config.APIToken, nil
+	}
+	return "", fmt.Errorf("github personal access token not found (tried: keyring, environment variables, config)\n"+
+		"Set it with: gosynctasks credentials set %s token --prompt\n"+
+		"Or add 'api_token' to your config file", config.Name)
+}
+
+// unsupported is the error of the changes GitHub issues cannot take
+func unsupported(operation, message string) error {
+	return backend.NewUnsupportedError(operation, message)
+}
+
+// GetTaskLists returns the repositories and labels of github_repos, or when
+// it is empty the repositories of the user that have issues. Archived
+// repositories are left out of the latter.
+func (gb *GitHubBackend) GetTaskLists() ([]backend.TaskList, error) {
+	if len(gb.config.GitHubRepos) == 0 {
+		repos, err := gb.client.GetUserRepositories()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get repositories: %w", err)
+		}
+		var lists []backend.TaskList
+		for _, repo := range repos {
+			if repo.HasIssues && !repo.Archived {
+				lists = append(lists, repoToTaskList(repo, ""))
+			}
+		}
+		return lists, nil
+	}
+
+	lists := make([]backend.TaskList, 0, len(gb.config.GitHubRepos))
+	for _, entry := range gb.config.GitHubRepos {
+		ref, _ := parseListID(entry)
+		repo, err := gb.client.GetRepository(ref.repo)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get repository %s: %w", ref.repo, err)
+		}
+		lists = append(lists, repoToTaskList(*repo, ref.label))
+	}
+	return lists, nil
+}
+
+// tasks returns the issues of a list as tasks
+func (gb *GitHubBackend) tasks(listID string) ([]backend.Task, error) {
+	ref, err := parseListID(listID)
+	if err != nil {
+		return nil, err
+	}
+	issues, err := gb.client.GetIssues(ref.repo, ref.label)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get issues: %w", err)
+	}
+	tasks := make([]backend.Task, len(issues))
+	for i, issue := range issues {
+		tasks[i] = issueToTask(issue)
+	}
+	return tasks, nil
+}
+
+// GetTasks returns the open and closed issues of a list
+func (gb *GitHubBackend) GetTasks(listID string, filter *backend.TaskFilter) ([]backend.Task, error) {
+	all, err := gb.tasks(listID)
+	if err != nil {
+		return nil, err
+	}
+	var tasks []backend.Task
+	for _, task := range all {
+		if matchesFilter(task, filter) {
+			tasks = append(tasks, task)
+		}
+	}
+	gb.SortTasks(tasks)
+	return tasks, nil
+}
+
+// matchesFilter checks if a task matches the given filter
+func matchesFilter(task backend.Task, filter *backend.TaskFilter) bool {
+	if filter == nil {
+		return true
+	}
+	if !filter.MatchesStatus(task.Status) || !filter.MatchesParent(task.ParentUID) ||
+		!filter.MatchesModified(task.Modified) || !filter.MatchesCompleted(task.Completed) ||
+		!filter.MatchesPriority(task.Priority) || !filter.MatchesOverdue(task.Status, task.DueDate, task.AllDay) ||
+		!filter.MatchesSummary(task.Summary) {
+		return false
+	}
+	if task.DueDate != nil {
+		if filter.DueAfter != nil && task.DueDate.Before(*filter.DueAfter) {
+			return false
+		}
+		if filter.DueBefore != nil && task.DueDate.After(*filter.DueBefore) {
+			return false
+		}
+	}
+	if filter.CreatedAfter != nil && task.Created.Before(*filter.CreatedAfter) {
+		return false
+	}
+	if filter.CreatedBefore != nil && task.Created.After(*filter.CreatedBefore) {
+		return false
+	}
+	return true
+}
+
+// FindTasksBySummary searches the issues of a list by title
+func (gb *GitHubBackend) FindTasksBySummary(listID string, summary string) ([]backend.Task, error) {
+	all, err := gb.tasks(listID)
+	if err != nil {
+		return nil, err
+	}
+	var matches []backend.Task
+	for _, task := range all {
+		if strings.Contains(strings.ToLower(task.Summary), strings.ToLower(summary)) {
+			matches = append(matches, task)
+		}
+	}
+	backend.ExactSummaryMatchesFirst(matches, summary)
+	return matches, nil
+}
+
+// GetTask returns one issue of a list
+func (gb *GitHubBackend) GetTask(listID string, taskUID string) (*backend.Task, error) {
+	ref, err := parseListID(listID)
+	if err != nil {
+		return nil, err
+	}
+	number, err := strconv.Atoi(taskUID)
+	if err != nil {
+		return nil, backend.NewNotFoundError("GetTask", listID, taskUID)
+	}
+	issue, err := gb.client.GetIssue(ref.repo, number)
+	if isNotFound(err) || (err == nil && issue.PullRequest != nil) {
+		return nil, backend.NewNotFoundError("GetTask", listID, taskUID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get issue: %w", err)
+	}
+	task := issueToTask(*issue)
+	return &task, nil
+}
+
+// isNotFound reports whether err is a 404 of the API
+func isNotFound(err error) bool {
+	var backendErr *backend.BackendError
+	return errors.As(err, &backendErr) && backendErr.IsNotFound()
+}
+
+// AddTask opens an issue, and closes it right away when the task is done
+func (gb *GitHubBackend) AddTask(listID string, task backend.Task) (string, error) {
+	ref, err := parseListID(listID)
+	if err != nil {
+		return "", err
+	}
+	state, reason, err := issueState(task.Status)
+	if err != nil {
+		return "", err
+	}
+	req := issueRequest{Title: task.Summary, Body: task.Description, Labels: labelsOf(task, ref)}
+	if task.DueDate != nil {
+		if req.Milestone, err = gb.milestoneFor("AddTask", ref.repo, task.DueDate); err != nil {
+			return "", err
+		}
+	}
+
+	issue, err := gb.client.CreateIssue(ref.repo, req)
+	if err != nil {
+		return "", fmt.Errorf("failed to create issue: %w", err)
+	}
+	if state == "closed" {
+		req.State, req.StateReason = state, reason
+		if err := gb.client.UpdateIssue(ref.repo, issue.Number, req); err != nil {
+			return "", fmt.Errorf("failed to close issue #%d: %w", issue.Number, err)
+		}
+	}
+	return strconv.Itoa(issue.Number), nil
+}
+
+// UpdateTask edits the title, body, labels and milestone of an issue, and
+// closes or reopens it with the status of the task
+func (gb *GitHubBackend) UpdateTask(listID string, task backend.Task) error {
+	ref, err := parseListID(listID)
+	if err != nil {
+		return err
+	}
+	state, reason, err := issueState(task.Status)
+	if err != nil {
+		return err
+	}
+	current, err := gb.GetTask(listID, task.UID)
+	if err != nil {
+		return err
+	}
+
+	req := issueRequest{Title: task.Summary, Body: task.Description, State: state, StateReason: reason, Labels: labelsOf(task, ref)}
+	if !sameDueDay(current.DueDate, task.DueDate) {
+		if req.Milestone, err = gb.milestoneFor("UpdateTask", ref.repo, task.DueDate); err != nil {
+			return err
+		}
+	}
+	number, _ := strconv.Atoi(task.UID)
+	if err := gb.client.UpdateIssue(ref.repo, number, req); err != nil {
+		return fmt.Errorf("failed to update issue #%d: %w", number, err)
+	}
+	return nil
+}
+
+// sameDueDay reports whether both due dates are on the same day, or both
+// unset
+func sameDueDay(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return sameDay(*a, *b)
+}
+
+// milestoneFor returns the milestone value of an issue due on due: the
+// number of a milestone of the repository due that day, open ones first, or
+// null to remove the milestone when due is nil. GitHub has no due dates
+// other than milestones', so a day without one is an error.
+func (gb *GitHubBackend) milestoneFor(operation, repo string, due *time.Time) (any, error) {
+	if due == nil {
+		return json.RawMessage("null"), nil
+	}
+	milestones, err := gb.client.GetMilestones(repo)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get milestones: %w", err)
+	}
+	slices.SortStableFunc(milestones, func(a, b Milestone) int {
+		return cmp.Compare(b.State, a.State) // "open" before "closed"
+	})
+	for _, milestone := range milestones {
+		if date := milestoneDate(milestone); date != nil && sameDay(*date, *due) {
+			return milestone.Number, nil
+		}
+	}
+	return nil, unsupported(operation, fmt.Sprintf("no milestone of %s is due on %s; GitHub issues are due when their milestone is, so create one due that day",
+		repo, due.Format(time.DateOnly)))
+}
+
+// DeleteTask is not supported: the REST API cannot delete issues
+func (gb *GitHubBackend) DeleteTask(listID string, taskUID string) error {
+	return unsupported("DeleteTask", "GitHub issues cannot be deleted through the API; close them instead")
+}
+
+// CreateTaskList is not supported
+func (gb *GitHubBackend) CreateTaskList(name, description, color string) (string, error) {
+	return "", unsupported("CreateTaskList", "lists are GitHub repositories; add them to github_repos")
+}
+
+// DeleteTaskList is not supported
+func (gb *GitHubBackend) DeleteTaskList(listID string) error {
+	return unsupported("DeleteTaskList", "lists are GitHub repositories and cannot be deleted from here")
+}
+
+// RenameTaskList is not supported
+func (gb *GitHubBackend) RenameTaskList(listID, newName string) error {
+	return unsupported("RenameTaskList", "lists are GitHub repositories and cannot be renamed from here")
+}
+
+// GetDeletedTaskLists returns no lists: repositories have no trash
+func (gb *GitHubBackend) GetDeletedTaskLists() ([]backend.TaskList, error) {
+	return []backend.TaskList{}, nil
+}
+
+// RestoreTaskList is not supported
+func (gb *GitHubBackend) RestoreTaskList(listID string) error {
+	return unsupported("RestoreTaskList", "the github backend has no deleted lists")
+}
+
+// PermanentlyDeleteTaskList is not supported
+func (gb *GitHubBackend) PermanentlyDeleteTaskList(listID string) error {
+	return unsupported("PermanentlyDeleteTaskList", "the github backend has no deleted lists")
+}
+
+// ParseStatusFlag converts user input to a CalDAV status
+func (gb *GitHubBackend) ParseStatusFlag(statusFlag string) (string, error) {
+	return statuskit.CalDAV.ParseStatusFlag(statusFlag)
+}
+
+// StatusToDisplayName converts a CalDAV status to its display name
+func (gb *GitHubBackend) StatusToDisplayName(backendStatus string) string {
+	return statuskit.CalDAV.StatusToDisplayName(backendStatus)
+}
+
+// SortTasks sorts issues by due date, issues without one last, then newest
+// first like GitHub lists them
+func (gb *GitHubBackend) SortTasks(tasks []backend.Task) {
+	slices.SortStableFunc(tasks, func(a, b backend.Task) int {
+		switch {
+		case a.DueDate == nil && b.DueDate != nil:
+			return 1
+		case a.DueDate != nil && b.DueDate == nil:
+			return -1
+		case a.DueDate != nil && !a.DueDate.Equal(*b.DueDate):
+			return a.DueDate.Compare(*b.DueDate)
+		}
+		return b.Created.Compare(a.Created)
+	})
+}
+
+// GetPriorityColor returns the color of a priority; issues have none
+func (gb *GitHubBackend) GetPriorityColor(priority int) string {
+	return statuskit.DefaultPalette.Color(priority)
+}
+
+// DescribeRequests returns the REST API requests a write of task makes
+func (gb *GitHubBackend) DescribeRequests(method, listID string, task backend.Task) []string {
+	ref, err := parseListID(listID)
+	if err != nil {
+		return nil
+	}
+	issuesURL := gb.apiURL + repoPath(ref.repo) + "/issues"
+	switch method {
+	case "AddTask":
+		return []string{http.MethodPost + " " + issuesURL}
+	case "UpdateTask":
+		issueURL := issuesURL + "/" + task.UID
+		return []string{http.MethodGet + " " + issueURL, http.MethodPatch + " " + issueURL}
+	}
+	return nil
+}
+
+// ReadsConcurrently reports that lists may be read concurrently, each read
+// being its own API request
+func (gb *GitHubBackend) ReadsConcurrently() bool {
+	return true
+}
+
+// GetBackendDisplayName returns formatted display name
+func (gb *GitHubBackend) GetBackendDisplayName() string {
+	return fmt.Sprintf("[github:%s]", gb.GetBackendContext())
+}
+
+// GetBackendType returns the backend type identifier
+func (gb *GitHubBackend) GetBackendType() string {
+	return "github"
+}
+
+// MaxSummaryLength returns the most characters GitHub accepts in an issue title
+func (gb *GitHubBackend) MaxSummaryLength() int {
+	return 256
+}
+
+// GetBackendContext returns the GitHub server
+func (gb *GitHubBackend) GetBackendContext() string {
+	if gb.apiURL == DefaultAPIURL {
+		return "github.com"
+	}
+	u, err := url.Parse(gb.apiURL)
+	if err != nil {
+		return gb.apiURL
+	}
+	return u.Host
+}
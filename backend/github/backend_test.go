@@ -0,0 +1,356 @@
+package github
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"gosynctasks/backend"
+)
+
+// fakeGitHub serves the REST API for one user with the repository
+// octo/app, whose 250 issues take three pages
+type fakeGitHub struct {
+	server   *httptest.Server
+	mu       sync.Mutex
+	issues   map[int]map[string]any
+	pages    int      // Issue listing pages served
+	requests []string // "METHOD path body" of each write
+}
+
+func newFakeGitHub(t *testing.T) *fakeGitHub {
+	t.Helper()
+	f := &fakeGitHub{issues: map[int]map[string]any{}}
+	for n := 1; n <= 250; n++ {
+		issue := map[string]any{
+			"number":     n,
+			"title":      fmt.Sprintf("Issue %d", n),
+			"state":      "open",
+			"labels":     []map[string]string{},
+			"created_at": time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC).Add(time.Duration(n) * time.Hour),
+			"updated_at": time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC),
+		}
+		if n%5 == 0 {
+			issue["pull_request"] = map[string]string{"url": "https://api.github.com/repos/octo/app/pulls/" + strconv.Itoa(n)}
+		}
+		if n%7 == 0 {
+			issue["labels"] = []map[string]string{{"name": "bug"}}
+		}
+		f.issues[n] = issue
+	}
+	f.issues[42]["milestone"] = map[string]any{"number": 3, "title": "v1", "state": "open", "due_on": "2026-03-06T07:00:00Z"}
+
+	f.server = httptest.NewServer(f)
+	t.Cleanup(f.server.Close)
+	return f
+}
+
+func (f *fakeGitHub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if r.Header.Get("Authorization") != "Bearer ghp_test" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	body, _ := io.ReadAll(r.Body)
+	if r.Method != http.MethodGet {
+		f.requests = append(f.requests, r.Method+" "+r.URL.Path+" "+string(body))
+	}
+
+	switch {
+	case r.URL.Path == "/user/repos" && r.URL.Query().Get("page") == "":
+		w.Header().Set("Link", `<`+f.server.URL+`/user/repos?per_page=100&page=2>; rel="next", <`+f.server.URL+`/user/repos?per_page=100&page=2>; rel="last"`)
+		writeJSON(w, http.StatusOK, []map[string]any{
+			{"full_name": "octo/app", "name": "app", "html_url": "https://github.com/octo/app", "has_issues": true},
+			{"full_name": "octo/old", "name": "old", "has_issues": true, "archived": true},
+		})
+	case r.URL.Path == "/user/repos":
+		writeJSON(w, http.StatusOK, []map[string]any{{"full_name": "octo/wiki", "name": "wiki"}})
+	case r.URL.Path == "/repos/octo/app":
+		writeJSON(w, http.StatusOK, map[string]any{"full_name": "octo/app", "name": "app", "description": "The app", "html_url": "https://github.com/octo/app", "has_issues": true})
+	case r.URL.Path == "/repos/octo/app/milestones":
+		writeJSON(w, http.StatusOK, []map[string]any{
+			{"number": 2, "state": "closed", "due_on": "2026-03-06T07:00:00Z"},
+			{"number": 3, "state": "open", "due_on": "2026-03-06T07:00:00Z"},
+			{"number": 4, "state": "open"},
+		})
+	case r.URL.Path == "/repos/octo/app/issues" && r.Method == http.MethodGet:
+		f.pages++
+		f.serveIssues(w, r)
+	case r.URL.Path == "/repos/octo/app/issues" && r.Method == http.MethodPost:
+		writeJSON(w, http.StatusCreated, map[string]any{"number": 251, "state": "open"})
+	case strings.HasPrefix(r.URL.Path, "/repos/octo/app/issues/"):
+		n, _ := strconv.Atoi(strings.TrimPrefix(r.URL.Path, "/repos/octo/app/issues/"))
+		issue, ok := f.issues[n]
+		if !ok && n != 251 {
+			writeJSON(w, http.StatusNotFound, map[string]string{"message": "Not Found"})
+			return
+		}
+		writeJSON(w, http.StatusOK, issue)
+	default:
+		writeJSON(w, http.StatusNotFound, map[string]string{"message": "Not Found"})
+	}
+}
+
+// serveIssues serves a page of the issues, with the label of the query
+func (f *fakeGitHub) serveIssues(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	if query.Get("state") != "all" {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"message": "want state=all"})
+		return
+	}
+	var matching []map[string]any
+	for n := 250; n >= 1; n-- {
+		issue := f.issues[n]
+		if label := query.Get("labels"); label != "" && !slices.ContainsFunc(issue["labels"].([]map[string]string), func(l map[string]string) bool { return l["name"] == label }) {
+			continue
+		}
+		matching = append(matching, issue)
+	}
+
+	perPage, _ := strconv.Atoi(query.Get("per_page"))
+	page, _ := strconv.Atoi(query.Get("page"))
+	page = max(page, 1)
+	start, end := min((page-1)*perPage, len(matching)), min(page*perPage, len(matching))
+	if end < len(matching) {
+		query.Set("page", strconv.Itoa(page+1))
+		w.Header().Set("Link", `<`+f.server.URL+r.URL.Path+"?"+query.Encode()+`>; rel="next"`)
+	}
+	writeJSON(w, http.StatusOK, matching[start:end])
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// lastRequest returns the last write the server received
+func (f *fakeGitHub) lastRequest() string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if len(f.requests) == 0 {
+		return ""
+	}
+	return f.requests[len(f.requests)-1]
+}
+
+func newTestGitHub(t *testing.T, config backend.BackendConfig) (*GitHubBackend, *fakeGitHub) {
+	t.Helper()
+	fake := newFakeGitHub(t)
+	config.URL = fake.server.URL
+	config.AllowHTTP = true
+	config.APIToken = "ghp_test"
+	gb, err := NewGitHubBackend(config)
+	if err != nil {
+		t.Fatalf("NewGitHubBackend() error = %v", err)
+	}
+	return gb, fake
+}
+
+func TestNewGitHubBackend(t *testing.T) {
+	if _, err := NewGitHubBackend(backend.BackendConfig{}); err == nil {
+		t.Error("NewGitHubBackend() without a token succeeded, want an error")
+	}
+	if _, err := NewGitHubBackend(backend.BackendConfig{APIToken: "t", GitHubRepos: []string{"app"}}); err == nil {
+		t.Error("NewGitHubBackend() with a repository without owner succeeded, want an error")
+	}
+
+	gb, err := NewGitHubBackend(backend.BackendConfig{APIToken: "t"})
+	if err != nil {
+		t.Fatalf("NewGitHubBackend() error = %v", err)
+	}
+	if gb.apiURL != DefaultAPIURL || gb.GetBackendDisplayName() != "[github:github.com]" {
+		t.Errorf("default backend: API %s, display name %s", gb.apiURL, gb.GetBackendDisplayName())
+	}
+
+	// GitHub Enterprise, over HTTPS unless allow_http is set
+	gb, _ = NewGitHubBackend(backend.BackendConfig{APIToken: "t", URL: "http://git.example.com/api/v3/"})
+	if gb.apiURL != "https://git.example.com/api/v3" || gb.GetBackendContext() != "git.example.com" {
+		t.Errorf("enterprise backend: API %s, context %s", gb.apiURL, gb.GetBackendContext())
+	}
+}
+
+func TestGetTaskLists(t *testing.T) {
+	gb, _ := newTestGitHub(t, backend.BackendConfig{})
+	lists, err := gb.GetTaskLists()
+	if err != nil {
+		t.Fatalf("GetTaskLists() error = %v", err)
+	}
+	if len(lists) != 1 || lists[0].ID != "octo/app" || lists[0].Name != "app" || lists[0].Path != "octo/app" {
+		t.Fatalf("GetTaskLists() = %+v, want only the active repository with issues", lists)
+	}
+
+	gb, _ = newTestGitHub(t, backend.BackendConfig{GitHubRepos: []string{"octo/app", "octo/app:bug"}})
+	lists, err = gb.GetTaskLists()
+	if err != nil {
+		t.Fatalf("GetTaskLists() error = %v", err)
+	}
+	if len(lists) != 2 || lists[1].ID != "octo/app:bug" || lists[1].Name != "bug" || lists[1].Path != "octo/app/bug" {
+		t.Fatalf("GetTaskLists() = %+v, want the repository and its bug label", lists)
+	}
+	if lists[0].Description != "The app" || lists[1].URL != "https://github.com/octo/app/labels/bug" {
+		t.Errorf("GetTaskLists() = %+v", lists)
+	}
+}
+
+func TestGetTasks(t *testing.T) {
+	gb, fake := newTestGitHub(t, backend.BackendConfig{})
+
+	tasks, err := gb.GetTasks("octo/app", nil)
+	if err != nil {
+		t.Fatalf("GetTasks() error = %v", err)
+	}
+	// 250 issues in three pages, less the 50 pull requests
+	if len(tasks) != 200 || fake.pages != 3 {
+		t.Errorf("GetTasks() = %d tasks from %d pages, want 200 from 3", len(tasks), fake.pages)
+	}
+	if tasks[0].UID != "42" || tasks[0].DueDate == nil || !tasks[0].AllDay {
+		t.Errorf("first task = %+v, want #42, due on its milestone's day", tasks[0])
+	}
+
+	bugs, err := gb.GetTasks("octo/app:bug", nil)
+	if err != nil {
+		t.Fatalf("GetTasks() error = %v", err)
+	}
+	if len(bugs) != 28 || !slices.Contains(bugs[0].Categories, "bug") {
+		t.Errorf("GetTasks(bug) = %d tasks, want the 28 bug issues", len(bugs))
+	}
+
+	if _, err := gb.GetTasks("app", nil); err == nil {
+		t.Error("GetTasks() of an invalid list ID succeeded, want an error")
+	}
+}
+
+func TestGetTask(t *testing.T) {
+	gb, _ := newTestGitHub(t, backend.BackendConfig{})
+	var backendErr *backend.BackendError
+
+	task, err := gb.GetTask("octo/app", "7")
+	if err != nil || task.Summary != "Issue 7" {
+		t.Fatalf("GetTask(7) = %+v, %v", task, err)
+	}
+	for _, uid := range []string{"10", "999", "abc"} {
+		if _, err := gb.GetTask("octo/app", uid); !errors.As(err, &backendErr) || !backendErr.IsNotFound() {
+			t.Errorf("GetTask(%s) error = %v, want not found", uid, err)
+		}
+	}
+}
+
+func TestAddTask(t *testing.T) {
+	gb, fake := newTestGitHub(t, backend.BackendConfig{})
+	var backendErr *backend.BackendError
+
+	due := time.Date(2026, 3, 6, 0, 0, 0, 0, time.Local)
+	uid, err := gb.AddTask("octo/app:bug", backend.Task{Summary: "Crash", Description: "On start", Categories: []string{"urgent"}, DueDate: &due, Status: statusOpen})
+	if err != nil {
+		t.Fatalf("AddTask() error = %v", err)
+	}
+	if uid != "251" {
+		t.Errorf("AddTask() = %q, want the issue number", uid)
+	}
+	want := `POST /repos/octo/app/issues {"title":"Crash","body":"On start","labels":["urgent","bug"],"milestone":3}`
+	if got := strings.TrimSpace(fake.lastRequest()); got != want {
+		t.Errorf("AddTask() sent\n%s\nwant\n%s", got, want)
+	}
+
+	// Done tasks are closed once created
+	if _, err := gb.AddTask("octo/app", backend.Task{Summary: "Old", Status: statusDone}); err != nil {
+		t.Fatalf("AddTask() error = %v", err)
+	}
+	if got := fake.lastRequest(); !strings.HasPrefix(got, "PATCH /repos/octo/app/issues/251 ") || !strings.Contains(got, `"state":"closed","state_reason":"completed"`) {
+		t.Errorf("AddTask() of a done task last sent %s, want the issue closed", got)
+	}
+
+	// Only milestones give due dates
+	other := time.Date(2026, 3, 7, 0, 0, 0, 0, time.Local)
+	if _, err := gb.AddTask("octo/app", backend.Task{Summary: "Later", DueDate: &other}); !errors.As(err, &backendErr) || !backendErr.IsUnsupported() {
+		t.Errorf("AddTask() due on a day without milestone error = %v, want unsupported", err)
+	}
+}
+
+func TestUpdateTask(t *testing.T) {
+	gb, fake := newTestGitHub(t, backend.BackendConfig{})
+	var backendErr *backend.BackendError
+
+	// Completing closes the issue; the milestone is left alone
+	task, err := gb.GetTask("octo/app", "42")
+	if err != nil {
+		t.Fatal(err)
+	}
+	task.Status = statusDone
+	if err := gb.UpdateTask("octo/app", *task); err != nil {
+		t.Fatalf("UpdateTask() error = %v", err)
+	}
+	want := `PATCH /repos/octo/app/issues/42 {"title":"Issue 42","body":"","state":"closed","state_reason":"completed","labels":["bug"]}`
+	if got := strings.TrimSpace(fake.lastRequest()); got != want {
+		t.Errorf("UpdateTask() sent\n%s\nwant\n%s", got, want)
+	}
+
+	// Cancelling closes it as not planned, and removing the due date the
+	// milestone
+	task.Status = statusCancelled
+	task.DueDate = nil
+	if err := gb.UpdateTask("octo/app", *task); err != nil {
+		t.Fatalf("UpdateTask() error = %v", err)
+	}
+	if got := fake.lastRequest(); !strings.Contains(got, `"state_reason":"not_planned"`) || !strings.Contains(got, `"milestone":null`) {
+		t.Errorf("UpdateTask() sent %s, want the issue closed as not planned without milestone", got)
+	}
+
+	task.Status = "IN-PROCESS"
+	if err := gb.UpdateTask("octo/app", *task); !errors.As(err, &backendErr) || !backendErr.IsUnsupported() {
+		t.Errorf("UpdateTask(IN-PROCESS) error = %v, want unsupported", err)
+	}
+	if err := gb.UpdateTask("octo/app", backend.Task{UID: "999", Summary: "Gone"}); !errors.As(err, &backendErr) || !backendErr.IsNotFound() {
+		t.Errorf("UpdateTask() of a missing issue error = %v, want not found", err)
+	}
+}
+
+func TestUnsupportedWrites(t *testing.T) {
+	gb, _ := newTestGitHub(t, backend.BackendConfig{})
+	var backendErr *backend.BackendError
+	if err := gb.DeleteTask("octo/app", "1"); !errors.As(err, &backendErr) || !backendErr.IsUnsupported() {
+		t.Errorf("DeleteTask() error = %v, want unsupported", err)
+	}
+	if _, err := gb.CreateTaskList("New", "", ""); !errors.As(err, &backendErr) || !backendErr.IsUnsupported() {
+		t.Errorf("CreateTaskList() error = %v, want unsupported", err)
+	}
+	if lists, err := gb.GetDeletedTaskLists(); err != nil || len(lists) != 0 {
+		t.Errorf("GetDeletedTaskLists() = %v, %v, want none", lists, err)
+	}
+}
+
+// TestNextPageOutsideAPI tests that a Link to another host is not followed
+// with the token
+func TestNextPageOutsideAPI(t *testing.T) {
+	var leaked bool
+	elsewhere := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		leaked = r.Header.Get("Authorization") != ""
+		writeJSON(w, http.StatusOK, []Repository{})
+	}))
+	defer elsewhere.Close()
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Link", `<`+elsewhere.URL+`/user/repos?page=2>; rel="next"`)
+		writeJSON(w, http.StatusOK, []Repository{{FullName: "octo/app"}})
+	}))
+	defer api.Close()
+
+	_, err := NewAPIClient(api.URL, "secret").GetUserRepositories()
+	if err == nil || !strings.Contains(err.Error(), "refusing to follow") {
+		t.Errorf("GetUserRepositories() error = %v, want the next page refused", err)
+	}
+	if leaked {
+		t.Error("the token was sent to another host")
+	}
+}
@@ -0,0 +1,142 @@
+package github
+
+import (
+	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"gosynctasks/backend"
+)
+
+// Task statuses, as CalDAV names like the Nextcloud backend
+const (
+	statusOpen      = "NEEDS-ACTION"
+	statusDone      = "COMPLETED"
+	statusCancelled = "CANCELLED"
+)
+
+// listRef is what a list ID stands for: the issues of a repository, or
+// those of them having a label
+type listRef struct {
+	repo  string // "owner/repo"
+	label string // Empty for all the issues
+}
+
+// listID returns the list ID of a repository, "owner/repo", or of its
+// issues having label, "owner/repo:label"
+func listID(repo, label string) string {
+	if label == "" {
+		return repo
+	}
+	return repo + ":" + label
+}
+
+// parseListID reads a list ID made by listID; repositories are the same as
+// the config's github_repos entries
+func parseListID(id string) (listRef, error) {
+	repo, label, _ := strings.Cut(id, ":")
+	owner, name, ok := strings.Cut(repo, "/")
+	if !ok || owner == "" || name == "" || strings.Contains(name, "/") {
+		return listRef{}, fmt.Errorf("invalid GitHub list ID %q (want owner/repo or owner/repo:label)", id)
+	}
+	return listRef{repo: repo, label: label}, nil
+}
+
+// repoToTaskList converts a repository to the list of its issues, or of
+// those having label, named after the label and nested in the repository
+func repoToTaskList(repo Repository, label string) backend.TaskList {
+	list := backend.TaskList{
+		ID:          listID(repo.FullName, label),
+		Name:        repo.Name,
+		Path:        repo.FullName,
+		Description: repo.Description,
+		URL:         repo.HTMLURL + "/issues",
+	}
+	if label != "" {
+		list.Name = label
+		list.Path = repo.FullName + "/" + label
+		list.URL = repo.HTMLURL + "/labels/" + url.PathEscape(label)
+	}
+	return list
+}
+
+// issueToTask converts an issue. Issues closed as not planned are
+// cancelled, other closed issues done; the due date of the milestone is
+// the due date of the issue.
+func issueToTask(issue Issue) backend.Task {
+	task := backend.Task{
+		UID:         strconv.Itoa(issue.Number),
+		Summary:     issue.Title,
+		Description: issue.Body,
+		Status:      statusOpen,
+		Created:     issue.CreatedAt,
+		Modified:    issue.UpdatedAt,
+	}
+	for _, label := range issue.Labels {
+		task.Categories = append(task.Categories, label.Name)
+	}
+	if issue.Milestone != nil {
+		task.DueDate = milestoneDate(*issue.Milestone)
+		task.AllDay = task.DueDate != nil
+	}
+	if issue.State == "closed" {
+		task.Status = statusDone
+		if issue.StateReason == "not_planned" {
+			task.Status = statusCancelled
+		}
+		task.Completed = issue.ClosedAt
+	}
+	return task
+}
+
+// milestoneDate returns the day a milestone is due, at local midnight, or
+// nil when it has no due date. GitHub keeps due dates as instants on the
+// day in UTC.
+func milestoneDate(milestone Milestone) *time.Time {
+	if milestone.DueOn == nil {
+		return nil
+	}
+	due := milestone.DueOn.UTC()
+	date := time.Date(due.Year(), due.Month(), due.Day(), 0, 0, 0, 0, time.Local)
+	return &date
+}
+
+// sameDay reports whether a and b fall on the same day of their own time
+// zones
+func sameDay(a, b time.Time) bool {
+	return a.Year() == b.Year() && a.YearDay() == b.YearDay()
+}
+
+// issueState returns the state and state reason an issue is given for a
+// task status. Issues are only open or closed, so a status in between is
+// an error.
+func issueState(status string) (state, reason string, err error) {
+	switch status {
+	case "", statusOpen:
+		return "open", "", nil
+	case statusDone:
+		return "closed", "completed", nil
+	case statusCancelled:
+		return "closed", "not_planned", nil
+	}
+	return "", "", backend.NewUnsupportedError("UpdateTask",
+		fmt.Sprintf("GitHub issues are open or closed; status %s is not supported", status))
+}
+
+// labelsOf returns the labels an issue of list is given for a task: its
+// categories, and the label of the list if the categories lack it
+func labelsOf(task backend.Task, ref listRef) []string {
+	labels := []string{}
+	for _, category := range task.Categories {
+		if category != "" {
+			labels = append(labels, category)
+		}
+	}
+	if ref.label != "" && !slices.ContainsFunc(labels, func(label string) bool { return strings.EqualFold(label, ref.label) }) {
+		labels = append(labels, ref.label)
+	}
+	return labels
+}
@@ -0,0 +1,82 @@
+package github
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestParseListID(t *testing.T) {
+	tests := []struct {
+		id      string
+		want    listRef
+		wantErr bool
+	}{
+		{id: "octo/app", want: listRef{repo: "octo/app"}},
+		{id: "octo/app:good first issue", want: listRef{repo: "octo/app", label: "good first issue"}},
+		{id: "octo/app:area:ui", want: listRef{repo: "octo/app", label: "area:ui"}},
+		{id: "app", wantErr: true},
+		{id: "/app", wantErr: true},
+		{id: "octo/app/extra", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := parseListID(tt.id)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("parseListID(%q) = %+v, %v, want %+v (error %v)", tt.id, got, err, tt.want, tt.wantErr)
+		}
+		if err == nil && listID(got.repo, got.label) != tt.id {
+			t.Errorf("listID() of %q = %q", tt.id, listID(got.repo, got.label))
+		}
+	}
+}
+
+func TestIssueToTask(t *testing.T) {
+	closed := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	due := time.Date(2026, 3, 6, 7, 0, 0, 0, time.UTC)
+	issue := Issue{
+		Number:      12,
+		Title:       "Fix login",
+		Body:        "Steps to reproduce",
+		State:       "closed",
+		StateReason: "not_planned",
+		Labels:      []Label{{Name: "bug"}, {Name: "ui"}},
+		Milestone:   &Milestone{Number: 3, DueOn: &due},
+		ClosedAt:    &closed,
+	}
+
+	task := issueToTask(issue)
+	if task.UID != "12" || task.Summary != "Fix login" || task.Description != "Steps to reproduce" {
+		t.Errorf("issueToTask() = %+v", task)
+	}
+	if task.Status != statusCancelled || task.Completed == nil || !task.Completed.Equal(closed) {
+		t.Errorf("issueToTask() status %s, completed %v, want cancelled at the close", task.Status, task.Completed)
+	}
+	if !slices.Equal(task.Categories, []string{"bug", "ui"}) {
+		t.Errorf("issueToTask() categories = %v", task.Categories)
+	}
+	if want := time.Date(2026, 3, 6, 0, 0, 0, 0, time.Local); task.DueDate == nil || !task.DueDate.Equal(want) || !task.AllDay {
+		t.Errorf("issueToTask() due %v (all day %v), want %s all day", task.DueDate, task.AllDay, want)
+	}
+
+	issue.StateReason = "completed"
+	if task := issueToTask(issue); task.Status != statusDone {
+		t.Errorf("issueToTask() of a completed issue status = %s", task.Status)
+	}
+	issue.State, issue.Milestone = "open", &Milestone{Number: 4}
+	if task := issueToTask(issue); task.Status != statusOpen || task.DueDate != nil || task.AllDay {
+		t.Errorf("issueToTask() of an open issue without due date = %+v", task)
+	}
+}
+
+func TestLabelsOf(t *testing.T) {
+	task := issueToTask(Issue{Labels: []Label{{Name: "Bug"}}})
+	if got := labelsOf(task, listRef{repo: "octo/app", label: "bug"}); !slices.Equal(got, []string{"Bug"}) {
+		t.Errorf("labelsOf() = %v, want the list label once", got)
+	}
+	if got := labelsOf(task, listRef{repo: "octo/app", label: "ui"}); !slices.Equal(got, []string{"Bug", "ui"}) {
+		t.Errorf("labelsOf() = %v, want the list label added", got)
+	}
+	if got := labelsOf(issueToTask(Issue{}), listRef{repo: "octo/app"}); got == nil || len(got) != 0 {
+		t.Errorf("labelsOf() = %#v, want an empty list, which removes the labels", got)
+	}
+}
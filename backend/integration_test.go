@@ -485,7 +485,7 @@ func TestErrorRecoveryWithRetry(t *testing.T) {
 	}
 
 	// Verify retry count incremented
-	ops, _ = localBackend.GetPendingSyncOperations()
+	ops, _ = localBackend.GetQueuedSyncOperations()
 	if len(ops) != 1 {
 		t.Fatalf("Expected 1 pending operation after failed sync, got %d", len(ops))
 	}
@@ -498,8 +498,12 @@ func TestErrorRecoveryWithRetry(t *testing.T) {
 		t.Error("Expected last error to be set")
 	}
 
-	// Clear error and retry
+	// Clear error and retry once the backoff is over
 	remoteBackend.AddTaskErr = nil
+	db, _ := localBackend.GetDB()
+	if _, err := db.Exec("UPDATE sync_queue SET next_retry_at = 0"); err != nil {
+		t.Fatalf("Failed to end the backoff: %v", err)
+	}
 
 	result, err = sm.Sync()
 	if err != nil {
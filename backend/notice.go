@@ -0,0 +1,70 @@
+package backend
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+)
+
+// SecurityNotice warns that a backend's connection settings expose data or
+// credentials, such as plain HTTP or disabled TLS verification
+type SecurityNotice struct {
+	Backend string   // Backend name from the config
+	Kind    string   // Short identifier of the warning, e.g. "http" or "tls"
+	Title   string   // One-line summary
+	Detail  []string // Explanation shown with the full notice
+}
+
+// ID identifies the notice for acknowledging it, e.g. "nextcloud:http"
+func (n SecurityNotice) ID() string {
+	return n.Backend + ":" + n.Kind
+}
+
+var (
+	noticeMu      sync.Mutex
+	noticeHandler = func(n SecurityNotice) { WriteNoticeBox(os.Stderr, n) }
+)
+
+// SetSecurityNoticeHandler replaces how security notices are shown. By
+// default each one is printed to stderr as it is reported.
+func SetSecurityNoticeHandler(handler func(SecurityNotice)) {
+	noticeMu.Lock()
+	defer noticeMu.Unlock()
+	noticeHandler = handler
+}
+
+// ReportSecurityNotice hands a notice to the current handler
+func ReportSecurityNotice(n SecurityNotice) {
+	noticeMu.Lock()
+	handler := noticeHandler
+	noticeMu.Unlock()
+	handler(n)
+}
+
+// noticeBoxWidth is the inner width of the box drawn by WriteNoticeBox
+const noticeBoxWidth = 67
+
+// WriteNoticeBox writes the full form of a notice, framed in a box
+func WriteNoticeBox(w io.Writer, n SecurityNotice) {
+	line := func(text string) {
+		padding := noticeBoxWidth - len([]rune(text)) - 1
+		if padding < 0 {
+			padding = 0
+		}
+		fmt.Fprintf(w, "║ %s%s║\n", text, strings.Repeat(" ", padding))
+	}
+
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "╔"+strings.Repeat("═", noticeBoxWidth)+"╗")
+	line(fmt.Sprintf("SECURITY WARNING (%s)", n.Backend))
+	fmt.Fprintln(w, "╠"+strings.Repeat("═", noticeBoxWidth)+"╣")
+	line(n.Title)
+	line("")
+	for _, detail := range n.Detail {
+		line(detail)
+	}
+	fmt.Fprintln(w, "╚"+strings.Repeat("═", noticeBoxWidth)+"╝")
+	fmt.Fprintln(w)
+}
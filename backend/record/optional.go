@@ -0,0 +1,221 @@
+package record
+
+import (
+	"reflect"
+
+	"gosynctasks/backend"
+)
+
+// serialTypes are the optional interfaces a recording hides, so the recorded
+// run makes its calls one at a time: replay answers them in recorded order
+var serialTypes = []reflect.Type{
+	reflect.TypeFor[backend.ConcurrentWriter](),
+	reflect.TypeFor[backend.ConcurrentReader](),
+}
+
+// Unwrap returns the recorded task manager
+func (t *recordingTaskManager) Unwrap() backend.TaskManager {
+	return t.inner
+}
+
+// Implements reports the optional interfaces of the recorded task manager,
+// which backend.As finds through the recorder, and remembers them for replay.
+// Concurrent reads and writes are left out.
+func (t *recordingTaskManager) Implements(iface reflect.Type) bool {
+	implements := backend.Implements(t.inner, iface)
+	for _, serial := range serialTypes {
+		if iface == serial {
+			implements = false
+		}
+	}
+	t.session.memo(memoKey(t.role, "Implements", iface.String()), implements)
+	return implements
+}
+
+func (t *recordingTaskManager) GetSummaryHistory(taskUID string) ([]backend.SummaryAlias, error) {
+	provider, _ := backend.As[backend.SummaryHistoryProvider](t.inner)
+	aliases, err := provider.GetSummaryHistory(taskUID)
+	t.record("GetSummaryHistory", []any{taskUID}, err, aliases)
+	return aliases, err
+}
+
+func (t *recordingTaskManager) ForceDeleteTask(listID string, taskUID string) error {
+	forceDeleter, _ := backend.As[backend.ForceDeleter](t.inner)
+	err := forceDeleter.ForceDeleteTask(listID, taskUID)
+	t.record("ForceDeleteTask", []any{listID, taskUID}, err)
+	return err
+}
+
+func (t *recordingTaskManager) DescribeRequests(method, listID string, task backend.Task) []string {
+	describer, _ := backend.As[backend.RequestDescriber](t.inner)
+	requests := describer.DescribeRequests(method, listID, task)
+	t.record("DescribeRequests", []any{method, listID, task}, nil, requests)
+	return requests
+}
+
+func (t *recordingTaskManager) GetListCTag(listID string) (string, error) {
+	prober, _ := backend.As[backend.CTagProber](t.inner)
+	ctag, err := prober.GetListCTag(listID)
+	t.record("GetListCTag", []any{listID}, err, ctag)
+	return ctag, err
+}
+
+func (t *recordingTaskManager) GetChanges(listID, syncToken string) (*backend.ListChanges, error) {
+	tracker, _ := backend.As[backend.ChangeTracker](t.inner)
+	changes, err := tracker.GetChanges(listID, syncToken)
+	t.record("GetChanges", []any{listID, syncToken}, err, changes)
+	return changes, err
+}
+
+func (t *recordingTaskManager) GetTasksAt(listID string, hrefs []string) ([]backend.Task, error) {
+	tracker, _ := backend.As[backend.ChangeTracker](t.inner)
+	tasks, err := tracker.GetTasksAt(listID, hrefs)
+	t.record("GetTasksAt", []any{listID, hrefs}, err, tasks)
+	return tasks, err
+}
+
+func (t *recordingTaskManager) CountCategories(listID string) (map[string]int, error) {
+	counter, _ := backend.As[backend.CategoryCounter](t.inner)
+	counts, err := counter.CountCategories(listID)
+	t.record("CountCategories", []any{listID}, err, counts)
+	return counts, err
+}
+
+func (t *recordingTaskManager) SearchAllLists(summary string) (map[string][]backend.Task, error) {
+	searcher, _ := backend.As[backend.CrossListSearcher](t.inner)
+	tasks, err := searcher.SearchAllLists(summary)
+	t.record("SearchAllLists", []any{redactedText(summary)}, err, tasks)
+	return tasks, err
+}
+
+func (t *recordingTaskManager) MaxSummaryLength() int {
+	limiter, _ := backend.As[backend.SummaryLimiter](t.inner)
+	length := limiter.MaxSummaryLength()
+	t.session.memo(memoKey(t.role, "MaxSummaryLength", ""), length)
+	return length
+}
+
+func (t *recordingTaskManager) CountPendingSync() (map[string]backend.PendingSync, error) {
+	counter, _ := backend.As[backend.PendingSyncCounter](t.inner)
+	pending, err := counter.CountPendingSync()
+	t.record("CountPendingSync", nil, err, pending)
+	return pending, err
+}
+
+func (t *recordingTaskManager) GetArchivedTaskLists() ([]backend.TaskList, error) {
+	archiver, _ := backend.As[backend.ListArchiver](t.inner)
+	lists, err := archiver.GetArchivedTaskLists()
+	t.record("GetArchivedTaskLists", nil, err, lists)
+	return lists, err
+}
+
+func (t *recordingTaskManager) UnarchiveTaskList(listID string) error {
+	archiver, _ := backend.As[backend.ListArchiver](t.inner)
+	err := archiver.UnarchiveTaskList(listID)
+	t.record("UnarchiveTaskList", []any{listID}, err)
+	return err
+}
+
+func (t *recordingTaskManager) GetDeletedTasks(listID string) ([]backend.DeletedTask, error) {
+	trash, _ := backend.As[backend.TaskTrash](t.inner)
+	tasks, err := trash.GetDeletedTasks(listID)
+	t.record("GetDeletedTasks", []any{listID}, err, tasks)
+	return tasks, err
+}
+
+func (t *recordingTaskManager) RestoreTask(listID string, taskUID string) error {
+	trash, _ := backend.As[backend.TaskTrash](t.inner)
+	err := trash.RestoreTask(listID, taskUID)
+	t.record("RestoreTask", []any{listID, taskUID}, err)
+	return err
+}
+
+// Unwrap returns nil, there being no task manager behind a replay
+func (t *replayTaskManager) Unwrap() backend.TaskManager {
+	return nil
+}
+
+// Implements reports the optional interfaces the recorded task manager had
+func (t *replayTaskManager) Implements(iface reflect.Type) bool {
+	var implements bool
+	t.replayer.memo(memoKey(t.role, "Implements", iface.String()), &implements)
+	return implements
+}
+
+func (t *replayTaskManager) GetSummaryHistory(taskUID string) ([]backend.SummaryAlias, error) {
+	var aliases []backend.SummaryAlias
+	err := t.replayer.take(t.role, "GetSummaryHistory", &aliases)
+	return aliases, err
+}
+
+func (t *replayTaskManager) ForceDeleteTask(listID string, taskUID string) error {
+	return t.replayer.take(t.role, "ForceDeleteTask")
+}
+
+func (t *replayTaskManager) DescribeRequests(method, listID string, task backend.Task) []string {
+	var requests []string
+	_ = t.replayer.take(t.role, "DescribeRequests", &requests)
+	return requests
+}
+
+func (t *replayTaskManager) GetListCTag(listID string) (string, error) {
+	var ctag string
+	err := t.replayer.take(t.role, "GetListCTag", &ctag)
+	return ctag, err
+}
+
+func (t *replayTaskManager) GetChanges(listID, syncToken string) (*backend.ListChanges, error) {
+	var changes *backend.ListChanges
+	err := t.replayer.take(t.role, "GetChanges", &changes)
+	return changes, err
+}
+
+func (t *replayTaskManager) GetTasksAt(listID string, hrefs []string) ([]backend.Task, error) {
+	var tasks []backend.Task
+	err := t.replayer.take(t.role, "GetTasksAt", &tasks)
+	return tasks, err
+}
+
+func (t *replayTaskManager) CountCategories(listID string) (map[string]int, error) {
+	var counts map[string]int
+	err := t.replayer.take(t.role, "CountCategories", &counts)
+	return counts, err
+}
+
+func (t *replayTaskManager) SearchAllLists(summary string) (map[string][]backend.Task, error) {
+	var tasks map[string][]backend.Task
+	err := t.replayer.take(t.role, "SearchAllLists", &tasks)
+	return tasks, err
+}
+
+func (t *replayTaskManager) MaxSummaryLength() int {
+	var length int
+	t.replayer.memo(memoKey(t.role, "MaxSummaryLength", ""), &length)
+	return length
+}
+
+func (t *replayTaskManager) CountPendingSync() (map[string]backend.PendingSync, error) {
+	var pending map[string]backend.PendingSync
+	err := t.replayer.take(t.role, "CountPendingSync", &pending)
+	return pending, err
+}
+
+func (t *replayTaskManager) GetArchivedTaskLists() ([]backend.TaskList, error) {
+	var lists []backend.TaskList
+	err := t.replayer.take(t.role, "GetArchivedTaskLists", &lists)
+	return lists, err
+}
+
+func (t *replayTaskManager) UnarchiveTaskList(listID string) error {
+	return t.replayer.take(t.role, "UnarchiveTaskList")
+}
+
+func (t *replayTaskManager) GetDeletedTasks(listID string) ([]backend.DeletedTask, error) {
+	var tasks []backend.DeletedTask
+	err := t.replayer.take(t.role, "GetDeletedTasks", &tasks)
+	return tasks, err
+}
+
+func (t *replayTaskManager) RestoreTask(listID string, taskUID string) error {
+	return t.replayer.take(t.role, "RestoreTask")
+}
@@ -0,0 +1,225 @@
+package record
+
+import (
+	"errors"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"testing"
+
+	"gosynctasks/backend"
+	"gosynctasks/backend/sqlite"
+)
+
+// sortingMock sorts by summary, so recorded sort orders are observable
+type sortingMock struct {
+	*backend.MockBackend
+}
+
+func (m sortingMock) SortTasks(tasks []backend.Task) {
+	sort.Slice(tasks, func(i, j int) bool { return tasks[i].Summary < tasks[j].Summary })
+}
+
+func newRecordedMock() sortingMock {
+	mb := backend.NewMockBackend()
+	mb.Lists = []backend.TaskList{{ID: "work", Name: "Work"}}
+	mb.Tasks["work"] = []backend.Task{
+		{UID: "1", Summary: "Write report", Status: "NEEDS-ACTION"},
+		{UID: "2", Summary: "Buy milk", Status: "NEEDS-ACTION"},
+	}
+	return sortingMock{mb}
+}
+
+// runSession exercises the calls an action makes
+func runSession(tm backend.TaskManager) (lists []backend.TaskList, found []backend.Task, sorted []backend.Task, deleteErr error, display string) {
+	lists, _ = tm.GetTaskLists()
+	found, _ = tm.FindTasksBySummary("work", "milk")
+	sorted, _ = tm.GetTasks("work", nil)
+	tm.SortTasks(sorted)
+	deleteErr = tm.DeleteTask("work", "missing")
+	display = tm.StatusToDisplayName("NEEDS-ACTION")
+	return
+}
+
+func TestRecordAndReplay(t *testing.T) {
+	session := NewSession([]string{"Work"}, false)
+	wantLists, wantFound, wantSorted, wantErr, wantDisplay := runSession(WrapTaskManager(newRecordedMock(), session, RoleBackend))
+	if wantSorted[0].Summary != "Buy milk" {
+		t.Fatalf("recording did not sort: %v", wantSorted)
+	}
+
+	path := filepath.Join(t.TempDir(), "session.json")
+	if err := session.Save(path); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	replayer := NewReplayer(loaded)
+	lists, found, sorted, deleteErr, display := runSession(replayer.TaskManager(RoleBackend))
+
+	if !slices.Equal(lists, wantLists) {
+		t.Errorf("lists = %v, want %v", lists, wantLists)
+	}
+	if len(found) != 1 || found[0].UID != wantFound[0].UID {
+		t.Errorf("found = %v, want %v", found, wantFound)
+	}
+	if len(sorted) != 2 || sorted[0].UID != "2" || sorted[1].UID != "1" {
+		t.Errorf("sorted = %v, want recorded order", sorted)
+	}
+	var backendErr *backend.BackendError
+	if !errors.As(deleteErr, &backendErr) || !backendErr.IsNotFound() {
+		t.Errorf("delete error = %v, want not found like %v", deleteErr, wantErr)
+	}
+	if display != wantDisplay {
+		t.Errorf("StatusToDisplayName() = %q, want %q", display, wantDisplay)
+	}
+	if err := replayer.Err(); err != nil {
+		t.Errorf("Err() = %v", err)
+	}
+	if n := replayer.Remaining(); n != 0 {
+		t.Errorf("Remaining() = %d, want 0", n)
+	}
+}
+
+func TestReplayDivergence(t *testing.T) {
+	session := NewSession(nil, false)
+	tm := WrapTaskManager(newRecordedMock(), session, RoleBackend)
+	_, _ = tm.GetTaskLists()
+
+	replay := NewReplayer(session).TaskManager(RoleBackend)
+	_, err := replay.GetTasks("work", nil)
+	var divergence *DivergenceError
+	if !errors.As(err, &divergence) || divergence.Want != "backend.GetTaskLists" {
+		t.Fatalf("GetTasks() error = %v, want divergence from GetTaskLists", err)
+	}
+
+	// Every later call fails with the first divergence
+	if _, err := replay.GetTaskLists(); !errors.As(err, &divergence) {
+		t.Errorf("GetTaskLists() after divergence error = %v", err)
+	}
+}
+
+// TestOptionalInterfaces wraps a SQLite backend: the recording and its
+// replay have the optional interfaces of the backend, recorded
+func TestOptionalInterfaces(t *testing.T) {
+	cache, err := sqlite.NewSQLiteBackend(backend.BackendConfig{
+		Type: "sqlite", Enabled: true, DBPath: filepath.Join(t.TempDir(), "cache.db"),
+	})
+	if err != nil {
+		t.Fatalf("NewSQLiteBackend() error = %v", err)
+	}
+	defer cache.Close()
+	listID, err := cache.CreateTaskList("Work", "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cache.AddTask(listID, backend.Task{Summary: "Buy milk", Status: "NEEDS-ACTION"}); err != nil {
+		t.Fatal(err)
+	}
+
+	session := NewSession(nil, false)
+	tm := WrapTaskManager(cache, session, RoleBackend)
+	has := func(tm backend.TaskManager) []bool {
+		_, trash := backend.As[backend.TaskTrash](tm)
+		_, history := backend.As[backend.SummaryHistoryProvider](tm)
+		_, counter := backend.As[backend.CategoryCounter](tm)
+		_, pending := backend.As[backend.PendingSyncCounter](tm)
+		_, archiver := backend.As[backend.ListArchiver](tm)
+		_, forceDeleter := backend.As[backend.ForceDeleter](tm)
+		return []bool{trash, history, counter, pending, archiver, forceDeleter}
+	}
+	want := []bool{true, true, true, true, true, false}
+	if got := has(tm); !slices.Equal(got, want) {
+		t.Errorf("optional interfaces of the recorder = %v, want %v", got, want)
+	}
+	if got, ok := backend.As[*sqlite.SQLiteBackend](tm); !ok || got != cache {
+		t.Errorf("As[*SQLiteBackend]() = %v, %v, want the wrapped cache", got, ok)
+	}
+	searcher, ok := backend.As[backend.CrossListSearcher](tm)
+	if !ok {
+		t.Fatal("recorder is not a CrossListSearcher")
+	}
+	found, err := searcher.SearchAllLists("milk")
+	if err != nil || len(found[listID]) != 1 {
+		t.Fatalf("SearchAllLists() = %v, %v", found, err)
+	}
+
+	replay := NewReplayer(session).TaskManager(RoleBackend)
+	if got := has(replay); !slices.Equal(got, want) {
+		t.Errorf("optional interfaces of the replay = %v, want %v", got, want)
+	}
+	searcher, ok = backend.As[backend.CrossListSearcher](replay)
+	if !ok {
+		t.Fatal("replay is not a CrossListSearcher")
+	}
+	if replayed, err := searcher.SearchAllLists("milk"); err != nil || len(replayed[listID]) != 1 || replayed[listID][0].UID != found[listID][0].UID {
+		t.Errorf("replayed SearchAllLists() = %v, %v, want %v", replayed, err, found)
+	}
+}
+
+// concurrentMock writes concurrently
+type concurrentMock struct {
+	sortingMock
+}
+
+func (concurrentMock) WritesConcurrently() bool { return true }
+
+func TestRecordingIsSerial(t *testing.T) {
+	tm := WrapTaskManager(concurrentMock{newRecordedMock()}, NewSession(nil, false), RoleBackend)
+	if _, ok := backend.As[backend.ConcurrentWriter](tm); ok {
+		t.Error("recorder writes concurrently, which replay could not reproduce")
+	}
+}
+
+func TestRedactContent(t *testing.T) {
+	session := NewSession(nil, true)
+	tm := WrapTaskManager(newRecordedMock(), session, RoleBackend)
+	found, _ := tm.FindTasksBySummary("work", "milk")
+	if found[0].Summary != "Buy milk" {
+		t.Fatalf("recording changed the returned task: %v", found)
+	}
+	session.SetOutput("Buy milk")
+
+	call := session.Calls[0]
+	for _, raw := range append(call.Args, call.Results...) {
+		if strings.Contains(string(raw), "milk") {
+			t.Errorf("recorded %s contains task content", raw)
+		}
+	}
+	if session.Output != "" {
+		t.Errorf("Output = %q, want none", session.Output)
+	}
+
+	replayed, _ := NewReplayer(session).TaskManager(RoleBackend).FindTasksBySummary("work", "milk")
+	if replayed[0].Summary != HashText("Buy milk") {
+		t.Errorf("replayed summary = %q, want its hash", replayed[0].Summary)
+	}
+}
+
+func TestRedactCommand(t *testing.T) {
+	takesValue := func(flag string) bool { return flag == "s" || flag == "summary" || flag == "config" }
+	got := RedactCommand([]string{"--config", "cfg.yaml", "Work", "update", "Buy milk", "-s", "DONE", "--summary", "Buy oat milk"}, takesValue)
+	want := []string{"--config", "cfg.yaml", "Work", "update", HashText("Buy milk"), "-s", "DONE", "--summary", HashText("Buy oat milk")}
+	if !slices.Equal(got, want) {
+		t.Errorf("RedactCommand() = %v, want %v", got, want)
+	}
+}
+
+func TestSizeLimit(t *testing.T) {
+	session := NewSession(nil, false)
+	session.SetMaxBytes(200)
+	tm := WrapTaskManager(newRecordedMock(), session, RoleBackend)
+	for range 10 {
+		_, _ = tm.GetTasks("work", nil)
+	}
+	if !session.Truncated {
+		t.Fatal("session not truncated past its size limit")
+	}
+	if len(session.Calls) >= 10 {
+		t.Errorf("recorded %d calls past the size limit", len(session.Calls))
+	}
+}
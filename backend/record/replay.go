@@ -0,0 +1,100 @@
+package record
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+)
+
+// DivergenceError reports that a replayed run made a different backend call
+// than the recorded run at the same point
+type DivergenceError struct {
+	Index int    // Position in the recorded calls
+	Got   string // Call the replayed run made, e.g. "backend.GetTasks"
+	Want  string // Call the recording has there, empty past its end
+}
+
+func (e *DivergenceError) Error() string {
+	if e.Want == "" {
+		return fmt.Sprintf("replay diverged at call %d: run made %s after the last recorded call", e.Index, e.Got)
+	}
+	return fmt.Sprintf("replay diverged at call %d: run made %s, recording has %s", e.Index, e.Got, e.Want)
+}
+
+// Replayer answers backend calls from a recorded session, in order. Calls are
+// matched by role and method; arguments are not compared since they hold
+// timestamps taken during the run.
+type Replayer struct {
+	session *Session
+
+	mu   sync.Mutex
+	next int
+	err  error
+}
+
+// NewReplayer creates a replayer for a loaded session
+func NewReplayer(session *Session) *Replayer {
+	return &Replayer{session: session}
+}
+
+// Session returns the session being replayed
+func (r *Replayer) Session() *Session {
+	return r.session
+}
+
+// Err returns the first divergence from the recording, if any
+func (r *Replayer) Err() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.err
+}
+
+// Remaining returns how many recorded calls the replayed run has not made
+func (r *Replayer) Remaining() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.session.Calls) - r.next
+}
+
+// take consumes the next recorded call, decoding its results into the given
+// pointers and returning its recorded error. After a divergence every call
+// fails with it, so the replayed run stops where it left the recorded path.
+func (r *Replayer) take(role, method string, results ...any) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.err != nil {
+		return r.err
+	}
+
+	got := role + "." + method
+	if r.next >= len(r.session.Calls) {
+		r.err = &DivergenceError{Index: r.next, Got: got}
+		if r.session.Truncated {
+			r.err = fmt.Errorf("%w (the recording was truncated by its size limit)", r.err)
+		}
+		return r.err
+	}
+
+	call := r.session.Calls[r.next]
+	if want := call.Role + "." + call.Method; want != got {
+		r.err = &DivergenceError{Index: r.next, Got: got, Want: want}
+		return r.err
+	}
+	r.next++
+
+	for i, result := range results {
+		if i >= len(call.Results) {
+			break
+		}
+		if err := json.Unmarshal(call.Results[i], result); err != nil {
+			return fmt.Errorf("failed to decode recorded result of %s: %w", got, err)
+		}
+	}
+	return call.Err.toError()
+}
+
+// memo looks up the recorded result of a side-effect free helper
+func (r *Replayer) memo(key string, result any) bool {
+	data, ok := r.session.Memo[key]
+	return ok && json.Unmarshal(data, result) == nil
+}
@@ -0,0 +1,344 @@
+// Package record captures the backend calls of a CLI run in a session file
+// and replays them against stub backends, so that a run on a user's machine
+// can be reproduced deterministically from a bug report.
+package record
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"sync"
+
+	"gosynctasks/backend"
+)
+
+// FormatVersion is the session file format written by Save
+const FormatVersion = 1
+
+// DefaultMaxBytes caps the recorded calls and output of a session
+const DefaultMaxBytes = 10 << 20
+
+// Roles name the backend a call was made to
+const (
+	RoleBackend = "backend" // The task manager used by actions and subcommands
+	RoleLocal   = "local"   // The local cache during a sync
+	RoleRemote  = "remote"  // The remote backend during a sync
+)
+
+// Session is a recorded CLI run
+type Session struct {
+	Version       int                        `json:"version"`
+	Command       []string                   `json:"command"`
+	Backend       string                     `json:"backend,omitempty"`
+	Config        json.RawMessage            `json:"config,omitempty"`
+	TaskLists     []backend.TaskList         `json:"task_lists,omitempty"`
+	TerminalWidth int                        `json:"terminal_width,omitempty"`
+	Calls         []Call                     `json:"calls"`
+	Memo          map[string]json.RawMessage `json:"memo,omitempty"`   // Results of side-effect free helpers such as StatusToDisplayName
+	Output        string                     `json:"output,omitempty"` // Not stored when content is redacted
+	Error         string                     `json:"error,omitempty"`
+	Redacted      bool                       `json:"redacted,omitempty"`
+	Truncated     bool                       `json:"truncated,omitempty"`
+
+	mu       sync.Mutex
+	maxBytes int
+	size     int
+}
+
+// Call is one recorded backend call
+type Call struct {
+	Role    string            `json:"role"`
+	Method  string            `json:"method"`
+	Args    []json.RawMessage `json:"args,omitempty"`
+	Results []json.RawMessage `json:"results,omitempty"`
+	Err     *CallError        `json:"error,omitempty"`
+}
+
+// CallError is a recorded error, keeping what callers branch on
+type CallError struct {
+	Message     string            `json:"message"`
+	Operation   string            `json:"operation,omitempty"`
+	StatusCode  int               `json:"status_code,omitempty"`
+	Kind        backend.ErrorKind `json:"kind,omitempty"`
+	TaskUID     string            `json:"task_uid,omitempty"`
+	ListID      string            `json:"list_id,omitempty"`
+	Unreachable bool              `json:"unreachable,omitempty"`
+}
+
+// NewSession starts a recording of the given command line. With
+// redactContent, task summaries and descriptions are stored as hashes; the
+// command line should then be redacted first with RedactCommand.
+func NewSession(command []string, redactContent bool) *Session {
+	return &Session{
+		Version:  FormatVersion,
+		Command:  command,
+		Calls:    []Call{},
+		Memo:     make(map[string]json.RawMessage),
+		Redacted: redactContent,
+		maxBytes: DefaultMaxBytes,
+	}
+}
+
+// SetMaxBytes changes the size limit; calls beyond it are dropped and the
+// session is marked truncated
+func (s *Session) SetMaxBytes(n int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.maxBytes = n
+}
+
+// SetConfig stores the resolved configuration, which must already have its
+// secrets redacted
+func (s *Session) SetConfig(cfg any) error {
+	data, err := json.Marshal(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to encode config: %w", err)
+	}
+	s.Config = data
+	return nil
+}
+
+// SetTaskLists stores the task lists the run started with
+func (s *Session) SetTaskLists(lists []backend.TaskList) {
+	s.TaskLists = append([]backend.TaskList(nil), lists...)
+}
+
+// SetOutput stores the final output of the run, unless content is redacted
+func (s *Session) SetOutput(output string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.Redacted {
+		return
+	}
+	if remaining := s.maxBytes - s.size; len(output) > remaining {
+		output = output[:max(remaining, 0)]
+		s.Truncated = true
+	}
+	s.Output = output
+}
+
+// SetError stores the error the run ended with
+func (s *Session) SetError(err error) {
+	if err != nil {
+		s.Error = s.text(err.Error())
+	}
+}
+
+// record appends a call, unless the size limit has been reached
+func (s *Session) record(role, method string, args []any, results []any, err error) {
+	call := Call{Role: role, Method: method, Err: newCallError(err)}
+	for _, arg := range args {
+		call.Args = append(call.Args, s.encode(arg))
+	}
+	for _, result := range results {
+		call.Results = append(call.Results, s.encode(result))
+	}
+	if call.Err != nil {
+		call.Err.Message = s.text(call.Err.Message)
+	}
+
+	data, _ := json.Marshal(call)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.reserve(len(data)) {
+		return
+	}
+	s.Calls = append(s.Calls, call)
+}
+
+// memo stores the result of a side-effect free helper
+func (s *Session) memo(key string, result any) {
+	data := s.encode(result)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, exists := s.Memo[key]; exists || !s.reserve(len(key)+len(data)) {
+		return
+	}
+	s.Memo[key] = data
+}
+
+// reserve accounts for n more bytes, reporting whether they fit. Once the
+// limit is hit nothing more is recorded, so a truncated session is a prefix
+// of the run. Callers hold s.mu.
+func (s *Session) reserve(n int) bool {
+	if s.Truncated || s.size+n > s.maxBytes {
+		s.Truncated = true
+		return false
+	}
+	s.size += n
+	return true
+}
+
+func (s *Session) encode(v any) json.RawMessage {
+	data, err := json.Marshal(s.redact(v))
+	if err != nil {
+		data, _ = json.Marshal(fmt.Sprintf("unencodable %T: %v", v, err))
+	}
+	return data
+}
+
+// Save writes the session as JSON. The file is private to the user since
+// it can hold task content.
+func (s *Session) Save(path string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode session: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0600); err != nil {
+		return fmt.Errorf("failed to write session: %w", err)
+	}
+	return nil
+}
+
+// Load reads a session written by Save
+func Load(path string) (*Session, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read session: %w", err)
+	}
+	var s Session
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("failed to parse session %s: %w", path, err)
+	}
+	if s.Version != FormatVersion {
+		return nil, fmt.Errorf("session %s has format version %d, this build reads version %d", path, s.Version, FormatVersion)
+	}
+	return &s, nil
+}
+
+// HashText returns a short stable hash of text, used in place of redacted content
+func HashText(text string) string {
+	sum := sha256.Sum256([]byte(text))
+	return "sha256:" + hex.EncodeToString(sum[:8])
+}
+
+// text returns content as stored in the session
+func (s *Session) text(v string) string {
+	if !s.Redacted || v == "" {
+		return v
+	}
+	return HashText(v)
+}
+
+// redact replaces task content in values passed to or returned by backends
+func (s *Session) redact(v any) any {
+	if !s.Redacted {
+		return v
+	}
+	switch v := v.(type) {
+	case backend.Task:
+		return s.redactTask(v)
+	case *backend.Task:
+		if v == nil {
+			return v
+		}
+		task := s.redactTask(*v)
+		return &task
+	case []backend.Task:
+		tasks := make([]backend.Task, len(v))
+		for i, task := range v {
+			tasks[i] = s.redactTask(task)
+		}
+		return tasks
+	case redactedText:
+		return s.text(string(v))
+	}
+	return v
+}
+
+func (s *Session) redactTask(task backend.Task) backend.Task {
+	task.Summary = s.text(task.Summary)
+	task.Description = s.text(task.Description)
+	return task
+}
+
+// redactedText marks a string argument holding task content
+type redactedText string
+
+// contentFlags hold task content and are hashed by RedactCommand
+var contentFlags = map[string]bool{"summary": true, "description": true, "d": true}
+
+// RedactCommand hashes the free text of a command line: positional arguments
+// after the list name and action, and summary or description flag values.
+// takesValue reports whether a flag, named without dashes, consumes the
+// following argument.
+func RedactCommand(command []string, takesValue func(flag string) bool) []string {
+	redacted := make([]string, 0, len(command))
+	positional := 0
+	for i := 0; i < len(command); i++ {
+		arg := command[i]
+		if arg == "--" || !strings.HasPrefix(arg, "-") || arg == "-" {
+			positional++
+			if positional > 2 && arg != "--" {
+				arg = HashText(arg)
+			}
+			redacted = append(redacted, arg)
+			continue
+		}
+
+		name, value, inline := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+		if inline {
+			if contentFlags[name] {
+				arg = arg[:len(arg)-len(value)] + HashText(value)
+			}
+			redacted = append(redacted, arg)
+			continue
+		}
+		redacted = append(redacted, arg)
+		if takesValue(name) && i+1 < len(command) {
+			i++
+			value = command[i]
+			if contentFlags[name] {
+				value = HashText(value)
+			}
+			redacted = append(redacted, value)
+		}
+	}
+	return redacted
+}
+
+func newCallError(err error) *CallError {
+	if err == nil {
+		return nil
+	}
+	var backendErr *backend.BackendError
+	if errors.As(err, &backendErr) {
+		return &CallError{
+			Message:    backendErr.Message,
+			Operation:  backendErr.Operation,
+			StatusCode: backendErr.StatusCode,
+			Kind:       backendErr.Kind,
+			TaskUID:    backendErr.TaskUID,
+			ListID:     backendErr.ListID,
+		}
+	}
+	return &CallError{Message: err.Error(), Unreachable: backend.IsUnreachable(err)}
+}
+
+// toError rebuilds an error that classifies like the recorded one
+func (e *CallError) toError() error {
+	if e == nil {
+		return nil
+	}
+	switch {
+	case e.Operation != "" || e.StatusCode != 0 || e.Kind != "":
+		return &backend.BackendError{
+			Operation:  e.Operation,
+			StatusCode: e.StatusCode,
+			Message:    e.Message,
+			Kind:       e.Kind,
+			TaskUID:    e.TaskUID,
+			ListID:     e.ListID,
+		}
+	case e.Unreachable:
+		return &net.OpError{Op: "replay", Net: "tcp", Err: errors.New(e.Message)}
+	}
+	return errors.New(e.Message)
+}
@@ -0,0 +1,356 @@
+package record
+
+import (
+	"time"
+
+	"gosynctasks/backend"
+	"gosynctasks/backend/sqlite"
+	"gosynctasks/backend/sync"
+)
+
+// recordingStore passes sync calls through to a local store and records them
+type recordingStore struct {
+	inner   sync.LocalStore
+	session *Session
+}
+
+// WrapStore returns a local store that records every call made to inner in
+// the session under RoleLocal. The sync calls nothing on its store outside
+// LocalStore, so the recorder hides nothing from it.
+func WrapStore(inner sync.LocalStore, session *Session) sync.LocalStore {
+	return &recordingStore{inner: inner, session: session}
+}
+
+func (s *recordingStore) record(method string, args []any, err error, results ...any) {
+	s.session.record(RoleLocal, method, args, results, err)
+}
+
+func (s *recordingStore) GetTaskLists() ([]backend.TaskList, error) {
+	lists, err := s.inner.GetTaskLists()
+	s.record("GetTaskLists", nil, err, lists)
+	return lists, err
+}
+
+func (s *recordingStore) GetTasks(listID string, filter *backend.TaskFilter) ([]backend.Task, error) {
+	tasks, err := s.inner.GetTasks(listID, filter)
+	s.record("GetTasks", []any{listID, filter}, err, tasks)
+	return tasks, err
+}
+
+func (s *recordingStore) GetTask(listID, taskUID string) (*backend.Task, error) {
+	task, err := s.inner.GetTask(listID, taskUID)
+	s.record("GetTask", []any{listID, taskUID}, err, task)
+	return task, err
+}
+
+func (s *recordingStore) AddTask(listID string, task backend.Task) (string, error) {
+	uid, err := s.inner.AddTask(listID, task)
+	s.record("AddTask", []any{listID, task}, err, uid)
+	return uid, err
+}
+
+func (s *recordingStore) GetPendingSyncOperations() ([]sqlite.SyncOperation, error) {
+	ops, err := s.inner.GetPendingSyncOperations()
+	s.record("GetPendingSyncOperations", nil, err, ops)
+	return ops, err
+}
+
+func (s *recordingStore) RecordSyncFailure(operationID int, message string, nextRetry time.Time) error {
+	err := s.inner.RecordSyncFailure(operationID, message, nextRetry)
+	s.record("RecordSyncFailure", []any{operationID, message, nextRetry}, err)
+	return err
+}
+
+func (s *recordingStore) ClearSyncFlagsAndQueue(taskUID string) error {
+	err := s.inner.ClearSyncFlagsAndQueue(taskUID)
+	s.record("ClearSyncFlagsAndQueue", []any{taskUID}, err)
+	return err
+}
+
+func (s *recordingStore) StrandSyncOperations(listID, message string) (int, error) {
+	stranded, err := s.inner.StrandSyncOperations(listID, message)
+	s.record("StrandSyncOperations", []any{listID, message}, err, stranded)
+	return stranded, err
+}
+
+func (s *recordingStore) MarkLocallyModified(taskUID string) error {
+	err := s.inner.MarkLocallyModified(taskUID)
+	s.record("MarkLocallyModified", []any{taskUID}, err)
+	return err
+}
+
+func (s *recordingStore) UpdateSyncMetadata(taskUID, listID, etag string, remoteModifiedAt time.Time) error {
+	err := s.inner.UpdateSyncMetadata(taskUID, listID, etag, remoteModifiedAt)
+	s.record("UpdateSyncMetadata", []any{taskUID, listID, etag, remoteModifiedAt}, err)
+	return err
+}
+
+func (s *recordingStore) IsLocallyModified(taskUID string) (bool, error) {
+	modified, err := s.inner.IsLocallyModified(taskUID)
+	s.record("IsLocallyModified", []any{taskUID}, err, modified)
+	return modified, err
+}
+
+func (s *recordingStore) GetRemoteModifiedAt(taskUID string) (*time.Time, error) {
+	modifiedAt, err := s.inner.GetRemoteModifiedAt(taskUID)
+	s.record("GetRemoteModifiedAt", []any{taskUID}, err, modifiedAt)
+	return modifiedAt, err
+}
+
+func (s *recordingStore) GetRemoteETag(taskUID string) (string, error) {
+	etag, err := s.inner.GetRemoteETag(taskUID)
+	s.record("GetRemoteETag", []any{taskUID}, err, etag)
+	return etag, err
+}
+
+func (s *recordingStore) CreateSyncedList(list backend.TaskList) error {
+	err := s.inner.CreateSyncedList(list)
+	s.record("CreateSyncedList", []any{list}, err)
+	return err
+}
+
+func (s *recordingStore) UpdateListInfo(list backend.TaskList) error {
+	err := s.inner.UpdateListInfo(list)
+	s.record("UpdateListInfo", []any{list}, err)
+	return err
+}
+
+func (s *recordingStore) SetListCTag(listID, ctag string) error {
+	err := s.inner.SetListCTag(listID, ctag)
+	s.record("SetListCTag", []any{listID, ctag}, err)
+	return err
+}
+
+func (s *recordingStore) GetListSyncToken(listID string) (string, error) {
+	token, err := s.inner.GetListSyncToken(listID)
+	s.record("GetListSyncToken", []any{listID}, err, token)
+	return token, err
+}
+
+func (s *recordingStore) SetListSyncToken(listID, token string) error {
+	err := s.inner.SetListSyncToken(listID, token)
+	s.record("SetListSyncToken", []any{listID, token}, err)
+	return err
+}
+
+func (s *recordingStore) ClearListCTags() error {
+	err := s.inner.ClearListCTags()
+	s.record("ClearListCTags", nil, err)
+	return err
+}
+
+func (s *recordingStore) GetArchivedTaskLists() ([]backend.TaskList, error) {
+	lists, err := s.inner.GetArchivedTaskLists()
+	s.record("GetArchivedTaskLists", nil, err, lists)
+	return lists, err
+}
+
+func (s *recordingStore) SetListArchived(listID string, archived bool) error {
+	err := s.inner.SetListArchived(listID, archived)
+	s.record("SetListArchived", []any{listID, archived}, err)
+	return err
+}
+
+func (s *recordingStore) InsertSyncedTask(listID string, task backend.Task) error {
+	err := s.inner.InsertSyncedTask(listID, task)
+	s.record("InsertSyncedTask", []any{listID, task}, err)
+	return err
+}
+
+func (s *recordingStore) BulkInsertTasks(listID string, tasks []backend.Task) error {
+	err := s.inner.BulkInsertTasks(listID, tasks)
+	s.record("BulkInsertTasks", []any{listID, tasks}, err)
+	return err
+}
+
+func (s *recordingStore) UpdateSyncedTask(listID string, task backend.Task) error {
+	err := s.inner.UpdateSyncedTask(listID, task)
+	s.record("UpdateSyncedTask", []any{listID, task}, err)
+	return err
+}
+
+func (s *recordingStore) DeleteSyncedTask(listID, taskUID string) error {
+	err := s.inner.DeleteSyncedTask(listID, taskUID)
+	s.record("DeleteSyncedTask", []any{listID, taskUID}, err)
+	return err
+}
+
+func (s *recordingStore) UpdateTaskUID(listID, oldUID, newUID string) error {
+	err := s.inner.UpdateTaskUID(listID, oldUID, newUID)
+	s.record("UpdateTaskUID", []any{listID, oldUID, newUID}, err)
+	return err
+}
+
+func (s *recordingStore) PrunedTasks(listID string) (map[string]time.Time, error) {
+	pruned, err := s.inner.PrunedTasks(listID)
+	s.record("PrunedTasks", []any{listID}, err, pruned)
+	return pruned, err
+}
+
+func (s *recordingStore) ForgetPrunedTasks(listID string, uids []string) error {
+	err := s.inner.ForgetPrunedTasks(listID, uids)
+	s.record("ForgetPrunedTasks", []any{listID, uids}, err)
+	return err
+}
+
+func (s *recordingStore) GetStats() (sqlite.DatabaseStats, error) {
+	stats, err := s.inner.GetStats()
+	s.record("GetStats", nil, err, stats)
+	return stats, err
+}
+
+// replayStore answers local store calls from a recorded session
+type replayStore struct {
+	replayer *Replayer
+}
+
+// Store returns a stub local store answering the calls recorded under RoleLocal
+func (r *Replayer) Store() sync.LocalStore {
+	return &replayStore{replayer: r}
+}
+
+func (s *replayStore) take(method string, results ...any) error {
+	return s.replayer.take(RoleLocal, method, results...)
+}
+
+func (s *replayStore) GetTaskLists() ([]backend.TaskList, error) {
+	var lists []backend.TaskList
+	err := s.take("GetTaskLists", &lists)
+	return lists, err
+}
+
+func (s *replayStore) GetTasks(listID string, filter *backend.TaskFilter) ([]backend.Task, error) {
+	var tasks []backend.Task
+	err := s.take("GetTasks", &tasks)
+	return tasks, err
+}
+
+func (s *replayStore) GetTask(listID, taskUID string) (*backend.Task, error) {
+	var task *backend.Task
+	err := s.take("GetTask", &task)
+	return task, err
+}
+
+func (s *replayStore) AddTask(listID string, task backend.Task) (string, error) {
+	var uid string
+	err := s.take("AddTask", &uid)
+	return uid, err
+}
+
+func (s *replayStore) GetPendingSyncOperations() ([]sqlite.SyncOperation, error) {
+	var ops []sqlite.SyncOperation
+	err := s.take("GetPendingSyncOperations", &ops)
+	return ops, err
+}
+
+func (s *replayStore) RecordSyncFailure(operationID int, message string, nextRetry time.Time) error {
+	return s.take("RecordSyncFailure")
+}
+
+func (s *replayStore) ClearSyncFlagsAndQueue(taskUID string) error {
+	return s.take("ClearSyncFlagsAndQueue")
+}
+
+func (s *replayStore) StrandSyncOperations(listID, message string) (int, error) {
+	var stranded int
+	err := s.take("StrandSyncOperations", &stranded)
+	return stranded, err
+}
+
+func (s *replayStore) MarkLocallyModified(taskUID string) error {
+	return s.take("MarkLocallyModified")
+}
+
+func (s *replayStore) UpdateSyncMetadata(taskUID, listID, etag string, remoteModifiedAt time.Time) error {
+	return s.take("UpdateSyncMetadata")
+}
+
+func (s *replayStore) IsLocallyModified(taskUID string) (bool, error) {
+	var modified bool
+	err := s.take("IsLocallyModified", &modified)
+	return modified, err
+}
+
+func (s *replayStore) GetRemoteModifiedAt(taskUID string) (*time.Time, error) {
+	var modifiedAt *time.Time
+	err := s.take("GetRemoteModifiedAt", &modifiedAt)
+	return modifiedAt, err
+}
+
+func (s *replayStore) GetRemoteETag(taskUID string) (string, error) {
+	var etag string
+	err := s.take("GetRemoteETag", &etag)
+	return etag, err
+}
+
+func (s *replayStore) CreateSyncedList(list backend.TaskList) error {
+	return s.take("CreateSyncedList")
+}
+
+func (s *replayStore) UpdateListInfo(list backend.TaskList) error {
+	return s.take("UpdateListInfo")
+}
+
+func (s *replayStore) SetListCTag(listID, ctag string) error {
+	return s.take("SetListCTag")
+}
+
+func (s *replayStore) GetListSyncToken(listID string) (string, error) {
+	var token string
+	err := s.take("GetListSyncToken", &token)
+	return token, err
+}
+
+func (s *replayStore) SetListSyncToken(listID, token string) error {
+	return s.take("SetListSyncToken")
+}
+
+func (s *replayStore) ClearListCTags() error {
+	return s.take("ClearListCTags")
+}
+
+func (s *replayStore) GetArchivedTaskLists() ([]backend.TaskList, error) {
+	var lists []backend.TaskList
+	err := s.take("GetArchivedTaskLists", &lists)
+	return lists, err
+}
+
+func (s *replayStore) SetListArchived(listID string, archived bool) error {
+	return s.take("SetListArchived")
+}
+
+func (s *replayStore) InsertSyncedTask(listID string, task backend.Task) error {
+	return s.take("InsertSyncedTask")
+}
+
+func (s *replayStore) BulkInsertTasks(listID string, tasks []backend.Task) error {
+	return s.take("BulkInsertTasks")
+}
+
+func (s *replayStore) UpdateSyncedTask(listID string, task backend.Task) error {
+	return s.take("UpdateSyncedTask")
+}
+
+func (s *replayStore) DeleteSyncedTask(listID, taskUID string) error {
+	return s.take("DeleteSyncedTask")
+}
+
+func (s *replayStore) UpdateTaskUID(listID, oldUID, newUID string) error {
+	return s.take("UpdateTaskUID")
+}
+
+func (s *replayStore) PrunedTasks(listID string) (map[string]time.Time, error) {
+	var pruned map[string]time.Time
+	err := s.take("PrunedTasks", &pruned)
+	return pruned, err
+}
+
+func (s *replayStore) ForgetPrunedTasks(listID string, uids []string) error {
+	return s.take("ForgetPrunedTasks")
+}
+
+func (s *replayStore) GetStats() (sqlite.DatabaseStats, error) {
+	var stats sqlite.DatabaseStats
+	err := s.take("GetStats", &stats)
+	return stats, err
+}
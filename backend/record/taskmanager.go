@@ -0,0 +1,303 @@
+package record
+
+import (
+	"fmt"
+	"slices"
+
+	"gosynctasks/backend"
+)
+
+// recordingTaskManager passes calls through to a backend and records them
+type recordingTaskManager struct {
+	inner   backend.TaskManager
+	session *Session
+	role    string
+}
+
+// WrapTaskManager returns a task manager that records every call made to
+// inner in the session under the given role. backend.As finds the optional
+// interfaces of inner through it, recorded too, except concurrent reads and
+// writes, so the run takes the paths it takes unrecorded. A concrete backend
+// type, like the SQLite cache, is inner itself and its calls go unrecorded.
+func WrapTaskManager(inner backend.TaskManager, session *Session, role string) backend.TaskManager {
+	return &recordingTaskManager{inner: inner, session: session, role: role}
+}
+
+func (t *recordingTaskManager) record(method string, args []any, err error, results ...any) {
+	t.session.record(t.role, method, args, results, err)
+}
+
+// memoKey identifies a helper call in Session.Memo
+func memoKey(role, method string, arg any) string {
+	return fmt.Sprintf("%s.%s(%v)", role, method, arg)
+}
+
+func (t *recordingTaskManager) GetTaskLists() ([]backend.TaskList, error) {
+	lists, err := t.inner.GetTaskLists()
+	t.record("GetTaskLists", nil, err, lists)
+	return lists, err
+}
+
+func (t *recordingTaskManager) GetTasks(listID string, taskFilter *backend.TaskFilter) ([]backend.Task, error) {
+	tasks, err := t.inner.GetTasks(listID, taskFilter)
+	t.record("GetTasks", []any{listID, taskFilter}, err, tasks)
+	return tasks, err
+}
+
+func (t *recordingTaskManager) FindTasksBySummary(listID string, summary string) ([]backend.Task, error) {
+	tasks, err := t.inner.FindTasksBySummary(listID, summary)
+	t.record("FindTasksBySummary", []any{listID, redactedText(summary)}, err, tasks)
+	return tasks, err
+}
+
+func (t *recordingTaskManager) GetTask(listID string, taskUID string) (*backend.Task, error) {
+	task, err := t.inner.GetTask(listID, taskUID)
+	t.record("GetTask", []any{listID, taskUID}, err, task)
+	return task, err
+}
+
+func (t *recordingTaskManager) AddTask(listID string, task backend.Task) (string, error) {
+	uid, err := t.inner.AddTask(listID, task)
+	t.record("AddTask", []any{listID, task}, err, uid)
+	return uid, err
+}
+
+func (t *recordingTaskManager) UpdateTask(listID string, task backend.Task) error {
+	err := t.inner.UpdateTask(listID, task)
+	t.record("UpdateTask", []any{listID, task}, err)
+	return err
+}
+
+func (t *recordingTaskManager) DeleteTask(listID string, taskUID string) error {
+	err := t.inner.DeleteTask(listID, taskUID)
+	t.record("DeleteTask", []any{listID, taskUID}, err)
+	return err
+}
+
+func (t *recordingTaskManager) CreateTaskList(name, description, color string) (string, error) {
+	listID, err := t.inner.CreateTaskList(name, description, color)
+	t.record("CreateTaskList", []any{name, description, color}, err, listID)
+	return listID, err
+}
+
+func (t *recordingTaskManager) DeleteTaskList(listID string) error {
+	err := t.inner.DeleteTaskList(listID)
+	t.record("DeleteTaskList", []any{listID}, err)
+	return err
+}
+
+func (t *recordingTaskManager) RenameTaskList(listID, newName string) error {
+	err := t.inner.RenameTaskList(listID, newName)
+	t.record("RenameTaskList", []any{listID, newName}, err)
+	return err
+}
+
+func (t *recordingTaskManager) GetDeletedTaskLists() ([]backend.TaskList, error) {
+	lists, err := t.inner.GetDeletedTaskLists()
+	t.record("GetDeletedTaskLists", nil, err, lists)
+	return lists, err
+}
+
+func (t *recordingTaskManager) RestoreTaskList(listID string) error {
+	err := t.inner.RestoreTaskList(listID)
+	t.record("RestoreTaskList", []any{listID}, err)
+	return err
+}
+
+func (t *recordingTaskManager) PermanentlyDeleteTaskList(listID string) error {
+	err := t.inner.PermanentlyDeleteTaskList(listID)
+	t.record("PermanentlyDeleteTaskList", []any{listID}, err)
+	return err
+}
+
+// SortTasks is recorded as the permutation it applied, so replay reproduces
+// the backend's order without knowing its rules
+func (t *recordingTaskManager) SortTasks(tasks []backend.Task) {
+	original := slices.Clone(tasks)
+	t.inner.SortTasks(tasks)
+	t.record("SortTasks", nil, nil, sortPermutation(original, tasks))
+}
+
+// sortPermutation returns, for each position of sorted, the index of that
+// task in original
+func sortPermutation(original, sorted []backend.Task) []int {
+	used := make([]bool, len(original))
+	perm := make([]int, len(sorted))
+	for i, task := range sorted {
+		perm[i] = -1
+		for j, candidate := range original {
+			if !used[j] && candidate.UID == task.UID && candidate.Summary == task.Summary {
+				used[j] = true
+				perm[i] = j
+				break
+			}
+		}
+	}
+	return perm
+}
+
+func (t *recordingTaskManager) ParseStatusFlag(statusFlag string) (string, error) {
+	status, err := t.inner.ParseStatusFlag(statusFlag)
+	if err == nil {
+		t.session.memo(memoKey(t.role, "ParseStatusFlag", statusFlag), status)
+	}
+	return status, err
+}
+
+func (t *recordingTaskManager) StatusToDisplayName(backendStatus string) string {
+	name := t.inner.StatusToDisplayName(backendStatus)
+	t.session.memo(memoKey(t.role, "StatusToDisplayName", backendStatus), name)
+	return name
+}
+
+func (t *recordingTaskManager) GetPriorityColor(priority int) string {
+	color := t.inner.GetPriorityColor(priority)
+	t.session.memo(memoKey(t.role, "GetPriorityColor", priority), color)
+	return color
+}
+
+func (t *recordingTaskManager) GetBackendDisplayName() string {
+	name := t.inner.GetBackendDisplayName()
+	t.session.memo(memoKey(t.role, "GetBackendDisplayName", ""), name)
+	return name
+}
+
+func (t *recordingTaskManager) GetBackendType() string {
+	backendType := t.inner.GetBackendType()
+	t.session.memo(memoKey(t.role, "GetBackendType", ""), backendType)
+	return backendType
+}
+
+func (t *recordingTaskManager) GetBackendContext() string {
+	context := t.inner.GetBackendContext()
+	t.session.memo(memoKey(t.role, "GetBackendContext", ""), context)
+	return context
+}
+
+// replayTaskManager answers calls from a recorded session
+type replayTaskManager struct {
+	replayer *Replayer
+	role     string
+}
+
+// TaskManager returns a stub task manager answering the calls recorded under role
+func (r *Replayer) TaskManager(role string) backend.TaskManager {
+	return &replayTaskManager{replayer: r, role: role}
+}
+
+func (t *replayTaskManager) GetTaskLists() ([]backend.TaskList, error) {
+	var lists []backend.TaskList
+	err := t.replayer.take(t.role, "GetTaskLists", &lists)
+	return lists, err
+}
+
+func (t *replayTaskManager) GetTasks(listID string, taskFilter *backend.TaskFilter) ([]backend.Task, error) {
+	var tasks []backend.Task
+	err := t.replayer.take(t.role, "GetTasks", &tasks)
+	return tasks, err
+}
+
+func (t *replayTaskManager) FindTasksBySummary(listID string, summary string) ([]backend.Task, error) {
+	var tasks []backend.Task
+	err := t.replayer.take(t.role, "FindTasksBySummary", &tasks)
+	return tasks, err
+}
+
+func (t *replayTaskManager) GetTask(listID string, taskUID string) (*backend.Task, error) {
+	var task *backend.Task
+	err := t.replayer.take(t.role, "GetTask", &task)
+	return task, err
+}
+
+func (t *replayTaskManager) AddTask(listID string, task backend.Task) (string, error) {
+	var uid string
+	err := t.replayer.take(t.role, "AddTask", &uid)
+	return uid, err
+}
+
+func (t *replayTaskManager) UpdateTask(listID string, task backend.Task) error {
+	return t.replayer.take(t.role, "UpdateTask")
+}
+
+func (t *replayTaskManager) DeleteTask(listID string, taskUID string) error {
+	return t.replayer.take(t.role, "DeleteTask")
+}
+
+func (t *replayTaskManager) CreateTaskList(name, description, color string) (string, error) {
+	var listID string
+	err := t.replayer.take(t.role, "CreateTaskList", &listID)
+	return listID, err
+}
+
+func (t *replayTaskManager) DeleteTaskList(listID string) error {
+	return t.replayer.take(t.role, "DeleteTaskList")
+}
+
+func (t *replayTaskManager) RenameTaskList(listID, newName string) error {
+	return t.replayer.take(t.role, "RenameTaskList")
+}
+
+func (t *replayTaskManager) GetDeletedTaskLists() ([]backend.TaskList, error) {
+	var lists []backend.TaskList
+	err := t.replayer.take(t.role, "GetDeletedTaskLists", &lists)
+	return lists, err
+}
+
+func (t *replayTaskManager) RestoreTaskList(listID string) error {
+	return t.replayer.take(t.role, "RestoreTaskList")
+}
+
+func (t *replayTaskManager) PermanentlyDeleteTaskList(listID string) error {
+	return t.replayer.take(t.role, "PermanentlyDeleteTaskList")
+}
+
+func (t *replayTaskManager) SortTasks(tasks []backend.Task) {
+	var perm []int
+	if t.replayer.take(t.role, "SortTasks", &perm) != nil || len(perm) != len(tasks) {
+		return
+	}
+	original := slices.Clone(tasks)
+	for i, j := range perm {
+		if j >= 0 && j < len(original) {
+			tasks[i] = original[j]
+		}
+	}
+}
+
+func (t *replayTaskManager) ParseStatusFlag(statusFlag string) (string, error) {
+	var status string
+	if !t.replayer.memo(memoKey(t.role, "ParseStatusFlag", statusFlag), &status) {
+		return "", fmt.Errorf("status %q was not parsed in the recorded run", statusFlag)
+	}
+	return status, nil
+}
+
+func (t *replayTaskManager) StatusToDisplayName(backendStatus string) string {
+	name := backendStatus
+	t.replayer.memo(memoKey(t.role, "StatusToDisplayName", backendStatus), &name)
+	return name
+}
+
+func (t *replayTaskManager) GetPriorityColor(priority int) string {
+	var color string
+	t.replayer.memo(memoKey(t.role, "GetPriorityColor", priority), &color)
+	return color
+}
+
+func (t *replayTaskManager) GetBackendDisplayName() string {
+	name := "[replay]"
+	t.replayer.memo(memoKey(t.role, "GetBackendDisplayName", ""), &name)
+	return name
+}
+
+func (t *replayTaskManager) GetBackendType() string {
+	var backendType string
+	t.replayer.memo(memoKey(t.role, "GetBackendType", ""), &backendType)
+	return backendType
+}
+
+func (t *replayTaskManager) GetBackendContext() string {
+	var context string
+	t.replayer.memo(memoKey(t.role, "GetBackendContext", ""), &context)
+	return context
+}
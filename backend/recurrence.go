@@ -0,0 +1,305 @@
+package backend
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// recurrenceShortcuts are the --repeat values standing for a whole rule
+var recurrenceShortcuts = map[string]string{
+	"daily":   "FREQ=DAILY",
+	"weekly":  "FREQ=WEEKLY",
+	"monthly": "FREQ=MONTHLY",
+	"yearly":  "FREQ=YEARLY",
+}
+
+var weekdayCodes = map[string]time.Weekday{
+	"SU": time.Sunday,
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+}
+
+// RecurrenceRule is the part of an RFC 5545 RRULE tasks are repeated by:
+// FREQ, INTERVAL, COUNT, UNTIL, BYDAY for weekly rules and BYMONTHDAY for
+// monthly ones.
+type RecurrenceRule struct {
+	Freq       string // DAILY, WEEKLY, MONTHLY or YEARLY
+	Interval   int
+	Count      int        // Occurrences left, the current one included (0 when unbounded)
+	Until      *time.Time // Last instant an occurrence may be at (nil when unbounded)
+	ByDay      []time.Weekday
+	ByMonthDay []int
+	WeekStart  time.Weekday
+}
+
+// NormalizeRecurrence turns a --repeat value into the RRULE value stored on
+// tasks: the shortcuts daily, weekly, monthly and yearly stand for their
+// rule, and a rule is upper-cased without its RRULE: name. An empty value
+// and "none" remove the recurrence. Rules ParseRecurrence rejects are
+// errors.
+func NormalizeRecurrence(value string) (string, error) {
+	value = strings.TrimSpace(value)
+	if value == "" || strings.EqualFold(value, "none") {
+		return "", nil
+	}
+	if rule, ok := recurrenceShortcuts[strings.ToLower(value)]; ok {
+		return rule, nil
+	}
+	value = strings.ToUpper(value)
+	value = strings.TrimPrefix(value, "RRULE:")
+	if _, err := ParseRecurrence(value); err != nil {
+		return "", err
+	}
+	return value, nil
+}
+
+// ParseRecurrence reads an RRULE value, e.g. "FREQ=WEEKLY;BYDAY=MO,TH".
+// Parts it cannot compute occurrences for are errors.
+func ParseRecurrence(value string) (*RecurrenceRule, error) {
+	rule := &RecurrenceRule{Interval: 1, WeekStart: time.Monday}
+	for part := range strings.SplitSeq(value, ";") {
+		if part == "" {
+			continue
+		}
+		name, val, ok := strings.Cut(part, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid recurrence rule part %q", part)
+		}
+		name, val = strings.ToUpper(name), strings.ToUpper(val)
+
+		switch name {
+		case "FREQ":
+			switch val {
+			case "DAILY", "WEEKLY", "MONTHLY", "YEARLY":
+				rule.Freq = val
+			default:
+				return nil, fmt.Errorf("unsupported recurrence frequency %q (use DAILY, WEEKLY, MONTHLY or YEARLY)", val)
+			}
+		case "INTERVAL":
+			n, err := strconv.Atoi(val)
+			if err != nil || n < 1 {
+				return nil, fmt.Errorf("invalid recurrence interval %q", val)
+			}
+			rule.Interval = n
+		case "COUNT":
+			n, err := strconv.Atoi(val)
+			if err != nil || n < 1 {
+				return nil, fmt.Errorf("invalid recurrence count %q", val)
+			}
+			rule.Count = n
+		case "UNTIL":
+			until, err := parseUntil(val)
+			if err != nil {
+				return nil, err
+			}
+			rule.Until = &until
+		case "BYDAY":
+			for code := range strings.SplitSeq(val, ",") {
+				day, ok := weekdayCodes[code]
+				if !ok {
+					return nil, fmt.Errorf("unsupported recurrence day %q (use MO, TU, WE, TH, FR, SA or SU)", code)
+				}
+				rule.ByDay = append(rule.ByDay, day)
+			}
+		case "BYMONTHDAY":
+			for field := range strings.SplitSeq(val, ",") {
+				n, err := strconv.Atoi(field)
+				if err != nil || n == 0 || n < -31 || n > 31 {
+					return nil, fmt.Errorf("invalid recurrence month day %q", field)
+				}
+				rule.ByMonthDay = append(rule.ByMonthDay, n)
+			}
+		case "WKST":
+			day, ok := weekdayCodes[val]
+			if !ok {
+				return nil, fmt.Errorf("invalid recurrence week start %q", val)
+			}
+			rule.WeekStart = day
+		default:
+			return nil, fmt.Errorf("unsupported recurrence rule part %s", name)
+		}
+	}
+
+	switch {
+	case rule.Freq == "":
+		return nil, fmt.Errorf("recurrence rule %q has no FREQ", value)
+	case rule.Count > 0 && rule.Until != nil:
+		return nil, fmt.Errorf("recurrence rule %q has both COUNT and UNTIL", value)
+	case len(rule.ByDay) > 0 && rule.Freq != "WEEKLY":
+		return nil, fmt.Errorf("BYDAY is only supported in weekly recurrence rules")
+	case len(rule.ByMonthDay) > 0 && rule.Freq != "MONTHLY":
+		return nil, fmt.Errorf("BYMONTHDAY is only supported in monthly recurrence rules")
+	}
+	return rule, nil
+}
+
+// parseUntil reads the UNTIL of a rule; a date stands for the end of that
+// day
+func parseUntil(value string) (time.Time, error) {
+	if t, err := time.Parse("20060102T150405Z", value); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation("20060102T150405", value, time.Local); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation("20060102", value, time.Local); err == nil {
+		return t.AddDate(0, 0, 1).Add(-time.Second), nil
+	}
+	return time.Time{}, fmt.Errorf("invalid recurrence end %q", value)
+}
+
+// maxRecurrenceSteps bounds the periods searched for the next occurrence of
+// rules that may have none, such as the 30th of every twelfth month from a
+// February
+const maxRecurrenceSteps = 1000
+
+// Next returns the occurrence of the rule following the occurrence at t, at
+// the same time of day, and false when there is none. COUNT and UNTIL are
+// not applied.
+func (r *RecurrenceRule) Next(t time.Time) (time.Time, bool) {
+	switch r.Freq {
+	case "WEEKLY":
+		if len(r.ByDay) == 0 {
+			return t.AddDate(0, 0, 7*r.Interval), true
+		}
+		// The days of the weeks every Interval weeks from the week of t
+		weekStart := dateOf(t).AddDate(0, 0, -daysSinceWeekStart(t, r.WeekStart))
+		for day := 1; day <= 7*r.Interval+7; day++ {
+			next := t.AddDate(0, 0, day)
+			weeks := daysBetween(weekStart, next) / 7
+			if weeks%r.Interval == 0 && slices.Contains(r.ByDay, next.Weekday()) {
+				return next, true
+			}
+		}
+	case "MONTHLY":
+		days := r.ByMonthDay
+		if len(days) == 0 {
+			days = []int{t.Day()}
+		}
+		// Months without any of the days, such as February for the 30th,
+		// have no occurrence
+		for step := range maxRecurrenceSteps {
+			first := time.Date(t.Year(), t.Month()+time.Month(step*r.Interval), 1, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location())
+			if next, ok := firstMonthDayAfter(first, days, t); ok {
+				return next, true
+			}
+		}
+	case "YEARLY":
+		// February 29th only comes back in leap years
+		for step := 1; step <= maxRecurrenceSteps; step++ {
+			next := time.Date(t.Year()+step*r.Interval, t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location())
+			if next.Day() == t.Day() {
+				return next, true
+			}
+		}
+	default:
+		return t.AddDate(0, 0, r.Interval), true
+	}
+	return time.Time{}, false
+}
+
+// firstMonthDayAfter returns the earliest of days in the month starting at
+// first that is after t; negative days count from the end of the month
+func firstMonthDayAfter(first time.Time, days []int, t time.Time) (time.Time, bool) {
+	length := first.AddDate(0, 1, -1).Day()
+	var best time.Time
+	found := false
+	for _, day := range days {
+		if day < 0 {
+			day = length + day + 1
+		}
+		if day < 1 || day > length {
+			continue
+		}
+		candidate := first.AddDate(0, 0, day-1)
+		if candidate.After(t) && (!found || candidate.Before(best)) {
+			best, found = candidate, true
+		}
+	}
+	return best, found
+}
+
+// dateOf returns midnight of the day of t
+func dateOf(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+// daysBetween returns the number of calendar days from the day of a to the
+// day of b
+func daysBetween(a, b time.Time) int {
+	da := time.Date(a.Year(), a.Month(), a.Day(), 0, 0, 0, 0, time.UTC)
+	db := time.Date(b.Year(), b.Month(), b.Day(), 0, 0, 0, 0, time.UTC)
+	return int(db.Sub(da).Hours() / 24)
+}
+
+func daysSinceWeekStart(t time.Time, weekStart time.Weekday) int {
+	return (int(t.Weekday()) - int(weekStart) + 7) % 7
+}
+
+// NextOccurrence returns the task moved to the occurrence of its recurrence
+// following the current one, to be done next: its start date goes to the
+// next occurrence of the rule, and its due date the same number of days
+// forward, or its due date when it has no start date. A task with neither
+// is due one step after now. The status is left to the caller.
+//
+// ok is false when the rule has no occurrence left: COUNT is the number of
+// occurrences left, the current one included, and goes down by one with each
+// call, and an occurrence after UNTIL does not count.
+func (t Task) NextOccurrence(now time.Time) (next Task, ok bool, err error) {
+	rule, err := ParseRecurrence(t.Recurrence)
+	if err != nil {
+		return t, false, err
+	}
+	if rule.Count == 1 {
+		return t, false, nil
+	}
+
+	next = t
+	anchor := now
+	switch {
+	case t.StartDate != nil:
+		anchor = *t.StartDate
+	case t.DueDate != nil:
+		anchor = *t.DueDate
+	}
+	at, found := rule.Next(anchor)
+	if !found || (rule.Until != nil && at.After(*rule.Until)) {
+		return t, false, nil
+	}
+
+	days := daysBetween(anchor, at)
+	switch {
+	case t.StartDate != nil:
+		next.StartDate = &at
+		if t.DueDate != nil {
+			due := t.DueDate.AddDate(0, 0, days)
+			next.DueDate = &due
+		}
+	default:
+		next.DueDate = &at
+	}
+	if rule.Count > 0 {
+		next.Recurrence = withCount(t.Recurrence, rule.Count-1)
+	}
+	next.Completed = nil
+	return next, true, nil
+}
+
+// withCount returns the rule value with its COUNT set to count, leaving the
+// other parts as written
+func withCount(value string, count int) string {
+	parts := strings.Split(value, ";")
+	for i, part := range parts {
+		if name, _, _ := strings.Cut(part, "="); strings.EqualFold(name, "COUNT") {
+			parts[i] = "COUNT=" + strconv.Itoa(count)
+		}
+	}
+	return strings.Join(parts, ";")
+}
@@ -0,0 +1,119 @@
+package backend
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNormalizeRecurrence(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    string
+		wantErr bool
+	}{
+		{value: "weekly", want: "FREQ=WEEKLY"},
+		{value: "Daily", want: "FREQ=DAILY"},
+		{value: "RRULE:freq=weekly;byday=mo", want: "FREQ=WEEKLY;BYDAY=MO"},
+		{value: "FREQ=MONTHLY;COUNT=3", want: "FREQ=MONTHLY;COUNT=3"},
+		{value: "none", want: ""},
+		{value: "", want: ""},
+		{value: "fortnightly", wantErr: true},
+		{value: "FREQ=HOURLY", wantErr: true},
+		{value: "FREQ=DAILY;BYDAY=MO", wantErr: true},
+		{value: "FREQ=WEEKLY;BYDAY=1MO", wantErr: true},
+		{value: "FREQ=DAILY;COUNT=2;UNTIL=20260101", wantErr: true},
+		{value: "FREQ=YEARLY;BYSETPOS=1", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := NormalizeRecurrence(tt.value)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("NormalizeRecurrence(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("NormalizeRecurrence(%q) = %q, want %q", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestRecurrenceRuleNext(t *testing.T) {
+	day := func(year int, month time.Month, d int) time.Time {
+		return time.Date(year, month, d, 9, 30, 0, 0, time.UTC)
+	}
+	tests := []struct {
+		rule string
+		from time.Time
+		want time.Time
+	}{
+		{"FREQ=DAILY", day(2026, 3, 31), day(2026, 4, 1)},
+		{"FREQ=DAILY;INTERVAL=3", day(2026, 3, 1), day(2026, 3, 4)},
+		{"FREQ=WEEKLY", day(2026, 3, 4), day(2026, 3, 11)},
+		// 2026-03-02 is a Monday
+		{"FREQ=WEEKLY;BYDAY=MO,TH", day(2026, 3, 2), day(2026, 3, 5)},
+		{"FREQ=WEEKLY;BYDAY=MO,TH", day(2026, 3, 5), day(2026, 3, 9)},
+		{"FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,TH", day(2026, 3, 5), day(2026, 3, 16)},
+		{"FREQ=MONTHLY", day(2026, 1, 15), day(2026, 2, 15)},
+		{"FREQ=MONTHLY", day(2026, 1, 31), day(2026, 3, 31)},
+		{"FREQ=MONTHLY;BYMONTHDAY=1,15", day(2026, 1, 1), day(2026, 1, 15)},
+		{"FREQ=MONTHLY;BYMONTHDAY=-1", day(2026, 1, 31), day(2026, 2, 28)},
+		{"FREQ=YEARLY", day(2026, 6, 1), day(2027, 6, 1)},
+		{"FREQ=YEARLY", day(2024, 2, 29), day(2028, 2, 29)},
+	}
+	for _, tt := range tests {
+		rule, err := ParseRecurrence(tt.rule)
+		if err != nil {
+			t.Fatalf("ParseRecurrence(%q) error = %v", tt.rule, err)
+		}
+		got, ok := rule.Next(tt.from)
+		if !ok || !got.Equal(tt.want) {
+			t.Errorf("%s: Next(%s) = %s, %v, want %s", tt.rule, tt.from.Format(time.DateOnly), got.Format(time.DateTime), ok, tt.want.Format(time.DateTime))
+		}
+	}
+}
+
+func TestTaskNextOccurrence(t *testing.T) {
+	start := time.Date(2026, 3, 2, 8, 0, 0, 0, time.UTC)
+	due := time.Date(2026, 3, 4, 18, 0, 0, 0, time.UTC)
+	completed := time.Date(2026, 3, 3, 10, 0, 0, 0, time.UTC)
+	task := Task{UID: "t", Summary: "Chore", StartDate: &start, DueDate: &due, Completed: &completed, Recurrence: "FREQ=WEEKLY;COUNT=2"}
+
+	next, ok, err := task.NextOccurrence(completed)
+	if err != nil || !ok {
+		t.Fatalf("NextOccurrence() = %v, %v, want the next occurrence", ok, err)
+	}
+	if !next.StartDate.Equal(start.AddDate(0, 0, 7)) || !next.DueDate.Equal(due.AddDate(0, 0, 7)) {
+		t.Errorf("NextOccurrence() moved to start %s, due %s, want a week later", next.StartDate, next.DueDate)
+	}
+	if next.Recurrence != "FREQ=WEEKLY;COUNT=1" || next.Completed != nil {
+		t.Errorf("NextOccurrence() = recurrence %q, completed %v, want COUNT=1 and no completion", next.Recurrence, next.Completed)
+	}
+	if !task.StartDate.Equal(start) || task.Recurrence != "FREQ=WEEKLY;COUNT=2" {
+		t.Error("NextOccurrence() modified the task")
+	}
+
+	// The last occurrence of the COUNT
+	if _, ok, err := next.NextOccurrence(completed); err != nil || ok {
+		t.Errorf("NextOccurrence() of the last occurrence = %v, %v, want none left", ok, err)
+	}
+
+	// An occurrence after UNTIL
+	task.Recurrence = "FREQ=WEEKLY;UNTIL=20260308"
+	if _, ok, err := task.NextOccurrence(completed); err != nil || ok {
+		t.Errorf("NextOccurrence() past UNTIL = %v, %v, want none left", ok, err)
+	}
+	task.Recurrence = "FREQ=WEEKLY;UNTIL=20260309"
+	if _, ok, err := task.NextOccurrence(completed); err != nil || !ok {
+		t.Errorf("NextOccurrence() on UNTIL = %v, %v, want the next occurrence", ok, err)
+	}
+
+	// Without dates, the task becomes due one step from now
+	undated := Task{UID: "u", Recurrence: "FREQ=DAILY"}
+	next, ok, err = undated.NextOccurrence(completed)
+	if err != nil || !ok || next.DueDate == nil || !next.DueDate.Equal(completed.AddDate(0, 0, 1)) {
+		t.Errorf("NextOccurrence() of an undated task = due %v, %v, %v, want a day after now", next.DueDate, ok, err)
+	}
+
+	if _, _, err := (Task{Recurrence: "FREQ=SECONDLY"}).NextOccurrence(completed); err == nil {
+		t.Error("NextOccurrence() of an unsupported rule succeeded, want an error")
+	}
+}
@@ -284,17 +284,14 @@ func (s *BackendSelector) DetectAll() []BackendInfo {
 	return detected
 }
 
-// isRemoteBackend checks if a backend is a remote backend (nextcloud, todoist)
+// isRemoteBackend checks if a backend is a remote backend (nextcloud, todoist),
+// or a git or file backend synced like one
 func (s *BackendSelector) isRemoteBackend(backendName string) bool {
 	config, exists := s.registry.configs[backendName]
 	if !exists {
 		return false
 	}
-	remoteTypes := map[string]bool{
-		"nextcloud": true,
-		"todoist":   true,
-	}
-	return remoteTypes[config.Type]
+	return config.IsRemoteBackend() || config.SyncOptedIn()
 }
 
 // createCacheBackend creates a cache backend instance for a remote backend
@@ -309,7 +306,8 @@ func (s *BackendSelector) createCacheBackend(cacheType string, remoteBackendName
 		Name:    remoteBackendName, // Use remote backend name for backend_name column
 		Type:    "sqlite",
 		Enabled: true,
-		DBPath:  cachePath, // Shared database for all backends
+		DBPath:  cachePath,                         // Shared database for all backends
+		Sync:    &BackendSyncConfig{Enabled: true}, // Marks the instance as a sync cache
 	}
 
 	// Create cache backend using TaskManager() method which calls registered constructor
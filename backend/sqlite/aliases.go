@@ -0,0 +1,144 @@
+package sqlite
+
+import (
+	"database/sql"
+	"time"
+
+	"gosynctasks/backend"
+)
+
+// MaxSummaryAliases is the number of former summaries kept per task
+const MaxSummaryAliases = 3
+
+// recordSummaryAlias stores oldSummary as a former name of the task when the
+// summary changed, keeping only the most recent MaxSummaryAliases entries.
+// It is called from both local updates and sync pulls; s seals the alias
+// when the task's list is encrypted.
+func recordSummaryAlias(tx *sql.Tx, s *sealer, internalID int64, oldSummary, newSummary string) error {
+	if oldSummary == "" || oldSummary == newSummary {
+		return nil
+	}
+
+	stored, err := s.seal(oldSummary)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec(`
+		INSERT INTO task_aliases (task_internal_id, former_summary, changed_at)
+		VALUES (?, ?, ?)
+	`, internalID, stored, time.Now().Unix())
+	if err != nil {
+		return err
+	}
+
+	// Drop any alias equal to the new name; sealed aliases only compare once opened
+	aliases, err := listAliases(tx, "SELECT id, former_summary FROM task_aliases WHERE task_internal_id = ?", internalID)
+	if err != nil {
+		return err
+	}
+	for id, former := range aliases {
+		if former, err = s.open(former); err != nil {
+			return err
+		}
+		if former != newSummary {
+			continue
+		}
+		if _, err := tx.Exec("DELETE FROM task_aliases WHERE id = ?", id); err != nil {
+			return err
+		}
+	}
+
+	// Drop aliases beyond the retention limit
+	_, err = tx.Exec(`
+		DELETE FROM task_aliases
+		WHERE task_internal_id = ?
+		  AND id NOT IN (
+		      SELECT id FROM task_aliases
+		      WHERE task_internal_id = ?
+		      ORDER BY changed_at DESC, id DESC
+		      LIMIT ?
+		  )
+	`, internalID, internalID, MaxSummaryAliases)
+	return err
+}
+
+// listAliases returns the former summaries selected by query, as stored, by alias ID
+func listAliases(tx *sql.Tx, query string, args ...interface{}) (map[int64]string, error) {
+	rows, err := tx.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	aliases := make(map[int64]string)
+	for rows.Next() {
+		var id int64
+		var summary string
+		if err := rows.Scan(&id, &summary); err != nil {
+			return nil, err
+		}
+		aliases[id] = summary
+	}
+	return aliases, rows.Err()
+}
+
+// GetSummaryHistory returns the former summaries of a task, most recent first
+func (sb *SQLiteBackend) GetSummaryHistory(taskUID string) ([]backend.SummaryAlias, error) {
+	db, err := sb.GetDB()
+	if err != nil {
+		return nil, &SQLiteError{Op: "GetSummaryHistory", TaskUID: taskUID, Err: err}
+	}
+
+	rows, err := db.Query(`
+		SELECT a.former_summary, a.changed_at
+		FROM task_aliases a
+		JOIN tasks t ON t.internal_id = a.task_internal_id
+		WHERE t.backend_name = ? AND t.uid = ?
+		ORDER BY a.changed_at DESC, a.id DESC
+	`, sb.backendName, taskUID)
+	if err != nil {
+		return nil, &SQLiteError{Op: "GetSummaryHistory", TaskUID: taskUID, Err: err}
+	}
+	defer func() { _ = rows.Close() }()
+
+	var history []backend.SummaryAlias
+	for rows.Next() {
+		var summary string
+		var changedAt int64
+		if err := rows.Scan(&summary, &changedAt); err != nil {
+			return nil, &SQLiteError{Op: "GetSummaryHistory", TaskUID: taskUID, Err: err}
+		}
+		if err := sb.openContent(db, &summary); err != nil {
+			return nil, &SQLiteError{Op: "GetSummaryHistory", TaskUID: taskUID, Err: err}
+		}
+		history = append(history, backend.SummaryAlias{
+			Summary:   summary,
+			ChangedAt: time.Unix(changedAt, 0),
+		})
+	}
+
+	return history, rows.Err()
+}
+
+// findTasksByFormerSummary returns tasks whose former summaries match the search term
+func (sb *SQLiteBackend) findTasksByFormerSummary(db *Database, listID, summary string) ([]backend.Task, error) {
+	query := `
+		SELECT internal_id, uid, list_id, summary, description, status, priority,
+		       created_at, modified_at, due_date, start_date, completed_at,
+		       parent_uid, categories, sequence, recurrence, all_day
+		FROM tasks
+		WHERE backend_name = ? AND list_id = ? AND internal_id IN (
+		    SELECT task_internal_id FROM task_aliases
+		    WHERE LOWER(former_summary) LIKE LOWER(?) ESCAPE '\'
+		)
+		ORDER BY priority ASC, created_at DESC
+	`
+
+	rows, err := db.Query(query, sb.backendName, listID, "%"+likeEscaper.Replace(summary)+"%")
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	return sb.scanTasks(rows)
+}
@@ -0,0 +1,132 @@
+package sqlite
+
+import (
+	"gosynctasks/backend"
+	"testing"
+)
+
+// renameTask updates a task's summary through the public API
+func renameTask(t *testing.T, sb *SQLiteBackend, listID, uid, summary string) {
+	t.Helper()
+	tasks, err := sb.GetTasks(listID, nil)
+	if err != nil {
+		t.Fatalf("GetTasks() error = %v", err)
+	}
+	for _, task := range tasks {
+		if task.UID == uid {
+			task.Summary = summary
+			if err := sb.UpdateTask(listID, task); err != nil {
+				t.Fatalf("UpdateTask() error = %v", err)
+			}
+			return
+		}
+	}
+	t.Fatalf("task %s not found", uid)
+}
+
+// TestSummaryHistoryRecordedOnRename tests that renames are recorded most recent first
+func TestSummaryHistoryRecordedOnRename(t *testing.T) {
+	sb, cleanup := createTestSQLiteBackend(t)
+	defer cleanup()
+
+	listID, _ := sb.CreateTaskList("Test List", "", "")
+	uid, _ := sb.AddTask(listID, backend.Task{Summary: "Draft", Status: "NEEDS-ACTION"})
+
+	// Updating without renaming records nothing
+	renameTask(t, sb, listID, uid, "Draft")
+	renameTask(t, sb, listID, uid, "Review")
+	renameTask(t, sb, listID, uid, "Publish")
+
+	history, err := sb.GetSummaryHistory(uid)
+	if err != nil {
+		t.Fatalf("GetSummaryHistory() error = %v", err)
+	}
+	if len(history) != 2 {
+		t.Fatalf("Expected 2 aliases, got %d: %v", len(history), history)
+	}
+	if history[0].Summary != "Review" || history[1].Summary != "Draft" {
+		t.Errorf("Unexpected history order: %v", history)
+	}
+}
+
+// TestSummaryHistoryKeepsLastThree tests that only the most recent aliases are retained
+func TestSummaryHistoryKeepsLastThree(t *testing.T) {
+	sb, cleanup := createTestSQLiteBackend(t)
+	defer cleanup()
+
+	listID, _ := sb.CreateTaskList("Test List", "", "")
+	uid, _ := sb.AddTask(listID, backend.Task{Summary: "v1", Status: "NEEDS-ACTION"})
+
+	for _, name := range []string{"v2", "v3", "v4", "v5"} {
+		renameTask(t, sb, listID, uid, name)
+	}
+
+	history, err := sb.GetSummaryHistory(uid)
+	if err != nil {
+		t.Fatalf("GetSummaryHistory() error = %v", err)
+	}
+	if len(history) != MaxSummaryAliases {
+		t.Fatalf("Expected %d aliases, got %d", MaxSummaryAliases, len(history))
+	}
+	want := []string{"v4", "v3", "v2"}
+	for i, alias := range history {
+		if alias.Summary != want[i] {
+			t.Errorf("history[%d] = %q, want %q", i, alias.Summary, want[i])
+		}
+	}
+}
+
+// TestFindTasksBySummaryFallsBackToAliases tests matching by a former name
+func TestFindTasksBySummaryFallsBackToAliases(t *testing.T) {
+	sb, cleanup := createTestSQLiteBackend(t)
+	defer cleanup()
+
+	listID, _ := sb.CreateTaskList("Test List", "", "")
+	uid, _ := sb.AddTask(listID, backend.Task{Summary: "Release v2", Status: "NEEDS-ACTION"})
+	sb.AddTask(listID, backend.Task{Summary: "Unrelated", Status: "NEEDS-ACTION"})
+	renameTask(t, sb, listID, uid, "Ship 2.0")
+
+	tasks, err := sb.FindTasksBySummary(listID, "release")
+	if err != nil {
+		t.Fatalf("FindTasksBySummary() error = %v", err)
+	}
+	if len(tasks) != 1 || tasks[0].UID != uid {
+		t.Fatalf("Expected renamed task %s, got %v", uid, tasks)
+	}
+
+	// Current names take precedence over aliases
+	renamed, _ := sb.AddTask(listID, backend.Task{Summary: "Release notes", Status: "NEEDS-ACTION"})
+	tasks, err = sb.FindTasksBySummary(listID, "release")
+	if err != nil {
+		t.Fatalf("FindTasksBySummary() error = %v", err)
+	}
+	if len(tasks) != 1 || tasks[0].UID != renamed {
+		t.Errorf("Expected only current-name match %s, got %v", renamed, tasks)
+	}
+}
+
+// TestSummaryAliasesDeletedWithTask tests that alias rows are garbage-collected with the task
+func TestSummaryAliasesDeletedWithTask(t *testing.T) {
+	sb, cleanup := createTestSQLiteBackend(t)
+	defer cleanup()
+
+	listID, _ := sb.CreateTaskList("Test List", "", "")
+	uid, _ := sb.AddTask(listID, backend.Task{Summary: "Old", Status: "NEEDS-ACTION"})
+	renameTask(t, sb, listID, uid, "New")
+
+	db, err := sb.GetDB()
+	if err != nil {
+		t.Fatalf("GetDB() error = %v", err)
+	}
+	if _, err := db.Exec("DELETE FROM tasks WHERE uid = ?", uid); err != nil {
+		t.Fatalf("Failed to delete task: %v", err)
+	}
+
+	var count int
+	if err := db.QueryRow("SELECT COUNT(*) FROM task_aliases").Scan(&count); err != nil {
+		t.Fatalf("Failed to count aliases: %v", err)
+	}
+	if count != 0 {
+		t.Errorf("Expected aliases to be deleted with task, got %d", count)
+	}
+}
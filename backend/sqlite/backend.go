@@ -2,8 +2,10 @@ package sqlite
 
 import (
 	"gosynctasks/backend"
+	"gosynctasks/backend/statuskit"
 	"database/sql"
 	"fmt"
+	"slices"
 	"strings"
 	"time"
 
@@ -96,53 +98,73 @@ func (sb *SQLiteBackend) Close() error {
 	return nil
 }
 
-// GetTaskLists retrieves all task lists from local storage
+// GetTaskLists retrieves all task lists from local storage, except the
+// lists archived on the remote
 func (sb *SQLiteBackend) GetTaskLists() ([]backend.TaskList, error) {
+	return sb.getTaskLists("GetTaskLists", false)
+}
+
+// getTaskLists retrieves the archived or the active task lists
+func (sb *SQLiteBackend) getTaskLists(op string, archived bool) ([]backend.TaskList, error) {
 	db, err := sb.GetDB()
 	if err != nil {
-		return nil, &SQLiteError{Op: "GetTaskLists", Err: err}
+		return nil, &SQLiteError{Op: op, Err: err}
 	}
 
 	query := `
-		SELECT list_id, list_name, list_color, last_ctag, created_at, modified_at
-		FROM list_sync_metadata
-		WHERE backend_name = ?
-		ORDER BY list_name ASC
+		SELECT l.list_id, l.list_name, l.list_path, l.list_color, l.last_ctag, l.list_position, l.created_at,
+			MAX(COALESCE(l.modified_at, 0), COALESCE((
+				SELECT MAX(t.modified_at) FROM tasks t
+				WHERE t.backend_name = l.backend_name AND t.list_id = l.list_id
+			), 0))
+		FROM list_sync_metadata l
+		WHERE l.backend_name = ? AND l.archived = ?
+		ORDER BY l.list_name ASC
 	`
 
-	rows, err := db.Query(query, sb.backendName)
+	rows, err := db.Query(query, sb.backendName, archived)
 	if err != nil {
-		return nil, &SQLiteError{Op: "GetTaskLists", Err: err}
+		return nil, &SQLiteError{Op: op, Err: err}
 	}
 	defer func() { _ = rows.Close() }()
 
 	var lists []backend.TaskList
 	for rows.Next() {
 		var list backend.TaskList
-		var createdAt, modifiedAt sql.NullInt64
-		var ctag sql.NullString
+		var position, createdAt, modifiedAt sql.NullInt64
+		var ctag, path sql.NullString
 
 		err := rows.Scan(
 			&list.ID,
 			&list.Name,
+			&path,
 			&list.Color,
 			&ctag,
+			&position,
 			&createdAt,
-			&modifiedAt,
+			&modifiedAt, // Latest change to the list or its tasks
 		)
 		if err != nil {
-			return nil, &SQLiteError{Op: "GetTaskLists", Err: err}
+			return nil, &SQLiteError{Op: op, Err: err}
 		}
 
 		if ctag.Valid {
 			list.CTags = ctag.String
 		}
+		if path.Valid {
+			list.Path = path.String
+		}
+		list.Position = int(position.Int64)
+		list.Archived = archived
+		if modifiedAt.Int64 > 0 {
+			list.Modified = time.Unix(modifiedAt.Int64, 0)
+		}
 
 		lists = append(lists, list)
 	}
 
 	if err = rows.Err(); err != nil {
-		return nil, &SQLiteError{Op: "GetTaskLists", Err: err}
+		return nil, &SQLiteError{Op: op, Err: err}
 	}
 
 	return lists, nil
@@ -157,21 +179,16 @@ func (sb *SQLiteBackend) GetTasks(listID string, taskFilter *backend.TaskFilter)
 
 	// Build query with filters
 	// LEFT JOIN with sync_metadata to filter out locally_deleted tasks
-	query := `
-		SELECT t.internal_id, t.uid, t.list_id, t.summary, t.description, t.status, t.priority,
-		       t.created_at, t.modified_at, t.due_date, t.start_date, t.completed_at,
-		       t.parent_uid, t.categories
-		FROM tasks t
-		LEFT JOIN sync_metadata sm ON t.internal_id = sm.task_internal_id AND t.backend_name = sm.backend_name
-		WHERE t.backend_name = ? AND t.list_id = ?
-		  AND (sm.locally_deleted IS NULL OR sm.locally_deleted = 0)
-	`
-
 	args := []interface{}{sb.backendName, listID}
-	query, args = sb.applyFilters(query, args, taskFilter)
+	query, args := sb.applyFilters(selectTasksByListSQL, args, taskFilter)
 	query += " ORDER BY t.priority ASC, t.created_at DESC"
 
-	rows, err := db.Query(query, args...)
+	stmt, err := db.prepared(query)
+	if err != nil {
+		return nil, &SQLiteError{Op: "GetTasks", ListID: listID, Err: err}
+	}
+
+	rows, err := stmt.Query(args...)
 	if err != nil {
 		return nil, &SQLiteError{Op: "GetTasks", ListID: listID, Err: err}
 	}
@@ -182,9 +199,40 @@ func (sb *SQLiteBackend) GetTasks(listID string, taskFilter *backend.TaskFilter)
 		return nil, &SQLiteError{Op: "GetTasks", ListID: listID, Err: err}
 	}
 
+	// Summaries are matched once opened, encrypted lists' included
+	if taskFilter != nil && taskFilter.SummaryContains != nil {
+		tasks = slices.DeleteFunc(tasks, func(task backend.Task) bool {
+			return !taskFilter.MatchesSummary(task.Summary)
+		})
+	}
+
 	return tasks, nil
 }
 
+// GetTask retrieves one task of a list by UID
+func (sb *SQLiteBackend) GetTask(listID, taskUID string) (*backend.Task, error) {
+	db, err := sb.GetDB()
+	if err != nil {
+		return nil, &SQLiteError{Op: "GetTask", ListID: listID, TaskUID: taskUID, Err: err}
+	}
+
+	rows, err := db.Query(selectTasksByListSQL+" AND t.uid = ?", sb.backendName, listID, taskUID)
+	if err != nil {
+		return nil, &SQLiteError{Op: "GetTask", ListID: listID, TaskUID: taskUID, Err: err}
+	}
+	defer func() { _ = rows.Close() }()
+
+	tasks, err := sb.scanTasks(rows)
+	if err != nil {
+		return nil, &SQLiteError{Op: "GetTask", ListID: listID, TaskUID: taskUID, Err: err}
+	}
+	if len(tasks) == 0 {
+		return nil, backend.NewNotFoundError("GetTask", listID, taskUID)
+	}
+
+	return &tasks[0], nil
+}
+
 // applyFilters adds WHERE clauses for task filtering
 func (sb *SQLiteBackend) applyFilters(query string, args []interface{}, filter *backend.TaskFilter) (string, []interface{}) {
 	if filter == nil {
@@ -231,7 +279,51 @@ func (sb *SQLiteBackend) applyFilters(query string, args []interface{}, filter *
 		args = append(args, filter.CreatedAfter.Unix())
 	}
 
-	// Priority filter (if we add it to backend.TaskFilter in future)
+	// Modified date filters
+	if filter.ModifiedBefore != nil {
+		query += " AND t.modified_at <= ?"
+		args = append(args, filter.ModifiedBefore.Unix())
+	}
+	if filter.ModifiedAfter != nil {
+		query += " AND t.modified_at >= ?"
+		args = append(args, filter.ModifiedAfter.Unix())
+	}
+
+	// Completed date filter (tasks that were never completed are kept)
+	if filter.CompletedAfter != nil {
+		query += " AND (t.completed_at IS NULL OR t.completed_at >= ?)"
+		args = append(args, filter.CompletedAfter.Unix())
+	}
+
+	// Parent filter; an empty parent UID selects root tasks
+	if filter.ParentUID != nil {
+		if *filter.ParentUID == "" {
+			query += " AND (t.parent_uid IS NULL OR t.parent_uid = '')"
+		} else {
+			query += " AND t.parent_uid = ?"
+			args = append(args, *filter.ParentUID)
+		}
+	}
+
+	// Priority filters
+	if filter.PriorityMin != nil {
+		query += " AND t.priority >= ?"
+		args = append(args, *filter.PriorityMin)
+	}
+	if filter.PriorityMax != nil {
+		query += " AND t.priority <= ?"
+		args = append(args, *filter.PriorityMax)
+	}
+
+	// Overdue filter: open tasks past their due date
+	if filter.Overdue {
+		// An all-day task is due until the end of its day
+		now := time.Now()
+		today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+		query += " AND t.due_date < (CASE WHEN t.all_day = 1 THEN ? ELSE ? END) AND t.status NOT IN ('COMPLETED', 'DONE', 'CANCELLED')"
+		args = append(args, today.Unix(), now.Unix())
+	}
+
 	// Categories filter would need LIKE queries for the categories TEXT field
 
 	return query, args
@@ -239,19 +331,26 @@ func (sb *SQLiteBackend) applyFilters(query string, args []interface{}, filter *
 
 // scanTasks scans task rows from a query result
 func (sb *SQLiteBackend) scanTasks(rows *sql.Rows) ([]backend.Task, error) {
+	tasks, _, err := sb.scanListTasks(rows)
+	return tasks, err
+}
+
+// scanListTasks is scanTasks also returning the list of each task
+func (sb *SQLiteBackend) scanListTasks(rows *sql.Rows) ([]backend.Task, []string, error) {
 	var tasks []backend.Task
+	var listIDs []string
 
 	for rows.Next() {
 		var task backend.Task
 		var internalID int64
-		var listID string // Temporary variable for list_id (not stored in backend.Task struct)
-		var description, parentUID, categories sql.NullString
+		var listID string // Not stored in backend.Task
+		var description, parentUID, categories, recurrence sql.NullString
 		var createdAt, modifiedAt, dueDate, startDate, completedAt sql.NullInt64
 
 		err := rows.Scan(
 			&internalID, // Scan internal_id but don't store in backend.Task
 			&task.UID,
-			&listID, // Scan list_id but don't store in backend.Task
+			&listID,
 			&task.Summary,
 			&description,
 			&task.Status,
@@ -263,9 +362,15 @@ func (sb *SQLiteBackend) scanTasks(rows *sql.Rows) ([]backend.Task, error) {
 			&completedAt,
 			&parentUID,
 			&categories,
+			&task.Sequence,
+			&recurrence,
+			&task.AllDay,
 		)
 		if err != nil {
-			return nil, err
+			return nil, nil, err
+		}
+		if err := sb.openContent(sb.db, &task.Summary, &description.String, &categories.String); err != nil {
+			return nil, nil, err
 		}
 
 		// Handle nullable fields
@@ -278,6 +383,7 @@ func (sb *SQLiteBackend) scanTasks(rows *sql.Rows) ([]backend.Task, error) {
 		if categories.Valid && categories.String != "" {
 			task.Categories = strings.Split(categories.String, ",")
 		}
+		task.Recurrence = recurrence.String
 
 		// Convert timestamps
 		if createdAt.Valid {
@@ -300,31 +406,53 @@ func (sb *SQLiteBackend) scanTasks(rows *sql.Rows) ([]backend.Task, error) {
 		}
 
 		tasks = append(tasks, task)
+		listIDs = append(listIDs, listID)
 	}
 
-	return tasks, rows.Err()
+	return tasks, listIDs, rows.Err()
 }
 
-// FindTasksBySummary searches for tasks by summary (case-insensitive)
+// likeEscaper escapes LIKE wildcards for use with ESCAPE '\'
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+// FindTasksBySummary searches for tasks by summary (case-insensitive). A
+// query of several words not found as is goes through SearchTasks.
 func (sb *SQLiteBackend) FindTasksBySummary(listID string, summary string) ([]backend.Task, error) {
 	db, err := sb.GetDB()
 	if err != nil {
 		return nil, &SQLiteError{Op: "FindTasksBySummary", ListID: listID, Err: err}
 	}
 
+	// Encrypted content can't be matched in SQL
+	encrypted, err := sb.isListEncrypted(db, listID)
+	if err != nil {
+		return nil, &SQLiteError{Op: "FindTasksBySummary", ListID: listID, Err: err}
+	}
+	if encrypted {
+		tasks, err := sb.findEncryptedTasks(db, listID, summary)
+		if err != nil {
+			return nil, &SQLiteError{Op: "FindTasksBySummary", ListID: listID, Err: err}
+		}
+		if len(tasks) == 0 && len(strings.Fields(summary)) > 1 {
+			return sb.SearchTasks(listID, summary)
+		}
+		return tasks, nil
+	}
+
 	query := `
 		SELECT internal_id, uid, list_id, summary, description, status, priority,
 		       created_at, modified_at, due_date, start_date, completed_at,
-		       parent_uid, categories
+		       parent_uid, categories, sequence, recurrence, all_day
 		FROM tasks
-		WHERE backend_name = ? AND list_id = ? AND LOWER(summary) LIKE LOWER(?)
+		WHERE backend_name = ? AND list_id = ? AND LOWER(summary) LIKE LOWER(?) ESCAPE '\'
 		ORDER BY
 			CASE WHEN LOWER(summary) = LOWER(?) THEN 0 ELSE 1 END,
 			priority ASC,
 			created_at DESC
 	`
 
-	searchPattern := "%" + summary + "%"
+	// % and _ in a summary are literal characters, not wildcards
+	searchPattern := "%" + likeEscaper.Replace(summary) + "%"
 	rows, err := db.Query(query, sb.backendName, listID, searchPattern, summary)
 	if err != nil {
 		return nil, &SQLiteError{Op: "FindTasksBySummary", ListID: listID, Err: err}
@@ -336,15 +464,94 @@ func (sb *SQLiteBackend) FindTasksBySummary(listID string, summary string) ([]ba
 		return nil, &SQLiteError{Op: "FindTasksBySummary", ListID: listID, Err: err}
 	}
 
+	// Words of a summary may be apart, or in the description or tags
+	if len(tasks) == 0 && len(strings.Fields(summary)) > 1 {
+		if tasks, err = sb.SearchTasks(listID, summary); err != nil {
+			return nil, err
+		}
+	}
+
+	// Fall back to former summaries so renamed tasks can still be found by their old name
+	if len(tasks) == 0 {
+		tasks, err = sb.findTasksByFormerSummary(db, listID, summary)
+		if err != nil {
+			return nil, &SQLiteError{Op: "FindTasksBySummary", ListID: listID, Err: err}
+		}
+	}
+
 	return tasks, nil
 }
 
+// SearchAllLists searches the summaries of every list in one query, ordered
+// as FindTasksBySummary orders them, and returns the matches by list ID.
+// Encrypted lists are searched one at a time, as their summaries can't be
+// matched in SQL. Former summaries are not searched.
+func (sb *SQLiteBackend) SearchAllLists(summary string) (map[string][]backend.Task, error) {
+	db, err := sb.GetDB()
+	if err != nil {
+		return nil, &SQLiteError{Op: "SearchAllLists", Err: err}
+	}
+
+	rows, err := db.Query(`
+		SELECT internal_id, uid, list_id, summary, description, status, priority,
+		       created_at, modified_at, due_date, start_date, completed_at,
+		       parent_uid, categories, sequence, recurrence, all_day
+		FROM tasks
+		WHERE backend_name = ? AND LOWER(summary) LIKE LOWER(?) ESCAPE '\'
+		  AND list_id NOT IN (SELECT list_id FROM encrypted_lists WHERE backend_name = ?)
+		ORDER BY
+			CASE WHEN LOWER(summary) = LOWER(?) THEN 0 ELSE 1 END,
+			priority ASC,
+			created_at DESC
+	`, sb.backendName, "%"+likeEscaper.Replace(summary)+"%", sb.backendName, summary)
+	if err != nil {
+		return nil, &SQLiteError{Op: "SearchAllLists", Err: err}
+	}
+	tasks, listIDs, err := sb.scanListTasks(rows)
+	_ = rows.Close()
+	if err != nil {
+		return nil, &SQLiteError{Op: "SearchAllLists", Err: err}
+	}
+
+	matches := make(map[string][]backend.Task)
+	for i, task := range tasks {
+		matches[listIDs[i]] = append(matches[listIDs[i]], task)
+	}
+
+	encrypted, err := sb.EncryptedLists()
+	if err != nil {
+		return nil, err
+	}
+	for _, listID := range encrypted {
+		tasks, err := sb.findEncryptedTasks(db, listID, summary)
+		if err != nil {
+			return nil, &SQLiteError{Op: "SearchAllLists", ListID: listID, Err: err}
+		}
+		if len(tasks) > 0 {
+			matches[listID] = tasks
+		}
+	}
+	return matches, nil
+}
+
 // AddTask creates a new task in the database
 func (sb *SQLiteBackend) AddTask(listID string, task backend.Task) (string, error) {
+	if err := backend.ValidateListID("AddTask", listID); err != nil {
+		return "", err
+	}
+
 	db, err := sb.GetDB()
 	if err != nil {
 		return "", &SQLiteError{Op: "AddTask", ListID: listID, Err: err}
 	}
+	s, err := sb.sealerFor(db, listID)
+	if err != nil {
+		return "", &SQLiteError{Op: "AddTask", ListID: listID, Err: err}
+	}
+	content, err := s.sealTask(task)
+	if err != nil {
+		return "", &SQLiteError{Op: "AddTask", ListID: listID, Err: err}
+	}
 
 	// Start transaction
 	tx, err := db.Begin()
@@ -361,25 +568,25 @@ func (sb *SQLiteBackend) AddTask(listID string, task backend.Task) (string, erro
 	if task.Modified.IsZero() {
 		task.Modified = now
 	}
+	if task.Completed == nil && backend.IsCompletedStatus(task.Status) {
+		task.Completed = &now
+	}
 
 	// Insert task with temporary UID - we'll update it after getting the internal_id
 	// Use "pending-temp" as placeholder since we need internal_id first
 	tempUID := "pending-temp-" + fmt.Sprintf("%d", now.UnixNano())
 
-	query := `
-		INSERT INTO tasks (
-			uid, backend_name, list_id, summary, description, status, priority,
-			created_at, modified_at, due_date, start_date, completed_at,
-			parent_uid, categories
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
-	`
+	insertStmt, err := db.txStmt(tx, insertTaskSQL)
+	if err != nil {
+		return "", &SQLiteError{Op: "AddTask", ListID: listID, Err: err}
+	}
 
-	result, err := tx.Exec(query,
+	result, err := insertStmt.Exec(
 		tempUID,
 		sb.backendName,
 		listID,
-		task.Summary,
-		NullString(task.Description),
+		content.summary,
+		content.description,
 		task.Status,
 		task.Priority,
 		TimeValueToNullInt64(task.Created),
@@ -388,7 +595,10 @@ func (sb *SQLiteBackend) AddTask(listID string, task backend.Task) (string, erro
 		TimeToNullInt64(task.StartDate),
 		TimeToNullInt64(task.Completed),
 		NullString(task.ParentUID),
-		NullString(strings.Join(task.Categories, ",")),
+		content.categories,
+		task.Sequence,
+		NullString(task.Recurrence),
+		task.AllDay,
 	)
 	if err != nil {
 		return "", &SQLiteError{Op: "AddTask", ListID: listID, Err: err}
@@ -418,11 +628,7 @@ func (sb *SQLiteBackend) AddTask(listID string, task backend.Task) (string, erro
 	}
 
 	// Queue sync operation using internal_id
-	_, err = tx.Exec(`
-		INSERT OR REPLACE INTO sync_queue (backend_name, task_internal_id, list_id, operation, created_at)
-		VALUES (?, ?, ?, 'create', ?)
-	`, sb.backendName, internalID, listID, now.Unix())
-	if err != nil {
+	if err := sb.queueOperation(db, tx, internalID, listID, "create", now.Unix()); err != nil {
 		return "", &SQLiteError{Op: "AddTask", ListID: listID, Err: err}
 	}
 
@@ -435,10 +641,22 @@ func (sb *SQLiteBackend) AddTask(listID string, task backend.Task) (string, erro
 
 // UpdateTask updates an existing task
 func (sb *SQLiteBackend) UpdateTask(listID string, task backend.Task) error {
+	if err := backend.ValidateTaskRef("UpdateTask", listID, task.UID); err != nil {
+		return err
+	}
+
 	db, err := sb.GetDB()
 	if err != nil {
 		return &SQLiteError{Op: "UpdateTask", ListID: listID, TaskUID: task.UID, Err: err}
 	}
+	s, err := sb.sealerFor(db, listID)
+	if err != nil {
+		return &SQLiteError{Op: "UpdateTask", ListID: listID, TaskUID: task.UID, Err: err}
+	}
+	content, err := s.sealTask(task)
+	if err != nil {
+		return &SQLiteError{Op: "UpdateTask", ListID: listID, TaskUID: task.UID, Err: err}
+	}
 
 	// Start transaction
 	tx, err := db.Begin()
@@ -447,32 +665,41 @@ func (sb *SQLiteBackend) UpdateTask(listID string, task backend.Task) error {
 	}
 	defer func() { _ = tx.Rollback() }()
 
-	// Get internal_id for this task
+	// Get internal_id and current summary for this task
 	var internalID int64
-	err = tx.QueryRow("SELECT internal_id FROM tasks WHERE backend_name = ? AND uid = ? AND list_id = ?",
-		sb.backendName, task.UID, listID).Scan(&internalID)
+	var oldSummary string
+	err = tx.QueryRow("SELECT internal_id, summary FROM tasks WHERE backend_name = ? AND uid = ? AND list_id = ?",
+		sb.backendName, task.UID, listID).Scan(&internalID, &oldSummary)
 	if err == sql.ErrNoRows {
 		return backend.NewBackendError("UpdateTask", 404, fmt.Sprintf("task %s not found in list %s", task.UID, listID))
 	} else if err != nil {
 		return &SQLiteError{Op: "UpdateTask", ListID: listID, TaskUID: task.UID, Err: err}
 	}
 
+	// Remember the previous summary if the task is being renamed
+	if oldSummary, err = s.open(oldSummary); err != nil {
+		return &SQLiteError{Op: "UpdateTask", ListID: listID, TaskUID: task.UID, Err: err}
+	}
+	if err := recordSummaryAlias(tx, s, internalID, oldSummary, task.Summary); err != nil {
+		return &SQLiteError{Op: "UpdateTask", ListID: listID, TaskUID: task.UID, Err: err}
+	}
+
 	// Update modified timestamp
 	now := time.Now()
 	task.Modified = now
+	if task.Completed == nil && backend.IsCompletedStatus(task.Status) {
+		task.Completed = &now
+	}
 
 	// Update task
-	query := `
-		UPDATE tasks
-		SET summary = ?, description = ?, status = ?, priority = ?,
-		    modified_at = ?, due_date = ?, start_date = ?, completed_at = ?,
-		    parent_uid = ?, categories = ?
-		WHERE backend_name = ? AND uid = ? AND list_id = ?
-	`
+	updateStmt, err := db.txStmt(tx, updateTaskSQL)
+	if err != nil {
+		return &SQLiteError{Op: "UpdateTask", ListID: listID, TaskUID: task.UID, Err: err}
+	}
 
-	result, err := tx.Exec(query,
-		task.Summary,
-		NullString(task.Description),
+	result, err := updateStmt.Exec(
+		content.summary,
+		content.description,
 		task.Status,
 		task.Priority,
 		TimeValueToNullInt64(task.Modified),
@@ -480,7 +707,10 @@ func (sb *SQLiteBackend) UpdateTask(listID string, task backend.Task) error {
 		TimeToNullInt64(task.StartDate),
 		TimeToNullInt64(task.Completed),
 		NullString(task.ParentUID),
-		NullString(strings.Join(task.Categories, ",")),
+		content.categories,
+		task.Sequence,
+		NullString(task.Recurrence),
+		task.AllDay,
 		sb.backendName,
 		task.UID,
 		listID,
@@ -509,19 +739,87 @@ func (sb *SQLiteBackend) UpdateTask(listID string, task backend.Task) error {
 	}
 
 	// Queue sync operation using internal_id
-	_, err = tx.Exec(`
-		INSERT OR REPLACE INTO sync_queue (backend_name, task_internal_id, list_id, operation, created_at)
-		VALUES (?, ?, ?, 'update', ?)
-	`, sb.backendName, internalID, listID, now.Unix())
-	if err != nil {
+	if err := sb.queueOperation(db, tx, internalID, listID, "update", now.Unix()); err != nil {
 		return &SQLiteError{Op: "UpdateTask", ListID: listID, TaskUID: task.UID, Err: err}
 	}
 
 	return tx.Commit()
 }
 
+// queueOperation records a pending sync operation for a task inside tx,
+// collapsing it with the operations already queued for the task:
+//   - an update after a create is folded into the create, which pushes the
+//     task's current state; an update after an update replaces it
+//   - a delete replaces queued creates and updates, and a delete of a task
+//     that never reached the remote removes the task outright
+//
+// A queued create is only treated as unpushed while the task still has its
+// "pending-" UID; once the remote assigned a UID the create went through and
+// a leftover queue entry for it is dropped.
+func (sb *SQLiteBackend) queueOperation(db *Database, tx *sql.Tx, internalID int64, listID, operation string, at int64) error {
+	var uid string
+	var createQueued bool
+	err := tx.QueryRow(`
+		SELECT t.uid, EXISTS(
+			SELECT 1 FROM sync_queue sq
+			WHERE sq.backend_name = ? AND sq.task_internal_id = t.internal_id AND sq.operation = 'create'
+		)
+		FROM tasks t WHERE t.internal_id = ?
+	`, sb.backendName, internalID).Scan(&uid, &createQueued)
+	if err != nil {
+		return err
+	}
+	unpushed := createQueued && strings.HasPrefix(uid, "pending-")
+
+	switch operation {
+	case "update":
+		if unpushed {
+			return nil
+		}
+		if createQueued {
+			if err := sb.dequeueOperations(tx, internalID, "create"); err != nil {
+				return err
+			}
+		}
+	case "delete":
+		if unpushed {
+			// Nothing to delete on the remote; this cascades to the queue and metadata
+			_, err := tx.Exec("DELETE FROM tasks WHERE internal_id = ?", internalID)
+			return err
+		}
+		if err := sb.dequeueOperations(tx, internalID, "create", "update"); err != nil {
+			return err
+		}
+	}
+
+	stmt, err := db.txStmt(tx, queueOperationSQL)
+	if err != nil {
+		return err
+	}
+	_, err = stmt.Exec(sb.backendName, internalID, listID, operation, at)
+	return err
+}
+
+// dequeueOperations removes the task's queued operations of the given kinds inside tx
+func (sb *SQLiteBackend) dequeueOperations(tx *sql.Tx, internalID int64, operations ...string) error {
+	for _, operation := range operations {
+		_, err := tx.Exec(`
+			DELETE FROM sync_queue
+			WHERE backend_name = ? AND task_internal_id = ? AND operation = ?
+		`, sb.backendName, internalID, operation)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // DeleteTask removes a task from the database
 func (sb *SQLiteBackend) DeleteTask(listID string, taskUID string) error {
+	if err := backend.ValidateTaskRef("DeleteTask", listID, taskUID); err != nil {
+		return err
+	}
+
 	db, err := sb.GetDB()
 	if err != nil {
 		return &SQLiteError{Op: "DeleteTask", ListID: listID, TaskUID: taskUID, Err: err}
@@ -534,10 +832,15 @@ func (sb *SQLiteBackend) DeleteTask(listID string, taskUID string) error {
 	}
 	defer func() { _ = tx.Rollback() }()
 
-	// Get internal_id for this task
+	// Get internal_id for this task; a task already deleted locally is gone
 	var internalID int64
-	err = tx.QueryRow("SELECT internal_id FROM tasks WHERE backend_name = ? AND uid = ? AND list_id = ?",
-		sb.backendName, taskUID, listID).Scan(&internalID)
+	err = tx.QueryRow(`
+		SELECT t.internal_id
+		FROM tasks t
+		LEFT JOIN sync_metadata sm ON t.internal_id = sm.task_internal_id AND t.backend_name = sm.backend_name
+		WHERE t.backend_name = ? AND t.uid = ? AND t.list_id = ?
+		  AND (sm.locally_deleted IS NULL OR sm.locally_deleted = 0)
+	`, sb.backendName, taskUID, listID).Scan(&internalID)
 	if err == sql.ErrNoRows {
 		return backend.NewBackendError("DeleteTask", 404, fmt.Sprintf("task %s not found in list %s", taskUID, listID))
 	} else if err != nil {
@@ -556,11 +859,7 @@ func (sb *SQLiteBackend) DeleteTask(listID string, taskUID string) error {
 	}
 
 	// Queue delete operation using internal_id
-	_, err = tx.Exec(`
-		INSERT OR REPLACE INTO sync_queue (backend_name, task_internal_id, list_id, operation, created_at)
-		VALUES (?, ?, ?, 'delete', ?)
-	`, sb.backendName, internalID, listID, now)
-	if err != nil {
+	if err := sb.queueOperation(db, tx, internalID, listID, "delete", now); err != nil {
 		return &SQLiteError{Op: "DeleteTask", ListID: listID, TaskUID: taskUID, Err: err}
 	}
 
@@ -675,37 +974,12 @@ func (sb *SQLiteBackend) PermanentlyDeleteTaskList(listID string) error {
 
 // ParseStatusFlag converts status abbreviations to backend status format
 func (sb *SQLiteBackend) ParseStatusFlag(statusFlag string) (string, error) {
-	// SQLite uses standard CalDAV status names
-	flag := strings.ToUpper(statusFlag)
-
-	switch flag {
-	case "T", "TODO", "NEEDS-ACTION":
-		return "NEEDS-ACTION", nil
-	case "D", "DONE", "COMPLETED":
-		return "COMPLETED", nil
-	case "P", "PROCESSING", "IN-PROCESS":
-		return "IN-PROCESS", nil
-	case "C", "CANCELLED":
-		return "CANCELLED", nil
-	default:
-		return "", fmt.Errorf("invalid status flag: %s (valid: T/TODO, D/DONE, P/PROCESSING, C/CANCELLED)", statusFlag)
-	}
+	return statuskit.CalDAV.ParseStatusFlag(statusFlag)
 }
 
 // StatusToDisplayName converts backend status to display name
 func (sb *SQLiteBackend) StatusToDisplayName(backendStatus string) string {
-	switch strings.ToUpper(backendStatus) {
-	case "NEEDS-ACTION":
-		return "TODO"
-	case "COMPLETED":
-		return "DONE"
-	case "IN-PROCESS":
-		return "PROCESSING"
-	case "CANCELLED":
-		return "CANCELLED"
-	default:
-		return backendStatus
-	}
+	return statuskit.CalDAV.StatusToDisplayName(backendStatus)
 }
 
 // SortTasks sorts tasks by priority (1=highest, 0=undefined goes last)
@@ -727,15 +1001,7 @@ func (sb *SQLiteBackend) SortTasks(tasks []backend.Task) {
 
 // GetPriorityColor returns ANSI color code for priority
 func (sb *SQLiteBackend) GetPriorityColor(priority int) string {
-	// Use same color scheme as Nextcloud
-	if priority >= 1 && priority <= 4 {
-		return "\033[31m" // Red (high priority)
-	} else if priority == 5 {
-		return "\033[33m" // Yellow (medium priority)
-	} else if priority >= 6 && priority <= 9 {
-		return "\033[34m" // Blue (low priority)
-	}
-	return "" // No color for priority 0 (undefined)
+	return statuskit.DefaultPalette.Color(priority)
 }
 
 // GetBackendDisplayName returns a formatted string for display in task list headers
@@ -827,7 +1093,7 @@ func (sb *SQLiteBackend) GetLocallyModifiedTasks() ([]backend.Task, error) {
 	query := `
 		SELECT t.internal_id, t.uid, t.list_id, t.summary, t.description, t.status, t.priority,
 		       t.created_at, t.modified_at, t.due_date, t.start_date, t.completed_at,
-		       t.parent_uid, t.categories
+		       t.parent_uid, t.categories, t.sequence, t.recurrence, t.all_day
 		FROM tasks t
 		INNER JOIN sync_metadata sm ON t.internal_id = sm.task_internal_id AND t.backend_name = sm.backend_name
 		WHERE t.backend_name = ? AND sm.locally_modified = 1
@@ -857,54 +1123,81 @@ type SyncOperation struct {
 	CreatedAt  time.Time
 	RetryCount int
 	LastError  string
+	Stranded   bool      // The list is gone from the remote, see StrandSyncOperations
+	NextRetry  time.Time // Before it a failed operation is not pushed again; zero when due
 }
 
-// GetPendingSyncOperations retrieves operations queued for sync
+// GetPendingSyncOperations retrieves the operations queued for sync that are
+// due, leaving out failed ones waiting for their next retry
 func (sb *SQLiteBackend) GetPendingSyncOperations() ([]SyncOperation, error) {
+	return sb.getSyncOperations("GetPendingSyncOperations", true)
+}
+
+// GetQueuedSyncOperations retrieves every operation queued for sync, failed
+// ones waiting for their next retry included
+func (sb *SQLiteBackend) GetQueuedSyncOperations() ([]SyncOperation, error) {
+	return sb.getSyncOperations("GetQueuedSyncOperations", false)
+}
+
+// getSyncOperations retrieves the queued operations, only the due ones with
+// dueOnly
+func (sb *SQLiteBackend) getSyncOperations(op string, dueOnly bool) ([]SyncOperation, error) {
 	db, err := sb.GetDB()
 	if err != nil {
-		return nil, &SQLiteError{Op: "GetPendingSyncOperations", Err: err}
+		return nil, &SQLiteError{Op: op, Err: err}
 	}
 
 	query := `
-		SELECT sq.id, t.uid, sq.list_id, sq.operation, sq.created_at, sq.retry_count, sq.last_error
+		SELECT sq.id, t.uid, sq.list_id, sq.operation, sq.created_at, sq.retry_count, sq.last_error, sq.stranded, sq.next_retry_at
 		FROM sync_queue sq
 		INNER JOIN tasks t ON sq.task_internal_id = t.internal_id AND sq.backend_name = t.backend_name
-		WHERE sq.backend_name = ?
+		WHERE sq.backend_name = ?`
+	args := []any{sb.backendName}
+	if dueOnly {
+		query += ` AND (sq.next_retry_at IS NULL OR sq.next_retry_at <= ?)`
+		args = append(args, time.Now().Unix())
+	}
+	query += `
 		ORDER BY sq.created_at ASC
 	`
 
-	rows, err := db.Query(query, sb.backendName)
+	rows, err := db.Query(query, args...)
 	if err != nil {
-		return nil, &SQLiteError{Op: "GetPendingSyncOperations", Err: err}
+		return nil, &SQLiteError{Op: op, Err: err}
 	}
 	defer func() { _ = rows.Close() }()
 
 	var operations []SyncOperation
 	for rows.Next() {
-		var op SyncOperation
+		var syncOp SyncOperation
 		var createdAt int64
 		var lastError sql.NullString
+		var nextRetry sql.NullInt64
 
 		err := rows.Scan(
-			&op.ID,
-			&op.TaskUID,
-			&op.ListID,
-			&op.Operation,
+			&syncOp.ID,
+			&syncOp.TaskUID,
+			&syncOp.ListID,
+			&syncOp.Operation,
 			&createdAt,
-			&op.RetryCount,
+			&syncOp.RetryCount,
 			&lastError,
+			&syncOp.Stranded,
+			&nextRetry,
 		)
 		if err != nil {
-			return nil, &SQLiteError{Op: "GetPendingSyncOperations", Err: err}
+			return nil, &SQLiteError{Op: op, Err: err}
 		}
 
-		op.CreatedAt = time.Unix(createdAt, 0)
+		syncOp.CreatedAt = time.Unix(createdAt, 0)
 		if lastError.Valid {
-			op.LastError = lastError.String
+			syncOp.LastError = lastError.String
+		}
+		if nextRetry.Valid && nextRetry.Int64 > time.Now().Unix() {
+			syncOp.NextRetry = time.Unix(nextRetry.Int64, 0)
 		}
 
-		operations = append(operations, op)
+		operations = append(operations, syncOp)
 	}
 
 	return operations, rows.Err()
@@ -0,0 +1,90 @@
+package sqlite
+
+import (
+	"fmt"
+	"gosynctasks/backend"
+	"gosynctasks/internal/testutil"
+	"path/filepath"
+	"testing"
+)
+
+// benchTaskCount is the size of the pre-populated database used by the benchmarks
+const benchTaskCount = 10000
+
+// newBenchBackend returns a backend whose "bench" list holds benchTaskCount tasks
+func newBenchBackend(b *testing.B) *SQLiteBackend {
+	b.Helper()
+
+	sb, err := NewSQLiteBackend(backend.BackendConfig{
+		Type:    "sqlite",
+		Enabled: true,
+		DBPath:  filepath.Join(b.TempDir(), "bench.db"),
+	})
+	if err != nil {
+		b.Fatalf("Failed to create SQLite backend: %v", err)
+	}
+	b.Cleanup(func() { _ = sb.Close() })
+
+	if _, err := sb.db.Exec(`INSERT INTO list_sync_metadata (list_id, backend_name, list_name, created_at, modified_at)
+		VALUES ('bench', ?, 'Bench', 0, 0)`, sb.backendName); err != nil {
+		b.Fatalf("Failed to create list: %v", err)
+	}
+
+	// Populate in a single transaction so setup stays fast
+	tx, err := sb.db.Begin()
+	if err != nil {
+		b.Fatalf("Failed to begin: %v", err)
+	}
+	dataset := testutil.GenerateDataset(testutil.DatasetOptions{Tasks: benchTaskCount, Lists: 1, Seed: 1})
+	for i, task := range dataset.AllTasks() {
+		_, err := tx.Exec(`INSERT INTO tasks (uid, backend_name, list_id, summary, description, status, priority, created_at, modified_at)
+			VALUES (?, ?, 'bench', ?, ?, ?, ?, ?, ?)`,
+			fmt.Sprintf("task-%d", i), sb.backendName, task.Summary, task.Description, task.Status, task.Priority, task.Created.Unix(), task.Modified.Unix())
+		if err != nil {
+			b.Fatalf("Failed to insert task: %v", err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		b.Fatalf("Failed to commit: %v", err)
+	}
+
+	return sb
+}
+
+func BenchmarkAddTask(b *testing.B) {
+	sb := newBenchBackend(b)
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		if _, err := sb.AddTask("bench", backend.Task{Summary: "New task", Status: "NEEDS-ACTION"}); err != nil {
+			b.Fatalf("AddTask() error = %v", err)
+		}
+	}
+}
+
+func BenchmarkUpdateTask(b *testing.B) {
+	sb := newBenchBackend(b)
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		task := backend.Task{UID: fmt.Sprintf("task-%d", i%benchTaskCount), Summary: "Task", Status: "NEEDS-ACTION"}
+		if err := sb.UpdateTask("bench", task); err != nil {
+			b.Fatalf("UpdateTask() error = %v", err)
+		}
+	}
+}
+
+func BenchmarkGetTasks(b *testing.B) {
+	sb := newBenchBackend(b)
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		tasks, err := sb.GetTasks("bench", nil)
+		if err != nil {
+			b.Fatalf("GetTasks() error = %v", err)
+		}
+		if len(tasks) != benchTaskCount {
+			b.Fatalf("GetTasks() returned %d tasks, want %d", len(tasks), benchTaskCount)
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package sqlite
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// backendNameTables are the tables keyed by backend_name
+var backendNameTables = []string{"tasks", "sync_metadata", "sync_queue", "list_sync_metadata"}
+
+// BackendNames returns the backend names the cache holds rows for, sorted.
+// Rows written before backend names were recorded (an empty name) are not reported.
+func (db *Database) BackendNames() ([]string, error) {
+	selects := make([]string, len(backendNameTables))
+	for i, table := range backendNameTables {
+		selects[i] = "SELECT backend_name FROM " + table
+	}
+	rows, err := db.Query("SELECT DISTINCT backend_name FROM (" + strings.Join(selects, " UNION ") + ") WHERE backend_name != '' ORDER BY backend_name")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read backend names: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var names []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, fmt.Errorf("failed to read backend names: %w", err)
+		}
+		names = append(names, name)
+	}
+	return names, rows.Err()
+}
+
+// UnknownBackendNames returns the backend names the cache holds rows for
+// that are not among configured, such as the former name of a renamed backend
+func (db *Database) UnknownBackendNames(configured []string) ([]string, error) {
+	names, err := db.BackendNames()
+	if err != nil {
+		return nil, err
+	}
+	var unknown []string
+	for _, name := range names {
+		if !slices.Contains(configured, name) {
+			unknown = append(unknown, name)
+		}
+	}
+	return unknown, nil
+}
+
+// RenameBackend moves every cached row of backend oldName to newName in one
+// transaction and returns the number of rows changed. It refuses to merge
+// into a name the cache already holds rows for.
+func (db *Database) RenameBackend(oldName, newName string) (int64, error) {
+	if oldName == "" || newName == "" {
+		return 0, fmt.Errorf("backend names must not be empty")
+	}
+	if oldName == newName {
+		return 0, fmt.Errorf("backend is already named %q", newName)
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	for _, table := range backendNameTables {
+		var exists bool
+		if err := tx.QueryRow("SELECT EXISTS(SELECT 1 FROM "+table+" WHERE backend_name = ?)", newName).Scan(&exists); err != nil {
+			return 0, fmt.Errorf("failed to check %s: %w", table, err)
+		}
+		if exists {
+			return 0, fmt.Errorf("the cache already has rows for backend %q; sync or clear it before renaming onto it", newName)
+		}
+	}
+
+	var renamed int64
+	for _, table := range backendNameTables {
+		result, err := tx.Exec("UPDATE "+table+" SET backend_name = ? WHERE backend_name = ?", newName, oldName)
+		if err != nil {
+			return 0, fmt.Errorf("failed to rename backend in %s: %w", table, err)
+		}
+		n, err := result.RowsAffected()
+		if err != nil {
+			return 0, err
+		}
+		renamed += n
+	}
+	if renamed == 0 {
+		return 0, fmt.Errorf("the cache has no rows for backend %q", oldName)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return renamed, nil
+}
@@ -0,0 +1,74 @@
+package sqlite
+
+import (
+	"gosynctasks/backend"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+// newNamedTestBackend opens the database at dbPath as the cache of backend name
+func newNamedTestBackend(t *testing.T, dbPath, name string) *SQLiteBackend {
+	t.Helper()
+	sb, err := NewSQLiteBackend(backend.BackendConfig{Name: name, Type: "sqlite", Enabled: true, DBPath: dbPath})
+	if err != nil {
+		t.Fatalf("NewSQLiteBackend() error = %v", err)
+	}
+	t.Cleanup(func() { _ = sb.Close() })
+	return sb
+}
+
+// TestRenameBackend tests that renaming moves the rows of every backend-keyed table
+func TestRenameBackend(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "cache.db")
+	old := newNamedTestBackend(t, dbPath, "nextcloud")
+	other := newNamedTestBackend(t, dbPath, "todoist")
+
+	listID, _ := old.CreateTaskList("Work", "", "")
+	if _, err := old.AddTask(listID, backend.Task{Summary: "Queued", Status: "NEEDS-ACTION"}); err != nil {
+		t.Fatalf("AddTask() error = %v", err)
+	}
+	otherList, _ := other.CreateTaskList("Home", "", "")
+	_, _ = other.AddTask(otherList, backend.Task{Summary: "Other", Status: "NEEDS-ACTION"})
+
+	db, _ := old.GetDB()
+	unknown, err := db.UnknownBackendNames([]string{"cloud", "todoist"})
+	if err != nil {
+		t.Fatalf("UnknownBackendNames() error = %v", err)
+	}
+	if !slices.Equal(unknown, []string{"nextcloud"}) {
+		t.Errorf("UnknownBackendNames() = %v, want [nextcloud]", unknown)
+	}
+
+	if _, err := db.RenameBackend("nextcloud", "todoist"); err == nil {
+		t.Error("RenameBackend() onto a backend with cached rows succeeded")
+	}
+	if _, err := db.RenameBackend("nextcloud", "cloud"); err != nil {
+		t.Fatalf("RenameBackend() error = %v", err)
+	}
+
+	for _, table := range backendNameTables {
+		var oldRows, newRows int
+		_ = db.QueryRow("SELECT COUNT(*) FROM " + table + " WHERE backend_name = 'nextcloud'").Scan(&oldRows)
+		_ = db.QueryRow("SELECT COUNT(*) FROM " + table + " WHERE backend_name = 'cloud'").Scan(&newRows)
+		if oldRows != 0 || newRows == 0 {
+			t.Errorf("%s: %d rows left under the old name, %d under the new one", table, oldRows, newRows)
+		}
+	}
+	if names, _ := db.BackendNames(); !slices.Equal(names, []string{"cloud", "todoist"}) {
+		t.Errorf("BackendNames() after rename = %v", names)
+	}
+
+	renamed := newNamedTestBackend(t, dbPath, "cloud")
+	tasks, _ := renamed.GetTasks(listID, nil)
+	if len(tasks) != 1 || tasks[0].Summary != "Queued" {
+		t.Errorf("tasks under the new name = %+v", tasks)
+	}
+	if ops, _ := renamed.GetPendingSyncOperations(); len(ops) != 1 {
+		t.Errorf("pending operations under the new name = %+v, want the queued create", ops)
+	}
+
+	if _, err := db.RenameBackend("nextcloud", "cloud2"); err == nil {
+		t.Error("RenameBackend() of a name without rows succeeded")
+	}
+}
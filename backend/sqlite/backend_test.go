@@ -1,9 +1,12 @@
 package sqlite
 
 import (
+	"fmt"
 	"gosynctasks/backend"
 	"os"
 	"path/filepath"
+	"reflect"
+	"sort"
 	"strings"
 	"testing"
 	"time"
@@ -123,6 +126,63 @@ func TestGetTaskLists(t *testing.T) {
 	}
 }
 
+// TestGetTaskListsPositionAndActivity tests that lists keep the remote's
+// position and report their latest task change
+func TestGetTaskListsPositionAndActivity(t *testing.T) {
+	sb, cleanup := createTestSQLiteBackend(t)
+	defer cleanup()
+
+	if err := sb.CreateSyncedList(backend.TaskList{ID: "inbox", Name: "Inbox", Position: 2}); err != nil {
+		t.Fatalf("CreateSyncedList failed: %v", err)
+	}
+	modified := time.Now().Add(time.Hour).Truncate(time.Second)
+	if err := sb.InsertSyncedTask("inbox", backend.Task{UID: "t1", Summary: "Recent", Status: "NEEDS-ACTION", Modified: modified}); err != nil {
+		t.Fatalf("InsertSyncedTask failed: %v", err)
+	}
+	if err := sb.UpdateListInfo(backend.TaskList{ID: "inbox", Name: "Inbox", Position: 1}); err != nil {
+		t.Fatalf("UpdateListInfo failed: %v", err)
+	}
+
+	lists, err := sb.GetTaskLists()
+	if err != nil {
+		t.Fatalf("Failed to get task lists: %v", err)
+	}
+	if len(lists) != 1 {
+		t.Fatalf("Expected 1 list, got %d", len(lists))
+	}
+	if lists[0].Position != 1 {
+		t.Errorf("Position = %d, want 1", lists[0].Position)
+	}
+	if !lists[0].Modified.Equal(modified) {
+		t.Errorf("Modified = %v, want the task's %v", lists[0].Modified, modified)
+	}
+}
+
+// TestGetTask tests fetching a single task by UID
+func TestGetTask(t *testing.T) {
+	sb, cleanup := createTestSQLiteBackend(t)
+	defer cleanup()
+
+	listID, _ := sb.CreateTaskList("Personal", "", "")
+	uid, err := sb.AddTask(listID, backend.Task{Summary: "Water plants", Status: "COMPLETED"})
+	if err != nil {
+		t.Fatalf("Failed to add task: %v", err)
+	}
+
+	task, err := sb.GetTask(listID, uid)
+	if err != nil {
+		t.Fatalf("GetTask failed: %v", err)
+	}
+	if task.UID != uid || task.Summary != "Water plants" {
+		t.Errorf("GetTask returned %+v", task)
+	}
+
+	_, err = sb.GetTask(listID, "missing")
+	if backendErr, ok := err.(*backend.BackendError); !ok || !backendErr.IsNotFound() {
+		t.Errorf("GetTask(missing) error = %v, want a not found error", err)
+	}
+}
+
 // TestAddTask tests task creation
 func TestAddTask(t *testing.T) {
 	sb, cleanup := createTestSQLiteBackend(t)
@@ -246,6 +306,76 @@ func TestUpdateTask(t *testing.T) {
 	}
 }
 
+// TestTaskRecurrence tests that the recurrence rule of a task is stored,
+// updated and cleared
+func TestTaskRecurrence(t *testing.T) {
+	sb, cleanup := createTestSQLiteBackend(t)
+	defer cleanup()
+
+	listID, _ := sb.CreateTaskList("Test List", "", "")
+
+	task := backend.Task{Summary: "Water plants", Status: "NEEDS-ACTION", Recurrence: "FREQ=WEEKLY;BYDAY=MO"}
+	uid, err := sb.AddTask(listID, task)
+	if err != nil {
+		t.Fatalf("Failed to add task: %v", err)
+	}
+	if stored, _ := sb.GetTask(listID, uid); stored == nil || stored.Recurrence != task.Recurrence {
+		t.Fatalf("Stored task = %+v, want recurrence %q", stored, task.Recurrence)
+	}
+
+	for _, recurrence := range []string{"FREQ=DAILY;COUNT=3", ""} {
+		task.UID = uid
+		task.Recurrence = recurrence
+		if err := sb.UpdateTask(listID, task); err != nil {
+			t.Fatalf("Failed to update task: %v", err)
+		}
+		tasks, _ := sb.GetTasks(listID, nil)
+		if len(tasks) != 1 || tasks[0].Recurrence != recurrence {
+			t.Errorf("After the update: %+v, want recurrence %q", tasks, recurrence)
+		}
+	}
+}
+
+// TestTaskAllDay tests that all-day tasks are stored as such and stay due
+// until the end of their day
+func TestTaskAllDay(t *testing.T) {
+	sb, cleanup := createTestSQLiteBackend(t)
+	defer cleanup()
+
+	listID, _ := sb.CreateTaskList("Test List", "", "")
+
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+	yesterday := today.AddDate(0, 0, -1)
+	hourAgo := now.Add(-time.Hour)
+	for _, task := range []backend.Task{
+		{Summary: "All day today", Status: "NEEDS-ACTION", DueDate: &today, AllDay: true},
+		{Summary: "All day yesterday", Status: "NEEDS-ACTION", DueDate: &yesterday, AllDay: true},
+		{Summary: "An hour ago", Status: "NEEDS-ACTION", DueDate: &hourAgo},
+	} {
+		uid, err := sb.AddTask(listID, task)
+		if err != nil {
+			t.Fatalf("Failed to add task: %v", err)
+		}
+		if stored, _ := sb.GetTask(listID, uid); stored == nil || stored.AllDay != task.AllDay {
+			t.Fatalf("Stored task = %+v, want all day %v", stored, task.AllDay)
+		}
+	}
+
+	overdue, err := sb.GetTasks(listID, &backend.TaskFilter{Overdue: true})
+	if err != nil {
+		t.Fatalf("GetTasks() error = %v", err)
+	}
+	var summaries []string
+	for _, task := range overdue {
+		summaries = append(summaries, task.Summary)
+	}
+	sort.Strings(summaries)
+	if want := []string{"All day yesterday", "An hour ago"}; !reflect.DeepEqual(summaries, want) {
+		t.Errorf("Overdue tasks = %q, want %q", summaries, want)
+	}
+}
+
 // TestUpdateNonexistentTask tests updating a task that doesn't exist
 func TestUpdateNonexistentTask(t *testing.T) {
 	sb, cleanup := createTestSQLiteBackend(t)
@@ -272,6 +402,39 @@ func TestUpdateNonexistentTask(t *testing.T) {
 	}
 }
 
+// TestEmptyIdentifiersRejected tests that empty UIDs and list IDs are rejected
+// as validation errors without touching the database
+func TestEmptyIdentifiersRejected(t *testing.T) {
+	sb, cleanup := createTestSQLiteBackend(t)
+	defer cleanup()
+
+	listID, _ := sb.CreateTaskList("Test List", "", "")
+
+	errs := map[string]error{
+		"update empty uid":  sb.UpdateTask(listID, backend.Task{UID: " ", Summary: "x"}),
+		"update empty list": sb.UpdateTask("", backend.Task{UID: "task-1", Summary: "x"}),
+		"delete empty uid":  sb.DeleteTask(listID, ""),
+		"delete empty list": sb.DeleteTask("  ", "task-1"),
+	}
+	_, errs["add empty list"] = sb.AddTask("", backend.Task{Summary: "x"})
+
+	for name, err := range errs {
+		backendErr, ok := err.(*backend.BackendError)
+		if !ok || !backendErr.IsValidation() {
+			t.Errorf("%s: expected validation BackendError, got %v", name, err)
+		}
+	}
+
+	db, _ := sb.GetDB()
+	var queued int
+	if err := db.QueryRow("SELECT COUNT(*) FROM sync_queue").Scan(&queued); err != nil {
+		t.Fatalf("Failed to count sync queue: %v", err)
+	}
+	if queued != 0 {
+		t.Errorf("Expected no queued operations, got %d", queued)
+	}
+}
+
 // TestDeleteTask tests task deletion
 func TestDeleteTask(t *testing.T) {
 	sb, cleanup := createTestSQLiteBackend(t)
@@ -392,6 +555,147 @@ func TestGetTasksWithDateFilter(t *testing.T) {
 	}
 }
 
+// TestGetTasksWithModifiedFilter tests inclusive modified-time bounds at the cutoff second
+func TestGetTasksWithModifiedFilter(t *testing.T) {
+	sb, cleanup := createTestSQLiteBackend(t)
+	defer cleanup()
+
+	listID, _ := sb.CreateTaskList("Test List", "", "")
+
+	cutoff := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	before := cutoff.Add(-time.Second)
+	after := cutoff.Add(time.Second)
+
+	sb.AddTask(listID, backend.Task{Summary: "Before", Status: "NEEDS-ACTION", Modified: before})
+	sb.AddTask(listID, backend.Task{Summary: "At cutoff", Status: "NEEDS-ACTION", Modified: cutoff})
+	sb.AddTask(listID, backend.Task{Summary: "After", Status: "NEEDS-ACTION", Modified: after})
+
+	tests := []struct {
+		name   string
+		filter *backend.TaskFilter
+		want   []string
+	}{
+		{"after is inclusive", &backend.TaskFilter{ModifiedAfter: &cutoff}, []string{"After", "At cutoff"}},
+		{"before is inclusive", &backend.TaskFilter{ModifiedBefore: &cutoff}, []string{"At cutoff", "Before"}},
+		{"both bounds at cutoff", &backend.TaskFilter{ModifiedAfter: &cutoff, ModifiedBefore: &cutoff}, []string{"At cutoff"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tasks, err := sb.GetTasks(listID, tt.filter)
+			if err != nil {
+				t.Fatalf("Failed to get filtered tasks: %v", err)
+			}
+
+			var got []string
+			for _, task := range tasks {
+				got = append(got, task.Summary)
+			}
+			sort.Strings(got)
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("Got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestGetTasksWithPriorityFilter tests priority ranges, and that tasks without
+// a priority only match a range from 0
+func TestGetTasksWithPriorityFilter(t *testing.T) {
+	sb, cleanup := createTestSQLiteBackend(t)
+	defer cleanup()
+
+	listID, _ := sb.CreateTaskList("Test List", "", "")
+	for _, priority := range []int{0, 1, 4, 5, 9} {
+		sb.AddTask(listID, backend.Task{Summary: fmt.Sprintf("P%d", priority), Status: "NEEDS-ACTION", Priority: priority})
+	}
+
+	bounds := func(minimum, maximum int) *backend.TaskFilter {
+		return &backend.TaskFilter{PriorityMin: &minimum, PriorityMax: &maximum}
+	}
+	tests := []struct {
+		name   string
+		filter *backend.TaskFilter
+		want   []string
+	}{
+		{"high", bounds(1, 4), []string{"P1", "P4"}},
+		{"single priority", bounds(5, 5), []string{"P5"}},
+		{"none", bounds(0, 0), []string{"P0"}},
+		{"no filter", &backend.TaskFilter{}, []string{"P0", "P1", "P4", "P5", "P9"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tasks, err := sb.GetTasks(listID, tt.filter)
+			if err != nil {
+				t.Fatalf("Failed to get filtered tasks: %v", err)
+			}
+
+			var got []string
+			for _, task := range tasks {
+				got = append(got, task.Summary)
+			}
+			sort.Strings(got)
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("Got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetTasksWithOverdueFilter(t *testing.T) {
+	sb, cleanup := createTestSQLiteBackend(t)
+	defer cleanup()
+
+	listID, _ := sb.CreateTaskList("Test List", "", "")
+	yesterday, tomorrow := time.Now().Add(-24*time.Hour), time.Now().Add(24*time.Hour)
+	sb.AddTask(listID, backend.Task{Summary: "Late", Status: "NEEDS-ACTION", DueDate: &yesterday})
+	sb.AddTask(listID, backend.Task{Summary: "Late, started", Status: "IN-PROCESS", DueDate: &yesterday})
+	sb.AddTask(listID, backend.Task{Summary: "Late, done", Status: "COMPLETED", DueDate: &yesterday})
+	sb.AddTask(listID, backend.Task{Summary: "Late, cancelled", Status: "CANCELLED", DueDate: &yesterday})
+	sb.AddTask(listID, backend.Task{Summary: "Upcoming", Status: "NEEDS-ACTION", DueDate: &tomorrow})
+	sb.AddTask(listID, backend.Task{Summary: "Undated", Status: "NEEDS-ACTION"})
+
+	tasks, err := sb.GetTasks(listID, &backend.TaskFilter{Overdue: true})
+	if err != nil {
+		t.Fatalf("Failed to get overdue tasks: %v", err)
+	}
+	var got []string
+	for _, task := range tasks {
+		got = append(got, task.Summary)
+	}
+	sort.Strings(got)
+	if strings.Join(got, ",") != "Late,Late, started" {
+		t.Errorf("Got %q, want the open tasks past their due date", got)
+	}
+}
+
+// TestCompletionTimestampSetOnComplete tests that completing a task records when it happened
+func TestCompletionTimestampSetOnComplete(t *testing.T) {
+	sb, cleanup := createTestSQLiteBackend(t)
+	defer cleanup()
+
+	listID, _ := sb.CreateTaskList("Test List", "", "")
+	uid, _ := sb.AddTask(listID, backend.Task{Summary: "Task", Status: "NEEDS-ACTION"})
+
+	tasks, _ := sb.GetTasks(listID, nil)
+	if tasks[0].Completed != nil {
+		t.Fatalf("Expected no completion time for open task, got %v", tasks[0].Completed)
+	}
+
+	before := time.Now().Add(-time.Second)
+	task := tasks[0]
+	task.Status = "COMPLETED"
+	if err := sb.UpdateTask(listID, task); err != nil {
+		t.Fatalf("UpdateTask() error = %v", err)
+	}
+
+	tasks, _ = sb.GetTasks(listID, nil)
+	if tasks[0].UID != uid || tasks[0].Completed == nil || tasks[0].Completed.Before(before) {
+		t.Errorf("Expected completion time to be set, got %v", tasks[0].Completed)
+	}
+}
+
 // TestFindTasksBySummary tests searching for tasks
 func TestFindTasksBySummary(t *testing.T) {
 	sb, cleanup := createTestSQLiteBackend(t)
@@ -426,6 +730,60 @@ func TestFindTasksBySummary(t *testing.T) {
 	}
 }
 
+// TestFindTasksBySummaryLiteralWildcards tests that LIKE wildcards in a search are literal
+func TestFindTasksBySummaryLiteralWildcards(t *testing.T) {
+	sb, cleanup := createTestSQLiteBackend(t)
+	defer cleanup()
+
+	listID, _ := sb.CreateTaskList("Test List", "", "")
+	sb.AddTask(listID, backend.Task{Summary: "Raise rent 5% in March", Status: "NEEDS-ACTION"})
+	sb.AddTask(listID, backend.Task{Summary: "Raise rent 50 in March", Status: "NEEDS-ACTION"})
+	sb.AddTask(listID, backend.Task{Summary: `Rename my_file.txt`, Status: "NEEDS-ACTION"})
+	sb.AddTask(listID, backend.Task{Summary: `Rename myXfile.txt`, Status: "NEEDS-ACTION"})
+
+	for search, want := range map[string]string{
+		"5%":      "Raise rent 5% in March",
+		"my_file": "Rename my_file.txt",
+	} {
+		tasks, err := sb.FindTasksBySummary(listID, search)
+		if err != nil {
+			t.Fatalf("Failed to find tasks: %v", err)
+		}
+		if len(tasks) != 1 || tasks[0].Summary != want {
+			t.Errorf("FindTasksBySummary(%q) = %v, want only %q", search, tasks, want)
+		}
+	}
+}
+
+// TestSearchAllLists tests searching every list in one query
+func TestSearchAllLists(t *testing.T) {
+	sb, cleanup := createTestSQLiteBackend(t)
+	defer cleanup()
+
+	work, _ := sb.CreateTaskList("Work", "", "")
+	home, _ := sb.CreateTaskList("Home", "", "")
+	empty, _ := sb.CreateTaskList("Empty", "", "")
+	sb.AddTask(work, backend.Task{Summary: "Send invoice to ACME", Status: "NEEDS-ACTION"})
+	sb.AddTask(work, backend.Task{Summary: "Invoice", Status: "COMPLETED"})
+	sb.AddTask(home, backend.Task{Summary: "Pay plumber invoice", Status: "NEEDS-ACTION"})
+	sb.AddTask(home, backend.Task{Summary: "Buy milk", Status: "NEEDS-ACTION"})
+	sb.AddTask(empty, backend.Task{Summary: "Nothing", Status: "NEEDS-ACTION"})
+
+	matches, err := sb.SearchAllLists("INVOICE")
+	if err != nil {
+		t.Fatalf("SearchAllLists() error = %v", err)
+	}
+	if len(matches) != 2 || len(matches[work]) != 2 || len(matches[home]) != 1 {
+		t.Fatalf("SearchAllLists() = %+v, want 2 tasks in Work and 1 in Home", matches)
+	}
+	if matches[work][0].Summary != "Invoice" {
+		t.Errorf("first Work match = %q, want the exact match", matches[work][0].Summary)
+	}
+	if matches[home][0].Summary != "Pay plumber invoice" {
+		t.Errorf("Home match = %q", matches[home][0].Summary)
+	}
+}
+
 // TestRenameTaskList tests renaming a task list
 func TestRenameTaskList(t *testing.T) {
 	sb, cleanup := createTestSQLiteBackend(t)
@@ -964,3 +1322,137 @@ func TestTransactionRollback(t *testing.T) {
 		t.Error("Expected transaction rollback, but task was added")
 	}
 }
+
+// queuedOps returns the pending operations as "operation:uid" strings
+func queuedOps(t *testing.T, sb *SQLiteBackend) []string {
+	t.Helper()
+	ops, err := sb.GetPendingSyncOperations()
+	if err != nil {
+		t.Fatalf("Failed to get pending operations: %v", err)
+	}
+	var queued []string
+	for _, op := range ops {
+		queued = append(queued, op.Operation+":"+op.TaskUID)
+	}
+	return queued
+}
+
+// TestSyncQueueCollapse tests that redundant queue entries are collapsed at enqueue time
+func TestSyncQueueCollapse(t *testing.T) {
+	sb, cleanup := createTestSQLiteBackend(t)
+	defer cleanup()
+
+	listID, _ := sb.CreateTaskList("Test List", "", "")
+
+	t.Run("updates after create fold into the create", func(t *testing.T) {
+		uid, _ := sb.AddTask(listID, backend.Task{Summary: "New", Status: "NEEDS-ACTION"})
+		for _, summary := range []string{"Renamed", "Renamed again"} {
+			if err := sb.UpdateTask(listID, backend.Task{UID: uid, Summary: summary, Status: "NEEDS-ACTION"}); err != nil {
+				t.Fatalf("UpdateTask failed: %v", err)
+			}
+		}
+		if got, want := queuedOps(t, sb), []string{"create:" + uid}; !reflect.DeepEqual(got, want) {
+			t.Errorf("queue = %v, want %v", got, want)
+		}
+		_ = sb.ClearSyncFlagsAndQueue(uid)
+	})
+
+	t.Run("delete after unpushed create removes both", func(t *testing.T) {
+		uid, _ := sb.AddTask(listID, backend.Task{Summary: "Short-lived", Status: "NEEDS-ACTION"})
+		if err := sb.DeleteTask(listID, uid); err != nil {
+			t.Fatalf("DeleteTask failed: %v", err)
+		}
+		if got := queuedOps(t, sb); len(got) != 0 {
+			t.Errorf("queue = %v, want empty", got)
+		}
+		db, _ := sb.GetDB()
+		var count int
+		_ = db.QueryRow("SELECT COUNT(*) FROM tasks WHERE uid = ?", uid).Scan(&count)
+		if count != 0 {
+			t.Errorf("Expected unpushed task to be removed, found %d rows", count)
+		}
+	})
+
+	t.Run("update after update replaces it", func(t *testing.T) {
+		_ = sb.InsertSyncedTask(listID, backend.Task{UID: "remote-1", Summary: "Pulled", Status: "NEEDS-ACTION"})
+		for _, summary := range []string{"Edit 1", "Edit 2"} {
+			_ = sb.UpdateTask(listID, backend.Task{UID: "remote-1", Summary: summary, Status: "NEEDS-ACTION"})
+		}
+		if got, want := queuedOps(t, sb), []string{"update:remote-1"}; !reflect.DeepEqual(got, want) {
+			t.Errorf("queue = %v, want %v", got, want)
+		}
+	})
+
+	t.Run("delete replaces a queued update", func(t *testing.T) {
+		if err := sb.DeleteTask(listID, "remote-1"); err != nil {
+			t.Fatalf("DeleteTask failed: %v", err)
+		}
+		if got, want := queuedOps(t, sb), []string{"delete:remote-1"}; !reflect.DeepEqual(got, want) {
+			t.Errorf("queue = %v, want %v", got, want)
+		}
+		_ = sb.ClearSyncFlagsAndQueue("remote-1")
+	})
+
+	t.Run("delete after create of a task already pushed keeps the delete", func(t *testing.T) {
+		// The create reached the remote, which assigned a UID, but the queue
+		// entry was left behind (e.g. sync was interrupted before clearing it)
+		uid, _ := sb.AddTask(listID, backend.Task{Summary: "Pushed", Status: "NEEDS-ACTION"})
+		if err := sb.UpdateTaskUID(listID, uid, "remote-2"); err != nil {
+			t.Fatalf("UpdateTaskUID failed: %v", err)
+		}
+		if err := sb.DeleteTask(listID, "remote-2"); err != nil {
+			t.Fatalf("DeleteTask failed: %v", err)
+		}
+		if got, want := queuedOps(t, sb), []string{"delete:remote-2"}; !reflect.DeepEqual(got, want) {
+			t.Errorf("queue = %v, want %v", got, want)
+		}
+		_ = sb.ClearSyncFlagsAndQueue("remote-2")
+	})
+
+	t.Run("delete after a completed push is queued", func(t *testing.T) {
+		uid, _ := sb.AddTask(listID, backend.Task{Summary: "Synced", Status: "NEEDS-ACTION"})
+		_ = sb.UpdateTaskUID(listID, uid, "remote-3")
+		_ = sb.ClearSyncFlagsAndQueue("remote-3")
+		_ = sb.UpdateTask(listID, backend.Task{UID: "remote-3", Summary: "Synced, edited", Status: "NEEDS-ACTION"})
+		if err := sb.DeleteTask(listID, "remote-3"); err != nil {
+			t.Fatalf("DeleteTask failed: %v", err)
+		}
+		if got, want := queuedOps(t, sb), []string{"delete:remote-3"}; !reflect.DeepEqual(got, want) {
+			t.Errorf("queue = %v, want %v", got, want)
+		}
+	})
+}
+
+// TestSyncQueueStats tests queue statistics and the cap on stored error text
+func TestSyncQueueStats(t *testing.T) {
+	sb, cleanup := createTestSQLiteBackend(t)
+	defer cleanup()
+
+	listID, _ := sb.CreateTaskList("Test List", "", "")
+	first, _ := sb.AddTask(listID, backend.Task{Summary: "First", Status: "NEEDS-ACTION"})
+	_, _ = sb.AddTask(listID, backend.Task{Summary: "Second", Status: "NEEDS-ACTION"})
+	_ = sb.InsertSyncedTask(listID, backend.Task{UID: "remote-1", Summary: "Pulled", Status: "NEEDS-ACTION"})
+	_ = sb.UpdateTask(listID, backend.Task{UID: "remote-1", Summary: "Edited", Status: "NEEDS-ACTION"})
+
+	ops, _ := sb.GetPendingSyncOperations()
+	if err := sb.RecordSyncFailure(ops[0].ID, strings.Repeat("x", 10*maxSyncErrorLength), time.Now()); err != nil {
+		t.Fatalf("RecordSyncFailure failed: %v", err)
+	}
+
+	stats, err := sb.GetSyncQueueStats(1)
+	if err != nil {
+		t.Fatalf("GetSyncQueueStats failed: %v", err)
+	}
+	if stats.Total != 3 || stats.ByOperation["create"] != 2 || stats.ByOperation["update"] != 1 {
+		t.Errorf("Unexpected counts: %+v", stats)
+	}
+	if stats.Failing != 1 {
+		t.Errorf("Failing = %d, want 1", stats.Failing)
+	}
+	if len(stats.Oldest) != 1 || stats.Oldest[0].TaskUID != first {
+		t.Fatalf("Oldest = %+v, want only %s", stats.Oldest, first)
+	}
+	if n := len([]rune(stats.Oldest[0].LastError)); n != maxSyncErrorLength {
+		t.Errorf("Stored error has %d characters, want %d", n, maxSyncErrorLength)
+	}
+}
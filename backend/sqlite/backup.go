@@ -0,0 +1,183 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	sqlitedriver "modernc.org/sqlite"
+)
+
+// backupTimeFormat names backup files so they sort by creation time
+const backupTimeFormat = "20060102-150405.000"
+
+// BackupFile is a snapshot of the cache in the backup directory
+type BackupFile struct {
+	Path      string
+	Reason    string // Operation the backup was taken before, e.g. "full-sync"
+	CreatedAt time.Time
+	Size      int64
+}
+
+// Name returns the file name of the backup
+func (b BackupFile) Name() string {
+	return filepath.Base(b.Path)
+}
+
+// backupConn is the part of the driver connection used for online backups
+type backupConn interface {
+	NewBackup(dstURI string) (*sqlitedriver.Backup, error)
+	NewRestore(srcURI string) (*sqlitedriver.Backup, error)
+}
+
+// BackupDir returns the directory backups of the database are kept in
+func (db *Database) BackupDir() string {
+	return filepath.Join(filepath.Dir(db.path), "backups")
+}
+
+// Backup snapshots the database into the backup directory with the SQLite
+// online backup API, so the copy is consistent even while it is in use.
+// Only the newest keep backups are kept afterwards.
+func (db *Database) Backup(reason string, keep int) (BackupFile, error) {
+	dir := db.BackupDir()
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return BackupFile{}, fmt.Errorf("failed to create backup directory: %w", err)
+	}
+
+	now := time.Now()
+	path := filepath.Join(dir, fmt.Sprintf("cache-%s-%s.db", now.Format(backupTimeFormat), reason))
+	err := db.onlineBackup(func(conn backupConn) (*sqlitedriver.Backup, error) {
+		return conn.NewBackup(path)
+	})
+	if err != nil {
+		_ = os.Remove(path)
+		return BackupFile{}, fmt.Errorf("failed to back up cache: %w", err)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return BackupFile{}, err
+	}
+	if _, err := PruneBackups(dir, keep, 0); err != nil {
+		return BackupFile{}, err
+	}
+	return BackupFile{Path: path, Reason: reason, CreatedAt: now, Size: info.Size()}, nil
+}
+
+// RestoreBackup replaces the contents of the database with a backup. It
+// fails instead of waiting when another connection is writing, such as a sync
+// in progress.
+func (db *Database) RestoreBackup(path string) error {
+	if _, err := os.Stat(path); err != nil {
+		return fmt.Errorf("backup not found: %w", err)
+	}
+	err := db.onlineBackup(func(conn backupConn) (*sqlitedriver.Backup, error) {
+		return conn.NewRestore(path)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to restore backup (is a sync running?): %w", err)
+	}
+	return nil
+}
+
+// onlineBackup runs a backup or restore, created by start on one pooled
+// connection, to completion
+func (db *Database) onlineBackup(start func(backupConn) (*sqlitedriver.Backup, error)) error {
+	conn, err := db.Conn(context.Background())
+	if err != nil {
+		return err
+	}
+	defer func() { _ = conn.Close() }()
+
+	return conn.Raw(func(driverConn any) error {
+		bc, ok := driverConn.(backupConn)
+		if !ok {
+			return fmt.Errorf("database driver does not support online backups")
+		}
+		backup, err := start(bc)
+		if err != nil {
+			return err
+		}
+		for {
+			more, err := backup.Step(-1)
+			if err != nil {
+				_ = backup.Finish()
+				return err
+			}
+			if !more {
+				break
+			}
+		}
+		return backup.Finish()
+	})
+}
+
+// ListBackups returns the backups in dir, newest first
+func ListBackups(dir string) ([]BackupFile, error) {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read backup directory: %w", err)
+	}
+
+	var backups []BackupFile
+	for _, entry := range entries {
+		backup, ok := parseBackupName(entry.Name())
+		if !ok || entry.IsDir() {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		backup.Path = filepath.Join(dir, entry.Name())
+		backup.Size = info.Size()
+		backups = append(backups, backup)
+	}
+	sort.Slice(backups, func(i, j int) bool { return backups[i].CreatedAt.After(backups[j].CreatedAt) })
+	return backups, nil
+}
+
+// parseBackupName reads the time and reason from a backup file name
+func parseBackupName(name string) (BackupFile, bool) {
+	rest, ok := strings.CutPrefix(name, "cache-")
+	if !ok || !strings.HasSuffix(rest, ".db") || len(rest) < len(backupTimeFormat) {
+		return BackupFile{}, false
+	}
+	createdAt, err := time.ParseInLocation(backupTimeFormat, rest[:len(backupTimeFormat)], time.Local)
+	if err != nil {
+		return BackupFile{}, false
+	}
+	reason := strings.TrimPrefix(strings.TrimSuffix(rest[len(backupTimeFormat):], ".db"), "-")
+	return BackupFile{Reason: reason, CreatedAt: createdAt}, true
+}
+
+// PruneBackups deletes the backups in dir beyond the newest keep, and those
+// older than maxAge when it is positive, returning the deleted paths. The
+// newest backup is never deleted for its age.
+func PruneBackups(dir string, keep int, maxAge time.Duration) ([]string, error) {
+	backups, err := ListBackups(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var pruned []string
+	for i, backup := range backups {
+		tooMany := keep > 0 && i >= keep
+		tooOld := maxAge > 0 && i > 0 && time.Since(backup.CreatedAt) > maxAge
+		if !tooMany && !tooOld {
+			continue
+		}
+		if err := os.Remove(backup.Path); err != nil {
+			return pruned, fmt.Errorf("failed to prune backup: %w", err)
+		}
+		pruned = append(pruned, backup.Path)
+	}
+	return pruned, nil
+}
@@ -0,0 +1,71 @@
+package sqlite
+
+import (
+	"gosynctasks/backend"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// TestBackupRestoreRoundTrip tests that restoring a backup brings back the data it was taken with
+func TestBackupRestoreRoundTrip(t *testing.T) {
+	sb, cleanup := createTestSQLiteBackend(t)
+	defer cleanup()
+	db, _ := sb.GetDB()
+
+	listID, _ := sb.CreateTaskList("Backup", "", "")
+	_, _ = sb.AddTask(listID, backend.Task{Summary: "Before backup", Status: "NEEDS-ACTION"})
+
+	backup, err := db.Backup("full-sync", 5)
+	if err != nil {
+		t.Fatalf("Backup() error = %v", err)
+	}
+	if filepath.Dir(backup.Path) != db.BackupDir() || backup.Size == 0 {
+		t.Errorf("Backup() = %+v", backup)
+	}
+
+	_, _ = sb.AddTask(listID, backend.Task{Summary: "After backup", Status: "NEEDS-ACTION"})
+	if err := db.RestoreBackup(backup.Path); err != nil {
+		t.Fatalf("RestoreBackup() error = %v", err)
+	}
+
+	tasks, _ := sb.GetTasks(listID, nil)
+	if len(tasks) != 1 || tasks[0].Summary != "Before backup" {
+		t.Errorf("tasks after restore = %+v, want only the backed up one", tasks)
+	}
+
+	backups, err := ListBackups(db.BackupDir())
+	if err != nil {
+		t.Fatalf("ListBackups() error = %v", err)
+	}
+	if len(backups) != 1 || backups[0].Reason != "full-sync" || backups[0].Path != backup.Path {
+		t.Errorf("ListBackups() = %+v", backups)
+	}
+}
+
+// TestPruneBackups tests rotation by count and by age
+func TestPruneBackups(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Now()
+	ages := []time.Duration{0, time.Hour, 48 * time.Hour, 72 * time.Hour}
+	for _, age := range ages {
+		name := "cache-" + now.Add(-age).Format(backupTimeFormat) + "-full-sync.db"
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("db"), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	_ = os.WriteFile(filepath.Join(dir, "notes.txt"), nil, 0600)
+
+	pruned, err := PruneBackups(dir, 3, 0)
+	if err != nil || len(pruned) != 1 {
+		t.Fatalf("PruneBackups(keep 3) = %v, %v, want the oldest pruned", pruned, err)
+	}
+	pruned, err = PruneBackups(dir, 0, 24*time.Hour)
+	if err != nil || len(pruned) != 1 {
+		t.Fatalf("PruneBackups(1 day) = %v, %v, want the 2 day old backup pruned", pruned, err)
+	}
+	if backups, _ := ListBackups(dir); len(backups) != 2 {
+		t.Errorf("%d backups left, want 2", len(backups))
+	}
+}
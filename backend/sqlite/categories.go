@@ -0,0 +1,61 @@
+package sqlite
+
+// countCategoriesSQL splits the comma-joined categories column with a
+// recursive CTE, so usage is counted in a single pass over the list
+const countCategoriesSQL = `
+	WITH RECURSIVE split(internal_id, tag, rest) AS (
+		SELECT t.internal_id, '', t.categories || ','
+		FROM tasks t
+		LEFT JOIN sync_metadata sm ON t.internal_id = sm.task_internal_id AND t.backend_name = sm.backend_name
+		WHERE t.backend_name = ? AND t.list_id = ?
+		  AND t.categories IS NOT NULL AND t.categories != ''
+		  AND (sm.locally_deleted IS NULL OR sm.locally_deleted = 0)
+		UNION ALL
+		SELECT internal_id, substr(rest, 1, instr(rest, ',') - 1), substr(rest, instr(rest, ',') + 1)
+		FROM split
+		WHERE rest != ''
+	)
+	SELECT tag, COUNT(DISTINCT internal_id)
+	FROM split
+	WHERE tag != ''
+	GROUP BY tag
+`
+
+// CountCategories returns the number of tasks in the list carrying each category
+func (sb *SQLiteBackend) CountCategories(listID string) (map[string]int, error) {
+	db, err := sb.GetDB()
+	if err != nil {
+		return nil, &SQLiteError{Op: "CountCategories", ListID: listID, Err: err}
+	}
+
+	// Encrypted categories are counted in Go
+	encrypted, err := sb.isListEncrypted(db, listID)
+	if err != nil {
+		return nil, &SQLiteError{Op: "CountCategories", ListID: listID, Err: err}
+	}
+	if encrypted {
+		counts, err := sb.countEncryptedCategories(db, listID)
+		if err != nil {
+			return nil, &SQLiteError{Op: "CountCategories", ListID: listID, Err: err}
+		}
+		return counts, nil
+	}
+
+	rows, err := db.Query(countCategoriesSQL, sb.backendName, listID)
+	if err != nil {
+		return nil, &SQLiteError{Op: "CountCategories", ListID: listID, Err: err}
+	}
+	defer func() { _ = rows.Close() }()
+
+	counts := make(map[string]int)
+	for rows.Next() {
+		var tag string
+		var count int
+		if err := rows.Scan(&tag, &count); err != nil {
+			return nil, &SQLiteError{Op: "CountCategories", ListID: listID, Err: err}
+		}
+		counts[tag] = count
+	}
+
+	return counts, rows.Err()
+}
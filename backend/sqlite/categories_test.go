@@ -0,0 +1,30 @@
+package sqlite
+
+import (
+	"gosynctasks/backend"
+	"maps"
+	"testing"
+)
+
+// TestCountCategories tests that every category of every task is counted once per task
+func TestCountCategories(t *testing.T) {
+	sb, cleanup := createTestSQLiteBackend(t)
+	defer cleanup()
+
+	listID, _ := sb.CreateTaskList("Tags", "", "")
+	otherID, _ := sb.CreateTaskList("Other", "", "")
+	_, _ = sb.AddTask(listID, backend.Task{Summary: "A", Status: "NEEDS-ACTION", Categories: []string{"work", "urgent"}})
+	_, _ = sb.AddTask(listID, backend.Task{Summary: "B", Status: "COMPLETED", Categories: []string{"work"}})
+	_, _ = sb.AddTask(listID, backend.Task{Summary: "C", Status: "NEEDS-ACTION", Categories: []string{"Work", "work"}})
+	_, _ = sb.AddTask(listID, backend.Task{Summary: "D", Status: "NEEDS-ACTION"})
+	_, _ = sb.AddTask(otherID, backend.Task{Summary: "E", Status: "NEEDS-ACTION", Categories: []string{"work"}})
+
+	counts, err := sb.CountCategories(listID)
+	if err != nil {
+		t.Fatalf("CountCategories() error = %v", err)
+	}
+	want := map[string]int{"work": 3, "urgent": 1, "Work": 1}
+	if !maps.Equal(counts, want) {
+		t.Errorf("CountCategories() = %v, want %v", counts, want)
+	}
+}
@@ -0,0 +1,46 @@
+package sqlite
+
+import "fmt"
+
+// CheckIssue is a problem found in the cache by Check
+type CheckIssue struct {
+	Rule   string // Name of the rule that found the issue
+	Count  int    // Number of affected rows
+	Detail string // Human-readable description
+}
+
+// checkRule counts rows in the cache that violate an invariant
+type checkRule struct {
+	name   string
+	query  string // Must return a single count
+	detail string
+}
+
+// checkRules lists the integrity rules run by Check
+var checkRules = []checkRule{
+	{
+		name:   "empty-uid",
+		query:  "SELECT COUNT(*) FROM tasks WHERE TRIM(uid) = ''",
+		detail: "tasks with an empty UID; they cannot be synced or addressed on the remote",
+	},
+	{
+		name:   "empty-list-id",
+		query:  "SELECT COUNT(*) FROM tasks WHERE TRIM(list_id) = ''",
+		detail: "tasks with an empty list ID",
+	},
+}
+
+// Check runs the integrity rules against the cache and returns any issues found
+func (db *Database) Check() ([]CheckIssue, error) {
+	var issues []CheckIssue
+	for _, rule := range checkRules {
+		var count int
+		if err := db.QueryRow(rule.query).Scan(&count); err != nil {
+			return nil, fmt.Errorf("check %s failed: %w", rule.name, err)
+		}
+		if count > 0 {
+			issues = append(issues, CheckIssue{Rule: rule.name, Count: count, Detail: rule.detail})
+		}
+	}
+	return issues, nil
+}
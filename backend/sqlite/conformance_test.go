@@ -0,0 +1,19 @@
+package sqlite
+
+import (
+	"gosynctasks/backend"
+	"gosynctasks/backend/conformance"
+	"testing"
+)
+
+// TestConformance runs the backend-agnostic TaskManager suite against SQLite
+func TestConformance(t *testing.T) {
+	sb, cleanup := createTestSQLiteBackend(t)
+	defer cleanup()
+
+	conformance.Run(t, func() backend.TaskManager { return sb }, conformance.Capabilities{
+		StableListIDs: true,
+		Hierarchy:     true,
+		Categories:    true,
+	})
+}
@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
+	"sync"
 	"time"
 
 	_ "modernc.org/sqlite" // SQLite driver
@@ -14,6 +16,17 @@ import (
 type Database struct {
 	*sql.DB
 	path string
+
+	// Prepared statement cache, keyed by query text
+	stmtMu sync.Mutex
+	stmts  map[string]*sql.Stmt
+
+	// Sealer of the encrypted lists, unlocked on first use
+	keyMu  sync.Mutex
+	sealer *sealer
+
+	// fullText is set when tasks_fts is available, SQLite having FTS5
+	fullText bool
 }
 
 // InitDatabase initializes the SQLite database with proper schema
@@ -30,15 +43,17 @@ func InitDatabase(customPath string) (*Database, error) {
 		return nil, fmt.Errorf("failed to create database directory: %w", err)
 	}
 
-	// Open database connection
-	db, err := sql.Open("sqlite", dbPath)
+	// Open database connection. Pragmas go in the DSN so that every pooled
+	// connection gets them, not just the one that runs initializeSchema.
+	db, err := sql.Open("sqlite", dataSourceName(dbPath))
 	if err != nil {
 		return nil, fmt.Errorf("failed to open database: %w", err)
 	}
 
 	database := &Database{
-		DB:   db,
-		path: dbPath,
+		DB:    db,
+		path:  dbPath,
+		stmts: make(map[string]*sql.Stmt),
 	}
 
 	// Initialize schema
@@ -50,6 +65,45 @@ func InitDatabase(customPath string) (*Database, error) {
 	return database, nil
 }
 
+// dataSourceName builds the driver DSN for dbPath with the connection pragmas applied
+func dataSourceName(dbPath string) string {
+	params := make([]string, 0, len(ConnectionPragmas()))
+	for _, pragma := range ConnectionPragmas() {
+		params = append(params, "_pragma="+pragma)
+	}
+	return "file:" + dbPath + "?" + strings.Join(params, "&")
+}
+
+// prepared returns a cached prepared statement for query, preparing it on first use.
+// Statements are safe for concurrent use; use tx.Stmt to run one inside a transaction.
+func (db *Database) prepared(query string) (*sql.Stmt, error) {
+	db.stmtMu.Lock()
+	defer db.stmtMu.Unlock()
+
+	if stmt, ok := db.stmts[query]; ok {
+		return stmt, nil
+	}
+
+	stmt, err := db.Prepare(query)
+	if err != nil {
+		return nil, err
+	}
+	db.stmts[query] = stmt
+	return stmt, nil
+}
+
+// Close closes cached statements and the underlying database
+func (db *Database) Close() error {
+	db.stmtMu.Lock()
+	for query, stmt := range db.stmts {
+		_ = stmt.Close()
+		delete(db.stmts, query)
+	}
+	db.stmtMu.Unlock()
+
+	return db.DB.Close()
+}
+
 // getDatabasePath returns the path to the SQLite database file
 // Priority: customPath > $XDG_DATA_HOME/gosynctasks/tasks.db > ~/.local/share/gosynctasks/tasks.db
 func getDatabasePath(customPath string) (string, error) {
@@ -87,6 +141,13 @@ func (db *Database) initializeSchema() error {
 		}
 	}
 
+	// Add columns that older databases are missing
+	for _, addition := range AllColumnAdditions() {
+		if err := db.ensureColumn(addition); err != nil {
+			return fmt.Errorf("failed to add column %s.%s: %w", addition.Table, addition.Column, err)
+		}
+	}
+
 	// Create all indexes
 	for _, index := range AllIndexes() {
 		if _, err := db.Exec(index); err != nil {
@@ -94,6 +155,10 @@ func (db *Database) initializeSchema() error {
 		}
 	}
 
+	if err := db.ensureFullTextIndex(); err != nil {
+		return fmt.Errorf("failed to create the full-text index: %w", err)
+	}
+
 	// Record schema version
 	if err := db.recordSchemaVersion(); err != nil {
 		return fmt.Errorf("failed to record schema version: %w", err)
@@ -102,6 +167,33 @@ func (db *Database) initializeSchema() error {
 	return nil
 }
 
+// ensureColumn adds a column to an existing table if it is not there yet
+func (db *Database) ensureColumn(addition ColumnAddition) error {
+	rows, err := db.Query(fmt.Sprintf("PRAGMA table_info(%s)", addition.Table))
+	if err != nil {
+		return err
+	}
+	defer func() { _ = rows.Close() }()
+
+	for rows.Next() {
+		var cid, notNull, pk int
+		var name, colType string
+		var dflt sql.NullString
+		if err := rows.Scan(&cid, &name, &colType, &notNull, &dflt, &pk); err != nil {
+			return err
+		}
+		if name == addition.Column {
+			return nil
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	_, err = db.Exec(fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", addition.Table, addition.Column, addition.Definition))
+	return err
+}
+
 // recordSchemaVersion records the current schema version in the database
 func (db *Database) recordSchemaVersion() error {
 	// Check if version already recorded
@@ -149,9 +241,10 @@ func (db *Database) Vacuum() error {
 	return err
 }
 
-// GetStats returns basic database statistics
+// GetStats returns database statistics: sync counts, the row count of every
+// table, the file sizes, the indexes and the schema version
 func (db *Database) GetStats() (DatabaseStats, error) {
-	stats := DatabaseStats{}
+	stats := DatabaseStats{TableRows: make(map[string]int)}
 
 	// Count tasks
 	err := db.QueryRow("SELECT COUNT(*) FROM tasks").Scan(&stats.TaskCount)
@@ -177,6 +270,35 @@ func (db *Database) GetStats() (DatabaseStats, error) {
 		return stats, fmt.Errorf("failed to count locally modified tasks: %w", err)
 	}
 
+	// Count the completed tasks pruned from the cache, still on the remote
+	err = db.QueryRow("SELECT COUNT(*) FROM pruned_tasks").Scan(&stats.PrunedTasks)
+	if err != nil {
+		return stats, fmt.Errorf("failed to count pruned tasks: %w", err)
+	}
+
+	// Count the rows of every table
+	tables, err := db.schemaObjects("table")
+	if err != nil {
+		return stats, fmt.Errorf("failed to list tables: %w", err)
+	}
+	for _, table := range tables {
+		var count int
+		if err := db.QueryRow(fmt.Sprintf("SELECT COUNT(*) FROM %q", table)).Scan(&count); err != nil {
+			return stats, fmt.Errorf("failed to count rows of %s: %w", table, err)
+		}
+		stats.TableRows[table] = count
+	}
+
+	stats.Indexes, err = db.schemaObjects("index")
+	if err != nil {
+		return stats, fmt.Errorf("failed to list indexes: %w", err)
+	}
+
+	stats.SchemaVersion, err = db.GetSchemaVersion()
+	if err != nil {
+		return stats, err
+	}
+
 	// Get database file size
 	fileInfo, err := os.Stat(db.path)
 	if err != nil {
@@ -184,16 +306,46 @@ func (db *Database) GetStats() (DatabaseStats, error) {
 	}
 	stats.DatabaseSize = fileInfo.Size()
 
+	// The write-ahead log only exists while the database is open in WAL mode
+	if walInfo, err := os.Stat(db.path + "-wal"); err == nil {
+		stats.WALSize = walInfo.Size()
+	}
+
 	return stats, nil
 }
 
+// schemaObjects returns the names of the tables or indexes of the database,
+// sorted, leaving out SQLite's internal ones
+func (db *Database) schemaObjects(objectType string) ([]string, error) {
+	rows, err := db.Query("SELECT name FROM sqlite_master WHERE type = ? AND name NOT LIKE 'sqlite_%' ORDER BY name", objectType)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	var names []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		names = append(names, name)
+	}
+	return names, rows.Err()
+}
+
 // DatabaseStats holds statistics about the database
 type DatabaseStats struct {
-	TaskCount       int
-	ListCount       int
-	PendingSyncOps  int
-	LocallyModified int
-	DatabaseSize    int64 // in bytes
+	TaskCount       int            `json:"task_count"`
+	ListCount       int            `json:"list_count"`
+	PendingSyncOps  int            `json:"pending_sync_ops"`
+	LocallyModified int            `json:"locally_modified"`
+	PrunedTasks     int            `json:"pruned_tasks"`  // Completed tasks left on the remote only by the retention policy
+	DatabaseSize    int64          `json:"database_size"` // in bytes
+	WALSize         int64          `json:"wal_size"`      // in bytes
+	TableRows       map[string]int `json:"table_rows"`    // Row count by table
+	Indexes         []string       `json:"indexes"`
+	SchemaVersion   int            `json:"schema_version"`
 }
 
 // String returns a human-readable representation of database statistics
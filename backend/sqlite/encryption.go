@@ -0,0 +1,584 @@
+package sqlite
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"gosynctasks/backend"
+	"sort"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+// Lists can be encrypted at rest: the summary, description and categories of
+// their tasks, and the former summaries kept for them, are sealed with
+// AES-256-GCM under a key derived from a passphrase with scrypt. Values are
+// sealed on write and opened on read inside this package, so callers and the
+// remote only ever see plaintext. Searching an encrypted list opens every task
+// of the list and matches in Go, which is slower than the LIKE queries.
+
+// sealedPrefix marks a sealed value: the prefix, then base64 of nonce||ciphertext
+const sealedPrefix = "enc:v1:"
+
+// verifierText is sealed with the key when the passphrase is set, to tell a
+// wrong passphrase from a corrupted value
+const verifierText = "gosynctasks cache key"
+
+// Parameters of the scrypt key derivation and the passphrase
+const (
+	scryptN       = 1 << 15
+	scryptR       = 8
+	scryptP       = 1
+	keyLength     = 32
+	saltLength    = 16
+	keyIDLength   = 8
+	minPassLength = 8
+)
+
+// ErrWrongPassphrase is returned when the passphrase does not open the encrypted lists
+var ErrWrongPassphrase = errors.New("wrong passphrase for the encrypted cache")
+
+var errNoPassphrase = errors.New("no passphrase is set for the cache; encrypt a list first")
+
+// KeySource supplies the key of the encrypted lists. The key derived from the
+// passphrase may be remembered between runs under an ID that changes with the
+// passphrase, so it is only asked for once per session.
+type KeySource interface {
+	// CachedKey returns the key remembered under id, if any
+	CachedKey(id string) ([]byte, bool)
+	// Passphrase asks for the passphrase; confirm is set when it is being chosen
+	Passphrase(confirm bool) (string, error)
+	// Remember keeps the key under id for later runs
+	Remember(id string, key []byte)
+}
+
+// Keys is where the passphrase of encrypted lists comes from. Reading or
+// writing an encrypted list fails while it is nil.
+var Keys KeySource
+
+// sealer seals and opens task content; a nil sealer stores plaintext
+type sealer struct {
+	aead cipher.AEAD
+}
+
+// newSealer returns a sealer for a derived key
+func newSealer(key []byte) (*sealer, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &sealer{aead: aead}, nil
+}
+
+// deriveKey derives the key of a passphrase with scrypt
+func deriveKey(passphrase string, salt []byte) ([]byte, error) {
+	return scrypt.Key([]byte(passphrase), salt, scryptN, scryptR, scryptP, keyLength)
+}
+
+// keyID names the key of a salt, for caching it outside the database
+func keyID(salt []byte) string {
+	sum := sha256.Sum256(salt)
+	return hex.EncodeToString(sum[:keyIDLength])
+}
+
+// isSealed reports whether a stored value is sealed
+func isSealed(value string) bool {
+	return strings.HasPrefix(value, sealedPrefix)
+}
+
+// seal returns the stored form of a value; empty values are kept empty
+func (s *sealer) seal(plain string) (string, error) {
+	if s == nil || plain == "" {
+		return plain, nil
+	}
+	nonce := make([]byte, s.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	sealed := s.aead.Seal(nonce, nonce, []byte(plain), nil)
+	return sealedPrefix + base64.RawStdEncoding.EncodeToString(sealed), nil
+}
+
+// open returns the plaintext of a stored value; plaintext values pass through
+func (s *sealer) open(value string) (string, error) {
+	encoded, ok := strings.CutPrefix(value, sealedPrefix)
+	if !ok {
+		return value, nil
+	}
+	if s == nil {
+		return "", errors.New("value is encrypted but no key is unlocked")
+	}
+	raw, err := base64.RawStdEncoding.DecodeString(encoded)
+	if err != nil || len(raw) < s.aead.NonceSize() {
+		return "", errors.New("encrypted value is corrupted")
+	}
+	nonceSize := s.aead.NonceSize()
+	plain, err := s.aead.Open(nil, raw[:nonceSize], raw[nonceSize:], nil)
+	if err != nil {
+		return "", errors.New("encrypted value does not open with the cache key")
+	}
+	return string(plain), nil
+}
+
+// sealedContent is the content of a task as stored
+type sealedContent struct {
+	summary     string
+	description sql.NullString
+	categories  sql.NullString
+}
+
+// sealTask returns the stored form of a task's summary, description and categories
+func (s *sealer) sealTask(task backend.Task) (sealedContent, error) {
+	var content sealedContent
+	var err error
+	if content.summary, err = s.seal(task.Summary); err != nil {
+		return content, err
+	}
+	description, err := s.seal(task.Description)
+	if err != nil {
+		return content, err
+	}
+	categories, err := s.seal(strings.Join(task.Categories, ","))
+	if err != nil {
+		return content, err
+	}
+	content.description = NullString(description)
+	content.categories = NullString(categories)
+	return content, nil
+}
+
+// unlock returns the sealer of the encrypted lists, asking Keys for the
+// passphrase the first time
+func (db *Database) unlock() (*sealer, error) {
+	db.keyMu.Lock()
+	defer db.keyMu.Unlock()
+	if db.sealer != nil {
+		return db.sealer, nil
+	}
+
+	var salt []byte
+	var verifier string
+	err := db.QueryRow("SELECT salt, verifier FROM encryption WHERE id = 1").Scan(&salt, &verifier)
+	if err == sql.ErrNoRows {
+		return nil, errNoPassphrase
+	} else if err != nil {
+		return nil, err
+	}
+	if Keys == nil {
+		return nil, errors.New("the cache has encrypted lists but no passphrase source is configured")
+	}
+
+	id := keyID(salt)
+	if key, ok := Keys.CachedKey(id); ok {
+		if s, err := checkKey(key, verifier); err == nil {
+			db.sealer = s
+			return s, nil
+		}
+	}
+
+	passphrase, err := Keys.Passphrase(false)
+	if err != nil {
+		return nil, err
+	}
+	key, err := deriveKey(passphrase, salt)
+	if err != nil {
+		return nil, err
+	}
+	s, err := checkKey(key, verifier)
+	if err != nil {
+		return nil, err
+	}
+	Keys.Remember(id, key)
+	db.sealer = s
+	return s, nil
+}
+
+// unlockOrChoose unlocks the encrypted lists, or has a passphrase chosen when
+// none is set yet
+func (db *Database) unlockOrChoose() (*sealer, error) {
+	var count int
+	if err := db.QueryRow("SELECT COUNT(*) FROM encryption").Scan(&count); err != nil {
+		return nil, err
+	}
+	if count > 0 {
+		return db.unlock()
+	}
+
+	db.keyMu.Lock()
+	defer db.keyMu.Unlock()
+	if Keys == nil {
+		return nil, errors.New("no passphrase source is configured")
+	}
+	passphrase, err := Keys.Passphrase(true)
+	if err != nil {
+		return nil, err
+	}
+	if len(passphrase) < minPassLength {
+		return nil, fmt.Errorf("the passphrase must be at least %d characters", minPassLength)
+	}
+
+	salt := make([]byte, saltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	key, err := deriveKey(passphrase, salt)
+	if err != nil {
+		return nil, err
+	}
+	s, err := newSealer(key)
+	if err != nil {
+		return nil, err
+	}
+	verifier, err := s.seal(verifierText)
+	if err != nil {
+		return nil, err
+	}
+	_, err = db.Exec("INSERT INTO encryption (id, salt, verifier, created_at) VALUES (1, ?, ?, ?)",
+		salt, verifier, time.Now().Unix())
+	if err != nil {
+		return nil, err
+	}
+	Keys.Remember(keyID(salt), key)
+	db.sealer = s
+	return s, nil
+}
+
+// checkKey returns a sealer for key if it opens the verifier
+func checkKey(key []byte, verifier string) (*sealer, error) {
+	s, err := newSealer(key)
+	if err != nil {
+		return nil, err
+	}
+	if text, err := s.open(verifier); err != nil || text != verifierText {
+		return nil, ErrWrongPassphrase
+	}
+	return s, nil
+}
+
+// CheckPassphrase reports whether passphrase opens the encrypted lists
+func (db *Database) CheckPassphrase(passphrase string) error {
+	var salt []byte
+	var verifier string
+	err := db.QueryRow("SELECT salt, verifier FROM encryption WHERE id = 1").Scan(&salt, &verifier)
+	if err == sql.ErrNoRows {
+		return errNoPassphrase
+	} else if err != nil {
+		return err
+	}
+	key, err := deriveKey(passphrase, salt)
+	if err != nil {
+		return err
+	}
+	_, err = checkKey(key, verifier)
+	return err
+}
+
+// isListEncrypted reports whether the task content of a list is stored encrypted
+func (sb *SQLiteBackend) isListEncrypted(db *Database, listID string) (bool, error) {
+	var encrypted bool
+	err := db.QueryRow("SELECT EXISTS(SELECT 1 FROM encrypted_lists WHERE backend_name = ? AND list_id = ?)",
+		sb.backendName, listID).Scan(&encrypted)
+	return encrypted, err
+}
+
+// sealerFor returns the sealer for writing to a list: nil for a plaintext list.
+// It is called before a write transaction begins, as it may ask for the passphrase.
+func (sb *SQLiteBackend) sealerFor(db *Database, listID string) (*sealer, error) {
+	encrypted, err := sb.isListEncrypted(db, listID)
+	if err != nil || !encrypted {
+		return nil, err
+	}
+	return db.unlock()
+}
+
+// openContent replaces sealed values with their plaintext, unlocking the
+// encrypted lists when one is met
+func (sb *SQLiteBackend) openContent(db *Database, values ...*string) error {
+	for _, value := range values {
+		if !isSealed(*value) {
+			continue
+		}
+		s, err := db.unlock()
+		if err != nil {
+			return err
+		}
+		if *value, err = s.open(*value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// EncryptedLists returns the IDs of the lists stored encrypted
+func (sb *SQLiteBackend) EncryptedLists() ([]string, error) {
+	db, err := sb.GetDB()
+	if err != nil {
+		return nil, &SQLiteError{Op: "EncryptedLists", Err: err}
+	}
+
+	rows, err := db.Query("SELECT list_id FROM encrypted_lists WHERE backend_name = ? ORDER BY list_id", sb.backendName)
+	if err != nil {
+		return nil, &SQLiteError{Op: "EncryptedLists", Err: err}
+	}
+	defer func() { _ = rows.Close() }()
+
+	var listIDs []string
+	for rows.Next() {
+		var listID string
+		if err := rows.Scan(&listID); err != nil {
+			return nil, &SQLiteError{Op: "EncryptedLists", Err: err}
+		}
+		listIDs = append(listIDs, listID)
+	}
+	return listIDs, rows.Err()
+}
+
+// EncryptList stores the task content of a list encrypted from now on and
+// seals what is already cached. The first list encrypted sets the passphrase.
+func (sb *SQLiteBackend) EncryptList(listID string) error {
+	db, err := sb.GetDB()
+	if err != nil {
+		return &SQLiteError{Op: "EncryptList", ListID: listID, Err: err}
+	}
+	s, err := db.unlockOrChoose()
+	if err != nil {
+		return &SQLiteError{Op: "EncryptList", ListID: listID, Err: err}
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return &SQLiteError{Op: "EncryptList", ListID: listID, Err: err}
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	_, err = tx.Exec("INSERT OR IGNORE INTO encrypted_lists (backend_name, list_id, encrypted_at) VALUES (?, ?, ?)",
+		sb.backendName, listID, time.Now().Unix())
+	if err != nil {
+		return &SQLiteError{Op: "EncryptList", ListID: listID, Err: err}
+	}
+	seal := func(value string) (string, error) {
+		if isSealed(value) {
+			return value, nil
+		}
+		return s.seal(value)
+	}
+	if err := sb.rewriteContent(tx, listID, seal); err != nil {
+		return &SQLiteError{Op: "EncryptList", ListID: listID, Err: err}
+	}
+
+	return tx.Commit()
+}
+
+// DecryptList stores the task content of an encrypted list as plaintext again
+func (sb *SQLiteBackend) DecryptList(listID string) error {
+	db, err := sb.GetDB()
+	if err != nil {
+		return &SQLiteError{Op: "DecryptList", ListID: listID, Err: err}
+	}
+	encrypted, err := sb.isListEncrypted(db, listID)
+	if err != nil {
+		return &SQLiteError{Op: "DecryptList", ListID: listID, Err: err}
+	}
+	if !encrypted {
+		return backend.NewBackendError("DecryptList", 404, fmt.Sprintf("list %s is not encrypted", listID))
+	}
+	s, err := db.unlock()
+	if err != nil {
+		return &SQLiteError{Op: "DecryptList", ListID: listID, Err: err}
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return &SQLiteError{Op: "DecryptList", ListID: listID, Err: err}
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if err := sb.rewriteContent(tx, listID, s.open); err != nil {
+		return &SQLiteError{Op: "DecryptList", ListID: listID, Err: err}
+	}
+	_, err = tx.Exec("DELETE FROM encrypted_lists WHERE backend_name = ? AND list_id = ?", sb.backendName, listID)
+	if err != nil {
+		return &SQLiteError{Op: "DecryptList", ListID: listID, Err: err}
+	}
+
+	return tx.Commit()
+}
+
+// rewriteContent passes the stored content of every task of a list, and its
+// former summaries, through transform inside tx
+func (sb *SQLiteBackend) rewriteContent(tx *sql.Tx, listID string, transform func(string) (string, error)) error {
+	type storedTask struct {
+		internalID                       int64
+		summary, description, categories string
+	}
+
+	rows, err := tx.Query(`
+		SELECT internal_id, summary, COALESCE(description, ''), COALESCE(categories, '')
+		FROM tasks WHERE backend_name = ? AND list_id = ?
+	`, sb.backendName, listID)
+	if err != nil {
+		return err
+	}
+	var tasks []storedTask
+	for rows.Next() {
+		var task storedTask
+		if err := rows.Scan(&task.internalID, &task.summary, &task.description, &task.categories); err != nil {
+			_ = rows.Close()
+			return err
+		}
+		tasks = append(tasks, task)
+	}
+	_ = rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, task := range tasks {
+		for _, value := range []*string{&task.summary, &task.description, &task.categories} {
+			if *value, err = transform(*value); err != nil {
+				return err
+			}
+		}
+		_, err = tx.Exec("UPDATE tasks SET summary = ?, description = ?, categories = ? WHERE internal_id = ?",
+			task.summary, NullString(task.description), NullString(task.categories), task.internalID)
+		if err != nil {
+			return err
+		}
+	}
+
+	aliases, err := listAliases(tx, `
+		SELECT a.id, a.former_summary FROM task_aliases a
+		JOIN tasks t ON t.internal_id = a.task_internal_id
+		WHERE t.backend_name = ? AND t.list_id = ?
+	`, sb.backendName, listID)
+	if err != nil {
+		return err
+	}
+	for id, summary := range aliases {
+		if summary, err = transform(summary); err != nil {
+			return err
+		}
+		if _, err := tx.Exec("UPDATE task_aliases SET former_summary = ? WHERE id = ?", summary, id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// findEncryptedTasks is FindTasksBySummary for an encrypted list: the tasks
+// are opened and matched in Go, then by their former summaries
+func (sb *SQLiteBackend) findEncryptedTasks(db *Database, listID, summary string) ([]backend.Task, error) {
+	rows, err := db.Query(`
+		SELECT internal_id, uid, list_id, summary, description, status, priority,
+		       created_at, modified_at, due_date, start_date, completed_at,
+		       parent_uid, categories, sequence, recurrence, all_day
+		FROM tasks
+		WHERE backend_name = ? AND list_id = ?
+		ORDER BY priority ASC, created_at DESC
+	`, sb.backendName, listID)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	all, err := sb.scanTasks(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	term := strings.ToLower(summary)
+	var tasks []backend.Task
+	for _, task := range all {
+		if strings.Contains(strings.ToLower(task.Summary), term) {
+			tasks = append(tasks, task)
+		}
+	}
+	if len(tasks) > 0 {
+		// Exact matches first, as in the LIKE query
+		sort.SliceStable(tasks, func(i, j int) bool {
+			return strings.EqualFold(tasks[i].Summary, summary) && !strings.EqualFold(tasks[j].Summary, summary)
+		})
+		return tasks, nil
+	}
+
+	// Fall back to former summaries
+	formerRows, err := db.Query(`
+		SELECT t.uid, a.former_summary FROM task_aliases a
+		JOIN tasks t ON t.internal_id = a.task_internal_id
+		WHERE t.backend_name = ? AND t.list_id = ?
+	`, sb.backendName, listID)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = formerRows.Close() }()
+
+	renamed := make(map[string]bool)
+	for formerRows.Next() {
+		var uid, former string
+		if err := formerRows.Scan(&uid, &former); err != nil {
+			return nil, err
+		}
+		if err := sb.openContent(db, &former); err != nil {
+			return nil, err
+		}
+		if strings.Contains(strings.ToLower(former), term) {
+			renamed[uid] = true
+		}
+	}
+	if err := formerRows.Err(); err != nil {
+		return nil, err
+	}
+	for _, task := range all {
+		if renamed[task.UID] {
+			tasks = append(tasks, task)
+		}
+	}
+	return tasks, nil
+}
+
+// countEncryptedCategories is CountCategories for an encrypted list
+func (sb *SQLiteBackend) countEncryptedCategories(db *Database, listID string) (map[string]int, error) {
+	rows, err := db.Query(`
+		SELECT t.categories
+		FROM tasks t
+		LEFT JOIN sync_metadata sm ON t.internal_id = sm.task_internal_id AND t.backend_name = sm.backend_name
+		WHERE t.backend_name = ? AND t.list_id = ?
+		  AND t.categories IS NOT NULL AND t.categories != ''
+		  AND (sm.locally_deleted IS NULL OR sm.locally_deleted = 0)
+	`, sb.backendName, listID)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	counts := make(map[string]int)
+	for rows.Next() {
+		var categories string
+		if err := rows.Scan(&categories); err != nil {
+			return nil, err
+		}
+		if err := sb.openContent(db, &categories); err != nil {
+			return nil, err
+		}
+		seen := make(map[string]bool)
+		for _, tag := range strings.Split(categories, ",") {
+			if tag != "" && !seen[tag] {
+				seen[tag] = true
+				counts[tag]++
+			}
+		}
+	}
+	return counts, rows.Err()
+}
@@ -0,0 +1,190 @@
+package sqlite
+
+import (
+	"errors"
+	"gosynctasks/backend"
+	"strings"
+	"testing"
+)
+
+// testKeys is a KeySource answering with a fixed passphrase
+type testKeys struct {
+	passphrase string
+	cached     map[string][]byte
+	asked      int
+}
+
+func (k *testKeys) CachedKey(id string) ([]byte, bool) {
+	key, ok := k.cached[id]
+	return key, ok
+}
+
+func (k *testKeys) Passphrase(confirm bool) (string, error) {
+	k.asked++
+	return k.passphrase, nil
+}
+
+func (k *testKeys) Remember(id string, key []byte) {
+	k.cached[id] = key
+}
+
+// useKeys installs keys as the KeySource for the test
+func useKeys(t *testing.T, passphrase string) *testKeys {
+	t.Helper()
+	keys := &testKeys{passphrase: passphrase, cached: make(map[string][]byte)}
+	previous := Keys
+	Keys = keys
+	t.Cleanup(func() { Keys = previous })
+	return keys
+}
+
+// storedContent returns the summary, description and categories of a task as stored
+func storedContent(t *testing.T, sb *SQLiteBackend, uid string) []string {
+	t.Helper()
+	var summary, description, categories string
+	err := sb.db.QueryRow("SELECT summary, COALESCE(description, ''), COALESCE(categories, '') FROM tasks WHERE uid = ?",
+		uid).Scan(&summary, &description, &categories)
+	if err != nil {
+		t.Fatalf("reading stored task: %v", err)
+	}
+	return []string{summary, description, categories}
+}
+
+func TestEncryptListCycle(t *testing.T) {
+	sb, cleanup := createTestSQLiteBackend(t)
+	defer cleanup()
+	useKeys(t, "correct horse battery")
+
+	listID, _ := sb.CreateTaskList("Clients", "", "")
+	otherID, _ := sb.CreateTaskList("Groceries", "", "")
+	uid, _ := sb.AddTask(listID, backend.Task{Summary: "Call ACME", Description: "About the ACME contract",
+		Categories: []string{"acme", "phone"}, Status: "NEEDS-ACTION"})
+	renameTask(t, sb, listID, uid, "Call ACME Corp")
+	plainUID, _ := sb.AddTask(otherID, backend.Task{Summary: "Milk", Status: "NEEDS-ACTION"})
+
+	if err := sb.EncryptList(listID); err != nil {
+		t.Fatalf("EncryptList() error = %v", err)
+	}
+
+	// Existing and new content is sealed, other lists are left alone
+	newUID, _ := sb.AddTask(listID, backend.Task{Summary: "Invoice Globex", Categories: []string{"globex"}, Status: "NEEDS-ACTION"})
+	if err := sb.InsertSyncedTask(listID, backend.Task{UID: "remote-1", Summary: "Pulled from ACME", Status: "NEEDS-ACTION"}); err != nil {
+		t.Fatalf("InsertSyncedTask() error = %v", err)
+	}
+	for _, id := range []string{uid, newUID, "remote-1"} {
+		for _, value := range storedContent(t, sb, id) {
+			if value != "" && !isSealed(value) {
+				t.Errorf("task %s stores %q in plaintext", id, value)
+			}
+		}
+	}
+	var alias string
+	_ = sb.db.QueryRow("SELECT former_summary FROM task_aliases").Scan(&alias)
+	if !isSealed(alias) {
+		t.Errorf("former summary stored as %q, want it sealed", alias)
+	}
+	if got := storedContent(t, sb, plainUID)[0]; got != "Milk" {
+		t.Errorf("plaintext list stores %q, want Milk", got)
+	}
+
+	// Reads, searches and counts see plaintext
+	task, err := sb.GetTask(listID, uid)
+	if err != nil || task.Summary != "Call ACME Corp" || task.Description != "About the ACME contract" || len(task.Categories) != 2 {
+		t.Fatalf("GetTask() = %+v, %v", task, err)
+	}
+	found, err := sb.FindTasksBySummary(listID, "acme")
+	if err != nil || len(found) != 2 {
+		t.Fatalf("FindTasksBySummary(acme) = %+v, %v, want 2 tasks", found, err)
+	}
+	found, err = sb.FindTasksBySummary(listID, "call acme corp")
+	if err != nil || len(found) != 1 || found[0].UID != uid {
+		t.Errorf("FindTasksBySummary(exact) = %+v, %v", found, err)
+	}
+	matches, err := sb.SearchAllLists("acme")
+	if err != nil || len(matches) != 1 || len(matches[listID]) != 2 {
+		t.Errorf("SearchAllLists(acme) = %+v, %v, want the 2 tasks of the encrypted list", matches, err)
+	}
+	found, err = sb.SearchTasks(listID, "contract acme")
+	if err != nil || len(found) != 1 || found[0].UID != uid {
+		t.Errorf("SearchTasks(contract acme) = %+v, %v", found, err)
+	}
+	renameTask(t, sb, listID, uid, "Call Initech")
+	found, err = sb.FindTasksBySummary(listID, "ACME Corp")
+	if err != nil || len(found) != 1 || found[0].UID != uid {
+		t.Errorf("FindTasksBySummary(former summary) = %+v, %v", found, err)
+	}
+	history, err := sb.GetSummaryHistory(uid)
+	if err != nil || len(history) != 2 || history[0].Summary != "Call ACME Corp" {
+		t.Errorf("GetSummaryHistory() = %+v, %v", history, err)
+	}
+	counts, err := sb.CountCategories(listID)
+	if err != nil || counts["acme"] != 1 || counts["globex"] != 1 {
+		t.Errorf("CountCategories() = %v, %v", counts, err)
+	}
+
+	if err := sb.DecryptList(listID); err != nil {
+		t.Fatalf("DecryptList() error = %v", err)
+	}
+	if got := storedContent(t, sb, uid); got[0] != "Call Initech" || got[2] != "acme,phone" {
+		t.Errorf("decrypted task stores %q", got)
+	}
+	_ = sb.db.QueryRow("SELECT former_summary FROM task_aliases ORDER BY id LIMIT 1").Scan(&alias)
+	if isSealed(alias) {
+		t.Errorf("former summary still sealed after DecryptList")
+	}
+	if listIDs, _ := sb.EncryptedLists(); len(listIDs) != 0 {
+		t.Errorf("EncryptedLists() = %v after DecryptList", listIDs)
+	}
+}
+
+func TestEncryptedListWrongPassphrase(t *testing.T) {
+	sb, cleanup := createTestSQLiteBackend(t)
+	defer cleanup()
+	keys := useKeys(t, "correct horse battery")
+
+	listID, _ := sb.CreateTaskList("Clients", "", "")
+	uid, _ := sb.AddTask(listID, backend.Task{Summary: "Call ACME", Status: "NEEDS-ACTION"})
+	if err := sb.EncryptList(listID); err != nil {
+		t.Fatalf("EncryptList() error = %v", err)
+	}
+
+	// A new process: the key is only in the session cache
+	reopened, err := NewSQLiteBackend(sb.Config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = reopened.Close() }()
+	if _, err := reopened.GetTask(listID, uid); err != nil || keys.asked != 1 {
+		t.Errorf("GetTask() with a cached key = %v, asked %d times, want only when choosing", err, keys.asked)
+	}
+
+	// Without the cached key, a wrong passphrase is refused on read and write
+	keys.cached = make(map[string][]byte)
+	keys.passphrase = "wrong horse battery"
+	for _, tt := range []struct {
+		name string
+		call func(sb *SQLiteBackend) error
+	}{
+		{"read", func(sb *SQLiteBackend) error { _, err := sb.GetTasks(listID, nil); return err }},
+		{"write", func(sb *SQLiteBackend) error {
+			_, err := sb.AddTask(listID, backend.Task{Summary: "Another", Status: "NEEDS-ACTION"})
+			return err
+		}},
+		{"decrypt", func(sb *SQLiteBackend) error { return sb.DecryptList(listID) }},
+	} {
+		fresh, err := NewSQLiteBackend(sb.Config)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = tt.call(fresh)
+		_ = fresh.Close()
+		if !errors.Is(err, ErrWrongPassphrase) {
+			t.Errorf("%s with a wrong passphrase: error = %v, want ErrWrongPassphrase", tt.name, err)
+		}
+	}
+
+	// The content was not touched
+	if got := storedContent(t, sb, uid)[0]; !isSealed(got) || strings.Contains(got, "ACME") {
+		t.Errorf("stored summary = %q, want it still sealed", got)
+	}
+}
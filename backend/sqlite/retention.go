@@ -0,0 +1,157 @@
+package sqlite
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// prunableTasks selects the completed and cancelled tasks closed before a
+// cutoff that can leave the cache: nothing of them waits to be pushed, and
+// no task has them as parent, so subtasks go first and open ones keep theirs
+const prunableTasks = `
+	SELECT t.internal_id, t.backend_name, t.list_id, t.uid, t.modified_at
+	FROM tasks t
+	WHERE t.status IN ('COMPLETED', 'DONE', 'CANCELLED')
+	  AND COALESCE(t.completed_at, t.modified_at) < ?
+	  AND NOT EXISTS (SELECT 1 FROM sync_queue q WHERE q.task_internal_id = t.internal_id)
+	  AND NOT EXISTS (
+		SELECT 1 FROM sync_metadata m
+		WHERE m.task_internal_id = t.internal_id AND (m.locally_modified = 1 OR m.locally_deleted = 1)
+	  )
+	  AND NOT EXISTS (SELECT 1 FROM tasks c WHERE c.parent_uid = t.uid)
+`
+
+// PruneCompleted removes the completed and cancelled tasks closed before
+// cutoff from the cache of every backend, with their sync metadata, and
+// records them in pruned_tasks. The remote keeps them. Tasks with changes
+// waiting to be pushed and open tasks are never touched. It returns the
+// number of tasks pruned.
+func (db *Database) PruneCompleted(cutoff time.Time) (int, error) {
+	tx, err := db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("failed to prune completed tasks: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	now := time.Now().Unix()
+	pruned := 0
+	for {
+		rows, err := tx.Query(prunableTasks, cutoff.Unix())
+		if err != nil {
+			return 0, fmt.Errorf("failed to prune completed tasks: %w", err)
+		}
+		type prunable struct {
+			internalID          int64
+			backendName, listID string
+			uid                 string
+			modifiedAt          sql.NullInt64
+		}
+		var batch []prunable
+		for rows.Next() {
+			var task prunable
+			if err := rows.Scan(&task.internalID, &task.backendName, &task.listID, &task.uid, &task.modifiedAt); err != nil {
+				_ = rows.Close()
+				return 0, fmt.Errorf("failed to prune completed tasks: %w", err)
+			}
+			batch = append(batch, task)
+		}
+		_ = rows.Close()
+		if err := rows.Err(); err != nil {
+			return 0, fmt.Errorf("failed to prune completed tasks: %w", err)
+		}
+		if len(batch) == 0 {
+			break
+		}
+
+		for _, task := range batch {
+			_, err := tx.Exec(`
+				INSERT OR REPLACE INTO pruned_tasks (backend_name, list_id, uid, modified_at, pruned_at)
+				VALUES (?, ?, ?, ?, ?)
+			`, task.backendName, task.listID, task.uid, task.modifiedAt, now)
+			if err == nil {
+				_, err = tx.Exec("DELETE FROM sync_metadata WHERE task_internal_id = ?", task.internalID)
+			}
+			if err == nil {
+				_, err = tx.Exec("DELETE FROM tasks WHERE internal_id = ?", task.internalID)
+			}
+			if err != nil {
+				return 0, fmt.Errorf("failed to prune task %s: %w", task.uid, err)
+			}
+		}
+		pruned += len(batch)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to prune completed tasks: %w", err)
+	}
+	return pruned, nil
+}
+
+// HasPrunable reports whether PruneCompleted with the same cutoff would
+// remove any task
+func (db *Database) HasPrunable(cutoff time.Time) (bool, error) {
+	var prunable bool
+	if err := db.QueryRow("SELECT EXISTS ("+prunableTasks+")", cutoff.Unix()).Scan(&prunable); err != nil {
+		return false, fmt.Errorf("failed to look for completed tasks to prune: %w", err)
+	}
+	return prunable, nil
+}
+
+// PrunedTasks returns the tasks of a list pruned from the cache, by UID, with
+// their last modification when they were pruned
+func (sb *SQLiteBackend) PrunedTasks(listID string) (map[string]time.Time, error) {
+	db, err := sb.GetDB()
+	if err != nil {
+		return nil, &SQLiteError{Op: "PrunedTasks", ListID: listID, Err: err}
+	}
+
+	rows, err := db.Query(`
+		SELECT uid, COALESCE(modified_at, 0) FROM pruned_tasks
+		WHERE backend_name = ? AND list_id = ?
+	`, sb.backendName, listID)
+	if err != nil {
+		return nil, &SQLiteError{Op: "PrunedTasks", ListID: listID, Err: err}
+	}
+	defer func() { _ = rows.Close() }()
+
+	pruned := make(map[string]time.Time)
+	for rows.Next() {
+		var uid string
+		var modifiedAt int64
+		if err := rows.Scan(&uid, &modifiedAt); err != nil {
+			return nil, &SQLiteError{Op: "PrunedTasks", ListID: listID, Err: err}
+		}
+		pruned[uid] = time.Unix(modifiedAt, 0)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, &SQLiteError{Op: "PrunedTasks", ListID: listID, Err: err}
+	}
+	return pruned, nil
+}
+
+// ForgetPrunedTasks drops the records of pruned tasks, once they are gone
+// from the remote too
+func (sb *SQLiteBackend) ForgetPrunedTasks(listID string, uids []string) error {
+	if len(uids) == 0 {
+		return nil
+	}
+	db, err := sb.GetDB()
+	if err != nil {
+		return &SQLiteError{Op: "ForgetPrunedTasks", ListID: listID, Err: err}
+	}
+
+	args := []any{sb.backendName, listID}
+	for _, uid := range uids {
+		args = append(args, uid)
+	}
+	_, err = db.Exec(`
+		DELETE FROM pruned_tasks
+		WHERE backend_name = ? AND list_id = ? AND uid IN (?`+strings.Repeat(", ?", len(uids)-1)+`)
+	`, args...)
+	if err != nil {
+		return &SQLiteError{Op: "ForgetPrunedTasks", ListID: listID, Err: err}
+	}
+	return nil
+}
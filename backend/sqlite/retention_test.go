@@ -0,0 +1,106 @@
+package sqlite
+
+import (
+	"gosynctasks/backend"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestPruneCompleted tests that pruning removes the completed and cancelled
+// tasks closed before the cutoff, and keeps open tasks, recent ones, tasks
+// with queued changes and parents of remaining tasks
+func TestPruneCompleted(t *testing.T) {
+	sb, err := NewSQLiteBackend(backend.BackendConfig{Name: "remote", Type: "sqlite", DBPath: filepath.Join(t.TempDir(), "cache.db")})
+	if err != nil {
+		t.Fatalf("Failed to create SQLite backend: %v", err)
+	}
+	defer sb.Close()
+	listID, _ := sb.CreateTaskList("Work", "", "")
+
+	now := time.Now()
+	old := now.AddDate(0, 0, -200)
+	recent := now.AddDate(0, 0, -10)
+	synced := []backend.Task{
+		{UID: "old-done", Summary: "Old done", Status: "COMPLETED", Completed: &old, Modified: old},
+		{UID: "old-cancelled", Summary: "Old cancelled", Status: "CANCELLED", Modified: old},
+		{UID: "recent-done", Summary: "Recent done", Status: "COMPLETED", Completed: &recent, Modified: recent},
+		{UID: "old-open", Summary: "Old open", Status: "NEEDS-ACTION", Modified: old},
+		{UID: "old-parent", Summary: "Old parent", Status: "COMPLETED", Completed: &old, Modified: old},
+		{UID: "open-child", Summary: "Open child", Status: "NEEDS-ACTION", ParentUID: "old-parent", Modified: old},
+		{UID: "old-grandparent", Summary: "Old grandparent", Status: "COMPLETED", Completed: &old, Modified: old},
+		{UID: "old-child", Summary: "Old child", Status: "COMPLETED", Completed: &old, ParentUID: "old-grandparent", Modified: old},
+		{UID: "old-queued", Summary: "Old queued", Status: "COMPLETED", Completed: &old, Modified: old},
+	}
+	for _, task := range synced {
+		if err := sb.InsertSyncedTask(listID, task); err != nil {
+			t.Fatalf("InsertSyncedTask(%s) error = %v", task.UID, err)
+		}
+	}
+	// A local edit waiting to be pushed; the task still closed long ago
+	queued, _ := sb.GetTask(listID, "old-queued")
+	queued.Description = "Edited offline"
+	if err := sb.UpdateTask(listID, *queued); err != nil {
+		t.Fatalf("UpdateTask() error = %v", err)
+	}
+	db, _ := sb.GetDB()
+
+	if prunable, err := db.HasPrunable(now.AddDate(0, 0, -180)); err != nil || !prunable {
+		t.Errorf("HasPrunable() = %v, %v, want true", prunable, err)
+	}
+	pruned, err := db.PruneCompleted(now.AddDate(0, 0, -180))
+	if err != nil {
+		t.Fatalf("PruneCompleted() error = %v", err)
+	}
+	if pruned != 4 {
+		t.Errorf("PruneCompleted() = %d, want 4", pruned)
+	}
+
+	tasks, _ := sb.GetTasks(listID, nil)
+	var kept []string
+	for _, task := range tasks {
+		kept = append(kept, task.UID)
+	}
+	sort.Strings(kept)
+	if got, want := strings.Join(kept, ","), "old-open,old-parent,old-queued,open-child,recent-done"; got != want {
+		t.Errorf("kept %s, want %s", got, want)
+	}
+
+	var metadata int
+	_ = db.QueryRow("SELECT COUNT(*) FROM sync_metadata").Scan(&metadata)
+	if metadata != len(kept) {
+		t.Errorf("sync_metadata has %d rows, want %d, one per kept task", metadata, len(kept))
+	}
+
+	tombstones, err := sb.PrunedTasks(listID)
+	if err != nil || len(tombstones) != 4 || tombstones["old-done"].Unix() != old.Unix() {
+		t.Errorf("PrunedTasks() = %v, %v, want the 4 pruned tasks with their modification time", tombstones, err)
+	}
+	if stats, _ := db.GetStats(); stats.PrunedTasks != 4 {
+		t.Errorf("GetStats().PrunedTasks = %d, want 4", stats.PrunedTasks)
+	}
+
+	// Pruning again finds nothing new
+	if pruned, _ := db.PruneCompleted(now.AddDate(0, 0, -180)); pruned != 0 {
+		t.Errorf("second PruneCompleted() = %d, want 0", pruned)
+	}
+	if prunable, _ := db.HasPrunable(now.AddDate(0, 0, -180)); prunable {
+		t.Error("HasPrunable() after pruning = true, want false")
+	}
+
+	// A pruned task pulled again is cached again, and forgotten tasks are dropped
+	reopened := synced[0]
+	reopened.Status, reopened.Completed, reopened.Modified = "NEEDS-ACTION", nil, now
+	if err := sb.InsertSyncedTask(listID, reopened); err != nil {
+		t.Fatalf("InsertSyncedTask() of a pruned task error = %v", err)
+	}
+	if err := sb.ForgetPrunedTasks(listID, []string{"old-cancelled"}); err != nil {
+		t.Fatalf("ForgetPrunedTasks() error = %v", err)
+	}
+	tombstones, _ = sb.PrunedTasks(listID)
+	if _, ok := tombstones["old-done"]; ok || len(tombstones) != 2 {
+		t.Errorf("PrunedTasks() = %v, want old-child and old-grandparent", tombstones)
+	}
+}
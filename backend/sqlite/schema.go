@@ -1,7 +1,7 @@
 package sqlite
 
 // Schema version for migration management
-const SchemaVersion = 3  // Incremented for internal_id/uid separation
+const SchemaVersion = 14 // Incremented for tasks.all_day
 
 // SQL statements for database schema creation
 
@@ -23,6 +23,9 @@ CREATE TABLE IF NOT EXISTS tasks (
     completed_at INTEGER,
     parent_uid TEXT,
     categories TEXT,
+    sequence INTEGER NOT NULL DEFAULT 0,  -- iCalendar SEQUENCE as last seen on the remote
+    recurrence TEXT,  -- RFC 5545 RRULE value, without the RRULE: name
+    all_day INTEGER NOT NULL DEFAULT 0,  -- due_date and start_date are dates, at local midnight
 
     FOREIGN KEY(parent_uid) REFERENCES tasks(uid) ON DELETE SET NULL
 );
@@ -57,7 +60,10 @@ CREATE TABLE IF NOT EXISTS list_sync_metadata (
     list_id TEXT NOT NULL,
     backend_name TEXT NOT NULL DEFAULT '',
     list_name TEXT NOT NULL,
+    list_path TEXT,             -- Qualified name of nested lists (e.g. "Work/Clients/ACME")
     list_color TEXT,
+    list_position INTEGER,      -- Place in the remote's list order, from 1
+    archived INTEGER NOT NULL DEFAULT 0,  -- Set when the remote archived the list; hidden and not pulled
 
     -- Sync state tracking
     last_ctag TEXT,
@@ -83,6 +89,8 @@ CREATE TABLE IF NOT EXISTS sync_queue (
     created_at INTEGER NOT NULL,
     retry_count INTEGER DEFAULT 0,
     last_error TEXT,
+    stranded INTEGER NOT NULL DEFAULT 0,  -- Set when the list is gone from the remote; not retried until rescued
+    next_retry_at INTEGER,                -- Unix time before which a failed operation is not pushed again
 
     -- Ensure we don't queue duplicate operations for the same task per backend
     UNIQUE(backend_name, task_internal_id, operation),
@@ -91,6 +99,84 @@ CREATE TABLE IF NOT EXISTS sync_queue (
 );
 `
 
+// TaskAliasesTableSQL creates the table of former task summaries used for rename-tolerant matching
+const TaskAliasesTableSQL = `
+CREATE TABLE IF NOT EXISTS task_aliases (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    task_internal_id INTEGER NOT NULL,
+    former_summary TEXT NOT NULL,
+    changed_at INTEGER NOT NULL,
+
+    FOREIGN KEY(task_internal_id) REFERENCES tasks(internal_id) ON DELETE CASCADE
+);
+`
+
+// EncryptionTableSQL creates the single-row table holding the salt of the
+// cache passphrase and a value sealed with the derived key to check it
+const EncryptionTableSQL = `
+CREATE TABLE IF NOT EXISTS encryption (
+    id INTEGER PRIMARY KEY CHECK(id = 1),
+    salt BLOB NOT NULL,
+    verifier TEXT NOT NULL,
+    created_at INTEGER NOT NULL
+);
+`
+
+// EncryptedListsTableSQL creates the table of lists whose task content is stored encrypted
+const EncryptedListsTableSQL = `
+CREATE TABLE IF NOT EXISTS encrypted_lists (
+    backend_name TEXT NOT NULL,
+    list_id TEXT NOT NULL,
+    encrypted_at INTEGER NOT NULL,
+
+    PRIMARY KEY(backend_name, list_id)
+);
+`
+
+// PrunedTasksTableSQL creates the table of completed tasks removed from the
+// cache by the retention policy. They stay on the remote, and pull leaves
+// them out until they change there.
+const PrunedTasksTableSQL = `
+CREATE TABLE IF NOT EXISTS pruned_tasks (
+    backend_name TEXT NOT NULL DEFAULT '',
+    list_id TEXT NOT NULL,
+    uid TEXT NOT NULL,
+    modified_at INTEGER,        -- Last modification of the task when it was pruned
+    pruned_at INTEGER NOT NULL,
+
+    PRIMARY KEY(backend_name, uid)
+);
+`
+
+// TasksFTSTableSQL creates the full-text index of the task content, kept by
+// the triggers of TasksFTSTriggersSQL. It needs SQLite built with FTS5; see
+// Database.ensureFullTextIndex.
+const TasksFTSTableSQL = `
+CREATE VIRTUAL TABLE IF NOT EXISTS tasks_fts USING fts5(
+    summary, description, categories,
+    content='tasks', content_rowid='internal_id',
+    tokenize='unicode61 remove_diacritics 2'
+);
+`
+
+// TasksFTSTriggersSQL keeps tasks_fts in step with the tasks table
+const TasksFTSTriggersSQL = `
+CREATE TRIGGER IF NOT EXISTS tasks_fts_insert AFTER INSERT ON tasks BEGIN
+    INSERT INTO tasks_fts(rowid, summary, description, categories)
+    VALUES (new.internal_id, new.summary, new.description, new.categories);
+END;
+CREATE TRIGGER IF NOT EXISTS tasks_fts_delete AFTER DELETE ON tasks BEGIN
+    INSERT INTO tasks_fts(tasks_fts, rowid, summary, description, categories)
+    VALUES ('delete', old.internal_id, old.summary, old.description, old.categories);
+END;
+CREATE TRIGGER IF NOT EXISTS tasks_fts_update AFTER UPDATE OF summary, description, categories ON tasks BEGIN
+    INSERT INTO tasks_fts(tasks_fts, rowid, summary, description, categories)
+    VALUES ('delete', old.internal_id, old.summary, old.description, old.categories);
+    INSERT INTO tasks_fts(rowid, summary, description, categories)
+    VALUES (new.internal_id, new.summary, new.description, new.categories);
+END;
+`
+
 // SchemaVersionTableSQL creates the schema version table for migration tracking
 const SchemaVersionTableSQL = `
 CREATE TABLE IF NOT EXISTS schema_version (
@@ -130,6 +216,12 @@ CREATE INDEX IF NOT EXISTS idx_sync_queue_backend_operation ON sync_queue(backen
 CREATE INDEX IF NOT EXISTS idx_sync_queue_operation ON sync_queue(operation);
 CREATE INDEX IF NOT EXISTS idx_sync_queue_created_at ON sync_queue(created_at);
 CREATE INDEX IF NOT EXISTS idx_sync_queue_retry_count ON sync_queue(retry_count);
+CREATE INDEX IF NOT EXISTS idx_sync_queue_task ON sync_queue(task_internal_id);
+`
+
+// TaskAliasesIndexesSQL creates indexes on task_aliases table
+const TaskAliasesIndexesSQL = `
+CREATE INDEX IF NOT EXISTS idx_task_aliases_task ON task_aliases(task_internal_id);
 `
 
 // AllTableSchemas returns all table creation statements in order
@@ -140,6 +232,10 @@ func AllTableSchemas() []string {
 		SyncMetadataTableSQL,
 		ListSyncMetadataTableSQL,
 		SyncQueueTableSQL,
+		TaskAliasesTableSQL,
+		EncryptionTableSQL,
+		EncryptedListsTableSQL,
+		PrunedTasksTableSQL,
 	}
 }
 
@@ -149,6 +245,30 @@ func AllIndexes() []string {
 		TasksIndexesSQL,
 		SyncMetadataIndexesSQL,
 		SyncQueueIndexesSQL,
+		TaskAliasesIndexesSQL,
+	}
+}
+
+// ColumnAddition describes a column added to a table after it was first released.
+// CREATE TABLE IF NOT EXISTS leaves existing tables alone, so these are applied
+// with ALTER TABLE when missing.
+type ColumnAddition struct {
+	Table      string
+	Column     string
+	Definition string
+}
+
+// AllColumnAdditions returns the columns to add to databases created by older versions
+func AllColumnAdditions() []ColumnAddition {
+	return []ColumnAddition{
+		{Table: "list_sync_metadata", Column: "list_path", Definition: "TEXT"},
+		{Table: "list_sync_metadata", Column: "list_position", Definition: "INTEGER"},
+		{Table: "sync_queue", Column: "stranded", Definition: "INTEGER NOT NULL DEFAULT 0"},
+		{Table: "tasks", Column: "sequence", Definition: "INTEGER NOT NULL DEFAULT 0"},
+		{Table: "list_sync_metadata", Column: "archived", Definition: "INTEGER NOT NULL DEFAULT 0"},
+		{Table: "sync_queue", Column: "next_retry_at", Definition: "INTEGER"},
+		{Table: "tasks", Column: "recurrence", Definition: "TEXT"},
+		{Table: "tasks", Column: "all_day", Definition: "INTEGER NOT NULL DEFAULT 0"},
 	}
 }
 
@@ -158,5 +278,19 @@ func PragmaStatements() []string {
 		"PRAGMA foreign_keys = ON",
 		"PRAGMA journal_mode = WAL",   // Write-Ahead Logging for better concurrency
 		"PRAGMA synchronous = NORMAL", // Balance between safety and performance
+		"PRAGMA temp_store = MEMORY",  // Keep temp tables and sort buffers off disk
+		"PRAGMA busy_timeout = 5000",  // Wait for the writes of concurrent pushes instead of failing
+	}
+}
+
+// ConnectionPragmas returns the per-connection pragmas in DSN form (name(value)).
+// They mirror PragmaStatements and are applied to every connection the pool opens.
+func ConnectionPragmas() []string {
+	return []string{
+		"foreign_keys(1)",
+		"journal_mode(WAL)",
+		"synchronous(NORMAL)",
+		"temp_store(MEMORY)",
+		"busy_timeout(5000)",
 	}
 }
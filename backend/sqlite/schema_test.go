@@ -1,9 +1,12 @@
 package sqlite
 
 import (
+	"context"
 	"database/sql"
 	"os"
 	"path/filepath"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 )
@@ -49,6 +52,10 @@ func TestAllTablesCreated(t *testing.T) {
 		"list_sync_metadata",
 		"sync_queue",
 		"schema_version",
+		"task_aliases",
+		"encryption",
+		"encrypted_lists",
+		"pruned_tasks",
 	}
 
 	for _, table := range expectedTables {
@@ -187,6 +194,45 @@ func TestListSyncMetadataTableSchema(t *testing.T) {
 	}
 }
 
+// TestColumnAdditionsOnExistingDatabase tests that columns added in later
+// schema versions are created on databases from before them
+func TestColumnAdditionsOnExistingDatabase(t *testing.T) {
+	tmpDir := t.TempDir()
+	dbPath := filepath.Join(tmpDir, "test.db")
+
+	oldTable := strings.Replace(ListSyncMetadataTableSQL, "list_path TEXT,", "", 1)
+	raw, err := sql.Open("sqlite", dbPath)
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	if _, err := raw.Exec(oldTable); err != nil {
+		t.Fatalf("Failed to create old table: %v", err)
+	}
+	if _, err := raw.Exec(`INSERT INTO list_sync_metadata (list_id, list_name) VALUES ('list-1', 'Acme')`); err != nil {
+		t.Fatalf("Failed to insert old row: %v", err)
+	}
+	_ = raw.Close()
+
+	db, err := InitDatabase(dbPath)
+	if err != nil {
+		t.Fatalf("Failed to initialize database: %v", err)
+	}
+	defer func() { _ = db.Close() }()
+
+	_, err = db.Exec("UPDATE list_sync_metadata SET list_path = ? WHERE list_id = ?", "Work/Acme", "list-1")
+	if err != nil {
+		t.Fatalf("Failed to set list_path after migration: %v", err)
+	}
+
+	var listPath string
+	if err := db.QueryRow("SELECT list_path FROM list_sync_metadata WHERE list_id = ?", "list-1").Scan(&listPath); err != nil {
+		t.Fatalf("Failed to query list_path: %v", err)
+	}
+	if listPath != "Work/Acme" {
+		t.Errorf("Expected list_path 'Work/Acme', got '%s'", listPath)
+	}
+}
+
 // TestSyncQueueTableSchema tests the sync_queue table schema
 func TestSyncQueueTableSchema(t *testing.T) {
 	tmpDir := t.TempDir()
@@ -358,6 +404,7 @@ func TestIndexesCreated(t *testing.T) {
 		"idx_sync_queue_operation",
 		"idx_sync_queue_created_at",
 		"idx_sync_queue_retry_count",
+		"idx_sync_queue_task",
 	}
 
 	for _, index := range expectedIndexes {
@@ -479,6 +526,23 @@ func TestDatabaseStats(t *testing.T) {
 	if stats.DatabaseSize == 0 {
 		t.Error("Expected non-zero database size")
 	}
+	if stats.SchemaVersion != SchemaVersion {
+		t.Errorf("Expected schema version %d, got %d", SchemaVersion, stats.SchemaVersion)
+	}
+	for table, want := range map[string]int{"tasks": 1, "list_sync_metadata": 1, "sync_queue": 1, "task_aliases": 0, "schema_version": 1} {
+		if got, ok := stats.TableRows[table]; !ok || got != want {
+			t.Errorf("TableRows[%s] = %d (present: %v), want %d", table, got, ok, want)
+		}
+	}
+	for table := range stats.TableRows {
+		if strings.HasPrefix(table, "sqlite_") {
+			t.Errorf("TableRows includes internal table %s", table)
+		}
+	}
+	wantIndexes := strings.Count(strings.Join(AllIndexes(), "\n"), "CREATE INDEX")
+	if len(stats.Indexes) != wantIndexes {
+		t.Errorf("Expected %d indexes, got %d: %v", wantIndexes, len(stats.Indexes), stats.Indexes)
+	}
 
 	// Test String() method
 	statsStr := stats.String()
@@ -674,3 +738,109 @@ func TestVacuum(t *testing.T) {
 		t.Errorf("Failed to vacuum database: %v", err)
 	}
 }
+
+// TestPragmasAppliedToEveryConnection checks that pooled connections share the tuned pragmas
+func TestPragmasAppliedToEveryConnection(t *testing.T) {
+	db, err := InitDatabase(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to initialize database: %v", err)
+	}
+	defer func() { _ = db.Close() }()
+
+	// Hold one connection so the next query must open a second one
+	held, err := db.Conn(context.Background())
+	if err != nil {
+		t.Fatalf("Failed to get connection: %v", err)
+	}
+	defer func() { _ = held.Close() }()
+
+	var synchronous, foreignKeys, tempStore int
+	if err := db.QueryRow("PRAGMA synchronous").Scan(&synchronous); err != nil {
+		t.Fatalf("Failed to read synchronous: %v", err)
+	}
+	if err := db.QueryRow("PRAGMA foreign_keys").Scan(&foreignKeys); err != nil {
+		t.Fatalf("Failed to read foreign_keys: %v", err)
+	}
+	if err := db.QueryRow("PRAGMA temp_store").Scan(&tempStore); err != nil {
+		t.Fatalf("Failed to read temp_store: %v", err)
+	}
+
+	if synchronous != 1 {
+		t.Errorf("synchronous = %d, want 1 (NORMAL)", synchronous)
+	}
+	if foreignKeys != 1 {
+		t.Errorf("foreign_keys = %d, want 1", foreignKeys)
+	}
+	if tempStore != 2 {
+		t.Errorf("temp_store = %d, want 2 (MEMORY)", tempStore)
+	}
+}
+
+// TestPreparedStatementCacheConcurrent checks the statement cache under concurrent use
+func TestPreparedStatementCacheConcurrent(t *testing.T) {
+	db, err := InitDatabase(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to initialize database: %v", err)
+	}
+	defer func() { _ = db.Close() }()
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 20)
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			stmt, err := db.prepared("SELECT COUNT(*) FROM tasks")
+			if err != nil {
+				errs <- err
+				return
+			}
+			var count int
+			if err := stmt.QueryRow().Scan(&count); err != nil {
+				errs <- err
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Errorf("concurrent prepared query failed: %v", err)
+	}
+	if len(db.stmts) != 1 {
+		t.Errorf("cached %d statements, want 1", len(db.stmts))
+	}
+}
+
+// TestCheckFlagsEmptyUIDs tests that Check reports tasks with an empty UID
+func TestCheckFlagsEmptyUIDs(t *testing.T) {
+	tmpDir := t.TempDir()
+	dbPath := filepath.Join(tmpDir, "test.db")
+
+	db, err := InitDatabase(dbPath)
+	if err != nil {
+		t.Fatalf("Failed to initialize database: %v", err)
+	}
+	defer func() { _ = db.Close() }()
+
+	issues, err := db.Check()
+	if err != nil {
+		t.Fatalf("Check failed: %v", err)
+	}
+	if len(issues) != 0 {
+		t.Errorf("Expected no issues on empty database, got %v", issues)
+	}
+
+	_, err = db.Exec(`INSERT INTO tasks (uid, list_id, summary, status) VALUES ('', 'list-1', 'Broken', 'NEEDS-ACTION')`)
+	if err != nil {
+		t.Fatalf("Failed to insert task: %v", err)
+	}
+
+	issues, err = db.Check()
+	if err != nil {
+		t.Fatalf("Check failed: %v", err)
+	}
+	if len(issues) != 1 || issues[0].Rule != "empty-uid" || issues[0].Count != 1 {
+		t.Errorf("Expected one empty-uid issue, got %v", issues)
+	}
+}
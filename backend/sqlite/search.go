@@ -0,0 +1,153 @@
+package sqlite
+
+import (
+	"database/sql"
+	"gosynctasks/backend"
+	"strings"
+)
+
+// ensureFullTextIndex creates tasks_fts and its triggers, filling the index
+// from the tasks already stored when the table is new. SQLite built without
+// FTS5 leaves fullText unset, and searches fall back to LIKE.
+func (db *Database) ensureFullTextIndex() error {
+	var exists bool
+	err := db.QueryRow("SELECT EXISTS(SELECT 1 FROM sqlite_master WHERE type = 'table' AND name = 'tasks_fts')").Scan(&exists)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		if _, err := db.Exec(TasksFTSTableSQL); err != nil {
+			if strings.Contains(err.Error(), "no such module") {
+				return nil
+			}
+			return err
+		}
+	}
+	if _, err := db.Exec(TasksFTSTriggersSQL); err != nil {
+		return err
+	}
+	if !exists {
+		// Databases of older versions have tasks to index
+		if _, err := db.Exec("INSERT INTO tasks_fts(tasks_fts) VALUES ('rebuild')"); err != nil {
+			return err
+		}
+	}
+	db.fullText = true
+	return nil
+}
+
+// fullTextQuery turns the words of query into an FTS5 query matching tasks
+// holding all of them, each as a word or the start of one. Words are quoted,
+// so FTS5 operators in them are taken literally.
+func fullTextQuery(words []string) string {
+	terms := make([]string, len(words))
+	for i, word := range words {
+		terms[i] = `"` + strings.ReplaceAll(word, `"`, `""`) + `"*`
+	}
+	return strings.Join(terms, " ")
+}
+
+// SearchTasks returns the tasks of a list whose summary, description or
+// categories hold every word of query, ignoring case and accents, best
+// matches first: matches in the summary rank above those in the categories,
+// and those above the description. Words also match the start of longer
+// words. Without FTS5, and for encrypted lists, the words are matched as
+// substrings instead, ordered as FindTasksBySummary orders its results.
+func (sb *SQLiteBackend) SearchTasks(listID, query string) ([]backend.Task, error) {
+	words := strings.Fields(query)
+	if len(words) == 0 {
+		return nil, nil
+	}
+	db, err := sb.GetDB()
+	if err != nil {
+		return nil, &SQLiteError{Op: "SearchTasks", ListID: listID, Err: err}
+	}
+	encrypted, err := sb.isListEncrypted(db, listID)
+	if err != nil {
+		return nil, &SQLiteError{Op: "SearchTasks", ListID: listID, Err: err}
+	}
+
+	var tasks []backend.Task
+	switch {
+	case encrypted:
+		tasks, err = sb.searchEncryptedTasks(db, listID, words)
+	case db.fullText:
+		tasks, err = sb.queryTasks(db, `
+			SELECT t.internal_id, t.uid, t.list_id, t.summary, t.description, t.status, t.priority,
+			       t.created_at, t.modified_at, t.due_date, t.start_date, t.completed_at,
+			       t.parent_uid, t.categories, t.sequence, t.recurrence, t.all_day
+			FROM tasks_fts
+			JOIN tasks t ON t.internal_id = tasks_fts.rowid
+			WHERE tasks_fts MATCH ? AND t.backend_name = ? AND t.list_id = ?
+			ORDER BY bm25(tasks_fts, 10.0, 1.0, 5.0), t.priority ASC
+		`, fullTextQuery(words), sb.backendName, listID)
+	default:
+		tasks, err = sb.searchTasksLike(db, listID, words)
+	}
+	if err != nil {
+		return nil, &SQLiteError{Op: "SearchTasks", ListID: listID, Err: err}
+	}
+	return tasks, nil
+}
+
+// searchTasksLike is SearchTasks without FTS5: every word is a substring of
+// the summary, description or categories
+func (sb *SQLiteBackend) searchTasksLike(db *Database, listID string, words []string) ([]backend.Task, error) {
+	conditions := make([]string, len(words))
+	args := []any{sb.backendName, listID}
+	for i, word := range words {
+		conditions[i] = `(LOWER(summary) LIKE LOWER(?) ESCAPE '\' OR LOWER(COALESCE(description, '')) LIKE LOWER(?) ESCAPE '\' OR LOWER(COALESCE(categories, '')) LIKE LOWER(?) ESCAPE '\')`
+		pattern := "%" + likeEscaper.Replace(word) + "%"
+		args = append(args, pattern, pattern, pattern)
+	}
+	return sb.queryTasks(db, `
+		SELECT internal_id, uid, list_id, summary, description, status, priority,
+		       created_at, modified_at, due_date, start_date, completed_at,
+		       parent_uid, categories, sequence, recurrence, all_day
+		FROM tasks
+		WHERE backend_name = ? AND list_id = ? AND `+strings.Join(conditions, " AND ")+`
+		ORDER BY priority ASC, created_at DESC
+	`, args...)
+}
+
+// searchEncryptedTasks is SearchTasks for an encrypted list, whose content is
+// opened and matched in Go
+func (sb *SQLiteBackend) searchEncryptedTasks(db *Database, listID string, words []string) ([]backend.Task, error) {
+	all, err := sb.queryTasks(db, `
+		SELECT internal_id, uid, list_id, summary, description, status, priority,
+		       created_at, modified_at, due_date, start_date, completed_at,
+		       parent_uid, categories, sequence, recurrence, all_day
+		FROM tasks
+		WHERE backend_name = ? AND list_id = ?
+		ORDER BY priority ASC, created_at DESC
+	`, sb.backendName, listID)
+	if err != nil {
+		return nil, err
+	}
+
+	var tasks []backend.Task
+	for _, task := range all {
+		content := strings.ToLower(task.Summary + "\n" + task.Description + "\n" + strings.Join(task.Categories, ","))
+		matches := true
+		for _, word := range words {
+			if !strings.Contains(content, strings.ToLower(word)) {
+				matches = false
+				break
+			}
+		}
+		if matches {
+			tasks = append(tasks, task)
+		}
+	}
+	return tasks, nil
+}
+
+// queryTasks runs a query selecting the columns scanTasks reads
+func (sb *SQLiteBackend) queryTasks(db *Database, query string, args ...any) ([]backend.Task, error) {
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func(rows *sql.Rows) { _ = rows.Close() }(rows)
+	return sb.scanTasks(rows)
+}
@@ -0,0 +1,140 @@
+package sqlite
+
+import (
+	"gosynctasks/backend"
+	"slices"
+	"testing"
+)
+
+func TestSearchTasks(t *testing.T) {
+	sb, cleanup := createTestSQLiteBackend(t)
+	defer cleanup()
+
+	listID, _ := sb.CreateTaskList("Work", "", "")
+	otherID, _ := sb.CreateTaskList("Home", "", "")
+	sb.AddTask(listID, backend.Task{Summary: "Call the plumber", Description: "About the leaking invoice", Status: "NEEDS-ACTION"})
+	sb.AddTask(listID, backend.Task{Summary: "Send invoice to ACME", Status: "NEEDS-ACTION"})
+	sb.AddTask(listID, backend.Task{Summary: "Review budget", Categories: []string{"invoices", "finance"}, Status: "NEEDS-ACTION"})
+	sb.AddTask(listID, backend.Task{Summary: "Réunion d'équipe", Status: "NEEDS-ACTION"})
+	sb.AddTask(otherID, backend.Task{Summary: "Pay invoice", Status: "NEEDS-ACTION"})
+
+	if !sb.db.fullText {
+		t.Fatal("tasks_fts was not created")
+	}
+
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		// Summaries rank above tags, tags above descriptions
+		{"invoice", []string{"Send invoice to ACME", "Review budget", "Call the plumber"}},
+		{"plumber leaking", []string{"Call the plumber"}},
+		{"INVOICE acme", []string{"Send invoice to ACME"}},
+		{"reunion", []string{"Réunion d'équipe"}},
+		{`acme"`, []string{"Send invoice to ACME"}},
+		{"acme OR budget", nil},
+		{"milk", nil},
+		{"  ", nil},
+	}
+	for _, tt := range tests {
+		tasks, err := sb.SearchTasks(listID, tt.query)
+		if err != nil {
+			t.Errorf("SearchTasks(%q) error = %v", tt.query, err)
+			continue
+		}
+		var got []string
+		for _, task := range tasks {
+			got = append(got, task.Summary)
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("SearchTasks(%q) = %q, want %q", tt.query, got, tt.want)
+		}
+	}
+
+	// Updates and deletions reach the index
+	tasks, _ := sb.FindTasksBySummary(listID, "Review budget")
+	task := tasks[0]
+	task.Categories = []string{"finance"}
+	if err := sb.UpdateTask(listID, task); err != nil {
+		t.Fatalf("UpdateTask() error = %v", err)
+	}
+	if tasks, _ := sb.SearchTasks(listID, "invoices"); len(tasks) != 0 {
+		t.Errorf("SearchTasks(invoices) after removing the tag = %+v", tasks)
+	}
+	if err := sb.DeleteTask(listID, task.UID); err != nil {
+		t.Fatalf("DeleteTask() error = %v", err)
+	}
+	if tasks, _ := sb.SearchTasks(listID, "budget"); len(tasks) != 0 {
+		t.Errorf("SearchTasks(budget) after deletion = %+v", tasks)
+	}
+}
+
+func TestSearchTasksWithoutFullText(t *testing.T) {
+	sb, cleanup := createTestSQLiteBackend(t)
+	defer cleanup()
+
+	listID, _ := sb.CreateTaskList("Work", "", "")
+	sb.AddTask(listID, backend.Task{Summary: "Call the plumber", Description: "About the leaking 50% invoice", Status: "NEEDS-ACTION"})
+	sb.AddTask(listID, backend.Task{Summary: "Send invoice", Categories: []string{"acme"}, Status: "NEEDS-ACTION"})
+	sb.db.fullText = false
+
+	for query, want := range map[string]int{"invoice": 2, "plumb leak": 1, "ACME invoice": 1, "50%": 1, "5_%": 0} {
+		tasks, err := sb.SearchTasks(listID, query)
+		if err != nil || len(tasks) != want {
+			t.Errorf("SearchTasks(%q) = %+v, %v, want %d tasks", query, tasks, err, want)
+		}
+	}
+}
+
+func TestFindTasksBySummaryWords(t *testing.T) {
+	sb, cleanup := createTestSQLiteBackend(t)
+	defer cleanup()
+
+	listID, _ := sb.CreateTaskList("Work", "", "")
+	sb.AddTask(listID, backend.Task{Summary: "Send the quarterly invoice", Status: "NEEDS-ACTION"})
+	sb.AddTask(listID, backend.Task{Summary: "Invoice", Description: "Quarterly figures", Status: "NEEDS-ACTION"})
+
+	// A substring match is kept as is
+	tasks, err := sb.FindTasksBySummary(listID, "quarterly invoice")
+	if err != nil || len(tasks) != 1 || tasks[0].Summary != "Send the quarterly invoice" {
+		t.Errorf("FindTasksBySummary(quarterly invoice) = %+v, %v", tasks, err)
+	}
+	// Words apart, or in the description, go through SearchTasks
+	tasks, err = sb.FindTasksBySummary(listID, "invoice quarterly")
+	if err != nil || len(tasks) != 2 {
+		t.Errorf("FindTasksBySummary(invoice quarterly) = %+v, %v, want 2 tasks", tasks, err)
+	}
+	// One word is still matched against summaries only
+	if tasks, _ := sb.FindTasksBySummary(listID, "figures"); len(tasks) != 0 {
+		t.Errorf("FindTasksBySummary(figures) = %+v, want none", tasks)
+	}
+}
+
+// TestFullTextIndexBackfill tests that opening a database made before
+// tasks_fts indexes the tasks it holds
+func TestFullTextIndexBackfill(t *testing.T) {
+	sb, cleanup := createTestSQLiteBackend(t)
+	defer cleanup()
+
+	listID, _ := sb.CreateTaskList("Work", "", "")
+	sb.AddTask(listID, backend.Task{Summary: "Send invoice", Description: "To ACME", Status: "NEEDS-ACTION"})
+	for _, statement := range []string{
+		"DROP TRIGGER tasks_fts_insert",
+		"DROP TRIGGER tasks_fts_delete",
+		"DROP TRIGGER tasks_fts_update",
+		"DROP TABLE tasks_fts",
+	} {
+		if _, err := sb.db.Exec(statement); err != nil {
+			t.Fatalf("%s: %v", statement, err)
+		}
+	}
+	sb.db.fullText = false
+	if err := sb.db.initializeSchema(); err != nil {
+		t.Fatalf("initializeSchema() error = %v", err)
+	}
+
+	tasks, err := sb.SearchTasks(listID, "acme invoice")
+	if err != nil || len(tasks) != 1 {
+		t.Errorf("SearchTasks() after the migration = %+v, %v, want the existing task", tasks, err)
+	}
+}
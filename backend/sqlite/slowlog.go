@@ -0,0 +1,68 @@
+package sqlite
+
+import (
+	"database/sql"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"gosynctasks/internal/utils"
+)
+
+// slowQueryMaxSQL is the length to which logged statements are cut
+const slowQueryMaxSQL = 120
+
+// slowQueryThreshold is the duration above which statements are logged, in
+// nanoseconds; 0 turns the log off
+var slowQueryThreshold atomic.Int64
+
+// logSlowQuery logs a slow statement; replaced in tests
+var logSlowQuery = func(elapsed time.Duration, query string) {
+	utils.Infof("[SLOW QUERY] %s: %s", elapsed.Round(time.Millisecond), query)
+}
+
+// SetSlowQueryThreshold logs the statements run through Database.Query,
+// QueryRow and Exec that take longer than threshold; 0 turns the log off.
+// Statements of transactions and prepared statements are not timed.
+func SetSlowQueryThreshold(threshold time.Duration) {
+	slowQueryThreshold.Store(int64(threshold))
+}
+
+// timeQuery logs query if it ran longer than the slow query threshold
+func timeQuery(start time.Time, query string) {
+	threshold := time.Duration(slowQueryThreshold.Load())
+	if threshold <= 0 {
+		return
+	}
+	if elapsed := time.Since(start); elapsed > threshold {
+		logSlowQuery(elapsed, truncateSQL(query))
+	}
+}
+
+// truncateSQL puts a statement on one line, cut to slowQueryMaxSQL characters
+func truncateSQL(query string) string {
+	query = strings.Join(strings.Fields(query), " ")
+	if runes := []rune(query); len(runes) > slowQueryMaxSQL {
+		return string(runes[:slowQueryMaxSQL]) + "…"
+	}
+	return query
+}
+
+// Query runs sql.DB.Query, logging it when slow
+func (db *Database) Query(query string, args ...any) (*sql.Rows, error) {
+	defer timeQuery(time.Now(), query)
+	return db.DB.Query(query, args...)
+}
+
+// QueryRow runs sql.DB.QueryRow, logging it when slow. Only the query is
+// timed, not the Scan of its row.
+func (db *Database) QueryRow(query string, args ...any) *sql.Row {
+	defer timeQuery(time.Now(), query)
+	return db.DB.QueryRow(query, args...)
+}
+
+// Exec runs sql.DB.Exec, logging it when slow
+func (db *Database) Exec(query string, args ...any) (sql.Result, error) {
+	defer timeQuery(time.Now(), query)
+	return db.DB.Exec(query, args...)
+}
@@ -0,0 +1,69 @@
+package sqlite
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSlowQueryLog(t *testing.T) {
+	db, err := InitDatabase(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to initialize database: %v", err)
+	}
+	defer func() { _ = db.Close() }()
+
+	var logged []string
+	original := logSlowQuery
+	logSlowQuery = func(elapsed time.Duration, query string) { logged = append(logged, query) }
+	defer func() {
+		logSlowQuery = original
+		SetSlowQueryThreshold(0)
+	}()
+
+	// Off by default
+	if _, err := db.Exec("INSERT INTO list_sync_metadata (list_id, list_name, created_at) VALUES ('l', 'Work', 0)"); err != nil {
+		t.Fatalf("Exec failed: %v", err)
+	}
+	if len(logged) != 0 {
+		t.Fatalf("logged %v with the slow query log off", logged)
+	}
+
+	// Every statement is slower than a nanosecond
+	SetSlowQueryThreshold(time.Nanosecond)
+	var count int
+	if err := db.QueryRow("SELECT COUNT(*)\n\t\tFROM list_sync_metadata").Scan(&count); err != nil {
+		t.Fatalf("QueryRow failed: %v", err)
+	}
+	rows, err := db.Query("SELECT list_id FROM list_sync_metadata")
+	if err != nil {
+		t.Fatalf("Query failed: %v", err)
+	}
+	_ = rows.Close()
+	want := []string{"SELECT COUNT(*) FROM list_sync_metadata", "SELECT list_id FROM list_sync_metadata"}
+	if strings.Join(logged, "|") != strings.Join(want, "|") {
+		t.Errorf("logged %q, want %q", logged, want)
+	}
+
+	// Nothing is slower than an hour
+	logged = nil
+	SetSlowQueryThreshold(time.Hour)
+	if _, err := db.GetStats(); err != nil {
+		t.Fatalf("GetStats failed: %v", err)
+	}
+	if len(logged) != 0 {
+		t.Errorf("logged %v below the threshold", logged)
+	}
+}
+
+func TestTruncateSQL(t *testing.T) {
+	long := "SELECT " + strings.Repeat("column, ", 30) + "last FROM tasks"
+	got := truncateSQL(long)
+	if len([]rune(got)) != slowQueryMaxSQL+1 || !strings.HasSuffix(got, "…") || !strings.HasPrefix(got, "SELECT column, ") {
+		t.Errorf("truncateSQL(long) = %q, want the first %d characters and an ellipsis", got, slowQueryMaxSQL)
+	}
+	if got := truncateSQL("  UPDATE tasks\n\tSET summary = ?  "); got != "UPDATE tasks SET summary = ?" {
+		t.Errorf("truncateSQL() = %q, want it on one line", got)
+	}
+}
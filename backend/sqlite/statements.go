@@ -0,0 +1,55 @@
+package sqlite
+
+import "database/sql"
+
+// Hot-path queries, prepared once per Database and reused across calls
+const (
+	selectTasksByListSQL = `
+		SELECT t.internal_id, t.uid, t.list_id, t.summary, t.description, t.status, t.priority,
+		       t.created_at, t.modified_at, t.due_date, t.start_date, t.completed_at,
+		       t.parent_uid, t.categories, t.sequence, t.recurrence, t.all_day
+		FROM tasks t
+		LEFT JOIN sync_metadata sm ON t.internal_id = sm.task_internal_id AND t.backend_name = sm.backend_name
+		WHERE t.backend_name = ? AND t.list_id = ?
+		  AND (sm.locally_deleted IS NULL OR sm.locally_deleted = 0)
+	`
+
+	insertTaskSQL = `
+		INSERT INTO tasks (
+			uid, backend_name, list_id, summary, description, status, priority,
+			created_at, modified_at, due_date, start_date, completed_at,
+			parent_uid, categories, sequence, recurrence, all_day
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	updateTaskSQL = `
+		UPDATE tasks
+		SET summary = ?, description = ?, status = ?, priority = ?,
+		    modified_at = ?, due_date = ?, start_date = ?, completed_at = ?,
+		    parent_uid = ?, categories = ?, sequence = MAX(sequence, ?), recurrence = ?, all_day = ?
+		WHERE backend_name = ? AND uid = ? AND list_id = ?
+	`
+
+	insertSyncMetadataSQL = `
+		INSERT INTO sync_metadata (
+			task_internal_id, backend_name, list_id, remote_etag, last_synced_at, remote_modified_at,
+			locally_modified, locally_deleted
+		) VALUES (?, ?, ?, ?, ?, ?, 0, 0)
+	`
+
+	forgetPrunedTaskSQL = `DELETE FROM pruned_tasks WHERE backend_name = ? AND uid = ?`
+
+	queueOperationSQL = `
+		INSERT OR REPLACE INTO sync_queue (backend_name, task_internal_id, list_id, operation, created_at)
+		VALUES (?, ?, ?, ?, ?)
+	`
+)
+
+// txStmt returns the cached statement for query bound to tx
+func (db *Database) txStmt(tx *sql.Tx, query string) (*sql.Stmt, error) {
+	stmt, err := db.prepared(query)
+	if err != nil {
+		return nil, err
+	}
+	return tx.Stmt(stmt), nil
+}
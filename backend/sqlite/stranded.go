@@ -0,0 +1,84 @@
+package sqlite
+
+// StrandSyncOperations marks the queued creates and updates of a list that
+// no longer exists on the remote as stranded, so sync stops retrying them,
+// and stores message as their error. It returns the number of tasks
+// stranded.
+func (sb *SQLiteBackend) StrandSyncOperations(listID, message string) (int, error) {
+	db, err := sb.GetDB()
+	if err != nil {
+		return 0, &SQLiteError{Op: "StrandSyncOperations", ListID: listID, Err: err}
+	}
+
+	result, err := db.Exec(`
+		UPDATE sync_queue
+		SET stranded = 1, last_error = ?
+		WHERE backend_name = ? AND list_id = ? AND operation IN ('create', 'update')
+	`, message, sb.backendName, listID)
+	if err != nil {
+		return 0, &SQLiteError{Op: "StrandSyncOperations", ListID: listID, Err: err}
+	}
+	stranded, err := result.RowsAffected()
+	if err != nil {
+		return 0, &SQLiteError{Op: "StrandSyncOperations", ListID: listID, Err: err}
+	}
+	return int(stranded), nil
+}
+
+// RescueStrandedTasks moves the tasks with stranded operations from one list
+// to another and queues them again, as creates since the target list does
+// not have them yet. When toListID is the stranded list itself, such as
+// after it was restored on the remote, the operations are only queued
+// again. It returns the number of tasks rescued.
+func (sb *SQLiteBackend) RescueStrandedTasks(fromListID, toListID string) (int, error) {
+	db, err := sb.GetDB()
+	if err != nil {
+		return 0, &SQLiteError{Op: "RescueStrandedTasks", ListID: fromListID, Err: err}
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return 0, &SQLiteError{Op: "RescueStrandedTasks", ListID: fromListID, Err: err}
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	strandedTasks := `
+		SELECT task_internal_id FROM sync_queue
+		WHERE backend_name = ? AND list_id = ? AND stranded = 1
+	`
+	if toListID != fromListID {
+		for _, table := range []string{"tasks", "sync_metadata"} {
+			idColumn := "internal_id"
+			if table == "sync_metadata" {
+				idColumn = "task_internal_id"
+			}
+			_, err := tx.Exec(`
+				UPDATE `+table+` SET list_id = ?
+				WHERE backend_name = ? AND `+idColumn+` IN (`+strandedTasks+`)
+			`, toListID, sb.backendName, sb.backendName, fromListID)
+			if err != nil {
+				return 0, &SQLiteError{Op: "RescueStrandedTasks", ListID: fromListID, Err: err}
+			}
+		}
+	}
+
+	// An update and a create of the same task collapse into the create
+	operation := "CASE WHEN list_id = ? THEN operation ELSE 'create' END"
+	result, err := tx.Exec(`
+		UPDATE OR REPLACE sync_queue
+		SET operation = `+operation+`, list_id = ?, stranded = 0, retry_count = 0, last_error = NULL, next_retry_at = NULL
+		WHERE backend_name = ? AND list_id = ? AND stranded = 1
+	`, toListID, toListID, sb.backendName, fromListID)
+	if err != nil {
+		return 0, &SQLiteError{Op: "RescueStrandedTasks", ListID: fromListID, Err: err}
+	}
+	rescued, err := result.RowsAffected()
+	if err != nil {
+		return 0, &SQLiteError{Op: "RescueStrandedTasks", ListID: fromListID, Err: err}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, &SQLiteError{Op: "RescueStrandedTasks", ListID: fromListID, Err: err}
+	}
+	return int(rescued), nil
+}
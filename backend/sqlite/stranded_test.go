@@ -0,0 +1,79 @@
+package sqlite
+
+import (
+	"gosynctasks/backend"
+	"testing"
+)
+
+// TestRescueStrandedTasks tests that the stranded changes of a list move to
+// another list as creates, and that other lists are left alone
+func TestRescueStrandedTasks(t *testing.T) {
+	sb, cleanup := createTestSQLiteBackend(t)
+	defer cleanup()
+
+	gone, _ := sb.CreateTaskList("Gone", "", "")
+	inbox, _ := sb.CreateTaskList("Inbox", "", "")
+	if err := sb.InsertSyncedTask(gone, backend.Task{UID: "remote-1", Summary: "Synced", Status: "NEEDS-ACTION"}); err != nil {
+		t.Fatalf("InsertSyncedTask() error = %v", err)
+	}
+	if err := sb.UpdateTask(gone, backend.Task{UID: "remote-1", Summary: "Edited", Status: "NEEDS-ACTION"}); err != nil {
+		t.Fatalf("UpdateTask() error = %v", err)
+	}
+	newUID, _ := sb.AddTask(gone, backend.Task{Summary: "New", Status: "NEEDS-ACTION"})
+	inboxUID, _ := sb.AddTask(inbox, backend.Task{Summary: "Elsewhere", Status: "NEEDS-ACTION"})
+
+	stranded, err := sb.StrandSyncOperations(gone, "list no longer exists on the remote")
+	if err != nil || stranded != 2 {
+		t.Fatalf("StrandSyncOperations() = %d, %v, want 2", stranded, err)
+	}
+
+	rescued, err := sb.RescueStrandedTasks(gone, inbox)
+	if err != nil || rescued != 2 {
+		t.Fatalf("RescueStrandedTasks() = %d, %v, want 2", rescued, err)
+	}
+
+	tasks, _ := sb.GetTasks(inbox, nil)
+	if len(tasks) != 3 {
+		t.Errorf("GetTasks(inbox) = %d tasks, want the 2 rescued and its own", len(tasks))
+	}
+	ops, _ := sb.GetPendingSyncOperations()
+	for _, op := range ops {
+		if op.ListID != inbox || op.Operation != "create" || op.Stranded || op.LastError != "" {
+			t.Errorf("operation %+v, want an unstranded create in inbox", op)
+		}
+	}
+	if len(ops) != 3 {
+		t.Errorf("%d operations queued, want 3 (%s, %s, remote-1)", len(ops), newUID, inboxUID)
+	}
+
+	// Nothing is left to rescue
+	if rescued, _ := sb.RescueStrandedTasks(gone, inbox); rescued != 0 {
+		t.Errorf("second RescueStrandedTasks() = %d, want 0", rescued)
+	}
+}
+
+// TestRescueStrandedTasksInPlace tests that rescuing a list to itself only
+// queues its changes again
+func TestRescueStrandedTasksInPlace(t *testing.T) {
+	sb, cleanup := createTestSQLiteBackend(t)
+	defer cleanup()
+
+	listID, _ := sb.CreateTaskList("Restored", "", "")
+	if err := sb.InsertSyncedTask(listID, backend.Task{UID: "remote-1", Summary: "Synced", Status: "NEEDS-ACTION"}); err != nil {
+		t.Fatalf("InsertSyncedTask() error = %v", err)
+	}
+	if err := sb.UpdateTask(listID, backend.Task{UID: "remote-1", Summary: "Edited", Status: "NEEDS-ACTION"}); err != nil {
+		t.Fatalf("UpdateTask() error = %v", err)
+	}
+	if _, err := sb.StrandSyncOperations(listID, "gone"); err != nil {
+		t.Fatalf("StrandSyncOperations() error = %v", err)
+	}
+
+	if rescued, err := sb.RescueStrandedTasks(listID, listID); err != nil || rescued != 1 {
+		t.Fatalf("RescueStrandedTasks() = %d, %v, want 1", rescued, err)
+	}
+	ops, _ := sb.GetPendingSyncOperations()
+	if len(ops) != 1 || ops[0].Operation != "update" || ops[0].Stranded {
+		t.Errorf("operations = %+v, want the update queued again", ops)
+	}
+}
@@ -0,0 +1,718 @@
+package sqlite
+
+import (
+	"database/sql"
+	"strings"
+	"time"
+
+	"gosynctasks/backend"
+)
+
+// Methods used by the sync manager to apply remote state to the cache.
+// Unlike AddTask/UpdateTask/DeleteTask they never queue sync operations,
+// since the changes already exist on the remote.
+
+// CreateSyncedList stores metadata for a list first seen on the remote
+func (sb *SQLiteBackend) CreateSyncedList(list backend.TaskList) error {
+	db, err := sb.GetDB()
+	if err != nil {
+		return &SQLiteError{Op: "CreateSyncedList", ListID: list.ID, Err: err}
+	}
+
+	now := time.Now().Unix()
+	_, err = db.Exec(`
+		INSERT INTO list_sync_metadata (list_id, backend_name, list_name, list_path, list_color, list_position, last_ctag, last_full_sync, created_at, modified_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, list.ID, sb.backendName, list.Name, NullString(list.Path), list.Color, list.Position, list.CTags, now, now, now)
+	if err != nil {
+		return &SQLiteError{Op: "CreateSyncedList", ListID: list.ID, Err: err}
+	}
+
+	return nil
+}
+
+// UpdateListInfo updates the cached name, path and position of a list
+func (sb *SQLiteBackend) UpdateListInfo(list backend.TaskList) error {
+	db, err := sb.GetDB()
+	if err != nil {
+		return &SQLiteError{Op: "UpdateListInfo", ListID: list.ID, Err: err}
+	}
+
+	_, err = db.Exec(`
+		UPDATE list_sync_metadata
+		SET list_name = ?, list_path = ?, list_position = ?, modified_at = ?
+		WHERE backend_name = ? AND list_id = ?
+	`, list.Name, NullString(list.Path), list.Position, time.Now().Unix(), sb.backendName, list.ID)
+	if err != nil {
+		return &SQLiteError{Op: "UpdateListInfo", ListID: list.ID, Err: err}
+	}
+
+	return nil
+}
+
+// SetListArchived records whether the remote archived a list. An archived
+// list is left out of GetTaskLists and not pulled, but keeps its tasks.
+func (sb *SQLiteBackend) SetListArchived(listID string, archived bool) error {
+	db, err := sb.GetDB()
+	if err != nil {
+		return &SQLiteError{Op: "SetListArchived", ListID: listID, Err: err}
+	}
+
+	_, err = db.Exec(`
+		UPDATE list_sync_metadata
+		SET archived = ?
+		WHERE backend_name = ? AND list_id = ?
+	`, archived, sb.backendName, listID)
+	if err != nil {
+		return &SQLiteError{Op: "SetListArchived", ListID: listID, Err: err}
+	}
+
+	return nil
+}
+
+// GetArchivedTaskLists retrieves the cached lists the remote archived
+func (sb *SQLiteBackend) GetArchivedTaskLists() ([]backend.TaskList, error) {
+	return sb.getTaskLists("GetArchivedTaskLists", true)
+}
+
+// UnarchiveTaskList shows an archived list again. It only changes the cache:
+// the remote is unarchived by its own backend.
+func (sb *SQLiteBackend) UnarchiveTaskList(listID string) error {
+	return sb.SetListArchived(listID, false)
+}
+
+// SetListCTag records the CTag of a list after its tasks were pulled
+func (sb *SQLiteBackend) SetListCTag(listID, ctag string) error {
+	db, err := sb.GetDB()
+	if err != nil {
+		return &SQLiteError{Op: "SetListCTag", ListID: listID, Err: err}
+	}
+
+	_, err = db.Exec(`
+		UPDATE list_sync_metadata
+		SET last_ctag = ?, last_full_sync = ?
+		WHERE backend_name = ? AND list_id = ?
+	`, ctag, time.Now().Unix(), sb.backendName, listID)
+	if err != nil {
+		return &SQLiteError{Op: "SetListCTag", ListID: listID, Err: err}
+	}
+
+	return nil
+}
+
+// ListSyncTime is when a cached list was last pulled
+type ListSyncTime struct {
+	ListID   string    `json:"list_id"`
+	Name     string    `json:"name"`
+	LastSync time.Time `json:"last_sync,omitzero"` // Zero if never pulled
+}
+
+// GetListSyncTimes returns when each cached list, archived ones included,
+// was last pulled, ordered by name
+func (sb *SQLiteBackend) GetListSyncTimes() ([]ListSyncTime, error) {
+	db, err := sb.GetDB()
+	if err != nil {
+		return nil, &SQLiteError{Op: "GetListSyncTimes", Err: err}
+	}
+
+	rows, err := db.Query(`
+		SELECT list_id, list_name, COALESCE(last_full_sync, 0)
+		FROM list_sync_metadata
+		WHERE backend_name = ?
+		ORDER BY list_name ASC
+	`, sb.backendName)
+	if err != nil {
+		return nil, &SQLiteError{Op: "GetListSyncTimes", Err: err}
+	}
+	defer func() { _ = rows.Close() }()
+
+	var times []ListSyncTime
+	for rows.Next() {
+		var list ListSyncTime
+		var lastSync int64
+		if err := rows.Scan(&list.ListID, &list.Name, &lastSync); err != nil {
+			return nil, &SQLiteError{Op: "GetListSyncTimes", Err: err}
+		}
+		if lastSync > 0 {
+			list.LastSync = time.Unix(lastSync, 0)
+		}
+		times = append(times, list)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, &SQLiteError{Op: "GetListSyncTimes", Err: err}
+	}
+
+	return times, nil
+}
+
+// GetListSyncToken returns the sync token of a list recorded at its last
+// pull, or "" if none was recorded
+func (sb *SQLiteBackend) GetListSyncToken(listID string) (string, error) {
+	db, err := sb.GetDB()
+	if err != nil {
+		return "", &SQLiteError{Op: "GetListSyncToken", ListID: listID, Err: err}
+	}
+
+	var token sql.NullString
+	err = db.QueryRow(`
+		SELECT sync_token FROM list_sync_metadata
+		WHERE backend_name = ? AND list_id = ?
+	`, sb.backendName, listID).Scan(&token)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	if err != nil {
+		return "", &SQLiteError{Op: "GetListSyncToken", ListID: listID, Err: err}
+	}
+
+	return token.String, nil
+}
+
+// SetListSyncToken records the sync token a list was pulled at; "" forgets it
+func (sb *SQLiteBackend) SetListSyncToken(listID, token string) error {
+	db, err := sb.GetDB()
+	if err != nil {
+		return &SQLiteError{Op: "SetListSyncToken", ListID: listID, Err: err}
+	}
+
+	_, err = db.Exec(`
+		UPDATE list_sync_metadata
+		SET sync_token = ?
+		WHERE backend_name = ? AND list_id = ?
+	`, NullString(token), sb.backendName, listID)
+	if err != nil {
+		return &SQLiteError{Op: "SetListSyncToken", ListID: listID, Err: err}
+	}
+
+	return nil
+}
+
+// ClearListCTags forgets all list CTags and sync tokens so the next sync pulls
+// every list in full
+func (sb *SQLiteBackend) ClearListCTags() error {
+	db, err := sb.GetDB()
+	if err != nil {
+		return &SQLiteError{Op: "ClearListCTags", Err: err}
+	}
+
+	_, err = db.Exec("UPDATE list_sync_metadata SET last_ctag = '', sync_token = NULL")
+	if err != nil {
+		return &SQLiteError{Op: "ClearListCTags", Err: err}
+	}
+
+	return nil
+}
+
+// InsertSyncedTask inserts a task pulled from the remote, not marked as locally modified
+func (sb *SQLiteBackend) InsertSyncedTask(listID string, task backend.Task) error {
+	return sb.insertSyncedTasks("InsertSyncedTask", listID, []backend.Task{task})
+}
+
+// BulkInsertTasks inserts tasks pulled from the remote as InsertSyncedTask
+// does, in one transaction, for a list pulled for the first time. Parents
+// must come before their subtasks.
+func (sb *SQLiteBackend) BulkInsertTasks(listID string, tasks []backend.Task) error {
+	return sb.insertSyncedTasks("BulkInsertTasks", listID, tasks)
+}
+
+// insertSyncedTasks inserts pulled tasks and their sync metadata in one
+// transaction with prepared statements, reporting errors as op
+func (sb *SQLiteBackend) insertSyncedTasks(op, listID string, tasks []backend.Task) error {
+	if len(tasks) == 0 {
+		return nil
+	}
+	db, err := sb.GetDB()
+	if err != nil {
+		return &SQLiteError{Op: op, ListID: listID, Err: err}
+	}
+	s, err := sb.sealerFor(db, listID)
+	if err != nil {
+		return &SQLiteError{Op: op, ListID: listID, Err: err}
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return &SQLiteError{Op: op, ListID: listID, Err: err}
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	insertTask, err := db.txStmt(tx, insertTaskSQL)
+	if err != nil {
+		return &SQLiteError{Op: op, ListID: listID, Err: err}
+	}
+	insertMetadata, err := db.txStmt(tx, insertSyncMetadataSQL)
+	if err != nil {
+		return &SQLiteError{Op: op, ListID: listID, Err: err}
+	}
+	forgetPruned, err := db.txStmt(tx, forgetPrunedTaskSQL)
+	if err != nil {
+		return &SQLiteError{Op: op, ListID: listID, Err: err}
+	}
+
+	syncedAt := time.Now().Unix()
+	for _, task := range tasks {
+		content, err := s.sealTask(task)
+		if err != nil {
+			return &SQLiteError{Op: op, ListID: listID, TaskUID: task.UID, Err: err}
+		}
+
+		result, err := insertTask.Exec(
+			task.UID,
+			sb.backendName,
+			listID,
+			content.summary,
+			content.description,
+			task.Status,
+			task.Priority,
+			TimeValueToNullInt64(task.Created),
+			TimeValueToNullInt64(task.Modified),
+			TimeToNullInt64(task.DueDate),
+			TimeToNullInt64(task.StartDate),
+			TimeToNullInt64(task.Completed),
+			NullString(task.ParentUID),
+			content.categories,
+			task.Sequence,
+			NullString(task.Recurrence),
+			task.AllDay,
+		)
+		if err != nil {
+			return &SQLiteError{Op: op, ListID: listID, TaskUID: task.UID, Err: err}
+		}
+
+		internalID, err := result.LastInsertId()
+		if err != nil {
+			return &SQLiteError{Op: op, ListID: listID, TaskUID: task.UID, Err: err}
+		}
+
+		_, err = insertMetadata.Exec(internalID, sb.backendName, listID, NullString(task.ETag), syncedAt, remoteModifiedUnix(task))
+		if err != nil {
+			return &SQLiteError{Op: op, ListID: listID, TaskUID: task.UID, Err: err}
+		}
+
+		// A pruned task that changed on the remote is cached again
+		if _, err := forgetPruned.Exec(sb.backendName, task.UID); err != nil {
+			return &SQLiteError{Op: op, ListID: listID, TaskUID: task.UID, Err: err}
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return &SQLiteError{Op: op, ListID: listID, Err: err}
+	}
+	return nil
+}
+
+// UpdateSyncedTask overwrites a cached task with its remote version and
+// clears its local modification flags
+func (sb *SQLiteBackend) UpdateSyncedTask(listID string, task backend.Task) error {
+	db, err := sb.GetDB()
+	if err != nil {
+		return &SQLiteError{Op: "UpdateSyncedTask", ListID: listID, TaskUID: task.UID, Err: err}
+	}
+	s, err := sb.sealerFor(db, listID)
+	if err != nil {
+		return &SQLiteError{Op: "UpdateSyncedTask", ListID: listID, TaskUID: task.UID, Err: err}
+	}
+	content, err := s.sealTask(task)
+	if err != nil {
+		return &SQLiteError{Op: "UpdateSyncedTask", ListID: listID, TaskUID: task.UID, Err: err}
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return &SQLiteError{Op: "UpdateSyncedTask", ListID: listID, TaskUID: task.UID, Err: err}
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var internalID int64
+	var oldSummary string
+	err = tx.QueryRow("SELECT internal_id, summary FROM tasks WHERE backend_name = ? AND uid = ? AND list_id = ?",
+		sb.backendName, task.UID, listID).Scan(&internalID, &oldSummary)
+	if err != nil {
+		return &SQLiteError{Op: "UpdateSyncedTask", ListID: listID, TaskUID: task.UID, Err: err}
+	}
+
+	// Remember the previous summary if the server renamed the task
+	if oldSummary, err = s.open(oldSummary); err != nil {
+		return &SQLiteError{Op: "UpdateSyncedTask", ListID: listID, TaskUID: task.UID, Err: err}
+	}
+	if err := recordSummaryAlias(tx, s, internalID, oldSummary, task.Summary); err != nil {
+		return &SQLiteError{Op: "UpdateSyncedTask", ListID: listID, TaskUID: task.UID, Err: err}
+	}
+
+	// Some backends omit the completion time on read; keep the cached one
+	// instead of overwriting it with NULL while the task is still completed
+	completedExpr := "?"
+	if task.Completed == nil && backend.IsCompletedStatus(task.Status) {
+		completedExpr = "COALESCE(?, completed_at)"
+	}
+
+	_, err = tx.Exec(`
+		UPDATE tasks
+		SET summary = ?, description = ?, status = ?, priority = ?,
+		    modified_at = ?, due_date = ?, start_date = ?, completed_at = `+completedExpr+`,
+		    parent_uid = ?, categories = ?, sequence = MAX(sequence, ?), recurrence = ?, all_day = ?
+		WHERE internal_id = ?
+	`,
+		content.summary,
+		content.description,
+		task.Status,
+		task.Priority,
+		TimeValueToNullInt64(task.Modified),
+		TimeToNullInt64(task.DueDate),
+		TimeToNullInt64(task.StartDate),
+		TimeToNullInt64(task.Completed),
+		NullString(task.ParentUID),
+		content.categories,
+		task.Sequence,
+		NullString(task.Recurrence),
+		task.AllDay,
+		internalID,
+	)
+	if err != nil {
+		return &SQLiteError{Op: "UpdateSyncedTask", ListID: listID, TaskUID: task.UID, Err: err}
+	}
+
+	_, err = tx.Exec(`
+		UPDATE sync_metadata
+		SET remote_etag = ?, last_synced_at = ?, remote_modified_at = ?, locally_modified = 0, locally_deleted = 0
+		WHERE task_internal_id = ? AND backend_name = ?
+	`, NullString(task.ETag), time.Now().Unix(), remoteModifiedUnix(task), internalID, sb.backendName)
+	if err != nil {
+		return &SQLiteError{Op: "UpdateSyncedTask", ListID: listID, TaskUID: task.UID, Err: err}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return &SQLiteError{Op: "UpdateSyncedTask", ListID: listID, TaskUID: task.UID, Err: err}
+	}
+	return nil
+}
+
+// DeleteSyncedTask removes a task that no longer exists on the remote.
+// Its sync metadata is removed by the foreign key cascade.
+func (sb *SQLiteBackend) DeleteSyncedTask(listID, taskUID string) error {
+	db, err := sb.GetDB()
+	if err != nil {
+		return &SQLiteError{Op: "DeleteSyncedTask", ListID: listID, TaskUID: taskUID, Err: err}
+	}
+
+	_, err = db.Exec("DELETE FROM tasks WHERE uid = ? AND backend_name = ? AND list_id = ?", taskUID, sb.backendName, listID)
+	if err != nil {
+		return &SQLiteError{Op: "DeleteSyncedTask", ListID: listID, TaskUID: taskUID, Err: err}
+	}
+
+	return nil
+}
+
+// UpdateTaskUID replaces a task's UID, e.g. a "pending-" placeholder with the
+// ID assigned by the remote, in its subtasks too. The internal_id is
+// unchanged, so references stay valid.
+func (sb *SQLiteBackend) UpdateTaskUID(listID, oldUID, newUID string) error {
+	db, err := sb.GetDB()
+	if err != nil {
+		return &SQLiteError{Op: "UpdateTaskUID", ListID: listID, TaskUID: oldUID, Err: err}
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return &SQLiteError{Op: "UpdateTaskUID", ListID: listID, TaskUID: oldUID, Err: err}
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	// The subtasks refer to the old UID until the second update
+	if _, err := tx.Exec("PRAGMA defer_foreign_keys = ON"); err != nil {
+		return &SQLiteError{Op: "UpdateTaskUID", ListID: listID, TaskUID: oldUID, Err: err}
+	}
+	_, err = tx.Exec(`
+		UPDATE tasks
+		SET uid = ?
+		WHERE backend_name = ? AND uid = ? AND list_id = ?
+	`, newUID, sb.backendName, oldUID, listID)
+	if err != nil {
+		return &SQLiteError{Op: "UpdateTaskUID", ListID: listID, TaskUID: oldUID, Err: err}
+	}
+
+	// Subtasks pushed after their parent refer to it by its remote UID
+	_, err = tx.Exec(`
+		UPDATE tasks
+		SET parent_uid = ?
+		WHERE backend_name = ? AND parent_uid = ?
+	`, newUID, sb.backendName, oldUID)
+	if err != nil {
+		return &SQLiteError{Op: "UpdateTaskUID", ListID: listID, TaskUID: oldUID, Err: err}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return &SQLiteError{Op: "UpdateTaskUID", ListID: listID, TaskUID: oldUID, Err: err}
+	}
+	return nil
+}
+
+// IsLocallyModified reports whether a task has local changes not yet pushed.
+// Tasks without sync metadata are reported as unmodified.
+func (sb *SQLiteBackend) IsLocallyModified(taskUID string) (bool, error) {
+	db, err := sb.GetDB()
+	if err != nil {
+		return false, &SQLiteError{Op: "IsLocallyModified", TaskUID: taskUID, Err: err}
+	}
+
+	var locallyModified int
+	err = db.QueryRow(`
+		SELECT COALESCE(sm.locally_modified, 0)
+		FROM sync_metadata sm
+		INNER JOIN tasks t ON sm.task_internal_id = t.internal_id
+		WHERE t.uid = ? AND t.backend_name = ?
+	`, taskUID, sb.backendName).Scan(&locallyModified)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, &SQLiteError{Op: "IsLocallyModified", TaskUID: taskUID, Err: err}
+	}
+
+	return locallyModified == 1, nil
+}
+
+// GetRemoteModifiedAt returns the remote modification time recorded at the
+// last sync of a task, or nil if none was recorded
+func (sb *SQLiteBackend) GetRemoteModifiedAt(taskUID string) (*time.Time, error) {
+	db, err := sb.GetDB()
+	if err != nil {
+		return nil, &SQLiteError{Op: "GetRemoteModifiedAt", TaskUID: taskUID, Err: err}
+	}
+
+	var remoteModifiedAt sql.NullInt64
+	err = db.QueryRow(`
+		SELECT sm.remote_modified_at
+		FROM sync_metadata sm
+		INNER JOIN tasks t ON sm.task_internal_id = t.internal_id
+		WHERE t.uid = ? AND t.backend_name = ?
+	`, taskUID, sb.backendName).Scan(&remoteModifiedAt)
+	if err == sql.ErrNoRows || (err == nil && !remoteModifiedAt.Valid) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, &SQLiteError{Op: "GetRemoteModifiedAt", TaskUID: taskUID, Err: err}
+	}
+
+	modified := time.Unix(remoteModifiedAt.Int64, 0)
+	return &modified, nil
+}
+
+// GetRemoteETag returns the ETag of the remote version of a task recorded at
+// its last sync, or "" if none was recorded
+func (sb *SQLiteBackend) GetRemoteETag(taskUID string) (string, error) {
+	db, err := sb.GetDB()
+	if err != nil {
+		return "", &SQLiteError{Op: "GetRemoteETag", TaskUID: taskUID, Err: err}
+	}
+
+	var etag sql.NullString
+	err = db.QueryRow(`
+		SELECT sm.remote_etag
+		FROM sync_metadata sm
+		INNER JOIN tasks t ON sm.task_internal_id = t.internal_id
+		WHERE t.uid = ? AND t.backend_name = ?
+	`, taskUID, sb.backendName).Scan(&etag)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	if err != nil {
+		return "", &SQLiteError{Op: "GetRemoteETag", TaskUID: taskUID, Err: err}
+	}
+
+	return etag.String, nil
+}
+
+// maxSyncErrorLength caps the error text stored per queued operation, so a
+// long run of failures with verbose errors cannot bloat the queue
+const maxSyncErrorLength = 500
+
+// RecordSyncFailure increments the retry count of a queued operation and
+// stores the error. The operation is not pushed again before nextRetry.
+func (sb *SQLiteBackend) RecordSyncFailure(operationID int, message string, nextRetry time.Time) error {
+	db, err := sb.GetDB()
+	if err != nil {
+		return &SQLiteError{Op: "RecordSyncFailure", Err: err}
+	}
+
+	if runes := []rune(message); len(runes) > maxSyncErrorLength {
+		message = string(runes[:maxSyncErrorLength-1]) + "…"
+	}
+
+	// Rounded up to the second, so the wait is never cut short
+	_, err = db.Exec(`
+		UPDATE sync_queue
+		SET retry_count = retry_count + 1, last_error = ?, next_retry_at = ?
+		WHERE id = ?
+	`, message, nextRetry.Add(time.Second-time.Nanosecond).Unix(), operationID)
+	if err != nil {
+		return &SQLiteError{Op: "RecordSyncFailure", Err: err}
+	}
+
+	return nil
+}
+
+// ResetSyncRetries clears the retry count, error and retry time of the failed
+// operations queued for the tasks with the given UIDs, or of every failed
+// operation when none are given, so the next sync pushes them again. It
+// returns how many operations were reset.
+func (sb *SQLiteBackend) ResetSyncRetries(taskUIDs ...string) (int, error) {
+	db, err := sb.GetDB()
+	if err != nil {
+		return 0, &SQLiteError{Op: "ResetSyncRetries", Err: err}
+	}
+
+	query := `
+		UPDATE sync_queue
+		SET retry_count = 0, last_error = NULL, next_retry_at = NULL
+		WHERE backend_name = ? AND retry_count > 0`
+	args := []any{sb.backendName}
+	if len(taskUIDs) > 0 {
+		query += `
+		AND task_internal_id IN (
+			SELECT internal_id FROM tasks
+			WHERE backend_name = ? AND uid IN (?` + strings.Repeat(", ?", len(taskUIDs)-1) + `)
+		)`
+		args = append(args, sb.backendName)
+		for _, uid := range taskUIDs {
+			args = append(args, uid)
+		}
+	}
+
+	result, err := db.Exec(query, args...)
+	if err != nil {
+		return 0, &SQLiteError{Op: "ResetSyncRetries", Err: err}
+	}
+	reset, err := result.RowsAffected()
+	if err != nil {
+		return 0, &SQLiteError{Op: "ResetSyncRetries", Err: err}
+	}
+	return int(reset), nil
+}
+
+// CountPendingSync returns the queued operations of each list, by list ID,
+// and whether some of them keep failing. It returns nil unless the backend
+// is the sync cache of a remote: a standalone database queues changes that
+// are never pushed.
+func (sb *SQLiteBackend) CountPendingSync() (map[string]backend.PendingSync, error) {
+	if sb.Config.Sync == nil || !sb.Config.Sync.Enabled {
+		return nil, nil
+	}
+	db, err := sb.GetDB()
+	if err != nil {
+		return nil, &SQLiteError{Op: "CountPendingSync", Err: err}
+	}
+
+	rows, err := db.Query(`
+		SELECT list_id, COUNT(*), MAX(retry_count >= 3 OR COALESCE(last_error, '') != '')
+		FROM sync_queue
+		WHERE backend_name = ?
+		GROUP BY list_id
+	`, sb.backendName)
+	if err != nil {
+		return nil, &SQLiteError{Op: "CountPendingSync", Err: err}
+	}
+	defer func() { _ = rows.Close() }()
+
+	counts := make(map[string]backend.PendingSync)
+	for rows.Next() {
+		var listID string
+		var pending backend.PendingSync
+		if err := rows.Scan(&listID, &pending.Operations, &pending.Failing); err != nil {
+			return nil, &SQLiteError{Op: "CountPendingSync", Err: err}
+		}
+		counts[listID] = pending
+	}
+	if err := rows.Err(); err != nil {
+		return nil, &SQLiteError{Op: "CountPendingSync", Err: err}
+	}
+	return counts, nil
+}
+
+// SyncQueueStats summarizes the sync queue of a backend
+type SyncQueueStats struct {
+	Total       int
+	ByOperation map[string]int
+	Failing     int   // Operations that failed at least once
+	ErrorBytes  int64 // Total size of the stored error texts
+	Oldest      []SyncOperation
+}
+
+// GetSyncQueueStats returns counts for the sync queue and its oldest operations, up to limit
+func (sb *SQLiteBackend) GetSyncQueueStats(limit int) (*SyncQueueStats, error) {
+	db, err := sb.GetDB()
+	if err != nil {
+		return nil, &SQLiteError{Op: "GetSyncQueueStats", Err: err}
+	}
+
+	stats := &SyncQueueStats{ByOperation: make(map[string]int)}
+	rows, err := db.Query(`
+		SELECT operation, COUNT(*), SUM(retry_count > 0), COALESCE(SUM(LENGTH(last_error)), 0)
+		FROM sync_queue
+		WHERE backend_name = ?
+		GROUP BY operation
+	`, sb.backendName)
+	if err != nil {
+		return nil, &SQLiteError{Op: "GetSyncQueueStats", Err: err}
+	}
+	defer func() { _ = rows.Close() }()
+
+	for rows.Next() {
+		var operation string
+		var count, failing int
+		var errorBytes int64
+		if err := rows.Scan(&operation, &count, &failing, &errorBytes); err != nil {
+			return nil, &SQLiteError{Op: "GetSyncQueueStats", Err: err}
+		}
+		stats.ByOperation[operation] = count
+		stats.Total += count
+		stats.Failing += failing
+		stats.ErrorBytes += errorBytes
+	}
+	if err := rows.Err(); err != nil {
+		return nil, &SQLiteError{Op: "GetSyncQueueStats", Err: err}
+	}
+
+	oldest, err := db.Query(`
+		SELECT sq.id, t.uid, sq.list_id, sq.operation, sq.created_at, sq.retry_count, COALESCE(sq.last_error, '')
+		FROM sync_queue sq
+		INNER JOIN tasks t ON sq.task_internal_id = t.internal_id AND sq.backend_name = t.backend_name
+		WHERE sq.backend_name = ?
+		ORDER BY sq.created_at ASC, sq.id ASC
+		LIMIT ?
+	`, sb.backendName, limit)
+	if err != nil {
+		return nil, &SQLiteError{Op: "GetSyncQueueStats", Err: err}
+	}
+	defer func() { _ = oldest.Close() }()
+
+	for oldest.Next() {
+		var op SyncOperation
+		var createdAt int64
+		if err := oldest.Scan(&op.ID, &op.TaskUID, &op.ListID, &op.Operation, &createdAt, &op.RetryCount, &op.LastError); err != nil {
+			return nil, &SQLiteError{Op: "GetSyncQueueStats", Err: err}
+		}
+		op.CreatedAt = time.Unix(createdAt, 0)
+		stats.Oldest = append(stats.Oldest, op)
+	}
+
+	return stats, oldest.Err()
+}
+
+// GetStats returns task, list and sync queue counts for the cache database
+func (sb *SQLiteBackend) GetStats() (DatabaseStats, error) {
+	db, err := sb.GetDB()
+	if err != nil {
+		return DatabaseStats{}, &SQLiteError{Op: "GetStats", Err: err}
+	}
+	return db.GetStats()
+}
+
+// remoteModifiedUnix returns the task's Modified time as stored in
+// sync_metadata.remote_modified_at, 0 when unknown
+func remoteModifiedUnix(task backend.Task) int64 {
+	if task.Modified.IsZero() {
+		return 0
+	}
+	return task.Modified.Unix()
+}
@@ -0,0 +1,309 @@
+package sqlite
+
+import (
+	"fmt"
+	"gosynctasks/backend"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+// TestCountPendingSync tests the queued operations counted per list, and that
+// operations failing 3 times or with an error mark the list as failing
+func TestCountPendingSync(t *testing.T) {
+	sb, err := NewSQLiteBackend(backend.BackendConfig{
+		Name:   "remote",
+		Type:   "sqlite",
+		DBPath: filepath.Join(t.TempDir(), "cache.db"),
+		Sync:   &backend.BackendSyncConfig{Enabled: true},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create SQLite backend: %v", err)
+	}
+	defer sb.Close()
+
+	work, _ := sb.CreateTaskList("Work", "", "")
+	home, _ := sb.CreateTaskList("Home", "", "")
+	quiet, _ := sb.CreateTaskList("Quiet", "", "")
+	sb.AddTask(work, backend.Task{Summary: "Report", Status: "NEEDS-ACTION"})
+	sb.AddTask(work, backend.Task{Summary: "Slides", Status: "NEEDS-ACTION"})
+	sb.AddTask(home, backend.Task{Summary: "Dishes", Status: "NEEDS-ACTION"})
+
+	counts, err := sb.CountPendingSync()
+	if err != nil {
+		t.Fatalf("CountPendingSync() error = %v", err)
+	}
+	if counts[work] != (backend.PendingSync{Operations: 2}) || counts[home] != (backend.PendingSync{Operations: 1}) {
+		t.Errorf("CountPendingSync() = %+v, want 2 for Work and 1 for Home, not failing", counts)
+	}
+	if _, ok := counts[quiet]; ok {
+		t.Errorf("CountPendingSync() = %+v, want nothing for a list without changes", counts)
+	}
+
+	ops, _ := sb.GetPendingSyncOperations()
+	for _, op := range ops {
+		if op.ListID == home {
+			_ = sb.RecordSyncFailure(op.ID, "503 Service Unavailable", time.Now())
+		}
+	}
+	counts, _ = sb.CountPendingSync()
+	if !counts[home].Failing || counts[work].Failing {
+		t.Errorf("CountPendingSync() = %+v, want only Home failing after an error", counts)
+	}
+
+	// A database that is not the cache of a remote has nothing to push
+	standalone, cleanup := createTestSQLiteBackend(t)
+	defer cleanup()
+	list, _ := standalone.CreateTaskList("Work", "", "")
+	standalone.AddTask(list, backend.Task{Summary: "Report", Status: "NEEDS-ACTION"})
+	if counts, err := standalone.CountPendingSync(); counts != nil || err != nil {
+		t.Errorf("CountPendingSync() without sync = %+v, %v, want nil", counts, err)
+	}
+}
+
+// TestSetListArchived tests that archived lists leave GetTaskLists for
+// GetArchivedTaskLists, keeping their tasks
+func TestSetListArchived(t *testing.T) {
+	sb, cleanup := createTestSQLiteBackend(t)
+	defer cleanup()
+
+	for _, list := range []backend.TaskList{{ID: "work", Name: "Work"}, {ID: "old", Name: "Old Project"}} {
+		if err := sb.CreateSyncedList(list); err != nil {
+			t.Fatalf("CreateSyncedList() error = %v", err)
+		}
+	}
+	if err := sb.InsertSyncedTask("old", backend.Task{UID: "t1", Summary: "Kept", Status: "NEEDS-ACTION"}); err != nil {
+		t.Fatalf("InsertSyncedTask() error = %v", err)
+	}
+
+	if err := sb.SetListArchived("old", true); err != nil {
+		t.Fatalf("SetListArchived() error = %v", err)
+	}
+	lists, _ := sb.GetTaskLists()
+	if len(lists) != 1 || lists[0].ID != "work" {
+		t.Errorf("GetTaskLists() = %+v, want only work", lists)
+	}
+	archived, _ := sb.GetArchivedTaskLists()
+	if len(archived) != 1 || archived[0].ID != "old" || !archived[0].Archived {
+		t.Errorf("GetArchivedTaskLists() = %+v, want old, marked archived", archived)
+	}
+	if tasks, _ := sb.GetTasks("old", nil); len(tasks) != 1 {
+		t.Errorf("GetTasks() of the archived list = %+v, want its task kept", tasks)
+	}
+
+	if err := sb.UnarchiveTaskList("old"); err != nil {
+		t.Fatalf("UnarchiveTaskList() error = %v", err)
+	}
+	if lists, _ := sb.GetTaskLists(); len(lists) != 2 {
+		t.Errorf("GetTaskLists() after unarchiving = %+v, want both lists", lists)
+	}
+}
+
+// TestRemoteETag tests that synced tasks keep the ETag of their remote
+// version, which local changes leave alone
+func TestRemoteETag(t *testing.T) {
+	sb, cleanup := createTestSQLiteBackend(t)
+	defer cleanup()
+
+	listID, _ := sb.CreateTaskList("Work", "", "")
+	if err := sb.InsertSyncedTask(listID, backend.Task{UID: "task-1", Summary: "Report", Status: "NEEDS-ACTION", ETag: `"e1"`}); err != nil {
+		t.Fatalf("InsertSyncedTask() error = %v", err)
+	}
+	if etag, err := sb.GetRemoteETag("task-1"); err != nil || etag != `"e1"` {
+		t.Errorf("GetRemoteETag() after insert = %q, %v, want \"e1\"", etag, err)
+	}
+
+	task, _ := sb.GetTask(listID, "task-1")
+	task.Summary = "Report v2"
+	if err := sb.UpdateTask(listID, *task); err != nil {
+		t.Fatalf("UpdateTask() error = %v", err)
+	}
+	if etag, _ := sb.GetRemoteETag("task-1"); etag != `"e1"` {
+		t.Errorf("GetRemoteETag() after a local change = %q, want \"e1\"", etag)
+	}
+
+	task.ETag = `"e2"`
+	if err := sb.UpdateSyncedTask(listID, *task); err != nil {
+		t.Fatalf("UpdateSyncedTask() error = %v", err)
+	}
+	if etag, _ := sb.GetRemoteETag("task-1"); etag != `"e2"` {
+		t.Errorf("GetRemoteETag() after a pull = %q, want \"e2\"", etag)
+	}
+	if etag, err := sb.GetRemoteETag("unknown"); etag != "" || err != nil {
+		t.Errorf("GetRemoteETag(unknown) = %q, %v", etag, err)
+	}
+}
+
+func TestListSyncToken(t *testing.T) {
+	sb, cleanup := createTestSQLiteBackend(t)
+	defer cleanup()
+
+	if err := sb.CreateSyncedList(backend.TaskList{ID: "work", Name: "Work", CTags: "ctag-1", SyncToken: "token-1"}); err != nil {
+		t.Fatalf("CreateSyncedList() error = %v", err)
+	}
+	if token, err := sb.GetListSyncToken("work"); token != "" || err != nil {
+		t.Errorf("GetListSyncToken() before a pull = %q, %v, want none", token, err)
+	}
+	if err := sb.SetListSyncToken("work", "token-1"); err != nil {
+		t.Fatalf("SetListSyncToken() error = %v", err)
+	}
+	if token, _ := sb.GetListSyncToken("work"); token != "token-1" {
+		t.Errorf("GetListSyncToken() = %q, want token-1", token)
+	}
+
+	if err := sb.ClearListCTags(); err != nil {
+		t.Fatalf("ClearListCTags() error = %v", err)
+	}
+	if token, _ := sb.GetListSyncToken("work"); token != "" {
+		t.Errorf("GetListSyncToken() after ClearListCTags = %q, want none so the list is fetched in full", token)
+	}
+	if token, err := sb.GetListSyncToken("unknown"); token != "" || err != nil {
+		t.Errorf("GetListSyncToken(unknown) = %q, %v", token, err)
+	}
+}
+
+// TestBulkInsertTasks tests that thousands of pulled tasks, subtasks after
+// their parents, are inserted with their sync metadata in one transaction
+func TestBulkInsertTasks(t *testing.T) {
+	sb, cleanup := createTestSQLiteBackend(t)
+	defer cleanup()
+
+	if err := sb.CreateSyncedList(backend.TaskList{ID: "work", Name: "Work"}); err != nil {
+		t.Fatalf("CreateSyncedList() error = %v", err)
+	}
+	const count = 5000
+	tasks := make([]backend.Task, count)
+	for i := range tasks {
+		tasks[i] = backend.Task{UID: fmt.Sprintf("task-%d", i), Summary: fmt.Sprintf("Task %d", i), Status: "NEEDS-ACTION", ETag: `"1"`}
+		if i%10 != 0 {
+			tasks[i].ParentUID = fmt.Sprintf("task-%d", i-i%10)
+		}
+	}
+
+	// One transaction: a failing task leaves none of the others inserted
+	broken := append(slices.Clone(tasks), backend.Task{UID: "task-0", Summary: "Duplicate", Status: "NEEDS-ACTION"})
+	if err := sb.BulkInsertTasks("work", broken); err == nil {
+		t.Fatal("BulkInsertTasks() with a duplicate UID succeeded, want an error")
+	}
+	db, _ := sb.GetDB()
+	var taskRows int
+	db.QueryRow("SELECT COUNT(*) FROM tasks WHERE list_id = 'work'").Scan(&taskRows)
+	if taskRows != 0 {
+		t.Fatalf("Failed BulkInsertTasks() left %d tasks, want none", taskRows)
+	}
+
+	if err := sb.BulkInsertTasks("work", tasks); err != nil {
+		t.Fatalf("BulkInsertTasks() error = %v", err)
+	}
+
+	var metadataRows, modified, syncTimes int
+	db.QueryRow("SELECT COUNT(*) FROM tasks WHERE list_id = 'work'").Scan(&taskRows)
+	db.QueryRow("SELECT COUNT(*), COALESCE(SUM(locally_modified), 0), COUNT(DISTINCT last_synced_at) FROM sync_metadata WHERE list_id = 'work'").Scan(&metadataRows, &modified, &syncTimes)
+	if taskRows != count || metadataRows != count || modified != 0 {
+		t.Errorf("Inserted %d tasks and %d sync metadata rows (%d locally modified), want %d unmodified", taskRows, metadataRows, modified, count)
+	}
+	if syncTimes != 1 {
+		t.Errorf("Sync metadata has %d sync times, want the one of the batch", syncTimes)
+	}
+	if child, err := sb.GetTask("work", "task-4999"); err != nil || child.ParentUID != "task-4990" {
+		t.Errorf("GetTask(task-4999) = %+v, %v, want a subtask of task-4990", child, err)
+	}
+	if etag, _ := sb.GetRemoteETag("task-42"); etag != `"1"` {
+		t.Errorf("GetRemoteETag(task-42) = %q, want the pulled ETag", etag)
+	}
+	if ops, _ := sb.GetQueuedSyncOperations(); len(ops) != 0 {
+		t.Errorf("BulkInsertTasks() queued %d operations, want none", len(ops))
+	}
+}
+
+// TestUpdateTaskUIDSubtasks tests that subtasks follow their parent's new UID
+func TestUpdateTaskUIDSubtasks(t *testing.T) {
+	sb, cleanup := createTestSQLiteBackend(t)
+	defer cleanup()
+
+	listID, _ := sb.CreateTaskList("Work", "", "")
+	parentUID, _ := sb.AddTask(listID, backend.Task{Summary: "Parent", Status: "NEEDS-ACTION"})
+	childUID, _ := sb.AddTask(listID, backend.Task{Summary: "Child", Status: "NEEDS-ACTION", ParentUID: parentUID})
+
+	if err := sb.UpdateTaskUID(listID, parentUID, "remote-parent"); err != nil {
+		t.Fatalf("UpdateTaskUID() error = %v", err)
+	}
+	child, err := sb.GetTask(listID, childUID)
+	if err != nil {
+		t.Fatalf("GetTask() error = %v", err)
+	}
+	if child.ParentUID != "remote-parent" {
+		t.Errorf("Child ParentUID = %q, want remote-parent", child.ParentUID)
+	}
+}
+
+func TestResetSyncRetries(t *testing.T) {
+	sb, cleanup := createTestSQLiteBackend(t)
+	defer cleanup()
+
+	listID, _ := sb.CreateTaskList("Work", "", "")
+	first, _ := sb.AddTask(listID, backend.Task{Summary: "Report", Status: "NEEDS-ACTION"})
+	second, _ := sb.AddTask(listID, backend.Task{Summary: "Slides", Status: "NEEDS-ACTION"})
+	_, _ = sb.AddTask(listID, backend.Task{Summary: "Budget", Status: "NEEDS-ACTION"})
+	ops, _ := sb.GetPendingSyncOperations()
+	for _, op := range ops {
+		if op.TaskUID == first || op.TaskUID == second {
+			_ = sb.RecordSyncFailure(op.ID, "HTTP 500", time.Now().Add(time.Minute))
+		}
+	}
+
+	if reset, err := sb.ResetSyncRetries(first); err != nil || reset != 1 {
+		t.Errorf("ResetSyncRetries(first) = %d, %v, want 1", reset, err)
+	}
+	if reset, err := sb.ResetSyncRetries(); err != nil || reset != 1 {
+		t.Errorf("ResetSyncRetries() = %d, %v, want the other failed operation", reset, err)
+	}
+	ops, _ = sb.GetPendingSyncOperations()
+	for _, op := range ops {
+		if op.RetryCount != 0 || op.LastError != "" {
+			t.Errorf("Operation of %s still has retry count %d and error %q", op.TaskUID, op.RetryCount, op.LastError)
+		}
+	}
+}
+
+// TestSyncRetryBackoff tests that failed operations are left out of the due
+// operations until their next retry
+func TestSyncRetryBackoff(t *testing.T) {
+	sb, cleanup := createTestSQLiteBackend(t)
+	defer cleanup()
+
+	listID, _ := sb.CreateTaskList("Work", "", "")
+	failing, _ := sb.AddTask(listID, backend.Task{Summary: "Report", Status: "NEEDS-ACTION"})
+	_, _ = sb.AddTask(listID, backend.Task{Summary: "Slides", Status: "NEEDS-ACTION"})
+	ops, _ := sb.GetPendingSyncOperations()
+	for _, op := range ops {
+		if op.TaskUID == failing {
+			_ = sb.RecordSyncFailure(op.ID, "HTTP 503", time.Now().Add(time.Hour))
+		}
+	}
+
+	due, err := sb.GetPendingSyncOperations()
+	if err != nil {
+		t.Fatalf("GetPendingSyncOperations() error = %v", err)
+	}
+	if len(due) != 1 || due[0].TaskUID == failing {
+		t.Errorf("GetPendingSyncOperations() = %+v, want only the operation not waiting", due)
+	}
+	queued, _ := sb.GetQueuedSyncOperations()
+	if len(queued) != 2 {
+		t.Fatalf("GetQueuedSyncOperations() = %+v, want both operations", queued)
+	}
+	for _, op := range queued {
+		if waiting := !op.NextRetry.IsZero(); waiting != (op.TaskUID == failing) {
+			t.Errorf("Operation of %s has next retry %v", op.TaskUID, op.NextRetry)
+		}
+	}
+
+	if _, err := sb.ResetSyncRetries(failing); err != nil {
+		t.Fatalf("ResetSyncRetries() error = %v", err)
+	}
+	if due, _ := sb.GetPendingSyncOperations(); len(due) != 2 {
+		t.Errorf("GetPendingSyncOperations() after a reset = %d operations, want 2", len(due))
+	}
+}
@@ -0,0 +1,127 @@
+package sqlite
+
+import (
+	"database/sql"
+	"fmt"
+	"gosynctasks/backend"
+	"time"
+)
+
+var _ backend.TaskTrash = (*SQLiteBackend)(nil)
+
+// selectDeletedTasksSQL selects the soft-deleted tasks of a list, most
+// recently deleted first. Deletes stay here until sync pushes them.
+const selectDeletedTasksSQL = `
+	SELECT t.internal_id, t.uid, t.list_id, t.summary, t.description, t.status, t.priority,
+	       t.created_at, t.modified_at, t.due_date, t.start_date, t.completed_at,
+	       t.parent_uid, t.categories, t.sequence, t.recurrence, t.all_day
+	FROM tasks t
+	JOIN sync_metadata sm ON t.internal_id = sm.task_internal_id AND t.backend_name = sm.backend_name
+	WHERE t.backend_name = ? AND t.list_id = ? AND sm.locally_deleted = 1
+	ORDER BY sm.local_modified_at DESC, t.internal_id DESC
+`
+
+// GetDeletedTasks returns the tasks of a list that were deleted locally and
+// whose delete has not been synced yet
+func (sb *SQLiteBackend) GetDeletedTasks(listID string) ([]backend.DeletedTask, error) {
+	db, err := sb.GetDB()
+	if err != nil {
+		return nil, &SQLiteError{Op: "GetDeletedTasks", ListID: listID, Err: err}
+	}
+
+	rows, err := db.Query(selectDeletedTasksSQL, sb.backendName, listID)
+	if err != nil {
+		return nil, &SQLiteError{Op: "GetDeletedTasks", ListID: listID, Err: err}
+	}
+	tasks, err := sb.scanTasks(rows)
+	_ = rows.Close()
+	if err != nil {
+		return nil, &SQLiteError{Op: "GetDeletedTasks", ListID: listID, Err: err}
+	}
+
+	// scanTasks reads the task columns only, so the delete times come separately
+	deletedAt := make(map[string]time.Time)
+	timeRows, err := db.Query(`
+		SELECT t.uid, sm.local_modified_at
+		FROM tasks t
+		JOIN sync_metadata sm ON t.internal_id = sm.task_internal_id AND t.backend_name = sm.backend_name
+		WHERE t.backend_name = ? AND t.list_id = ? AND sm.locally_deleted = 1
+	`, sb.backendName, listID)
+	if err != nil {
+		return nil, &SQLiteError{Op: "GetDeletedTasks", ListID: listID, Err: err}
+	}
+	defer func() { _ = timeRows.Close() }()
+	for timeRows.Next() {
+		var uid string
+		var at sql.NullInt64
+		if err := timeRows.Scan(&uid, &at); err != nil {
+			return nil, &SQLiteError{Op: "GetDeletedTasks", ListID: listID, Err: err}
+		}
+		if at.Valid {
+			deletedAt[uid] = time.Unix(at.Int64, 0)
+		}
+	}
+	if err := timeRows.Err(); err != nil {
+		return nil, &SQLiteError{Op: "GetDeletedTasks", ListID: listID, Err: err}
+	}
+
+	deleted := make([]backend.DeletedTask, len(tasks))
+	for i, task := range tasks {
+		deleted[i] = backend.DeletedTask{Task: task, DeletedAt: deletedAt[task.UID]}
+	}
+	return deleted, nil
+}
+
+// RestoreTask undoes a local delete that has not been synced yet, putting
+// back the update the delete replaced if the task had unsynced changes
+func (sb *SQLiteBackend) RestoreTask(listID string, taskUID string) error {
+	if err := backend.ValidateTaskRef("RestoreTask", listID, taskUID); err != nil {
+		return err
+	}
+
+	db, err := sb.GetDB()
+	if err != nil {
+		return &SQLiteError{Op: "RestoreTask", ListID: listID, TaskUID: taskUID, Err: err}
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return &SQLiteError{Op: "RestoreTask", ListID: listID, TaskUID: taskUID, Err: err}
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var internalID int64
+	var locallyModified bool
+	err = tx.QueryRow(`
+		SELECT t.internal_id, sm.locally_modified
+		FROM tasks t
+		JOIN sync_metadata sm ON t.internal_id = sm.task_internal_id AND t.backend_name = sm.backend_name
+		WHERE t.backend_name = ? AND t.uid = ? AND t.list_id = ? AND sm.locally_deleted = 1
+	`, sb.backendName, taskUID, listID).Scan(&internalID, &locallyModified)
+	if err == sql.ErrNoRows {
+		return backend.NewBackendError("RestoreTask", 404, fmt.Sprintf("task %s not found in the trash of list %s", taskUID, listID))
+	} else if err != nil {
+		return &SQLiteError{Op: "RestoreTask", ListID: listID, TaskUID: taskUID, Err: err}
+	}
+
+	now := time.Now().Unix()
+	_, err = tx.Exec(`
+		UPDATE sync_metadata
+		SET locally_deleted = 0, local_modified_at = ?
+		WHERE backend_name = ? AND task_internal_id = ?
+	`, now, sb.backendName, internalID)
+	if err != nil {
+		return &SQLiteError{Op: "RestoreTask", ListID: listID, TaskUID: taskUID, Err: err}
+	}
+
+	if err := sb.dequeueOperations(tx, internalID, "delete"); err != nil {
+		return &SQLiteError{Op: "RestoreTask", ListID: listID, TaskUID: taskUID, Err: err}
+	}
+	if locallyModified {
+		if err := sb.queueOperation(db, tx, internalID, listID, "update", now); err != nil {
+			return &SQLiteError{Op: "RestoreTask", ListID: listID, TaskUID: taskUID, Err: err}
+		}
+	}
+
+	return tx.Commit()
+}
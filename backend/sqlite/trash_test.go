@@ -0,0 +1,54 @@
+package sqlite
+
+import (
+	"errors"
+	"gosynctasks/backend"
+	"testing"
+)
+
+// TestTrashRestoresUnsyncedDelete tests that a deleted synced task is listed
+// in the trash and that restoring it brings back its pending update
+func TestTrashRestoresUnsyncedDelete(t *testing.T) {
+	sb, cleanup := createTestSQLiteBackend(t)
+	defer cleanup()
+
+	listID, _ := sb.CreateTaskList("Trash", "", "")
+	if err := sb.InsertSyncedTask(listID, backend.Task{UID: "remote-1", Summary: "Synced", Status: "NEEDS-ACTION"}); err != nil {
+		t.Fatalf("InsertSyncedTask() error = %v", err)
+	}
+	if err := sb.UpdateTask(listID, backend.Task{UID: "remote-1", Summary: "Edited", Status: "NEEDS-ACTION"}); err != nil {
+		t.Fatalf("UpdateTask() error = %v", err)
+	}
+	if err := sb.DeleteTask(listID, "remote-1"); err != nil {
+		t.Fatalf("DeleteTask() error = %v", err)
+	}
+
+	deleted, err := sb.GetDeletedTasks(listID)
+	if err != nil {
+		t.Fatalf("GetDeletedTasks() error = %v", err)
+	}
+	if len(deleted) != 1 || deleted[0].Summary != "Edited" || deleted[0].DeletedAt.IsZero() {
+		t.Fatalf("GetDeletedTasks() = %+v, want the edited task with its delete time", deleted)
+	}
+
+	if err := sb.RestoreTask(listID, "remote-1"); err != nil {
+		t.Fatalf("RestoreTask() error = %v", err)
+	}
+	tasks, _ := sb.GetTasks(listID, nil)
+	if len(tasks) != 1 || tasks[0].UID != "remote-1" {
+		t.Errorf("GetTasks() after restore = %+v, want the restored task", tasks)
+	}
+	if deleted, _ := sb.GetDeletedTasks(listID); len(deleted) != 0 {
+		t.Errorf("GetDeletedTasks() after restore = %+v, want none", deleted)
+	}
+
+	ops, _ := sb.GetPendingSyncOperations()
+	if len(ops) != 1 || ops[0].Operation != "update" {
+		t.Errorf("pending operations = %+v, want only the update", ops)
+	}
+
+	var backendErr *backend.BackendError
+	if err := sb.RestoreTask(listID, "remote-1"); !errors.As(err, &backendErr) || !backendErr.IsNotFound() {
+		t.Errorf("RestoreTask() of a task not in the trash error = %v, want not found", err)
+	}
+}
@@ -0,0 +1,146 @@
+// Package statuskit holds the status and priority conventions the backends
+// share: the canonical statuses with their abbreviations and CalDAV names,
+// and the default priority palette. A backend picks the Scheme matching how
+// it stores statuses, and a Palette of its own only when its priorities mean
+// something else.
+//
+//	func (b *MyBackend) ParseStatusFlag(flag string) (string, error) {
+//		return statuskit.CalDAV.ParseStatusFlag(flag)
+//	}
+package statuskit
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Status is a canonical task status, named as it is displayed
+type Status string
+
+// The canonical statuses
+const (
+	Todo       Status = "TODO"
+	Done       Status = "DONE"
+	Processing Status = "PROCESSING"
+	Cancelled  Status = "CANCELLED"
+)
+
+// Statuses lists the canonical statuses, in display order
+var Statuses = []Status{Todo, Done, Processing, Cancelled}
+
+var calDAVNames = map[Status]string{
+	Todo:       "NEEDS-ACTION",
+	Done:       "COMPLETED",
+	Processing: "IN-PROCESS",
+	Cancelled:  "CANCELLED",
+}
+
+// Abbreviation returns the one-letter form accepted from users
+func (s Status) Abbreviation() string {
+	return string(s)[:1]
+}
+
+// CalDAVName returns the RFC 5545 STATUS value of s
+func (s Status) CalDAVName() string {
+	return calDAVNames[s]
+}
+
+// Lookup returns the status a stored name stands for: a display name or a
+// CalDAV name, in any case. Abbreviations are user input only and not
+// recognized here.
+func Lookup(name string) (Status, bool) {
+	upper := strings.ToUpper(strings.TrimSpace(name))
+	for _, s := range Statuses {
+		if upper == string(s) || upper == s.CalDAVName() {
+			return s, true
+		}
+	}
+	return "", false
+}
+
+// Parse returns the status for user input: an abbreviation, a display name
+// or a CalDAV name, in any case
+func Parse(input string) (Status, error) {
+	if strings.TrimSpace(input) == "" {
+		return "", errors.New("status flag cannot be empty")
+	}
+	if s, ok := Lookup(input); ok {
+		return s, nil
+	}
+	upper := strings.ToUpper(strings.TrimSpace(input))
+	for _, s := range Statuses {
+		if upper == s.Abbreviation() {
+			return s, nil
+		}
+	}
+	return "", fmt.Errorf("invalid status: %s (valid: TODO/T, DONE/D, PROCESSING/P, CANCELLED/C)", input)
+}
+
+// Scheme is how a backend stores the canonical statuses
+type Scheme struct {
+	name func(Status) string
+}
+
+var (
+	// CalDAV stores RFC 5545 names: NEEDS-ACTION, COMPLETED, IN-PROCESS, CANCELLED
+	CalDAV = Scheme{name: Status.CalDAVName}
+	// AppNames stores the display names: TODO, DONE, PROCESSING, CANCELLED
+	AppNames = Scheme{name: func(s Status) string { return string(s) }}
+)
+
+// ParseStatusFlag converts user input to the stored name of the status, as
+// backend.TaskManager.ParseStatusFlag does
+func (sc Scheme) ParseStatusFlag(statusFlag string) (string, error) {
+	s, err := Parse(statusFlag)
+	if err != nil {
+		return "", err
+	}
+	return sc.name(s), nil
+}
+
+// StatusToDisplayName converts a stored status to its display name, as
+// backend.TaskManager.StatusToDisplayName does. Unknown statuses are
+// returned unchanged.
+func (sc Scheme) StatusToDisplayName(backendStatus string) string {
+	if s, ok := Lookup(backendStatus); ok {
+		return string(s)
+	}
+	return backendStatus
+}
+
+// ANSI colors of the priority palettes
+const (
+	Red    = "\033[31m"
+	Yellow = "\033[33m"
+	Cyan   = "\033[36m"
+	Blue   = "\033[34m"
+)
+
+// PriorityBand colors the priorities from Min to Max
+type PriorityBand struct {
+	Min, Max int
+	Color    string
+}
+
+// Palette maps priorities to ANSI colors
+type Palette []PriorityBand
+
+// DefaultPalette follows the CalDAV priority ranges: high (1-4) in red,
+// medium (5) in yellow, low (6-9) in blue
+var DefaultPalette = Palette{
+	{Min: 1, Max: 4, Color: Red},
+	{Min: 5, Max: 5, Color: Yellow},
+	{Min: 6, Max: 9, Color: Blue},
+}
+
+// Color returns the ANSI color of priority, or "" for priorities outside
+// every band, such as 0 (undefined)
+func (p Palette) Color(priority int) string {
+	for _, band := range p {
+		if priority >= band.Min && priority <= band.Max {
+			return band.Color
+		}
+	}
+	return ""
+}
@@ -0,0 +1,61 @@
+package statuskit
+
+import "testing"
+
+func TestSchemes(t *testing.T) {
+	tests := []struct {
+		input   string
+		calDAV  string
+		app     string
+		display string
+		wantErr bool
+	}{
+		{input: "T", calDAV: "NEEDS-ACTION", app: "TODO", display: "TODO"},
+		{input: "d", calDAV: "COMPLETED", app: "DONE", display: "DONE"},
+		{input: "Processing", calDAV: "IN-PROCESS", app: "PROCESSING", display: "PROCESSING"},
+		{input: "cancelled", calDAV: "CANCELLED", app: "CANCELLED", display: "CANCELLED"},
+		{input: "needs-action", calDAV: "NEEDS-ACTION", app: "TODO", display: "TODO"},
+		{input: "COMPLETED", calDAV: "COMPLETED", app: "DONE", display: "DONE"},
+		{input: "X", wantErr: true},
+		{input: "", wantErr: true},
+		{input: "INVALID", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			calDAV, err := CalDAV.ParseStatusFlag(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("CalDAV.ParseStatusFlag(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			app, _ := AppNames.ParseStatusFlag(tt.input)
+			if calDAV != tt.calDAV || app != tt.app {
+				t.Errorf("ParseStatusFlag(%q) = %q (CalDAV), %q (app), want %q, %q", tt.input, calDAV, app, tt.calDAV, tt.app)
+			}
+			if tt.wantErr {
+				return
+			}
+			if got := CalDAV.StatusToDisplayName(calDAV); got != tt.display {
+				t.Errorf("CalDAV.StatusToDisplayName(%q) = %q, want %q", calDAV, got, tt.display)
+			}
+			if got := AppNames.StatusToDisplayName(app); got != tt.display {
+				t.Errorf("AppNames.StatusToDisplayName(%q) = %q, want %q", app, got, tt.display)
+			}
+		})
+	}
+
+	// Unknown stored statuses, and abbreviations, are shown as they are
+	for _, status := range []string{"WAITING", "T"} {
+		if got := CalDAV.StatusToDisplayName(status); got != status {
+			t.Errorf("StatusToDisplayName(%q) = %q, want it unchanged", status, got)
+		}
+	}
+}
+
+func TestDefaultPalette(t *testing.T) {
+	tests := map[int]string{0: "", 1: Red, 4: Red, 5: Yellow, 6: Blue, 9: Blue, 10: ""}
+	for priority, want := range tests {
+		if got := DefaultPalette.Color(priority); got != want {
+			t.Errorf("Color(%d) = %q, want %q", priority, got, want)
+		}
+	}
+}
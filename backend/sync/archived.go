@@ -0,0 +1,66 @@
+package sync
+
+import (
+	"fmt"
+	"slices"
+
+	"gosynctasks/backend"
+)
+
+// syncArchivedLists records in the cache which lists the remote archived.
+// An archived list keeps its tasks on the remote, so its cached copy is
+// hidden rather than deleted, and is not pulled until it is unarchived;
+// remoteLists are the active lists of the remote.
+func (sm *SyncManager) syncArchivedLists(remoteLists []backend.TaskList) error {
+	isActive := func(listID string) bool {
+		return slices.ContainsFunc(remoteLists, func(list backend.TaskList) bool { return list.ID == listID })
+	}
+
+	cachedArchived, err := sm.local.GetArchivedTaskLists()
+	if err != nil {
+		return fmt.Errorf("failed to get archived local lists: %w", err)
+	}
+	for _, list := range cachedArchived {
+		if isActive(list.ID) {
+			if err := sm.local.SetListArchived(list.ID, false); err != nil {
+				return fmt.Errorf("failed to unarchive list %s: %w", list.ID, err)
+			}
+		}
+	}
+
+	archiver, ok := backend.As[backend.ListArchiver](sm.remote)
+	if !ok {
+		return nil
+	}
+	archived, err := archiver.GetArchivedTaskLists()
+	if err != nil {
+		return fmt.Errorf("failed to get archived remote lists: %w", err)
+	}
+	localLists, err := sm.local.GetTaskLists()
+	if err != nil {
+		return fmt.Errorf("failed to get local lists: %w", err)
+	}
+	for _, list := range archived {
+		cached := slices.ContainsFunc(localLists, func(local backend.TaskList) bool { return local.ID == list.ID })
+		if cached && !isActive(list.ID) {
+			if err := sm.local.SetListArchived(list.ID, true); err != nil {
+				return fmt.Errorf("failed to archive list %s: %w", list.ID, err)
+			}
+		}
+	}
+	return nil
+}
+
+// remoteListArchived reports whether the remote archived a list, which then
+// still exists with its tasks
+func (sm *SyncManager) remoteListArchived(listID string) (bool, error) {
+	archiver, ok := backend.As[backend.ListArchiver](sm.remote)
+	if !ok {
+		return false, nil
+	}
+	archived, err := archiver.GetArchivedTaskLists()
+	if err != nil {
+		return false, err
+	}
+	return slices.ContainsFunc(archived, func(list backend.TaskList) bool { return list.ID == listID }), nil
+}
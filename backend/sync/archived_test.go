@@ -0,0 +1,92 @@
+package sync
+
+import (
+	"testing"
+	"time"
+
+	"gosynctasks/backend"
+)
+
+// archivingRemote is a deletedListRemote whose lists can be archived, as
+// Todoist projects: archived ones leave Lists for archived but keep their
+// tasks
+type archivingRemote struct {
+	*deletedListRemote
+	archived []backend.TaskList
+}
+
+func (r *archivingRemote) GetArchivedTaskLists() ([]backend.TaskList, error) {
+	return r.archived, nil
+}
+
+func (r *archivingRemote) UnarchiveTaskList(listID string) error {
+	return nil
+}
+
+// archive moves a list of the remote to its archived lists
+func (r *archivingRemote) archive(listID string) {
+	for i, list := range r.Lists {
+		if list.ID == listID {
+			list.Archived = true
+			r.archived = append(r.archived, list)
+			r.Lists = append(r.Lists[:i], r.Lists[i+1:]...)
+			return
+		}
+	}
+}
+
+func TestArchivedListIsNotDeleted(t *testing.T) {
+	_, local, mock, cleanup := createTestSyncManager(t, ServerWins)
+	defer cleanup()
+	remote := &archivingRemote{deletedListRemote: &deletedListRemote{MockBackend: mock}}
+	sm := NewSyncManager(local, remote, ServerWins)
+
+	mock.Lists = []backend.TaskList{
+		{ID: "work", Name: "Work", CTags: "ctag-work"},
+		{ID: "home", Name: "Home", CTags: "ctag-home"},
+	}
+	mock.AddTask("work", backend.Task{UID: "report", Summary: "Report", Status: "NEEDS-ACTION", Modified: time.Now()})
+	if _, err := sm.Sync(); err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+	if _, err := local.AddTask("work", backend.Task{Summary: "Slides", Status: "NEEDS-ACTION"}); err != nil {
+		t.Fatalf("AddTask() error = %v", err)
+	}
+
+	// Work is archived on the server while a create is queued
+	remote.archive("work")
+	mock.Lists[0].CTags = "ctag-home-2"
+	result, err := sm.Sync()
+	if err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+	if len(result.Stranded) != 0 {
+		t.Errorf("Stranded = %+v, want none for an archived list", result.Stranded)
+	}
+	ops, _ := local.GetQueuedSyncOperations()
+	if len(ops) != 1 || ops[0].Stranded {
+		t.Errorf("operations = %+v, want the create kept and retried as usual", ops)
+	}
+
+	lists, _ := local.GetTaskLists()
+	if len(lists) != 1 || lists[0].ID != "home" {
+		t.Errorf("GetTaskLists() = %+v, want the archived list hidden", lists)
+	}
+	archived, _ := local.GetArchivedTaskLists()
+	if len(archived) != 1 || archived[0].ID != "work" || !archived[0].Archived {
+		t.Errorf("GetArchivedTaskLists() = %+v, want work", archived)
+	}
+	if tasks, _ := local.GetTasks("work", nil); len(tasks) != 2 {
+		t.Errorf("cached tasks of the archived list = %d, want both kept", len(tasks))
+	}
+
+	// Unarchived on the server, the list is shown and pulled again
+	mock.Lists = append(mock.Lists, backend.TaskList{ID: "work", Name: "Work", CTags: "ctag-work-2"})
+	remote.archived = nil
+	if _, err := sm.Sync(); err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+	if lists, _ := local.GetTaskLists(); len(lists) != 2 {
+		t.Errorf("GetTaskLists() = %+v, want work back", lists)
+	}
+}
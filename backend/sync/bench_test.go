@@ -3,6 +3,7 @@ package sync
 import (
 	"gosynctasks/backend"
 	"gosynctasks/backend/sqlite"
+	"gosynctasks/internal/testutil"
 	"fmt"
 	"path/filepath"
 	"testing"
@@ -30,22 +31,10 @@ func BenchmarkSyncPull(b *testing.B) {
 			}
 			defer localBackend.Close()
 
-			remoteBackend := backend.NewMockBackend()
-			listID, _ := remoteBackend.CreateTaskList("Benchmark List", "", "")
-			remoteBackend.Lists[0].CTags = "ctag-bench"
-
-			// Pre-populate remote with tasks
-			now := time.Now()
-			for i := 0; i < size; i++ {
-				remoteBackend.AddTask(listID, backend.Task{
-					UID:      fmt.Sprintf("task-%d", i),
-					Summary:  fmt.Sprintf("backend.Task %d", i),
-					Status:   "NEEDS-ACTION",
-					Priority: (i % 9) + 1,
-					Created:  now,
-					Modified: now,
-				})
-			}
+			// Pre-populate remote with a generated list
+			dataset := testutil.GenerateDataset(testutil.DatasetOptions{Tasks: size, Lists: 1, HierarchyDepth: 3, Seed: 1})
+			remoteBackend := dataset.MockBackend()
+			listID := dataset.Lists[0].ID
 
 			sm := NewSyncManager(localBackend, remoteBackend, ServerWins)
 
@@ -370,33 +359,8 @@ func BenchmarkHierarchicalTaskSorting(b *testing.B) {
 
 	for _, size := range sizes {
 		b.Run(fmt.Sprintf("tasks=%d", size), func(b *testing.B) {
-			// Create hierarchical task structure
-			now := time.Now()
-			tasks := make([]backend.Task, size)
-
-			// Create a tree: root tasks with children
-			numRoots := size / 10
-			if numRoots == 0 {
-				numRoots = 1
-			}
-
-			for i := 0; i < size; i++ {
-				task := backend.Task{
-					UID:      fmt.Sprintf("task-%d", i),
-					Summary:  fmt.Sprintf("backend.Task %d", i),
-					Status:   "NEEDS-ACTION",
-					Created:  now,
-					Modified: now,
-				}
-
-				// Assign parent (skip first numRoots tasks as roots)
-				if i >= numRoots {
-					parentIdx := i % numRoots
-					task.ParentUID = fmt.Sprintf("task-%d", parentIdx)
-				}
-
-				tasks[i] = task
-			}
+			// Create a hierarchical task structure
+			tasks := testutil.GenerateDataset(testutil.DatasetOptions{Tasks: size, Lists: 1, HierarchyDepth: 3, Seed: 1}).AllTasks()
 
 			b.ResetTimer()
 			for i := 0; i < b.N; i++ {
@@ -405,7 +369,7 @@ func BenchmarkHierarchicalTaskSorting(b *testing.B) {
 				copy(tasksCopy, tasks)
 
 				// Sort by hierarchy
-				_ = sortTasksByHierarchy(tasksCopy)
+				_ = backend.SortTasksByHierarchy(tasksCopy)
 			}
 		})
 	}
@@ -0,0 +1,297 @@
+package sync
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"gosynctasks/backend"
+	"gosynctasks/backend/statuskit"
+	"gosynctasks/internal/utils"
+)
+
+const (
+	diffRed   = "\033[31m"
+	diffGreen = "\033[32m"
+	diffGray  = "\033[90m"
+	diffReset = "\033[0m"
+
+	// diffMaxText is the length up to which differing texts are shown whole
+	diffMaxText = 60
+	// diffContext is the number of unchanged characters kept around a change
+	diffContext = 15
+)
+
+// taskField is a field compared between two versions of a task
+type taskField struct {
+	name string
+	// show renders the change of the field, or "" when it did not change
+	show func(before, after backend.Task) string
+}
+
+// change renders a changed value as "old → new"
+func change(beforeText, afterText string) string {
+	return diffRed + beforeText + diffReset + " → " + diffGreen + afterText + diffReset
+}
+
+// showIf renders the change of the values when differs is true
+func showIf(differs bool, values func() (string, string)) string {
+	if !differs {
+		return ""
+	}
+	return change(values())
+}
+
+// taskFields are the compared fields, in display order
+var taskFields = []taskField{
+	{"summary", func(before, after backend.Task) string {
+		return showIf(before.Summary != after.Summary, func() (string, string) { return diffText(before.Summary, after.Summary) })
+	}},
+	{"status", func(before, after backend.Task) string {
+		return showIf(before.Status != after.Status, func() (string, string) { return diffValue(before.Status), diffValue(after.Status) })
+	}},
+	{"priority", func(before, after backend.Task) string {
+		return showIf(before.Priority != after.Priority, func() (string, string) { return fmt.Sprint(before.Priority), fmt.Sprint(after.Priority) })
+	}},
+	{"due", func(before, after backend.Task) string {
+		return showIf(!sameDate(before.DueDate, after.DueDate), func() (string, string) { return diffDate(before.DueDate), diffDate(after.DueDate) })
+	}},
+	{"start", func(before, after backend.Task) string {
+		return showIf(!sameDate(before.StartDate, after.StartDate), func() (string, string) { return diffDate(before.StartDate), diffDate(after.StartDate) })
+	}},
+	{"completed", func(before, after backend.Task) string {
+		return showIf(!sameDate(before.Completed, after.Completed), func() (string, string) { return diffDate(before.Completed), diffDate(after.Completed) })
+	}},
+	{"parent", func(before, after backend.Task) string {
+		return showIf(before.ParentUID != after.ParentUID, func() (string, string) { return diffValue(before.ParentUID), diffValue(after.ParentUID) })
+	}},
+	{"repeat", func(before, after backend.Task) string {
+		return showIf(before.Recurrence != after.Recurrence, func() (string, string) { return diffValue(before.Recurrence), diffValue(after.Recurrence) })
+	}},
+	{"tags", func(before, after backend.Task) string {
+		return diffCategories(before.Categories, after.Categories)
+	}},
+	{"description", func(before, after backend.Task) string {
+		return showIf(before.Description != after.Description, func() (string, string) { return diffText(before.Description, after.Description) })
+	}},
+}
+
+// FormatTaskDiff renders the fields that differ between two versions of a
+// task, one per line, as "old → new". Long texts are cut down to the region
+// that changed, and categories are shown as +tag/−tag. It returns an empty
+// string when the versions do not differ.
+func FormatTaskDiff(before, after backend.Task, indent string) string {
+	var sb strings.Builder
+	for _, field := range taskFields {
+		if text := field.show(before, after); text != "" {
+			fmt.Fprintf(&sb, "%s%s%-12s%s %s\n", indent, diffGray, field.name+":", diffReset, text)
+		}
+	}
+	return sb.String()
+}
+
+// differingFields returns the names of the fields that differ between two
+// versions of a task, in display order. The modification time is not
+// compared.
+func differingFields(before, after backend.Task) []string {
+	var fields []string
+	for _, field := range taskFields {
+		if field.show(before, after) != "" {
+			fields = append(fields, field.name)
+		}
+	}
+	return fields
+}
+
+// onlyClosingDiffers reports whether two versions of a task are both closed
+// (done or cancelled) and differ in nothing but their status and completion
+// time, as when the task was completed on both sides
+func onlyClosingDiffers(local, remote backend.Task) bool {
+	if !isClosedStatus(local.Status) || !isClosedStatus(remote.Status) {
+		return false
+	}
+	for _, field := range differingFields(local, remote) {
+		if field != "status" && field != "completed" {
+			return false
+		}
+	}
+	return true
+}
+
+// isClosedStatus reports whether a stored status is done or cancelled
+func isClosedStatus(status string) bool {
+	s, ok := statuskit.Lookup(status)
+	return ok && (s == statuskit.Done || s == statuskit.Cancelled)
+}
+
+// diffText returns both texts on one line each, cut down to the changed
+// region with some context when either is long
+func diffText(before, after string) (string, string) {
+	beforeRunes := []rune(strings.ReplaceAll(before, "\n", "↵"))
+	afterRunes := []rune(strings.ReplaceAll(after, "\n", "↵"))
+	if len(beforeRunes) <= diffMaxText && len(afterRunes) <= diffMaxText {
+		return diffValue(string(beforeRunes)), diffValue(string(afterRunes))
+	}
+
+	prefix := 0
+	for prefix < len(beforeRunes) && prefix < len(afterRunes) && beforeRunes[prefix] == afterRunes[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(beforeRunes)-prefix && suffix < len(afterRunes)-prefix &&
+		beforeRunes[len(beforeRunes)-1-suffix] == afterRunes[len(afterRunes)-1-suffix] {
+		suffix++
+	}
+
+	start := max(prefix-diffContext, 0)
+	return excerpt(beforeRunes, start, len(beforeRunes)-suffix), excerpt(afterRunes, start, len(afterRunes)-suffix)
+}
+
+// excerpt returns runes[start:end] widened by the context, with ellipses where cut
+func excerpt(runes []rune, start, end int) string {
+	end = min(end+diffContext, len(runes))
+	text := utils.SanitizeLine(string(runes[start:end]))
+	if start > 0 {
+		text = "…" + text
+	}
+	if end < len(runes) {
+		text += "…"
+	}
+	return text
+}
+
+// diffValue shows empty values explicitly
+func diffValue(s string) string {
+	if s == "" {
+		return "(none)"
+	}
+	return utils.SanitizeLine(s)
+}
+
+func diffDate(t *time.Time) string {
+	if t == nil {
+		return "(none)"
+	}
+	if t.Hour() == 0 && t.Minute() == 0 {
+		return t.Format("2006-01-02")
+	}
+	return t.Format("2006-01-02 15:04")
+}
+
+func sameDate(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
+
+// diffCategories lists categories only in after as +tag and only in before as −tag
+func diffCategories(before, after []string) string {
+	inBefore := make(map[string]bool, len(before))
+	for _, tag := range before {
+		inBefore[tag] = true
+	}
+	inAfter := make(map[string]bool, len(after))
+	for _, tag := range after {
+		inAfter[tag] = true
+	}
+
+	var added, removed []string
+	for tag := range inAfter {
+		if !inBefore[tag] {
+			added = append(added, tag)
+		}
+	}
+	for tag := range inBefore {
+		if !inAfter[tag] {
+			removed = append(removed, tag)
+		}
+	}
+	sort.Strings(added)
+	sort.Strings(removed)
+
+	var parts []string
+	for _, tag := range added {
+		parts = append(parts, diffGreen+"+"+utils.SanitizeLine(tag)+diffReset)
+	}
+	for _, tag := range removed {
+		parts = append(parts, diffRed+"−"+utils.SanitizeLine(tag)+diffReset)
+	}
+	return strings.Join(parts, " ")
+}
+
+// FormatConflict renders a conflict as a heading with the resolution, followed
+// by the fields that differ between the local and the remote version
+func FormatConflict(conflict Conflict) string {
+	resolution := ""
+	if conflict.Resolution != "" {
+		resolution = fmt.Sprintf(" %s[%s]%s", diffGray, conflict.Resolution, diffReset)
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "  %s %s(%s, local → remote)%s%s\n",
+		utils.SanitizeLine(conflict.Local.Summary), diffGray, utils.SanitizeLine(conflict.ListName), diffReset, resolution)
+	sb.WriteString(FormatTaskDiff(conflict.Local, conflict.Remote, "    "))
+	return sb.String()
+}
+
+// FormatResolvedBy renders the count of conflicts resolved by each strategy,
+// as " (1 local_wins, 2 server_wins)", or "" when there are none
+func FormatResolvedBy(counts map[ConflictResolutionStrategy]int) string {
+	if len(counts) == 0 {
+		return ""
+	}
+	parts := make([]string, 0, len(counts))
+	for _, strategy := range slices.Sorted(maps.Keys(counts)) {
+		parts = append(parts, fmt.Sprintf("%d %s", counts[strategy], strategy))
+	}
+	return " (" + strings.Join(parts, ", ") + ")"
+}
+
+// sideBySideFields are the fields FormatConflictSideBySide shows, in order
+var sideBySideFields = []struct {
+	name  string
+	value func(task backend.Task) string
+}{
+	{"summary", func(task backend.Task) string { return diffValue(task.Summary) }},
+	{"status", func(task backend.Task) string { return diffValue(task.Status) }},
+	{"priority", func(task backend.Task) string { return fmt.Sprint(task.Priority) }},
+	{"due", func(task backend.Task) string { return diffDate(task.DueDate) }},
+	{"start", func(task backend.Task) string { return diffDate(task.StartDate) }},
+	{"completed", func(task backend.Task) string { return diffDate(task.Completed) }},
+	{"description", func(task backend.Task) string {
+		return diffValue(strings.ReplaceAll(task.Description, "\n", "↵"))
+	}},
+}
+
+// FormatConflictSideBySide renders the local and the remote version of a
+// conflicting task in two columns fitting width, one field per row. Rows that
+// differ are marked with '*', the others are dimmed.
+func FormatConflictSideBySide(conflict Conflict, width int) string {
+	const nameWidth = 12
+	column := max((width-nameWidth-7)/2, 12)
+
+	cut := func(text string) string {
+		return utils.TruncateWidth(text, column, "…")
+	}
+	cell := func(text string) string {
+		text = cut(text)
+		return text + strings.Repeat(" ", column-utils.DisplayWidth(text))
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "  %s %s(%s)%s\n", utils.SanitizeLine(conflict.Local.Summary), diffGray, utils.SanitizeLine(conflict.ListName), diffReset)
+	fmt.Fprintf(&sb, "    %-*s %s │ %s\n", nameWidth, "", cell("local"), "remote")
+	for _, field := range sideBySideFields {
+		local, remote := field.value(conflict.Local), field.value(conflict.Remote)
+		if local == remote {
+			fmt.Fprintf(&sb, "%s    %-*s %s │ %s%s\n", diffGray, nameWidth, field.name+":", cell(local), cut(remote), diffReset)
+		} else {
+			fmt.Fprintf(&sb, "  * %-*s %s │ %s\n", nameWidth, field.name+":", cell(local), cut(remote))
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,164 @@
+package sync
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"gosynctasks/backend"
+	"gosynctasks/internal/utils"
+)
+
+var ansiPattern = regexp.MustCompile(`\x1b\[[0-9;]*m`)
+
+func stripANSI(s string) string {
+	return ansiPattern.ReplaceAllString(s, "")
+}
+
+func TestFormatTaskDiff(t *testing.T) {
+	due := time.Date(2026, 7, 14, 0, 0, 0, 0, time.UTC)
+	later := time.Date(2026, 7, 20, 9, 30, 0, 0, time.UTC)
+	longText := strings.Repeat("The quarterly report covers revenue and costs. ", 3)
+
+	tests := []struct {
+		name   string
+		before backend.Task
+		after  backend.Task
+		want   string
+	}{
+		{
+			name:   "identical tasks",
+			before: backend.Task{Summary: "Same", Priority: 1, DueDate: &due},
+			after:  backend.Task{Summary: "Same", Priority: 1, DueDate: &due},
+			want:   "",
+		},
+		{
+			name:   "simple fields",
+			before: backend.Task{Summary: "Buy milk", Status: "NEEDS-ACTION", Priority: 1},
+			after:  backend.Task{Summary: "Buy oat milk", Status: "COMPLETED", Priority: 5},
+			want: "summary:     Buy milk → Buy oat milk\n" +
+				"status:      NEEDS-ACTION → COMPLETED\n" +
+				"priority:    1 → 5\n",
+		},
+		{
+			name:   "nil dates",
+			before: backend.Task{DueDate: &due},
+			after:  backend.Task{StartDate: &later},
+			want: "due:         2026-07-14 → (none)\n" +
+				"start:       (none) → 2026-07-20 09:30\n",
+		},
+		{
+			name:   "category set difference ignores order",
+			before: backend.Task{Categories: []string{"home", "errands", "urgent"}},
+			after:  backend.Task{Categories: []string{"urgent", "work", "errands", "call"}},
+			want:   "tags:        +call +work −home\n",
+		},
+		{
+			name:   "same categories in another order",
+			before: backend.Task{Categories: []string{"a", "b"}},
+			after:  backend.Task{Categories: []string{"b", "a"}},
+			want:   "",
+		},
+		{
+			name:   "long description cut to the changed region",
+			before: backend.Task{Description: longText + "Send it to Alice by Friday. " + longText},
+			after:  backend.Task{Description: longText + "Send it to Bob by Friday. " + longText},
+			want:   "description: …ts. Send it to Alice by Friday. The… → …ts. Send it to Bob by Friday. The…\n",
+		},
+		{
+			name:   "description added",
+			before: backend.Task{},
+			after:  backend.Task{Description: "line one\nline two"},
+			want:   "description: (none) → line one↵line two\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := stripANSI(FormatTaskDiff(tt.before, tt.after, ""))
+			if got != tt.want {
+				t.Errorf("FormatTaskDiff() =\n%s\nwant:\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatConflict(t *testing.T) {
+	conflict := Conflict{
+		ListName:   "Work",
+		Local:      backend.Task{Summary: "Report", Priority: 1},
+		Remote:     backend.Task{Summary: "Report", Priority: 3},
+		Resolution: ServerWins,
+	}
+
+	want := "  Report (Work, local → remote) [server_wins]\n" +
+		"    priority:    1 → 3\n"
+	if got := stripANSI(FormatConflict(conflict)); got != want {
+		t.Errorf("FormatConflict() =\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestFormatConflictSideBySide(t *testing.T) {
+	due := time.Date(2026, 3, 14, 0, 0, 0, 0, time.UTC)
+	conflict := Conflict{
+		ListName: "Work",
+		Local:    backend.Task{Summary: "Report", Status: "NEEDS-ACTION", Priority: 1, DueDate: &due},
+		Remote:   backend.Task{Summary: "Report", Status: "COMPLETED", Priority: 1, Description: "Sent to the whole team on Friday"},
+	}
+
+	lines := strings.Split(stripANSI(FormatConflictSideBySide(conflict, 60)), "\n")
+	rows := make(map[string]string)
+	for _, line := range lines[2:] {
+		if fields := strings.Fields(line); len(fields) > 1 {
+			name := fields[0]
+			if name == "*" {
+				name = "*" + fields[1]
+			}
+			rows[name] = line
+		}
+	}
+
+	for _, name := range []string{"*status:", "*due:", "*description:", "summary:", "priority:", "start:", "completed:"} {
+		if _, ok := rows[name]; !ok {
+			t.Errorf("FormatConflictSideBySide() has no %q row:\n%s", name, strings.Join(lines, "\n"))
+		}
+	}
+	if row := rows["*status:"]; !strings.Contains(row, "NEEDS-ACTION") || !strings.Contains(row, "│ COMPLETED") {
+		t.Errorf("status row = %q, want local then remote", row)
+	}
+	if row := rows["*description:"]; !strings.Contains(row, "…") {
+		t.Errorf("description row = %q, want the long text cut", row)
+	}
+	for _, line := range lines {
+		if width := utils.DisplayWidth(line); width > 60 {
+			t.Errorf("line %q is %d columns wide, want at most 60", line, width)
+		}
+	}
+}
+
+func TestOnlyClosingDiffers(t *testing.T) {
+	earlier := time.Date(2026, 7, 1, 9, 0, 0, 0, time.UTC)
+	later := earlier.Add(time.Hour)
+	done := backend.Task{Summary: "Report", Status: "COMPLETED", Completed: &earlier}
+
+	tests := []struct {
+		name   string
+		remote backend.Task
+		want   bool
+	}{
+		{"same completion", done, true},
+		{"completed later", backend.Task{Summary: "Report", Status: "DONE", Completed: &later}, true},
+		{"cancelled", backend.Task{Summary: "Report", Status: "CANCELLED"}, true},
+		{"reopened", backend.Task{Summary: "Report", Status: "NEEDS-ACTION"}, false},
+		{"closed with another summary", backend.Task{Summary: "Final report", Status: "COMPLETED", Completed: &later}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := onlyClosingDiffers(done, tt.remote); got != tt.want {
+				t.Errorf("onlyClosingDiffers() = %v, want %v (differing: %v)", got, tt.want, differingFields(done, tt.remote))
+			}
+		})
+	}
+}
@@ -0,0 +1,188 @@
+package sync
+
+import (
+	"errors"
+
+	"gosynctasks/backend"
+	"gosynctasks/internal/utils"
+)
+
+// DirectRouter is the task manager of a cache where some lists skip the sync
+// queue. Writes to those lists go to the remote at once and the remote's
+// version is then stored in the cache as synced, so the cache copy stays
+// current for search and the next pull finds nothing to reconcile. When
+// the remote is unreachable the write is queued as usual, with a warning.
+// Tasks with queued changes also stay on the queued path, keeping the
+// queue's order. Everything else is handled by the cache.
+type DirectRouter struct {
+	backend.TaskManager // The cache
+
+	sm          *SyncManager
+	isDirect    func(listID string) bool
+	directReads bool
+}
+
+// NewDirectRouter returns the task manager for cache, the local store of sm,
+// sending the lists isDirect selects to the remote of sm. With directReads,
+// those lists are also read from the remote, falling back to the cache when
+// it is unreachable.
+func (sm *SyncManager) NewDirectRouter(cache backend.TaskManager, isDirect func(listID string) bool, directReads bool) *DirectRouter {
+	return &DirectRouter{TaskManager: cache, sm: sm, isDirect: isDirect, directReads: directReads}
+}
+
+// direct reports whether a write of the task goes straight to the remote
+func (r *DirectRouter) direct(listID, taskUID string) bool {
+	if !r.isDirect(listID) {
+		return false
+	}
+	if taskUID == "" {
+		return true
+	}
+	queued, err := r.sm.local.IsLocallyModified(taskUID)
+	return err == nil && !queued
+}
+
+// fallBack reports whether err means the write should be queued instead
+func (r *DirectRouter) fallBack(err error) bool {
+	if !backend.IsUnreachable(err) {
+		return false
+	}
+	utils.Warnf("Remote unreachable (%s): the change is queued for the next sync instead", backend.UnreachableReason(err))
+	return true
+}
+
+// cacheRemoteTask stores the remote's version of a task just written, or
+// sent when it cannot be read back
+func (r *DirectRouter) cacheRemoteTask(listID string, sent backend.Task, isNew bool) error {
+	task := sent
+	if remote, err := r.sm.remote.GetTask(listID, sent.UID); err == nil {
+		task = *remote
+		task.Status = r.sm.toLocalStatus(task.Status)
+	} else {
+		utils.Debugf("[DIRECT] read-back of task %s failed: %v", sent.UID, err)
+	}
+	if isNew {
+		return r.sm.local.InsertSyncedTask(listID, task)
+	}
+	return r.sm.local.UpdateSyncedTask(listID, task)
+}
+
+func (r *DirectRouter) AddTask(listID string, task backend.Task) (string, error) {
+	if !r.direct(listID, "") {
+		return r.TaskManager.AddTask(listID, task)
+	}
+
+	remoteTask := task
+	remoteTask.Status = r.sm.toRemoteStatus(task.Status)
+	uid, err := r.sm.remote.AddTask(listID, remoteTask)
+	if err != nil {
+		if r.fallBack(err) {
+			return r.TaskManager.AddTask(listID, task)
+		}
+		return "", err
+	}
+
+	task.UID = uid
+	if err := r.cacheRemoteTask(listID, task, true); err != nil {
+		utils.Warnf("Task added to the remote, but not to the cache (the next sync adds it): %v", err)
+	}
+	return uid, nil
+}
+
+func (r *DirectRouter) UpdateTask(listID string, task backend.Task) error {
+	if !r.direct(listID, task.UID) {
+		return r.TaskManager.UpdateTask(listID, task)
+	}
+
+	remoteTask := task
+	remoteTask.Status = r.sm.toRemoteStatus(task.Status)
+	if err := r.sm.remote.UpdateTask(listID, remoteTask); err != nil {
+		if r.fallBack(err) {
+			return r.TaskManager.UpdateTask(listID, task)
+		}
+		return err
+	}
+
+	if err := r.cacheRemoteTask(listID, task, false); err != nil {
+		utils.Warnf("Task updated on the remote, but not in the cache (the next sync updates it): %v", err)
+	}
+	return nil
+}
+
+func (r *DirectRouter) DeleteTask(listID string, taskUID string) error {
+	if !r.direct(listID, taskUID) {
+		return r.TaskManager.DeleteTask(listID, taskUID)
+	}
+
+	if err := r.sm.remote.DeleteTask(listID, taskUID); err != nil {
+		if r.fallBack(err) {
+			return r.TaskManager.DeleteTask(listID, taskUID)
+		}
+		return err
+	}
+
+	if err := r.sm.local.DeleteSyncedTask(listID, taskUID); err != nil {
+		utils.Warnf("Task deleted on the remote, but not in the cache (the next sync deletes it): %v", err)
+	}
+	return nil
+}
+
+// readRemote reports whether reads of the list go to the remote
+func (r *DirectRouter) readRemote(listID string) bool {
+	return r.directReads && r.isDirect(listID)
+}
+
+// localStatuses converts the statuses of tasks read from the remote to the cache's
+func (r *DirectRouter) localStatuses(tasks []backend.Task) []backend.Task {
+	for i := range tasks {
+		tasks[i].Status = r.sm.toLocalStatus(tasks[i].Status)
+	}
+	return tasks
+}
+
+// readFailed reports whether a failed remote read should be served by the cache
+func (r *DirectRouter) readFailed(err error) bool {
+	if !backend.IsUnreachable(err) {
+		return false
+	}
+	utils.Warnf("Remote unreachable (%s): showing the cached copy", backend.UnreachableReason(err))
+	return true
+}
+
+func (r *DirectRouter) GetTasks(listID string, filter *backend.TaskFilter) ([]backend.Task, error) {
+	if !r.readRemote(listID) {
+		return r.TaskManager.GetTasks(listID, filter)
+	}
+	tasks, err := r.sm.remote.GetTasks(listID, filter)
+	if err != nil && r.readFailed(err) {
+		return r.TaskManager.GetTasks(listID, filter)
+	}
+	return r.localStatuses(tasks), err
+}
+
+func (r *DirectRouter) FindTasksBySummary(listID string, summary string) ([]backend.Task, error) {
+	if !r.readRemote(listID) {
+		return r.TaskManager.FindTasksBySummary(listID, summary)
+	}
+	tasks, err := r.sm.remote.FindTasksBySummary(listID, summary)
+	if err != nil && r.readFailed(err) {
+		return r.TaskManager.FindTasksBySummary(listID, summary)
+	}
+	return r.localStatuses(tasks), err
+}
+
+func (r *DirectRouter) GetTask(listID string, taskUID string) (*backend.Task, error) {
+	if !r.readRemote(listID) {
+		return r.TaskManager.GetTask(listID, taskUID)
+	}
+	task, err := r.sm.remote.GetTask(listID, taskUID)
+	var backendErr *backend.BackendError
+	if err != nil && (r.readFailed(err) || errors.As(err, &backendErr) && backendErr.IsNotFound()) {
+		// A task only queued so far is not on the remote yet
+		return r.TaskManager.GetTask(listID, taskUID)
+	}
+	if task != nil {
+		task.Status = r.sm.toLocalStatus(task.Status)
+	}
+	return task, err
+}
@@ -0,0 +1,153 @@
+package sync
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"gosynctasks/backend"
+)
+
+// setUpDirectLists returns a router where the first of two lists pulled from
+// the remote is direct
+func setUpDirectLists(t *testing.T, directReads bool) (*DirectRouter, *backend.MockBackend, string, string, func()) {
+	t.Helper()
+	sm, local, remote, cleanup := createTestSyncManager(t, ServerWins)
+	direct, _ := remote.CreateTaskList("Groceries", "", "")
+	queued, _ := remote.CreateTaskList("Work", "", "")
+	if _, err := sm.Sync(); err != nil {
+		cleanup()
+		t.Fatalf("Sync failed: %v", err)
+	}
+	isDirect := func(listID string) bool { return listID == direct }
+	return sm.NewDirectRouter(local, isDirect, directReads), remote, direct, queued, cleanup
+}
+
+func pendingOperations(t *testing.T, r *DirectRouter) int {
+	t.Helper()
+	ops, err := r.sm.local.GetPendingSyncOperations()
+	if err != nil {
+		t.Fatalf("GetPendingSyncOperations failed: %v", err)
+	}
+	return len(ops)
+}
+
+func TestDirectRouter_Routing(t *testing.T) {
+	r, remote, direct, queued, cleanup := setUpDirectLists(t, false)
+	defer cleanup()
+
+	now := time.Now()
+	uid, err := r.AddTask(direct, backend.Task{UID: "milk", Summary: "Milk", Status: "NEEDS-ACTION", Created: now, Modified: now})
+	if err != nil {
+		t.Fatalf("AddTask failed: %v", err)
+	}
+	if got, err := remote.GetTask(direct, uid); err != nil || got.Status != "NEEDS-ACTION" {
+		t.Fatalf("remote task = %+v, %v, want it added with the CalDAV status", got, err)
+	}
+	if got, err := r.GetTask(direct, uid); err != nil || got.Status != "NEEDS-ACTION" {
+		t.Fatalf("cached task = %+v, %v, want it cached with the local status", got, err)
+	}
+	if n := pendingOperations(t, r); n != 0 {
+		t.Errorf("direct add queued %d operations, want none", n)
+	}
+
+	task, _ := r.GetTask(direct, uid)
+	task.Summary = "Oat milk"
+	task.Status = "COMPLETED"
+	if err := r.UpdateTask(direct, *task); err != nil {
+		t.Fatalf("UpdateTask failed: %v", err)
+	}
+	if got, _ := remote.GetTask(direct, uid); got.Summary != "Oat milk" || got.Status != "COMPLETED" {
+		t.Errorf("remote task = %+v, want the update", got)
+	}
+	if got, _ := r.GetTask(direct, uid); got.Summary != "Oat milk" || got.Status != "COMPLETED" {
+		t.Errorf("cached task = %+v, want the update", got)
+	}
+
+	if err := r.DeleteTask(direct, uid); err != nil {
+		t.Fatalf("DeleteTask failed: %v", err)
+	}
+	if _, err := remote.GetTask(direct, uid); err == nil {
+		t.Error("task still on the remote after a direct delete")
+	}
+	if _, err := r.GetTask(direct, uid); err == nil {
+		t.Error("task still in the cache after a direct delete")
+	}
+	if n := pendingOperations(t, r); n != 0 {
+		t.Errorf("direct writes queued %d operations, want none", n)
+	}
+
+	// Other lists keep using the queue
+	if _, err := r.AddTask(queued, backend.Task{UID: "report", Summary: "Report", Status: "NEEDS-ACTION", Created: now, Modified: now}); err != nil {
+		t.Fatalf("AddTask failed: %v", err)
+	}
+	if _, err := remote.GetTask(queued, "report"); err == nil {
+		t.Error("task of a queued list was sent to the remote at once")
+	}
+	if n := pendingOperations(t, r); n != 1 {
+		t.Errorf("queued add queued %d operations, want 1", n)
+	}
+
+	// Nothing to reconcile on the next sync but the queued add
+	result, err := r.sm.Sync()
+	if err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+	if result.PushedTasks != 1 || result.ConflictsFound != 0 {
+		t.Errorf("Sync pushed %d tasks with %d conflicts, want 1 and none", result.PushedTasks, result.ConflictsFound)
+	}
+}
+
+func TestDirectRouter_UnreachableFallsBackToQueue(t *testing.T) {
+	r, remote, direct, _, cleanup := setUpDirectLists(t, true)
+	defer cleanup()
+
+	unreachable := &net.OpError{Op: "dial", Net: "tcp", Err: &net.DNSError{Err: "no such host", IsNotFound: true}}
+	remote.AddTaskErr = unreachable
+	now := time.Now()
+	uid, err := r.AddTask(direct, backend.Task{UID: "bread", Summary: "Bread", Status: "NEEDS-ACTION", Created: now, Modified: now})
+	if err != nil {
+		t.Fatalf("AddTask failed: %v", err)
+	}
+	if n := pendingOperations(t, r); n != 1 {
+		t.Fatalf("offline add queued %d operations, want 1", n)
+	}
+
+	// Once queued, the task keeps to the queue so its changes stay in order
+	remote.AddTaskErr = nil
+	task, err := r.GetTask(direct, uid)
+	if err != nil {
+		t.Fatalf("GetTask of a queued task failed: %v", err)
+	}
+	task.Summary = "Rye bread"
+	if err := r.UpdateTask(direct, *task); err != nil {
+		t.Fatalf("UpdateTask failed: %v", err)
+	}
+	if _, err := remote.GetTask(direct, uid); err == nil {
+		t.Error("update of a queued task was sent ahead of its creation")
+	}
+
+	// Rejected writes are not queued
+	remote.AddTaskErr = backend.NewBackendError("AddTask", 400, "Bad Request")
+	if _, err := r.AddTask(direct, backend.Task{UID: "eggs", Summary: "Eggs", Status: "NEEDS-ACTION"}); err == nil {
+		t.Error("AddTask rejected by the remote succeeded")
+	}
+	if n := pendingOperations(t, r); n != 1 {
+		t.Errorf("pending operations = %d, want the queued add only", n)
+	}
+}
+
+func TestDirectRouter_Reads(t *testing.T) {
+	r, remote, direct, _, cleanup := setUpDirectLists(t, true)
+	defer cleanup()
+
+	// A task added on the remote since the last pull
+	remote.AddTask(direct, backend.Task{UID: "apples", Summary: "Apples", Status: "NEEDS-ACTION"})
+	tasks, err := r.GetTasks(direct, nil)
+	if err != nil {
+		t.Fatalf("GetTasks failed: %v", err)
+	}
+	if len(tasks) != 1 || tasks[0].Status != "NEEDS-ACTION" {
+		t.Errorf("GetTasks = %+v, want the remote task with the local status", tasks)
+	}
+}
@@ -0,0 +1,204 @@
+package sync
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"gosynctasks/backend"
+	"gosynctasks/backend/git"
+	"gosynctasks/backend/sqlite"
+)
+
+// newGitRemote creates a repository with a committed task file and returns a
+// git backend with auto-commit on it
+func newGitRemote(t *testing.T, content string) (*git.GitBackend, string) {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	for _, key := range []string{"GIT_AUTHOR_NAME", "GIT_COMMITTER_NAME"} {
+		t.Setenv(key, "Test")
+	}
+	for _, key := range []string{"GIT_AUTHOR_EMAIL", "GIT_COMMITTER_EMAIL"} {
+		t.Setenv(key, "test@example.com")
+	}
+
+	repo := t.TempDir()
+	runGit := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = repo
+		if output, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, output)
+		}
+	}
+	runGit("init", "-q")
+	todoPath := filepath.Join(repo, "TODO.md")
+	if err := os.WriteFile(todoPath, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	runGit("add", "TODO.md")
+	runGit("commit", "-q", "-m", "Add tasks")
+	t.Chdir(repo)
+
+	gb, err := git.NewGitBackend(backend.BackendConfig{Type: "git", Enabled: true, AutoCommit: true})
+	if err != nil {
+		t.Fatalf("NewGitBackend() error = %v", err)
+	}
+	return gb, todoPath
+}
+
+func findBySummary(t *testing.T, tm interface {
+	GetTasks(string, *backend.TaskFilter) ([]backend.Task, error)
+}, listID, summary string) *backend.Task {
+	t.Helper()
+	tasks, err := tm.GetTasks(listID, nil)
+	if err != nil {
+		t.Fatalf("GetTasks() error = %v", err)
+	}
+	for i := range tasks {
+		if tasks[i].Summary == summary {
+			return &tasks[i]
+		}
+	}
+	return nil
+}
+
+func mustSync(t *testing.T, sm *SyncManager) *SyncResult {
+	t.Helper()
+	result, err := sm.Sync()
+	if err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+	if len(result.Errors) > 0 {
+		t.Fatalf("Sync() errors = %v", result.Errors)
+	}
+	return result
+}
+
+// TestSyncWithGitRemote syncs a SQLite cache with a TODO.md through adds,
+// updates and deletes made on each side, and a conflict won by the file
+func TestSyncWithGitRemote(t *testing.T) {
+	gb, todoPath := newGitRemote(t, `<!-- gosynctasks:enabled -->
+
+## Work
+- [ ] Write report @priority:2
+- [x] Old task @uid:old-1
+`)
+	local, err := sqlite.NewSQLiteBackend(backend.BackendConfig{
+		Type: "sqlite", Enabled: true, DBPath: filepath.Join(t.TempDir(), "cache.db"),
+	})
+	if err != nil {
+		t.Fatalf("NewSQLiteBackend() error = %v", err)
+	}
+	defer local.Close()
+	sm := NewSyncManager(local, gb, ServerWins)
+
+	// Initial pull: the hand-written task got a UID persisted in the file
+	mustSync(t, sm)
+	report := findBySummary(t, local, "Work", "Write report")
+	if report == nil || report.UID == "" || report.Priority != 2 || report.Status != "NEEDS-ACTION" {
+		t.Fatalf("pulled report = %+v", report)
+	}
+	if old := findBySummary(t, local, "Work", "Old task"); old == nil || old.Status != "COMPLETED" {
+		t.Fatalf("pulled old task = %+v, want COMPLETED", old)
+	}
+	content, _ := os.ReadFile(todoPath)
+	if !strings.Contains(string(content), "@uid:"+report.UID) {
+		t.Fatalf("generated UID not written to the file:\n%s", content)
+	}
+
+	// Unchanged file: the list is skipped
+	result := mustSync(t, sm)
+	if len(result.ListResults) != 1 || !result.ListResults[0].Skipped {
+		t.Errorf("unchanged list not skipped: %+v", result.ListResults)
+	}
+
+	// Changes in the file reach the cache
+	if _, err := gb.AddTask("Work", backend.Task{Summary: "Buy milk", Status: "TODO"}); err != nil {
+		t.Fatal(err)
+	}
+	remoteReport := *report
+	remoteReport.Summary = "Write annual report"
+	remoteReport.Status = "PROCESSING"
+	if err := gb.UpdateTask("Work", remoteReport); err != nil {
+		t.Fatal(err)
+	}
+	if err := gb.DeleteTask("Work", "old-1"); err != nil {
+		t.Fatal(err)
+	}
+	mustSync(t, sm)
+	if task := findBySummary(t, local, "Work", "Write annual report"); task == nil || task.Status != "IN-PROCESS" {
+		t.Errorf("updated task in cache = %+v", task)
+	}
+	milk := findBySummary(t, local, "Work", "Buy milk")
+	if milk == nil {
+		t.Fatal("added task not pulled")
+	}
+	if findBySummary(t, local, "Work", "Old task") != nil {
+		t.Error("deleted task still cached")
+	}
+
+	// Changes in the cache reach the file
+	if _, err := local.AddTask("Work", backend.Task{Summary: "Call Bob", Status: "NEEDS-ACTION", Categories: []string{"phone", "home office"}}); err != nil {
+		t.Fatal(err)
+	}
+	milk.Status = "COMPLETED"
+	if err := local.UpdateTask("Work", *milk); err != nil {
+		t.Fatal(err)
+	}
+	if err := local.DeleteTask("Work", report.UID); err != nil {
+		t.Fatal(err)
+	}
+	mustSync(t, sm)
+	call := findBySummary(t, gb, "Work", "Call Bob")
+	if call == nil || call.Status != "TODO" || strings.Join(call.Categories, ",") != "phone,home office" {
+		t.Fatalf("pushed task in file = %+v", call)
+	}
+	if task := findBySummary(t, gb, "Work", "Buy milk"); task == nil || task.Status != "DONE" {
+		t.Errorf("completed task in file = %+v", task)
+	}
+	if findBySummary(t, gb, "Work", "Write annual report") != nil {
+		t.Error("deleted task still in the file")
+	}
+	content, _ = os.ReadFile(todoPath)
+	if !strings.Contains(string(content), "- [x] Buy milk") {
+		t.Errorf("file content:\n%s", content)
+	}
+	cmd := exec.Command("git", "log", "--format=%s")
+	log, err := cmd.Output()
+	if err != nil || !strings.Contains(string(log), "gosynctasks: Update tasks in TODO.md") {
+		t.Errorf("changes not committed: %v\n%s", err, log)
+	}
+
+	// Both sides change the same task; the hand edit in the file wins
+	localCall := *findBySummary(t, local, "Work", "Call Bob")
+	localCall.Summary = "Call Bob today"
+	if err := local.UpdateTask("Work", localCall); err != nil {
+		t.Fatal(err)
+	}
+	content, _ = os.ReadFile(todoPath)
+	edited := strings.Replace(string(content), "Call Bob", "Call Bob tomorrow", 1)
+	if err := os.WriteFile(todoPath, []byte(edited), 0644); err != nil {
+		t.Fatal(err)
+	}
+	later := time.Now().Add(time.Minute)
+	if err := os.Chtimes(todoPath, later, later); err != nil {
+		t.Fatal(err)
+	}
+
+	result = mustSync(t, sm)
+	if result.ConflictsFound != 1 {
+		t.Errorf("ConflictsFound = %d, want 1", result.ConflictsFound)
+	}
+	if findBySummary(t, local, "Work", "Call Bob tomorrow") == nil {
+		t.Error("server version not kept in the cache")
+	}
+	if findBySummary(t, gb, "Work", "Call Bob today") != nil {
+		t.Error("discarded local change pushed to the file")
+	}
+}
@@ -1,9 +1,14 @@
 package sync
 
 import (
-	"database/sql"
+	"errors"
 	"fmt"
+	"maps"
+	"net/url"
+	"path"
+	"slices"
 	"strings"
+	"sync"
 	"time"
 
 	"gosynctasks/backend"
@@ -19,17 +24,40 @@ const (
 	LocalWins  ConflictResolutionStrategy = "local_wins"  // Overwrite server with local version
 	Merge      ConflictResolutionStrategy = "merge"       // Combine non-conflicting fields
 	KeepBoth   ConflictResolutionStrategy = "keep_both"   // Create duplicate with suffix
+	Prompt     ConflictResolutionStrategy = "prompt"      // Ask for each conflict, the fallback strategy when nobody can be asked
 )
 
+// Conflict is a task changed both locally and remotely since the last sync
+type Conflict struct {
+	ListID     string
+	ListName   string
+	Local      backend.Task
+	Remote     backend.Task
+	Resolution ConflictResolutionStrategy // Strategy applied; empty in a preview
+}
+
+// ConflictPrompter asks how to resolve a conflict under the Prompt strategy
+type ConflictPrompter func(conflict Conflict) (ConflictResolutionStrategy, error)
+
 // SyncManager coordinates synchronization between local SQLite and remote backend
 type SyncManager struct {
-	local    *sqlite.SQLiteBackend
+	local    LocalStore
 	remote   backend.TaskManager
 	strategy ConflictResolutionStrategy
+	prompter ConflictPrompter
+	fallback ConflictResolutionStrategy
+
+	maxRetries     int
+	parallelism    int
+	beforeFullSync func() error
 }
 
+// DefaultMaxRetries is how many times a queued operation is pushed before it
+// is given up on, unless SetMaxRetries says otherwise
+const DefaultMaxRetries = 5
+
 // NewSyncManager creates a new sync manager
-func NewSyncManager(local *sqlite.SQLiteBackend, remote backend.TaskManager, strategy ConflictResolutionStrategy) *SyncManager {
+func NewSyncManager(local LocalStore, remote backend.TaskManager, strategy ConflictResolutionStrategy) *SyncManager {
 	return &SyncManager{
 		local:    local,
 		remote:   remote,
@@ -37,23 +65,197 @@ func NewSyncManager(local *sqlite.SQLiteBackend, remote backend.TaskManager, str
 	}
 }
 
+// SetConflictPrompter sets the function asked for each conflict under the
+// Prompt strategy. Without one, the fallback strategy applies.
+func (sm *SyncManager) SetConflictPrompter(prompter ConflictPrompter) {
+	sm.prompter = prompter
+}
+
+// SetConflictFallback sets the strategy the Prompt strategy applies when
+// there is no prompter, keep_both by default
+func (sm *SyncManager) SetConflictFallback(strategy ConflictResolutionStrategy) {
+	sm.fallback = strategy
+}
+
+// conflictFallback returns the strategy applied to prompted conflicts
+// nobody is asked about
+func (sm *SyncManager) conflictFallback() ConflictResolutionStrategy {
+	if sm.fallback == "" {
+		return KeepBoth
+	}
+	return sm.fallback
+}
+
+// SetMaxRetries sets how many failed pushes of a queued operation are
+// retried; operations past it stay queued, reported in SyncResult.Errors,
+// until their retry count is reset. Zero or less means DefaultMaxRetries.
+func (sm *SyncManager) SetMaxRetries(maxRetries int) {
+	sm.maxRetries = maxRetries
+}
+
+// retryLimit returns the retry count past which operations are not pushed
+func (sm *SyncManager) retryLimit() int {
+	if sm.maxRetries <= 0 {
+		return DefaultMaxRetries
+	}
+	return sm.maxRetries
+}
+
+// DefaultParallelism is how many operations a push sends at once, and how
+// many lists a pull fetches at once, to a remote accepting concurrent
+// requests, unless SetParallelism says otherwise
+const DefaultParallelism = 4
+
+// SetParallelism sets how many queued operations a push sends at once when
+// the remote is a backend.ConcurrentWriter, and how many lists a pull fetches
+// at once when it is a backend.ConcurrentReader; other remotes get one
+// request at a time. Zero or less means DefaultParallelism.
+func (sm *SyncManager) SetParallelism(parallelism int) {
+	sm.parallelism = parallelism
+}
+
+// pushWorkers returns how many operations a push sends at once
+func (sm *SyncManager) pushWorkers() int {
+	if writer, ok := backend.As[backend.ConcurrentWriter](sm.remote); !ok || !writer.WritesConcurrently() {
+		return 1
+	}
+	return sm.requestLimit()
+}
+
+// pullWorkers returns how many lists a pull fetches at once
+func (sm *SyncManager) pullWorkers() int {
+	if reader, ok := backend.As[backend.ConcurrentReader](sm.remote); !ok || !reader.ReadsConcurrently() {
+		return 1
+	}
+	return sm.requestLimit()
+}
+
+// requestLimit returns how many requests a sync sends at once to a remote
+// accepting them concurrently
+func (sm *SyncManager) requestLimit() int {
+	if sm.parallelism <= 0 {
+		return DefaultParallelism
+	}
+	return sm.parallelism
+}
+
+// maxRetryBackoff caps the wait before a failed operation is pushed again
+const maxRetryBackoff = 5 * time.Minute
+
+// retryBackoff returns how long an operation that failed retryCount times
+// before waits before its next push: 1s, 2s, 4s... up to maxRetryBackoff
+func retryBackoff(retryCount int) time.Duration {
+	if retryCount >= 9 {
+		return maxRetryBackoff
+	}
+	return min(time.Duration(1<<retryCount)*time.Second, maxRetryBackoff)
+}
+
+// SetBeforeFullSync sets a function run before a full sync rewrites the local
+// store, such as a backup. The full sync is not started if it fails.
+func (sm *SyncManager) SetBeforeFullSync(hook func() error) {
+	sm.beforeFullSync = hook
+}
+
 // SyncResult contains statistics about the sync operation
 type SyncResult struct {
 	PulledTasks       int
 	PushedTasks       int
 	ConflictsFound    int
 	ConflictsResolved int
+	Conflicts         []Conflict
 	Errors            []error
 	Duration          time.Duration
+	ListResults       []ListSyncResult // Per list, in remote order; lists only pushed to come last
+	Rewrites          []RemoteRewrite  // Pushed tasks the remote stored with different content
+	Stranded          []StrandedList   // Lists deleted on the remote with local changes left
+	ListID            string           // The list SyncList synced; empty when every list was
+}
+
+// ResolvedBy counts the resolved conflicts by the strategy applied to them,
+// which for the Prompt strategy is what the user picked
+func (r *SyncResult) ResolvedBy() map[ConflictResolutionStrategy]int {
+	counts := make(map[ConflictResolutionStrategy]int)
+	for _, conflict := range r.Conflicts {
+		if conflict.Resolution != "" {
+			counts[conflict.Resolution]++
+		}
+	}
+	return counts
+}
+
+// ListSyncResult is what a sync did with one list
+type ListSyncResult struct {
+	ListID     string
+	Name       string
+	Skipped    bool   // Not pulled, see SkipReason
+	SkipReason string // Why the list was not pulled
+	LocalCTag  string // CTag cached before the sync
+	RemoteCTag string // CTag reported by the remote
+	Delta      bool   // Only the tasks changed since the list's sync token were fetched
+	Pulled     int
+	Pushed     int
+	Conflicts  int
+	Err        error // First problem with the list; the sync went on with other lists
+}
+
+// listResult returns the result of listID, adding one if the list has none yet
+func (r *SyncResult) listResult(listID string) *ListSyncResult {
+	for i := range r.ListResults {
+		if r.ListResults[i].ListID == listID {
+			return &r.ListResults[i]
+		}
+	}
+	r.ListResults = append(r.ListResults, ListSyncResult{ListID: listID, Name: listID})
+	return &r.ListResults[len(r.ListResults)-1]
+}
+
+// addPushResults counts the pushed operations and failures of a push by list
+func (r *SyncResult) addPushResults(push *pushResult) {
+	r.PushedTasks = push.PushedTasks
+	r.Rewrites = push.rewrites
+	r.Stranded = push.stranded
+	for _, listID := range push.pushedLists {
+		r.listResult(listID).Pushed++
+	}
+	for _, failure := range push.failures {
+		if list := r.listResult(failure.listID); list.Err == nil {
+			list.Err = failure.err
+		}
+		r.Errors = append(r.Errors, failure.err)
+	}
+	for _, exhausted := range push.exhausted {
+		if list := r.listResult(exhausted.listID); list.Err == nil {
+			list.Err = exhausted.err
+		}
+		r.Errors = append(r.Errors, exhausted.err)
+	}
+	for _, conflict := range push.conflicts {
+		r.ConflictsFound++
+		r.listResult(conflict.listID).Conflicts++
+		r.Errors = append(r.Errors, fmt.Errorf("%w; the change stays queued and the conflict is resolved on the next sync", conflict.err))
+	}
 }
 
 // Sync performs bidirectional synchronization
 func (sm *SyncManager) Sync() (*SyncResult, error) {
+	return sm.sync("")
+}
+
+// SyncList synchronizes one list: it pulls the list, skipped as in a sync
+// when its CTag did not change, and pushes only its pending changes. Other
+// lists are left alone, changes queued for them included.
+func (sm *SyncManager) SyncList(listID string) (*SyncResult, error) {
+	return sm.sync(listID)
+}
+
+// sync pulls then pushes every list, or only listID when it is not empty
+func (sm *SyncManager) sync(listID string) (*SyncResult, error) {
 	startTime := time.Now()
-	result := &SyncResult{}
+	result := &SyncResult{ListID: listID}
 
 	// Phase 1: Pull remote changes
-	pullResult, err := sm.pull()
+	pullResult, err := sm.pull(listID)
 	if err != nil {
 		result.Errors = append(result.Errors, fmt.Errorf("pull phase failed: %w", err))
 		// Continue to push phase even if pull fails
@@ -61,14 +263,21 @@ func (sm *SyncManager) Sync() (*SyncResult, error) {
 		result.PulledTasks = pullResult.PulledTasks
 		result.ConflictsFound = pullResult.ConflictsFound
 		result.ConflictsResolved = pullResult.ConflictsResolved
+		result.Conflicts = pullResult.Conflicts
+		result.Errors = append(result.Errors, pullResult.Errors...)
+		result.ListResults = pullResult.Lists
 	}
 
 	// Phase 2: Push local changes
-	pushResult, err := sm.push()
+	var listIDs []string
+	if listID != "" {
+		listIDs = []string{listID}
+	}
+	pushResult, err := sm.pushLists(listIDs)
 	if err != nil {
 		result.Errors = append(result.Errors, fmt.Errorf("push phase failed: %w", err))
 	} else {
-		result.PushedTasks = pushResult.PushedTasks
+		result.addPushResults(pushResult)
 	}
 
 	result.Duration = time.Since(startTime)
@@ -80,10 +289,14 @@ type pullResult struct {
 	PulledTasks       int
 	ConflictsFound    int
 	ConflictsResolved int
+	Conflicts         []Conflict
+	Errors            []error // Non-fatal problems with individual lists
+	Lists             []ListSyncResult
 }
 
-// pull retrieves remote changes and applies them locally
-func (sm *SyncManager) pull() (*pullResult, error) {
+// pull retrieves remote changes and applies them locally, for every list or
+// only listID when it is not empty
+func (sm *SyncManager) pull(listID string) (*pullResult, error) {
 	result := &pullResult{}
 
 	// Get all remote task lists
@@ -91,163 +304,487 @@ func (sm *SyncManager) pull() (*pullResult, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to get remote lists: %w", err)
 	}
+	remoteLists = backend.NumberListPositions(remoteLists)
+
+	// Archived lists are left out of the remote lists; their cached copies are
+	// hidden instead of pulled
+	if listID == "" {
+		if err := sm.syncArchivedLists(remoteLists); err != nil {
+			result.Errors = append(result.Errors, err)
+		}
+	}
 
-	// Sync each list
+	// Decide which lists to fetch before fetching any: lists whose CTag is
+	// unchanged are skipped
+	var pulls []*listPull
 	for _, remoteList := range remoteLists {
-		// Check if list exists locally
-		localLists, err := sm.local.GetTaskLists()
+		if listID != "" && remoteList.ID != listID {
+			continue
+		}
+		pull, err := sm.preparePull(remoteList)
 		if err != nil {
-			return nil, fmt.Errorf("failed to get local lists: %w", err)
+			return nil, err
 		}
+		pulls = append(pulls, pull)
+	}
+	if listID != "" && len(pulls) == 0 {
+		return nil, fmt.Errorf("list %s not found on the remote", listID)
+	}
 
-		// Find or create list locally
-		listExists := false
-		var localCTag string
-		for _, localList := range localLists {
-			if localList.ID == remoteList.ID {
-				listExists = true
-				localCTag = localList.CTags
-				break
-			}
+	// Fetch the changed lists a few at a time, then apply them one after the
+	// other, so the cache has a single writer
+	forEachConcurrently(pulls, sm.pullWorkers(), sm.fetchList)
+	for _, pull := range pulls {
+		err := sm.applyList(pull, result)
+		result.Lists = append(result.Lists, pull.result)
+		if err != nil {
+			return nil, err
 		}
+	}
 
-		// Check if list changed (CTag comparison)
-		if listExists && localCTag == remoteList.CTags {
-			// No changes, skip this list
-			continue
+	return result, nil
+}
+
+// pullList applies the remote changes of one list locally, counting them in
+// result and listResult
+func (sm *SyncManager) pullList(remoteList backend.TaskList, result *pullResult, listResult *ListSyncResult) error {
+	pull, err := sm.preparePull(remoteList)
+	if err != nil {
+		return err
+	}
+	sm.fetchList(pull)
+	err = sm.applyList(pull, result)
+	*listResult = pull.result
+	return err
+}
+
+// listPull is a list being pulled. It is prepared and applied to the cache
+// one list after the other, and fetched from the remote in between,
+// concurrently with the other lists.
+type listPull struct {
+	remote backend.TaskList
+	result ListSyncResult
+	skip   bool   // The CTag is unchanged, there is nothing to fetch
+	exists bool   // The list was cached before the pull
+	token  string // Sync token of the last pull, to fetch only the changes
+
+	changes *backend.ListChanges // Changes since token, nil when the list is fetched in full
+	tasks   []backend.Task       // The changed tasks, or all of them
+	err     error                // Error fetching the list in full
+}
+
+// preparePull compares the CTag of a remote list with its cached copy's,
+// keeping the copy's name, path and position current, and reads what the
+// fetch of a changed list needs from the cache
+func (sm *SyncManager) preparePull(remoteList backend.TaskList) (*listPull, error) {
+	pull := &listPull{
+		remote: remoteList,
+		result: ListSyncResult{ListID: remoteList.ID, Name: remoteList.Name, RemoteCTag: remoteList.CTags},
+	}
+
+	// Check if list exists locally
+	localLists, err := sm.local.GetTaskLists()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get local lists: %w", err)
+	}
+
+	// Find or create list locally
+	listExists := false
+	var localCTag string
+	var localName, localPath string
+	var localPosition int
+	for _, localList := range localLists {
+		if localList.ID == remoteList.ID {
+			listExists = true
+			localCTag = localList.CTags
+			localName, localPath = localList.Name, localList.Path
+			localPosition = localList.Position
+			break
 		}
+	}
 
-		// Create list if it doesn't exist
-		if !listExists {
-			// Insert list metadata
-			db, err := sm.local.GetDB()
-			if err != nil {
-				return nil, err
-			}
+	// Keep name, path and position current even when the list's tasks are
+	// unchanged, so renaming a parent project updates the paths of its children
+	if listExists && (localName != remoteList.Name || localPath != remoteList.Path || localPosition != remoteList.Position) {
+		if err := sm.local.UpdateListInfo(remoteList); err != nil {
+			return nil, fmt.Errorf("failed to update list name: %w", err)
+		}
+	}
 
-			now := time.Now().Unix()
-			_, err = db.Exec(`
-				INSERT INTO list_sync_metadata (list_id, backend_name, list_name, list_color, last_ctag, last_full_sync, created_at, modified_at)
-				VALUES (?, ?, ?, ?, ?, ?, ?, ?)
-			`, remoteList.ID, sm.getBackendName(), remoteList.Name, remoteList.Color, remoteList.CTags, now, now, now)
-			if err != nil {
-				return nil, fmt.Errorf("failed to create local list: %w", err)
-			}
-		} else {
-			// Update list CTag
-			db, err := sm.local.GetDB()
-			if err != nil {
-				return nil, err
-			}
+	// Check if list changed (CTag comparison); lists of backends without
+	// CTags, like Todoist projects, are always fetched
+	pull.result.LocalCTag = localCTag
+	if listExists && remoteList.CTags != "" && localCTag == remoteList.CTags {
+		// No changes, skip this list
+		pull.skip = true
+		pull.result.Skipped = true
+		pull.result.SkipReason = fmt.Sprintf("CTag unchanged (local %q, remote %q)", localCTag, remoteList.CTags)
+		return pull, nil
+	}
+	pull.exists = listExists
 
-			_, err = db.Exec(`
-				UPDATE list_sync_metadata
-				SET last_ctag = ?, last_full_sync = ?
-				WHERE backend_name = ? AND list_id = ?
-			`, remoteList.CTags, time.Now().Unix(), sm.getBackendName(), remoteList.ID)
-			if err != nil {
-				return nil, fmt.Errorf("failed to update list CTag: %w", err)
+	// Lists pulled before are fetched from their changes when the remote
+	// tracks them
+	if _, ok := backend.As[backend.ChangeTracker](sm.remote); ok && listExists {
+		if pull.token, err = sm.local.GetListSyncToken(remoteList.ID); err != nil {
+			return nil, fmt.Errorf("failed to get list sync token: %w", err)
+		}
+	}
+	return pull, nil
+}
+
+// fetchList fetches the tasks of a list changed since its sync token, or all
+// of them when the remote doesn't track changes, there is no token, or the
+// changes can't be had, e.g. the token expired. Only the remote is read, so
+// that lists can be fetched concurrently.
+func (sm *SyncManager) fetchList(pull *listPull) {
+	if pull.skip {
+		return
+	}
+	if tracker, ok := backend.As[backend.ChangeTracker](sm.remote); ok && pull.token != "" {
+		changes, err := tracker.GetChanges(pull.remote.ID, pull.token)
+		if err == nil {
+			tasks, err := tracker.GetTasksAt(pull.remote.ID, changes.Changed)
+			if err == nil {
+				pull.changes, pull.tasks = changes, tasks
+				return
 			}
 		}
+	}
+	sm.fetchAll(pull)
+}
 
-		// Get all remote tasks for this list
-		remoteTasks, err := sm.remote.GetTasks(remoteList.ID, nil)
-		if err != nil {
-			return nil, fmt.Errorf("failed to get remote tasks for list %s: %w", remoteList.ID, err)
+// fetchAll fetches every task of a list, including completed ones so
+// completions made elsewhere reach the cache
+func (sm *SyncManager) fetchAll(pull *listPull) {
+	pull.changes = nil
+	pull.tasks, pull.err = sm.remote.GetTasks(pull.remote.ID, &backend.TaskFilter{IncludeCompleted: true})
+}
+
+// applyList applies a fetched list to the cache, counting the changes in
+// result and pull.result
+func (sm *SyncManager) applyList(pull *listPull, result *pullResult) error {
+	if pull.skip {
+		return nil
+	}
+	remoteList, listResult := pull.remote, &pull.result
+
+	// Create list if it doesn't exist
+	if !pull.exists {
+		if err := sm.local.CreateSyncedList(remoteList); err != nil {
+			return fmt.Errorf("failed to create local list: %w", err)
 		}
+	} else {
+		if err := sm.local.SetListCTag(remoteList.ID, remoteList.CTags); err != nil {
+			return fmt.Errorf("failed to update list CTag: %w", err)
+		}
+	}
 
-		// Sort remote tasks so parents come before children (important for foreign key constraints)
-		remoteTasks = sortTasksByHierarchy(remoteTasks)
+	if pull.changes != nil {
+		applied, err := sm.applyChanges(pull, result)
+		if err != nil || applied {
+			return err
+		}
+		// The changes can't be applied exactly; fetch the list in full
+		sm.fetchAll(pull)
+	}
 
-		// Get all local tasks for this list
-		localTasks, err := sm.local.GetTasks(remoteList.ID, nil)
-		if err != nil {
-			return nil, fmt.Errorf("failed to get local tasks for list %s: %w", remoteList.ID, err)
+	remoteTasks, err := pull.tasks, pull.err
+	if err != nil {
+		// Report the list and go on with the others; forget its CTag and sync
+		// token so it is fetched again on the next sync
+		listResult.Err = err
+		result.Errors = append(result.Errors, fmt.Errorf("failed to get remote tasks for list %s: %w", remoteList.Name, err))
+		if err := sm.local.SetListCTag(remoteList.ID, ""); err != nil {
+			return fmt.Errorf("failed to update list CTag: %w", err)
 		}
+		if err := sm.local.SetListSyncToken(remoteList.ID, ""); err != nil {
+			return fmt.Errorf("failed to update list sync token: %w", err)
+		}
+		return nil
+	}
 
-		// Create map of local tasks for quick lookup
-		localTaskMap := make(map[string]*backend.Task)
-		for i := range localTasks {
-			localTaskMap[localTasks[i].UID] = &localTasks[i]
+	// A list returning the same UID twice cannot be trusted for deletion
+	// inference; keep the first copy of each task and report the list
+	inconsistent := false
+	if duplicates := backend.DuplicateUIDs(remoteTasks); duplicates != nil {
+		inconsistent = true
+		remoteTasks = firstOccurrences(remoteTasks)
+		result.Errors = append(result.Errors, fmt.Errorf(
+			"list %s returned %d task UID(s) more than once; kept the first copy of each and skipped deleting local tasks (repair with 'gosynctasks --backend <remote> dedupe <list> --by-uid')",
+			remoteList.Name, len(duplicates)))
+
+		// Forget the CTag and sync token so the list is fetched in full
+		// again on the next sync
+		if err := sm.local.SetListCTag(remoteList.ID, ""); err != nil {
+			return fmt.Errorf("failed to update list CTag: %w", err)
 		}
+		if err := sm.local.SetListSyncToken(remoteList.ID, ""); err != nil {
+			return fmt.Errorf("failed to update list sync token: %w", err)
+		}
+	}
+
+	// Sort remote tasks so parents come before children (important for foreign key constraints)
+	remoteTasks = backend.SortTasksByHierarchy(remoteTasks)
+	for i := range remoteTasks {
+		remoteTasks[i].Status = sm.toLocalStatus(remoteTasks[i].Status)
+	}
 
-		// Process each remote task
-		for _, remoteTask := range remoteTasks {
-			localTask, exists := localTaskMap[remoteTask.UID]
+	// Get all local tasks for this list
+	localTasks, err := sm.local.GetTasks(remoteList.ID, nil)
+	if err != nil {
+		return fmt.Errorf("failed to get local tasks for list %s: %w", remoteList.ID, err)
+	}
 
-			if !exists {
-				// New remote task - insert locally
-				err := sm.insertTaskLocally(remoteList.ID, remoteTask)
-				if err != nil {
-					return nil, fmt.Errorf("failed to insert task %s: %w", remoteTask.UID, err)
-				}
-				result.PulledTasks++
-			} else {
-				// backend.Task exists locally - check for conflict
-				isLocallyModified, err := sm.isTaskLocallyModified(remoteTask.UID)
-				if err != nil {
-					return nil, err
-				}
+	// Create map of local tasks for quick lookup
+	localTaskMap := make(map[string]*backend.Task)
+	for i := range localTasks {
+		localTaskMap[localTasks[i].UID] = &localTasks[i]
+	}
 
-				isRemoteModified, err := sm.isTaskRemoteModified(remoteTask)
-				if err != nil {
-					return nil, err
-				}
+	// Completed tasks pruned by the retention policy stay on the remote only,
+	// until they change there
+	pruned, err := sm.local.PrunedTasks(remoteList.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get pruned tasks for list %s: %w", remoteList.ID, err)
+	}
 
-				if isLocallyModified && isRemoteModified {
-					// Both modified - real conflict
-					result.ConflictsFound++
-					err := sm.resolveConflict(remoteList.ID, *localTask, remoteTask)
-					if err != nil {
-						return nil, fmt.Errorf("failed to resolve conflict for task %s: %w", remoteTask.UID, err)
-					}
-					result.ConflictsResolved++
-				} else if isLocallyModified {
-					// Only local modified - will be pushed in push phase, don't update local
-					// Do nothing here, let push phase handle it
-				} else {
-					// Remote modified or neither modified - update local with remote
-					err := sm.updateTaskLocally(remoteList.ID, remoteTask)
-					if err != nil {
-						return nil, fmt.Errorf("failed to update task %s: %w", remoteTask.UID, err)
-					}
-					result.PulledTasks++
-				}
+	// A list new to the cache is filled in one transaction, there being
+	// nothing to compare its tasks with
+	if !pull.exists && len(localTasks) == 0 && len(pruned) == 0 {
+		if err := sm.local.BulkInsertTasks(remoteList.ID, remoteTasks); err != nil {
+			return fmt.Errorf("failed to insert the tasks of list %s: %w", remoteList.ID, err)
+		}
+		result.PulledTasks += len(remoteTasks)
+		listResult.Pulled += len(remoteTasks)
+		if inconsistent {
+			return nil
+		}
+		if err := sm.local.SetListSyncToken(remoteList.ID, remoteList.SyncToken); err != nil {
+			return fmt.Errorf("failed to update list sync token: %w", err)
+		}
+		return nil
+	}
+
+	// Process each remote task
+	for _, remoteTask := range remoteTasks {
+		localTask, exists := localTaskMap[remoteTask.UID]
+
+		if modified, ok := pruned[remoteTask.UID]; ok {
+			delete(pruned, remoteTask.UID)
+			if !exists && remoteTask.Modified.Unix() <= modified.Unix() {
+				continue
 			}
+		}
+
+		if err := sm.pullTask(remoteList, remoteTask, localTask, result, listResult); err != nil {
+			return err
+		}
+
+		// Remove from map (for deletion detection)
+		delete(localTaskMap, remoteTask.UID)
+	}
+
+	// Remaining tasks in map were deleted remotely
+	if inconsistent {
+		return nil
+	}
+	if len(pruned) > 0 {
+		// Pruned tasks the remote no longer has are not worth remembering
+		if err := sm.local.ForgetPrunedTasks(remoteList.ID, slices.Collect(maps.Keys(pruned))); err != nil {
+			return fmt.Errorf("failed to forget pruned tasks: %w", err)
+		}
+	}
+	for _, deletedTask := range localTaskMap {
+		if err := sm.pullDeletion(remoteList.ID, deletedTask.UID); err != nil {
+			return err
+		}
+	}
+
+	if err := sm.local.SetListSyncToken(remoteList.ID, remoteList.SyncToken); err != nil {
+		return fmt.Errorf("failed to update list sync token: %w", err)
+	}
+	return nil
+}
+
+// applyChanges applies the changes of a list since its sync token. It
+// reports false, having changed nothing, when they can't be applied exactly,
+// a deleted task not being told from its href, for the caller to fetch the
+// list in full.
+func (sm *SyncManager) applyChanges(pull *listPull, result *pullResult) (bool, error) {
+	remoteList, listResult, changes := pull.remote, &pull.result, pull.changes
+
+	localTasks, err := sm.local.GetTasks(remoteList.ID, nil)
+	if err != nil {
+		return false, fmt.Errorf("failed to get local tasks for list %s: %w", remoteList.ID, err)
+	}
+	localTaskMap := make(map[string]*backend.Task)
+	for i := range localTasks {
+		localTaskMap[localTasks[i].UID] = &localTasks[i]
+	}
+	pruned, err := sm.local.PrunedTasks(remoteList.ID)
+	if err != nil {
+		return false, fmt.Errorf("failed to get pruned tasks for list %s: %w", remoteList.ID, err)
+	}
+
+	// Deleted tasks are known by the name of their resource, which is their
+	// UID unless a client chose another; a name matching no cached task may be
+	// one of those
+	var deleted, forgotten []string
+	for _, href := range changes.Deleted {
+		uid := hrefUID(href)
+		if _, ok := localTaskMap[uid]; ok {
+			deleted = append(deleted, uid)
+		} else if _, ok := pruned[uid]; ok {
+			forgotten = append(forgotten, uid)
+		} else {
+			return false, nil
+		}
+	}
+
+	remoteTasks := backend.SortTasksByHierarchy(firstOccurrences(pull.tasks))
+	for i := range remoteTasks {
+		remoteTasks[i].Status = sm.toLocalStatus(remoteTasks[i].Status)
+	}
+
+	listResult.Delta = true
+	for _, remoteTask := range remoteTasks {
+		localTask := localTaskMap[remoteTask.UID]
+		if modified, ok := pruned[remoteTask.UID]; ok && localTask == nil && remoteTask.Modified.Unix() <= modified.Unix() {
+			continue
+		}
+		if err := sm.pullTask(remoteList, remoteTask, localTask, result, listResult); err != nil {
+			return true, err
+		}
+	}
+	for _, uid := range deleted {
+		if err := sm.pullDeletion(remoteList.ID, uid); err != nil {
+			return true, err
+		}
+	}
+	if len(forgotten) > 0 {
+		if err := sm.local.ForgetPrunedTasks(remoteList.ID, forgotten); err != nil {
+			return true, fmt.Errorf("failed to forget pruned tasks: %w", err)
+		}
+	}
 
-			// Remove from map (for deletion detection)
-			delete(localTaskMap, remoteTask.UID)
+	if err := sm.local.SetListSyncToken(remoteList.ID, changes.SyncToken); err != nil {
+		return true, fmt.Errorf("failed to update list sync token: %w", err)
+	}
+	return true, nil
+}
+
+// hrefUID returns the name of the resource at href without its extension,
+// the UID of the task it holds for resources named as CalDAV clients do
+func hrefUID(href string) string {
+	name := path.Base(href)
+	if unescaped, err := url.PathUnescape(name); err == nil {
+		name = unescaped
+	}
+	return strings.TrimSuffix(name, ".ics")
+}
+
+// pullTask applies one remote task locally: inserted when new, updated unless
+// only changed locally, and resolved as a conflict when changed on both sides.
+// localTask is the cached copy, nil for a new task.
+func (sm *SyncManager) pullTask(remoteList backend.TaskList, remoteTask backend.Task, localTask *backend.Task, result *pullResult, listResult *ListSyncResult) error {
+	if localTask == nil {
+		// New remote task - insert locally
+		err := sm.local.InsertSyncedTask(remoteList.ID, remoteTask)
+		if err != nil {
+			return fmt.Errorf("failed to insert task %s: %w", remoteTask.UID, err)
+		}
+		result.PulledTasks++
+		listResult.Pulled++
+	} else {
+		// backend.Task exists locally - check for conflict
+		isLocallyModified, err := sm.local.IsLocallyModified(remoteTask.UID)
+		if err != nil {
+			return err
+		}
+
+		isRemoteModified, err := sm.isTaskRemoteModified(*localTask, remoteTask)
+		if err != nil {
+			return err
 		}
 
-		// Remaining tasks in map were deleted remotely
-		for _, deletedTask := range localTaskMap {
-			isLocallyModified, err := sm.isTaskLocallyModified(deletedTask.UID)
+		if isLocallyModified && isRemoteModified && onlyClosingDiffers(*localTask, remoteTask) {
+			// Closed on both sides, e.g. completed on two devices: not a conflict
+			if err := sm.acceptClosedRemote(remoteList.ID, *localTask, remoteTask); err != nil {
+				return fmt.Errorf("failed to update task %s: %w", remoteTask.UID, err)
+			}
+			result.PulledTasks++
+			listResult.Pulled++
+		} else if isLocallyModified && isRemoteModified {
+			// Both modified - real conflict
+			result.ConflictsFound++
+			listResult.Conflicts++
+			conflict := Conflict{ListID: remoteList.ID, ListName: remoteList.Name, Local: *localTask, Remote: remoteTask}
+			err := sm.resolveConflict(&conflict)
 			if err != nil {
-				return nil, err
+				return fmt.Errorf("failed to resolve conflict for task %s: %w", remoteTask.UID, err)
 			}
-
-			if !isLocallyModified {
-				// Delete locally
-				err := sm.deleteTaskLocally(remoteList.ID, deletedTask.UID)
-				if err != nil {
-					return nil, fmt.Errorf("failed to delete task %s: %w", deletedTask.UID, err)
-				}
+			result.ConflictsResolved++
+			result.Conflicts = append(result.Conflicts, conflict)
+		} else if isLocallyModified {
+			// Only local modified - will be pushed in push phase, don't update local
+			// Do nothing here, let push phase handle it
+		} else {
+			// Remote modified or neither modified - update local with remote
+			err := sm.local.UpdateSyncedTask(remoteList.ID, remoteTask)
+			if err != nil {
+				return fmt.Errorf("failed to update task %s: %w", remoteTask.UID, err)
 			}
-			// If locally modified, keep it (will be pushed in push phase)
+			result.PulledTasks++
+			listResult.Pulled++
 		}
 	}
+	return nil
+}
 
-	return result, nil
+// pullDeletion deletes locally a task deleted on the remote, unless it was
+// modified locally, in which case it is kept and pushed in the push phase
+func (sm *SyncManager) pullDeletion(listID, taskUID string) error {
+	isLocallyModified, err := sm.local.IsLocallyModified(taskUID)
+	if err != nil {
+		return err
+	}
+	if isLocallyModified {
+		return nil
+	}
+	if err := sm.local.DeleteSyncedTask(listID, taskUID); err != nil {
+		return fmt.Errorf("failed to delete task %s: %w", taskUID, err)
+	}
+	return nil
 }
 
 // pushResult contains statistics from the push phase
 type pushResult struct {
 	PushedTasks int
+	pushedLists []string // List of each pushed operation
+	failures    []pushFailure
+	conflicts   []pushFailure             // Writes the remote refused, the task having changed there
+	exhausted   []pushFailure             // Operations past the retry limit, not pushed
+	sent        map[string][]backend.Task // Created and updated tasks as sent, by list
+	rewrites    []RemoteRewrite
+	stranded    []StrandedList
+}
+
+// pushFailure is an operation that failed to push and stays queued
+type pushFailure struct {
+	listID string
+	err    error
 }
 
 // push sends local changes to remote backend
 func (sm *SyncManager) push() (*pushResult, error) {
-	result := &pushResult{}
+	return sm.pushLists(nil)
+}
+
+// pushLists sends the local changes of the given lists to the remote backend,
+// or of every list when listIDs is empty
+func (sm *SyncManager) pushLists(listIDs []string) (*pushResult, error) {
+	result := &pushResult{sent: make(map[string][]backend.Task)}
 
 	// Get pending sync operations
 	operations, err := sm.local.GetPendingSyncOperations()
@@ -255,90 +792,175 @@ func (sm *SyncManager) push() (*pushResult, error) {
 		return nil, fmt.Errorf("failed to get pending operations: %w", err)
 	}
 
-	// Process each operation
+	// Lists deleted on the remote by earlier pushes
+	strandedEarlier := make(map[string]int)
+
+	var pushable []sqlite.SyncOperation
 	for _, op := range operations {
-		// Skip if too many retries
-		if op.RetryCount >= 5 {
+		if len(listIDs) > 0 && !slices.Contains(listIDs, op.ListID) {
 			continue
 		}
-
-		var pushErr error
-
-		switch op.Operation {
-		case "create":
-			pushErr = sm.pushCreate(op)
-		case "update":
-			pushErr = sm.pushUpdate(op)
-		case "delete":
-			pushErr = sm.pushDelete(op)
-		default:
-			pushErr = fmt.Errorf("unknown operation: %s", op.Operation)
+		if op.Stranded {
+			strandedEarlier[op.ListID]++
+			continue
 		}
+		// Operations that failed too often wait for 'sync retry' or 'sync drop'
+		if op.RetryCount >= sm.retryLimit() {
+			result.exhausted = append(result.exhausted, pushFailure{listID: op.ListID, err: fmt.Errorf(
+				"%s of task %s failed %d times and is no longer retried (last error: %s); run 'gosynctasks sync retry %s' to push it again or 'gosynctasks sync drop %s' to discard it",
+				op.Operation, op.TaskUID, op.RetryCount, op.LastError, op.TaskUID, op.TaskUID)})
+			continue
+		}
+		pushable = append(pushable, op)
+	}
 
-		if pushErr != nil {
-			// Increment retry count
-			db, err := sm.local.GetDB()
-			if err != nil {
-				return nil, err
-			}
-
-			_, err = db.Exec(`
-				UPDATE sync_queue
-				SET retry_count = retry_count + 1, last_error = ?
-				WHERE id = ?
-			`, pushErr.Error(), op.ID)
-			if err != nil {
-				return nil, fmt.Errorf("failed to update retry count: %w", err)
-			}
-
-			// Apply exponential backoff
-			backoffSeconds := 1 << op.RetryCount // 2^retryCount
-			if backoffSeconds > 300 {
-				backoffSeconds = 300 // Max 5 minutes
-			}
-			time.Sleep(time.Duration(backoffSeconds) * time.Second)
-		} else {
-			// Success - pushCreate already handles clearing flags for create operations
-			// Only clear for update/delete operations
-			if op.Operation != "create" {
-				err := sm.local.ClearSyncFlagsAndQueue(op.TaskUID)
-				if err != nil {
-					return nil, fmt.Errorf("failed to clear sync flags and queue: %w", err)
+	// The operations of a task are sent in order, those of different tasks
+	// concurrently when the remote allows it
+	run := &pushRun{result: result}
+	for _, wave := range sm.pushWaves(pushable) {
+		forEachConcurrently(wave, sm.pushWorkers(), func(taskOps []sqlite.SyncOperation) {
+			for _, op := range taskOps {
+				if !sm.pushOperation(op, run) {
+					break
 				}
 			}
-
-			result.PushedTasks++
+		})
+		if run.err != nil {
+			return nil, run.err
 		}
 	}
 
+	result.stranded, err = sm.strandLists(run.gone, strandedEarlier)
+	if err != nil {
+		return nil, err
+	}
+	result.rewrites = sm.readBack(result.sent)
 	return result, nil
 }
 
-// pushCreate pushes a create operation to remote
-func (sm *SyncManager) pushCreate(op sqlite.SyncOperation) error {
-	// Get task from local
-	tasks, err := sm.local.GetTasks(op.ListID, nil)
-	if err != nil {
-		return err
+// pushRun is the state of a push shared by the operations sent at once
+type pushRun struct {
+	mu     sync.Mutex
+	result *pushResult
+	gone   []string // Lists found deleted on the remote
+	err    error    // First error of the local store, ending the push
+}
+
+// record updates the push result under the run's lock
+func (run *pushRun) record(update func(result *pushResult)) {
+	run.mu.Lock()
+	defer run.mu.Unlock()
+	update(run.result)
+}
+
+// fail ends the push with err, unless it already failed
+func (run *pushRun) fail(err error) {
+	run.mu.Lock()
+	defer run.mu.Unlock()
+	if run.err == nil {
+		run.err = err
 	}
+}
 
-	var task *backend.Task
-	for i := range tasks {
-		if tasks[i].UID == op.TaskUID {
-			task = &tasks[i]
-			break
+// skips reports whether op is not to be sent: the push failed, or op writes
+// to a list found deleted on the remote
+func (run *pushRun) skips(op sqlite.SyncOperation) bool {
+	run.mu.Lock()
+	defer run.mu.Unlock()
+	return run.err != nil || (op.Operation != "delete" && slices.Contains(run.gone, op.ListID))
+}
+
+// pushOperation sends a queued operation to the remote and records the
+// outcome in run. It returns false when the operation stays queued, so that
+// the next operations of its task wait for it.
+func (sm *SyncManager) pushOperation(op sqlite.SyncOperation, run *pushRun) bool {
+	if run.skips(op) {
+		return false
+	}
+
+	var pushErr error
+	var sentTask *backend.Task
+
+	switch op.Operation {
+	case "create":
+		sentTask, pushErr = sm.pushCreate(op)
+	case "update":
+		sentTask, pushErr = sm.pushUpdate(op)
+	case "delete":
+		pushErr = sm.pushDelete(op)
+	default:
+		pushErr = fmt.Errorf("unknown operation: %s", op.Operation)
+	}
+
+	var backendErr *backend.BackendError
+	if pushErr != nil && sm.listGone(op, pushErr) {
+		// Retrying cannot help; the list's changes wait for a rescue
+		run.record(func(*pushResult) {
+			if !slices.Contains(run.gone, op.ListID) {
+				run.gone = append(run.gone, op.ListID)
+			}
+		})
+		return false
+	} else if errors.As(pushErr, &backendErr) && backendErr.IsConflict() {
+		// Retrying cannot help either: the operation stays queued, and the
+		// list is pulled on the next sync, resolving the conflict
+		run.record(func(result *pushResult) {
+			result.conflicts = append(result.conflicts, pushFailure{listID: op.ListID, err: pushErr})
+		})
+		if err := sm.local.SetListCTag(op.ListID, ""); err != nil {
+			run.fail(fmt.Errorf("failed to update list CTag: %w", err))
 		}
+		return false
+	} else if pushErr != nil {
+		run.record(func(result *pushResult) {
+			result.failures = append(result.failures, pushFailure{listID: op.ListID, err: pushErr})
+		})
+
+		// Increment retry count; the operation waits out an exponential
+		// backoff, skipped by the pushes until then
+		if err := sm.local.RecordSyncFailure(op.ID, pushErr.Error(), time.Now().Add(retryBackoff(op.RetryCount))); err != nil {
+			run.fail(fmt.Errorf("failed to update retry count: %w", err))
+		}
+		return false
 	}
 
-	if task == nil {
-		// Task was deleted locally, remove from queue
-		return nil
+	// Success - pushCreate already handles clearing flags for create operations
+	// Only clear for update/delete operations
+	if op.Operation != "create" {
+		if err := sm.local.ClearSyncFlagsAndQueue(op.TaskUID); err != nil {
+			run.fail(fmt.Errorf("failed to clear sync flags and queue: %w", err))
+			return false
+		}
+	}
+
+	run.record(func(result *pushResult) {
+		result.PushedTasks++
+		result.pushedLists = append(result.pushedLists, op.ListID)
+		if sentTask != nil {
+			result.sent[op.ListID] = append(result.sent[op.ListID], *sentTask)
+		}
+	})
+	return true
+}
+
+// pushCreate pushes a create operation to remote and returns the task as
+// sent, with its remote UID, or nil when there was nothing to send
+func (sm *SyncManager) pushCreate(op sqlite.SyncOperation) (*backend.Task, error) {
+	// Get task from local
+	task, err := sm.local.GetTask(op.ListID, op.TaskUID)
+	if err != nil {
+		if backendErr, ok := err.(*backend.BackendError); ok && backendErr.IsNotFound() {
+			// Task was deleted locally, remove from queue
+			return nil, nil
+		}
+		return nil, err
 	}
 
 	// Add to remote and get the remote-assigned UID
+	task.Status = sm.toRemoteStatus(task.Status)
 	remoteUID, err := sm.remote.AddTask(op.ListID, *task)
 	if err != nil {
-		return fmt.Errorf("failed to create task on remote: %w", err)
+		return nil, fmt.Errorf("failed to create task on remote: %w", err)
 	}
 
 	// If the remote backend assigned a different UID, update local task
@@ -347,62 +969,59 @@ func (sm *SyncManager) pushCreate(op sqlite.SyncOperation) error {
 		// This is critical for Todoist and other backends that generate their own IDs
 		err = sm.updateLocalTaskUID(op.ListID, task.UID, remoteUID)
 		if err != nil {
-			return fmt.Errorf("failed to update local task UID: %w", err)
+			return nil, fmt.Errorf("failed to update local task UID: %w", err)
 		}
 
 		// Clear sync flags and queue using the NEW UID (after update)
 		err = sm.local.ClearSyncFlagsAndQueue(remoteUID)
 		if err != nil {
-			return fmt.Errorf("failed to clear sync flags and queue: %w", err)
+			return nil, fmt.Errorf("failed to clear sync flags and queue: %w", err)
 		}
 	} else {
 		// UID didn't change, clear flags using existing UID
 		err = sm.local.ClearSyncFlagsAndQueue(task.UID)
 		if err != nil {
-			return fmt.Errorf("failed to clear sync flags and queue: %w", err)
+			return nil, fmt.Errorf("failed to clear sync flags and queue: %w", err)
 		}
 	}
 
-	return nil
+	task.UID = remoteUID
+	return task, nil
 }
 
-// pushUpdate pushes an update operation to remote
-func (sm *SyncManager) pushUpdate(op sqlite.SyncOperation) error {
+// pushUpdate pushes an update operation to remote and returns the task as
+// sent, or nil when there was nothing to send
+func (sm *SyncManager) pushUpdate(op sqlite.SyncOperation) (*backend.Task, error) {
 	utils.Debugf("[SYNC] pushUpdate: task=%s, list=%s", op.TaskUID, op.ListID)
 
 	// Get task from local
-	tasks, err := sm.local.GetTasks(op.ListID, nil)
+	task, err := sm.local.GetTask(op.ListID, op.TaskUID)
 	if err != nil {
-		utils.Debugf("[SYNC] ERROR getting tasks: %v", err)
-		return err
-	}
-
-	var task *backend.Task
-	for i := range tasks {
-		if tasks[i].UID == op.TaskUID {
-			task = &tasks[i]
-			break
+		if backendErr, ok := err.(*backend.BackendError); ok && backendErr.IsNotFound() {
+			// backend.Task was deleted locally, remove from queue
+			utils.Debugf("[SYNC] Task %s not found in local (deleted?), skipping update", op.TaskUID)
+			return nil, nil
 		}
-	}
-
-	if task == nil {
-		// backend.Task was deleted locally, remove from queue
-		utils.Debugf("[SYNC] Task %s not found in local (deleted?), skipping update", op.TaskUID)
-		return nil
+		utils.Debugf("[SYNC] ERROR getting task: %v", err)
+		return nil, err
 	}
 
 	utils.Debugf("[SYNC] Found task: %s (status: %s)", task.Summary, task.Status)
 
-	// Update on remote
+	// Update on remote, replacing only the version last synced
 	utils.Debugf("[SYNC] Calling remote.UpdateTask...")
+	task.Status = sm.toRemoteStatus(task.Status)
+	if task.ETag, err = sm.local.GetRemoteETag(task.UID); err != nil {
+		return nil, err
+	}
 	err = sm.remote.UpdateTask(op.ListID, *task)
 	if err != nil {
 		utils.Debugf("[SYNC] ERROR updating remote: %v", err)
-		return fmt.Errorf("failed to update task on remote: %w", err)
+		return nil, fmt.Errorf("failed to update task on remote: %w", err)
 	}
 
 	utils.Debugf("[SYNC] ✅ Successfully updated task on remote")
-	return nil
+	return task, nil
 }
 
 // pushDelete pushes a delete operation to remote
@@ -419,59 +1038,44 @@ func (sm *SyncManager) pushDelete(op sqlite.SyncOperation) error {
 	return nil
 }
 
-// isTaskLocallyModified checks if a task is locally modified
-func (sm *SyncManager) isTaskLocallyModified(taskUID string) (bool, error) {
-	db, err := sm.local.GetDB()
-	if err != nil {
-		return false, err
+// isTaskRemoteModified checks if a remote task has been modified since last sync.
+// The ETag of the remote version is compared first when the remote reports
+// one and the last sync recorded one, as it changes with any change of the
+// resource, including those of servers that don't update LAST-MODIFIED.
+// Otherwise, when both versions carry a SEQUENCE, only a higher remote one
+// counts: other clients rewrite LAST-MODIFIED without changing the task, and
+// an equal or lower SEQUENCE is a version we already have.
+func (sm *SyncManager) isTaskRemoteModified(localTask, remoteTask backend.Task) (bool, error) {
+	if remoteTask.ETag != "" {
+		etag, err := sm.local.GetRemoteETag(remoteTask.UID)
+		if err != nil {
+			return false, err
+		}
+		if etag != "" {
+			return etag != remoteTask.ETag, nil
+		}
 	}
 
-	var locallyModified int
-	err = db.QueryRow(`
-		SELECT COALESCE(sm.locally_modified, 0)
-		FROM sync_metadata sm
-		INNER JOIN tasks t ON sm.task_internal_id = t.internal_id
-		WHERE t.uid = ? AND t.backend_name = ?
-	`, taskUID, sm.getBackendName()).Scan(&locallyModified)
-	if err != nil {
-		// If no sync metadata, treat as not modified
-		return false, nil
+	if localTask.Sequence > 0 && remoteTask.Sequence > 0 {
+		return remoteTask.Sequence > localTask.Sequence, nil
 	}
 
-	return locallyModified == 1, nil
-}
-
-// isTaskRemoteModified checks if a remote task has been modified since last sync
-func (sm *SyncManager) isTaskRemoteModified(remoteTask backend.Task) (bool, error) {
-	db, err := sm.local.GetDB()
+	lastRemoteModified, err := sm.local.GetRemoteModifiedAt(remoteTask.UID)
 	if err != nil {
 		return false, err
 	}
 
-	var remoteModifiedAt sql.NullInt64
-	err = db.QueryRow(`
-		SELECT sm.remote_modified_at
-		FROM sync_metadata sm
-		INNER JOIN tasks t ON sm.task_internal_id = t.internal_id
-		WHERE t.uid = ? AND t.backend_name = ?
-	`, remoteTask.UID, sm.getBackendName()).Scan(&remoteModifiedAt)
-	if err != nil {
-		// If no sync metadata exists, treat as modified (new from our perspective)
-		return true, nil
-	}
-
-	// If we don't have a remote modified timestamp, treat as modified
-	if !remoteModifiedAt.Valid {
+	// Without a stored timestamp the task is new from our perspective
+	if lastRemoteModified == nil {
 		return true, nil
 	}
 
 	// Compare remote task's Modified timestamp with stored remote_modified_at
 	// Truncate to second precision since we store timestamps as Unix seconds
-	lastRemoteModified := time.Unix(remoteModifiedAt.Int64, 0)
 	currentRemoteModified := time.Unix(remoteTask.Modified.Unix(), 0)
 
 	// If remote task's Modified is newer than our stored timestamp, it's been modified
-	if !remoteTask.Modified.IsZero() && currentRemoteModified.After(lastRemoteModified) {
+	if !remoteTask.Modified.IsZero() && currentRemoteModified.After(*lastRemoteModified) {
 		return true, nil
 	}
 
@@ -479,8 +1083,22 @@ func (sm *SyncManager) isTaskRemoteModified(remoteTask backend.Task) (bool, erro
 }
 
 // resolveConflict resolves a conflict between local and remote versions
-func (sm *SyncManager) resolveConflict(listID string, localTask, remoteTask backend.Task) error {
-	switch sm.strategy {
+func (sm *SyncManager) resolveConflict(conflict *Conflict) error {
+	strategy := sm.strategy
+	if strategy == Prompt {
+		strategy = sm.conflictFallback()
+		if sm.prompter != nil {
+			chosen, err := sm.prompter(*conflict)
+			if err != nil {
+				return err
+			}
+			strategy = chosen
+		}
+	}
+	conflict.Resolution = strategy
+
+	listID, localTask, remoteTask := conflict.ListID, conflict.Local, conflict.Remote
+	switch strategy {
 	case ServerWins:
 		return sm.resolveServerWins(listID, localTask, remoteTask)
 	case LocalWins:
@@ -490,14 +1108,24 @@ func (sm *SyncManager) resolveConflict(listID string, localTask, remoteTask back
 	case KeepBoth:
 		return sm.resolveKeepBoth(listID, localTask, remoteTask)
 	default:
-		return fmt.Errorf("unknown conflict resolution strategy: %s", sm.strategy)
+		return fmt.Errorf("unknown conflict resolution strategy: %s", strategy)
 	}
 }
 
+// acceptClosedRemote settles a task closed on both sides, differing at most
+// in its closed status and completion time, by taking the remote version
+// without going through the conflict strategy: whichever side it picks, the
+// task stays closed, and KeepBoth would only duplicate it
+func (sm *SyncManager) acceptClosedRemote(listID string, localTask, remoteTask backend.Task) error {
+	utils.Debugf("[SYNC] task %s closed on both sides (local %s, remote %s), taking the remote version",
+		remoteTask.UID, localTask.Status, remoteTask.Status)
+	return sm.resolveServerWins(listID, localTask, remoteTask)
+}
+
 // resolveServerWins discards local changes and uses server version
 func (sm *SyncManager) resolveServerWins(listID string, localTask, remoteTask backend.Task) error {
 	// Update local with remote version
-	err := sm.updateTaskLocally(listID, remoteTask)
+	err := sm.local.UpdateSyncedTask(listID, remoteTask)
 	if err != nil {
 		return err
 	}
@@ -511,9 +1139,21 @@ func (sm *SyncManager) resolveServerWins(listID string, localTask, remoteTask ba
 func (sm *SyncManager) resolveLocalWins(listID string, localTask, remoteTask backend.Task) error {
 	// Keep local version, mark for push
 	// Local task already has locally_modified=1, so it will be pushed
-	// Just update sync metadata with remote info
+	// Push on top of the remote's SEQUENCE, which must never go down
+	if remoteTask.Sequence > localTask.Sequence {
+		localTask.Sequence = remoteTask.Sequence
+		if err := sm.local.UpdateSyncedTask(listID, localTask); err != nil {
+			return err
+		}
+		if err := sm.local.MarkLocallyModified(localTask.UID); err != nil {
+			return err
+		}
+	}
+
+	// Just update sync metadata with remote info, the push replacing the
+	// remote version
 	if !remoteTask.Modified.IsZero() {
-		return sm.local.UpdateSyncMetadata(localTask.UID, listID, "", remoteTask.Modified)
+		return sm.local.UpdateSyncMetadata(localTask.UID, listID, remoteTask.ETag, remoteTask.Modified)
 	}
 	return nil
 }
@@ -551,7 +1191,7 @@ func (sm *SyncManager) resolveMerge(listID string, localTask, remoteTask backend
 	}
 
 	// Update locally with merged version
-	err := sm.updateTaskLocally(listID, mergedTask)
+	err := sm.local.UpdateSyncedTask(listID, mergedTask)
 	if err != nil {
 		return err
 	}
@@ -563,7 +1203,7 @@ func (sm *SyncManager) resolveMerge(listID string, localTask, remoteTask backend
 // resolveKeepBoth creates a copy of the local version
 func (sm *SyncManager) resolveKeepBoth(listID string, localTask, remoteTask backend.Task) error {
 	// Update local task with remote version
-	err := sm.updateTaskLocally(listID, remoteTask)
+	err := sm.local.UpdateSyncedTask(listID, remoteTask)
 	if err != nil {
 		return err
 	}
@@ -584,160 +1224,31 @@ func (sm *SyncManager) resolveKeepBoth(listID string, localTask, remoteTask back
 	return sm.local.ClearSyncFlagsAndQueue(remoteTask.UID)
 }
 
-// insertTaskLocally inserts a remote task into local storage
-func (sm *SyncManager) insertTaskLocally(listID string, task backend.Task) error {
-	db, err := sm.local.GetDB()
-	if err != nil {
-		return err
-	}
-
-	tx, err := db.Begin()
-	if err != nil {
-		return err
-	}
-	defer func() { _ = tx.Rollback() }()
-
-	// Insert task
-	result, err := tx.Exec(`
-		INSERT INTO tasks (
-			uid, backend_name, list_id, summary, description, status, priority,
-			created_at, modified_at, due_date, start_date, completed_at,
-			parent_uid, categories
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
-	`,
-		task.UID,
-		sm.getBackendName(),
-		listID,
-		task.Summary,
-		sqlite.NullString(task.Description),
-		task.Status,
-		task.Priority,
-		sqlite.TimeValueToNullInt64(task.Created),
-		sqlite.TimeValueToNullInt64(task.Modified),
-		sqlite.TimeToNullInt64(task.DueDate),
-		sqlite.TimeToNullInt64(task.StartDate),
-		sqlite.TimeToNullInt64(task.Completed),
-		sqlite.NullString(task.ParentUID),
-		sqlite.NullString(strings.Join(task.Categories, ",")),
-	)
-	if err != nil {
-		return err
-	}
-
-	// Get the internal_id that was just created
-	internalID, err := result.LastInsertId()
-	if err != nil {
-		return err
-	}
-
-	// Insert sync metadata (not locally modified since it came from server)
-	now := time.Now().Unix()
-	remoteModifiedAt := int64(0)
-	if !task.Modified.IsZero() {
-		remoteModifiedAt = task.Modified.Unix()
-	}
-
-	_, err = tx.Exec(`
-		INSERT INTO sync_metadata (
-			task_internal_id, backend_name, list_id, last_synced_at, remote_modified_at,
-			locally_modified, locally_deleted
-		) VALUES (?, ?, ?, ?, ?, 0, 0)
-	`, internalID, sm.getBackendName(), listID, now, remoteModifiedAt)
-	if err != nil {
-		return err
-	}
-
-	return tx.Commit()
-}
-
-// updateTaskLocally updates a local task with remote data
-func (sm *SyncManager) updateTaskLocally(listID string, task backend.Task) error {
-	db, err := sm.local.GetDB()
-	if err != nil {
-		return err
-	}
-
-	tx, err := db.Begin()
-	if err != nil {
-		return err
-	}
-	defer func() { _ = tx.Rollback() }()
-
-	// Get internal_id for this task
-	var internalID int64
-	err = tx.QueryRow("SELECT internal_id FROM tasks WHERE backend_name = ? AND uid = ? AND list_id = ?",
-		sm.getBackendName(), task.UID, listID).Scan(&internalID)
-	if err != nil {
-		return err
-	}
-
-	// Update task
-	_, err = tx.Exec(`
-		UPDATE tasks
-		SET summary = ?, description = ?, status = ?, priority = ?,
-		    modified_at = ?, due_date = ?, start_date = ?, completed_at = ?,
-		    parent_uid = ?, categories = ?
-		WHERE uid = ? AND backend_name = ? AND list_id = ?
-	`,
-		task.Summary,
-		sqlite.NullString(task.Description),
-		task.Status,
-		task.Priority,
-		sqlite.TimeValueToNullInt64(task.Modified),
-		sqlite.TimeToNullInt64(task.DueDate),
-		sqlite.TimeToNullInt64(task.StartDate),
-		sqlite.TimeToNullInt64(task.Completed),
-		sqlite.NullString(task.ParentUID),
-		sqlite.NullString(strings.Join(task.Categories, ",")),
-		task.UID,
-		sm.getBackendName(),
-		listID,
-	)
-	if err != nil {
-		return err
-	}
-
-	// Update sync metadata
-	now := time.Now().Unix()
-	remoteModifiedAt := int64(0)
-	if !task.Modified.IsZero() {
-		remoteModifiedAt = task.Modified.Unix()
-	}
-
-	_, err = tx.Exec(`
-		UPDATE sync_metadata
-		SET last_synced_at = ?, remote_modified_at = ?, locally_modified = 0, locally_deleted = 0
-		WHERE task_internal_id = ? AND backend_name = ?
-	`, now, remoteModifiedAt, internalID, sm.getBackendName())
-	if err != nil {
-		return err
+// AdoptList starts caching a remote list the cache does not know yet: it
+// records the list and pulls its tasks, as a sync does for new lists. A list
+// already cached is pulled as in a sync.
+func (sm *SyncManager) AdoptList(remoteList backend.TaskList) (*ListSyncResult, error) {
+	result := &pullResult{}
+	listResult := &ListSyncResult{ListID: remoteList.ID, Name: remoteList.Name}
+	if err := sm.pullList(remoteList, result, listResult); err != nil {
+		return nil, fmt.Errorf("failed to adopt list %s: %w", remoteList.Name, err)
 	}
-
-	return tx.Commit()
-}
-
-// deleteTaskLocally deletes a task from local storage
-func (sm *SyncManager) deleteTaskLocally(listID string, taskUID string) error {
-	db, err := sm.local.GetDB()
-	if err != nil {
-		return err
+	if listResult.Err != nil {
+		return listResult, fmt.Errorf("failed to adopt list %s: %w", remoteList.Name, listResult.Err)
 	}
-
-	// Delete task (cascade will delete sync_metadata via internal_id foreign key)
-	_, err = db.Exec("DELETE FROM tasks WHERE uid = ? AND backend_name = ? AND list_id = ?", taskUID, sm.getBackendName(), listID)
-	return err
+	return listResult, nil
 }
 
 // FullSync performs a complete synchronization, ignoring CTags
 func (sm *SyncManager) FullSync() (*SyncResult, error) {
-	// Clear all CTags to force full sync
-	db, err := sm.local.GetDB()
-	if err != nil {
-		return nil, err
+	if sm.beforeFullSync != nil {
+		if err := sm.beforeFullSync(); err != nil {
+			return nil, err
+		}
 	}
 
-	_, err = db.Exec("UPDATE list_sync_metadata SET last_ctag = ''")
-	if err != nil {
+	// Clear all CTags and sync tokens to force full sync
+	if err := sm.local.ClearListCTags(); err != nil {
 		return nil, fmt.Errorf("failed to clear CTags: %w", err)
 	}
 
@@ -746,12 +1257,7 @@ func (sm *SyncManager) FullSync() (*SyncResult, error) {
 
 // GetSyncStats returns current sync statistics
 func (sm *SyncManager) GetSyncStats() (*SyncStats, error) {
-	db, err := sm.local.GetDB()
-	if err != nil {
-		return nil, err
-	}
-
-	stats, err := db.GetStats()
+	stats, err := sm.local.GetStats()
 	if err != nil {
 		return nil, err
 	}
@@ -772,53 +1278,17 @@ type SyncStats struct {
 	LocallyModified   int
 }
 
-// sortTasksByHierarchy sorts tasks so parent tasks come before child tasks.
-// This is critical for respecting foreign key constraints during sync.
-func sortTasksByHierarchy(tasks []backend.Task) []backend.Task {
-	// Build parent-child relationships
-	childrenMap := make(map[string][]int) // parentUID -> child indexes
-	rootIndexes := []int{}                // tasks with no parent
-
-	for i, task := range tasks {
-		if task.ParentUID == "" {
-			rootIndexes = append(rootIndexes, i)
-		} else {
-			childrenMap[task.ParentUID] = append(childrenMap[task.ParentUID], i)
+// firstOccurrences returns tasks without the later copies of duplicated UIDs
+func firstOccurrences(tasks []backend.Task) []backend.Task {
+	seen := make(map[string]bool, len(tasks))
+	unique := make([]backend.Task, 0, len(tasks))
+	for _, task := range tasks {
+		if !seen[task.UID] {
+			seen[task.UID] = true
+			unique = append(unique, task)
 		}
 	}
-
-	// Traverse hierarchy depth-first, collecting tasks in order
-	sorted := []backend.Task{}
-	visited := make(map[int]bool)
-
-	var visit func(index int)
-	visit = func(index int) {
-		if visited[index] {
-			return
-		}
-		visited[index] = true
-		sorted = append(sorted, tasks[index])
-
-		// Visit children
-		taskUID := tasks[index].UID
-		for _, childIndex := range childrenMap[taskUID] {
-			visit(childIndex)
-		}
-	}
-
-	// Visit all root tasks (and their descendants)
-	for _, rootIndex := range rootIndexes {
-		visit(rootIndex)
-	}
-
-	// Add any orphaned tasks (tasks with parent_uid pointing to non-existent parents)
-	for i := range tasks {
-		if !visited[i] {
-			sorted = append(sorted, tasks[i])
-		}
-	}
-
-	return sorted
+	return unique
 }
 
 // PushOnly executes only the push phase of sync (no pull)
@@ -832,7 +1302,24 @@ func (sm *SyncManager) PushOnly() (*SyncResult, error) {
 	if err != nil {
 		result.Errors = append(result.Errors, fmt.Errorf("push phase failed: %w", err))
 	} else {
-		result.PushedTasks = pushResult.PushedTasks
+		result.addPushResults(pushResult)
+	}
+
+	result.Duration = time.Since(startTime)
+	return result, nil
+}
+
+// PushListsOnly pushes the pending local changes of the given lists, leaving
+// the changes of other lists queued
+func (sm *SyncManager) PushListsOnly(listIDs []string) (*SyncResult, error) {
+	startTime := time.Now()
+	result := &SyncResult{}
+
+	pushResult, err := sm.pushLists(listIDs)
+	if err != nil {
+		result.Errors = append(result.Errors, fmt.Errorf("push phase failed: %w", err))
+	} else {
+		result.addPushResults(pushResult)
 	}
 
 	result.Duration = time.Since(startTime)
@@ -842,31 +1329,45 @@ func (sm *SyncManager) PushOnly() (*SyncResult, error) {
 // updateLocalTaskUID updates a task's UID in the local cache
 // This is needed when remote backends (like Todoist) assign their own IDs
 func (sm *SyncManager) updateLocalTaskUID(listID string, oldUID string, newUID string) error {
-	db, err := sm.local.GetDB()
-	if err != nil {
-		return err
+	if err := sm.local.UpdateTaskUID(listID, oldUID, newUID); err != nil {
+		return fmt.Errorf("failed to update task UID: %w", err)
 	}
+	return nil
+}
 
-	// Simply update the UID from "pending-{internal_id}" to the real remote UID
-	// The internal_id remains unchanged, so all foreign keys stay valid
-	_, err = db.Exec(`
-		UPDATE tasks
-		SET uid = ?
-		WHERE backend_name = ? AND uid = ? AND list_id = ?
-	`, newUID, sm.local.Config.Name, oldUID, listID)
-	if err != nil {
-		return fmt.Errorf("failed to update task UID: %w", err)
+// cacheStatuses maps display status names to the CalDAV statuses the cache
+// stores
+var cacheStatuses = map[string]string{
+	"TODO":       "NEEDS-ACTION",
+	"DONE":       "COMPLETED",
+	"PROCESSING": "IN-PROCESS",
+	"CANCELLED":  "CANCELLED",
+}
+
+// toLocalStatus converts a remote status to the cache's through its display
+// name, e.g. the git backend's TODO to NEEDS-ACTION. Unknown statuses are
+// kept unchanged.
+func (sm *SyncManager) toLocalStatus(status string) string {
+	if local, ok := cacheStatuses[strings.ToUpper(sm.remote.StatusToDisplayName(status))]; ok {
+		return local
 	}
+	return status
+}
 
-	return nil
+// toRemoteStatus converts a cached status to the remote's; statuses the
+// remote rejects are kept unchanged
+func (sm *SyncManager) toRemoteStatus(status string) string {
+	for display, local := range cacheStatuses {
+		if local == status {
+			if remote, err := sm.remote.ParseStatusFlag(display); err == nil {
+				return remote
+			}
+		}
+	}
+	return status
 }
 
 // GetRemote returns the remote backend.TaskManager
 func (sm *SyncManager) GetRemote() backend.TaskManager {
 	return sm.remote
 }
-
-// getBackendName returns the backend name from the local cache backend
-func (sm *SyncManager) getBackendName() string {
-	return sm.local.Config.Name
-}
@@ -3,8 +3,11 @@ package sync
 import (
 	"gosynctasks/backend"
 	"gosynctasks/backend/sqlite"
+	"errors"
 	"fmt"
 	"path/filepath"
+	"slices"
+	"strings"
 	"testing"
 	"time"
 )
@@ -153,13 +156,180 @@ func TestPullUpdatedTasks(t *testing.T) {
 	}
 }
 
-// TestConflictResolutionServerWins tests server_wins strategy
-func TestConflictResolutionServerWins(t *testing.T) {
+// TestPullRenameRecordsSummaryAlias tests that remote renames are kept as former names
+func TestPullRenameRecordsSummaryAlias(t *testing.T) {
+	sm, local, remote, cleanup := createTestSyncManager(t, ServerWins)
+	defer cleanup()
+
+	listID, _ := remote.CreateTaskList("Test List", "", "")
+	now := time.Now()
+	remote.AddTask(listID, backend.Task{
+		UID:      "task-1",
+		Summary:  "Release v2",
+		Status:   "NEEDS-ACTION",
+		Created:  now,
+		Modified: now,
+	})
+
+	if _, err := sm.Sync(); err != nil {
+		t.Fatalf("Initial sync failed: %v", err)
+	}
+
+	// Rename on remote and change CTag to trigger pull
+	remote.Tasks[listID][0].Summary = "Ship 2.0"
+	remote.Tasks[listID][0].Modified = now.Add(time.Hour)
+	remote.Lists[0].CTags = "ctag-renamed"
+
+	if _, err := sm.Sync(); err != nil {
+		t.Fatalf("Second sync failed: %v", err)
+	}
+
+	history, err := local.GetSummaryHistory("task-1")
+	if err != nil {
+		t.Fatalf("GetSummaryHistory failed: %v", err)
+	}
+	if len(history) != 1 || history[0].Summary != "Release v2" {
+		t.Errorf("Expected former name 'Release v2', got %v", history)
+	}
+
+	tasks, err := local.FindTasksBySummary(listID, "Release v2")
+	if err != nil {
+		t.Fatalf("FindTasksBySummary failed: %v", err)
+	}
+	if len(tasks) != 1 || tasks[0].Summary != "Ship 2.0" {
+		t.Errorf("Expected renamed task via alias, got %v", tasks)
+	}
+}
+
+// TestPullUpdatesListPath tests that renaming a parent list updates the stored
+// path of its children, even when the child's tasks are unchanged
+func TestPullUpdatesListPath(t *testing.T) {
 	sm, local, remote, cleanup := createTestSyncManager(t, ServerWins)
 	defer cleanup()
 
+	listID, _ := remote.CreateTaskList("Acme", "", "")
+	remote.Lists[0].Path = "Clients/Acme"
+	remote.Lists[0].CTags = "ctag-1"
+
+	if _, err := sm.Sync(); err != nil {
+		t.Fatalf("Initial sync failed: %v", err)
+	}
+
+	remote.Lists[0].Path = "Customers/Acme"
+
+	if _, err := sm.Sync(); err != nil {
+		t.Fatalf("Second sync failed: %v", err)
+	}
+
+	lists, err := local.GetTaskLists()
+	if err != nil {
+		t.Fatalf("GetTaskLists failed: %v", err)
+	}
+	if len(lists) != 1 || lists[0].ID != listID {
+		t.Fatalf("Expected one local list, got %v", lists)
+	}
+	if lists[0].Path != "Customers/Acme" {
+		t.Errorf("Expected path 'Customers/Acme', got %q", lists[0].Path)
+	}
+}
+
+// TestPullPreservesCompletionTimestamp tests that a completion time survives repeated pulls,
+// including when the remote stops reporting it
+func TestPullPreservesCompletionTimestamp(t *testing.T) {
+	sm, local, remote, cleanup := createTestSyncManager(t, ServerWins)
+	defer cleanup()
+
+	listID, _ := remote.CreateTaskList("Test List", "", "")
+	now := time.Now()
+	lastWeek := now.Add(-7 * 24 * time.Hour).Truncate(time.Second)
+	remote.AddTask(listID, backend.Task{
+		UID:       "task-1",
+		Summary:   "Finished last week",
+		Status:    "COMPLETED",
+		Created:   now.Add(-14 * 24 * time.Hour),
+		Modified:  lastWeek,
+		Completed: &lastWeek,
+	})
+
+	if _, err := sm.Sync(); err != nil {
+		t.Fatalf("First sync failed: %v", err)
+	}
+
+	// Second cycle: remote changes something else and omits the completion time
+	remote.Tasks[listID][0].Description = "Notes added later"
+	remote.Tasks[listID][0].Completed = nil
+	remote.Tasks[listID][0].Modified = now
+	remote.Lists[0].CTags = "ctag-second"
+
+	if _, err := sm.Sync(); err != nil {
+		t.Fatalf("Second sync failed: %v", err)
+	}
+
+	tasks, err := local.GetTasks(listID, nil)
+	if err != nil {
+		t.Fatalf("Failed to get local tasks: %v", err)
+	}
+	if len(tasks) != 1 {
+		t.Fatalf("Expected 1 local task, got %d", len(tasks))
+	}
+	if tasks[0].Description != "Notes added later" {
+		t.Errorf("Expected second pull to be applied, got description %q", tasks[0].Description)
+	}
+	if tasks[0].Completed == nil || !tasks[0].Completed.Equal(lastWeek) {
+		t.Errorf("Expected completion time %v to survive, got %v", lastWeek, tasks[0].Completed)
+	}
+}
+
+// TestPullSkipsPrunedTasks tests that pull does not bring back the tasks
+// pruned from the cache unless they change on the remote
+func TestPullSkipsPrunedTasks(t *testing.T) {
+	sm, local, remote, cleanup := createTestSyncManager(t, ServerWins)
+	defer cleanup()
+
+	listID, _ := remote.CreateTaskList("Test List", "", "")
+	lastYear := time.Now().AddDate(-1, 0, 0).Truncate(time.Second)
+	for _, uid := range []string{"done-1", "done-2", "done-3"} {
+		remote.AddTask(listID, backend.Task{UID: uid, Summary: uid, Status: "COMPLETED", Modified: lastYear, Completed: &lastYear})
+	}
+	if _, err := sm.Sync(); err != nil {
+		t.Fatalf("First sync failed: %v", err)
+	}
+
+	db, _ := local.GetDB()
+	if pruned, err := db.PruneCompleted(time.Now()); err != nil || pruned != 3 {
+		t.Fatalf("PruneCompleted() = %d, %v, want 3", pruned, err)
+	}
+
+	// One pruned task is reopened on the remote, another is deleted there
+	remote.Tasks[listID][0].Status = "NEEDS-ACTION"
+	remote.Tasks[listID][0].Modified = time.Now()
+	remote.Tasks[listID] = remote.Tasks[listID][:2]
+	remote.Lists[0].CTags = "ctag-second"
+
+	result, err := sm.Sync()
+	if err != nil {
+		t.Fatalf("Second sync failed: %v", err)
+	}
+	if result.PulledTasks != 1 {
+		t.Errorf("Expected 1 pulled task, got %d", result.PulledTasks)
+	}
+	tasks, _ := local.GetTasks(listID, nil)
+	if len(tasks) != 1 || tasks[0].UID != "done-1" {
+		t.Errorf("Expected only the reopened task back in the cache, got %v", tasks)
+	}
+	pruned, _ := local.PrunedTasks(listID)
+	if _, ok := pruned["done-2"]; !ok || len(pruned) != 1 {
+		t.Errorf("Expected only done-2 still recorded as pruned, got %v", pruned)
+	}
+}
+
+// TestConflictResolutionServerWins tests server_wins strategy
+func TestConflictResolutionServerWins(t *testing.T) {
+	sm, local, remote := newMemSyncManager(ServerWins)
+
 	// Create list
-	listID, _ := local.CreateTaskList("Test List", "", "")
+	listID := "list-1"
+	local.lists = append(local.lists, backend.TaskList{ID: listID, Name: "Test List"})
 	remote.Lists = append(remote.Lists, backend.TaskList{
 		ID:    listID,
 		Name:  "Test List",
@@ -177,7 +347,7 @@ func TestConflictResolutionServerWins(t *testing.T) {
 		Modified: now,
 	}
 
-	// Capture the actual UID assigned by SQLite
+	// Capture the UID assigned by the local store
 	taskUID, err := local.AddTask(listID, task)
 	if err != nil {
 		t.Fatalf("Failed to add task: %v", err)
@@ -224,11 +394,11 @@ func TestConflictResolutionServerWins(t *testing.T) {
 
 // TestConflictResolutionLocalWins tests local_wins strategy
 func TestConflictResolutionLocalWins(t *testing.T) {
-	sm, local, remote, cleanup := createTestSyncManager(t, LocalWins)
-	defer cleanup()
+	sm, local, remote := newMemSyncManager(LocalWins)
 
 	// Create list
-	listID, _ := local.CreateTaskList("Test List", "", "")
+	listID := "list-1"
+	local.lists = append(local.lists, backend.TaskList{ID: listID, Name: "Test List"})
 	remote.Lists = append(remote.Lists, backend.TaskList{
 		ID:    listID,
 		Name:  "Test List",
@@ -246,7 +416,7 @@ func TestConflictResolutionLocalWins(t *testing.T) {
 		Modified: now,
 	}
 
-	// Capture the actual UID assigned by SQLite
+	// Capture the UID assigned by the local store
 	taskUID, err := local.AddTask(listID, task)
 	if err != nil {
 		t.Fatalf("Failed to add task: %v", err)
@@ -286,11 +456,11 @@ func TestConflictResolutionLocalWins(t *testing.T) {
 
 // TestConflictResolutionKeepBoth tests keep_both strategy
 func TestConflictResolutionKeepBoth(t *testing.T) {
-	sm, local, remote, cleanup := createTestSyncManager(t, KeepBoth)
-	defer cleanup()
+	sm, local, remote := newMemSyncManager(KeepBoth)
 
 	// Create list
-	listID, _ := local.CreateTaskList("Test List", "", "")
+	listID := "list-1"
+	local.lists = append(local.lists, backend.TaskList{ID: listID, Name: "Test List"})
 	remote.Lists = append(remote.Lists, backend.TaskList{
 		ID:    listID,
 		Name:  "Test List",
@@ -308,7 +478,7 @@ func TestConflictResolutionKeepBoth(t *testing.T) {
 		Modified: now,
 	}
 
-	// Capture the actual UID assigned by SQLite
+	// Capture the UID assigned by the local store
 	taskUID, err := local.AddTask(listID, task)
 	if err != nil {
 		t.Fatalf("Failed to add task: %v", err)
@@ -355,6 +525,208 @@ func TestConflictResolutionKeepBoth(t *testing.T) {
 	}
 }
 
+// TestClosedOnBothSides tests that a task closed on both sides is settled
+// without going through the conflict strategy
+func TestClosedOnBothSides(t *testing.T) {
+	earlier := time.Date(2026, 7, 1, 9, 0, 0, 0, time.UTC)
+	later := earlier.Add(2 * time.Hour)
+
+	tests := []struct {
+		name          string
+		local         backend.Task
+		remote        backend.Task
+		wantConflicts int
+		wantTasks     int
+		wantStatus    string
+	}{
+		{
+			name:       "both completed",
+			local:      backend.Task{Summary: "Water plants", Status: "COMPLETED", Completed: &earlier},
+			remote:     backend.Task{Summary: "Water plants", Status: "COMPLETED", Completed: &later},
+			wantTasks:  1,
+			wantStatus: "COMPLETED",
+		},
+		{
+			name:       "completed and cancelled",
+			local:      backend.Task{Summary: "Water plants", Status: "COMPLETED", Completed: &earlier},
+			remote:     backend.Task{Summary: "Water plants", Status: "CANCELLED"},
+			wantTasks:  1,
+			wantStatus: "CANCELLED",
+		},
+		{
+			name:          "other fields changed too",
+			local:         backend.Task{Summary: "Water all plants", Status: "COMPLETED", Completed: &earlier},
+			remote:        backend.Task{Summary: "Water plants", Status: "COMPLETED", Completed: &later},
+			wantConflicts: 1,
+			wantTasks:     2,
+			wantStatus:    "COMPLETED",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sm, local, remote := newMemSyncManager(KeepBoth)
+			listID := "list-1"
+			local.lists = append(local.lists, backend.TaskList{ID: listID, Name: "Home"})
+			remote.Lists = append(remote.Lists, backend.TaskList{ID: listID, Name: "Home", CTags: "ctag-1"})
+			remote.Tasks[listID] = []backend.Task{}
+
+			now := time.Now()
+			uid, err := local.AddTask(listID, backend.Task{Summary: "Water plants", Status: "NEEDS-ACTION", Created: now, Modified: now})
+			if err != nil {
+				t.Fatalf("Failed to add task: %v", err)
+			}
+			tt.local.UID, tt.local.Modified = uid, now
+			local.UpdateTask(listID, tt.local)
+			tt.remote.UID, tt.remote.Modified = uid, now.Add(time.Minute)
+			remote.AddTask(listID, tt.remote)
+			remote.Lists[0].CTags = "ctag-2"
+
+			result, err := sm.Sync()
+			if err != nil {
+				t.Fatalf("Sync failed: %v", err)
+			}
+			if result.ConflictsFound != tt.wantConflicts {
+				t.Errorf("ConflictsFound = %d, want %d", result.ConflictsFound, tt.wantConflicts)
+			}
+
+			tasks, _ := local.GetTasks(listID, nil)
+			if len(tasks) != tt.wantTasks {
+				t.Fatalf("got %d tasks, want %d", len(tasks), tt.wantTasks)
+			}
+			got, _ := local.GetTask(listID, uid)
+			if got.Status != tt.wantStatus {
+				t.Errorf("status = %s, want %s", got.Status, tt.wantStatus)
+			}
+			if tt.wantConflicts > 0 {
+				return
+			}
+			if !sameDate(got.Completed, tt.remote.Completed) {
+				t.Errorf("completed = %v, want the remote's %v", got.Completed, tt.remote.Completed)
+			}
+			if pending, _ := local.IsLocallyModified(uid); pending {
+				t.Error("task still queued for push after taking the remote version")
+			}
+		})
+	}
+}
+
+// TestConflictResolutionMerge tests merge strategy
+func TestConflictResolutionMerge(t *testing.T) {
+	sm, local, remote := newMemSyncManager(Merge)
+
+	listID := "list-1"
+	local.lists = append(local.lists, backend.TaskList{ID: listID, Name: "Test List"})
+	remote.Lists = append(remote.Lists, backend.TaskList{ID: listID, Name: "Test List", CTags: "ctag-456"})
+	remote.Tasks[listID] = []backend.Task{}
+
+	now := time.Now()
+	taskUID, err := local.AddTask(listID, backend.Task{Summary: "Original", Status: "NEEDS-ACTION", Priority: 5, Created: now, Modified: now})
+	if err != nil {
+		t.Fatalf("Failed to add task: %v", err)
+	}
+	local.queue = nil
+
+	// Local adds a description and raises priority; remote renames and tags
+	localTask := backend.Task{UID: taskUID, Summary: "Original", Description: "local notes", Status: "NEEDS-ACTION", Priority: 1, Categories: []string{"home"}, Modified: now}
+	if err := local.UpdateTask(listID, localTask); err != nil {
+		t.Fatalf("Failed to update task: %v", err)
+	}
+	remoteTask := backend.Task{UID: taskUID, Summary: "Renamed", Status: "NEEDS-ACTION", Priority: 5, Categories: []string{"work"}, Modified: now.Add(time.Minute)}
+	remote.AddTask(listID, remoteTask)
+
+	result, err := sm.Sync()
+	if err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+	if result.ConflictsFound != 1 || result.ConflictsResolved != 1 {
+		t.Errorf("Expected 1 conflict found and resolved, got %d/%d", result.ConflictsFound, result.ConflictsResolved)
+	}
+
+	tasks, _ := local.GetTasks(listID, nil)
+	if len(tasks) != 1 {
+		t.Fatalf("Expected 1 task, got %d", len(tasks))
+	}
+	merged := tasks[0]
+	if merged.Summary != "Renamed" || merged.Description != "local notes" || merged.Priority != 1 {
+		t.Errorf("Unexpected merge result: summary=%q description=%q priority=%d", merged.Summary, merged.Description, merged.Priority)
+	}
+	if len(merged.Categories) != 2 {
+		t.Errorf("Expected categories to be unioned, got %v", merged.Categories)
+	}
+
+	// The merged version is pushed back to the remote
+	remoteTasks, _ := remote.GetTasks(listID, nil)
+	if remoteTasks[0].Description != "local notes" {
+		t.Errorf("Expected merged task to be pushed, remote description is %q", remoteTasks[0].Description)
+	}
+}
+
+// TestSequenceConflictDetection checks that SEQUENCE, when both sides have
+// one, decides whether the remote changed: a newer LAST-MODIFIED with the
+// same SEQUENCE is not a remote edit, a higher SEQUENCE is, and a local
+// version that wins is pushed on top of the remote's SEQUENCE
+func TestSequenceConflictDetection(t *testing.T) {
+	sm, local, remote, cleanup := createTestSyncManager(t, LocalWins)
+	defer cleanup()
+
+	listID, _ := local.CreateTaskList("Test List", "", "")
+	remote.Lists = append(remote.Lists, backend.TaskList{ID: listID, Name: "Test List", CTags: "ctag-1"})
+
+	now := time.Now().Truncate(time.Second)
+	synced := backend.Task{UID: "task-1", Summary: "Call", Status: "NEEDS-ACTION", Created: now, Modified: now, Sequence: 3}
+	if err := local.InsertSyncedTask(listID, synced); err != nil {
+		t.Fatalf("Failed to cache task: %v", err)
+	}
+	editLocally := func(summary string) {
+		t.Helper()
+		task, err := local.GetTask(listID, "task-1")
+		if err != nil {
+			t.Fatalf("GetTask failed: %v", err)
+		}
+		task.Summary = summary
+		if err := local.UpdateTask(listID, *task); err != nil {
+			t.Fatalf("UpdateTask failed: %v", err)
+		}
+	}
+
+	// Another client only touched LAST-MODIFIED
+	editLocally("Call mom")
+	touched := synced
+	touched.Modified = now.Add(time.Hour)
+	remote.Tasks[listID] = []backend.Task{touched}
+	result, err := sm.Sync()
+	if err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+	if result.ConflictsFound != 0 {
+		t.Errorf("same SEQUENCE: %d conflicts, want 0", result.ConflictsFound)
+	}
+
+	// Another client edited the task and raised SEQUENCE
+	editLocally("Call mom today")
+	bumped := remote.Tasks[listID][0]
+	bumped.Summary = "Call dad"
+	bumped.Sequence = 5
+	remote.Tasks[listID] = []backend.Task{bumped}
+	remote.Lists[0].CTags = "ctag-2"
+	result, err = sm.Sync()
+	if err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+	if result.ConflictsFound != 1 {
+		t.Errorf("higher SEQUENCE: %d conflicts, want 1", result.ConflictsFound)
+	}
+	pushed := remote.Tasks[listID][0]
+	if pushed.Summary != "Call mom today" || pushed.Sequence < 5 {
+		t.Errorf("pushed %q with SEQUENCE %d, want the local version with at least 5", pushed.Summary, pushed.Sequence)
+	}
+	cached, _ := local.GetTask(listID, "task-1")
+	if cached.Sequence < 5 {
+		t.Errorf("cached SEQUENCE = %d, went below the remote's 5", cached.Sequence)
+	}
+}
+
 // TestPushCreateOperation tests pushing a create operation
 func TestPushCreateOperation(t *testing.T) {
 	sm, local, remote, cleanup := createTestSyncManager(t, ServerWins)
@@ -402,6 +774,86 @@ func TestPushCreateOperation(t *testing.T) {
 	}
 }
 
+// TestPushListsOnly tests that only the given lists' changes are pushed,
+// the others staying queued
+func TestPushListsOnly(t *testing.T) {
+	sm, local, remote, cleanup := createTestSyncManager(t, ServerWins)
+	defer cleanup()
+
+	var listIDs []string
+	for _, name := range []string{"Work", "Home"} {
+		listID, _ := local.CreateTaskList(name, "", "")
+		remote.Lists = append(remote.Lists, backend.TaskList{ID: listID, Name: name})
+		remote.Tasks[listID] = []backend.Task{}
+		local.AddTask(listID, backend.Task{UID: name + "-1", Summary: "Task in " + name, Status: "NEEDS-ACTION"})
+		listIDs = append(listIDs, listID)
+	}
+
+	result, err := sm.PushListsOnly(listIDs[:1])
+	if err != nil {
+		t.Fatalf("PushListsOnly failed: %v", err)
+	}
+	if result.PushedTasks != 1 {
+		t.Errorf("Expected 1 pushed task, got %d", result.PushedTasks)
+	}
+	if len(remote.Tasks[listIDs[0]]) != 1 || len(remote.Tasks[listIDs[1]]) != 0 {
+		t.Errorf("Remote tasks = %v, want only the Work task", remote.Tasks)
+	}
+	ops, _ := local.GetPendingSyncOperations()
+	if len(ops) != 1 || ops[0].ListID != listIDs[1] {
+		t.Errorf("Pending operations = %+v, want the Home task's", ops)
+	}
+}
+
+// TestSyncList tests that only the given list is pulled and pushed, that its
+// CTag still short-circuits the pull, and that the result names the list
+func TestSyncList(t *testing.T) {
+	sm, local, remote, cleanup := createTestSyncManager(t, ServerWins)
+	defer cleanup()
+
+	var listIDs []string
+	for _, name := range []string{"Work", "Home"} {
+		listID, _ := local.CreateTaskList(name, "", "")
+		remote.Lists = append(remote.Lists, backend.TaskList{ID: listID, Name: name, CTags: "ctag-1"})
+		remote.Tasks[listID] = []backend.Task{{UID: name + "-remote", Summary: "Remote task in " + name, Status: "NEEDS-ACTION"}}
+		local.AddTask(listID, backend.Task{UID: name + "-local", Summary: "Local task in " + name, Status: "NEEDS-ACTION"})
+		listIDs = append(listIDs, listID)
+	}
+	work, home := listIDs[0], listIDs[1]
+
+	result, err := sm.SyncList(work)
+	if err != nil {
+		t.Fatalf("SyncList failed: %v", err)
+	}
+	if result.ListID != work || len(result.ListResults) != 1 || result.ListResults[0].ListID != work {
+		t.Errorf("SyncList result names %q with lists %+v, want only %s", result.ListID, result.ListResults, work)
+	}
+	if result.PulledTasks != 1 || result.PushedTasks != 1 {
+		t.Errorf("SyncList pulled %d and pushed %d, want 1 and 1", result.PulledTasks, result.PushedTasks)
+	}
+	if homeTasks, _ := local.GetTasks(home, nil); len(homeTasks) != 1 {
+		t.Errorf("Home has %d local tasks, want its remote task left unpulled", len(homeTasks))
+	}
+	if ops, _ := local.GetPendingSyncOperations(); len(ops) != 1 || ops[0].ListID != home {
+		t.Errorf("Pending operations = %+v, want the Home task's", ops)
+	}
+
+	// The CTag did not change: the pull is skipped
+	remote.Tasks[work] = append(remote.Tasks[work], backend.Task{UID: "unseen", Summary: "Added without a CTag change", Status: "NEEDS-ACTION"})
+	result, err = sm.SyncList(work)
+	if err != nil {
+		t.Fatalf("SyncList failed: %v", err)
+	}
+	if len(result.ListResults) != 1 || !result.ListResults[0].Skipped || result.PulledTasks != 0 {
+		t.Errorf("Second SyncList = %+v, want the list skipped", result.ListResults)
+	}
+
+	result, _ = sm.SyncList("missing")
+	if len(result.Errors) == 0 {
+		t.Error("SyncList of a list missing on the remote reported no error")
+	}
+}
+
 // TestPushUpdateOperation tests pushing an update operation
 func TestPushUpdateOperation(t *testing.T) {
 	sm, local, remote, cleanup := createTestSyncManager(t, LocalWins)
@@ -561,6 +1013,43 @@ func TestFullSync(t *testing.T) {
 	}
 }
 
+// TestFullSyncBacksUpFirst tests that the cache is backed up before a full sync changes it
+func TestFullSyncBacksUpFirst(t *testing.T) {
+	sm, local, remote, cleanup := createTestSyncManager(t, ServerWins)
+	defer cleanup()
+
+	listID, _ := remote.CreateTaskList("Test List", "", "")
+	_, _ = sm.Sync()
+	_, _ = remote.AddTask(listID, backend.Task{UID: "task-1", Summary: "Pulled by the full sync", Status: "NEEDS-ACTION"})
+
+	db, _ := local.GetDB()
+	var backup sqlite.BackupFile
+	sm.SetBeforeFullSync(func() error {
+		var err error
+		backup, err = db.Backup("full-sync", 5)
+		return err
+	})
+	if _, err := sm.FullSync(); err != nil {
+		t.Fatalf("FullSync() error = %v", err)
+	}
+	if backup.Path == "" {
+		t.Fatal("no backup taken before the full sync")
+	}
+
+	// The backup holds the cache as it was before the full sync
+	if err := db.RestoreBackup(backup.Path); err != nil {
+		t.Fatalf("RestoreBackup() error = %v", err)
+	}
+	if tasks, _ := local.GetTasks(listID, nil); len(tasks) != 0 {
+		t.Errorf("restored cache has %d tasks, want the pre-sync state", len(tasks))
+	}
+
+	sm.SetBeforeFullSync(func() error { return errors.New("disk full") })
+	if _, err := sm.FullSync(); err == nil {
+		t.Error("FullSync() ran although the backup failed")
+	}
+}
+
 // TestRetryLogic tests retry with exponential backoff
 func TestRetryLogic(t *testing.T) {
 	sm, local, remote, cleanup := createTestSyncManager(t, ServerWins)
@@ -593,7 +1082,7 @@ func TestRetryLogic(t *testing.T) {
 	_, _ = sm.Sync()
 
 	// Check retry count
-	ops, _ := local.GetPendingSyncOperations()
+	ops, _ := local.GetQueuedSyncOperations()
 	if len(ops) != 1 {
 		t.Fatalf("Expected 1 pending operation, got %d", len(ops))
 	}
@@ -606,9 +1095,26 @@ func TestRetryLogic(t *testing.T) {
 		t.Error("Expected last error to be set")
 	}
 
-	// Clear error and sync again
+	if ops[0].NextRetry.IsZero() {
+		t.Error("Expected the next retry to be set")
+	}
+
+	// Clear error; the operation is not pushed before its next retry
 	remote.AddTaskErr = nil
 	result, err := sm.Sync()
+	if err != nil {
+		t.Fatalf("Sync during backoff failed: %v", err)
+	}
+	if result.PushedTasks != 0 {
+		t.Errorf("Expected no pushed task during the backoff, got %d", result.PushedTasks)
+	}
+
+	// Once due, it is pushed
+	db, _ := local.GetDB()
+	if _, err := db.Exec("UPDATE sync_queue SET next_retry_at = 0"); err != nil {
+		t.Fatalf("Failed to end the backoff: %v", err)
+	}
+	result, err = sm.Sync()
 	if err != nil {
 		t.Fatalf("Retry sync failed: %v", err)
 	}
@@ -624,6 +1130,99 @@ func TestRetryLogic(t *testing.T) {
 	}
 }
 
+// TestPushConflict tests that an update the remote refuses as changed since
+// read stays queued without a retry, and that its list is pulled next time
+func TestPushConflict(t *testing.T) {
+	sm, local, remote, cleanup := createTestSyncManager(t, ServerWins)
+	defer cleanup()
+
+	listID, _ := local.CreateTaskList("Test List", "", "")
+	remote.Lists = append(remote.Lists, backend.TaskList{ID: listID, Name: "Test List", CTags: "ctag-123"})
+	remote.Tasks[listID] = []backend.Task{}
+	local.SetListCTag(listID, "ctag-123")
+	task := backend.Task{Summary: "Task", Status: "NEEDS-ACTION"}
+	task.UID, _ = local.AddTask(listID, task)
+	local.ClearSyncFlagsAndQueue(task.UID)
+	task.Summary = "Changed locally"
+	local.UpdateTask(listID, task)
+
+	remote.UpdateTaskErr = backend.NewBackendError("UpdateTask", 412, "the task changed on the server since it was read")
+	result, err := sm.Sync()
+	if err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+
+	if result.ConflictsFound != 1 || result.PushedTasks != 0 || result.ListResults[0].Conflicts != 1 {
+		t.Errorf("Sync result = %+v, want 1 conflict and nothing pushed", result)
+	}
+	if len(result.Errors) != 1 || !strings.Contains(result.Errors[0].Error(), "next sync") {
+		t.Errorf("Errors = %v, want the conflict reported", result.Errors)
+	}
+	ops, _ := local.GetPendingSyncOperations()
+	if len(ops) != 1 || ops[0].RetryCount != 0 {
+		t.Errorf("Pending operations = %+v, want the update queued without a retry", ops)
+	}
+	lists, _ := local.GetTaskLists()
+	if lists[0].CTags != "" {
+		t.Errorf("CTag = %q, want it cleared so the list is pulled", lists[0].CTags)
+	}
+}
+
+// etagRemote records the ETag each update is sent with
+type etagRemote struct {
+	*backend.MockBackend
+	sent []string
+}
+
+func (r *etagRemote) UpdateTask(listID string, task backend.Task) error {
+	r.sent = append(r.sent, task.ETag)
+	return r.MockBackend.UpdateTask(listID, task)
+}
+
+// TestRemoteModifiedByETag tests that a remote change is found by its ETag
+// when the server leaves LAST-MODIFIED and SEQUENCE as they were, and that
+// updates are pushed with the ETag of the version last synced
+func TestRemoteModifiedByETag(t *testing.T) {
+	local := newMemStore()
+	remote := &etagRemote{MockBackend: backend.NewMockBackend()}
+	sm := NewSyncManager(local, remote, ServerWins)
+
+	modified := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	remote.Lists = []backend.TaskList{{ID: "work", Name: "Work", CTags: "ctag-1"}}
+	remote.Tasks["work"] = []backend.Task{{UID: "task-1", Summary: "Original", Status: "NEEDS-ACTION", Modified: modified, Sequence: 1, ETag: `"e1"`}}
+	if _, err := sm.Sync(); err != nil {
+		t.Fatalf("Initial sync failed: %v", err)
+	}
+
+	// Changed content, same timestamps and SEQUENCE
+	remote.Tasks["work"][0].Summary = "Changed on the server"
+	remote.Tasks["work"][0].ETag = `"e2"`
+	remote.Lists[0].CTags = "ctag-2"
+	if _, err := sm.Sync(); err != nil {
+		t.Fatalf("Second sync failed: %v", err)
+	}
+	task, _ := local.GetTask("work", "task-1")
+	if task.Summary != "Changed on the server" {
+		t.Errorf("Local summary = %q, want the remote change pulled", task.Summary)
+	}
+
+	// A later LAST-MODIFIED with the same ETag is the version already cached
+	task.Summary = "Changed locally"
+	local.UpdateTask("work", *task)
+	remote.Tasks["work"][0].Modified = modified.Add(time.Hour)
+	remote.Lists[0].CTags = "ctag-3"
+	result, err := sm.Sync()
+	if err != nil {
+		t.Fatalf("Third sync failed: %v", err)
+	}
+	if result.ConflictsFound != 0 || result.PushedTasks != 1 {
+		t.Errorf("Sync result = %+v, want the local change pushed without a conflict", result)
+	}
+	if len(remote.sent) != 1 || remote.sent[0] != `"e2"` {
+		t.Errorf("Updates sent with ETags %q, want the one last synced", remote.sent)
+	}
+}
+
 // TestSyncStats tests getting sync statistics
 func TestSyncStats(t *testing.T) {
 	sm, local, _, cleanup := createTestSyncManager(t, ServerWins)
@@ -706,3 +1305,509 @@ func TestSyncResult(t *testing.T) {
 		t.Errorf("Expected 1 pulled task, got %d", result.PulledTasks)
 	}
 }
+
+// TestPullDuplicateRemoteUIDs tests that a list returning the same UID twice
+// keeps the first copy, reports the list and does not infer deletions
+func TestPullDuplicateRemoteUIDs(t *testing.T) {
+	sm, local, remote := newMemSyncManager(ServerWins)
+
+	listID := "list-1"
+	local.lists = append(local.lists, backend.TaskList{ID: listID, Name: "Test List", CTags: "ctag-1"})
+	local.InsertSyncedTask(listID, backend.Task{UID: "dup", Summary: "Cached", Status: "NEEDS-ACTION"})
+	local.InsertSyncedTask(listID, backend.Task{UID: "missing", Summary: "Not returned", Status: "NEEDS-ACTION"})
+
+	remote.Lists = append(remote.Lists, backend.TaskList{ID: listID, Name: "Test List", CTags: "ctag-2"})
+	remote.Tasks[listID] = []backend.Task{
+		{UID: "dup", Summary: "First copy", Status: "NEEDS-ACTION"},
+		{UID: "dup", Summary: "Second copy", Status: "NEEDS-ACTION"},
+	}
+
+	result, err := sm.Sync()
+	if err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+
+	if len(result.Errors) != 1 {
+		t.Fatalf("Expected 1 error reporting the duplicates, got %v", result.Errors)
+	}
+	if !strings.Contains(result.Errors[0].Error(), "Test List") {
+		t.Errorf("Expected error to name the list, got %v", result.Errors[0])
+	}
+
+	tasks, _ := local.GetTasks(listID, nil)
+	if len(tasks) != 2 {
+		t.Fatalf("Expected 2 cached tasks (no deletion inferred), got %d", len(tasks))
+	}
+	for _, task := range tasks {
+		if task.UID == "dup" && task.Summary != "First copy" {
+			t.Errorf("Expected first copy to be kept, got %q", task.Summary)
+		}
+	}
+
+	if local.lists[0].CTags != "" {
+		t.Errorf("Expected CTag to be cleared so the list is re-checked, got %q", local.lists[0].CTags)
+	}
+}
+
+// setupMemConflict creates a task modified both locally and remotely
+func setupMemConflict(t *testing.T, local *memStore, remote *backend.MockBackend) (string, string) {
+	listID := "list-1"
+	local.lists = append(local.lists, backend.TaskList{ID: listID, Name: "Test List"})
+	remote.Lists = append(remote.Lists, backend.TaskList{ID: listID, Name: "Test List", CTags: "ctag-2"})
+
+	now := time.Now()
+	task := backend.Task{Summary: "Original", Status: "NEEDS-ACTION", Priority: 5, Modified: now}
+	uid, err := local.AddTask(listID, task)
+	if err != nil {
+		t.Fatalf("Failed to add task: %v", err)
+	}
+	task.UID = uid
+	task.Summary = "Local Modification"
+	local.UpdateTask(listID, task)
+
+	remoteTask := task
+	remoteTask.Summary = "Remote Modification"
+	remote.Tasks[listID] = []backend.Task{remoteTask}
+	return listID, uid
+}
+
+// TestConflictResolutionPrompt tests that the prompter chooses the strategy
+// and that conflicts are reported in the result
+func TestConflictResolutionPrompt(t *testing.T) {
+	sm, local, remote := newMemSyncManager(Prompt)
+	listID, uid := setupMemConflict(t, local, remote)
+
+	var prompted []Conflict
+	sm.SetConflictPrompter(func(conflict Conflict) (ConflictResolutionStrategy, error) {
+		prompted = append(prompted, conflict)
+		return ServerWins, nil
+	})
+
+	result, err := sm.Sync()
+	if err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+
+	if len(prompted) != 1 || prompted[0].Local.UID != uid || prompted[0].Remote.Summary != "Remote Modification" {
+		t.Fatalf("Expected one prompt for the conflicting task, got %+v", prompted)
+	}
+	if len(result.Conflicts) != 1 || result.Conflicts[0].Resolution != ServerWins {
+		t.Errorf("Expected conflict resolved with server_wins in result, got %+v", result.Conflicts)
+	}
+
+	tasks, _ := local.GetTasks(listID, nil)
+	if len(tasks) != 1 || tasks[0].Summary != "Remote Modification" {
+		t.Errorf("Expected remote version to be kept, got %+v", tasks)
+	}
+}
+
+// TestConflictResolutionPromptWithoutPrompter tests that prompted conflicts
+// keep both versions when nobody can be asked and no fallback is set
+func TestConflictResolutionPromptWithoutPrompter(t *testing.T) {
+	sm, local, remote := newMemSyncManager(Prompt)
+	listID, _ := setupMemConflict(t, local, remote)
+
+	result, err := sm.Sync()
+	if err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+	if len(result.Conflicts) != 1 || result.Conflicts[0].Resolution != KeepBoth {
+		t.Errorf("Expected keep_both resolution, got %+v", result.Conflicts)
+	}
+
+	tasks, _ := local.GetTasks(listID, nil)
+	if len(tasks) != 2 {
+		t.Errorf("Expected both versions to be kept, got %d tasks", len(tasks))
+	}
+}
+
+// TestConflictResolutionPromptFallback tests that the prompter picks the
+// strategy of each conflict, that the fallback applies without one, and that
+// the result counts the conflicts resolved each way
+func TestConflictResolutionPromptFallback(t *testing.T) {
+	sm, local, remote := newMemSyncManager(Prompt)
+	listID, uid := setupMemConflict(t, local, remote)
+	sm.SetConflictPrompter(func(conflict Conflict) (ConflictResolutionStrategy, error) {
+		return LocalWins, nil
+	})
+
+	result, err := sm.Sync()
+	if err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+	if len(result.Conflicts) != 1 || result.Conflicts[0].Resolution != LocalWins {
+		t.Errorf("Expected conflict resolved with local_wins, got %+v", result.Conflicts)
+	}
+	if got := result.ResolvedBy(); len(got) != 1 || got[LocalWins] != 1 {
+		t.Errorf("ResolvedBy() = %v, want 1 local_wins", got)
+	}
+	tasks, _ := local.GetTasks(listID, nil)
+	if len(tasks) != 1 || tasks[0].UID != uid || tasks[0].Summary == "Remote Modification" {
+		t.Errorf("Expected the local version to be kept, got %+v", tasks)
+	}
+
+	// Without a terminal, the fallback strategy applies
+	for _, tt := range []struct {
+		fallback ConflictResolutionStrategy
+		want     ConflictResolutionStrategy
+		tasks    int
+	}{
+		{"", KeepBoth, 2},
+		{ServerWins, ServerWins, 1},
+	} {
+		sm, local, remote := newMemSyncManager(Prompt)
+		listID, _ := setupMemConflict(t, local, remote)
+		sm.SetConflictFallback(tt.fallback)
+
+		result, err := sm.Sync()
+		if err != nil {
+			t.Fatalf("Sync failed: %v", err)
+		}
+		if got := result.ResolvedBy(); len(got) != 1 || got[tt.want] != 1 {
+			t.Errorf("fallback %q: ResolvedBy() = %v, want 1 %s", tt.fallback, got, tt.want)
+		}
+		if tasks, _ := local.GetTasks(listID, nil); len(tasks) != tt.tasks {
+			t.Errorf("fallback %q: got %d tasks, want %d", tt.fallback, len(tasks), tt.tasks)
+		}
+	}
+}
+
+// TestPreview tests that Preview reports changes without applying them
+func TestPreview(t *testing.T) {
+	sm, local, remote := newMemSyncManager(ServerWins)
+	listID, _ := setupMemConflict(t, local, remote)
+
+	local.InsertSyncedTask(listID, backend.Task{UID: "stale", Summary: "Removed remotely", Status: "NEEDS-ACTION"})
+	local.InsertSyncedTask(listID, backend.Task{UID: "edited", Summary: "Old name", Status: "NEEDS-ACTION"})
+	remote.Tasks[listID] = append(remote.Tasks[listID],
+		backend.Task{UID: "edited", Summary: "New name", Status: "NEEDS-ACTION", Modified: time.Now()},
+		backend.Task{UID: "fresh", Summary: "Added remotely", Status: "NEEDS-ACTION"},
+	)
+
+	before, _ := local.GetTasks(listID, nil)
+	preview, err := sm.Preview()
+	if err != nil {
+		t.Fatalf("Preview failed: %v", err)
+	}
+
+	kinds := make(map[string]string)
+	for _, change := range preview.Changes {
+		uid := change.Remote.UID
+		if uid == "" {
+			uid = change.Local.UID
+		}
+		kinds[uid] = change.Kind
+	}
+	want := map[string]string{"fresh": "new", "edited": "update", "stale": "delete"}
+	if len(kinds) != len(want) {
+		t.Errorf("Expected changes %v, got %v", want, kinds)
+	}
+	for uid, kind := range want {
+		if kinds[uid] != kind {
+			t.Errorf("Task %s: expected %s, got %q", uid, kind, kinds[uid])
+		}
+	}
+
+	if len(preview.Conflicts) != 1 || preview.Conflicts[0].Resolution != "" {
+		t.Errorf("Expected 1 unresolved conflict, got %+v", preview.Conflicts)
+	}
+	if len(preview.PendingOperations) != 2 {
+		t.Errorf("Expected 2 pending operations, got %d", len(preview.PendingOperations))
+	}
+
+	after, _ := local.GetTasks(listID, nil)
+	if len(after) != len(before) || local.lists[0].CTags != "" {
+		t.Error("Preview changed the local store")
+	}
+}
+
+// failingListRemote fails to return the tasks of one list
+type failingListRemote struct {
+	*backend.MockBackend
+	failingList string
+}
+
+func (r failingListRemote) GetTasks(listID string, filter *backend.TaskFilter) ([]backend.Task, error) {
+	if listID == r.failingList {
+		return nil, errors.New("server error")
+	}
+	return r.MockBackend.GetTasks(listID, filter)
+}
+
+// TestSyncListResults tests the per-list breakdown of an unchanged, a changed
+// and a failing list
+func TestSyncListResults(t *testing.T) {
+	local := newMemStore()
+	mock := backend.NewMockBackend()
+	sm := NewSyncManager(local, failingListRemote{MockBackend: mock, failingList: "broken"}, ServerWins)
+
+	local.lists = []backend.TaskList{
+		{ID: "same", Name: "Same", CTags: "ctag-1"},
+		{ID: "changed", Name: "Changed", CTags: "ctag-1"},
+		{ID: "broken", Name: "Broken", CTags: "ctag-1"},
+	}
+	mock.Lists = []backend.TaskList{
+		{ID: "same", Name: "Same", CTags: "ctag-1"},
+		{ID: "changed", Name: "Changed", CTags: "ctag-2"},
+		{ID: "broken", Name: "Broken", CTags: "ctag-2"},
+	}
+	mock.Tasks["same"] = []backend.Task{{UID: "unseen", Summary: "Not pulled", Status: "NEEDS-ACTION"}}
+	mock.Tasks["changed"] = []backend.Task{{UID: "remote", Summary: "Pulled", Status: "NEEDS-ACTION"}}
+	if _, err := local.AddTask("changed", backend.Task{UID: "local", Summary: "Pushed", Status: "NEEDS-ACTION"}); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := sm.Sync()
+	if err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+	if len(result.ListResults) != 3 {
+		t.Fatalf("Expected 3 list results, got %+v", result.ListResults)
+	}
+
+	same, changed, broken := result.ListResults[0], result.ListResults[1], result.ListResults[2]
+	if !same.Skipped || same.LocalCTag != "ctag-1" || same.RemoteCTag != "ctag-1" || same.Pulled != 0 {
+		t.Errorf("Unchanged list: %+v", same)
+	}
+	if !strings.Contains(same.SkipReason, "ctag-1") {
+		t.Errorf("Skip reason %q does not show the CTags", same.SkipReason)
+	}
+	if changed.Skipped || changed.Err != nil || changed.Pulled != 1 || changed.Pushed != 1 {
+		t.Errorf("Changed list: %+v", changed)
+	}
+	if changed.LocalCTag != "ctag-1" || changed.RemoteCTag != "ctag-2" {
+		t.Errorf("Changed list CTags: local %q, remote %q", changed.LocalCTag, changed.RemoteCTag)
+	}
+	if broken.Err == nil || broken.Skipped || broken.Pulled != 0 {
+		t.Errorf("Failing list: %+v", broken)
+	}
+	if len(result.Errors) != 1 || !strings.Contains(result.Errors[0].Error(), "Broken") {
+		t.Errorf("Expected 1 error naming the failing list, got %v", result.Errors)
+	}
+	if local.lists[2].CTags != "" {
+		t.Errorf("Expected the failing list's CTag to be cleared, got %q", local.lists[2].CTags)
+	}
+}
+
+// TestAdoptList tests adopting one remote list without a full sync
+func TestAdoptList(t *testing.T) {
+	sm, local, remote, cleanup := createTestSyncManager(t, ServerWins)
+	defer cleanup()
+
+	listID, _ := remote.CreateTaskList("Groceries", "", "")
+	otherID, _ := remote.CreateTaskList("Work", "", "")
+	remote.AddTask(listID, backend.Task{UID: "task-1", Summary: "Milk", Status: "NEEDS-ACTION"})
+	remote.AddTask(otherID, backend.Task{UID: "task-2", Summary: "Report", Status: "NEEDS-ACTION"})
+
+	result, err := sm.AdoptList(remote.Lists[0])
+	if err != nil {
+		t.Fatalf("AdoptList failed: %v", err)
+	}
+	if result.Pulled != 1 {
+		t.Errorf("Expected 1 pulled task, got %d", result.Pulled)
+	}
+
+	lists, err := local.GetTaskLists()
+	if err != nil {
+		t.Fatalf("Failed to get local lists: %v", err)
+	}
+	if len(lists) != 1 || lists[0].ID != listID {
+		t.Errorf("Expected only the adopted list in the cache, got %+v", lists)
+	}
+	tasks, _ := local.GetTasks(listID, nil)
+	if len(tasks) != 1 || tasks[0].UID != "task-1" {
+		t.Errorf("Expected the adopted list's task, got %+v", tasks)
+	}
+}
+
+// changesRemote tracks the changes of its lists for delta pulls
+type changesRemote struct {
+	*backend.MockBackend
+	changes     *backend.ListChanges // Returned by GetChanges; nil refuses the token
+	tokens      []string             // Tokens GetChanges was called with
+	fullFetches int
+}
+
+func (r *changesRemote) GetChanges(listID, syncToken string) (*backend.ListChanges, error) {
+	r.tokens = append(r.tokens, syncToken)
+	if r.changes == nil {
+		return nil, backend.NewBackendError("GetChanges", 403, "sync token no longer valid")
+	}
+	return r.changes, nil
+}
+
+func (r *changesRemote) GetTasksAt(listID string, hrefs []string) ([]backend.Task, error) {
+	var tasks []backend.Task
+	for _, href := range hrefs {
+		for _, task := range r.Tasks[listID] {
+			if href == "/calendars/"+listID+"/"+task.UID+".ics" {
+				tasks = append(tasks, task)
+			}
+		}
+	}
+	return tasks, nil
+}
+
+func (r *changesRemote) GetTasks(listID string, filter *backend.TaskFilter) ([]backend.Task, error) {
+	r.fullFetches++
+	return r.MockBackend.GetTasks(listID, filter)
+}
+
+// TestPullChanges tests that lists pulled before are pulled from their
+// changes since the stored sync token, and in full when the token is refused
+// or a deleted task can't be found from its href
+func TestPullChanges(t *testing.T) {
+	local := newMemStore()
+	remote := &changesRemote{MockBackend: backend.NewMockBackend()}
+	sm := NewSyncManager(local, remote, ServerWins)
+
+	remote.Lists = []backend.TaskList{{ID: "work", Name: "Work", CTags: "ctag-1", SyncToken: "token-1"}}
+	remote.Tasks["work"] = []backend.Task{
+		{UID: "task-1", Summary: "Report", Status: "NEEDS-ACTION"},
+		{UID: "task-2", Summary: "Slides", Status: "NEEDS-ACTION"},
+	}
+	if _, err := sm.Sync(); err != nil {
+		t.Fatalf("Initial sync failed: %v", err)
+	}
+	if token, _ := local.GetListSyncToken("work"); token != "token-1" {
+		t.Errorf("Sync token after the first sync = %q, want the list's token", token)
+	}
+
+	remote.Tasks["work"] = []backend.Task{
+		{UID: "task-1", Summary: "Report, revised", Status: "NEEDS-ACTION"},
+		{UID: "task-3", Summary: "Budget", Status: "NEEDS-ACTION"},
+	}
+	remote.Lists[0].CTags, remote.Lists[0].SyncToken = "ctag-2", "token-3"
+	remote.changes = &backend.ListChanges{
+		Changed:   []string{"/calendars/work/task-1.ics", "/calendars/work/task-3.ics"},
+		Deleted:   []string{"/calendars/work/task-2.ics"},
+		SyncToken: "token-2",
+	}
+	result, err := sm.Sync()
+	if err != nil {
+		t.Fatalf("Delta sync failed: %v", err)
+	}
+	if remote.fullFetches != 1 || !slices.Equal(remote.tokens, []string{"token-1"}) {
+		t.Errorf("Full fetches = %d with tokens %v, want only the changes since token-1", remote.fullFetches, remote.tokens)
+	}
+	if !result.ListResults[0].Delta || result.PulledTasks != 2 {
+		t.Errorf("List result %+v with %d pulled, want a delta pull of 2 tasks", result.ListResults[0], result.PulledTasks)
+	}
+	tasks, _ := local.GetTasks("work", nil)
+	summaries := make([]string, len(tasks))
+	for i, task := range tasks {
+		summaries[i] = task.Summary
+	}
+	slices.Sort(summaries)
+	if !slices.Equal(summaries, []string{"Budget", "Report, revised"}) {
+		t.Errorf("Local tasks = %v, want the changes applied", summaries)
+	}
+	if token, _ := local.GetListSyncToken("work"); token != "token-2" {
+		t.Errorf("Sync token after the delta = %q, want token-2", token)
+	}
+
+	// A refused token falls back to the full fetch
+	remote.changes = nil
+	remote.Lists[0].CTags = "ctag-3"
+	if _, err := sm.Sync(); err != nil {
+		t.Fatalf("Sync with a refused token failed: %v", err)
+	}
+	if remote.fullFetches != 2 {
+		t.Errorf("Full fetches = %d, want a full fetch after the token was refused", remote.fullFetches)
+	}
+	if token, _ := local.GetListSyncToken("work"); token != "token-3" {
+		t.Errorf("Sync token after the full fetch = %q, want the list's token", token)
+	}
+
+	// So does a deletion of a task the cache doesn't know by its href
+	remote.changes = &backend.ListChanges{Deleted: []string{"/calendars/work/other-name.ics"}, SyncToken: "token-4"}
+	remote.Lists[0].CTags = "ctag-4"
+	if _, err := sm.Sync(); err != nil {
+		t.Fatalf("Sync with an unknown deletion failed: %v", err)
+	}
+	if remote.fullFetches != 3 {
+		t.Errorf("Full fetches = %d, want a full fetch for an unknown deletion", remote.fullFetches)
+	}
+}
+
+// TestMaxRetries tests that operations past the retry limit are not pushed
+// and are reported instead of skipped silently
+func TestMaxRetries(t *testing.T) {
+	sm, local, remote := newMemSyncManager(ServerWins)
+	remote.Lists = []backend.TaskList{{ID: "work", Name: "Work", CTags: "ctag-1"}}
+	if _, err := sm.Sync(); err != nil {
+		t.Fatalf("Initial sync failed: %v", err)
+	}
+	uid, _ := local.AddTask("work", backend.Task{Summary: "Report", Status: "NEEDS-ACTION"})
+	local.queue[0].RetryCount = 2
+	local.queue[0].LastError = "HTTP 507: quota exceeded"
+
+	sm.SetMaxRetries(2)
+	result, err := sm.Sync()
+	if err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+	if result.PushedTasks != 0 || len(remote.Tasks["work"]) != 0 {
+		t.Errorf("Pushed %d tasks, want none past the retry limit", result.PushedTasks)
+	}
+	if len(result.Errors) != 1 || !strings.Contains(result.Errors[0].Error(), "gosynctasks sync retry "+uid) ||
+		!strings.Contains(result.Errors[0].Error(), "quota exceeded") {
+		t.Errorf("Errors = %v, want the operation reported with its last error", result.Errors)
+	}
+
+	sm.SetMaxRetries(3)
+	result, err = sm.Sync()
+	if err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+	if result.PushedTasks != 1 || len(result.Errors) != 0 {
+		t.Errorf("Pushed %d tasks with errors %v, want the operation pushed under a higher limit", result.PushedTasks, result.Errors)
+	}
+}
+
+// brokenTaskRemote refuses to create one task
+type brokenTaskRemote struct {
+	*backend.MockBackend
+	broken string // Summary of the task refused
+}
+
+func (r *brokenTaskRemote) AddTask(listID string, task backend.Task) (string, error) {
+	if task.Summary == r.broken {
+		return "", backend.NewBackendError("AddTask", 500, "Internal Server Error")
+	}
+	return r.MockBackend.AddTask(listID, task)
+}
+
+// TestPushDoesNotWaitForBackoff tests that a failing operation doesn't hold
+// up the push of the others, and waits out its backoff in the queue
+func TestPushDoesNotWaitForBackoff(t *testing.T) {
+	_, local, mock, cleanup := createTestSyncManager(t, ServerWins)
+	defer cleanup()
+	remote := &brokenTaskRemote{MockBackend: mock, broken: "Broken"}
+	sm := NewSyncManager(local, remote, ServerWins)
+
+	listID, _ := local.CreateTaskList("Work", "", "")
+	mock.Lists = []backend.TaskList{{ID: listID, Name: "Work"}}
+	local.AddTask(listID, backend.Task{Summary: "Broken", Status: "NEEDS-ACTION"})
+	for i := range 10 {
+		local.AddTask(listID, backend.Task{Summary: fmt.Sprintf("Task %d", i), Status: "NEEDS-ACTION"})
+	}
+
+	start := time.Now()
+	for range 3 {
+		if _, err := sm.PushOnly(); err != nil {
+			t.Fatalf("PushOnly failed: %v", err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("Pushes took %v, want no waiting for the failing operation", elapsed)
+	}
+
+	if len(mock.Tasks[listID]) != 10 {
+		t.Errorf("Remote has %d tasks, want the 10 good ones", len(mock.Tasks[listID]))
+	}
+	ops, _ := local.GetQueuedSyncOperations()
+	if len(ops) != 1 || ops[0].RetryCount != 1 || ops[0].NextRetry.IsZero() {
+		t.Errorf("Queue = %+v, want the failing create waiting for its retry after one attempt", ops)
+	}
+}
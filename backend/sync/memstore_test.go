@@ -0,0 +1,368 @@
+package sync
+
+import (
+	"fmt"
+	"maps"
+	"time"
+
+	"gosynctasks/backend"
+	"gosynctasks/backend/sqlite"
+)
+
+// memTask is a task in memStore with its sync state
+type memTask struct {
+	listID           string
+	task             backend.Task
+	locallyModified  bool
+	remoteModifiedAt *time.Time
+	remoteETag       string
+}
+
+// memStore is an in-memory LocalStore for testing SyncManager logic without SQLite.
+// Like the SQLite cache, local changes made through AddTask and UpdateTask are
+// queued for push, while the *Synced* methods apply remote state without queueing.
+type memStore struct {
+	lists    []backend.TaskList
+	tasks    []*memTask
+	queue    []sqlite.SyncOperation
+	nextOpID int
+	pruned   map[string]map[string]time.Time // Pruned tasks by list, then UID
+}
+
+var _ LocalStore = (*memStore)(nil)
+
+func newMemStore() *memStore {
+	return &memStore{}
+}
+
+// newMemSyncManager returns a sync manager backed by a memStore and a mock remote
+func newMemSyncManager(strategy ConflictResolutionStrategy) (*SyncManager, *memStore, *backend.MockBackend) {
+	local := newMemStore()
+	remote := backend.NewMockBackend()
+	return NewSyncManager(local, remote, strategy), local, remote
+}
+
+func (m *memStore) find(taskUID string) *memTask {
+	for _, t := range m.tasks {
+		if t.task.UID == taskUID {
+			return t
+		}
+	}
+	return nil
+}
+
+func (m *memStore) enqueue(listID, taskUID, operation string) {
+	m.nextOpID++
+	m.queue = append(m.queue, sqlite.SyncOperation{
+		ID:        m.nextOpID,
+		TaskUID:   taskUID,
+		ListID:    listID,
+		Operation: operation,
+		CreatedAt: time.Now(),
+	})
+}
+
+func (m *memStore) GetTaskLists() ([]backend.TaskList, error) {
+	var lists []backend.TaskList
+	for _, list := range m.lists {
+		if !list.Archived {
+			lists = append(lists, list)
+		}
+	}
+	return lists, nil
+}
+
+func (m *memStore) GetTasks(listID string, filter *backend.TaskFilter) ([]backend.Task, error) {
+	var tasks []backend.Task
+	for _, t := range m.tasks {
+		if t.listID == listID {
+			tasks = append(tasks, t.task)
+		}
+	}
+	return tasks, nil
+}
+
+func (m *memStore) GetTask(listID, taskUID string) (*backend.Task, error) {
+	t := m.find(taskUID)
+	if t == nil || t.listID != listID {
+		return nil, backend.NewNotFoundError("GetTask", listID, taskUID)
+	}
+	task := t.task
+	return &task, nil
+}
+
+func (m *memStore) AddTask(listID string, task backend.Task) (string, error) {
+	if task.UID == "" {
+		task.UID = sqlite.GenerateUID()
+	}
+	if m.find(task.UID) != nil {
+		return "", fmt.Errorf("task %s already exists", task.UID)
+	}
+	m.tasks = append(m.tasks, &memTask{listID: listID, task: task, locallyModified: true})
+	m.enqueue(listID, task.UID, "create")
+	return task.UID, nil
+}
+
+// UpdateTask records a local edit, as SQLiteBackend.UpdateTask does
+func (m *memStore) UpdateTask(listID string, task backend.Task) error {
+	t := m.find(task.UID)
+	if t == nil {
+		return fmt.Errorf("task %s not found", task.UID)
+	}
+	t.task = task
+	t.locallyModified = true
+	m.enqueue(listID, task.UID, "update")
+	return nil
+}
+
+func (m *memStore) GetPendingSyncOperations() ([]sqlite.SyncOperation, error) {
+	var due []sqlite.SyncOperation
+	for _, op := range m.queue {
+		if !op.NextRetry.After(time.Now()) {
+			due = append(due, op)
+		}
+	}
+	return due, nil
+}
+
+func (m *memStore) RecordSyncFailure(operationID int, message string, nextRetry time.Time) error {
+	for i := range m.queue {
+		if m.queue[i].ID == operationID {
+			m.queue[i].RetryCount++
+			m.queue[i].LastError = message
+			m.queue[i].NextRetry = nextRetry
+		}
+	}
+	return nil
+}
+
+func (m *memStore) ClearSyncFlagsAndQueue(taskUID string) error {
+	t := m.find(taskUID)
+	if t == nil {
+		return fmt.Errorf("task %s not found", taskUID)
+	}
+	t.locallyModified = false
+
+	queue := m.queue[:0]
+	for _, op := range m.queue {
+		if op.TaskUID != taskUID {
+			queue = append(queue, op)
+		}
+	}
+	m.queue = queue
+	return nil
+}
+
+func (m *memStore) StrandSyncOperations(listID, message string) (int, error) {
+	stranded := 0
+	for i := range m.queue {
+		if m.queue[i].ListID == listID && m.queue[i].Operation != "delete" {
+			m.queue[i].Stranded = true
+			m.queue[i].LastError = message
+			stranded++
+		}
+	}
+	return stranded, nil
+}
+
+func (m *memStore) MarkLocallyModified(taskUID string) error {
+	t := m.find(taskUID)
+	if t == nil {
+		return fmt.Errorf("task %s not found", taskUID)
+	}
+	t.locallyModified = true
+	return nil
+}
+
+func (m *memStore) UpdateSyncMetadata(taskUID, listID, etag string, remoteModifiedAt time.Time) error {
+	t := m.find(taskUID)
+	if t == nil {
+		return fmt.Errorf("task %s not found", taskUID)
+	}
+	modified := time.Unix(remoteModifiedAt.Unix(), 0)
+	t.remoteModifiedAt = &modified
+	t.remoteETag = etag
+	return nil
+}
+
+func (m *memStore) IsLocallyModified(taskUID string) (bool, error) {
+	if t := m.find(taskUID); t != nil {
+		return t.locallyModified, nil
+	}
+	return false, nil
+}
+
+func (m *memStore) GetRemoteModifiedAt(taskUID string) (*time.Time, error) {
+	if t := m.find(taskUID); t != nil {
+		return t.remoteModifiedAt, nil
+	}
+	return nil, nil
+}
+
+func (m *memStore) GetRemoteETag(taskUID string) (string, error) {
+	if t := m.find(taskUID); t != nil {
+		return t.remoteETag, nil
+	}
+	return "", nil
+}
+
+func (m *memStore) CreateSyncedList(list backend.TaskList) error {
+	list.SyncToken = "" // Set once the list is pulled, as in SQLite
+	m.lists = append(m.lists, list)
+	return nil
+}
+
+func (m *memStore) UpdateListInfo(list backend.TaskList) error {
+	for i := range m.lists {
+		if m.lists[i].ID == list.ID {
+			m.lists[i].Name = list.Name
+			m.lists[i].Path = list.Path
+		}
+	}
+	return nil
+}
+
+func (m *memStore) SetListCTag(listID, ctag string) error {
+	for i := range m.lists {
+		if m.lists[i].ID == listID {
+			m.lists[i].CTags = ctag
+		}
+	}
+	return nil
+}
+
+func (m *memStore) ClearListCTags() error {
+	for i := range m.lists {
+		m.lists[i].CTags = ""
+		m.lists[i].SyncToken = ""
+	}
+	return nil
+}
+
+func (m *memStore) GetListSyncToken(listID string) (string, error) {
+	for _, list := range m.lists {
+		if list.ID == listID {
+			return list.SyncToken, nil
+		}
+	}
+	return "", nil
+}
+
+func (m *memStore) SetListSyncToken(listID, token string) error {
+	for i := range m.lists {
+		if m.lists[i].ID == listID {
+			m.lists[i].SyncToken = token
+		}
+	}
+	return nil
+}
+
+func (m *memStore) GetArchivedTaskLists() ([]backend.TaskList, error) {
+	var lists []backend.TaskList
+	for _, list := range m.lists {
+		if list.Archived {
+			lists = append(lists, list)
+		}
+	}
+	return lists, nil
+}
+
+func (m *memStore) SetListArchived(listID string, archived bool) error {
+	for i := range m.lists {
+		if m.lists[i].ID == listID {
+			m.lists[i].Archived = archived
+		}
+	}
+	return nil
+}
+
+func (m *memStore) InsertSyncedTask(listID string, task backend.Task) error {
+	if m.find(task.UID) != nil {
+		return fmt.Errorf("task %s already exists", task.UID)
+	}
+	modified := time.Unix(remoteModifiedUnix(task), 0)
+	m.tasks = append(m.tasks, &memTask{listID: listID, task: task, remoteModifiedAt: &modified, remoteETag: task.ETag})
+	return nil
+}
+
+func (m *memStore) BulkInsertTasks(listID string, tasks []backend.Task) error {
+	for _, task := range tasks {
+		if err := m.InsertSyncedTask(listID, task); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *memStore) UpdateSyncedTask(listID string, task backend.Task) error {
+	t := m.find(task.UID)
+	if t == nil || t.listID != listID {
+		return fmt.Errorf("task %s not found in list %s", task.UID, listID)
+	}
+	if task.Completed == nil && backend.IsCompletedStatus(task.Status) {
+		task.Completed = t.task.Completed
+	}
+	modified := time.Unix(remoteModifiedUnix(task), 0)
+	t.task = task
+	t.locallyModified = false
+	t.remoteModifiedAt = &modified
+	t.remoteETag = task.ETag
+	return nil
+}
+
+func (m *memStore) DeleteSyncedTask(listID, taskUID string) error {
+	tasks := m.tasks[:0]
+	for _, t := range m.tasks {
+		if t.listID != listID || t.task.UID != taskUID {
+			tasks = append(tasks, t)
+		}
+	}
+	m.tasks = tasks
+	return nil
+}
+
+func (m *memStore) PrunedTasks(listID string) (map[string]time.Time, error) {
+	return maps.Clone(m.pruned[listID]), nil
+}
+
+func (m *memStore) ForgetPrunedTasks(listID string, uids []string) error {
+	for _, uid := range uids {
+		delete(m.pruned[listID], uid)
+	}
+	return nil
+}
+
+func (m *memStore) UpdateTaskUID(listID, oldUID, newUID string) error {
+	t := m.find(oldUID)
+	if t == nil || t.listID != listID {
+		return fmt.Errorf("task %s not found in list %s", oldUID, listID)
+	}
+	t.task.UID = newUID
+	for i := range m.queue {
+		if m.queue[i].TaskUID == oldUID {
+			m.queue[i].TaskUID = newUID
+		}
+	}
+	return nil
+}
+
+func (m *memStore) GetStats() (sqlite.DatabaseStats, error) {
+	stats := sqlite.DatabaseStats{
+		TaskCount:      len(m.tasks),
+		ListCount:      len(m.lists),
+		PendingSyncOps: len(m.queue),
+	}
+	for _, t := range m.tasks {
+		if t.locallyModified {
+			stats.LocallyModified++
+		}
+	}
+	return stats, nil
+}
+
+func remoteModifiedUnix(task backend.Task) int64 {
+	if task.Modified.IsZero() {
+		return 0
+	}
+	return task.Modified.Unix()
+}
@@ -0,0 +1,92 @@
+package sync
+
+import (
+	"sync"
+
+	"gosynctasks/backend/sqlite"
+)
+
+// forEachConcurrently calls fn for each item, in order, on up to workers
+// goroutines, and returns once every call has
+func forEachConcurrently[T any](items []T, workers int, fn func(T)) {
+	if workers <= 1 || len(items) <= 1 {
+		for _, item := range items {
+			fn(item)
+		}
+		return
+	}
+
+	jobs := make(chan T)
+	var wg sync.WaitGroup
+	for range min(workers, len(items)) {
+		wg.Go(func() {
+			for item := range jobs {
+				fn(item)
+			}
+		})
+	}
+	for _, item := range items {
+		jobs <- item
+	}
+	close(jobs)
+	wg.Wait()
+}
+
+// pushWaves groups queued operations by task, in the queue's order so that a
+// create is sent before the update or delete following it, and splits the
+// groups into waves pushed one after the other. A task created along with
+// its parent is in the wave after its parent's, so that it refers to a
+// parent the remote has, under its remote UID. The groups of a wave may be
+// pushed concurrently.
+func (sm *SyncManager) pushWaves(operations []sqlite.SyncOperation) [][][]sqlite.SyncOperation {
+	var order []string
+	byTask := make(map[string][]sqlite.SyncOperation)
+	for _, op := range operations {
+		if _, ok := byTask[op.TaskUID]; !ok {
+			order = append(order, op.TaskUID)
+		}
+		byTask[op.TaskUID] = append(byTask[op.TaskUID], op)
+	}
+
+	// The parents of the tasks created that are created too
+	parents := make(map[string]string)
+	for _, uid := range order {
+		op := byTask[uid][0]
+		if op.Operation != "create" {
+			continue
+		}
+		task, err := sm.local.GetTask(op.ListID, op.TaskUID)
+		if err != nil || task.ParentUID == "" {
+			continue
+		}
+		if parentOps := byTask[task.ParentUID]; len(parentOps) > 0 && parentOps[0].Operation == "create" {
+			parents[uid] = task.ParentUID
+		}
+	}
+
+	depths := make(map[string]int)
+	var depth func(uid string, ancestors int) int
+	depth = func(uid string, ancestors int) int {
+		if d, ok := depths[uid]; ok {
+			return d
+		}
+		d := 0
+		// A parent cycle, which only a corrupt cache has, stops at the
+		// number of tasks
+		if parent, ok := parents[uid]; ok && ancestors < len(order) {
+			d = depth(parent, ancestors+1) + 1
+		}
+		depths[uid] = d
+		return d
+	}
+
+	var waves [][][]sqlite.SyncOperation
+	for _, uid := range order {
+		d := depth(uid, 0)
+		for len(waves) <= d {
+			waves = append(waves, nil)
+		}
+		waves[d] = append(waves[d], byTask[uid])
+	}
+	return waves
+}
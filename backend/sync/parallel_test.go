@@ -0,0 +1,306 @@
+package sync
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path"
+	"path/filepath"
+	"regexp"
+	gosync "sync"
+	"testing"
+	"time"
+
+	"gosynctasks/backend"
+	"gosynctasks/backend/nextcloud"
+	"gosynctasks/backend/sqlite"
+)
+
+// slowCalDAVServer stores the calendar objects PUT to it, answering every
+// request after a delay, and records the order in which objects were stored
+// and the most requests it served at once
+type slowCalDAVServer struct {
+	*httptest.Server
+	delay time.Duration
+
+	mu          gosync.Mutex
+	objects     map[string]string // Calendar data by object name
+	stored      []string          // Object names, in the order their PUT completed
+	inFlight    int
+	maxInFlight int
+}
+
+func newSlowCalDAVServer(t *testing.T, delay time.Duration) *slowCalDAVServer {
+	s := &slowCalDAVServer{delay: delay, objects: make(map[string]string)}
+	s.Server = httptest.NewTLSServer(http.HandlerFunc(s.serve))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *slowCalDAVServer) serve(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	s.inFlight++
+	s.maxInFlight = max(s.maxInFlight, s.inFlight)
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		s.inFlight--
+		s.mu.Unlock()
+	}()
+
+	time.Sleep(s.delay)
+	name := path.Base(r.URL.Path)
+	switch r.Method {
+	case http.MethodPut:
+		body, _ := io.ReadAll(r.Body)
+		s.mu.Lock()
+		s.objects[name] = string(body)
+		s.stored = append(s.stored, name)
+		s.mu.Unlock()
+		w.Header().Set("ETag", `"1"`)
+		w.WriteHeader(http.StatusCreated)
+	case http.MethodGet:
+		s.mu.Lock()
+		data, ok := s.objects[name]
+		s.mu.Unlock()
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("ETag", `"1"`)
+		_, _ = io.WriteString(w, data)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+// remote returns a Nextcloud backend talking to the server
+func (s *slowCalDAVServer) remote(t *testing.T) backend.TaskManager {
+	serverURL, _ := url.Parse(s.URL)
+	remote, err := nextcloud.NewNextcloudBackend(backend.ConnectorConfig{
+		URL:                &url.URL{Scheme: "nextcloud", User: url.UserPassword("user", "pass"), Host: serverURL.Host},
+		InsecureSkipVerify: true,
+		SuppressSSLWarning: true,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create the Nextcloud backend: %v", err)
+	}
+	return remote
+}
+
+// newTestCache returns an empty cache
+func newTestCache(t *testing.T) *sqlite.SQLiteBackend {
+	local, err := sqlite.NewSQLiteBackend(backend.BackendConfig{
+		Type:    "sqlite",
+		Enabled: true,
+		DBPath:  filepath.Join(t.TempDir(), "test.db"),
+	})
+	if err != nil {
+		t.Fatalf("Failed to create local backend: %v", err)
+	}
+	t.Cleanup(func() { local.Close() })
+	return local
+}
+
+// newParallelTestStore returns a cache with a list holding count queued
+// creates
+func newParallelTestStore(t *testing.T, count int) (*sqlite.SQLiteBackend, string) {
+	local := newTestCache(t)
+	listID, _ := local.CreateTaskList("Work", "", "")
+	for i := range count {
+		if _, err := local.AddTask(listID, backend.Task{Summary: fmt.Sprintf("Task %d", i), Status: "NEEDS-ACTION"}); err != nil {
+			t.Fatalf("AddTask failed: %v", err)
+		}
+	}
+	return local, listID
+}
+
+// TestPushConcurrently pushes the same queue one operation at a time and
+// with the default parallelism, against a server taking a while to answer
+func TestPushConcurrently(t *testing.T) {
+	const tasks = 16
+
+	push := func(parallelism int) (time.Duration, *slowCalDAVServer) {
+		server := newSlowCalDAVServer(t, 30*time.Millisecond)
+		local, _ := newParallelTestStore(t, tasks)
+		sm := NewSyncManager(local, server.remote(t), ServerWins)
+		sm.SetParallelism(parallelism)
+
+		start := time.Now()
+		result, err := sm.PushOnly()
+		elapsed := time.Since(start)
+		if err != nil || len(result.Errors) > 0 {
+			t.Fatalf("PushOnly(parallelism %d) failed: %v %v", parallelism, err, result.Errors)
+		}
+		if result.PushedTasks != tasks || len(server.stored) != tasks {
+			t.Errorf("Parallelism %d pushed %d tasks, server stored %d, want %d", parallelism, result.PushedTasks, len(server.stored), tasks)
+		}
+		if ops, _ := local.GetQueuedSyncOperations(); len(ops) != 0 {
+			t.Errorf("Parallelism %d left %d operations queued", parallelism, len(ops))
+		}
+		return elapsed, server
+	}
+
+	serial, serialServer := push(1)
+	parallel, parallelServer := push(0)
+	t.Logf("Pushed %d creates in %v one at a time, %v with parallelism %d", tasks, serial, parallel, DefaultParallelism)
+
+	if serialServer.maxInFlight != 1 {
+		t.Errorf("Parallelism 1 sent %d requests at once", serialServer.maxInFlight)
+	}
+	if parallelServer.maxInFlight < 2 || parallelServer.maxInFlight > DefaultParallelism {
+		t.Errorf("Default parallelism sent %d requests at once, want 2 to %d", parallelServer.maxInFlight, DefaultParallelism)
+	}
+	if parallel > serial*3/4 {
+		t.Errorf("Concurrent push took %v, want well under the %v of the serial one", parallel, serial)
+	}
+}
+
+// TestPushConcurrentlyCreatesParentsFirst tests that subtasks created along
+// with their parent are pushed once the parent is on the remote, referring to
+// the parent's remote UID
+func TestPushConcurrentlyCreatesParentsFirst(t *testing.T) {
+	server := newSlowCalDAVServer(t, 5*time.Millisecond)
+	local, listID := newParallelTestStore(t, 6)
+	parentUID, _ := local.AddTask(listID, backend.Task{Summary: "Parent", Status: "NEEDS-ACTION"})
+	childUID, _ := local.AddTask(listID, backend.Task{Summary: "Child", Status: "NEEDS-ACTION", ParentUID: parentUID})
+	local.AddTask(listID, backend.Task{Summary: "Grandchild", Status: "NEEDS-ACTION", ParentUID: childUID})
+
+	sm := NewSyncManager(local, server.remote(t), ServerWins)
+	result, err := sm.PushOnly()
+	if err != nil || len(result.Errors) > 0 || result.PushedTasks != 9 {
+		t.Fatalf("PushOnly pushed %d of 9 tasks: %v %v", result.PushedTasks, err, result.Errors)
+	}
+
+	relatedTo := regexp.MustCompile(`RELATED-TO[^:]*:(\S+)`)
+	uid := regexp.MustCompile(`(?m)^UID:(\S+)`)
+	position := make(map[string]int) // Order of storage, by remote UID
+	for i, name := range server.stored {
+		position[uid.FindStringSubmatch(server.objects[name])[1]] = i
+	}
+	for _, name := range server.stored {
+		data := server.objects[name]
+		match := relatedTo.FindStringSubmatch(data)
+		if match == nil {
+			continue
+		}
+		parent, ok := position[match[1]]
+		if !ok {
+			t.Errorf("%s refers to parent %s, which the remote does not have", name, match[1])
+		} else if parent > position[uid.FindStringSubmatch(data)[1]] {
+			t.Errorf("%s was stored before its parent %s", name, match[1])
+		}
+	}
+	if len(server.stored) != 9 {
+		t.Errorf("Server stored %d objects, want 9", len(server.stored))
+	}
+}
+
+// slowListRemote is a MockBackend whose lists may be read concurrently, each
+// read taking a while. It records the lists read and the most read at once.
+type slowListRemote struct {
+	*backend.MockBackend
+	delay time.Duration
+
+	mu          gosync.Mutex
+	reads       []string
+	inFlight    int
+	maxInFlight int
+}
+
+func (r *slowListRemote) ReadsConcurrently() bool {
+	return true
+}
+
+func (r *slowListRemote) GetTasks(listID string, filter *backend.TaskFilter) ([]backend.Task, error) {
+	r.mu.Lock()
+	r.reads = append(r.reads, listID)
+	r.inFlight++
+	r.maxInFlight = max(r.maxInFlight, r.inFlight)
+	r.mu.Unlock()
+	defer func() {
+		r.mu.Lock()
+		r.inFlight--
+		r.mu.Unlock()
+	}()
+
+	time.Sleep(r.delay)
+	return r.MockBackend.GetTasks(listID, filter)
+}
+
+// TestPullConcurrently tests that fetching lists concurrently caches the same
+// tasks as fetching them one at a time, and that lists with an unchanged
+// CTag are not fetched
+func TestPullConcurrently(t *testing.T) {
+	mock := backend.NewMockBackend()
+	for i := range 8 {
+		listID := fmt.Sprintf("list-%d", i)
+		mock.Lists = append(mock.Lists, backend.TaskList{ID: listID, Name: fmt.Sprintf("List %d", i), CTags: "ctag-1"})
+		mock.Tasks[listID] = []backend.Task{
+			{UID: listID + "-child", Summary: "Child", Status: "NEEDS-ACTION", ParentUID: listID + "-parent"},
+			{UID: listID + "-parent", Summary: "Parent", Status: "NEEDS-ACTION", Priority: i},
+			{UID: listID + "-done", Summary: "Done", Status: "COMPLETED"},
+		}
+	}
+
+	pull := func(parallelism int) (*sqlite.SQLiteBackend, *SyncManager, *slowListRemote, *SyncResult, time.Duration) {
+		remote := &slowListRemote{MockBackend: mock, delay: 60 * time.Millisecond}
+		local := newTestCache(t)
+		sm := NewSyncManager(local, remote, ServerWins)
+		sm.SetParallelism(parallelism)
+		start := time.Now()
+		result, err := sm.Sync()
+		if err != nil || len(result.Errors) > 0 {
+			t.Fatalf("Sync(parallelism %d) failed: %v %v", parallelism, err, result.Errors)
+		}
+		return local, sm, remote, result, time.Since(start)
+	}
+
+	serialCache, _, serialRemote, serialResult, serial := pull(1)
+	cache, sm, remote, result, parallel := pull(0)
+	t.Logf("Pulled %d lists in %v one at a time, %v with parallelism %d", len(mock.Lists), serial, parallel, DefaultParallelism)
+
+	if serialRemote.maxInFlight != 1 {
+		t.Errorf("Parallelism 1 read %d lists at once", serialRemote.maxInFlight)
+	}
+	if remote.maxInFlight < 2 || remote.maxInFlight > DefaultParallelism {
+		t.Errorf("Default parallelism read %d lists at once, want 2 to %d", remote.maxInFlight, DefaultParallelism)
+	}
+	if parallel > serial*3/4 {
+		t.Errorf("Concurrent pull took %v, want well under the %v of the serial one", parallel, serial)
+	}
+
+	if result.PulledTasks != serialResult.PulledTasks || len(result.ListResults) != len(serialResult.ListResults) {
+		t.Fatalf("Concurrent pull pulled %d tasks in %d lists, serial one %d in %d",
+			result.PulledTasks, len(result.ListResults), serialResult.PulledTasks, len(serialResult.ListResults))
+	}
+	for i := range result.ListResults {
+		if got, want := result.ListResults[i], serialResult.ListResults[i]; got.ListID != want.ListID || got.Pulled != want.Pulled {
+			t.Errorf("List result %d = %s pulled %d, serial one %s pulled %d", i, got.ListID, got.Pulled, want.ListID, want.Pulled)
+		}
+	}
+	for _, list := range mock.Lists {
+		got, _ := cache.GetTasks(list.ID, nil)
+		want, _ := serialCache.GetTasks(list.ID, nil)
+		if len(got) != 3 || len(got) != len(want) {
+			t.Fatalf("List %s has %d cached tasks, %d with a serial pull, want 3", list.ID, len(got), len(want))
+		}
+		for i := range got {
+			if got[i].UID != want[i].UID || got[i].Status != want[i].Status || got[i].ParentUID != want[i].ParentUID {
+				t.Errorf("List %s task %d = %+v, serial pull cached %+v", list.ID, i, got[i], want[i])
+			}
+		}
+	}
+
+	// Only the list whose CTag changed is fetched again
+	remote.reads = nil
+	mock.Lists[3].CTags = "ctag-2"
+	if _, err := sm.Sync(); err != nil {
+		t.Fatalf("Second sync failed: %v", err)
+	}
+	if len(remote.reads) != 1 || remote.reads[0] != "list-3" {
+		t.Errorf("Second sync read lists %v, want only list-3", remote.reads)
+	}
+}
@@ -0,0 +1,127 @@
+package sync
+
+import (
+	"fmt"
+
+	"gosynctasks/backend"
+	"gosynctasks/backend/sqlite"
+)
+
+// TaskChange is a remote change a pull would apply to the local cache
+type TaskChange struct {
+	ListName string
+	Kind     string       // "new", "update" or "delete"
+	Local    backend.Task // Zero for "new"
+	Remote   backend.Task // Zero for "delete"
+}
+
+// SyncPreview describes what Sync would do, without changing anything
+type SyncPreview struct {
+	Changes           []TaskChange
+	Conflicts         []Conflict
+	PendingOperations []sqlite.SyncOperation
+	Errors            []error
+}
+
+// Preview compares the remote with the local cache using the same rules as
+// Sync and reports the changes a sync would make
+func (sm *SyncManager) Preview() (*SyncPreview, error) {
+	preview := &SyncPreview{}
+
+	remoteLists, err := sm.remote.GetTaskLists()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get remote lists: %w", err)
+	}
+	localLists, err := sm.local.GetTaskLists()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get local lists: %w", err)
+	}
+	localCTags := make(map[string]string, len(localLists))
+	for _, list := range localLists {
+		localCTags[list.ID] = list.CTags
+	}
+
+	for _, remoteList := range remoteLists {
+		localCTag, listExists := localCTags[remoteList.ID]
+		if listExists && localCTag == remoteList.CTags {
+			continue
+		}
+
+		remoteTasks, err := sm.remote.GetTasks(remoteList.ID, &backend.TaskFilter{IncludeCompleted: true})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get remote tasks for list %s: %w", remoteList.ID, err)
+		}
+		inconsistent := backend.DuplicateUIDs(remoteTasks) != nil
+		if inconsistent {
+			remoteTasks = firstOccurrences(remoteTasks)
+			preview.Errors = append(preview.Errors, fmt.Errorf("list %s returned task UIDs more than once; deletions will not be detected", remoteList.Name))
+		}
+
+		var localTasks []backend.Task
+		if listExists {
+			localTasks, err = sm.local.GetTasks(remoteList.ID, nil)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get local tasks for list %s: %w", remoteList.ID, err)
+			}
+		}
+		localTaskMap := make(map[string]backend.Task, len(localTasks))
+		for _, task := range localTasks {
+			localTaskMap[task.UID] = task
+		}
+
+		for _, remoteTask := range remoteTasks {
+			localTask, exists := localTaskMap[remoteTask.UID]
+			delete(localTaskMap, remoteTask.UID)
+			if !exists {
+				preview.Changes = append(preview.Changes, TaskChange{ListName: remoteList.Name, Kind: "new", Remote: remoteTask})
+				continue
+			}
+
+			isLocallyModified, err := sm.local.IsLocallyModified(remoteTask.UID)
+			if err != nil {
+				return nil, err
+			}
+			isRemoteModified, err := sm.isTaskRemoteModified(localTask, remoteTask)
+			if err != nil {
+				return nil, err
+			}
+
+			switch {
+			case isLocallyModified && isRemoteModified && onlyClosingDiffers(localTask, remoteTask):
+				// Settled without a conflict, by taking the remote version
+				if FormatTaskDiff(localTask, remoteTask, "") != "" {
+					preview.Changes = append(preview.Changes, TaskChange{ListName: remoteList.Name, Kind: "update", Local: localTask, Remote: remoteTask})
+				}
+			case isLocallyModified && isRemoteModified:
+				preview.Conflicts = append(preview.Conflicts, Conflict{ListID: remoteList.ID, ListName: remoteList.Name, Local: localTask, Remote: remoteTask})
+			case isLocallyModified:
+				// Pushed in the push phase
+			case FormatTaskDiff(localTask, remoteTask, "") != "":
+				preview.Changes = append(preview.Changes, TaskChange{ListName: remoteList.Name, Kind: "update", Local: localTask, Remote: remoteTask})
+			}
+		}
+
+		if inconsistent {
+			continue
+		}
+		for _, localTask := range localTasks {
+			if _, deleted := localTaskMap[localTask.UID]; !deleted {
+				continue
+			}
+			isLocallyModified, err := sm.local.IsLocallyModified(localTask.UID)
+			if err != nil {
+				return nil, err
+			}
+			if !isLocallyModified {
+				preview.Changes = append(preview.Changes, TaskChange{ListName: remoteList.Name, Kind: "delete", Local: localTask})
+			}
+		}
+	}
+
+	preview.PendingOperations, err = sm.local.GetPendingSyncOperations()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pending operations: %w", err)
+	}
+
+	return preview, nil
+}
@@ -0,0 +1,80 @@
+package sync
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"gosynctasks/backend"
+	"gosynctasks/internal/utils"
+)
+
+// RemoteRewrite is a pushed task the remote stored differently from how it
+// was sent, in a field that matters
+type RemoteRewrite struct {
+	ListID string
+	Sent   backend.Task // As pushed, with the remote's status names
+	Remote backend.Task // As read back
+}
+
+// FormatRemoteRewrite renders a rewrite as a warning with the fields that differ
+func FormatRemoteRewrite(rewrite RemoteRewrite) string {
+	return fmt.Sprintf("⚠ The remote changed %q after it was pushed:\n%s",
+		utils.SanitizeLine(rewrite.Remote.Summary), FormatTaskDiff(rewrite.Sent, rewrite.Remote, "    "))
+}
+
+// readBack fetches each task just pushed and stores the remote's version in
+// the cache, so normalization done by the remote (line folding, property
+// order, trimmed titles) is not taken for a remote change on the next pull.
+// Tasks whose fields differ from what was sent in a way that matters are
+// returned. Read-back problems are only logged: the next pull catches up
+// with the remote anyway. Tasks are fetched as many at once as pushed.
+func (sm *SyncManager) readBack(sent map[string][]backend.Task) []RemoteRewrite {
+	var pushed []RemoteRewrite
+	for listID, tasks := range sent {
+		for _, sentTask := range tasks {
+			pushed = append(pushed, RemoteRewrite{ListID: listID, Sent: sentTask})
+		}
+	}
+
+	var mu sync.Mutex
+	var rewrites []RemoteRewrite
+	forEachConcurrently(pushed, sm.pushWorkers(), func(task RemoteRewrite) {
+		listID, sentTask := task.ListID, task.Sent
+		remoteTask, err := sm.remote.GetTask(listID, sentTask.UID)
+		if err != nil {
+			utils.Debugf("[SYNC] read-back of task %s in list %s failed: %v", sentTask.UID, listID, err)
+			return
+		}
+		if rewritten(sentTask, *remoteTask) {
+			mu.Lock()
+			rewrites = append(rewrites, RemoteRewrite{ListID: listID, Sent: sentTask, Remote: *remoteTask})
+			mu.Unlock()
+		}
+
+		// A change made while pushing is newer than both; leave it queued
+		modified, err := sm.local.IsLocallyModified(sentTask.UID)
+		if err != nil || modified {
+			return
+		}
+		remoteTask.Status = sm.toLocalStatus(remoteTask.Status)
+		if err := sm.local.UpdateSyncedTask(listID, *remoteTask); err != nil {
+			utils.Debugf("[SYNC] read-back: failed to cache task %s: %v", sentTask.UID, err)
+		}
+	})
+	return rewrites
+}
+
+// rewritten reports whether the remote's version of a task differs from the
+// one sent in a field that matters. Whitespace around texts and the order
+// of categories are not compared, nor are timestamps the remote maintains.
+func rewritten(sent, remote backend.Task) bool {
+	normalize := func(task backend.Task) backend.Task {
+		task.Summary = strings.TrimSpace(task.Summary)
+		task.Description = strings.TrimSpace(task.Description)
+		task.Categories = slices.Sorted(slices.Values(task.Categories))
+		return task
+	}
+	return FormatTaskDiff(normalize(sent), normalize(remote), "") != ""
+}
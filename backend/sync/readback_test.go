@@ -0,0 +1,85 @@
+package sync
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"gosynctasks/backend"
+)
+
+// normalizingRemote stores summaries trimmed and uppercased, stamping its
+// own modification time, like a server that rewrites what it is sent
+type normalizingRemote struct {
+	*backend.MockBackend
+}
+
+func (r normalizingRemote) normalize(task backend.Task) backend.Task {
+	task.Summary = strings.ToUpper(strings.TrimSpace(task.Summary))
+	task.Modified = time.Now().Add(time.Hour).Truncate(time.Second)
+	return task
+}
+
+func (r normalizingRemote) AddTask(listID string, task backend.Task) (string, error) {
+	return r.MockBackend.AddTask(listID, r.normalize(task))
+}
+
+func (r normalizingRemote) UpdateTask(listID string, task backend.Task) error {
+	return r.MockBackend.UpdateTask(listID, r.normalize(task))
+}
+
+// TestPushReadBack tests that a remote rewriting a pushed summary is warned
+// about once, and that the rewritten version does not conflict with the
+// next local change
+func TestPushReadBack(t *testing.T) {
+	sm, local, mock, cleanup := createTestSyncManager(t, ServerWins)
+	defer cleanup()
+	sm.remote = normalizingRemote{mock}
+
+	listID, _ := local.CreateTaskList("Errands", "", "")
+	mock.Lists = append(mock.Lists, backend.TaskList{ID: listID, Name: "Errands", CTags: "1"})
+	mock.Tasks[listID] = []backend.Task{}
+
+	uid, _ := local.AddTask(listID, backend.Task{Summary: "buy milk", Status: "NEEDS-ACTION"})
+	_, _ = local.AddTask(listID, backend.Task{Summary: "  CALL MOM ", Status: "NEEDS-ACTION"}) // Only trimmed
+
+	result, err := sm.Sync()
+	if err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+	if len(result.Rewrites) != 1 || result.Rewrites[0].Sent.Summary != "buy milk" || result.Rewrites[0].Remote.Summary != "BUY MILK" {
+		t.Fatalf("Rewrites = %+v, want one for the uppercased summary", result.Rewrites)
+	}
+	if warning := FormatRemoteRewrite(result.Rewrites[0]); !strings.Contains(warning, "summary") {
+		t.Errorf("warning does not name the field:\n%s", warning)
+	}
+
+	tasks, _ := local.GetTasks(listID, nil)
+	var cached backend.Task
+	for _, task := range tasks {
+		if task.UID == uid {
+			cached = task
+		}
+	}
+	if cached.Summary != "BUY MILK" {
+		t.Fatalf("cached summary = %q, want the remote's version", cached.Summary)
+	}
+
+	// A local change, then a sync that pulls the list again
+	cached.Description = "oat"
+	if err := local.UpdateTask(listID, cached); err != nil {
+		t.Fatal(err)
+	}
+	mock.Lists[0].CTags = "2"
+
+	result, err = sm.Sync()
+	if err != nil {
+		t.Fatalf("second Sync() error = %v", err)
+	}
+	if result.ConflictsFound != 0 || len(result.Rewrites) != 0 {
+		t.Errorf("second sync found %d conflicts and %d rewrites, want none", result.ConflictsFound, len(result.Rewrites))
+	}
+	if result.PushedTasks != 1 {
+		t.Errorf("second sync pushed %d tasks, want the local change", result.PushedTasks)
+	}
+}
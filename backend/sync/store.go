@@ -0,0 +1,57 @@
+package sync
+
+import (
+	"time"
+
+	"gosynctasks/backend"
+	"gosynctasks/backend/sqlite"
+)
+
+// LocalStore is the local cache the sync manager reads from and applies
+// remote changes to. *sqlite.SQLiteBackend implements it.
+type LocalStore interface {
+	GetTaskLists() ([]backend.TaskList, error)
+	GetTasks(listID string, filter *backend.TaskFilter) ([]backend.Task, error)
+	GetTask(listID, taskUID string) (*backend.Task, error)
+
+	// AddTask adds a task as a local change, queued for push
+	AddTask(listID string, task backend.Task) (string, error)
+
+	// Sync queue and per-task sync state
+	GetPendingSyncOperations() ([]sqlite.SyncOperation, error)
+	RecordSyncFailure(operationID int, message string, nextRetry time.Time) error
+	ClearSyncFlagsAndQueue(taskUID string) error
+	StrandSyncOperations(listID, message string) (int, error)
+	MarkLocallyModified(taskUID string) error
+	UpdateSyncMetadata(taskUID, listID, etag string, remoteModifiedAt time.Time) error
+	IsLocallyModified(taskUID string) (bool, error)
+	GetRemoteModifiedAt(taskUID string) (*time.Time, error)
+	GetRemoteETag(taskUID string) (string, error)
+
+	// Lists pulled from the remote
+	CreateSyncedList(list backend.TaskList) error
+	UpdateListInfo(list backend.TaskList) error
+	SetListCTag(listID, ctag string) error
+	ClearListCTags() error
+	GetListSyncToken(listID string) (string, error)
+	SetListSyncToken(listID, token string) error
+
+	// Lists archived on the remote, left out of GetTaskLists
+	GetArchivedTaskLists() ([]backend.TaskList, error)
+	SetListArchived(listID string, archived bool) error
+
+	// Tasks pulled from the remote; these never queue sync operations
+	InsertSyncedTask(listID string, task backend.Task) error
+	BulkInsertTasks(listID string, tasks []backend.Task) error
+	UpdateSyncedTask(listID string, task backend.Task) error
+	DeleteSyncedTask(listID, taskUID string) error
+	UpdateTaskUID(listID, oldUID, newUID string) error
+
+	// Completed tasks pruned from the cache by the retention policy
+	PrunedTasks(listID string) (map[string]time.Time, error)
+	ForgetPrunedTasks(listID string, uids []string) error
+
+	GetStats() (sqlite.DatabaseStats, error)
+}
+
+var _ LocalStore = (*sqlite.SQLiteBackend)(nil)
@@ -0,0 +1,112 @@
+package sync
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"gosynctasks/backend"
+	"gosynctasks/backend/sqlite"
+)
+
+// StrandedList is a list deleted on the remote while local changes to its
+// tasks were queued. Its creates and updates are no longer retried until
+// they are moved to another list with 'gosynctasks sync rescue'.
+type StrandedList struct {
+	ListID string
+	Name   string
+	Remote string // Type of the remote backend
+	Tasks  int    // Tasks whose changes are stranded
+}
+
+// String returns the warning for the list, with the command to rescue it
+func (s StrandedList) String() string {
+	name := s.Name
+	if strings.ContainsAny(name, " \t'\"") {
+		name = strconv.Quote(name)
+	}
+	return fmt.Sprintf("list '%s' no longer exists on %s — %d local tasks are stranded; run `gosynctasks sync rescue %s` to move them to another list",
+		s.Name, s.Remote, s.Tasks, name)
+}
+
+// strandedMessage is the error stored with stranded operations
+const strandedMessage = "list no longer exists on the remote"
+
+// isNotFound reports whether err is, or wraps, a not found BackendError
+func isNotFound(err error) bool {
+	var backendErr *backend.BackendError
+	return errors.As(err, &backendErr) && backendErr.IsNotFound()
+}
+
+// listGone reports whether a create or update failed because its list was
+// deleted on the remote, rather than its task. The list is probed only
+// after a not found error.
+func (sm *SyncManager) listGone(op sqlite.SyncOperation, pushErr error) bool {
+	if op.Operation == "delete" || !isNotFound(pushErr) {
+		return false
+	}
+	exists, err := sm.remoteListExists(op.ListID)
+	return err == nil && !exists
+}
+
+// remoteListExists reports whether a list is still on the remote, with a
+// CTag request when the remote is a backend.CTagProber. An archived list
+// still exists.
+func (sm *SyncManager) remoteListExists(listID string) (bool, error) {
+	if prober, ok := backend.As[backend.CTagProber](sm.remote); ok {
+		_, err := prober.GetListCTag(listID)
+		if isNotFound(err) {
+			return sm.remoteListArchived(listID)
+		}
+		return err == nil, err
+	}
+
+	lists, err := sm.remote.GetTaskLists()
+	if err != nil {
+		return false, err
+	}
+	if slices.ContainsFunc(lists, func(list backend.TaskList) bool { return list.ID == listID }) {
+		return true, nil
+	}
+	return sm.remoteListArchived(listID)
+}
+
+// strandLists marks the queued changes of the lists found gone as stranded
+// and returns every stranded list, with those stranded by earlier syncs,
+// whose operations are counted in earlier
+func (sm *SyncManager) strandLists(gone []string, earlier map[string]int) ([]StrandedList, error) {
+	counts := make(map[string]int)
+	var listIDs []string
+	for listID, count := range earlier {
+		counts[listID] = count
+		listIDs = append(listIDs, listID)
+	}
+	slices.Sort(listIDs)
+	for _, listID := range gone {
+		count, err := sm.local.StrandSyncOperations(listID, strandedMessage)
+		if err != nil {
+			return nil, fmt.Errorf("failed to stop retrying changes of list %s: %w", listID, err)
+		}
+		counts[listID] = count
+		listIDs = append(listIDs, listID)
+	}
+	if len(listIDs) == 0 {
+		return nil, nil
+	}
+
+	localLists, err := sm.local.GetTaskLists()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get local lists: %w", err)
+	}
+	stranded := make([]StrandedList, 0, len(listIDs))
+	for _, listID := range listIDs {
+		list := StrandedList{ListID: listID, Name: listID, Remote: sm.remote.GetBackendType(), Tasks: counts[listID]}
+		if i := slices.IndexFunc(localLists, func(l backend.TaskList) bool { return l.ID == listID }); i >= 0 {
+			list.Name = localLists[i].QualifiedName()
+		}
+		stranded = append(stranded, list)
+	}
+	return stranded, nil
+}
@@ -0,0 +1,136 @@
+package sync
+
+import (
+	"strings"
+	"testing"
+
+	"gosynctasks/backend"
+)
+
+// deletedListRemote is a MockBackend answering writes to lists that are not
+// in its Lists with 404, as a server does once a list is deleted
+type deletedListRemote struct {
+	*backend.MockBackend
+	addCalls int
+}
+
+func (r *deletedListRemote) hasList(listID string) bool {
+	for _, list := range r.Lists {
+		if list.ID == listID {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *deletedListRemote) AddTask(listID string, task backend.Task) (string, error) {
+	r.addCalls++
+	if !r.hasList(listID) {
+		return "", backend.NewBackendError("AddTask", 404, "Not Found")
+	}
+	return r.MockBackend.AddTask(listID, task)
+}
+
+func TestStrandedList(t *testing.T) {
+	_, local, mock, cleanup := createTestSyncManager(t, ServerWins)
+	defer cleanup()
+	remote := &deletedListRemote{MockBackend: mock}
+	sm := NewSyncManager(local, remote, ServerWins)
+
+	mock.Lists = []backend.TaskList{
+		{ID: "work", Name: "Work", CTags: "ctag-work"},
+		{ID: "home", Name: "Home", CTags: "ctag-home"},
+	}
+	if _, err := sm.Sync(); err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+	for _, summary := range []string{"Report", "Slides"} {
+		if _, err := local.AddTask("work", backend.Task{Summary: summary, Status: "NEEDS-ACTION"}); err != nil {
+			t.Fatalf("AddTask() error = %v", err)
+		}
+	}
+	if _, err := local.AddTask("home", backend.Task{Summary: "Groceries", Status: "NEEDS-ACTION"}); err != nil {
+		t.Fatalf("AddTask() error = %v", err)
+	}
+
+	// Work is deleted on the server while its creates are queued
+	mock.Lists = mock.Lists[1:]
+	result, err := sm.Sync()
+	if err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+	if result.PushedTasks != 1 || len(mock.Tasks["home"]) != 1 {
+		t.Errorf("pushed %d tasks, want the one of the list still on the server", result.PushedTasks)
+	}
+	if len(result.Stranded) != 1 || result.Stranded[0].ListID != "work" || result.Stranded[0].Tasks != 2 {
+		t.Fatalf("Stranded = %+v, want the 2 tasks of work", result.Stranded)
+	}
+	message := result.Stranded[0].String()
+	for _, want := range []string{"list 'Work' no longer exists on mock", "2 local tasks are stranded", "gosynctasks sync rescue Work"} {
+		if !strings.Contains(message, want) {
+			t.Errorf("message %q does not contain %q", message, want)
+		}
+	}
+	if remote.addCalls != 2 {
+		t.Errorf("AddTask called %d times, want the list given up after its first 404", remote.addCalls)
+	}
+
+	ops, err := local.GetPendingSyncOperations()
+	if err != nil {
+		t.Fatalf("GetPendingSyncOperations() error = %v", err)
+	}
+	for _, op := range ops {
+		if !op.Stranded || op.RetryCount != 0 {
+			t.Errorf("operation %+v should be stranded without using up retries", op)
+		}
+	}
+
+	// Later syncs keep reporting the list without retrying it
+	remote.addCalls = 0
+	result, err = sm.Sync()
+	if err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+	if remote.addCalls != 0 || len(result.Stranded) != 1 || result.Stranded[0].Tasks != 2 {
+		t.Errorf("second sync: %d AddTask calls, Stranded = %+v", remote.addCalls, result.Stranded)
+	}
+
+	// Rescued tasks are pushed to their new list
+	rescued, err := local.RescueStrandedTasks("work", "home")
+	if err != nil || rescued != 2 {
+		t.Fatalf("RescueStrandedTasks() = %d, %v, want 2", rescued, err)
+	}
+	result, err = sm.Sync()
+	if err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+	if result.PushedTasks != 2 || len(result.Stranded) != 0 || len(mock.Tasks["home"]) != 3 {
+		t.Errorf("after rescue: pushed %d, Stranded = %+v, %d tasks in home", result.PushedTasks, result.Stranded, len(mock.Tasks["home"]))
+	}
+}
+
+func TestMissingTaskIsNotStranded(t *testing.T) {
+	sm, local, mock, cleanup := createTestSyncManager(t, ServerWins)
+	defer cleanup()
+
+	mock.Lists = []backend.TaskList{{ID: "work", Name: "Work", CTags: "ctag-work"}}
+	mock.AddTaskErr = backend.NewBackendError("AddTask", 404, "Not Found")
+	if _, err := sm.Sync(); err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+	if _, err := local.AddTask("work", backend.Task{Summary: "Report", Status: "NEEDS-ACTION"}); err != nil {
+		t.Fatalf("AddTask() error = %v", err)
+	}
+
+	result, err := sm.Sync()
+	if err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+	if len(result.Stranded) != 0 {
+		t.Errorf("Stranded = %+v, want none while the list exists", result.Stranded)
+	}
+	ops, _ := local.GetQueuedSyncOperations()
+	if len(ops) != 1 || ops[0].Stranded || ops[0].RetryCount != 1 {
+		t.Errorf("operations = %+v, want one failed and retried as usual", ops)
+	}
+}
@@ -0,0 +1,146 @@
+package sync
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"gosynctasks/backend"
+)
+
+// Default polling intervals of a ChangeDetector
+const (
+	DefaultMinWatchInterval = 15 * time.Second
+	DefaultMaxWatchInterval = 5 * time.Minute
+)
+
+// WatchStats counts the work of a ChangeDetector
+type WatchStats struct {
+	Probes         int // CTag requests made
+	Changes        int // Lists found changed, each needing a full fetch
+	FetchesAvoided int // Lists found unchanged, whose full fetch was skipped
+}
+
+// ChangeDetector watches lists of a remote for changes by polling their
+// CTags, with a CTag-only request when the backend is a backend.CTagProber
+// and one GetTaskLists call otherwise. The polling interval doubles while
+// nothing changes, up to MaxInterval, and drops back to MinInterval after a
+// change.
+type ChangeDetector struct {
+	remote  backend.TaskManager
+	listIDs []string
+	ctags   map[string]string
+
+	MinInterval time.Duration
+	MaxInterval time.Duration
+	interval    time.Duration
+
+	Stats WatchStats
+}
+
+// NewChangeDetector returns a detector for the given lists of remote, or of
+// every list when listIDs is empty
+func NewChangeDetector(remote backend.TaskManager, listIDs []string) *ChangeDetector {
+	return &ChangeDetector{
+		remote:      remote,
+		listIDs:     listIDs,
+		ctags:       make(map[string]string),
+		MinInterval: DefaultMinWatchInterval,
+		MaxInterval: DefaultMaxWatchInterval,
+	}
+}
+
+// Check polls the CTags once and returns the lists that changed since the
+// last check. On the first check every list counts as changed. A list
+// without a CTag always counts as changed, as there is nothing to compare.
+func (d *ChangeDetector) Check() ([]string, error) {
+	listIDs, current, err := d.probe()
+	if err != nil {
+		d.backOff()
+		return nil, err
+	}
+
+	var changed []string
+	for _, listID := range listIDs {
+		ctag := current[listID]
+		previous, seen := d.ctags[listID]
+		if seen && ctag != "" && ctag == previous {
+			d.Stats.FetchesAvoided++
+			continue
+		}
+		d.ctags[listID] = ctag
+		changed = append(changed, listID)
+	}
+	d.Stats.Changes += len(changed)
+
+	if len(changed) > 0 {
+		d.interval = d.MinInterval
+	} else {
+		d.backOff()
+	}
+	return changed, nil
+}
+
+// Interval returns how long to wait before the next check
+func (d *ChangeDetector) Interval() time.Duration {
+	if d.interval == 0 {
+		return d.MinInterval
+	}
+	return d.interval
+}
+
+// backOff doubles the interval, up to MaxInterval
+func (d *ChangeDetector) backOff() {
+	d.interval = min(d.Interval()*2, d.MaxInterval)
+}
+
+// probe returns the watched lists, in a stable order, with their current CTags
+func (d *ChangeDetector) probe() ([]string, map[string]string, error) {
+	ctags := make(map[string]string)
+	if prober, ok := backend.As[backend.CTagProber](d.remote); ok && len(d.listIDs) > 0 {
+		for _, listID := range d.listIDs {
+			d.Stats.Probes++
+			ctag, err := prober.GetListCTag(listID)
+			if err != nil {
+				return nil, nil, fmt.Errorf("failed to check list %s: %w", listID, err)
+			}
+			ctags[listID] = ctag
+		}
+		return d.listIDs, ctags, nil
+	}
+
+	d.Stats.Probes++
+	lists, err := d.remote.GetTaskLists()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to check task lists: %w", err)
+	}
+	var listIDs []string
+	for _, list := range lists {
+		if len(d.listIDs) == 0 || slices.Contains(d.listIDs, list.ID) {
+			listIDs = append(listIDs, list.ID)
+			ctags[list.ID] = list.CTags
+		}
+	}
+	return listIDs, ctags, nil
+}
+
+// Watch checks for changes until ctx is done, calling onChange with the
+// lists that changed. Failed checks are retried after the backed-off
+// interval; an error from onChange stops the watch.
+func (d *ChangeDetector) Watch(ctx context.Context, onChange func(listIDs []string) error) error {
+	for {
+		changed, err := d.Check()
+		if err == nil && len(changed) > 0 {
+			if err := onChange(changed); err != nil {
+				return err
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(d.Interval()):
+		}
+	}
+}
@@ -0,0 +1,116 @@
+package sync
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"gosynctasks/backend"
+)
+
+// probingRemote serves list CTags through GetListCTag and counts full list reads
+type probingRemote struct {
+	*backend.MockBackend
+	listReads int
+}
+
+func (r *probingRemote) GetTaskLists() ([]backend.TaskList, error) {
+	r.listReads++
+	return r.MockBackend.GetTaskLists()
+}
+
+func (r *probingRemote) GetListCTag(listID string) (string, error) {
+	for _, list := range r.Lists {
+		if list.ID == listID {
+			return list.CTags, nil
+		}
+	}
+	return "", backend.NewBackendError("GetListCTag", 404, "task list not found")
+}
+
+// TestChangeDetector tests that unchanged lists are skipped while the interval
+// backs off, and that a change is reported and tightens it again
+func TestChangeDetector(t *testing.T) {
+	remote := &probingRemote{MockBackend: backend.NewMockBackend()}
+	remote.Lists = []backend.TaskList{{ID: "work", CTags: "1"}, {ID: "home", CTags: "1"}}
+	d := NewChangeDetector(remote, []string{"work", "home"})
+	d.MinInterval, d.MaxInterval = time.Second, 4*time.Second
+
+	check := func(want ...string) {
+		t.Helper()
+		changed, err := d.Check()
+		if err != nil {
+			t.Fatalf("Check() error = %v", err)
+		}
+		if !slices.Equal(changed, want) {
+			t.Fatalf("Check() = %v, want %v", changed, want)
+		}
+	}
+
+	check("work", "home") // First check fetches everything
+	if d.Interval() != time.Second {
+		t.Errorf("interval after a change = %v, want 1s", d.Interval())
+	}
+	check()
+	check()
+	check()
+	if d.Interval() != 4*time.Second {
+		t.Errorf("interval after three quiet checks = %v, want the 4s maximum", d.Interval())
+	}
+
+	remote.Lists[1].CTags = "2"
+	check("home")
+	if d.Interval() != time.Second {
+		t.Errorf("interval after a change = %v, want 1s", d.Interval())
+	}
+
+	if want := (WatchStats{Probes: 10, Changes: 3, FetchesAvoided: 7}); d.Stats != want {
+		t.Errorf("Stats = %+v, want %+v", d.Stats, want)
+	}
+	if remote.listReads != 0 {
+		t.Errorf("GetTaskLists called %d times, want only CTag probes", remote.listReads)
+	}
+}
+
+// TestChangeDetectorWithoutProber tests the fallback to one GetTaskLists per check
+func TestChangeDetectorWithoutProber(t *testing.T) {
+	remote := backend.NewMockBackend()
+	remote.Lists = []backend.TaskList{{ID: "work", CTags: "1"}, {ID: "home", CTags: "1"}}
+	d := NewChangeDetector(remote, nil)
+
+	if changed, _ := d.Check(); len(changed) != 2 {
+		t.Fatalf("first Check() = %v, want both lists", changed)
+	}
+	remote.Lists[0].CTags = "2"
+	if changed, _ := d.Check(); !slices.Equal(changed, []string{"work"}) {
+		t.Errorf("Check() = %v, want [work]", changed)
+	}
+	if d.Stats.Probes != 2 || d.Stats.FetchesAvoided != 1 {
+		t.Errorf("Stats = %+v, want 2 probes and 1 avoided fetch", d.Stats)
+	}
+}
+
+// TestChangeDetectorWatch tests that Watch reports changes and stops with its context
+func TestChangeDetectorWatch(t *testing.T) {
+	remote := &probingRemote{MockBackend: backend.NewMockBackend()}
+	remote.Lists = []backend.TaskList{{ID: "work", CTags: "1"}}
+	d := NewChangeDetector(remote, []string{"work"})
+	d.MinInterval, d.MaxInterval = time.Millisecond, 2*time.Millisecond
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var calls int
+	err := d.Watch(ctx, func(listIDs []string) error {
+		calls++
+		if calls == 1 {
+			remote.Lists[0].CTags = "2"
+			return nil
+		}
+		cancel()
+		return nil
+	})
+	if !errors.Is(err, context.Canceled) || calls != 2 {
+		t.Errorf("Watch() = %v after %d changes, want context.Canceled after 2", err, calls)
+	}
+}
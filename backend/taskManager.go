@@ -3,9 +3,13 @@ package backend
 import (
 	"fmt"
 	"net/url"
+	"reflect"
+	"slices"
 	"strings"
 	"time"
 
+	"gosynctasks/internal/utils"
+
 	"gopkg.in/yaml.v3"
 )
 
@@ -35,11 +39,12 @@ type BackendSyncConfig struct {
 	Enabled bool `yaml:"enabled"` // Set to false to opt-out of automatic caching
 }
 
-// IsRemoteBackend returns true if this backend is a remote backend (nextcloud, todoist).
+// IsRemoteBackend returns true if this backend is a remote backend (nextcloud, caldav, todoist).
 // Remote backends are automatically cached when global sync is enabled.
 func (bc *BackendConfig) IsRemoteBackend() bool {
 	remoteTypes := map[string]bool{
 		"nextcloud": true,
+		"caldav":    true,
 		"todoist":   true,
 	}
 	return remoteTypes[bc.Type]
@@ -51,16 +56,23 @@ func (bc *BackendConfig) IsLocalBackend() bool {
 	return !bc.IsRemoteBackend()
 }
 
+// SyncOptedIn returns true if this is a git or file backend that opted in with
+// sync: {enabled: true} to be synced with the cache like a remote backend.
+func (bc *BackendConfig) SyncOptedIn() bool {
+	return (bc.Type == "git" || bc.Type == "file") && bc.Sync != nil && bc.Sync.Enabled
+}
+
 // ShouldBeCached returns true if this backend should be cached based on global sync settings.
 // Remote backends are auto-cached when global sync is enabled, unless they opt-out with sync: false.
+// Git and file backends are cached only when they opt in.
 func (bc *BackendConfig) ShouldBeCached(globalSyncEnabled bool) bool {
 	if !globalSyncEnabled {
 		return false
 	}
 
-	// Only remote backends are auto-cached
+	// Local backends are cached only when they opt in
 	if !bc.IsRemoteBackend() {
-		return false
+		return bc.SyncOptedIn()
 	}
 
 	// Check if backend explicitly opts out
@@ -73,12 +85,12 @@ func (bc *BackendConfig) ShouldBeCached(globalSyncEnabled bool) bool {
 }
 
 // BackendConfig represents configuration for a single backend in the multi-backend system.
-// Each backend has a type (nextcloud, git, file, sqlite, todoist) and type-specific configuration.
+// Each backend has a type (nextcloud, caldav, git, file, sqlite, todoist, deck, github) and type-specific configuration.
 type BackendConfig struct {
 	Name                string              `yaml:"-"`                               // Backend name (set during config loading from map key)
-	Type                string              `yaml:"type" validate:"required,oneof=nextcloud git file sqlite todoist"`
+	Type                string              `yaml:"type" validate:"required,oneof=nextcloud caldav git file sqlite todoist deck github"`
 	Enabled             bool                `yaml:"enabled"`
-	URL                 string              `yaml:"url,omitempty"`                   // Used by: nextcloud, file
+	URL                 string              `yaml:"url,omitempty"`                   // Used by: nextcloud, caldav, file, github (GitHub Enterprise API URL)
 	Host                string              `yaml:"host,omitempty"`                  // Alternative to URL (used with credentials from keyring/env)
 	Username            string              `yaml:"username,omitempty"`              // Username hint for keyring/env credential lookup
 	InsecureSkipVerify  bool                `yaml:"insecure_skip_verify,omitempty"`  // Used by: nextcloud
@@ -90,7 +102,11 @@ type BackendConfig struct {
 	FallbackFiles       []string            `yaml:"fallback_files,omitempty"`        // Used by: git
 	AutoCommit          bool                `yaml:"auto_commit,omitempty"`           // Used by: git
 	DBPath              string              `yaml:"db_path,omitempty"`               // Used by: sqlite
-	APIToken            string              `yaml:"api_token,omitempty"`             // Used by: todoist (can also be stored in keyring)
+	APIToken            string              `yaml:"api_token,omitempty"`             // Used by: todoist, github (can also be stored in keyring)
+	CredentialsFrom     string              `yaml:"credentials_from,omitempty"`      // Used by: deck (backend whose credentials to reuse, e.g. "nextcloud")
+	DeckDoneStack       string              `yaml:"deck_done_stack,omitempty"`       // Used by: deck (stack of done cards, default "Done")
+	DeckStackLists      bool                `yaml:"deck_stack_lists,omitempty"`      // Used by: deck (one list per stack, "Board/Stack", instead of per board)
+	GitHubRepos         []string            `yaml:"github_repos,omitempty"`          // Used by: github ("owner/repo", or "owner/repo:label" for the issues having a label; all the user's repositories when empty)
 	Sync                *BackendSyncConfig  `yaml:"sync,omitempty"`                  // Per-backend sync configuration
 }
 
@@ -125,6 +141,10 @@ func (c *ConnectorConfig) UnmarshalYAML(value *yaml.Node) error {
 	return nil
 }
 
+// TaskManager creates a TaskManager from the connector URL scheme.
+//
+// Deprecated: Legacy connector configs are converted to BackendConfig at load time;
+// use BackendConfig.TaskManager. ConnectorConfig.TaskManager will be removed in v2.0.
 func (c *ConnectorConfig) TaskManager() (TaskManager, error) {
 	constructor, err := GetSchemeConstructor(c.URL.Scheme)
 	if err != nil {
@@ -168,10 +188,16 @@ type TaskManager interface {
 	GetTasks(listID string, taskFilter *TaskFilter) ([]Task, error)
 
 	// FindTasksBySummary searches for tasks by summary text (case-insensitive).
-	// Returns all tasks with summaries that contain the search string (exact and partial matches).
+	// Returns all tasks with summaries that contain the search string (exact and partial matches),
+	// exact matches first.
 	// This is used for interactive task selection in update/delete operations.
 	FindTasksBySummary(listID string, summary string) ([]Task, error)
 
+	// GetTask retrieves one task of the specified list by UID, completed or not.
+	// Returns a BackendError with IsNotFound() == true if the list has no such task.
+	// Backends that cannot fetch a single task implement it with ScanForTask.
+	GetTask(listID string, taskUID string) (*Task, error)
+
 	// AddTask creates a new task in the specified list.
 	// The task.UID may be generated by the backend if not provided.
 	// Returns the UID of the created task (which may differ from task.UID for remote backends).
@@ -242,7 +268,7 @@ type TaskManager interface {
 	GetBackendDisplayName() string
 
 	// GetBackendType returns the backend type identifier.
-	// Returns one of: "nextcloud", "git", "sqlite", "file"
+	// Returns one of: "nextcloud", "caldav", "git", "sqlite", "file"
 	GetBackendType() string
 
 	// GetBackendContext returns contextual details specific to the backend.
@@ -268,6 +294,211 @@ type DetectableBackend interface {
 	DetectionInfo() string
 }
 
+// SummaryAlias is a former summary of a task, recorded when the task was renamed.
+type SummaryAlias struct {
+	Summary   string
+	ChangedAt time.Time
+}
+
+// SummaryHistoryProvider is implemented by backends that remember former task
+// summaries, so lookups by name keep working after a rename.
+type SummaryHistoryProvider interface {
+	// GetSummaryHistory returns former summaries of a task, most recent first.
+	GetSummaryHistory(taskUID string) ([]SummaryAlias, error)
+}
+
+// ForceDeleter is implemented by backends whose DeleteTask performs safety
+// checks on the remote resource. ForceDeleteTask skips those checks.
+type ForceDeleter interface {
+	ForceDeleteTask(listID string, taskUID string) error
+}
+
+// RequestDescriber is implemented by backends that talk to a server, so the
+// requests a write makes can be shown before it is made (--explain).
+type RequestDescriber interface {
+	// DescribeRequests returns the requests, as "METHOD URL", that the named
+	// write method (AddTask, UpdateTask, DeleteTask or ForceDeleteTask)
+	// makes for task.
+	DescribeRequests(method, listID string, task Task) []string
+}
+
+// CTagProber is implemented by backends that can read the CTag of one list
+// with a request much smaller than listing tasks or lists, so watchers can
+// poll for changes cheaply.
+type CTagProber interface {
+	// GetListCTag returns the current CTag of a list. Returns a BackendError
+	// with IsNotFound() == true if the list does not exist.
+	GetListCTag(listID string) (string, error)
+}
+
+// ChangeTracker is implemented by backends that can report what changed in a
+// list since a sync token, so a sync fetches only the changed tasks.
+type ChangeTracker interface {
+	// GetChanges returns the tasks added, modified or deleted in a list since
+	// syncToken, by href, and the token to ask from next time. A token the
+	// server no longer accepts is a BackendError with StatusCode 403.
+	GetChanges(listID, syncToken string) (*ListChanges, error)
+
+	// GetTasksAt returns the tasks at hrefs returned by GetChanges.
+	GetTasksAt(listID string, hrefs []string) ([]Task, error)
+}
+
+// ListChanges is what changed in a list since a sync token
+type ListChanges struct {
+	Changed   []string // Hrefs of added or modified tasks
+	Deleted   []string // Hrefs of deleted tasks
+	SyncToken string
+}
+
+// ScanForTask finds one task by listing its list, completed tasks included.
+// It is the GetTask of backends that cannot fetch a single task.
+func ScanForTask(taskManager TaskManager, listID, taskUID string) (*Task, error) {
+	tasks, err := taskManager.GetTasks(listID, &TaskFilter{IncludeCompleted: true})
+	if err != nil {
+		return nil, err
+	}
+	for i := range tasks {
+		if tasks[i].UID == taskUID {
+			return &tasks[i], nil
+		}
+	}
+	return nil, NewNotFoundError("GetTask", listID, taskUID)
+}
+
+// CategoryCounter is implemented by backends that can count category usage
+// without loading every task of a list.
+type CategoryCounter interface {
+	// CountCategories returns the number of tasks in the list carrying each category.
+	CountCategories(listID string) (map[string]int, error)
+}
+
+// CrossListSearcher is implemented by backends that can search the tasks of
+// every list at once, rather than list by list with FindTasksBySummary.
+type CrossListSearcher interface {
+	// SearchAllLists returns the tasks whose summary contains summary,
+	// ignoring case, by the ID of their list. Lists without matches are absent.
+	SearchAllLists(summary string) (map[string][]Task, error)
+}
+
+// SummaryLimiter is implemented by backends that reject summaries longer
+// than some length, so that a write can be refused with a clear error
+// before it is sent.
+type SummaryLimiter interface {
+	// MaxSummaryLength returns the most characters a summary may have.
+	MaxSummaryLength() int
+}
+
+// ConcurrentWriter is implemented by backends whose writes may be sent from
+// several goroutines at once, each being its own request to the server. The
+// sync pushes to other backends one operation at a time.
+type ConcurrentWriter interface {
+	// WritesConcurrently reports whether AddTask, UpdateTask and DeleteTask
+	// may run concurrently.
+	WritesConcurrently() bool
+}
+
+// ConcurrentReader is implemented by backends whose lists may be read from
+// several goroutines at once. The sync fetches the lists of other backends
+// one at a time.
+type ConcurrentReader interface {
+	// ReadsConcurrently reports whether GetTasks, and the ChangeTracker
+	// methods of backends having them, may run concurrently.
+	ReadsConcurrently() bool
+}
+
+// PendingSync describes the changes of a list that are queued for the remote
+type PendingSync struct {
+	Operations int  // Number of queued operations
+	Failing    bool // Set when one of them failed 3 times or has an error recorded
+}
+
+// PendingSyncCounter is implemented by sync caches, so that lists can show
+// the changes not pushed to the remote yet.
+type PendingSyncCounter interface {
+	// CountPendingSync returns the queued operations of each list that has
+	// some, by list ID. It returns nil when the backend is not a sync cache.
+	CountPendingSync() (map[string]PendingSync, error)
+}
+
+// DeletedTask is a task in a backend's trash
+type DeletedTask struct {
+	Task
+	DeletedAt time.Time // Zero when the backend does not record it
+}
+
+// ListArchiver is implemented by backends whose lists can be archived. An
+// archived list keeps its tasks but is left out of GetTaskLists, so it must
+// not be taken for a deleted one.
+type ListArchiver interface {
+	// GetArchivedTaskLists returns the archived lists, with Archived set
+	GetArchivedTaskLists() ([]TaskList, error)
+
+	// UnarchiveTaskList makes an archived list active again
+	UnarchiveTaskList(listID string) error
+}
+
+// TaskTrash is implemented by backends that keep deleted tasks restorable,
+// such as the Nextcloud trash bin or SQLite deletes not yet synced.
+type TaskTrash interface {
+	// GetDeletedTasks returns the restorable deleted tasks of a list.
+	GetDeletedTasks(listID string) ([]DeletedTask, error)
+
+	// RestoreTask moves a deleted task back into its list.
+	// Returns a BackendError with IsNotFound() == true if the task is not in the trash.
+	RestoreTask(listID string, taskUID string) error
+}
+
+// Wrapper is implemented by task managers standing in for another, such as
+// the recorder of --record. A wrapper implements the optional interfaces it
+// passes on, and As returns one of them only when the task manager it stands
+// for has it.
+type Wrapper interface {
+	// Unwrap returns the wrapped task manager, or nil when there is none to
+	// reach, as when calls are answered from a recording.
+	Unwrap() TaskManager
+
+	// Implements reports whether the task manager stood for has the
+	// optional interface, or is of the concrete type, iface.
+	Implements(iface reflect.Type) bool
+}
+
+// As returns the optional interface T of a task manager, as a type assertion
+// would, looking through wrappers. T may also be a concrete backend type.
+func As[T any](tm TaskManager) (T, bool) {
+	var zero T
+	for tm != nil {
+		wrapper, ok := tm.(Wrapper)
+		if !ok {
+			t, ok := tm.(T)
+			return t, ok
+		}
+		if !wrapper.Implements(reflect.TypeFor[T]()) {
+			return zero, false
+		}
+		if t, ok := tm.(T); ok {
+			return t, true
+		}
+		tm = wrapper.Unwrap()
+	}
+	return zero, false
+}
+
+// Implements reports whether tm, looking through wrappers, has the optional
+// interface or is of the concrete type iface. Wrappers answer their own
+// Implements with it.
+func Implements(tm TaskManager, iface reflect.Type) bool {
+	if tm == nil {
+		return false
+	}
+	if wrapper, ok := tm.(Wrapper); ok {
+		return wrapper.Implements(iface)
+	}
+	if iface.Kind() == reflect.Interface {
+		return reflect.TypeOf(tm).Implements(iface)
+	}
+	return reflect.TypeOf(tm) == iface
+}
+
 // TaskFilter specifies filtering criteria for task queries.
 // All filter fields are optional (nil means no filtering on that field).
 // Multiple filter criteria are combined with AND logic.
@@ -293,6 +524,138 @@ type TaskFilter struct {
 
 	// CreatedBefore filters tasks created before this time (inclusive).
 	CreatedBefore *time.Time
+
+	// ModifiedAfter filters tasks last modified at or after this time (inclusive).
+	ModifiedAfter *time.Time
+
+	// ModifiedBefore filters tasks last modified at or before this time (inclusive).
+	ModifiedBefore *time.Time
+
+	// CompletedAfter filters completed tasks to those completed at or after this
+	// time (inclusive). Tasks without a completion time are not affected.
+	CompletedAfter *time.Time
+
+	// IncludeCompleted asks backends that omit completed tasks by default
+	// (e.g., Todoist) to fetch them as well.
+	IncludeCompleted bool
+
+	// ParentUID filters tasks to the direct children of this task.
+	// An empty string selects root tasks (tasks without a parent).
+	ParentUID *string
+
+	// PriorityMin and PriorityMax filter tasks to a priority range (inclusive).
+	// Priority 0 means no priority, so those tasks only match a range from 0.
+	PriorityMin *int
+	PriorityMax *int
+
+	// Overdue filters tasks to those not completed or cancelled whose due date
+	// has passed. Tasks without a due date never match.
+	Overdue bool
+
+	// SummaryContains filters tasks to those whose summary contains this text,
+	// ignoring case. CalDAV backends have the server match it.
+	SummaryContains *string
+}
+
+// MatchesStatus reports whether a status is one of Statuses (when set) and
+// none of ExcludeStatuses
+func (f *TaskFilter) MatchesStatus(status string) bool {
+	if f == nil {
+		return true
+	}
+	if f.ExcludeStatuses != nil && slices.Contains(*f.ExcludeStatuses, status) {
+		return false
+	}
+	return f.Statuses == nil || slices.Contains(*f.Statuses, status)
+}
+
+// MatchesParent reports whether a task with this parent UID satisfies ParentUID
+func (f *TaskFilter) MatchesParent(parentUID string) bool {
+	return f == nil || f.ParentUID == nil || *f.ParentUID == parentUID
+}
+
+// MatchesSummary reports whether a summary contains SummaryContains, ignoring case
+func (f *TaskFilter) MatchesSummary(summary string) bool {
+	if f == nil || f.SummaryContains == nil {
+		return true
+	}
+	return strings.Contains(strings.ToLower(summary), strings.ToLower(*f.SummaryContains))
+}
+
+// MatchesPriority reports whether a priority is within PriorityMin/PriorityMax
+func (f *TaskFilter) MatchesPriority(priority int) bool {
+	if f == nil {
+		return true
+	}
+	if f.PriorityMin != nil && priority < *f.PriorityMin {
+		return false
+	}
+	return f.PriorityMax == nil || priority <= *f.PriorityMax
+}
+
+// MatchesOverdue reports whether a task with this status and due date
+// satisfies Overdue
+func (f *TaskFilter) MatchesOverdue(status string, due *time.Time, allDay bool) bool {
+	if f == nil || !f.Overdue {
+		return true
+	}
+	return due != nil && IsOverdue(*due, allDay, time.Now()) && !IsCompletedStatus(status) && status != "CANCELLED"
+}
+
+// IsOverdue reports whether a due date has passed at now. An all-day due
+// date passes at the end of its day in the time zone of now.
+func IsOverdue(due time.Time, allDay bool, now time.Time) bool {
+	if allDay {
+		year, month, day := due.In(now.Location()).Date()
+		return !now.Before(time.Date(year, month, day+1, 0, 0, 0, 0, now.Location()))
+	}
+	return due.Before(now)
+}
+
+// MatchesModified reports whether a modification time satisfies the
+// ModifiedAfter/ModifiedBefore bounds. Comparison uses whole seconds, the
+// precision of both iCalendar LAST-MODIFIED and the SQLite cache.
+func (f *TaskFilter) MatchesModified(modified time.Time) bool {
+	if f == nil {
+		return true
+	}
+	if f.ModifiedAfter != nil && modified.Unix() < f.ModifiedAfter.Unix() {
+		return false
+	}
+	if f.ModifiedBefore != nil && modified.Unix() > f.ModifiedBefore.Unix() {
+		return false
+	}
+	return true
+}
+
+// MatchesCompleted reports whether a completion time satisfies CompletedAfter.
+// Tasks that were never completed always match.
+func (f *TaskFilter) MatchesCompleted(completed *time.Time) bool {
+	if f == nil || f.CompletedAfter == nil || completed == nil {
+		return true
+	}
+	return completed.Unix() >= f.CompletedAfter.Unix()
+}
+
+// ExactSummaryMatchesFirst stably moves the tasks whose summary equals summary,
+// ignoring case, to the front, as FindTasksBySummary results are ordered.
+func ExactSummaryMatchesFirst(tasks []Task, summary string) {
+	slices.SortStableFunc(tasks, func(a, b Task) int {
+		aExact, bExact := strings.EqualFold(a.Summary, summary), strings.EqualFold(b.Summary, summary)
+		switch {
+		case aExact && !bExact:
+			return -1
+		case bExact && !aExact:
+			return 1
+		}
+		return 0
+	})
+}
+
+// IsCompletedStatus reports whether a backend status means the task is done.
+// Covers both CalDAV (COMPLETED) and app-style (DONE) status names.
+func IsCompletedStatus(status string) bool {
+	return status == "COMPLETED" || status == "DONE"
 }
 
 // StatusStringTranslateToStandardStatus converts app status names to CalDAV standard statuses.
@@ -380,6 +743,11 @@ type Task struct {
 	// StartDate is when work on the task should begin (optional).
 	StartDate *time.Time `json:"start_date,omitempty"`
 
+	// AllDay marks DueDate and StartDate as dates without a time of day
+	// (iCalendar VALUE=DATE), held at midnight in the local time zone.
+	// An all-day task is due until the end of its due day.
+	AllDay bool `json:"all_day,omitempty"`
+
 	// Completed timestamp when the task was marked as COMPLETED (optional).
 	Completed *time.Time `json:"completed,omitempty"`
 
@@ -388,6 +756,20 @@ type Task struct {
 
 	// ParentUID links this task as a subtask of another task (optional).
 	ParentUID string `json:"parent_uid,omitempty"`
+
+	// Recurrence is the RFC 5545 RRULE value repeating the task, such as
+	// "FREQ=WEEKLY;BYDAY=MO", without the RRULE: name (optional).
+	// Completing a recurring task moves it to its next occurrence.
+	Recurrence string `json:"recurrence,omitempty"`
+
+	// Sequence is the iCalendar SEQUENCE revision, raised by each update a
+	// CalDAV client makes (0 when the backend has none).
+	Sequence int `json:"sequence,omitempty"`
+
+	// ETag is the entity tag of the task's resource when a CalDAV backend
+	// read it, sent back with updates so they fail rather than overwrite a
+	// change made since (empty when unknown).
+	ETag string `json:"etag,omitempty"`
 }
 
 // String returns a basic formatted string representation of the task.
@@ -407,7 +789,8 @@ func (t Task) String() string {
 //   - Status symbols: ✓ (done), ● (in progress), ✗ (cancelled), ○ (todo)
 //   - Priority colors: determined by backend.GetPriorityColor()
 //   - Start date colors: cyan (past), yellow (within 3 days), gray (future)
-//   - Due date colors: red (overdue), yellow (due soon), gray (future)
+//   - Due date colors: by calendar day, see utils.ClassifyDue: red (overdue),
+//     bold bright yellow (today), yellow (tomorrow), white (this week), gray (later)
 func (t Task) FormatWithView(view string, backend TaskManager, dateFormat string) string {
 	return t.formatWithIndent(view, backend, dateFormat, 0)
 }
@@ -458,6 +841,12 @@ func (t Task) formatWithIndent(view string, backend TaskManager, dateFormat stri
 		priorityColor = backend.GetPriorityColor(t.Priority)
 	}
 
+	// All-day tasks have no time of day to show
+	dayFormat := dateFormat
+	if t.AllDay {
+		dayFormat = utils.DateOnlyLayout(dateFormat)
+	}
+
 	// Start date
 	startStr := ""
 	if t.StartDate != nil {
@@ -467,28 +856,26 @@ func (t Task) formatWithIndent(view string, backend TaskManager, dateFormat stri
 
 		if start.Before(now) || start.Equal(now) {
 			// Past/present: work should have begun (cyan)
-			startStr = fmt.Sprintf(" \033[36m(starts: %s)\033[0m", start.Format(dateFormat))
+			startStr = fmt.Sprintf(" \033[36m(starts: %s)\033[0m", start.Format(dayFormat))
 		} else if hoursDiff <= 72 { // Within 3 days (inclusive)
 			// Within 3 days (yellow) - includes exactly 72 hours
-			startStr = fmt.Sprintf(" \033[33m(starts: %s)\033[0m", start.Format(dateFormat))
+			startStr = fmt.Sprintf(" \033[33m(starts: %s)\033[0m", start.Format(dayFormat))
 		} else {
 			// Future beyond 3 days (gray)
-			startStr = fmt.Sprintf(" \033[90m(starts: %s)\033[0m", start.Format(dateFormat))
+			startStr = fmt.Sprintf(" \033[90m(starts: %s)\033[0m", start.Format(dayFormat))
 		}
 	}
 
 	// Due date
 	dueStr := ""
 	if t.DueDate != nil {
-		now := time.Now()
 		due := *t.DueDate
-		if due.Before(now) {
-			dueStr = fmt.Sprintf(" \033[31m(overdue: %s)\033[0m", due.Format(dateFormat))
-		} else if due.Sub(now).Hours() < 24 {
-			dueStr = fmt.Sprintf(" \033[33m(due: %s)\033[0m", due.Format(dateFormat))
-		} else {
-			dueStr = fmt.Sprintf(" \033[90m(due: %s)\033[0m", due.Format(dateFormat))
+		urgency := utils.ClassifyDue(due, time.Now(), utils.ActiveLocale().FirstWeekday)
+		label := "due"
+		if urgency == utils.DueOverdue {
+			label = "overdue"
 		}
+		dueStr = fmt.Sprintf(" %s(%s: %s)\033[0m", urgency.Color(), label, due.Format(dayFormat))
 	}
 
 	// Main line: status + colored summary (by priority) + start + due
@@ -501,11 +888,11 @@ func (t Task) formatWithIndent(view string, backend TaskManager, dateFormat stri
 		summaryColor = summaryColor + "\033[1m" // Bold + priority color
 	}
 	result.WriteString(fmt.Sprintf("  %s%s%s\033[0m %s%s\033[0m%s%s\n",
-		indent, statusColor, statusSymbol, summaryColor, t.Summary, startStr, dueStr))
+		indent, statusColor, statusSymbol, summaryColor, utils.SanitizeLine(t.Summary), startStr, dueStr))
 
 	// Description (if present)
 	if t.Description != "" {
-		desc := strings.ReplaceAll(t.Description, "\n", " ")
+		desc := utils.SanitizeLine(strings.ReplaceAll(t.Description, "\n", " "))
 		if len(desc) > 70 {
 			desc = desc[:67] + "..."
 		}
@@ -528,6 +915,10 @@ func (t Task) formatWithIndent(view string, backend TaskManager, dateFormat stri
 			metadata = append(metadata, fmt.Sprintf("Priority: %d", t.Priority))
 		}
 
+		if len(t.Categories) > 0 {
+			metadata = append(metadata, fmt.Sprintf("Tags: %s", utils.SanitizeLine(strings.Join(t.Categories, ", "))))
+		}
+
 		if len(metadata) > 0 {
 			result.WriteString(fmt.Sprintf("     %s\033[2m%s\033[0m\n", indent, strings.Join(metadata, " | ")))
 		}
@@ -538,71 +929,136 @@ func (t Task) formatWithIndent(view string, backend TaskManager, dateFormat stri
 
 // TaskWithLevel represents a task and its hierarchical depth level.
 // This is used when displaying tasks in a hierarchy where subtasks are indented.
+// Task points into the slice given to OrganizeTasksHierarchically.
 type TaskWithLevel struct {
-	Task  Task
+	Task  *Task
 	Level int
 }
 
 // OrganizeTasksHierarchically organizes tasks into a hierarchical structure where
 // subtasks appear immediately after their parent tasks with appropriate indentation levels.
-// Tasks without parents (or whose parents don't exist in the list) are treated as root tasks.
+// The result points into tasks rather than copying them, and keeps every task:
+//   - Tasks without parents, or whose parents are not in the list (orphans),
+//     are root tasks.
+//   - Tasks sharing a UID are all kept; their children are listed under the first of them.
+//   - Tasks only reachable through a cycle of parents, such as a task that is
+//     its own parent, are promoted to root tasks in the order of tasks, the
+//     cycle being cut where it leads back to a task already listed.
 func OrganizeTasksHierarchically(tasks []Task) []TaskWithLevel {
 	if len(tasks) == 0 {
 		return nil
 	}
 
-	// Build maps for quick lookups
-	taskByUID := make(map[string]*Task)
-	childrenMap := make(map[string][]Task)
+	// Build maps for quick lookups (indexes into tasks)
+	taskByUID := make(map[string]int, len(tasks))
+	childrenMap := make(map[string][]int)
 
 	for i := range tasks {
 		task := &tasks[i]
-		taskByUID[task.UID] = task
-
-		if task.ParentUID != "" {
-			childrenMap[task.ParentUID] = append(childrenMap[task.ParentUID], *task)
+		if _, exists := taskByUID[task.UID]; !exists {
+			taskByUID[task.UID] = i
 		}
-	}
 
-	// Find root tasks (tasks without parents or whose parents don't exist)
-	var rootTasks []Task
-	for _, task := range tasks {
-		if task.ParentUID == "" || taskByUID[task.ParentUID] == nil {
-			rootTasks = append(rootTasks, task)
+		if task.ParentUID != "" {
+			childrenMap[task.ParentUID] = append(childrenMap[task.ParentUID], i)
 		}
 	}
 
 	// Recursively build the hierarchical list
-	var result []TaskWithLevel
-	visited := make(map[string]bool)
-
-	var addTaskWithChildren func(task Task, level int)
-	addTaskWithChildren = func(task Task, level int) {
-		// Prevent infinite loops in case of circular references
-		if visited[task.UID] {
+	result := make([]TaskWithLevel, 0, len(tasks))
+	visited := make([]bool, len(tasks))
+	expanded := make(map[string]bool)
+
+	var addTaskWithChildren func(index int, level int)
+	addTaskWithChildren = func(index int, level int) {
+		// Cut circular references where they lead back to a listed task
+		if visited[index] {
 			return
 		}
-		visited[task.UID] = true
+		visited[index] = true
 
 		// Add the current task
+		task := &tasks[index]
 		result = append(result, TaskWithLevel{Task: task, Level: level})
 
-		// Add children recursively
-		if children, ok := childrenMap[task.UID]; ok {
-			for _, child := range children {
-				addTaskWithChildren(child, level+1)
-			}
+		// Add children recursively, once per UID
+		if expanded[task.UID] {
+			return
+		}
+		expanded[task.UID] = true
+		for _, child := range childrenMap[task.UID] {
+			addTaskWithChildren(child, level+1)
+		}
+	}
+
+	// Process all root tasks (tasks without parents or whose parents don't exist)
+	for i := range tasks {
+		if _, parentExists := taskByUID[tasks[i].ParentUID]; tasks[i].ParentUID == "" || !parentExists {
+			addTaskWithChildren(i, 0)
 		}
 	}
 
-	// Process all root tasks
-	for _, task := range rootTasks {
-		addTaskWithChildren(task, 0)
+	// Tasks left are in parent cycles
+	for i := range tasks {
+		if !visited[i] {
+			addTaskWithChildren(i, 0)
+		}
 	}
 
 	return result
 }
 
+// SortTasksByHierarchy returns tasks ordered so that parent tasks come before
+// their children, as storing or creating a subtask needs its parent: the sync
+// respects the foreign keys of the cache with it, and imports create parents
+// first. Tasks whose parent is not among tasks come last.
+func SortTasksByHierarchy(tasks []Task) []Task {
+	// Build parent-child relationships
+	childrenMap := make(map[string][]int) // parentUID -> child indexes
+	rootIndexes := []int{}                // tasks with no parent
+
+	for i, task := range tasks {
+		if task.ParentUID == "" {
+			rootIndexes = append(rootIndexes, i)
+		} else {
+			childrenMap[task.ParentUID] = append(childrenMap[task.ParentUID], i)
+		}
+	}
+
+	// Traverse hierarchy depth-first, collecting tasks in order
+	sorted := []Task{}
+	visited := make(map[int]bool)
+
+	var visit func(index int)
+	visit = func(index int) {
+		if visited[index] {
+			return
+		}
+		visited[index] = true
+		sorted = append(sorted, tasks[index])
+
+		// Visit children
+		taskUID := tasks[index].UID
+		for _, childIndex := range childrenMap[taskUID] {
+			visit(childIndex)
+		}
+	}
+
+	// Visit all root tasks (and their descendants)
+	for _, rootIndex := range rootIndexes {
+		visit(rootIndex)
+	}
+
+	// Add any orphaned tasks (tasks with parent_uid pointing to non-existent parents)
+	for i := range tasks {
+		if !visited[i] {
+			sorted = append(sorted, tasks[i])
+		}
+	}
+
+	return sorted
+}
+
 // TaskList represents a collection/category of tasks.
 // In CalDAV, this corresponds to a calendar that supports VTODO components.
 // Each backend may have its own interpretation (e.g., file directory, database table).
@@ -613,6 +1069,10 @@ type TaskList struct {
 	// Name is the human-readable list name.
 	Name string `json:"name"`
 
+	// Path is the qualified name of a nested list, e.g. "Work/Clients/ACME".
+	// Empty for top-level lists and backends without list nesting.
+	Path string `json:"path,omitempty"`
+
 	// Description provides additional context about the list (optional).
 	Description string `json:"description,omitempty"`
 
@@ -626,20 +1086,70 @@ type TaskList struct {
 	// Used for efficient sync operations (CalDAV-specific, optional).
 	CTags string `json:"ctags,omitempty"`
 
+	// SyncToken is the WebDAV sync token of the list, from which
+	// ChangeTracker.GetChanges reports changes (CalDAV-specific, optional).
+	SyncToken string `json:"sync_token,omitempty"`
+
 	// DeletedAt indicates when the list was deleted (moved to trash).
 	// Empty string means the list is not deleted.
 	// Used by Nextcloud to track trashed calendars (Nextcloud-specific, optional).
 	DeletedAt string `json:"deleted_at,omitempty"`
+
+	// Archived marks a list the backend archived: its tasks are kept, but it
+	// is left out of GetTaskLists. See ListArchiver (Todoist-specific, optional).
+	Archived bool `json:"archived,omitempty"`
+
+	// Position is the list's place in the order the server returned the
+	// lists, from 1. Zero when unknown. See NumberListPositions.
+	Position int `json:"position,omitempty"`
+
+	// Modified is when the list or one of its tasks last changed, for
+	// backends that track it (optional, SQLite cache).
+	Modified time.Time `json:"modified,omitzero"`
+}
+
+// NumberListPositions returns a copy of lists with the Position of each
+// set to its place in the slice, so the server's order survives sorting
+// and caching. Lists already numbered, such as those of the SQLite cache,
+// are returned as they are. lists itself is not modified: backends may
+// hand the same slice to several callers.
+func NumberListPositions(lists []TaskList) []TaskList {
+	for _, list := range lists {
+		if list.Position != 0 {
+			return lists
+		}
+	}
+	numbered := slices.Clone(lists)
+	for i := range numbered {
+		numbered[i].Position = i + 1
+	}
+	return numbered
+}
+
+// QualifiedName returns the list's Path when it is nested, otherwise its Name
+func (t TaskList) QualifiedName() string {
+	if t.Path != "" {
+		return t.Path
+	}
+	return t.Name
+}
+
+// titleText returns the header title, safe to print on one line
+func (t TaskList) titleText() string {
+	titleText := "─ " + utils.SanitizeLine(t.Name)
+	if t.Description != "" {
+		titleText += " - " + utils.SanitizeLine(t.Description)
+	}
+	return titleText + " "
 }
 
 func (t TaskList) String() string {
 	return t.StringWithWidth(80) // Default width
 }
 
-func (t TaskList) StringWithWidth(termWidth int) string {
-	var result strings.Builder
-
-	// Calculate border width
+// boxWidth returns the number of columns between the corners of the list
+// box for a terminal width
+func boxWidth(termWidth int) int {
 	borderWidth := termWidth - 2
 	if borderWidth < 40 {
 		borderWidth = 40
@@ -647,24 +1157,27 @@ func (t TaskList) StringWithWidth(termWidth int) string {
 	if borderWidth > 100 {
 		borderWidth = 100
 	}
+	return borderWidth
+}
 
-	// Build the title text
-	titleText := "─ " + t.Name
-	if t.Description != "" {
-		titleText += " - " + t.Description
+// fitTitle shortens a header title to at most width columns
+func fitTitle(titleText string, width int) string {
+	if utils.DisplayWidth(titleText) <= width {
+		return titleText
 	}
-	titleText += " "
+	return utils.TruncateWidth(titleText, width-1, "...") + " "
+}
 
-	// Calculate padding for header
-	headerPadding := borderWidth - len(titleText) - 1
-	if headerPadding < 0 {
-		headerPadding = 0
-	}
+func (t TaskList) StringWithWidth(termWidth int) string {
+	borderWidth := boxWidth(termWidth)
 
-	// Top border with corner and title
-	result.WriteString(fmt.Sprintf("\n\033[1;36m┌%s%s┐\033[0m\n", titleText, strings.Repeat("─", headerPadding)))
+	// Padding is computed from display widths, so the corner lines up with
+	// the bottom border whether or not the colors are stripped
+	titleText := fitTitle(t.titleText(), borderWidth)
+	headerPadding := borderWidth - utils.DisplayWidth(titleText)
 
-	return result.String()
+	// Top border with corner and title
+	return fmt.Sprintf("\n\033[1;36m┌%s%s┐\033[0m\n", titleText, strings.Repeat("─", headerPadding))
 }
 
 func (t TaskList) BottomBorder() string {
@@ -672,17 +1185,28 @@ func (t TaskList) BottomBorder() string {
 }
 
 func (t TaskList) BottomBorderWithWidth(termWidth int) string {
-	// Calculate border width
-	borderWidth := termWidth - 2
-	if borderWidth < 40 {
-		borderWidth = 40
+	// Bottom border
+	return fmt.Sprintf("\033[1;36m└%s┘\033[0m\n", strings.Repeat("─", boxWidth(termWidth)))
+}
+
+// pendingSyncMarker returns the marker of a list with changes queued for the
+// remote, such as " ↑3 pending ", yellow or red when some keep failing. It
+// is empty when there are none or the backend is not a sync cache.
+func pendingSyncMarker(listID string, backend TaskManager) string {
+	counter, ok := backend.(PendingSyncCounter)
+	if !ok {
+		return ""
 	}
-	if borderWidth > 100 {
-		borderWidth = 100
+	counts, err := counter.CountPendingSync()
+	if err != nil || counts[listID].Operations == 0 {
+		return ""
 	}
-
-	// Bottom border
-	return fmt.Sprintf("\033[1;36m└%s┘\033[0m\n", strings.Repeat("─", borderWidth))
+	color := "\033[33m"
+	if counts[listID].Failing {
+		color = "\033[31m"
+	}
+	// The header color is restored after the marker
+	return fmt.Sprintf(" %s↑%d pending\033[0m\033[1;36m ", color, counts[listID].Operations)
 }
 
 // StringWithBackend returns the list header with backend information displayed on the right side.
@@ -693,63 +1217,38 @@ func (t TaskList) StringWithBackend(backend TaskManager) string {
 
 // StringWithWidthAndBackend formats the list header with backend information.
 // The backend info is positioned on the right side of the header, adapting to terminal width.
+// All widths are display widths: escape sequences count for nothing and wide
+// characters for two columns.
 func (t TaskList) StringWithWidthAndBackend(termWidth int, backend TaskManager) string {
 	// If no backend provided, fall back to standard display
 	if backend == nil {
 		return t.StringWithWidth(termWidth)
 	}
 
-	var result strings.Builder
-
-	// Calculate border width
-	borderWidth := termWidth - 2
-	if borderWidth < 40 {
-		borderWidth = 40
-	}
-	if borderWidth > 100 {
-		borderWidth = 100
-	}
+	borderWidth := boxWidth(termWidth)
 
 	// Get backend display name
-	backendInfo := backend.GetBackendDisplayName()
-
-	// Build the title text
-	titleText := "─ " + t.Name
-	if t.Description != "" {
-		titleText += " - " + t.Description
-	}
-	titleText += " "
-
-	// Calculate available space for padding between title and backend info
-	// Format: ┌─ Title ──────── [backend] ┐
-	titleLen := len(titleText)
-	backendLen := len(backendInfo)
-	totalContentLen := titleLen + backendLen + 2 // +2 for space and corner
-
-	if totalContentLen >= borderWidth {
-		// Not enough space, truncate description or show without backend
-		maxTitleLen := borderWidth - backendLen - 3
-		if maxTitleLen < 10 {
-			// If still not enough space, show without backend info
-			return t.StringWithWidth(termWidth)
-		}
-		// Truncate title
-		if len(titleText) > maxTitleLen {
-			titleText = titleText[:maxTitleLen-3] + "... "
-		}
+	backendInfo := utils.SanitizeLine(backend.GetBackendDisplayName())
+
+	// Format: ┌─ Title ──── ↑3 pending [backend]┐, with at least one ─
+	// between the title and the backend info; the pending marker goes first
+	// when there is no room
+	if marker := pendingSyncMarker(t.ID, backend); marker != "" &&
+		borderWidth-utils.DisplayWidth(marker+backendInfo)-1 >= 10 {
+		backendInfo = marker + backendInfo
 	}
-
-	// Calculate padding between title and backend info
-	paddingLen := borderWidth - len(titleText) - len(backendInfo) - 1
-	if paddingLen < 1 {
-		paddingLen = 1
+	backendWidth := utils.DisplayWidth(backendInfo)
+	maxTitleWidth := borderWidth - backendWidth - 1
+	if maxTitleWidth < 10 {
+		// Not enough space, show without backend info
+		return t.StringWithWidth(termWidth)
 	}
+	titleText := fitTitle(t.titleText(), maxTitleWidth)
+	paddingLen := borderWidth - utils.DisplayWidth(titleText) - backendWidth
 
 	// Top border with corner, title, padding, backend info
-	result.WriteString(fmt.Sprintf("\n\033[1;36m┌%s%s%s┐\033[0m\n",
+	return fmt.Sprintf("\n\033[1;36m┌%s%s%s┐\033[0m\n",
 		titleText,
 		strings.Repeat("─", paddingLen),
-		backendInfo))
-
-	return result.String()
+		backendInfo)
 }
@@ -24,7 +24,7 @@ func TestOrganizeTasksHierarchically(t *testing.T) {
 				{UID: "task1", Summary: "Task 1", Created: now},
 			},
 			expected: []TaskWithLevel{
-				{Task: Task{UID: "task1", Summary: "Task 1", Created: now}, Level: 0},
+				{Task: &Task{UID: "task1", Summary: "Task 1", Created: now}, Level: 0},
 			},
 		},
 		{
@@ -34,8 +34,8 @@ func TestOrganizeTasksHierarchically(t *testing.T) {
 				{UID: "child1", Summary: "Child Task", ParentUID: "parent1", Created: now},
 			},
 			expected: []TaskWithLevel{
-				{Task: Task{UID: "parent1", Summary: "Parent Task", Created: now}, Level: 0},
-				{Task: Task{UID: "child1", Summary: "Child Task", ParentUID: "parent1", Created: now}, Level: 1},
+				{Task: &Task{UID: "parent1", Summary: "Parent Task", Created: now}, Level: 0},
+				{Task: &Task{UID: "child1", Summary: "Child Task", ParentUID: "parent1", Created: now}, Level: 1},
 			},
 		},
 		{
@@ -46,9 +46,9 @@ func TestOrganizeTasksHierarchically(t *testing.T) {
 				{UID: "child2", Summary: "Child 2", ParentUID: "parent1", Created: now},
 			},
 			expected: []TaskWithLevel{
-				{Task: Task{UID: "parent1", Summary: "Parent Task", Created: now}, Level: 0},
-				{Task: Task{UID: "child1", Summary: "Child 1", ParentUID: "parent1", Created: now}, Level: 1},
-				{Task: Task{UID: "child2", Summary: "Child 2", ParentUID: "parent1", Created: now}, Level: 1},
+				{Task: &Task{UID: "parent1", Summary: "Parent Task", Created: now}, Level: 0},
+				{Task: &Task{UID: "child1", Summary: "Child 1", ParentUID: "parent1", Created: now}, Level: 1},
+				{Task: &Task{UID: "child2", Summary: "Child 2", ParentUID: "parent1", Created: now}, Level: 1},
 			},
 		},
 		{
@@ -59,9 +59,9 @@ func TestOrganizeTasksHierarchically(t *testing.T) {
 				{UID: "grandchild1", Summary: "Grandchild Task", ParentUID: "child1", Created: now},
 			},
 			expected: []TaskWithLevel{
-				{Task: Task{UID: "parent1", Summary: "Parent Task", Created: now}, Level: 0},
-				{Task: Task{UID: "child1", Summary: "Child Task", ParentUID: "parent1", Created: now}, Level: 1},
-				{Task: Task{UID: "grandchild1", Summary: "Grandchild Task", ParentUID: "child1", Created: now}, Level: 2},
+				{Task: &Task{UID: "parent1", Summary: "Parent Task", Created: now}, Level: 0},
+				{Task: &Task{UID: "child1", Summary: "Child Task", ParentUID: "parent1", Created: now}, Level: 1},
+				{Task: &Task{UID: "grandchild1", Summary: "Grandchild Task", ParentUID: "child1", Created: now}, Level: 2},
 			},
 		},
 		{
@@ -73,10 +73,10 @@ func TestOrganizeTasksHierarchically(t *testing.T) {
 				{UID: "child2", Summary: "Child 2", ParentUID: "parent2", Created: now},
 			},
 			expected: []TaskWithLevel{
-				{Task: Task{UID: "parent1", Summary: "Parent 1", Created: now}, Level: 0},
-				{Task: Task{UID: "child1", Summary: "Child 1", ParentUID: "parent1", Created: now}, Level: 1},
-				{Task: Task{UID: "parent2", Summary: "Parent 2", Created: now}, Level: 0},
-				{Task: Task{UID: "child2", Summary: "Child 2", ParentUID: "parent2", Created: now}, Level: 1},
+				{Task: &Task{UID: "parent1", Summary: "Parent 1", Created: now}, Level: 0},
+				{Task: &Task{UID: "child1", Summary: "Child 1", ParentUID: "parent1", Created: now}, Level: 1},
+				{Task: &Task{UID: "parent2", Summary: "Parent 2", Created: now}, Level: 0},
+				{Task: &Task{UID: "child2", Summary: "Child 2", ParentUID: "parent2", Created: now}, Level: 1},
 			},
 		},
 		{
@@ -85,7 +85,7 @@ func TestOrganizeTasksHierarchically(t *testing.T) {
 				{UID: "child1", Summary: "Orphaned Child", ParentUID: "nonexistent", Created: now},
 			},
 			expected: []TaskWithLevel{
-				{Task: Task{UID: "child1", Summary: "Orphaned Child", ParentUID: "nonexistent", Created: now}, Level: 0},
+				{Task: &Task{UID: "child1", Summary: "Orphaned Child", ParentUID: "nonexistent", Created: now}, Level: 0},
 			},
 		},
 		{
@@ -97,10 +97,23 @@ func TestOrganizeTasksHierarchically(t *testing.T) {
 				{UID: "root2", Summary: "Root 2", Created: now},
 			},
 			expected: []TaskWithLevel{
-				{Task: Task{UID: "root1", Summary: "Root 1", Created: now}, Level: 0},
-				{Task: Task{UID: "parent1", Summary: "Parent 1", Created: now}, Level: 0},
-				{Task: Task{UID: "child1", Summary: "Child 1", ParentUID: "parent1", Created: now}, Level: 1},
-				{Task: Task{UID: "root2", Summary: "Root 2", Created: now}, Level: 0},
+				{Task: &Task{UID: "root1", Summary: "Root 1", Created: now}, Level: 0},
+				{Task: &Task{UID: "parent1", Summary: "Parent 1", Created: now}, Level: 0},
+				{Task: &Task{UID: "child1", Summary: "Child 1", ParentUID: "parent1", Created: now}, Level: 1},
+				{Task: &Task{UID: "root2", Summary: "Root 2", Created: now}, Level: 0},
+			},
+		},
+		{
+			name: "duplicate UIDs are all kept",
+			tasks: []Task{
+				{UID: "dup", Summary: "First copy", Created: now},
+				{UID: "child1", Summary: "Child", ParentUID: "dup", Created: now},
+				{UID: "dup", Summary: "Second copy", Created: now},
+			},
+			expected: []TaskWithLevel{
+				{Task: &Task{UID: "dup", Summary: "First copy", Created: now}, Level: 0},
+				{Task: &Task{UID: "child1", Summary: "Child", ParentUID: "dup", Created: now}, Level: 1},
+				{Task: &Task{UID: "dup", Summary: "Second copy", Created: now}, Level: 0},
 			},
 		},
 	}
@@ -145,18 +158,73 @@ func TestOrganizeTasksHierarchically(t *testing.T) {
 }
 
 func TestOrganizeTasksHierarchically_CircularReference(t *testing.T) {
-	// Test that circular references don't cause infinite loops
-	now := time.Now()
+	// Tasks in parent cycles have no root to hang from; they are promoted to
+	// roots rather than left out, and the cycle is cut
+	tests := []struct {
+		name     string
+		tasks    []Task
+		expected []TaskWithLevel
+	}{
+		{
+			name:  "self-parenting task",
+			tasks: []Task{{UID: "task1", ParentUID: "task1"}, {UID: "task2"}},
+			expected: []TaskWithLevel{
+				{Task: &Task{UID: "task2"}, Level: 0},
+				{Task: &Task{UID: "task1"}, Level: 0},
+			},
+		},
+		{
+			name: "two-node cycle",
+			tasks: []Task{
+				{UID: "task1", ParentUID: "task2"},
+				{UID: "task2", ParentUID: "task1"},
+				{UID: "child", ParentUID: "task2"},
+			},
+			expected: []TaskWithLevel{
+				{Task: &Task{UID: "task1"}, Level: 0},
+				{Task: &Task{UID: "task2"}, Level: 1},
+				{Task: &Task{UID: "child"}, Level: 2},
+			},
+		},
+		{
+			name: "orphan next to a cycle",
+			tasks: []Task{
+				{UID: "task1", ParentUID: "task2"},
+				{UID: "task2", ParentUID: "task1"},
+				{UID: "orphan", ParentUID: "nonexistent"},
+			},
+			expected: []TaskWithLevel{
+				{Task: &Task{UID: "orphan"}, Level: 0},
+				{Task: &Task{UID: "task1"}, Level: 0},
+				{Task: &Task{UID: "task2"}, Level: 1},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := OrganizeTasksHierarchically(tt.tasks)
+			if len(result) != len(tt.expected) {
+				t.Fatalf("expected %d tasks, got %d", len(tt.expected), len(result))
+			}
+			for i, expected := range tt.expected {
+				if result[i].Task.UID != expected.Task.UID || result[i].Level != expected.Level {
+					t.Errorf("index %d: got %s at level %d, want %s at level %d",
+						i, result[i].Task.UID, result[i].Level, expected.Task.UID, expected.Level)
+				}
+			}
+		})
+	}
+}
+
+func TestOrganizeTasksHierarchically_PointsIntoTasks(t *testing.T) {
 	tasks := []Task{
-		{UID: "task1", Summary: "Task 1", ParentUID: "task2", Created: now},
-		{UID: "task2", Summary: "Task 2", ParentUID: "task1", Created: now},
+		{UID: "parent", Summary: "Parent"},
+		{UID: "child", Summary: "Child", ParentUID: "parent"},
 	}
 
 	result := OrganizeTasksHierarchically(tasks)
-
-	// Circular references result in no tasks being displayed since neither is a root task
-	// This is expected behavior - circular references in data should be fixed at source
-	if len(result) != 0 {
-		t.Errorf("expected 0 tasks (circular reference), got %d", len(result))
+	if result[0].Task != &tasks[0] || result[1].Task != &tasks[1] {
+		t.Fatal("result does not point into the given tasks")
 	}
 }
@@ -2,6 +2,7 @@ package backend
 
 import (
 	"testing"
+	"time"
 )
 
 func TestStatusStringTranslateToStandardStatus(t *testing.T) {
@@ -266,3 +267,214 @@ func normalizeStatus(status string) string {
 	}
 	return status
 }
+
+func TestTaskFilterMatchesModified(t *testing.T) {
+	cutoff := time.Date(2026, 3, 1, 12, 0, 0, 500_000_000, time.UTC)
+
+	tests := []struct {
+		name     string
+		filter   *TaskFilter
+		modified time.Time
+		want     bool
+	}{
+		{"nil filter matches", nil, cutoff, true},
+		{"after: same second earlier nanos is inclusive", &TaskFilter{ModifiedAfter: &cutoff}, cutoff.Truncate(time.Second), true},
+		{"after: previous second excluded", &TaskFilter{ModifiedAfter: &cutoff}, cutoff.Add(-time.Second), false},
+		{"after: next second included", &TaskFilter{ModifiedAfter: &cutoff}, cutoff.Add(time.Second), true},
+		{"before: same second is inclusive", &TaskFilter{ModifiedBefore: &cutoff}, cutoff.Truncate(time.Second), true},
+		{"before: next second excluded", &TaskFilter{ModifiedBefore: &cutoff}, cutoff.Add(time.Second), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.filter.MatchesModified(tt.modified); got != tt.want {
+				t.Errorf("MatchesModified(%v) = %v, want %v", tt.modified, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTaskFilterMatchesStatus(t *testing.T) {
+	open := []string{"NEEDS-ACTION", "IN-PROCESS"}
+	closed := []string{"COMPLETED"}
+
+	tests := []struct {
+		name   string
+		filter *TaskFilter
+		status string
+		want   bool
+	}{
+		{"nil filter matches", nil, "COMPLETED", true},
+		{"any of several statuses", &TaskFilter{Statuses: &open}, "IN-PROCESS", true},
+		{"status not listed", &TaskFilter{Statuses: &open}, "COMPLETED", false},
+		{"excluded status", &TaskFilter{ExcludeStatuses: &closed}, "COMPLETED", false},
+		{"exclusion wins over inclusion", &TaskFilter{Statuses: &closed, ExcludeStatuses: &closed}, "COMPLETED", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.filter.MatchesStatus(tt.status); got != tt.want {
+				t.Errorf("MatchesStatus(%q) = %v, want %v", tt.status, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTaskFilterMatchesParent(t *testing.T) {
+	root := ""
+	parent := "parent-1"
+
+	tests := []struct {
+		name      string
+		filter    *TaskFilter
+		parentUID string
+		want      bool
+	}{
+		{"nil filter matches", nil, "parent-1", true},
+		{"unset parent matches", &TaskFilter{}, "parent-1", true},
+		{"child of the parent", &TaskFilter{ParentUID: &parent}, "parent-1", true},
+		{"child of another task", &TaskFilter{ParentUID: &parent}, "parent-2", false},
+		{"root task for empty parent", &TaskFilter{ParentUID: &root}, "", true},
+		{"subtask for empty parent", &TaskFilter{ParentUID: &root}, "parent-1", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.filter.MatchesParent(tt.parentUID); got != tt.want {
+				t.Errorf("MatchesParent(%q) = %v, want %v", tt.parentUID, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTaskFilterMatchesPriority(t *testing.T) {
+	zero, one, four := 0, 1, 4
+
+	tests := []struct {
+		name     string
+		filter   *TaskFilter
+		priority int
+		want     bool
+	}{
+		{"nil filter matches", nil, 0, true},
+		{"unset range matches no priority", &TaskFilter{}, 0, true},
+		{"within the range", &TaskFilter{PriorityMin: &one, PriorityMax: &four}, 4, true},
+		{"above the range", &TaskFilter{PriorityMin: &one, PriorityMax: &four}, 5, false},
+		{"no priority is below the range", &TaskFilter{PriorityMin: &one, PriorityMax: &four}, 0, false},
+		{"no priority for none", &TaskFilter{PriorityMin: &zero, PriorityMax: &zero}, 0, true},
+		{"a priority for none", &TaskFilter{PriorityMin: &zero, PriorityMax: &zero}, 1, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.filter.MatchesPriority(tt.priority); got != tt.want {
+				t.Errorf("MatchesPriority(%d) = %v, want %v", tt.priority, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTaskFilterMatchesSummary(t *testing.T) {
+	text := "Groceries"
+
+	tests := []struct {
+		name    string
+		filter  *TaskFilter
+		summary string
+		want    bool
+	}{
+		{"nil filter matches", nil, "Anything", true},
+		{"unset text matches", &TaskFilter{}, "Anything", true},
+		{"substring ignoring case", &TaskFilter{SummaryContains: &text}, "Buy groceries", true},
+		{"other summary", &TaskFilter{SummaryContains: &text}, "Finish report", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.filter.MatchesSummary(tt.summary); got != tt.want {
+				t.Errorf("MatchesSummary(%q) = %v, want %v", tt.summary, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTaskFilterMatchesOverdue(t *testing.T) {
+	yesterday, tomorrow := time.Now().Add(-24*time.Hour), time.Now().Add(24*time.Hour)
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+	overdue := &TaskFilter{Overdue: true}
+
+	tests := []struct {
+		name   string
+		filter *TaskFilter
+		status string
+		due    *time.Time
+		allDay bool
+		want   bool
+	}{
+		{"nil filter matches", nil, "NEEDS-ACTION", nil, false, true},
+		{"unset matches anything", &TaskFilter{}, "COMPLETED", &tomorrow, false, true},
+		{"open and past due", overdue, "NEEDS-ACTION", &yesterday, false, true},
+		{"in process and past due", overdue, "IN-PROCESS", &yesterday, false, true},
+		{"due later", overdue, "NEEDS-ACTION", &tomorrow, false, false},
+		{"no due date", overdue, "NEEDS-ACTION", nil, false, false},
+		{"completed", overdue, "COMPLETED", &yesterday, false, false},
+		{"done", overdue, "DONE", &yesterday, false, false},
+		{"cancelled", overdue, "CANCELLED", &yesterday, false, false},
+		{"all day today", overdue, "NEEDS-ACTION", &today, true, false},
+		{"all day yesterday", overdue, "NEEDS-ACTION", &yesterday, true, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.filter.MatchesOverdue(tt.status, tt.due, tt.allDay); got != tt.want {
+				t.Errorf("MatchesOverdue(%s, %v) = %v, want %v", tt.status, tt.due, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBackendConfigShouldBeCached(t *testing.T) {
+	optIn := &BackendSyncConfig{Enabled: true}
+	optOut := &BackendSyncConfig{Enabled: false}
+
+	tests := []struct {
+		name   string
+		config BackendConfig
+		global bool
+		want   bool
+	}{
+		{"remote", BackendConfig{Type: "nextcloud"}, true, true},
+		{"remote opted out", BackendConfig{Type: "todoist", Sync: optOut}, true, false},
+		{"remote without global sync", BackendConfig{Type: "nextcloud"}, false, false},
+		{"git", BackendConfig{Type: "git"}, true, false},
+		{"git opted in", BackendConfig{Type: "git", Sync: optIn}, true, true},
+		{"file opted in", BackendConfig{Type: "file", Sync: optIn}, true, true},
+		{"git opted in without global sync", BackendConfig{Type: "git", Sync: optIn}, false, false},
+		{"sqlite opted in", BackendConfig{Type: "sqlite", Sync: optIn}, true, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.config.ShouldBeCached(tt.global); got != tt.want {
+				t.Errorf("ShouldBeCached(%v) = %v, want %v", tt.global, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNumberListPositions(t *testing.T) {
+	lists := []TaskList{{ID: "a"}, {ID: "b"}}
+	numbered := NumberListPositions(lists)
+	if numbered[0].Position != 1 || numbered[1].Position != 2 {
+		t.Errorf("NumberListPositions() = %+v, want positions 1 and 2", numbered)
+	}
+	if lists[0].Position != 0 || lists[1].Position != 0 {
+		t.Errorf("NumberListPositions() modified the caller's lists: %+v", lists)
+	}
+
+	cached := []TaskList{{ID: "a", Position: 2}, {ID: "b", Position: 1}}
+	if got := NumberListPositions(cached); got[0].Position != 2 || got[1].Position != 1 {
+		t.Errorf("NumberListPositions() renumbered numbered lists: %+v", got)
+	}
+}
@@ -3,7 +3,10 @@ package backend
 import (
 	"fmt"
 	"math/rand"
+	"strings"
 	"time"
+
+	"gosynctasks/backend/statuskit"
 )
 
 // This file contains shared test helpers and mocks used across backend tests.
@@ -58,11 +61,33 @@ func (mb *MockBackend) GetTasks(listID string, filter *TaskFilter) ([]Task, erro
 	if !ok {
 		return []Task{}, nil
 	}
+	if filter != nil && (filter.ParentUID != nil || filter.PriorityMin != nil || filter.PriorityMax != nil || filter.Overdue || filter.SummaryContains != nil) {
+		var matches []Task
+		for _, task := range tasks {
+			if filter.MatchesParent(task.ParentUID) && filter.MatchesPriority(task.Priority) && filter.MatchesOverdue(task.Status, task.DueDate, task.AllDay) &&
+				filter.MatchesSummary(task.Summary) {
+				matches = append(matches, task)
+			}
+		}
+		return matches, nil
+	}
 	return tasks, nil
 }
 
+// FindTasksBySummary matches summaries case-insensitively by substring, like the real backends
 func (mb *MockBackend) FindTasksBySummary(listID string, summary string) ([]Task, error) {
-	return []Task{}, nil
+	matches := []Task{}
+	for _, task := range mb.Tasks[listID] {
+		if strings.Contains(strings.ToLower(task.Summary), strings.ToLower(summary)) {
+			matches = append(matches, task)
+		}
+	}
+	ExactSummaryMatchesFirst(matches, summary)
+	return matches, nil
+}
+
+func (mb *MockBackend) GetTask(listID string, taskUID string) (*Task, error) {
+	return ScanForTask(mb, listID, taskUID)
 }
 
 func (mb *MockBackend) AddTask(listID string, task Task) (string, error) {
@@ -74,9 +99,15 @@ func (mb *MockBackend) AddTask(listID string, task Task) (string, error) {
 		task.UID = "mock-task-id"
 	}
 
+	// Like a PUT to the task's CalDAV resource, a known UID is overwritten
 	tasks := mb.Tasks[listID]
-	tasks = append(tasks, task)
-	mb.Tasks[listID] = tasks
+	for i := range tasks {
+		if tasks[i].UID == task.UID {
+			tasks[i] = task
+			return task.UID, nil
+		}
+	}
+	mb.Tasks[listID] = append(tasks, task)
 	return task.UID, nil
 }
 
@@ -158,34 +189,11 @@ func (mb *MockBackend) PermanentlyDeleteTaskList(listID string) error {
 }
 
 func (mb *MockBackend) ParseStatusFlag(statusFlag string) (string, error) {
-	// Simple mock implementation
-	switch statusFlag {
-	case "T", "TODO":
-		return "NEEDS-ACTION", nil
-	case "D", "DONE":
-		return "COMPLETED", nil
-	case "P", "PROCESSING":
-		return "IN-PROCESS", nil
-	case "C", "CANCELLED":
-		return "CANCELLED", nil
-	default:
-		return statusFlag, nil
-	}
+	return statuskit.CalDAV.ParseStatusFlag(statusFlag)
 }
 
 func (mb *MockBackend) StatusToDisplayName(backendStatus string) string {
-	switch backendStatus {
-	case "NEEDS-ACTION":
-		return "TODO"
-	case "COMPLETED":
-		return "DONE"
-	case "IN-PROCESS":
-		return "PROCESSING"
-	case "CANCELLED":
-		return "CANCELLED"
-	default:
-		return backendStatus
-	}
+	return statuskit.CalDAV.StatusToDisplayName(backendStatus)
 }
 
 func (mb *MockBackend) SortTasks(tasks []Task) {
@@ -2,10 +2,13 @@ package todoist
 
 import (
 	"bytes"
+	"crypto/rand"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
+	"strconv"
 	"time"
 )
 
@@ -13,22 +16,33 @@ const (
 	// Todoist REST API v2 base URL
 	APIBaseURL = "https://api.todoist.com/rest/v2"
 
+	// Todoist Sync API v9 base URL (used for completed tasks, which REST omits)
+	SyncAPIBaseURL = "https://api.todoist.com/sync/v9"
+
+	// completedPageLimit is the maximum page size of /completed/get_all
+	completedPageLimit = 200
+
+	// defaultCompletedWindow is how far back completed tasks are fetched by default
+	defaultCompletedWindow = 30 * 24 * time.Hour
+
 	// API rate limit: ~450 requests per 15 minutes
 	// We'll implement basic retry logic with exponential backoff
 )
 
 // APIClient handles HTTP communication with Todoist REST API v2
 type APIClient struct {
-	baseURL    string
-	apiToken   string
-	httpClient *http.Client
+	baseURL     string
+	syncBaseURL string
+	apiToken    string
+	httpClient  *http.Client
 }
 
 // NewAPIClient creates a new Todoist API client
 func NewAPIClient(apiToken string) *APIClient {
 	return &APIClient{
-		baseURL:  APIBaseURL,
-		apiToken: apiToken,
+		baseURL:     APIBaseURL,
+		syncBaseURL: SyncAPIBaseURL,
+		apiToken:    apiToken,
 		httpClient: &http.Client{
 			Timeout: 30 * time.Second,
 		},
@@ -49,28 +63,30 @@ type Project struct {
 	ViewStyle      string `json:"view_style"`
 	URL            string `json:"url"`
 	ParentID       string `json:"parent_id,omitempty"`
+	IsArchived     bool   `json:"is_archived,omitempty"` // Only set by the Sync API
 }
 
 // TodoistTask represents a task from Todoist API
 type TodoistTask struct {
-	ID            string   `json:"id"`
-	ProjectID     string   `json:"project_id"`
-	SectionID     string   `json:"section_id,omitempty"`
-	Content       string   `json:"content"`
-	Description   string   `json:"description"`
-	IsCompleted   bool     `json:"is_completed"`
-	Labels        []string `json:"labels"`
-	ParentID      string   `json:"parent_id,omitempty"`
-	Order         int      `json:"order"`
-	Priority      int      `json:"priority"` // 1=normal, 2, 3, 4=urgent
-	Due           *Due     `json:"due,omitempty"`
-	URL           string   `json:"url"`
-	CommentCount  int      `json:"comment_count"`
-	CreatedAt     string   `json:"created_at"` // RFC3339 format
-	CreatorID     string   `json:"creator_id"`
-	AssigneeID    string   `json:"assignee_id,omitempty"`
-	AssignerID    string   `json:"assigner_id,omitempty"`
-	Duration      *Duration `json:"duration,omitempty"`
+	ID           string    `json:"id"`
+	ProjectID    string    `json:"project_id"`
+	SectionID    string    `json:"section_id,omitempty"`
+	Content      string    `json:"content"`
+	Description  string    `json:"description"`
+	IsCompleted  bool      `json:"is_completed"`
+	Labels       []string  `json:"labels"`
+	ParentID     string    `json:"parent_id,omitempty"`
+	Order        int       `json:"order"`
+	Priority     int       `json:"priority"` // 1=normal, 2, 3, 4=urgent
+	Due          *Due      `json:"due,omitempty"`
+	URL          string    `json:"url"`
+	CommentCount int       `json:"comment_count"`
+	CreatedAt    string    `json:"created_at"`         // RFC3339 format
+	AddedAt      string    `json:"added_at,omitempty"` // Sync API items' created_at
+	CreatorID    string    `json:"creator_id"`
+	AssigneeID   string    `json:"assignee_id,omitempty"`
+	AssignerID   string    `json:"assigner_id,omitempty"`
+	Duration     *Duration `json:"duration,omitempty"`
 }
 
 // Due represents task due date information
@@ -89,6 +105,39 @@ type Duration struct {
 	Unit   string `json:"unit"` // "minute" or "day"
 }
 
+// CompletedItem represents a completed task from the Sync API
+type CompletedItem struct {
+	ID          string       `json:"id"`
+	TaskID      string       `json:"task_id"`
+	ProjectID   string       `json:"project_id"`
+	Content     string       `json:"content"`
+	CompletedAt string       `json:"completed_at"`          // RFC3339 format
+	ItemObject  *TodoistTask `json:"item_object,omitempty"` // The task itself, asked for with annotate_items
+}
+
+// completedItemsResponse is the body returned by /completed/get_all
+type completedItemsResponse struct {
+	Items []CompletedItem `json:"items"`
+}
+
+// syncCommand is one command of a Sync API /sync request
+type syncCommand struct {
+	Type string         `json:"type"`
+	UUID string         `json:"uuid"`
+	Args map[string]any `json:"args"`
+}
+
+// syncRequest is the body of a Sync API /sync request
+type syncRequest struct {
+	Commands []syncCommand `json:"commands"`
+}
+
+// syncResponse is the body returned by /sync: "ok" by command UUID, or an
+// object describing the error
+type syncResponse struct {
+	SyncStatus map[string]json.RawMessage `json:"sync_status"`
+}
+
 // CreateTaskRequest represents request body for creating a task
 type CreateTaskRequest struct {
 	Content     string   `json:"content"`
@@ -138,6 +187,11 @@ type UpdateProjectRequest struct {
 
 // doRequest performs an HTTP request with authentication
 func (c *APIClient) doRequest(method, endpoint string, body interface{}) (*http.Response, error) {
+	return c.doRequestURL(method, c.baseURL+endpoint, body)
+}
+
+// doRequestURL performs an authenticated HTTP request against a full URL
+func (c *APIClient) doRequestURL(method, url string, body interface{}) (*http.Response, error) {
 	var reqBody io.Reader
 	if body != nil {
 		jsonData, err := json.Marshal(body)
@@ -147,7 +201,6 @@ func (c *APIClient) doRequest(method, endpoint string, body interface{}) (*http.
 		reqBody = bytes.NewBuffer(jsonData)
 	}
 
-	url := c.baseURL + endpoint
 	req, err := http.NewRequest(method, url, reqBody)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
@@ -188,6 +241,77 @@ func (c *APIClient) GetProjects() ([]Project, error) {
 	return projects, nil
 }
 
+// GetArchivedProjects retrieves the archived projects, which GetProjects
+// leaves out, from the Sync API
+func (c *APIClient) GetArchivedProjects() ([]Project, error) {
+	resp, err := c.doRequestURL("GET", c.syncURL()+"/projects/get_archived", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("API error (status %d): %s", resp.StatusCode, string(body))
+	}
+
+	var projects []Project
+	if err := json.NewDecoder(resp.Body).Decode(&projects); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return projects, nil
+}
+
+// UnarchiveProject makes an archived project active again with the Sync API
+// project_unarchive command
+func (c *APIClient) UnarchiveProject(projectID string) error {
+	uuid := newCommandUUID()
+	req := syncRequest{Commands: []syncCommand{{
+		Type: "project_unarchive",
+		UUID: uuid,
+		Args: map[string]any{"id": projectID},
+	}}}
+
+	resp, err := c.doRequestURL("POST", c.syncURL()+"/sync", req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("API error (status %d): %s", resp.StatusCode, string(body))
+	}
+
+	var result syncResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	if status := string(result.SyncStatus[uuid]); status != `"ok"` {
+		return fmt.Errorf("failed to unarchive project %s: %s", projectID, status)
+	}
+
+	return nil
+}
+
+// syncURL returns the Sync API base URL
+func (c *APIClient) syncURL() string {
+	if c.syncBaseURL == "" {
+		return SyncAPIBaseURL
+	}
+	return c.syncBaseURL
+}
+
+// newCommandUUID returns a random UUID identifying a Sync API command
+func newCommandUUID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
+
 // GetProject retrieves a single project by ID
 func (c *APIClient) GetProject(projectID string) (*Project, error) {
 	resp, err := c.doRequest("GET", "/projects/"+projectID, nil)
@@ -300,6 +424,46 @@ func (c *APIClient) GetTasks(projectID string) ([]TodoistTask, error) {
 	return tasks, nil
 }
 
+// GetCompletedTasks retrieves tasks of a project completed since the given time,
+// each with its task annotated, following offset pagination until a short page
+// is returned
+func (c *APIClient) GetCompletedTasks(projectID string, since time.Time) ([]CompletedItem, error) {
+	baseURL := c.syncURL()
+
+	var items []CompletedItem
+	for offset := 0; ; offset += completedPageLimit {
+		params := url.Values{}
+		params.Set("project_id", projectID)
+		params.Set("since", since.UTC().Format("2006-01-02T15:04:05"))
+		params.Set("limit", strconv.Itoa(completedPageLimit))
+		params.Set("offset", strconv.Itoa(offset))
+		params.Set("annotate_items", "true")
+
+		resp, err := c.doRequestURL("GET", baseURL+"/completed/get_all?"+params.Encode(), nil)
+		if err != nil {
+			return nil, err
+		}
+
+		if resp.StatusCode != http.StatusOK {
+			body, _ := io.ReadAll(resp.Body)
+			_ = resp.Body.Close()
+			return nil, fmt.Errorf("API error (status %d): %s", resp.StatusCode, string(body))
+		}
+
+		var page completedItemsResponse
+		err = json.NewDecoder(resp.Body).Decode(&page)
+		_ = resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode response: %w", err)
+		}
+
+		items = append(items, page.Items...)
+		if len(page.Items) < completedPageLimit {
+			return items, nil
+		}
+	}
+}
+
 // GetTask retrieves a single task by ID
 func (c *APIClient) GetTask(taskID string) (*TodoistTask, error) {
 	resp, err := c.doRequest("GET", "/tasks/"+taskID, nil)
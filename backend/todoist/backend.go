@@ -3,8 +3,10 @@ package todoist
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"gosynctasks/backend"
+	"gosynctasks/backend/statuskit"
 	"gosynctasks/internal/credentials"
 	"gosynctasks/internal/utils"
 )
@@ -95,14 +97,50 @@ func (tb *TodoistBackend) GetTaskLists() ([]backend.TaskList, error) {
 		return nil, fmt.Errorf("failed to get projects: %w", err)
 	}
 
+	paths := projectPaths(projects)
+
+	lists := make([]backend.TaskList, len(projects))
+	for i, project := range projects {
+		lists[i] = toTaskList(&project)
+		if project.ParentID != "" {
+			lists[i].Path = paths[project.ID]
+		}
+	}
+
+	return lists, nil
+}
+
+// GetArchivedTaskLists retrieves the archived Todoist projects, which
+// GetTaskLists leaves out. Their tasks are still there.
+func (tb *TodoistBackend) GetArchivedTaskLists() ([]backend.TaskList, error) {
+	projects, err := tb.apiClient.GetArchivedProjects()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get archived projects: %w", err)
+	}
+
+	paths := projectPaths(projects)
+
 	lists := make([]backend.TaskList, len(projects))
 	for i, project := range projects {
 		lists[i] = toTaskList(&project)
+		lists[i].Archived = true
+		if project.ParentID != "" {
+			lists[i].Path = paths[project.ID]
+		}
 	}
 
 	return lists, nil
 }
 
+// UnarchiveTaskList makes an archived Todoist project active again
+func (tb *TodoistBackend) UnarchiveTaskList(listID string) error {
+	if err := tb.apiClient.UnarchiveProject(listID); err != nil {
+		return fmt.Errorf("failed to unarchive project: %w", err)
+	}
+
+	return nil
+}
+
 // GetTasks retrieves tasks from a specific project
 func (tb *TodoistBackend) GetTasks(listID string, filter *backend.TaskFilter) ([]backend.Task, error) {
 	todoistTasks, err := tb.apiClient.GetTasks(listID)
@@ -122,12 +160,70 @@ func (tb *TodoistBackend) GetTasks(listID string, filter *backend.TaskFilter) ([
 		tasks = append(tasks, task)
 	}
 
+	// The REST endpoint omits completed tasks, fetch them separately when asked for
+	if wantsCompletedTasks(filter) {
+		completed, err := tb.getCompletedTasks(listID, filter)
+		if err != nil {
+			return nil, err
+		}
+		seen := make(map[string]bool, len(tasks))
+		for _, task := range tasks {
+			seen[task.UID] = true
+		}
+		for _, task := range completed {
+			if !seen[task.UID] {
+				tasks = append(tasks, task)
+			}
+		}
+	}
+
 	// Sort tasks
 	tb.SortTasks(tasks)
 
 	return tasks, nil
 }
 
+// wantsCompletedTasks reports whether a filter needs tasks from the completed-items endpoint
+func wantsCompletedTasks(filter *backend.TaskFilter) bool {
+	if filter == nil {
+		return false
+	}
+	if filter.Statuses != nil && len(*filter.Statuses) > 0 {
+		for _, status := range *filter.Statuses {
+			if status == "DONE" || status == "COMPLETED" {
+				return true
+			}
+		}
+		return false
+	}
+	return filter.IncludeCompleted || filter.CompletedAfter != nil
+}
+
+// getCompletedTasks fetches completed tasks for a project within the filter's window
+// (CompletedAfter, or the last 30 days by default)
+func (tb *TodoistBackend) getCompletedTasks(listID string, filter *backend.TaskFilter) ([]backend.Task, error) {
+	since := time.Now().Add(-defaultCompletedWindow)
+	if filter.CompletedAfter != nil {
+		since = *filter.CompletedAfter
+	}
+
+	items, err := tb.apiClient.GetCompletedTasks(listID, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get completed tasks: %w", err)
+	}
+
+	var tasks []backend.Task
+	for i := range items {
+		task := toCompletedTask(&items[i])
+		if !tb.matchesFilter(task, filter) {
+			continue
+		}
+		tasks = append(tasks, task)
+	}
+
+	return tasks, nil
+}
+
 // matchesFilter checks if a task matches the given filter
 func (tb *TodoistBackend) matchesFilter(task backend.Task, filter *backend.TaskFilter) bool {
 	// Check status filter
@@ -164,7 +260,18 @@ func (tb *TodoistBackend) matchesFilter(task backend.Task, filter *backend.TaskF
 		}
 	}
 
-	return true
+	// Check modified filters
+	if !filter.MatchesModified(task.Modified) {
+		return false
+	}
+
+	// Check completed after filter
+	if !filter.MatchesCompleted(task.Completed) {
+		return false
+	}
+
+	return filter.MatchesParent(task.ParentUID) && filter.MatchesPriority(task.Priority) &&
+		filter.MatchesOverdue(task.Status, task.DueDate, task.AllDay) && filter.MatchesSummary(task.Summary)
 }
 
 // FindTasksBySummary searches for tasks by content
@@ -174,18 +281,46 @@ func (tb *TodoistBackend) FindTasksBySummary(listID string, summary string) ([]b
 		return nil, err
 	}
 
-	summary = strings.ToLower(summary)
+	search := strings.ToLower(summary)
 	var matches []backend.Task
 
 	for _, task := range tasks {
-		if strings.Contains(strings.ToLower(task.Summary), summary) {
+		if strings.Contains(strings.ToLower(task.Summary), search) {
 			matches = append(matches, task)
 		}
 	}
+	backend.ExactSummaryMatchesFirst(matches, summary)
 
 	return matches, nil
 }
 
+// GetTask retrieves one task with the task endpoint. Completed tasks, which
+// the endpoint no longer returns, are looked up among the project's completed items.
+func (tb *TodoistBackend) GetTask(listID string, taskUID string) (*backend.Task, error) {
+	todoistTask, err := tb.apiClient.GetTask(taskUID)
+	if err == nil {
+		if todoistTask.ProjectID != listID {
+			return nil, backend.NewNotFoundError("GetTask", listID, taskUID)
+		}
+		task := toTask(todoistTask)
+		return &task, nil
+	}
+	if !strings.Contains(err.Error(), "not found") {
+		return nil, fmt.Errorf("failed to get task: %w", err)
+	}
+
+	completed, err := tb.getCompletedTasks(listID, &backend.TaskFilter{IncludeCompleted: true})
+	if err != nil {
+		return nil, err
+	}
+	for i := range completed {
+		if completed[i].UID == taskUID {
+			return &completed[i], nil
+		}
+	}
+	return nil, backend.NewNotFoundError("GetTask", listID, taskUID)
+}
+
 // AddTask creates a new task in Todoist
 func (tb *TodoistBackend) AddTask(listID string, task backend.Task) (string, error) {
 	req := toCreateTaskRequest(task, listID)
@@ -266,6 +401,31 @@ func (tb *TodoistBackend) DeleteTask(listID string, taskUID string) error {
 	return nil
 }
 
+// DescribeRequests returns the REST API requests a write of task makes
+func (tb *TodoistBackend) DescribeRequests(method, listID string, task backend.Task) []string {
+	baseURL := APIBaseURL
+	if tb.apiClient != nil {
+		baseURL = tb.apiClient.baseURL
+	}
+	taskURL := baseURL + "/tasks/" + task.UID
+	switch method {
+	case "AddTask":
+		return []string{"POST " + baseURL + "/tasks"}
+	case "UpdateTask":
+		requests := []string{"POST " + taskURL}
+		switch task.Status {
+		case "COMPLETED":
+			requests = append(requests, "POST "+taskURL+"/close")
+		case "TODO":
+			requests = append(requests, "POST "+taskURL+"/reopen")
+		}
+		return requests
+	case "DeleteTask":
+		return []string{"DELETE " + taskURL}
+	}
+	return nil
+}
+
 // CreateTaskList creates a new Todoist project
 func (tb *TodoistBackend) CreateTaskList(name, description, color string) (string, error) {
 	req := CreateProjectRequest{
@@ -305,7 +465,7 @@ func (tb *TodoistBackend) RenameTaskList(listID, newName string) error {
 
 // GetDeletedTaskLists retrieves deleted projects (not supported by Todoist)
 func (tb *TodoistBackend) GetDeletedTaskLists() ([]backend.TaskList, error) {
-	// Todoist doesn't have a trash/archive API for projects
+	// Todoist has no trash for projects; archived ones are GetArchivedTaskLists
 	return []backend.TaskList{}, nil
 }
 
@@ -322,12 +482,12 @@ func (tb *TodoistBackend) PermanentlyDeleteTaskList(listID string) error {
 
 // ParseStatusFlag converts user input to Todoist status
 func (tb *TodoistBackend) ParseStatusFlag(statusFlag string) (string, error) {
-	return parseStatusFlag(statusFlag)
+	return statuskit.AppNames.ParseStatusFlag(statusFlag)
 }
 
 // StatusToDisplayName converts Todoist status to display name
 func (tb *TodoistBackend) StatusToDisplayName(backendStatus string) string {
-	return statusToDisplayName(backendStatus)
+	return statuskit.AppNames.StatusToDisplayName(backendStatus)
 }
 
 // SortTasks sorts tasks by priority and creation date
@@ -360,18 +520,7 @@ func (tb *TodoistBackend) SortTasks(tasks []backend.Task) {
 
 // GetPriorityColor returns ANSI color code for priority
 func (tb *TodoistBackend) GetPriorityColor(priority int) string {
-	switch {
-	case priority >= 1 && priority <= 2: // Urgent
-		return "\033[31m" // Red
-	case priority >= 3 && priority <= 4: // High
-		return "\033[33m" // Yellow
-	case priority >= 5 && priority <= 6: // Medium
-		return "\033[36m" // Cyan
-	case priority >= 7 && priority <= 9: // Low
-		return "\033[34m" // Blue
-	default:
-		return "" // No color
-	}
+	return priorityPalette.Color(priority)
 }
 
 // GetBackendDisplayName returns formatted display name
@@ -384,6 +533,23 @@ func (tb *TodoistBackend) GetBackendType() string {
 	return "todoist"
 }
 
+// MaxSummaryLength returns the most characters Todoist accepts in a task's content
+func (tb *TodoistBackend) MaxSummaryLength() int {
+	return 500
+}
+
+// WritesConcurrently reports that tasks may be written concurrently, each
+// write being its own API request
+func (tb *TodoistBackend) WritesConcurrently() bool {
+	return true
+}
+
+// ReadsConcurrently reports that lists may be read concurrently, each read
+// being its own API request
+func (tb *TodoistBackend) ReadsConcurrently() bool {
+	return true
+}
+
 // GetBackendContext returns contextual details
 func (tb *TodoistBackend) GetBackendContext() string {
 	// We could fetch user info from API, but for now just return the type
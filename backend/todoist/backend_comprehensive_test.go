@@ -6,6 +6,8 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"strconv"
+	"strings"
 	"testing"
 	"time"
 
@@ -758,3 +760,230 @@ func TestTodoistBackend_Integration_RealAPI(t *testing.T) {
 		t.Log("Deleted task")
 	})
 }
+
+func TestTodoistBackend_GetTasks_IncludesCompleted(t *testing.T) {
+	var sinceParams []string
+	var offsets []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/tasks":
+			json.NewEncoder(w).Encode([]TodoistTask{
+				{ID: "active1", ProjectID: "project1", Content: "Active task", Priority: 1},
+			})
+		case "/completed/get_all":
+			if r.URL.Query().Get("project_id") != "project1" {
+				t.Errorf("project_id = %q, want project1", r.URL.Query().Get("project_id"))
+			}
+			sinceParams = append(sinceParams, r.URL.Query().Get("since"))
+			offset := r.URL.Query().Get("offset")
+			offsets = append(offsets, offset)
+
+			// First page is full, second page is short to end pagination
+			var items []CompletedItem
+			count := completedPageLimit
+			if offset != "0" {
+				count = 1
+			}
+			for i := 0; i < count; i++ {
+				items = append(items, CompletedItem{
+					ID:          "item-" + offset + "-" + strconv.Itoa(i),
+					TaskID:      "done-" + offset + "-" + strconv.Itoa(i),
+					ProjectID:   "project1",
+					Content:     "Done task",
+					CompletedAt: "2026-03-05T10:00:00Z",
+				})
+			}
+			json.NewEncoder(w).Encode(completedItemsResponse{Items: items})
+		default:
+			t.Errorf("Unexpected request %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	tb := &TodoistBackend{
+		apiToken: "test-token",
+		apiClient: &APIClient{
+			baseURL:     server.URL,
+			syncBaseURL: server.URL,
+			apiToken:    "test-token",
+			httpClient:  &http.Client{},
+		},
+	}
+
+	t.Run("DONE status filter fetches all pages", func(t *testing.T) {
+		sinceParams, offsets = nil, nil
+		statuses := []string{"DONE"}
+		tasks, err := tb.GetTasks("project1", &backend.TaskFilter{Statuses: &statuses})
+		if err != nil {
+			t.Fatalf("GetTasks() error = %v", err)
+		}
+		if len(tasks) != completedPageLimit+1 {
+			t.Errorf("GetTasks() returned %d tasks, want %d", len(tasks), completedPageLimit+1)
+		}
+		if len(offsets) != 2 || offsets[1] != strconv.Itoa(completedPageLimit) {
+			t.Errorf("offsets = %v, want [0 %d]", offsets, completedPageLimit)
+		}
+		for _, task := range tasks {
+			if task.Status != "DONE" || task.Completed == nil {
+				t.Fatalf("Expected completed task with timestamp, got %+v", task)
+			}
+		}
+
+		// Default window is 30 days
+		since, err := time.Parse("2006-01-02T15:04:05", sinceParams[0])
+		if err != nil {
+			t.Fatalf("Invalid since parameter %q: %v", sinceParams[0], err)
+		}
+		if age := time.Since(since); age < 29*24*time.Hour || age > 31*24*time.Hour {
+			t.Errorf("since = %v, want about 30 days ago", since)
+		}
+	})
+
+	t.Run("CompletedAfter sets window and filters", func(t *testing.T) {
+		sinceParams, offsets = nil, nil
+		cutoff := time.Date(2026, 3, 6, 0, 0, 0, 0, time.UTC)
+		tasks, err := tb.GetTasks("project1", &backend.TaskFilter{CompletedAfter: &cutoff, IncludeCompleted: true})
+		if err != nil {
+			t.Fatalf("GetTasks() error = %v", err)
+		}
+		if sinceParams[0] != "2026-03-06T00:00:00" {
+			t.Errorf("since = %q, want 2026-03-06T00:00:00", sinceParams[0])
+		}
+		// Completed items fall before the cutoff, so only the active task remains
+		if len(tasks) != 1 || tasks[0].UID != "active1" {
+			t.Errorf("GetTasks() = %v, want only active1", tasks)
+		}
+	})
+
+	t.Run("TODO filter skips completed endpoint", func(t *testing.T) {
+		offsets = nil
+		statuses := []string{"TODO"}
+		if _, err := tb.GetTasks("project1", &backend.TaskFilter{Statuses: &statuses}); err != nil {
+			t.Fatalf("GetTasks() error = %v", err)
+		}
+		if len(offsets) != 0 {
+			t.Errorf("Completed endpoint called %d times, want 0", len(offsets))
+		}
+	})
+}
+
+func TestTodoistBackend_GetTask(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/tasks/active1":
+			json.NewEncoder(w).Encode(TodoistTask{ID: "active1", ProjectID: "project1", Content: "Active task", Priority: 1})
+		case "/completed/get_all":
+			json.NewEncoder(w).Encode(completedItemsResponse{Items: []CompletedItem{
+				{ID: "item1", TaskID: "done1", ProjectID: "project1", Content: "Done task", CompletedAt: time.Now().UTC().Format(time.RFC3339)},
+			}})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	tb := &TodoistBackend{
+		apiToken: "test-token",
+		apiClient: &APIClient{
+			baseURL:     server.URL,
+			syncBaseURL: server.URL,
+			apiToken:    "test-token",
+			httpClient:  &http.Client{},
+		},
+	}
+
+	task, err := tb.GetTask("project1", "active1")
+	if err != nil || task.Summary != "Active task" {
+		t.Fatalf("GetTask(active1) = %v, %v, want the active task", task, err)
+	}
+
+	// The task endpoint does not return completed tasks
+	task, err = tb.GetTask("project1", "done1")
+	if err != nil || task.Summary != "Done task" || task.Status != "DONE" {
+		t.Fatalf("GetTask(done1) = %v, %v, want the completed task", task, err)
+	}
+
+	for _, ref := range []struct{ listID, uid string }{{"project2", "active1"}, {"project1", "missing"}} {
+		_, err := tb.GetTask(ref.listID, ref.uid)
+		backendErr, ok := err.(*backend.BackendError)
+		if !ok || !backendErr.IsNotFound() {
+			t.Errorf("GetTask(%s, %s) error = %v, want not found", ref.listID, ref.uid, err)
+		}
+	}
+}
+
+func TestTodoistBackend_ArchivedProjects(t *testing.T) {
+	var commands []syncCommand
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == "GET" && r.URL.Path == "/projects/get_archived":
+			json.NewEncoder(w).Encode([]Project{
+				{ID: "old", Name: "Old Project", Color: "grey", IsArchived: true},
+				{ID: "old-child", Name: "Phase 1", ParentID: "old", IsArchived: true},
+			})
+		case r.Method == "POST" && r.URL.Path == "/sync":
+			var req syncRequest
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				t.Fatalf("Failed to decode /sync request: %v", err)
+			}
+			commands = append(commands, req.Commands...)
+			status := map[string]any{}
+			for _, command := range req.Commands {
+				if command.Args["id"] == "missing" {
+					status[command.UUID] = map[string]any{"error_code": 21, "error": "Project not found"}
+				} else {
+					status[command.UUID] = "ok"
+				}
+			}
+			json.NewEncoder(w).Encode(map[string]any{"sync_status": status})
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	tb := &TodoistBackend{
+		apiToken: "test-token",
+		apiClient: &APIClient{
+			baseURL:     server.URL,
+			syncBaseURL: server.URL,
+			apiToken:    "test-token",
+			httpClient:  &http.Client{},
+		},
+	}
+	var _ backend.ListArchiver = tb
+
+	t.Run("archived projects are lists marked archived", func(t *testing.T) {
+		lists, err := tb.GetArchivedTaskLists()
+		if err != nil {
+			t.Fatalf("GetArchivedTaskLists() error = %v", err)
+		}
+		if len(lists) != 2 || !lists[0].Archived || !lists[1].Archived {
+			t.Fatalf("GetArchivedTaskLists() = %+v, want 2 archived lists", lists)
+		}
+		if lists[0].Name != "Old Project" || lists[0].Color != "grey" || lists[1].Path != "Old Project/Phase 1" {
+			t.Errorf("GetArchivedTaskLists() = %+v", lists)
+		}
+	})
+
+	t.Run("unarchive sends a project_unarchive command", func(t *testing.T) {
+		if err := tb.UnarchiveTaskList("old"); err != nil {
+			t.Fatalf("UnarchiveTaskList() error = %v", err)
+		}
+		if len(commands) != 1 || commands[0].Type != "project_unarchive" || commands[0].Args["id"] != "old" || commands[0].UUID == "" {
+			t.Errorf("commands = %+v, want one project_unarchive of old", commands)
+		}
+	})
+
+	t.Run("a rejected command is an error", func(t *testing.T) {
+		err := tb.UnarchiveTaskList("missing")
+		if err == nil || !strings.Contains(err.Error(), "Project not found") {
+			t.Errorf("UnarchiveTaskList(missing) error = %v, want the command error", err)
+		}
+	})
+}
@@ -0,0 +1,32 @@
+package todoist
+
+import (
+	"gosynctasks/backend"
+	"gosynctasks/backend/conformance"
+	"os"
+	"testing"
+)
+
+// TestConformance runs the backend-agnostic TaskManager suite against the Todoist API
+func TestConformance(t *testing.T) {
+	apiToken := os.Getenv("TODOIST_API_TOKEN")
+	if apiToken == "" {
+		t.Skip("TODOIST_API_TOKEN not set, skipping Todoist conformance tests")
+	}
+
+	tb, err := NewTodoistBackend(backend.BackendConfig{
+		Type:     "todoist",
+		Name:     "todoist",
+		Enabled:  true,
+		APIToken: apiToken,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create Todoist backend: %v", err)
+	}
+
+	conformance.Run(t, func() backend.TaskManager { return tb }, conformance.Capabilities{
+		StableListIDs: true,
+		Hierarchy:     true,
+		Categories:    true,
+	})
+}
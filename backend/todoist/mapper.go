@@ -2,6 +2,7 @@ package todoist
 
 import (
 	"gosynctasks/backend"
+	"gosynctasks/backend/statuskit"
 	"fmt"
 	"strings"
 	"time"
@@ -47,16 +48,21 @@ func toTask(todoistTask *TodoistTask) backend.Task {
 				task.DueDate = &dueTime
 			}
 		} else if todoistTask.Due.Date != "" {
-			// Has only date (no specific time)
-			if dueTime, err := time.Parse("2006-01-02", todoistTask.Due.Date); err == nil {
+			// Has only date (no specific time): an all-day task
+			if dueTime, err := time.ParseInLocation("2006-01-02", todoistTask.Due.Date, time.Local); err == nil {
 				task.DueDate = &dueTime
+				task.AllDay = true
 			}
 		}
 	}
 
 	// Parse created timestamp
-	if todoistTask.CreatedAt != "" {
-		if createdTime, err := time.Parse(time.RFC3339, todoistTask.CreatedAt); err == nil {
+	createdAt := todoistTask.CreatedAt
+	if createdAt == "" {
+		createdAt = todoistTask.AddedAt
+	}
+	if createdAt != "" {
+		if createdTime, err := time.Parse(time.RFC3339, createdAt); err == nil {
 			task.Created = createdTime
 		}
 	}
@@ -68,6 +74,25 @@ func toTask(todoistTask *TodoistTask) backend.Task {
 	return task
 }
 
+// toCompletedTask converts a completed item from the Sync API to gosynctasks Task.
+// The annotated task gives the fields the item lacks, which a sync would
+// otherwise clear from the cached task.
+func toCompletedTask(item *CompletedItem) backend.Task {
+	task := backend.Task{Summary: item.Content}
+	if item.ItemObject != nil {
+		task = toTask(item.ItemObject)
+	}
+	task.UID = item.TaskID
+	task.Status = "DONE"
+
+	if completedTime, err := time.Parse(time.RFC3339, item.CompletedAt); err == nil {
+		task.Completed = &completedTime
+		task.Modified = completedTime
+	}
+
+	return task
+}
+
 // toTaskList converts a Todoist project to gosynctasks TaskList
 func toTaskList(project *Project) backend.TaskList {
 	return backend.TaskList{
@@ -75,7 +100,32 @@ func toTaskList(project *Project) backend.TaskList {
 		Name:        project.Name,
 		Description: fmt.Sprintf("%d comments", project.CommentCount),
 		Color:       project.Color,
+		Archived:    project.IsArchived,
+	}
+}
+
+// projectPaths resolves each project's parent_id chain into a qualified name
+// like "Work/Clients/ACME". Missing parents end the chain; cycles are cut.
+func projectPaths(projects []Project) map[string]string {
+	byID := make(map[string]*Project, len(projects))
+	for i := range projects {
+		byID[projects[i].ID] = &projects[i]
+	}
+
+	paths := make(map[string]string, len(projects))
+	for i := range projects {
+		var parts []string
+		seen := make(map[string]bool)
+		for p := &projects[i]; p != nil && !seen[p.ID]; p = byID[p.ParentID] {
+			seen[p.ID] = true
+			parts = append([]string{p.Name}, parts...)
+			if p.ParentID == "" {
+				break
+			}
+		}
+		paths[projects[i].ID] = strings.Join(parts, "/")
 	}
+	return paths
 }
 
 // toCreateTaskRequest converts gosynctasks Task to Todoist create request
@@ -105,7 +155,7 @@ func toCreateTaskRequest(task backend.Task, projectID string) CreateTaskRequest
 	// Set due date
 	if task.DueDate != nil && !task.DueDate.IsZero() {
 		// Check if it has time component
-		if task.DueDate.Hour() == 0 && task.DueDate.Minute() == 0 && task.DueDate.Second() == 0 {
+		if task.AllDay || task.DueDate.Hour() == 0 && task.DueDate.Minute() == 0 && task.DueDate.Second() == 0 {
 			// Date only
 			req.DueDate = task.DueDate.Format("2006-01-02")
 		} else {
@@ -154,7 +204,7 @@ func toUpdateTaskRequest(task backend.Task) UpdateTaskRequest {
 
 	// Set due date only if present
 	if task.DueDate != nil && !task.DueDate.IsZero() {
-		if task.DueDate.Hour() == 0 && task.DueDate.Minute() == 0 && task.DueDate.Second() == 0 {
+		if task.AllDay || task.DueDate.Hour() == 0 && task.DueDate.Minute() == 0 && task.DueDate.Second() == 0 {
 			dueDate := task.DueDate.Format("2006-01-02")
 			req.DueDate = &dueDate
 		} else {
@@ -166,35 +216,11 @@ func toUpdateTaskRequest(task backend.Task) UpdateTaskRequest {
 	return req
 }
 
-// parseStatusFlag converts CLI status input to Todoist-compatible status
-func parseStatusFlag(statusFlag string) (string, error) {
-	upper := strings.ToUpper(statusFlag)
-
-	// Handle abbreviations
-	switch upper {
-	case "T":
-		return "TODO", nil
-	case "D":
-		return "DONE", nil
-	case "P":
-		// Todoist doesn't have "PROCESSING" - we'll use TODO with a label
-		return "PROCESSING", nil
-	case "C":
-		// Todoist doesn't have "CANCELLED" - we'll use a label
-		return "CANCELLED", nil
-	}
-
-	// Handle full names
-	switch upper {
-	case "TODO", "DONE", "PROCESSING", "CANCELLED":
-		return upper, nil
-	}
-
-	return "", fmt.Errorf("invalid status flag: %s (use TODO/T, DONE/D, PROCESSING/P, CANCELLED/C)", statusFlag)
-}
-
-// statusToDisplayName converts backend status to display name
-func statusToDisplayName(backendStatus string) string {
-	// Todoist backend uses app-style status names directly
-	return backendStatus
+// priorityPalette colors priorities by the Todoist level they map to: p1
+// (1-2) red, p2 (3-4) yellow, p3 (5-6) cyan and p4 (7-9) blue
+var priorityPalette = statuskit.Palette{
+	{Min: 1, Max: 2, Color: statuskit.Red},
+	{Min: 3, Max: 4, Color: statuskit.Yellow},
+	{Min: 5, Max: 6, Color: statuskit.Cyan},
+	{Min: 7, Max: 9, Color: statuskit.Blue},
 }
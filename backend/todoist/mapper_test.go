@@ -124,6 +124,9 @@ func TestToTask(t *testing.T) {
 				if result.DueDate == nil {
 					t.Error("DueDate is nil, expected non-nil")
 				}
+				if allDay := tt.todoistTask.Due.Datetime == ""; result.AllDay != allDay {
+					t.Errorf("AllDay = %v, want %v", result.AllDay, allDay)
+				}
 			}
 
 			// Check created time was parsed
@@ -381,7 +384,7 @@ func TestParseStatusFlag(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.input, func(t *testing.T) {
-			result, err := parseStatusFlag(tt.input)
+			result, err := (&TodoistBackend{}).ParseStatusFlag(tt.input)
 
 			if tt.wantErr {
 				if err == nil {
@@ -396,7 +399,7 @@ func TestParseStatusFlag(t *testing.T) {
 			}
 
 			if result != tt.expected {
-				t.Errorf("parseStatusFlag(%q) = %q, want %q", tt.input, result, tt.expected)
+				t.Errorf("ParseStatusFlag(%q) = %q, want %q", tt.input, result, tt.expected)
 			}
 		})
 	}
@@ -415,10 +418,35 @@ func TestStatusToDisplayName(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.status, func(t *testing.T) {
-			result := statusToDisplayName(tt.status)
+			result := (&TodoistBackend{}).StatusToDisplayName(tt.status)
 			if result != tt.expected {
-				t.Errorf("statusToDisplayName(%q) = %q, want %q", tt.status, result, tt.expected)
+				t.Errorf("StatusToDisplayName(%q) = %q, want %q", tt.status, result, tt.expected)
 			}
 		})
 	}
 }
+
+func TestProjectPaths(t *testing.T) {
+	projects := []Project{
+		{ID: "1", Name: "Work"},
+		{ID: "2", Name: "Clients", ParentID: "1"},
+		{ID: "3", Name: "Acme", ParentID: "2"},
+		{ID: "4", Name: "Loop A", ParentID: "5"},
+		{ID: "5", Name: "Loop B", ParentID: "4"},
+	}
+
+	paths := projectPaths(projects)
+
+	tests := map[string]string{
+		"1": "Work",
+		"2": "Work/Clients",
+		"3": "Work/Clients/Acme",
+		"4": "Loop B/Loop A",
+		"5": "Loop A/Loop B",
+	}
+	for id, want := range tests {
+		if got := paths[id]; got != want {
+			t.Errorf("paths[%s] = %q, want %q", id, got, want)
+		}
+	}
+}
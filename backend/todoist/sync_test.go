@@ -0,0 +1,110 @@
+package todoist
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"gosynctasks/backend"
+	"gosynctasks/backend/sqlite"
+	"gosynctasks/backend/sync"
+)
+
+// TestSyncKeepsFieldsOfRemotelyCompletedTask completes a task in Todoist
+// between two syncs: the cached task keeps the fields the REST listing gave
+func TestSyncKeepsFieldsOfRemotelyCompletedTask(t *testing.T) {
+	report := TodoistTask{
+		ID:          "t1",
+		ProjectID:   "project1",
+		Content:     "Write report",
+		Description: "Q3 numbers",
+		Labels:      []string{"work"},
+		ParentID:    "p1",
+		Priority:    4,
+		Due:         &Due{Date: "2026-03-10"},
+		CreatedAt:   "2026-03-01T09:00:00Z",
+	}
+	parent := TodoistTask{ID: "p1", ProjectID: "project1", Content: "Q3", Priority: 1, CreatedAt: "2026-03-01T08:00:00Z"}
+	completed := false
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/projects":
+			json.NewEncoder(w).Encode([]Project{{ID: "project1", Name: "Work"}})
+		case "/projects/get_archived":
+			json.NewEncoder(w).Encode([]Project{})
+		case "/tasks":
+			tasks := []TodoistTask{parent}
+			if !completed {
+				tasks = append(tasks, report)
+			}
+			json.NewEncoder(w).Encode(tasks)
+		case "/completed/get_all":
+			var items []CompletedItem
+			if completed && r.URL.Query().Get("annotate_items") == "true" {
+				// Sync API items name created_at added_at
+				item := report
+				item.CreatedAt, item.AddedAt = "", report.CreatedAt
+				items = append(items, CompletedItem{
+					ID: "item1", TaskID: "t1", ProjectID: "project1", Content: report.Content,
+					CompletedAt: time.Now().UTC().Format(time.RFC3339), ItemObject: &item,
+				})
+			}
+			json.NewEncoder(w).Encode(completedItemsResponse{Items: items})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	tb := &TodoistBackend{
+		apiToken: "test-token",
+		apiClient: &APIClient{
+			baseURL:     server.URL,
+			syncBaseURL: server.URL,
+			apiToken:    "test-token",
+			httpClient:  &http.Client{},
+		},
+	}
+	local, err := sqlite.NewSQLiteBackend(backend.BackendConfig{
+		Type: "sqlite", Enabled: true, DBPath: filepath.Join(t.TempDir(), "cache.db"),
+	})
+	if err != nil {
+		t.Fatalf("NewSQLiteBackend() error = %v", err)
+	}
+	defer local.Close()
+	sm := sync.NewSyncManager(local, tb, sync.ServerWins)
+
+	syncOnce := func() {
+		t.Helper()
+		result, err := sm.Sync()
+		if err != nil || len(result.Errors) > 0 {
+			t.Fatalf("Sync() = %+v, %v", result, err)
+		}
+	}
+	syncOnce()
+	completed = true
+	syncOnce()
+
+	task, err := local.GetTask("project1", "t1")
+	if err != nil {
+		t.Fatalf("GetTask() error = %v", err)
+	}
+	if task.Completed == nil || task.Completed.IsZero() {
+		t.Errorf("cached task not completed: %+v", task)
+	}
+	if task.Description != "Q3 numbers" || task.Priority != 1 || task.ParentUID != "p1" || !slices.Equal(task.Categories, []string{"work"}) {
+		t.Errorf("cached task lost its fields: %+v", task)
+	}
+	if task.DueDate == nil || task.DueDate.Format("2006-01-02") != "2026-03-10" || !task.AllDay {
+		t.Errorf("cached task due %v (all day %v), want 2026-03-10 all day", task.DueDate, task.AllDay)
+	}
+	if task.Created.IsZero() {
+		t.Errorf("cached task lost its creation time: %+v", task)
+	}
+}
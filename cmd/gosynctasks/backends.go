@@ -6,8 +6,11 @@ package main
 // The blank imports ensure that all backends are registered at program startup.
 
 import (
+	_ "gosynctasks/backend/caldav"    // CalDAV backend
+	_ "gosynctasks/backend/deck"      // Nextcloud Deck backend
 	_ "gosynctasks/backend/file"      // File backend
 	_ "gosynctasks/backend/git"       // Git backend
+	_ "gosynctasks/backend/github"    // GitHub Issues backend
 	_ "gosynctasks/backend/nextcloud" // Nextcloud backend
 	_ "gosynctasks/backend/sqlite"    // SQLite backend
 	_ "gosynctasks/backend/todoist"   // Todoist backend
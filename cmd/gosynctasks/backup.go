@@ -0,0 +1,185 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"gosynctasks/backend"
+	"gosynctasks/backend/sqlite"
+	"gosynctasks/internal/config"
+	internalSync "gosynctasks/internal/sync"
+	"gosynctasks/internal/utils"
+
+	"github.com/spf13/cobra"
+)
+
+// backupCache snapshots the cache before a destructive operation and reports
+// where the backup went
+func backupCache(cfg *config.Config, db *sqlite.Database, reason string, quiet bool) error {
+	backup, err := db.Backup(reason, cfg.GetBackupCount())
+	if err != nil {
+		return fmt.Errorf("%w; nothing was changed", err)
+	}
+	if !quiet {
+		fmt.Printf("Backed up cache to %s\n", backup.Path)
+	}
+	return nil
+}
+
+// backupCacheAt is backupCache for commands that do not hold the cache open
+func backupCacheAt(cfg *config.Config, reason string, quiet bool) error {
+	db, err := openCacheDatabase(cfg)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = db.Close() }()
+	return backupCache(cfg, db, reason, quiet)
+}
+
+// backupTaskManagerCache is backupCache for commands working through a task
+// manager: it backs up its cache, and does nothing for a remote backend
+func backupTaskManagerCache(cfg *config.Config, taskManager backend.TaskManager, reason string) error {
+	cache, ok := backend.As[*sqlite.SQLiteBackend](taskManager)
+	if !ok {
+		return nil
+	}
+	db, err := cache.GetDB()
+	if err != nil {
+		return err
+	}
+	return backupCache(cfg, db, reason, false)
+}
+
+// newDBRestoreBackupCmd creates the 'db restore-backup' command
+func newDBRestoreBackupCmd() *cobra.Command {
+	var list bool
+
+	cmd := &cobra.Command{
+		Use:   "restore-backup [backup]",
+		Short: "List or restore backups of the local cache",
+		Long: `List the backups of the local cache, or restore one of them.
+
+A backup is taken automatically before a full sync, pruning completed tasks,
+'list merge', 'sync drop', 'sync rescue', 'db rename-backend', 'db decrypt'
+and restoring a backup. The newest sync.backup_count backups are kept (default 5).
+Restoring replaces the whole cache, including changes not yet synced, and is
+refused while a sync is writing to the cache.
+
+Examples:
+  gosynctasks db restore-backup --list
+  gosynctasks db restore-backup cache-20260114-093000.000-full-sync.db`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg := config.GetConfig()
+			db, err := openCacheDatabase(cfg)
+			if err != nil {
+				return err
+			}
+			defer func() { _ = db.Close() }()
+
+			backups, err := sqlite.ListBackups(db.BackupDir())
+			if err != nil {
+				return err
+			}
+			if list || len(args) == 0 {
+				printBackups(db.BackupDir(), backups)
+				return nil
+			}
+
+			var chosen *sqlite.BackupFile
+			for i := range backups {
+				if backups[i].Name() == args[0] || backups[i].Path == args[0] {
+					chosen = &backups[i]
+					break
+				}
+			}
+			if chosen == nil {
+				return fmt.Errorf("backup '%s' not found (see 'gosynctasks db restore-backup --list')", args[0])
+			}
+
+			confirmed, err := utils.PromptConfirmation(fmt.Sprintf("Replace the cache with the backup from %s? Changes made since then that are not synced are lost.",
+				chosen.CreatedAt.Format("2006-01-02 15:04:05")))
+			if err != nil {
+				return err
+			}
+			if !confirmed {
+				return fmt.Errorf("restore cancelled")
+			}
+
+			// The current cache becomes a backup itself, so the restore can be undone
+			if err := backupCache(cfg, db, "pre-restore", false); err != nil {
+				return err
+			}
+			if err := db.RestoreBackup(chosen.Path); err != nil {
+				return err
+			}
+			fmt.Printf("Restored cache from %s\n", chosen.Name())
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&list, "list", false, "List available backups")
+	return cmd
+}
+
+// printBackups lists backups with their time, size and the operation they preceded
+func printBackups(dir string, backups []sqlite.BackupFile) {
+	if len(backups) == 0 {
+		fmt.Printf("No backups in %s\n", dir)
+		return
+	}
+	fmt.Printf("Backups in %s:\n", dir)
+	for _, backup := range backups {
+		fmt.Printf("  %s  %s  %8s  %s\n", backup.CreatedAt.Format("2006-01-02 15:04:05"),
+			backup.Name(), formatBackupSize(backup.Size), strings.ReplaceAll(backup.Reason, "-", " "))
+	}
+}
+
+// formatBackupSize formats a file size in KB or MB
+func formatBackupSize(size int64) string {
+	if size < 1024*1024 {
+		return fmt.Sprintf("%.1f KB", float64(size)/1024)
+	}
+	return fmt.Sprintf("%.1f MB", float64(size)/(1024*1024))
+}
+
+// newDBMaintenanceCmd creates the 'db maintenance' command
+func newDBMaintenanceCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "maintenance",
+		Short: "Compact the local cache and prune old backups",
+		Long: `Remove the completed tasks closed longer than cache.completed_retention ago
+from the local cache (they stay on the remote), compact it with VACUUM and
+delete its backups older than sync.backup_retention_days (default 30). The
+newest backup is always kept.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg := config.GetConfig()
+			db, err := openCacheDatabase(cfg)
+			if err != nil {
+				return err
+			}
+			defer func() { _ = db.Close() }()
+
+			pruned, err := internalSync.PruneCompleted(cfg, db)
+			if err != nil {
+				return err
+			}
+			if retention, _ := cfg.GetCompletedRetention(); retention > 0 {
+				fmt.Printf("Pruned %d completed task(s) past cache.completed_retention.\n", pruned)
+			}
+
+			if err := db.Vacuum(); err != nil {
+				return fmt.Errorf("failed to compact cache: %w", err)
+			}
+			fmt.Println("Compacted cache.")
+
+			prunedBackups, err := sqlite.PruneBackups(db.BackupDir(), cfg.GetBackupCount(), cfg.GetBackupRetention())
+			if err != nil {
+				return err
+			}
+			fmt.Printf("Pruned %d old backup(s).\n", len(prunedBackups))
+			return nil
+		},
+	}
+}
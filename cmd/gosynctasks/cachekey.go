@@ -0,0 +1,103 @@
+package main
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"gosynctasks/internal/credentials"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+
+	"golang.org/x/term"
+)
+
+// Keyring entry of the passphrase of encrypted lists
+const (
+	cacheKeyringName = "cache"
+	cacheKeyringUser = "encryption"
+)
+
+// cacheKeys supplies the passphrase of the encrypted lists of the cache: from
+// the system keyring when stored there with 'db encrypt --keyring', else asked
+// on the terminal. The key derived from it is kept in XDG_RUNTIME_DIR for the
+// rest of the login session, so background syncs can open the lists too.
+type cacheKeys struct {
+	entered string // Passphrase typed on the terminal, for --keyring
+}
+
+// passphrases is the key source of this process
+var passphrases = &cacheKeys{}
+
+// sessionKeyPath returns the file keeping the key with an ID for the login
+// session; there is none without XDG_RUNTIME_DIR, which is private to the user
+func sessionKeyPath(id string) (string, bool) {
+	runtimeDir := os.Getenv("XDG_RUNTIME_DIR")
+	if runtimeDir == "" {
+		return "", false
+	}
+	return filepath.Join(runtimeDir, "gosynctasks", "cache-key-"+id), true
+}
+
+func (k *cacheKeys) CachedKey(id string) ([]byte, bool) {
+	path, ok := sessionKeyPath(id)
+	if !ok {
+		return nil, false
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, false
+	}
+	key, err := hex.DecodeString(strings.TrimSpace(string(data)))
+	return key, err == nil
+}
+
+func (k *cacheKeys) Passphrase(confirm bool) (string, error) {
+	if !confirm {
+		if passphrase, err := credentials.Get(cacheKeyringName, cacheKeyringUser); err == nil && passphrase != "" {
+			return passphrase, nil
+		}
+	}
+	if !term.IsTerminal(int(syscall.Stdin)) {
+		return "", errors.New("encrypted lists need the cache passphrase: run a command in a terminal to enter it, or store it with 'gosynctasks db encrypt --keyring'")
+	}
+
+	passphrase, err := readPassphrase("Cache passphrase: ")
+	if err != nil {
+		return "", err
+	}
+	if confirm {
+		again, err := readPassphrase("Repeat the passphrase: ")
+		if err != nil {
+			return "", err
+		}
+		if again != passphrase {
+			return "", errors.New("the passphrases do not match")
+		}
+	}
+	k.entered = passphrase
+	return passphrase, nil
+}
+
+func (k *cacheKeys) Remember(id string, key []byte) {
+	path, ok := sessionKeyPath(id)
+	if !ok {
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return
+	}
+	_ = os.WriteFile(path, []byte(hex.EncodeToString(key)), 0600)
+}
+
+// readPassphrase reads a passphrase from the terminal without echoing it
+func readPassphrase(prompt string) (string, error) {
+	fmt.Fprint(os.Stderr, prompt)
+	passphrase, err := term.ReadPassword(int(syscall.Stdin))
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return "", fmt.Errorf("failed to read passphrase: %w", err)
+	}
+	return string(passphrase), nil
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"fmt"
+	"gosynctasks/internal/config"
+	"gosynctasks/internal/operations"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// newCaptureCmd creates the 'in' command for quick task capture
+func newCaptureCmd() *cobra.Command {
+	var listName string
+	var literal bool
+
+	cmd := &cobra.Command{
+		Use:     "in <text>",
+		Aliases: []string{"inbox"},
+		Short:   "Quickly capture a task to the inbox list",
+		Long: `Add a task to the capture list without any prompts.
+
+Inline tokens are parsed out of the text:
+  +tag        adds a tag (anywhere in the text)
+  pN          sets priority N, 1 = highest (at the end)
+  date        sets the due date: today, tomorrow, fri, 14 Jul, 2026-07-14 (at the end)
+  // text     everything after a double slash becomes the description
+
+The task is written locally and pushed by background sync when sync is enabled.
+The capture list is set with capture.list in the config (default: Inbox).
+
+Examples:
+  gosynctasks in "call plumber tomorrow p2 +home"
+  gosynctasks in "renew passport // bring old one and photos"
+  gosynctasks in --literal "p2p networking talk tomorrow"`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			taskManager := application.GetTaskManager()
+			if taskManager == nil {
+				return fmt.Errorf("task manager not initialized")
+			}
+
+			capture, err := operations.ParseCapture(strings.Join(args, " "), literal, time.Now())
+			if err != nil {
+				return err
+			}
+
+			if listName == "" {
+				listName = config.GetConfig().GetCaptureList()
+			}
+
+			message, err := operations.CaptureTask(taskManager, application.GetTaskLists(), listName, capture, application)
+			if err != nil {
+				return err
+			}
+			fmt.Println(message)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&listName, "list", "", "Capture to this list instead of the configured one")
+	cmd.Flags().BoolVar(&literal, "literal", false, "Use the text as the summary without parsing tokens")
+
+	return cmd
+}
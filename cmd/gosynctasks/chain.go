@@ -0,0 +1,107 @@
+package main
+
+import (
+	"fmt"
+	"gosynctasks/internal/operations"
+	"gosynctasks/internal/utils"
+	"io"
+	"slices"
+	"strings"
+)
+
+// chainSeparator splits one invocation into several commands, e.g.
+// gosynctasks Work add "notes" :: Work complete "standup" :: sync
+const chainSeparator = "::"
+
+// chained is set while the steps of a chain run, so that later steps reuse
+// the application the first one initialized
+var chained bool
+
+// splitChain splits args at each separate "::" argument. A quoted argument
+// that merely contains "::" is not a separator. It returns nil for args
+// without a separator.
+func splitChain(args []string) ([][]string, error) {
+	if !slices.Contains(args, chainSeparator) {
+		return nil, nil
+	}
+	var steps [][]string
+	step := []string{}
+	for _, arg := range args {
+		if arg != chainSeparator {
+			step = append(step, arg)
+			continue
+		}
+		steps = append(steps, step)
+		step = []string{}
+	}
+	steps = append(steps, step)
+
+	for i, step := range steps {
+		if len(step) == 0 {
+			return nil, fmt.Errorf("command %d of the chain is empty: put a command between each '%s'", i+1, chainSeparator)
+		}
+	}
+	return steps, nil
+}
+
+// chainResult is how one step of a chain went
+type chainResult struct {
+	args    []string
+	err     error
+	skipped bool
+}
+
+// runChain runs each step as its own command, sharing one application and
+// backend connection, and triggers one sync after the last step for every
+// write made. It stops at the first failed step unless keepGoing, writes a
+// summary of the steps to out and returns the first error.
+func runChain(steps [][]string, keepGoing bool, out io.Writer, execute func(args []string) error) error {
+	chained = true
+	defer func() { chained = false }()
+	release := operations.HoldPushSync()
+	defer release()
+
+	results := make([]chainResult, len(steps))
+	var firstErr error
+	for i, args := range steps {
+		results[i].args = args
+		if firstErr != nil && !keepGoing {
+			results[i].skipped = true
+			continue
+		}
+		if err := execute(args); err != nil {
+			results[i].err = err
+			if firstErr == nil {
+				firstErr = fmt.Errorf("command %d of the chain failed: %w", i+1, err)
+			}
+		}
+	}
+
+	printChainSummary(out, results)
+	return firstErr
+}
+
+// executeStep runs one command of a chain through a new root command
+func executeStep(args []string) error {
+	root := newRootCmd()
+	root.SetArgs(args)
+	invocation = args
+	err := root.Execute()
+	flushNotices()
+	return err
+}
+
+// printChainSummary writes one line per step: ok, failed with the error, or skipped
+func printChainSummary(out io.Writer, results []chainResult) {
+	fmt.Fprintf(out, "\nChain of %d commands:\n", len(results))
+	for i, result := range results {
+		status := "ok"
+		switch {
+		case result.skipped:
+			status = "skipped"
+		case result.err != nil:
+			status = "failed: " + utils.SanitizeLine(result.err.Error())
+		}
+		fmt.Fprintf(out, "  %d. %-40s %s\n", i+1, utils.SanitizeLine(strings.Join(result.args, " ")), status)
+	}
+}
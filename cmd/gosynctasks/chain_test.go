@@ -0,0 +1,119 @@
+package main
+
+import (
+	"bytes"
+	"slices"
+	"strings"
+	"testing"
+
+	"gosynctasks/backend"
+	"gosynctasks/internal/app"
+	"gosynctasks/internal/config"
+)
+
+func TestSplitChain(t *testing.T) {
+	tests := []struct {
+		args    []string
+		want    [][]string
+		wantErr bool
+	}{
+		{args: []string{"Work", "add", "notes"}, want: nil},
+		{args: []string{"Work", "add", "a :: b"}, want: nil}, // Quoted summary
+		{
+			args: []string{"Work", "add", "notes", "-p", "3", "::", "Work", "complete", "standup", "::", "sync"},
+			want: [][]string{{"Work", "add", "notes", "-p", "3"}, {"Work", "complete", "standup"}, {"sync"}},
+		},
+		{args: []string{"Work", "::"}, wantErr: true},
+		{args: []string{"::", "sync"}, wantErr: true},
+		{args: []string{"Work", "::", "::", "sync"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(strings.Join(tt.args, " "), func(t *testing.T) {
+			got, err := splitChain(tt.args)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("splitChain() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !slices.EqualFunc(got, tt.want, slices.Equal) {
+				t.Errorf("splitChain() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// setupChainApp gives the chain an application on a mock backend, as the
+// first command would have initialized it
+func setupChainApp(t *testing.T) *backend.MockBackend {
+	t.Helper()
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	config.SetConfigForTest(&config.Config{})
+	mock := backend.NewMockBackend()
+	mock.Lists = []backend.TaskList{{ID: "work", Name: "Work"}}
+	mock.Tasks["work"] = []backend.Task{{UID: "t1", Summary: "Yesterday's standup", Status: "NEEDS-ACTION"}}
+	application = app.NewAppWithTaskManager("mock", mock, mock.Lists)
+	t.Cleanup(func() { application = nil })
+	return mock
+}
+
+func taskStatus(mock *backend.MockBackend, summary string) string {
+	for _, task := range mock.Tasks["work"] {
+		if task.Summary == summary {
+			return task.Status
+		}
+	}
+	return ""
+}
+
+// TestRunChain runs a chain mixing reads and writes
+func TestRunChain(t *testing.T) {
+	mock := setupChainApp(t)
+	var out bytes.Buffer
+
+	err := runChain([][]string{
+		{"Work", "add", "Standup notes", "-p", "3"},
+		{"Work"},
+		{"Work", "complete", "Yesterday's standup"},
+		{"Work", "get", "-s", "DONE"},
+	}, false, &out, executeStep)
+	if err != nil {
+		t.Fatalf("runChain() error = %v\n%s", err, out.String())
+	}
+
+	if taskStatus(mock, "Standup notes") != "NEEDS-ACTION" || taskStatus(mock, "Yesterday's standup") != "COMPLETED" {
+		t.Errorf("tasks after the chain = %+v", mock.Tasks["work"])
+	}
+	if strings.Count(out.String(), " ok\n") != 4 {
+		t.Errorf("summary does not report 4 successful commands:\n%s", out.String())
+	}
+}
+
+// TestRunChainStopsAtFailure tests the default stop and --keep-going
+func TestRunChainStopsAtFailure(t *testing.T) {
+	steps := func() [][]string {
+		return [][]string{
+			{"Work", "complete", "No such task"},
+			{"Work", "add", "After the failure"},
+		}
+	}
+
+	mock := setupChainApp(t)
+	var out bytes.Buffer
+	if err := runChain(steps(), false, &out, executeStep); err == nil || !strings.Contains(err.Error(), "command 1 of the chain failed") {
+		t.Errorf("runChain() error = %v, want the first command's failure", err)
+	}
+	if taskStatus(mock, "After the failure") != "" {
+		t.Error("command after the failure ran without --keep-going")
+	}
+	if !strings.Contains(out.String(), "1. Work complete No such task") || !strings.Contains(out.String(), "failed: ") || !strings.Contains(out.String(), " skipped\n") {
+		t.Errorf("summary =\n%s", out.String())
+	}
+
+	mock = setupChainApp(t)
+	out.Reset()
+	if err := runChain(steps(), true, &out, executeStep); err == nil {
+		t.Error("runChain() with keepGoing returned no error for the failed command")
+	}
+	if taskStatus(mock, "After the failure") == "" {
+		t.Error("command after the failure did not run with --keep-going")
+	}
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"fmt"
+	"gosynctasks/internal/config"
+
+	"github.com/spf13/cobra"
+)
+
+// newConfigCmd creates the config management command
+func newConfigCmd() *cobra.Command {
+	configCmd := &cobra.Command{
+		Use:   "config",
+		Short: "Manage the configuration file",
+	}
+
+	configCmd.AddCommand(newConfigMigrateCmd())
+
+	return configCmd
+}
+
+// newConfigMigrateCmd creates the 'config migrate' command
+func newConfigMigrateCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "migrate",
+		Short: "Rewrite a legacy config in the multi-backend format",
+		Long: `Rewrite a legacy single-connector config in the multi-backend format.
+
+The 'connector' section becomes a backend named "default", which is also made
+the default backend. Connection flags (insecure_skip_verify, allow_http, ...)
+and all other settings are kept. The original file is saved as <config>.backup.
+
+Legacy configs keep working without migrating, but print a notice on each run.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			configPath, err := config.GetConfigPath()
+			if err != nil {
+				return err
+			}
+
+			backupPath, err := config.MigrateConfigFile(configPath)
+			if err != nil {
+				return err
+			}
+
+			fmt.Printf("Config migrated: %s\n", configPath)
+			fmt.Printf("Original saved as: %s\n", backupPath)
+			return nil
+		},
+	}
+}
@@ -0,0 +1,230 @@
+package main
+
+import (
+	"fmt"
+	"gosynctasks/backend/sqlite"
+	"gosynctasks/internal/config"
+	"gosynctasks/internal/utils"
+	"gosynctasks/internal/version"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// newDBCmd creates the command for inspecting the local sync cache
+func newDBCmd() *cobra.Command {
+	dbCmd := &cobra.Command{
+		Use:   "db",
+		Short: "Inspect and maintain the local sync cache",
+	}
+
+	dbCmd.AddCommand(newDBCheckCmd())
+	dbCmd.AddCommand(newDBStatsCmd())
+	dbCmd.AddCommand(newDBRenameBackendCmd())
+	dbCmd.AddCommand(newDBRestoreBackupCmd())
+	dbCmd.AddCommand(newDBMaintenanceCmd())
+	dbCmd.AddCommand(newDBEncryptCmd())
+	dbCmd.AddCommand(newDBDecryptCmd())
+
+	return dbCmd
+}
+
+// newDBCheckCmd creates the 'db check' command
+func newDBCheckCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "check",
+		Short: "Check the local cache for invalid rows",
+		Long: `Run integrity rules against the local SQLite cache used for sync.
+
+Reports rows that would cause bad requests against the remote, such as tasks
+with an empty UID left behind by an interrupted migration.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			db, err := openCacheDatabase(config.GetConfig())
+			if err != nil {
+				return err
+			}
+			defer func() { _ = db.Close() }()
+
+			issues, err := db.Check()
+			if err != nil {
+				return err
+			}
+			fmt.Println(version.Get())
+			fmt.Printf("Database: %s\n\n", db.Path())
+			if len(issues) == 0 {
+				fmt.Println("No problems found.")
+				return nil
+			}
+
+			for _, issue := range issues {
+				fmt.Printf("  ✗ %s: %d %s\n", issue.Rule, issue.Count, issue.Detail)
+			}
+			return fmt.Errorf("%d check(s) failed", len(issues))
+		},
+	}
+}
+
+// newDBStatsCmd creates the 'db stats' command
+func newDBStatsCmd() *cobra.Command {
+	var jsonOutput bool
+	cmd := &cobra.Command{
+		Use:   "stats",
+		Short: "Show statistics of the local cache",
+		Long: `Show the row count of every table of the local SQLite cache, its file and
+write-ahead log sizes, its indexes and its schema version, along with the
+pending sync operations.
+
+To find slow cache operations, set sync.slow_query_ms in the config: queries
+taking longer are logged with their duration.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			db, err := openCacheDatabase(config.GetConfig())
+			if err != nil {
+				return err
+			}
+			defer func() { _ = db.Close() }()
+
+			stats, err := db.GetStats()
+			if err != nil {
+				return err
+			}
+			if jsonOutput {
+				return utils.OutputJSON(struct {
+					Path string `json:"path"`
+					sqlite.DatabaseStats
+				}{db.Path(), stats})
+			}
+			printDBStats(cmd.OutOrStdout(), db.Path(), stats)
+			return nil
+		},
+	}
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output in JSON format")
+	return cmd
+}
+
+// printDBStats prints cache statistics for 'db stats'
+func printDBStats(w io.Writer, path string, stats sqlite.DatabaseStats) {
+	fmt.Fprintf(w, "Database: %s\n", path)
+	fmt.Fprintf(w, "Schema version: %d\n", stats.SchemaVersion)
+	fmt.Fprintf(w, "Size: %s (write-ahead log: %s)\n", formatBytes(stats.DatabaseSize), formatBytes(stats.WALSize))
+	fmt.Fprintf(w, "Pending sync operations: %d, locally modified tasks: %d\n", stats.PendingSyncOps, stats.LocallyModified)
+	if stats.PrunedTasks > 0 {
+		fmt.Fprintf(w, "Completed tasks pruned by cache.completed_retention: %d (on the remote only)\n", stats.PrunedTasks)
+	}
+
+	tables := slices.Sorted(maps.Keys(stats.TableRows))
+	width := 0
+	for _, table := range tables {
+		width = max(width, len(table))
+	}
+	fmt.Fprintln(w, "\nTables:")
+	for _, table := range tables {
+		fmt.Fprintf(w, "  %-*s  %d rows\n", width, table, stats.TableRows[table])
+	}
+
+	fmt.Fprintln(w, "\nIndexes:")
+	for _, index := range stats.Indexes {
+		fmt.Fprintf(w, "  %s\n", index)
+	}
+}
+
+// formatBytes formats a size in bytes as KB or MB
+func formatBytes(size int64) string {
+	switch {
+	case size >= 1024*1024:
+		return fmt.Sprintf("%.2f MB", float64(size)/(1024*1024))
+	case size >= 1024:
+		return fmt.Sprintf("%.1f KB", float64(size)/1024)
+	}
+	return fmt.Sprintf("%d bytes", size)
+}
+
+// newDBRenameBackendCmd creates the 'db rename-backend' command
+func newDBRenameBackendCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "rename-backend OLD NEW",
+		Short: "Move cached data to a renamed backend",
+		Long: `Move everything the local cache holds for backend OLD to backend NEW.
+
+The cache keys tasks, lists and queued changes by backend name. After a
+backend is renamed in the config its cached data no longer matches, and a
+sync would download every task again and push queued creates as duplicates,
+so sync refuses to run until the data is moved with this command.
+
+Example:
+  gosynctasks db rename-backend nextcloud work-nextcloud`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			oldName, newName := args[0], args[1]
+			cfg := config.GetConfig()
+			if _, err := cfg.GetBackend(newName); err != nil {
+				return fmt.Errorf("%w; rename the backend in the config first", err)
+			}
+
+			db, err := openCacheDatabase(cfg)
+			if err != nil {
+				return err
+			}
+			defer func() { _ = db.Close() }()
+
+			if err := backupCache(cfg, db, "rename-backend", false); err != nil {
+				return err
+			}
+			renamed, err := db.RenameBackend(oldName, newName)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("Moved %d cached rows from backend '%s' to '%s'.\n", renamed, oldName, newName)
+			return nil
+		},
+	}
+}
+
+// openCacheDatabase opens the shared sync cache without selecting a backend
+func openCacheDatabase(cfg *config.Config) (*sqlite.Database, error) {
+	cachePath, err := cfg.GetCacheDatabasePath()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cache database path: %w", err)
+	}
+	db, err := sqlite.InitDatabase(cachePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open cache database: %w", err)
+	}
+	return db, nil
+}
+
+// checkCacheBackendNames refuses to sync while the cache holds data for
+// backends missing from the config, which is what renaming a backend leaves
+func checkCacheBackendNames(cfg *config.Config, db *sqlite.Database) error {
+	configured := make([]string, 0, len(cfg.Backends))
+	for name := range cfg.Backends {
+		configured = append(configured, name)
+	}
+	unknown, err := db.UnknownBackendNames(configured)
+	if err != nil {
+		return err
+	}
+	if len(unknown) == 0 {
+		return nil
+	}
+
+	slices.Sort(configured)
+	return fmt.Errorf(`the sync cache has data for backend %s, which is not in the config (configured: %s).
+If the backend was renamed, syncing would download every task again and push queued tasks as duplicates.
+Move the cached data to the new name first:
+  gosynctasks db rename-backend %s NEW-NAME`,
+		quoteNames(unknown), strings.Join(configured, ", "), unknown[0])
+}
+
+// quoteNames formats names as a quoted, comma-separated list
+func quoteNames(names []string) string {
+	quoted := make([]string, len(names))
+	for i, name := range names {
+		quoted[i] = fmt.Sprintf("%q", name)
+	}
+	return strings.Join(quoted, ", ")
+}
@@ -0,0 +1,190 @@
+package main
+
+import (
+	"fmt"
+	"gosynctasks/backend"
+	"gosynctasks/backend/sqlite"
+	"gosynctasks/internal/config"
+	"gosynctasks/internal/credentials"
+	"gosynctasks/internal/operations"
+	"gosynctasks/internal/utils"
+
+	"github.com/spf13/cobra"
+)
+
+// newDBEncryptCmd creates the 'db encrypt' command
+func newDBEncryptCmd() *cobra.Command {
+	var keyring bool
+
+	cmd := &cobra.Command{
+		Use:   "encrypt [list...]",
+		Short: "Encrypt the cached tasks of lists",
+		Long: `Store the summary, description and categories of the tasks of the given
+lists encrypted in the local cache, and keep them encrypted from now on. The
+first list encrypted sets the passphrase; it is asked for again once per login
+session. The remote still receives plaintext, so sync is unaffected.
+
+Searching an encrypted list decrypts every task of the list, which is slower.
+Background sync can only open encrypted lists once the passphrase was entered
+in the session, or when it is stored in the system keyring with --keyring.
+
+Backups taken before encrypting still hold the plaintext of the lists.
+Without lists, the encrypted lists are shown.
+
+Examples:
+  gosynctasks db encrypt Clients
+  gosynctasks db encrypt Clients Invoices --keyring
+  gosynctasks db encrypt`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cache, lists, err := cacheBackendLists(cmd)
+			if err != nil {
+				return err
+			}
+
+			if len(args) == 0 {
+				return printEncryptedLists(cache, lists)
+			}
+
+			var encrypt []*backend.TaskList
+			for _, name := range args {
+				list, err := operations.FindListByNameFull(lists, name)
+				if err != nil {
+					return fmt.Errorf("list '%s' not found in the cache: %w", name, err)
+				}
+				encrypt = append(encrypt, list)
+			}
+			for _, list := range encrypt {
+				if err := cache.EncryptList(list.ID); err != nil {
+					return fmt.Errorf("failed to encrypt '%s': %w", list.QualifiedName(), err)
+				}
+				fmt.Printf("Encrypted '%s'\n", list.QualifiedName())
+			}
+
+			// Rewriting the file drops the plaintext left in freed pages
+			db, err := cache.GetDB()
+			if err != nil {
+				return err
+			}
+			if err := db.Vacuum(); err != nil {
+				utils.Debugf("vacuum after encrypting failed: %v", err)
+			}
+
+			if keyring {
+				return storeCachePassphrase(db)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&keyring, "keyring", false, "Store the passphrase in the system keyring for background sync")
+	return cmd
+}
+
+// newDBDecryptCmd creates the 'db decrypt' command
+func newDBDecryptCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "decrypt <list...>",
+		Short: "Store the cached tasks of encrypted lists as plaintext again",
+		Long: `Decrypt the cached tasks of the given encrypted lists and store them as
+plaintext from now on. The cache is backed up first.
+
+Example:
+  gosynctasks db decrypt Clients`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cache, lists, err := cacheBackendLists(cmd)
+			if err != nil {
+				return err
+			}
+
+			var decrypt []*backend.TaskList
+			for _, name := range args {
+				list, err := operations.FindListByNameFull(lists, name)
+				if err != nil {
+					return fmt.Errorf("list '%s' not found in the cache: %w", name, err)
+				}
+				decrypt = append(decrypt, list)
+			}
+
+			db, err := cache.GetDB()
+			if err != nil {
+				return err
+			}
+			if err := backupCache(config.GetConfig(), db, "decrypt", false); err != nil {
+				return err
+			}
+			for _, list := range decrypt {
+				if err := cache.DecryptList(list.ID); err != nil {
+					return fmt.Errorf("failed to decrypt '%s': %w", list.QualifiedName(), err)
+				}
+				fmt.Printf("Decrypted '%s'\n", list.QualifiedName())
+			}
+			return nil
+		},
+	}
+}
+
+// cacheBackendLists returns the cache of the selected backend and its lists
+func cacheBackendLists(cmd *cobra.Command) (*sqlite.SQLiteBackend, []backend.TaskList, error) {
+	cfg := config.GetConfig()
+	if len(cfg.GetSyncPairs()) == 0 && (cfg.Sync == nil || !cfg.Sync.Enabled) {
+		return nil, nil, utils.ErrSyncNotEnabled()
+	}
+
+	explicitBackend, _ := cmd.Root().PersistentFlags().GetString("backend")
+	cache, _, err := getSyncBackends(cfg, explicitBackend)
+	if err != nil {
+		return nil, nil, err
+	}
+	lists, err := cache.GetTaskLists()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get cached lists: %w", err)
+	}
+	return cache, lists, nil
+}
+
+// printEncryptedLists shows the lists of the cache stored encrypted
+func printEncryptedLists(cache *sqlite.SQLiteBackend, lists []backend.TaskList) error {
+	listIDs, err := cache.EncryptedLists()
+	if err != nil {
+		return err
+	}
+	if len(listIDs) == 0 {
+		fmt.Println("No lists are encrypted.")
+		return nil
+	}
+
+	names := make(map[string]string, len(lists))
+	for _, list := range lists {
+		names[list.ID] = list.QualifiedName()
+	}
+	fmt.Println("Encrypted lists:")
+	for _, listID := range listIDs {
+		name, ok := names[listID]
+		if !ok {
+			name = listID
+		}
+		fmt.Printf("  %s\n", name)
+	}
+	return nil
+}
+
+// storeCachePassphrase stores the passphrase of the encrypted lists in the
+// system keyring, asking for it when it was not entered in this run
+func storeCachePassphrase(db *sqlite.Database) error {
+	passphrase := passphrases.entered
+	if passphrase == "" {
+		var err error
+		if passphrase, err = readPassphrase("Cache passphrase: "); err != nil {
+			return err
+		}
+		if err := db.CheckPassphrase(passphrase); err != nil {
+			return err
+		}
+	}
+	if err := credentials.Set(cacheKeyringName, cacheKeyringUser, passphrase); err != nil {
+		return fmt.Errorf("failed to store the passphrase in the keyring: %w", err)
+	}
+	fmt.Println("Stored the cache passphrase in the system keyring")
+	return nil
+}
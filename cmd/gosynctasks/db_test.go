@@ -0,0 +1,73 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gosynctasks/backend"
+	"gosynctasks/backend/sqlite"
+	"gosynctasks/internal/config"
+)
+
+// TestCheckCacheBackendNames tests that sync is refused while the cache holds
+// data of a backend that was renamed in the config
+func TestCheckCacheBackendNames(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "cache.db")
+	cache, err := sqlite.NewSQLiteBackend(backend.BackendConfig{Name: "nextcloud", Type: "sqlite", DBPath: dbPath})
+	if err != nil {
+		t.Fatalf("NewSQLiteBackend() error = %v", err)
+	}
+	defer func() { _ = cache.Close() }()
+	listID, _ := cache.CreateTaskList("Work", "", "")
+	_, _ = cache.AddTask(listID, backend.Task{Summary: "Queued", Status: "NEEDS-ACTION"})
+	db, _ := cache.GetDB()
+
+	cfg := &config.Config{Backends: map[string]backend.BackendConfig{"cloud": {Type: "nextcloud"}}}
+	err = checkCacheBackendNames(cfg, db)
+	if err == nil || !strings.Contains(err.Error(), "gosynctasks db rename-backend nextcloud NEW-NAME") {
+		t.Fatalf("checkCacheBackendNames() error = %v, want a rename-backend hint", err)
+	}
+
+	if _, err := db.RenameBackend("nextcloud", "cloud"); err != nil {
+		t.Fatalf("RenameBackend() error = %v", err)
+	}
+	if err := checkCacheBackendNames(cfg, db); err != nil {
+		t.Errorf("checkCacheBackendNames() after rename error = %v", err)
+	}
+}
+
+// TestPrintDBStats tests the 'db stats' output for a seeded cache
+func TestPrintDBStats(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "cache.db")
+	cache, err := sqlite.NewSQLiteBackend(backend.BackendConfig{Name: "nextcloud", Type: "sqlite", DBPath: dbPath})
+	if err != nil {
+		t.Fatalf("NewSQLiteBackend() error = %v", err)
+	}
+	defer func() { _ = cache.Close() }()
+	listID, _ := cache.CreateTaskList("Work", "", "")
+	_, _ = cache.AddTask(listID, backend.Task{Summary: "Queued", Status: "NEEDS-ACTION"})
+	_, _ = cache.AddTask(listID, backend.Task{Summary: "Also queued", Status: "NEEDS-ACTION"})
+	db, _ := cache.GetDB()
+
+	stats, err := db.GetStats()
+	if err != nil {
+		t.Fatalf("GetStats() error = %v", err)
+	}
+	var out strings.Builder
+	printDBStats(&out, db.Path(), stats)
+
+	for _, want := range []string{
+		"Database: " + dbPath,
+		fmt.Sprintf("Schema version: %d", sqlite.SchemaVersion),
+		"Pending sync operations: 2, locally modified tasks: 2",
+		"  tasks               2 rows",
+		"  list_sync_metadata  1 rows",
+		"Indexes:",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("output lacks %q:\n%s", want, out.String())
+		}
+	}
+}
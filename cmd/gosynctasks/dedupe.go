@@ -0,0 +1,111 @@
+package main
+
+import (
+	"fmt"
+	"gosynctasks/backend"
+	"gosynctasks/internal/operations"
+	"gosynctasks/internal/utils"
+
+	"github.com/spf13/cobra"
+)
+
+// newDedupeCmd creates the 'dedupe' command for repairing duplicated tasks
+func newDedupeCmd() *cobra.Command {
+	var byUID bool
+	var dryRun bool
+	var yes bool
+
+	cmd := &cobra.Command{
+		Use:   "dedupe [list]",
+		Short: "Repair tasks that share a UID",
+		Long: `Find tasks that share a UID in one list, or all lists, and give every copy
+but the first its own UID.
+
+A misbehaving server can return several tasks with the same UID. Updates to
+such tasks are refused, and sync keeps only the first copy in the cache, until
+they are repaired. The first copy keeps the UID, so its subtasks stay attached;
+the other copies are re-added with the UID plus a suffix ("-2", "-3").
+
+With sync enabled, use --backend to repair the remote backend directly.
+
+Examples:
+  gosynctasks dedupe --by-uid --dry-run
+  gosynctasks --backend nextcloud dedupe Work --by-uid`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if !byUID {
+				return fmt.Errorf("specify what to deduplicate by: --by-uid")
+			}
+
+			taskManager := application.GetTaskManager()
+			if taskManager == nil {
+				return fmt.Errorf("task manager not initialized")
+			}
+
+			lists := application.GetTaskLists()
+			if len(args) == 1 {
+				list, err := operations.FindListByNameFull(lists, args[0])
+				if err != nil {
+					return err
+				}
+				lists = []backend.TaskList{*list}
+			}
+
+			groups, err := operations.FindDuplicateUIDs(taskManager, lists)
+			if err != nil {
+				return err
+			}
+			if len(groups) == 0 {
+				fmt.Println("No duplicated task UIDs found.")
+				return nil
+			}
+
+			for _, group := range groups {
+				fmt.Printf("[%s] %s (%d copies)\n", utils.SanitizeLine(group.ListName), utils.SanitizeLine(group.UID), len(group.Tasks))
+				for _, task := range group.Tasks {
+					fmt.Printf("    %s\n", utils.SanitizeLine(task.Summary))
+				}
+			}
+
+			if dryRun {
+				fmt.Println("Dry run: no changes made.")
+				return nil
+			}
+
+			if !yes {
+				confirmed, err := utils.PromptConfirmation("Give the extra copies new UIDs?")
+				if err != nil {
+					return err
+				}
+				if !confirmed {
+					fmt.Println("Dedupe cancelled.")
+					return nil
+				}
+			}
+
+			failed := 0
+			for _, group := range groups {
+				newUIDs, err := operations.DedupeByUID(taskManager, group)
+				if err != nil {
+					failed++
+					fmt.Printf("  ✗ %s: %v\n", utils.SanitizeLine(group.UID), err)
+					continue
+				}
+				for _, uid := range newUIDs {
+					fmt.Printf("  ✓ %s -> %s\n", utils.SanitizeLine(group.UID), utils.SanitizeLine(uid))
+				}
+			}
+
+			if failed > 0 {
+				return fmt.Errorf("%d duplicated UID(s) could not be repaired", failed)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&byUID, "by-uid", false, "Repair tasks that share a UID")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Show duplicates without changing them")
+	cmd.Flags().BoolVarP(&yes, "yes", "y", false, "Repair without confirmation")
+
+	return cmd
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"gosynctasks/backend"
+	"gosynctasks/backend/sqlite"
+	"gosynctasks/internal/testutil"
+
+	"github.com/spf13/cobra"
+)
+
+// newDevCmd creates the hidden 'dev' command with tools for developing gosynctasks
+func newDevCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:    "dev",
+		Hidden: true,
+		Short:  "Tools for developing gosynctasks",
+		// Skip the root's backend setup; dev tools work on their own files
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			return nil
+		},
+	}
+	cmd.AddCommand(newDevSeedCmd())
+	return cmd
+}
+
+// newDevSeedCmd creates the 'dev seed' command
+func newDevSeedCmd() *cobra.Command {
+	opts := testutil.DefaultDatasetOptions()
+	var dbPath, backendName string
+
+	cmd := &cobra.Command{
+		Use:   "seed --db <path>",
+		Short: "Fill a new SQLite database with a synthetic dataset",
+		Long: `Fill a new SQLite database with generated tasks for trying out performance
+and display with realistic amounts of data: priorities, due dates around
+today, completed tasks, subtasks, non-ASCII summaries, long descriptions and
+categories. The same seed on the same day gives the same dataset.
+
+To browse it, add a sqlite backend with this db_path and name to the config.
+
+Examples:
+  gosynctasks dev seed --db /tmp/seed.db
+  gosynctasks dev seed --tasks 5000 --lists 10 --hierarchy-depth 3 --db /tmp/big.db`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if _, err := os.Stat(dbPath); err == nil {
+				return fmt.Errorf("%s already exists; seed only creates new databases", dbPath)
+			}
+
+			db, err := sqlite.NewSQLiteBackend(backend.BackendConfig{Name: backendName, Type: "sqlite", Enabled: true, DBPath: dbPath})
+			if err != nil {
+				return err
+			}
+			defer func() { _ = db.Close() }()
+
+			opts.Now = time.Now().Truncate(24 * time.Hour)
+			dataset := testutil.GenerateDataset(opts)
+			if err := dataset.WriteTo(db); err != nil {
+				return err
+			}
+			fmt.Printf("Wrote %d tasks in %d lists to %s (backend name %q)\n", dataset.Count(), len(dataset.Lists), dbPath, backendName)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&dbPath, "db", "", "SQLite database to create")
+	cmd.Flags().StringVar(&backendName, "backend-name", "sqlite", "backend name the tasks are stored under")
+	cmd.Flags().IntVar(&opts.Tasks, "tasks", opts.Tasks, "number of tasks")
+	cmd.Flags().IntVar(&opts.Lists, "lists", opts.Lists, "number of lists")
+	cmd.Flags().IntVar(&opts.HierarchyDepth, "hierarchy-depth", opts.HierarchyDepth, "deepest subtask level (0 for no subtasks)")
+	cmd.Flags().Int64Var(&opts.Seed, "seed", opts.Seed, "random seed")
+	_ = cmd.MarkFlagRequired("db")
+
+	return cmd
+}
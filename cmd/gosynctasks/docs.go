@@ -0,0 +1,450 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"gosynctasks/internal/operations"
+	"gosynctasks/internal/version"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// rootIntro is the description of the root command, above its actions
+const rootIntro = `Task synchronization tool for managing tasks across different backends.`
+
+// rootExamples are examples of the root command not about a single action
+var rootExamples = []operations.Example{
+	{Command: "gosynctasks all -s TODO", Comment: "Tasks of every list, grouped by list"},
+	{Command: `gosynctasks all complete "Buy groceries"`, Comment: "Complete the one task matching in any list"},
+	{Command: "gosynctasks --list all get", Comment: `A list really named "all"`},
+	{Command: `gosynctasks Work add "Standup notes" :: Work complete "Standup" :: sync`, Comment: "Several commands, one sync"},
+}
+
+// rootConfigHelp ends the root command's help
+const rootConfigHelp = `Config:
+  --config .                            # Use ./gosynctasks/config.json
+  --config /path/to/config.json         # Use specific config file
+  --config /path/to/dir                 # Use /path/to/dir/config.json
+`
+
+// rootLongHelp returns the root command's help, with the actions and their
+// examples taken from the action registry
+func rootLongHelp() string {
+	var b strings.Builder
+	b.WriteString(rootIntro + "\n\nActions (abbreviations in parentheses):\n")
+	for _, action := range operations.Actions {
+		name := action.Name
+		if action.Alias != "" {
+			name += " (" + action.Alias + ")"
+		}
+		fmt.Fprintf(&b, "  %-13s - %s\n", name, action.Short)
+	}
+
+	b.WriteString("\nExamples:\n")
+	for _, action := range operations.Actions {
+		b.WriteString(formatExamples(action.Examples, "  ") + "\n")
+	}
+	b.WriteString(formatExamples(rootExamples, "  ") + "\n")
+	b.WriteString(rootConfigHelp)
+	return b.String()
+}
+
+// exampleWidth is the widest command that example comments are aligned
+// after; longer commands are followed by their comment directly
+const exampleWidth = 37
+
+// formatExamples renders examples one per line, their comments aligned
+func formatExamples(examples []operations.Example, indent string) string {
+	width := 0
+	for _, example := range examples {
+		if len(example.Command) <= exampleWidth {
+			width = max(width, len(example.Command))
+		}
+	}
+	var b strings.Builder
+	for _, example := range examples {
+		line := indent + example.Command
+		if example.Comment != "" {
+			line = fmt.Sprintf("%s%-*s  # %s", indent, width, example.Command, example.Comment)
+		}
+		b.WriteString(line + "\n")
+	}
+	return b.String()
+}
+
+// newDocsCmd creates the hidden 'docs' command generating man pages and
+// markdown from the commands, flags and action registry
+func newDocsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:    "docs",
+		Short:  "Generate man pages or markdown documentation",
+		Hidden: true,
+		// Generating docs needs no config or backend
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error { return nil },
+	}
+
+	manCmd := &cobra.Command{
+		Use:   "man",
+		Short: "Write gosynctasks.1 and a page per action and command",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out, _ := cmd.Flags().GetString("out")
+			return writeDocs(cmd.Root(), out, manPages)
+		},
+	}
+	manCmd.Flags().String("out", "./man/", "directory to write the pages to")
+
+	markdownCmd := &cobra.Command{
+		Use:   "markdown",
+		Short: "Write a markdown page per action and command, for the website",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out, _ := cmd.Flags().GetString("out")
+			return writeDocs(cmd.Root(), out, markdownPages)
+		},
+	}
+	markdownCmd.Flags().String("out", "./docs/cli/", "directory to write the pages to")
+
+	cmd.AddCommand(manCmd, markdownCmd)
+	return cmd
+}
+
+// docPage is one page of documentation, for the root command, an action or
+// a subcommand
+type docPage struct {
+	name        string // Command path, like "gosynctasks sync rescue"
+	short       string
+	description string
+	synopsis    string
+	examples    []operations.Example
+	exampleText string // Free-form examples of a subcommand
+	flags       *pflag.FlagSet
+	global      *pflag.FlagSet // Flags of every command, on the root page only
+	actions     []operations.ActionSpec
+	seeAlso     []string // Command paths
+}
+
+// docPages returns the pages of the root command, its actions and every
+// available subcommand
+func docPages(root *cobra.Command) ([]docPage, error) {
+	pages := []docPage{{
+		name:        root.Name(),
+		short:       root.Short,
+		description: rootIntro,
+		synopsis:    root.UseLine(),
+		examples:    rootExamples,
+		flags:       root.LocalNonPersistentFlags(),
+		global:      root.PersistentFlags(),
+		actions:     operations.Actions,
+	}}
+
+	for _, action := range operations.Actions {
+		flags, err := actionFlags(root, action)
+		if err != nil {
+			return nil, err
+		}
+		synopsis := fmt.Sprintf("%s [list-name] %s", root.Name(), action.Name)
+		if action.Args != "" {
+			synopsis += " " + action.Args
+		}
+		pages = append(pages, docPage{
+			name:        root.Name() + " " + action.Name,
+			short:       action.Short,
+			description: action.Description,
+			synopsis:    synopsis + " [flags]",
+			examples:    action.Examples,
+			flags:       flags,
+			seeAlso:     []string{root.Name()},
+		})
+		pages[0].seeAlso = append(pages[0].seeAlso, root.Name()+" "+action.Name)
+	}
+
+	var walk func(cmd *cobra.Command)
+	walk = func(cmd *cobra.Command) {
+		for _, sub := range cmd.Commands() {
+			if !sub.IsAvailableCommand() || sub.IsAdditionalHelpTopicCommand() {
+				continue
+			}
+			page := docPage{
+				name:        sub.CommandPath(),
+				short:       sub.Short,
+				description: sub.Long,
+				synopsis:    sub.UseLine(),
+				exampleText: sub.Example,
+				flags:       sub.NonInheritedFlags(),
+				seeAlso:     []string{cmd.CommandPath()},
+			}
+			if page.description == "" {
+				page.description = sub.Short
+			}
+			for _, child := range sub.Commands() {
+				if child.IsAvailableCommand() {
+					page.seeAlso = append(page.seeAlso, child.CommandPath())
+				}
+			}
+			if cmd == root {
+				pages[0].seeAlso = append(pages[0].seeAlso, sub.CommandPath())
+			}
+			pages = append(pages, page)
+			walk(sub)
+		}
+	}
+	walk(root)
+	return pages, nil
+}
+
+// actionFlags returns the root command flags an action reads
+func actionFlags(root *cobra.Command, action operations.ActionSpec) (*pflag.FlagSet, error) {
+	flags := pflag.NewFlagSet(action.Name, pflag.ContinueOnError)
+	flags.SortFlags = false
+	for _, name := range action.Flags {
+		flag := root.Flags().Lookup(name)
+		if flag == nil {
+			return nil, fmt.Errorf("action %s documents flag --%s, which does not exist", action.Name, name)
+		}
+		flags.AddFlag(flag)
+	}
+	return flags, nil
+}
+
+// writeDocs renders every page with render into the directory out
+func writeDocs(root *cobra.Command, out string, render func([]docPage) map[string][]byte) error {
+	pages, err := docPages(root)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(out, 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", out, err)
+	}
+	for name, content := range render(pages) {
+		if err := os.WriteFile(filepath.Join(out, name), content, 0644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// paragraphs splits text on blank lines. Indented lines are examples, to
+// be rendered verbatim: a paragraph of them, or those below a heading line
+// like "Examples:".
+func paragraphs(text string) (blocks []string, verbatim []bool) {
+	indented := func(line string) bool {
+		return strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")
+	}
+	for _, block := range strings.Split(strings.Trim(text, "\n"), "\n\n") {
+		lines := strings.Split(strings.Trim(block, "\n"), "\n")
+		if lines[0] == "" {
+			continue
+		}
+		rest := lines[1:]
+		if !indented(lines[0]) && len(rest) > 0 && !slices.ContainsFunc(rest, func(line string) bool { return !indented(line) }) {
+			blocks = append(blocks, lines[0], dedent(rest))
+			verbatim = append(verbatim, false, true)
+			continue
+		}
+		if slices.ContainsFunc(lines, func(line string) bool { return !indented(line) }) {
+			blocks = append(blocks, strings.Join(lines, "\n"))
+			verbatim = append(verbatim, false)
+			continue
+		}
+		blocks = append(blocks, dedent(lines))
+		verbatim = append(verbatim, true)
+	}
+	return blocks, verbatim
+}
+
+// dedent removes the indentation lines have in common
+func dedent(lines []string) string {
+	common := -1
+	for _, line := range lines {
+		if indent := len(line) - len(strings.TrimLeft(line, " \t")); common < 0 || indent < common {
+			common = indent
+		}
+	}
+	out := make([]string, len(lines))
+	for i, line := range lines {
+		out[i] = line[common:]
+	}
+	return strings.Join(out, "\n")
+}
+
+// manDate is the date of the man pages: SOURCE_DATE_EPOCH for reproducible
+// package builds, today otherwise
+func manDate() string {
+	if epoch, err := strconv.ParseInt(os.Getenv("SOURCE_DATE_EPOCH"), 10, 64); err == nil {
+		return time.Unix(epoch, 0).UTC().Format("Jan 2006")
+	}
+	return time.Now().Format("Jan 2006")
+}
+
+// manPages renders the pages as roff man pages, gosynctasks-sync-rescue.1
+func manPages(pages []docPage) map[string][]byte {
+	files := make(map[string][]byte, len(pages))
+	header := fmt.Sprintf(`"%s" "gosynctasks %s" "User Commands"`, manDate(), version.Get().Version)
+	for _, page := range pages {
+		title := strings.ReplaceAll(page.name, " ", "-")
+		var b bytes.Buffer
+		fmt.Fprintf(&b, ".TH \"%s\" \"1\" %s\n", strings.ToUpper(title), header)
+		fmt.Fprintf(&b, ".SH NAME\n%s \\- %s\n", roffEscape(title), roffEscape(page.short))
+		fmt.Fprintf(&b, ".SH SYNOPSIS\n\\fB%s\\fP\n", roffEscape(page.synopsis))
+
+		b.WriteString(".SH DESCRIPTION\n")
+		writeRoffText(&b, page.description)
+
+		if len(page.actions) > 0 {
+			b.WriteString(".SH ACTIONS\n")
+			for _, action := range page.actions {
+				name := `\fB` + action.Name + `\fP`
+				if action.Alias != "" {
+					name += ", " + `\fB` + action.Alias + `\fP`
+				}
+				fmt.Fprintf(&b, ".TP\n%s\n%s\n", name, roffEscape(action.Short))
+			}
+		}
+		writeRoffFlags(&b, "OPTIONS", page.flags)
+		writeRoffFlags(&b, "GLOBAL OPTIONS", page.global)
+
+		if len(page.examples) > 0 || page.exampleText != "" {
+			b.WriteString(".SH EXAMPLES\n")
+			if len(page.examples) > 0 {
+				writeRoffVerbatim(&b, formatExamples(page.examples, ""))
+			}
+			if page.exampleText != "" {
+				writeRoffText(&b, page.exampleText)
+			}
+		}
+
+		if len(page.seeAlso) > 0 {
+			refs := make([]string, len(page.seeAlso))
+			for i, name := range page.seeAlso {
+				refs[i] = `\fB` + roffEscape(strings.ReplaceAll(name, " ", "-")) + `\fP(1)`
+			}
+			fmt.Fprintf(&b, ".SH SEE ALSO\n%s\n", strings.Join(refs, ", "))
+		}
+		files[title+".1"] = b.Bytes()
+	}
+	return files
+}
+
+// roffEscape escapes text for roff: backslashes and minus signs, and a dot
+// or quote starting a line, which roff would take for a request
+func roffEscape(text string) string {
+	text = strings.ReplaceAll(text, `\`, `\e`)
+	text = strings.ReplaceAll(text, "-", `\-`)
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		if strings.HasPrefix(line, ".") || strings.HasPrefix(line, "'") {
+			lines[i] = `\&` + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// writeRoffText writes paragraphs, indented ones as verbatim blocks
+func writeRoffText(b *bytes.Buffer, text string) {
+	blocks, verbatim := paragraphs(text)
+	for i, block := range blocks {
+		if verbatim[i] {
+			writeRoffVerbatim(b, block)
+			continue
+		}
+		fmt.Fprintf(b, ".PP\n%s\n", roffEscape(block))
+	}
+}
+
+// writeRoffVerbatim writes lines as they are, without filling or adjusting
+func writeRoffVerbatim(b *bytes.Buffer, text string) {
+	fmt.Fprintf(b, ".PP\n.RS\n.nf\n%s\n.fi\n.RE\n", roffEscape(strings.TrimRight(text, "\n")))
+}
+
+// writeRoffFlags writes a section describing flags, if there are any
+func writeRoffFlags(b *bytes.Buffer, section string, flags *pflag.FlagSet) {
+	if flags == nil || !flags.HasAvailableFlags() {
+		return
+	}
+	fmt.Fprintf(b, ".SH %s\n", section)
+	flags.VisitAll(func(flag *pflag.Flag) {
+		if flag.Hidden {
+			return
+		}
+		name := `\fB\-\-` + roffEscape(flag.Name) + `\fP`
+		if flag.Shorthand != "" {
+			name = `\fB\-` + flag.Shorthand + `\fP, ` + name
+		}
+		if flag.Value.Type() != "bool" {
+			name += `=\fI` + flag.Value.Type() + `\fP`
+		}
+		fmt.Fprintf(b, ".TP\n%s\n%s\n", name, roffEscape(flag.Usage))
+	})
+}
+
+// markdownPages renders the pages as markdown, gosynctasks_sync_rescue.md
+func markdownPages(pages []docPage) map[string][]byte {
+	files := make(map[string][]byte, len(pages))
+	link := func(name string) string {
+		return fmt.Sprintf("[%s](%s.md)", name, strings.ReplaceAll(name, " ", "_"))
+	}
+	for _, page := range pages {
+		var b bytes.Buffer
+		fmt.Fprintf(&b, "## %s\n\n%s\n\n### Synopsis\n\n", page.name, page.short)
+		writeMarkdownText(&b, page.description)
+		fmt.Fprintf(&b, "```\n%s\n```\n\n", page.synopsis)
+
+		if len(page.actions) > 0 {
+			b.WriteString("### Actions\n\n")
+			for _, action := range page.actions {
+				name := action.Name
+				if action.Alias != "" {
+					name += " (" + action.Alias + ")"
+				}
+				fmt.Fprintf(&b, "* [%s](%s_%s.md) - %s\n", name, strings.ReplaceAll(pages[0].name, " ", "_"), action.Name, action.Short)
+			}
+			b.WriteString("\n")
+		}
+
+		if len(page.examples) > 0 || page.exampleText != "" {
+			b.WriteString("### Examples\n\n")
+			if len(page.examples) > 0 {
+				fmt.Fprintf(&b, "```bash\n%s```\n\n", formatExamples(page.examples, ""))
+			}
+			if page.exampleText != "" {
+				writeMarkdownText(&b, page.exampleText)
+			}
+		}
+
+		if page.flags != nil && page.flags.HasAvailableFlags() {
+			fmt.Fprintf(&b, "### Options\n\n```\n%s```\n\n", page.flags.FlagUsages())
+		}
+		if page.global != nil && page.global.HasAvailableFlags() {
+			fmt.Fprintf(&b, "### Global options\n\n```\n%s```\n\n", page.global.FlagUsages())
+		}
+
+		if len(page.seeAlso) > 0 {
+			b.WriteString("### SEE ALSO\n\n")
+			for _, name := range page.seeAlso {
+				fmt.Fprintf(&b, "* %s\n", link(name))
+			}
+		}
+		files[strings.ReplaceAll(page.name, " ", "_")+".md"] = b.Bytes()
+	}
+	return files
+}
+
+// writeMarkdownText writes paragraphs, indented ones as code blocks
+func writeMarkdownText(b *bytes.Buffer, text string) {
+	blocks, verbatim := paragraphs(text)
+	for i, block := range blocks {
+		if verbatim[i] {
+			fmt.Fprintf(b, "```\n%s\n```\n\n", block)
+			continue
+		}
+		fmt.Fprintf(b, "%s\n\n", block)
+	}
+}
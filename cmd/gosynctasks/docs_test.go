@@ -0,0 +1,82 @@
+package main
+
+import (
+	"gosynctasks/internal/operations"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestDocsGenerated regenerates the man pages and markdown docs into a
+// temporary directory, and checks every action is documented with a
+// description, an example and only flags that exist
+func TestDocsGenerated(t *testing.T) {
+	for _, action := range operations.Actions {
+		if strings.TrimSpace(action.Short) == "" || strings.TrimSpace(action.Description) == "" {
+			t.Errorf("action %s has no description", action.Name)
+		}
+		if len(action.Examples) == 0 {
+			t.Errorf("action %s has no example", action.Name)
+		}
+	}
+
+	root := newRootCmd()
+	for _, format := range []struct {
+		render func([]docPage) map[string][]byte
+		page   func(name string) string
+	}{
+		{manPages, func(name string) string { return strings.ReplaceAll(name, " ", "-") + ".1" }},
+		{markdownPages, func(name string) string { return strings.ReplaceAll(name, " ", "_") + ".md" }},
+	} {
+		out := t.TempDir()
+		if err := writeDocs(root, out, format.render); err != nil {
+			t.Fatalf("writeDocs() error = %v", err)
+		}
+
+		pages := []string{"gosynctasks", "gosynctasks sync rescue"}
+		for _, action := range operations.Actions {
+			pages = append(pages, "gosynctasks "+action.Name)
+		}
+		for _, name := range pages {
+			content, err := os.ReadFile(filepath.Join(out, format.page(name)))
+			if err != nil {
+				t.Errorf("no page for %q: %v", name, err)
+				continue
+			}
+			if !strings.Contains(string(content), "gosynctasks") {
+				t.Errorf("page %s does not name the command", format.page(name))
+			}
+		}
+	}
+}
+
+func TestRoffEscape(t *testing.T) {
+	tests := map[string]string{
+		"--to":                      `\-\-to`,
+		`C:\tasks`:                  `C:\etasks`,
+		".hidden line":              `\&.hidden line`,
+		"first\n'quoted' at start":  "first\n" + `\&'quoted' at start`,
+		"mid-line . and ' are fine": `mid\-line . and ' are fine`,
+	}
+	for in, want := range tests {
+		if got := roffEscape(in); got != want {
+			t.Errorf("roffEscape(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestParagraphsKeepExamplesVerbatim(t *testing.T) {
+	text := "Move the tasks.\n\nExamples:\n  gosynctasks sync rescue Work\n    --to Inbox\n\n  gosynctasks sync"
+	blocks, verbatim := paragraphs(text)
+	want := []string{"Move the tasks.", "Examples:", "gosynctasks sync rescue Work\n  --to Inbox", "gosynctasks sync"}
+	wantVerbatim := []bool{false, false, true, true}
+	if len(blocks) != len(want) {
+		t.Fatalf("paragraphs() = %q, want %q", blocks, want)
+	}
+	for i := range want {
+		if blocks[i] != want[i] || verbatim[i] != wantVerbatim[i] {
+			t.Errorf("block %d = %q (verbatim %v), want %q (verbatim %v)", i, blocks[i], verbatim[i], want[i], wantVerbatim[i])
+		}
+	}
+}
@@ -3,14 +3,18 @@ package main
 import (
 	"fmt"
 	"gosynctasks/backend"
+	"gosynctasks/internal/config"
 	"gosynctasks/internal/operations"
 	"gosynctasks/internal/utils"
+	"slices"
 
 	"github.com/spf13/cobra"
 )
 
 // newListCmd creates the list management command with all subcommands
 func newListCmd() *cobra.Command {
+	var includeArchived bool
+
 	listCmd := &cobra.Command{
 		Use:   "list",
 		Short: "Manage task lists",
@@ -33,10 +37,23 @@ Examples:
 
   gosynctasks list info "Work Tasks"                    # Show list details
   gosynctasks list info --all                           # Show all lists with details
-  gosynctasks list info "Work Tasks" --json             # JSON output`,
+  gosynctasks list info "Work Tasks" --json             # JSON output
+
+  gosynctasks list pin "Work Tasks"                     # Show first in pickers and completion
+  gosynctasks list unpin "Work Tasks"
+
+  gosynctasks list --include-archived                   # Also show archived lists (Todoist)
+  gosynctasks list unarchive "Old Project"              # Make an archived list active again`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			// Default action: show all lists (simple view)
 			taskLists := application.GetTaskLists()
+			if includeArchived {
+				archived, _, err := application.ArchivedTaskLists()
+				if err != nil {
+					return fmt.Errorf("failed to get archived lists: %w", err)
+				}
+				taskLists = append(slices.Clone(taskLists), archived...)
+			}
 			if len(taskLists) == 0 {
 				fmt.Println("No task lists found.")
 				return nil
@@ -44,23 +61,33 @@ Examples:
 
 			fmt.Println("\nAvailable task lists:")
 			for _, list := range taskLists {
+				name := list.QualifiedName()
+				if list.Archived {
+					name += " (archived)"
+				}
 				if list.Description != "" {
-					fmt.Printf("  • %s - %s\n", list.Name, list.Description)
+					fmt.Printf("  • %s - %s\n", name, list.Description)
 				} else {
-					fmt.Printf("  • %s\n", list.Name)
+					fmt.Printf("  • %s\n", name)
 				}
 			}
 			fmt.Println()
 			return nil
 		},
 	}
+	listCmd.Flags().BoolVar(&includeArchived, "include-archived", false, "Also show the lists the backend archived")
 
 	// Add subcommands
 	listCmd.AddCommand(newListCreateCmd())
 	listCmd.AddCommand(newListDeleteCmd())
 	listCmd.AddCommand(newListRenameCmd())
+	listCmd.AddCommand(newListMergeCmd())
 	listCmd.AddCommand(newListInfoCmd())
 	listCmd.AddCommand(newListTrashCmd())
+	listCmd.AddCommand(newListAdoptCmd())
+	listCmd.AddCommand(newListUnarchiveCmd())
+	listCmd.AddCommand(newListPinCmd())
+	listCmd.AddCommand(newListUnpinCmd())
 
 	return listCmd
 }
@@ -242,6 +269,245 @@ By default, prompts for confirmation.`,
 	return cmd
 }
 
+// newListMergeCmd creates the 'list merge' command
+func newListMergeCmd() *cobra.Command {
+	var force bool
+
+	cmd := &cobra.Command{
+		Use:   "merge <name>",
+		Short: "Merge lists that have the same name",
+		Long: `Merge the lists named <name> into one, for example the two "Work" calendars
+left by a list creation retried after a timeout.
+
+You choose the list to keep. The tasks of the others are moved into it,
+subtasks with their parents, and the emptied lists are then deleted.
+By default, prompts for confirmation before moving and before deleting.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name := args[0]
+
+			taskManager := application.GetTaskManager()
+			if taskManager == nil {
+				return fmt.Errorf("task manager not initialized")
+			}
+
+			lists := operations.ListsNamed(application.GetTaskLists(), name)
+			switch len(lists) {
+			case 0:
+				return fmt.Errorf("list '%s' not found", name)
+			case 1:
+				return fmt.Errorf("only one list is named '%s', there is nothing to merge", name)
+			}
+
+			counts := make([]int, len(lists))
+			for i, list := range lists {
+				tasks, err := taskManager.GetTasks(list.ID, nil)
+				if err != nil {
+					return fmt.Errorf("failed to read list %s: %w", list.ID, err)
+				}
+				counts[i] = len(tasks)
+			}
+
+			fmt.Printf("Lists named '%s':\n", name)
+			choice, err := utils.PromptSelection(lists, "Keep which list", func(i int, list backend.TaskList) {
+				fmt.Printf("  %d. %s (ID: %s, %d tasks)\n", i+1, utils.SanitizeLine(list.Name), list.ID, counts[i])
+			})
+			if err != nil {
+				return err
+			}
+			into := lists[choice]
+			others := slices.Delete(slices.Clone(lists), choice, choice+1)
+
+			moving := 0
+			for i := range lists {
+				if i != choice {
+					moving += counts[i]
+				}
+			}
+			if !force && moving > 0 {
+				confirmed, err := utils.PromptConfirmation(fmt.Sprintf("Move %d tasks from %d lists into %s?", moving, len(others), into.ID))
+				if err != nil {
+					return err
+				}
+				if !confirmed {
+					fmt.Println("Merge cancelled.")
+					return nil
+				}
+			}
+
+			if err := backupTaskManagerCache(config.GetConfig(), taskManager, "list-merge"); err != nil {
+				return err
+			}
+			moved, err := operations.MergeLists(taskManager, into, others)
+			application.RefreshTaskListsOrWarn()
+			if err != nil {
+				return fmt.Errorf("merge stopped after moving %d tasks: %w", moved, err)
+			}
+			fmt.Printf("Moved %d tasks into '%s' (ID: %s).\n", moved, into.Name, into.ID)
+
+			if !force {
+				confirmed, err := utils.PromptConfirmation(fmt.Sprintf("Delete the %d emptied lists?", len(others)))
+				if err != nil {
+					return err
+				}
+				if !confirmed {
+					fmt.Println("The emptied lists were kept.")
+					return nil
+				}
+			}
+			for _, list := range others {
+				// Tasks added meanwhile keep their list from being deleted
+				tasks, err := taskManager.GetTasks(list.ID, nil)
+				if err != nil {
+					return fmt.Errorf("failed to read list %s: %w", list.ID, err)
+				}
+				if len(tasks) > 0 {
+					utils.Warnf("List %s was kept: %d tasks were added to it meanwhile", list.ID, len(tasks))
+					continue
+				}
+				if err := taskManager.DeleteTaskList(list.ID); err != nil {
+					return fmt.Errorf("failed to delete list %s: %w", list.ID, err)
+				}
+				fmt.Printf("Deleted list %s.\n", list.ID)
+			}
+			application.RefreshTaskListsOrWarn()
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVarP(&force, "force", "f", false, "Skip the confirmation prompts")
+
+	return cmd
+}
+
+// newListAdoptCmd creates the 'list adopt' command
+func newListAdoptCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "adopt <name>",
+		Short: "Start caching a list created on the remote",
+		Long: `Adopt a list that exists on the remote but is not in the sync cache yet,
+for example one created in another client. The list is recorded in the
+cache and its tasks are pulled, without a full sync.
+
+Only available when sync is enabled.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name := args[0]
+
+			if _, err := operations.FindListByNameFull(application.GetTaskLists(), name); err == nil {
+				return fmt.Errorf("list '%s' is already in the cache", name)
+			}
+
+			remoteLists, err := application.RemoteTaskLists()
+			if err != nil {
+				return utils.WrapWithSuggestion(
+					fmt.Errorf("cannot reach the remote: %w", err),
+					"'list adopt' needs sync enabled, see 'gosynctasks sync --help'")
+			}
+			list, err := operations.FindListByNameFull(remoteLists, name)
+			if err != nil {
+				return fmt.Errorf("on the remote: %w", err)
+			}
+
+			if err := application.AdoptList(*list); err != nil {
+				return err
+			}
+
+			fmt.Printf("List '%s' adopted from the remote.\n", list.QualifiedName())
+			return nil
+		},
+	}
+}
+
+// newListUnarchiveCmd creates the 'list unarchive' command
+func newListUnarchiveCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "unarchive <name>",
+		Short: "Make a list the backend archived active again",
+		Long: `Unarchive a list archived on the backend, such as an archived Todoist
+project. Archived lists keep their tasks but are left out of the lists and
+not synced; 'gosynctasks list --include-archived' shows them.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			archived, _, err := application.ArchivedTaskLists()
+			if err != nil {
+				return fmt.Errorf("failed to get archived lists: %w", err)
+			}
+			list, err := operations.FindListByNameFull(archived, args[0])
+			if err != nil {
+				return fmt.Errorf("among archived lists: %w", err)
+			}
+
+			if err := application.UnarchiveList(*list); err != nil {
+				return err
+			}
+
+			fmt.Printf("List '%s' unarchived.\n", list.QualifiedName())
+			return nil
+		},
+	}
+}
+
+// newListPinCmd creates the 'list pin' command
+func newListPinCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "pin <name>",
+		Short: "Show a list first in pickers, completion and 'list'",
+		Long: `Pin a list, adding it to pinned_lists in the config file. Pinned lists come
+first, in the order they were pinned. Lists are pinned by ID, so the pin
+survives renaming the list.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			list, err := operations.FindListByNameFull(application.GetTaskLists(), args[0])
+			if err != nil {
+				return err
+			}
+
+			pinned := config.GetConfig().PinnedLists
+			if slices.ContainsFunc(pinned, func(pin string) bool { return operations.PinMatches(pin, *list) }) {
+				fmt.Printf("List '%s' is already pinned.\n", list.QualifiedName())
+				return nil
+			}
+			if err := config.SetPinnedLists(append(slices.Clone(pinned), list.ID)); err != nil {
+				return fmt.Errorf("failed to pin list: %w", err)
+			}
+
+			fmt.Printf("List '%s' pinned.\n", list.QualifiedName())
+			return nil
+		},
+	}
+}
+
+// newListUnpinCmd creates the 'list unpin' command
+func newListUnpinCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "unpin <name>",
+		Short: "Stop showing a list first",
+		Long: `Unpin a list, removing it from pinned_lists in the config file. Also removes
+pins of lists that no longer exist, given the name or ID they were pinned by.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name := args[0]
+			target := backend.TaskList{ID: name, Name: name}
+			if list, err := operations.FindListByNameFull(application.GetTaskLists(), name); err == nil {
+				target = *list
+			}
+
+			pinned := config.GetConfig().PinnedLists
+			kept := slices.DeleteFunc(slices.Clone(pinned), func(pin string) bool { return operations.PinMatches(pin, target) })
+			if len(kept) == len(pinned) {
+				return fmt.Errorf("list '%s' is not pinned", name)
+			}
+			if err := config.SetPinnedLists(kept); err != nil {
+				return fmt.Errorf("failed to unpin list: %w", err)
+			}
+
+			fmt.Printf("List '%s' unpinned.\n", target.QualifiedName())
+			return nil
+		},
+	}
+}
+
 // newListInfoCmd creates the 'list info' command
 func newListInfoCmd() *cobra.Command {
 	var showAll bool
@@ -334,6 +600,13 @@ func buildListInfo(tm backend.TaskManager, list backend.TaskList) map[string]int
 	listMap["color"] = list.Color
 	listMap["ctag"] = list.CTags
 
+	// Changes not pushed yet, when working on a sync cache
+	if counter, ok := backend.As[backend.PendingSyncCounter](tm); ok {
+		if counts, err := counter.CountPendingSync(); err == nil && counts != nil {
+			listMap["pending_sync"] = counts[list.ID].Operations
+		}
+	}
+
 	// Get tasks to count them
 	tasks, err := tm.GetTasks(list.ID, nil)
 	if err != nil {
@@ -396,6 +669,9 @@ func printListInfo(info map[string]interface{}) {
 	if ctag, ok := info["ctag"].(string); ok && ctag != "" {
 		fmt.Printf("CTag: %s\n", ctag)
 	}
+	if pending, ok := info["pending_sync"].(int); ok && pending > 0 {
+		fmt.Printf("Pending sync: %d changes not pushed yet\n", pending)
+	}
 }
 
 // newListTrashCmd creates the 'list trash' command with subcommands
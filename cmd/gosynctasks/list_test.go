@@ -0,0 +1,42 @@
+package main
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gosynctasks/backend"
+	"gosynctasks/backend/sqlite"
+	"gosynctasks/internal/utils"
+)
+
+// TestBuildListInfoPendingSync tests the pending_sync count of list info
+// JSON, given on a sync cache only
+func TestBuildListInfoPendingSync(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "cache.db")
+	cache, err := sqlite.NewSQLiteBackend(backend.BackendConfig{
+		Name: "nextcloud", Type: "sqlite", DBPath: dbPath, Sync: &backend.BackendSyncConfig{Enabled: true},
+	})
+	if err != nil {
+		t.Fatalf("NewSQLiteBackend() error = %v", err)
+	}
+	defer func() { _ = cache.Close() }()
+	work, _ := cache.CreateTaskList("Work", "", "")
+	home, _ := cache.CreateTaskList("Home", "", "")
+	_, _ = cache.AddTask(work, backend.Task{Summary: "Queued", Status: "NEEDS-ACTION"})
+	_, _ = cache.AddTask(work, backend.Task{Summary: "Also queued", Status: "NEEDS-ACTION"})
+
+	for listID, want := range map[string]string{work: `"pending_sync": 2`, home: `"pending_sync": 0`} {
+		data, err := utils.MarshalJSON(buildListInfo(cache, backend.TaskList{ID: listID}))
+		if err != nil {
+			t.Fatalf("MarshalJSON() error = %v", err)
+		}
+		if !strings.Contains(string(data), want) {
+			t.Errorf("list info = %s, want %s", data, want)
+		}
+	}
+
+	if info := buildListInfo(backend.NewMockBackend(), backend.TaskList{ID: work}); info["pending_sync"] != nil {
+		t.Errorf("list info without sync = %v, want no pending_sync", info)
+	}
+}
@@ -1,6 +1,8 @@
 package main
 
 import (
+	"gosynctasks/backend/record"
+	"gosynctasks/backend/sqlite"
 	"gosynctasks/internal/app"
 	"gosynctasks/internal/cli"
 	"gosynctasks/internal/config"
@@ -8,6 +10,7 @@ import (
 	"log"
 	"os"
 	"os/signal"
+	"slices"
 	"syscall"
 
 	"github.com/spf13/cobra"
@@ -23,50 +26,48 @@ var (
 )
 
 func main() {
+	rootCmd := newRootCmd()
+	sqlite.Keys = passphrases
+
+	// Set up graceful shutdown on Ctrl+C / SIGTERM
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-sigChan
+		if application != nil {
+			application.Shutdown()
+		}
+		os.Exit(0)
+	}()
+
+	// Execute command, or each command of a '::' chain
+	var err error
+	if steps, chainErr := splitChain(os.Args[1:]); chainErr != nil {
+		err = chainErr
+	} else if steps != nil {
+		err = runChain(steps, slices.Contains(os.Args[1:], "--keep-going"), os.Stderr, executeStep)
+	} else {
+		invocation = os.Args[1:]
+		err = rootCmd.Execute()
+	}
+	flushNotices() // Notices of backends created while the command ran
+	if recordErr := finishRecording(err); recordErr != nil {
+		log.Printf("Warning: %v", recordErr)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Exit immediately - background sync runs in detached process
+	// Operations are queued in sqlite and synced by background daemon
+}
+
+// newRootCmd creates the root command with all flags and subcommands
+func newRootCmd() *cobra.Command {
 	rootCmd := &cobra.Command{
 		Use:   "gosynctasks [list-name] [action] [task-summary]",
 		Short: "Task synchronization tool",
-		Long: `Task synchronization tool for managing tasks across different backends.
-
-Actions (abbreviations in parentheses):
-  get (g)       - List tasks from a task list (default action)
-  add (a)       - Add a new task to a list
-  update (u)    - Update an existing task by summary
-  complete (c)  - Change task status by summary (defaults to DONE)
-  delete (d)    - Delete a task by summary
-
-Examples:
-  gosynctasks                           # Interactive list selection, show tasks
-  gosynctasks MyList                    # Show tasks from "MyList"
-  gosynctasks MyList get                # Show tasks from "MyList" (g also works)
-  gosynctasks MyList -s TODO,PROCESSING # Filter tasks by status
-
-  gosynctasks MyList add "New task"     # Add a task to "MyList"
-  gosynctasks MyList a "New task"       # Same using abbreviation
-  gosynctasks MyList add                # Add a task (will prompt for summary)
-  gosynctasks MyList add "Task" -d "Details" -p 1 -S done  # Add with options
-  gosynctasks MyList add "Report" --due-date 2025-01-31 --start-date 2025-01-15  # With dates
-  gosynctasks MyList add "Subtask" -P "Parent Task"  # Add subtask under parent
-  gosynctasks MyList add "Fix bug" -P "Feature/Code"  # Path-based parent reference
-  gosynctasks MyList add "parent/child/grandchild"  # Shorthand: auto-creates hierarchy
-  gosynctasks MyList add -l "be a good/generous person"  # Use -l to disable path parsing
-
-  gosynctasks MyList update "Buy groceries" -s DONE  # Update task status
-  gosynctasks MyList u "groceries" --summary "Buy milk"  # Partial match + rename
-  gosynctasks MyList update "task" -p 5              # Partial match + set priority
-  gosynctasks MyList update "task" --due-date 2025-02-15  # Update due date
-
-  gosynctasks MyList complete "Buy groceries"      # Mark as DONE (default)
-  gosynctasks MyList c "groceries"
-
-  gosynctasks MyList delete "Buy groceries"        # Delete a task
-  gosynctasks MyList d "groceries"                 # Same using abbreviation
-
-Config:
-  --config .                            # Use ./gosynctasks/config.json
-  --config /path/to/config.json         # Use specific config file
-  --config /path/to/dir                 # Use /path/to/dir/config.json
-`,
+		Long:  rootLongHelp(),
 		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
 			// Set verbose mode first
 			if verbose {
@@ -74,6 +75,24 @@ Config:
 				utils.Debugf("Verbose mode enabled")
 			}
 
+			// Later commands of a '::' chain, and the command line of a shortcut,
+			// share the first one's application
+			if (chained || expanding) && application != nil {
+				return nil
+			}
+
+			if err := setupNotices(cmd); err != nil {
+				return err
+			}
+			showSyncFailure(cmd)
+
+			// A replayed session brings its own config and backend
+			if replayer != nil {
+				session := replayer.Session()
+				application = app.NewAppWithTaskManager(session.Backend, replayer.TaskManager(record.RoleBackend), session.TaskLists)
+				return nil
+			}
+
 			// Set custom config path if specified
 			if configPath != "" {
 				config.SetCustomConfigPath(configPath)
@@ -83,13 +102,21 @@ Config:
 			// Initialize app after config path is set
 			var err error
 			application, err = app.NewApp(backendName)
+			flushNotices() // Before any command output
 			if err != nil {
 				return err
 			}
+			application.SetConflictPrompter(listSyncPrompter(config.GetConfig()))
 			if backendName != "" {
 				utils.Debugf("Application initialized with backend argument: %s", backendName)
 			}
 
+			if recordPath != "" {
+				if err := startRecording(cmd); err != nil {
+					return err
+				}
+			}
+
 			// Handle --list-backends flag
 			if listBackends {
 				return application.ListBackends()
@@ -102,14 +129,24 @@ Config:
 
 			return nil
 		},
-		Args: cobra.MaximumNArgs(3),
+		Args: cobra.ArbitraryArgs, // Extra words after the summary are joined into it
 		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 			if application == nil {
 				return []string{}, cobra.ShellCompDirectiveNoFileComp
 			}
-			return cli.SmartCompletion(application.GetTaskLists())(cmd, args, toComplete)
+			completions, directive := cli.SmartCompletion(application.GetTaskLists(), application.GetTaskManager())(cmd, args, toComplete)
+			if len(args) == 0 {
+				completions = append(completions, shortcutCompletions(toComplete)...)
+			}
+			return completions, directive
 		},
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if acknowledgeInsecure != "" && len(args) == 0 {
+				return nil // Only recording the acknowledgment
+			}
+			if ran, err := runShortcut(cmd, args); ran || err != nil {
+				return err
+			}
 			return application.Run(cmd, args)
 		},
 	}
@@ -120,23 +157,60 @@ Config:
 	rootCmd.PersistentFlags().BoolVar(&listBackends, "list-backends", false, "list all configured backends and exit")
 	rootCmd.PersistentFlags().BoolVar(&detectBackends, "detect-backend", false, "show auto-detected backends and exit")
 	rootCmd.PersistentFlags().BoolVarP(&verbose, "verbose", "V", false, "enable verbose/debug logging")
+	rootCmd.PersistentFlags().Bool("strict", false, "fail instead of falling back to cached task lists when the backend is unreachable")
+	rootCmd.PersistentFlags().StringVar(&recordPath, "record", "", "record this run (command, redacted config, backend calls and output) to a session file for bug reports")
+	rootCmd.PersistentFlags().StringVar(&acknowledgeInsecure, "acknowledge-insecure", "", "permanently silence a security warning, by the ID it shows (e.g. nextcloud:http)")
+	rootCmd.PersistentFlags().BoolVar(&redactContent, "redact-content", false, "with --record, store task summaries and descriptions as hashes")
+	rootCmd.PersistentFlags().Bool("keep-going", false, "in a chain of commands separated by '::', run the remaining commands after one fails")
 
 	// Command flags
 	rootCmd.Flags().StringArrayP("status", "s", []string{}, "filter by status (for get) or set status (for update): [T]ODO, [D]ONE, [P]ROCESSING, [C]ANCELLED")
 	rootCmd.Flags().StringP("view", "v", "default", "view mode (default, all, or custom view name)")
+	rootCmd.Flags().String("format-template", "", "Go template for each task line (for get), or @name of a template from config")
+	rootCmd.Flags().String("fields", "", "comma-separated fields to show, in order (for get), e.g. uid,summary,status,due_date")
+	rootCmd.Flags().Bool("json", false, "output tasks as JSON objects (for get), restricted to --fields when given")
 	rootCmd.Flags().StringP("description", "d", "", "task description (for add/update)")
-	rootCmd.Flags().IntP("priority", "p", 0, "task priority (for add/update, 0-9: 0=undefined, 1=highest, 9=lowest)")
+	rootCmd.Flags().StringP("priority", "p", "", "filter by priority (for get): "+cli.PriorityFormats+"; or set it (for add/update, 0-9: 0=undefined, 1=highest, 9=lowest)")
 	rootCmd.Flags().StringP("add-status", "S", "", "task status when adding (TODO/T, DONE/D, PROCESSING/P, CANCELLED/C)")
 	rootCmd.Flags().String("summary", "", "task summary (for update)")
+	rootCmd.Flags().String("due", "", "task due date (for add/update): "+cli.DueFormats)
 	rootCmd.Flags().String("due-date", "", "task due date (for add/update, format: YYYY-MM-DD, empty string to clear)")
 	rootCmd.Flags().String("start-date", "", "task start date (for add/update, format: YYYY-MM-DD, empty string to clear)")
-	rootCmd.Flags().StringP("parent", "P", "", "parent task reference (for add): task summary or path like 'Parent/Child'")
+	rootCmd.Flags().String("repeat", "", "repeat the task (for add/update): daily, weekly, monthly, yearly or an RRULE like \"FREQ=WEEKLY;BYDAY=MO\"; none to stop")
+	rootCmd.Flags().String("modified-since", "", "only show tasks modified since a time (for get): duration like 2d, 12h, 1w or date YYYY-MM-DD")
+	rootCmd.Flags().String("completed-since", "", "include tasks completed since a time (for get): duration like 7d or date YYYY-MM-DD")
+	rootCmd.Flags().Bool("overdue", false, "only show open tasks past their due date (for get)")
+	rootCmd.Flags().Bool("include-archived", false, "read a list the backend archived, e.g. a Todoist project (for get)")
+	rootCmd.Flags().StringArray("tag", []string{}, "task tags (for add/update, repeatable or comma-separated): +tag adds and -tag removes on update, plain tags replace them")
+	rootCmd.Flags().StringP("parent", "P", "", "parent task reference (for add): task summary, path like 'Parent/Child' or %N")
 	rootCmd.Flags().BoolP("literal", "l", false, "treat task summary literally (for add): disable automatic path-based hierarchy creation")
+	rootCmd.Flags().Bool("collapse", false, "show only root tasks with a count of their hidden subtasks (for get)")
+	rootCmd.Flags().String("expand", "", "show only this task and its subtasks (for get): task summary or %N")
+	rootCmd.MarkFlagsMutuallyExclusive("collapse", "expand")
+	rootCmd.MarkFlagsMutuallyExclusive("format-template", "fields")
+	rootCmd.MarkFlagsMutuallyExclusive("format-template", "json")
+	rootCmd.MarkFlagsMutuallyExclusive("due", "due-date")
+	rootCmd.Flags().Bool("with-children", false, "also give open subtasks the new status (for complete, e.g. with -s CANCELLED)")
+	rootCmd.Flags().String("reason", "", "closing note, added to the description on a timestamped line (for complete with DONE or CANCELLED)")
+	rootCmd.Flags().Bool("explain", false, "show the task a write sends, field by field, and the backend requests, then ask before sending (for add/update/complete/delete)")
+	rootCmd.Flags().BoolP("yes", "y", false, "proceed without asking (with --explain)")
+	rootCmd.Flags().BoolP("force", "f", false, "delete without asking for confirmation or checking the task on the server first (for delete)")
+	rootCmd.Flags().String("format", "ics", "file format (for export/import): ics, csv or md (export only); import follows the file's extension by default")
+	rootCmd.Flags().String("delimiter", ",", "field separator of csv files (for export/import), e.g. ';' or tab")
+	rootCmd.Flags().StringP("output", "o", "", "file to write (for export), stdout when not given")
+	rootCmd.Flags().Bool("include-descriptions", false, "quote task descriptions under their tasks (for export --format md)")
+	rootCmd.Flags().Bool("skip-existing", false, "leave the tasks of the list whose UID is in the file (for import)")
+	rootCmd.Flags().Bool("overwrite", false, "update the tasks of the list whose UID is in the file (for import)")
+	rootCmd.MarkFlagsMutuallyExclusive("skip-existing", "overwrite")
+	rootCmd.Flags().String("list", "", "list to use, taken literally even when named like the 'all' pseudo-list; the first argument is then the action")
 
 	// Register flag value completion for status flags
 	_ = rootCmd.RegisterFlagCompletionFunc("status", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 		return []string{"TODO", "DONE", "PROCESSING", "CANCELLED"}, cobra.ShellCompDirectiveNoFileComp
 	})
+	_ = rootCmd.RegisterFlagCompletionFunc("priority", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{"high", "medium", "low", "none"}, cobra.ShellCompDirectiveNoFileComp
+	})
 	_ = rootCmd.RegisterFlagCompletionFunc("add-status", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 		return []string{"TODO", "DONE", "PROCESSING", "CANCELLED"}, cobra.ShellCompDirectiveNoFileComp
 	})
@@ -158,24 +232,19 @@ Config:
 	rootCmd.AddCommand(newListCmd())
 	rootCmd.AddCommand(newSyncCmd())
 	rootCmd.AddCommand(newCredentialsCmd())
+	rootCmd.AddCommand(newConfigCmd())
+	rootCmd.AddCommand(newReplaceCmd())
+	rootCmd.AddCommand(newCaptureCmd())
+	rootCmd.AddCommand(newDBCmd())
+	rootCmd.AddCommand(newDedupeCmd())
+	rootCmd.AddCommand(newVersionCmd())
+	rootCmd.AddCommand(newTagsCmd())
+	rootCmd.AddCommand(newReplayCmd())
+	rootCmd.AddCommand(newShortcutsCmd())
+	rootCmd.AddCommand(newSearchCmd())
+	rootCmd.AddCommand(newDocsCmd())           // Hidden man page and markdown generator
+	rootCmd.AddCommand(newDevCmd())            // Hidden developer tools
 	rootCmd.AddCommand(newBackgroundSyncCmd()) // Hidden internal command for background sync
 
-	// Set up graceful shutdown on Ctrl+C / SIGTERM
-	sigChan := make(chan os.Signal, 1)
-	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
-	go func() {
-		<-sigChan
-		if application != nil {
-			application.Shutdown()
-		}
-		os.Exit(0)
-	}()
-
-	// Execute command
-	if err := rootCmd.Execute(); err != nil {
-		log.Fatal(err)
-	}
-
-	// Exit immediately - background sync runs in detached process
-	// Operations are queued in sqlite and synced by background daemon
+	return rootCmd
 }
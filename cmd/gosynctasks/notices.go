@@ -0,0 +1,89 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"gosynctasks/internal/notices"
+	internalSync "gosynctasks/internal/sync"
+	"gosynctasks/internal/utils"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	acknowledgeInsecure string
+	notifier            *notices.Notifier
+)
+
+// setupNotices installs the notifier backends report security notices to,
+// muted while the output is read by a program, and records a pending
+// --acknowledge-insecure
+func setupNotices(cmd *cobra.Command) error {
+	path, err := notices.DefaultPath()
+	if err != nil {
+		utils.Debugf("No state directory for notices: %v", err)
+	}
+	notifier = notices.NewNotifier(path, os.Stderr, noticesMuted(cmd))
+	notifier.Install()
+
+	if acknowledgeInsecure == "" {
+		return nil
+	}
+	if path == "" {
+		return fmt.Errorf("cannot record the acknowledgment: %w", err)
+	}
+	if err := notifier.Acknowledge(acknowledgeInsecure); err != nil {
+		return fmt.Errorf("failed to record the acknowledgment: %w", err)
+	}
+	fmt.Fprintf(os.Stderr, "Acknowledged %s; its security warning will no longer be shown.\n", acknowledgeInsecure)
+	return nil
+}
+
+// noticesMuted reports whether cmd's output must not be mixed with notices:
+// shell completion, machine-readable or quiet output, and background syncs
+func noticesMuted(cmd *cobra.Command) bool {
+	switch cmd.Name() {
+	case cobra.ShellCompRequestCmd, cobra.ShellCompNoDescRequestCmd, "_internal_background_sync":
+		return true
+	}
+	if cmd.HasParent() && cmd.Parent().Name() == "completion" {
+		return true
+	}
+	for _, name := range []string{"json", "quiet"} {
+		if on, err := cmd.Flags().GetBool(name); err == nil && on {
+			return true
+		}
+	}
+	return false
+}
+
+// flushNotices prints the security notices reported so far
+func flushNotices() {
+	if notifier != nil {
+		notifier.Flush()
+	}
+}
+
+// showSyncFailure prints, once, the errors of the last background sync run
+// after a write if it failed, unless cmd's output is machine-read
+func showSyncFailure(cmd *cobra.Command) {
+	if noticesMuted(cmd) {
+		return
+	}
+	path, err := internalSync.StatusPath()
+	if err != nil {
+		return
+	}
+	run, err := internalSync.TakeFailedRun(path)
+	if err != nil {
+		utils.Debugf("Failed to read the sync status: %v", err)
+		return
+	}
+	if run == nil {
+		return
+	}
+	fmt.Fprintf(os.Stderr, "⚠ Background sync at %s failed: %s\n  Your changes are still queued; run 'gosynctasks sync' to retry.\n",
+		run.Finished.Format("15:04"), strings.Join(run.Errors, "; "))
+}
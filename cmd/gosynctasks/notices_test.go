@@ -0,0 +1,78 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"gosynctasks/backend"
+	"gosynctasks/internal/config"
+)
+
+// runCapturingOutput runs the root command with args and returns everything
+// written to stdout and stderr
+func runCapturingOutput(t *testing.T, args ...string) string {
+	t.Helper()
+	reader, writer, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout, stderr := os.Stdout, os.Stderr
+	os.Stdout, os.Stderr = writer, writer
+	done := make(chan []byte)
+	go func() {
+		data, _ := io.ReadAll(reader)
+		done <- data
+	}()
+
+	root := newRootCmd()
+	root.SetArgs(args)
+	_ = root.Execute()
+	flushNotices()
+
+	os.Stdout, os.Stderr = stdout, stderr
+	_ = writer.Close()
+	return string(<-done)
+}
+
+// TestCompletionHasNoSecurityWarnings tests that shell completion output
+// carries no warning bytes, while a normal run of the same setup warns
+func TestCompletionHasNoSecurityWarnings(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusMultiStatus)
+		_, _ = io.WriteString(w, `<?xml version="1.0"?><d:multistatus xmlns:d="DAV:"></d:multistatus>`)
+	}))
+	defer server.Close()
+
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	host := strings.TrimPrefix(server.URL, "https://")
+	config.SetConfigForTest(&config.Config{
+		DefaultBackend: "cloud",
+		Backends: map[string]backend.BackendConfig{
+			"cloud": {Name: "cloud", Type: "nextcloud", Enabled: true, URL: "nextcloud://user:pass@" + host, InsecureSkipVerify: true},
+		},
+	})
+	defer func() {
+		application = nil
+		notifier = nil
+		backend.SetSecurityNoticeHandler(func(backend.SecurityNotice) {})
+	}()
+
+	for _, args := range [][]string{{"__complete", ""}, {"__completeNoDesc", "Work", ""}, {"list", "info", "--all", "--json"}} {
+		out := runCapturingOutput(t, args...)
+		for _, warning := range []string{"SECURITY", "⚠", "acknowledge-insecure"} {
+			if strings.Contains(out, warning) {
+				t.Errorf("%v output contains %q:\n%s", args, warning, out)
+			}
+		}
+	}
+
+	if out := runCapturingOutput(t, "list"); !bytes.Contains([]byte(out), []byte("SECURITY WARNING (cloud)")) {
+		t.Errorf("list output = %q, want the TLS warning", out)
+	}
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+
+	"gosynctasks/backend"
+	"gosynctasks/backend/record"
+	"gosynctasks/backend/sync"
+	"gosynctasks/internal/cli"
+	"gosynctasks/internal/config"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	recordPath    string
+	redactContent bool
+	recording     *record.Session // Set while --record is active
+	recordCapture *outputCapture
+	replayer      *record.Replayer // Set while 'replay' re-runs a session
+)
+
+// startRecording begins a --record session: it stores the command and the
+// redacted config, wraps the application's task manager, and tees stdout
+func startRecording(cmd *cobra.Command) error {
+	command := os.Args[1:]
+	if redactContent {
+		command = record.RedactCommand(command, flagTakesValue(cmd))
+	}
+	session := record.NewSession(command, redactContent)
+	if err := session.SetConfig(config.GetConfig().Redacted()); err != nil {
+		return err
+	}
+	session.Backend = application.GetSelectedBackend()
+	session.SetTaskLists(application.GetTaskLists())
+	application.WrapTaskManager(func(tm backend.TaskManager) backend.TaskManager {
+		return record.WrapTaskManager(tm, session, record.RoleBackend)
+	})
+
+	// Keep the detected width, since stdout becomes a pipe while capturing
+	session.TerminalWidth = cli.GetTerminalWidth()
+	cli.SetTerminalWidth(session.TerminalWidth)
+	capture, err := captureOutput()
+	if err != nil {
+		return err
+	}
+
+	recording = session
+	recordCapture = capture
+	return nil
+}
+
+// finishRecording stores the output and error of the run and writes the
+// session file. It does nothing when the run was not recorded.
+func finishRecording(runErr error) error {
+	if recording == nil {
+		return nil
+	}
+	recording.SetOutput(recordCapture.stop())
+	recording.SetError(runErr)
+	if err := recording.Save(recordPath); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "Recorded session to %s\n", recordPath)
+	if recording.Truncated {
+		fmt.Fprintf(os.Stderr, "Warning: the session reached its %d MB size limit and was truncated\n", record.DefaultMaxBytes>>20)
+	}
+	return nil
+}
+
+// openSyncBackends returns the local store and remote backend for a sync,
+// recorded under --record or answered from the session under 'replay'
+func openSyncBackends(cfg *config.Config, explicitBackend string) (sync.LocalStore, backend.TaskManager, error) {
+	if replayer != nil {
+		return replayer.Store(), replayer.TaskManager(record.RoleRemote), nil
+	}
+
+	localBackend, remoteBackend, err := getSyncBackends(cfg, explicitBackend)
+	if err != nil {
+		return nil, nil, err
+	}
+	if recording != nil {
+		return record.WrapStore(localBackend, recording), record.WrapTaskManager(remoteBackend, recording, record.RoleRemote), nil
+	}
+	return localBackend, remoteBackend, nil
+}
+
+// flagTakesValue reports whether a flag of cmd, by long or short name,
+// consumes the next argument
+func flagTakesValue(cmd *cobra.Command) func(string) bool {
+	return func(name string) bool {
+		flag := cmd.Flags().Lookup(name)
+		if flag == nil && len(name) == 1 {
+			flag = cmd.Flags().ShorthandLookup(name)
+		}
+		return flag != nil && flag.NoOptDefVal == ""
+	}
+}
+
+// outputCapture tees stdout into a buffer
+type outputCapture struct {
+	original *os.File
+	writer   *os.File
+	buf      bytes.Buffer
+	done     chan struct{}
+}
+
+func captureOutput() (*outputCapture, error) {
+	reader, writer, err := os.Pipe()
+	if err != nil {
+		return nil, fmt.Errorf("failed to capture output: %w", err)
+	}
+	c := &outputCapture{original: os.Stdout, writer: writer, done: make(chan struct{})}
+	os.Stdout = writer
+	go func() {
+		_, _ = io.Copy(io.MultiWriter(c.original, &c.buf), reader)
+		_ = reader.Close()
+		close(c.done)
+	}()
+	return c, nil
+}
+
+// stop restores stdout and returns everything written while capturing
+func (c *outputCapture) stop() string {
+	os.Stdout = c.original
+	_ = c.writer.Close()
+	<-c.done
+	return c.buf.String()
+}
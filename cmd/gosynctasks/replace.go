@@ -0,0 +1,117 @@
+package main
+
+import (
+	"fmt"
+	"gosynctasks/backend"
+	"gosynctasks/internal/config"
+	"gosynctasks/internal/operations"
+	"gosynctasks/internal/utils"
+
+	"github.com/spf13/cobra"
+)
+
+// newReplaceCmd creates the 'replace' command for bulk summary rewrites
+func newReplaceCmd() *cobra.Command {
+	var listName string
+	var useRegex bool
+	var includeDescriptions bool
+	var dryRun bool
+	var yes bool
+
+	cmd := &cobra.Command{
+		Use:   "replace <search> <replacement>",
+		Short: "Search and replace text in task summaries",
+		Long: `Replace text in the summaries of open tasks across all lists, or one list.
+
+Each matching task is shown as a before/after diff and the changes are applied
+after confirmation. Updates go through the normal task operations, so they are
+queued for sync like any other edit.
+
+With --regex, the search is a Go regular expression and the replacement may
+reference capture groups ($1, ${name}).
+
+Examples:
+  gosynctasks replace "ACME" "Acme Corp"                     # All lists
+  gosynctasks replace "ACME" "Acme Corp" --list Work         # One list
+  gosynctasks replace "v(\d+)" "version $1" --regex --dry-run
+  gosynctasks replace "foo" "bar" --include-descriptions --yes`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			taskManager := application.GetTaskManager()
+			if taskManager == nil {
+				return fmt.Errorf("task manager not initialized")
+			}
+
+			lists := application.GetTaskLists()
+			if listName != "" {
+				list, err := operations.FindListByNameFull(lists, listName)
+				if err != nil {
+					return err
+				}
+				lists = []backend.TaskList{*list}
+			}
+
+			changes, err := operations.FindReplacements(taskManager, lists, operations.ReplaceOptions{
+				Pattern:             args[0],
+				Replacement:         args[1],
+				Regex:               useRegex,
+				IncludeDescriptions: includeDescriptions,
+			})
+			if err != nil {
+				return err
+			}
+			if len(changes) == 0 {
+				fmt.Println("No matching open tasks found.")
+				return nil
+			}
+
+			colorize := operations.UsesColor(config.GetConfig())
+			for _, change := range changes {
+				fmt.Print(operations.FormatReplaceDiff(change, colorize))
+			}
+			fmt.Printf("\n%d task(s) matched.\n", len(changes))
+
+			if dryRun {
+				fmt.Println("Dry run: no changes made.")
+				return nil
+			}
+
+			if !yes {
+				confirmed, err := utils.PromptConfirmation("Apply these changes?")
+				if err != nil {
+					return err
+				}
+				if !confirmed {
+					fmt.Println("Replace cancelled.")
+					return nil
+				}
+			}
+
+			results := operations.ApplyReplacements(taskManager, changes, application)
+
+			failed := 0
+			for _, result := range results {
+				if result.Err != nil {
+					failed++
+					fmt.Printf("  ✗ %s: %v\n", result.Change.Task.Summary, result.Err)
+				} else {
+					fmt.Printf("  ✓ %s\n", result.Change.NewSummary)
+				}
+			}
+			fmt.Printf("\n%d updated, %d failed.\n", len(results)-failed, failed)
+
+			if failed > 0 {
+				return fmt.Errorf("%d task(s) could not be updated", failed)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&listName, "list", "", "Only replace in this list")
+	cmd.Flags().BoolVar(&useRegex, "regex", false, "Treat search as a regular expression")
+	cmd.Flags().BoolVar(&includeDescriptions, "include-descriptions", false, "Also replace in task descriptions")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Show changes without applying them")
+	cmd.Flags().BoolVarP(&yes, "yes", "y", false, "Apply without confirmation")
+
+	return cmd
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"gosynctasks/backend/record"
+	"gosynctasks/internal/cli"
+	"gosynctasks/internal/config"
+
+	"github.com/spf13/cobra"
+)
+
+// newReplayCmd creates the 'replay' command
+func newReplayCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "replay <session.json>",
+		Short: "Re-run a session recorded with --record (for debugging)",
+		Long: `Re-run the command of a session recorded with --record, with every backend
+call answered from the recording instead of a real backend.
+
+The recorded config is used, nothing is written to your backends or caches,
+and background sync is not started. The replay reports where it makes a
+different backend call than the recorded run did, and whether its output
+matches. Commands that prompt still read from stdin.
+
+Examples:
+  gosynctasks Work complete "Report" --record session.json
+  gosynctasks replay session.json`,
+		Args: cobra.ExactArgs(1),
+		// Skip the root's backend setup; the session provides the backend
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			session, err := record.Load(args[0])
+			if err != nil {
+				return err
+			}
+			result, err := replaySession(session)
+			if err != nil {
+				return err
+			}
+			return result.report()
+		},
+	}
+}
+
+// replayResult is the outcome of replaying a session
+type replayResult struct {
+	session       *record.Session
+	output        string
+	runErr        error
+	divergence    error
+	remaining     int
+	outputMatches bool
+}
+
+// replaySession re-runs the command of session against stub backends fed
+// from its recorded calls
+func replaySession(session *record.Session) (*replayResult, error) {
+	if len(session.Command) > 0 && session.Command[0] == "replay" {
+		return nil, fmt.Errorf("session records a replay, not a command to replay")
+	}
+
+	cfg := &config.Config{}
+	if len(session.Config) > 0 {
+		if err := json.Unmarshal(session.Config, cfg); err != nil {
+			return nil, fmt.Errorf("failed to parse recorded config: %w", err)
+		}
+	}
+	if cfg.Sync != nil {
+		cfg.Sync.AutoSync = false // Never spawn a background sync from a replay
+	}
+	config.UseConfig(cfg)
+
+	// Keep refreshed list caches out of the user's cache directory
+	cacheDir, err := os.MkdirTemp("", "gosynctasks-replay-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create replay cache directory: %w", err)
+	}
+	defer func() { _ = os.RemoveAll(cacheDir) }()
+	previousCache, hadCache := os.LookupEnv("XDG_CACHE_HOME")
+	_ = os.Setenv("XDG_CACHE_HOME", cacheDir)
+	defer func() {
+		if hadCache {
+			_ = os.Setenv("XDG_CACHE_HOME", previousCache)
+		} else {
+			_ = os.Unsetenv("XDG_CACHE_HOME")
+		}
+	}()
+
+	cli.SetTerminalWidth(session.TerminalWidth)
+	defer cli.SetTerminalWidth(0)
+
+	replayer = record.NewReplayer(session)
+	defer func() { replayer = nil }()
+
+	capture, err := captureOutput()
+	if err != nil {
+		return nil, err
+	}
+	root := newRootCmd()
+	root.SetArgs(session.Command)
+	runErr := root.Execute()
+	output := capture.stop()
+
+	return &replayResult{
+		session:       session,
+		output:        output,
+		runErr:        runErr,
+		divergence:    replayer.Err(),
+		remaining:     replayer.Remaining(),
+		outputMatches: output == session.Output,
+	}, nil
+}
+
+// report prints how the replay compares to the recording, returning an
+// error when it left the recorded path
+func (r *replayResult) report() error {
+	w := os.Stderr
+	fmt.Fprintln(w)
+	if r.divergence != nil {
+		return r.divergence
+	}
+	if r.remaining > 0 {
+		return fmt.Errorf("replay ended with %d recorded backend calls not made", r.remaining)
+	}
+
+	fmt.Fprintf(w, "Replayed %d backend calls\n", len(r.session.Calls))
+	recordedErr := r.session.Error
+	switch {
+	case r.runErr == nil && recordedErr != "":
+		fmt.Fprintf(w, "Recorded run failed with %q, the replay succeeded\n", recordedErr)
+	case r.runErr != nil && recordedErr == "":
+		fmt.Fprintf(w, "Replay failed with %q, the recorded run succeeded\n", r.runErr)
+	}
+	switch {
+	case r.session.Redacted:
+		fmt.Fprintln(w, "Output not compared: the recording has redacted content")
+	case r.session.Truncated:
+		fmt.Fprintln(w, "Output not compared: the recording was truncated")
+	case r.outputMatches:
+		fmt.Fprintln(w, "Output matches the recording")
+	default:
+		fmt.Fprintln(w, "Output differs from the recording (relative dates and colors can differ between runs)")
+	}
+	return nil
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+
+	"gosynctasks/backend/record"
+)
+
+// TestReplaySessions replays every session in testdata/sessions. A session
+// recorded with --record from a bug report becomes a regression test by
+// dropping it there: the replay must take the same path through the
+// operations layer and, unless redacted, print the same output.
+func TestReplaySessions(t *testing.T) {
+	paths, err := filepath.Glob(filepath.Join("testdata", "sessions", "*.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, path := range paths {
+		t.Run(filepath.Base(path), func(t *testing.T) {
+			session, err := record.Load(path)
+			if err != nil {
+				t.Fatalf("Load() error = %v", err)
+			}
+			result, err := replaySession(session)
+			if err != nil {
+				t.Fatalf("replaySession() error = %v", err)
+			}
+			if result.divergence != nil {
+				t.Fatalf("replay diverged: %v", result.divergence)
+			}
+			if result.remaining != 0 {
+				t.Errorf("replay left %d recorded calls unmade", result.remaining)
+			}
+			if (result.runErr != nil) != (session.Error != "") {
+				t.Errorf("replay error = %v, recorded error = %q", result.runErr, session.Error)
+			}
+			if !session.Redacted && !session.Truncated && !result.outputMatches {
+				t.Errorf("output differs from the recording:\n got: %q\nwant: %q", result.output, session.Output)
+			}
+		})
+	}
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"fmt"
+	"gosynctasks/internal/config"
+	"gosynctasks/internal/operations"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// newSearchCmd creates the 'search' command, which finds tasks in every list
+func newSearchCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "search <text>",
+		Short: "Find tasks by summary across all lists",
+		Long: `Show the tasks whose summary contains the text, ignoring case, in every
+list, grouped by list. Completed tasks are included unless --status leaves
+them out.
+
+The command fails when no task matches, so scripts can branch on its exit code.
+
+Examples:
+  gosynctasks search invoice
+  gosynctasks search "weekly report" -s TODO,PROCESSING
+  gosynctasks search meeting --limit 3 -v all`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			taskManager := application.GetTaskManager()
+			if taskManager == nil {
+				return fmt.Errorf("task manager not initialized")
+			}
+			return operations.HandleSearch(cmd, taskManager, config.GetConfig(), application.GetTaskLists(), strings.Join(args, " "))
+		},
+	}
+
+	cmd.Flags().StringArrayP("status", "s", []string{}, "Only show tasks with these statuses: [T]ODO, [D]ONE, [P]ROCESSING, [C]ANCELLED")
+	cmd.Flags().Int("limit", 0, "Show at most this many tasks per list (0 for all)")
+	cmd.Flags().StringP("view", "v", "default", "View mode (default, all, or custom view name)")
+	cmd.Flags().String("fields", "", "Comma-separated fields to show, in order, e.g. uid,summary,status")
+
+	return cmd
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"fmt"
+	"gosynctasks/internal/config"
+	"gosynctasks/internal/operations"
+	"gosynctasks/internal/utils"
+	"io"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// invocation is the command line being run, the arguments of the current
+// step for a '::' chain; a shortcut is expanded in it
+var invocation []string
+
+// expanding is set while the command line of a shortcut runs, so that it
+// reuses the application and is not expanded again
+var expanding bool
+
+// newShortcutsCmd creates the 'shortcuts' command
+func newShortcutsCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "shortcuts",
+		Short: "Show the saved invocations of the config",
+		Long: `Show the shortcuts of the config with the command lines they stand for.
+
+A shortcut is used as the first argument, and the rest of the command line
+is appended to what it stands for:
+
+  shortcuts:
+    today: "all get -s TODO,PROCESSING -v minimal"
+    capture: "Inbox add"
+
+  gosynctasks capture "call mom"    # gosynctasks Inbox add "call mom"
+
+A list with the same name as a shortcut wins, with a warning.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			printShortcuts(cmd.OutOrStdout(), config.GetConfig().Shortcuts)
+			return nil
+		},
+	}
+}
+
+// printShortcuts writes the shortcuts by name with their definitions
+func printShortcuts(w io.Writer, shortcuts map[string]string) {
+	if len(shortcuts) == 0 {
+		fmt.Fprintln(w, "No shortcuts configured. Add them under 'shortcuts:' in the config.")
+		return
+	}
+	names := slices.Sorted(maps.Keys(shortcuts))
+	width := 0
+	for _, name := range names {
+		width = max(width, len(name))
+	}
+	for _, name := range names {
+		fmt.Fprintf(w, "  %-*s  %s\n", width, name, utils.SanitizeLine(shortcuts[name]))
+	}
+}
+
+// runShortcut runs the command line the first argument stands for when it
+// names a shortcut, and reports whether it did
+func runShortcut(cmd *cobra.Command, args []string) (bool, error) {
+	if expanding || application == nil {
+		return false, nil
+	}
+	if listName, _ := cmd.Flags().GetString("list"); listName != "" {
+		return false, nil
+	}
+
+	expanded, shadowed, err := operations.ExpandShortcut(config.GetConfig().Shortcuts, application.GetTaskLists(), invocation, args)
+	if shadowed {
+		fmt.Fprintf(os.Stderr, "Warning: '%s' is both a list and a shortcut; using the list. Rename the shortcut to use it.\n", args[0])
+	}
+	if err != nil || expanded == nil {
+		return false, err
+	}
+	utils.Debugf("Shortcut %s: %s", args[0], strings.Join(expanded, " "))
+
+	expanding = true
+	defer func() { expanding = false }()
+	root := newRootCmd()
+	root.SetArgs(expanded)
+	root.SilenceErrors = true // Reported once, by the command that was invoked
+	root.SilenceUsage = true
+	return true, root.Execute()
+}
+
+// shortcutCompletions returns the shortcut names starting with prefix
+func shortcutCompletions(prefix string) []string {
+	var names []string
+	for name := range config.GetConfig().Shortcuts {
+		if strings.HasPrefix(strings.ToLower(name), strings.ToLower(prefix)) {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+	return names
+}
@@ -6,12 +6,18 @@ import (
 	"gosynctasks/backend/sqlite"
 	"gosynctasks/backend/sync"
 	"gosynctasks/internal/config"
+	"gosynctasks/internal/operations"
+	internalSync "gosynctasks/internal/sync"
 	"gosynctasks/internal/utils"
 	"net"
 	"net/url"
+	"os"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
+	"golang.org/x/term"
 )
 
 // newSyncCmd creates the sync command with all subcommands
@@ -31,17 +37,21 @@ The sync command performs bidirectional synchronization:
 - Push: Upload local changes to remote backend
 
 Conflict resolution is handled according to the configured strategy
-(server_wins, local_wins, merge, or keep_both).
+(server_wins, local_wins, merge, keep_both, or prompt to choose per conflict).
+Conflicts are listed after the sync with the fields that differ.
 
 Examples:
   gosynctasks sync                  # Perform sync
   gosynctasks sync --full          # Force full re-sync (ignore CTags)
   gosynctasks sync --dry-run       # Preview changes without applying
   gosynctasks sync -l "Work"       # Sync specific list only
+  gosynctasks sync --verbose       # Also show what was done with each list
 
   gosynctasks sync status          # Show sync status
   gosynctasks sync queue           # Show pending operations
-  gosynctasks sync queue clear     # Clear failed operations`,
+  gosynctasks sync queue --stats   # Show queue size and oldest entries
+  gosynctasks sync queue clear     # Clear failed operations
+  gosynctasks sync rescue "Work"   # Move changes of a list deleted on the remote`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			// Get sync configuration
 			cfg := config.GetConfig()
@@ -59,7 +69,7 @@ Examples:
 			explicitBackend, _ := cmd.Root().PersistentFlags().GetString("backend")
 
 			// Get backends for sync (respects --backend flag)
-			localBackend, remoteBackend, err := getSyncBackends(cfg, explicitBackend)
+			localBackend, remoteBackend, err := openSyncBackends(cfg, explicitBackend)
 			if err != nil {
 				return err
 			}
@@ -88,12 +98,37 @@ Examples:
 			}
 
 			sm := sync.NewSyncManager(localBackend, remoteBackend, strategy)
+			sm.SetConflictFallback(sync.ConflictResolutionStrategy(cfg.Sync.ConflictFallback))
+			sm.SetMaxRetries(cfg.Sync.MaxRetries)
+			sm.SetParallelism(cfg.Sync.Parallelism)
+			if !quiet {
+				sm.SetConflictPrompter(conflictPrompterFor(strategy))
+			}
+			if fullSync && replayer == nil {
+				sm.SetBeforeFullSync(func() error { return backupCacheAt(cfg, "full-sync", quiet) })
+			}
+
+			// --list pulls and pushes that list alone
+			var listID string
+			if listName != "" {
+				if fullSync || dryRun {
+					return fmt.Errorf("--list cannot be combined with --full or --dry-run, which cover every list")
+				}
+				if listID, err = findSyncList(localBackend, remoteBackend, listName); err != nil {
+					return err
+				}
+			}
 
 			if dryRun {
-				if !quiet {
-					fmt.Println("Dry run mode - no changes will be made")
+				if quiet {
+					return nil
+				}
+				fmt.Println("Dry run mode - no changes will be made")
+				preview, err := sm.Preview()
+				if err != nil {
+					return fmt.Errorf("sync preview failed: %w", err)
 				}
-				// TODO: Implement dry run preview
+				printSyncPreview(preview, strategy)
 				return nil
 			}
 
@@ -104,6 +139,8 @@ Examples:
 			var result *sync.SyncResult
 			if fullSync {
 				result, err = sm.FullSync()
+			} else if listID != "" {
+				result, err = sm.SyncList(listID)
 			} else {
 				result, err = sm.Sync()
 			}
@@ -118,12 +155,19 @@ Examples:
 			// Display results
 			if !quiet {
 				printSyncResult(result)
+				if verbose {
+					printListResults(result.ListResults)
+				}
+			}
+
+			if replayer == nil {
+				pruneCacheAfterSync(cfg, quiet)
 			}
 			return nil
 		},
 	}
 
-	syncCmd.Flags().BoolVar(&fullSync, "full", false, "Force full re-sync (ignore CTags)")
+	syncCmd.Flags().BoolVar(&fullSync, "full", false, "Force full re-sync (ignore CTags and sync tokens)")
 	syncCmd.Flags().BoolVar(&dryRun, "dry-run", false, "Preview changes without applying")
 	syncCmd.Flags().StringVarP(&listName, "list", "l", "", "Sync specific list only")
 	syncCmd.Flags().BoolVar(&quiet, "quiet", false, "Suppress output (for background sync)")
@@ -131,21 +175,75 @@ Examples:
 	// Add subcommands
 	syncCmd.AddCommand(newSyncStatusCmd())
 	syncCmd.AddCommand(newSyncQueueCmd())
+	syncCmd.AddCommand(newSyncRescueCmd())
+	syncCmd.AddCommand(newSyncRetryCmd())
+	syncCmd.AddCommand(newSyncDropCmd())
 
 	return syncCmd
 }
 
+// pruneCacheAfterSync removes the completed tasks past
+// cache.completed_retention from the cache when cache.prune_after_sync is set
+func pruneCacheAfterSync(cfg *config.Config, quiet bool) {
+	if cfg.Cache == nil || !cfg.Cache.PruneAfterSync {
+		return
+	}
+	db, err := openCacheDatabase(cfg)
+	if err != nil {
+		utils.Warnf("Could not prune the cache: %v", err)
+		return
+	}
+	defer func() { _ = db.Close() }()
+
+	pruned, err := internalSync.PruneAfterSync(cfg, db)
+	if err != nil {
+		utils.Warnf("Could not prune the cache: %v", err)
+	} else if pruned > 0 && !quiet {
+		fmt.Printf("Pruned %d completed tasks past cache.completed_retention from the cache.\n", pruned)
+	}
+}
+
+// syncStatus is what 'sync status' reports, as printed with --json
+type syncStatus struct {
+	Online             bool                  `json:"online"`
+	OfflineReason      string                `json:"offline_reason,omitempty"`
+	Strategy           string                `json:"strategy"`
+	LocalTasks         int                   `json:"local_tasks"`
+	LocalLists         int                   `json:"local_lists"`
+	PendingOperations  int                   `json:"pending_operations"`
+	PendingByOperation map[string]int        `json:"pending_by_operation"`
+	LocallyModified    int                   `json:"locally_modified"`
+	LastSync           time.Time             `json:"last_sync,omitzero"`
+	Lists              []sqlite.ListSyncTime `json:"lists"`
+	Failing            []failingOperation    `json:"failing_operations"`
+}
+
+// failingOperation is a queued operation that failed at least once
+type failingOperation struct {
+	ID         int       `json:"id"`
+	Operation  string    `json:"operation"`
+	TaskUID    string    `json:"task_uid"`
+	ListID     string    `json:"list_id"`
+	CreatedAt  time.Time `json:"created_at"`
+	RetryCount int       `json:"retry_count"`
+	LastError  string    `json:"last_error"`
+	Stranded   bool      `json:"stranded,omitempty"`
+	NextRetry  time.Time `json:"next_retry,omitzero"` // Zero when due
+}
+
 // newSyncStatusCmd creates the 'sync status' command
 func newSyncStatusCmd() *cobra.Command {
-	return &cobra.Command{
+	var jsonOutput bool
+	cmd := &cobra.Command{
 		Use:   "status",
 		Short: "Show sync status",
 		Long: `Display current synchronization status including:
-- Last sync time
-- Number of tasks synced
-- Pending operations
+- Offline/online status
+- Number of cached tasks and lists
+- Pending operations, by operation
 - Locally modified tasks
-- Offline/online status`,
+- Last sync time of each list
+- Queued operations that failed, with their last error`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			cfg := config.GetConfig()
 
@@ -155,6 +253,9 @@ func newSyncStatusCmd() *cobra.Command {
 			hasGlobalSync := cfg.Sync != nil && cfg.Sync.Enabled
 
 			if !hasPerBackendSync && !hasGlobalSync {
+				if jsonOutput {
+					return fmt.Errorf("sync is not enabled")
+				}
 				fmt.Println("Sync is not enabled in configuration")
 				return nil
 			}
@@ -168,16 +269,6 @@ func newSyncStatusCmd() *cobra.Command {
 				return err
 			}
 
-			// Check connection status
-			isOffline, offlineReason := isBackendOffline(remoteBackend)
-
-			// Get sync stats
-			sm := sync.NewSyncManager(localBackend, remoteBackend, sync.ServerWins)
-			stats, err := sm.GetSyncStats()
-			if err != nil {
-				return fmt.Errorf("failed to get sync stats: %w", err)
-			}
-
 			// Determine conflict resolution strategy for display
 			var strategyStr string
 			if hasPerBackendSync {
@@ -189,33 +280,129 @@ func newSyncStatusCmd() *cobra.Command {
 				strategyStr = "server_wins (default)"
 			}
 
-			// Display status
-			fmt.Println("\n=== Sync Status ===")
-			if isOffline {
-				fmt.Printf("Connection: Offline (%s)\n", offlineReason)
-			} else {
-				fmt.Println("Connection: Online")
+			status, err := getSyncStatus(localBackend, remoteBackend)
+			if err != nil {
+				return err
 			}
+			status.Strategy = strategyStr
 
-			fmt.Printf("Local tasks: %d\n", stats.LocalTasks)
-			fmt.Printf("Local lists: %d\n", stats.LocalLists)
-			fmt.Printf("Pending operations: %d\n", stats.PendingOperations)
-			fmt.Printf("Locally modified: %d\n", stats.LocallyModified)
-			fmt.Printf("Strategy: %s\n", strategyStr)
-
-			// Get last sync time
-			lastSync, err := getLastSyncTime(localBackend)
-			if err == nil && !lastSync.IsZero() {
-				timeSince := time.Since(lastSync)
-				fmt.Printf("Last sync: %s ago\n", formatDuration(timeSince))
-			} else {
-				fmt.Println("Last sync: Never")
+			if jsonOutput {
+				return utils.OutputJSON(status)
 			}
-
-			fmt.Println()
+			printSyncStatus(status)
 			return nil
 		},
 	}
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output in JSON format")
+	return cmd
+}
+
+// getSyncStatus gathers the state of the cache and its sync queue
+func getSyncStatus(localBackend *sqlite.SQLiteBackend, remoteBackend backend.TaskManager) (*syncStatus, error) {
+	isOffline, offlineReason := isBackendOffline(remoteBackend)
+
+	sm := sync.NewSyncManager(localBackend, remoteBackend, sync.ServerWins)
+	stats, err := sm.GetSyncStats()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sync stats: %w", err)
+	}
+	queueStats, err := localBackend.GetSyncQueueStats(0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get queue stats: %w", err)
+	}
+	lists, err := localBackend.GetListSyncTimes()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get list sync times: %w", err)
+	}
+	ops, err := localBackend.GetQueuedSyncOperations()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pending operations: %w", err)
+	}
+
+	status := &syncStatus{
+		Online:             !isOffline,
+		OfflineReason:      offlineReason,
+		LocalTasks:         stats.LocalTasks,
+		LocalLists:         stats.LocalLists,
+		PendingOperations:  stats.PendingOperations,
+		PendingByOperation: queueStats.ByOperation,
+		LocallyModified:    stats.LocallyModified,
+		Lists:              lists,
+		Failing:            []failingOperation{},
+	}
+	for _, list := range lists {
+		if list.LastSync.After(status.LastSync) {
+			status.LastSync = list.LastSync
+		}
+	}
+	for _, op := range ops {
+		if op.RetryCount > 0 {
+			status.Failing = append(status.Failing, failingOperation{
+				ID: op.ID, Operation: op.Operation, TaskUID: op.TaskUID, ListID: op.ListID,
+				CreatedAt: op.CreatedAt, RetryCount: op.RetryCount, LastError: op.LastError, Stranded: op.Stranded, NextRetry: op.NextRetry,
+			})
+		}
+	}
+	return status, nil
+}
+
+// printSyncStatus displays the sync status as text
+func printSyncStatus(status *syncStatus) {
+	fmt.Println("\n=== Sync Status ===")
+	if status.Online {
+		fmt.Println("Connection: Online")
+	} else {
+		fmt.Printf("Connection: Offline (%s)\n", status.OfflineReason)
+	}
+
+	fmt.Printf("Local tasks: %d\n", status.LocalTasks)
+	fmt.Printf("Local lists: %d\n", status.LocalLists)
+	fmt.Printf("Pending operations: %d", status.PendingOperations)
+	if status.PendingOperations > 0 {
+		fmt.Printf(" (create: %d, update: %d, delete: %d)",
+			status.PendingByOperation["create"], status.PendingByOperation["update"], status.PendingByOperation["delete"])
+	}
+	fmt.Println()
+	fmt.Printf("Locally modified: %d\n", status.LocallyModified)
+	fmt.Printf("Strategy: %s\n", status.Strategy)
+	fmt.Printf("Last sync: %s\n", formatSyncTime(status.LastSync))
+
+	if len(status.Lists) > 0 {
+		width := 0
+		for _, list := range status.Lists {
+			width = max(width, len(utils.SanitizeLine(list.Name)))
+		}
+		fmt.Println("\nLists:")
+		for _, list := range status.Lists {
+			fmt.Printf("  %-*s  %s\n", width, utils.SanitizeLine(list.Name), formatSyncTime(list.LastSync))
+		}
+	}
+
+	if len(status.Failing) > 0 {
+		fmt.Printf("\nFailing operations (%d):\n", len(status.Failing))
+		for _, op := range status.Failing {
+			fmt.Printf("  %s: %s (list: %s, retries: %d)\n", op.Operation, op.TaskUID, op.ListID, op.RetryCount)
+			if op.LastError != "" {
+				fmt.Printf("    Error: %s\n", utils.SanitizeLine(op.LastError))
+			}
+			if !op.NextRetry.IsZero() {
+				fmt.Printf("    Next retry: in %s\n", formatDuration(time.Until(op.NextRetry)))
+			}
+			if op.Stranded {
+				fmt.Println("    Stranded: not retried until moved with 'gosynctasks sync rescue'")
+			}
+		}
+	}
+	fmt.Println("\nQueue details: gosynctasks sync queue; cache details: gosynctasks db stats")
+	fmt.Println()
+}
+
+// formatSyncTime describes a sync time as how long ago it was
+func formatSyncTime(t time.Time) string {
+	if t.IsZero() {
+		return "Never"
+	}
+	return formatDuration(time.Since(t)) + " ago"
 }
 
 // newSyncQueueCmd creates the 'sync queue' command
@@ -244,8 +431,12 @@ func newSyncQueueCmd() *cobra.Command {
 				return err
 			}
 
+			if showStats, _ := cmd.Flags().GetBool("stats"); showStats {
+				return printSyncQueueStats(localBackend)
+			}
+
 			// Get pending operations
-			ops, err := localBackend.GetPendingSyncOperations()
+			ops, err := localBackend.GetQueuedSyncOperations()
 			if err != nil {
 				return fmt.Errorf("failed to get pending operations: %w", err)
 			}
@@ -262,9 +453,15 @@ func newSyncQueueCmd() *cobra.Command {
 				if op.RetryCount > 0 {
 					fmt.Printf("    Retries: %d\n", op.RetryCount)
 				}
+				if !op.NextRetry.IsZero() {
+					fmt.Printf("    Next retry: in %s\n", formatDuration(time.Until(op.NextRetry)))
+				}
 				if op.LastError != "" {
 					fmt.Printf("    Error: %s\n", op.LastError)
 				}
+				if op.Stranded {
+					fmt.Println("    Stranded: not retried until moved with 'gosynctasks sync rescue'")
+				}
 				fmt.Println()
 			}
 
@@ -272,12 +469,37 @@ func newSyncQueueCmd() *cobra.Command {
 		},
 	}
 
+	queueCmd.Flags().Bool("stats", false, "Show queue size by operation and the oldest entries")
 	queueCmd.AddCommand(newSyncQueueClearCmd())
 	queueCmd.AddCommand(newSyncQueueRetryCmd())
 
 	return queueCmd
 }
 
+// printSyncQueueStats prints row counts and the oldest entries of the sync queue
+func printSyncQueueStats(localBackend *sqlite.SQLiteBackend) error {
+	stats, err := localBackend.GetSyncQueueStats(5)
+	if err != nil {
+		return fmt.Errorf("failed to get queue stats: %w", err)
+	}
+
+	fmt.Printf("\nSync Queue: %d operation(s)\n", stats.Total)
+	for _, operation := range []string{"create", "update", "delete"} {
+		fmt.Printf("  %-8s %d\n", operation+":", stats.ByOperation[operation])
+	}
+	fmt.Printf("  Failing: %d (%d bytes of error text)\n", stats.Failing, stats.ErrorBytes)
+
+	if len(stats.Oldest) > 0 {
+		fmt.Println("\nOldest:")
+		for _, op := range stats.Oldest {
+			fmt.Printf("  %s  %s: %s (list: %s, retries: %d)\n",
+				op.CreatedAt.Format("2006-01-02 15:04:05"), op.Operation, op.TaskUID, op.ListID, op.RetryCount)
+		}
+	}
+	fmt.Println()
+	return nil
+}
+
 // newSyncQueueClearCmd creates the 'sync queue clear' command
 func newSyncQueueClearCmd() *cobra.Command {
 	var failed bool
@@ -304,8 +526,12 @@ func newSyncQueueClearCmd() *cobra.Command {
 				return err
 			}
 
+			if showStats, _ := cmd.Flags().GetBool("stats"); showStats {
+				return printSyncQueueStats(localBackend)
+			}
+
 			// Get pending operations
-			ops, err := localBackend.GetPendingSyncOperations()
+			ops, err := localBackend.GetQueuedSyncOperations()
 			if err != nil {
 				return fmt.Errorf("failed to get pending operations: %w", err)
 			}
@@ -357,36 +583,205 @@ func newSyncQueueRetryCmd() *cobra.Command {
 				return err
 			}
 
-			// Get failed operations
-			ops, err := localBackend.GetPendingSyncOperations()
+			retried, err := localBackend.ResetSyncRetries()
 			if err != nil {
-				return fmt.Errorf("failed to get pending operations: %w", err)
+				return fmt.Errorf("failed to reset failed operations: %w", err)
 			}
 
-			retried := 0
-			for _, op := range ops {
-				if op.RetryCount == 0 {
-					continue // Skip non-failed
+			fmt.Printf("Reset %d failed operations for retry\n", retried)
+			return nil
+		},
+	}
+}
+
+// newSyncRetryCmd creates the 'sync retry' command
+func newSyncRetryCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "retry [task-uid...]",
+		Short: "Push failed operations again",
+		Long: `Reset the retry count of queued operations that failed, so the next sync
+pushes them again. An operation that failed sync.max_retries times (5 by
+default) is not pushed until it is reset. Without task UIDs, every failed
+operation is reset; 'gosynctasks sync status' lists them.
+
+Examples:
+  gosynctasks sync retry
+  gosynctasks sync retry task-1718000000-abcd1234`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg := config.GetConfig()
+			if len(cfg.GetSyncPairs()) == 0 && (cfg.Sync == nil || !cfg.Sync.Enabled) {
+				return utils.ErrSyncNotEnabled()
+			}
+
+			explicitBackend, _ := cmd.Root().PersistentFlags().GetString("backend")
+			localBackend, _, err := getSyncBackends(cfg, explicitBackend)
+			if err != nil {
+				return err
+			}
+
+			reset, err := localBackend.ResetSyncRetries(args...)
+			if err != nil {
+				return fmt.Errorf("failed to reset failed operations: %w", err)
+			}
+			if reset == 0 {
+				if len(args) > 0 {
+					return fmt.Errorf("no failed operations queued for %s", strings.Join(args, ", "))
 				}
+				fmt.Println("No failed operations")
+				return nil
+			}
+			fmt.Printf("Reset %d failed operations; run 'gosynctasks sync' to push them\n", reset)
+			return nil
+		},
+	}
+}
+
+// newSyncDropCmd creates the 'sync drop' command
+func newSyncDropCmd() *cobra.Command {
+	var force bool
 
-				// Reset retry count by updating database directly
-				db, err := localBackend.GetDB()
+	cmd := &cobra.Command{
+		Use:   "drop <task-uid>",
+		Short: "Discard the queued changes of a task",
+		Long: `Remove the operations queued for a task and mark it as in sync, discarding
+its local changes: the next sync replaces them with the version on the
+remote, a task deleted locally comes back, and a task created locally and
+never pushed is removed from the cache. Use it for a change the remote keeps
+refusing; 'gosynctasks sync status' lists those.
+
+Example:
+  gosynctasks sync drop task-1718000000-abcd1234`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			taskUID := args[0]
+			cfg := config.GetConfig()
+			if len(cfg.GetSyncPairs()) == 0 && (cfg.Sync == nil || !cfg.Sync.Enabled) {
+				return utils.ErrSyncNotEnabled()
+			}
+
+			explicitBackend, _ := cmd.Root().PersistentFlags().GetString("backend")
+			localBackend, _, err := getSyncBackends(cfg, explicitBackend)
+			if err != nil {
+				return err
+			}
+
+			ops, err := localBackend.GetQueuedSyncOperations()
+			if err != nil {
+				return fmt.Errorf("failed to get pending operations: %w", err)
+			}
+			ops = slices.DeleteFunc(ops, func(op sqlite.SyncOperation) bool { return op.TaskUID != taskUID })
+			if len(ops) == 0 {
+				return fmt.Errorf("no operations queued for task %s", taskUID)
+			}
+
+			if !force {
+				for _, op := range ops {
+					fmt.Printf("  %s: %s (list: %s, retries: %d)\n", op.Operation, op.TaskUID, op.ListID, op.RetryCount)
+					if op.LastError != "" {
+						fmt.Printf("    Error: %s\n", utils.SanitizeLine(op.LastError))
+					}
+				}
+				confirmed, err := utils.PromptConfirmation(fmt.Sprintf("Discard the local changes of task %s?", taskUID))
 				if err != nil {
 					return err
 				}
+				if !confirmed {
+					fmt.Println("Drop cancelled.")
+					return nil
+				}
+			}
 
-				_, err = db.Exec("UPDATE sync_queue SET retry_count = 0, last_error = '' WHERE id = ?", op.ID)
+			if err := backupTaskManagerCache(cfg, localBackend, "sync-drop"); err != nil {
+				return err
+			}
+			if err := localBackend.ClearSyncFlagsAndQueue(taskUID); err != nil {
+				return fmt.Errorf("failed to drop the operations of task %s: %w", taskUID, err)
+			}
+			fmt.Printf("Dropped %d operations of task %s\n", len(ops), taskUID)
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVarP(&force, "force", "f", false, "Skip confirmation prompt")
+	return cmd
+}
+
+// newSyncRescueCmd creates the 'sync rescue' command
+func newSyncRescueCmd() *cobra.Command {
+	var target string
+
+	cmd := &cobra.Command{
+		Use:   "rescue <list>",
+		Short: "Move stranded changes of a list deleted on the remote",
+		Long: `Move the tasks of a list deleted on the remote, whose local changes could
+not be pushed, to another list. They are pushed there as new tasks on the
+next sync. Without --to, the target list is chosen interactively.
+
+Rescuing a list to itself queues its changes again, for a list that was
+restored on the remote.
+
+Examples:
+  gosynctasks sync rescue Work
+  gosynctasks sync rescue Work --to Inbox`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg := config.GetConfig()
+			if len(cfg.GetSyncPairs()) == 0 && (cfg.Sync == nil || !cfg.Sync.Enabled) {
+				return utils.ErrSyncNotEnabled()
+			}
+
+			explicitBackend, _ := cmd.Root().PersistentFlags().GetString("backend")
+			localBackend, remoteBackend, err := getSyncBackends(cfg, explicitBackend)
+			if err != nil {
+				return err
+			}
+
+			localLists, err := localBackend.GetTaskLists()
+			if err != nil {
+				return fmt.Errorf("failed to get cached lists: %w", err)
+			}
+			source, err := operations.FindListByNameFull(localLists, args[0])
+			if err != nil {
+				return fmt.Errorf("list '%s' not found in the cache: %w", args[0], err)
+			}
+
+			remoteLists, err := remoteBackend.GetTaskLists()
+			if err != nil {
+				return fmt.Errorf("failed to get remote lists: %w", err)
+			}
+			var destination *backend.TaskList
+			if target != "" {
+				destination, err = operations.FindListByNameFull(remoteLists, target)
 				if err != nil {
-					fmt.Printf("Warning: failed to reset retry for operation %d: %v\n", op.ID, err)
-					continue
+					return fmt.Errorf("list '%s' not found on the remote: %w", target, err)
+				}
+			} else {
+				fmt.Printf("Move the stranded tasks of '%s' to:\n", source.QualifiedName())
+				destination, err = operations.SelectListInteractively(remoteLists, remoteBackend)
+				if err != nil {
+					return err
 				}
-				retried++
 			}
 
-			fmt.Printf("Reset %d failed operations for retry\n", retried)
+			if err := backupTaskManagerCache(cfg, localBackend, "sync-rescue"); err != nil {
+				return err
+			}
+			rescued, err := localBackend.RescueStrandedTasks(source.ID, destination.ID)
+			if err != nil {
+				return fmt.Errorf("failed to move stranded tasks: %w", err)
+			}
+			if rescued == 0 {
+				fmt.Printf("No stranded tasks in '%s'\n", source.QualifiedName())
+				return nil
+			}
+			fmt.Printf("Moved %d tasks from '%s' to '%s'; run 'gosynctasks sync' to push them\n",
+				rescued, source.QualifiedName(), destination.QualifiedName())
 			return nil
 		},
 	}
+
+	cmd.Flags().StringVar(&target, "to", "", "List to move the stranded tasks to")
+	return cmd
 }
 
 // Helper functions
@@ -437,6 +832,15 @@ func getSyncBackends(cfg *config.Config, explicitBackend string) (*sqlite.SQLite
 		return nil, nil, fmt.Errorf("failed to create cache backend: %w", err)
 	}
 
+	// Rows of a renamed backend would no longer match and be synced as new
+	cacheDB, err := cacheBackend.GetDB()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open cache database: %w", err)
+	}
+	if err := checkCacheBackendNames(cfg, cacheDB); err != nil {
+		return nil, nil, err
+	}
+
 	// Get remote backend
 	remoteCfg, err := cfg.GetBackend(remoteBackendName)
 	if err != nil {
@@ -534,7 +938,18 @@ func printSyncResult(result *sync.SyncResult) {
 
 	if result.ConflictsFound > 0 {
 		fmt.Printf("Conflicts found: %d\n", result.ConflictsFound)
-		fmt.Printf("Conflicts resolved: %d\n", result.ConflictsResolved)
+		fmt.Printf("Conflicts resolved: %d%s\n", result.ConflictsResolved, sync.FormatResolvedBy(result.ResolvedBy()))
+		for _, conflict := range result.Conflicts {
+			fmt.Print(sync.FormatConflict(conflict))
+		}
+	}
+
+	for _, rewrite := range result.Rewrites {
+		fmt.Print(sync.FormatRemoteRewrite(rewrite))
+	}
+
+	for _, stranded := range result.Stranded {
+		fmt.Printf("\n⚠ %s\n", stranded)
 	}
 
 	if len(result.Errors) > 0 {
@@ -548,24 +963,146 @@ func printSyncResult(result *sync.SyncResult) {
 	fmt.Println()
 }
 
-// getLastSyncTime retrieves the most recent sync timestamp
-func getLastSyncTime(local *sqlite.SQLiteBackend) (time.Time, error) {
-	db, err := local.GetDB()
-	if err != nil {
-		return time.Time{}, err
+// printListResults displays what a sync did with each list as a table
+func printListResults(results []sync.ListSyncResult) {
+	if len(results) == 0 {
+		return
+	}
+
+	width := len("LIST")
+	for _, list := range results {
+		width = max(width, len(utils.SanitizeLine(list.Name)))
+	}
+
+	fmt.Printf("%-*s  %6s  %6s  %9s  %s\n", width, "LIST", "PULLED", "PUSHED", "CONFLICTS", "STATUS")
+	for _, list := range results {
+		var status string
+		switch {
+		case list.Err != nil:
+			status = fmt.Sprintf("error: %v", list.Err)
+		case list.Skipped:
+			status = "skipped: " + list.SkipReason
+		case list.LocalCTag == "" && list.RemoteCTag == "":
+			status = "synced"
+		case list.Delta:
+			status = fmt.Sprintf("synced changes only (CTag %q -> %q)", list.LocalCTag, list.RemoteCTag)
+		default:
+			status = fmt.Sprintf("synced (CTag %q -> %q)", list.LocalCTag, list.RemoteCTag)
+		}
+		fmt.Printf("%-*s  %6d  %6d  %9d  %s\n", width, utils.SanitizeLine(list.Name), list.Pulled, list.Pushed, list.Conflicts, utils.SanitizeLine(status))
+	}
+	fmt.Println()
+}
+
+// printSyncPreview displays what a sync would change
+func printSyncPreview(preview *sync.SyncPreview, strategy sync.ConflictResolutionStrategy) {
+	counts := make(map[string]int)
+	for _, change := range preview.Changes {
+		counts[change.Kind]++
+	}
+	fmt.Printf("\nTo pull: %d new, %d updated, %d deleted\n", counts["new"], counts["update"], counts["delete"])
+	for _, change := range preview.Changes {
+		switch change.Kind {
+		case "new":
+			fmt.Printf("  + %s (%s)\n", utils.SanitizeLine(change.Remote.Summary), utils.SanitizeLine(change.ListName))
+		case "delete":
+			fmt.Printf("  - %s (%s)\n", utils.SanitizeLine(change.Local.Summary), utils.SanitizeLine(change.ListName))
+		default:
+			fmt.Printf("  ~ %s (%s)\n", utils.SanitizeLine(change.Local.Summary), utils.SanitizeLine(change.ListName))
+			fmt.Print(sync.FormatTaskDiff(change.Local, change.Remote, "    "))
+		}
 	}
 
-	var lastSync int64
-	err = db.QueryRow("SELECT MAX(last_full_sync) FROM list_sync_metadata").Scan(&lastSync)
+	fmt.Printf("To push: %d pending operation(s)\n", len(preview.PendingOperations))
+
+	if len(preview.Conflicts) > 0 {
+		fmt.Printf("Conflicts: %d (would be resolved with %s)\n", len(preview.Conflicts), strategy)
+		for _, conflict := range preview.Conflicts {
+			fmt.Print(sync.FormatConflict(conflict))
+		}
+	}
+
+	for _, err := range preview.Errors {
+		fmt.Printf("⚠ %v\n", err)
+	}
+	fmt.Println()
+}
+
+// findSyncList returns the ID of the list named name, looked up in the cache
+// and then on the remote, for a list created there and not cached yet
+func findSyncList(local sync.LocalStore, remote backend.TaskManager, name string) (string, error) {
+	lists, err := local.GetTaskLists()
 	if err != nil {
-		return time.Time{}, err
+		return "", fmt.Errorf("failed to get local lists: %w", err)
 	}
+	listID, err := operations.FindListByName(lists, name)
+	if err == nil {
+		return listID, nil
+	}
+	remoteLists, remoteErr := remote.GetTaskLists()
+	if remoteErr != nil {
+		return "", err
+	}
+	if remoteID, remoteErr := operations.FindListByName(remoteLists, name); remoteErr == nil {
+		return remoteID, nil
+	}
+	return "", err
+}
 
-	if lastSync == 0 {
-		return time.Time{}, nil
+// listSyncPrompter returns the function asking how to resolve conflicts when
+// the application syncs a list, as '<list> sync' does
+func listSyncPrompter(cfg *config.Config) sync.ConflictPrompter {
+	if cfg.Sync == nil {
+		return nil
 	}
+	return conflictPrompterFor(sync.ConflictResolutionStrategy(cfg.Sync.ConflictResolution))
+}
+
+// conflictPrompterFor returns the function asking how to resolve conflicts
+// under strategy, or nil when the strategy does not ask or stdin is not a
+// terminal, so that the strategy falls back
+func conflictPrompterFor(strategy sync.ConflictResolutionStrategy) sync.ConflictPrompter {
+	if strategy == sync.Prompt && term.IsTerminal(int(os.Stdin.Fd())) {
+		return promptConflict
+	}
+	return nil
+}
 
-	return time.Unix(lastSync, 0), nil
+// promptConflict shows a conflict with both versions side by side and asks
+// how to resolve it
+func promptConflict(conflict sync.Conflict) (sync.ConflictResolutionStrategy, error) {
+	width := 80
+	if w, _, err := term.GetSize(int(os.Stdout.Fd())); err == nil && w > 0 {
+		width = w
+	}
+	fmt.Println("\nConflict:")
+	fmt.Print(sync.FormatConflictSideBySide(conflict, width))
+	return askConflictResolution(sync.KeepBoth), nil
+}
+
+// askConflictResolution reads the strategy picked for a conflict, def on an
+// empty answer or when input ends
+func askConflictResolution(def sync.ConflictResolutionStrategy) sync.ConflictResolutionStrategy {
+	for {
+		fmt.Print("Keep [s]erver, [l]ocal, [m]erge or [b]oth? [b]: ")
+		answer, err := utils.ReadString()
+		if err != nil {
+			return def
+		}
+		switch strings.ToLower(answer) {
+		case "s", "server", "r", "remote":
+			return sync.ServerWins
+		case "l", "local":
+			return sync.LocalWins
+		case "m", "merge":
+			return sync.Merge
+		case "b", "both":
+			return sync.KeepBoth
+		case "":
+			return def
+		}
+		fmt.Println("Please enter s, l, m or b")
+	}
 }
 
 // formatDuration formats a duration in a human-readable way
@@ -636,6 +1173,9 @@ func performAutoSync(cfg *config.Config) {
 		}
 
 		sm := sync.NewSyncManager(localBackend, remoteBackend, strategy)
+		sm.SetConflictFallback(sync.ConflictResolutionStrategy(cfg.Sync.ConflictFallback))
+		sm.SetMaxRetries(cfg.Sync.MaxRetries)
+		sm.SetParallelism(cfg.Sync.Parallelism)
 		_, _ = sm.Sync()
 	}()
 }
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"gosynctasks/backend"
+	"gosynctasks/backend/sqlite"
+)
+
+// TestGetSyncStatus tests that 'sync status' reports the queue by operation
+// and the operations that failed with their errors
+func TestGetSyncStatus(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "cache.db")
+	cache, err := sqlite.NewSQLiteBackend(backend.BackendConfig{Name: "nextcloud", Type: "sqlite", DBPath: dbPath})
+	if err != nil {
+		t.Fatalf("NewSQLiteBackend() error = %v", err)
+	}
+	defer func() { _ = cache.Close() }()
+	listID, _ := cache.CreateTaskList("Work", "", "")
+	_, _ = cache.AddTask(listID, backend.Task{Summary: "Queued", Status: "NEEDS-ACTION"})
+	uid, _ := cache.AddTask(listID, backend.Task{Summary: "Refused", Status: "NEEDS-ACTION"})
+	ops, _ := cache.GetPendingSyncOperations()
+	for _, op := range ops {
+		if op.TaskUID == uid {
+			_ = cache.RecordSyncFailure(op.ID, "HTTP 507: quota exceeded", time.Now().Add(time.Minute))
+		}
+	}
+
+	status, err := getSyncStatus(cache, backend.NewMockBackend())
+	if err != nil {
+		t.Fatalf("getSyncStatus() error = %v", err)
+	}
+	if !status.Online || status.PendingOperations != 2 || status.PendingByOperation["create"] != 2 {
+		t.Errorf("Status = %+v, want online with 2 queued creates", status)
+	}
+	if len(status.Lists) != 1 || status.Lists[0].Name != "Work" {
+		t.Errorf("Lists = %+v, want Work", status.Lists)
+	}
+	if len(status.Failing) != 1 || status.Failing[0].TaskUID != uid || status.Failing[0].LastError != "HTTP 507: quota exceeded" || status.Failing[0].RetryCount != 1 || status.Failing[0].NextRetry.IsZero() {
+		t.Errorf("Failing = %+v, want the refused create with its error", status.Failing)
+	}
+}
@@ -0,0 +1,180 @@
+package main
+
+import (
+	"fmt"
+	"gosynctasks/backend"
+	"gosynctasks/internal/operations"
+	"gosynctasks/internal/utils"
+
+	"github.com/spf13/cobra"
+)
+
+// newTagsCmd creates the 'tags' command for listing and managing categories
+func newTagsCmd() *cobra.Command {
+	var listName string
+
+	cmd := &cobra.Command{
+		Use:   "tags",
+		Short: "List and manage task tags",
+		Long: `Show every tag (category) in use with the number of tasks carrying it,
+across all lists or one list.
+
+Use the subcommands to fix typos and merge or remove tags. Changes go through
+the normal task operations, so they are queued for sync like any other edit.
+
+Examples:
+  gosynctasks tags
+  gosynctasks tags --list Work
+  gosynctasks tags rename work wokr Work --to work
+  gosynctasks tags rm obsolete --dry-run`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			taskManager, lists, err := tagsTarget(listName)
+			if err != nil {
+				return err
+			}
+
+			counts, err := operations.CountTags(taskManager, lists)
+			if err != nil {
+				return err
+			}
+			if len(counts) == 0 {
+				fmt.Println("No tags in use.")
+				return nil
+			}
+
+			for _, tag := range counts {
+				fmt.Printf("%5d  %s\n", tag.Count, utils.SanitizeLine(tag.Name))
+			}
+			return nil
+		},
+	}
+
+	cmd.PersistentFlags().StringVar(&listName, "list", "", "Only use this list")
+	cmd.AddCommand(newTagsRenameCmd(&listName), newTagsRmCmd(&listName))
+
+	return cmd
+}
+
+// newTagsRenameCmd creates 'tags rename', which merges one or more tags into one
+func newTagsRenameCmd(listName *string) *cobra.Command {
+	var to string
+	var dryRun bool
+	var yes bool
+
+	cmd := &cobra.Command{
+		Use:   "rename <tag>... --to <tag>",
+		Short: "Rename or merge tags",
+		Long: `Replace each of the given tags with the --to tag on every task, closed tasks
+included. Tags match exactly; list every spelling to merge, e.g. "Work" and
+"wokr" into "work".`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if to == "" {
+				return fmt.Errorf("specify the new tag with --to")
+			}
+			return runTagChanges(*listName, args, to, dryRun, yes, "Apply these changes?")
+		},
+	}
+
+	cmd.Flags().StringVar(&to, "to", "", "Tag to rename to")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Show changes without applying them")
+	cmd.Flags().BoolVarP(&yes, "yes", "y", false, "Apply without confirmation")
+
+	return cmd
+}
+
+// newTagsRmCmd creates 'tags rm', which removes tags from every task
+func newTagsRmCmd(listName *string) *cobra.Command {
+	var dryRun bool
+	var yes bool
+
+	cmd := &cobra.Command{
+		Use:   "rm <tag>...",
+		Short: "Remove tags from all tasks",
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runTagChanges(*listName, args, "", dryRun, yes, "Remove these tags?")
+		},
+	}
+
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Show changes without applying them")
+	cmd.Flags().BoolVarP(&yes, "yes", "y", false, "Remove without confirmation")
+
+	return cmd
+}
+
+// tagsTarget returns the task manager and the lists the tags commands work on
+func tagsTarget(listName string) (backend.TaskManager, []backend.TaskList, error) {
+	taskManager := application.GetTaskManager()
+	if taskManager == nil {
+		return nil, nil, fmt.Errorf("task manager not initialized")
+	}
+
+	lists := application.GetTaskLists()
+	if listName != "" {
+		list, err := operations.FindListByNameFull(lists, listName)
+		if err != nil {
+			return nil, nil, err
+		}
+		lists = []backend.TaskList{*list}
+	}
+	return taskManager, lists, nil
+}
+
+// runTagChanges shows the tag changes, asks for confirmation and applies them
+func runTagChanges(listName string, sources []string, target string, dryRun, yes bool, prompt string) error {
+	taskManager, lists, err := tagsTarget(listName)
+	if err != nil {
+		return err
+	}
+
+	changes, err := operations.FindTagChanges(taskManager, lists, sources, target)
+	if err != nil {
+		return err
+	}
+	if len(changes) == 0 {
+		fmt.Println("No tasks carry these tags.")
+		return nil
+	}
+
+	for _, change := range changes {
+		fmt.Print(operations.FormatTagChange(change))
+	}
+	fmt.Printf("\n%d task(s) matched.\n", len(changes))
+
+	if dryRun {
+		fmt.Println("Dry run: no changes made.")
+		return nil
+	}
+
+	if !yes {
+		confirmed, err := utils.PromptConfirmation(prompt)
+		if err != nil {
+			return err
+		}
+		if !confirmed {
+			fmt.Println("Cancelled.")
+			return nil
+		}
+	}
+
+	results := operations.ApplyTagChanges(taskManager, changes, application)
+
+	failed := 0
+	for _, result := range results {
+		summary := utils.SanitizeLine(result.Change.Task.Summary)
+		if result.Err != nil {
+			failed++
+			fmt.Printf("  ✗ %s: %v\n", summary, result.Err)
+		} else {
+			fmt.Printf("  ✓ %s\n", summary)
+		}
+	}
+	fmt.Printf("\n%d updated, %d failed.\n", len(results)-failed, failed)
+
+	if failed > 0 {
+		return fmt.Errorf("%d task(s) could not be updated", failed)
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"fmt"
+	"gosynctasks/internal/cache"
+	"gosynctasks/internal/config"
+	"gosynctasks/internal/version"
+
+	"github.com/spf13/cobra"
+)
+
+// newVersionCmd creates the 'version' command
+func newVersionCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "version",
+		Short: "Show version and build information",
+		Long: `Show the version, git commit, build date and Go version of this binary.
+
+With --check-update, also ask GitHub whether a newer release exists. The result
+is cached for a day, nothing is downloaded, and the lookup can be turned off
+with 'disable_update_check: true' in the config.`,
+		Args: cobra.NoArgs,
+		// Skip the root's backend setup so version works with a broken config
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			if configPath != "" {
+				config.SetCustomConfigPath(configPath)
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			info := version.Get()
+			fmt.Printf("gosynctasks %s\n", info.Version)
+			fmt.Printf("  Commit:     %s\n", info.Commit)
+			fmt.Printf("  Built:      %s\n", info.Date)
+			fmt.Printf("  Go version: %s\n", info.GoVersion)
+			fmt.Printf("  Platform:   %s\n", info.Platform)
+
+			checkUpdate, _ := cmd.Flags().GetBool("check-update")
+			if !checkUpdate {
+				return nil
+			}
+			if config.GetConfig().DisableUpdateCheck {
+				return fmt.Errorf("update checks are disabled in the config (disable_update_check)")
+			}
+
+			cacheDir, err := cache.GetCacheDir()
+			if err != nil {
+				return fmt.Errorf("failed to get cache directory: %w", err)
+			}
+			status, err := version.NewUpdateChecker(cacheDir).Check(info.Version)
+			if err != nil {
+				return err
+			}
+
+			fmt.Println()
+			switch {
+			case status.Newer:
+				fmt.Printf("A newer release is available: %s\n  %s\n", status.Latest.Version, status.Latest.URL)
+			case info.Version == "devel":
+				fmt.Printf("Development build; the latest release is %s\n", status.Latest.Version)
+			default:
+				fmt.Printf("Up to date (latest release: %s)\n", status.Latest.Version)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().Bool("check-update", false, "check GitHub for a newer release (cached for 24h, never downloads)")
+
+	return cmd
+}
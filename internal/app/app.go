@@ -3,10 +3,14 @@ package app
 import (
 	"fmt"
 	"gosynctasks/backend"
+	"gosynctasks/backend/sqlite"
+	backendsync "gosynctasks/backend/sync"
 	"gosynctasks/internal/cache"
 	"gosynctasks/internal/config"
 	"gosynctasks/internal/operations"
+	"gosynctasks/internal/utils"
 	"log"
+	"slices"
 	"time"
 
 	"github.com/spf13/cobra"
@@ -20,6 +24,8 @@ type App struct {
 	registry        *backend.BackendRegistry
 	selector        *backend.BackendSelector
 	selectedBackend string
+	// conflictPrompter asks how to resolve conflicts when a list is synced
+	conflictPrompter backendsync.ConflictPrompter
 	// syncCoordinator disabled - needs redesign for multi-remote architecture
 	// syncCoordinator *sync.SyncCoordinator
 }
@@ -28,6 +34,11 @@ type App struct {
 // explicitBackend can be empty (will use default/auto-detection)
 func NewApp(explicitBackend string) (*App, error) {
 	cfg := config.GetConfig()
+	if err := operations.ValidateShortcuts(cfg.Shortcuts); err != nil {
+		return nil, fmt.Errorf("invalid config: %w", err)
+	}
+	utils.SetLocale(cfg.GetLocale())
+	sqlite.SetSlowQueryThreshold(cfg.GetSlowQueryThreshold())
 
 	// Create backend registry
 	registry, err := backend.NewBackendRegistry(cfg.GetEnabledBackends())
@@ -73,19 +84,50 @@ func NewApp(explicitBackend string) (*App, error) {
 	}
 
 	// Load task lists with cache fallback
-	app.taskLists, err = cache.LoadTaskListsWithFallback(taskManager)
+	lists, err := cache.LoadTaskListsWithFallbackFor(selectedBackend, taskManager)
 	if err != nil {
 		log.Printf("Warning: Could not load task lists: %v", err)
 	}
+	app.setTaskLists(lists)
 
 	return app, nil
 }
 
-// GetTaskLists returns the cached task lists
+// NewAppWithTaskManager creates an App around an already built task manager,
+// such as a replay of a recorded session, skipping backend selection
+func NewAppWithTaskManager(backendName string, taskManager backend.TaskManager, taskLists []backend.TaskList) *App {
+	app := &App{
+		config:          config.GetConfig(),
+		taskManager:     taskManager,
+		selectedBackend: backendName,
+	}
+	app.setTaskLists(taskLists)
+	return app
+}
+
+// WrapTaskManager replaces the task manager with a decorator around it,
+// e.g. one recording its calls
+func (a *App) WrapTaskManager(wrap func(backend.TaskManager) backend.TaskManager) {
+	a.taskManager = wrap(a.taskManager)
+}
+
+// GetSelectedBackend returns the name of the selected backend
+func (a *App) GetSelectedBackend() string {
+	return a.selectedBackend
+}
+
+// GetTaskLists returns the cached task lists, in display order
 func (a *App) GetTaskLists() []backend.TaskList {
 	return a.taskLists
 }
 
+// setTaskLists stores lists as the backend returned them, in the display
+// order of the config
+func (a *App) setTaskLists(lists []backend.TaskList) {
+	lists = backend.NumberListPositions(lists)
+	a.taskLists = operations.OrderTaskLists(lists, a.config)
+}
+
 // GetTaskManager returns the task manager
 func (a *App) GetTaskManager() backend.TaskManager {
 	return a.taskManager
@@ -93,11 +135,11 @@ func (a *App) GetTaskManager() backend.TaskManager {
 
 // RefreshTaskLists refreshes the task list cache from the backend
 func (a *App) RefreshTaskLists() error {
-	lists, err := cache.RefreshAndCacheTaskLists(a.taskManager)
+	lists, err := cache.RefreshAndCacheTaskListsFor(a.selectedBackend, a.taskManager)
 	if err != nil {
 		return err
 	}
-	a.taskLists = lists
+	a.setTaskLists(lists)
 	return nil
 }
 
@@ -153,27 +195,124 @@ func (a *App) DetectBackends() error {
 
 // Run is a thin wrapper that delegates to operations
 func (a *App) Run(cmd *cobra.Command, args []string) error {
-	// Refresh task lists from remote for actual operations
-	lists, err := cache.RefreshAndCacheTaskLists(a.taskManager)
+	strict, _ := cmd.Flags().GetBool("strict")
+	if err := a.refreshTaskListsForRun(strict); err != nil {
+		return err
+	}
+
+	return operations.ExecuteAction(a.taskManagerFor(cmd, args), a.config, a.taskLists, cmd, args, a)
+}
+
+// taskManagerFor returns the task manager an action works on: the router
+// sending writes straight to the remote when it targets one of the
+// sync.direct_lists, the app's task manager otherwise. Only the actions the
+// router handles are routed, so the cache's optional interfaces, such as the
+// trash, stay available to the others.
+func (a *App) taskManagerFor(cmd *cobra.Command, args []string) backend.TaskManager {
+	if a.config == nil || a.config.Sync == nil || len(a.config.Sync.DirectLists) == 0 {
+		return a.taskManager
+	}
+	if !isDirectAction(cmd, args) || !a.targetsDirectList(cmd, args) {
+		return a.taskManager
+	}
+	cache, remote, err := a.cacheAndRemote()
 	if err != nil {
-		// Check if it's a backend error that should be surfaced to the user
-		if backendErr, ok := err.(*backend.BackendError); ok {
-			// Authentication or connection errors should stop execution
-			if backendErr.IsUnauthorized() {
-				return backendErr
-			}
-			// Other HTTP errors should also stop execution
-			if backendErr.StatusCode >= 400 {
-				return backendErr
-			}
+		return a.taskManager
+	}
+	strategy := backendsync.ConflictResolutionStrategy(a.config.Sync.ConflictResolution)
+	return backendsync.NewSyncManager(cache, remote, strategy).NewDirectRouter(cache, a.isDirectListID, a.config.Sync.DirectReads)
+}
+
+// targetsDirectList reports whether the list an action names is a direct list
+func (a *App) targetsDirectList(cmd *cobra.Command, args []string) bool {
+	listName, _ := cmd.Flags().GetString("list")
+	if listName == "" {
+		if len(args) == 0 {
+			return false
+		}
+		listName = args[0]
+	}
+	list, err := operations.FindListByNameFull(a.taskLists, listName)
+	return err == nil && a.config.IsDirectList(list.ID, list.Name)
+}
+
+// isDirectListID reports whether the list with the given ID is a direct list
+func (a *App) isDirectListID(listID string) bool {
+	for _, list := range a.taskLists {
+		if list.ID == listID {
+			return a.config.IsDirectList(list.ID, list.Name)
 		}
-		// For other errors, log warning but try to continue
-		log.Printf("Warning: Could not refresh task lists: %v", err)
-	} else {
-		a.taskLists = lists
 	}
+	return a.config.IsDirectList(listID, "")
+}
+
+// isDirectAction reports whether the action of args is one the direct-list
+// router handles
+func isDirectAction(cmd *cobra.Command, args []string) bool {
+	actionIndex := 1
+	if listName, _ := cmd.Flags().GetString("list"); listName != "" {
+		actionIndex = 0
+	}
+	action := "get"
+	if len(args) > actionIndex {
+		action = operations.NormalizeAction(args[actionIndex])
+	}
+	switch action {
+	case "get", "add", "update", "complete", "delete":
+		return true
+	}
+	return false
+}
+
+// refreshTaskListsForRun refreshes the task lists from the backend before an
+// action. When the backend is unreachable the cached list names are used
+// instead, with a warning, so known lists still resolve; strict turns that
+// into an error. Rejected requests, such as bad credentials, always fail.
+func (a *App) refreshTaskListsForRun(strict bool) error {
+	lists, err := cache.RefreshAndCacheTaskListsFor(a.selectedBackend, a.taskManager)
+	if err == nil {
+		a.setTaskLists(lists)
+		return nil
+	}
+
+	if backend.IsUnreachable(err) {
+		if strict {
+			return err
+		}
+		cached, cacheErr := cache.LoadTaskListsFromCacheFor(a.selectedBackend)
+		if cacheErr != nil {
+			return fmt.Errorf("%w (no cached lists to fall back to)", err)
+		}
+		a.setTaskLists(cached)
+		utils.Warnf("%s unreachable: %s — showing cached lists only", a.backendLabel(), backend.UnreachableReason(err))
+		return nil
+	}
+
+	// Check if it's a backend error that should be surfaced to the user
+	if backendErr, ok := err.(*backend.BackendError); ok {
+		// Authentication or connection errors should stop execution
+		if backendErr.IsUnauthorized() {
+			return backendErr
+		}
+		// Other HTTP errors should also stop execution
+		if backendErr.StatusCode >= 400 {
+			return backendErr
+		}
+	}
+	if strict {
+		return err
+	}
+	// For other errors, log warning but try to continue
+	log.Printf("Warning: Could not refresh task lists: %v", err)
+	return nil
+}
 
-	return operations.ExecuteAction(a.taskManager, a.config, a.taskLists, cmd, args, a)
+// backendLabel names the selected backend in messages
+func (a *App) backendLabel() string {
+	if a.selectedBackend != "" {
+		return a.selectedBackend
+	}
+	return "backend"
 }
 
 // initializeSyncCoordinator is currently disabled - needs redesign for multi-remote architecture
@@ -188,6 +327,122 @@ func (a *App) GetSyncCoordinator() interface{} {
 	return nil // Disabled for now
 }
 
+// cacheAndRemote returns the sync cache the app works on and the remote
+// behind it, or an error when the app works on a backend directly
+func (a *App) cacheAndRemote() (*sqlite.SQLiteBackend, backend.TaskManager, error) {
+	cache, ok := backend.As[*sqlite.SQLiteBackend](a.taskManager)
+	if !ok || a.registry == nil || a.config.Sync == nil || !a.config.Sync.Enabled {
+		return nil, nil, fmt.Errorf("not working on a sync cache")
+	}
+	remote, err := a.registry.GetBackend(cache.Config.Name)
+	if err != nil {
+		return nil, nil, err
+	}
+	return cache, remote, nil
+}
+
+// RemoteTaskLists returns the lists of the remote the cache syncs with
+func (a *App) RemoteTaskLists() ([]backend.TaskList, error) {
+	_, remote, err := a.cacheAndRemote()
+	if err != nil {
+		return nil, err
+	}
+	lists, err := remote.GetTaskLists()
+	return backend.NumberListPositions(lists), err
+}
+
+// AdoptList starts caching a remote list: it records the list and pulls its
+// tasks into the cache
+func (a *App) AdoptList(list backend.TaskList) error {
+	cache, remote, err := a.cacheAndRemote()
+	if err != nil {
+		return err
+	}
+	strategy := backendsync.ConflictResolutionStrategy(a.config.Sync.ConflictResolution)
+	if _, err := backendsync.NewSyncManager(cache, remote, strategy).AdoptList(list); err != nil {
+		return err
+	}
+	a.RefreshTaskListsOrWarn()
+	return nil
+}
+
+// SetConflictPrompter sets the function asked for conflicts when a list is
+// synced under the prompt and interactive strategies
+func (a *App) SetConflictPrompter(prompter backendsync.ConflictPrompter) {
+	a.conflictPrompter = prompter
+}
+
+// SyncList pulls and pushes one list with the remote behind the cache
+func (a *App) SyncList(list backend.TaskList) (*backendsync.SyncResult, error) {
+	cache, remote, err := a.cacheAndRemote()
+	if err != nil {
+		return nil, utils.ErrSyncNotEnabled()
+	}
+	strategy := backendsync.ConflictResolutionStrategy(a.config.Sync.ConflictResolution)
+	sm := backendsync.NewSyncManager(cache, remote, strategy)
+	sm.SetConflictFallback(backendsync.ConflictResolutionStrategy(a.config.Sync.ConflictFallback))
+	sm.SetMaxRetries(a.config.Sync.MaxRetries)
+	sm.SetParallelism(a.config.Sync.Parallelism)
+	sm.SetConflictPrompter(a.conflictPrompter)
+	result, err := sm.SyncList(list.ID)
+	if err != nil {
+		return nil, err
+	}
+	a.RefreshTaskListsOrWarn()
+	return result, nil
+}
+
+// archiveOwner returns the backend whose lists are archived: the remote
+// behind the cache, or the backend the app works on directly
+func (a *App) archiveOwner() backend.TaskManager {
+	if _, remote, err := a.cacheAndRemote(); err == nil {
+		return remote
+	}
+	return a.taskManager
+}
+
+// ArchivedTaskLists returns the lists the backend archived, with the task
+// manager reading them: the remote itself when working on a sync cache, as
+// archived lists are not pulled
+func (a *App) ArchivedTaskLists() ([]backend.TaskList, backend.TaskManager, error) {
+	owner := a.archiveOwner()
+	archiver, ok := backend.As[backend.ListArchiver](owner)
+	if !ok {
+		return nil, nil, nil
+	}
+	lists, err := archiver.GetArchivedTaskLists()
+	return lists, owner, err
+}
+
+// UnarchiveList makes an archived list active again. On a sync cache, its
+// cached copy is shown again, or the list is adopted when it was archived
+// before it was ever cached.
+func (a *App) UnarchiveList(list backend.TaskList) error {
+	archiver, ok := backend.As[backend.ListArchiver](a.archiveOwner())
+	if !ok {
+		return fmt.Errorf("%s cannot archive lists", a.backendLabel())
+	}
+	if err := archiver.UnarchiveTaskList(list.ID); err != nil {
+		return err
+	}
+
+	if cache, _, err := a.cacheAndRemote(); err == nil {
+		cached, err := cache.GetArchivedTaskLists()
+		if err != nil {
+			return err
+		}
+		if !slices.ContainsFunc(cached, func(c backend.TaskList) bool { return c.ID == list.ID }) {
+			list.Archived = false
+			return a.AdoptList(list)
+		}
+		if err := cache.UnarchiveTaskList(list.ID); err != nil {
+			return err
+		}
+	}
+	a.RefreshTaskListsOrWarn()
+	return nil
+}
+
 // Shutdown gracefully shuts down the application
 func (a *App) Shutdown() {
 	a.ShutdownWithTimeout(5 * time.Second)
@@ -3,11 +3,16 @@ package app
 import (
 	"encoding/json"
 	"gosynctasks/backend"
+	"gosynctasks/internal/cache"
 	"gosynctasks/internal/config"
+	"net"
+	"net/url"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
+
+	"github.com/spf13/cobra"
 )
 
 // mockTaskManagerForApp implements backend.TaskManager for app testing
@@ -31,6 +36,10 @@ func (m *mockTaskManagerForApp) FindTasksBySummary(listID string, summary string
 	return nil, nil
 }
 
+func (m *mockTaskManagerForApp) GetTask(listID string, taskUID string) (*backend.Task, error) {
+	return backend.ScanForTask(m, listID, taskUID)
+}
+
 func (m *mockTaskManagerForApp) AddTask(listID string, task backend.Task) (string, error) {
 	return "mock-task-id", nil
 }
@@ -362,3 +371,112 @@ func TestApp_BackendSelection(t *testing.T) {
 
 // Note: TestRun_* tests are omitted because they require complex mocking of cobra.Command.
 // The Run() method's error handling logic is better tested through integration tests.
+
+func TestRefreshTaskListsForRun_Unreachable(t *testing.T) {
+	_, cleanup := setupTestConfig(t)
+	defer cleanup()
+
+	cachedLists := []backend.TaskList{{ID: "cached", Name: "Cached List"}}
+	timeout := &url.Error{Op: "Get", URL: "https://tasks.example.com", Err: &net.DNSError{IsTimeout: true}}
+
+	tests := []struct {
+		name       string
+		err        error
+		strict     bool
+		cachedFor  string
+		wantErr    bool
+		wantCached bool
+	}{
+		{"timeout falls back to cached lists", timeout, false, "todoist", false, true},
+		{"server error falls back to cached lists", backend.NewBackendError("GetTaskLists", 503, "Service Unavailable"), false, "todoist", false, true},
+		{"strict fails fast", timeout, true, "todoist", true, false},
+		{"rejected credentials still fail", backend.NewBackendError("GetTaskLists", 401, "Unauthorized"), false, "todoist", true, false},
+		{"lists cached for another backend are not used", timeout, false, "nextcloud", true, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := cache.SaveTaskListsToCacheFor(tt.cachedFor, cachedLists); err != nil {
+				t.Fatalf("Failed to seed cache: %v", err)
+			}
+			app := &App{
+				taskManager:     &mockTaskManagerForApp{err: tt.err},
+				selectedBackend: "todoist",
+			}
+
+			err := app.refreshTaskListsForRun(tt.strict)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("refreshTaskListsForRun() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			gotCached := len(app.taskLists) == 1 && app.taskLists[0].ID == "cached"
+			if gotCached != tt.wantCached {
+				t.Errorf("task lists = %+v, want cached lists: %v", app.taskLists, tt.wantCached)
+			}
+		})
+	}
+}
+
+func TestRefreshTaskListsForRun_CachesPerBackend(t *testing.T) {
+	_, cleanup := setupTestConfig(t)
+	defer cleanup()
+
+	lists := []backend.TaskList{{ID: "remote", Name: "Remote List"}}
+	app := &App{
+		taskManager:     &mockTaskManagerForApp{lists: lists},
+		selectedBackend: "nextcloud",
+	}
+	if err := app.refreshTaskListsForRun(false); err != nil {
+		t.Fatalf("refreshTaskListsForRun() failed: %v", err)
+	}
+
+	if _, err := cache.LoadTaskListsFromCacheFor("nextcloud"); err != nil {
+		t.Errorf("Expected lists cached for nextcloud: %v", err)
+	}
+	if _, err := cache.LoadTaskListsFromCacheFor("todoist"); err == nil {
+		t.Error("Expected nextcloud's lists not to be served for todoist")
+	}
+}
+
+func TestDirectListRouting(t *testing.T) {
+	app := &App{
+		config: &config.Config{Sync: &config.SyncConfig{Enabled: true, DirectLists: []string{"groceries", "list-2"}}},
+		taskLists: []backend.TaskList{
+			{ID: "list-1", Name: "Groceries"},
+			{ID: "list-2", Name: "Errands"},
+			{ID: "list-3", Name: "Work"},
+		},
+	}
+
+	tests := []struct {
+		name       string
+		listFlag   string
+		args       []string
+		wantDirect bool
+	}{
+		{"list named in any case", "", []string{"Groceries", "add", "Milk"}, true},
+		{"list given by ID", "", []string{"Errands", "complete", "Post"}, true},
+		{"list flag", "Groceries", []string{"update", "Milk"}, true},
+		{"reads are routed too", "", []string{"Groceries"}, true},
+		{"other list", "", []string{"Work", "add", "Report"}, false},
+		{"unknown list", "", []string{"Nowhere", "add", "Report"}, false},
+		{"action the router does not handle", "", []string{"Groceries", "trash"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := &cobra.Command{}
+			cmd.Flags().String("list", "", "")
+			if tt.listFlag != "" {
+				cmd.Flags().Set("list", tt.listFlag)
+			}
+			got := isDirectAction(cmd, tt.args) && app.targetsDirectList(cmd, tt.args)
+			if got != tt.wantDirect {
+				t.Errorf("routed direct = %v, want %v", got, tt.wantDirect)
+			}
+		})
+	}
+
+	if !app.isDirectListID("list-1") || app.isDirectListID("list-3") {
+		t.Error("isDirectListID does not match the configured lists")
+	}
+}
@@ -0,0 +1,122 @@
+// Package autostart moves tasks from TODO to PROCESSING once their start date
+// has passed, for lists with auto_start enabled. Each task is started at most
+// once for a given start date, so a task moved back to TODO by hand is left
+// alone until it is rescheduled.
+package autostart
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"gosynctasks/backend"
+)
+
+// Marker remembers the tasks that were started, by UID, with the start date
+// each one was started for
+type Marker struct {
+	path    string
+	Started map[string]time.Time `json:"started"`
+}
+
+// DefaultPath returns the marker file in the XDG state directory
+func DefaultPath() (string, error) {
+	stateDir := os.Getenv("XDG_STATE_HOME")
+	if stateDir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		stateDir = filepath.Join(home, ".local", "state")
+	}
+	return filepath.Join(stateDir, "gosynctasks", "autostart.json"), nil
+}
+
+// Load reads the marker file at path; a missing file is an empty marker
+func Load(path string) (*Marker, error) {
+	m := &Marker{path: path, Started: make(map[string]time.Time)}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return m, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read auto-start state: %w", err)
+	}
+	if err := json.Unmarshal(data, m); err != nil {
+		return nil, fmt.Errorf("failed to parse auto-start state %s: %w", path, err)
+	}
+	if m.Started == nil {
+		m.Started = make(map[string]time.Time)
+	}
+	return m, nil
+}
+
+// Save writes the marker file, replacing it atomically
+func (m *Marker) Save() error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(m.path), 0755); err != nil {
+		return fmt.Errorf("failed to create state directory: %w", err)
+	}
+	tmp := m.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("failed to write auto-start state: %w", err)
+	}
+	return os.Rename(tmp, m.path)
+}
+
+// wasStarted reports whether task was already started for its current start date
+func (m *Marker) wasStarted(task backend.Task) bool {
+	started, ok := m.Started[task.UID]
+	return ok && started.Equal(*task.StartDate)
+}
+
+// Due returns the tasks with todoStatus whose start date is at or before now
+// and that were not started for that start date yet
+func (m *Marker) Due(tasks []backend.Task, todoStatus string, now time.Time) []backend.Task {
+	var due []backend.Task
+	for _, task := range tasks {
+		if task.Status != todoStatus || task.StartDate == nil || task.StartDate.After(now) {
+			continue
+		}
+		if m.wasStarted(task) {
+			continue
+		}
+		due = append(due, task)
+	}
+	return due
+}
+
+// Statuses returns the backend's TODO and PROCESSING statuses
+func Statuses(tm backend.TaskManager) (todo, processing string, err error) {
+	if todo, err = tm.ParseStatusFlag("TODO"); err != nil {
+		return "", "", err
+	}
+	if processing, err = tm.ParseStatusFlag("PROCESSING"); err != nil {
+		return "", "", err
+	}
+	return todo, processing, nil
+}
+
+// Start updates the due tasks of a list to PROCESSING through tm and marks
+// them. It returns how many were started; on failure the remaining tasks are
+// left for the next run. The marker is not saved.
+func (m *Marker) Start(tm backend.TaskManager, listID string, due []backend.Task) (int, error) {
+	_, processing, err := Statuses(tm)
+	if err != nil {
+		return 0, err
+	}
+
+	for i, task := range due {
+		task.Status = processing
+		if err := tm.UpdateTask(listID, task); err != nil {
+			return i, fmt.Errorf("failed to start task '%s': %w", task.Summary, err)
+		}
+		m.Started[task.UID] = *task.StartDate
+	}
+	return len(due), nil
+}
@@ -0,0 +1,81 @@
+package autostart
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"gosynctasks/backend"
+)
+
+func newStartBackend(start time.Time) *backend.MockBackend {
+	mb := backend.NewMockBackend()
+	mb.Tasks["work"] = []backend.Task{
+		{UID: "due", Summary: "Due", Status: "NEEDS-ACTION", StartDate: &start},
+		{UID: "done", Summary: "Done", Status: "COMPLETED", StartDate: &start},
+		{UID: "unscheduled", Summary: "Unscheduled", Status: "NEEDS-ACTION"},
+	}
+	return mb
+}
+
+func statusOf(mb *backend.MockBackend, uid string) string {
+	for _, task := range mb.Tasks["work"] {
+		if task.UID == uid {
+			return task.Status
+		}
+	}
+	return ""
+}
+
+// TestStartOnce tests that a task is started once per start date, so moving
+// it back to TODO sticks until it is rescheduled, and that DONE tasks are
+// left alone
+func TestStartOnce(t *testing.T) {
+	now := time.Now()
+	mb := newStartBackend(now.Add(-time.Hour))
+	path := filepath.Join(t.TempDir(), "autostart.json")
+
+	marker, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	started, err := marker.Start(mb, "work", marker.Due(mb.Tasks["work"], "NEEDS-ACTION", now))
+	if err != nil || started != 1 {
+		t.Fatalf("Start() = %d, %v, want 1 started", started, err)
+	}
+	if err := marker.Save(); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	if got := statusOf(mb, "due"); got != "IN-PROCESS" {
+		t.Errorf("due task status = %q, want IN-PROCESS", got)
+	}
+	if got := statusOf(mb, "done"); got != "COMPLETED" {
+		t.Errorf("done task status = %q, want COMPLETED", got)
+	}
+
+	// Moved back to TODO by hand: a later run leaves it
+	mb.Tasks["work"][0].Status = "NEEDS-ACTION"
+	reloaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if due := reloaded.Due(mb.Tasks["work"], "NEEDS-ACTION", now); len(due) != 0 {
+		t.Errorf("Due() after manual revert = %v, want none", due)
+	}
+
+	// Rescheduled: started again once the new start date passes
+	rescheduled := now.Add(-time.Minute)
+	mb.Tasks["work"][0].StartDate = &rescheduled
+	if due := reloaded.Due(mb.Tasks["work"], "NEEDS-ACTION", now); len(due) != 1 {
+		t.Errorf("Due() after rescheduling = %v, want the task", due)
+	}
+}
+
+func TestDueSkipsFutureStart(t *testing.T) {
+	now := time.Now()
+	mb := newStartBackend(now.Add(time.Hour))
+	marker, _ := Load(filepath.Join(t.TempDir(), "autostart.json"))
+	if due := marker.Due(mb.Tasks["work"], "NEEDS-ACTION", now); len(due) != 0 {
+		t.Errorf("Due() = %v, want none before the start date", due)
+	}
+}
@@ -2,6 +2,7 @@ package cache
 
 import (
 	"encoding/json"
+	"fmt"
 	"gosynctasks/backend"
 	"os"
 	"path/filepath"
@@ -12,6 +13,7 @@ import (
 type CachedData struct {
 	Lists     []backend.TaskList `json:"lists"`
 	Timestamp int64              `json:"timestamp"`
+	Backend   string             `json:"backend,omitempty"` // Backend the lists came from, empty if unknown
 }
 
 // GetCacheDir returns the XDG-compliant cache directory path
@@ -39,6 +41,27 @@ func GetCacheFile() (string, error) {
 
 // LoadTaskListsFromCache loads task lists from the cache file
 func LoadTaskListsFromCache() ([]backend.TaskList, error) {
+	cached, err := loadCachedData()
+	if err != nil {
+		return nil, err
+	}
+	return cached.Lists, nil
+}
+
+// LoadTaskListsFromCacheFor loads the cached task lists if they belong to the
+// named backend. Caches written before the backend was recorded are accepted.
+func LoadTaskListsFromCacheFor(backendName string) ([]backend.TaskList, error) {
+	cached, err := loadCachedData()
+	if err != nil {
+		return nil, err
+	}
+	if cached.Backend != "" && cached.Backend != backendName {
+		return nil, fmt.Errorf("cached lists belong to backend '%s'", cached.Backend)
+	}
+	return cached.Lists, nil
+}
+
+func loadCachedData() (*CachedData, error) {
 	cacheFile, err := GetCacheFile()
 	if err != nil {
 		return nil, err
@@ -54,11 +77,16 @@ func LoadTaskListsFromCache() ([]backend.TaskList, error) {
 		return nil, err
 	}
 
-	return cached.Lists, nil
+	return &cached, nil
 }
 
 // SaveTaskListsToCache saves task lists to the cache file with timestamp
 func SaveTaskListsToCache(lists []backend.TaskList) error {
+	return SaveTaskListsToCacheFor("", lists)
+}
+
+// SaveTaskListsToCacheFor saves the task lists of the named backend to the cache file
+func SaveTaskListsToCacheFor(backendName string, lists []backend.TaskList) error {
 	cacheFile, err := GetCacheFile()
 	if err != nil {
 		return err
@@ -67,6 +95,7 @@ func SaveTaskListsToCache(lists []backend.TaskList) error {
 	cached := CachedData{
 		Lists:     lists,
 		Timestamp: time.Now().Unix(),
+		Backend:   backendName,
 	}
 
 	data, err := json.MarshalIndent(cached, "", "  ")
@@ -79,8 +108,14 @@ func SaveTaskListsToCache(lists []backend.TaskList) error {
 
 // LoadTaskListsWithFallback attempts to load from cache, falls back to fetching from remote
 func LoadTaskListsWithFallback(taskManager backend.TaskManager) ([]backend.TaskList, error) {
+	return LoadTaskListsWithFallbackFor("", taskManager)
+}
+
+// LoadTaskListsWithFallbackFor loads the named backend's lists from cache,
+// falling back to fetching them from the backend
+func LoadTaskListsWithFallbackFor(backendName string, taskManager backend.TaskManager) ([]backend.TaskList, error) {
 	// Try cache first
-	lists, err := LoadTaskListsFromCache()
+	lists, err := LoadTaskListsFromCacheFor(backendName)
 	if err == nil {
 		return lists, nil
 	}
@@ -92,16 +127,21 @@ func LoadTaskListsWithFallback(taskManager backend.TaskManager) ([]backend.TaskL
 	}
 
 	// Save to cache for next time
-	_ = SaveTaskListsToCache(lists)
+	_ = SaveTaskListsToCacheFor(backendName, lists)
 	return lists, nil
 }
 
 // RefreshAndCacheTaskLists force-fetches task lists from remote and updates cache
 func RefreshAndCacheTaskLists(taskManager backend.TaskManager) ([]backend.TaskList, error) {
+	return RefreshAndCacheTaskListsFor("", taskManager)
+}
+
+// RefreshAndCacheTaskListsFor force-fetches the named backend's task lists and updates the cache
+func RefreshAndCacheTaskListsFor(backendName string, taskManager backend.TaskManager) ([]backend.TaskList, error) {
 	lists, err := taskManager.GetTaskLists()
 	if err != nil {
 		return nil, err
 	}
-	_ = SaveTaskListsToCache(lists)
+	_ = SaveTaskListsToCacheFor(backendName, lists)
 	return lists, nil
 }
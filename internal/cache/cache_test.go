@@ -27,6 +27,10 @@ func (m *mockTaskManager) FindTasksBySummary(listID string, summary string) ([]b
 	return nil, nil
 }
 
+func (m *mockTaskManager) GetTask(listID string, taskUID string) (*backend.Task, error) {
+	return backend.ScanForTask(m, listID, taskUID)
+}
+
 func (m *mockTaskManager) AddTask(listID string, task backend.Task) (string, error) {
 	return "mock-task-id", nil
 }
@@ -2,46 +2,157 @@ package cli
 
 import (
 	"gosynctasks/backend"
+	"gosynctasks/internal/utils"
 	"gosynctasks/internal/views"
 	"strings"
 
 	"github.com/spf13/cobra"
 )
 
-// SmartCompletion provides shell completion for list names and actions
-func SmartCompletion(taskLists []backend.TaskList) func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+// summaryActions are the actions whose third argument is an existing task's summary
+var summaryActions = map[string]bool{
+	"update": true, "u": true,
+	"complete": true, "c": true,
+	"delete": true, "d": true,
+	"restore": true,
+}
+
+// SmartCompletion provides shell completion for list names, actions and, for
+// update/complete/delete, the summaries of the tasks in the list (of its
+// deleted tasks for restore). The file of import is left to the shell.
+//
+// Candidates are returned unescaped: the completion scripts cobra generates
+// quote the value they insert for their own shell, so escaping here would
+// quote it twice. They are sanitized so that a tab or newline in a name cannot
+// break cobra's line-based protocol, and the partial word typed so far is
+// unquoted before matching, so a prefix typed with quotes or backslashes
+// still finds its task.
+func SmartCompletion(taskLists []backend.TaskList, taskManager backend.TaskManager) func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
 	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 		var completions []string
+		prefix := strings.ToLower(UnquoteCompletionWord(toComplete))
 
-		// First argument: suggest list names
-		if len(args) == 0 {
+		switch len(args) {
+		case 0:
+			// First argument: suggest list names
 			for _, list := range taskLists {
-				if strings.HasPrefix(strings.ToLower(list.Name), strings.ToLower(toComplete)) {
-					completions = append(completions, list.Name)
+				// Escaped names still resolve: FindListByName matches the sanitized form
+				name := utils.SanitizeLine(list.QualifiedName())
+				if strings.HasPrefix(strings.ToLower(name), prefix) {
+					completions = append(completions, name)
 				}
 			}
-		}
 
-		// Second argument (after list): suggest actions (full names only)
-		if len(args) == 1 {
-			actions := []string{"get", "add", "update", "complete", "delete"}
+		case 1:
+			// Second argument (after list): suggest actions (full names only)
+			actions := []string{"get", "add", "update", "complete", "delete", "trash", "restore", "sync", "export", "import"}
 			for _, action := range actions {
-				if strings.HasPrefix(action, strings.ToLower(toComplete)) {
+				if strings.HasPrefix(action, prefix) {
 					completions = append(completions, action)
 				}
 			}
-		}
 
-		// Third argument (after "<list> <action>"): no completion, user enters task summary
-		// Return directive to stop completion
-		if len(args) >= 2 {
-			return nil, cobra.ShellCompDirectiveNoFileComp
+		case 2:
+			// Third argument: summaries of existing tasks, for actions that look one up
+			action := strings.ToLower(args[1])
+			if action == "import" {
+				return nil, cobra.ShellCompDirectiveDefault // The file to import
+			}
+			if !summaryActions[action] || taskManager == nil {
+				break
+			}
+			list := findCompletionList(taskLists, args[0])
+			if list == nil {
+				break
+			}
+			tasks, err := completionTasks(taskManager, list.ID, action)
+			if err != nil {
+				break
+			}
+			seen := make(map[string]bool)
+			for _, task := range tasks {
+				if (action == "complete" || action == "c") && backend.IsCompletedStatus(task.Status) {
+					continue
+				}
+				summary := utils.SanitizeLine(task.Summary)
+				if !seen[summary] && strings.HasPrefix(strings.ToLower(summary), prefix) {
+					seen[summary] = true
+					completions = append(completions, summary)
+				}
+			}
 		}
 
 		return completions, cobra.ShellCompDirectiveNoFileComp
 	}
 }
 
+// completionTasks returns the tasks whose summaries complete the action's
+// third argument: the deleted ones for restore, the list's tasks otherwise
+func completionTasks(taskManager backend.TaskManager, listID, action string) ([]backend.Task, error) {
+	if action != "restore" {
+		return taskManager.GetTasks(listID, nil)
+	}
+	trash, ok := backend.As[backend.TaskTrash](taskManager)
+	if !ok {
+		return nil, nil
+	}
+	deleted, err := trash.GetDeletedTasks(listID)
+	if err != nil {
+		return nil, err
+	}
+	tasks := make([]backend.Task, len(deleted))
+	for i := range deleted {
+		tasks[i] = deleted[i].Task
+	}
+	return tasks, nil
+}
+
+// findCompletionList returns the list an already typed list argument names
+func findCompletionList(taskLists []backend.TaskList, name string) *backend.TaskList {
+	for i := range taskLists {
+		qualified := utils.SanitizeLine(taskLists[i].QualifiedName())
+		if strings.EqualFold(qualified, name) || strings.EqualFold(taskLists[i].Name, name) {
+			return &taskLists[i]
+		}
+	}
+	return nil
+}
+
+// UnquoteCompletionWord removes POSIX shell quoting from the word being
+// completed, as far as it has been typed: single and double quotes (which may
+// still be open) and backslash escapes. Shells pass this word as typed, while
+// candidates are plain values.
+func UnquoteCompletionWord(word string) string {
+	var sb strings.Builder
+	var quote rune
+	escaped := false
+
+	for _, r := range word {
+		switch {
+		case escaped:
+			// Inside double quotes a backslash only escapes $ ` " \
+			if quote == '"' && !strings.ContainsRune("$`\"\\", r) {
+				sb.WriteRune('\\')
+			}
+			sb.WriteRune(r)
+			escaped = false
+		case r == '\\' && quote != '\'':
+			escaped = true
+		case quote == 0 && (r == '\'' || r == '"'):
+			quote = r
+		case r == quote:
+			quote = 0
+		default:
+			sb.WriteRune(r)
+		}
+	}
+	if escaped {
+		sb.WriteRune('\\')
+	}
+
+	return sb.String()
+}
+
 // ListViewNames returns all available view names for shell completion
 func ListViewNames() ([]string, error) {
 	return views.ListViews()
@@ -0,0 +1,79 @@
+package cli
+
+import (
+	"gosynctasks/backend"
+	"reflect"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func TestUnquoteCompletionWord(t *testing.T) {
+	tests := []struct {
+		word string
+		want string
+	}{
+		{`plain`, `plain`},
+		{`Fix\ the\ \"fo`, `Fix the "fo`},
+		{`"Fix the \"fo`, `Fix the "fo`},
+		{`'Don'\''t pa`, `Don't pa`},
+		{`"Don't`, `Don't`},
+		{`'Pay $1`, `Pay $1`},
+		{`"Pay \$1`, `Pay $1`},
+		{"Run\\ \\`ma", "Run `ma"},
+		{"\"Run \\`ma", "Run `ma"},
+		{`"C:\path`, `C:\path`},
+		{`Read\ “Du`, `Read “Du`},
+		{`trailing\`, `trailing\`},
+	}
+
+	for _, tt := range tests {
+		if got := UnquoteCompletionWord(tt.word); got != tt.want {
+			t.Errorf("UnquoteCompletionWord(%q) = %q, want %q", tt.word, got, tt.want)
+		}
+	}
+}
+
+func TestSmartCompletionSummaries(t *testing.T) {
+	mb := backend.NewMockBackend()
+	lists := []backend.TaskList{{ID: "list-1", Name: "Chores"}}
+	mb.Tasks["list-1"] = []backend.Task{
+		{UID: "1", Summary: `Fix the "foo" bug`, Status: "NEEDS-ACTION"},
+		{UID: "2", Summary: "Don't panic", Status: "NEEDS-ACTION"},
+		{UID: "3", Summary: "Pay $100 rent", Status: "NEEDS-ACTION"},
+		{UID: "4", Summary: "Run `make test`", Status: "NEEDS-ACTION"},
+		{UID: "5", Summary: "Read “Dune”", Status: "NEEDS-ACTION"},
+		{UID: "6", Summary: "Pay $50 fine", Status: "COMPLETED"},
+		{UID: "7", Summary: "Tab\there", Status: "NEEDS-ACTION"},
+	}
+	complete := SmartCompletion(lists, mb)
+
+	tests := []struct {
+		name       string
+		args       []string
+		toComplete string
+		want       []string
+	}{
+		{"double quotes typed with backslashes", []string{"Chores", "complete"}, `Fix\ the\ \"`, []string{`Fix the "foo" bug`}},
+		{"single quote inside double quotes", []string{"Chores", "update"}, `"Don't`, []string{"Don't panic"}},
+		{"dollar sign", []string{"Chores", "delete"}, `'Pay $`, []string{"Pay $100 rent", "Pay $50 fine"}},
+		{"completed tasks skipped for complete", []string{"Chores", "c"}, `'Pay $`, []string{"Pay $100 rent"}},
+		{"backtick", []string{"Chores", "update"}, "Run\\ \\`", []string{"Run `make test`"}},
+		{"unicode quotes", []string{"chores", "update"}, "Read “", []string{"Read “Dune”"}},
+		{"tabs cannot split the candidate", []string{"Chores", "update"}, "Tab", []string{"Tab here"}},
+		{"no summaries for add", []string{"Chores", "add"}, "", nil},
+		{"unknown list", []string{"Errands", "update"}, "", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, directive := complete(&cobra.Command{}, tt.args, tt.toComplete)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("completions = %q, want %q", got, tt.want)
+			}
+			if directive != cobra.ShellCompDirectiveNoFileComp {
+				t.Errorf("directive = %v, want NoFileComp", directive)
+			}
+		})
+	}
+}
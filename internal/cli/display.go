@@ -3,14 +3,27 @@ package cli
 import (
 	"fmt"
 	"gosynctasks/backend"
+	"gosynctasks/internal/utils"
 	"os"
 	"strings"
 
 	"golang.org/x/term"
 )
 
+// terminalWidthOverride, when set, is returned by GetTerminalWidth
+var terminalWidthOverride int
+
+// SetTerminalWidth fixes the width GetTerminalWidth returns, e.g. to the width
+// a recorded session ran with; 0 restores detection
+func SetTerminalWidth(width int) {
+	terminalWidthOverride = width
+}
+
 // GetTerminalWidth returns the current terminal width, defaulting to 80 if unable to detect
 func GetTerminalWidth() int {
+	if terminalWidthOverride > 0 {
+		return terminalWidthOverride
+	}
 	width, _, err := term.GetSize(int(os.Stdout.Fd()))
 	if err != nil {
 		// Default to 80 if we can't detect terminal size
@@ -55,7 +68,7 @@ func ShowTaskLists(taskLists []backend.TaskList, taskManager backend.TaskManager
 		countColor := "\033[90m"  // Gray
 		reset := "\033[0m"
 
-		fmt.Printf("  %s%2d.%s %s%-30s%s", numColor, i+1, reset, nameColor, list.Name, reset)
+		fmt.Printf("  %s%2d.%s %s%-30s%s", numColor, i+1, reset, nameColor, utils.SanitizeLine(list.QualifiedName()), reset)
 
 		// Show task count
 		if taskCount > 0 {
@@ -68,7 +81,7 @@ func ShowTaskLists(taskLists []backend.TaskList, taskManager backend.TaskManager
 
 		// Show description if available
 		if list.Description != "" {
-			fmt.Printf("\n      %s%s%s", countColor, list.Description, reset)
+			fmt.Printf("\n      %s%s%s", countColor, utils.SanitizeLine(list.Description), reset)
 		}
 		fmt.Println()
 	}
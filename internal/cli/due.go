@@ -0,0 +1,64 @@
+package cli
+
+import (
+	"fmt"
+	"gosynctasks/internal/utils"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DueFormats lists what ParseDue accepts, for its errors and the flag help
+const DueFormats = "2026-12-31, 2026-12-31T15:00, today, tomorrow, +3d, +2w, friday, next monday, 14 Jul, or none to clear"
+
+// ParseDue parses the value of --due relative to now: an absolute date, with
+// an optional time (2026-12-31, 2026-12-31T15:00 or "2026-12-31 15:00"), an
+// offset in days or weeks (+3d, +2w), "next" and a weekday, or anything
+// ParseLocalizedDate accepts (tomorrow, friday, 14 Jul). "none" and the empty
+// string return nil, to clear the due date.
+func ParseDue(value string, now time.Time) (*time.Time, error) {
+	value = strings.TrimSpace(value)
+	lower := strings.ToLower(value)
+	if lower == "" || lower == "none" {
+		return nil, nil
+	}
+
+	for _, layout := range []string{"2006-01-02T15:04", "2006-01-02 15:04", "2006-01-02T15:04:05"} {
+		if parsed, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return &parsed, nil
+		}
+	}
+
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+	if strings.HasPrefix(lower, "+") && len(lower) > 2 {
+		n, err := strconv.Atoi(lower[1 : len(lower)-1])
+		if err == nil && n >= 0 {
+			switch lower[len(lower)-1] {
+			case 'd':
+				date := today.AddDate(0, 0, n)
+				return &date, nil
+			case 'w':
+				date := today.AddDate(0, 0, 7*n)
+				return &date, nil
+			}
+		}
+		return nil, errInvalidDue(value)
+	}
+
+	// A weekday alone is already the next one, 1-7 days ahead
+	if rest, ok := strings.CutPrefix(lower, "next "); ok {
+		lower = strings.TrimSpace(rest)
+	}
+	date, err := utils.ParseLocalizedDate(lower, utils.ActiveLocale(), now)
+	if err != nil {
+		return nil, errInvalidDue(value)
+	}
+	return date, nil
+}
+
+func errInvalidDue(value string) error {
+	return utils.WrapWithSuggestion(
+		fmt.Errorf("invalid due date: %s", value),
+		"Use one of: "+DueFormats,
+	)
+}
@@ -0,0 +1,53 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseDue(t *testing.T) {
+	now := time.Date(2026, 3, 4, 10, 30, 0, 0, time.Local) // A Wednesday
+
+	tests := []struct {
+		input string
+		want  string // Formatted as 2006-01-02 15:04, empty for nil
+	}{
+		{"2026-12-31", "2026-12-31 00:00"},
+		{"2026-12-31T15:00", "2026-12-31 15:00"},
+		{"2026-12-31 15:00", "2026-12-31 15:00"},
+		{"today", "2026-03-04 00:00"},
+		{"tomorrow", "2026-03-05 00:00"},
+		{"+3d", "2026-03-07 00:00"},
+		{"+0d", "2026-03-04 00:00"},
+		{"+2w", "2026-03-18 00:00"},
+		{"next monday", "2026-03-09 00:00"},
+		{"Next Wednesday", "2026-03-11 00:00"},
+		{"friday", "2026-03-06 00:00"},
+		{"none", ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		got, err := ParseDue(tt.input, now)
+		if err != nil {
+			t.Errorf("ParseDue(%q) error = %v", tt.input, err)
+			continue
+		}
+		formatted := ""
+		if got != nil {
+			formatted = got.Format("2006-01-02 15:04")
+		}
+		if formatted != tt.want {
+			t.Errorf("ParseDue(%q) = %q, want %q", tt.input, formatted, tt.want)
+		}
+	}
+
+	for _, input := range []string{"soon", "+3x", "+d", "next blursday", "2026-13-01", "2026-12-31T25:00"} {
+		_, err := ParseDue(input, now)
+		if err == nil {
+			t.Errorf("ParseDue(%q) error = nil, want one", input)
+		} else if !strings.Contains(err.Error(), "next monday") {
+			t.Errorf("ParseDue(%q) error = %q, want the accepted formats listed", input, err)
+		}
+	}
+}
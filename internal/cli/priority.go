@@ -0,0 +1,43 @@
+package cli
+
+import (
+	"fmt"
+	"gosynctasks/internal/utils"
+	"strconv"
+	"strings"
+)
+
+// PriorityFormats lists what ParsePriorityRange accepts, for its errors and the flag help
+const PriorityFormats = "3, 1-4, high (1-4), medium (5), low (6-9), or none (no priority)"
+
+// priorityNames are the named ranges of --priority, as the iCalendar
+// PRIORITY property groups its values
+var priorityNames = map[string][2]int{
+	"high":   {1, 4},
+	"medium": {5, 5},
+	"low":    {6, 9},
+	"none":   {0, 0},
+}
+
+// ParsePriorityRange parses the value of --priority as a filter: a priority
+// (3), a range (1-4), or a name from priorityNames. The bounds are inclusive.
+func ParsePriorityRange(value string) (minimum, maximum int, err error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if bounds, ok := priorityNames[value]; ok {
+		return bounds[0], bounds[1], nil
+	}
+
+	low, high, isRange := strings.Cut(value, "-")
+	if !isRange {
+		high = low
+	}
+	minimum, errLow := strconv.Atoi(strings.TrimSpace(low))
+	maximum, errHigh := strconv.Atoi(strings.TrimSpace(high))
+	if errLow != nil || errHigh != nil || minimum < 0 || maximum > 9 || minimum > maximum {
+		return 0, 0, utils.WrapWithSuggestion(
+			fmt.Errorf("invalid priority filter: %s", value),
+			"Use one of: "+PriorityFormats,
+		)
+	}
+	return minimum, maximum, nil
+}
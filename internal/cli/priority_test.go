@@ -0,0 +1,28 @@
+package cli
+
+import "testing"
+
+func TestParsePriorityRange(t *testing.T) {
+	tests := map[string][2]int{
+		"3":      {3, 3},
+		"1-4":    {1, 4},
+		" 6 - 9": {6, 9},
+		"0-9":    {0, 9},
+		"high":   {1, 4},
+		"Medium": {5, 5},
+		"low":    {6, 9},
+		"none":   {0, 0},
+	}
+	for input, want := range tests {
+		minimum, maximum, err := ParsePriorityRange(input)
+		if err != nil || minimum != want[0] || maximum != want[1] {
+			t.Errorf("ParsePriorityRange(%q) = %d, %d, %v, want %d, %d", input, minimum, maximum, err, want[0], want[1])
+		}
+	}
+
+	for _, input := range []string{"", "urgent", "10", "-1", "4-1", "1-", "1-10"} {
+		if _, _, err := ParsePriorityRange(input); err == nil {
+			t.Errorf("ParsePriorityRange(%q) error = nil, want one", input)
+		}
+	}
+}
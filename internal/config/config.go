@@ -2,6 +2,7 @@ package config
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 
 	// "gosynctasks/backend"
@@ -10,9 +11,11 @@ import (
 	"gosynctasks/internal/utils"
 	"gosynctasks/internal/views"
 	"log"
+	"net/url"
 	"os"
 	"path/filepath"
 	"sync"
+	"time"
 
 	"github.com/go-playground/validator/v10"
 	"gopkg.in/yaml.v3"
@@ -94,8 +97,102 @@ type Config struct {
 
 	// Common settings
 	UI         string      `yaml:"ui" validate:"oneof=cli tui"`
-	DateFormat string      `yaml:"date_format,omitempty"` // Go time format string, defaults to "2006-01-02"
+	DateFormat string      `yaml:"date_format,omitempty"` // Go time format string or preset (iso, eu, us), defaults to "2006-01-02"
+	Locale     string      `yaml:"locale,omitempty"`      // Language for date words and names (en, de), defaults to LC_TIME
 	Sync       *SyncConfig `yaml:"sync,omitempty"`        // Sync configuration
+
+	// List ordering in the picker, completion and 'list'
+	PinnedLists []string `yaml:"pinned_lists,omitempty"`                                            // List IDs or names shown first, in this order
+	ListSort    string   `yaml:"list_sort,omitempty" validate:"omitempty,oneof=name recent server"` // Order of the other lists; the backend's order when empty
+
+	AllListsName string `yaml:"all_lists_name,omitempty"` // Pseudo-list standing for every list at once, defaults to "all"
+
+	// Task behavior
+	AutoCompleteParent bool     `yaml:"auto_complete_parent,omitempty"` // Complete parent without prompting when all subtasks are done
+	AutoStart          bool     `yaml:"auto_start,omitempty"`           // Move TODO tasks to PROCESSING once their start date passes
+	AutoStartLists     []string `yaml:"auto_start_lists,omitempty"`     // Lists auto_start applies to when it is off for all lists
+	ListingMaxAge      int      `yaml:"listing_max_age,omitempty"`      // Minutes tasks can be named by %N position after a listing (default: 10)
+
+	// Where complete --reason notes go: the task description (default) or a local file
+	ReasonStorage string `yaml:"reason_storage,omitempty" validate:"omitempty,oneof=description local"`
+
+	// A start date after the due date is an error (default) or only a warning
+	StartAfterDue string `yaml:"start_after_due,omitempty" validate:"omitempty,oneof=error warn"`
+
+	// Named output templates, used with --format-template @name
+	Templates map[string]string `yaml:"templates,omitempty"`
+
+	// Saved invocations: a first argument naming one is replaced by its command line
+	Shortcuts map[string]string `yaml:"shortcuts,omitempty"`
+
+	Accessibility *AccessibilityConfig `yaml:"accessibility,omitempty"`
+
+	Capture *CaptureConfig `yaml:"capture,omitempty"`
+
+	Cache *CacheConfig `yaml:"cache,omitempty"`
+
+	DisableUpdateCheck bool `yaml:"disable_update_check,omitempty"` // Refuse 'version --check-update' lookups
+}
+
+// AutoStartsList reports whether tasks of the list, by name or ID, are moved
+// to PROCESSING once their start date passes
+func (c *Config) AutoStartsList(list backend.TaskList) bool {
+	if c.AutoStart {
+		return true
+	}
+	for _, name := range c.AutoStartLists {
+		if strings.EqualFold(name, list.Name) || name == list.ID {
+			return true
+		}
+	}
+	return false
+}
+
+// ReasonStorageLocal keeps complete --reason notes in a local file instead of
+// the task description, out of shared calendars
+const ReasonStorageLocal = "local"
+
+// DefaultListingMaxAge is how long a listing's %N positions stay valid when not configured
+const DefaultListingMaxAge = 10 * time.Minute
+
+// GetListingMaxAge returns how long after a listing its tasks can be named by position
+func (c *Config) GetListingMaxAge() time.Duration {
+	if c.ListingMaxAge > 0 {
+		return time.Duration(c.ListingMaxAge) * time.Minute
+	}
+	return DefaultListingMaxAge
+}
+
+// DefaultAllListsName is the pseudo-list standing for every list when none is configured
+const DefaultAllListsName = "all"
+
+// GetAllListsName returns the name of the pseudo-list standing for every list at once
+func (c *Config) GetAllListsName() string {
+	if c.AllListsName != "" {
+		return c.AllListsName
+	}
+	return DefaultAllListsName
+}
+
+// CaptureConfig holds settings for the 'in' quick capture command
+type CaptureConfig struct {
+	List string `yaml:"list"` // List that captured tasks go to, defaults to "Inbox"
+}
+
+// DefaultCaptureList is the capture list used when none is configured
+const DefaultCaptureList = "Inbox"
+
+// GetCaptureList returns the list name for quick capture
+func (c *Config) GetCaptureList() string {
+	if c.Capture != nil && c.Capture.List != "" {
+		return c.Capture.List
+	}
+	return DefaultCaptureList
+}
+
+// AccessibilityConfig holds display settings for users who can't rely on color
+type AccessibilityConfig struct {
+	NoColorSemantics bool `yaml:"no_color_semantics"` // Show urgency and priority as text instead of color
 }
 
 // SyncConfig represents global sync settings that apply to ALL remote backends.
@@ -103,12 +200,113 @@ type Config struct {
 // automatic cache database (e.g., ~/.local/share/gosynctasks/caches/nextcloud.db).
 // Remote backends can opt-out by setting sync: false in their backend config.
 type SyncConfig struct {
-	Enabled            bool   `yaml:"enabled"`                       // Enable automatic caching for all remote backends
-	LocalBackend       string `yaml:"local_backend,omitempty"`       // Type of cache backend: "sqlite" (default), "file", "git"
-	ConflictResolution string `yaml:"conflict_resolution,omitempty"` // Conflict strategy: server_wins (default), local_wins, merge, keep_both
-	AutoSync           bool   `yaml:"auto_sync,omitempty"`           // Auto-sync after write operations
-	SyncInterval       int    `yaml:"sync_interval,omitempty"`       // Minutes between syncs (default: 5, 0=manual only)
-	OfflineMode        string `yaml:"offline_mode,omitempty"`        // Offline mode: auto (default), online, offline
+	Enabled            bool     `yaml:"enabled"`                         // Enable automatic caching for all remote backends
+	LocalBackend       string   `yaml:"local_backend,omitempty"`         // Type of cache backend: "sqlite" (default), "file", "git"
+	ConflictResolution string   `yaml:"conflict_resolution,omitempty"`   // Conflict strategy: server_wins (default), local_wins, merge, keep_both, prompt (or interactive)
+	ConflictFallback   string   `yaml:"conflict_fallback,omitempty"`     // Strategy of prompt conflict resolution without a terminal (default: keep_both)
+	AutoSync           bool     `yaml:"auto_sync,omitempty"`             // Auto-sync after write operations
+	AfterWrite         string   `yaml:"after_write,omitempty"`           // What auto-sync runs after a write: push_list (default), push_all, full, off
+	SyncInterval       int      `yaml:"sync_interval,omitempty"`         // Minutes between syncs (default: 5, 0=manual only)
+	OfflineMode        string   `yaml:"offline_mode,omitempty"`          // Offline mode: auto (default), online, offline
+	BackupCount        int      `yaml:"backup_count,omitempty"`          // Cache backups kept before destructive operations (default: 5)
+	BackupRetention    int      `yaml:"backup_retention_days,omitempty"` // Days before 'db maintenance' prunes backups (default: 30)
+	AutoAdoptLists     bool     `yaml:"auto_adopt_lists,omitempty"`      // Start caching a remote list the cache lacks when it is addressed, without asking
+	DirectLists        []string `yaml:"direct_lists,omitempty"`          // Lists (names or IDs) whose writes go straight to the remote, skipping the queue
+	DirectReads        bool     `yaml:"direct_reads,omitempty"`          // Also read the direct lists from the remote instead of the cache
+	SlowQueryMS        int      `yaml:"slow_query_ms,omitempty"`         // Log cache queries slower than this many milliseconds (default: 0, off)
+	MaxRetries         int      `yaml:"max_retries,omitempty"`           // Failed pushes of a queued change before it is no longer retried (default: 5)
+	Parallelism        int      `yaml:"parallelism,omitempty"`           // Lists fetched and queued changes pushed at once with remotes accepting concurrent requests (default: 4)
+}
+
+// IsDirectList reports whether writes to the list, given by name or ID, skip
+// the sync queue
+func (c *Config) IsDirectList(listID, listName string) bool {
+	if c.Sync == nil {
+		return false
+	}
+	for _, direct := range c.Sync.DirectLists {
+		if direct == listID || strings.EqualFold(direct, listName) {
+			return true
+		}
+	}
+	return false
+}
+
+// What the background sync started after a write does (sync.after_write)
+const (
+	AfterWritePushList = "push_list" // Push the pending changes of the written lists
+	AfterWritePushAll  = "push_all"  // Push every pending change
+	AfterWriteFull     = "full"      // Pull and push every list
+	AfterWriteOff      = "off"       // Don't sync after writes
+)
+
+// GetAfterWrite returns what auto-sync does after a write, off when auto-sync
+// is disabled
+func (c *Config) GetAfterWrite() string {
+	if c.Sync == nil || !c.Sync.Enabled || !c.Sync.AutoSync {
+		return AfterWriteOff
+	}
+	if c.Sync.AfterWrite == "" {
+		return AfterWritePushList
+	}
+	return c.Sync.AfterWrite
+}
+
+// Defaults for cache backups
+const (
+	DefaultBackupCount         = 5
+	DefaultBackupRetentionDays = 30
+)
+
+// GetBackupCount returns how many cache backups are kept
+func (c *Config) GetBackupCount() int {
+	if c.Sync != nil && c.Sync.BackupCount > 0 {
+		return c.Sync.BackupCount
+	}
+	return DefaultBackupCount
+}
+
+// GetBackupRetention returns the age after which 'db maintenance' prunes cache backups
+func (c *Config) GetBackupRetention() time.Duration {
+	days := DefaultBackupRetentionDays
+	if c.Sync != nil && c.Sync.BackupRetention > 0 {
+		days = c.Sync.BackupRetention
+	}
+	return time.Duration(days) * 24 * time.Hour
+}
+
+// CacheConfig holds settings for what the sync cache keeps
+type CacheConfig struct {
+	CompletedRetention string `yaml:"completed_retention,omitempty"` // Age after which completed tasks leave the cache, like 180d or 26w (0: keep forever)
+	PruneAfterSync     bool   `yaml:"prune_after_sync,omitempty"`    // Also prune after each sync, not only in 'db maintenance'
+}
+
+// GetCompletedRetention returns how long completed tasks stay in the cache
+// after they were closed, 0 to keep them forever (the default)
+func (c *Config) GetCompletedRetention() (time.Duration, error) {
+	if c.Cache == nil {
+		return 0, nil
+	}
+	value := strings.TrimSpace(c.Cache.CompletedRetention)
+	if value == "" || value == "0" {
+		return 0, nil
+	}
+	units := map[byte]time.Duration{'d': 24 * time.Hour, 'w': 7 * 24 * time.Hour}
+	if unit, ok := units[value[len(value)-1]]; ok {
+		if n, err := strconv.Atoi(value[:len(value)-1]); err == nil && n >= 0 {
+			return time.Duration(n) * unit, nil
+		}
+	}
+	return 0, fmt.Errorf("cache.completed_retention must be a number of days or weeks like 180d or 26w, or 0 to keep completed tasks, got %q", value)
+}
+
+// GetSlowQueryThreshold returns the duration above which cache queries are
+// logged, 0 when the slow query log is off
+func (c *Config) GetSlowQueryThreshold() time.Duration {
+	if c.Sync == nil || c.Sync.SlowQueryMS <= 0 {
+		return 0
+	}
+	return time.Duration(c.Sync.SlowQueryMS) * time.Millisecond
 }
 
 // GetBackend returns the backend configuration for the given name
@@ -149,6 +347,39 @@ func (c *Config) GetEnabledBackends() map[string]backend.BackendConfig {
 	return enabled
 }
 
+// redactedValue replaces credentials in Redacted
+const redactedValue = "REDACTED"
+
+// Redacted returns a copy of the config without credentials (API tokens and
+// passwords in backend URLs), safe to attach to a bug report
+func (c *Config) Redacted() *Config {
+	redacted := *c
+	redacted.Backends = make(map[string]backend.BackendConfig, len(c.Backends))
+	for name, backendConfig := range c.Backends {
+		if backendConfig.APIToken != "" {
+			backendConfig.APIToken = redactedValue
+		}
+		backendConfig.URL = redactURL(backendConfig.URL)
+		redacted.Backends[name] = backendConfig
+	}
+	return &redacted
+}
+
+// redactURL replaces the password of a URL with credentials
+func redactURL(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		if strings.Contains(rawURL, "@") {
+			return redactedValue
+		}
+		return rawURL
+	}
+	if _, hasPassword := u.User.Password(); hasPassword {
+		u.User = url.UserPassword(u.User.Username(), redactedValue)
+	}
+	return u.String()
+}
+
 func (c Config) Validate() error {
 	validate := validator.New()
 	if err := validate.Struct(c); err != nil {
@@ -168,7 +399,7 @@ func (c Config) Validate() error {
 
 		// Type-specific validation
 		switch backendConfig.Type {
-		case "nextcloud", "file":
+		case "nextcloud", "caldav", "file":
 			// Accept either:
 			// - Full URL with credentials (legacy)
 			// - URL without credentials + username (keyring/env)
@@ -251,14 +482,33 @@ func (c Config) Validate() error {
 				"local_wins":  true,
 				"merge":       true,
 				"keep_both":   true,
+				"prompt":      true,
+				"interactive": true,
 			}
 			if !validStrategies[c.Sync.ConflictResolution] {
-				return fmt.Errorf("sync.conflict_resolution must be server_wins, local_wins, merge, or keep_both, got %q", c.Sync.ConflictResolution)
+				return fmt.Errorf("sync.conflict_resolution must be server_wins, local_wins, merge, keep_both, or prompt, got %q", c.Sync.ConflictResolution)
+			}
+			// interactive is another name of prompt
+			if c.Sync.ConflictResolution == "interactive" {
+				c.Sync.ConflictResolution = "prompt"
 			}
 		} else {
 			c.Sync.ConflictResolution = "server_wins" // Default
 		}
 
+		// Validate the fallback of prompt conflict resolution
+		if c.Sync.ConflictFallback != "" {
+			validFallbacks := map[string]bool{
+				"server_wins": true,
+				"local_wins":  true,
+				"merge":       true,
+				"keep_both":   true,
+			}
+			if !validFallbacks[c.Sync.ConflictFallback] {
+				return fmt.Errorf("sync.conflict_fallback must be server_wins, local_wins, merge, or keep_both, got %q", c.Sync.ConflictFallback)
+			}
+		}
+
 		// Validate offline mode
 		if c.Sync.OfflineMode != "" {
 			validModes := map[string]bool{
@@ -273,6 +523,13 @@ func (c Config) Validate() error {
 			c.Sync.OfflineMode = "auto" // Default
 		}
 
+		// Validate after-write sync
+		switch c.Sync.AfterWrite {
+		case "", AfterWritePushList, AfterWritePushAll, AfterWriteFull, AfterWriteOff:
+		default:
+			return fmt.Errorf("sync.after_write must be push_list, push_all, full, or off, got %q", c.Sync.AfterWrite)
+		}
+
 		// Validate sync interval
 		if c.Sync.SyncInterval < 0 {
 			return fmt.Errorf("sync.sync_interval cannot be negative")
@@ -282,6 +539,10 @@ func (c Config) Validate() error {
 		}
 	}
 
+	if _, err := c.GetCompletedRetention(); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -373,11 +634,28 @@ type SyncPair struct {
 	OfflineMode        string // Offline mode: auto, online, offline
 }
 
+// NoColorSemantics reports whether meaning must not be carried by color alone,
+// either from accessibility.no_color_semantics or the NO_COLOR environment variable
+func (c *Config) NoColorSemantics() bool {
+	if os.Getenv("NO_COLOR") != "" {
+		return true
+	}
+	return c.Accessibility != nil && c.Accessibility.NoColorSemantics
+}
+
 func (c *Config) GetDateFormat() string {
 	if c.DateFormat == "" {
 		return "2006-01-02" // Default to yyyy-mm-dd
 	}
-	return c.DateFormat
+	return utils.ResolveDateFormat(c.DateFormat)
+}
+
+// GetLocale returns the configured locale, or the one from the environment (LC_TIME)
+func (c *Config) GetLocale() string {
+	if c.Locale != "" {
+		return c.Locale
+	}
+	return utils.LocaleFromEnv()
 }
 
 // expandAllPaths expands ~ and $HOME in all path fields throughout the config
@@ -466,6 +744,13 @@ func GetConfig() *Config {
 // SetConfigForTest allows tests to override the global config
 // This should only be used in tests to set up test-specific configuration
 func SetConfigForTest(cfg *Config) {
+	UseConfig(cfg)
+}
+
+// UseConfig replaces the global config instead of loading it from a file,
+// e.g. with the config stored in a recorded session
+func UseConfig(cfg *Config) {
+	configOnce.Do(func() {}) // Keep GetConfig from loading (and prompting for) a user config
 	globalConfig = cfg
 }
 
@@ -553,6 +838,15 @@ func parseConfig(configData []byte, configPath string) (*Config, error) {
 		log.Fatalf("Invalid YAML in config file %s: %v", configPath, err)
 	}
 
+	// Accept the legacy single-connector format by converting it in memory
+	migrated, err := configObj.applyLegacyConnector(configData)
+	if err != nil {
+		log.Fatalf("Invalid legacy connector in config file %s: %v", configPath, err)
+	}
+	if migrated {
+		printLegacyNotice(configPath)
+	}
+
 	// Set backend names from map keys
 	for name, backendConfig := range configObj.Backends {
 		backendConfig.Name = name
@@ -0,0 +1,78 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// SetPinnedLists writes pinned_lists to the config file and to the loaded
+// config. An empty slice removes the setting.
+func SetPinnedLists(pinned []string) error {
+	if err := setConfigFileValue("pinned_lists", pinned, len(pinned) == 0); err != nil {
+		return err
+	}
+	if globalConfig != nil {
+		globalConfig.PinnedLists = pinned
+	}
+	return nil
+}
+
+// setConfigFileValue sets a top-level key of the config file, or removes it,
+// leaving the other settings and their comments in place
+func setConfigFileValue(key string, value any, remove bool) error {
+	configPath, err := GetConfigPath()
+	if err != nil {
+		return err
+	}
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return fmt.Errorf("invalid YAML in config file %s: %w", configPath, err)
+	}
+	if doc.Kind == 0 {
+		doc = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode, Tag: "!!map"}}}
+	}
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return fmt.Errorf("config file %s is not a YAML mapping", configPath)
+	}
+
+	var valueNode yaml.Node
+	if err := valueNode.Encode(value); err != nil {
+		return err
+	}
+	found := false
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		if root.Content[i].Value != key {
+			continue
+		}
+		found = true
+		if remove {
+			root.Content = append(root.Content[:i], root.Content[i+2:]...)
+		} else {
+			root.Content[i+1] = &valueNode
+		}
+		break
+	}
+	if !found && !remove {
+		root.Content = append(root.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}, &valueNode)
+	}
+
+	var out bytes.Buffer
+	encoder := yaml.NewEncoder(&out)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&doc); err != nil {
+		return err
+	}
+	if err := encoder.Close(); err != nil {
+		return err
+	}
+	return WriteConfigFile(configPath, out.Bytes())
+}
@@ -0,0 +1,42 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestSetPinnedLists(t *testing.T) {
+	path := filepath.Join(t.TempDir(), CONFIG_FILE_PATH)
+	original := "# My settings\nui: cli # Terminal only\ndate_format: iso\n"
+	if err := os.WriteFile(path, []byte(original), 0600); err != nil {
+		t.Fatal(err)
+	}
+	oldPath := customConfigPath
+	customConfigPath = path
+	t.Cleanup(func() { customConfigPath = oldPath })
+	SetConfigForTest(&Config{})
+
+	if err := SetPinnedLists([]string{"list-2", "list-1"}); err != nil {
+		t.Fatalf("SetPinnedLists() error = %v", err)
+	}
+	data, _ := os.ReadFile(path)
+	for _, want := range []string{"# My settings", "ui: cli # Terminal only", "date_format: iso", "pinned_lists:\n  - list-2\n  - list-1"} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("config file lacks %q:\n%s", want, data)
+		}
+	}
+	if !slices.Equal(GetConfig().PinnedLists, []string{"list-2", "list-1"}) {
+		t.Errorf("loaded config PinnedLists = %v", GetConfig().PinnedLists)
+	}
+
+	if err := SetPinnedLists(nil); err != nil {
+		t.Fatalf("SetPinnedLists(nil) error = %v", err)
+	}
+	data, _ = os.ReadFile(path)
+	if strings.Contains(string(data), "pinned_lists") || !strings.Contains(string(data), "ui: cli") {
+		t.Errorf("unpinning everything left:\n%s", data)
+	}
+}
@@ -0,0 +1,186 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"gosynctasks/backend"
+	"gosynctasks/internal/utils"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+// LegacyBackendName is the name given to the backend converted from a legacy connector config
+const LegacyBackendName = "default"
+
+// legacyNoticeOnce keeps the migration notice to one per run
+var legacyNoticeOnce sync.Once
+
+// legacyConnector is the single-backend "connector" section used before multi-backend configs.
+// Decoded separately from backend.ConnectorConfig so every flag is kept as written.
+type legacyConnector struct {
+	URL                 string `yaml:"url" json:"url"`
+	InsecureSkipVerify  bool   `yaml:"insecure_skip_verify" json:"insecure_skip_verify"`
+	SuppressSSLWarning  bool   `yaml:"suppress_ssl_warning" json:"suppress_ssl_warning"`
+	AllowHTTP           bool   `yaml:"allow_http" json:"allow_http"`
+	SuppressHTTPWarning bool   `yaml:"suppress_http_warning" json:"suppress_http_warning"`
+}
+
+// legacyConfig holds just enough of the old format to detect it
+type legacyConfig struct {
+	Connector *legacyConnector `yaml:"connector"`
+}
+
+// detectLegacyConnector returns the connector section if configData uses the legacy format
+func detectLegacyConnector(configData []byte) *legacyConnector {
+	var legacy legacyConfig
+	if err := yaml.Unmarshal(configData, &legacy); err != nil {
+		return nil
+	}
+	return legacy.Connector
+}
+
+// toBackendConfig converts a legacy connector into a backend entry.
+// The backend type comes from the URL scheme (nextcloud://, file://).
+func (lc *legacyConnector) toBackendConfig() (backend.BackendConfig, error) {
+	u, err := url.Parse(lc.URL)
+	if err != nil {
+		return backend.BackendConfig{}, fmt.Errorf("invalid legacy connector url: %w", err)
+	}
+
+	var backendType string
+	switch strings.ToLower(u.Scheme) {
+	case "nextcloud":
+		backendType = "nextcloud"
+	case "file":
+		backendType = "file"
+	default:
+		return backend.BackendConfig{}, fmt.Errorf("legacy connector url has unsupported scheme %q", u.Scheme)
+	}
+
+	return backend.BackendConfig{
+		Name:                LegacyBackendName,
+		Type:                backendType,
+		Enabled:             true,
+		URL:                 lc.URL,
+		InsecureSkipVerify:  lc.InsecureSkipVerify,
+		SuppressSSLWarning:  lc.SuppressSSLWarning,
+		AllowHTTP:           lc.AllowHTTP,
+		SuppressHTTPWarning: lc.SuppressHTTPWarning,
+	}, nil
+}
+
+// applyLegacyConnector converts a legacy connector section into the "default" backend
+// in memory. It does nothing when the config already defines backends.
+func (c *Config) applyLegacyConnector(configData []byte) (bool, error) {
+	if len(c.Backends) > 0 {
+		return false, nil
+	}
+
+	connector := detectLegacyConnector(configData)
+	if connector == nil {
+		return false, nil
+	}
+
+	backendConfig, err := connector.toBackendConfig()
+	if err != nil {
+		return false, err
+	}
+
+	c.Backends = map[string]backend.BackendConfig{LegacyBackendName: backendConfig}
+	if c.DefaultBackend == "" {
+		c.DefaultBackend = LegacyBackendName
+	}
+	return true, nil
+}
+
+// printLegacyNotice tells the user once per run that their config uses the old format
+func printLegacyNotice(configPath string) {
+	legacyNoticeOnce.Do(func() {
+		utils.Warnf("Config %s uses the legacy 'connector' format; it was loaded as backend %q. Run 'gosynctasks config migrate' to update the file.", configPath, LegacyBackendName)
+	})
+}
+
+// MigrateConfigFile rewrites a legacy connector config at configPath in the
+// multi-backend format. The original file is kept at <configPath>.backup.
+// Other top-level settings are preserved; JSON files stay JSON.
+func MigrateConfigFile(configPath string) (string, error) {
+	configData, err := os.ReadFile(configPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to read config: %w", err)
+	}
+
+	connector := detectLegacyConnector(configData)
+	if connector == nil {
+		return "", fmt.Errorf("config %s is already in the current format", configPath)
+	}
+
+	backendConfig, err := connector.toBackendConfig()
+	if err != nil {
+		return "", err
+	}
+
+	var raw map[string]interface{}
+	if err := yaml.Unmarshal(configData, &raw); err != nil {
+		return "", fmt.Errorf("failed to parse config: %w", err)
+	}
+
+	delete(raw, "connector")
+	if _, ok := raw["backends"]; ok {
+		return "", fmt.Errorf("config %s has both 'connector' and 'backends'; migrate it manually", configPath)
+	}
+	raw["backends"] = map[string]interface{}{
+		LegacyBackendName: legacyBackendEntry(backendConfig),
+	}
+	if _, ok := raw["default_backend"]; !ok {
+		raw["default_backend"] = LegacyBackendName
+	}
+
+	var migrated []byte
+	if strings.EqualFold(filepath.Ext(configPath), ".json") {
+		migrated, err = json.MarshalIndent(raw, "", "  ")
+		migrated = append(migrated, '\n')
+	} else {
+		migrated, err = yaml.Marshal(raw)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to encode migrated config: %w", err)
+	}
+
+	backupPath := configPath + ".backup"
+	if err := os.WriteFile(backupPath, configData, CONFIG_FILE_PERM); err != nil {
+		return "", fmt.Errorf("failed to write backup: %w", err)
+	}
+	if err := WriteConfigFile(configPath, migrated); err != nil {
+		return "", fmt.Errorf("failed to write migrated config: %w", err)
+	}
+
+	return backupPath, nil
+}
+
+// legacyBackendEntry returns the config file representation of a converted backend,
+// omitting flags that were not set
+func legacyBackendEntry(bc backend.BackendConfig) map[string]interface{} {
+	entry := map[string]interface{}{
+		"type":    bc.Type,
+		"enabled": bc.Enabled,
+		"url":     bc.URL,
+	}
+	if bc.InsecureSkipVerify {
+		entry["insecure_skip_verify"] = true
+	}
+	if bc.SuppressSSLWarning {
+		entry["suppress_ssl_warning"] = true
+	}
+	if bc.AllowHTTP {
+		entry["allow_http"] = true
+	}
+	if bc.SuppressHTTPWarning {
+		entry["suppress_http_warning"] = true
+	}
+	return entry
+}
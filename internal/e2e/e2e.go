@@ -0,0 +1,104 @@
+//go:build e2e
+
+// Package e2e runs end-to-end tests against real servers in Docker. The
+// servers are started and provisioned by scripts/run-e2e.sh (make test-e2e),
+// which passes the backend under test in GOSYNCTASKS_E2E_TYPE and
+// GOSYNCTASKS_E2E_URL; the tests only run with -tags e2e.
+package e2e
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"gosynctasks/backend"
+	_ "gosynctasks/backend/caldav" // Registers the caldav backend type
+	"gosynctasks/backend/conformance"
+	_ "gosynctasks/backend/nextcloud" // Registers the nextcloud backend type
+	"gosynctasks/backend/sqlite"
+)
+
+// capabilities are the conformance features of each backend type the
+// harness can target; types missing here run the base suite only
+var capabilities = map[string]conformance.Capabilities{
+	"caldav":    {StableListIDs: true, Hierarchy: true, Categories: true},
+	"nextcloud": {StableListIDs: true, Hierarchy: true, Categories: true},
+}
+
+// Target is the server the end-to-end tests run against
+type Target struct {
+	Type string // Backend type, such as nextcloud or caldav
+	URL  string // Backend URL with the credentials of the provisioned user
+}
+
+// TargetFromEnv returns the target scripts/run-e2e.sh set up, and skips the
+// test when the script did not run
+func TargetFromEnv(t *testing.T) Target {
+	t.Helper()
+	target := Target{Type: os.Getenv("GOSYNCTASKS_E2E_TYPE"), URL: os.Getenv("GOSYNCTASKS_E2E_URL")}
+	if target.Type == "" || target.URL == "" {
+		t.Skip("GOSYNCTASKS_E2E_TYPE and GOSYNCTASKS_E2E_URL are not set; run the e2e tests with make test-e2e")
+	}
+	return target
+}
+
+// Capabilities returns the conformance features the target supports
+func (tg Target) Capabilities() conformance.Capabilities {
+	return capabilities[tg.Type]
+}
+
+// Remote connects to the target. The test is skipped when no backend of its
+// type is built in, so the harness can start servers ahead of their backend.
+func (tg Target) Remote(t *testing.T) backend.TaskManager {
+	t.Helper()
+	config := backend.BackendConfig{
+		Name:                "e2e",
+		Type:                tg.Type,
+		Enabled:             true,
+		URL:                 tg.URL,
+		AllowHTTP:           true, // The test servers only listen on localhost
+		SuppressHTTPWarning: true,
+		InsecureSkipVerify:  true,
+		SuppressSSLWarning:  true,
+	}
+	remote, err := config.TaskManager()
+	var unsupported *backend.UnsupportedSchemeError
+	if errors.As(err, &unsupported) {
+		t.Skipf("no %s backend is built in", tg.Type)
+	}
+	if err != nil {
+		t.Fatalf("failed to connect to the %s server: %v", tg.Type, err)
+	}
+	return remote
+}
+
+// Cache opens an empty SQLite cache, removed when the test ends
+func Cache(t *testing.T) *sqlite.SQLiteBackend {
+	t.Helper()
+	cache, err := sqlite.NewSQLiteBackend(backend.BackendConfig{
+		Type:    "sqlite",
+		Enabled: true,
+		DBPath:  filepath.Join(t.TempDir(), "cache.db"),
+	})
+	if err != nil {
+		t.Fatalf("failed to create the cache: %v", err)
+	}
+	t.Cleanup(func() { _ = cache.Close() })
+	return cache
+}
+
+// NewList creates a list with a unique name on the remote, deleted when the
+// test ends if the test did not delete it, and returns its ID and name
+func NewList(t *testing.T, remote backend.TaskManager, prefix string) (string, string) {
+	t.Helper()
+	name := fmt.Sprintf("e2e %s %d", prefix, time.Now().UnixNano())
+	listID, err := remote.CreateTaskList(name, "", "")
+	if err != nil {
+		t.Fatalf("CreateTaskList(%q) error = %v", name, err)
+	}
+	t.Cleanup(func() { _ = remote.DeleteTaskList(listID) })
+	return listID, name
+}
@@ -0,0 +1,133 @@
+//go:build e2e
+
+package e2e
+
+import (
+	"testing"
+	"time"
+
+	"gosynctasks/backend"
+	"gosynctasks/backend/conformance"
+	"gosynctasks/backend/sync"
+)
+
+// TestE2EConformance runs the backend-agnostic TaskManager suite against the
+// live server
+func TestE2EConformance(t *testing.T) {
+	target := TargetFromEnv(t)
+	remote := target.Remote(t)
+	conformance.Run(t, func() backend.TaskManager { return remote }, target.Capabilities())
+}
+
+// TestE2ESyncFlow walks a list through its life against the live server:
+// created with a hierarchy on the remote, synced to a cache, edited on both
+// sides, the conflict resolved, then deleted
+func TestE2ESyncFlow(t *testing.T) {
+	target := TargetFromEnv(t)
+	remote := target.Remote(t)
+	cache := Cache(t)
+	listID, _ := NewList(t, remote, "flow")
+
+	// Create a hierarchy on the remote
+	now := time.Now()
+	parent := backend.Task{UID: "e2e-parent-" + listID, Summary: "Release", Status: "NEEDS-ACTION", Created: now, Modified: now}
+	child := backend.Task{UID: "e2e-child-" + listID, Summary: "Write notes", Status: "NEEDS-ACTION", ParentUID: parent.UID, Created: now, Modified: now}
+	for _, task := range []backend.Task{parent, child} {
+		if _, err := remote.AddTask(listID, task); err != nil {
+			t.Fatalf("AddTask(%q) error = %v", task.Summary, err)
+		}
+	}
+
+	// Sync it to the cache
+	var prompted []sync.Conflict
+	sm := sync.NewSyncManager(cache, remote, sync.Prompt)
+	sm.SetConflictPrompter(func(conflict sync.Conflict) (sync.ConflictResolutionStrategy, error) {
+		prompted = append(prompted, conflict)
+		return sync.LocalWins, nil
+	})
+	syncOnce(t, sm)
+
+	cached := tasksByUID(t, cache, listID)
+	if len(cached) != 2 {
+		t.Fatalf("cache has %d tasks after the first sync, want 2", len(cached))
+	}
+	if got := cached[child.UID].ParentUID; got != parent.UID {
+		t.Errorf("cached child has parent %q, want %q", got, parent.UID)
+	}
+
+	// Modify the child on both sides; the remote edit must be newer than the
+	// sync to count as a change
+	time.Sleep(1100 * time.Millisecond)
+	localEdit := cached[child.UID]
+	localEdit.Summary = "Write release notes"
+	if err := cache.UpdateTask(listID, localEdit); err != nil {
+		t.Fatalf("cache UpdateTask error = %v", err)
+	}
+	remoteEdit := tasksByUID(t, remote, listID)[child.UID]
+	remoteEdit.Summary = "Write the changelog"
+	remoteEdit.Priority = 2
+	remoteEdit.Modified = time.Now()
+	if err := remote.UpdateTask(listID, remoteEdit); err != nil {
+		t.Fatalf("remote UpdateTask error = %v", err)
+	}
+
+	// Resolve the conflict, keeping the local version
+	result := syncOnce(t, sm)
+	if len(prompted) != 1 || prompted[0].Local.UID != child.UID {
+		t.Fatalf("prompted for %d conflicts, want one for the child", len(prompted))
+	}
+	if got := result.ResolvedBy(); got[sync.LocalWins] != 1 {
+		t.Errorf("ResolvedBy() = %v, want 1 local_wins", got)
+	}
+	if got := tasksByUID(t, remote, listID)[child.UID].Summary; got != localEdit.Summary {
+		t.Errorf("remote child summary = %q after the sync, want the local %q", got, localEdit.Summary)
+	}
+	if got := tasksByUID(t, remote, listID)[child.UID].ParentUID; got != parent.UID {
+		t.Errorf("remote child has parent %q after the sync, want %q", got, parent.UID)
+	}
+
+	// Delete the list on both sides; syncing again must not bring it back
+	if err := remote.DeleteTaskList(listID); err != nil {
+		t.Fatalf("remote DeleteTaskList error = %v", err)
+	}
+	if err := cache.DeleteTaskList(listID); err != nil {
+		t.Fatalf("cache DeleteTaskList error = %v", err)
+	}
+	syncOnce(t, sm)
+	lists, err := cache.GetTaskLists()
+	if err != nil {
+		t.Fatalf("cache GetTaskLists error = %v", err)
+	}
+	for _, list := range lists {
+		if list.ID == listID {
+			t.Errorf("deleted list %s is back in the cache", listID)
+		}
+	}
+}
+
+// syncOnce runs a sync, failing the test on any error
+func syncOnce(t *testing.T, sm *sync.SyncManager) *sync.SyncResult {
+	t.Helper()
+	result, err := sm.Sync()
+	if err != nil {
+		t.Fatalf("Sync() error = %v", err)
+	}
+	for _, err := range result.Errors {
+		t.Errorf("Sync() reported: %v", err)
+	}
+	return result
+}
+
+// tasksByUID returns the tasks of a list by UID
+func tasksByUID(t *testing.T, tm backend.TaskManager, listID string) map[string]backend.Task {
+	t.Helper()
+	tasks, err := tm.GetTasks(listID, nil)
+	if err != nil {
+		t.Fatalf("GetTasks(%s) error = %v", listID, err)
+	}
+	byUID := make(map[string]backend.Task, len(tasks))
+	for _, task := range tasks {
+		byUID[task.UID] = task
+	}
+	return byUID
+}
@@ -0,0 +1,295 @@
+package ical
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"gosynctasks/backend"
+)
+
+// Property is a content line: NAME;PARAM=value:VALUE. Names and parameter
+// names are upper case.
+type Property struct {
+	Name   string
+	Params map[string]string
+	Value  string
+}
+
+// Decode reads the VTODO components of an iCalendar stream, such as the
+// exports of Apple Reminders or Thunderbird. Components nested in a VTODO,
+// like VALARM, and other components are skipped. Statuses are left as the
+// RFC 5545 names of the file; times with a TZID are read in that zone, and
+// floating times in the local one.
+func Decode(r io.Reader) ([]backend.Task, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the calendar: %w", err)
+	}
+	lines := Unfold(string(data))
+
+	var tasks []backend.Task
+	var task *backend.Task
+	nested := 0 // Depth of the components open inside the current VTODO
+	for n, line := range lines {
+		if line == "" {
+			continue
+		}
+		prop, err := ParseProperty(line)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", n+1, err)
+		}
+
+		switch {
+		case prop.Name == "BEGIN" && task == nil:
+			if strings.EqualFold(prop.Value, "VTODO") {
+				task = &backend.Task{}
+			}
+		case prop.Name == "BEGIN":
+			nested++
+		case prop.Name == "END" && task != nil && nested > 0:
+			nested--
+		case prop.Name == "END" && task != nil:
+			if !strings.EqualFold(prop.Value, "VTODO") {
+				return nil, fmt.Errorf("line %d: END:%s inside a VTODO", n+1, prop.Value)
+			}
+			tasks = append(tasks, *task)
+			task = nil
+		case task != nil && nested == 0:
+			if err := setProperty(task, prop); err != nil {
+				return nil, fmt.Errorf("line %d: %w", n+1, err)
+			}
+		}
+	}
+	if task != nil {
+		return nil, fmt.Errorf("unterminated VTODO")
+	}
+	return tasks, nil
+}
+
+// Unfold splits iCalendar text into its content lines, joining the lines
+// folded by a leading space or tab. Lines ended by LF alone are accepted
+// too.
+func Unfold(data string) []string {
+	var lines []string
+	for line := range strings.SplitSeq(data, "\n") {
+		line = strings.TrimSuffix(line, "\r")
+		if len(lines) > 0 && line != "" && (line[0] == ' ' || line[0] == '\t') {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// ParseProperty splits a content line into its name, parameters and value.
+// Parameter values may be quoted, and a quoted value may hold ':' and ';'.
+func ParseProperty(line string) (Property, error) {
+	prop := Property{}
+	inQuotes := false
+	start := 0
+	var fields []string
+	for i := 0; i < len(line); i++ {
+		switch c := line[i]; {
+		case c == '"':
+			inQuotes = !inQuotes
+		case c == ';' && !inQuotes:
+			fields = append(fields, line[start:i])
+			start = i + 1
+		case c == ':' && !inQuotes:
+			fields = append(fields, line[start:i])
+			prop.Value = line[i+1:]
+			prop.Name = strings.ToUpper(fields[0])
+			if prop.Name == "" {
+				return prop, fmt.Errorf("property without a name")
+			}
+			for _, param := range fields[1:] {
+				key, value, _ := strings.Cut(param, "=")
+				if prop.Params == nil {
+					prop.Params = make(map[string]string)
+				}
+				prop.Params[strings.ToUpper(key)] = strings.Trim(value, `"`)
+			}
+			return prop, nil
+		}
+	}
+	return prop, fmt.Errorf("no ':' in %q", line)
+}
+
+// setProperty sets the field of task a VTODO property stands for. Properties
+// the tasks have no field for are ignored.
+func setProperty(task *backend.Task, prop Property) error {
+	var err error
+	switch prop.Name {
+	case "UID":
+		task.UID = prop.Value
+	case "SUMMARY":
+		task.Summary = UnescapeText(prop.Value)
+	case "DESCRIPTION":
+		task.Description = UnescapeText(prop.Value)
+	case "STATUS":
+		task.Status = strings.ToUpper(prop.Value)
+	case "PRIORITY":
+		// Out of range priorities are kept for the caller to refuse
+		if task.Priority, err = strconv.Atoi(prop.Value); err != nil {
+			err = fmt.Errorf("invalid PRIORITY %q", prop.Value)
+		}
+	case "SEQUENCE":
+		task.Sequence, _ = strconv.Atoi(prop.Value)
+	case "CREATED":
+		task.Created, err = parseTime(prop)
+	case "LAST-MODIFIED":
+		task.Modified, err = parseTime(prop)
+	case "DUE", "DTSTART":
+		err = SetDate(task, prop)
+	case "COMPLETED":
+		task.Completed, err = parseTimePtr(prop)
+	case "CATEGORIES":
+		task.Categories = append(task.Categories, SplitCategories(prop.Value)...)
+	case "RRULE":
+		task.Recurrence = prop.Value
+	case "RELATED-TO":
+		// Only the parent is kept; CHILD and SIBLING relations are implied by it
+		if reltype := prop.Params["RELTYPE"]; reltype == "" || strings.EqualFold(reltype, "PARENT") {
+			task.ParentUID = prop.Value
+		}
+	}
+	return err
+}
+
+// ParseTime reads the value of a date property with its parameters: a
+// DATE-TIME in UTC, in the zone of its TZID or floating, in the local time
+// zone, or a DATE, as local midnight. isDate tells a DATE.
+func ParseTime(value string, params map[string]string) (t time.Time, isDate bool, err error) {
+	location := time.Local
+	if tzid := params["TZID"]; tzid != "" {
+		location = loadTZID(tzid)
+	}
+
+	if strings.EqualFold(params["VALUE"], "DATE") || len(value) == len("20060102") {
+		t, err = time.ParseInLocation("20060102", value, time.Local)
+		if err != nil {
+			return time.Time{}, false, fmt.Errorf("invalid date %q", value)
+		}
+		return t, true, nil
+	}
+	if strings.HasSuffix(value, "Z") {
+		t, err = time.Parse(timeFormat, value)
+	} else {
+		t, err = time.ParseInLocation("20060102T150405", value, location)
+	}
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("invalid time %q", value)
+	}
+	return t, false, nil
+}
+
+// loadTZID returns the zone a TZID names: an IANA name, possibly behind a
+// prefix such as "/mozilla.org/20050126_1/" as some clients write it. Zones
+// the system does not know are taken as the local one.
+func loadTZID(tzid string) *time.Location {
+	name := strings.TrimPrefix(tzid, "/")
+	for {
+		if location, err := time.LoadLocation(name); err == nil && name != "" {
+			return location
+		}
+		_, rest, ok := strings.Cut(name, "/")
+		if !ok {
+			return time.Local
+		}
+		name = rest
+	}
+}
+
+// SetDate sets the due or start date of a task from its DUE or DTSTART
+// property. The task is all-day while each of its dates is a DATE, which
+// keeps it due until the end of its day (see backend.IsOverdue); a date and
+// a date-time together, which RFC 5545 forbids, keep the time. A value that
+// does not parse leaves the task unchanged.
+func SetDate(task *backend.Task, prop Property) error {
+	t, isDate, err := ParseTime(prop.Value, prop.Params)
+	if err != nil {
+		return fmt.Errorf("%s: %w", prop.Name, err)
+	}
+	if task.DueDate == nil && task.StartDate == nil {
+		task.AllDay = isDate
+	} else {
+		task.AllDay = task.AllDay && isDate
+	}
+	switch prop.Name {
+	case "DUE":
+		task.DueDate = &t
+	case "DTSTART":
+		task.StartDate = &t
+	}
+	return nil
+}
+
+// parseTime reads a date property whose kind of value does not matter
+func parseTime(prop Property) (time.Time, error) {
+	t, _, err := ParseTime(prop.Value, prop.Params)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%s: %w", prop.Name, err)
+	}
+	return t, nil
+}
+
+func parseTimePtr(prop Property) (*time.Time, error) {
+	t, err := parseTime(prop)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// SplitCategories splits a CATEGORIES value at the commas that separate
+// tags, keeping escaped commas inside them
+func SplitCategories(value string) []string {
+	var categories []string
+	start := 0
+	for i := 0; i < len(value); i++ {
+		switch value[i] {
+		case '\\':
+			i++ // Skip the escaped character
+		case ',':
+			categories = append(categories, UnescapeText(value[start:i]))
+			start = i + 1
+		}
+	}
+	return append(categories, UnescapeText(value[start:]))
+}
+
+// UnescapeText decodes an iCalendar TEXT value in one pass, so an escaped
+// backslash followed by "n" stays literal
+func UnescapeText(text string) string {
+	if !strings.Contains(text, `\`) {
+		return text
+	}
+
+	var sb strings.Builder
+	escaped := false
+	for _, r := range text {
+		if !escaped {
+			if r == '\\' {
+				escaped = true
+			} else {
+				sb.WriteRune(r)
+			}
+			continue
+		}
+		escaped = false
+		switch r {
+		case 'n', 'N':
+			sb.WriteRune('\n')
+		default:
+			sb.WriteRune(r)
+		}
+	}
+	if escaped {
+		sb.WriteRune('\\')
+	}
+	return sb.String()
+}
@@ -0,0 +1,257 @@
+package ical
+
+import (
+	"bytes"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"gosynctasks/backend"
+)
+
+// reminders is shaped like an Apple Reminders export: LF line ends, folded
+// lines, a TZID, an alarm inside a VTODO and a VEVENT to skip
+const reminders = `BEGIN:VCALENDAR
+VERSION:2.0
+PRODID:-//Apple Inc.//iOS 17.0//EN
+BEGIN:VEVENT
+UID:event
+SUMMARY:Not a task
+END:VEVENT
+BEGIN:VTODO
+UID:groceries
+SUMMARY:Groceries\, weekly
+DESCRIPTION:milk\nbread and a very long line that the exporter folded a
+ cross two lines
+DUE;TZID=Europe/Paris:20260320T180000
+PRIORITY:5
+CATEGORIES:home,errands
+BEGIN:VALARM
+ACTION:DISPLAY
+DESCRIPTION:Reminder
+TRIGGER:-PT15M
+END:VALARM
+END:VTODO
+BEGIN:VTODO
+UID:milk
+SUMMARY:Milk
+STATUS:COMPLETED
+COMPLETED:20260318T101500Z
+DTSTART;VALUE=DATE:20260317
+RELATED-TO;RELTYPE=PARENT:groceries
+END:VTODO
+BEGIN:VTODO
+UID:sibling
+SUMMARY:Sibling
+RELATED-TO;RELTYPE=SIBLING:milk
+END:VTODO
+END:VCALENDAR
+`
+
+func TestDecode(t *testing.T) {
+	tasks, err := Decode(strings.NewReader(reminders))
+	if err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	if len(tasks) != 3 {
+		t.Fatalf("Decode() returned %d tasks, want 3: %+v", len(tasks), tasks)
+	}
+
+	groceries := tasks[0]
+	if groceries.Summary != "Groceries, weekly" || groceries.Priority != 5 {
+		t.Errorf("groceries = %+v", groceries)
+	}
+	if want := "milk\nbread and a very long line that the exporter folded across two lines"; groceries.Description != want {
+		t.Errorf("description = %q, want %q (the alarm's must not replace it)", groceries.Description, want)
+	}
+	paris, _ := time.LoadLocation("Europe/Paris")
+	if groceries.DueDate == nil || !groceries.DueDate.Equal(time.Date(2026, 3, 20, 18, 0, 0, 0, paris)) {
+		t.Errorf("due = %v, want 18:00 in Paris", groceries.DueDate)
+	}
+	if !slices.Equal(groceries.Categories, []string{"home", "errands"}) {
+		t.Errorf("categories = %q", groceries.Categories)
+	}
+
+	milk := tasks[1]
+	if milk.ParentUID != "groceries" || milk.Status != "COMPLETED" {
+		t.Errorf("milk = %+v", milk)
+	}
+	if milk.Completed == nil || !milk.Completed.Equal(time.Date(2026, 3, 18, 10, 15, 0, 0, time.UTC)) {
+		t.Errorf("completed = %v", milk.Completed)
+	}
+	if milk.StartDate == nil || milk.StartDate.Day() != 17 || !milk.AllDay {
+		t.Errorf("start = %v, all day %v, want the 17th, all day", milk.StartDate, milk.AllDay)
+	}
+	if groceries.AllDay {
+		t.Error("a DATE-TIME due made groceries all day")
+	}
+	if tasks[2].ParentUID != "" {
+		t.Errorf("a SIBLING relation became parent %q", tasks[2].ParentUID)
+	}
+
+	for _, broken := range []string{
+		"BEGIN:VCALENDAR\nBEGIN:VTODO\nUID:x\n",
+		"BEGIN:VCALENDAR\nBEGIN:VTODO\nUID:x\nEND:VCALENDAR\n",
+		"BEGIN:VCALENDAR\nBEGIN:VTODO\nno colon\nEND:VTODO\n",
+		"BEGIN:VCALENDAR\nBEGIN:VTODO\nDUE:tomorrow\nEND:VTODO\n",
+		"BEGIN:VCALENDAR\nBEGIN:VTODO\nPRIORITY:high\nEND:VTODO\n",
+	} {
+		if _, err := Decode(strings.NewReader(broken)); err == nil {
+			t.Errorf("Decode(%q) error = nil, want one", broken)
+		}
+	}
+}
+
+// TestDecodeReadsEncode tests that an export reads back as the same tasks
+func TestDecodeReadsEncode(t *testing.T) {
+	due := time.Date(2026, 3, 14, 9, 30, 0, 0, time.UTC)
+	day := time.Date(2026, 3, 16, 0, 0, 0, 0, time.Local)
+	tasks := []backend.Task{
+		{UID: "parent", Summary: "Release; v2, " + strings.Repeat("long ", 30), Status: "NEEDS-ACTION", Priority: 1, DueDate: &due, Categories: []string{"work", "a,b"}},
+		{UID: "child", Summary: "Notes", Description: `line one` + "\n" + `C:\new`, Status: "COMPLETED", ParentUID: "parent", Sequence: 3, Recurrence: "FREQ=WEEKLY;BYDAY=MO,TH"},
+		{UID: "day", Summary: "Day off", DueDate: &day, AllDay: true},
+	}
+	var buf bytes.Buffer
+	if err := Encode(&buf, "Work", tasks); err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+	decoded, err := Decode(&buf)
+	if err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	if len(decoded) != len(tasks) {
+		t.Fatalf("Decode() returned %d tasks, want %d", len(decoded), len(tasks))
+	}
+	for i, task := range tasks {
+		got := decoded[i]
+		if got.UID != task.UID || got.Summary != task.Summary || got.Description != task.Description ||
+			got.Status != task.Status || got.Priority != task.Priority || got.ParentUID != task.ParentUID ||
+			got.Sequence != task.Sequence || got.Recurrence != task.Recurrence || got.AllDay != task.AllDay || !slices.Equal(got.Categories, task.Categories) {
+			t.Errorf("task %d = %+v, want %+v", i, got, task)
+		}
+	}
+	if decoded[0].DueDate == nil || !decoded[0].DueDate.Equal(due) {
+		t.Errorf("due = %v, want %v", decoded[0].DueDate, due)
+	}
+	if decoded[2].DueDate == nil || !decoded[2].DueDate.Equal(day) {
+		t.Errorf("all-day due = %v, want %v", decoded[2].DueDate, day)
+	}
+}
+
+func TestUnescapeText(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "newline escape",
+			input:    "Line 1\\nLine 2",
+			expected: "Line 1\nLine 2",
+		},
+		{
+			name:     "comma escape",
+			input:    "Item 1\\, Item 2",
+			expected: "Item 1, Item 2",
+		},
+		{
+			name:     "semicolon escape",
+			input:    "Part 1\\; Part 2",
+			expected: "Part 1; Part 2",
+		},
+		{
+			name:     "backslash escape",
+			input:    "Path\\\\to\\\\file",
+			expected: "Path\\to\\file",
+		},
+		{
+			name:     "multiple escapes",
+			input:    "Text\\nwith\\, multiple\\; escapes\\\\here",
+			expected: "Text\nwith, multiple; escapes\\here",
+		},
+		{
+			name:     "no escapes",
+			input:    "Plain text",
+			expected: "Plain text",
+		},
+		{
+			name:     "empty string",
+			input:    "",
+			expected: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := UnescapeText(tt.input)
+			if result != tt.expected {
+				t.Errorf("UnescapeText(%q) = %q, want %q", tt.input, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestParseTime(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		wantError bool
+		expected  string // Expected time in RFC3339 format for comparison, local without a zone
+	}{
+		{
+			name:      "UTC time format",
+			input:     "20240315T143000Z",
+			wantError: false,
+			expected:  "2024-03-15T14:30:00Z",
+		},
+		{
+			name:      "Local time format",
+			input:     "20240315T143000",
+			wantError: false,
+			expected:  "2024-03-15T14:30:00",
+		},
+		{
+			name:      "Date only format",
+			input:     "20240315",
+			wantError: false,
+			expected:  "2024-03-15T00:00:00",
+		},
+		{
+			name:      "Invalid format",
+			input:     "2024-03-15",
+			wantError: true,
+		},
+		{
+			name:      "Empty string",
+			input:     "",
+			wantError: true,
+		},
+		{
+			name:      "Malformed date",
+			input:     "20241332T143000Z",
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, _, err := ParseTime(tt.input, nil)
+
+			if (err != nil) != tt.wantError {
+				t.Errorf("ParseTime(%q) error = %v, wantError %v", tt.input, err, tt.wantError)
+				return
+			}
+
+			if !tt.wantError {
+				expected, err := time.Parse(time.RFC3339, tt.expected)
+				if err != nil {
+					expected, _ = time.ParseInLocation("2006-01-02T15:04:05", tt.expected, time.Local)
+				}
+				if !result.Equal(expected) {
+					t.Errorf("ParseTime(%q) = %v, want %v", tt.input, result, expected)
+				}
+			}
+		})
+	}
+}
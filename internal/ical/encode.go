@@ -0,0 +1,151 @@
+// Package ical converts tasks to and from iCalendar (RFC 5545) VTODO
+// components. It works on the generic backend.Task, so that the tasks of
+// every backend can be exported and imported the same way.
+package ical
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"gosynctasks/backend"
+	"gosynctasks/backend/statuskit"
+)
+
+// prodID identifies gosynctasks as the producer of the calendars it writes
+const prodID = "-//gosynctasks//export//EN"
+
+// timeFormat is the UTC DATE-TIME form dates are written in
+const timeFormat = "20060102T150405Z"
+
+// maxLineOctets is the length content lines are folded at
+const maxLineOctets = 75
+
+var textEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`)
+
+// EscapeText encodes text as an iCalendar TEXT value
+func EscapeText(text string) string {
+	return textEscaper.Replace(text)
+}
+
+// Encode writes a VCALENDAR named name with one VTODO per task. Statuses are
+// written as their RFC 5545 names whatever the backend stores, and parents
+// as RELATED-TO, so the hierarchy survives an import.
+func Encode(w io.Writer, name string, tasks []backend.Task) error {
+	bw := bufio.NewWriter(w)
+	line := func(property, value string) {
+		writeFolded(bw, property+":"+value)
+	}
+
+	line("BEGIN", "VCALENDAR")
+	line("VERSION", "2.0")
+	line("PRODID", prodID)
+	if name != "" {
+		line("X-WR-CALNAME", EscapeText(name))
+	}
+	stamp := time.Now().UTC().Format(timeFormat)
+	for _, task := range tasks {
+		line("BEGIN", "VTODO")
+		line("UID", task.UID)
+		line("DTSTAMP", stamp)
+		if !task.Created.IsZero() {
+			line("CREATED", formatTime(task.Created))
+		}
+		if !task.Modified.IsZero() {
+			line("LAST-MODIFIED", formatTime(task.Modified))
+		}
+		if task.Sequence > 0 {
+			line("SEQUENCE", fmt.Sprint(task.Sequence))
+		}
+		line("SUMMARY", EscapeText(task.Summary))
+		if task.Description != "" {
+			line("DESCRIPTION", EscapeText(task.Description))
+		}
+		if task.Status != "" {
+			line("STATUS", statusName(task.Status))
+		}
+		if task.Priority > 0 {
+			line("PRIORITY", fmt.Sprint(task.Priority))
+		}
+		if task.DueDate != nil {
+			writeFolded(bw, FormatDate("DUE", *task.DueDate, task.AllDay))
+		}
+		if task.StartDate != nil {
+			writeFolded(bw, FormatDate("DTSTART", *task.StartDate, task.AllDay))
+		}
+		if task.Recurrence != "" {
+			line("RRULE", task.Recurrence)
+		}
+		if task.Completed != nil {
+			line("COMPLETED", formatTime(*task.Completed))
+		}
+		if len(task.Categories) > 0 {
+			categories := make([]string, len(task.Categories))
+			for i, category := range task.Categories {
+				categories[i] = EscapeText(category)
+			}
+			line("CATEGORIES", strings.Join(categories, ","))
+		}
+		if task.ParentUID != "" {
+			line("RELATED-TO", task.ParentUID)
+		}
+		line("END", "VTODO")
+	}
+	line("END", "VCALENDAR")
+	return bw.Flush()
+}
+
+// statusName returns the RFC 5545 name of a stored status; statuses outside
+// the canonical ones are written unchanged
+func statusName(status string) string {
+	if s, ok := statuskit.Lookup(status); ok {
+		return s.CalDAVName()
+	}
+	return status
+}
+
+func formatTime(t time.Time) string {
+	return t.UTC().Format(timeFormat)
+}
+
+// FormatDate returns the content line of a DUE or DTSTART: the DATE of its
+// local day for all-day tasks, a DATE-TIME in UTC otherwise
+func FormatDate(property string, t time.Time, allDay bool) string {
+	if allDay {
+		return property + ";VALUE=DATE:" + t.Local().Format("20060102")
+	}
+	return property + ":" + formatTime(t)
+}
+
+// Fold folds the CRLF-ended content lines of iCalendar text as writeFolded
+// does
+func Fold(data string) string {
+	var folded strings.Builder
+	w := bufio.NewWriter(&folded)
+	for line := range strings.SplitSeq(strings.TrimSuffix(data, "\r\n"), "\r\n") {
+		writeFolded(w, line)
+	}
+	_ = w.Flush()
+	return folded.String()
+}
+
+// writeFolded writes a content line ended by CRLF, folded into lines of at
+// most 75 octets continued by a space, without splitting a UTF-8 sequence
+func writeFolded(w *bufio.Writer, line string) {
+	limit := maxLineOctets
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		w.WriteString(line[:cut])
+		w.WriteString("\r\n ")
+		line = line[cut:]
+		limit = maxLineOctets - 1 // The leading space counts
+	}
+	w.WriteString(line)
+	w.WriteString("\r\n")
+}
@@ -0,0 +1,91 @@
+package ical
+
+import (
+	"bytes"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"gosynctasks/backend"
+)
+
+func TestEncode(t *testing.T) {
+	due := time.Date(2026, 3, 14, 9, 30, 0, 0, time.UTC)
+	completed := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	tasks := []backend.Task{
+		{UID: "parent", Summary: "Release, v2; final", Status: "TODO", Priority: 1, DueDate: &due, Categories: []string{"work", "a,b"}},
+		{UID: "child", Summary: "Notes", Description: "line one\nline two", Status: "DONE", Completed: &completed, ParentUID: "parent"},
+	}
+
+	var buf bytes.Buffer
+	if err := Encode(&buf, "Work", tasks); err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+	out := buf.String()
+
+	for _, want := range []string{
+		"BEGIN:VCALENDAR\r\n", "VERSION:2.0\r\n", "X-WR-CALNAME:Work\r\n",
+		"UID:parent\r\n", `SUMMARY:Release\, v2\; final` + "\r\n", "STATUS:NEEDS-ACTION\r\n", "PRIORITY:1\r\n",
+		"DUE:20260314T093000Z\r\n", `CATEGORIES:work,a\,b` + "\r\n",
+		"UID:child\r\n", `DESCRIPTION:line one\nline two` + "\r\n", "STATUS:COMPLETED\r\n",
+		"COMPLETED:20260301T120000Z\r\n", "RELATED-TO:parent\r\n", "END:VCALENDAR\r\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Encode() output lacks %q:\n%s", want, out)
+		}
+	}
+	if got := strings.Count(out, "BEGIN:VTODO\r\n"); got != 2 {
+		t.Errorf("Encode() wrote %d VTODOs, want 2", got)
+	}
+}
+
+func TestEncodeFoldsLongLines(t *testing.T) {
+	summary := strings.Repeat("Déjà vu 🎉 ", 20)
+	var buf bytes.Buffer
+	if err := Encode(&buf, "", []backend.Task{{UID: "long", Summary: summary}}); err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\r\n"), "\r\n")
+	var unfolded []string
+	for _, line := range lines {
+		if len(line) > maxLineOctets {
+			t.Errorf("line of %d octets: %q", len(line), line)
+		}
+		if !utf8.ValidString(line) {
+			t.Errorf("line splits a UTF-8 sequence: %q", line)
+		}
+		if strings.HasPrefix(line, " ") {
+			unfolded[len(unfolded)-1] += line[1:]
+		} else {
+			unfolded = append(unfolded, line)
+		}
+	}
+
+	want := "SUMMARY:" + summary
+	found := false
+	for _, line := range unfolded {
+		found = found || line == want
+	}
+	if !found {
+		t.Errorf("unfolded lines lack %q:\n%s", want, strings.Join(unfolded, "\n"))
+	}
+}
+
+func TestFoldAndUnfold(t *testing.T) {
+	long := "DESCRIPTION:" + strings.Repeat("x", 200)
+	folded := Fold(long + "\r\n")
+	if strings.Count(folded, "\r\n ") != 2 {
+		t.Errorf("Fold() = %q, want three lines", folded)
+	}
+	if got := Unfold(folded); len(got) != 2 || got[0] != long || got[1] != "" {
+		t.Errorf("Unfold() = %q, want the line back", got)
+	}
+
+	// Servers fold with a tab too, and some end lines with LF alone
+	if got := Unfold("SUMMARY:a long\n\t summary\nUID:x"); !slices.Equal(got, []string{"SUMMARY:a long summary", "UID:x"}) {
+		t.Errorf("Unfold() = %q", got)
+	}
+}
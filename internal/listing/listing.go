@@ -0,0 +1,140 @@
+// Package listing remembers the task listings shown in a terminal session,
+// so a task can be named by its position in the last listing of its list:
+// after 'gosynctasks Work', '%3' is the third task shown.
+package listing
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Entry is a task as it was shown
+type Entry struct {
+	UID     string `json:"uid"`
+	Summary string `json:"summary"`
+	Status  string `json:"status"`
+}
+
+// Listing is the last listing of one list, tasks in display order
+type Listing struct {
+	ListName string    `json:"list_name"`
+	ShownAt  time.Time `json:"shown_at"`
+	Tasks    []Entry   `json:"tasks"`
+}
+
+// Session holds the last listing of each list shown in a terminal session
+type Session struct {
+	path  string
+	Lists map[string]Listing `json:"lists"` // By list ID
+}
+
+// sessionKey identifies the terminal session: GOSYNCTASKS_SESSION when set,
+// then the session IDs terminals and multiplexers export, and the parent
+// shell's PID otherwise
+func sessionKey() string {
+	for _, name := range []string{"GOSYNCTASKS_SESSION", "TERM_SESSION_ID", "TMUX_PANE", "WINDOWID"} {
+		if value := os.Getenv(name); value != "" {
+			return name + "=" + value
+		}
+	}
+	return "ppid=" + strconv.Itoa(os.Getppid())
+}
+
+// DefaultPath returns the listing file of the current terminal session in
+// the XDG state directory
+func DefaultPath() (string, error) {
+	stateDir := os.Getenv("XDG_STATE_HOME")
+	if stateDir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		stateDir = filepath.Join(home, ".local", "state")
+	}
+	sum := sha256.Sum256([]byte(sessionKey()))
+	return filepath.Join(stateDir, "gosynctasks", "listings", hex.EncodeToString(sum[:8])+".json"), nil
+}
+
+// NewSession returns an empty session kept in the file at path
+func NewSession(path string) *Session {
+	return &Session{path: path, Lists: make(map[string]Listing)}
+}
+
+// Load reads the session file at path; a missing file is an empty session
+func Load(path string) (*Session, error) {
+	s := NewSession(path)
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read last listing: %w", err)
+	}
+	if err := json.Unmarshal(data, s); err != nil {
+		return nil, fmt.Errorf("failed to parse last listing %s: %w", path, err)
+	}
+	if s.Lists == nil {
+		s.Lists = make(map[string]Listing)
+	}
+	return s, nil
+}
+
+// Save writes the session file, replacing it atomically
+func (s *Session) Save() error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return fmt.Errorf("failed to create state directory: %w", err)
+	}
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("failed to write last listing: %w", err)
+	}
+	return os.Rename(tmp, s.path)
+}
+
+// Remember replaces the last listing of a list
+func (s *Session) Remember(listID string, listing Listing) {
+	s.Lists[listID] = listing
+}
+
+// ParseRef returns the position a reference like "%3" names, from 1
+func ParseRef(ref string) (int, bool) {
+	digits, ok := strings.CutPrefix(ref, "%")
+	if !ok {
+		return 0, false
+	}
+	position, err := strconv.Atoi(digits)
+	if err != nil || position < 1 {
+		return 0, false
+	}
+	return position, true
+}
+
+// Resolve returns the task shown at position in the last listing of a list.
+// The listing must have been shown within maxAge of now.
+func (s *Session) Resolve(listID, listName string, position int, maxAge time.Duration, now time.Time) (Entry, error) {
+	relist := fmt.Sprintf("list it again with 'gosynctasks %s' and use the positions shown", listName)
+	listing, ok := s.Lists[listID]
+	if !ok {
+		return Entry{}, fmt.Errorf("%%%d: '%s' was not listed in this terminal; %s", position, listName, relist)
+	}
+	if age := now.Sub(listing.ShownAt); age > maxAge {
+		return Entry{}, fmt.Errorf("%%%d: the last listing of '%s' is %s old, older than %s; %s",
+			position, listName, age.Round(time.Minute), maxAge, relist)
+	}
+	if position > len(listing.Tasks) {
+		return Entry{}, fmt.Errorf("%%%d: the last listing of '%s' showed %d tasks; %s",
+			position, listName, len(listing.Tasks), relist)
+	}
+	return listing.Tasks[position-1], nil
+}
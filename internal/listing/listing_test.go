@@ -0,0 +1,72 @@
+package listing
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseRef(t *testing.T) {
+	tests := map[string]int{"%1": 1, "%12": 12, "%0": 0, "%": 0, "%x": 0, "3": 0, "Buy %3": 0}
+	for ref, want := range tests {
+		got, ok := ParseRef(ref)
+		if got != want || ok != (want > 0) {
+			t.Errorf("ParseRef(%q) = %d, %v, want %d", ref, got, ok, want)
+		}
+	}
+}
+
+func TestSessionRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "listing.json")
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	session, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() of a missing file error = %v", err)
+	}
+	session.Remember("work-id", Listing{ListName: "Work", ShownAt: now, Tasks: []Entry{
+		{UID: "a", Summary: "First"}, {UID: "b", Summary: "Second"},
+	}})
+	if err := session.Save(); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	session, err = Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	entry, err := session.Resolve("work-id", "Work", 2, 10*time.Minute, now.Add(5*time.Minute))
+	if err != nil || entry.UID != "b" {
+		t.Errorf("Resolve(%%2) = %+v, %v, want task b", entry, err)
+	}
+
+	rejected := []struct {
+		name     string
+		listID   string
+		position int
+		at       time.Time
+		want     string
+	}{
+		{"stale", "work-id", 1, now.Add(11 * time.Minute), "11m0s old"},
+		{"other list", "home-id", 1, now, "was not listed"},
+		{"out of range", "work-id", 3, now, "showed 2 tasks"},
+	}
+	for _, tt := range rejected {
+		_, err := session.Resolve(tt.listID, "Work", tt.position, 10*time.Minute, tt.at)
+		if err == nil || !strings.Contains(err.Error(), tt.want) || !strings.Contains(err.Error(), "gosynctasks Work") {
+			t.Errorf("%s: Resolve() error = %v, want one containing %q and how to re-list", tt.name, err, tt.want)
+		}
+	}
+}
+
+func TestDefaultPathPerSession(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	t.Setenv("GOSYNCTASKS_SESSION", "one")
+	first, _ := DefaultPath()
+	t.Setenv("GOSYNCTASKS_SESSION", "two")
+	second, _ := DefaultPath()
+	if first == second {
+		t.Errorf("sessions share the listing file %s", first)
+	}
+}
@@ -0,0 +1,250 @@
+// Package metrics keeps counters, gauges and histograms in memory and
+// serves them in the Prometheus text exposition format. It has no
+// dependencies beyond the standard library, and only a long-running sync
+// process is meant to import it.
+package metrics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultBuckets are the upper bounds, in seconds, of request duration histograms
+var DefaultBuckets = []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// Kinds of metric families
+const (
+	kindCounter   = "counter"
+	kindGauge     = "gauge"
+	kindHistogram = "histogram"
+)
+
+// Registry holds metric families and writes them in registration order
+type Registry struct {
+	mu       sync.Mutex
+	families []*family
+}
+
+// NewRegistry returns an empty registry
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+// family is one named metric with its series, by label values
+type family struct {
+	name    string
+	help    string
+	kind    string
+	labels  []string
+	buckets []float64
+	series  map[string]*series
+}
+
+// series is one combination of label values
+type series struct {
+	labelValues []string
+	value       float64  // Counter or gauge value; histogram sum
+	counts      []uint64 // Histogram observations per bucket, not cumulative
+	count       uint64   // Histogram observations
+}
+
+func (r *Registry) register(name, help, kind string, buckets []float64, labels []string) *family {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	f := &family{name: name, help: help, kind: kind, labels: labels, buckets: buckets, series: make(map[string]*series)}
+	r.families = append(r.families, f)
+	return f
+}
+
+// get returns the series of f for labelValues, creating it. Callers hold r.mu.
+func (f *family) get(labelValues []string) *series {
+	if len(labelValues) != len(f.labels) {
+		panic(fmt.Sprintf("metrics: %s takes %d label values, got %d", f.name, len(f.labels), len(labelValues)))
+	}
+	key := strings.Join(labelValues, "\xff")
+	s, ok := f.series[key]
+	if !ok {
+		s = &series{labelValues: slices.Clone(labelValues)}
+		if f.kind == kindHistogram {
+			s.counts = make([]uint64, len(f.buckets))
+		}
+		f.series[key] = s
+	}
+	return s
+}
+
+// Counter is a value that only goes up
+type Counter struct {
+	r *Registry
+	f *family
+}
+
+// NewCounter registers a counter with the given label names
+func (r *Registry) NewCounter(name, help string, labels ...string) *Counter {
+	return &Counter{r: r, f: r.register(name, help, kindCounter, nil, labels)}
+}
+
+// Add adds delta, which must not be negative, to the series of labelValues
+func (c *Counter) Add(delta float64, labelValues ...string) {
+	if delta < 0 {
+		panic(fmt.Sprintf("metrics: counter %s cannot decrease", c.f.name))
+	}
+	c.r.mu.Lock()
+	defer c.r.mu.Unlock()
+	c.f.get(labelValues).value += delta
+}
+
+// Inc adds one to the series of labelValues
+func (c *Counter) Inc(labelValues ...string) {
+	c.Add(1, labelValues...)
+}
+
+// Gauge is a value that is set
+type Gauge struct {
+	r *Registry
+	f *family
+}
+
+// NewGauge registers a gauge with the given label names
+func (r *Registry) NewGauge(name, help string, labels ...string) *Gauge {
+	return &Gauge{r: r, f: r.register(name, help, kindGauge, nil, labels)}
+}
+
+// Set sets the series of labelValues to value
+func (g *Gauge) Set(value float64, labelValues ...string) {
+	g.r.mu.Lock()
+	defer g.r.mu.Unlock()
+	g.f.get(labelValues).value = value
+}
+
+// Histogram counts observations into buckets
+type Histogram struct {
+	r *Registry
+	f *family
+}
+
+// NewHistogram registers a histogram with the given bucket upper bounds,
+// DefaultBuckets when nil, and label names
+func (r *Registry) NewHistogram(name, help string, buckets []float64, labels ...string) *Histogram {
+	if buckets == nil {
+		buckets = DefaultBuckets
+	}
+	buckets = slices.Clone(buckets)
+	slices.Sort(buckets)
+	return &Histogram{r: r, f: r.register(name, help, kindHistogram, buckets, labels)}
+}
+
+// Observe records value in the series of labelValues
+func (h *Histogram) Observe(value float64, labelValues ...string) {
+	h.r.mu.Lock()
+	defer h.r.mu.Unlock()
+	s := h.f.get(labelValues)
+	s.value += value
+	s.count++
+	if i, _ := slices.BinarySearch(h.f.buckets, value); i < len(s.counts) {
+		s.counts[i]++
+	}
+}
+
+// WriteText writes every family in the Prometheus text format, series
+// sorted by label values
+func (r *Registry) WriteText(w io.Writer) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var b strings.Builder
+	for _, f := range r.families {
+		fmt.Fprintf(&b, "# HELP %s %s\n", f.name, escapeHelp(f.help))
+		fmt.Fprintf(&b, "# TYPE %s %s\n", f.name, f.kind)
+
+		keys := make([]string, 0, len(f.series))
+		for key := range f.series {
+			keys = append(keys, key)
+		}
+		slices.Sort(keys)
+		for _, key := range keys {
+			s := f.series[key]
+			if f.kind != kindHistogram {
+				fmt.Fprintf(&b, "%s%s %s\n", f.name, labelSet(f.labels, s.labelValues), formatValue(s.value))
+				continue
+			}
+			var cumulative uint64
+			for i, bound := range f.buckets {
+				cumulative += s.counts[i]
+				fmt.Fprintf(&b, "%s_bucket%s %d\n", f.name, labelSet(append(slices.Clone(f.labels), "le"), append(slices.Clone(s.labelValues), formatValue(bound))), cumulative)
+			}
+			fmt.Fprintf(&b, "%s_bucket%s %d\n", f.name, labelSet(append(slices.Clone(f.labels), "le"), append(slices.Clone(s.labelValues), "+Inf")), s.count)
+			fmt.Fprintf(&b, "%s_sum%s %s\n", f.name, labelSet(f.labels, s.labelValues), formatValue(s.value))
+			fmt.Fprintf(&b, "%s_count%s %d\n", f.name, labelSet(f.labels, s.labelValues), s.count)
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// ServeHTTP serves the registry as a /metrics scrape
+func (r *Registry) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	_ = r.WriteText(w)
+}
+
+// Serve serves the registry at /metrics on addr until ctx is done
+func Serve(ctx context.Context, addr string, r *Registry) error {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", r)
+	server := &http.Server{Addr: addr, Handler: mux, ReadHeaderTimeout: 5 * time.Second}
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = server.Shutdown(shutdownCtx)
+	}()
+
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("failed to serve metrics on %s: %w", addr, err)
+	}
+	return nil
+}
+
+// labelSet formats label pairs as {a="1",b="2"}, or nothing without labels
+func labelSet(names, values []string) string {
+	if len(names) == 0 {
+		return ""
+	}
+	pairs := make([]string, len(names))
+	for i, name := range names {
+		pairs[i] = name + `="` + labelEscaper.Replace(values[i]) + `"`
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+// formatValue formats a sample value as the exposition format expects
+func formatValue(value float64) string {
+	switch {
+	case math.IsInf(value, 1):
+		return "+Inf"
+	case math.IsInf(value, -1):
+		return "-Inf"
+	case math.IsNaN(value):
+		return "NaN"
+	}
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
+
+// labelEscaper escapes label values
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// escapeHelp escapes a HELP line
+func escapeHelp(help string) string {
+	return strings.NewReplacer(`\`, `\\`, "\n", `\n`).Replace(help)
+}
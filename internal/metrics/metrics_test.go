@@ -0,0 +1,114 @@
+package metrics
+
+import (
+	"errors"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"gosynctasks/backend"
+	"gosynctasks/backend/sync"
+)
+
+// scrape fetches /metrics from a test server for m
+func scrape(t *testing.T, m *SyncMetrics) string {
+	t.Helper()
+	server := httptest.NewServer(m.Registry)
+	defer server.Close()
+
+	resp, err := server.Client().Get(server.URL + "/metrics")
+	if err != nil {
+		t.Fatalf("scrape failed: %v", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if got := resp.Header.Get("Content-Type"); !strings.HasPrefix(got, "text/plain; version=0.0.4") {
+		t.Errorf("Content-Type = %q", got)
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(body)
+}
+
+func TestSyncMetricsScrape(t *testing.T) {
+	m := NewSyncMetrics()
+	finished := time.Unix(1783500000, 0)
+
+	m.ObserveSync(&sync.SyncResult{
+		PulledTasks: 3,
+		PushedTasks: 2,
+		Conflicts:   []sync.Conflict{{Resolution: sync.ServerWins}, {Resolution: sync.ServerWins}, {Resolution: sync.KeepBoth}},
+		ListResults: []sync.ListSyncResult{{ListID: "family"}, {ListID: "work", Err: errors.New("timeout")}},
+		Errors:      []error{errors.New("timeout")},
+	}, nil, finished)
+	m.ObserveSync(&sync.SyncResult{PushedTasks: 1, ListResults: []sync.ListSyncResult{{ListID: "work"}}}, nil, finished.Add(time.Minute))
+	m.ObserveSync(nil, errors.New("offline"), finished.Add(2*time.Minute))
+	m.SetQueueDepth(4)
+
+	tm := m.InstrumentTaskManager(backend.NewMockBackend())
+	_, _ = tm.GetTaskLists()
+	_, _ = tm.GetTaskLists()
+
+	body := scrape(t, m)
+	for _, want := range []string{
+		"# TYPE gosynctasks_syncs_total counter",
+		`gosynctasks_syncs_total{result="error"} 1`,
+		`gosynctasks_syncs_total{result="partial"} 1`,
+		`gosynctasks_syncs_total{result="success"} 1`,
+		"gosynctasks_tasks_pushed_total 3",
+		"gosynctasks_tasks_pulled_total 3",
+		`gosynctasks_conflicts_total{strategy="keep_both"} 1`,
+		`gosynctasks_conflicts_total{strategy="server_wins"} 2`,
+		"# TYPE gosynctasks_queue_depth gauge",
+		"gosynctasks_queue_depth 4",
+		`gosynctasks_last_successful_sync_timestamp_seconds{list="family"} 1.7835e+09`,
+		`gosynctasks_last_successful_sync_timestamp_seconds{list="work"} 1.78350006e+09`,
+		"# TYPE gosynctasks_backend_request_duration_seconds histogram",
+		`gosynctasks_backend_request_duration_seconds_bucket{method="GetTaskLists",le="0.05"} 2`,
+		`gosynctasks_backend_request_duration_seconds_bucket{method="GetTaskLists",le="+Inf"} 2`,
+		`gosynctasks_backend_request_duration_seconds_count{method="GetTaskLists"} 2`,
+	} {
+		if !strings.Contains(body, want+"\n") {
+			t.Errorf("scrape is missing %q:\n%s", want, body)
+		}
+	}
+}
+
+func TestHistogramBuckets(t *testing.T) {
+	r := NewRegistry()
+	h := r.NewHistogram("latency_seconds", "Latency.", []float64{1, 0.1})
+	for _, v := range []float64{0.05, 0.1, 0.5, 3} {
+		h.Observe(v)
+	}
+
+	var out strings.Builder
+	if err := r.WriteText(&out); err != nil {
+		t.Fatal(err)
+	}
+	want := `# HELP latency_seconds Latency.
+# TYPE latency_seconds histogram
+latency_seconds_bucket{le="0.1"} 2
+latency_seconds_bucket{le="1"} 3
+latency_seconds_bucket{le="+Inf"} 4
+latency_seconds_sum 3.65
+latency_seconds_count 4
+`
+	if out.String() != want {
+		t.Errorf("WriteText() =\n%s\nwant\n%s", out.String(), want)
+	}
+}
+
+func TestLabelEscaping(t *testing.T) {
+	r := NewRegistry()
+	r.NewGauge("list_tasks", "Tasks\nper list.", "list").Set(1, `Mom's "errands"\n`)
+
+	var out strings.Builder
+	_ = r.WriteText(&out)
+	if !strings.Contains(out.String(), `# HELP list_tasks Tasks\nper list.`) ||
+		!strings.Contains(out.String(), `list_tasks{list="Mom's \"errands\"\\n"} 1`) {
+		t.Errorf("escaping wrong:\n%s", out.String())
+	}
+}
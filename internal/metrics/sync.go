@@ -0,0 +1,118 @@
+package metrics
+
+import (
+	"time"
+
+	"gosynctasks/backend"
+	"gosynctasks/backend/sync"
+)
+
+// SyncMetrics are the metrics of a sync process
+type SyncMetrics struct {
+	Registry *Registry
+
+	syncs           *Counter
+	pushed          *Counter
+	pulled          *Counter
+	conflicts       *Counter
+	queueDepth      *Gauge
+	lastSuccess     *Gauge
+	requestDuration *Histogram
+}
+
+// NewSyncMetrics registers the sync metrics in a new registry
+func NewSyncMetrics() *SyncMetrics {
+	r := NewRegistry()
+	return &SyncMetrics{
+		Registry:        r,
+		syncs:           r.NewCounter("gosynctasks_syncs_total", "Syncs run, by result.", "result"),
+		pushed:          r.NewCounter("gosynctasks_tasks_pushed_total", "Tasks pushed to the remote."),
+		pulled:          r.NewCounter("gosynctasks_tasks_pulled_total", "Tasks pulled from the remote."),
+		conflicts:       r.NewCounter("gosynctasks_conflicts_total", "Sync conflicts, by resolution strategy.", "strategy"),
+		queueDepth:      r.NewGauge("gosynctasks_queue_depth", "Operations waiting in the sync queue."),
+		lastSuccess:     r.NewGauge("gosynctasks_last_successful_sync_timestamp_seconds", "When each list last synced without error.", "list"),
+		requestDuration: r.NewHistogram("gosynctasks_backend_request_duration_seconds", "Duration of backend calls, by method.", nil, "method"),
+	}
+}
+
+// ObserveSync records a finished sync. A sync with a result and errors is
+// partial: the lists without an error still count as synced at finished.
+func (m *SyncMetrics) ObserveSync(result *sync.SyncResult, err error, finished time.Time) {
+	switch {
+	case err != nil || result == nil:
+		m.syncs.Inc("error")
+		return
+	case len(result.Errors) > 0:
+		m.syncs.Inc("partial")
+	default:
+		m.syncs.Inc("success")
+	}
+
+	m.pushed.Add(float64(result.PushedTasks))
+	m.pulled.Add(float64(result.PulledTasks))
+	for _, conflict := range result.Conflicts {
+		m.conflicts.Inc(string(conflict.Resolution))
+	}
+	for _, list := range result.ListResults {
+		if list.Err == nil {
+			m.lastSuccess.Set(float64(finished.Unix()), list.ListID)
+		}
+	}
+}
+
+// SetQueueDepth records how many operations wait to be pushed
+func (m *SyncMetrics) SetQueueDepth(depth int) {
+	m.queueDepth.Set(float64(depth))
+}
+
+// timedTaskManager times the calls it passes through to a backend
+type timedTaskManager struct {
+	backend.TaskManager
+	metrics *SyncMetrics
+}
+
+// InstrumentTaskManager returns a task manager that records the duration of
+// every call to inner that reaches the remote. Optional interfaces of inner
+// are not exposed.
+func (m *SyncMetrics) InstrumentTaskManager(inner backend.TaskManager) backend.TaskManager {
+	return &timedTaskManager{TaskManager: inner, metrics: m}
+}
+
+func (t *timedTaskManager) observe(method string, start time.Time) {
+	t.metrics.requestDuration.Observe(time.Since(start).Seconds(), method)
+}
+
+func (t *timedTaskManager) GetTaskLists() ([]backend.TaskList, error) {
+	defer t.observe("GetTaskLists", time.Now())
+	return t.TaskManager.GetTaskLists()
+}
+
+func (t *timedTaskManager) GetTasks(listID string, taskFilter *backend.TaskFilter) ([]backend.Task, error) {
+	defer t.observe("GetTasks", time.Now())
+	return t.TaskManager.GetTasks(listID, taskFilter)
+}
+
+func (t *timedTaskManager) FindTasksBySummary(listID string, summary string) ([]backend.Task, error) {
+	defer t.observe("FindTasksBySummary", time.Now())
+	return t.TaskManager.FindTasksBySummary(listID, summary)
+}
+
+func (t *timedTaskManager) GetTask(listID string, taskUID string) (*backend.Task, error) {
+	defer t.observe("GetTask", time.Now())
+	return t.TaskManager.GetTask(listID, taskUID)
+}
+
+func (t *timedTaskManager) AddTask(listID string, task backend.Task) (string, error) {
+	defer t.observe("AddTask", time.Now())
+	return t.TaskManager.AddTask(listID, task)
+}
+
+func (t *timedTaskManager) UpdateTask(listID string, task backend.Task) error {
+	defer t.observe("UpdateTask", time.Now())
+	return t.TaskManager.UpdateTask(listID, task)
+}
+
+func (t *timedTaskManager) DeleteTask(listID string, taskUID string) error {
+	defer t.observe("DeleteTask", time.Now())
+	return t.TaskManager.DeleteTask(listID, taskUID)
+}
@@ -0,0 +1,94 @@
+// Package notes keeps the reasons tasks were closed with on this machine,
+// for reason_storage: local, so that shared calendars don't get them in
+// their descriptions.
+package notes
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Note is the reason a task was closed with
+type Note struct {
+	Label  string    `json:"label"` // "Cancelled" or "Done"
+	Reason string    `json:"reason"`
+	At     time.Time `json:"at"`
+}
+
+// Line returns the note as the timestamped line appended to descriptions
+func (n Note) Line() string {
+	return fmt.Sprintf("%s: %s %s", n.Label, n.At.Format("2006-01-02 15:04"), n.Reason)
+}
+
+// Store holds the notes by task UID, oldest first
+type Store struct {
+	path  string
+	Notes map[string][]Note `json:"notes"`
+}
+
+// DefaultPath returns the notes file in the XDG state directory
+func DefaultPath() (string, error) {
+	stateDir := os.Getenv("XDG_STATE_HOME")
+	if stateDir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		stateDir = filepath.Join(home, ".local", "state")
+	}
+	return filepath.Join(stateDir, "gosynctasks", "notes.json"), nil
+}
+
+// Load reads the notes file at path; a missing file is an empty store
+func Load(path string) (*Store, error) {
+	s := &Store{path: path, Notes: make(map[string][]Note)}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read local notes: %w", err)
+	}
+	if err := json.Unmarshal(data, s); err != nil {
+		return nil, fmt.Errorf("failed to parse local notes %s: %w", path, err)
+	}
+	if s.Notes == nil {
+		s.Notes = make(map[string][]Note)
+	}
+	return s, nil
+}
+
+// Save writes the notes file, replacing it atomically
+func (s *Store) Save() error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return fmt.Errorf("failed to create state directory: %w", err)
+	}
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return fmt.Errorf("failed to write local notes: %w", err)
+	}
+	return os.Rename(tmp, s.path)
+}
+
+// Add records note for the task with uid, unless its last note has the same
+// label and reason, and reports whether it did
+func (s *Store) Add(uid string, note Note) bool {
+	existing := s.Notes[uid]
+	if n := len(existing); n > 0 && existing[n-1].Label == note.Label && existing[n-1].Reason == note.Reason {
+		return false
+	}
+	s.Notes[uid] = append(existing, note)
+	return true
+}
+
+// For returns the notes of the task with uid, oldest first
+func (s *Store) For(uid string) []Note {
+	return s.Notes[uid]
+}
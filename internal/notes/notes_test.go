@@ -0,0 +1,38 @@
+package notes
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// TestAddSaveLoad tests that notes survive a save, and that the same reason
+// given again is not recorded twice
+func TestAddSaveLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state", "notes.json")
+	store, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() of a missing file error = %v", err)
+	}
+
+	at := time.Date(2026, 3, 4, 9, 30, 0, 0, time.Local)
+	note := Note{Label: "Cancelled", Reason: "superseded by #a3f", At: at}
+	if !store.Add("t1", note) {
+		t.Fatal("Add() = false, want true")
+	}
+	if store.Add("t1", Note{Label: "Cancelled", Reason: "superseded by #a3f", At: at.Add(time.Hour)}) {
+		t.Error("Add() of the same reason again = true, want false")
+	}
+	if err := store.Save(); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	got := loaded.For("t1")
+	if len(got) != 1 || got[0].Line() != "Cancelled: 2026-03-04 09:30 superseded by #a3f" {
+		t.Errorf("For(t1) = %+v, want the one note", got)
+	}
+}
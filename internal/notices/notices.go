@@ -0,0 +1,155 @@
+// Package notices shows backend security notices without flooding the
+// terminal: each one at most once a day, collected and printed together
+// between command output, and never while output is machine-read.
+package notices
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"gosynctasks/backend"
+)
+
+// Interval is how long a shown notice stays quiet
+const Interval = 24 * time.Hour
+
+// Acknowledgment records that a user accepted the risk a notice warns about
+type Acknowledgment struct {
+	At time.Time `json:"at"`
+}
+
+// state is what the notices file stores between runs
+type state struct {
+	Shown        map[string]time.Time      `json:"shown,omitempty"`
+	Acknowledged map[string]Acknowledgment `json:"acknowledged,omitempty"`
+}
+
+// Notifier collects notices reported during a run and prints them on Flush
+type Notifier struct {
+	path  string // State file; notices are shown every run when empty
+	out   io.Writer
+	muted bool
+	now   func() time.Time
+
+	mu       sync.Mutex
+	pending  []backend.SecurityNotice
+	reported map[string]bool
+}
+
+// NewNotifier returns a notifier keeping its state in path and printing to
+// out. A muted notifier drops every notice, e.g. during shell completion.
+func NewNotifier(path string, out io.Writer, muted bool) *Notifier {
+	return &Notifier{path: path, out: out, muted: muted, now: time.Now, reported: make(map[string]bool)}
+}
+
+// DefaultPath returns the notices file in the XDG state directory
+func DefaultPath() (string, error) {
+	stateDir := os.Getenv("XDG_STATE_HOME")
+	if stateDir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		stateDir = filepath.Join(home, ".local", "state")
+	}
+	return filepath.Join(stateDir, "gosynctasks", "notices.json"), nil
+}
+
+// Install makes n the handler of backend security notices
+func (n *Notifier) Install() {
+	backend.SetSecurityNoticeHandler(n.Report)
+}
+
+// Report queues a notice for the next Flush. Backends created several times
+// in a run report the same notice once.
+func (n *Notifier) Report(notice backend.SecurityNotice) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.muted || n.reported[notice.ID()] {
+		return
+	}
+	n.reported[notice.ID()] = true
+	n.pending = append(n.pending, notice)
+}
+
+// Flush prints the queued notices that are due: the full notice the first
+// time, a two-line reminder once a day after that, and nothing once acknowledged
+func (n *Notifier) Flush() {
+	n.mu.Lock()
+	pending := n.pending
+	n.pending = nil
+	n.mu.Unlock()
+	if len(pending) == 0 {
+		return
+	}
+
+	st := n.load()
+	now := n.now()
+	changed := false
+	for _, notice := range pending {
+		id := notice.ID()
+		if _, ok := st.Acknowledged[id]; ok {
+			continue
+		}
+		last, shown := st.Shown[id]
+		if shown && now.Sub(last) < Interval {
+			continue
+		}
+
+		if shown {
+			fmt.Fprintf(n.out, "⚠ %s: %s\n", notice.Backend, notice.Title)
+			fmt.Fprintf(n.out, "  Silence with: gosynctasks --acknowledge-insecure %s\n", id)
+		} else {
+			backend.WriteNoticeBox(n.out, notice)
+			fmt.Fprintf(n.out, "Shown once a day. Silence with: gosynctasks --acknowledge-insecure %s\n\n", id)
+		}
+		st.Shown[id] = now
+		changed = true
+	}
+	if changed {
+		_ = n.save(st)
+	}
+}
+
+// Acknowledge silences the notice with the given ID permanently
+func (n *Notifier) Acknowledge(id string) error {
+	st := n.load()
+	st.Acknowledged[id] = Acknowledgment{At: n.now()}
+	return n.save(st)
+}
+
+// load reads the state file; a missing or unreadable file is an empty state
+func (n *Notifier) load() *state {
+	st := &state{}
+	if n.path != "" {
+		if data, err := os.ReadFile(n.path); err == nil {
+			_ = json.Unmarshal(data, st)
+		}
+	}
+	if st.Shown == nil {
+		st.Shown = make(map[string]time.Time)
+	}
+	if st.Acknowledged == nil {
+		st.Acknowledged = make(map[string]Acknowledgment)
+	}
+	return st
+}
+
+func (n *Notifier) save(st *state) error {
+	if n.path == "" {
+		return nil
+	}
+	data, err := json.MarshalIndent(st, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(n.path), 0700); err != nil {
+		return err
+	}
+	return os.WriteFile(n.path, data, 0600)
+}
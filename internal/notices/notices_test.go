@@ -0,0 +1,70 @@
+package notices
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"gosynctasks/backend"
+)
+
+var tlsNotice = backend.SecurityNotice{Backend: "cloud", Kind: "tls", Title: "TLS certificate verification is DISABLED"}
+
+// run reports notice through a new notifier at now and returns what it printed
+func run(path string, now time.Time, muted bool, notices ...backend.SecurityNotice) string {
+	var out bytes.Buffer
+	n := NewNotifier(path, &out, muted)
+	n.now = func() time.Time { return now }
+	for _, notice := range notices {
+		n.Report(notice)
+	}
+	n.Flush()
+	return out.String()
+}
+
+func TestNoticeShownOncePerDay(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "notices.json")
+	day := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+
+	first := run(path, day, false, tlsNotice, tlsNotice)
+	if strings.Count(first, "SECURITY WARNING") != 1 {
+		t.Fatalf("first run printed %q, want one full notice", first)
+	}
+	if out := run(path, day.Add(time.Hour), false, tlsNotice); out != "" {
+		t.Errorf("run within a day printed %q", out)
+	}
+
+	reminder := run(path, day.Add(25*time.Hour), false, tlsNotice)
+	if strings.Contains(reminder, "╔") || strings.Count(reminder, "\n") != 2 {
+		t.Errorf("reminder = %q, want a two-line notice", reminder)
+	}
+	if !strings.Contains(reminder, "--acknowledge-insecure cloud:tls") {
+		t.Errorf("reminder = %q, want the acknowledge hint", reminder)
+	}
+}
+
+func TestNoticeMutedAndAcknowledged(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "notices.json")
+	now := time.Now()
+
+	if out := run(path, now, true, tlsNotice); out != "" {
+		t.Errorf("muted run printed %q", out)
+	}
+	// A muted run does not count as shown
+	if out := run(path, now, false, tlsNotice); !strings.Contains(out, "SECURITY WARNING") {
+		t.Errorf("first unmuted run printed %q, want the full notice", out)
+	}
+
+	if err := NewNotifier(path, nil, false).Acknowledge(tlsNotice.ID()); err != nil {
+		t.Fatalf("Acknowledge() error = %v", err)
+	}
+	if out := run(path, now.Add(48*time.Hour), false, tlsNotice); out != "" {
+		t.Errorf("acknowledged notice printed %q", out)
+	}
+	other := backend.SecurityNotice{Backend: "cloud", Kind: "http", Title: "HTTP"}
+	if out := run(path, now.Add(48*time.Hour), false, other); out == "" {
+		t.Error("acknowledging one notice silenced another")
+	}
+}
@@ -1,14 +1,19 @@
 package operations
 
 import (
+	"errors"
 	"fmt"
 	"gosynctasks/backend"
 	"gosynctasks/internal/cli"
 	"gosynctasks/internal/config"
+	internalSync "gosynctasks/internal/sync"
 	"gosynctasks/internal/utils"
 	"gosynctasks/internal/views"
 	"reflect"
+	"slices"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 )
@@ -19,6 +24,19 @@ type SyncCoordinatorProvider interface {
 	GetSyncCoordinator() interface{}
 }
 
+// joinSummaryArgs joins the words after "<list> <action>" into one summary
+// argument. An unquoted summary reaches us as several arguments, with any
+// quotes already removed by the shell, so the result is shown in a warning.
+func joinSummaryArgs(args []string) []string {
+	if len(args) <= 3 {
+		return args
+	}
+	summary := strings.Join(args[2:], " ")
+	utils.Warnf("Task summary was given as %d separate arguments and was joined as %q; quote it to keep quotes, $ and ` as typed",
+		len(args)-2, summary)
+	return []string{args[0], args[1], summary}
+}
+
 // ExecuteAction parses arguments and routes to the appropriate action handler
 func ExecuteAction(taskManager backend.TaskManager, cfg *config.Config, taskLists []backend.TaskList, cmd *cobra.Command, args []string, syncProvider SyncCoordinatorProvider) error {
 	var listName string
@@ -26,7 +44,14 @@ func ExecuteAction(taskManager backend.TaskManager, cfg *config.Config, taskList
 	var searchSummary string
 	action := "get"
 
-	// Argument order: <list> [action] [task-summary]
+	// Argument order: <list> [action] [task-summary], the list coming from
+	// --list instead when given
+	literalList, _ := cmd.Flags().GetString("list")
+	literal := literalList != ""
+	if literal {
+		args = append([]string{literalList}, args...)
+	}
+	args = joinSummaryArgs(args)
 	if len(args) >= 1 {
 		listName = args[0]
 	}
@@ -34,11 +59,12 @@ func ExecuteAction(taskManager backend.TaskManager, cfg *config.Config, taskList
 		action = args[1]
 	}
 	if len(args) >= 3 {
-		// For update/complete/delete: arg[2] is summary to search for
+		// For update/complete/delete/restore: arg[2] is summary to search for
 		// For add: arg[2] is task summary to create
 		if strings.ToLower(action) == "update" || strings.ToLower(action) == "u" ||
 			strings.ToLower(action) == "complete" || strings.ToLower(action) == "c" ||
-			strings.ToLower(action) == "delete" || strings.ToLower(action) == "d" {
+			strings.ToLower(action) == "delete" || strings.ToLower(action) == "d" ||
+			strings.ToLower(action) == "restore" {
 			searchSummary = args[2]
 		} else {
 			taskSummary = args[2]
@@ -48,18 +74,69 @@ func ExecuteAction(taskManager backend.TaskManager, cfg *config.Config, taskList
 	// Normalize action (support abbreviations)
 	action = NormalizeAction(action)
 
+	warnDuplicateLists(taskLists)
+
+	// The pseudo-list standing for every list, unless --list names a real one
+	if !literal {
+		warnAllListsCollision(cfg, taskLists)
+		if isAllLists(cfg, listName) {
+			var filter *backend.TaskFilter
+			if action == "get" {
+				var err error
+				if filter, err = buildGetFilter(cmd, taskManager, cfg); err != nil {
+					return err
+				}
+			}
+			return executeAllListsAction(cmd, taskManager, cfg, taskLists, action, searchSummary, filter, syncProvider)
+		}
+	}
+
 	selectedList, err := GetSelectedList(taskLists, taskManager, listName)
+	if err != nil && listName != "" && !errors.Is(err, errAmbiguousListName) {
+		// An archived list is left out of the task lists
+		if provider, ok := syncProvider.(ArchivedListProvider); ok {
+			archived, reader, archivedErr := resolveArchivedList(cmd, provider, listName, action)
+			if archivedErr != nil {
+				return archivedErr
+			}
+			if archived != nil {
+				selectedList, err = archived, nil
+				if reader != nil {
+					taskManager = reader
+				}
+			}
+		}
+	}
 	if err != nil {
-		return err
+		// A list created on the remote directly is not in the cache yet
+		adopter, ok := syncProvider.(ListAdopter)
+		if !ok || listName == "" || errors.Is(err, errAmbiguousListName) {
+			return err
+		}
+		adopted, adoptErr := adoptUnknownList(cfg, adopter, listName)
+		if adoptErr != nil {
+			return adoptErr
+		}
+		if adopted == nil {
+			return err
+		}
+		selectedList = adopted
 	}
 
-	filter, err := BuildFilter(cmd, taskManager)
-	if err != nil {
-		return err
+	// With --explain, writes are shown before they are sent
+	if explain, _ := cmd.Flags().GetBool("explain"); explain && isWriteAction(action) {
+		yes, _ := cmd.Flags().GetBool("yes")
+		taskManager = NewExplainingTaskManager(taskManager, cmd.OutOrStdout(), yes)
 	}
 
 	switch action {
 	case "get":
+		// Filters, --status and --priority among them, only apply to get: other
+		// actions give them values to set
+		filter, err := buildGetFilter(cmd, taskManager, cfg)
+		if err != nil {
+			return err
+		}
 		return HandleGetAction(cmd, taskManager, cfg, selectedList, filter, syncProvider)
 
 	case "add":
@@ -74,28 +151,46 @@ func ExecuteAction(taskManager backend.TaskManager, cfg *config.Config, taskList
 	case "delete":
 		return HandleDeleteAction(cmd, taskManager, cfg, selectedList, searchSummary, syncProvider)
 
+	case "trash":
+		return HandleTrashAction(taskManager, selectedList, cfg.GetDateFormat())
+
+	case "restore":
+		return HandleRestoreAction(taskManager, selectedList, searchSummary, syncProvider)
+
+	case "sync":
+		return HandleSyncAction(cmd, selectedList, syncProvider)
+
+	case "export":
+		return HandleExportAction(cmd, taskManager, cfg, selectedList)
+
+	case "import":
+		return HandleImportAction(cmd, taskManager, selectedList, taskSummary)
+
 	default:
-		return fmt.Errorf("unknown action: %s (supported: get/g, add/a, update/u, complete/c, delete/d)", action)
+		return unknownActionError(action)
 	}
 }
 
+// unknownActionError is the error for an action ExecuteAction does not know
+func unknownActionError(action string) error {
+	return fmt.Errorf("unknown action: %s (supported: %s)", action, supportedActions())
+}
+
+// isWriteAction reports whether action changes tasks through the task manager
+func isWriteAction(action string) bool {
+	switch action {
+	case "add", "update", "complete", "delete":
+		return true
+	}
+	return false
+}
+
 // NormalizeAction converts action abbreviations to full action names
 func NormalizeAction(action string) string {
-	action = strings.ToLower(action)
-	switch action {
-	case "g":
-		return "get"
-	case "a":
-		return "add"
-	case "u":
-		return "update"
-	case "c":
-		return "complete"
-	case "d":
-		return "delete"
-	default:
-		return action
+	if spec, ok := LookupAction(action); ok {
+		return spec.Name
 	}
+	return strings.ToLower(action)
 }
 
 // HandleGetAction lists tasks from a task list
@@ -110,28 +205,169 @@ func HandleGetAction(cmd *cobra.Command, taskManager backend.TaskManager, cfg *c
 		}
 	}
 
-	tasks, err := taskManager.GetTasks(selectedList.ID, filter)
+	// Get optional flags (errors ignored as flags are always defined by the command)
+	expand, _ := cmd.Flags().GetString("expand")
+	fields, err := fieldsFlag(cmd)
+	if err != nil {
+		return err
+	}
+	jsonOutput, _ := cmd.Flags().GetBool("json")
+
+	var tasks []backend.Task
+	if expand != "" {
+		// Only the subtree of one task, fetched level by level
+		var root *backend.Task
+		root, err = NewTaskSelector(taskManager, cfg).Select(selectedList.ID, expand, DefaultOptions())
+		if err != nil {
+			return err
+		}
+		tasks, err = FetchSubtree(taskManager, selectedList.ID, *root, filter)
+	} else {
+		tasks, err = taskManager.GetTasks(selectedList.ID, filter)
+	}
 	if err != nil {
 		return fmt.Errorf("error retrieving tasks: %w", err)
 	}
+	tasks = markDuplicateUIDs(tasks, selectedList.Name)
 
 	// Sort using backend-specific sorting
 	taskManager.SortTasks(tasks)
 
-	// Get optional flags (errors ignored as flags are always defined by the command)
+	// Tasks whose start date passed show as started under auto_start; the
+	// updates are made once the list is shown
+	startDue := prepareAutoStart(cfg, taskManager, selectedList, tasks)
+	defer func() {
+		if startDue() > 0 {
+			triggerPushSync(syncProvider, selectedList.ID)
+		}
+	}()
+
+	// Machine-readable output: the tasks as JSON objects, no list header
+	if jsonOutput {
+		data, err := marshalTasksJSON(tasks, fields)
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	return printListTasks(cmd, taskManager, cfg, selectedList, withLocalNotes(cfg, tasks), fields)
+}
+
+// dueFlag returns the due date given with --due, or --due-date, and whether
+// one was given; nil with changed set clears the due date
+func dueFlag(cmd *cobra.Command) (dueDate *time.Time, changed bool, err error) {
+	for _, name := range []string{"due", "due-date"} {
+		if flag := cmd.Flags().Lookup(name); flag != nil && flag.Changed {
+			dueDate, err = cli.ParseDue(flag.Value.String(), time.Now())
+			return dueDate, true, err
+		}
+	}
+	return nil, false, nil
+}
+
+// allDayDates reports whether a task with these due and start dates is an
+// all-day one: dates given without a time of day are at local midnight
+func allDayDates(dates ...*time.Time) bool {
+	allDay := false
+	for _, date := range dates {
+		if date == nil {
+			continue
+		}
+		local := date.In(time.Local)
+		if local.Hour() != 0 || local.Minute() != 0 || local.Second() != 0 {
+			return false
+		}
+		allDay = true
+	}
+	return allDay
+}
+
+// repeatFlag returns the recurrence rule given with --repeat, and whether one
+// was given; an empty rule with changed set removes the recurrence
+func repeatFlag(cmd *cobra.Command) (rule string, changed bool, err error) {
+	flag := cmd.Flags().Lookup("repeat")
+	if flag == nil || !flag.Changed {
+		return "", false, nil
+	}
+	rule, err = backend.NormalizeRecurrence(flag.Value.String())
+	if err != nil {
+		return "", true, fmt.Errorf("invalid --repeat: %w", err)
+	}
+	return rule, true, nil
+}
+
+// priorityFlag returns the priority given with --priority for add and
+// update, 0 when not given; the range is checked by ValidateTask
+func priorityFlag(cmd *cobra.Command) (int, error) {
+	flag := cmd.Flags().Lookup("priority")
+	if flag == nil || !flag.Changed {
+		return 0, nil
+	}
+	priority, err := strconv.Atoi(strings.TrimSpace(flag.Value.String()))
+	if err != nil {
+		return 0, fmt.Errorf("invalid priority: %s (a number, 0-9: 0=undefined, 1=highest, 9=lowest)", flag.Value)
+	}
+	return priority, nil
+}
+
+// fieldsFlag returns the fields given with --fields, or nil when not given
+func fieldsFlag(cmd *cobra.Command) ([]string, error) {
+	fieldsSpec, _ := cmd.Flags().GetString("fields")
+	if fieldsSpec == "" {
+		return nil, nil
+	}
+	return parseFieldsFlag(fieldsSpec)
+}
+
+// printListTasks renders the sorted tasks of a list as the output flags ask:
+// templated lines, or the list header with the tasks in a view or as a tree
+func printListTasks(cmd *cobra.Command, taskManager backend.TaskManager, cfg *config.Config, selectedList *backend.TaskList, tasks []backend.Task, fields []string) error {
 	viewName, _ := cmd.Flags().GetString("view")
+	formatTemplate, _ := cmd.Flags().GetString("format-template")
+	collapse, _ := cmd.Flags().GetBool("collapse")
 	dateFormat := cfg.GetDateFormat()
 	termWidth := cli.GetTerminalWidth()
 
-	// Try to use custom view rendering first
-	// Note: Custom views currently don't support hierarchical display
-	// This will be added in a future enhancement
-	rendered, err := RenderWithCustomView(tasks, viewName, taskManager, dateFormat)
+	// Scriptable output: one templated line per task, no list header
+	if formatTemplate != "" {
+		text, err := resolveFormatTemplate(cfg, formatTemplate)
+		if err != nil {
+			return err
+		}
+		tmpl, err := views.ParseFormatTemplate(text)
+		if err != nil {
+			return err
+		}
+		tree := BuildTaskTree(tasks)
+		if collapse {
+			CollapseTaskTree(tree)
+		}
+		rendered, err := RenderWithFormatTemplate(tree, tmpl, taskManager, selectedList.Name)
+		if err != nil {
+			return err
+		}
+		fmt.Print(rendered)
+		rememberListing(selectedList, tree)
+		return nil
+	}
+
+	// Try to use custom view rendering first, --fields being a view of its own
+	var view *views.View
+	var err error
+	if fields != nil {
+		view = fieldsView(fields)
+	} else {
+		view, err = views.ResolveView(viewName)
+	}
 	if err == nil {
-		// Custom view found and rendered successfully
+		// Custom view found
+		tree, renderer := viewTree(tasks, view, taskManager, dateFormat, cfg.NoColorSemantics(), collapse)
 		fmt.Print(selectedList.StringWithWidthAndBackend(termWidth, taskManager))
-		fmt.Print(rendered)
+		fmt.Print(RenderTaskTreeWithCustomView(tree, renderer))
 		fmt.Print(selectedList.BottomBorderWithWidth(termWidth))
+		rememberListing(selectedList, tree)
 		return nil
 	}
 
@@ -140,12 +376,16 @@ func HandleGetAction(cmd *cobra.Command, taskManager backend.TaskManager, cfg *c
 
 	// Build task tree
 	tree := BuildTaskTree(tasks)
+	if collapse {
+		CollapseTaskTree(tree)
+	}
 
 	// Format and display tree
 	treeOutput := FormatTaskTree(tree, viewName, taskManager, dateFormat)
 	fmt.Print(treeOutput)
 
 	fmt.Print(selectedList.BottomBorderWithWidth(termWidth))
+	rememberListing(selectedList, tree)
 	return nil
 }
 
@@ -167,9 +407,7 @@ func HandleAddAction(cmd *cobra.Command, taskManager backend.TaskManager, select
 
 	// Get optional flags (errors ignored as flags are always defined by the command)
 	description, _ := cmd.Flags().GetString("description")
-	priority, _ := cmd.Flags().GetInt("priority")
 	statusFlag, _ := cmd.Flags().GetString("add-status")
-	dueDateStr, _ := cmd.Flags().GetString("due-date")
 	startDateStr, _ := cmd.Flags().GetString("start-date")
 	parentRef, _ := cmd.Flags().GetString("parent")
 	literal, _ := cmd.Flags().GetBool("literal")
@@ -186,13 +424,13 @@ func HandleAddAction(cmd *cobra.Command, taskManager backend.TaskManager, select
 		return err
 	}
 
-	// Validate priority
-	if err := utils.ValidatePriority(priority); err != nil {
+	priority, err := priorityFlag(cmd)
+	if err != nil {
 		return err
 	}
 
-	// Parse and validate dates
-	dueDate, err := utils.ParseDateFlag(dueDateStr)
+	// Parse dates
+	dueDate, _, err := dueFlag(cmd)
 	if err != nil {
 		return err
 	}
@@ -202,11 +440,35 @@ func HandleAddAction(cmd *cobra.Command, taskManager backend.TaskManager, select
 		return err
 	}
 
-	if err := utils.ValidateDates(startDate, dueDate); err != nil {
+	tagValues, _ := cmd.Flags().GetStringArray("tag")
+	categories, err := EditTags(nil, tagValues)
+	if err != nil {
 		return err
 	}
 
+	recurrence, _, err := repeatFlag(cmd)
+	if err != nil {
+		return err
+	}
+
+	task := backend.Task{
+		Summary:     taskSummary,
+		Description: description,
+		Status:      taskStatus,
+		Priority:    priority,
+		DueDate:     dueDate,
+		StartDate:   startDate,
+		Categories:  categories,
+		Recurrence:  recurrence,
+		AllDay:      allDayDates(dueDate, startDate),
+	}
+
+	// Refuse invalid values before any parent of a path is created
 	cfg := config.GetConfig()
+	if err := ValidateTask(taskManager, cfg, task); err != nil {
+		return err
+	}
+
 	var parentUID string
 	var actualTaskName string
 
@@ -231,15 +493,8 @@ func HandleAddAction(cmd *cobra.Command, taskManager backend.TaskManager, select
 		actualTaskName = taskSummary
 	}
 
-	task := backend.Task{
-		Summary:     actualTaskName,
-		Description: description,
-		Status:      taskStatus,
-		Priority:    priority,
-		DueDate:     dueDate,
-		StartDate:   startDate,
-		ParentUID:   parentUID,
-	}
+	task.Summary = actualTaskName
+	task.ParentUID = parentUID
 
 	if _, err := taskManager.AddTask(selectedList.ID, task); err != nil {
 		return fmt.Errorf("error adding task: %w", err)
@@ -248,7 +503,7 @@ func HandleAddAction(cmd *cobra.Command, taskManager backend.TaskManager, select
 	fmt.Printf("Task '%s' added successfully to list '%s'\n", actualTaskName, selectedList.Name)
 
 	// Trigger background push sync
-	triggerPushSync(syncProvider)
+	triggerPushSync(syncProvider, selectedList.ID)
 
 	return nil
 }
@@ -277,13 +532,16 @@ func HandleUpdateAction(cmd *cobra.Command, taskManager backend.TaskManager, cfg
 	if err != nil {
 		return err
 	}
+	if selector.Prompted() {
+		if taskToUpdate, err = recheckTask(taskManager, selectedList.ID, taskToUpdate); err != nil {
+			return err
+		}
+	}
 
 	// Get update flags (errors ignored as flags are always defined by the command)
 	statusFlags, _ := cmd.Flags().GetStringArray("status")
 	description, _ := cmd.Flags().GetString("description")
-	priority, _ := cmd.Flags().GetInt("priority")
 	summaryFlag, _ := cmd.Flags().GetString("summary")
-	dueDateStr, _ := cmd.Flags().GetString("due-date")
 	startDateStr, _ := cmd.Flags().GetString("start-date")
 
 	// Update fields if provided
@@ -305,19 +563,20 @@ func HandleUpdateAction(cmd *cobra.Command, taskManager backend.TaskManager, cfg
 	}
 
 	if cmd.Flags().Changed("priority") {
-		if err := utils.ValidatePriority(priority); err != nil {
+		priority, err := priorityFlag(cmd)
+		if err != nil {
 			return err
 		}
 		taskToUpdate.Priority = priority
 	}
 
 	// Parse and update dates if changed
-	if cmd.Flags().Changed("due-date") {
-		dueDate, err := utils.ParseDateFlag(dueDateStr)
-		if err != nil {
-			return err
-		}
+	datesChanged := false
+	if dueDate, changed, err := dueFlag(cmd); err != nil {
+		return err
+	} else if changed {
 		taskToUpdate.DueDate = dueDate
+		datesChanged = true
 	}
 
 	if cmd.Flags().Changed("start-date") {
@@ -326,14 +585,34 @@ func HandleUpdateAction(cmd *cobra.Command, taskManager backend.TaskManager, cfg
 			return err
 		}
 		taskToUpdate.StartDate = startDate
+		datesChanged = true
+	}
+	if datesChanged {
+		taskToUpdate.AllDay = allDayDates(taskToUpdate.DueDate, taskToUpdate.StartDate)
+	}
+
+	if cmd.Flags().Changed("tag") {
+		tagValues, _ := cmd.Flags().GetStringArray("tag")
+		if taskToUpdate.Categories, err = EditTags(taskToUpdate.Categories, tagValues); err != nil {
+			return err
+		}
+	}
+
+	if recurrence, changed, err := repeatFlag(cmd); err != nil {
+		return err
+	} else if changed {
+		taskToUpdate.Recurrence = recurrence
 	}
 
-	// Validate dates (after all updates applied)
-	if err := utils.ValidateDates(taskToUpdate.StartDate, taskToUpdate.DueDate); err != nil {
+	// Validate the task as it will be written
+	if err := ValidateTask(taskManager, cfg, *taskToUpdate); err != nil {
 		return err
 	}
 
 	// Update the task
+	if err := requireUniqueUID(taskManager, "UpdateTask", selectedList.ID, taskToUpdate.UID); err != nil {
+		return err
+	}
 	if err := taskManager.UpdateTask(selectedList.ID, *taskToUpdate); err != nil {
 		return fmt.Errorf("error updating task: %w", err)
 	}
@@ -341,7 +620,7 @@ func HandleUpdateAction(cmd *cobra.Command, taskManager backend.TaskManager, cfg
 	fmt.Printf("Task '%s' updated successfully in list '%s'\n", taskToUpdate.Summary, selectedList.Name)
 
 	// Trigger background push sync
-	triggerPushSync(syncProvider)
+	triggerPushSync(syncProvider, selectedList.ID)
 
 	return nil
 }
@@ -354,15 +633,9 @@ func HandleCompleteAction(cmd *cobra.Command, taskManager backend.TaskManager, c
 	// Create task selector
 	selector := NewTaskSelector(taskManager, cfg)
 
-	// Exclude tasks that are already completed or cancelled
-	excludeStatuses := []string{"DONE", "COMPLETED", "CANCELLED"}
-	filter := &backend.TaskFilter{
-		ExcludeStatuses: &excludeStatuses,
-	}
-
 	// Configure selection options
 	opts := DefaultOptions()
-	opts.Filter = filter
+	opts.Filter = openTasksFilter()
 	opts.CancelText = "cancel"
 
 	// If no search summary provided, show interactive tree selection
@@ -376,6 +649,11 @@ func HandleCompleteAction(cmd *cobra.Command, taskManager backend.TaskManager, c
 	if err != nil {
 		return err
 	}
+	if selector.Prompted() {
+		if taskToComplete, err = recheckTask(taskManager, selectedList.ID, taskToComplete); err != nil {
+			return err
+		}
+	}
 
 	// Get status flag (errors ignored as flags are always defined by the command)
 	// If provided, use it; otherwise default to DONE
@@ -394,22 +672,96 @@ func HandleCompleteAction(cmd *cobra.Command, taskManager backend.TaskManager, c
 	// Get display name for user feedback
 	statusName := taskManager.StatusToDisplayName(newStatus)
 
-	// Set the new status
+	// With --reason, a timestamped closing note goes with the new status
+	saveNote := func() error { return nil }
+	if reason, _ := cmd.Flags().GetString("reason"); strings.TrimSpace(reason) != "" {
+		note, err := closingNote(newStatus, reason, time.Now())
+		if err != nil {
+			return err
+		}
+		if saveNote, err = recordReason(cfg, taskManager, selectedList.ID, taskToComplete, note); err != nil {
+			return err
+		}
+	}
+
+	// Set the new status; a recurring task that is done moves to its next
+	// occurrence instead, until its rule has none left
 	taskToComplete.Status = newStatus
+	recurs := false
+	if taskToComplete.Recurrence != "" && backend.IsCompletedStatus(newStatus) {
+		next, ok, err := taskToComplete.NextOccurrence(time.Now())
+		if err != nil {
+			return fmt.Errorf("cannot find the next occurrence of '%s' (remove its recurrence with update --repeat none): %w", taskToComplete.Summary, err)
+		}
+		if ok {
+			if next.Status, err = taskManager.ParseStatusFlag("TODO"); err != nil {
+				return err
+			}
+			*taskToComplete = next
+			recurs = true
+		}
+	}
 
 	// Update the task
+	if err := requireUniqueUID(taskManager, "UpdateTask", selectedList.ID, taskToComplete.UID); err != nil {
+		return err
+	}
 	if err := taskManager.UpdateTask(selectedList.ID, *taskToComplete); err != nil {
 		return fmt.Errorf("error updating task: %w", err)
 	}
+	if err := saveNote(); err != nil {
+		utils.Warnf("the reason was not kept: %v", err)
+	}
+
+	if recurs {
+		fmt.Printf("Task '%s' marked as %s in list '%s'; next occurrence %s\n", taskToComplete.Summary, statusName, selectedList.Name, nextOccurrenceDate(taskToComplete, cfg.GetDateFormat()))
+	} else {
+		fmt.Printf("Task '%s' marked as %s in list '%s'\n", taskToComplete.Summary, statusName, selectedList.Name)
+	}
 
-	fmt.Printf("Task '%s' marked as %s in list '%s'\n", taskToComplete.Summary, statusName, selectedList.Name)
+	// With --with-children, open subtasks get the same status
+	withChildren, _ := cmd.Flags().GetBool("with-children")
+	if withChildren {
+		if err := cascadeStatus(taskManager, selectedList.ID, taskToComplete, newStatus); err != nil {
+			// Push the updates that were made before failing
+			triggerPushSync(syncProvider, selectedList.ID)
+			return err
+		}
+	}
+
+	// Keep the hierarchy consistent: offer to close open subtasks and completed parents
+	if isClosedStatus(newStatus) && !recurs {
+		if !withChildren {
+			if err := completeOpenChildren(taskManager, selectedList.ID, taskToComplete, newStatus); err != nil {
+				utils.Warnf("%v", err)
+			}
+		}
+		if err := completeParentChain(taskManager, cfg, selectedList.ID, taskToComplete); err != nil {
+			utils.Warnf("%v", err)
+		}
+	}
 
 	// Trigger background push sync
-	triggerPushSync(syncProvider)
+	triggerPushSync(syncProvider, selectedList.ID)
 
 	return nil
 }
 
+// nextOccurrenceDate describes when the next occurrence of a recurring task
+// is, by its start date or else its due date
+func nextOccurrenceDate(task *backend.Task, dateFormat string) string {
+	if task.StartDate != nil {
+		return "starts " + task.StartDate.Format(dateFormat)
+	}
+	return "due " + task.DueDate.Format(dateFormat)
+}
+
+// openTasksFilter leaves out tasks that are already completed or cancelled
+func openTasksFilter() *backend.TaskFilter {
+	excludeStatuses := []string{"DONE", "COMPLETED", "CANCELLED"}
+	return &backend.TaskFilter{ExcludeStatuses: &excludeStatuses}
+}
+
 // HandleDeleteAction deletes a task by summary
 func HandleDeleteAction(cmd *cobra.Command, taskManager backend.TaskManager, cfg *config.Config, selectedList *backend.TaskList, searchSummary string, syncProvider SyncCoordinatorProvider) error {
 	var taskToDelete *backend.Task
@@ -435,25 +787,40 @@ func HandleDeleteAction(cmd *cobra.Command, taskManager backend.TaskManager, cfg
 		return err
 	}
 
-	// Show a final confirmation before deletion
-	fmt.Println()
-	confirmed, err := utils.PromptConfirmation(fmt.Sprintf("Are you sure you want to delete task '%s'? This action cannot be undone.", taskToDelete.Summary))
-	if err != nil {
+	if err := requireUniqueUID(taskManager, "DeleteTask", selectedList.ID, taskToDelete.UID); err != nil {
 		return err
 	}
-	if !confirmed {
-		return fmt.Errorf("deletion cancelled")
+
+	// Show a final confirmation before deletion, unless --force is given for scripts
+	force, _ := cmd.Flags().GetBool("force")
+	if !force {
+		fmt.Println()
+		confirmed, err := utils.PromptConfirmation(fmt.Sprintf("Are you sure you want to delete task '%s'? This action cannot be undone.", taskToDelete.Summary))
+		if err != nil {
+			return err
+		}
+		if !confirmed {
+			return fmt.Errorf("deletion cancelled")
+		}
+		if taskToDelete, err = recheckTask(taskManager, selectedList.ID, taskToDelete); err != nil {
+			return err
+		}
 	}
 
-	// Delete the task
-	if err := taskManager.DeleteTask(selectedList.ID, taskToDelete.UID); err != nil {
+	// Delete the task; --force also skips the checks the backend makes on
+	// the resource before deleting it
+	deleteTask := taskManager.DeleteTask
+	if forceDeleter, ok := backend.As[backend.ForceDeleter](taskManager); ok && force {
+		deleteTask = forceDeleter.ForceDeleteTask
+	}
+	if err := deleteTask(selectedList.ID, taskToDelete.UID); err != nil {
 		return fmt.Errorf("error deleting task: %w", err)
 	}
 
 	fmt.Printf("Task '%s' deleted successfully from list '%s'\n", taskToDelete.Summary, selectedList.Name)
 
 	// Trigger background push sync
-	triggerPushSync(syncProvider)
+	triggerPushSync(syncProvider, selectedList.ID)
 
 	return nil
 }
@@ -461,15 +828,27 @@ func HandleDeleteAction(cmd *cobra.Command, taskManager backend.TaskManager, cfg
 // RenderWithCustomView attempts to render tasks using a custom view
 // Returns the rendered output or an error if the view cannot be loaded
 // This version supports hierarchical display with tree structure
-func RenderWithCustomView(tasks []backend.Task, viewName string, taskManager backend.TaskManager, dateFormat string) (string, error) {
+func RenderWithCustomView(tasks []backend.Task, viewName string, taskManager backend.TaskManager, dateFormat string, noColorSemantics bool, collapse bool) (string, error) {
 	// Try to resolve the view
 	view, err := views.ResolveView(viewName)
 	if err != nil {
 		return "", err
 	}
+	return RenderWithView(tasks, view, taskManager, dateFormat, noColorSemantics, collapse), nil
+}
+
+// RenderWithView formats tasks using the given view
+func RenderWithView(tasks []backend.Task, view *views.View, taskManager backend.TaskManager, dateFormat string, noColorSemantics bool, collapse bool) string {
+	tree, renderer := viewTree(tasks, view, taskManager, dateFormat, noColorSemantics, collapse)
+	return RenderTaskTreeWithCustomView(tree, renderer)
+}
 
+// viewTree returns the tree of the tasks a view shows, filtered and sorted
+// as the view says, with the renderer to format it
+func viewTree(tasks []backend.Task, view *views.View, taskManager backend.TaskManager, dateFormat string, noColorSemantics bool, collapse bool) ([]*TaskNode, *views.ViewRenderer) {
 	// Create renderer
 	renderer := views.NewViewRenderer(view, taskManager, dateFormat)
+	renderer.SetNoColorSemantics(noColorSemantics)
 
 	// Apply view-specific filters
 	filteredTasks := tasks
@@ -480,6 +859,9 @@ func RenderWithCustomView(tasks []backend.Task, viewName string, taskManager bac
 	// Build task tree BEFORE sorting
 	// This preserves parent-child relationships
 	tree := BuildTaskTree(filteredTasks)
+	if collapse {
+		CollapseTaskTree(tree)
+	}
 
 	// Apply view-specific sorting hierarchically
 	// This sorts root tasks and recursively sorts children within each parent
@@ -487,9 +869,7 @@ func RenderWithCustomView(tasks []backend.Task, viewName string, taskManager bac
 	if sortBy != "" {
 		SortTaskTree(tree, sortBy, sortOrder)
 	}
-
-	// Render tasks with hierarchy
-	return RenderTaskTreeWithCustomView(tree, renderer), nil
+	return tree, renderer
 }
 
 // RenderTaskTreeWithCustomView formats a task tree using a custom view renderer
@@ -530,6 +910,9 @@ func formatNodeWithCustomView(result *strings.Builder, nodes []*TaskNode, prefix
 		if len(node.Children) > 0 {
 			taskOutput = addParentIndicator(taskOutput, len(node.Children))
 		}
+		if node.Hidden > 0 {
+			taskOutput = addCollapsedIndicator(taskOutput, node.Hidden)
+		}
 
 		// Apply hierarchical formatting with tree prefix
 		taskOutput = applyHierarchicalFormatting(taskOutput, nodePrefix, childPrefix)
@@ -562,14 +945,65 @@ func applyHierarchicalFormatting(taskOutput, nodePrefix, childPrefix string) str
 	return result.String()
 }
 
-// triggerPushSync spawns a detached background process to sync
-func triggerPushSync(syncProvider SyncCoordinatorProvider) {
-	// Check if sync is enabled in config
+// bulkUpdate is a task a bulk command such as replace or tag changes, with
+// the error that keeps it from being written, if any
+type bulkUpdate struct {
+	listID string
+	task   backend.Task
+	err    error
+}
+
+// applyBulkUpdates updates each task through the task manager, so that
+// sync-enabled backends queue the changes, and returns the error of each
+// update in order; an update that already has one is not written. The lists
+// with an updated task are pushed.
+func applyBulkUpdates(taskManager backend.TaskManager, updates []bulkUpdate, syncProvider SyncCoordinatorProvider) []error {
+	errs := make([]error, len(updates))
+	var updatedLists []string // Lists with an updated task, for the push
+
+	for i, update := range updates {
+		if update.err != nil {
+			errs[i] = update.err
+			continue
+		}
+		errs[i] = taskManager.UpdateTask(update.listID, update.task)
+		if errs[i] == nil && !slices.Contains(updatedLists, update.listID) {
+			updatedLists = append(updatedLists, update.listID)
+		}
+	}
+
+	if len(updatedLists) > 0 && syncProvider != nil {
+		triggerPushSync(syncProvider, updatedLists...)
+	}
+	return errs
+}
+
+// triggerPushSync records a write to the given lists for the after-write
+// sync (every list when none is given) and starts its detached runner unless
+// one is already running. The command exits without waiting for the sync.
+func triggerPushSync(syncProvider SyncCoordinatorProvider, listIDs ...string) {
+	if heldSync != nil {
+		heldSync.writes++
+		heldSync.listIDs = append(heldSync.listIDs, listIDs...)
+		heldSync.all = heldSync.all || len(listIDs) == 0
+		return
+	}
+
 	cfg := config.GetConfig()
-	if cfg.Sync == nil || !cfg.Sync.Enabled || !cfg.Sync.AutoSync {
+	mode := cfg.GetAfterWrite()
+	if mode == config.AfterWriteOff {
 		return // Auto-sync not enabled
 	}
 
+	statusPath, err := internalSync.StatusPath()
+	if err != nil {
+		return // Silent fail - will sync on next operation
+	}
+	spawn, err := internalSync.RequestAfterWrite(statusPath, listIDs, mode)
+	if err != nil || !spawn {
+		return // A running sync picks the write up
+	}
+
 	// Get config path to pass to spawned process
 	configPath, err := config.GetConfigPath()
 	if err != nil {
@@ -578,10 +1012,42 @@ func triggerPushSync(syncProvider SyncCoordinatorProvider) {
 
 	// Spawn detached background process to run sync
 	// This process will outlive the parent CLI
-	// Note: spawnBackgroundSync will detect if we're in a test binary and skip spawning
-	spawnBackgroundSync(configPath)
+	startAfterWriteSync(configPath)
+}
+
+// heldPushSync collects the writes made while syncs are held
+type heldPushSync struct {
+	writes  int
+	listIDs []string
+	all     bool // A write named no list
 }
 
+// heldSync is set while HoldPushSync defers syncs
+var heldSync *heldPushSync
+
+// HoldPushSync defers the syncs writes trigger until the returned release
+// is called, which then triggers one sync for everything written meanwhile
+func HoldPushSync() (release func()) {
+	held := &heldPushSync{}
+	heldSync = held
+	return func() {
+		if heldSync != held {
+			return
+		}
+		heldSync = nil
+		switch {
+		case held.writes == 0:
+		case held.all:
+			triggerPushSync(nil)
+		default:
+			triggerPushSync(nil, held.listIDs...)
+		}
+	}
+}
+
+// startAfterWriteSync starts the after-write sync runner; tests replace it
+var startAfterWriteSync = spawnBackgroundSync
+
 // spawnBackgroundSync is implemented in platform-specific files:
 // - actions_unix.go for Unix/Linux/macOS
 // - actions_windows.go for Windows
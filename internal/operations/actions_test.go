@@ -0,0 +1,236 @@
+package operations
+
+import (
+	"gosynctasks/backend"
+	"gosynctasks/internal/cli"
+	"gosynctasks/internal/config"
+	internalSync "gosynctasks/internal/sync"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// newActionCmd returns a command with the flags ExecuteAction reads
+func newActionCmd() *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Flags().StringArrayP("status", "s", []string{}, "")
+	cmd.Flags().StringP("description", "d", "", "")
+	cmd.Flags().StringP("priority", "p", "", "")
+	cmd.Flags().StringP("add-status", "S", "", "")
+	cmd.Flags().String("summary", "", "")
+	cmd.Flags().String("due", "", "")
+	cmd.Flags().StringArray("tag", []string{}, "")
+	cmd.Flags().String("due-date", "", "")
+	cmd.Flags().String("start-date", "", "")
+	cmd.Flags().String("repeat", "", "")
+	cmd.Flags().String("modified-since", "", "")
+	cmd.Flags().String("completed-since", "", "")
+	cmd.Flags().Bool("overdue", false, "")
+	cmd.Flags().Bool("include-archived", false, "")
+	cmd.Flags().StringP("parent", "P", "", "")
+	cmd.Flags().BoolP("literal", "l", false, "")
+	cmd.Flags().String("format", "ics", "")
+	cmd.Flags().StringP("output", "o", "", "")
+	cmd.Flags().String("delimiter", ",", "")
+	cmd.Flags().Bool("include-descriptions", false, "")
+	cmd.Flags().Bool("skip-existing", false, "")
+	cmd.Flags().Bool("overwrite", false, "")
+	return cmd
+}
+
+// TestSpecialCharacterSummaryRoundTrip adds tasks whose summaries contain
+// shell-special characters, completes them through the value shell completion
+// offers for a quoted prefix, and checks the same task is marked done.
+func TestSpecialCharacterSummaryRoundTrip(t *testing.T) {
+	config.SetConfigForTest(&config.Config{})
+
+	tests := []struct {
+		summary string
+		typed   string // Prefix as typed at the shell before pressing TAB
+	}{
+		{`Fix the "foo" bug`, `"Fix the \"foo`},
+		{"Don't panic", `'Don'\''t`},
+		{"Pay $100 rent", `Pay\ \$1`},
+		{"Run `make test`", "\"Run \\`make"},
+		{"Read “Dune” again", "Read\\ “Dune"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.summary, func(t *testing.T) {
+			mb := backend.NewMockBackend()
+			lists := []backend.TaskList{{ID: "list-1", Name: "Chores"}}
+
+			if err := ExecuteAction(mb, &config.Config{}, lists, newActionCmd(), []string{"Chores", "add", tt.summary}, nil); err != nil {
+				t.Fatalf("add failed: %v", err)
+			}
+			if got := mb.Tasks["list-1"]; len(got) != 1 || got[0].Summary != tt.summary {
+				t.Fatalf("added tasks = %+v, want one with summary %q", got, tt.summary)
+			}
+
+			candidates, _ := cli.SmartCompletion(lists, mb)(&cobra.Command{}, []string{"Chores", "complete"}, tt.typed)
+			if len(candidates) != 1 || candidates[0] != tt.summary {
+				t.Fatalf("completion for %q = %q, want [%q]", tt.typed, candidates, tt.summary)
+			}
+
+			if err := ExecuteAction(mb, &config.Config{}, lists, newActionCmd(), []string{"Chores", "complete", candidates[0]}, nil); err != nil {
+				t.Fatalf("complete failed: %v", err)
+			}
+			if status := mb.Tasks["list-1"][0].Status; status != "COMPLETED" {
+				t.Errorf("status = %q, want COMPLETED", status)
+			}
+		})
+	}
+}
+
+func TestUnquotedSummaryArgsAreJoined(t *testing.T) {
+	config.SetConfigForTest(&config.Config{})
+
+	mb := backend.NewMockBackend()
+	lists := []backend.TaskList{{ID: "list-1", Name: "Chores"}}
+
+	// gst Chores add Fix the "foo" bug: the shell drops the quotes and splits the words
+	args := []string{"Chores", "add", "Fix", "the", "foo", "bug"}
+	if err := ExecuteAction(mb, &config.Config{}, lists, newActionCmd(), args, nil); err != nil {
+		t.Fatalf("add failed: %v", err)
+	}
+	if got := mb.Tasks["list-1"]; len(got) != 1 || got[0].Summary != "Fix the foo bug" {
+		t.Errorf("added tasks = %+v, want one with summary %q", got, "Fix the foo bug")
+	}
+}
+
+// TestAfterWriteSyncDebounced adds three tasks in quick succession with a
+// slow remote: each add returns before the push completes, and the three
+// writes are pushed in a single run of the written list
+func TestAfterWriteSyncDebounced(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	config.SetConfigForTest(&config.Config{Sync: &config.SyncConfig{Enabled: true, AutoSync: true}})
+	defer config.SetConfigForTest(&config.Config{})
+	statusPath, err := internalSync.StatusPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	release := make(chan struct{})
+	finished := make(chan error, 1)
+	var runs [][]string
+	spawned := 0
+	defer func(start func(string)) { startAfterWriteSync = start }(startAfterWriteSync)
+	startAfterWriteSync = func(string) {
+		spawned++
+		go func() {
+			finished <- internalSync.RunAfterWrite(statusPath, 100*time.Millisecond, func(mode string, lists []string) internalSync.RunOutcome {
+				<-release // The remote answers slowly
+				runs = append(runs, lists)
+				return internalSync.RunOutcome{Mode: mode, Lists: lists}
+			})
+		}()
+	}
+
+	tm := backend.NewMockBackend()
+	list := &backend.TaskList{ID: "list-1", Name: "Chores"}
+	for _, summary := range []string{"Buy milk", "Call Bob", "Water plants"} {
+		if err := HandleAddAction(newActionCmd(), tm, list, summary, nil); err != nil {
+			t.Fatalf("add %q error = %v", summary, err)
+		}
+	}
+	select {
+	case <-finished:
+		t.Fatal("sync finished before the remote answered")
+	default:
+	}
+
+	close(release)
+	select {
+	case err := <-finished:
+		if err != nil {
+			t.Fatalf("RunAfterWrite() error = %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("after-write sync did not finish")
+	}
+	if spawned != 1 || len(runs) != 1 || !slices.Equal(runs[0], []string{"list-1"}) {
+		t.Errorf("spawned %d runners, runs = %v, want one push run of list-1", spawned, runs)
+	}
+}
+
+// TestHoldPushSync tests that held writes trigger one sync, on release
+func TestHoldPushSync(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	config.SetConfigForTest(&config.Config{Sync: &config.SyncConfig{Enabled: true, AutoSync: true}})
+	defer config.SetConfigForTest(&config.Config{})
+	statusPath, err := internalSync.StatusPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	spawned := 0
+	defer func(start func(string)) { startAfterWriteSync = start }(startAfterWriteSync)
+	startAfterWriteSync = func(string) { spawned++ }
+
+	release := HoldPushSync()
+	tm := backend.NewMockBackend()
+	for _, list := range []*backend.TaskList{{ID: "list-1"}, {ID: "list-2"}, {ID: "list-1"}} {
+		if err := HandleAddAction(newActionCmd(), tm, list, "Buy milk", nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if status, _ := internalSync.LoadStatus(statusPath); spawned != 0 || len(status.Pending) != 0 {
+		t.Fatalf("sync requested while held: %d spawned, pending %v", spawned, status.Pending)
+	}
+
+	release()
+	release() // A second release does nothing
+	status, _ := internalSync.LoadStatus(statusPath)
+	if spawned != 1 || !slices.Equal(status.Pending, []string{"list-1", "list-2"}) {
+		t.Errorf("after release: %d spawned, pending %v, want one sync of list-1 and list-2", spawned, status.Pending)
+	}
+}
+
+// TestDueFlag tests that --due sets a relative due date on add, and that
+// --due none on update clears it
+func TestDueFlag(t *testing.T) {
+	config.SetConfigForTest(&config.Config{})
+	list := &backend.TaskList{ID: "list-1", Name: "Chores"}
+	mb := backend.NewMockBackend()
+
+	add := newActionCmd()
+	_ = add.Flags().Set("due", "+3d")
+	if err := HandleAddAction(add, mb, list, "Call back", nil); err != nil {
+		t.Fatalf("add --due +3d error = %v", err)
+	}
+	want := time.Now().AddDate(0, 0, 3).Format("2006-01-02")
+	if task := mb.Tasks["list-1"][0]; task.DueDate == nil || task.DueDate.Format("2006-01-02") != want {
+		t.Fatalf("due date after add = %v, want %s", task.DueDate, want)
+	}
+	if !mb.Tasks["list-1"][0].AllDay {
+		t.Error("a due date without a time did not make the task all day")
+	}
+
+	timed := newActionCmd()
+	_ = timed.Flags().Set("due", "2026-03-06 14:00")
+	if err := HandleUpdateAction(timed, mb, &config.Config{}, list, "Call back", nil); err != nil {
+		t.Fatalf("update --due with a time error = %v", err)
+	}
+	if mb.Tasks["list-1"][0].AllDay {
+		t.Error("a due date with a time left the task all day")
+	}
+
+	update := newActionCmd()
+	_ = update.Flags().Set("due", "none")
+	if err := HandleUpdateAction(update, mb, &config.Config{}, list, "Call back", nil); err != nil {
+		t.Fatalf("update --due none error = %v", err)
+	}
+	if task := mb.Tasks["list-1"][0]; task.DueDate != nil {
+		t.Errorf("due date after --due none = %v, want none", task.DueDate)
+	}
+
+	invalid := newActionCmd()
+	_ = invalid.Flags().Set("due", "someday")
+	if err := HandleAddAction(invalid, mb, list, "Never", nil); err == nil {
+		t.Error("add --due someday error = nil, want one")
+	}
+	if len(mb.Tasks["list-1"]) != 1 {
+		t.Errorf("tasks after an invalid --due = %d, want 1", len(mb.Tasks["list-1"]))
+	}
+}
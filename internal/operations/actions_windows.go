@@ -18,18 +18,18 @@ func spawnBackgroundSync(configPath string) {
 	}
 
 	// Check if we're running from a test binary
-	// Test binaries don't have sync commands, so spawning would cause issues
+	// Test binaries don't have the background sync command, so spawning would cause issues
 	if isTestBinary(executable) {
 		return // Don't spawn from test binaries
 	}
 
 	// Build command args with config path
-	args := []string{"sync", "--quiet"}
+	args := []string{"_internal_background_sync"}
 	if configPath != "" {
 		args = append(args, "--config", configPath)
 	}
 
-	// Spawn detached process: gosynctasks sync --quiet --config <path>
+	// Spawn detached process: gosynctasks _internal_background_sync --config <path>
 	cmd := exec.Command(executable, args...)
 
 	// Windows-specific: create new process group
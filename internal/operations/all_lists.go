@@ -0,0 +1,204 @@
+package operations
+
+import (
+	"fmt"
+	"gosynctasks/backend"
+	"gosynctasks/internal/config"
+	"gosynctasks/internal/utils"
+	"strings"
+	"sync"
+
+	"github.com/spf13/cobra"
+)
+
+// maxListFetches bounds the lists read at the same time for the pseudo-list
+const maxListFetches = 4
+
+// isAllLists reports whether listName is the pseudo-list standing for every list
+func isAllLists(cfg *config.Config, listName string) bool {
+	return listName != "" && strings.EqualFold(listName, cfg.GetAllListsName())
+}
+
+// warnAllListsCollision warns when a real list carries the name of the
+// pseudo-list, which then hides it
+func warnAllListsCollision(cfg *config.Config, taskLists []backend.TaskList) {
+	name := cfg.GetAllListsName()
+	for _, list := range taskLists {
+		if strings.EqualFold(list.Name, name) {
+			utils.Warnf("List '%s' has the name of the pseudo-list for every list; use --list %s for the list itself, or rename the pseudo-list with all_lists_name",
+				list.Name, list.Name)
+			return
+		}
+	}
+}
+
+// listResult is what was read from one list of the pseudo-list
+type listResult struct {
+	list  backend.TaskList
+	tasks []backend.Task
+	err   error
+}
+
+// readAllLists calls read for every list not in the trash, a few lists at a
+// time, and returns the results in the order of the lists
+func readAllLists(taskLists []backend.TaskList, read func(listID string) ([]backend.Task, error)) []listResult {
+	var results []listResult
+	for _, list := range taskLists {
+		if list.DeletedAt == "" {
+			results = append(results, listResult{list: list})
+		}
+	}
+
+	var wg sync.WaitGroup
+	slots := make(chan struct{}, maxListFetches)
+	for i := range results {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			slots <- struct{}{}
+			defer func() { <-slots }()
+			results[i].tasks, results[i].err = read(results[i].list.ID)
+		}()
+	}
+	wg.Wait()
+	return results
+}
+
+// executeAllListsAction runs an action on the pseudo-list. Reads cover every
+// list; writes need a single list, except update and complete when the
+// search matches exactly one task across all lists.
+func executeAllListsAction(cmd *cobra.Command, taskManager backend.TaskManager, cfg *config.Config, taskLists []backend.TaskList, action, searchSummary string, filter *backend.TaskFilter, syncProvider SyncCoordinatorProvider) error {
+	name := cfg.GetAllListsName()
+	switch action {
+	case "get":
+		return handleGetAllAction(cmd, taskManager, cfg, taskLists, filter, syncProvider)
+
+	case "update", "complete":
+		if searchSummary == "" {
+			return fmt.Errorf("'%s' stands for every list: give the task to %s, or name its list", name, action)
+		}
+		list, err := findSingleMatchList(taskManager, taskLists, action, searchSummary)
+		if err != nil {
+			return err
+		}
+		if action == "update" {
+			return HandleUpdateAction(cmd, taskManager, cfg, list, searchSummary, syncProvider)
+		}
+		return HandleCompleteAction(cmd, taskManager, cfg, list, searchSummary, syncProvider)
+
+	case "add", "delete", "trash", "restore", "sync", "export", "import":
+		return fmt.Errorf("'%s' stands for every list and %s needs a single one: name the list, or use --list %s for a list called '%s'",
+			name, action, name, name)
+
+	default:
+		return unknownActionError(action)
+	}
+}
+
+// handleGetAllAction shows the tasks of every list, grouped by list. Lists
+// without matching tasks are left out; lists that cannot be read are
+// warned about.
+func handleGetAllAction(cmd *cobra.Command, taskManager backend.TaskManager, cfg *config.Config, taskLists []backend.TaskList, filter *backend.TaskFilter, syncProvider SyncCoordinatorProvider) error {
+	if expand, _ := cmd.Flags().GetString("expand"); expand != "" {
+		return fmt.Errorf("--expand needs a single list")
+	}
+	fields, err := fieldsFlag(cmd)
+	if err != nil {
+		return err
+	}
+	jsonOutput, _ := cmd.Flags().GetBool("json")
+
+	results := readAllLists(taskLists, func(listID string) ([]backend.Task, error) {
+		return taskManager.GetTasks(listID, filter)
+	})
+	if len(results) == 0 {
+		return fmt.Errorf("no task lists available")
+	}
+
+	var allTasks []backend.Task
+	var failed int
+	var startDue []func()
+	for i := range results {
+		result := &results[i]
+		if result.err != nil {
+			utils.Warnf("Could not read list '%s': %v", result.list.Name, result.err)
+			failed++
+			continue
+		}
+		result.tasks = markDuplicateUIDs(result.tasks, result.list.Name)
+		taskManager.SortTasks(result.tasks)
+		started, listID := prepareAutoStart(cfg, taskManager, &result.list, result.tasks), result.list.ID
+		startDue = append(startDue, func() {
+			if started() > 0 {
+				triggerPushSync(syncProvider, listID)
+			}
+		})
+		allTasks = append(allTasks, result.tasks...)
+	}
+	if failed == len(results) {
+		return fmt.Errorf("error retrieving tasks: no list could be read")
+	}
+	defer func() {
+		for _, start := range startDue {
+			start()
+		}
+	}()
+
+	// One JSON array for all lists
+	if jsonOutput {
+		data, err := marshalTasksJSON(allTasks, fields)
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	if len(allTasks) == 0 {
+		fmt.Println("No matching tasks in any list")
+		return nil
+	}
+	for i := range results {
+		if results[i].err != nil || len(results[i].tasks) == 0 {
+			continue
+		}
+		if err := printListTasks(cmd, taskManager, cfg, &results[i].list, results[i].tasks, fields); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// findSingleMatchList searches every list for the task to update or
+// complete and returns the list holding it, when exactly one task matches
+func findSingleMatchList(taskManager backend.TaskManager, taskLists []backend.TaskList, action, searchSummary string) (*backend.TaskList, error) {
+	results := readAllLists(taskLists, func(listID string) ([]backend.Task, error) {
+		return taskManager.FindTasksBySummary(listID, searchSummary)
+	})
+
+	var found *backend.TaskList
+	var matches []string
+	for i := range results {
+		result := &results[i]
+		if result.err != nil {
+			return nil, fmt.Errorf("error searching list '%s': %w", result.list.Name, result.err)
+		}
+		for _, task := range result.tasks {
+			if action == "complete" && isClosedStatus(task.Status) {
+				continue // Not offered for completion, as in a single list
+			}
+			found = &result.list
+			matches = append(matches, fmt.Sprintf("'%s' in %s", utils.SanitizeLine(task.Summary), result.list.Name))
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return nil, utils.ErrTaskNotFound(searchSummary)
+	case 1:
+		return found, nil
+	default:
+		return nil, fmt.Errorf("'%s' matches %d tasks across lists (%s); name the list to %s one of them",
+			searchSummary, len(matches), strings.Join(matches, ", "), action)
+	}
+}
@@ -0,0 +1,110 @@
+package operations
+
+import (
+	"gosynctasks/backend"
+	"gosynctasks/internal/config"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func newAllListsBackend() (*backend.MockBackend, []backend.TaskList) {
+	mb := backend.NewMockBackend()
+	lists := []backend.TaskList{{ID: "work", Name: "Work"}, {ID: "home", Name: "Home"}, {ID: "old", Name: "Old", DeletedAt: "2026-01-01"}}
+	mb.Tasks["work"] = []backend.Task{{UID: "w1", Summary: "Call dentist", Status: "NEEDS-ACTION"}, {UID: "w2", Summary: "Write report", Status: "NEEDS-ACTION"}}
+	mb.Tasks["home"] = []backend.Task{{UID: "h1", Summary: "Water plants", Status: "NEEDS-ACTION"}, {UID: "h2", Summary: "Write letter", Status: "NEEDS-ACTION"}}
+	return mb, lists
+}
+
+func TestAllListsPseudoList(t *testing.T) {
+	cfg := &config.Config{}
+	config.SetConfigForTest(cfg)
+
+	t.Run("get reads every list", func(t *testing.T) {
+		mb, lists := newAllListsBackend()
+		if err := ExecuteAction(mb, cfg, lists, newActionCmd(), []string{"all"}, nil); err != nil {
+			t.Fatalf("all get failed: %v", err)
+		}
+	})
+
+	t.Run("complete with one match across lists", func(t *testing.T) {
+		mb, lists := newAllListsBackend()
+		if err := ExecuteAction(mb, cfg, lists, newActionCmd(), []string{"ALL", "complete", "Call dentist"}, nil); err != nil {
+			t.Fatalf("all complete failed: %v", err)
+		}
+		if status := mb.Tasks["work"][0].Status; status != "COMPLETED" {
+			t.Errorf("status = %q, want COMPLETED", status)
+		}
+	})
+
+	t.Run("complete with matches in several lists", func(t *testing.T) {
+		mb, lists := newAllListsBackend()
+		err := ExecuteAction(mb, cfg, lists, newActionCmd(), []string{"all", "complete", "Write"}, nil)
+		if err == nil || !strings.Contains(err.Error(), "in Work") || !strings.Contains(err.Error(), "in Home") {
+			t.Fatalf("error = %v, want one naming both lists", err)
+		}
+		if mb.Tasks["work"][1].Status != "NEEDS-ACTION" || mb.Tasks["home"][1].Status != "NEEDS-ACTION" {
+			t.Error("a task was completed despite the ambiguous search")
+		}
+	})
+
+	t.Run("writes needing a list are refused", func(t *testing.T) {
+		mb, lists := newAllListsBackend()
+		for _, args := range [][]string{{"all", "add", "New task"}, {"all", "delete", "Water plants"}} {
+			if err := ExecuteAction(mb, cfg, lists, newActionCmd(), args, nil); err == nil || !strings.Contains(err.Error(), "single") {
+				t.Errorf("%v error = %v, want a refusal", args, err)
+			}
+		}
+		if len(mb.Tasks["work"]) != 2 || len(mb.Tasks["home"]) != 2 {
+			t.Error("tasks were added or deleted")
+		}
+	})
+
+	t.Run("--list names a real list called all", func(t *testing.T) {
+		mb, lists := newAllListsBackend()
+		lists = append(lists, backend.TaskList{ID: "literal", Name: "all"})
+		cmd := newActionCmd()
+		cmd.Flags().String("list", "", "")
+		_ = cmd.Flags().Set("list", "all")
+		if err := ExecuteAction(mb, cfg, lists, cmd, []string{"add", "Into the real list"}, nil); err != nil {
+			t.Fatalf("--list all add failed: %v", err)
+		}
+		if len(mb.Tasks["literal"]) != 1 {
+			t.Errorf("tasks of the list called all = %v, want the added task", mb.Tasks["literal"])
+		}
+	})
+
+	t.Run("configured name", func(t *testing.T) {
+		mb, lists := newAllListsBackend()
+		renamed := &config.Config{AllListsName: "everything"}
+		if err := ExecuteAction(mb, renamed, lists, newActionCmd(), []string{"everything", "complete", "Water plants"}, nil); err != nil {
+			t.Fatalf("everything complete failed: %v", err)
+		}
+		if status := mb.Tasks["home"][0].Status; status != "COMPLETED" {
+			t.Errorf("status = %q, want COMPLETED", status)
+		}
+	})
+}
+
+func TestReadAllLists(t *testing.T) {
+	_, lists := newAllListsBackend()
+
+	var mu sync.Mutex
+	var read []string
+	results := readAllLists(lists, func(listID string) ([]backend.Task, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		read = append(read, listID)
+		return []backend.Task{{UID: listID + "-task"}}, nil
+	})
+
+	if len(results) != 2 || results[0].list.ID != "work" || results[1].list.ID != "home" {
+		t.Fatalf("results = %+v, want work then home", results)
+	}
+	if results[1].tasks[0].UID != "home-task" {
+		t.Errorf("home tasks = %v, want its own", results[1].tasks)
+	}
+	if len(read) != 2 {
+		t.Errorf("read lists %v, want the two not in the trash", read)
+	}
+}
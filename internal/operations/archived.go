@@ -0,0 +1,60 @@
+package operations
+
+import (
+	"fmt"
+	"gosynctasks/backend"
+	"gosynctasks/internal/utils"
+
+	"github.com/spf13/cobra"
+)
+
+// ArchivedListProvider reaches the lists the backend, or the remote behind
+// the cache, archived. They are left out of the task lists: get reads them
+// with --include-archived, and a write offers to unarchive them first.
+type ArchivedListProvider interface {
+	// ArchivedTaskLists returns the archived lists and the task manager
+	// reading them, nil when the backend cannot archive lists
+	ArchivedTaskLists() ([]backend.TaskList, backend.TaskManager, error)
+	// UnarchiveList makes an archived list active again
+	UnarchiveList(list backend.TaskList) error
+}
+
+// resolveArchivedList handles a list name not among the task lists that
+// names an archived list. get reads it with the task manager returned when
+// --include-archived is set; other actions unarchive it first, after
+// confirmation in a terminal. It returns a nil list when no archived list
+// has the name.
+func resolveArchivedList(cmd *cobra.Command, provider ArchivedListProvider, name, action string) (*backend.TaskList, backend.TaskManager, error) {
+	lists, reader, err := provider.ArchivedTaskLists()
+	if err != nil {
+		utils.Debugf("Could not check the archived lists for '%s': %v", name, err)
+		return nil, nil, nil
+	}
+	list, err := FindListByNameFull(lists, name)
+	if err != nil {
+		return nil, nil, nil
+	}
+	archivedErr := fmt.Errorf("list '%s' is archived", list.QualifiedName())
+
+	if action == "get" {
+		if includeArchived, _ := cmd.Flags().GetBool("include-archived"); !includeArchived {
+			return nil, nil, utils.WrapWithSuggestion(archivedErr,
+				fmt.Sprintf("Read it with 'gosynctasks \"%s\" --include-archived', or unarchive it with 'gosynctasks list unarchive \"%s\"'", list.QualifiedName(), list.QualifiedName()))
+		}
+		return list, reader, nil
+	}
+
+	if !isInteractive() {
+		return nil, nil, utils.WrapWithSuggestion(archivedErr,
+			fmt.Sprintf("Unarchive it first with 'gosynctasks list unarchive \"%s\"'", list.QualifiedName()))
+	}
+	if !promptYesNoDefault(fmt.Sprintf("List '%s' is archived. Unarchive it to %s?", list.QualifiedName(), action), true) {
+		return nil, nil, archivedErr
+	}
+	if err := provider.UnarchiveList(*list); err != nil {
+		return nil, nil, err
+	}
+	utils.Infof("Unarchived list '%s'", list.QualifiedName())
+	list.Archived = false
+	return list, nil, nil
+}
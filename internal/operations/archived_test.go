@@ -0,0 +1,91 @@
+package operations
+
+import (
+	"gosynctasks/backend"
+	"gosynctasks/internal/config"
+	"strings"
+	"testing"
+)
+
+// fakeArchive is a sync provider whose backend archived a list
+type fakeArchive struct {
+	archived   []backend.TaskList
+	reader     *countingReader
+	unarchived []string
+}
+
+// countingReader counts the task reads of the archived lists
+type countingReader struct {
+	*backend.MockBackend
+	reads int
+}
+
+func (r *countingReader) GetTasks(listID string, filter *backend.TaskFilter) ([]backend.Task, error) {
+	r.reads++
+	return r.MockBackend.GetTasks(listID, filter)
+}
+
+func (f *fakeArchive) GetSyncCoordinator() interface{} { return nil }
+
+func (f *fakeArchive) ArchivedTaskLists() ([]backend.TaskList, backend.TaskManager, error) {
+	return f.archived, f.reader, nil
+}
+
+func (f *fakeArchive) UnarchiveList(list backend.TaskList) error {
+	f.unarchived = append(f.unarchived, list.ID)
+	return nil
+}
+
+// TestArchivedListActions tests commands naming a list the backend archived
+func TestArchivedListActions(t *testing.T) {
+	tests := []struct {
+		name           string
+		args           []string
+		include        bool
+		interactive    bool
+		answer         bool
+		wantUnarchived bool
+		wantErr        string
+	}{
+		{name: "get needs --include-archived", args: []string{"Old Project"}, wantErr: "--include-archived"},
+		{name: "get with --include-archived", args: []string{"old project"}, include: true},
+		{name: "write, non-interactive", args: []string{"Old Project", "add", "Milk"}, wantErr: "gosynctasks list unarchive"},
+		{name: "write, unarchive accepted", args: []string{"Old Project", "add", "Milk"}, interactive: true, answer: true, wantUnarchived: true},
+		{name: "write, unarchive refused", args: []string{"Old Project", "add", "Milk"}, interactive: true, wantErr: "is archived"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stubPrompts(t, tt.interactive, tt.answer)
+			cfg := &config.Config{}
+			config.SetConfigForTest(cfg)
+			reader := &countingReader{MockBackend: backend.NewMockBackend()}
+			reader.Tasks["old"] = []backend.Task{{UID: "t1", Summary: "Kept", Status: "NEEDS-ACTION"}}
+			archive := &fakeArchive{archived: []backend.TaskList{{ID: "old", Name: "Old Project", Archived: true}}, reader: reader}
+			tm := backend.NewMockBackend()
+			cmd := newActionCmd()
+			if tt.include {
+				cmd.Flags().Set("include-archived", "true")
+			}
+
+			err := ExecuteAction(tm, cfg, nil, cmd, tt.args, archive)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("ExecuteAction() error = %v, want %q", err, tt.wantErr)
+				}
+			} else if err != nil {
+				t.Fatalf("ExecuteAction() error = %v", err)
+			}
+
+			if got := len(archive.unarchived) == 1; got != tt.wantUnarchived {
+				t.Errorf("unarchived = %v, want unarchived %v", archive.unarchived, tt.wantUnarchived)
+			}
+			if got := reader.reads > 0; got != tt.include {
+				t.Errorf("read the archived list %d times, want reads %v", reader.reads, tt.include)
+			}
+			if tt.wantUnarchived && len(tm.Tasks["old"]) != 1 {
+				t.Errorf("task not added to the unarchived list: %+v", tm.Tasks)
+			}
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package operations
+
+import (
+	"time"
+
+	"gosynctasks/backend"
+	"gosynctasks/internal/autostart"
+	"gosynctasks/internal/config"
+	"gosynctasks/internal/utils"
+)
+
+// autoStartPath returns the auto-start marker file, replaced in tests
+var autoStartPath = autostart.DefaultPath
+
+// prepareAutoStart finds the tasks of a shown list whose start date has
+// passed, under auto_start, and shows them as PROCESSING already. The
+// returned function makes the updates and returns how many were made; it is
+// run once the list is shown, so the read doesn't wait for them.
+func prepareAutoStart(cfg *config.Config, taskManager backend.TaskManager, list *backend.TaskList, tasks []backend.Task) func() int {
+	none := func() int { return 0 }
+	if cfg == nil || !cfg.AutoStartsList(*list) {
+		return none
+	}
+
+	todo, processing, err := autostart.Statuses(taskManager)
+	if err != nil {
+		return none
+	}
+	path, err := autoStartPath()
+	if err != nil {
+		return none
+	}
+	marker, err := autostart.Load(path)
+	if err != nil {
+		utils.Warnf("%v", err)
+		return none
+	}
+
+	due := marker.Due(tasks, todo, time.Now())
+	if len(due) == 0 {
+		return none
+	}
+	dueUIDs := make(map[string]bool, len(due))
+	for _, task := range due {
+		dueUIDs[task.UID] = true
+	}
+	for i := range tasks {
+		if dueUIDs[tasks[i].UID] {
+			tasks[i].Status = processing
+		}
+	}
+
+	return func() int {
+		started, err := marker.Start(taskManager, list.ID, due)
+		if err != nil {
+			utils.Warnf("%v", err)
+		}
+		if started > 0 {
+			if err := marker.Save(); err != nil {
+				utils.Warnf("%v", err)
+			}
+		}
+		return started
+	}
+}
@@ -0,0 +1,52 @@
+package operations
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"gosynctasks/backend"
+	"gosynctasks/internal/config"
+)
+
+func TestPrepareAutoStart(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "autostart.json")
+	oldPath := autoStartPath
+	autoStartPath = func() (string, error) { return path, nil }
+	t.Cleanup(func() { autoStartPath = oldPath })
+
+	start := time.Now().Add(-time.Hour)
+	mb := backend.NewMockBackend()
+	mb.Tasks["work"] = []backend.Task{
+		{UID: "due", Summary: "Due", Status: "NEEDS-ACTION", StartDate: &start},
+		{UID: "done", Summary: "Done", Status: "COMPLETED", StartDate: &start},
+	}
+	list := &backend.TaskList{ID: "work", Name: "Work"}
+	cfg := &config.Config{AutoStartLists: []string{"work"}}
+
+	shown := append([]backend.Task(nil), mb.Tasks["work"]...)
+	apply := prepareAutoStart(cfg, mb, list, shown)
+	if shown[0].Status != "IN-PROCESS" || shown[1].Status != "COMPLETED" {
+		t.Errorf("shown statuses = %q, %q, want IN-PROCESS, COMPLETED", shown[0].Status, shown[1].Status)
+	}
+	if mb.Tasks["work"][0].Status != "NEEDS-ACTION" {
+		t.Error("task updated before the list was shown")
+	}
+	if started := apply(); started != 1 || statusOf(t, mb, "work", "due") != "IN-PROCESS" {
+		t.Errorf("apply() = %d, status %q, want the task started", started, statusOf(t, mb, "work", "due"))
+	}
+
+	// Moved back to TODO by hand: not started again
+	mb.Tasks["work"][0].Status = "NEEDS-ACTION"
+	shown = append([]backend.Task(nil), mb.Tasks["work"]...)
+	if started := prepareAutoStart(cfg, mb, list, shown)(); started != 0 || shown[0].Status != "NEEDS-ACTION" {
+		t.Errorf("reverted task started again (%d, shown %q)", started, shown[0].Status)
+	}
+
+	// Lists without auto_start are left alone
+	other := &backend.TaskList{ID: "home", Name: "Home"}
+	mb.Tasks["home"] = []backend.Task{{UID: "home-due", Status: "NEEDS-ACTION", StartDate: &start}}
+	if started := prepareAutoStart(cfg, mb, other, mb.Tasks["home"])(); started != 0 {
+		t.Errorf("started %d tasks in a list without auto_start", started)
+	}
+}
@@ -0,0 +1,160 @@
+package operations
+
+import (
+	"fmt"
+	"gosynctasks/backend"
+	"gosynctasks/internal/utils"
+	"regexp"
+	"strings"
+	"time"
+)
+
+var (
+	capturePriorityPattern    = regexp.MustCompile(`^[pP][1-9]$`)
+	captureTagPattern         = regexp.MustCompile(`^\+[\p{L}\p{N}_][\p{L}\p{N}_-]*$`)
+	captureDescriptionPattern = regexp.MustCompile(`(^|\s)//`)
+)
+
+// Capture is a task parsed from a quick capture string
+type Capture struct {
+	Summary     string
+	Description string
+	DueDate     *time.Time
+	DuePhrase   string // Date phrase as written, for the confirmation message
+	Priority    int
+	Tags        []string
+}
+
+// ParseCapture extracts inline tokens from a capture string:
+//   - "+tag" anywhere adds a tag
+//   - "pN" (N 1-9) and a date phrase ("tomorrow", "fri", "14 Jul") are taken
+//     only from the end of the text, so "p2p networking" or "Monday meeting notes" stay intact
+//   - text after " //" becomes the description
+//
+// With literal set, the whole text is the summary.
+func ParseCapture(text string, literal bool, now time.Time) (Capture, error) {
+	text = strings.TrimSpace(text)
+	if literal {
+		if text == "" {
+			return Capture{}, fmt.Errorf("task summary cannot be empty")
+		}
+		return Capture{Summary: text}, nil
+	}
+
+	var capture Capture
+	if loc := captureDescriptionPattern.FindStringIndex(text); loc != nil {
+		capture.Description = strings.TrimSpace(text[loc[1]:])
+		text = text[:loc[0]]
+	}
+
+	var words []string
+	for _, word := range strings.Fields(text) {
+		if captureTagPattern.MatchString(word) {
+			capture.Tags = append(capture.Tags, word[1:])
+			continue
+		}
+		words = append(words, word)
+	}
+
+	// Consume priority and due date from the end, keeping at least one summary word
+	locale := utils.ActiveLocale()
+	for len(words) > 1 {
+		last := words[len(words)-1]
+		if capture.Priority == 0 && capturePriorityPattern.MatchString(last) {
+			capture.Priority = int(last[1] - '0')
+			words = words[:len(words)-1]
+			continue
+		}
+		if capture.DueDate == nil {
+			if n := parseTrailingDate(words, locale, now, &capture); n > 0 {
+				words = words[:len(words)-n]
+				continue
+			}
+		}
+		break
+	}
+
+	capture.Summary = strings.Join(words, " ")
+	if capture.Summary == "" {
+		return Capture{}, fmt.Errorf("task summary cannot be empty")
+	}
+	return capture, nil
+}
+
+// parseTrailingDate tries the last two words, then the last word, as a date phrase.
+// It returns the number of words consumed.
+func parseTrailingDate(words []string, locale *utils.Locale, now time.Time, capture *Capture) int {
+	for n := 2; n >= 1; n-- {
+		if len(words) <= n {
+			continue
+		}
+		phrase := strings.Join(words[len(words)-n:], " ")
+		if date, err := utils.ParseLocalizedDate(phrase, locale, now); err == nil {
+			capture.DueDate = date
+			capture.DuePhrase = phrase
+			return n
+		}
+	}
+	return 0
+}
+
+// Extracted describes what was parsed out of the text, e.g. "due tomorrow, p2, +home"
+func (c Capture) Extracted() string {
+	var parts []string
+	if c.DueDate != nil {
+		parts = append(parts, "due "+c.DuePhrase)
+	}
+	if c.Priority > 0 {
+		parts = append(parts, fmt.Sprintf("p%d", c.Priority))
+	}
+	for _, tag := range c.Tags {
+		parts = append(parts, "+"+tag)
+	}
+	if c.Description != "" {
+		parts = append(parts, "with description")
+	}
+	return strings.Join(parts, ", ")
+}
+
+// CaptureTask adds a captured task to the named list without prompting and
+// returns the confirmation message. The push to the remote is left to
+// background sync.
+func CaptureTask(taskManager backend.TaskManager, taskLists []backend.TaskList, listName string, capture Capture, syncProvider SyncCoordinatorProvider) (string, error) {
+	list, err := FindListByNameFull(taskLists, listName)
+	if err != nil {
+		return "", utils.WrapWithSuggestion(
+			fmt.Errorf("capture list: %w", err),
+			"Create the list or set capture.list in the config",
+		)
+	}
+
+	status, err := taskManager.ParseStatusFlag("TODO")
+	if err != nil {
+		return "", err
+	}
+
+	task := backend.Task{
+		Summary:     capture.Summary,
+		Description: capture.Description,
+		Status:      status,
+		Priority:    capture.Priority,
+		DueDate:     capture.DueDate,
+		Categories:  capture.Tags,
+	}
+	if err := ValidateTask(taskManager, nil, task); err != nil { // No start date, so no config needed
+		return "", err
+	}
+	if _, err := taskManager.AddTask(list.ID, task); err != nil {
+		return "", fmt.Errorf("error adding task: %w", err)
+	}
+
+	if syncProvider != nil {
+		triggerPushSync(syncProvider, list.ID)
+	}
+
+	message := fmt.Sprintf("'%s' added to %s", capture.Summary, list.Name)
+	if extracted := capture.Extracted(); extracted != "" {
+		message += ", " + extracted
+	}
+	return message, nil
+}
@@ -0,0 +1,115 @@
+package operations
+
+import (
+	"gosynctasks/backend"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseCapture(t *testing.T) {
+	// Wednesday 2026-07-08
+	now := time.Date(2026, 7, 8, 10, 0, 0, 0, time.Local)
+
+	tests := []struct {
+		input       string
+		summary     string
+		due         string // YYYY-MM-DD, empty for no due date
+		priority    int
+		tags        []string
+		description string
+	}{
+		{input: "call plumber tomorrow p2 +home", summary: "call plumber", due: "2026-07-09", priority: 2, tags: []string{"home"}},
+		{input: "+work +urgent send report", summary: "send report", tags: []string{"work", "urgent"}},
+		{input: "send report fri", summary: "send report", due: "2026-07-10"},
+		{input: "dentist 14 Jul p1", summary: "dentist", due: "2026-07-14", priority: 1},
+		{input: "renew passport 2026-09-01", summary: "renew passport", due: "2026-09-01"},
+		{input: "renew passport // bring old one", summary: "renew passport", description: "bring old one"},
+		{input: "buy milk today // 2 liters +home", summary: "buy milk", due: "2026-07-08", description: "2 liters +home"},
+
+		// False positives that must stay in the summary
+		{input: "p2p networking", summary: "p2p networking"},
+		{input: "read about p2p networking", summary: "read about p2p networking"},
+		{input: "Monday meeting notes", summary: "Monday meeting notes"},
+		{input: "check https://example.com/docs", summary: "check https://example.com/docs"},
+		{input: "learn C++ templates", summary: "learn C++ templates"},
+		{input: "fix ticket p10", summary: "fix ticket p10"},
+		{input: "agree to 1+1 meeting", summary: "agree to 1+1 meeting"},
+		{input: "tomorrow", summary: "tomorrow"},
+		{input: "p2", summary: "p2"},
+		{input: "email 01/02 invoice", summary: "email 01/02 invoice"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseCapture(tt.input, false, now)
+			if err != nil {
+				t.Fatalf("ParseCapture(%q) unexpected error: %v", tt.input, err)
+			}
+			if got.Summary != tt.summary {
+				t.Errorf("Summary = %q, want %q", got.Summary, tt.summary)
+			}
+			gotDue := ""
+			if got.DueDate != nil {
+				gotDue = got.DueDate.Format("2006-01-02")
+			}
+			if gotDue != tt.due {
+				t.Errorf("DueDate = %q, want %q", gotDue, tt.due)
+			}
+			if got.Priority != tt.priority {
+				t.Errorf("Priority = %d, want %d", got.Priority, tt.priority)
+			}
+			if !reflect.DeepEqual(got.Tags, tt.tags) {
+				t.Errorf("Tags = %v, want %v", got.Tags, tt.tags)
+			}
+			if got.Description != tt.description {
+				t.Errorf("Description = %q, want %q", got.Description, tt.description)
+			}
+		})
+	}
+}
+
+func TestParseCaptureLiteral(t *testing.T) {
+	got, err := ParseCapture("call plumber tomorrow p2 +home", true, time.Now())
+	if err != nil {
+		t.Fatalf("ParseCapture() unexpected error: %v", err)
+	}
+	if got.Summary != "call plumber tomorrow p2 +home" || got.DueDate != nil || got.Priority != 0 || got.Tags != nil {
+		t.Errorf("literal capture should keep raw text, got %+v", got)
+	}
+
+	if _, err := ParseCapture("  ", true, time.Now()); err == nil {
+		t.Error("expected error for empty capture")
+	}
+}
+
+func TestCaptureTask(t *testing.T) {
+	mock := backend.NewMockBackend()
+	listID, _ := mock.CreateTaskList("Inbox", "", "")
+	lists, _ := mock.GetTaskLists()
+
+	now := time.Now()
+	capture, _ := ParseCapture("call plumber tomorrow p2 +home", false, now)
+
+	message, err := CaptureTask(mock, lists, "inbox", capture, nil)
+	if err != nil {
+		t.Fatalf("CaptureTask() unexpected error: %v", err)
+	}
+	if want := "'call plumber' added to Inbox, due tomorrow, p2, +home"; message != want {
+		t.Errorf("message = %q, want %q", message, want)
+	}
+
+	tasks, _ := mock.GetTasks(listID, nil)
+	if len(tasks) != 1 {
+		t.Fatalf("expected 1 task, got %d", len(tasks))
+	}
+	if tasks[0].Priority != 2 || tasks[0].DueDate == nil || !reflect.DeepEqual(tasks[0].Categories, []string{"home"}) {
+		t.Errorf("captured task missing fields: %+v", tasks[0])
+	}
+
+	_, err = CaptureTask(mock, lists, "Missing", capture, nil)
+	if err == nil || !strings.Contains(err.Error(), "capture.list") {
+		t.Errorf("expected missing list error with config hint, got %v", err)
+	}
+}
@@ -0,0 +1,209 @@
+package operations
+
+import (
+	"fmt"
+	"gosynctasks/backend"
+	"gosynctasks/internal/config"
+	"gosynctasks/internal/utils"
+	"os"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+// Prompt hooks, replaced in tests so the hierarchy logic can run without a terminal
+var (
+	isInteractive = func() bool {
+		return term.IsTerminal(int(os.Stdin.Fd()))
+	}
+	promptYesNoDefault = utils.PromptYesNoDefault
+)
+
+// isClosedStatus reports whether a status counts as finished for hierarchy purposes
+func isClosedStatus(status string) bool {
+	switch strings.ToUpper(status) {
+	case "COMPLETED", "DONE", "CANCELLED":
+		return true
+	}
+	return false
+}
+
+// findTaskByUID returns a pointer into tasks for the given UID, or nil
+func findTaskByUID(tasks []backend.Task, uid string) *backend.Task {
+	for i := range tasks {
+		if tasks[i].UID == uid {
+			return &tasks[i]
+		}
+	}
+	return nil
+}
+
+// allChildrenClosed reports whether parentUID has children and all of them are done or cancelled
+func allChildrenClosed(tasks []backend.Task, parentUID string) bool {
+	hasChildren := false
+	for _, t := range tasks {
+		if t.ParentUID != parentUID {
+			continue
+		}
+		hasChildren = true
+		if !isClosedStatus(t.Status) {
+			return false
+		}
+	}
+	return hasChildren
+}
+
+// findOpenDescendants returns all descendants of parentUID that are not yet closed
+func findOpenDescendants(tasks []backend.Task, parentUID string) []backend.Task {
+	var open []backend.Task
+	queue := []string{parentUID}
+	visited := map[string]bool{parentUID: true}
+
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		for _, t := range tasks {
+			if t.ParentUID != current || visited[t.UID] {
+				continue
+			}
+			visited[t.UID] = true
+			queue = append(queue, t.UID)
+			if !isClosedStatus(t.Status) {
+				open = append(open, t)
+			}
+		}
+	}
+
+	return open
+}
+
+// shouldCompleteParent decides whether to complete a parent whose subtasks are all done.
+// Non-interactive runs never complete parents unless auto_complete_parent is set.
+func shouldCompleteParent(cfg *config.Config, parent *backend.Task) bool {
+	if cfg != nil && cfg.AutoCompleteParent {
+		return true
+	}
+	if !isInteractive() {
+		return false
+	}
+	return promptYesNoDefault(fmt.Sprintf("All subtasks done — complete parent '%s'?", parent.Summary), true)
+}
+
+// completeParentChain walks up from a completed task, completing each ancestor whose
+// subtasks are all done or cancelled. Tasks are re-fetched at each level so the check
+// sees the state after the previous update.
+func completeParentChain(taskManager backend.TaskManager, cfg *config.Config, listID string, task *backend.Task) error {
+	doneStatus, err := taskManager.ParseStatusFlag("DONE")
+	if err != nil {
+		return err
+	}
+
+	parentUID := task.ParentUID
+	for parentUID != "" {
+		tasks, err := taskManager.GetTasks(listID, nil)
+		if err != nil {
+			return fmt.Errorf("error fetching tasks: %w", err)
+		}
+
+		parent := findTaskByUID(tasks, parentUID)
+		if parent == nil || isClosedStatus(parent.Status) {
+			return nil
+		}
+		if !allChildrenClosed(tasks, parentUID) {
+			return nil
+		}
+		if !shouldCompleteParent(cfg, parent) {
+			return nil
+		}
+
+		parent.Status = doneStatus
+		if err := taskManager.UpdateTask(listID, *parent); err != nil {
+			return fmt.Errorf("error updating parent task: %w", err)
+		}
+		fmt.Printf("Parent task '%s' marked as %s\n", parent.Summary, taskManager.StatusToDisplayName(doneStatus))
+
+		parentUID = parent.ParentUID
+	}
+
+	return nil
+}
+
+// completeOpenChildren warns when a closed task still has open subtasks and offers
+// to give them the same status recursively
+func completeOpenChildren(taskManager backend.TaskManager, listID string, task *backend.Task, status string) error {
+	tasks, err := taskManager.GetTasks(listID, nil)
+	if err != nil {
+		return fmt.Errorf("error fetching tasks: %w", err)
+	}
+
+	open := findOpenDescendants(tasks, task.UID)
+	if len(open) == 0 {
+		return nil
+	}
+
+	statusName := taskManager.StatusToDisplayName(status)
+	fmt.Printf("Warning: '%s' still has %d open subtask(s)\n", task.Summary, len(open))
+	if !isInteractive() {
+		return nil
+	}
+	if !promptYesNoDefault(fmt.Sprintf("Mark them as %s too?", statusName), false) {
+		return nil
+	}
+
+	for _, child := range open {
+		child.Status = status
+		if err := taskManager.UpdateTask(listID, child); err != nil {
+			return fmt.Errorf("error updating subtask '%s': %w", child.Summary, err)
+		}
+	}
+	fmt.Printf("%d subtask(s) marked as %s\n", len(open), statusName)
+
+	return nil
+}
+
+// cascadeConfirmThreshold is the number of subtasks above which --with-children
+// asks before changing them
+const cascadeConfirmThreshold = 5
+
+// cascadeStatus gives the open descendants of a task the same status for
+// --with-children. The subtasks are listed first, and confirmed when there are
+// more than cascadeConfirmThreshold. Each one is updated on its own so a sync
+// queues it individually; on failure the error names the unchanged subtasks.
+func cascadeStatus(taskManager backend.TaskManager, listID string, task *backend.Task, status string) error {
+	tasks, err := taskManager.GetTasks(listID, nil)
+	if err != nil {
+		return fmt.Errorf("error fetching tasks: %w", err)
+	}
+
+	open := findOpenDescendants(tasks, task.UID)
+	if len(open) == 0 {
+		return nil
+	}
+
+	statusName := taskManager.StatusToDisplayName(status)
+	fmt.Printf("%d subtask(s) of '%s' will be marked as %s:\n", len(open), utils.SanitizeLine(task.Summary), statusName)
+	for _, child := range open {
+		fmt.Printf("  - %s\n", utils.SanitizeLine(child.Summary))
+	}
+	if len(open) > cascadeConfirmThreshold && isInteractive() &&
+		!promptYesNoDefault(fmt.Sprintf("Mark all %d subtasks as %s?", len(open), statusName), false) {
+		fmt.Println("Subtasks left unchanged")
+		return nil
+	}
+
+	for i, child := range open {
+		child.Status = status
+		if err := taskManager.UpdateTask(listID, child); err != nil {
+			unchanged := make([]string, 0, len(open)-i)
+			for _, t := range open[i:] {
+				unchanged = append(unchanged, fmt.Sprintf("'%s'", utils.SanitizeLine(t.Summary)))
+			}
+			return fmt.Errorf("error updating subtask '%s': %w (%d of %d subtask(s) marked as %s; unchanged: %s)",
+				utils.SanitizeLine(child.Summary), err, i, len(open), statusName, strings.Join(unchanged, ", "))
+		}
+	}
+	fmt.Printf("%d subtask(s) marked as %s\n", len(open), statusName)
+
+	return nil
+}
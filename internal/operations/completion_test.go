@@ -0,0 +1,261 @@
+package operations
+
+import (
+	"errors"
+	"fmt"
+	"gosynctasks/backend"
+	"gosynctasks/internal/config"
+	"strings"
+	"testing"
+)
+
+// stubPrompts replaces the interactive hooks for the duration of a test
+func stubPrompts(t *testing.T, interactive bool, answer bool) *int {
+	t.Helper()
+	oldInteractive, oldPrompt := isInteractive, promptYesNoDefault
+	calls := 0
+	isInteractive = func() bool { return interactive }
+	promptYesNoDefault = func(question string, def bool) bool {
+		calls++
+		return answer
+	}
+	t.Cleanup(func() {
+		isInteractive, promptYesNoDefault = oldInteractive, oldPrompt
+	})
+	return &calls
+}
+
+func statusOf(t *testing.T, mb *backend.MockBackend, listID, uid string) string {
+	t.Helper()
+	for _, task := range mb.Tasks[listID] {
+		if task.UID == uid {
+			return task.Status
+		}
+	}
+	t.Fatalf("task %q not found", uid)
+	return ""
+}
+
+// newHierarchyBackend builds: release -> (docs, build -> (compile, package))
+func newHierarchyBackend() *backend.MockBackend {
+	mb := backend.NewMockBackend()
+	mb.Tasks["list"] = []backend.Task{
+		{UID: "release", Summary: "Release v2", Status: "NEEDS-ACTION"},
+		{UID: "docs", Summary: "Docs", Status: "COMPLETED", ParentUID: "release"},
+		{UID: "build", Summary: "Build", Status: "NEEDS-ACTION", ParentUID: "release"},
+		{UID: "compile", Summary: "Compile", Status: "COMPLETED", ParentUID: "build"},
+		{UID: "package", Summary: "Package", Status: "COMPLETED", ParentUID: "build"},
+	}
+	return mb
+}
+
+func TestCompleteParentChain_MultiLevel(t *testing.T) {
+	mb := newHierarchyBackend()
+	calls := stubPrompts(t, true, true)
+
+	task := mb.Tasks["list"][4]
+	if err := completeParentChain(mb, &config.Config{}, "list", &task); err != nil {
+		t.Fatalf("completeParentChain() error = %v", err)
+	}
+
+	if got := statusOf(t, mb, "list", "build"); got != "COMPLETED" {
+		t.Errorf("build status = %q, want COMPLETED", got)
+	}
+	if got := statusOf(t, mb, "list", "release"); got != "COMPLETED" {
+		t.Errorf("release status = %q, want COMPLETED", got)
+	}
+	if *calls != 2 {
+		t.Errorf("prompt called %d times, want 2", *calls)
+	}
+}
+
+func TestCompleteParentChain_CancelledSiblingCountsAsDone(t *testing.T) {
+	mb := newHierarchyBackend()
+	mb.Tasks["list"][3].Status = "CANCELLED"
+	stubPrompts(t, true, true)
+
+	task := mb.Tasks["list"][4]
+	if err := completeParentChain(mb, &config.Config{}, "list", &task); err != nil {
+		t.Fatalf("completeParentChain() error = %v", err)
+	}
+
+	if got := statusOf(t, mb, "list", "build"); got != "COMPLETED" {
+		t.Errorf("build status = %q, want COMPLETED", got)
+	}
+}
+
+func TestCompleteParentChain_OpenSiblingStops(t *testing.T) {
+	mb := newHierarchyBackend()
+	mb.Tasks["list"][3].Status = "IN-PROCESS"
+	calls := stubPrompts(t, true, true)
+
+	task := mb.Tasks["list"][4]
+	if err := completeParentChain(mb, &config.Config{}, "list", &task); err != nil {
+		t.Fatalf("completeParentChain() error = %v", err)
+	}
+
+	if got := statusOf(t, mb, "list", "build"); got != "NEEDS-ACTION" {
+		t.Errorf("build status = %q, want NEEDS-ACTION", got)
+	}
+	if *calls != 0 {
+		t.Errorf("prompt called %d times, want 0", *calls)
+	}
+}
+
+func TestCompleteParentChain_DeclineStopsChain(t *testing.T) {
+	mb := newHierarchyBackend()
+	stubPrompts(t, true, false)
+
+	task := mb.Tasks["list"][4]
+	if err := completeParentChain(mb, &config.Config{}, "list", &task); err != nil {
+		t.Fatalf("completeParentChain() error = %v", err)
+	}
+
+	if got := statusOf(t, mb, "list", "build"); got != "NEEDS-ACTION" {
+		t.Errorf("build status = %q, want NEEDS-ACTION", got)
+	}
+	if got := statusOf(t, mb, "list", "release"); got != "NEEDS-ACTION" {
+		t.Errorf("release status = %q, want NEEDS-ACTION", got)
+	}
+}
+
+func TestCompleteParentChain_NonInteractive(t *testing.T) {
+	tests := []struct {
+		name       string
+		autoConfig bool
+		want       string
+	}{
+		{"without config leaves parent open", false, "NEEDS-ACTION"},
+		{"with auto_complete_parent completes parent", true, "COMPLETED"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mb := newHierarchyBackend()
+			calls := stubPrompts(t, false, true)
+
+			task := mb.Tasks["list"][4]
+			cfg := &config.Config{AutoCompleteParent: tt.autoConfig}
+			if err := completeParentChain(mb, cfg, "list", &task); err != nil {
+				t.Fatalf("completeParentChain() error = %v", err)
+			}
+
+			if got := statusOf(t, mb, "list", "build"); got != tt.want {
+				t.Errorf("build status = %q, want %q", got, tt.want)
+			}
+			if *calls != 0 {
+				t.Errorf("prompt called %d times, want 0", *calls)
+			}
+		})
+	}
+}
+
+func TestCompleteOpenChildren_Recursive(t *testing.T) {
+	mb := newHierarchyBackend()
+	mb.Tasks["list"][3].Status = "NEEDS-ACTION"
+	stubPrompts(t, true, true)
+
+	parent := mb.Tasks["list"][0]
+	parent.Status = "COMPLETED"
+	if err := completeOpenChildren(mb, "list", &parent, "COMPLETED"); err != nil {
+		t.Fatalf("completeOpenChildren() error = %v", err)
+	}
+
+	for _, uid := range []string{"build", "compile"} {
+		if got := statusOf(t, mb, "list", uid); got != "COMPLETED" {
+			t.Errorf("%s status = %q, want COMPLETED", uid, got)
+		}
+	}
+}
+
+func TestCompleteOpenChildren_NonInteractiveOnlyWarns(t *testing.T) {
+	mb := newHierarchyBackend()
+	calls := stubPrompts(t, false, true)
+
+	parent := mb.Tasks["list"][0]
+	if err := completeOpenChildren(mb, "list", &parent, "COMPLETED"); err != nil {
+		t.Fatalf("completeOpenChildren() error = %v", err)
+	}
+
+	if got := statusOf(t, mb, "list", "build"); got != "NEEDS-ACTION" {
+		t.Errorf("build status = %q, want NEEDS-ACTION", got)
+	}
+	if *calls != 0 {
+		t.Errorf("prompt called %d times, want 0", *calls)
+	}
+}
+
+func TestCascadeStatus_SkipsClosedSubtasks(t *testing.T) {
+	mb := newHierarchyBackend()
+	mb.Tasks["list"][3].Status = "NEEDS-ACTION"
+	calls := stubPrompts(t, true, false)
+
+	parent := mb.Tasks["list"][0]
+	if err := cascadeStatus(mb, "list", &parent, "CANCELLED"); err != nil {
+		t.Fatalf("cascadeStatus() error = %v", err)
+	}
+
+	want := map[string]string{"docs": "COMPLETED", "build": "CANCELLED", "compile": "CANCELLED", "package": "COMPLETED"}
+	for uid, status := range want {
+		if got := statusOf(t, mb, "list", uid); got != status {
+			t.Errorf("%s status = %q, want %s", uid, got, status)
+		}
+	}
+	if *calls != 0 {
+		t.Errorf("prompt called %d times below the threshold, want 0", *calls)
+	}
+}
+
+func TestCascadeStatus_AsksAboveThreshold(t *testing.T) {
+	mb := backend.NewMockBackend()
+	mb.Tasks["list"] = []backend.Task{{UID: "parent", Summary: "Parent", Status: "NEEDS-ACTION"}}
+	for i := range cascadeConfirmThreshold + 1 {
+		mb.Tasks["list"] = append(mb.Tasks["list"], backend.Task{UID: fmt.Sprintf("child-%d", i), Summary: "Child", Status: "NEEDS-ACTION", ParentUID: "parent"})
+	}
+	calls := stubPrompts(t, true, false)
+
+	parent := mb.Tasks["list"][0]
+	if err := cascadeStatus(mb, "list", &parent, "CANCELLED"); err != nil {
+		t.Fatalf("cascadeStatus() error = %v", err)
+	}
+	if *calls != 1 {
+		t.Errorf("prompt called %d times, want 1", *calls)
+	}
+	if got := statusOf(t, mb, "list", "child-0"); got != "NEEDS-ACTION" {
+		t.Errorf("declined cascade changed child-0 to %q", got)
+	}
+}
+
+// failingUpdateBackend fails to update one task
+type failingUpdateBackend struct {
+	*backend.MockBackend
+	failUID string
+}
+
+func (b failingUpdateBackend) UpdateTask(listID string, task backend.Task) error {
+	if task.UID == b.failUID {
+		return errors.New("server error")
+	}
+	return b.MockBackend.UpdateTask(listID, task)
+}
+
+func TestCascadeStatus_PartialFailureNamesUnchanged(t *testing.T) {
+	mb := newHierarchyBackend()
+	mb.Tasks["list"][3].Status = "NEEDS-ACTION"
+	mb.Tasks["list"][4].Status = "NEEDS-ACTION"
+	stubPrompts(t, false, false)
+
+	parent := mb.Tasks["list"][0]
+	err := cascadeStatus(failingUpdateBackend{MockBackend: mb, failUID: "compile"}, "list", &parent, "CANCELLED")
+	if err == nil {
+		t.Fatal("cascadeStatus() error = nil, want the failed update")
+	}
+	if got := statusOf(t, mb, "list", "build"); got != "CANCELLED" {
+		t.Errorf("build status = %q, want CANCELLED", got)
+	}
+	for _, name := range []string{"'Compile'", "'Package'"} {
+		if !strings.Contains(err.Error(), name) {
+			t.Errorf("error %q does not name unchanged subtask %s", err, name)
+		}
+	}
+}
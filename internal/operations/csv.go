@@ -0,0 +1,198 @@
+package operations
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"gosynctasks/backend"
+	"gosynctasks/backend/statuskit"
+	"gosynctasks/internal/utils"
+)
+
+// csvColumns are the columns export writes, in order. Import maps columns by
+// these header names, in any order, and needs only summary.
+var csvColumns = []string{"uid", "summary", "description", "status", "priority", "due_date", "start_date", "created", "modified", "categories", "parent_uid"}
+
+// csvDateFormat is how dates at midnight are written, other times being RFC 3339
+const csvDateFormat = "2006-01-02"
+
+// parseDelimiter returns the field separator --delimiter names: a single
+// character, or "tab"
+func parseDelimiter(delimiter string) (rune, error) {
+	if strings.EqualFold(delimiter, "tab") || delimiter == `\t` {
+		return '\t', nil
+	}
+	comma, size := utf8.DecodeRuneInString(delimiter)
+	if delimiter == "" || size != len(delimiter) || comma == '"' || comma == '\r' || comma == '\n' || comma == utf8.RuneError {
+		return 0, fmt.Errorf("invalid delimiter %q: use a single character other than a quote, such as ';', or 'tab'", delimiter)
+	}
+	return comma, nil
+}
+
+// encodeCSV writes tasks as the rows of csvColumns, quoted as RFC 4180 asks.
+// Statuses are written as their display names, tags joined by commas.
+func encodeCSV(w io.Writer, tasks []backend.Task, delimiter rune) error {
+	writer := csv.NewWriter(w)
+	writer.Comma = delimiter
+	writer.UseCRLF = true
+	if err := writer.Write(csvColumns); err != nil {
+		return err
+	}
+	for _, task := range tasks {
+		status := task.Status
+		if s, ok := statuskit.Lookup(status); ok {
+			status = string(s)
+		}
+		priority := ""
+		if task.Priority > 0 {
+			priority = strconv.Itoa(task.Priority)
+		}
+		row := []string{
+			task.UID, task.Summary, task.Description, status, priority,
+			formatCSVDate(task.DueDate), formatCSVDate(task.StartDate),
+			formatCSVTime(task.Created), formatCSVTime(task.Modified),
+			strings.Join(task.Categories, ","), task.ParentUID,
+		}
+		if err := writer.Write(row); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+func formatCSVDate(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	local := t.Local()
+	if local.Hour() == 0 && local.Minute() == 0 && local.Second() == 0 {
+		return local.Format(csvDateFormat)
+	}
+	return t.Format(time.RFC3339)
+}
+
+func formatCSVTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
+
+// decodeCSV reads tasks from rows whose first one names the columns. Columns
+// are matched to csvColumns by name, ignoring case; a summary column is
+// required, and the others, unknown ones included, may be left out.
+func decodeCSV(r io.Reader, delimiter rune) ([]backend.Task, error) {
+	reader := csv.NewReader(r)
+	reader.Comma = delimiter
+	reader.FieldsPerRecord = -1 // Spreadsheets drop empty trailing fields
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		if i == 0 {
+			name = strings.TrimPrefix(name, "\ufeff") // Byte order mark of Excel's UTF-8 files
+		}
+		name = strings.ToLower(strings.TrimSpace(name))
+		columns[strings.ReplaceAll(name, " ", "_")] = i
+	}
+	if _, ok := columns["summary"]; !ok {
+		return nil, fmt.Errorf("no summary column in the header %q (columns: %s)", strings.Join(header, string(delimiter)), strings.Join(csvColumns, ", "))
+	}
+	var unknown []string
+	for name := range columns {
+		if !slices.Contains(csvColumns, name) {
+			unknown = append(unknown, name)
+		}
+	}
+	if len(unknown) > 0 {
+		slices.Sort(unknown)
+		utils.Warnf("Ignoring the columns %s", strings.Join(unknown, ", "))
+	}
+
+	var tasks []backend.Task
+	for {
+		row, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return tasks, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		line, _ := reader.FieldPos(0)
+		field := func(name string) string {
+			if i, ok := columns[name]; ok && i < len(row) {
+				return strings.TrimSpace(row[i])
+			}
+			return ""
+		}
+		if strings.Join(row, "") == "" {
+			continue // Blank rows of spreadsheets
+		}
+
+		task := backend.Task{
+			UID:         field("uid"),
+			Summary:     field("summary"),
+			Description: field("description"),
+			Status:      field("status"),
+			ParentUID:   field("parent_uid"),
+		}
+		if priority := field("priority"); priority != "" {
+			if task.Priority, err = strconv.Atoi(priority); err != nil {
+				return nil, fmt.Errorf("line %d: invalid priority %q", line, priority)
+			}
+		}
+		for _, date := range []struct {
+			name string
+			set  func(time.Time)
+		}{
+			{"due_date", func(t time.Time) { task.DueDate = &t }},
+			{"start_date", func(t time.Time) { task.StartDate = &t }},
+			{"created", func(t time.Time) { task.Created = t }},
+			{"modified", func(t time.Time) { task.Modified = t }},
+		} {
+			value := field(date.name)
+			if value == "" {
+				continue
+			}
+			t, err := parseCSVTime(value)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %s: %w", line, date.name, err)
+			}
+			date.set(t)
+		}
+		for tag := range strings.SplitSeq(field("categories"), ",") {
+			if tag = strings.TrimSpace(tag); tag != "" {
+				task.Categories = append(task.Categories, tag)
+			}
+		}
+		tasks = append(tasks, task)
+	}
+}
+
+// parseCSVTime reads a time as RFC 3339, as a local date and time or as a
+// local date
+func parseCSVTime(value string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	for _, layout := range []string{"2006-01-02 15:04:05", "2006-01-02 15:04", "2006-01-02T15:04", csvDateFormat} {
+		if t, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid time %q (YYYY-MM-DD, YYYY-MM-DD HH:MM or RFC 3339)", value)
+}
@@ -0,0 +1,93 @@
+package operations
+
+import (
+	"bytes"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"gosynctasks/backend"
+)
+
+func TestCSVRoundTrip(t *testing.T) {
+	due := time.Date(2026, 4, 1, 0, 0, 0, 0, time.Local)
+	start := time.Date(2026, 3, 20, 9, 30, 0, 0, time.UTC)
+	tasks := []backend.Task{
+		{UID: "plan", Summary: `Plan "Q2"; sprint`, Description: "line one\nline two", Status: "NEEDS-ACTION", Priority: 2, DueDate: &due, StartDate: &start, Categories: []string{"work", "team"}},
+		{UID: "review", Summary: "Review", Status: "COMPLETED", ParentUID: "plan"},
+	}
+
+	for _, delimiter := range []rune{',', ';'} {
+		var buf bytes.Buffer
+		if err := encodeCSV(&buf, tasks, delimiter); err != nil {
+			t.Fatalf("encodeCSV() error = %v", err)
+		}
+		out := buf.String()
+		header := strings.Join(csvColumns, string(delimiter)) + "\r\n"
+		if !strings.HasPrefix(out, header) {
+			t.Errorf("encodeCSV(%q) header = %q, want %q", delimiter, strings.SplitAfter(out, "\n")[0], header)
+		}
+		if !strings.Contains(out, `"Plan ""Q2""; sprint"`) || !strings.Contains(out, string(delimiter)+"TODO"+string(delimiter)) || !strings.Contains(out, "2026-04-01") {
+			t.Errorf("encodeCSV(%q) = %q, want quoted fields, display statuses and plain dates", delimiter, out)
+		}
+
+		decoded, err := decodeCSV(&buf, delimiter)
+		if err != nil {
+			t.Fatalf("decodeCSV() error = %v", err)
+		}
+		if len(decoded) != 2 {
+			t.Fatalf("decodeCSV() returned %d tasks, want 2", len(decoded))
+		}
+		got := decoded[0]
+		if got.Summary != tasks[0].Summary || got.Description != tasks[0].Description || got.Status != "TODO" || got.Priority != 2 ||
+			!slices.Equal(got.Categories, tasks[0].Categories) || !got.DueDate.Equal(due) || !got.StartDate.Equal(start) {
+			t.Errorf("decoded task = %+v, want %+v", got, tasks[0])
+		}
+		if decoded[1].ParentUID != "plan" || decoded[1].Status != "DONE" {
+			t.Errorf("decoded subtask = %+v", decoded[1])
+		}
+	}
+}
+
+// TestDecodeCSVPartial tests a spreadsheet with a few columns in its own
+// order, a byte order mark and a blank row
+func TestDecodeCSVPartial(t *testing.T) {
+	in := "\ufeffDue Date;Summary;Status\n2026-05-02;Write specs;D\n;;\n;Review specs\n"
+	tasks, err := decodeCSV(strings.NewReader(in), ';')
+	if err != nil {
+		t.Fatalf("decodeCSV() error = %v", err)
+	}
+	if len(tasks) != 2 {
+		t.Fatalf("decodeCSV() returned %d tasks, want 2: %+v", len(tasks), tasks)
+	}
+	if tasks[0].Summary != "Write specs" || tasks[0].Status != "D" || tasks[0].DueDate == nil || tasks[0].DueDate.Day() != 2 {
+		t.Errorf("tasks[0] = %+v", tasks[0])
+	}
+	if tasks[1].Summary != "Review specs" || tasks[1].DueDate != nil {
+		t.Errorf("tasks[1] = %+v", tasks[1])
+	}
+
+	for _, in := range []string{
+		"uid,due_date\nx,2026-01-01\n",
+		"summary,priority\nTask,urgent\n",
+		"summary,due_date\nTask,next week\n",
+	} {
+		if _, err := decodeCSV(strings.NewReader(in), ','); err == nil {
+			t.Errorf("decodeCSV(%q) error = nil, want one", in)
+		}
+	}
+}
+
+func TestParseDelimiter(t *testing.T) {
+	for in, want := range map[string]rune{",": ',', ";": ';', "tab": '\t', `\t`: '\t', "|": '|'} {
+		if got, err := parseDelimiter(in); err != nil || got != want {
+			t.Errorf("parseDelimiter(%q) = %q, %v, want %q", in, got, err, want)
+		}
+	}
+	for _, in := range []string{"", ";;", `"`, "\n"} {
+		if _, err := parseDelimiter(in); err == nil {
+			t.Errorf("parseDelimiter(%q) error = nil, want one", in)
+		}
+	}
+}
@@ -67,10 +67,17 @@ func TestParseDateFlag(t *testing.T) {
 		},
 		{
 			name:      "invalid format - text",
-			input:     "tomorrow",
+			input:     "someday",
 			expectNil: false,
 			expectErr: true,
 		},
+		{
+			name:      "relative word",
+			input:     "tomorrow",
+			expectNil: false,
+			expectErr: false,
+			expected:  time.Now().AddDate(0, 0, 1).Format("2006-01-02"),
+		},
 		{
 			name:      "leap year date",
 			input:     "2024-02-29",
@@ -0,0 +1,143 @@
+package operations
+
+import (
+	"fmt"
+	"gosynctasks/backend"
+	"gosynctasks/internal/utils"
+	"sort"
+)
+
+// DuplicateGroup is a set of tasks in one list that share a UID
+type DuplicateGroup struct {
+	ListID   string
+	ListName string
+	UID      string
+	Tasks    []backend.Task // In the order the backend returned them
+}
+
+// markDuplicateUIDs warns when several tasks in a list share a UID and returns
+// the tasks with occurrence markers on the duplicated ones, for display
+func markDuplicateUIDs(tasks []backend.Task, listName string) []backend.Task {
+	duplicates := backend.DuplicateUIDs(tasks)
+	if duplicates == nil {
+		return tasks
+	}
+
+	utils.Warnf("List '%s' has %d task UID(s) shared by several tasks; updates to them are refused until resolved with 'gosynctasks dedupe %s --by-uid'",
+		listName, len(duplicates), listName)
+	return backend.MarkDuplicateUIDs(tasks)
+}
+
+// requireUniqueUID refuses a UID-addressed write when the UID is shared by
+// several tasks in the list, since the backend cannot tell which one is meant
+func requireUniqueUID(taskManager backend.TaskManager, operation, listID, taskUID string) error {
+	tasks, err := taskManager.GetTasks(listID, nil)
+	if err != nil {
+		return fmt.Errorf("error checking task UID: %w", err)
+	}
+	if count := backend.DuplicateUIDs(tasks)[taskUID]; count > 1 {
+		return utils.WrapWithSuggestion(
+			backend.NewDuplicateUIDError(operation, listID, taskUID, count),
+			"Run 'gosynctasks dedupe <list> --by-uid' to give each copy its own UID",
+		)
+	}
+	return nil
+}
+
+// FindDuplicateUIDs returns the groups of tasks sharing a UID in the given lists
+func FindDuplicateUIDs(taskManager backend.TaskManager, lists []backend.TaskList) ([]DuplicateGroup, error) {
+	var groups []DuplicateGroup
+	for _, list := range lists {
+		tasks, err := taskManager.GetTasks(list.ID, nil)
+		if err != nil {
+			return nil, fmt.Errorf("error retrieving tasks from '%s': %w", list.Name, err)
+		}
+
+		duplicates := backend.DuplicateUIDs(tasks)
+		uids := make([]string, 0, len(duplicates))
+		for uid := range duplicates {
+			uids = append(uids, uid)
+		}
+		sort.Strings(uids)
+
+		for _, uid := range uids {
+			group := DuplicateGroup{ListID: list.ID, ListName: list.Name, UID: uid}
+			for _, task := range tasks {
+				if task.UID == uid {
+					group.Tasks = append(group.Tasks, task)
+				}
+			}
+			groups = append(groups, group)
+		}
+	}
+	return groups, nil
+}
+
+// DedupeByUID gives every task in the group but the first its own UID.
+// The other copies are re-added under new UIDs, all entries under the shared
+// UID are deleted, and the first copy is re-added under the original UID so
+// that subtasks stay attached to it. It returns the new UIDs.
+func DedupeByUID(taskManager backend.TaskManager, group DuplicateGroup) ([]string, error) {
+	if len(group.Tasks) < 2 {
+		return nil, nil
+	}
+
+	existing, err := taskManager.GetTasks(group.ListID, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving tasks: %w", err)
+	}
+	taken := make(map[string]bool, len(existing))
+	for _, task := range existing {
+		taken[task.UID] = true
+	}
+
+	var newUIDs []string
+	for i, task := range group.Tasks[1:] {
+		uid := freeDuplicateUID(group.UID, i+2, taken)
+		task.UID = uid
+		if _, err := taskManager.AddTask(group.ListID, task); err != nil {
+			return newUIDs, fmt.Errorf("error re-adding copy %d of %s: %w", i+2, group.UID, err)
+		}
+		taken[uid] = true
+		newUIDs = append(newUIDs, uid)
+	}
+
+	// Backends resolve a UID to one entry at a time, so delete until none is left
+	for range group.Tasks {
+		if err := taskManager.DeleteTask(group.ListID, group.UID); err != nil {
+			if backendErr, ok := err.(*backend.BackendError); ok && backendErr.IsNotFound() {
+				break
+			}
+			return newUIDs, fmt.Errorf("error removing duplicates of %s: %w", group.UID, err)
+		}
+	}
+
+	remaining, err := taskManager.GetTasks(group.ListID, nil)
+	if err != nil {
+		return newUIDs, fmt.Errorf("error retrieving tasks: %w", err)
+	}
+	for _, task := range remaining {
+		if task.UID == group.UID {
+			return newUIDs, utils.WrapWithSuggestion(
+				fmt.Errorf("could not remove all entries with UID %s", group.UID),
+				"The copies were re-added under new UIDs; remove the remaining entries on the server",
+			)
+		}
+	}
+
+	if _, err := taskManager.AddTask(group.ListID, group.Tasks[0]); err != nil {
+		return newUIDs, fmt.Errorf("error restoring %s: %w", group.UID, err)
+	}
+	return newUIDs, nil
+}
+
+// freeDuplicateUID derives an unused UID for the nth copy of uid
+func freeDuplicateUID(uid string, n int, taken map[string]bool) string {
+	for {
+		candidate := fmt.Sprintf("%s-%d", uid, n)
+		if !taken[candidate] {
+			return candidate
+		}
+		n++
+	}
+}
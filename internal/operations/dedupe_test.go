@@ -0,0 +1,94 @@
+package operations
+
+import (
+	"errors"
+	"gosynctasks/backend"
+	"testing"
+)
+
+func newDuplicateBackend() (*backend.MockBackend, []backend.TaskList) {
+	mb := backend.NewMockBackend()
+	mb.Tasks["work"] = []backend.Task{
+		{UID: "a", Summary: "Original", Status: "NEEDS-ACTION"},
+		{UID: "child", Summary: "Subtask", ParentUID: "a", Status: "NEEDS-ACTION"},
+		{UID: "a", Summary: "Copy", Status: "NEEDS-ACTION"},
+		{UID: "a-2", Summary: "Unrelated", Status: "NEEDS-ACTION"},
+		{UID: "a", Summary: "Another copy", Status: "NEEDS-ACTION"},
+	}
+	return mb, []backend.TaskList{{ID: "work", Name: "Work"}}
+}
+
+func TestFindDuplicateUIDs(t *testing.T) {
+	mb, lists := newDuplicateBackend()
+
+	groups, err := FindDuplicateUIDs(mb, lists)
+	if err != nil {
+		t.Fatalf("FindDuplicateUIDs() error = %v", err)
+	}
+	if len(groups) != 1 {
+		t.Fatalf("expected 1 group, got %d", len(groups))
+	}
+	if groups[0].UID != "a" || len(groups[0].Tasks) != 3 || groups[0].Tasks[0].Summary != "Original" {
+		t.Errorf("unexpected group: %+v", groups[0])
+	}
+}
+
+func TestRequireUniqueUID(t *testing.T) {
+	mb, _ := newDuplicateBackend()
+
+	if err := requireUniqueUID(mb, "UpdateTask", "work", "child"); err != nil {
+		t.Errorf("unique UID rejected: %v", err)
+	}
+
+	err := requireUniqueUID(mb, "UpdateTask", "work", "a")
+	var backendErr *backend.BackendError
+	if !errors.As(err, &backendErr) || !backendErr.IsDuplicateUID() {
+		t.Errorf("expected duplicate UID error, got %v", err)
+	}
+}
+
+func TestDedupeByUID(t *testing.T) {
+	mb, lists := newDuplicateBackend()
+	groups, _ := FindDuplicateUIDs(mb, lists)
+
+	newUIDs, err := DedupeByUID(mb, groups[0])
+	if err != nil {
+		t.Fatalf("DedupeByUID() error = %v", err)
+	}
+	if len(newUIDs) != 2 || newUIDs[0] != "a-3" || newUIDs[1] != "a-4" {
+		t.Errorf("expected new UIDs [a-3 a-4] (a-2 is taken), got %v", newUIDs)
+	}
+
+	tasks := mb.Tasks["work"]
+	if dups := backend.DuplicateUIDs(tasks); dups != nil {
+		t.Fatalf("duplicates remain: %v", dups)
+	}
+
+	summaries := make(map[string]string)
+	for _, task := range tasks {
+		summaries[task.UID] = task.Summary
+	}
+	want := map[string]string{"a": "Original", "child": "Subtask", "a-2": "Unrelated", "a-3": "Copy", "a-4": "Another copy"}
+	if len(summaries) != len(want) {
+		t.Errorf("got tasks %v, want %v", summaries, want)
+	}
+	for uid, summary := range want {
+		if summaries[uid] != summary {
+			t.Errorf("task %s: got %q, want %q", uid, summaries[uid], summary)
+		}
+	}
+}
+
+func TestFindReplacementsSkipsDuplicateUIDs(t *testing.T) {
+	mb, lists := newDuplicateBackend()
+
+	changes, err := FindReplacements(mb, lists, ReplaceOptions{Pattern: "Subtask", Replacement: "Step"})
+	if err != nil || len(changes) != 1 || changes[0].Err != nil {
+		t.Fatalf("unique task should be replaceable: %v %+v", err, changes)
+	}
+
+	changes, _ = FindReplacements(mb, lists, ReplaceOptions{Pattern: "Copy", Replacement: "Duplicate"})
+	if len(changes) != 1 || changes[0].Err == nil {
+		t.Errorf("expected change to duplicated UID to be rejected, got %+v", changes)
+	}
+}
@@ -0,0 +1,234 @@
+package operations
+
+import (
+	"errors"
+	"fmt"
+	"gosynctasks/backend"
+	"gosynctasks/internal/utils"
+	"io"
+	"strings"
+	"time"
+)
+
+// errExplainDeclined is returned for a write the user chose not to send
+var errExplainDeclined = errors.New("not sent, cancelled after --explain")
+
+// explainingTaskManager shows each write before passing it to the wrapped
+// task manager: the task field by field, and the requests an HTTP backend
+// will make. Unless yes is set, it asks whether to proceed. Tasks read
+// through it are remembered, so updates are explained against the task as
+// the command found it.
+type explainingTaskManager struct {
+	backend.TaskManager
+	out  io.Writer
+	yes  bool
+	read map[string]backend.Task // By UID
+}
+
+// NewExplainingTaskManager wraps taskManager for --explain, printing to out.
+// Optional interfaces of taskManager are not exposed.
+func NewExplainingTaskManager(taskManager backend.TaskManager, out io.Writer, yes bool) backend.TaskManager {
+	return &explainingTaskManager{TaskManager: taskManager, out: out, yes: yes, read: make(map[string]backend.Task)}
+}
+
+func (e *explainingTaskManager) remember(tasks []backend.Task) {
+	for _, task := range tasks {
+		if _, ok := e.read[task.UID]; !ok {
+			e.read[task.UID] = task
+		}
+	}
+}
+
+func (e *explainingTaskManager) GetTasks(listID string, taskFilter *backend.TaskFilter) ([]backend.Task, error) {
+	tasks, err := e.TaskManager.GetTasks(listID, taskFilter)
+	e.remember(tasks)
+	return tasks, err
+}
+
+func (e *explainingTaskManager) FindTasksBySummary(listID string, summary string) ([]backend.Task, error) {
+	tasks, err := e.TaskManager.FindTasksBySummary(listID, summary)
+	e.remember(tasks)
+	return tasks, err
+}
+
+func (e *explainingTaskManager) GetTask(listID string, taskUID string) (*backend.Task, error) {
+	task, err := e.TaskManager.GetTask(listID, taskUID)
+	if task != nil {
+		e.remember([]backend.Task{*task})
+	}
+	return task, err
+}
+
+func (e *explainingTaskManager) AddTask(listID string, task backend.Task) (string, error) {
+	if err := e.explain("AddTask", listID, task, nil); err != nil {
+		return "", err
+	}
+	return e.TaskManager.AddTask(listID, task)
+}
+
+func (e *explainingTaskManager) UpdateTask(listID string, task backend.Task) error {
+	var previous *backend.Task
+	if read, ok := e.read[task.UID]; ok {
+		previous = &read
+	}
+	if err := e.explain("UpdateTask", listID, task, previous); err != nil {
+		return err
+	}
+	if err := e.TaskManager.UpdateTask(listID, task); err != nil {
+		return err
+	}
+	e.read[task.UID] = task
+	return nil
+}
+
+func (e *explainingTaskManager) DeleteTask(listID string, taskUID string) error {
+	return e.delete("DeleteTask", listID, taskUID, e.TaskManager.DeleteTask)
+}
+
+// ForceDeleteTask passes delete --force on to the wrapped backend, which
+// deletes as usual when it makes no checks to skip
+func (e *explainingTaskManager) ForceDeleteTask(listID string, taskUID string) error {
+	if forceDeleter, ok := backend.As[backend.ForceDeleter](e.TaskManager); ok {
+		return e.delete("ForceDeleteTask", listID, taskUID, forceDeleter.ForceDeleteTask)
+	}
+	return e.DeleteTask(listID, taskUID)
+}
+
+func (e *explainingTaskManager) delete(method, listID, taskUID string, deleteTask func(listID, taskUID string) error) error {
+	task := backend.Task{UID: taskUID}
+	if read, ok := e.read[taskUID]; ok {
+		task = read
+	}
+	if err := e.explain(method, listID, task, nil); err != nil {
+		return err
+	}
+	return deleteTask(listID, taskUID)
+}
+
+// explain prints what a write sends and asks whether to proceed
+func (e *explainingTaskManager) explain(method, listID string, task backend.Task, previous *backend.Task) error {
+	fmt.Fprintf(e.out, "%s in list %s:\n", method, listID)
+	if method == "DeleteTask" || method == "ForceDeleteTask" {
+		fmt.Fprintf(e.out, "  %-12s %s\n", "UID", explainValue(task.UID))
+		fmt.Fprintf(e.out, "  %-12s %s\n", "Summary", explainValue(task.Summary))
+	} else {
+		for _, field := range ExplainTask(task, previous, e.TaskManager) {
+			fmt.Fprintf(e.out, "  %-12s %-30s %s\n", field.Name, field.Value, field.Note)
+		}
+	}
+	if describer, ok := backend.As[backend.RequestDescriber](e.TaskManager); ok {
+		for _, request := range describer.DescribeRequests(method, listID, task) {
+			fmt.Fprintf(e.out, "  → %s\n", request)
+		}
+	}
+
+	if e.yes {
+		return nil
+	}
+	proceed, err := utils.PromptConfirmation("Proceed?")
+	if err != nil {
+		return err
+	}
+	if !proceed {
+		return errExplainDeclined
+	}
+	return nil
+}
+
+// ExplainedField is one field of a task a write sends, as --explain shows it
+type ExplainedField struct {
+	Name  string
+	Value string
+	Note  string // "will set", "not set", "unchanged" or "will set (was ...)"
+}
+
+// ExplainTask returns the fields of task as a write sends them. Without a
+// previous version (a new task), fields are noted as set or not; otherwise
+// as unchanged or changed from their previous value. taskManager, when not
+// nil, adds the display name of the status.
+func ExplainTask(task backend.Task, previous *backend.Task, taskManager backend.TaskManager) []ExplainedField {
+	status := func(t backend.Task) string {
+		if taskManager == nil || t.Status == "" {
+			return explainValue(t.Status)
+		}
+		return fmt.Sprintf("%s (%s)", t.Status, taskManager.StatusToDisplayName(t.Status))
+	}
+	values := func(t backend.Task) []string {
+		return []string{
+			explainValue(t.UID),
+			explainValue(t.Summary),
+			explainValue(t.Description),
+			status(t),
+			explainValue(t.Priority),
+			explainValue(t.DueDate),
+			explainValue(t.StartDate),
+			explainValue(t.Completed),
+			explainValue(t.Created),
+			explainValue(t.Modified),
+			explainValue(t.ParentUID),
+			explainValue(t.Categories),
+			explainValue(t.Recurrence),
+		}
+	}
+	names := []string{"UID", "Summary", "Description", "Status", "Priority", "DueDate", "StartDate",
+		"Completed", "Created", "Modified", "ParentUID", "Categories", "Recurrence"}
+
+	current := values(task)
+	var before []string
+	if previous != nil {
+		before = values(*previous)
+	}
+
+	fields := make([]ExplainedField, len(names))
+	for i, name := range names {
+		field := ExplainedField{Name: name, Value: current[i]}
+		switch {
+		case previous == nil && current[i] == explainNone:
+			field.Note = "not set"
+		case previous == nil:
+			field.Note = "will set"
+		case current[i] == before[i]:
+			field.Note = "unchanged"
+		default:
+			field.Note = fmt.Sprintf("will set (was %s)", before[i])
+		}
+		fields[i] = field
+	}
+	return fields
+}
+
+// explainNone is how --explain shows an empty field
+const explainNone = "(none)"
+
+// explainValue formats a task field value for --explain
+func explainValue(value any) string {
+	switch v := value.(type) {
+	case string:
+		if v == "" {
+			return explainNone
+		}
+		return fmt.Sprintf("%q", utils.SanitizeLine(v))
+	case int:
+		if v == 0 {
+			return explainNone
+		}
+		return fmt.Sprintf("%d", v)
+	case *time.Time:
+		if v == nil {
+			return explainNone
+		}
+		return explainValue(*v)
+	case time.Time:
+		if v.IsZero() {
+			return explainNone
+		}
+		return v.Format(time.RFC3339)
+	case []string:
+		if len(v) == 0 {
+			return explainNone
+		}
+		return utils.SanitizeLine(strings.Join(v, ", "))
+	default:
+		return fmt.Sprint(v)
+	}
+}
@@ -0,0 +1,176 @@
+package operations
+
+import (
+	"bytes"
+	"gosynctasks/backend"
+	"gosynctasks/internal/config"
+	"os"
+	"strings"
+	"testing"
+)
+
+// sendingBackend records the tasks writes send, and describes its requests
+// like an HTTP backend
+type sendingBackend struct {
+	*backend.MockBackend
+	sent []backend.Task
+}
+
+func (s *sendingBackend) AddTask(listID string, task backend.Task) (string, error) {
+	s.sent = append(s.sent, task)
+	return s.MockBackend.AddTask(listID, task)
+}
+
+func (s *sendingBackend) UpdateTask(listID string, task backend.Task) error {
+	s.sent = append(s.sent, task)
+	return s.MockBackend.UpdateTask(listID, task)
+}
+
+func (s *sendingBackend) DescribeRequests(method, listID string, task backend.Task) []string {
+	return []string{"PUT https://dav.example.com/" + listID + "/" + task.UID + ".ics"}
+}
+
+// explainedFields parses the field lines --explain printed
+func explainedFields(output string) map[string]ExplainedField {
+	fields := make(map[string]ExplainedField)
+	for _, line := range strings.Split(output, "\n") {
+		if !strings.HasPrefix(line, "  ") || strings.HasPrefix(line, "  →") {
+			continue
+		}
+		line = strings.TrimSpace(line)
+		name, rest, _ := strings.Cut(line, " ")
+		rest = strings.TrimSpace(rest)
+		for _, note := range []string{"will set", "not set", "unchanged"} {
+			if i := strings.LastIndex(rest, note); i >= 0 {
+				fields[name] = ExplainedField{Name: name, Value: strings.TrimSpace(rest[:i]), Note: rest[i:]}
+				break
+			}
+		}
+	}
+	return fields
+}
+
+// TestExplainMatchesSentTask runs writes with --explain --yes and checks the
+// explained values are the ones the backend received
+func TestExplainMatchesSentTask(t *testing.T) {
+	config.SetConfigForTest(&config.Config{})
+	lists := []backend.TaskList{{ID: "list-1", Name: "Chores"}}
+
+	tests := []struct {
+		name  string
+		args  []string
+		flags map[string]string
+		want  map[string]string // Field notes
+	}{
+		{
+			name:  "add",
+			args:  []string{"Chores", "add", "Water plants"},
+			flags: map[string]string{"priority": "3", "due-date": "2026-07-10"},
+			want:  map[string]string{"Summary": "will set", "Priority": "will set", "DueDate": "will set", "Description": "not set"},
+		},
+		{
+			name:  "update to the same priority",
+			args:  []string{"Chores", "update", "Buy milk"},
+			flags: map[string]string{"priority": "5", "summary": "Buy oat milk"},
+			want:  map[string]string{"Priority": "unchanged", "Summary": `will set (was "Buy milk")`, "Status": "unchanged"},
+		},
+		{
+			name: "complete",
+			args: []string{"Chores", "complete", "Buy milk"},
+			want: map[string]string{"Status": "will set (was NEEDS-ACTION (TODO))", "Summary": "unchanged"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tm := &sendingBackend{MockBackend: backend.NewMockBackend()}
+			tm.Tasks["list-1"] = []backend.Task{{UID: "t1", Summary: "Buy milk", Status: "NEEDS-ACTION", Priority: 5}}
+
+			cmd := newActionCmd()
+			cmd.Flags().Bool("explain", false, "")
+			cmd.Flags().Bool("yes", false, "")
+			cmd.Flags().Bool("with-children", false, "")
+			var out bytes.Buffer
+			cmd.SetOut(&out)
+			for name, value := range map[string]string{"explain": "true", "yes": "true"} {
+				_ = cmd.Flags().Set(name, value)
+			}
+			for name, value := range tt.flags {
+				if err := cmd.Flags().Set(name, value); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if err := ExecuteAction(tm, &config.Config{}, lists, cmd, tt.args, nil); err != nil {
+				t.Fatalf("ExecuteAction() error = %v", err)
+			}
+			if len(tm.sent) != 1 {
+				t.Fatalf("sent %d tasks, want 1", len(tm.sent))
+			}
+
+			explained := explainedFields(out.String())
+			for _, field := range ExplainTask(tm.sent[0], nil, tm) {
+				if got := explained[field.Name].Value; got != field.Value {
+					t.Errorf("explained %s = %q, sent %q\n%s", field.Name, got, field.Value, out.String())
+				}
+			}
+			for name, note := range tt.want {
+				if got := explained[name].Note; got != note {
+					t.Errorf("%s note = %q, want %q", name, got, note)
+				}
+			}
+			if !strings.Contains(out.String(), "→ PUT https://dav.example.com/list-1/") {
+				t.Errorf("request not explained:\n%s", out.String())
+			}
+		})
+	}
+}
+
+// TestExplainDeclined tests that nothing is sent when the user says no
+func TestExplainDeclined(t *testing.T) {
+	tm := &sendingBackend{MockBackend: backend.NewMockBackend()}
+	var out bytes.Buffer
+	explaining := NewExplainingTaskManager(tm, &out, false)
+
+	reader, writer, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdin := os.Stdin
+	os.Stdin = reader
+	defer func() { os.Stdin = stdin }()
+	_, _ = writer.WriteString("n\n")
+	_ = writer.Close()
+
+	if _, err := explaining.AddTask("list-1", backend.Task{Summary: "Water plants"}); err != errExplainDeclined {
+		t.Fatalf("AddTask() error = %v, want errExplainDeclined", err)
+	}
+	if len(tm.sent) != 0 {
+		t.Errorf("declined write was sent: %+v", tm.sent)
+	}
+}
+
+// TestExplainForceDelete tests that delete --force is passed on to backends
+// that have ForceDeleteTask, and deletes as usual on the others
+func TestExplainForceDelete(t *testing.T) {
+	mb := backend.NewMockBackend()
+	mb.Tasks["list-1"] = []backend.Task{{UID: "t1", Summary: "Buy milk"}, {UID: "t2", Summary: "Buy bread"}}
+	forcing := &forceDeletingBackend{MockBackend: mb}
+	var out bytes.Buffer
+
+	explaining := NewExplainingTaskManager(forcing, &out, true).(backend.ForceDeleter)
+	if err := explaining.ForceDeleteTask("list-1", "t1"); err != nil {
+		t.Fatalf("ForceDeleteTask() error = %v", err)
+	}
+	if len(forcing.forced) != 1 || !strings.Contains(out.String(), "ForceDeleteTask in list list-1") {
+		t.Errorf("ForceDeleteTask() forced %v and explained %q", forcing.forced, out.String())
+	}
+
+	plain := NewExplainingTaskManager(mb, &out, true).(backend.ForceDeleter)
+	if err := plain.ForceDeleteTask("list-1", "t2"); err != nil {
+		t.Fatalf("ForceDeleteTask() without the backend's error = %v", err)
+	}
+	if len(mb.Tasks["list-1"]) != 0 {
+		t.Errorf("tasks left = %+v, want none", mb.Tasks["list-1"])
+	}
+}
@@ -0,0 +1,100 @@
+package operations
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"gosynctasks/backend"
+	"gosynctasks/internal/config"
+	"gosynctasks/internal/ical"
+	"gosynctasks/internal/utils"
+
+	"github.com/spf13/cobra"
+)
+
+// fileOptions are the flags shaping an exported or imported file
+type fileOptions struct {
+	delimiter           rune // Field separator of csv
+	includeDescriptions bool // Quote descriptions in md
+}
+
+// exporter writes the tasks of a list in one format
+type exporter func(w io.Writer, list *backend.TaskList, tasks []backend.Task, opts fileOptions) error
+
+// exporters are the formats of export, by --format name
+var exporters = map[string]exporter{
+	"ics": func(w io.Writer, list *backend.TaskList, tasks []backend.Task, _ fileOptions) error {
+		return ical.Encode(w, list.Name, tasks)
+	},
+	"csv": func(w io.Writer, _ *backend.TaskList, tasks []backend.Task, opts fileOptions) error {
+		return encodeCSV(w, tasks, opts.delimiter)
+	},
+	"md": func(w io.Writer, list *backend.TaskList, tasks []backend.Task, opts fileOptions) error {
+		return encodeMarkdown(w, list, tasks, opts.includeDescriptions)
+	},
+}
+
+// getFileOptions reads the flags shaping exported and imported files
+func getFileOptions(cmd *cobra.Command) (fileOptions, error) {
+	delimiter, _ := cmd.Flags().GetString("delimiter")
+	comma, err := parseDelimiter(delimiter)
+	if err != nil {
+		return fileOptions{}, err
+	}
+	includeDescriptions, _ := cmd.Flags().GetBool("include-descriptions")
+	return fileOptions{delimiter: comma, includeDescriptions: includeDescriptions}, nil
+}
+
+// exportFormats lists the --format names of export, as "csv, ics"
+func exportFormats() string {
+	names := make([]string, 0, len(exporters))
+	for name := range exporters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
+
+// HandleExportAction writes every task of a list, completed ones included, in
+// the format of --format to the file of --output, or to stdout
+func HandleExportAction(cmd *cobra.Command, taskManager backend.TaskManager, cfg *config.Config, selectedList *backend.TaskList) error {
+	format, _ := cmd.Flags().GetString("format")
+	output, _ := cmd.Flags().GetString("output")
+	export, ok := exporters[strings.ToLower(format)]
+	if !ok {
+		return fmt.Errorf("unsupported export format %q (supported: %s)", format, exportFormats())
+	}
+	opts, err := getFileOptions(cmd)
+	if err != nil {
+		return err
+	}
+
+	tasks, err := taskManager.GetTasks(selectedList.ID, nil)
+	if err != nil {
+		return fmt.Errorf("failed to get tasks: %w", err)
+	}
+	taskManager.SortTasks(tasks)
+	if note := RetentionNote(cfg, nil); note != "" {
+		utils.Warnf("%s", note)
+	}
+
+	if output == "" || output == "-" {
+		return export(cmd.OutOrStdout(), selectedList, tasks, opts)
+	}
+	file, err := os.Create(output)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", output, err)
+	}
+	if err := export(file, selectedList, tasks, opts); err != nil {
+		_ = file.Close()
+		return fmt.Errorf("failed to write %s: %w", output, err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to write %s: %w", output, err)
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "Exported %d tasks from %s to %s\n", len(tasks), utils.SanitizeLine(selectedList.Name), output)
+	return nil
+}
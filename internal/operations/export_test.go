@@ -0,0 +1,60 @@
+package operations
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gosynctasks/backend"
+	"gosynctasks/internal/config"
+)
+
+// TestHandleExportAction tests exporting a list to a file and to stdout
+func TestHandleExportAction(t *testing.T) {
+	config.SetConfigForTest(&config.Config{})
+	mb := backend.NewMockBackend()
+	listID, _ := mb.CreateTaskList("Chores", "", "")
+	lists, _ := mb.GetTaskLists()
+	mb.AddTask(listID, backend.Task{UID: "laundry", Summary: "Laundry", Status: "NEEDS-ACTION"})
+	mb.AddTask(listID, backend.Task{UID: "socks", Summary: "Sort socks", Status: "COMPLETED", ParentUID: "laundry"})
+
+	path := filepath.Join(t.TempDir(), "chores.ics")
+	cmd := newActionCmd()
+	var out strings.Builder
+	cmd.SetOut(&out)
+	cmd.Flags().Set("output", path)
+	if err := ExecuteAction(mb, &config.Config{}, lists, cmd, []string{"Chores", "export"}, nil); err != nil {
+		t.Fatalf("export failed: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("export wrote no file: %v", err)
+	}
+	for _, want := range []string{"X-WR-CALNAME:Chores", "UID:laundry", "UID:socks", "RELATED-TO:laundry", "STATUS:COMPLETED"} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("exported file lacks %q", want)
+		}
+	}
+	if !strings.Contains(out.String(), "Exported 2 tasks") {
+		t.Errorf("output = %q, want the count of exported tasks", out.String())
+	}
+
+	// Without --output, the calendar goes to stdout
+	cmd = newActionCmd()
+	out.Reset()
+	cmd.SetOut(&out)
+	if err := ExecuteAction(mb, &config.Config{}, lists, cmd, []string{"Chores", "export"}, nil); err != nil {
+		t.Fatalf("export to stdout failed: %v", err)
+	}
+	if !strings.HasPrefix(out.String(), "BEGIN:VCALENDAR") {
+		t.Errorf("stdout = %q, want the calendar", out.String())
+	}
+
+	cmd = newActionCmd()
+	cmd.Flags().Set("format", "pdf")
+	err = ExecuteAction(mb, &config.Config{}, lists, cmd, []string{"Chores", "export"}, nil)
+	if err == nil || !strings.Contains(err.Error(), "unsupported export format") {
+		t.Errorf("export --format pdf error = %v, want unsupported format", err)
+	}
+}
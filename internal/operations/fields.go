@@ -0,0 +1,80 @@
+package operations
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"gosynctasks/backend"
+	"gosynctasks/internal/utils"
+	"gosynctasks/internal/views"
+)
+
+// parseFieldsFlag parses --fields against the field registry
+func parseFieldsFlag(spec string) ([]string, error) {
+	fields, err := views.ParseFieldList(spec)
+	var validationErr *views.ValidationError
+	if errors.As(err, &validationErr) {
+		return nil, utils.WrapWithSuggestion(fmt.Errorf("--fields: %s", validationErr.Message), validationErr.Hint)
+	}
+	return fields, err
+}
+
+// fieldsView is the view --fields shows in a table: one line per task with
+// exactly the given fields, in the given order
+func fieldsView(fields []string) *views.View {
+	show := true
+	view := &views.View{
+		Name:       "fields",
+		FieldOrder: fields,
+		Display:    views.DisplayOptions{CompactMode: true},
+	}
+	for _, name := range fields {
+		view.Fields = append(view.Fields, views.FieldConfig{
+			Name:   name,
+			Format: views.GetDefaultFormat(name),
+			Color:  true,
+			Show:   &show,
+		})
+	}
+	return view
+}
+
+// taskFields is a task as a JSON object of some of its fields, keys in order
+type taskFields struct {
+	task   backend.Task
+	fields []string
+}
+
+func (t taskFields) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, name := range t.fields {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		key, _ := json.Marshal(name)
+		value, err := json.Marshal(views.FieldRegistry[name].Value(t.task))
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// marshalTasksJSON returns tasks as a JSON array of objects with the given
+// fields, every field when none are given
+func marshalTasksJSON(tasks []backend.Task, fields []string) ([]byte, error) {
+	if len(fields) == 0 {
+		fields = views.FieldNames()
+	}
+	objects := make([]taskFields, len(tasks))
+	for i, task := range tasks {
+		objects[i] = taskFields{task: task, fields: fields}
+	}
+	return utils.MarshalJSON(objects)
+}
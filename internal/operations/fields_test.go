@@ -0,0 +1,62 @@
+package operations
+
+import (
+	"encoding/json"
+	"gosynctasks/backend"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMarshalTasksJSONFields(t *testing.T) {
+	due := time.Date(2026, 7, 10, 0, 0, 0, 0, time.UTC)
+	tasks := []backend.Task{
+		{UID: "t1", Summary: "Water plants", Status: "NEEDS-ACTION", DueDate: &due, Categories: []string{"home"}},
+		{UID: "t2", Summary: "Buy milk", Status: "COMPLETED"},
+	}
+
+	data, err := marshalTasksJSON(tasks, []string{"summary", "uid", "due_date", "tags"})
+	if err != nil {
+		t.Fatalf("marshalTasksJSON() error = %v", err)
+	}
+	compact := strings.Join(strings.Fields(string(data)), "")
+	want := `[{"summary":"Water plants","uid":"t1","due_date":"2026-07-10T00:00:00Z","tags":["home"]},` +
+		`{"summary":"Buy milk","uid":"t2","due_date":null,"tags":[]}]`
+	if compact != strings.Join(strings.Fields(want), "") {
+		t.Errorf("marshalTasksJSON() =\n%s\nwant\n%s", compact, want)
+	}
+
+	// Without --fields every registry field is output
+	data, _ = marshalTasksJSON(tasks[:1], nil)
+	var objects []map[string]any
+	if err := json.Unmarshal(data, &objects); err != nil {
+		t.Fatal(err)
+	}
+	if len(objects[0]) != 12 || objects[0]["parent"] != "" || objects[0]["priority"] != float64(0) {
+		t.Errorf("all fields = %v", objects[0])
+	}
+}
+
+func TestFieldsViewColumnOrder(t *testing.T) {
+	tasks := []backend.Task{{UID: "task-uid-1", Summary: "Water plants", Status: "NEEDS-ACTION", Priority: 3}}
+
+	rendered := RenderWithView(tasks, fieldsView([]string{"priority", "summary"}), backend.NewMockBackend(), "2006-01-02", true, false)
+	lines := strings.Split(strings.TrimRight(rendered, "\n"), "\n")
+	if len(lines) != 1 {
+		t.Fatalf("rendered %d lines, want one line per task:\n%s", len(lines), rendered)
+	}
+	summary, priority := strings.Index(lines[0], "Water plants"), strings.Index(lines[0], "3")
+	if priority < 0 || summary < 0 || priority > summary {
+		t.Errorf("columns not in --fields order: %q", lines[0])
+	}
+	if strings.Contains(lines[0], "task-uid") {
+		t.Errorf("unselected field shown: %q", lines[0])
+	}
+}
+
+func TestParseFieldsFlagSuggestion(t *testing.T) {
+	_, err := parseFieldsFlag("summary,duedate")
+	if err == nil || !strings.Contains(err.Error(), "unknown field 'duedate'") || !strings.Contains(err.Error(), "Did you mean 'due_date'?") {
+		t.Errorf("parseFieldsFlag() error = %v, want an unknown field error suggesting due_date", err)
+	}
+}
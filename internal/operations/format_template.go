@@ -0,0 +1,51 @@
+package operations
+
+import (
+	"fmt"
+	"gosynctasks/backend"
+	"gosynctasks/internal/config"
+	"gosynctasks/internal/views"
+	"strings"
+	"text/template"
+)
+
+// resolveFormatTemplate returns the template text for a --format-template value.
+// Values starting with '@' name a template from the config's templates section.
+func resolveFormatTemplate(cfg *config.Config, value string) (string, error) {
+	if !strings.HasPrefix(value, "@") {
+		return value, nil
+	}
+
+	name := strings.TrimPrefix(value, "@")
+	if cfg != nil {
+		if text, ok := cfg.Templates[name]; ok {
+			return text, nil
+		}
+	}
+	return "", fmt.Errorf("format template '%s' not found in config templates", name)
+}
+
+// RenderWithFormatTemplate renders each task in tree order with the template,
+// one line per task
+func RenderWithFormatTemplate(nodes []*TaskNode, tmpl *template.Template, taskManager backend.TaskManager, listName string) (string, error) {
+	var result strings.Builder
+	if err := formatNodeWithTemplate(&result, nodes, 0, tmpl, taskManager, listName); err != nil {
+		return "", err
+	}
+	return result.String(), nil
+}
+
+func formatNodeWithTemplate(result *strings.Builder, nodes []*TaskNode, depth int, tmpl *template.Template, taskManager backend.TaskManager, listName string) error {
+	for _, node := range nodes {
+		ctx := views.NewTemplateContext(*node.Task, taskManager, listName, depth)
+		if err := tmpl.Execute(result, ctx); err != nil {
+			return fmt.Errorf("error rendering task '%s': %w", node.Task.Summary, err)
+		}
+		result.WriteString("\n")
+
+		if err := formatNodeWithTemplate(result, node.Children, depth+1, tmpl, taskManager, listName); err != nil {
+			return err
+		}
+	}
+	return nil
+}
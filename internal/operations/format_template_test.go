@@ -0,0 +1,45 @@
+package operations
+
+import (
+	"gosynctasks/backend"
+	"gosynctasks/internal/config"
+	"gosynctasks/internal/views"
+	"testing"
+)
+
+func TestResolveFormatTemplate(t *testing.T) {
+	cfg := &config.Config{Templates: map[string]string{"short": "{{.Summary}}"}}
+
+	if got, err := resolveFormatTemplate(cfg, "@short"); err != nil || got != "{{.Summary}}" {
+		t.Errorf("resolveFormatTemplate(@short) = %q, %v", got, err)
+	}
+	if got, err := resolveFormatTemplate(cfg, "{{.UID}}"); err != nil || got != "{{.UID}}" {
+		t.Errorf("resolveFormatTemplate(inline) = %q, %v", got, err)
+	}
+	if _, err := resolveFormatTemplate(cfg, "@missing"); err == nil {
+		t.Error("resolveFormatTemplate(@missing) expected error")
+	}
+}
+
+func TestRenderWithFormatTemplate_Hierarchy(t *testing.T) {
+	mb := backend.NewMockBackend()
+	tasks := []backend.Task{
+		{UID: "p", Summary: "Parent", Status: "NEEDS-ACTION"},
+		{UID: "c", Summary: "Child", Status: "COMPLETED", ParentUID: "p"},
+	}
+
+	tmpl, err := views.ParseFormatTemplate("{{.IndentPrefix}}{{.StatusSymbol}} {{.Summary}} [{{.List}}]")
+	if err != nil {
+		t.Fatalf("ParseFormatTemplate() error = %v", err)
+	}
+
+	got, err := RenderWithFormatTemplate(BuildTaskTree(tasks), tmpl, mb, "Work")
+	if err != nil {
+		t.Fatalf("RenderWithFormatTemplate() error = %v", err)
+	}
+
+	want := "○ Parent [Work]\n  ✓ Child [Work]\n"
+	if got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,215 @@
+package operations
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gosynctasks/backend"
+	"gosynctasks/internal/config"
+	"gosynctasks/internal/ical"
+	"gosynctasks/internal/utils"
+
+	"github.com/spf13/cobra"
+)
+
+// importer reads the tasks of a file in one format
+type importer func(r io.Reader, opts fileOptions) ([]backend.Task, error)
+
+// importers are the formats of import, by --format name
+var importers = map[string]importer{
+	"ics": func(r io.Reader, _ fileOptions) ([]backend.Task, error) {
+		return ical.Decode(r)
+	},
+	"csv": func(r io.Reader, opts fileOptions) ([]backend.Task, error) {
+		return decodeCSV(r, opts.delimiter)
+	},
+}
+
+// importFormat returns the format to read a file in: --format when given,
+// else the one its extension names, else ics
+func importFormat(cmd *cobra.Command, path string) string {
+	format, _ := cmd.Flags().GetString("format")
+	if cmd.Flags().Changed("format") {
+		return strings.ToLower(format)
+	}
+	if ext := strings.ToLower(strings.TrimPrefix(filepath.Ext(path), ".")); importers[ext] != nil {
+		return ext
+	}
+	return "ics"
+}
+
+// HandleImportAction adds the tasks of a file to a list, parents before their
+// subtasks. A task whose UID the list already has is a conflict: with
+// --skip-existing it is left alone, with --overwrite the task of the list is
+// updated from it, and otherwise nothing is imported and the conflicts are
+// listed.
+func HandleImportAction(cmd *cobra.Command, taskManager backend.TaskManager, selectedList *backend.TaskList, path string) error {
+	if path == "" {
+		return fmt.Errorf("import needs a file: gosynctasks %s import tasks.ics", selectedList.Name)
+	}
+	skipExisting, _ := cmd.Flags().GetBool("skip-existing")
+	overwrite, _ := cmd.Flags().GetBool("overwrite")
+	if skipExisting && overwrite {
+		return fmt.Errorf("--skip-existing and --overwrite can't be used together")
+	}
+	format := importFormat(cmd, path)
+	decode, ok := importers[format]
+	if !ok {
+		return fmt.Errorf("unsupported import format %q (supported: %s)", format, importFormats())
+	}
+	opts, err := getFileOptions(cmd)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	tasks, err := decode(file, opts)
+	_ = file.Close()
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	if len(tasks) == 0 {
+		fmt.Fprintf(cmd.OutOrStdout(), "No tasks in %s\n", path)
+		return nil
+	}
+
+	existing, err := taskManager.GetTasks(selectedList.ID, nil)
+	if err != nil {
+		return fmt.Errorf("failed to get tasks: %w", err)
+	}
+	inList := make(map[string]bool, len(existing))
+	for _, task := range existing {
+		inList[task.UID] = true
+	}
+
+	var conflicts []string
+	inFile := make(map[string]bool, len(tasks))
+	for _, task := range tasks {
+		inFile[task.UID] = true
+		if task.UID != "" && inList[task.UID] {
+			conflicts = append(conflicts, fmt.Sprintf("'%s' (%s)", utils.SanitizeLine(task.Summary), task.UID))
+		}
+	}
+	if len(conflicts) > 0 && !skipExisting && !overwrite {
+		return fmt.Errorf("%d tasks of %s are already in %s: %s\nUse --skip-existing to leave them or --overwrite to update them",
+			len(conflicts), path, selectedList.Name, strings.Join(conflicts, ", "))
+	}
+
+	if err := importStatuses(taskManager, tasks); err != nil {
+		return err
+	}
+	dropped := 0
+	for i := range tasks {
+		if parent := tasks[i].ParentUID; parent != "" && !inFile[parent] && !inList[parent] {
+			tasks[i].ParentUID = ""
+			dropped++
+		}
+	}
+	if dropped > 0 {
+		utils.Warnf("%d tasks refer to a parent neither in %s nor in the list; they are imported without it", dropped, path)
+	}
+	var written []backend.Task
+	for _, task := range tasks {
+		if !skipExisting || task.UID == "" || !inList[task.UID] {
+			written = append(written, task)
+		}
+	}
+	if err := validateImport(taskManager, written); err != nil {
+		return err
+	}
+
+	// Backends may give added tasks UIDs of their own; subtasks follow them
+	uids := make(map[string]string, len(tasks))
+	added, updated, skipped := 0, 0, 0
+	for _, task := range backend.SortTasksByHierarchy(tasks) {
+		if uid, ok := uids[task.ParentUID]; ok {
+			task.ParentUID = uid
+		}
+		switch {
+		case task.UID != "" && inList[task.UID] && skipExisting:
+			skipped++
+		case task.UID != "" && inList[task.UID]:
+			if err := taskManager.UpdateTask(selectedList.ID, task); err != nil {
+				return fmt.Errorf("failed to update task '%s' (%d tasks imported before): %w", task.Summary, added+updated, err)
+			}
+			updated++
+		default:
+			uid, err := taskManager.AddTask(selectedList.ID, task)
+			if err != nil {
+				return fmt.Errorf("failed to add task '%s' (%d tasks imported before): %w", task.Summary, added+updated, err)
+			}
+			if task.UID != "" {
+				uids[task.UID] = uid
+			}
+			added++
+		}
+	}
+
+	fmt.Fprintf(cmd.OutOrStdout(), "Imported %d tasks into %s from %s", added+updated, utils.SanitizeLine(selectedList.Name), path)
+	if updated > 0 || skipped > 0 {
+		fmt.Fprintf(cmd.OutOrStdout(), " (%d updated, %d skipped)", updated, skipped)
+	}
+	fmt.Fprintln(cmd.OutOrStdout())
+	return nil
+}
+
+// validateImport checks the tasks of a file as add and update do, before
+// any of them is written. The error lists the problems of every invalid task.
+func validateImport(taskManager backend.TaskManager, tasks []backend.Task) error {
+	cfg := config.GetConfig()
+	var problems []string
+	for _, task := range tasks {
+		var validationErr *TaskValidationError
+		if err := ValidateTask(taskManager, cfg, task); errors.As(err, &validationErr) {
+			for _, p := range validationErr.Problems {
+				problems = append(problems, fmt.Sprintf("'%s': %s %s: %s", utils.SanitizeLine(task.Summary), p.Field, p.Value, p.Reason))
+			}
+		} else if err != nil {
+			return err
+		}
+	}
+	if len(problems) == 0 {
+		return nil
+	}
+	return fmt.Errorf("nothing was imported, %d invalid values:\n  - %s", len(problems), strings.Join(problems, "\n  - "))
+}
+
+// importStatuses converts the statuses of imported tasks to the ones the
+// backend stores. Tasks without a status, or with one outside the canonical
+// statuses, are imported as TODO.
+func importStatuses(taskManager backend.TaskManager, tasks []backend.Task) error {
+	todo, err := taskManager.ParseStatusFlag("TODO")
+	if err != nil {
+		return err
+	}
+	for i := range tasks {
+		status := todo
+		if tasks[i].Status != "" {
+			if parsed, err := taskManager.ParseStatusFlag(tasks[i].Status); err == nil {
+				status = parsed
+			} else {
+				utils.Warnf("Task '%s' has status %s, imported as TODO", utils.SanitizeLine(tasks[i].Summary), utils.SanitizeLine(tasks[i].Status))
+			}
+		}
+		tasks[i].Status = status
+	}
+	return nil
+}
+
+// importFormats lists the --format names of import, as "csv, ics"
+func importFormats() string {
+	names := make([]string, 0, len(importers))
+	for name := range importers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,207 @@
+package operations
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gosynctasks/backend"
+	"gosynctasks/internal/config"
+)
+
+// parentFirstBackend refuses subtasks whose parent is not in the list yet,
+// as backends with foreign keys do
+type parentFirstBackend struct {
+	*backend.MockBackend
+}
+
+func (b parentFirstBackend) AddTask(listID string, task backend.Task) (string, error) {
+	if task.ParentUID != "" {
+		if _, err := b.GetTask(listID, task.ParentUID); err != nil {
+			return "", fmt.Errorf("parent %s of %s is missing", task.ParentUID, task.UID)
+		}
+	}
+	return b.MockBackend.AddTask(listID, task)
+}
+
+func (b parentFirstBackend) GetTask(listID, uid string) (*backend.Task, error) {
+	for _, task := range b.Tasks[listID] {
+		if task.UID == uid {
+			return &task, nil
+		}
+	}
+	return nil, backend.NewNotFoundError("GetTask", listID, uid)
+}
+
+// importFile writes an .ics file with the VTODOs given and returns its path
+func importFile(t *testing.T, vtodos ...string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "tasks.ics")
+	content := "BEGIN:VCALENDAR\nVERSION:2.0\n" + strings.Join(vtodos, "") + "END:VCALENDAR\n"
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func vtodo(uid, summary, parent string) string {
+	todo := "BEGIN:VTODO\nUID:" + uid + "\nSUMMARY:" + summary + "\nSTATUS:NEEDS-ACTION\n"
+	if parent != "" {
+		todo += "RELATED-TO:" + parent + "\n"
+	}
+	return todo + "END:VTODO\n"
+}
+
+// TestHandleImportAction tests importing subtasks before their parents in the
+// file, and the three ways of handling tasks the list already has
+func TestHandleImportAction(t *testing.T) {
+	config.SetConfigForTest(&config.Config{})
+	mb := backend.NewMockBackend()
+	tm := parentFirstBackend{mb}
+	listID, _ := mb.CreateTaskList("Chores", "", "")
+	lists, _ := mb.GetTaskLists()
+
+	run := func(args ...string) (string, error) {
+		cmd := newActionCmd()
+		var out strings.Builder
+		cmd.SetOut(&out)
+		for _, flag := range args[1:] {
+			cmd.Flags().Set(flag, "true")
+		}
+		err := ExecuteAction(tm, &config.Config{}, lists, cmd, []string{"Chores", "import", args[0]}, nil)
+		return out.String(), err
+	}
+
+	// Subtasks come first in the file
+	path := importFile(t, vtodo("socks", "Sort socks", "laundry"), vtodo("laundry", "Laundry", "house"), vtodo("house", "House", ""))
+	out, err := run(path)
+	if err != nil {
+		t.Fatalf("import failed: %v", err)
+	}
+	if !strings.Contains(out, "Imported 3 tasks into Chores") {
+		t.Errorf("output = %q", out)
+	}
+	if got := len(mb.Tasks[listID]); got != 3 {
+		t.Fatalf("the list has %d tasks, want 3", got)
+	}
+
+	// Importing again fails and names the conflicts, adding nothing
+	path = importFile(t, vtodo("laundry", "Laundry again", ""), vtodo("dishes", "Dishes", ""))
+	_, err = run(path)
+	if err == nil || !strings.Contains(err.Error(), "'Laundry again' (laundry)") || !strings.Contains(err.Error(), "--skip-existing") {
+		t.Fatalf("import of a known UID error = %v, want the conflict listed", err)
+	}
+	if got := len(mb.Tasks[listID]); got != 3 {
+		t.Fatalf("a failed import left %d tasks, want 3", got)
+	}
+
+	out, err = run(path, "skip-existing")
+	if err != nil || !strings.Contains(out, "Imported 1 tasks") || !strings.Contains(out, "1 skipped") {
+		t.Fatalf("import --skip-existing = %q, %v", out, err)
+	}
+	if task, _ := tm.GetTask(listID, "laundry"); task.Summary != "Laundry" {
+		t.Errorf("--skip-existing changed the task to %q", task.Summary)
+	}
+
+	out, err = run(path, "overwrite")
+	if err != nil || !strings.Contains(out, "2 updated") {
+		t.Fatalf("import --overwrite = %q, %v", out, err)
+	}
+	if task, _ := tm.GetTask(listID, "laundry"); task.Summary != "Laundry again" {
+		t.Errorf("--overwrite left the task as %q", task.Summary)
+	}
+
+	if _, err := run(path, "skip-existing", "overwrite"); err == nil {
+		t.Error("import with --skip-existing and --overwrite succeeded, want an error")
+	}
+}
+
+// TestHandleImportActionCSV tests a semicolon-separated file found by its
+// extension, with statuses translated for the backend
+func TestHandleImportActionCSV(t *testing.T) {
+	config.SetConfigForTest(&config.Config{})
+	mb := backend.NewMockBackend()
+	listID, _ := mb.CreateTaskList("Sprint", "", "")
+	lists, _ := mb.GetTaskLists()
+
+	path := filepath.Join(t.TempDir(), "sprint.csv")
+	content := "uid;summary;status;parent_uid\nspecs;Write specs;DONE;\nreview;Review specs;IN-PROCESS;specs\n"
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	cmd := newActionCmd()
+	cmd.SetOut(&strings.Builder{})
+	cmd.Flags().Set("delimiter", ";")
+	if err := ExecuteAction(mb, &config.Config{}, lists, cmd, []string{"Sprint", "import", path}, nil); err != nil {
+		t.Fatalf("import failed: %v", err)
+	}
+
+	statuses := make(map[string]string)
+	for _, task := range mb.Tasks[listID] {
+		statuses[task.UID] = task.Status
+	}
+	if statuses["specs"] != "COMPLETED" || statuses["review"] != "IN-PROCESS" {
+		t.Errorf("statuses = %v, want the backend's names", statuses)
+	}
+}
+
+// TestHandleImportActionInvalid tests that a file with invalid tasks is
+// refused as a whole, every problem listed
+func TestHandleImportActionInvalid(t *testing.T) {
+	config.SetConfigForTest(&config.Config{})
+	mb := backend.NewMockBackend()
+	listID, _ := mb.CreateTaskList("Chores", "", "")
+	lists, _ := mb.GetTaskLists()
+
+	path := importFile(t,
+		vtodo("ok", "Fine task", ""),
+		"BEGIN:VTODO\nUID:urgent\nSUMMARY:Urgent\nPRIORITY:12\nEND:VTODO\n",
+		"BEGIN:VTODO\nUID:blank\nSUMMARY: \nEND:VTODO\n",
+		"BEGIN:VTODO\nUID:late\nSUMMARY:Late\nDTSTART;VALUE=DATE:20260320\nDUE;VALUE=DATE:20260310\nEND:VTODO\n",
+	)
+	cmd := newActionCmd()
+	cmd.SetOut(&strings.Builder{})
+	err := ExecuteAction(mb, &config.Config{}, lists, cmd, []string{"Chores", "import", path}, nil)
+	if err == nil {
+		t.Fatal("import of invalid tasks succeeded, want an error")
+	}
+	for _, want := range []string{"3 invalid values", "'Urgent': priority 12", "summary", "'Late': start date 2026-03-20"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error = %v, want it to hold %q", err, want)
+		}
+	}
+	if got := len(mb.Tasks[listID]); got != 0 {
+		t.Errorf("a refused import left %d tasks, want none", got)
+	}
+}
+
+// TestHandleImportActionInvalidCSV tests that csv rows are refused for what
+// add refuses, as the tasks of an .ics file are
+func TestHandleImportActionInvalidCSV(t *testing.T) {
+	config.SetConfigForTest(&config.Config{})
+	mb := backend.NewMockBackend()
+	listID, _ := mb.CreateTaskList("Sprint", "", "")
+	lists, _ := mb.GetTaskLists()
+
+	path := filepath.Join(t.TempDir(), "sprint.csv")
+	content := "summary,priority,start_date,due_date\nWrite specs,12,,\n,3,,\nReview,,2026-03-20,2026-03-10\n"
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	cmd := newActionCmd()
+	cmd.SetOut(&strings.Builder{})
+	err := ExecuteAction(mb, &config.Config{}, lists, cmd, []string{"Sprint", "import", path}, nil)
+	if err == nil {
+		t.Fatal("import of invalid rows succeeded, want an error")
+	}
+	for _, want := range []string{"3 invalid values", "'Write specs': priority 12", "summary", "'Review': start date"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error = %v, want it to hold %q", err, want)
+		}
+	}
+	if got := len(mb.Tasks[listID]); got != 0 {
+		t.Errorf("a refused import left %d tasks, want none", got)
+	}
+}
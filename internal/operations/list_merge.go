@@ -0,0 +1,130 @@
+package operations
+
+import (
+	"fmt"
+	"gosynctasks/backend"
+	"gosynctasks/internal/utils"
+	"strings"
+)
+
+// DuplicateLists returns the groups of lists that display the same name,
+// such as the calendars left by a list creation retried after a timeout. A
+// list in the trash is not a duplicate.
+func DuplicateLists(taskLists []backend.TaskList) [][]backend.TaskList {
+	var keys []string
+	groups := make(map[string][]backend.TaskList)
+	for _, list := range taskLists {
+		if list.DeletedAt != "" {
+			continue
+		}
+		key := strings.ToLower(strings.TrimSpace(list.QualifiedName()))
+		if _, ok := groups[key]; !ok {
+			keys = append(keys, key)
+		}
+		groups[key] = append(groups[key], list)
+	}
+
+	var duplicates [][]backend.TaskList
+	for _, key := range keys {
+		if len(groups[key]) > 1 {
+			duplicates = append(duplicates, groups[key])
+		}
+	}
+	return duplicates
+}
+
+// warnDuplicateLists warns about lists that display the same name, which
+// pickers can't tell apart
+func warnDuplicateLists(taskLists []backend.TaskList) {
+	for _, group := range DuplicateLists(taskLists) {
+		name := group[0].QualifiedName()
+		utils.Warnf("%d lists are named '%s'; merge them with: gosynctasks list merge %q", len(group), name, name)
+	}
+}
+
+// ListsNamed returns the lists displaying name, ignoring case, outside the trash
+func ListsNamed(taskLists []backend.TaskList, name string) []backend.TaskList {
+	var named []backend.TaskList
+	for _, list := range taskLists {
+		if list.DeletedAt == "" && matchesListName(list.QualifiedName(), name) {
+			named = append(named, list)
+		}
+	}
+	return named
+}
+
+// MergeLists moves the tasks of the lists from into the list into, parents
+// before their subtasks so the hierarchy is kept, and returns how many were
+// moved. Moved tasks get new UIDs, as some backends require UIDs unique
+// across lists. The tasks of a list are deleted from it once all of them
+// were copied, so a failure leaves that list whole; the emptied lists
+// themselves are left for the caller to delete.
+func MergeLists(taskManager backend.TaskManager, into backend.TaskList, from []backend.TaskList) (int, error) {
+	moved := 0
+	for _, list := range from {
+		if list.ID == into.ID {
+			continue
+		}
+		tasks, err := taskManager.GetTasks(list.ID, nil)
+		if err != nil {
+			return moved, fmt.Errorf("failed to read list '%s' (%s): %w", list.Name, list.ID, err)
+		}
+
+		if err := copyTasks(taskManager, into.ID, tasks); err != nil {
+			return moved, fmt.Errorf("failed to copy the tasks of list '%s' (%s): %w", list.Name, list.ID, err)
+		}
+
+		for _, task := range tasks {
+			if err := taskManager.DeleteTask(list.ID, task.UID); err != nil {
+				return moved, fmt.Errorf("task '%s' was copied but not deleted from list %s: %w", task.Summary, list.ID, err)
+			}
+		}
+		moved += len(tasks)
+	}
+	return moved, nil
+}
+
+// copyTasks adds tasks to the list listID with new UIDs, each after its
+// parent so that subtasks point to the parent's copy. A task whose parent is
+// not among tasks keeps its parent reference.
+func copyTasks(taskManager backend.TaskManager, listID string, tasks []backend.Task) error {
+	inList := make(map[string]bool, len(tasks))
+	for _, task := range tasks {
+		inList[task.UID] = true
+	}
+
+	newUIDs := make(map[string]string, len(tasks))
+	copied := make([]bool, len(tasks))
+	for remaining := len(tasks); remaining > 0; {
+		progress := false
+		for i, task := range tasks {
+			parentPending := inList[task.ParentUID] && newUIDs[task.ParentUID] == ""
+			if copied[i] || (parentPending && task.ParentUID != task.UID) {
+				continue
+			}
+			if parent, ok := newUIDs[task.ParentUID]; ok {
+				task.ParentUID = parent
+			}
+			oldUID := task.UID
+			task.UID = ""
+			uid, err := taskManager.AddTask(listID, task)
+			if err != nil {
+				return fmt.Errorf("task '%s': %w", task.Summary, err)
+			}
+			newUIDs[oldUID] = uid
+			copied[i] = true
+			remaining--
+			progress = true
+		}
+		if !progress {
+			// Parents referring to each other in a cycle: break it at the first one
+			for i := range tasks {
+				if !copied[i] {
+					tasks[i].ParentUID = ""
+					break
+				}
+			}
+		}
+	}
+	return nil
+}
@@ -1,33 +1,160 @@
 package operations
 
 import (
+	"cmp"
+	"errors"
 	"fmt"
 	"gosynctasks/backend"
 	"gosynctasks/internal/cli"
 	"gosynctasks/internal/config"
+	"gosynctasks/internal/utils"
+	"slices"
 	"strings"
 )
 
+// errAmbiguousListName is returned when a bare list name matches several nested lists
+var errAmbiguousListName = errors.New("ambiguous list name")
+
 // FindListByName searches for a task list by name and returns its ID.
-// Performs case-insensitive search. Returns an error if the list is not found.
+// Accepts the same names as FindListByNameFull.
 func FindListByName(taskLists []backend.TaskList, name string) (string, error) {
-	for _, list := range taskLists {
-		if strings.EqualFold(list.Name, name) {
-			return list.ID, nil
-		}
+	list, err := FindListByNameFull(taskLists, name)
+	if err != nil {
+		return "", err
 	}
-	return "", fmt.Errorf("list '%s' not found", name)
+	return list.ID, nil
 }
 
 // FindListByNameFull searches for a task list by name and returns the complete TaskList struct.
-// Performs case-insensitive search. Returns an error if the list is not found.
+// Performs case-insensitive search on the qualified path of nested lists ("Work/Clients/ACME")
+// first, then on the bare name. A bare name shared by several nested lists is an error.
 func FindListByNameFull(taskLists []backend.TaskList, name string) (*backend.TaskList, error) {
-	for _, list := range taskLists {
-		if strings.EqualFold(list.Name, name) {
+	for i := range taskLists {
+		if taskLists[i].Path != "" && matchesListName(taskLists[i].Path, name) {
+			list := taskLists[i]
 			return &list, nil
 		}
 	}
-	return nil, fmt.Errorf("list '%s' not found", name)
+
+	var matches []backend.TaskList
+	for _, list := range taskLists {
+		if matchesListName(list.Name, name) {
+			matches = append(matches, list)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("list '%s' not found", name)
+	case 1:
+		return &matches[0], nil
+	}
+
+	paths := make([]string, len(matches))
+	for i, list := range matches {
+		paths[i] = list.QualifiedName()
+	}
+	if !slices.ContainsFunc(paths, func(path string) bool { return !strings.EqualFold(path, paths[0]) }) {
+		return nil, utils.WrapWithSuggestion(
+			fmt.Errorf("%w '%s': %d lists have that name", errAmbiguousListName, name, len(matches)),
+			fmt.Sprintf("Merge them with: gosynctasks list merge %q", paths[0]))
+	}
+	return nil, fmt.Errorf("%w '%s', use the full path: %s", errAmbiguousListName, name, strings.Join(paths, ", "))
+}
+
+// OrderTaskLists returns the lists in display order: those pinned in the
+// config first, in the order given, then the others by cfg.ListSort: name,
+// recent (latest activity first) or server (Position), keeping the backend's
+// order when unset.
+func OrderTaskLists(lists []backend.TaskList, cfg *config.Config) []backend.TaskList {
+	ordered := slices.Clone(lists)
+	if cfg == nil {
+		return ordered
+	}
+
+	pinIndex := func(list backend.TaskList) int {
+		for i, pin := range cfg.PinnedLists {
+			if PinMatches(pin, list) {
+				return i
+			}
+		}
+		return len(cfg.PinnedLists)
+	}
+
+	slices.SortStableFunc(ordered, func(a, b backend.TaskList) int {
+		pinA, pinB := pinIndex(a), pinIndex(b)
+		if pinA != pinB || pinA < len(cfg.PinnedLists) {
+			return cmp.Compare(pinA, pinB)
+		}
+		switch cfg.ListSort {
+		case "name":
+			return cmp.Compare(strings.ToLower(a.QualifiedName()), strings.ToLower(b.QualifiedName()))
+		case "recent":
+			// Latest activity first, lists without any last
+			return b.Modified.Compare(a.Modified)
+		case "server":
+			// Lists without a position last
+			if (a.Position == 0) != (b.Position == 0) {
+				if a.Position == 0 {
+					return 1
+				}
+				return -1
+			}
+			return cmp.Compare(a.Position, b.Position)
+		}
+		return 0
+	})
+	return ordered
+}
+
+// PinMatches reports whether a pinned_lists entry refers to list: by its ID
+// or, ignoring case, its name or path
+func PinMatches(pin string, list backend.TaskList) bool {
+	return pin == list.ID || matchesListName(list.Name, pin) || (list.Path != "" && matchesListName(list.Path, pin))
+}
+
+// ListAdopter reaches the remote behind a cache, to adopt remote lists the
+// cache does not know yet
+type ListAdopter interface {
+	// RemoteTaskLists returns the lists of the remote
+	RemoteTaskLists() ([]backend.TaskList, error)
+	// AdoptList starts caching a remote list and pulls its tasks
+	AdoptList(list backend.TaskList) error
+}
+
+// adoptUnknownList handles a list name the cache does not know: when the
+// remote has the list, it is adopted without asking under
+// sync.auto_adopt_lists, after confirmation in a terminal, and otherwise the
+// error says how to adopt it. It returns nil, nil when the remote does not
+// have the list either.
+func adoptUnknownList(cfg *config.Config, adopter ListAdopter, name string) (*backend.TaskList, error) {
+	remoteLists, err := adopter.RemoteTaskLists()
+	if err != nil {
+		utils.Debugf("Could not check the remote for list '%s': %v", name, err)
+		return nil, nil
+	}
+	list, err := FindListByNameFull(remoteLists, name)
+	if err != nil {
+		return nil, nil
+	}
+
+	autoAdopt := cfg != nil && cfg.Sync != nil && cfg.Sync.AutoAdoptLists
+	if !autoAdopt {
+		if !isInteractive() {
+			return nil, utils.WrapWithSuggestion(
+				fmt.Errorf("list '%s' exists on the remote but is not in the cache yet", list.QualifiedName()),
+				fmt.Sprintf("Adopt it with 'gosynctasks list adopt \"%s\"', run 'gosynctasks sync', or set sync.auto_adopt_lists: true", list.QualifiedName()))
+		}
+		if !promptYesNoDefault(fmt.Sprintf("List '%s' exists on the remote but is not in the cache yet. Adopt it now?", list.QualifiedName()), true) {
+			return nil, fmt.Errorf("list '%s' not adopted", list.QualifiedName())
+		}
+	}
+
+	if err := adopter.AdoptList(*list); err != nil {
+		return nil, err
+	}
+	utils.Infof("Adopted list '%s' from the remote", list.QualifiedName())
+	return list, nil
 }
 
 // SelectListInteractively displays task lists and prompts user to select one
@@ -60,6 +187,9 @@ func GetSelectedList(taskLists []backend.TaskList, taskManager backend.TaskManag
 			if len(taskLists) == 0 {
 				return nil, fmt.Errorf("list '%s' not found - no task lists could be loaded. This usually means a connection or authentication failure. Please check your connection URL, username, and password in the config file", listName)
 			}
+			if errors.Is(err, errAmbiguousListName) {
+				return nil, err
+			}
 			return nil, fmt.Errorf("list '%s' not found. Available lists: %s", listName, formatAvailableLists(taskLists))
 		}
 		return selectedList, nil
@@ -84,7 +214,13 @@ func formatAvailableLists(taskLists []backend.TaskList) string {
 	}
 	names := make([]string, len(taskLists))
 	for i, list := range taskLists {
-		names[i] = list.Name
+		names[i] = list.QualifiedName()
 	}
 	return strings.Join(names, ", ")
 }
+
+// matchesListName compares a list name with user input, also accepting the
+// escaped form shown for names containing control characters
+func matchesListName(listName, input string) bool {
+	return strings.EqualFold(listName, input) || strings.EqualFold(utils.SanitizeLine(listName), input)
+}
@@ -0,0 +1,175 @@
+package operations
+
+import (
+	"errors"
+	"gosynctasks/backend"
+	"gosynctasks/internal/config"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFindListByNameFull_NestedPaths(t *testing.T) {
+	lists := []backend.TaskList{
+		{ID: "1", Name: "Work"},
+		{ID: "2", Name: "Meetings", Path: "Work/Meetings"},
+		{ID: "3", Name: "Meetings", Path: "Home/Meetings"},
+		{ID: "4", Name: "Errands", Path: "Home/Errands"},
+	}
+
+	tests := []struct {
+		name    string
+		query   string
+		wantID  string
+		wantErr error
+	}{
+		{name: "qualified path", query: "work/meetings", wantID: "2"},
+		{name: "unique leaf", query: "Errands", wantID: "4"},
+		{name: "top level", query: "Work", wantID: "1"},
+		{name: "ambiguous leaf", query: "Meetings", wantErr: errAmbiguousListName},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			list, err := FindListByNameFull(lists, tt.query)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("FindListByNameFull(%q) error = %v, want %v", tt.query, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("FindListByNameFull(%q) unexpected error: %v", tt.query, err)
+			}
+			if list.ID != tt.wantID {
+				t.Errorf("FindListByNameFull(%q) = %s, want %s", tt.query, list.ID, tt.wantID)
+			}
+		})
+	}
+
+	if _, err := FindListByNameFull(lists, "Missing"); err == nil || errors.Is(err, errAmbiguousListName) {
+		t.Errorf("expected not-found error, got %v", err)
+	}
+}
+
+func TestFindListByNameFull_EscapedName(t *testing.T) {
+	lists := []backend.TaskList{{ID: "1", Name: "Bell\aList"}}
+
+	list, err := FindListByNameFull(lists, `Bell\x07List`)
+	if err != nil {
+		t.Fatalf("FindListByNameFull() with escaped name: %v", err)
+	}
+	if list.ID != "1" {
+		t.Errorf("FindListByNameFull() = %s, want 1", list.ID)
+	}
+}
+
+// fakeAdopter is a sync provider whose remote has lists the cache lacks
+type fakeAdopter struct {
+	remote  []backend.TaskList
+	adopted []string
+}
+
+func (f *fakeAdopter) GetSyncCoordinator() interface{} { return nil }
+
+func (f *fakeAdopter) RemoteTaskLists() ([]backend.TaskList, error) { return f.remote, nil }
+
+func (f *fakeAdopter) AdoptList(list backend.TaskList) error {
+	f.adopted = append(f.adopted, list.ID)
+	return nil
+}
+
+// TestAdoptUnknownList tests commands naming a list only the remote has
+func TestAdoptUnknownList(t *testing.T) {
+	tests := []struct {
+		name        string
+		interactive bool
+		answer      bool
+		autoAdopt   bool
+		listName    string
+		wantAdopted bool
+		wantPrompts int
+		wantErr     string
+	}{
+		{name: "non-interactive", listName: "Groceries", wantErr: "gosynctasks list adopt"},
+		{name: "interactive yes", interactive: true, answer: true, listName: "Groceries", wantAdopted: true, wantPrompts: 1},
+		{name: "interactive no", interactive: true, listName: "Groceries", wantPrompts: 1, wantErr: "not adopted"},
+		{name: "auto adopt", autoAdopt: true, listName: "groceries", wantAdopted: true},
+		{name: "unknown on the remote too", interactive: true, answer: true, listName: "Garden", wantErr: "not found"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prompts := stubPrompts(t, tt.interactive, tt.answer)
+			cfg := &config.Config{Sync: &config.SyncConfig{Enabled: true, AutoAdoptLists: tt.autoAdopt}}
+			config.SetConfigForTest(cfg)
+			adopter := &fakeAdopter{remote: []backend.TaskList{{ID: "remote-1", Name: "Groceries"}}}
+			tm := backend.NewMockBackend()
+
+			err := ExecuteAction(tm, cfg, nil, newActionCmd(), []string{tt.listName, "add", "Milk"}, adopter)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("ExecuteAction() error = %v, want %q", err, tt.wantErr)
+				}
+			} else if err != nil {
+				t.Fatalf("ExecuteAction() error = %v", err)
+			}
+
+			if got := len(adopter.adopted) == 1; got != tt.wantAdopted {
+				t.Errorf("adopted = %v, want adopted %v", adopter.adopted, tt.wantAdopted)
+			}
+			if tt.wantAdopted && len(tm.Tasks["remote-1"]) != 1 {
+				t.Errorf("task not added to the adopted list: %+v", tm.Tasks)
+			}
+			if *prompts != tt.wantPrompts {
+				t.Errorf("prompted %d times, want %d", *prompts, tt.wantPrompts)
+			}
+		})
+	}
+}
+
+func TestOrderTaskLists(t *testing.T) {
+	now := time.Now()
+	lists := []backend.TaskList{
+		{ID: "b", Name: "beta", Position: 3, Modified: now.Add(-time.Hour)},
+		{ID: "a", Name: "Alpha", Position: 2},
+		{ID: "g", Name: "Gamma", Position: 1, Modified: now},
+		{ID: "w", Name: "Work", Position: 4, Modified: now.Add(-2 * time.Hour)},
+	}
+
+	tests := []struct {
+		name   string
+		pinned []string
+		sort   string
+		want   []string // IDs
+	}{
+		{name: "backend order", want: []string{"b", "a", "g", "w"}},
+		{name: "name", sort: "name", want: []string{"a", "b", "g", "w"}},
+		{name: "recent", sort: "recent", want: []string{"g", "b", "w", "a"}},
+		{name: "server", sort: "server", want: []string{"g", "a", "b", "w"}},
+		{name: "pinned first in pin order", pinned: []string{"w", "alpha"}, sort: "name", want: []string{"w", "a", "b", "g"}},
+		{name: "unknown pin", pinned: []string{"gone", "g"}, sort: "server", want: []string{"g", "a", "b", "w"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ordered := OrderTaskLists(lists, &config.Config{PinnedLists: tt.pinned, ListSort: tt.sort})
+			var got []string
+			for _, list := range ordered {
+				got = append(got, list.ID)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("OrderTaskLists() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	// A list pinned by ID stays pinned once renamed
+	renamed := slices.Clone(lists)
+	renamed[3].Name = "Office"
+	ordered := OrderTaskLists(renamed, &config.Config{PinnedLists: []string{"w"}, ListSort: "name"})
+	if ordered[0].Name != "Office" {
+		t.Errorf("renamed pinned list is not first: %+v", ordered)
+	}
+}
@@ -0,0 +1,62 @@
+package operations
+
+import (
+	"fmt"
+	"io"
+
+	"gosynctasks/backend"
+	backendsync "gosynctasks/backend/sync"
+	"gosynctasks/internal/utils"
+
+	"github.com/spf13/cobra"
+)
+
+// ListSyncer syncs one list with the remote behind the cache
+type ListSyncer interface {
+	SyncList(list backend.TaskList) (*backendsync.SyncResult, error)
+}
+
+// HandleSyncAction pulls and pushes the selected list alone, leaving the
+// other lists and their queued changes for a full sync
+func HandleSyncAction(cmd *cobra.Command, selectedList *backend.TaskList, syncProvider SyncCoordinatorProvider) error {
+	syncer, ok := syncProvider.(ListSyncer)
+	if !ok {
+		return utils.ErrSyncNotEnabled()
+	}
+	result, err := syncer.SyncList(*selectedList)
+	if err != nil {
+		return err
+	}
+
+	printListSyncResult(cmd.OutOrStdout(), selectedList.Name, result)
+	if len(result.Errors) > 0 {
+		return fmt.Errorf("sync of %s finished with %d errors", selectedList.Name, len(result.Errors))
+	}
+	return nil
+}
+
+// printListSyncResult writes what the sync of one list did
+func printListSyncResult(w io.Writer, name string, result *backendsync.SyncResult) {
+	name = utils.SanitizeLine(name)
+	if len(result.ListResults) == 1 && result.ListResults[0].Skipped {
+		fmt.Fprintf(w, "%s is unchanged on the remote; pushed %d\n", name, result.PushedTasks)
+	} else {
+		fmt.Fprintf(w, "Synced %s: pulled %d, pushed %d\n", name, result.PulledTasks, result.PushedTasks)
+	}
+
+	if result.ConflictsFound > 0 {
+		fmt.Fprintf(w, "Conflicts resolved: %d%s\n", result.ConflictsResolved, backendsync.FormatResolvedBy(result.ResolvedBy()))
+		for _, conflict := range result.Conflicts {
+			fmt.Fprint(w, backendsync.FormatConflict(conflict))
+		}
+	}
+	for _, rewrite := range result.Rewrites {
+		fmt.Fprint(w, backendsync.FormatRemoteRewrite(rewrite))
+	}
+	for _, stranded := range result.Stranded {
+		fmt.Fprintf(w, "⚠ %s\n", stranded)
+	}
+	for _, err := range result.Errors {
+		fmt.Fprintf(w, "⚠ %v\n", err)
+	}
+}
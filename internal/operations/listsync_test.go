@@ -0,0 +1,62 @@
+package operations
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"gosynctasks/backend"
+	backendsync "gosynctasks/backend/sync"
+	"gosynctasks/internal/config"
+)
+
+// fakeListSyncer is a sync provider recording the lists synced
+type fakeListSyncer struct {
+	result *backendsync.SyncResult
+	synced []string
+}
+
+func (f *fakeListSyncer) GetSyncCoordinator() interface{} { return nil }
+
+func (f *fakeListSyncer) SyncList(list backend.TaskList) (*backendsync.SyncResult, error) {
+	f.synced = append(f.synced, list.ID)
+	return f.result, nil
+}
+
+// TestHandleSyncAction tests that "<list> sync" syncs the named list alone
+// and reports what was done
+func TestHandleSyncAction(t *testing.T) {
+	lists := []backend.TaskList{{ID: "work-1", Name: "Work"}, {ID: "home-1", Name: "Home"}}
+	cfg := &config.Config{}
+
+	syncer := &fakeListSyncer{result: &backendsync.SyncResult{
+		ListID: "work-1", PulledTasks: 2, PushedTasks: 1, ListResults: []backendsync.ListSyncResult{{ListID: "work-1"}},
+	}}
+	cmd := newActionCmd()
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	if err := ExecuteAction(backend.NewMockBackend(), cfg, lists, cmd, []string{"Work", "sync"}, syncer); err != nil {
+		t.Fatalf("ExecuteAction() error = %v", err)
+	}
+	if len(syncer.synced) != 1 || syncer.synced[0] != "work-1" {
+		t.Errorf("synced %v, want [work-1]", syncer.synced)
+	}
+	if got := out.String(); got != "Synced Work: pulled 2, pushed 1\n" {
+		t.Errorf("output = %q", got)
+	}
+
+	// Errors of the sync fail the action after being shown
+	syncer.result = &backendsync.SyncResult{ListID: "work-1", Errors: []error{errors.New("push phase failed: offline")}}
+	out.Reset()
+	err := ExecuteAction(backend.NewMockBackend(), cfg, lists, cmd, []string{"Work", "sync"}, syncer)
+	if err == nil || !strings.Contains(out.String(), "offline") {
+		t.Errorf("ExecuteAction() error = %v, output %q; want the sync error shown and returned", err, out.String())
+	}
+
+	// Without sync there is nothing to sync with
+	err = ExecuteAction(backend.NewMockBackend(), cfg, lists, newActionCmd(), []string{"Work", "sync"}, nil)
+	if err == nil || !strings.Contains(err.Error(), "sync is not enabled") {
+		t.Errorf("ExecuteAction() without sync error = %v, want sync not enabled", err)
+	}
+}
@@ -0,0 +1,70 @@
+package operations
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+
+	"gosynctasks/backend"
+	"gosynctasks/backend/statuskit"
+)
+
+// markdownEscaper escapes the characters GitHub would read as formatting in
+// a summary
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`, "`", "\\`", "*", `\*`, "_", `\_`, "[", `\[`, "]", `\]`,
+	"~", `\~`, "<", `\<`, ">", `\>`, "#", `\#`, "|", `\|`,
+)
+
+// encodeMarkdown writes tasks as a GitHub-flavored checklist under a heading
+// naming the list: open tasks unchecked, done ones checked and cancelled ones
+// checked and struck through, with subtasks indented under their parents.
+// Due dates and priorities follow the summary, as "(due 2024-06-01, P1)";
+// with includeDescriptions, descriptions are quoted under their task.
+func encodeMarkdown(w io.Writer, list *backend.TaskList, tasks []backend.Task, includeDescriptions bool) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "## %s\n\n", markdownEscaper.Replace(lineText(list.Name)))
+
+	for _, entry := range backend.OrganizeTasksHierarchically(tasks) {
+		task := entry.Task
+		indent := strings.Repeat("  ", entry.Level)
+		summary := markdownEscaper.Replace(lineText(task.Summary))
+
+		checkbox := "[ ]"
+		status, _ := statuskit.Lookup(task.Status)
+		switch status {
+		case statuskit.Done:
+			checkbox = "[x]"
+		case statuskit.Cancelled:
+			checkbox = "[x]"
+			summary = "~~" + summary + "~~"
+		}
+
+		var notes []string
+		if task.DueDate != nil {
+			notes = append(notes, "due "+task.DueDate.Format("2006-01-02"))
+		}
+		if task.Priority > 0 {
+			notes = append(notes, fmt.Sprintf("P%d", task.Priority))
+		}
+		fmt.Fprintf(bw, "%s- %s %s", indent, checkbox, summary)
+		if len(notes) > 0 {
+			fmt.Fprintf(bw, " (%s)", strings.Join(notes, ", "))
+		}
+		bw.WriteString("\n")
+
+		if includeDescriptions && strings.TrimSpace(task.Description) != "" {
+			for line := range strings.SplitSeq(strings.TrimRight(task.Description, "\n"), "\n") {
+				quoted := strings.TrimRight("> "+strings.TrimRight(line, "\r"), " ")
+				fmt.Fprintf(bw, "%s  %s\n", indent, quoted)
+			}
+		}
+	}
+	return bw.Flush()
+}
+
+// lineText puts text on one line, for a heading or a list item
+func lineText(text string) string {
+	return strings.Join(strings.Fields(text), " ")
+}
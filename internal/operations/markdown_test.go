@@ -0,0 +1,46 @@
+package operations
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"gosynctasks/backend"
+)
+
+func TestEncodeMarkdown(t *testing.T) {
+	due := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	tasks := []backend.Task{
+		{UID: "release", Summary: "Release *v2*", Status: "IN-PROCESS", Priority: 1, DueDate: &due, Description: "Ship it\n\nthen tell people"},
+		{UID: "notes", Summary: "Write notes", Status: "COMPLETED", ParentUID: "release"},
+		{UID: "blog", Summary: "Blog post", Status: "CANCELLED", ParentUID: "release", Priority: 5},
+		{UID: "fix", Summary: "Fix [login]", Status: "NEEDS-ACTION", ParentUID: "notes"},
+	}
+	list := &backend.TaskList{Name: "Work"}
+
+	var buf bytes.Buffer
+	if err := encodeMarkdown(&buf, list, tasks, false); err != nil {
+		t.Fatalf("encodeMarkdown() error = %v", err)
+	}
+	want := "## Work\n\n" +
+		"- [ ] Release \\*v2\\* (due 2024-06-01, P1)\n" +
+		"  - [x] Write notes\n" +
+		"    - [ ] Fix \\[login\\]\n" +
+		"  - [x] ~~Blog post~~ (P5)\n"
+	if got := buf.String(); got != want {
+		t.Errorf("encodeMarkdown() =\n%s\nwant\n%s", got, want)
+	}
+
+	buf.Reset()
+	if err := encodeMarkdown(&buf, list, tasks[:1], true); err != nil {
+		t.Fatalf("encodeMarkdown() error = %v", err)
+	}
+	want = "## Work\n\n" +
+		"- [ ] Release \\*v2\\* (due 2024-06-01, P1)\n" +
+		"  > Ship it\n" +
+		"  >\n" +
+		"  > then tell people\n"
+	if got := buf.String(); got != want {
+		t.Errorf("encodeMarkdown() with descriptions =\n%s\nwant\n%s", got, want)
+	}
+}
@@ -0,0 +1,96 @@
+package operations
+
+import (
+	"fmt"
+	"gosynctasks/backend"
+	"gosynctasks/internal/config"
+	"gosynctasks/internal/listing"
+	"gosynctasks/internal/utils"
+	"os"
+	"time"
+
+	"golang.org/x/term"
+)
+
+// showsToTerminal reports whether output is read on a terminal; replaced in tests
+var showsToTerminal = func() bool {
+	return term.IsTerminal(int(os.Stdout.Fd()))
+}
+
+// rememberListing records the tasks shown for a list, in display order, so
+// they can be named by position with %N until the list is shown again.
+// Output read by a program is not remembered.
+func rememberListing(list *backend.TaskList, tree []*TaskNode) {
+	if !showsToTerminal() {
+		return
+	}
+	path, err := listing.DefaultPath()
+	if err != nil {
+		utils.Debugf("No state directory for listings: %v", err)
+		return
+	}
+	session, err := listing.Load(path)
+	if err != nil {
+		utils.Debugf("Replacing unreadable listings: %v", err)
+		session = listing.NewSession(path)
+	}
+
+	var shown []*backend.Task
+	buildFlatTaskList(tree, &shown)
+	entries := make([]listing.Entry, len(shown))
+	for i, task := range shown {
+		entries[i] = listing.Entry{UID: task.UID, Summary: task.Summary, Status: task.Status}
+	}
+	session.Remember(list.ID, listing.Listing{ListName: list.Name, ShownAt: time.Now(), Tasks: entries})
+	if err := session.Save(); err != nil {
+		utils.Debugf("Failed to remember listing of %s: %v", list.Name, err)
+	}
+}
+
+// selectByPosition returns the task shown at position in the last listing of
+// a list in this terminal, read again as it may have changed since
+func (ts *TaskSelector) selectByPosition(listID string, position int, opts SelectionOptions) (*backend.Task, error) {
+	path, err := listing.DefaultPath()
+	if err != nil {
+		return nil, fmt.Errorf("cannot find the last listing: %w", err)
+	}
+	session, err := listing.Load(path)
+	if err != nil {
+		return nil, err
+	}
+
+	maxAge := config.DefaultListingMaxAge
+	if ts.config != nil {
+		maxAge = ts.config.GetListingMaxAge()
+	}
+	entry, err := session.Resolve(listID, ts.listName(listID), position, maxAge, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	current, err := ts.taskManager.GetTask(listID, entry.UID)
+	if err != nil {
+		if backendErr, ok := err.(*backend.BackendError); ok && backendErr.IsNotFound() {
+			return nil, fmt.Errorf("%%%d: task '%s' no longer exists", position, entry.Summary)
+		}
+		return nil, fmt.Errorf("failed to read task '%s': %w", entry.Summary, err)
+	}
+	if opts.Filter != nil && len(ts.applyFilter([]backend.Task{*current}, opts.Filter)) == 0 {
+		return nil, fmt.Errorf("%%%d: task '%s' is %s", position, current.Summary, ts.taskManager.StatusToDisplayName(current.Status))
+	}
+	return confirmIfChanged(ts.taskManager, &backend.Task{UID: entry.UID, Summary: entry.Summary, Status: entry.Status}, current)
+}
+
+// listName returns the name of a list for messages, or its ID when unknown
+func (ts *TaskSelector) listName(listID string) string {
+	lists, err := ts.taskManager.GetTaskLists()
+	if err != nil {
+		return listID
+	}
+	for _, list := range lists {
+		if list.ID == listID {
+			return list.Name
+		}
+	}
+	return listID
+}
@@ -0,0 +1,103 @@
+package operations
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"gosynctasks/backend"
+	"gosynctasks/internal/config"
+	"gosynctasks/internal/listing"
+)
+
+// withListings keeps listings in a temporary state directory, as if shown
+// on a terminal
+func withListings(t *testing.T) string {
+	t.Helper()
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	t.Setenv("GOSYNCTASKS_SESSION", "test")
+	defer func(shows func() bool) { t.Cleanup(func() { showsToTerminal = shows }) }(showsToTerminal)
+	showsToTerminal = func() bool { return true }
+	config.SetConfigForTest(&config.Config{})
+	t.Cleanup(func() { config.SetConfigForTest(&config.Config{}) })
+
+	path, err := listing.DefaultPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestCompleteByPosition(t *testing.T) {
+	path := withListings(t)
+	mb := backend.NewMockBackend()
+	lists := []backend.TaskList{{ID: "list-1", Name: "Chores"}, {ID: "list-2", Name: "Work"}}
+	mb.Lists = lists
+	mb.Tasks["list-1"] = []backend.Task{
+		{UID: "a", Summary: "Water plants", Status: "NEEDS-ACTION"},
+		{UID: "b", Summary: "Take out trash", Status: "NEEDS-ACTION"},
+		{UID: "c", Summary: "Call plumber", Status: "NEEDS-ACTION"},
+	}
+
+	if err := ExecuteAction(mb, &config.Config{}, lists, newActionCmd(), []string{"Chores"}, nil); err != nil {
+		t.Fatalf("list failed: %v", err)
+	}
+	session, err := listing.Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	shown := session.Lists["list-1"].Tasks
+	if len(shown) != 3 {
+		t.Fatalf("remembered %d tasks, want 3", len(shown))
+	}
+
+	if err := ExecuteAction(mb, &config.Config{}, lists, newActionCmd(), []string{"Chores", "complete", "%2"}, nil); err != nil {
+		t.Fatalf("complete %%2 failed: %v", err)
+	}
+	for _, task := range mb.Tasks["list-1"] {
+		want := "NEEDS-ACTION"
+		if task.UID == shown[1].UID {
+			want = "COMPLETED"
+		}
+		if task.Status != want {
+			t.Errorf("%s: status = %q, want %q", task.Summary, task.Status, want)
+		}
+	}
+
+	// Completed since the listing: not offered for completion again
+	err = ExecuteAction(mb, &config.Config{}, lists, newActionCmd(), []string{"Chores", "complete", "%2"}, nil)
+	if err == nil || !strings.Contains(err.Error(), shown[1].Summary) {
+		t.Errorf("completing %%2 twice error = %v, want one naming %q", err, shown[1].Summary)
+	}
+
+	// Positions belong to the list they were shown for
+	err = ExecuteAction(mb, &config.Config{}, lists, newActionCmd(), []string{"Work", "complete", "%1"}, nil)
+	if err == nil || !strings.Contains(err.Error(), "gosynctasks Work") {
+		t.Errorf("complete %%1 in an unlisted list error = %v, want a hint to list it", err)
+	}
+}
+
+func TestStaleListingRejected(t *testing.T) {
+	path := withListings(t)
+	mb := backend.NewMockBackend()
+	lists := []backend.TaskList{{ID: "list-1", Name: "Chores"}}
+	mb.Tasks["list-1"] = []backend.Task{{UID: "a", Summary: "Water plants", Status: "NEEDS-ACTION"}}
+
+	session := listing.NewSession(path)
+	session.Remember("list-1", listing.Listing{
+		ListName: "Chores",
+		ShownAt:  time.Now().Add(-time.Hour),
+		Tasks:    []listing.Entry{{UID: "a", Summary: "Water plants"}},
+	})
+	if err := session.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	err := ExecuteAction(mb, &config.Config{}, lists, newActionCmd(), []string{"Chores", "complete", "%1"}, nil)
+	if err == nil || !strings.Contains(err.Error(), "older than") {
+		t.Errorf("complete %%1 after an hour error = %v, want the listing rejected as stale", err)
+	}
+	if status := mb.Tasks["list-1"][0].Status; status == "COMPLETED" {
+		t.Error("task completed through a stale listing")
+	}
+}
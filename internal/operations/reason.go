@@ -0,0 +1,101 @@
+package operations
+
+import (
+	"fmt"
+	"gosynctasks/backend"
+	"gosynctasks/internal/config"
+	"gosynctasks/internal/notes"
+	"gosynctasks/internal/utils"
+	"strings"
+	"time"
+)
+
+// notesPath returns the local notes file, replaced in tests
+var notesPath = notes.DefaultPath
+
+// closingNote returns the note --reason records for a task closed with status
+func closingNote(status, reason string, at time.Time) (notes.Note, error) {
+	if !isClosedStatus(status) {
+		return notes.Note{}, fmt.Errorf("--reason is for closing a task, with -s DONE or -s CANCELLED")
+	}
+	label := "Done"
+	if strings.EqualFold(status, "CANCELLED") {
+		label = "Cancelled"
+	}
+	return notes.Note{Label: label, Reason: strings.TrimSpace(reason), At: at}, nil
+}
+
+// appendNote returns description with the line of note appended, or
+// unchanged when a line already gives the same reason, so completing again
+// doesn't repeat it
+func appendNote(description string, note notes.Note) string {
+	for _, line := range strings.Split(description, "\n") {
+		if strings.HasPrefix(line, note.Label+": ") && strings.HasSuffix(line, " "+note.Reason) {
+			return description
+		}
+	}
+	if description == "" {
+		return note.Line()
+	}
+	return strings.TrimRight(description, "\n") + "\n" + note.Line()
+}
+
+// recordReason stores the note of a task being closed: in its description,
+// read again right before the write so that an edit made meanwhile is kept,
+// or in the local notes under reason_storage: local. The task is updated in
+// place; a local note is saved once the update succeeds, by the returned
+// function.
+func recordReason(cfg *config.Config, taskManager backend.TaskManager, listID string, task *backend.Task, note notes.Note) (func() error, error) {
+	if cfg != nil && cfg.ReasonStorage == config.ReasonStorageLocal {
+		path, err := notesPath()
+		if err != nil {
+			return nil, err
+		}
+		store, err := notes.Load(path)
+		if err != nil {
+			return nil, err
+		}
+		return func() error {
+			if !store.Add(task.UID, note) {
+				return nil
+			}
+			return store.Save()
+		}, nil
+	}
+
+	current, err := taskManager.GetTask(listID, task.UID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read task '%s' again: %w", task.Summary, err)
+	}
+	task.Description = appendNote(current.Description, note)
+	return func() error { return nil }, nil
+}
+
+// withLocalNotes returns tasks with their local notes appended to their
+// descriptions for display, under reason_storage: local
+func withLocalNotes(cfg *config.Config, tasks []backend.Task) []backend.Task {
+	if cfg == nil || cfg.ReasonStorage != config.ReasonStorageLocal {
+		return tasks
+	}
+	path, err := notesPath()
+	if err != nil {
+		return tasks
+	}
+	store, err := notes.Load(path)
+	if err != nil {
+		utils.Warnf("%v", err)
+		return tasks
+	}
+	if len(store.Notes) == 0 {
+		return tasks
+	}
+
+	shown := make([]backend.Task, len(tasks))
+	copy(shown, tasks)
+	for i := range shown {
+		for _, note := range store.For(shown[i].UID) {
+			shown[i].Description = appendNote(shown[i].Description, note)
+		}
+	}
+	return shown
+}
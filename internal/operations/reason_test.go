@@ -0,0 +1,108 @@
+package operations
+
+import (
+	"gosynctasks/backend"
+	"gosynctasks/internal/config"
+	"gosynctasks/internal/notes"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+// staleFinder returns tasks found by summary as they were before their
+// description was edited elsewhere
+type staleFinder struct {
+	*backend.MockBackend
+}
+
+func (s staleFinder) FindTasksBySummary(listID, summary string) ([]backend.Task, error) {
+	tasks, err := s.MockBackend.FindTasksBySummary(listID, summary)
+	for i := range tasks {
+		tasks[i].Description = "before the edit"
+	}
+	return tasks, err
+}
+
+func newReasonCmd(status, reason string) *cobra.Command {
+	cmd := newActionCmd()
+	cmd.Flags().Bool("with-children", false, "")
+	cmd.Flags().String("reason", "", "")
+	_ = cmd.Flags().Set("status", status)
+	_ = cmd.Flags().Set("reason", reason)
+	return cmd
+}
+
+func useNotesFile(t *testing.T) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "notes.json")
+	oldPath := notesPath
+	notesPath = func() (string, error) { return path, nil }
+	t.Cleanup(func() { notesPath = oldPath })
+	return path
+}
+
+func TestCompleteReasonInDescription(t *testing.T) {
+	config.SetConfigForTest(&config.Config{})
+	useNotesFile(t)
+	list := &backend.TaskList{ID: "list-1", Name: "Chores"}
+	mb := backend.NewMockBackend()
+	mb.Tasks["list-1"] = []backend.Task{{UID: "t1", Summary: "Old plan", Description: "edited meanwhile", Status: "NEEDS-ACTION"}}
+
+	if err := HandleCompleteAction(newReasonCmd("CANCELLED", "superseded by #a3f"), staleFinder{mb}, &config.Config{}, list, "Old plan", nil); err != nil {
+		t.Fatalf("complete --reason error = %v", err)
+	}
+	task := mb.Tasks["list-1"][0]
+	lines := strings.Split(task.Description, "\n")
+	if task.Status != "CANCELLED" || len(lines) != 2 || lines[0] != "edited meanwhile" ||
+		!strings.HasPrefix(lines[1], "Cancelled: ") || !strings.HasSuffix(lines[1], " superseded by #a3f") {
+		t.Fatalf("task = %q %q, want CANCELLED with the edit and a Cancelled: line", task.Status, task.Description)
+	}
+
+	// Closing it again with the same reason doesn't repeat the line
+	mb.Tasks["list-1"][0].Status = "NEEDS-ACTION"
+	if err := HandleCompleteAction(newReasonCmd("CANCELLED", "superseded by #a3f"), mb, &config.Config{}, list, "Old plan", nil); err != nil {
+		t.Fatalf("second complete --reason error = %v", err)
+	}
+	if got := mb.Tasks["list-1"][0].Description; got != task.Description {
+		t.Errorf("description after completing again = %q, want %q", got, task.Description)
+	}
+
+	// Only closing statuses take a reason
+	if err := HandleCompleteAction(newReasonCmd("PROCESSING", "started"), mb, &config.Config{}, list, "Old plan", nil); err == nil {
+		t.Error("complete -s PROCESSING --reason error = nil, want one")
+	}
+}
+
+func TestCompleteReasonLocal(t *testing.T) {
+	config.SetConfigForTest(&config.Config{})
+	path := useNotesFile(t)
+	cfg := &config.Config{ReasonStorage: config.ReasonStorageLocal}
+	list := &backend.TaskList{ID: "list-1", Name: "Chores"}
+	mb := backend.NewMockBackend()
+	mb.Tasks["list-1"] = []backend.Task{{UID: "t1", Summary: "Release", Description: "shared", Status: "NEEDS-ACTION"}}
+
+	if err := HandleCompleteAction(newReasonCmd("DONE", "shipped in 2.1"), mb, cfg, list, "Release", nil); err != nil {
+		t.Fatalf("complete --reason error = %v", err)
+	}
+	if task := mb.Tasks["list-1"][0]; task.Status != "COMPLETED" || task.Description != "shared" {
+		t.Errorf("task = %q %q, want COMPLETED with its description untouched", task.Status, task.Description)
+	}
+
+	store, err := notes.Load(path)
+	if err != nil {
+		t.Fatalf("notes.Load() error = %v", err)
+	}
+	if got := store.For("t1"); len(got) != 1 || got[0].Label != "Done" || got[0].Reason != "shipped in 2.1" {
+		t.Fatalf("local notes = %+v, want one Done note", got)
+	}
+
+	shown := withLocalNotes(cfg, mb.Tasks["list-1"])
+	if want := "shared\n" + store.For("t1")[0].Line(); shown[0].Description != want {
+		t.Errorf("shown description = %q, want %q", shown[0].Description, want)
+	}
+	if mb.Tasks["list-1"][0].Description != "shared" {
+		t.Error("withLocalNotes changed the stored task")
+	}
+}
@@ -0,0 +1,104 @@
+package operations
+
+import (
+	"gosynctasks/backend"
+	"gosynctasks/internal/config"
+	"testing"
+	"time"
+)
+
+func TestCompleteRecurringTask(t *testing.T) {
+	config.SetConfigForTest(&config.Config{})
+	mb := backend.NewMockBackend()
+	lists := []backend.TaskList{{ID: "list-1", Name: "Chores"}}
+
+	run := func(args ...string) error {
+		cmd := newActionCmd()
+		cmd.Flags().Bool("with-children", false, "")
+		cmd.Flags().String("reason", "", "")
+		return ExecuteAction(mb, &config.Config{}, lists, cmd, args, nil)
+	}
+
+	cmd := newActionCmd()
+	_ = cmd.Flags().Set("due-date", "2026-03-06")
+	_ = cmd.Flags().Set("repeat", "FREQ=WEEKLY;COUNT=2")
+	if err := ExecuteAction(mb, &config.Config{}, lists, cmd, []string{"Chores", "add", "Water plants"}, nil); err != nil {
+		t.Fatalf("add failed: %v", err)
+	}
+	task := mb.Tasks["list-1"][0]
+	if task.Recurrence != "FREQ=WEEKLY;COUNT=2" {
+		t.Fatalf("Recurrence = %q, want the --repeat rule", task.Recurrence)
+	}
+
+	// Done once: the task comes back a week later
+	if err := run("Chores", "complete", "Water plants"); err != nil {
+		t.Fatalf("complete failed: %v", err)
+	}
+	task = mb.Tasks["list-1"][0]
+	if task.Status != "NEEDS-ACTION" || task.Completed != nil {
+		t.Errorf("after completing: status %q, completed %v, want NEEDS-ACTION", task.Status, task.Completed)
+	}
+	if want := time.Date(2026, 3, 13, 0, 0, 0, 0, task.DueDate.Location()); !task.DueDate.Equal(want) {
+		t.Errorf("after completing: due %s, want %s", task.DueDate, want)
+	}
+	if task.Recurrence != "FREQ=WEEKLY;COUNT=1" {
+		t.Errorf("after completing: Recurrence = %q, want COUNT=1", task.Recurrence)
+	}
+
+	// Done the last time: it stays done
+	if err := run("Chores", "complete", "Water plants"); err != nil {
+		t.Fatalf("complete failed: %v", err)
+	}
+	if status := mb.Tasks["list-1"][0].Status; status != "COMPLETED" {
+		t.Errorf("after the last occurrence: status %q, want COMPLETED", status)
+	}
+}
+
+func TestCancelRecurringTask(t *testing.T) {
+	config.SetConfigForTest(&config.Config{})
+	mb := backend.NewMockBackend()
+	list := &backend.TaskList{ID: "list-1", Name: "Chores"}
+	due := time.Date(2026, 3, 6, 0, 0, 0, 0, time.UTC)
+	if _, err := mb.AddTask(list.ID, backend.Task{UID: "t", Summary: "Water plants", Status: "NEEDS-ACTION", DueDate: &due, Recurrence: "FREQ=DAILY"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Cancelling ends the series
+	if err := HandleCompleteAction(newReasonCmd("CANCELLED", ""), mb, &config.Config{}, list, "Water plants", nil); err != nil {
+		t.Fatalf("complete failed: %v", err)
+	}
+	if task := mb.Tasks["list-1"][0]; task.Status != "CANCELLED" || !task.DueDate.Equal(due) {
+		t.Errorf("after cancelling: status %q, due %s, want CANCELLED on the same day", task.Status, task.DueDate)
+	}
+}
+
+func TestUpdateRepeat(t *testing.T) {
+	config.SetConfigForTest(&config.Config{})
+	mb := backend.NewMockBackend()
+	lists := []backend.TaskList{{ID: "list-1", Name: "Chores"}}
+	if _, err := mb.AddTask("list-1", backend.Task{UID: "t", Summary: "Water plants", Status: "NEEDS-ACTION"}); err != nil {
+		t.Fatal(err)
+	}
+
+	update := func(repeat string) error {
+		cmd := newActionCmd()
+		_ = cmd.Flags().Set("repeat", repeat)
+		return ExecuteAction(mb, &config.Config{}, lists, cmd, []string{"Chores", "update", "Water plants"}, nil)
+	}
+
+	if err := update("rrule:freq=weekly;byday=mo"); err != nil {
+		t.Fatalf("update failed: %v", err)
+	}
+	if got := mb.Tasks["list-1"][0].Recurrence; got != "FREQ=WEEKLY;BYDAY=MO" {
+		t.Errorf("Recurrence = %q, want FREQ=WEEKLY;BYDAY=MO", got)
+	}
+	if err := update("every other day"); err == nil {
+		t.Error("update with an invalid --repeat succeeded")
+	}
+	if err := update("none"); err != nil {
+		t.Fatalf("update failed: %v", err)
+	}
+	if got := mb.Tasks["list-1"][0].Recurrence; got != "" {
+		t.Errorf("Recurrence = %q after --repeat none, want none", got)
+	}
+}
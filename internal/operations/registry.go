@@ -0,0 +1,215 @@
+package operations
+
+import "strings"
+
+// Example is a command line with what it does
+type Example struct {
+	Command string
+	Comment string
+}
+
+// ActionSpec describes an action on a list. The root command's help, the
+// man pages and markdown docs are generated from it.
+type ActionSpec struct {
+	Name        string
+	Alias       string   // Abbreviation, if any
+	Args        string   // Arguments after the action, for the synopsis
+	Short       string   // One line for the action list in --help
+	Description string   // Paragraph for the action's man page
+	Flags       []string // Root command flags the action reads
+	Examples    []Example
+}
+
+// Actions is the registry of actions, in the order they are documented
+var Actions = []ActionSpec{
+	{
+		Name:  "get",
+		Alias: "g",
+		Short: "List tasks from a task list (default action)",
+		Description: `Shows the tasks of a list as a tree of subtasks, sorted by the backend's order.
+Without a list name, the list is chosen interactively. Completed tasks are hidden
+unless asked for with --status or --completed-since. The output can be shaped
+with a view, a Go template or a set of fields, or printed as JSON. On a terminal
+the tasks are numbered, and %N names the Nth of them in complete, update, delete,
+--expand and add --parent.`,
+		Flags: []string{"status", "priority", "view", "format-template", "fields", "json", "modified-since", "completed-since", "overdue", "include-archived", "collapse", "expand"},
+		Examples: []Example{
+			{"gosynctasks", "Interactive list selection, show tasks"},
+			{"gosynctasks MyList", `Show tasks from "MyList"`},
+			{"gosynctasks MyList get", `Show tasks from "MyList" (g also works)`},
+			{"gosynctasks MyList -s TODO,PROCESSING", "Filter tasks by status"},
+			{"gosynctasks MyList -p high", "Only tasks of priority 1-4 (also 3, 1-4, medium, low, none)"},
+			{"gosynctasks all --overdue", "Open tasks past their due date, in every list"},
+		},
+	},
+	{
+		Name:  "add",
+		Alias: "a",
+		Args:  "[task-summary]",
+		Short: "Add a new task to a list",
+		Description: `Adds a task with the given summary, asking for it when none is given. A summary
+with slashes creates the missing parents of a hierarchy, unless --literal is set;
+--parent puts the task under an existing task instead.`,
+		Flags: []string{"description", "priority", "add-status", "due", "due-date", "start-date", "repeat", "tag", "parent", "literal", "explain", "yes"},
+		Examples: []Example{
+			{`gosynctasks MyList add "New task"`, `Add a task to "MyList"`},
+			{`gosynctasks MyList a "New task"`, "Same using abbreviation"},
+			{"gosynctasks MyList add", "Add a task (will prompt for summary)"},
+			{`gosynctasks MyList add "Task" -d "Details" -p 1 -S done`, "Add with options"},
+			{`gosynctasks MyList add "Report" --due-date 2025-01-31 --start-date 2025-01-15`, "With dates"},
+			{`gosynctasks MyList add "Call back" --due +3d`, "Due in three days"},
+			{`gosynctasks MyList add "Fix login" --tag work --tag urgent`, "With tags"},
+			{`gosynctasks MyList add "Water plants" --due friday --repeat weekly`, "Repeat every week once done"},
+			{`gosynctasks MyList add "Subtask" -P "Parent Task"`, "Add subtask under parent"},
+			{`gosynctasks MyList add "Fix bug" -P "Feature/Code"`, "Path-based parent reference"},
+			{`gosynctasks MyList add "parent/child/grandchild"`, "Shorthand: auto-creates hierarchy"},
+			{`gosynctasks MyList add -l "be a good/generous person"`, "Use -l to disable path parsing"},
+		},
+	},
+	{
+		Name:  "update",
+		Alias: "u",
+		Args:  "<search-term>",
+		Short: "Update an existing task by summary",
+		Description: `Changes the task whose summary matches the search term, case-insensitively and
+partially; several matches are offered for choosing. %N names the Nth task of
+the last listing of the list instead. Only the fields given as flags change.`,
+		Flags: []string{"status", "description", "priority", "summary", "due", "due-date", "start-date", "repeat", "tag", "explain", "yes"},
+		Examples: []Example{
+			{`gosynctasks MyList update "Buy groceries" -s DONE`, "Update task status"},
+			{`gosynctasks MyList u "groceries" --summary "Buy milk"`, "Partial match + rename"},
+			{`gosynctasks MyList update "task" -p 5`, "Partial match + set priority"},
+			{`gosynctasks MyList update "task" --due-date 2025-02-15`, "Update due date"},
+			{`gosynctasks MyList update "task" --due "next monday"`, ""},
+			{`gosynctasks MyList update "task" --due none`, "Clear the due date"},
+			{`gosynctasks MyList update "task" --tag +urgent --tag -someday`, "Add and remove tags"},
+			{`gosynctasks MyList update "task" --repeat "FREQ=WEEKLY;BYDAY=MO,TH"`, "Repeat on Mondays and Thursdays"},
+		},
+	},
+	{
+		Name:  "complete",
+		Alias: "c",
+		Args:  "<search-term>",
+		Short: "Change task status by summary (defaults to DONE)",
+		Description: `Marks the task matching the search term as DONE, or gives it the status of
+--status. Tasks already closed are not offered. When the last open subtask of a
+task is completed, completing the parent too is offered. A repeating task
+(--repeat) moves to its next occurrence as TODO instead of staying DONE, until
+its rule has no occurrence left. --reason adds a
+timestamped "Cancelled:" or "Done:" line with why, to the description or, with
+reason_storage: local, to notes kept on this machine.`,
+		Flags: []string{"status", "with-children", "reason", "explain", "yes"},
+		Examples: []Example{
+			{`gosynctasks MyList complete "Buy groceries"`, "Mark as DONE (default)"},
+			{`gosynctasks MyList c "groceries"`, ""},
+			{`gosynctasks MyList c "Release" -s C --with-children`, "Cancel a task and its open subtasks"},
+			{`gosynctasks MyList c "Old plan" -s C --reason "superseded by #a3f"`, "Cancel with a closing note"},
+			{"gosynctasks MyList complete %3", "The third task of the last listing"},
+		},
+	},
+	{
+		Name:  "delete",
+		Alias: "d",
+		Args:  "<search-term>",
+		Short: "Delete a task by summary",
+		Description: `Deletes the task matching the search term after asking for confirmation, which
+--force skips for scripts; several matches are offered for choosing. %N names the
+Nth task of the last listing of the list instead. Deleted
+tasks can be listed with trash and brought back with restore, as long as the
+backend keeps them.`,
+		Flags: []string{"force", "explain", "yes"},
+		Examples: []Example{
+			{`gosynctasks MyList delete "Buy groceries"`, "Delete a task"},
+			{`gosynctasks MyList d "groceries"`, "Same using abbreviation"},
+			{`gosynctasks MyList delete "Old draft" --force`, "Delete without confirmation"},
+			{"gosynctasks MyList delete %2", "The second task of the last listing"},
+		},
+	},
+	{
+		Name:  "trash",
+		Short: "List deleted tasks that can still be restored",
+		Description: `Lists the deleted tasks of a list: the Nextcloud trash bin, or local deletes not
+synced yet.`,
+		Examples: []Example{
+			{"gosynctasks MyList trash", "Show deleted tasks"},
+		},
+	},
+	{
+		Name:  "restore",
+		Args:  "<search-term>",
+		Short: "Restore a deleted task by summary",
+		Description: `Brings back a deleted task matching the search term, from the same places trash
+lists.`,
+		Examples: []Example{
+			{`gosynctasks MyList restore "Buy groceries"`, "Undo the delete"},
+		},
+	},
+	{
+		Name:  "sync",
+		Short: "Sync this list with the remote, leaving the others",
+		Description: `Pulls the list from the remote and pushes its queued changes, as a sync does for
+every list. The pull is skipped when the list's CTag did not change. Changes
+queued for other lists wait for the next full sync. Needs sync to be enabled.`,
+		Examples: []Example{
+			{"gosynctasks MyList sync", `Sync "MyList" only`},
+		},
+	},
+	{
+		Name:  "export",
+		Short: "Write every task of a list to a file",
+		Description: `Writes all tasks of the list, completed ones included, to the file of --output
+or to stdout. The ics format is a calendar with one VTODO per task, keeping
+UIDs, parents, tags, dates, status and priority, whatever the backend. The csv
+format has one row per task under a header naming the columns, separated by
+--delimiter. The md format is a GitHub-flavored checklist with subtasks indented,
+for pasting into an issue or pull request.`,
+		Flags: []string{"format", "output", "delimiter", "include-descriptions"},
+		Examples: []Example{
+			{"gosynctasks MyList export -o mylist.ics", "Export to an iCalendar file"},
+			{"gosynctasks MyList export --format ics", "Write the calendar to stdout"},
+			{"gosynctasks MyList export --format csv --delimiter ';' -o mylist.csv", "Export for a spreadsheet"},
+			{"gosynctasks MyList export --format md --include-descriptions | pbcopy", "Copy a checklist with descriptions"},
+		},
+	},
+	{
+		Name:  "import",
+		Args:  "<file>",
+		Short: "Add the tasks of a file to a list",
+		Description: `Adds the tasks of a file, such as the VTODOs of an .ics export from Apple
+Reminders or Thunderbird, to the list, parents before their subtasks. The format
+follows the file's extension unless --format is given. Tasks whose UID the list
+already has stop the import, which lists them, unless --skip-existing leaves
+them or --overwrite updates them from the file. Columns of a csv file are found
+by their header names, so a file with just summary and due_date works.`,
+		Flags: []string{"format", "delimiter", "skip-existing", "overwrite"},
+		Examples: []Example{
+			{"gosynctasks MyList import reminders.ics", "Import the tasks of an iCalendar file"},
+			{"gosynctasks MyList import backup.ics --skip-existing", "Only add the tasks the list doesn't have"},
+			{"gosynctasks MyList import backup.ics --overwrite", "Update the tasks the list has from the file"},
+			{"gosynctasks MyList import sprint.csv --delimiter ';'", "Import the rows of a spreadsheet"},
+		},
+	},
+}
+
+// LookupAction returns the action with a name or abbreviation
+func LookupAction(name string) (ActionSpec, bool) {
+	name = strings.ToLower(name)
+	for _, action := range Actions {
+		if name == action.Name || (action.Alias != "" && name == action.Alias) {
+			return action, true
+		}
+	}
+	return ActionSpec{}, false
+}
+
+// supportedActions lists the actions with their abbreviations, as "get/g, add/a, trash"
+func supportedActions() string {
+	names := make([]string, len(Actions))
+	for i, action := range Actions {
+		names[i] = action.Name
+		if action.Alias != "" {
+			names[i] += "/" + action.Alias
+		}
+	}
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,155 @@
+package operations
+
+import (
+	"fmt"
+	"gosynctasks/backend"
+	"gosynctasks/internal/config"
+	"regexp"
+	"strings"
+)
+
+// ReplaceOptions controls how FindReplacements matches and rewrites tasks
+type ReplaceOptions struct {
+	Pattern             string
+	Replacement         string
+	Regex               bool // Treat Pattern as a regular expression; Replacement may use $1, ${name}
+	IncludeDescriptions bool // Also rewrite task descriptions
+}
+
+// ReplaceChange is a planned rewrite of one task
+type ReplaceChange struct {
+	ListID         string
+	ListName       string
+	Task           backend.Task
+	NewSummary     string
+	NewDescription string
+	Err            error // Set when the change is rejected before applying
+}
+
+// ReplaceResult is the outcome of applying one change
+type ReplaceResult struct {
+	Change ReplaceChange
+	Err    error
+}
+
+// newReplacer returns a function that applies the replacement to a string
+func newReplacer(opts ReplaceOptions) (func(string) string, error) {
+	if opts.Pattern == "" {
+		return nil, fmt.Errorf("search pattern cannot be empty")
+	}
+
+	if !opts.Regex {
+		return func(s string) string {
+			return strings.ReplaceAll(s, opts.Pattern, opts.Replacement)
+		}, nil
+	}
+
+	re, err := regexp.Compile(opts.Pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid regex %q: %w", opts.Pattern, err)
+	}
+	return func(s string) string {
+		return re.ReplaceAllString(s, opts.Replacement)
+	}, nil
+}
+
+// FindReplacements scans open tasks in the given lists and returns the changes
+// the replacement would make. Changes that would leave an empty summary are
+// returned with Err set.
+func FindReplacements(taskManager backend.TaskManager, lists []backend.TaskList, opts ReplaceOptions) ([]ReplaceChange, error) {
+	replace, err := newReplacer(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var changes []ReplaceChange
+	for _, list := range lists {
+		tasks, err := taskManager.GetTasks(list.ID, nil)
+		if err != nil {
+			return nil, fmt.Errorf("error retrieving tasks from '%s': %w", list.Name, err)
+		}
+		duplicates := backend.DuplicateUIDs(tasks)
+
+		for _, task := range tasks {
+			if isClosedStatus(task.Status) {
+				continue
+			}
+
+			newSummary := replace(task.Summary)
+			newDescription := task.Description
+			if opts.IncludeDescriptions {
+				newDescription = replace(task.Description)
+			}
+			if newSummary == task.Summary && newDescription == task.Description {
+				continue
+			}
+
+			change := ReplaceChange{
+				ListID:         list.ID,
+				ListName:       list.Name,
+				Task:           task,
+				NewSummary:     newSummary,
+				NewDescription: newDescription,
+			}
+			if strings.TrimSpace(newSummary) == "" {
+				change.Err = fmt.Errorf("summary would become empty")
+			} else if count := duplicates[task.UID]; count > 1 {
+				change.Err = backend.NewDuplicateUIDError("UpdateTask", list.ID, task.UID, count)
+			}
+			changes = append(changes, change)
+		}
+	}
+
+	return changes, nil
+}
+
+// UsesColor reports whether output may be colored: it is read on a
+// terminal, and neither NO_COLOR nor accessibility.no_color_semantics is set
+func UsesColor(cfg *config.Config) bool {
+	return showsToTerminal() && !cfg.NoColorSemantics()
+}
+
+// FormatReplaceDiff renders a before/after view of a change, colored when
+// colorize is set
+func FormatReplaceDiff(change ReplaceChange, colorize bool) string {
+	red, green, gray, reset := "\033[31m", "\033[32m", "\033[90m", "\033[0m"
+	if !colorize {
+		red, green, gray, reset = "", "", "", ""
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%s[%s]%s\n", gray, change.ListName, reset)
+	if change.NewSummary != change.Task.Summary {
+		fmt.Fprintf(&sb, "  %s- %s%s\n", red, change.Task.Summary, reset)
+		fmt.Fprintf(&sb, "  %s+ %s%s\n", green, change.NewSummary, reset)
+	} else {
+		fmt.Fprintf(&sb, "    %s\n", change.Task.Summary)
+	}
+	if change.NewDescription != change.Task.Description {
+		fmt.Fprintf(&sb, "    %sdescription:%s\n", gray, reset)
+		fmt.Fprintf(&sb, "  %s- %s%s\n", red, change.Task.Description, reset)
+		fmt.Fprintf(&sb, "  %s+ %s%s\n", green, change.NewDescription, reset)
+	}
+	if change.Err != nil {
+		fmt.Fprintf(&sb, "  %s! skipped: %v%s\n", red, change.Err, reset)
+	}
+	return sb.String()
+}
+
+// ApplyReplacements writes the replacements with applyBulkUpdates and
+// reports a result per task
+func ApplyReplacements(taskManager backend.TaskManager, changes []ReplaceChange, syncProvider SyncCoordinatorProvider) []ReplaceResult {
+	updates := make([]bulkUpdate, len(changes))
+	for i, change := range changes {
+		task := change.Task
+		task.Summary = change.NewSummary
+		task.Description = change.NewDescription
+		updates[i] = bulkUpdate{listID: change.ListID, task: task, err: change.Err}
+	}
+
+	results := make([]ReplaceResult, len(changes))
+	for i, err := range applyBulkUpdates(taskManager, updates, syncProvider) {
+		results[i] = ReplaceResult{Change: changes[i], Err: err}
+	}
+	return results
+}
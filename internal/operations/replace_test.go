@@ -0,0 +1,151 @@
+package operations
+
+import (
+	"gosynctasks/backend"
+	"gosynctasks/internal/config"
+	"strings"
+	"testing"
+)
+
+func newReplaceBackend() (*backend.MockBackend, []backend.TaskList) {
+	mb := backend.NewMockBackend()
+	mb.Tasks["work"] = []backend.Task{
+		{UID: "1", Summary: "Call ACME", Description: "ACME contract", Status: "NEEDS-ACTION"},
+		{UID: "2", Summary: "ACME", Status: "NEEDS-ACTION"},
+		{UID: "3", Summary: "Old ACME invoice", Status: "COMPLETED"},
+	}
+	mb.Tasks["home"] = []backend.Task{
+		{UID: "4", Summary: "Release v12", Status: "IN-PROCESS"},
+	}
+	lists := []backend.TaskList{{ID: "work", Name: "Work"}, {ID: "home", Name: "Home"}}
+	return mb, lists
+}
+
+func TestFindReplacements(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    ReplaceOptions
+		want    map[string]string // UID -> new summary
+		wantErr []string          // UIDs rejected before applying
+	}{
+		{
+			name: "plain text skips closed tasks",
+			opts: ReplaceOptions{Pattern: "ACME", Replacement: "Acme Corp"},
+			want: map[string]string{"1": "Call Acme Corp", "2": "Acme Corp"},
+		},
+		{
+			name:    "empty summary is rejected",
+			opts:    ReplaceOptions{Pattern: "ACME", Replacement: ""},
+			want:    map[string]string{"1": "Call ", "2": ""},
+			wantErr: []string{"2"},
+		},
+		{
+			name: "regex capture groups",
+			opts: ReplaceOptions{Pattern: `v(\d+)`, Replacement: "version $1", Regex: true},
+			want: map[string]string{"4": "Release version 12"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mb, lists := newReplaceBackend()
+			changes, err := FindReplacements(mb, lists, tt.opts)
+			if err != nil {
+				t.Fatalf("FindReplacements() error = %v", err)
+			}
+			if len(changes) != len(tt.want) {
+				t.Fatalf("got %d changes, want %d", len(changes), len(tt.want))
+			}
+
+			rejected := map[string]bool{}
+			for _, c := range changes {
+				if c.NewSummary != tt.want[c.Task.UID] {
+					t.Errorf("task %s: NewSummary = %q, want %q", c.Task.UID, c.NewSummary, tt.want[c.Task.UID])
+				}
+				if c.Err != nil {
+					rejected[c.Task.UID] = true
+				}
+			}
+			for _, uid := range tt.wantErr {
+				if !rejected[uid] {
+					t.Errorf("task %s should be rejected", uid)
+				}
+			}
+		})
+	}
+}
+
+func TestFindReplacements_InvalidRegex(t *testing.T) {
+	mb, lists := newReplaceBackend()
+	if _, err := FindReplacements(mb, lists, ReplaceOptions{Pattern: "(", Regex: true}); err == nil {
+		t.Error("FindReplacements() expected error for invalid regex")
+	}
+}
+
+func TestApplyReplacements(t *testing.T) {
+	mb, lists := newReplaceBackend()
+	changes, err := FindReplacements(mb, lists, ReplaceOptions{
+		Pattern:             "ACME",
+		Replacement:         "",
+		IncludeDescriptions: true,
+	})
+	if err != nil {
+		t.Fatalf("FindReplacements() error = %v", err)
+	}
+
+	results := ApplyReplacements(mb, changes, nil)
+	if len(results) != 2 {
+		t.Fatalf("got %d results, want 2", len(results))
+	}
+
+	for _, r := range results {
+		switch r.Change.Task.UID {
+		case "1":
+			if r.Err != nil {
+				t.Errorf("task 1 error = %v", r.Err)
+			}
+		case "2":
+			if r.Err == nil {
+				t.Error("task 2 should fail with empty summary")
+			}
+		}
+	}
+
+	task := mb.Tasks["work"][0]
+	if task.Summary != "Call " || task.Description != " contract" {
+		t.Errorf("task 1 = %q / %q, want updated summary and description", task.Summary, task.Description)
+	}
+	if mb.Tasks["work"][1].Summary != "ACME" {
+		t.Errorf("rejected task was modified: %q", mb.Tasks["work"][1].Summary)
+	}
+}
+
+// TestFormatReplaceDiff tests that diffs are colored only when asked, which
+// UsesColor leaves to terminals without NO_COLOR
+func TestFormatReplaceDiff(t *testing.T) {
+	change := ReplaceChange{ListName: "Work", Task: backend.Task{Summary: "Call ACME"}, NewSummary: "Call Acme Corp"}
+
+	want := "[Work]\n  - Call ACME\n  + Call Acme Corp\n"
+	if got := FormatReplaceDiff(change, false); got != want {
+		t.Errorf("FormatReplaceDiff() = %q, want %q", got, want)
+	}
+	if got := FormatReplaceDiff(change, true); !strings.Contains(got, "\033[31m- Call ACME\033[0m") {
+		t.Errorf("FormatReplaceDiff() colored = %q, want the removed summary in red", got)
+	}
+
+	defer func(shows func() bool) { showsToTerminal = shows }(showsToTerminal)
+	showsToTerminal = func() bool { return true }
+	t.Setenv("NO_COLOR", "")
+	if !UsesColor(&config.Config{}) {
+		t.Error("UsesColor() on a terminal = false, want true")
+	}
+	t.Setenv("NO_COLOR", "1")
+	if UsesColor(&config.Config{}) {
+		t.Error("UsesColor() with NO_COLOR = true, want false")
+	}
+	showsToTerminal = func() bool { return false }
+	t.Setenv("NO_COLOR", "")
+	if UsesColor(&config.Config{}) {
+		t.Error("UsesColor() off a terminal = true, want false")
+	}
+}
@@ -0,0 +1,44 @@
+package operations
+
+import (
+	"fmt"
+	"gosynctasks/backend"
+	"gosynctasks/internal/config"
+	"gosynctasks/internal/utils"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// RetentionNote returns a note for results going back to since, or to the
+// beginning when since is nil, when the cache prunes completed tasks older
+// than cache.completed_retention so that some may be missing; "" otherwise
+func RetentionNote(cfg *config.Config, since *time.Time) string {
+	if cfg == nil || cfg.Sync == nil || !cfg.Sync.Enabled {
+		return ""
+	}
+	retention, err := cfg.GetCompletedRetention()
+	if err != nil || retention == 0 {
+		return ""
+	}
+	cutoff := time.Now().Add(-retention)
+	if since != nil && !since.Before(cutoff) {
+		return ""
+	}
+	return fmt.Sprintf("Completed tasks closed before %s are pruned from the cache (cache.completed_retention) and may be missing; they are still on the remote.", cutoff.Format("2006-01-02"))
+}
+
+// buildGetFilter builds the filter of get, noting when completed tasks asked
+// for with --completed-since may have been pruned from the cache
+func buildGetFilter(cmd *cobra.Command, taskManager backend.TaskManager, cfg *config.Config) (*backend.TaskFilter, error) {
+	filter, err := BuildFilter(cmd, taskManager)
+	if err != nil {
+		return nil, err
+	}
+	if filter.CompletedAfter != nil {
+		if note := RetentionNote(cfg, filter.CompletedAfter); note != "" {
+			utils.Warnf("%s", note)
+		}
+	}
+	return filter, nil
+}
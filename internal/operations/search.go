@@ -0,0 +1,120 @@
+package operations
+
+import (
+	"fmt"
+	"gosynctasks/backend"
+	"gosynctasks/internal/config"
+	"gosynctasks/internal/utils"
+	"slices"
+
+	"github.com/spf13/cobra"
+)
+
+// SearchResult is the tasks of one list matching a search
+type SearchResult struct {
+	List  backend.TaskList
+	Tasks []backend.Task
+}
+
+// SearchLists searches the summaries of every list outside the trash for
+// query, and returns the lists with matches in the order of taskLists, their
+// tasks in the backend's order. Backends implementing
+// backend.CrossListSearcher are searched at once; others list by list, a few
+// lists at a time. Only tasks the filter's statuses match are kept, and at
+// most limit per list when limit is above 0. Lists that cannot be searched
+// are warned about.
+func SearchLists(taskManager backend.TaskManager, taskLists []backend.TaskList, query string, filter *backend.TaskFilter, limit int) ([]SearchResult, error) {
+	var results []listResult
+	if searcher, ok := backend.As[backend.CrossListSearcher](taskManager); ok {
+		matches, err := searcher.SearchAllLists(query)
+		if err != nil {
+			return nil, fmt.Errorf("error searching the lists: %w", err)
+		}
+		for _, list := range taskLists {
+			if list.DeletedAt == "" {
+				results = append(results, listResult{list: list, tasks: matches[list.ID]})
+			}
+		}
+	} else {
+		results = readAllLists(taskLists, func(listID string) ([]backend.Task, error) {
+			return taskManager.FindTasksBySummary(listID, query)
+		})
+	}
+	if len(results) == 0 {
+		return nil, fmt.Errorf("no task lists available")
+	}
+
+	var found []SearchResult
+	failed := 0
+	for _, result := range results {
+		if result.err != nil {
+			utils.Warnf("Could not search list '%s': %v", result.list.Name, result.err)
+			failed++
+			continue
+		}
+		var tasks []backend.Task
+		for _, task := range result.tasks {
+			if filter == nil || filter.MatchesStatus(task.Status) {
+				tasks = append(tasks, task)
+			}
+		}
+		if len(tasks) == 0 {
+			continue
+		}
+		taskManager.SortTasks(tasks)
+		if limit > 0 && len(tasks) > limit {
+			tasks = tasks[:limit]
+		}
+		found = append(found, SearchResult{List: result.list, Tasks: tasks})
+	}
+	if failed == len(results) {
+		return nil, fmt.Errorf("error searching the lists: no list could be read")
+	}
+	return found, nil
+}
+
+// HandleSearch prints the tasks of every list whose summary contains query,
+// grouped by list with the formatting of get. Finding nothing is an error, so
+// that scripts can tell from the exit code.
+func HandleSearch(cmd *cobra.Command, taskManager backend.TaskManager, cfg *config.Config, taskLists []backend.TaskList, query string) error {
+	filter, err := BuildFilter(cmd, taskManager)
+	if err != nil {
+		return err
+	}
+	limit, _ := cmd.Flags().GetInt("limit")
+	if limit < 0 {
+		return fmt.Errorf("--limit must be positive, got %d", limit)
+	}
+	if searchesClosedTasks(filter) {
+		if note := RetentionNote(cfg, nil); note != "" {
+			utils.Warnf("%s", note)
+		}
+	}
+
+	results, err := SearchLists(taskManager, taskLists, query, filter, limit)
+	if err != nil {
+		return err
+	}
+	if len(results) == 0 {
+		return fmt.Errorf("no tasks match '%s' in any list", query)
+	}
+	fields, err := fieldsFlag(cmd)
+	if err != nil {
+		return err
+	}
+	for i := range results {
+		if err := printListTasks(cmd, taskManager, cfg, &results[i].List, results[i].Tasks, fields); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// searchesClosedTasks reports whether the filter lets completed or cancelled
+// tasks through, which the cache may have pruned
+func searchesClosedTasks(filter *backend.TaskFilter) bool {
+	if filter.Statuses == nil {
+		return true
+	}
+	return slices.ContainsFunc(*filter.Statuses, isClosedStatus)
+}
@@ -0,0 +1,64 @@
+package operations
+
+import (
+	"strings"
+	"testing"
+
+	"gosynctasks/backend"
+	"gosynctasks/internal/config"
+)
+
+func TestSearchLists(t *testing.T) {
+	mb := backend.NewMockBackend()
+	work, _ := mb.CreateTaskList("Work", "", "")
+	home, _ := mb.CreateTaskList("Home", "", "")
+	mb.CreateTaskList("Empty", "", "")
+	mb.AddTask(work, backend.Task{UID: "w1", Summary: "Send invoice", Status: "NEEDS-ACTION"})
+	mb.AddTask(work, backend.Task{UID: "w2", Summary: "File invoice", Status: "COMPLETED"})
+	mb.AddTask(work, backend.Task{UID: "w3", Summary: "Chase invoice", Status: "NEEDS-ACTION"})
+	mb.AddTask(home, backend.Task{UID: "h1", Summary: "Pay INVOICE", Status: "NEEDS-ACTION"})
+	lists, _ := mb.GetTaskLists()
+
+	results, err := SearchLists(mb, lists, "invoice", nil, 0)
+	if err != nil {
+		t.Fatalf("SearchLists() error = %v", err)
+	}
+	if len(results) != 2 || results[0].List.Name != "Work" || len(results[0].Tasks) != 3 || results[1].List.Name != "Home" {
+		t.Fatalf("SearchLists() = %+v, want 3 tasks in Work and 1 in Home", results)
+	}
+
+	open := []string{"NEEDS-ACTION"}
+	results, err = SearchLists(mb, lists, "invoice", &backend.TaskFilter{Statuses: &open}, 1)
+	if err != nil {
+		t.Fatalf("SearchLists() error = %v", err)
+	}
+	if len(results) != 2 || len(results[0].Tasks) != 1 || results[0].Tasks[0].Status != "NEEDS-ACTION" {
+		t.Errorf("SearchLists(open, limit 1) = %+v", results)
+	}
+
+	results, err = SearchLists(mb, lists, "nothing", nil, 0)
+	if err != nil || len(results) != 0 {
+		t.Errorf("SearchLists(nothing) = %+v, %v, want no results", results, err)
+	}
+}
+
+// TestHandleSearchNoMatch tests that finding nothing fails, for scripts
+func TestHandleSearchNoMatch(t *testing.T) {
+	config.SetConfigForTest(&config.Config{})
+	mb := backend.NewMockBackend()
+	listID, _ := mb.CreateTaskList("Work", "", "")
+	mb.AddTask(listID, backend.Task{UID: "w1", Summary: "Send invoice", Status: "NEEDS-ACTION"})
+	lists, _ := mb.GetTaskLists()
+
+	cmd := newActionCmd()
+	cmd.Flags().Int("limit", 0, "")
+	err := HandleSearch(cmd, mb, &config.Config{}, lists, "receipt")
+	if err == nil || !strings.Contains(err.Error(), "no tasks match 'receipt'") {
+		t.Errorf("HandleSearch(receipt) error = %v, want no match", err)
+	}
+
+	cmd.Flags().Set("limit", "-1")
+	if err := HandleSearch(cmd, mb, &config.Config{}, lists, "invoice"); err == nil {
+		t.Error("HandleSearch with --limit -1 succeeded, want an error")
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"gosynctasks/backend"
 	"gosynctasks/internal/cli"
 	"gosynctasks/internal/config"
+	"gosynctasks/internal/listing"
 	"gosynctasks/internal/utils"
 	"strings"
 )
@@ -15,6 +16,7 @@ import (
 type TaskSelector struct {
 	taskManager backend.TaskManager
 	config      *config.Config
+	prompted    bool // The user was asked to pick or confirm the task
 }
 
 // SelectionOptions configures how task selection should behave.
@@ -49,6 +51,13 @@ func NewTaskSelector(taskManager backend.TaskManager, cfg *config.Config) *TaskS
 // Select finds and selects a task based on the search term and options.
 // This is the unified entry point replacing all the individual selection functions.
 func (ts *TaskSelector) Select(listID string, searchTerm string, opts SelectionOptions) (*backend.Task, error) {
+	ts.prompted = false
+
+	// A position in the last listing of the list, like %3
+	if position, ok := listing.ParseRef(searchTerm); ok {
+		return ts.selectByPosition(listID, position, opts)
+	}
+
 	// If no search term and we're in interactive mode, show all tasks
 	if searchTerm == "" && opts.DisplayFormat == "tree" {
 		return ts.selectFromAll(listID, opts)
@@ -73,6 +82,9 @@ func (ts *TaskSelector) Select(listID string, searchTerm string, opts SelectionO
 		return nil, utils.ErrTaskNotFound(searchTerm)
 	}
 
+	// Tell the user when a task was found by a name it had before a rename
+	ts.noteFormerNameMatches(matches, searchTerm)
+
 	// Handle exact and partial matches
 	if task, done, err := ts.handleMatches(matches, searchTerm, listID, opts); done {
 		return task, err
@@ -82,12 +94,45 @@ func (ts *TaskSelector) Select(listID string, searchTerm string, opts SelectionO
 	return ts.promptSelection(matches, searchTerm, listID, opts)
 }
 
+// Prompted reports whether the last selection waited for the user, so the
+// selected task may have changed since it was read
+func (ts *TaskSelector) Prompted() bool {
+	return ts.prompted
+}
+
+// noteFormerNameMatches prints which former summary matched for tasks whose
+// current summary does not contain the search term.
+func (ts *TaskSelector) noteFormerNameMatches(matches []backend.Task, searchTerm string) {
+	provider, ok := backend.As[backend.SummaryHistoryProvider](ts.taskManager)
+	if !ok {
+		return
+	}
+
+	term := strings.ToLower(searchTerm)
+	for _, task := range matches {
+		if strings.Contains(strings.ToLower(task.Summary), term) {
+			continue
+		}
+		history, err := provider.GetSummaryHistory(task.UID)
+		if err != nil {
+			continue
+		}
+		for _, alias := range history {
+			if strings.Contains(strings.ToLower(alias.Summary), term) {
+				fmt.Printf("'%s' matched former name '%s'\n", task.Summary, alias.Summary)
+				break
+			}
+		}
+	}
+}
+
 // selectFromAll shows all tasks in the list and prompts for selection (interactive mode).
 func (ts *TaskSelector) selectFromAll(listID string, opts SelectionOptions) (*backend.Task, error) {
 	tasks, err := ts.taskManager.GetTasks(listID, opts.Filter)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get tasks: %w", err)
 	}
+	tasks = backend.MarkDuplicateUIDs(tasks)
 
 	if len(tasks) == 0 {
 		return nil, utils.WrapWithSuggestion(
@@ -97,6 +142,7 @@ func (ts *TaskSelector) selectFromAll(listID string, opts SelectionOptions) (*ba
 	}
 
 	// Always use tree format for interactive selection
+	ts.prompted = true
 	return ts.displayTreeAndSelect(tasks, listID, opts.CancelText)
 }
 
@@ -123,6 +169,7 @@ func (ts *TaskSelector) handleMatches(matches []backend.Task, searchTerm string,
 	// Single partial match - ask for confirmation if configured
 	if len(exactMatches) == 0 && len(partialMatches) == 1 && opts.ConfirmSinglePartial {
 		task := &partialMatches[0]
+		ts.prompted = true
 		confirmed, err := confirmTask(task, ts.taskManager, ts.config)
 		if err != nil {
 			return nil, true, err
@@ -147,6 +194,7 @@ func (ts *TaskSelector) promptSelection(tasks []backend.Task, searchTerm string,
 	if len(tasks) == 0 {
 		return nil, fmt.Errorf("no tasks to select from")
 	}
+	ts.prompted = true
 
 	// Choose display format
 	if opts.DisplayFormat == "tree" {
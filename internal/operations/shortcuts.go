@@ -0,0 +1,88 @@
+package operations
+
+import (
+	"errors"
+	"fmt"
+	"gosynctasks/backend"
+	"gosynctasks/internal/utils"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// ParseShortcut splits the command line a shortcut stands for into words. It
+// starts with a list, optionally followed by an action from the registry, and
+// may carry flags.
+func ParseShortcut(definition string) ([]string, error) {
+	words, err := utils.SplitWords(definition)
+	if err != nil {
+		return nil, err
+	}
+	if len(words) == 0 {
+		return nil, fmt.Errorf("the command line is empty")
+	}
+	if strings.HasPrefix(words[0], "-") {
+		return nil, fmt.Errorf("the command line must start with a list, not %q", words[0])
+	}
+	if len(words) > 1 && !strings.HasPrefix(words[1], "-") {
+		if _, ok := LookupAction(words[1]); !ok {
+			return nil, fmt.Errorf("%q is not an action (%s)", words[1], supportedActions())
+		}
+	}
+	return words, nil
+}
+
+// ValidateShortcuts checks the shortcuts of the config: names that are single
+// words other than an action, and command lines ParseShortcut accepts
+func ValidateShortcuts(shortcuts map[string]string) error {
+	names := make([]string, 0, len(shortcuts))
+	for name := range shortcuts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if name == "" || strings.HasPrefix(name, "-") || strings.ContainsAny(name, " \t\n") {
+			return fmt.Errorf("shortcut %q: the name must be a single word not starting with '-'", name)
+		}
+		if _, ok := LookupAction(name); ok {
+			return fmt.Errorf("shortcut %q: the name is an action", name)
+		}
+		if _, err := ParseShortcut(shortcuts[name]); err != nil {
+			return fmt.Errorf("shortcut %q: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// ExpandShortcut returns the command line invoked, with the shortcut the first
+// argument names replaced by its definition and the rest of the line after
+// it. Expansion happens once: the words of a definition are not expanded
+// again. It returns nil when the first argument is no shortcut, and also when
+// a list has the same name, which wins; shadowed is then set.
+func ExpandShortcut(shortcuts map[string]string, lists []backend.TaskList, invocation, args []string) (expanded []string, shadowed bool, err error) {
+	if len(args) == 0 {
+		return nil, false, nil
+	}
+	definition, ok := shortcuts[args[0]]
+	if !ok {
+		return nil, false, nil
+	}
+	if _, err := FindListByNameFull(lists, args[0]); err == nil || errors.Is(err, errAmbiguousListName) {
+		return nil, true, nil
+	}
+
+	words, err := ParseShortcut(definition)
+	if err != nil {
+		return nil, false, fmt.Errorf("shortcut %q: %w", args[0], err)
+	}
+
+	// Flags given before the shortcut stay in front of it
+	at := slices.Index(invocation, args[0])
+	if at < 0 {
+		return append(words, args[1:]...), false, nil
+	}
+	expanded = append(expanded, invocation[:at]...)
+	expanded = append(expanded, words...)
+	return append(expanded, invocation[at+1:]...), false, nil
+}
@@ -0,0 +1,105 @@
+package operations
+
+import (
+	"gosynctasks/backend"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestExpandShortcut(t *testing.T) {
+	shortcuts := map[string]string{
+		"capture": "Inbox add",
+		"today":   "all get -s TODO,PROCESSING -v minimal",
+		"mom":     `Inbox add "call \"mom\"" -p 1`,
+		"again":   "capture add",
+		"Work":    "Inbox get",
+	}
+	lists := []backend.TaskList{{ID: "1", Name: "Inbox"}, {ID: "2", Name: "Work"}}
+
+	tests := []struct {
+		name       string
+		invocation []string
+		args       []string
+		want       []string
+	}{
+		{
+			name:       "rest of the line appended",
+			invocation: []string{"capture", "call mom", "-p", "1"},
+			args:       []string{"capture", "call mom"},
+			want:       []string{"Inbox", "add", "call mom", "-p", "1"},
+		},
+		{
+			name:       "flags of the definition kept",
+			invocation: []string{"today", "--sort", "due"},
+			args:       []string{"today"},
+			want:       []string{"all", "get", "-s", "TODO,PROCESSING", "-v", "minimal", "--sort", "due"},
+		},
+		{
+			name:       "quoting preserved",
+			invocation: []string{"mom"},
+			args:       []string{"mom"},
+			want:       []string{"Inbox", "add", `call "mom"`, "-p", "1"},
+		},
+		{
+			name:       "flags before the shortcut stay in front",
+			invocation: []string{"--verbose", "capture", "x"},
+			args:       []string{"capture", "x"},
+			want:       []string{"--verbose", "Inbox", "add", "x"},
+		},
+		{
+			name:       "not expanded again",
+			invocation: []string{"again"},
+			args:       []string{"again"},
+			want:       []string{"capture", "add"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, shadowed, err := ExpandShortcut(shortcuts, lists, tt.invocation, tt.args)
+			if err != nil || shadowed {
+				t.Fatalf("ExpandShortcut() shadowed = %v, error = %v", shadowed, err)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("ExpandShortcut() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("list wins", func(t *testing.T) {
+		got, shadowed, err := ExpandShortcut(shortcuts, lists, []string{"Work", "get"}, []string{"Work", "get"})
+		if err != nil || !shadowed || got != nil {
+			t.Errorf("ExpandShortcut(Work) = %q, shadowed %v, error %v; want nil, shadowed", got, shadowed, err)
+		}
+	})
+
+	t.Run("no shortcut", func(t *testing.T) {
+		got, shadowed, err := ExpandShortcut(shortcuts, lists, []string{"Inbox"}, []string{"Inbox"})
+		if err != nil || shadowed || got != nil {
+			t.Errorf("ExpandShortcut(Inbox) = %q, shadowed %v, error %v; want nil", got, shadowed, err)
+		}
+	})
+}
+
+func TestValidateShortcuts(t *testing.T) {
+	if err := ValidateShortcuts(map[string]string{"capture": "Inbox add", "inbox": "Inbox", "urgent": "all get -p 1"}); err != nil {
+		t.Fatalf("ValidateShortcuts() error = %v", err)
+	}
+
+	invalid := map[string]map[string]string{
+		"unknown action": {"x": "Inbox frobnicate"},
+		"action name":    {"add": "Inbox add"},
+		"flag first":     {"x": "-v all"},
+		"empty":          {"x": ""},
+		"unterminated":   {"x": `Inbox add "call`},
+		"spaced name":    {"my today": "all get"},
+	}
+	for name, shortcuts := range invalid {
+		err := ValidateShortcuts(shortcuts)
+		if err == nil {
+			t.Errorf("%s: ValidateShortcuts(%v) error = nil, want one", name, shortcuts)
+		} else if !strings.Contains(err.Error(), "shortcut") {
+			t.Errorf("%s: error %q does not name the shortcut", name, err)
+		}
+	}
+}
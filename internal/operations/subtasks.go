@@ -288,6 +288,60 @@ func selectTaskWithPath(tasks []backend.Task, searchSummary string, taskManager
 type TaskNode struct {
 	Task     *backend.Task
 	Children []*TaskNode
+	Hidden   int // Number of descendants folded away by CollapseTaskTree
+}
+
+// CollapseTaskTree folds every root's subtree into a count of its descendants,
+// so that only root tasks are rendered
+func CollapseTaskTree(nodes []*TaskNode) {
+	for _, node := range nodes {
+		node.Hidden = countDescendants(node)
+		node.Children = nil
+	}
+}
+
+func countDescendants(node *TaskNode) int {
+	count := node.Hidden
+	for _, child := range node.Children {
+		count += 1 + countDescendants(child)
+	}
+	return count
+}
+
+// FetchSubtree returns the task and its descendants, fetched one level at a
+// time through the ParentUID filter. The task is returned as a root, first.
+// Other criteria in filter apply to the descendants.
+func FetchSubtree(taskManager backend.TaskManager, listID string, task backend.Task, filter *backend.TaskFilter) ([]backend.Task, error) {
+	task.ParentUID = ""
+	subtree := []backend.Task{task}
+	seen := map[string]bool{task.UID: true}
+
+	for level := []string{task.UID}; len(level) > 0; {
+		var next []string
+		for _, parentUID := range level {
+			childFilter := backend.TaskFilter{}
+			if filter != nil {
+				childFilter = *filter
+			}
+			childFilter.ParentUID = &parentUID
+
+			children, err := taskManager.GetTasks(listID, &childFilter)
+			if err != nil {
+				return nil, fmt.Errorf("error retrieving subtasks: %w", err)
+			}
+			for _, child := range children {
+				if seen[child.UID] {
+					continue
+				}
+				seen[child.UID] = true
+				subtree = append(subtree, child)
+				next = append(next, child.UID)
+			}
+		}
+		level = next
+	}
+
+	return subtree, nil
 }
 
 // BuildTaskTree builds a hierarchical tree from a flat list of tasks
@@ -311,13 +365,19 @@ func BuildTaskTree(tasks []backend.Task) []*TaskNode {
 		}
 	}
 
-	// Recursively build tree
+	// Recursively build tree. Children of a UID shared by several tasks
+	// are attached to the first of them only.
+	expanded := make(map[string]bool)
 	var buildNode func(*backend.Task) *TaskNode
 	buildNode = func(task *backend.Task) *TaskNode {
 		node := &TaskNode{
 			Task:     task,
 			Children: []*TaskNode{},
 		}
+		if expanded[task.UID] {
+			return node
+		}
+		expanded[task.UID] = true
 
 		// Add children recursively
 		if children, exists := childrenMap[task.UID]; exists {
@@ -454,6 +514,21 @@ func addParentIndicator(taskOutput string, childCount int) string {
 	return strings.Join(lines, "\n")
 }
 
+// addCollapsedIndicator appends "▸ N subtasks" to the first line of a task
+// whose subtree was folded away
+func addCollapsedIndicator(taskOutput string, hidden int) string {
+	noun := "subtasks"
+	if hidden == 1 {
+		noun = "subtask"
+	}
+	first, rest, found := strings.Cut(taskOutput, "\n")
+	indicator := fmt.Sprintf("%s ▸ %d %s", first, hidden, noun)
+	if !found {
+		return indicator
+	}
+	return indicator + "\n" + rest
+}
+
 // FormatTaskTree formats a task tree with box-drawing characters for hierarchical display
 func FormatTaskTree(nodes []*TaskNode, view string, taskManager backend.TaskManager, dateFormat string) string {
 	var result strings.Builder
@@ -492,6 +567,9 @@ func formatNode(result *strings.Builder, nodes []*TaskNode, prefix string, isRoo
 		if len(node.Children) > 0 {
 			taskOutput = addParentIndicator(taskOutput, len(node.Children))
 		}
+		if node.Hidden > 0 {
+			taskOutput = addCollapsedIndicator(taskOutput, node.Hidden)
+		}
 
 		// Add indentation to each line of the task output
 		if nodePrefix != "" {
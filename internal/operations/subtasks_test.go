@@ -345,3 +345,62 @@ func TestSortTaskTree_MultiLevelHierarchy(t *testing.T) {
 		t.Errorf("Expected grandchild to be 'Grandchild Task', got '%s'", grandchild.Task.Summary)
 	}
 }
+
+func newReleaseTasks() []backend.Task {
+	return []backend.Task{
+		{UID: "release", Summary: "Release v2", Status: "NEEDS-ACTION"},
+		{UID: "build", Summary: "Build", Status: "NEEDS-ACTION", ParentUID: "release"},
+		{UID: "linux", Summary: "Linux", Status: "NEEDS-ACTION", ParentUID: "build"},
+		{UID: "mac", Summary: "Mac", Status: "NEEDS-ACTION", ParentUID: "build"},
+		{UID: "notes", Summary: "Notes", Status: "NEEDS-ACTION", ParentUID: "release"},
+		{UID: "other", Summary: "Other", Status: "NEEDS-ACTION"},
+	}
+}
+
+// TestCollapseTaskTree verifies hidden counts include nested descendants
+func TestCollapseTaskTree(t *testing.T) {
+	tree := BuildTaskTree(newReleaseTasks())
+	CollapseTaskTree(tree)
+
+	hidden := map[string]int{}
+	for _, node := range tree {
+		if node.Children != nil {
+			t.Errorf("root %s still has children after collapse", node.Task.UID)
+		}
+		hidden[node.Task.UID] = node.Hidden
+	}
+	if hidden["release"] != 4 || hidden["other"] != 0 {
+		t.Errorf("Hidden = %v, want release=4 other=0", hidden)
+	}
+
+	if got := addCollapsedIndicator("Release v2", 4); got != "Release v2 ▸ 4 subtasks" {
+		t.Errorf("addCollapsedIndicator() = %q", got)
+	}
+	if got := addCollapsedIndicator("Build", 1); got != "Build ▸ 1 subtask" {
+		t.Errorf("addCollapsedIndicator() = %q", got)
+	}
+}
+
+// TestFetchSubtree verifies only the selected task and its descendants are returned
+func TestFetchSubtree(t *testing.T) {
+	mb := backend.NewMockBackend()
+	mb.Tasks["list"] = newReleaseTasks()
+	root := mb.Tasks["list"][0]
+	root.ParentUID = "ignored"
+
+	tasks, err := FetchSubtree(mb, "list", root, nil)
+	if err != nil {
+		t.Fatalf("FetchSubtree() error = %v", err)
+	}
+	if len(tasks) != 5 {
+		t.Fatalf("FetchSubtree() returned %d tasks, want 5", len(tasks))
+	}
+	if tasks[0].UID != "release" || tasks[0].ParentUID != "" {
+		t.Errorf("first task = %s (parent %q), want root with no parent", tasks[0].UID, tasks[0].ParentUID)
+	}
+	for _, task := range tasks {
+		if task.UID == "other" {
+			t.Error("FetchSubtree() included a task outside the subtree")
+		}
+	}
+}
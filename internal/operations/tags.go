@@ -0,0 +1,223 @@
+package operations
+
+import (
+	"fmt"
+	"gosynctasks/backend"
+	"gosynctasks/internal/utils"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// TagCount is the number of tasks carrying a tag
+type TagCount struct {
+	Name  string
+	Count int
+}
+
+// TagChange is a planned rewrite of one task's categories
+type TagChange struct {
+	ListID        string
+	ListName      string
+	Task          backend.Task
+	NewCategories []string
+	Err           error // Set when the change is rejected before applying
+}
+
+// TagResult is the outcome of applying one change
+type TagResult struct {
+	Change TagChange
+	Err    error
+}
+
+// CountTags returns the tags used in the given lists with the number of tasks
+// carrying each, most used first. Backends implementing
+// backend.CategoryCounter count without loading the tasks.
+func CountTags(taskManager backend.TaskManager, lists []backend.TaskList) ([]TagCount, error) {
+	totals := make(map[string]int)
+	for _, list := range lists {
+		if counter, ok := backend.As[backend.CategoryCounter](taskManager); ok {
+			counts, err := counter.CountCategories(list.ID)
+			if err != nil {
+				return nil, fmt.Errorf("error counting tags in '%s': %w", list.Name, err)
+			}
+			for tag, count := range counts {
+				totals[tag] += count
+			}
+			continue
+		}
+
+		tasks, err := taskManager.GetTasks(list.ID, nil)
+		if err != nil {
+			return nil, fmt.Errorf("error retrieving tasks from '%s': %w", list.Name, err)
+		}
+		for _, task := range tasks {
+			seen := make(map[string]bool, len(task.Categories))
+			for _, tag := range task.Categories {
+				tag = strings.TrimSpace(tag)
+				if tag == "" || seen[tag] {
+					continue
+				}
+				seen[tag] = true
+				totals[tag]++
+			}
+		}
+	}
+
+	counts := make([]TagCount, 0, len(totals))
+	for tag, count := range totals {
+		counts = append(counts, TagCount{Name: tag, Count: count})
+	}
+	sort.Slice(counts, func(i, j int) bool {
+		if counts[i].Count != counts[j].Count {
+			return counts[i].Count > counts[j].Count
+		}
+		return counts[i].Name < counts[j].Name
+	})
+	return counts, nil
+}
+
+// FindTagChanges returns the changes that replace the source tags with target
+// on every task in the given lists, closed tasks included. An empty target
+// removes the sources. Tags match exactly, so "Work" and "work" are distinct
+// sources; a task ends up with the target at most once.
+func FindTagChanges(taskManager backend.TaskManager, lists []backend.TaskList, sources []string, target string) ([]TagChange, error) {
+	target = strings.TrimSpace(target)
+	if strings.Contains(target, ",") {
+		return nil, fmt.Errorf("tag %q cannot contain a comma", target)
+	}
+	isSource := make(map[string]bool, len(sources))
+	for _, source := range sources {
+		if source = strings.TrimSpace(source); source != "" && source != target {
+			isSource[source] = true
+		}
+	}
+	if len(isSource) == 0 {
+		return nil, fmt.Errorf("no tags to change")
+	}
+
+	var changes []TagChange
+	for _, list := range lists {
+		tasks, err := taskManager.GetTasks(list.ID, nil)
+		if err != nil {
+			return nil, fmt.Errorf("error retrieving tasks from '%s': %w", list.Name, err)
+		}
+		duplicates := backend.DuplicateUIDs(tasks)
+
+		for _, task := range tasks {
+			if !slices.ContainsFunc(task.Categories, func(tag string) bool { return isSource[strings.TrimSpace(tag)] }) {
+				continue
+			}
+
+			change := TagChange{
+				ListID:        list.ID,
+				ListName:      list.Name,
+				Task:          task,
+				NewCategories: retag(task.Categories, isSource, target),
+			}
+			if count := duplicates[task.UID]; count > 1 {
+				change.Err = backend.NewDuplicateUIDError("UpdateTask", list.ID, task.UID, count)
+			}
+			changes = append(changes, change)
+		}
+	}
+
+	return changes, nil
+}
+
+// retag replaces the source tags with target, keeping the order of the other
+// tags and the position of the first replaced one
+func retag(categories []string, isSource map[string]bool, target string) []string {
+	result := make([]string, 0, len(categories))
+	for _, tag := range categories {
+		if isSource[strings.TrimSpace(tag)] {
+			tag = target
+		}
+		if tag == "" || slices.Contains(result, tag) {
+			continue
+		}
+		result = append(result, tag)
+	}
+	return result
+}
+
+// EditTags applies the values of --tag to categories. Each value may hold
+// several tags separated by commas. "+foo" adds a tag and "-foo" removes one;
+// plain tags replace the set, before the additions and removals are applied.
+// Tags are kept in order, without duplicates.
+func EditTags(categories []string, values []string) ([]string, error) {
+	var replace, add, remove []string
+	replacing := false
+	for _, value := range values {
+		for _, tag := range strings.Split(value, ",") {
+			tag = strings.TrimSpace(tag)
+			switch {
+			case strings.HasPrefix(tag, "+"):
+				add = append(add, strings.TrimSpace(tag[1:]))
+			case strings.HasPrefix(tag, "-"):
+				remove = append(remove, strings.TrimSpace(tag[1:]))
+			default:
+				replace = append(replace, tag)
+				replacing = true
+			}
+		}
+	}
+	for _, tag := range slices.Concat(replace, add, remove) {
+		if tag == "" {
+			return nil, fmt.Errorf("empty tag in --tag %s", strings.Join(values, " "))
+		}
+	}
+
+	base := categories
+	if replacing {
+		base = nil
+	}
+	var edited []string
+	for _, tag := range slices.Concat(base, replace, add) {
+		if !slices.Contains(edited, tag) && !slices.Contains(remove, tag) {
+			edited = append(edited, tag)
+		}
+	}
+	return edited, nil
+}
+
+// FormatTagChange renders a change as the task with its added and removed tags
+func FormatTagChange(change TagChange) string {
+	red, green, gray, reset := "\033[31m", "\033[32m", "\033[90m", "\033[0m"
+
+	var parts []string
+	for _, tag := range change.Task.Categories {
+		if !slices.Contains(change.NewCategories, tag) {
+			parts = append(parts, red+"-"+utils.SanitizeLine(tag)+reset)
+		}
+	}
+	for _, tag := range change.NewCategories {
+		if !slices.Contains(change.Task.Categories, tag) {
+			parts = append(parts, green+"+"+utils.SanitizeLine(tag)+reset)
+		}
+	}
+
+	line := fmt.Sprintf("%s[%s]%s %s  %s\n", gray, utils.SanitizeLine(change.ListName), reset,
+		utils.SanitizeLine(change.Task.Summary), strings.Join(parts, " "))
+	if change.Err != nil {
+		line += fmt.Sprintf("  %s! skipped: %v%s\n", red, change.Err, reset)
+	}
+	return line
+}
+
+// ApplyTagChanges writes the tag changes with applyBulkUpdates and reports a
+// result per task
+func ApplyTagChanges(taskManager backend.TaskManager, changes []TagChange, syncProvider SyncCoordinatorProvider) []TagResult {
+	updates := make([]bulkUpdate, len(changes))
+	for i, change := range changes {
+		task := change.Task
+		task.Categories = change.NewCategories
+		updates[i] = bulkUpdate{listID: change.ListID, task: task, err: change.Err}
+	}
+
+	results := make([]TagResult, len(changes))
+	for i, err := range applyBulkUpdates(taskManager, updates, syncProvider) {
+		results[i] = TagResult{Change: changes[i], Err: err}
+	}
+	return results
+}
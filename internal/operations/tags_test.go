@@ -0,0 +1,160 @@
+package operations
+
+import (
+	"gosynctasks/backend"
+	"gosynctasks/internal/config"
+	"slices"
+	"testing"
+)
+
+func newTagsBackend() (*backend.MockBackend, []backend.TaskList) {
+	mb := backend.NewMockBackend()
+	mb.Tasks["work"] = []backend.Task{
+		{UID: "1", Summary: "Report", Status: "NEEDS-ACTION", Categories: []string{"work", "urgent"}},
+		{UID: "2", Summary: "Typo", Status: "NEEDS-ACTION", Categories: []string{"wokr"}},
+		{UID: "3", Summary: "Both", Status: "COMPLETED", Categories: []string{"Work", "work", "later"}},
+	}
+	mb.Tasks["home"] = []backend.Task{
+		{UID: "4", Summary: "Garden", Status: "NEEDS-ACTION", Categories: []string{"later"}},
+		{UID: "5", Summary: "Untagged", Status: "NEEDS-ACTION"},
+	}
+	lists := []backend.TaskList{{ID: "work", Name: "Work"}, {ID: "home", Name: "Home"}}
+	return mb, lists
+}
+
+func TestCountTags(t *testing.T) {
+	mb, lists := newTagsBackend()
+	counts, err := CountTags(mb, lists)
+	if err != nil {
+		t.Fatalf("CountTags() error = %v", err)
+	}
+	want := []TagCount{{"later", 2}, {"work", 2}, {"Work", 1}, {"urgent", 1}, {"wokr", 1}}
+	if !slices.Equal(counts, want) {
+		t.Errorf("CountTags() = %v, want %v", counts, want)
+	}
+}
+
+func TestFindTagChanges(t *testing.T) {
+	tests := []struct {
+		name    string
+		sources []string
+		target  string
+		want    map[string][]string // UID -> new categories
+	}{
+		{
+			name:    "merge spellings into one tag",
+			sources: []string{"wokr", "Work"},
+			target:  "work",
+			want: map[string][]string{
+				"2": {"work"},
+				"3": {"work", "later"},
+			},
+		},
+		{
+			name:    "remove everywhere",
+			sources: []string{"later"},
+			want: map[string][]string{
+				"3": {"Work", "work"},
+				"4": {},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mb, lists := newTagsBackend()
+			changes, err := FindTagChanges(mb, lists, tt.sources, tt.target)
+			if err != nil {
+				t.Fatalf("FindTagChanges() error = %v", err)
+			}
+			if len(changes) != len(tt.want) {
+				t.Fatalf("got %d changes, want %d", len(changes), len(tt.want))
+			}
+			for _, c := range changes {
+				if !slices.Equal(c.NewCategories, tt.want[c.Task.UID]) {
+					t.Errorf("task %s: NewCategories = %v, want %v", c.Task.UID, c.NewCategories, tt.want[c.Task.UID])
+				}
+			}
+		})
+	}
+}
+
+func TestFindTagChanges_Invalid(t *testing.T) {
+	mb, lists := newTagsBackend()
+	if _, err := FindTagChanges(mb, lists, []string{"work"}, "work"); err == nil {
+		t.Error("FindTagChanges() expected error when the only source is the target")
+	}
+	if _, err := FindTagChanges(mb, lists, []string{"wokr"}, "a,b"); err == nil {
+		t.Error("FindTagChanges() expected error for a target with a comma")
+	}
+}
+
+func TestApplyTagChanges(t *testing.T) {
+	mb, lists := newTagsBackend()
+	changes, err := FindTagChanges(mb, lists, []string{"wokr"}, "work")
+	if err != nil {
+		t.Fatalf("FindTagChanges() error = %v", err)
+	}
+
+	results := ApplyTagChanges(mb, changes, nil)
+	if len(results) != 1 || results[0].Err != nil {
+		t.Fatalf("ApplyTagChanges() = %+v", results)
+	}
+	if got := mb.Tasks["work"][1].Categories; !slices.Equal(got, []string{"work"}) {
+		t.Errorf("categories after apply = %v, want [work]", got)
+	}
+}
+
+func TestEditTags(t *testing.T) {
+	current := []string{"work", "someday"}
+	tests := []struct {
+		values []string
+		want   []string
+	}{
+		{[]string{"home"}, []string{"home"}},
+		{[]string{"home,errands", "home"}, []string{"home", "errands"}},
+		{[]string{"+urgent"}, []string{"work", "someday", "urgent"}},
+		{[]string{"-someday"}, []string{"work"}},
+		{[]string{"+urgent, -someday"}, []string{"work", "urgent"}},
+		{[]string{"+work"}, []string{"work", "someday"}},
+		{[]string{"home", "+urgent"}, []string{"home", "urgent"}},
+		{[]string{"-work", "-someday"}, nil},
+	}
+	for _, tt := range tests {
+		got, err := EditTags(current, tt.values)
+		if err != nil || !slices.Equal(got, tt.want) {
+			t.Errorf("EditTags(%q, %q) = %q, %v, want %q", current, tt.values, got, err, tt.want)
+		}
+	}
+
+	if _, err := EditTags(current, []string{"+"}); err == nil {
+		t.Error("EditTags(+) error = nil, want one")
+	}
+}
+
+// TestTagFlag tests that --tag sets the tags on add, and edits them on update
+func TestTagFlag(t *testing.T) {
+	config.SetConfigForTest(&config.Config{})
+	list := &backend.TaskList{ID: "list-1", Name: "Chores"}
+	mb := backend.NewMockBackend()
+
+	add := newActionCmd()
+	_ = add.Flags().Set("tag", "work")
+	_ = add.Flags().Set("tag", "urgent,someday")
+	if err := HandleAddAction(add, mb, list, "Fix login", nil); err != nil {
+		t.Fatalf("add --tag error = %v", err)
+	}
+	if got := mb.Tasks["list-1"][0].Categories; !slices.Equal(got, []string{"work", "urgent", "someday"}) {
+		t.Fatalf("tags after add = %q", got)
+	}
+
+	update := newActionCmd()
+	_ = update.Flags().Set("tag", "-someday")
+	_ = update.Flags().Set("tag", "+review")
+	if err := HandleUpdateAction(update, mb, &config.Config{}, list, "Fix login", nil); err != nil {
+		t.Fatalf("update --tag error = %v", err)
+	}
+	if got := mb.Tasks["list-1"][0].Categories; !slices.Equal(got, []string{"work", "urgent", "review"}) {
+		t.Errorf("tags after update = %q, want work, urgent, review", got)
+	}
+}
@@ -3,9 +3,11 @@ package operations
 import (
 	"fmt"
 	"gosynctasks/backend"
+	"gosynctasks/internal/cli"
 	"gosynctasks/internal/config"
 	"gosynctasks/internal/utils"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 )
@@ -33,10 +35,77 @@ func confirmTask(task *backend.Task, taskManager backend.TaskManager, cfg *confi
 	dateFormat := cfg.GetDateFormat()
 	fmt.Println("\nTask found:")
 	fmt.Print(task.FormatWithView("all", taskManager, dateFormat))
+	fmt.Print(formatSummaryHistory(taskManager, task.UID, dateFormat))
 	fmt.Println()
 	return utils.PromptConfirmation("Proceed with this task?")
 }
 
+// formatSummaryHistory lists the former summaries of a task, most recent
+// first, when the backend remembers them; empty otherwise
+func formatSummaryHistory(taskManager backend.TaskManager, taskUID, dateFormat string) string {
+	provider, ok := backend.As[backend.SummaryHistoryProvider](taskManager)
+	if !ok {
+		return ""
+	}
+	history, err := provider.GetSummaryHistory(taskUID)
+	if err != nil || len(history) == 0 {
+		return ""
+	}
+
+	var sb strings.Builder
+	sb.WriteString("  Formerly:\n")
+	for _, alias := range history {
+		fmt.Fprintf(&sb, "    '%s' (renamed %s)\n", utils.SanitizeLine(alias.Summary), alias.ChangedAt.Local().Format(dateFormat))
+	}
+	return sb.String()
+}
+
+// confirmChangedTask asks whether to go on with a task that changed while
+// the user was choosing it; a variable so tests can answer
+var confirmChangedTask = utils.PromptConfirmation
+
+// recheckTask reads a task again after the user picked or confirmed it, as
+// minutes may pass in between and a background sync may change it. When its
+// summary or status is no longer what was shown, the change is displayed and
+// the user asked again. Returns the current version of the task.
+func recheckTask(taskManager backend.TaskManager, listID string, shown *backend.Task) (*backend.Task, error) {
+	current, err := taskManager.GetTask(listID, shown.UID)
+	if err != nil {
+		if backendErr, ok := err.(*backend.BackendError); ok && backendErr.IsNotFound() {
+			return nil, fmt.Errorf("task '%s' no longer exists, it was deleted while you were choosing it", shown.Summary)
+		}
+		return nil, fmt.Errorf("failed to read task '%s' again: %w", shown.Summary, err)
+	}
+	return confirmIfChanged(taskManager, shown, current)
+}
+
+// confirmIfChanged displays how a task changed since it was shown, if it did,
+// and asks whether to go on with its current version
+func confirmIfChanged(taskManager backend.TaskManager, shown, current *backend.Task) (*backend.Task, error) {
+	var changes []string
+	if current.Summary != shown.Summary {
+		changes = append(changes, fmt.Sprintf("  Summary: '%s' → '%s'", utils.SanitizeLine(shown.Summary), utils.SanitizeLine(current.Summary)))
+	}
+	if current.Status != shown.Status {
+		changes = append(changes, fmt.Sprintf("  Status: %s → %s",
+			taskManager.StatusToDisplayName(shown.Status), taskManager.StatusToDisplayName(current.Status)))
+	}
+	if len(changes) == 0 {
+		return current, nil
+	}
+
+	fmt.Println("\nThe task changed since it was shown:")
+	fmt.Println(strings.Join(changes, "\n"))
+	confirmed, err := confirmChangedTask("Proceed with the changed task?")
+	if err != nil {
+		return nil, err
+	}
+	if !confirmed {
+		return nil, fmt.Errorf("operation cancelled")
+	}
+	return current, nil
+}
+
 // buildFlatTaskList recursively builds a flat list of tasks from the tree
 // This is useful for numbered selection where we need sequential access
 func buildFlatTaskList(nodes []*TaskNode, flatTasks *[]*backend.Task) {
@@ -148,5 +217,62 @@ func BuildFilter(cmd *cobra.Command, taskManager backend.TaskManager) (*backend.
 		filter.Statuses = &parsedStatuses
 	}
 
+	// Get overdue flag (open tasks past their due date)
+	if overdue, _ := cmd.Flags().GetBool("overdue"); overdue {
+		filter.Overdue = true
+		warnClosedStatusesOverdue(filter, taskManager)
+	}
+
+	// Get priority flag (a priority, a range like 1-4, or a name like high)
+	if flag := cmd.Flags().Lookup("priority"); flag != nil && flag.Changed {
+		minimum, maximum, err := cli.ParsePriorityRange(flag.Value.String())
+		if err != nil {
+			return nil, err
+		}
+		filter.PriorityMin, filter.PriorityMax = &minimum, &maximum
+	}
+
+	// Get modified-since flag (accepts relative durations like 2d or a date)
+	modifiedSince, _ := cmd.Flags().GetString("modified-since")
+	if modifiedSince != "" {
+		since, err := utils.ParseSinceFlag(modifiedSince, time.Now())
+		if err != nil {
+			return nil, err
+		}
+		filter.ModifiedAfter = since
+	}
+
+	// Get completed-since flag (also asks backends to include completed tasks)
+	completedSince, _ := cmd.Flags().GetString("completed-since")
+	if completedSince != "" {
+		since, err := utils.ParseSinceFlag(completedSince, time.Now())
+		if err != nil {
+			return nil, err
+		}
+		filter.CompletedAfter = since
+		filter.IncludeCompleted = true
+	}
+
 	return filter, nil
 }
+
+// warnClosedStatusesOverdue warns when --overdue is combined with statuses of
+// closed tasks, which it never matches
+func warnClosedStatusesOverdue(filter *backend.TaskFilter, taskManager backend.TaskManager) {
+	if filter.Statuses == nil {
+		return
+	}
+	var closed []string
+	for _, status := range *filter.Statuses {
+		if backend.IsCompletedStatus(status) || status == "CANCELLED" {
+			closed = append(closed, taskManager.StatusToDisplayName(status))
+		}
+	}
+	switch {
+	case len(closed) == 0:
+	case len(closed) == len(*filter.Statuses):
+		utils.Warnf("--overdue only matches open tasks, so no task can match -s %s", strings.Join(closed, ","))
+	default:
+		utils.Warnf("--overdue only matches open tasks, so -s %s matches none", strings.Join(closed, ","))
+	}
+}
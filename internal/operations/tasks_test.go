@@ -5,8 +5,10 @@ import (
 	"fmt"
 	"gosynctasks/backend"
 	"gosynctasks/internal/config"
+	"os"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/spf13/cobra"
 )
@@ -36,6 +38,10 @@ func (m *mockTaskManagerForOperations) FindTasksBySummary(listID string, summary
 	return m.findResults, nil
 }
 
+func (m *mockTaskManagerForOperations) GetTask(listID string, taskUID string) (*backend.Task, error) {
+	return backend.ScanForTask(m, listID, taskUID)
+}
+
 func (m *mockTaskManagerForOperations) AddTask(listID string, task backend.Task) (string, error) {
 	return "mock-task-id", nil
 }
@@ -352,6 +358,53 @@ func TestBuildFilter_TrimSpaces(t *testing.T) {
 	}
 }
 
+func TestBuildFilter_Priority(t *testing.T) {
+	tests := map[string][2]int{"2": {2, 2}, "1-4": {1, 4}, "low": {6, 9}, "none": {0, 0}}
+	for value, want := range tests {
+		cmd := &cobra.Command{}
+		cmd.Flags().StringP("priority", "p", "", "")
+		cmd.Flags().Set("priority", value)
+
+		filter, err := BuildFilter(cmd, &mockTaskManagerForOperations{})
+		if err != nil {
+			t.Fatalf("BuildFilter(-p %s) failed: %v", value, err)
+		}
+		if filter.PriorityMin == nil || filter.PriorityMax == nil || *filter.PriorityMin != want[0] || *filter.PriorityMax != want[1] {
+			t.Errorf("BuildFilter(-p %s) range = %v-%v, want %d-%d", value, filter.PriorityMin, filter.PriorityMax, want[0], want[1])
+		}
+	}
+
+	cmd := &cobra.Command{}
+	cmd.Flags().StringP("priority", "p", "", "")
+	if filter, err := BuildFilter(cmd, &mockTaskManagerForOperations{}); err != nil || filter.PriorityMin != nil || filter.PriorityMax != nil {
+		t.Errorf("BuildFilter() without -p = %+v, %v, want no priority range", filter, err)
+	}
+	cmd.Flags().Set("priority", "urgent")
+	if _, err := BuildFilter(cmd, &mockTaskManagerForOperations{}); err == nil {
+		t.Error("BuildFilter(-p urgent) should return an error")
+	}
+}
+
+func TestBuildFilter_Overdue(t *testing.T) {
+	cmd := &cobra.Command{}
+	cmd.Flags().StringArray("status", []string{}, "")
+	cmd.Flags().Bool("overdue", false, "")
+	if filter, err := BuildFilter(cmd, &mockTaskManagerForOperations{}); err != nil || filter.Overdue {
+		t.Errorf("BuildFilter() without --overdue = %+v, %v, want Overdue unset", filter, err)
+	}
+
+	// Composes with status filters, even ones it never matches
+	cmd.Flags().Set("overdue", "true")
+	cmd.Flags().Set("status", "TODO,DONE")
+	filter, err := BuildFilter(cmd, &mockTaskManagerForOperations{})
+	if err != nil {
+		t.Fatalf("BuildFilter(--overdue -s TODO,DONE) failed: %v", err)
+	}
+	if !filter.Overdue || filter.Statuses == nil || len(*filter.Statuses) != 2 {
+		t.Errorf("BuildFilter(--overdue -s TODO,DONE) = %+v, want Overdue with both statuses", filter)
+	}
+}
+
 func TestBuildFilter_CaseInsensitive(t *testing.T) {
 	tests := []struct {
 		input    string
@@ -755,3 +808,229 @@ func TestSelectTaskInteractively_BackendError(t *testing.T) {
 		// We mainly want to ensure it doesn't panic
 	}
 }
+
+// mutatingBackend changes its tasks right after a search returns, as a
+// background sync or another client could while the user is choosing
+type mutatingBackend struct {
+	*backend.MockBackend
+	mutate func(mb *backend.MockBackend)
+}
+
+func (m *mutatingBackend) FindTasksBySummary(listID string, summary string) ([]backend.Task, error) {
+	tasks, err := m.MockBackend.FindTasksBySummary(listID, summary)
+	m.mutate(m.MockBackend)
+	return tasks, err
+}
+
+// typeInput feeds input to the prompts reading stdin
+func typeInput(t *testing.T, input string) {
+	t.Helper()
+	reader, writer, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdin := os.Stdin
+	os.Stdin = reader
+	t.Cleanup(func() { os.Stdin = stdin })
+	_, _ = writer.WriteString(input)
+	_ = writer.Close()
+}
+
+// TestStaleReadRecheck tests that a task changed between being shown and the
+// user's choice is read again and, if its summary or status changed,
+// confirmed again before the write
+func TestStaleReadRecheck(t *testing.T) {
+	config.SetConfigForTest(&config.Config{})
+	list := &backend.TaskList{ID: "list-1", Name: "Chores"}
+	renamed := func(mb *backend.MockBackend) { mb.Tasks["list-1"][0].Summary = "Buy oat milk" }
+	completed := func(mb *backend.MockBackend) { mb.Tasks["list-1"][0].Status = "COMPLETED" }
+	deleted := func(mb *backend.MockBackend) { mb.Tasks["list-1"] = mb.Tasks["list-1"][1:] }
+	untouched := func(mb *backend.MockBackend) {}
+
+	tests := []struct {
+		name        string
+		action      string
+		input       string // Typed at the selection or deletion prompt
+		mutate      func(mb *backend.MockBackend)
+		answer      bool // To the re-confirmation
+		wantAsked   bool
+		wantErr     string
+		wantSummary string // Of task t1 afterwards, "" when deleted
+		wantStatus  string
+	}{
+		{name: "complete renamed, confirmed", action: "complete", input: "1\n", mutate: renamed, answer: true,
+			wantAsked: true, wantSummary: "Buy oat milk", wantStatus: "COMPLETED"},
+		{name: "complete renamed, declined", action: "complete", input: "1\n", mutate: renamed,
+			wantAsked: true, wantErr: "cancelled", wantSummary: "Buy oat milk", wantStatus: "NEEDS-ACTION"},
+		{name: "update completed meanwhile", action: "update", input: "1\n", mutate: completed,
+			wantAsked: true, wantErr: "cancelled", wantSummary: "Buy milk", wantStatus: "COMPLETED"},
+		{name: "complete unchanged", action: "complete", input: "1\n", mutate: untouched,
+			wantSummary: "Buy milk", wantStatus: "COMPLETED"},
+		{name: "delete deleted meanwhile", action: "delete", input: "y\n", mutate: deleted,
+			wantErr: "no longer exists"},
+		{name: "delete renamed, confirmed", action: "delete", input: "y\n", mutate: renamed, answer: true,
+			wantAsked: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mb := backend.NewMockBackend()
+			mb.Tasks["list-1"] = []backend.Task{
+				{UID: "t1", Summary: "Buy milk", Status: "NEEDS-ACTION"},
+				{UID: "t2", Summary: "Buy bread", Status: "NEEDS-ACTION"},
+			}
+			tm := &mutatingBackend{MockBackend: mb, mutate: tt.mutate}
+
+			asked := false
+			oldConfirm := confirmChangedTask
+			confirmChangedTask = func(string) (bool, error) { asked = true; return tt.answer, nil }
+			t.Cleanup(func() { confirmChangedTask = oldConfirm })
+			typeInput(t, tt.input)
+
+			search := "Buy"
+			if tt.action == "delete" {
+				search = "Buy milk"
+			}
+			cmd := newActionCmd()
+			var err error
+			switch tt.action {
+			case "complete":
+				err = HandleCompleteAction(cmd, tm, &config.Config{}, list, search, nil)
+			case "update":
+				_ = cmd.Flags().Set("priority", "1")
+				err = HandleUpdateAction(cmd, tm, &config.Config{}, list, search, nil)
+			case "delete":
+				err = HandleDeleteAction(cmd, tm, &config.Config{}, list, search, nil)
+			}
+
+			if tt.wantErr == "" && err != nil {
+				t.Fatalf("%s error = %v", tt.action, err)
+			}
+			if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Fatalf("%s error = %v, want %q", tt.action, err, tt.wantErr)
+			}
+			if asked != tt.wantAsked {
+				t.Errorf("asked again = %v, want %v", asked, tt.wantAsked)
+			}
+
+			var task *backend.Task
+			for i := range mb.Tasks["list-1"] {
+				if mb.Tasks["list-1"][i].UID == "t1" {
+					task = &mb.Tasks["list-1"][i]
+				}
+			}
+			if tt.wantSummary == "" {
+				if task != nil {
+					t.Errorf("task t1 still exists: %+v", task)
+				}
+				return
+			}
+			if task == nil {
+				t.Fatal("task t1 is gone")
+			}
+			if task.Summary != tt.wantSummary || task.Status != tt.wantStatus {
+				t.Errorf("task t1 = %q %s, want %q %s", task.Summary, task.Status, tt.wantSummary, tt.wantStatus)
+			}
+		})
+	}
+}
+
+// TestDeleteForce tests that --force deletes without reading a confirmation
+func TestDeleteForce(t *testing.T) {
+	config.SetConfigForTest(&config.Config{})
+	list := &backend.TaskList{ID: "list-1", Name: "Chores"}
+	mb := backend.NewMockBackend()
+	mb.Tasks["list-1"] = []backend.Task{
+		{UID: "t1", Summary: "Buy milk", Status: "NEEDS-ACTION"},
+		{UID: "t2", Summary: "Buy bread", Status: "NEEDS-ACTION"},
+	}
+	typeInput(t, "") // A prompt would fail on end of input
+
+	cmd := newActionCmd()
+	cmd.Flags().BoolP("force", "f", false, "")
+	_ = cmd.Flags().Set("force", "true")
+	if err := HandleDeleteAction(cmd, mb, &config.Config{}, list, "Buy milk", nil); err != nil {
+		t.Fatalf("delete --force error = %v", err)
+	}
+	if tasks := mb.Tasks["list-1"]; len(tasks) != 1 || tasks[0].UID != "t2" {
+		t.Errorf("tasks after delete = %+v, want only t2", tasks)
+	}
+}
+
+// forceDeletingBackend records whether deletes skipped the backend's checks
+type forceDeletingBackend struct {
+	*backend.MockBackend
+	forced []string
+}
+
+func (f *forceDeletingBackend) ForceDeleteTask(listID string, taskUID string) error {
+	f.forced = append(f.forced, taskUID)
+	return f.MockBackend.DeleteTask(listID, taskUID)
+}
+
+// TestDeleteForceSkipsBackendChecks tests that --force deletes through
+// ForceDeleteTask, and only --force does
+func TestDeleteForceSkipsBackendChecks(t *testing.T) {
+	config.SetConfigForTest(&config.Config{})
+	list := &backend.TaskList{ID: "list-1", Name: "Chores"}
+	mb := backend.NewMockBackend()
+	mb.Tasks["list-1"] = []backend.Task{
+		{UID: "t1", Summary: "Buy milk", Status: "NEEDS-ACTION"},
+		{UID: "t2", Summary: "Buy bread", Status: "NEEDS-ACTION"},
+	}
+	tm := &forceDeletingBackend{MockBackend: mb}
+
+	cmd := newActionCmd()
+	cmd.Flags().BoolP("force", "f", false, "")
+	_ = cmd.Flags().Set("force", "true")
+	typeInput(t, "")
+	if err := HandleDeleteAction(cmd, tm, &config.Config{}, list, "Buy milk", nil); err != nil {
+		t.Fatalf("delete --force error = %v", err)
+	}
+	if len(tm.forced) != 1 || tm.forced[0] != "t1" {
+		t.Errorf("ForceDeleteTask calls = %v, want t1", tm.forced)
+	}
+
+	cmd = newActionCmd()
+	cmd.Flags().BoolP("force", "f", false, "")
+	typeInput(t, "y\n")
+	if err := HandleDeleteAction(cmd, tm, &config.Config{}, list, "Buy bread", nil); err != nil {
+		t.Fatalf("delete error = %v", err)
+	}
+	if len(tm.forced) != 1 || len(mb.Tasks["list-1"]) != 0 {
+		t.Errorf("delete without --force: ForceDeleteTask calls %v, tasks left %+v", tm.forced, mb.Tasks["list-1"])
+	}
+}
+
+// renamedBackend remembers former summaries of its tasks
+type renamedBackend struct {
+	*backend.MockBackend
+	history map[string][]backend.SummaryAlias
+}
+
+func (r *renamedBackend) GetSummaryHistory(taskUID string) ([]backend.SummaryAlias, error) {
+	return r.history[taskUID], nil
+}
+
+// TestFormatSummaryHistory tests that task details list the former
+// summaries of backends that remember them
+func TestFormatSummaryHistory(t *testing.T) {
+	renamed := time.Date(2026, 3, 2, 9, 30, 0, 0, time.Local)
+	tm := &renamedBackend{
+		MockBackend: backend.NewMockBackend(),
+		history: map[string][]backend.SummaryAlias{
+			"t1": {{Summary: "Buy milk", ChangedAt: renamed}, {Summary: "Milk", ChangedAt: renamed.Add(-time.Hour)}},
+		},
+	}
+
+	want := "  Formerly:\n    'Buy milk' (renamed 2026-03-02 09:30)\n    'Milk' (renamed 2026-03-02 08:30)\n"
+	if got := formatSummaryHistory(tm, "t1", "2006-01-02 15:04"); got != want {
+		t.Errorf("formatSummaryHistory() = %q, want %q", got, want)
+	}
+	if got := formatSummaryHistory(tm, "t2", "2006-01-02 15:04"); got != "" {
+		t.Errorf("formatSummaryHistory() of a task never renamed = %q, want nothing", got)
+	}
+	if got := formatSummaryHistory(tm.MockBackend, "t1", "2006-01-02 15:04"); got != "" {
+		t.Errorf("formatSummaryHistory() without history = %q, want nothing", got)
+	}
+}
@@ -0,0 +1,109 @@
+package operations
+
+import (
+	"fmt"
+	"gosynctasks/backend"
+	"strings"
+)
+
+// taskTrash returns the trash of a backend, or an error naming the backend
+// when it does not keep deleted tasks
+func taskTrash(taskManager backend.TaskManager) (backend.TaskTrash, error) {
+	trash, ok := backend.As[backend.TaskTrash](taskManager)
+	if !ok {
+		return nil, fmt.Errorf("the %s backend does not keep deleted tasks", taskManager.GetBackendType())
+	}
+	return trash, nil
+}
+
+// HandleTrashAction lists the deleted tasks of a list that can be restored
+func HandleTrashAction(taskManager backend.TaskManager, selectedList *backend.TaskList, dateFormat string) error {
+	trash, err := taskTrash(taskManager)
+	if err != nil {
+		return err
+	}
+
+	deleted, err := trash.GetDeletedTasks(selectedList.ID)
+	if err != nil {
+		return fmt.Errorf("error retrieving deleted tasks: %w", err)
+	}
+	if len(deleted) == 0 {
+		fmt.Printf("No deleted tasks in '%s'.\n", selectedList.Name)
+		return nil
+	}
+
+	fmt.Printf("\nDeleted tasks in '%s' (restore with: %s restore \"summary\"):\n", selectedList.Name, selectedList.Name)
+	for _, task := range deleted {
+		if task.DeletedAt.IsZero() {
+			fmt.Printf("  • %s\n", task.Summary)
+		} else {
+			fmt.Printf("  • %s (deleted: %s)\n", task.Summary, task.DeletedAt.Local().Format(dateFormat))
+		}
+	}
+	fmt.Println()
+	return nil
+}
+
+// HandleRestoreAction restores the deleted task matching searchSummary
+func HandleRestoreAction(taskManager backend.TaskManager, selectedList *backend.TaskList, searchSummary string, syncProvider SyncCoordinatorProvider) error {
+	if searchSummary == "" {
+		return fmt.Errorf("task summary required for restore (see '%s trash')", selectedList.Name)
+	}
+
+	trash, err := taskTrash(taskManager)
+	if err != nil {
+		return err
+	}
+
+	deleted, err := trash.GetDeletedTasks(selectedList.ID)
+	if err != nil {
+		return fmt.Errorf("error retrieving deleted tasks: %w", err)
+	}
+	task, err := findDeletedTask(deleted, searchSummary)
+	if err != nil {
+		return err
+	}
+
+	if err := trash.RestoreTask(selectedList.ID, task.UID); err != nil {
+		return fmt.Errorf("error restoring task: %w", err)
+	}
+
+	fmt.Printf("Task '%s' restored to list '%s'\n", task.Summary, selectedList.Name)
+
+	// Trigger background push sync
+	triggerPushSync(syncProvider, selectedList.ID)
+
+	return nil
+}
+
+// findDeletedTask picks the deleted task whose summary matches, preferring a
+// case-insensitive exact match over partial ones
+func findDeletedTask(deleted []backend.DeletedTask, searchSummary string) (*backend.DeletedTask, error) {
+	search := strings.ToLower(searchSummary)
+	var exact, partial []*backend.DeletedTask
+	for i := range deleted {
+		summary := strings.ToLower(deleted[i].Summary)
+		if summary == search {
+			exact = append(exact, &deleted[i])
+		} else if strings.Contains(summary, search) {
+			partial = append(partial, &deleted[i])
+		}
+	}
+
+	matches := exact
+	if len(matches) == 0 {
+		matches = partial
+	}
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("no deleted task found matching '%s'", searchSummary)
+	case 1:
+		return matches[0], nil
+	}
+
+	var names strings.Builder
+	for _, match := range matches {
+		names.WriteString("\n  • " + match.Summary)
+	}
+	return nil, fmt.Errorf("%d deleted tasks match '%s', give a more specific summary:%s", len(matches), searchSummary, names.String())
+}
@@ -0,0 +1,103 @@
+package operations
+
+import (
+	"gosynctasks/backend"
+	"gosynctasks/internal/cli"
+	"gosynctasks/internal/config"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+// trashMock keeps deleted tasks restorable
+type trashMock struct {
+	*backend.MockBackend
+	deleted map[string][]backend.DeletedTask
+}
+
+func (m *trashMock) GetDeletedTasks(listID string) ([]backend.DeletedTask, error) {
+	return m.deleted[listID], nil
+}
+
+func (m *trashMock) RestoreTask(listID string, taskUID string) error {
+	for i, task := range m.deleted[listID] {
+		if task.UID == taskUID {
+			m.deleted[listID] = slices.Delete(m.deleted[listID], i, i+1)
+			m.Tasks[listID] = append(m.Tasks[listID], task.Task)
+			return nil
+		}
+	}
+	return backend.NewBackendError("RestoreTask", 404, "task not found in trash")
+}
+
+func newTrashMock() *trashMock {
+	return &trashMock{
+		MockBackend: backend.NewMockBackend(),
+		deleted: map[string][]backend.DeletedTask{"list-1": {
+			{Task: backend.Task{UID: "1", Summary: "Buy milk"}},
+			{Task: backend.Task{UID: "2", Summary: "Buy oat milk"}},
+			{Task: backend.Task{UID: "3", Summary: "Call Bob"}},
+		}},
+	}
+}
+
+func TestRestoreAction(t *testing.T) {
+	config.SetConfigForTest(&config.Config{})
+	lists := []backend.TaskList{{ID: "list-1", Name: "Chores"}}
+
+	tests := []struct {
+		name    string
+		search  string
+		wantUID string
+		wantErr string
+	}{
+		{name: "exact match wins over partial ones", search: "buy milk", wantUID: "1"},
+		{name: "unique partial match", search: "bob", wantUID: "3"},
+		{name: "ambiguous partial match", search: "milk", wantErr: "2 deleted tasks match"},
+		{name: "no match", search: "dentist", wantErr: "no deleted task found"},
+		{name: "summary required", search: "", wantErr: "task summary required"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tm := newTrashMock()
+			args := []string{"Chores", "restore"}
+			if tt.search != "" {
+				args = append(args, tt.search)
+			}
+			err := ExecuteAction(tm, &config.Config{}, lists, newActionCmd(), args, nil)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("restore %q error = %v, want %q", tt.search, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("restore %q error = %v", tt.search, err)
+			}
+			if restored := tm.Tasks["list-1"]; len(restored) != 1 || restored[0].UID != tt.wantUID {
+				t.Errorf("restored tasks = %+v, want task %s", restored, tt.wantUID)
+			}
+		})
+	}
+}
+
+func TestTrashActionUnsupportedBackend(t *testing.T) {
+	config.SetConfigForTest(&config.Config{})
+	lists := []backend.TaskList{{ID: "list-1", Name: "Chores"}}
+
+	err := ExecuteAction(backend.NewMockBackend(), &config.Config{}, lists, newActionCmd(), []string{"Chores", "trash"}, nil)
+	if err == nil || !strings.Contains(err.Error(), "does not keep deleted tasks") {
+		t.Errorf("trash on a backend without trash error = %v", err)
+	}
+}
+
+func TestRestoreCompletion(t *testing.T) {
+	lists := []backend.TaskList{{ID: "list-1", Name: "Chores"}}
+	candidates, _ := cli.SmartCompletion(lists, newTrashMock())(&cobra.Command{}, []string{"Chores", "restore"}, "buy")
+	if want := []string{"Buy milk", "Buy oat milk"}; !slices.Equal(candidates, want) {
+		t.Errorf("restore completion = %q, want %q", candidates, want)
+	}
+}
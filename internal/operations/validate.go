@@ -0,0 +1,129 @@
+package operations
+
+import (
+	"fmt"
+	"gosynctasks/backend"
+	"gosynctasks/internal/config"
+	"gosynctasks/internal/utils"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// TaskProblem is one value of a task that a backend would reject
+type TaskProblem struct {
+	Field  string
+	Value  string // The offending value, quoted or shortened for display
+	Reason string
+}
+
+// TaskValidationError lists every problem found in a task before a write
+type TaskValidationError struct {
+	Problems []TaskProblem
+}
+
+func (e *TaskValidationError) Error() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "the task was not saved, %d invalid values:", len(e.Problems))
+	for _, p := range e.Problems {
+		fmt.Fprintf(&sb, "\n  - %s %s: %s", p.Field, p.Value, p.Reason)
+	}
+	return sb.String()
+}
+
+// ValidateTask checks task before it is added or updated through
+// taskManager. It returns a *TaskValidationError listing every problem, or
+// nil. A start date after the due date only warns under start_after_due: warn.
+func ValidateTask(taskManager backend.TaskManager, cfg *config.Config, task backend.Task) error {
+	maxSummary := 0
+	if limiter, ok := backend.As[backend.SummaryLimiter](taskManager); ok {
+		maxSummary = limiter.MaxSummaryLength()
+	}
+
+	var problems []TaskProblem
+	add := func(problem *TaskProblem) {
+		if problem != nil {
+			problems = append(problems, *problem)
+		}
+	}
+	add(checkSummary(task.Summary, maxSummary))
+	add(checkPriority(task.Priority))
+	add(checkStatus(taskManager, task.Status))
+	problems = append(problems, checkCategories(task.Categories)...)
+
+	if problem := checkDates(task.StartDate, task.DueDate); problem != nil {
+		if cfg != nil && cfg.StartAfterDue == "warn" {
+			utils.Warnf("%s %s: %s", problem.Field, problem.Value, problem.Reason)
+		} else {
+			problems = append(problems, *problem)
+		}
+	}
+
+	if len(problems) == 0 {
+		return nil
+	}
+	return &TaskValidationError{Problems: problems}
+}
+
+// checkSummary requires a summary with something besides spaces, of at most
+// max characters when max is not 0
+func checkSummary(summary string, max int) *TaskProblem {
+	if strings.TrimSpace(summary) == "" {
+		return &TaskProblem{Field: "summary", Value: fmt.Sprintf("%q", summary), Reason: "must not be empty"}
+	}
+	if length := utf8.RuneCountInString(summary); max > 0 && length > max {
+		return &TaskProblem{
+			Field:  "summary",
+			Value:  fmt.Sprintf("%q (%d characters)", utils.TruncateWidth(utils.SanitizeLine(summary), 30, "..."), length),
+			Reason: fmt.Sprintf("the backend accepts at most %d characters", max),
+		}
+	}
+	return nil
+}
+
+// checkPriority requires a priority from 0 (none) to 9
+func checkPriority(priority int) *TaskProblem {
+	if priority < 0 || priority > 9 {
+		return &TaskProblem{Field: "priority", Value: fmt.Sprint(priority), Reason: "must be between 0 (none) and 9"}
+	}
+	return nil
+}
+
+// checkDates requires the start date, when both are set, not to be after the due date
+func checkDates(start, due *time.Time) *TaskProblem {
+	if start == nil || due == nil || !start.After(*due) {
+		return nil
+	}
+	return &TaskProblem{
+		Field:  "start date",
+		Value:  start.Format("2006-01-02"),
+		Reason: fmt.Sprintf("is after the due date %s", due.Format("2006-01-02")),
+	}
+}
+
+// checkStatus requires a status the backend stores, as its ParseStatusFlag
+// returns them; an empty status lets the backend use its default
+func checkStatus(taskManager backend.TaskManager, status string) *TaskProblem {
+	if status == "" {
+		return nil
+	}
+	if parsed, err := taskManager.ParseStatusFlag(status); err == nil && strings.EqualFold(parsed, status) {
+		return nil
+	}
+	return &TaskProblem{Field: "status", Value: fmt.Sprintf("%q", status), Reason: "is not a status of this backend (TODO, DONE, PROCESSING, CANCELLED)"}
+}
+
+// checkCategories requires tags that are not empty and have no comma, which
+// separates tags where they are stored
+func checkCategories(categories []string) []TaskProblem {
+	var problems []TaskProblem
+	for _, tag := range categories {
+		switch {
+		case strings.TrimSpace(tag) == "":
+			problems = append(problems, TaskProblem{Field: "tag", Value: fmt.Sprintf("%q", tag), Reason: "must not be empty"})
+		case strings.Contains(tag, ","):
+			problems = append(problems, TaskProblem{Field: "tag", Value: fmt.Sprintf("%q", tag), Reason: "must not contain a comma, which separates tags"})
+		}
+	}
+	return problems
+}
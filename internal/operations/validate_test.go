@@ -0,0 +1,134 @@
+package operations
+
+import (
+	"errors"
+	"gosynctasks/backend"
+	"gosynctasks/internal/config"
+	"strings"
+	"testing"
+	"time"
+)
+
+// limitedBackend advertises a summary length limit, as Todoist and Deck do
+type limitedBackend struct {
+	*backend.MockBackend
+}
+
+func (limitedBackend) MaxSummaryLength() int { return 10 }
+
+func TestCheckSummary(t *testing.T) {
+	if p := checkSummary("Buy milk", 10); p != nil {
+		t.Errorf("checkSummary(Buy milk) = %+v, want nil", p)
+	}
+	if p := checkSummary("Füße waschen", 0); p != nil {
+		t.Errorf("checkSummary() without a limit = %+v, want nil", p)
+	}
+	if p := checkSummary("Fußbälle", 8); p != nil {
+		t.Errorf("checkSummary() of 8 characters = %+v, want nil: characters are counted, not bytes", p)
+	}
+	if p := checkSummary(" \t", 0); p == nil || p.Reason != "must not be empty" {
+		t.Errorf("checkSummary(blank) = %+v, want empty", p)
+	}
+	if p := checkSummary(strings.Repeat("x", 5000), 500); p == nil || !strings.Contains(p.Value, "5000 characters") || !strings.Contains(p.Reason, "500") {
+		t.Errorf("checkSummary(5000 characters) = %+v, want the length and the limit", p)
+	}
+}
+
+func TestCheckPriority(t *testing.T) {
+	for _, priority := range []int{0, 1, 9} {
+		if p := checkPriority(priority); p != nil {
+			t.Errorf("checkPriority(%d) = %+v, want nil", priority, p)
+		}
+	}
+	for _, priority := range []int{-1, 10, 12} {
+		if p := checkPriority(priority); p == nil {
+			t.Errorf("checkPriority(%d) = nil, want a problem", priority)
+		}
+	}
+}
+
+func TestCheckDates(t *testing.T) {
+	day := time.Date(2026, 5, 10, 0, 0, 0, 0, time.Local)
+	before := day.AddDate(0, 0, -1)
+	if p := checkDates(&before, &day); p != nil {
+		t.Errorf("checkDates(start before due) = %+v, want nil", p)
+	}
+	if p := checkDates(&day, &day); p != nil {
+		t.Errorf("checkDates(same day) = %+v, want nil", p)
+	}
+	if p := checkDates(nil, &day); p != nil {
+		t.Errorf("checkDates(no start) = %+v, want nil", p)
+	}
+	if p := checkDates(&day, &before); p == nil || p.Value != "2026-05-10" || !strings.Contains(p.Reason, "2026-05-09") {
+		t.Errorf("checkDates(start after due) = %+v, want both dates", p)
+	}
+}
+
+func TestCheckStatus(t *testing.T) {
+	mb := backend.NewMockBackend()
+	for _, status := range []string{"", "NEEDS-ACTION", "COMPLETED", "CANCELLED"} {
+		if p := checkStatus(mb, status); p != nil {
+			t.Errorf("checkStatus(%q) = %+v, want nil", status, p)
+		}
+	}
+	// DONE is user input; the CalDAV backend stores COMPLETED
+	for _, status := range []string{"DONE", "WAITING"} {
+		if p := checkStatus(mb, status); p == nil {
+			t.Errorf("checkStatus(%q) = nil, want a problem", status)
+		}
+	}
+}
+
+func TestCheckCategories(t *testing.T) {
+	if problems := checkCategories([]string{"work", "q1 review"}); len(problems) != 0 {
+		t.Errorf("checkCategories(valid) = %+v, want none", problems)
+	}
+	problems := checkCategories([]string{"work", "a,b", " "})
+	if len(problems) != 2 || problems[0].Value != `"a,b"` || problems[1].Reason != "must not be empty" {
+		t.Errorf("checkCategories() = %+v, want the comma and the empty tag", problems)
+	}
+}
+
+// TestValidateTaskAggregated tests that every problem of a task is reported
+// in one error, and that nothing is written
+func TestValidateTaskAggregated(t *testing.T) {
+	config.SetConfigForTest(&config.Config{})
+	mb := backend.NewMockBackend()
+	tm := limitedBackend{mb}
+	list := &backend.TaskList{ID: "list-1", Name: "Chores"}
+
+	cmd := newActionCmd()
+	_ = cmd.Flags().Set("priority", "12")
+	_ = cmd.Flags().Set("start-date", "2026-05-10")
+	_ = cmd.Flags().Set("due", "2026-05-01")
+	_ = cmd.Flags().Set("tag", "a,b") // Two tags, split by --tag
+	err := HandleAddAction(cmd, tm, list, "A summary too long", nil)
+
+	var validationErr *TaskValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("add error = %v, want a TaskValidationError", err)
+	}
+	fields := make([]string, len(validationErr.Problems))
+	for i, p := range validationErr.Problems {
+		fields[i] = p.Field
+	}
+	if got := strings.Join(fields, ","); got != "summary,priority,start date" {
+		t.Errorf("problems = %s, want summary,priority,start date", got)
+	}
+	for _, want := range []string{"3 invalid values", `"A summary too long"`, "18 characters", "priority 12", "start date 2026-05-10: is after the due date 2026-05-01"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q does not contain %q", err, want)
+		}
+	}
+	if len(mb.Tasks["list-1"]) != 0 {
+		t.Errorf("tasks = %d, want none written", len(mb.Tasks["list-1"]))
+	}
+
+	// Under start_after_due: warn the dates alone don't stop the write
+	cfg := &config.Config{StartAfterDue: "warn"}
+	start := time.Date(2026, 5, 10, 0, 0, 0, 0, time.Local)
+	due := start.AddDate(0, 0, -9)
+	if err := ValidateTask(tm, cfg, backend.Task{Summary: "Ok", StartDate: &start, DueDate: &due}); err != nil {
+		t.Errorf("ValidateTask() under start_after_due: warn error = %v", err)
+	}
+}
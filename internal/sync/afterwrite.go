@@ -0,0 +1,231 @@
+package sync
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+)
+
+// DebounceWindow is how long the after-write sync waits for writes to stop
+// before running, so a burst of commands is pushed in one run
+var DebounceWindow = 2 * time.Second
+
+// runnerStale is how long a runner can go without updating the status file
+// before it is assumed dead and a new one is started
+const runnerStale = 2 * time.Minute
+
+// lockStale is how old a status lock can get before it is assumed abandoned
+const lockStale = 10 * time.Second
+
+// RunOutcome is the result of an after-write sync run
+type RunOutcome struct {
+	Started  time.Time `json:"started"`
+	Finished time.Time `json:"finished"`
+	Mode     string    `json:"mode"`
+	Lists    []string  `json:"lists,omitempty"` // Empty when every list was synced
+	Pushed   int       `json:"pushed"`
+	Errors   []string  `json:"errors,omitempty"`
+	Shown    bool      `json:"shown,omitempty"` // The errors were shown to the user
+}
+
+// Status is the sync status file: the writes waiting for the after-write
+// sync, the process running it, and the outcome of its last run
+type Status struct {
+	Pending     []string    `json:"pending,omitempty"` // List IDs; "" stands for every list
+	Mode        string      `json:"mode,omitempty"`
+	RequestedAt time.Time   `json:"requested_at,omitempty"`
+	RunnerPID   int         `json:"runner_pid,omitempty"`
+	RunnerSeen  time.Time   `json:"runner_seen,omitempty"`
+	LastRun     *RunOutcome `json:"last_run,omitempty"`
+}
+
+// StatusPath returns the sync status file in the XDG state directory
+func StatusPath() (string, error) {
+	stateDir := os.Getenv("XDG_STATE_HOME")
+	if stateDir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		stateDir = filepath.Join(home, ".local", "state")
+	}
+	return filepath.Join(stateDir, "gosynctasks", "sync-status.json"), nil
+}
+
+// LoadStatus reads the sync status file at path; a missing file is an empty
+// status
+func LoadStatus(path string) (*Status, error) {
+	status := &Status{}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return status, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read sync status: %w", err)
+	}
+	if err := json.Unmarshal(data, status); err != nil {
+		return nil, fmt.Errorf("failed to parse sync status %s: %w", path, err)
+	}
+	return status, nil
+}
+
+// save writes the status file, replacing it atomically
+func (s *Status) save(path string) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("failed to write sync status: %w", err)
+	}
+	return os.Rename(tmp, path)
+}
+
+// UpdateStatus changes the status file at path through update, holding a
+// lock so commands and the runner don't overwrite each other's changes
+func UpdateStatus(path string, update func(*Status)) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create state directory: %w", err)
+	}
+	unlock, err := lockStatus(path)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	status, err := LoadStatus(path)
+	if err != nil {
+		// A corrupt file only loses the pending writes, which stay queued
+		status = &Status{}
+	}
+	update(status)
+	return status.save(path)
+}
+
+// lockStatus creates the lock file of the status file, waiting for other
+// holders and removing a lock left behind by a process that died
+func lockStatus(path string) (func(), error) {
+	lockPath := path + ".lock"
+	deadline := time.Now().Add(2 * lockStale)
+	for {
+		f, err := os.OpenFile(lockPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+		if err == nil {
+			_ = f.Close()
+			return func() { _ = os.Remove(lockPath) }, nil
+		}
+		if !errors.Is(err, os.ErrExist) {
+			return nil, fmt.Errorf("failed to lock sync status: %w", err)
+		}
+		if info, statErr := os.Stat(lockPath); statErr == nil && time.Since(info.ModTime()) > lockStale {
+			_ = os.Remove(lockPath)
+			continue
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("failed to lock sync status: %s is held", lockPath)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// RequestAfterWrite records a write to the given lists, an empty list ID
+// standing for every list, for the after-write sync with mode. It returns
+// whether the caller must start a runner; while one is running, it picks the
+// write up in its next run.
+func RequestAfterWrite(path string, listIDs []string, mode string) (bool, error) {
+	if len(listIDs) == 0 {
+		listIDs = []string{""}
+	}
+	spawn := false
+	err := UpdateStatus(path, func(status *Status) {
+		now := time.Now()
+		for _, listID := range listIDs {
+			if !slices.Contains(status.Pending, listID) {
+				status.Pending = append(status.Pending, listID)
+			}
+		}
+		status.Mode = mode
+		status.RequestedAt = now
+		if status.RunnerPID == 0 || now.Sub(status.RunnerSeen) > runnerStale {
+			// Claimed until the runner records its own PID
+			status.RunnerPID = os.Getpid()
+			status.RunnerSeen = now
+			spawn = true
+		}
+	})
+	return spawn, err
+}
+
+// RunAfterWrite runs the after-write sync until no write is pending. Each
+// run waits until no write was requested for window, then passes the pending
+// mode and lists to run (nil when every list must be synced) and records its
+// outcome as the last run.
+func RunAfterWrite(path string, window time.Duration, run func(mode string, lists []string) RunOutcome) error {
+	for {
+		var mode string
+		var lists []string
+		var wait time.Duration
+		done := false
+
+		err := UpdateStatus(path, func(status *Status) {
+			now := time.Now()
+			if len(status.Pending) == 0 {
+				status.RunnerPID = 0
+				status.RunnerSeen = time.Time{}
+				done = true
+				return
+			}
+			status.RunnerPID = os.Getpid()
+			status.RunnerSeen = now
+			if wait = status.RequestedAt.Add(window).Sub(now); wait > 0 {
+				return
+			}
+			mode = status.Mode
+			if !slices.Contains(status.Pending, "") {
+				lists = status.Pending
+			}
+			status.Pending = nil
+		})
+		if err != nil {
+			return err
+		}
+		if done {
+			return nil
+		}
+		if wait > 0 {
+			time.Sleep(wait)
+			continue
+		}
+
+		outcome := run(mode, lists)
+		if err := UpdateStatus(path, func(status *Status) {
+			status.LastRun = &outcome
+			status.RunnerSeen = time.Now()
+		}); err != nil {
+			return err
+		}
+	}
+}
+
+// TakeFailedRun returns the last after-write run if it failed and was not
+// shown yet, marking it shown
+func TakeFailedRun(path string) (*RunOutcome, error) {
+	status, err := LoadStatus(path)
+	if err != nil || status.LastRun == nil || status.LastRun.Shown || len(status.LastRun.Errors) == 0 {
+		return nil, err
+	}
+
+	var failed *RunOutcome
+	err = UpdateStatus(path, func(status *Status) {
+		if run := status.LastRun; run != nil && !run.Shown && len(run.Errors) > 0 {
+			run.Shown = true
+			shown := *run
+			failed = &shown
+		}
+	})
+	return failed, err
+}
@@ -0,0 +1,102 @@
+package sync
+
+import (
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+// TestRunAfterWriteDebounces tests that rapid writes start one runner, which
+// syncs them in a single run once they stop
+func TestRunAfterWriteDebounces(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sync-status.json")
+
+	var spawned int
+	for _, listID := range []string{"work", "home", "work"} {
+		spawn, err := RequestAfterWrite(path, []string{listID}, "push_list")
+		if err != nil {
+			t.Fatalf("RequestAfterWrite() error = %v", err)
+		}
+		if spawn {
+			spawned++
+		}
+	}
+	if spawned != 1 {
+		t.Fatalf("runners started = %d, want 1", spawned)
+	}
+
+	var runs [][]string
+	err := RunAfterWrite(path, 50*time.Millisecond, func(mode string, lists []string) RunOutcome {
+		if mode != "push_list" {
+			t.Errorf("mode = %q, want push_list", mode)
+		}
+		runs = append(runs, lists)
+		return RunOutcome{Mode: mode, Lists: lists, Pushed: 2}
+	})
+	if err != nil {
+		t.Fatalf("RunAfterWrite() error = %v", err)
+	}
+	if len(runs) != 1 || !slices.Equal(runs[0], []string{"work", "home"}) {
+		t.Fatalf("runs = %v, want one run of [work home]", runs)
+	}
+
+	status, err := LoadStatus(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.RunnerPID != 0 || len(status.Pending) != 0 {
+		t.Errorf("status after the run = %+v, want no runner or pending write", status)
+	}
+	if status.LastRun == nil || status.LastRun.Pushed != 2 {
+		t.Errorf("LastRun = %+v, want the run's outcome", status.LastRun)
+	}
+
+	// The next write starts a new runner
+	if spawn, _ := RequestAfterWrite(path, nil, "push_all"); !spawn {
+		t.Error("write after the runner exited did not start one")
+	}
+}
+
+// TestRunAfterWriteAllLists tests that a write to an unknown list syncs every list
+func TestRunAfterWriteAllLists(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sync-status.json")
+	for _, listIDs := range [][]string{{"work"}, nil} {
+		if _, err := RequestAfterWrite(path, listIDs, "push_list"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	runs := 0
+	err := RunAfterWrite(path, 0, func(mode string, lists []string) RunOutcome {
+		runs++
+		if lists != nil {
+			t.Errorf("lists = %v, want nil for every list", lists)
+		}
+		return RunOutcome{}
+	})
+	if err != nil || runs != 1 {
+		t.Errorf("RunAfterWrite() = %v with %d runs, want one run", err, runs)
+	}
+}
+
+// TestTakeFailedRun tests that a failed run is reported once
+func TestTakeFailedRun(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sync-status.json")
+	if run, err := TakeFailedRun(path); run != nil || err != nil {
+		t.Fatalf("TakeFailedRun() without status = %+v, %v", run, err)
+	}
+
+	if err := UpdateStatus(path, func(status *Status) {
+		status.LastRun = &RunOutcome{Errors: []string{"Timeout syncing cloud"}}
+	}); err != nil {
+		t.Fatal(err)
+	}
+	run, err := TakeFailedRun(path)
+	if err != nil || run == nil || run.Errors[0] != "Timeout syncing cloud" {
+		t.Fatalf("TakeFailedRun() = %+v, %v, want the failed run", run, err)
+	}
+	if run, _ := TakeFailedRun(path); run != nil {
+		t.Errorf("failed run reported twice: %+v", run)
+	}
+}
@@ -1,6 +1,7 @@
 package sync
 
 import (
+	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -9,6 +10,7 @@ import (
 	"gosynctasks/backend"
 	"gosynctasks/backend/sqlite"
 	backendsync "gosynctasks/backend/sync"
+	"gosynctasks/internal/autostart"
 	"gosynctasks/internal/config"
 	"gosynctasks/internal/utils"
 )
@@ -47,8 +49,10 @@ func SpawnBackgroundSync() error {
 	return nil
 }
 
-// RunBackgroundSyncInProcess runs background sync in the current process
-// This is used in test mode instead of spawning a separate process
+// RunBackgroundSyncInProcess runs the after-write sync in the current
+// process until no write is pending, recording each run's outcome in the sync
+// status file. It is what _internal_background_sync runs, and is called
+// directly in test mode instead of spawning a separate process.
 func RunBackgroundSyncInProcess() error {
 	// Set up background logger
 	bgLogger, err := utils.NewBackgroundLogger()
@@ -60,121 +64,200 @@ func RunBackgroundSyncInProcess() error {
 		bgLogger.Printf("Started in-process sync at %s (PID: %d)", time.Now().Format(time.RFC3339), os.Getpid())
 	}
 
-	// Load config
+	path, err := StatusPath()
+	if err != nil {
+		return err
+	}
 	cfg := config.GetConfig()
+	return RunAfterWrite(path, DebounceWindow, func(mode string, lists []string) RunOutcome {
+		return runAfterWriteSync(cfg, mode, lists, bgLogger)
+	})
+}
+
+// runAfterWriteSync syncs every sync pair as mode asks: the pending changes
+// of lists (all lists when nil), every pending change, or a full sync
+func runAfterWriteSync(cfg *config.Config, mode string, lists []string, bgLogger *utils.BackgroundLogger) RunOutcome {
+	outcome := RunOutcome{Started: time.Now(), Mode: mode, Lists: lists}
+	defer func() { outcome.Finished = time.Now() }()
 
-	// Check if sync is enabled
-	if cfg.Sync == nil || !cfg.Sync.Enabled || !cfg.Sync.AutoSync {
+	logf := func(format string, args ...interface{}) {
 		if bgLogger != nil {
-			bgLogger.Printf("Sync or AutoSync not enabled")
+			bgLogger.Printf(format, args...)
 		}
-		return nil
 	}
-
-	// Get all sync pairs
-	syncPairs := cfg.GetSyncPairs()
-	if bgLogger != nil {
-		bgLogger.Printf("Found %d sync pairs", len(syncPairs))
+	fail := func(format string, args ...interface{}) {
+		logf(format, args...)
+		outcome.Errors = append(outcome.Errors, fmt.Sprintf(format, args...))
 	}
-	if len(syncPairs) == 0 {
-		return nil
+
+	if mode == config.AfterWriteOff || cfg.Sync == nil {
+		logf("Sync after writes not enabled")
+		return outcome
 	}
 
-	// Sync all backends with pending operations
-	for _, pair := range syncPairs {
-		if bgLogger != nil {
-			bgLogger.Printf("Checking backend: %s", pair.RemoteBackendName)
-		}
+	syncPairs := cfg.GetSyncPairs()
+	logf("Found %d sync pairs, mode %s, lists %v", len(syncPairs), mode, lists)
 
-		// Get backends
-		cacheBackend, remoteBackend, err := getBackendsForSync(cfg, pair.RemoteBackendName)
+	for _, pair := range syncPairs {
+		name := pair.RemoteBackendName
+		cacheBackend, remoteBackend, err := getBackendsForSync(cfg, name)
 		if err != nil {
-			if bgLogger != nil {
-				bgLogger.Printf("Failed to get sync backends for %s: %v", pair.RemoteBackendName, err)
-			}
+			fail("Failed to get sync backends for %s: %v", name, err)
 			continue
 		}
 
-		// Check for pending operations
-		ops, err := cacheBackend.GetPendingSyncOperations()
+		// Start tasks whose start date passed, so their updates are pushed below
+		started, err := startDueTasks(cfg, cacheBackend)
 		if err != nil {
-			if bgLogger != nil {
-				bgLogger.Printf("Error getting pending ops for %s: %v", pair.RemoteBackendName, err)
-			}
-			continue
+			logf("Auto-start error for %s: %v", name, err)
 		}
-		if bgLogger != nil {
-			bgLogger.Printf("Backend %s has %d pending operations", pair.RemoteBackendName, len(ops))
+		if started > 0 {
+			logf("Auto-started %d tasks in %s", started, name)
 		}
-		if len(ops) == 0 {
-			continue
+
+		if mode != config.AfterWriteFull {
+			ops, err := cacheBackend.GetPendingSyncOperations()
+			if err != nil {
+				fail("Error getting pending ops for %s: %v", name, err)
+				continue
+			}
+			logf("Backend %s has %d pending operations", name, len(ops))
+			if len(ops) == 0 {
+				continue
+			}
 		}
 
-		// Create sync manager
 		strategy := backendsync.ConflictResolutionStrategy(cfg.Sync.ConflictResolution)
 		syncManager := backendsync.NewSyncManager(cacheBackend, remoteBackend, strategy)
+		syncManager.SetConflictFallback(backendsync.ConflictResolutionStrategy(cfg.Sync.ConflictFallback))
+		syncManager.SetMaxRetries(cfg.Sync.MaxRetries)
+		syncManager.SetParallelism(cfg.Sync.Parallelism)
 
-		// Execute sync with timeout
-		done := make(chan struct{})
+		// Execute sync with timeout; operations left over stay queued
+		type syncDone struct {
+			result *backendsync.SyncResult
+			err    error
+		}
+		done := make(chan syncDone, 1)
 		go func() {
-			result, err := syncManager.PushOnly()
-			if err != nil {
-				if bgLogger != nil {
-					bgLogger.Printf("Push error for %s: %v", pair.RemoteBackendName, err)
-				}
-			} else {
-				if bgLogger != nil {
-					bgLogger.Printf("Successfully synced %s: %d tasks pushed", pair.RemoteBackendName, result.PushedTasks)
-				}
+			var d syncDone
+			switch mode {
+			case config.AfterWriteFull:
+				d.result, d.err = syncManager.Sync()
+			case config.AfterWritePushAll:
+				d.result, d.err = syncManager.PushOnly()
+			default:
+				d.result, d.err = syncManager.PushListsOnly(lists)
 			}
-			close(done)
+			done <- d
 		}()
 
-		// Wait for sync
 		select {
-		case <-done:
-			if bgLogger != nil {
-				bgLogger.Printf("Completed sync for %s", pair.RemoteBackendName)
+		case d := <-done:
+			if d.err != nil {
+				fail("Sync error for %s: %v", name, d.err)
+				continue
 			}
-		case <-time.After(10 * time.Second):
-			if bgLogger != nil {
-				bgLogger.Printf("Timeout syncing %s", pair.RemoteBackendName)
+			outcome.Pushed += d.result.PushedTasks
+			for _, syncErr := range d.result.Errors {
+				fail("Sync error for %s: %v", name, syncErr)
+			}
+			for _, stranded := range d.result.Stranded {
+				fail("%s", stranded)
 			}
+			logf("Completed sync for %s: %d tasks pushed", name, d.result.PushedTasks)
+			if db, err := cacheBackend.GetDB(); err == nil {
+				if pruned, err := PruneAfterSync(cfg, db); err != nil {
+					logf("Pruning error for %s: %v", name, err)
+				} else if pruned > 0 {
+					logf("Pruned %d completed tasks past cache.completed_retention", pruned)
+				}
+			}
+		case <-time.After(10 * time.Second):
+			fail("Timeout syncing %s", name)
 		}
 	}
 
-	if bgLogger != nil {
-		bgLogger.Printf("Finished at %s", time.Now().Format(time.RFC3339))
+	logf("Finished at %s", time.Now().Format(time.RFC3339))
+	return outcome
+}
+
+// startDueTasks moves the cached tasks whose start date passed to PROCESSING,
+// for the lists with auto_start, queueing the updates for push
+func startDueTasks(cfg *config.Config, cache backend.TaskManager) (int, error) {
+	if !cfg.AutoStart && len(cfg.AutoStartLists) == 0 {
+		return 0, nil
 	}
-	return nil
+
+	path, err := autostart.DefaultPath()
+	if err != nil {
+		return 0, err
+	}
+	marker, err := autostart.Load(path)
+	if err != nil {
+		return 0, err
+	}
+	todo, _, err := autostart.Statuses(cache)
+	if err != nil {
+		return 0, err
+	}
+	lists, err := cache.GetTaskLists()
+	if err != nil {
+		return 0, err
+	}
+
+	total := 0
+	for _, list := range lists {
+		if !cfg.AutoStartsList(list) {
+			continue
+		}
+		var tasks []backend.Task
+		tasks, err = cache.GetTasks(list.ID, nil)
+		if err != nil {
+			break
+		}
+		var started int
+		started, err = marker.Start(cache, list.ID, marker.Due(tasks, todo, time.Now()))
+		total += started
+		if err != nil {
+			break
+		}
+	}
+
+	if total > 0 {
+		if saveErr := marker.Save(); saveErr != nil && err == nil {
+			err = saveErr
+		}
+	}
+	return total, err
 }
 
-// getBackendsForSync gets cache and remote backends for a sync pair
+// getBackendsForSync gets cache and remote backends for a sync pair. Every
+// remote shares the cache database, its rows told apart by the backend name.
 func getBackendsForSync(cfg *config.Config, remoteName string) (*sqlite.SQLiteBackend, backend.TaskManager, error) {
-	// Create registry
-	registry, err := backend.NewBackendRegistry(cfg.GetEnabledBackends())
+	cachePath, err := cfg.GetCacheDatabasePath()
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, fmt.Errorf("failed to get cache database path: %w", err)
 	}
-
-	// Get cache backend for this remote
-	cacheBackendName := "cache_" + remoteName
-	cacheBackend, err := registry.GetBackend(cacheBackendName)
+	cacheBackend, err := sqlite.NewSQLiteBackend(backend.BackendConfig{
+		Name:    remoteName,
+		Type:    "sqlite",
+		Enabled: true,
+		DBPath:  cachePath,
+	})
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, fmt.Errorf("failed to create cache backend: %w", err)
 	}
 
-	// Type assert to SQLiteBackend
-	sqliteBackend, ok := cacheBackend.(*sqlite.SQLiteBackend)
-	if !ok {
+	// Get remote backend
+	registry, err := backend.NewBackendRegistry(cfg.GetEnabledBackends())
+	if err != nil {
 		return nil, nil, err
 	}
-
-	// Get remote backend
 	remoteBackend, err := registry.GetBackend(remoteName)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	return sqliteBackend, remoteBackend, nil
+	return cacheBackend, remoteBackend, nil
 }
@@ -51,6 +51,9 @@ func NewSyncCoordinator(cfg *config.Config, local *backendsqlite.SQLiteBackend,
 	// Convert conflict resolution string to strategy type
 	strategy := backendsync.ConflictResolutionStrategy(cfg.Sync.ConflictResolution)
 	syncManager := backendsync.NewSyncManager(local, remote, strategy)
+	syncManager.SetConflictFallback(backendsync.ConflictResolutionStrategy(cfg.Sync.ConflictFallback))
+	syncManager.SetMaxRetries(cfg.Sync.MaxRetries)
+	syncManager.SetParallelism(cfg.Sync.Parallelism)
 
 	// Create logger for silent error logging
 	logger := log.New(os.Stderr, "[AutoSync] ", log.LstdFlags)
@@ -173,6 +176,12 @@ func (sc *SyncCoordinator) doPullSync(listID string, pullFlag *atomic.Bool) {
 		sc.logger.Printf("Background sync completed: %d pulled, %d pushed",
 			result.PulledTasks, result.PushedTasks)
 	}
+
+	if db, err := sc.local.GetDB(); err == nil {
+		if _, err := PruneAfterSync(sc.config, db); err != nil {
+			sc.logger.Printf("Pruning error: %v", err)
+		}
+	}
 }
 
 // IsStale checks if the data for a given list is stale based on sync_interval
@@ -0,0 +1,37 @@
+package sync
+
+import (
+	"fmt"
+	"time"
+
+	"gosynctasks/backend/sqlite"
+	"gosynctasks/internal/config"
+)
+
+// PruneCompleted removes from the cache the completed tasks closed longer
+// than cache.completed_retention ago; the remote keeps them. The cache is
+// backed up first when there is anything to prune. It returns the number of
+// tasks pruned, 0 when the retention is off.
+func PruneCompleted(cfg *config.Config, db *sqlite.Database) (int, error) {
+	retention, err := cfg.GetCompletedRetention()
+	if err != nil || retention == 0 {
+		return 0, err
+	}
+	cutoff := time.Now().Add(-retention)
+	prunable, err := db.HasPrunable(cutoff)
+	if err != nil || !prunable {
+		return 0, err
+	}
+	if _, err := db.Backup("prune", cfg.GetBackupCount()); err != nil {
+		return 0, fmt.Errorf("%w; nothing was pruned", err)
+	}
+	return db.PruneCompleted(cutoff)
+}
+
+// PruneAfterSync runs PruneCompleted when cache.prune_after_sync is set
+func PruneAfterSync(cfg *config.Config, db *sqlite.Database) (int, error) {
+	if cfg.Cache == nil || !cfg.Cache.PruneAfterSync {
+		return 0, nil
+	}
+	return PruneCompleted(cfg, db)
+}
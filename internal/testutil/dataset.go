@@ -0,0 +1,241 @@
+// Package testutil generates deterministic synthetic task data for
+// benchmarks, tests and 'gosynctasks dev seed'. The same options always
+// produce the same dataset, so results are comparable across machines.
+package testutil
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"time"
+
+	"gosynctasks/backend"
+)
+
+// ReferenceTime is the "now" datasets are generated around unless
+// DatasetOptions.Now is set
+var ReferenceTime = time.Date(2025, time.June, 15, 12, 0, 0, 0, time.UTC)
+
+// DatasetOptions controls the size and shape of a generated dataset
+type DatasetOptions struct {
+	Tasks          int
+	Lists          int
+	HierarchyDepth int       // Deepest subtask level; 0 generates flat lists
+	Seed           int64     // Seed of the random generator
+	Now            time.Time // Time dates are spread around; ReferenceTime when zero
+}
+
+// DefaultDatasetOptions returns a medium dataset with a few hierarchy levels
+func DefaultDatasetOptions() DatasetOptions {
+	return DatasetOptions{Tasks: 1000, Lists: 5, HierarchyDepth: 3, Seed: 1}
+}
+
+// Dataset is a generated set of lists and their tasks. Parents come before
+// their subtasks in each list.
+type Dataset struct {
+	Lists []backend.TaskList
+	Tasks map[string][]backend.Task // By list ID
+}
+
+// Count returns the number of tasks in the dataset
+func (d *Dataset) Count() int {
+	count := 0
+	for _, tasks := range d.Tasks {
+		count += len(tasks)
+	}
+	return count
+}
+
+// AllTasks returns the tasks of every list, in list order
+func (d *Dataset) AllTasks() []backend.Task {
+	all := make([]backend.Task, 0, d.Count())
+	for _, list := range d.Lists {
+		all = append(all, d.Tasks[list.ID]...)
+	}
+	return all
+}
+
+// MockBackend returns a mock backend serving the dataset
+func (d *Dataset) MockBackend() *backend.MockBackend {
+	mb := backend.NewMockBackend()
+	mb.Lists = append(mb.Lists, d.Lists...)
+	for listID, tasks := range d.Tasks {
+		mb.Tasks[listID] = append([]backend.Task(nil), tasks...)
+	}
+	return mb
+}
+
+// SyncedStore is a store a dataset can be written to as synced data, such as
+// a SQLite cache
+type SyncedStore interface {
+	CreateSyncedList(list backend.TaskList) error
+	InsertSyncedTask(listID string, task backend.Task) error
+}
+
+// WriteTo writes the lists and tasks of the dataset to store
+func (d *Dataset) WriteTo(store SyncedStore) error {
+	for _, list := range d.Lists {
+		if err := store.CreateSyncedList(list); err != nil {
+			return fmt.Errorf("failed to create list %s: %w", list.Name, err)
+		}
+		for _, task := range d.Tasks[list.ID] {
+			if err := store.InsertSyncedTask(list.ID, task); err != nil {
+				return fmt.Errorf("failed to insert task %s: %w", task.UID, err)
+			}
+		}
+	}
+	return nil
+}
+
+// Vocabularies the generated summaries, descriptions and categories are drawn
+// from. Summaries mix in non-ASCII words so display width handling is exercised.
+var (
+	listNames  = []string{"Work", "Home", "Errands", "Side project", "Reading", "Garden", "Travel", "Finances", "Health", "Learning"}
+	verbs      = []string{"Write", "Review", "Call", "Fix", "Plan", "Buy", "Clean", "Update", "Prepare", "Überprüfen", "Réserver", "整理する"}
+	nouns      = []string{"report", "invoice", "the garage", "release notes", "dentist", "groceries", "budget", "café order", "Straßenbahn pass", "naïve tests", "日本語 slides", "🚀 launch checklist"}
+	categories = []string{"urgent", "waiting", "errand", "phone", "computer", "home", "work", "someday", "review", "family"}
+	sentences  = []string{
+		"Check the previous version before starting.",
+		"Ask for feedback once the first draft is done.",
+		"Remember to attach the receipts.",
+		"This depends on the answer from the supplier.",
+		"Keep it short, the last one was too long.",
+		"Notes from the meeting are in the shared folder.",
+		"Prix à confirmer avec le fournisseur.",
+		"Bitte vor Freitag erledigen.",
+	}
+)
+
+// GenerateDataset returns the dataset described by opts. Of the tasks, about
+// 30% have a due date spread from two weeks before to a month after Now, 20%
+// are completed with a completion time, 10% have a start date and 15% a long
+// description; most have categories and a priority.
+func GenerateDataset(opts DatasetOptions) *Dataset {
+	if opts.Lists < 1 {
+		opts.Lists = 1
+	}
+	now := opts.Now
+	if now.IsZero() {
+		now = ReferenceTime
+	}
+	rng := rand.New(rand.NewSource(opts.Seed))
+
+	d := &Dataset{Tasks: make(map[string][]backend.Task)}
+	for i := 0; i < opts.Lists; i++ {
+		name := listNames[i%len(listNames)]
+		if i >= len(listNames) {
+			name = fmt.Sprintf("%s %d", name, i/len(listNames)+1)
+		}
+		list := backend.TaskList{ID: fmt.Sprintf("seed-list-%d", i), Name: name, CTags: fmt.Sprintf("seed-ctag-%d", i)}
+		d.Lists = append(d.Lists, list)
+	}
+
+	// Tasks that can still get subtasks, by list, with their depth
+	type parent struct {
+		uid   string
+		depth int
+	}
+	parents := make(map[string][]parent)
+
+	for i := 0; i < opts.Tasks; i++ {
+		listID := d.Lists[rng.Intn(len(d.Lists))].ID
+		task := generateTask(rng, i, now)
+
+		depth := 0
+		if candidates := parents[listID]; len(candidates) > 0 && rng.Float64() < 0.4 {
+			p := candidates[rng.Intn(len(candidates))]
+			task.ParentUID = p.uid
+			depth = p.depth + 1
+		}
+		if depth < opts.HierarchyDepth {
+			parents[listID] = append(parents[listID], parent{uid: task.UID, depth: depth})
+		}
+
+		d.Tasks[listID] = append(d.Tasks[listID], task)
+	}
+
+	return d
+}
+
+// generateTask returns the i-th task of a dataset
+func generateTask(rng *rand.Rand, i int, now time.Time) backend.Task {
+	created := now.Add(-time.Duration(rng.Intn(90*24)) * time.Hour)
+	task := backend.Task{
+		UID:      fmt.Sprintf("seed-task-%d", i),
+		Summary:  fmt.Sprintf("%s %s", verbs[rng.Intn(len(verbs))], nouns[rng.Intn(len(nouns))]),
+		Status:   "NEEDS-ACTION",
+		Priority: generatePriority(rng),
+		Created:  created,
+		Modified: created,
+	}
+	if rng.Float64() < 0.2 {
+		task.Summary += fmt.Sprintf(" #%d", i)
+	}
+
+	switch r := rng.Float64(); {
+	case r < 0.2:
+		completed := created.Add(time.Duration(rng.Int63n(int64(now.Sub(created)) + 1)))
+		task.Status = "COMPLETED"
+		task.Completed = &completed
+		task.Modified = completed
+	case r < 0.3:
+		task.Status = "IN-PROCESS"
+	case r < 0.32:
+		task.Status = "CANCELLED"
+	}
+
+	if rng.Float64() < 0.3 {
+		due := now.Add(time.Duration(rng.Intn(44*24)-14*24) * time.Hour)
+		task.DueDate = &due
+	}
+	if rng.Float64() < 0.1 {
+		start := now.Add(time.Duration(rng.Intn(21*24)-7*24) * time.Hour)
+		task.StartDate = &start
+	}
+	if rng.Float64() < 0.15 {
+		task.Description = generateDescription(rng)
+	}
+	if rng.Float64() < 0.6 {
+		for _, j := range rng.Perm(len(categories))[:1+rng.Intn(3)] {
+			task.Categories = append(task.Categories, categories[j])
+		}
+	}
+
+	return task
+}
+
+// generatePriority returns no priority for about half of the tasks and
+// favors the middle priorities for the rest
+func generatePriority(rng *rand.Rand) int {
+	if rng.Float64() < 0.5 {
+		return 0
+	}
+	weights := []int{3, 4, 6, 8, 10, 8, 5, 3, 2} // Priorities 1-9
+	total := 0
+	for _, w := range weights {
+		total += w
+	}
+	r := rng.Intn(total)
+	for i, w := range weights {
+		if r < w {
+			return i + 1
+		}
+		r -= w
+	}
+	return 5
+}
+
+// generateDescription returns a multi-line description of a few hundred to
+// about two thousand characters
+func generateDescription(rng *rand.Rand) string {
+	var sb strings.Builder
+	for n := 5 + rng.Intn(40); n > 0; n-- {
+		sb.WriteString(sentences[rng.Intn(len(sentences))])
+		if rng.Float64() < 0.2 {
+			sb.WriteString("\n")
+		} else {
+			sb.WriteString(" ")
+		}
+	}
+	return strings.TrimSpace(sb.String())
+}
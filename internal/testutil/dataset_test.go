@@ -0,0 +1,77 @@
+package testutil
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGenerateDatasetDeterministic(t *testing.T) {
+	opts := DatasetOptions{Tasks: 500, Lists: 4, HierarchyDepth: 2, Seed: 42}
+	a, b := GenerateDataset(opts), GenerateDataset(opts)
+	if !reflect.DeepEqual(a, b) {
+		t.Fatal("same options generated different datasets")
+	}
+
+	opts.Seed = 43
+	if reflect.DeepEqual(a, GenerateDataset(opts)) {
+		t.Error("different seeds generated the same dataset")
+	}
+}
+
+func TestGenerateDatasetShape(t *testing.T) {
+	d := GenerateDataset(DatasetOptions{Tasks: 5000, Lists: 10, HierarchyDepth: 3, Seed: 1})
+	if len(d.Lists) != 10 || d.Count() != 5000 {
+		t.Fatalf("got %d lists and %d tasks, want 10 and 5000", len(d.Lists), d.Count())
+	}
+
+	var due, completed, described, tagged, prioritized int
+	maxDepth := 0
+	for _, list := range d.Lists {
+		depths := make(map[string]int)
+		for _, task := range d.Tasks[list.ID] {
+			if task.ParentUID != "" {
+				parentDepth, ok := depths[task.ParentUID]
+				if !ok {
+					t.Fatalf("task %s comes before its parent or is in another list", task.UID)
+				}
+				depths[task.UID] = parentDepth + 1
+				maxDepth = max(maxDepth, parentDepth+1)
+			} else {
+				depths[task.UID] = 0
+			}
+
+			if task.DueDate != nil {
+				due++
+			}
+			if task.Status == "COMPLETED" {
+				completed++
+				if task.Completed == nil {
+					t.Errorf("completed task %s has no completion time", task.UID)
+				}
+			}
+			if len(task.Description) > 200 {
+				described++
+			}
+			if len(task.Categories) > 0 {
+				tagged++
+			}
+			if task.Priority > 0 {
+				prioritized++
+			}
+		}
+	}
+
+	if maxDepth != 3 {
+		t.Errorf("deepest subtask level = %d, want 3", maxDepth)
+	}
+	within := func(name string, got int, lo, hi float64) {
+		if share := float64(got) / 5000; share < lo || share > hi {
+			t.Errorf("%s share = %.2f, want between %.2f and %.2f", name, share, lo, hi)
+		}
+	}
+	within("due date", due, 0.25, 0.35)
+	within("completed", completed, 0.15, 0.25)
+	within("long description", described, 0.05, 0.2)
+	within("categories", tagged, 0.5, 0.7)
+	within("priority", prioritized, 0.4, 0.6)
+}
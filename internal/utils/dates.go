@@ -0,0 +1,204 @@
+package utils
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	slashDatePattern  = regexp.MustCompile(`^\d{1,2}/\d{1,2}(/\d{2,4})?$`)
+	dottedDatePattern = regexp.MustCompile(`^(\d{1,2})\.(\d{1,2})\.(\d{4})?$`)
+)
+
+// ParseLocalizedDate parses a date written in the given locale, relative to now.
+// Accepted forms, besides ISO (YYYY-MM-DD):
+//   - today, tomorrow, yesterday (English or the locale's words)
+//   - a weekday name or abbreviation: the next such day, 1-7 days ahead
+//   - day and month name in either order, with optional year: "14 Jul", "July 14 2026"
+//   - day-first dotted dates in locales that use them: "14.07.", "14.07.2026"
+//   - any of the explicit dates above prefixed by a matching weekday: "Mo 14.07."
+//
+// A date without a year is the next occurrence on or after today. Numeric dates
+// whose order depends on the reader (01/02) are rejected.
+func ParseLocalizedDate(input string, locale *Locale, now time.Time) (*time.Time, error) {
+	input = strings.TrimSpace(input)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+
+	if parsed, err := time.ParseInLocation("2006-01-02", input, time.Local); err == nil {
+		return &parsed, nil
+	}
+
+	lower := strings.ToLower(input)
+	switch lower {
+	case "today", strings.ToLower(locale.Today):
+		return &today, nil
+	case "tomorrow", strings.ToLower(locale.Tomorrow):
+		date := today.AddDate(0, 0, 1)
+		return &date, nil
+	case "yesterday", strings.ToLower(locale.Yesterday):
+		date := today.AddDate(0, 0, -1)
+		return &date, nil
+	}
+
+	fields := strings.Fields(strings.ReplaceAll(input, ",", " "))
+	if len(fields) == 0 {
+		return nil, errInvalidLocalizedDate(input, locale)
+	}
+
+	weekday, hasWeekday := locale.lookupWeekday(fields[0])
+	if hasWeekday {
+		fields = fields[1:]
+		if len(fields) == 0 {
+			days := (int(weekday) - int(today.Weekday()) + 7) % 7
+			if days == 0 {
+				days = 7
+			}
+			date := today.AddDate(0, 0, days)
+			return &date, nil
+		}
+	}
+
+	date, err := locale.parseExplicitDate(fields, input, today)
+	if err != nil {
+		return nil, err
+	}
+	if hasWeekday && date.Weekday() != weekday {
+		return nil, WrapWithSuggestion(
+			fmt.Errorf("%s is a %s, not a %s", date.Format("2006-01-02"), locale.Weekdays[date.Weekday()][0], locale.Weekdays[weekday][0]),
+			"Check the weekday, or leave it out",
+		)
+	}
+	return date, nil
+}
+
+// parseExplicitDate parses a day and month, with optional year, from fields
+func (l *Locale) parseExplicitDate(fields []string, input string, today time.Time) (*time.Time, error) {
+	joined := strings.Join(fields, " ")
+
+	if slashDatePattern.MatchString(joined) {
+		return nil, errAmbiguousDate(input, l)
+	}
+
+	if m := dottedDatePattern.FindStringSubmatch(joined); m != nil {
+		if !l.DottedDates {
+			return nil, errAmbiguousDate(input, l)
+		}
+		day, _ := strconv.Atoi(m[1])
+		month, _ := strconv.Atoi(m[2])
+		year, _ := strconv.Atoi(m[3])
+		return buildDate(year, time.Month(month), day, input, l, today)
+	}
+
+	if len(fields) < 2 || len(fields) > 3 {
+		return nil, errInvalidLocalizedDate(input, l)
+	}
+
+	// Day and month name in either order: "14 Jul", "14. Juli", "Jul 14"
+	dayField, monthField := fields[0], fields[1]
+	if _, ok := l.lookupMonth(dayField); ok {
+		dayField, monthField = monthField, dayField
+	}
+	month, ok := l.lookupMonth(monthField)
+	if !ok {
+		return nil, errInvalidLocalizedDate(input, l)
+	}
+	day, err := strconv.Atoi(strings.TrimSuffix(dayField, "."))
+	if err != nil {
+		return nil, errInvalidLocalizedDate(input, l)
+	}
+
+	year := 0
+	if len(fields) == 3 {
+		if year, err = strconv.Atoi(fields[2]); err != nil || year < 1000 {
+			return nil, errInvalidLocalizedDate(input, l)
+		}
+	}
+	return buildDate(year, month, day, input, l, today)
+}
+
+// buildDate validates a day and month and fills in the year when it is 0
+func buildDate(year int, month time.Month, day int, input string, l *Locale, today time.Time) (*time.Time, error) {
+	inferYear := year == 0
+	if inferYear {
+		year = today.Year()
+	}
+
+	date := time.Date(year, month, day, 0, 0, 0, 0, time.Local)
+	if month < time.January || month > time.December || date.Day() != day {
+		return nil, errInvalidLocalizedDate(input, l)
+	}
+
+	if inferYear && date.Before(today) {
+		date = time.Date(year+1, month, day, 0, 0, 0, 0, time.Local)
+	}
+	return &date, nil
+}
+
+// lookupWeekday matches a weekday name or abbreviation, ignoring case and a trailing dot
+func (l *Locale) lookupWeekday(word string) (time.Weekday, bool) {
+	word = strings.TrimSuffix(word, ".")
+	for i, names := range l.Weekdays {
+		for _, name := range names {
+			if strings.EqualFold(word, name) {
+				return time.Weekday(i), true
+			}
+		}
+	}
+	return 0, false
+}
+
+// lookupMonth matches a month name or abbreviation, ignoring case and a trailing dot
+func (l *Locale) lookupMonth(word string) (time.Month, bool) {
+	word = strings.TrimSuffix(word, ".")
+	for i, names := range l.Months {
+		for _, name := range names {
+			if strings.EqualFold(word, name) {
+				return time.Month(i + 1), true
+			}
+		}
+	}
+	return 0, false
+}
+
+// dateExamples lists input forms accepted in this locale
+func (l *Locale) dateExamples() string {
+	examples := []string{l.Today, l.Tomorrow, l.Weekdays[time.Monday][1], "14 " + l.Months[6][1]}
+	if l.DottedDates {
+		examples = append(examples, "14.07.", "14.07.2026")
+	}
+	return strings.Join(examples, ", ")
+}
+
+func errInvalidLocalizedDate(input string, l *Locale) error {
+	return WrapWithSuggestion(
+		fmt.Errorf("invalid date format: %s", input),
+		"Use YYYY-MM-DD (e.g., 2026-07-14) or one of: "+l.dateExamples(),
+	)
+}
+
+func errAmbiguousDate(input string, l *Locale) error {
+	return WrapWithSuggestion(
+		fmt.Errorf("ambiguous date: %s (day and month order depends on the reader)", input),
+		"Use ISO format YYYY-MM-DD (e.g., 2026-07-14) or one of: "+l.dateExamples(),
+	)
+}
+
+// DateOnlyLayout returns a time layout without its time of day, for dates
+// that have none, such as those of all-day tasks: "2006-01-02 15:04" gives
+// "2006-01-02". A layout without a time of day is returned unchanged.
+func DateOnlyLayout(layout string) string {
+	for _, clock := range []string{"15:04:05", "15:04", "03:04:05 PM", "03:04 PM", "3:04:05 PM", "3:04 PM", "03:04", "3:04"} {
+		i := strings.Index(layout, clock)
+		if i < 0 {
+			continue
+		}
+		if i == 0 {
+			return strings.TrimLeft(layout[len(clock):], " ,T")
+		}
+		return strings.TrimRight(layout[:i], " ,T") + layout[i+len(clock):]
+	}
+	return layout
+}
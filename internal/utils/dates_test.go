@@ -0,0 +1,153 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// Fixed reference date: Wednesday 2026-07-08
+var testNow = time.Date(2026, 7, 8, 15, 30, 0, 0, time.Local)
+
+func TestParseLocalizedDate(t *testing.T) {
+	tests := []struct {
+		locale  string
+		input   string
+		want    string // YYYY-MM-DD, empty when an error is expected
+		errText string
+	}{
+		// English
+		{locale: "en", input: "2026-07-14", want: "2026-07-14"},
+		{locale: "en", input: "today", want: "2026-07-08"},
+		{locale: "en", input: "Tomorrow", want: "2026-07-09"},
+		{locale: "en", input: "yesterday", want: "2026-07-07"},
+		{locale: "en", input: "Mon", want: "2026-07-13"},
+		{locale: "en", input: "wednesday", want: "2026-07-15"},
+		{locale: "en", input: "14 Jul", want: "2026-07-14"},
+		{locale: "en", input: "July 14, 2027", want: "2027-07-14"},
+		{locale: "en", input: "Jan 3", want: "2027-01-03"},
+		{locale: "en", input: "Tue 14 Jul", want: "2026-07-14"},
+		{locale: "en", input: "Mon 14 Jul", errText: "not a Monday"},
+		{locale: "en", input: "01/02", errText: "ambiguous date"},
+		{locale: "en", input: "14.07.", errText: "ambiguous date"},
+		{locale: "en", input: "31 Feb", errText: "invalid date format"},
+		{locale: "en", input: "heute", errText: "invalid date format"},
+
+		// German
+		{locale: "de", input: "heute", want: "2026-07-08"},
+		{locale: "de", input: "morgen", want: "2026-07-09"},
+		{locale: "de", input: "gestern", want: "2026-07-07"},
+		{locale: "de", input: "tomorrow", want: "2026-07-09"},
+		{locale: "de", input: "Mo", want: "2026-07-13"},
+		{locale: "de", input: "Mi.", want: "2026-07-15"},
+		{locale: "de", input: "14.07.", want: "2026-07-14"},
+		{locale: "de", input: "Di 14.07.", want: "2026-07-14"},
+		{locale: "de", input: "Mo 14.07.", errText: "not a Montag"},
+		{locale: "de", input: "1.3.", want: "2027-03-01"},
+		{locale: "de", input: "14.07.2027", want: "2027-07-14"},
+		{locale: "de", input: "14. Juli", want: "2026-07-14"},
+		{locale: "de", input: "3. März 2027", want: "2027-03-03"},
+		{locale: "de", input: "01/02", errText: "ambiguous date"},
+		{locale: "de", input: "32.01.", errText: "invalid date format"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.locale+"/"+tt.input, func(t *testing.T) {
+			got, err := ParseLocalizedDate(tt.input, GetLocale(tt.locale), testNow)
+			if tt.errText != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.errText) {
+					t.Fatalf("ParseLocalizedDate(%q) error = %v, want error containing %q", tt.input, err, tt.errText)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseLocalizedDate(%q) unexpected error: %v", tt.input, err)
+			}
+			if got.Format("2006-01-02") != tt.want {
+				t.Errorf("ParseLocalizedDate(%q) = %s, want %s", tt.input, got.Format("2006-01-02"), tt.want)
+			}
+		})
+	}
+}
+
+func TestAmbiguousDateSuggestsISO(t *testing.T) {
+	_, err := ParseLocalizedDate("01/02", GetLocale("de"), testNow)
+	if err == nil || !strings.Contains(err.Error(), "YYYY-MM-DD") || !strings.Contains(err.Error(), "14.07.") {
+		t.Errorf("expected ISO and locale examples in error, got %v", err)
+	}
+}
+
+func TestLocaleFormat(t *testing.T) {
+	date := time.Date(2026, 7, 14, 9, 5, 0, 0, time.Local)
+
+	tests := []struct {
+		locale string
+		layout string
+		want   string
+	}{
+		{locale: "en", layout: "Mon 02.01.", want: "Tue 14.07."},
+		{locale: "en", layout: "Monday, January 2 2006", want: "Tuesday, July 14 2026"},
+		{locale: "de", layout: "Mon 02.01.", want: "Di 14.07."},
+		{locale: "de", layout: "Monday, 2. January 2006 15:04", want: "Dienstag, 14. Juli 2026 09:05"},
+		{locale: "de", layout: "Jan 02", want: "Jul 14"},
+		{locale: "de", layout: "2006-01-02", want: "2026-07-14"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.locale+"/"+tt.layout, func(t *testing.T) {
+			if got := GetLocale(tt.locale).Format(date, tt.layout); got != tt.want {
+				t.Errorf("Format(%q) = %q, want %q", tt.layout, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLocaleSelection(t *testing.T) {
+	tests := map[string]string{
+		"de_DE.UTF-8": "de",
+		"en_US":       "en",
+		"C":           "en",
+		"fr_FR":       "en", // unknown locales fall back to English
+	}
+	for code, want := range tests {
+		if got := GetLocale(code).Code; got != want {
+			t.Errorf("GetLocale(%q) = %s, want %s", code, got, want)
+		}
+	}
+
+	t.Setenv("LC_ALL", "")
+	t.Setenv("LC_TIME", "de_AT.UTF-8")
+	if got := LocaleFromEnv(); got != "de" {
+		t.Errorf("LocaleFromEnv() = %s, want de", got)
+	}
+}
+
+func TestResolveDateFormat(t *testing.T) {
+	tests := map[string]string{
+		"iso":        "2006-01-02",
+		"EU":         "02.01.2006",
+		"us":         "01/02/2006",
+		"Mon 02.01.": "Mon 02.01.",
+	}
+	for input, want := range tests {
+		if got := ResolveDateFormat(input); got != want {
+			t.Errorf("ResolveDateFormat(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
+
+func TestDateOnlyLayout(t *testing.T) {
+	tests := map[string]string{
+		"2006-01-02":          "2006-01-02",
+		"2006-01-02 15:04":    "2006-01-02",
+		"2006-01-02T15:04:05": "2006-01-02",
+		"Jan 2, 2006 3:04 PM": "Jan 2, 2006",
+		"15:04 02.01.2006":    "02.01.2006",
+		"Mon 02 Jan":          "Mon 02 Jan",
+	}
+	for layout, want := range tests {
+		if got := DateOnlyLayout(layout); got != want {
+			t.Errorf("DateOnlyLayout(%q) = %q, want %q", layout, got, want)
+		}
+	}
+}
@@ -58,6 +58,35 @@ func PromptYesNo(question string) bool {
 	}
 }
 
+// PromptYesNoDefault prompts with a [Y/n] or [y/N] suffix and returns def on an empty answer
+func PromptYesNoDefault(question string, def bool) bool {
+	suffix := "[y/N]"
+	if def {
+		suffix = "[Y/n]"
+	}
+
+	reader := bufio.NewReader(os.Stdin)
+	for {
+		fmt.Printf("%s %s: ", question, suffix)
+		response, err := reader.ReadString('\n')
+		response = strings.ToLower(strings.TrimSpace(response))
+
+		switch response {
+		case "y", "yes":
+			return true
+		case "n", "no":
+			return false
+		case "":
+			return def
+		default:
+			if err != nil {
+				return def
+			}
+			fmt.Println("Please enter y or n")
+		}
+	}
+}
+
 // PromptSelection displays a numbered list of items and prompts user to select one
 // displayFunc is called for each item to display it
 // Returns the selected index (0-based) and an error if cancelled or invalid input
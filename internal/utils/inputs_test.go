@@ -216,3 +216,30 @@ func TestPromptYesNo_VariousResponses(t *testing.T) {
 		})
 	}
 }
+
+func TestPromptYesNoDefault(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		def   bool
+		want  bool
+	}{
+		{"empty uses default yes", "\n", true, true},
+		{"empty uses default no", "\n", false, false},
+		{"explicit no overrides default", "n\n", true, false},
+		{"explicit yes overrides default", "yes\n", false, true},
+		{"invalid then yes", "maybe\ny\n", false, true},
+		{"eof uses default", "", true, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cleanup := mockStdin(t, tt.input)
+			defer cleanup()
+
+			if got := PromptYesNoDefault("Continue?", tt.def); got != tt.want {
+				t.Errorf("PromptYesNoDefault(%q, %v) = %v, want %v", tt.input, tt.def, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,185 @@
+package utils
+
+import (
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultLocale is used when no locale is configured or the configured one is unknown
+const DefaultLocale = "en"
+
+// Locale holds the words used to parse and display dates in one language
+type Locale struct {
+	Code string
+
+	// Weekdays and Months are indexed like time.Weekday and time.Month-1.
+	// Each entry lists the full name first, then accepted abbreviations;
+	// the second entry is used when displaying abbreviated names.
+	Weekdays [7][]string
+	Months   [12][]string
+
+	Today     string
+	Tomorrow  string
+	Yesterday string
+
+	// DottedDates accepts day-first "14.07." and "14.07.2026" input
+	DottedDates bool
+
+	// FirstWeekday starts the week due dates "this week" end with
+	FirstWeekday time.Weekday
+
+	// RelativePast and RelativeFuture wrap a humanized duration, e.g. "%s ago"
+	RelativePast   string
+	RelativeFuture string
+
+	// Units are the suffixes for years, months, weeks, days, hours, minutes, seconds
+	Units [7]string
+}
+
+var (
+	localeMu     sync.RWMutex
+	activeLocale = DefaultLocale
+	locales      = map[string]*Locale{
+		"en": {
+			Code: "en",
+			Weekdays: [7][]string{
+				{"Sunday", "Sun"}, {"Monday", "Mon"}, {"Tuesday", "Tue", "Tues"}, {"Wednesday", "Wed"},
+				{"Thursday", "Thu", "Thurs"}, {"Friday", "Fri"}, {"Saturday", "Sat"},
+			},
+			Months: [12][]string{
+				{"January", "Jan"}, {"February", "Feb"}, {"March", "Mar"}, {"April", "Apr"},
+				{"May", "May"}, {"June", "Jun"}, {"July", "Jul"}, {"August", "Aug"},
+				{"September", "Sep", "Sept"}, {"October", "Oct"}, {"November", "Nov"}, {"December", "Dec"},
+			},
+			Today:          "today",
+			Tomorrow:       "tomorrow",
+			Yesterday:      "yesterday",
+			FirstWeekday:   time.Sunday,
+			RelativePast:   "%s ago",
+			RelativeFuture: "in %s",
+			Units:          [7]string{"y", "mo", "w", "d", "h", "m", "s"},
+		},
+		"de": {
+			Code: "de",
+			Weekdays: [7][]string{
+				{"Sonntag", "So"}, {"Montag", "Mo"}, {"Dienstag", "Di"}, {"Mittwoch", "Mi"},
+				{"Donnerstag", "Do"}, {"Freitag", "Fr"}, {"Samstag", "Sa"},
+			},
+			Months: [12][]string{
+				{"Januar", "Jan"}, {"Februar", "Feb"}, {"März", "Mär", "Maerz"}, {"April", "Apr"},
+				{"Mai", "Mai"}, {"Juni", "Jun"}, {"Juli", "Jul"}, {"August", "Aug"},
+				{"September", "Sep", "Sept"}, {"Oktober", "Okt"}, {"November", "Nov"}, {"Dezember", "Dez"},
+			},
+			Today:          "heute",
+			Tomorrow:       "morgen",
+			Yesterday:      "gestern",
+			DottedDates:    true,
+			FirstWeekday:   time.Monday,
+			RelativePast:   "vor %s",
+			RelativeFuture: "in %s",
+			Units:          [7]string{"J", "Mon", "W", "T", "Std", "Min", "s"},
+		},
+	}
+)
+
+// RegisterLocale adds or replaces a locale table
+func RegisterLocale(locale *Locale) {
+	localeMu.Lock()
+	defer localeMu.Unlock()
+	locales[locale.Code] = locale
+}
+
+// GetLocale returns the locale for code, falling back to English
+func GetLocale(code string) *Locale {
+	localeMu.RLock()
+	defer localeMu.RUnlock()
+	if locale, ok := locales[normalizeLocaleCode(code)]; ok {
+		return locale
+	}
+	return locales[DefaultLocale]
+}
+
+// SetLocale selects the locale used by ParseDateFlag and date display
+func SetLocale(code string) {
+	localeMu.Lock()
+	defer localeMu.Unlock()
+	activeLocale = normalizeLocaleCode(code)
+}
+
+// ActiveLocale returns the locale selected with SetLocale
+func ActiveLocale() *Locale {
+	localeMu.RLock()
+	code := activeLocale
+	localeMu.RUnlock()
+	return GetLocale(code)
+}
+
+// LocaleFromEnv returns the language of LC_ALL, LC_TIME or LANG, in that order
+func LocaleFromEnv() string {
+	for _, name := range []string{"LC_ALL", "LC_TIME", "LANG"} {
+		if value := os.Getenv(name); value != "" {
+			return normalizeLocaleCode(value)
+		}
+	}
+	return DefaultLocale
+}
+
+// normalizeLocaleCode reduces values like "de_DE.UTF-8" to "de"
+func normalizeLocaleCode(code string) string {
+	code = strings.ToLower(strings.TrimSpace(code))
+	if i := strings.IndexAny(code, "_.-@"); i >= 0 {
+		code = code[:i]
+	}
+	if code == "" || code == "c" || code == "posix" {
+		return DefaultLocale
+	}
+	return code
+}
+
+// Format formats t with a Go layout, using the locale's weekday and month names
+func (l *Locale) Format(t time.Time, layout string) string {
+	var sb strings.Builder
+	for layout != "" {
+		switch {
+		case strings.HasPrefix(layout, "Monday"):
+			sb.WriteString(l.Weekdays[t.Weekday()][0])
+			layout = layout[len("Monday"):]
+		case strings.HasPrefix(layout, "Mon"):
+			sb.WriteString(l.Weekdays[t.Weekday()][1])
+			layout = layout[len("Mon"):]
+		case strings.HasPrefix(layout, "January"):
+			sb.WriteString(l.Months[t.Month()-1][0])
+			layout = layout[len("January"):]
+		case strings.HasPrefix(layout, "Jan"):
+			sb.WriteString(l.Months[t.Month()-1][1])
+			layout = layout[len("Jan"):]
+		default:
+			next := len(layout)
+			for _, token := range []string{"Mon", "Jan"} {
+				if i := strings.Index(layout[1:], token); i >= 0 && i+1 < next {
+					next = i + 1
+				}
+			}
+			sb.WriteString(t.Format(layout[:next]))
+			layout = layout[next:]
+		}
+	}
+	return sb.String()
+}
+
+// DateFormatPresets maps named date formats to Go layouts
+var DateFormatPresets = map[string]string{
+	"iso": "2006-01-02",
+	"eu":  "02.01.2006",
+	"us":  "01/02/2006",
+}
+
+// ResolveDateFormat returns the Go layout for a preset name, or format unchanged
+func ResolveDateFormat(format string) string {
+	if layout, ok := DateFormatPresets[strings.ToLower(format)]; ok {
+		return layout
+	}
+	return format
+}
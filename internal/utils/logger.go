@@ -2,6 +2,7 @@ package utils
 
 import (
 	"fmt"
+	"gosynctasks/internal/version"
 	"io"
 	"log"
 	"os"
@@ -176,6 +177,7 @@ func NewBackgroundLogger() (*BackgroundLogger, error) {
 
 	bl.logFile = logFile
 	bl.logger = log.New(logFile, "[BackgroundSync] ", log.LstdFlags)
+	bl.logger.Print(version.Get()) // Build metadata heads every run, for bug reports
 
 	return bl, nil
 }
@@ -0,0 +1,83 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// tabWidth is the tab stop used when expanding tabs in rendered text
+const tabWidth = 4
+
+// SanitizeLine makes a user- or remote-sourced string safe to print on one
+// terminal line: control characters (including ESC and newlines) and invisible
+// format characters are shown escaped ("\x1b", "\u200b"), and tabs are expanded
+// to spaces so column widths stay correct.
+func SanitizeLine(s string) string {
+	return sanitize(s, false)
+}
+
+// SanitizeText is SanitizeLine for multi-line text: newlines are kept
+func SanitizeText(s string) string {
+	return sanitize(s, true)
+}
+
+func sanitize(s string, keepNewlines bool) string {
+	if isTerminalSafe(s, keepNewlines) {
+		return s
+	}
+
+	var sb strings.Builder
+	column := 0
+	for i := 0; i < len(s); {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		var out string
+		switch {
+		case r == utf8.RuneError && size == 1:
+			out = fmt.Sprintf(`\x%02x`, s[i])
+		case r == '\t':
+			out = strings.Repeat(" ", tabWidth-column%tabWidth)
+		case r == '\n' && keepNewlines:
+			sb.WriteByte('\n')
+			column = 0
+			i += size
+			continue
+		case needsEscape(r):
+			if r <= 0xff {
+				out = fmt.Sprintf(`\x%02x`, r)
+			} else {
+				out = fmt.Sprintf(`\u%04x`, r)
+			}
+		default:
+			out = string(r)
+		}
+		sb.WriteString(out)
+		column += utf8.RuneCountInString(out)
+		i += size
+	}
+	return sb.String()
+}
+
+// isTerminalSafe reports whether s can be printed unchanged
+func isTerminalSafe(s string, keepNewlines bool) bool {
+	for _, r := range s {
+		if r == '\n' && keepNewlines {
+			continue
+		}
+		if r == utf8.RuneError || r == '\t' || needsEscape(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// needsEscape reports whether r is a C0/C1 control character or an invisible
+// format character (zero-width space, bidi overrides). The zero-width joiner
+// is kept because emoji sequences rely on it.
+func needsEscape(r rune) bool {
+	if r < 0x20 || r == 0x7f || (r >= 0x80 && r <= 0x9f) {
+		return true
+	}
+	return r != '\u200d' && unicode.Is(unicode.Cf, r)
+}
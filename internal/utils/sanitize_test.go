@@ -0,0 +1,40 @@
+package utils
+
+import "testing"
+
+func TestSanitizeLine(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{name: "plain", input: "Buy milk", want: "Buy milk"},
+		{name: "unicode kept", input: "Café ✓ 日本", want: "Café ✓ 日本"},
+		{name: "ESC sequence", input: "red \x1b[31mtext\x1b[0m", want: `red \x1b[31mtext\x1b[0m`},
+		{name: "BEL", input: "ding\a", want: `ding\x07`},
+		{name: "tab expands to stop", input: "a\tb", want: "a   b"},
+		{name: "tab at stop", input: "abcd\te", want: "abcd    e"},
+		{name: "zero-width space", input: "zero\u200bwidth", want: `zero\u200bwidth`},
+		{name: "bidi override", input: "abc\u202edef", want: `abc\u202edef`},
+		{name: "newline escaped", input: "one\ntwo", want: `one\x0atwo`},
+		{name: "C1 control", input: "csi\u009b2J", want: `csi\x9b2J`},
+		{name: "invalid utf8", input: "bad\xffbyte", want: `bad\xffbyte`},
+		{name: "zero-width joiner kept", input: "👩\u200d💻", want: "👩\u200d💻"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SanitizeLine(tt.input); got != tt.want {
+				t.Errorf("SanitizeLine(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSanitizeTextKeepsNewlines(t *testing.T) {
+	input := "first\tline\nsecond \x1b[2J line"
+	want := "first   line\nsecond \\x1b[2J line"
+	if got := SanitizeText(input); got != want {
+		t.Errorf("SanitizeText(%q) = %q, want %q", input, got, want)
+	}
+}
@@ -0,0 +1,75 @@
+package utils
+
+import "time"
+
+// DueUrgency is how close a due date is, counted in calendar days
+type DueUrgency int
+
+const (
+	DueLater    DueUrgency = iota // After the end of the current week
+	DueThisWeek                   // Later in the current week
+	DueTomorrow                   // Tomorrow
+	DueToday                      // Later today
+	DueOverdue                    // Past
+)
+
+// dueUrgencyRoles are the names of the urgencies, as used in views
+var dueUrgencyRoles = map[DueUrgency]string{
+	DueLater:    "later",
+	DueThisWeek: "this_week",
+	DueTomorrow: "tomorrow",
+	DueToday:    "today",
+	DueOverdue:  "overdue",
+}
+
+// dueUrgencyColors are the ANSI colors due dates are shown in, by urgency
+var dueUrgencyColors = map[DueUrgency]string{
+	DueLater:    "\033[90m",   // Gray
+	DueThisWeek: "\033[37m",   // White
+	DueTomorrow: "\033[33m",   // Yellow
+	DueToday:    "\033[1;93m", // Bold bright yellow
+	DueOverdue:  "\033[31m",   // Red
+}
+
+// String returns the name of the urgency: overdue, today, tomorrow, this_week or later
+func (u DueUrgency) String() string {
+	return dueUrgencyRoles[u]
+}
+
+// Color returns the ANSI color due dates with this urgency are shown in
+func (u DueUrgency) Color() string {
+	return dueUrgencyColors[u]
+}
+
+// ClassifyDue returns the urgency of a due date on the calendar of now's
+// location, weeks starting on firstWeekday. A due date at midnight is an
+// all-day date, as date-only input is stored: it is due today until the day
+// ends instead of being overdue from its first minute.
+func ClassifyDue(due, now time.Time, firstWeekday time.Weekday) DueUrgency {
+	due = due.In(now.Location())
+	today := startOfDay(now)
+	dueDay := startOfDay(due)
+
+	allDay := due.Equal(dueDay)
+	if dueDay.Before(today) || (!allDay && due.Before(now)) {
+		return DueOverdue
+	}
+
+	// Calendar arithmetic, so days stay days across DST changes
+	daysToNextWeek := 7 - (int(today.Weekday())-int(firstWeekday)+7)%7
+	switch {
+	case dueDay.Equal(today):
+		return DueToday
+	case dueDay.Equal(today.AddDate(0, 0, 1)):
+		return DueTomorrow
+	case dueDay.Before(today.AddDate(0, 0, daysToNextWeek)):
+		return DueThisWeek
+	default:
+		return DueLater
+	}
+}
+
+// startOfDay returns midnight of t's day in t's location
+func startOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
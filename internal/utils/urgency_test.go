@@ -0,0 +1,64 @@
+package utils
+
+import (
+	"testing"
+	"time"
+	_ "time/tzdata" // Europe/Berlin on systems without zoneinfo
+)
+
+func TestClassifyDue(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	at := func(value string) time.Time {
+		parsed, err := time.ParseInLocation("2006-01-02 15:04:05", value, berlin)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return parsed
+	}
+
+	tests := []struct {
+		name         string
+		now          string
+		due          time.Time
+		firstWeekday time.Weekday
+		want         DueUrgency
+	}{
+		// Wednesday 2026-07-08, just before midnight
+		{name: "later today", now: "2026-07-08 23:59:30", due: at("2026-07-08 23:59:59"), want: DueToday},
+		{name: "earlier today", now: "2026-07-08 23:59:30", due: at("2026-07-08 23:59:00"), want: DueOverdue},
+		{name: "all-day today", now: "2026-07-08 23:59:30", due: at("2026-07-08 00:00:00"), want: DueToday},
+		{name: "all-day yesterday", now: "2026-07-08 23:59:30", due: at("2026-07-07 00:00:00"), want: DueOverdue},
+		{name: "just after midnight", now: "2026-07-08 23:59:30", due: at("2026-07-09 00:00:30"), want: DueTomorrow},
+		{name: "end of tomorrow", now: "2026-07-08 00:00:10", due: at("2026-07-09 23:59:59"), want: DueTomorrow},
+		{name: "due in another zone", now: "2026-07-08 20:00:00", due: time.Date(2026, 7, 8, 22, 30, 0, 0, time.UTC), want: DueTomorrow},
+
+		// Just after midnight, yesterday's tasks are overdue and today's all-day ones are not
+		{name: "yesterday night", now: "2026-07-09 00:00:10", due: at("2026-07-08 23:59:00"), want: DueOverdue},
+		{name: "all-day after midnight", now: "2026-07-09 00:00:10", due: at("2026-07-09 00:00:00"), want: DueToday},
+
+		// Weeks end before the locale's first weekday
+		{name: "Sunday in a Monday week", now: "2026-07-08 12:00:00", due: at("2026-07-12 09:00:00"), firstWeekday: time.Monday, want: DueThisWeek},
+		{name: "next Monday", now: "2026-07-08 12:00:00", due: at("2026-07-13 09:00:00"), firstWeekday: time.Monday, want: DueLater},
+		{name: "Saturday in a Sunday week", now: "2026-07-08 12:00:00", due: at("2026-07-11 09:00:00"), firstWeekday: time.Sunday, want: DueThisWeek},
+		{name: "Sunday in a Sunday week", now: "2026-07-08 12:00:00", due: at("2026-07-12 09:00:00"), firstWeekday: time.Sunday, want: DueLater},
+		{name: "tomorrow in the next week", now: "2026-07-11 12:00:00", due: at("2026-07-12 09:00:00"), firstWeekday: time.Sunday, want: DueTomorrow},
+
+		// Clocks go forward on 2026-03-29, back on 2026-10-25: days are 23 and 25 hours long
+		{name: "short day", now: "2026-03-29 00:30:00", due: at("2026-03-29 23:30:00"), want: DueToday},
+		{name: "after the short day", now: "2026-03-29 00:30:00", due: at("2026-03-30 00:00:00"), want: DueTomorrow},
+		{name: "before the short day", now: "2026-03-28 23:30:00", due: at("2026-03-29 23:59:00"), want: DueTomorrow},
+		{name: "long day", now: "2026-10-25 00:30:00", due: at("2026-10-25 23:45:00"), want: DueToday},
+		{name: "after the long day", now: "2026-10-25 00:30:00", due: at("2026-10-26 00:00:00"), want: DueTomorrow},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ClassifyDue(tt.due, at(tt.now), tt.firstWeekday); got != tt.want {
+				t.Errorf("ClassifyDue(%s, %s) = %s, want %s", tt.due, tt.now, got, tt.want)
+			}
+		})
+	}
+}
@@ -2,6 +2,8 @@ package utils
 
 import (
 	"fmt"
+	"strconv"
+	"strings"
 	"time"
 )
 
@@ -13,19 +15,47 @@ func ValidatePriority(priority int) error {
 	return nil
 }
 
-// ParseDateFlag parses a date string in ISO format (YYYY-MM-DD).
+// ParseDateFlag parses a date in ISO format (YYYY-MM-DD) or, using the active
+// locale, a natural form such as "tomorrow" or "Mo 14.07." (see ParseLocalizedDate).
 // Returns nil for empty strings (used to clear dates).
-// Returns error for invalid formats or dates.
+// Returns error for invalid, ambiguous or impossible dates.
 func ParseDateFlag(dateStr string) (*time.Time, error) {
 	// Empty string means clear the date
 	if dateStr == "" {
 		return nil, nil
 	}
 
-	// Parse ISO date format (YYYY-MM-DD) in local timezone
-	parsedDate, err := time.ParseInLocation("2006-01-02", dateStr, time.Local)
+	return ParseLocalizedDate(dateStr, ActiveLocale(), time.Now())
+}
+
+// ParseSinceFlag parses a point in the past relative to now.
+// Accepts a duration with unit suffix (30m, 12h, 2d, 1w) or a date (YYYY-MM-DD).
+func ParseSinceFlag(value string, now time.Time) (*time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil, nil
+	}
+
+	units := map[byte]time.Duration{
+		'm': time.Minute,
+		'h': time.Hour,
+		'd': 24 * time.Hour,
+		'w': 7 * 24 * time.Hour,
+	}
+
+	if unit, ok := units[value[len(value)-1]]; ok {
+		if n, err := strconv.Atoi(value[:len(value)-1]); err == nil && n >= 0 {
+			since := now.Add(-time.Duration(n) * unit)
+			return &since, nil
+		}
+	}
+
+	parsedDate, err := time.ParseInLocation("2006-01-02", value, time.Local)
 	if err != nil {
-		return nil, ErrInvalidDate(dateStr)
+		return nil, WrapWithSuggestion(
+			fmt.Errorf("invalid time reference: %s", value),
+			"Use a duration like 30m, 12h, 2d, 1w or a date like 2026-01-15",
+		)
 	}
 
 	return &parsedDate, nil
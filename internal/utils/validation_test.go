@@ -161,3 +161,46 @@ func TestValidateDates(t *testing.T) {
 		})
 	}
 }
+
+func TestParseSinceFlag(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.Local)
+
+	tests := []struct {
+		name    string
+		input   string
+		want    *time.Time
+		wantErr bool
+	}{
+		{"empty returns nil", "", nil, false},
+		{"minutes", "30m", timePtr(now.Add(-30 * time.Minute)), false},
+		{"hours", "12h", timePtr(now.Add(-12 * time.Hour)), false},
+		{"days", "2d", timePtr(now.Add(-48 * time.Hour)), false},
+		{"weeks", "1w", timePtr(now.Add(-7 * 24 * time.Hour)), false},
+		{"date", "2026-03-01", timePtr(time.Date(2026, 3, 1, 0, 0, 0, 0, time.Local)), false},
+		{"unknown unit", "2y", nil, true},
+		{"negative", "-2d", nil, true},
+		{"garbage", "yesterday", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseSinceFlag(tt.input, now)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseSinceFlag(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if (got == nil) != (tt.want == nil) {
+				t.Fatalf("ParseSinceFlag(%q) = %v, want %v", tt.input, got, tt.want)
+			}
+			if got != nil && !got.Equal(*tt.want) {
+				t.Errorf("ParseSinceFlag(%q) = %v, want %v", tt.input, *got, *tt.want)
+			}
+		})
+	}
+}
+
+func timePtr(t time.Time) *time.Time {
+	return &t
+}
@@ -0,0 +1,37 @@
+package utils
+
+import (
+	"regexp"
+
+	"github.com/mattn/go-runewidth"
+)
+
+// ansiSequence matches the escape sequences used for colors and terminal
+// titles: CSI sequences (ESC [ ... letter) and OSC sequences ended by BEL or ST
+var ansiSequence = regexp.MustCompile(`\x1b\[[0-9;?]*[ -/]*[@-~]|\x1b\][^\x07\x1b]*(\x07|\x1b\\)`)
+
+// widths measures columns with ambiguous-width characters, such as the box
+// drawing characters of list headers, as narrow whatever the locale, as most
+// terminals draw them
+var widths = func() *runewidth.Condition {
+	condition := runewidth.NewCondition()
+	condition.EastAsianWidth = false
+	return condition
+}()
+
+// StripANSI removes color and other escape sequences from s
+func StripANSI(s string) string {
+	return ansiSequence.ReplaceAllString(s, "")
+}
+
+// DisplayWidth returns the number of terminal columns s occupies: escape
+// sequences take none, wide characters such as CJK and most emoji take two
+func DisplayWidth(s string) int {
+	return widths.StringWidth(StripANSI(s))
+}
+
+// TruncateWidth shortens s, which must not contain escape sequences, to at
+// most width columns, ending it with tail when it was cut
+func TruncateWidth(s string, width int, tail string) string {
+	return widths.Truncate(s, width, tail)
+}
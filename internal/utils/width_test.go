@@ -0,0 +1,28 @@
+package utils
+
+import "testing"
+
+func TestDisplayWidth(t *testing.T) {
+	tests := map[string]int{
+		"plain":                         5,
+		"\033[1;36mcolored\033[0m":      7,
+		"\033]0;title\a┌─┐":             3,
+		"日本語":                           6,
+		"Café ✓":                        6,
+		"\033[38;2;255;0;0m🚀\033[0m go": 5,
+	}
+	for s, want := range tests {
+		if got := DisplayWidth(s); got != want {
+			t.Errorf("DisplayWidth(%q) = %d, want %d", s, got, want)
+		}
+	}
+}
+
+func TestTruncateWidth(t *testing.T) {
+	if got := TruncateWidth("日本語のリスト", 9, "..."); got != "日本語..." {
+		t.Errorf("TruncateWidth() = %q, want the wide characters that fit and the tail", got)
+	}
+	if got := TruncateWidth("short", 9, "..."); got != "short" {
+		t.Errorf("TruncateWidth() = %q, want it unchanged", got)
+	}
+}
@@ -0,0 +1,32 @@
+package utils
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestSplitWords(t *testing.T) {
+	tests := map[string][]string{
+		`Inbox add`:                 {"Inbox", "add"},
+		`  all   get -v minimal `:   {"all", "get", "-v", "minimal"},
+		`Inbox add "call mom"`:      {"Inbox", "add", "call mom"},
+		`Work add 'Pay $100 "now"'`: {"Work", "add", `Pay $100 "now"`},
+		`Work add "Say \"hi\" \n"`:  {"Work", "add", `Say "hi" \n`},
+		`Work\ Stuff get`:           {"Work Stuff", "get"},
+		`Work add ""`:               {"Work", "add", ""},
+		`My" "List get`:             {"My List", "get"},
+		``:                          nil,
+	}
+	for line, want := range tests {
+		got, err := SplitWords(line)
+		if err != nil || !slices.Equal(got, want) {
+			t.Errorf("SplitWords(%q) = %q, %v, want %q", line, got, err, want)
+		}
+	}
+
+	for _, line := range []string{`Inbox add "call mom`, `Inbox add 'x`, `Inbox add \`} {
+		if _, err := SplitWords(line); err == nil {
+			t.Errorf("SplitWords(%q) error = nil, want one", line)
+		}
+	}
+}
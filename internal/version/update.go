@@ -0,0 +1,174 @@
+package version
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultReleasesURL is the GitHub API endpoint for the latest release
+const DefaultReleasesURL = "https://api.github.com/repos/DeepReef11/gosynctasks/releases/latest"
+
+// updateCacheTTL is how long a release lookup is reused before asking GitHub again
+const updateCacheTTL = 24 * time.Hour
+
+// Release is the latest published release
+type Release struct {
+	Version string `json:"tag_name"`
+	URL     string `json:"html_url"`
+}
+
+// UpdateStatus is the result of an update check
+type UpdateStatus struct {
+	Current   string
+	Latest    Release
+	Newer     bool // Latest is newer than Current
+	Cached    bool // Latest came from the on-disk cache
+	CheckedAt time.Time
+}
+
+// updateCache is the on-disk record of the last release lookup
+type updateCache struct {
+	CheckedAt time.Time `json:"checked_at"`
+	Latest    Release   `json:"latest"`
+}
+
+// UpdateChecker looks up the latest release, at most once per day.
+// It only reports; nothing is ever downloaded.
+type UpdateChecker struct {
+	ReleasesURL string
+	CachePath   string // File the last lookup is kept in; empty disables caching
+	Client      *http.Client
+	Now         func() time.Time
+}
+
+// NewUpdateChecker returns a checker for the GitHub releases of gosynctasks
+// that caches lookups in cacheDir
+func NewUpdateChecker(cacheDir string) *UpdateChecker {
+	return &UpdateChecker{
+		ReleasesURL: DefaultReleasesURL,
+		CachePath:   filepath.Join(cacheDir, "update-check.json"),
+		Client:      &http.Client{Timeout: 10 * time.Second},
+		Now:         time.Now,
+	}
+}
+
+// Check compares current with the latest release
+func (c *UpdateChecker) Check(current string) (*UpdateStatus, error) {
+	now := c.Now()
+	status := &UpdateStatus{Current: current}
+
+	if cached, ok := c.readCache(); ok && now.Sub(cached.CheckedAt) < updateCacheTTL {
+		status.Latest = cached.Latest
+		status.Cached = true
+		status.CheckedAt = cached.CheckedAt
+	} else {
+		latest, err := c.fetchLatest()
+		if err != nil {
+			return nil, err
+		}
+		status.Latest = latest
+		status.CheckedAt = now
+		c.writeCache(updateCache{CheckedAt: now, Latest: latest})
+	}
+
+	status.Newer = IsNewer(status.Latest.Version, current)
+	return status, nil
+}
+
+func (c *UpdateChecker) fetchLatest() (Release, error) {
+	req, err := http.NewRequest(http.MethodGet, c.ReleasesURL, nil)
+	if err != nil {
+		return Release{}, err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("User-Agent", "gosynctasks/"+Version)
+
+	resp, err := c.Client.Do(req)
+	if err != nil {
+		return Release{}, fmt.Errorf("failed to query releases: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return Release{}, fmt.Errorf("failed to query releases: %s", resp.Status)
+	}
+
+	var release Release
+	if err := json.NewDecoder(resp.Body).Decode(&release); err != nil {
+		return Release{}, fmt.Errorf("failed to parse release: %w", err)
+	}
+	if release.Version == "" {
+		return Release{}, fmt.Errorf("release has no version tag")
+	}
+	return release, nil
+}
+
+func (c *UpdateChecker) readCache() (updateCache, bool) {
+	var cached updateCache
+	if c.CachePath == "" {
+		return cached, false
+	}
+	data, err := os.ReadFile(c.CachePath)
+	if err != nil || json.Unmarshal(data, &cached) != nil || cached.Latest.Version == "" {
+		return cached, false
+	}
+	return cached, true
+}
+
+// writeCache stores the lookup; failures only cost a lookup next time
+func (c *UpdateChecker) writeCache(cached updateCache) {
+	if c.CachePath == "" {
+		return
+	}
+	data, err := json.Marshal(cached)
+	if err != nil {
+		return
+	}
+	_ = os.WriteFile(c.CachePath, data, 0644)
+}
+
+// IsNewer reports whether latest is a later semantic version than current.
+// Development builds are never considered out of date.
+func IsNewer(latest, current string) bool {
+	latestParts, ok := parseSemver(latest)
+	if !ok {
+		return false
+	}
+	currentParts, ok := parseSemver(current)
+	if !ok {
+		return false
+	}
+	for i := range latestParts {
+		if latestParts[i] != currentParts[i] {
+			return latestParts[i] > currentParts[i]
+		}
+	}
+	return false
+}
+
+// parseSemver parses "v1.2.3" or "1.2.3", ignoring pre-release and build suffixes
+func parseSemver(s string) ([3]int, bool) {
+	var parts [3]int
+	s = strings.TrimPrefix(strings.TrimSpace(s), "v")
+	if i := strings.IndexAny(s, "-+"); i >= 0 {
+		s = s[:i]
+	}
+	fields := strings.Split(s, ".")
+	if len(fields) == 0 || len(fields) > 3 {
+		return parts, false
+	}
+	for i, field := range fields {
+		n, err := strconv.Atoi(field)
+		if err != nil || n < 0 {
+			return parts, false
+		}
+		parts[i] = n
+	}
+	return parts, true
+}
@@ -0,0 +1,70 @@
+// Package version holds the build metadata of the gosynctasks binary.
+//
+// Release builds set the variables with ldflags:
+//
+//	go build -ldflags "-X gosynctasks/internal/version.Version=v1.2.3 \
+//	  -X gosynctasks/internal/version.Commit=$(git rev-parse --short HEAD) \
+//	  -X gosynctasks/internal/version.Date=$(date -u +%Y-%m-%dT%H:%M:%SZ)"
+package version
+
+import (
+	"fmt"
+	"runtime"
+	"runtime/debug"
+)
+
+// Set via ldflags; "devel" for plain go build / go run
+var (
+	Version = "devel"
+	Commit  = "devel"
+	Date    = "devel"
+)
+
+// Info is the build metadata of the running binary
+type Info struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit"`
+	Date      string `json:"date"`
+	GoVersion string `json:"go_version"`
+	Platform  string `json:"platform"`
+}
+
+// Get returns the build metadata. When the ldflags were not set, the commit
+// and date fall back to the VCS stamp Go embeds in binaries built from a checkout.
+func Get() Info {
+	info := Info{
+		Version:   Version,
+		Commit:    Commit,
+		Date:      Date,
+		GoVersion: runtime.Version(),
+		Platform:  runtime.GOOS + "/" + runtime.GOARCH,
+	}
+
+	if buildInfo, ok := debug.ReadBuildInfo(); ok {
+		modified := false
+		for _, setting := range buildInfo.Settings {
+			switch setting.Key {
+			case "vcs.revision":
+				if info.Commit == "devel" && len(setting.Value) >= 7 {
+					info.Commit = setting.Value[:7]
+				}
+			case "vcs.time":
+				if info.Date == "devel" {
+					info.Date = setting.Value
+				}
+			case "vcs.modified":
+				modified = setting.Value == "true"
+			}
+		}
+		if modified && Commit == "devel" && info.Commit != "devel" {
+			info.Commit += "-dirty"
+		}
+	}
+
+	return info
+}
+
+// String returns the metadata on one line, e.g. for log headers
+func (i Info) String() string {
+	return fmt.Sprintf("gosynctasks %s (commit %s, built %s, %s, %s)", i.Version, i.Commit, i.Date, i.GoVersion, i.Platform)
+}
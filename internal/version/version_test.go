@@ -0,0 +1,99 @@
+package version
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestIsNewer(t *testing.T) {
+	tests := []struct {
+		latest, current string
+		want            bool
+	}{
+		{"v1.3.0", "v1.2.9", true},
+		{"v1.2.10", "v1.2.9", true},
+		{"v2.0.0", "1.9.9", true},
+		{"v1.2.3", "v1.2.3", false},
+		{"v1.2.3", "v1.3.0", false},
+		{"v1.2.3", "v1.2.3-rc1", false},
+		{"v1.2.3", "devel", false},
+		{"nightly", "v1.0.0", false},
+	}
+
+	for _, tt := range tests {
+		if got := IsNewer(tt.latest, tt.current); got != tt.want {
+			t.Errorf("IsNewer(%q, %q) = %v, want %v", tt.latest, tt.current, got, tt.want)
+		}
+	}
+}
+
+func TestGetFallsBackToDevel(t *testing.T) {
+	info := Get()
+	if info.Version != "devel" {
+		t.Errorf("Version = %q, want devel without ldflags", info.Version)
+	}
+	if info.GoVersion == "" || info.Commit == "" || info.Date == "" {
+		t.Errorf("Expected every field to be set, got %+v", info)
+	}
+	if !strings.HasPrefix(info.String(), "gosynctasks devel (commit ") {
+		t.Errorf("String() = %q", info.String())
+	}
+}
+
+func TestUpdateCheckerCachesForADay(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write([]byte(`{"tag_name": "v1.4.0", "html_url": "https://example.com/v1.4.0"}`))
+	}))
+	defer server.Close()
+
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	checker := &UpdateChecker{
+		ReleasesURL: server.URL,
+		CachePath:   filepath.Join(t.TempDir(), "update-check.json"),
+		Client:      server.Client(),
+		Now:         func() time.Time { return now },
+	}
+
+	status, err := checker.Check("v1.3.2")
+	if err != nil {
+		t.Fatalf("Check failed: %v", err)
+	}
+	if !status.Newer || status.Cached || status.Latest.Version != "v1.4.0" {
+		t.Errorf("Unexpected first status: %+v", status)
+	}
+
+	now = now.Add(23 * time.Hour)
+	status, err = checker.Check("v1.4.0")
+	if err != nil {
+		t.Fatalf("Check failed: %v", err)
+	}
+	if status.Newer || !status.Cached || requests != 1 {
+		t.Errorf("Expected cached up-to-date result after 23h, got %+v (%d requests)", status, requests)
+	}
+
+	now = now.Add(2 * time.Hour)
+	if _, err := checker.Check("v1.4.0"); err != nil {
+		t.Fatalf("Check failed: %v", err)
+	}
+	if requests != 2 {
+		t.Errorf("Expected a new lookup once the cache is a day old, got %d requests", requests)
+	}
+}
+
+func TestUpdateCheckerReportsHTTPErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	checker := &UpdateChecker{ReleasesURL: server.URL, Client: server.Client(), Now: time.Now}
+	if _, err := checker.Check("v1.0.0"); err == nil || !strings.Contains(err.Error(), "403") {
+		t.Errorf("Expected a 403 error, got %v", err)
+	}
+}
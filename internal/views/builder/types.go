@@ -116,9 +116,7 @@ type ViewBuilder struct {
 func NewViewBuilder(name string) *ViewBuilder {
 	// Initialize available fields from field registry
 	// This ensures single source of truth and maintains consistency
-	fieldOrder := []string{"status", "summary", "description", "priority",
-		"due_date", "start_date", "created", "modified", "completed",
-		"tags", "uid", "parent"}
+	fieldOrder := views.FieldNames()
 
 	availableFields := make([]FieldItem, 0, len(fieldOrder))
 
@@ -1,82 +1,237 @@
 package views
 
-// FieldRegistry maps field names to their definitions
-var FieldRegistry = map[string]FieldDefinition{
-	"status": {
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"gosynctasks/backend"
+)
+
+// fieldDefinitions are the task fields, in their canonical order. Views,
+// the view builder, sorting and --fields all take their names from here.
+var fieldDefinitions = []FieldDefinition{
+	{
 		Name:          "status",
 		Description:   "Task completion status",
 		Formats:       []string{"symbol", "text", "emoji", "short"},
 		DefaultFormat: "symbol",
+		Sortable:      true,
+		Value:         func(t backend.Task) any { return t.Status },
 	},
-	"summary": {
+	{
 		Name:          "summary",
 		Description:   "Task title/summary",
 		Formats:       []string{"full", "truncate"},
 		DefaultFormat: "full",
+		Sortable:      true,
+		Value:         func(t backend.Task) any { return t.Summary },
 	},
-	"description": {
+	{
 		Name:          "description",
 		Description:   "Task detailed description",
 		Formats:       []string{"full", "truncate", "first_line"},
 		DefaultFormat: "truncate",
+		Value:         func(t backend.Task) any { return t.Description },
 	},
-	"priority": {
+	{
 		Name:            "priority",
 		Description:     "Task priority (0-9)",
-		Formats:         []string{"number", "text", "stars", "color"},
+		Formats:         []string{"number", "text", "stars", "color", "badge", "bang"},
 		DefaultFormat:   "number",
 		RequiresBackend: true, // For priority color
+		Sortable:        true,
+		Value:           func(t backend.Task) any { return t.Priority },
 	},
-	"due_date": {
+	{
 		Name:          "due_date",
 		Description:   "Task due date",
-		Formats:       []string{"full", "relative", "short"},
+		Formats:       []string{"full", "relative", "short", "urgency"},
 		DefaultFormat: "full",
+		Sortable:      true,
+		Value:         func(t backend.Task) any { return timeValue(t.DueDate) },
 	},
-	"start_date": {
+	{
 		Name:          "start_date",
 		Description:   "Task start date",
 		Formats:       []string{"full", "relative", "short"},
 		DefaultFormat: "full",
+		Sortable:      true,
+		Value:         func(t backend.Task) any { return timeValue(t.StartDate) },
 	},
-	"created": {
+	{
 		Name:          "created",
 		Description:   "Task creation timestamp",
 		Formats:       []string{"full", "relative", "date_only"},
 		DefaultFormat: "full",
+		Sortable:      true,
+		Value:         func(t backend.Task) any { return timeValue(&t.Created) },
 	},
-	"modified": {
+	{
 		Name:          "modified",
 		Description:   "Task last modified timestamp",
 		Formats:       []string{"full", "relative", "date_only"},
 		DefaultFormat: "full",
+		Sortable:      true,
+		Value:         func(t backend.Task) any { return timeValue(&t.Modified) },
 	},
-	"completed": {
+	{
 		Name:          "completed",
 		Description:   "Task completion timestamp",
 		Formats:       []string{"full", "relative", "date_only", "short"},
 		DefaultFormat: "full",
+		Value:         func(t backend.Task) any { return timeValue(t.Completed) },
 	},
-	"tags": {
+	{
 		Name:          "tags",
 		Description:   "Task categories/labels",
 		Formats:       []string{"list", "comma", "hash"},
 		DefaultFormat: "comma",
+		Value:         func(t backend.Task) any { return tagsValue(t.Categories) },
 	},
-	"uid": {
+	{
 		Name:          "uid",
 		Description:   "Unique task identifier",
 		Formats:       []string{"full", "short"},
 		DefaultFormat: "short",
+		Value:         func(t backend.Task) any { return t.UID },
 	},
-	"parent": {
+	{
 		Name:          "parent",
 		Description:   "Parent task UID (for subtasks)",
 		Formats:       []string{"full", "short"},
 		DefaultFormat: "short",
+		Value:         func(t backend.Task) any { return t.ParentUID },
 	},
 }
 
+// FieldRegistry maps field names to their definitions
+var FieldRegistry = indexFields(fieldDefinitions)
+
+func indexFields(definitions []FieldDefinition) map[string]FieldDefinition {
+	registry := make(map[string]FieldDefinition, len(definitions))
+	for _, def := range definitions {
+		registry[def.Name] = def
+	}
+	return registry
+}
+
+// FieldNames returns the names of all fields in canonical order
+func FieldNames() []string {
+	names := make([]string, len(fieldDefinitions))
+	for i, def := range fieldDefinitions {
+		names[i] = def.Name
+	}
+	return names
+}
+
+// SortFieldNames returns the names of the fields tasks can be sorted by
+func SortFieldNames() []string {
+	var names []string
+	for _, def := range fieldDefinitions {
+		if def.Sortable {
+			names = append(names, def.Name)
+		}
+	}
+	return names
+}
+
+// ParseFieldList parses a comma-separated list of field names, as given to
+// --fields, keeping its order. Unknown names are an error suggesting the
+// closest known field.
+func ParseFieldList(spec string) ([]string, error) {
+	var names []string
+	for _, name := range strings.Split(spec, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		if _, ok := FieldRegistry[name]; !ok {
+			return nil, unknownFieldError(name)
+		}
+		if !slices.Contains(names, name) {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return nil, &ValidationError{Field: "fields", Message: "no fields given", Hint: "Valid fields: " + strings.Join(FieldNames(), ", ")}
+	}
+	return names, nil
+}
+
+// unknownFieldError reports a field name missing from the registry
+func unknownFieldError(name string) *ValidationError {
+	hint := "Valid fields: " + strings.Join(FieldNames(), ", ")
+	if suggestion := closestField(name); suggestion != "" {
+		hint = fmt.Sprintf("Did you mean '%s'? %s", suggestion, hint)
+	}
+	return &ValidationError{
+		Field:   "name",
+		Message: fmt.Sprintf("unknown field '%s'", name),
+		Value:   name,
+		Hint:    hint,
+	}
+}
+
+// closestField returns the field name within two edits of name, or one
+// name contains, if there is one
+func closestField(name string) string {
+	best, bestDistance := "", 3
+	for _, field := range FieldNames() {
+		distance := editDistance(name, field)
+		if distance < bestDistance {
+			best, bestDistance = field, distance
+		}
+	}
+	if best != "" {
+		return best
+	}
+	for _, field := range FieldNames() {
+		if len(name) >= 3 && (strings.Contains(field, name) || strings.Contains(name, field)) {
+			return field
+		}
+	}
+	return ""
+}
+
+// editDistance returns the Levenshtein distance between a and b
+func editDistance(a, b string) int {
+	previous := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current := make([]int, len(b)+1)
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous = current
+	}
+	return previous[len(b)]
+}
+
+// timeValue is the machine-readable value of a date field: RFC 3339, or nil when unset
+func timeValue(t *time.Time) any {
+	if t == nil || t.IsZero() {
+		return nil
+	}
+	return t.Format(time.RFC3339)
+}
+
+// tagsValue is the machine-readable value of the tags field, never nil
+func tagsValue(tags []string) []string {
+	if tags == nil {
+		return []string{}
+	}
+	return tags
+}
+
 // GetFieldDefinition returns the definition for a field name
 func GetFieldDefinition(name string) (FieldDefinition, bool) {
 	def, ok := FieldRegistry[name]
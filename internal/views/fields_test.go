@@ -1,6 +1,11 @@
 package views
 
-import "testing"
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+)
 
 func TestGetFieldDefinition(t *testing.T) {
 	tests := []struct {
@@ -11,8 +16,8 @@ func TestGetFieldDefinition(t *testing.T) {
 	}{
 		{"status field", "status", true, 4},
 		{"summary field", "summary", true, 2},
-		{"priority field", "priority", true, 4},
-		{"due_date field", "due_date", true, 3},
+		{"priority field", "priority", true, 6},
+		{"due_date field", "due_date", true, 4},
 		{"invalid field", "nonexistent", false, 0},
 	}
 
@@ -162,3 +167,47 @@ func TestRequiredFields(t *testing.T) {
 		}
 	}
 }
+
+func TestParseFieldList(t *testing.T) {
+	tests := []struct {
+		spec    string
+		want    []string
+		wantErr string
+	}{
+		{spec: "uid,summary,status,due_date", want: []string{"uid", "summary", "status", "due_date"}},
+		{spec: " Due_Date , tags,due_date", want: []string{"due_date", "tags"}},
+		{spec: "summary,priorty", wantErr: "Did you mean 'priority'?"},
+		{spec: "summary,tag", wantErr: "Did you mean 'tags'?"},
+		{spec: "categories", wantErr: "Valid fields: status, summary"},
+		{spec: " , ", wantErr: "no fields given"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			got, err := ParseFieldList(tt.spec)
+			if tt.wantErr != "" {
+				var validationErr *ValidationError
+				if !errors.As(err, &validationErr) || !strings.Contains(validationErr.Message+" "+validationErr.Hint, tt.wantErr) {
+					t.Fatalf("ParseFieldList(%q) error = %#v, want %q", tt.spec, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil || !slices.Equal(got, tt.want) {
+				t.Errorf("ParseFieldList(%q) = %v, %v, want %v", tt.spec, got, err, tt.want)
+			}
+		})
+	}
+}
+
+// TestFieldRegistryIsComplete tests every field has what views, sorting and --fields need
+func TestFieldRegistryIsComplete(t *testing.T) {
+	for _, name := range FieldNames() {
+		def := FieldRegistry[name]
+		if def.Value == nil || def.DefaultFormat == "" {
+			t.Errorf("field %s is missing a value or default format", name)
+		}
+	}
+	if got := SortFieldNames(); !slices.Equal(got, []string{"status", "summary", "priority", "due_date", "start_date", "created", "modified"}) {
+		t.Errorf("SortFieldNames() = %v", got)
+	}
+}
@@ -2,6 +2,7 @@ package views
 
 import (
 	"gosynctasks/backend"
+	"gosynctasks/internal/testutil"
 	"testing"
 	"time"
 )
@@ -233,6 +234,34 @@ func TestApplySort_DueDate(t *testing.T) {
 	}
 }
 
+func TestApplyFiltersAndSort_GeneratedDataset(t *testing.T) {
+	dataset := testutil.GenerateDataset(testutil.DatasetOptions{Tasks: 500, Lists: 3, HierarchyDepth: 2, Seed: 7})
+	tasks := dataset.AllTasks()
+	now := testutil.ReferenceTime
+
+	want := 0
+	for _, task := range tasks {
+		if task.DueDate != nil && task.DueDate.Before(now) {
+			want++
+		}
+	}
+	result := ApplyFilters(tasks, &ViewFilters{DueBefore: &now})
+	if len(result) != want || want == 0 {
+		t.Errorf("Expected %d overdue tasks, got %d", want, len(result))
+	}
+
+	ApplySort(tasks, "due_date", "asc")
+	for i := 1; i < len(tasks); i++ {
+		prev, cur := tasks[i-1].DueDate, tasks[i].DueDate
+		if prev == nil && cur != nil {
+			t.Fatalf("Task %s without due date sorted before %s", tasks[i-1].UID, tasks[i].UID)
+		}
+		if prev != nil && cur != nil && cur.Before(*prev) {
+			t.Fatalf("Task %s sorted after later task %s", tasks[i].UID, tasks[i-1].UID)
+		}
+	}
+}
+
 func TestApplySort_NoSort(t *testing.T) {
 	tasks := []backend.Task{
 		{UID: "1", Summary: "Task 1"},
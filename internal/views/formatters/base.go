@@ -2,6 +2,8 @@ package formatters
 
 import (
 	"gosynctasks/backend"
+	"gosynctasks/internal/utils"
+	"os"
 	"time"
 )
 
@@ -16,11 +18,18 @@ type FormatContext struct {
 	// DateFormat is the Go time format string for date display
 	DateFormat string
 
+	// Locale supplies weekday/month names and relative date words
+	Locale *utils.Locale
+
 	// Backend provides backend-specific functionality (e.g., priority colors)
 	Backend backend.TaskManager
 
 	// Now is the current time (useful for relative date calculations)
 	Now time.Time
+
+	// NoColorSemantics replaces meaning carried only by color (due date urgency,
+	// priority level) with text, and disables color. Defaults to on when NO_COLOR is set.
+	NoColorSemantics bool
 }
 
 // NewFormatContext creates a new format context with default values
@@ -30,8 +39,11 @@ func NewFormatContext(backend backend.TaskManager, dateFormat string) *FormatCon
 	}
 
 	return &FormatContext{
-		DateFormat: dateFormat,
+		DateFormat: utils.ResolveDateFormat(dateFormat),
+		Locale:     utils.ActiveLocale(),
 		Backend:    backend,
 		Now:        time.Now(),
+
+		NoColorSemantics: os.Getenv("NO_COLOR") != "",
 	}
 }
@@ -3,6 +3,7 @@ package formatters
 import (
 	"fmt"
 	"gosynctasks/backend"
+	"gosynctasks/internal/utils"
 	"time"
 )
 
@@ -21,26 +22,40 @@ func NewDateFormatter(ctx *FormatContext, fieldName string) *DateFormatter {
 }
 
 // Format formats the date field according to the specified format
-// Supported formats: full, relative, short, date_only
+// Supported formats: full, relative, short, date_only, urgency
 func (f *DateFormatter) Format(task backend.Task, format string, width int, colorize bool) string {
 	date := f.getDate(task)
 	if date == nil {
 		return ""
 	}
 
+	if f.ctx.NoColorSemantics {
+		colorize = false
+	}
+
 	var result string
+	layout := f.layout(task)
 
 	switch format {
 	case "full":
-		result = f.formatFull(*date, colorize)
+		result = f.formatFull(*date, layout, colorize)
 	case "relative":
 		result = f.formatRelative(*date, colorize)
 	case "short":
-		result = f.formatShort(*date, colorize)
+		result = f.formatShort(*date, layout, colorize)
 	case "date_only":
 		result = f.formatDateOnly(*date, colorize)
+	case "urgency":
+		result = f.formatFull(*date, layout, colorize)
 	default:
-		result = f.formatFull(*date, colorize)
+		result = f.formatFull(*date, layout, colorize)
+	}
+
+	// Spell out urgency when asked to, or when color can't carry it
+	if format == "urgency" || f.ctx.NoColorSemantics {
+		if word := f.urgencyWord(task, *date); word != "" {
+			result += " " + word
+		}
 	}
 
 	return truncate(result, width)
@@ -64,15 +79,28 @@ func (f *DateFormatter) getDate(task backend.Task) *time.Time {
 		}
 		return &task.Modified
 	case "completed":
+		// A completion date is only meaningful while the task is done
+		if !backend.IsCompletedStatus(task.Status) {
+			return nil
+		}
 		return task.Completed
 	default:
 		return nil
 	}
 }
 
+// layout returns the date format for the task: all-day tasks have no time
+// of day to show on their due and start dates
+func (f *DateFormatter) layout(task backend.Task) string {
+	if task.AllDay && (f.fieldName == "due_date" || f.fieldName == "start_date") {
+		return utils.DateOnlyLayout(f.ctx.DateFormat)
+	}
+	return f.ctx.DateFormat
+}
+
 // formatFull returns full date with color coding based on date type
-func (f *DateFormatter) formatFull(date time.Time, colorize bool) string {
-	dateStr := date.Format(f.ctx.DateFormat)
+func (f *DateFormatter) formatFull(date time.Time, layout string, colorize bool) string {
+	dateStr := f.locale().Format(date, layout)
 
 	if !colorize {
 		return dateStr
@@ -92,14 +120,14 @@ func (f *DateFormatter) formatRelative(date time.Time, colorize bool) string {
 	duration := f.ctx.Now.Sub(date)
 
 	var result string
+	locale := f.locale()
 
 	if duration < 0 {
 		// Future date
-		duration = -duration
-		result = f.humanizeDuration(duration, "in ")
+		result = fmt.Sprintf(locale.RelativeFuture, f.humanizeDuration(-duration))
 	} else {
 		// Past date
-		result = f.humanizeDuration(duration, "") + " ago"
+		result = fmt.Sprintf(locale.RelativePast, f.humanizeDuration(duration))
 	}
 
 	if !colorize {
@@ -115,10 +143,10 @@ func (f *DateFormatter) formatRelative(date time.Time, colorize bool) string {
 }
 
 // formatShort returns short date format (e.g., "01/15", "Jan 15")
-func (f *DateFormatter) formatShort(date time.Time, colorize bool) string {
+func (f *DateFormatter) formatShort(date time.Time, layout string, colorize bool) string {
 	// Use short format from context or default
 	shortFormat := "01/02"
-	if f.ctx.DateFormat == "2006-01-02 15:04" {
+	if layout == "2006-01-02 15:04" {
 		shortFormat = "01/02 15:04"
 	}
 
@@ -164,14 +192,32 @@ func (f *DateFormatter) getDateColor(date time.Time) string {
 	}
 }
 
+// urgencyWord returns OVERDUE, TODAY or TOMORROW for open tasks' due dates,
+// using the same buckets as getDueDateColor
+func (f *DateFormatter) urgencyWord(task backend.Task, date time.Time) string {
+	if f.fieldName != "due_date" || backend.IsCompletedStatus(task.Status) || task.Status == "CANCELLED" {
+		return ""
+	}
+	switch f.dueUrgency(date) {
+	case utils.DueOverdue:
+		return "OVERDUE"
+	case utils.DueToday:
+		return "TODAY"
+	case utils.DueTomorrow:
+		return "TOMORROW"
+	}
+	return ""
+}
+
 // getDueDateColor returns color for due dates
 func (f *DateFormatter) getDueDateColor(date time.Time) string {
-	if date.Before(f.ctx.Now) {
-		return "\033[31m" // Red (overdue)
-	} else if date.Sub(f.ctx.Now).Hours() < 24 {
-		return "\033[33m" // Yellow (due soon)
-	}
-	return "\033[90m" // Gray (future)
+	return f.dueUrgency(date).Color()
+}
+
+// dueUrgency returns the calendar-day urgency of a due date, weeks starting
+// on the locale's first weekday
+func (f *DateFormatter) dueUrgency(date time.Time) utils.DueUrgency {
+	return utils.ClassifyDue(date, f.ctx.Now, f.locale().FirstWeekday)
 }
 
 // getStartDateColor returns color for start dates
@@ -186,8 +232,8 @@ func (f *DateFormatter) getStartDateColor(date time.Time) string {
 	return "\033[90m" // Gray (future)
 }
 
-// humanizeDuration converts duration to human-readable format
-func (f *DateFormatter) humanizeDuration(d time.Duration, prefix string) string {
+// humanizeDuration converts duration to human-readable format using the locale's unit suffixes
+func (f *DateFormatter) humanizeDuration(d time.Duration) string {
 	seconds := int(d.Seconds())
 	minutes := seconds / 60
 	hours := minutes / 60
@@ -196,18 +242,19 @@ func (f *DateFormatter) humanizeDuration(d time.Duration, prefix string) string
 	months := days / 30
 	years := days / 365
 
-	if years > 0 {
-		return fmt.Sprintf("%s%dy", prefix, years)
-	} else if months > 0 {
-		return fmt.Sprintf("%s%dmo", prefix, months)
-	} else if weeks > 0 {
-		return fmt.Sprintf("%s%dw", prefix, weeks)
-	} else if days > 0 {
-		return fmt.Sprintf("%s%dd", prefix, days)
-	} else if hours > 0 {
-		return fmt.Sprintf("%s%dh", prefix, hours)
-	} else if minutes > 0 {
-		return fmt.Sprintf("%s%dm", prefix, minutes)
-	}
-	return fmt.Sprintf("%s%ds", prefix, seconds)
+	units := f.locale().Units
+	for i, n := range []int{years, months, weeks, days, hours, minutes} {
+		if n > 0 {
+			return fmt.Sprintf("%d%s", n, units[i])
+		}
+	}
+	return fmt.Sprintf("%d%s", seconds, units[6])
+}
+
+// locale returns the context's locale, or the active one if unset
+func (f *DateFormatter) locale() *utils.Locale {
+	if f.ctx.Locale != nil {
+		return f.ctx.Locale
+	}
+	return utils.ActiveLocale()
 }
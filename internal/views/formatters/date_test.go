@@ -0,0 +1,104 @@
+package formatters
+
+import (
+	"gosynctasks/backend"
+	"gosynctasks/internal/utils"
+	"testing"
+	"time"
+)
+
+func TestDateFormatterLocalized(t *testing.T) {
+	now := time.Date(2026, 7, 8, 12, 0, 0, 0, time.Local)
+	due := now.Add(3 * 24 * time.Hour)
+	past := now.Add(-2 * time.Hour)
+	task := backend.Task{Status: "NEEDS-ACTION", DueDate: &due, StartDate: &past}
+
+	tests := []struct {
+		locale     string
+		dateFormat string
+		field      string
+		format     string
+		want       string
+	}{
+		{locale: "en", dateFormat: "iso", field: "due_date", format: "full", want: "2026-07-11"},
+		{locale: "en", dateFormat: "eu", field: "due_date", format: "full", want: "11.07.2026"},
+		{locale: "en", dateFormat: "Mon 02.01.", field: "due_date", format: "full", want: "Sat 11.07."},
+		{locale: "de", dateFormat: "Mon 02.01.", field: "due_date", format: "full", want: "Sa 11.07."},
+		{locale: "en", dateFormat: "iso", field: "due_date", format: "relative", want: "in 3d"},
+		{locale: "en", dateFormat: "iso", field: "start_date", format: "relative", want: "2h ago"},
+		{locale: "de", dateFormat: "iso", field: "due_date", format: "relative", want: "in 3T"},
+		{locale: "de", dateFormat: "iso", field: "start_date", format: "relative", want: "vor 2Std"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.locale+"/"+tt.dateFormat+"/"+tt.format, func(t *testing.T) {
+			ctx := NewFormatContext(nil, tt.dateFormat)
+			ctx.Now = now
+			ctx.Locale = utils.GetLocale(tt.locale)
+			ctx.NoColorSemantics = false
+
+			got := NewDateFormatter(ctx, tt.field).Format(task, tt.format, 0, false)
+			if got != tt.want {
+				t.Errorf("Format() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestDateFormatterUrgency tests that due dates are marked by calendar day in
+// the formatter's locale: Saturday is this week for a Monday week only
+func TestDateFormatterUrgency(t *testing.T) {
+	now := time.Date(2026, 7, 8, 23, 0, 0, 0, time.Local) // Wednesday
+
+	tests := []struct {
+		locale    string
+		due       time.Time
+		wantWord  string
+		wantColor string
+	}{
+		{locale: "en", due: now.Add(-time.Minute), wantWord: " OVERDUE", wantColor: "\033[31m"},
+		{locale: "en", due: time.Date(2026, 7, 8, 0, 0, 0, 0, time.Local), wantWord: " TODAY", wantColor: "\033[1;93m"},
+		{locale: "en", due: now.Add(2 * time.Hour), wantWord: " TOMORROW", wantColor: "\033[33m"},
+		{locale: "en", due: time.Date(2026, 7, 11, 9, 0, 0, 0, time.Local), wantColor: "\033[37m"},
+		{locale: "en", due: time.Date(2026, 7, 12, 9, 0, 0, 0, time.Local), wantColor: "\033[90m"},
+		{locale: "de", due: time.Date(2026, 7, 12, 9, 0, 0, 0, time.Local), wantColor: "\033[37m"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.locale+"/"+tt.due.Format("2006-01-02 15:04"), func(t *testing.T) {
+			ctx := NewFormatContext(nil, "iso")
+			ctx.Now = now
+			ctx.Locale = utils.GetLocale(tt.locale)
+			ctx.NoColorSemantics = false
+			task := backend.Task{Status: "NEEDS-ACTION", DueDate: &tt.due}
+			formatter := NewDateFormatter(ctx, "due_date")
+
+			if got := formatter.Format(task, "urgency", 0, false); got != tt.due.Format("2006-01-02")+tt.wantWord {
+				t.Errorf("Format(urgency) = %q, want the date followed by %q", got, tt.wantWord)
+			}
+			if got := formatter.Format(task, "full", 0, true); got != tt.wantColor+tt.due.Format("2006-01-02")+"\033[0m" {
+				t.Errorf("Format(full) = %q, want color %q", got, tt.wantColor)
+			}
+		})
+	}
+}
+
+// TestDateFormatterAllDay tests that all-day due and start dates are shown
+// without a time of day, and other dates still with one
+func TestDateFormatterAllDay(t *testing.T) {
+	due := time.Date(2026, 7, 11, 0, 0, 0, 0, time.Local)
+	created := time.Date(2026, 7, 8, 9, 30, 0, 0, time.Local)
+	task := backend.Task{Status: "NEEDS-ACTION", DueDate: &due, Created: created, AllDay: true}
+	ctx := NewFormatContext(nil, "2006-01-02 15:04")
+	ctx.Now = created
+
+	if got := NewDateFormatter(ctx, "due_date").Format(task, "full", 0, false); got != "2026-07-11" {
+		t.Errorf("all-day due date = %q, want 2026-07-11", got)
+	}
+	if got := NewDateFormatter(ctx, "due_date").Format(task, "short", 0, false); got != "07/11" {
+		t.Errorf("all-day short due date = %q, want 07/11", got)
+	}
+	if got := NewDateFormatter(ctx, "created").Format(task, "full", 0, false); got != "2026-07-08 09:30" {
+		t.Errorf("created date = %q, want 2026-07-08 09:30", got)
+	}
+}
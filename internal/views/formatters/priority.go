@@ -17,10 +17,14 @@ func NewPriorityFormatter(ctx *FormatContext) *PriorityFormatter {
 }
 
 // Format formats the priority field according to the specified format
-// Supported formats: number, text, stars, color
+// Supported formats: number, text, stars, color, badge, bang
 func (f *PriorityFormatter) Format(task backend.Task, format string, width int, colorize bool) string {
 	var result string
 
+	if f.ctx.NoColorSemantics {
+		colorize = false
+	}
+
 	switch format {
 	case "number":
 		result = f.formatNumber(task.Priority, colorize)
@@ -30,6 +34,10 @@ func (f *PriorityFormatter) Format(task backend.Task, format string, width int,
 		result = f.formatStars(task.Priority, colorize)
 	case "color":
 		result = f.formatColorBar(task.Priority)
+	case "badge":
+		result = f.formatBadge(task.Priority)
+	case "bang":
+		result = f.formatBang(task.Priority)
 	default:
 		result = f.formatNumber(task.Priority, colorize)
 	}
@@ -117,9 +125,33 @@ func (f *PriorityFormatter) formatStars(priority int, colorize bool) string {
 	return stars
 }
 
+// formatBadge returns priority as a textual badge ("!1".."!9")
+func (f *PriorityFormatter) formatBadge(priority int) string {
+	if priority == 0 {
+		return ""
+	}
+	return fmt.Sprintf("!%d", priority)
+}
+
+// formatBang returns priority as bang buckets: !!! high, !! medium, ! low
+func (f *PriorityFormatter) formatBang(priority int) string {
+	switch {
+	case priority >= 1 && priority <= 3:
+		return "!!!"
+	case priority >= 4 && priority <= 6:
+		return "!!"
+	case priority >= 7 && priority <= 9:
+		return "!"
+	}
+	return ""
+}
+
 // formatColorBar returns priority as a colored bar
 func (f *PriorityFormatter) formatColorBar(priority int) string {
 	if priority == 0 {
+		if f.ctx.NoColorSemantics {
+			return "━"
+		}
 		return "\033[90m━\033[0m" // Gray bar for no priority
 	}
 
@@ -142,5 +174,8 @@ func (f *PriorityFormatter) formatColorBar(priority int) string {
 	}
 
 	bar := strings.Repeat("█", barLength)
+	if f.ctx.NoColorSemantics {
+		return bar
+	}
 	return color + bar + "\033[0m"
 }
@@ -20,6 +20,10 @@ func NewStatusFormatter(ctx *FormatContext) *StatusFormatter {
 func (f *StatusFormatter) Format(task backend.Task, format string, width int, color bool) string {
 	var result string
 
+	if f.ctx.NoColorSemantics {
+		color = false
+	}
+
 	switch format {
 	case "symbol":
 		result = f.formatSymbol(task.Status, color)
@@ -1,7 +1,9 @@
 package formatters
 
 import (
+	"fmt"
 	"gosynctasks/backend"
+	"gosynctasks/internal/utils"
 	"strings"
 )
 
@@ -18,7 +20,18 @@ func NewSummaryFormatter(ctx *FormatContext) *SummaryFormatter {
 // Format formats the summary field according to the specified format
 // Supported formats: full, truncate
 func (f *SummaryFormatter) Format(task backend.Task, format string, width int, colorize bool) string {
-	summary := task.Summary
+	summary := utils.SanitizeLine(task.Summary)
+
+	// Priority would be shown by color alone: show it as a badge instead
+	if colorize && f.ctx.NoColorSemantics {
+		if format == "truncate" && width > 0 {
+			summary = truncate(summary, width)
+		}
+		if task.Priority > 0 {
+			summary += fmt.Sprintf(" !%d", task.Priority)
+		}
+		return summary
+	}
 
 	// Apply priority color if enabled
 	if colorize && task.Priority > 0 && f.ctx.Backend != nil {
@@ -54,16 +67,17 @@ func (f *DescriptionFormatter) Format(task backend.Task, format string, width in
 	}
 
 	var result string
+	description := utils.SanitizeText(task.Description)
 
 	switch format {
 	case "full":
-		result = task.Description
+		result = description
 	case "truncate":
-		result = f.formatTruncate(task.Description, width)
+		result = f.formatTruncate(description, width)
 	case "first_line":
-		result = f.formatFirstLine(task.Description, width)
+		result = f.formatFirstLine(description, width)
 	default:
-		result = f.formatTruncate(task.Description, width)
+		result = f.formatTruncate(description, width)
 	}
 
 	// Description is typically shown in dim gray
@@ -141,6 +155,7 @@ func (f *TagsFormatter) Format(task backend.Task, format string, width int, colo
 	default:
 		result = f.formatComma(task.Categories)
 	}
+	result = utils.SanitizeLine(result)
 
 	if width > 0 && len(result) > width {
 		if width > 3 {
@@ -215,6 +230,7 @@ func (f *UIDFormatter) Format(task backend.Task, format string, width int, color
 	default:
 		result = f.formatShort(task.UID)
 	}
+	result = utils.SanitizeLine(result)
 
 	if width > 0 && len(result) > width {
 		result = result[:width]
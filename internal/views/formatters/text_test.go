@@ -0,0 +1,39 @@
+package formatters
+
+import (
+	"strings"
+	"testing"
+
+	"gosynctasks/backend"
+)
+
+func TestTextFormattersEscapeControlCharacters(t *testing.T) {
+	ctx := NewFormatContext(nil, "")
+	task := backend.Task{
+		UID:         "uid\x1b]0;x\a",
+		Summary:     "Pay \x1b[31mrent\x07\tnow\u200b",
+		Description: "line one\x1b[2J\nline\ttwo",
+		Categories:  []string{"work\x1b[0m"},
+	}
+
+	outputs := map[string]string{
+		"summary":     NewSummaryFormatter(ctx).Format(task, "full", 0, false),
+		"description": NewDescriptionFormatter(ctx).Format(task, "full", 0, false),
+		"first_line":  NewDescriptionFormatter(ctx).Format(task, "first_line", 0, false),
+		"tags":        NewTagsFormatter(ctx).Format(task, "comma", 0, false),
+		"uid":         NewUIDFormatter(ctx).Format(task, "full", 0, false),
+	}
+
+	for name, out := range outputs {
+		if strings.ContainsAny(out, "\x1b\x07\t\u200b") {
+			t.Errorf("%s output contains raw control characters: %q", name, out)
+		}
+	}
+
+	if want := `Pay \x1b[31mrent\x07    now\u200b`; outputs["summary"] != want {
+		t.Errorf("summary = %q, want %q", outputs["summary"], want)
+	}
+	if want := "line one\\x1b[2J\nline    two"; outputs["description"] != want {
+		t.Errorf("description = %q, want %q", outputs["description"], want)
+	}
+}
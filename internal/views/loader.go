@@ -24,6 +24,12 @@ func init() {
 		}
 		return true
 	})
+
+	// Field names come from the field registry
+	_ = validate.RegisterValidation("task_field", func(fl validator.FieldLevel) bool {
+		_, ok := FieldRegistry[fl.Field().String()]
+		return ok
+	})
 }
 
 // LoadView loads a view configuration from a YAML file
@@ -163,6 +169,8 @@ func formatValidationError(err error) error {
 				return fmt.Errorf("field '%s' must have at most %s items/characters", e.Field(), e.Param())
 			case "oneof":
 				return fmt.Errorf("field '%s' must be one of: %s", e.Field(), e.Param())
+			case "task_field":
+				return unknownFieldError(fmt.Sprint(e.Value()))
 			case "alphanum_underscore":
 				return fmt.Errorf("field '%s' must contain only letters, numbers, underscores, and hyphens", e.Field())
 			}
@@ -21,6 +21,9 @@ func NewViewRenderer(view *View, backend backend.TaskManager, dateFormat string)
 	}
 
 	ctx := formatters.NewFormatContext(backend, dateFormat)
+	if view.Display.NoColorSemantics {
+		ctx.NoColorSemantics = true
+	}
 
 	renderer := &ViewRenderer{
 		view:   view,
@@ -34,6 +37,14 @@ func NewViewRenderer(view *View, backend backend.TaskManager, dateFormat string)
 	return renderer
 }
 
+// SetNoColorSemantics turns on textual urgency and priority markers in place of color.
+// It can only enable the mode; NO_COLOR in the environment already enables it.
+func (r *ViewRenderer) SetNoColorSemantics(enabled bool) {
+	if enabled {
+		r.ctx.NoColorSemantics = true
+	}
+}
+
 // initializeFormatters creates formatter instances for all fields in the view
 func (r *ViewRenderer) initializeFormatters() {
 	for _, field := range r.view.Fields {
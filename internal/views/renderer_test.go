@@ -2,6 +2,8 @@ package views
 
 import (
 	"gosynctasks/backend"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
@@ -221,3 +223,111 @@ func TestViewRenderer_HiddenFields(t *testing.T) {
 		t.Errorf("Hidden field appeared in output: %s", result)
 	}
 }
+
+func TestViewRenderer_AllViewShowsCompletionDate(t *testing.T) {
+	view, err := getBuiltInView("all")
+	if err != nil {
+		t.Fatalf("Failed to load built-in view: %v", err)
+	}
+
+	renderer := NewViewRenderer(view, nil, "")
+	completed := time.Date(2024, 6, 21, 15, 30, 0, 0, time.Local)
+
+	done := backend.Task{UID: "1", Summary: "Done task", Status: "COMPLETED", Completed: &completed}
+	if result := renderer.RenderTask(done); !strings.Contains(result, "done: 2024-06-21") {
+		t.Errorf("Expected completion date for done task, got: %s", result)
+	}
+
+	// A reopened task may still carry a stale completion time
+	reopened := backend.Task{UID: "2", Summary: "Reopened task", Status: "NEEDS-ACTION", Completed: &completed}
+	if result := renderer.RenderTask(reopened); strings.Contains(result, "done:") {
+		t.Errorf("Expected no completion date for open task, got: %s", result)
+	}
+}
+
+// goldenTasks covers each urgency and priority bucket relative to goldenNow
+var goldenNow = time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+
+func goldenTasks() []backend.Task {
+	overdue := goldenNow.Add(-48 * time.Hour)
+	soon := goldenNow.Add(6 * time.Hour)
+	later := goldenNow.Add(10 * 24 * time.Hour)
+	return []backend.Task{
+		{UID: "1", Summary: "Pay invoice", Status: "NEEDS-ACTION", Priority: 1, DueDate: &overdue},
+		{UID: "2", Summary: "Call back", Status: "IN-PROCESS", Priority: 5, DueDate: &soon},
+		{UID: "3", Summary: "Plan trip", Status: "NEEDS-ACTION", Priority: 9, DueDate: &later},
+		{UID: "4", Summary: "Old report", Status: "COMPLETED", DueDate: &overdue},
+		{UID: "5", Summary: "Someday", Status: "CANCELLED"},
+	}
+}
+
+// renderGolden renders goldenTasks and compares with testdata/golden/<name>.golden.
+// Set UPDATE_GOLDEN=1 to rewrite the file.
+func renderGolden(t *testing.T, name string, renderer *ViewRenderer) {
+	t.Helper()
+	renderer.ctx.Now = goldenNow
+
+	var sb strings.Builder
+	for _, task := range goldenTasks() {
+		sb.WriteString(renderer.RenderTask(task))
+	}
+	got := sb.String()
+
+	path := filepath.Join("testdata", "golden", name+".golden")
+	if os.Getenv("UPDATE_GOLDEN") != "" {
+		if err := os.WriteFile(path, []byte(got), 0644); err != nil {
+			t.Fatalf("Failed to write golden file: %v", err)
+		}
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read golden file: %v", err)
+	}
+	if got != string(want) {
+		t.Errorf("Output does not match %s\ngot:\n%s\nwant:\n%s", path, got, want)
+	}
+}
+
+func TestViewRenderer_AccessibleViewGolden(t *testing.T) {
+	view, err := getBuiltInView("accessible")
+	if err != nil {
+		t.Fatalf("Failed to load accessible view: %v", err)
+	}
+
+	renderGolden(t, "accessible", NewViewRenderer(view, nil, ""))
+}
+
+func TestViewRenderer_NoColorSemanticsGolden(t *testing.T) {
+	t.Setenv("NO_COLOR", "")
+
+	// The compact view relies on color for urgency; with no_color_semantics it must use words
+	view, err := getBuiltInView("compact")
+	if err != nil {
+		t.Fatalf("Failed to load compact view: %v", err)
+	}
+
+	renderer := NewViewRenderer(view, nil, "")
+	renderer.SetNoColorSemantics(true)
+	renderGolden(t, "compact_no_color_semantics", renderer)
+}
+
+func TestViewRenderer_NoColorEnv(t *testing.T) {
+	t.Setenv("NO_COLOR", "1")
+
+	view, err := getBuiltInView("compact")
+	if err != nil {
+		t.Fatalf("Failed to load compact view: %v", err)
+	}
+
+	renderer := NewViewRenderer(view, nil, "")
+	renderer.ctx.Now = goldenNow
+	out := renderer.RenderTask(goldenTasks()[0])
+
+	if strings.Contains(out, "\033[") {
+		t.Errorf("NO_COLOR output contains ANSI codes: %q", out)
+	}
+	if !strings.Contains(out, "OVERDUE") {
+		t.Errorf("NO_COLOR output missing OVERDUE marker: %q", out)
+	}
+}
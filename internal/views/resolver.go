@@ -16,7 +16,7 @@ var cacheMutex sync.RWMutex
 
 // ResolveView loads a view by name with the following priority:
 // 1. User views (~/.config/gosynctasks/views/<name>.yaml)
-// 2. Built-in views (basic, all, minimal, full, kanban, timeline, compact)
+// 2. Built-in views (basic, all, minimal, full, kanban, timeline, compact, accessible)
 //
 // Views are cached after first load for performance.
 func ResolveView(name string) (*View, error) {
@@ -93,7 +93,7 @@ func getBuiltInView(name string) (*View, error) {
 
 // GetBuiltInViews returns a list of built-in view names
 func GetBuiltInViews() []string {
-	return []string{"default", "all", "minimal", "full", "kanban", "timeline", "compact"}
+	return []string{"default", "all", "minimal", "full", "kanban", "timeline", "compact", "accessible"}
 }
 
 // IsBuiltInView checks if a view name is a built-in view
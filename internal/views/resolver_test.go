@@ -134,14 +134,14 @@ func TestInvalidateViewCache(t *testing.T) {
 func TestGetBuiltInViews(t *testing.T) {
 	views := GetBuiltInViews()
 
-	// Should have 7 built-in views
-	expectedCount := 7
+	// Should have 8 built-in views
+	expectedCount := 8
 	if len(views) != expectedCount {
 		t.Errorf("Expected %d built-in views, got %d", expectedCount, len(views))
 	}
 
 	// Check for all expected built-in views
-	expectedViews := []string{"default", "all", "minimal", "full", "kanban", "timeline", "compact"}
+	expectedViews := []string{"default", "all", "minimal", "full", "kanban", "timeline", "compact", "accessible"}
 	for _, expected := range expectedViews {
 		found := false
 		for _, name := range views {
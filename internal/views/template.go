@@ -0,0 +1,170 @@
+package views
+
+import (
+	"fmt"
+	"gosynctasks/backend"
+	"gosynctasks/internal/utils"
+	"io"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// TemplateContext is the data passed to --format-template templates.
+// All backend.Task fields (Summary, Status, DueDate, Priority, ...) are
+// available directly, alongside the computed helpers below.
+type TemplateContext struct {
+	backend.Task
+
+	// StatusSymbol is the plain status symbol: ✓ done, ● in progress, ✗ cancelled, ○ todo
+	StatusSymbol string
+
+	// DisplayStatus is the backend-independent status name (TODO, DONE, PROCESSING, CANCELLED)
+	DisplayStatus string
+
+	// Overdue is true when the task has a past due date and is not finished
+	Overdue bool
+
+	// Urgency is the calendar-day bucket of the due date of an unfinished
+	// task: overdue, today, tomorrow, this_week or later; empty otherwise
+	Urgency string
+
+	// List is the name of the list the task belongs to
+	List string
+
+	// IndentPrefix is the indentation for the task's depth in the hierarchy
+	IndentPrefix string
+}
+
+// templateColors maps color names accepted by the color helper to ANSI codes
+var templateColors = map[string]string{
+	"black":   "\033[30m",
+	"red":     "\033[31m",
+	"green":   "\033[32m",
+	"yellow":  "\033[33m",
+	"blue":    "\033[34m",
+	"magenta": "\033[35m",
+	"cyan":    "\033[36m",
+	"white":   "\033[37m",
+	"gray":    "\033[90m",
+	"bold":    "\033[1m",
+}
+
+// TemplateFuncs returns the helper functions available to format templates:
+//
+//	date LAYOUT VALUE   formats a time.Time or *time.Time (empty for nil)
+//	trunc N VALUE       truncates a string to N characters, adding "..."
+//	color NAME VALUE    wraps a string in an ANSI color (red, green, yellow, ...)
+//	sanitize VALUE      escapes control characters and expands tabs (fields are raw otherwise)
+func TemplateFuncs() template.FuncMap {
+	return template.FuncMap{
+		"date":     templateDate,
+		"trunc":    templateTrunc,
+		"color":    templateColor,
+		"sanitize": utils.SanitizeLine,
+	}
+}
+
+func templateDate(layout string, value interface{}) (string, error) {
+	switch t := value.(type) {
+	case time.Time:
+		if t.IsZero() {
+			return "", nil
+		}
+		return t.Format(layout), nil
+	case *time.Time:
+		if t == nil || t.IsZero() {
+			return "", nil
+		}
+		return t.Format(layout), nil
+	case nil:
+		return "", nil
+	}
+	return "", fmt.Errorf("date: unsupported value of type %T", value)
+}
+
+func templateTrunc(n int, s string) string {
+	runes := []rune(s)
+	if n <= 0 || len(runes) <= n {
+		return s
+	}
+	if n <= 3 {
+		return string(runes[:n])
+	}
+	return string(runes[:n-3]) + "..."
+}
+
+func templateColor(name string, s string) (string, error) {
+	code, ok := templateColors[strings.ToLower(name)]
+	if !ok {
+		return "", fmt.Errorf("color: unknown color %q", name)
+	}
+	return code + s + "\033[0m", nil
+}
+
+// ParseFormatTemplate parses a format template and checks it against a sample
+// task, so that both syntax errors and bad field references are reported
+// (with line:column position) before any task is printed.
+func ParseFormatTemplate(text string) (*template.Template, error) {
+	tmpl, err := template.New("format").Funcs(TemplateFuncs()).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid format template: %w", err)
+	}
+
+	now := time.Now()
+	sample := TemplateContext{
+		Task: backend.Task{
+			Summary: "sample",
+			Status:  "NEEDS-ACTION",
+			Created: now,
+			DueDate: &now,
+		},
+		StatusSymbol:  "○",
+		DisplayStatus: "TODO",
+	}
+	if err := tmpl.Execute(io.Discard, sample); err != nil {
+		return nil, fmt.Errorf("invalid format template: %w", err)
+	}
+
+	return tmpl, nil
+}
+
+// NewTemplateContext builds the template data for a task
+func NewTemplateContext(task backend.Task, taskManager backend.TaskManager, listName string, depth int) TemplateContext {
+	displayStatus := task.Status
+	if taskManager != nil {
+		displayStatus = taskManager.StatusToDisplayName(task.Status)
+	}
+
+	urgency := dueUrgency(task, displayStatus)
+	return TemplateContext{
+		Task:          task,
+		StatusSymbol:  statusSymbol(displayStatus),
+		DisplayStatus: displayStatus,
+		Overdue:       urgency == utils.DueOverdue.String(),
+		Urgency:       urgency,
+		List:          listName,
+		IndentPrefix:  strings.Repeat("  ", depth),
+	}
+}
+
+func statusSymbol(displayStatus string) string {
+	switch displayStatus {
+	case "DONE":
+		return "✓"
+	case "PROCESSING":
+		return "●"
+	case "CANCELLED":
+		return "✗"
+	default:
+		return "○"
+	}
+}
+
+// dueUrgency returns the urgency bucket of an unfinished task's due date
+func dueUrgency(task backend.Task, displayStatus string) string {
+	if task.DueDate == nil || displayStatus == "DONE" || displayStatus == "CANCELLED" {
+		return ""
+	}
+	return utils.ClassifyDue(*task.DueDate, time.Now(), utils.ActiveLocale().FirstWeekday).String()
+}
@@ -0,0 +1,85 @@
+package views
+
+import (
+	"gosynctasks/backend"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseFormatTemplate_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		wantErr  string
+	}{
+		{"syntax error reports position", "{{.Summary", "format:1:"},
+		{"unknown field reports position", "{{.Nope}}", "format:1:2"},
+		{"unknown color", `{{.Summary | color "plaid"}}`, "unknown color"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseFormatTemplate(tt.template)
+			if err == nil {
+				t.Fatal("ParseFormatTemplate() expected error")
+			}
+			if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("error = %q, want it to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestFormatTemplate_Execute(t *testing.T) {
+	due := time.Date(2026, 3, 5, 0, 0, 0, 0, time.UTC)
+	task := backend.Task{Summary: "Write the quarterly report", Status: "NEEDS-ACTION", DueDate: &due}
+
+	tests := []struct {
+		name     string
+		template string
+		task     backend.Task
+		want     string
+	}{
+		{
+			name:     "symbol, summary and due date",
+			template: `{{.StatusSymbol}} {{.Summary}} {{if .DueDate}}({{.DueDate | date "Jan 2"}}){{end}}`,
+			task:     task,
+			want:     "○ Write the quarterly report (Mar 5)",
+		},
+		{
+			name:     "missing due date",
+			template: `{{.Summary}}{{if .DueDate}} ({{.DueDate | date "Jan 2"}}){{end}}`,
+			task:     backend.Task{Summary: "No date"},
+			want:     "No date",
+		},
+		{
+			name:     "trunc and computed fields",
+			template: `{{.IndentPrefix}}{{.Summary | trunc 10}} {{.Overdue}} {{.List}}`,
+			task:     task,
+			want:     "  Write t... true Work",
+		},
+		{
+			name:     "color",
+			template: `{{.Summary | color "green"}}`,
+			task:     backend.Task{Summary: "ok"},
+			want:     "\033[32mok\033[0m",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpl, err := ParseFormatTemplate(tt.template)
+			if err != nil {
+				t.Fatalf("ParseFormatTemplate() error = %v", err)
+			}
+			var sb strings.Builder
+			if err := tmpl.Execute(&sb, NewTemplateContext(tt.task, nil, "Work", 1)); err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+			if sb.String() != tt.want {
+				t.Errorf("got %q, want %q", sb.String(), tt.want)
+			}
+		})
+	}
+}
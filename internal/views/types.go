@@ -1,6 +1,10 @@
 package views
 
-import "time"
+import (
+	"time"
+
+	"gosynctasks/backend"
+)
 
 // View represents a custom view configuration for displaying tasks.
 // Views are stored as YAML files in ~/.config/gosynctasks/views/
@@ -28,7 +32,7 @@ type View struct {
 // FieldConfig specifies how to display a single task field
 type FieldConfig struct {
 	// Name is the field identifier (e.g., "status", "summary", "priority")
-	Name string `yaml:"name" validate:"required,oneof=status summary description priority due_date start_date created modified completed tags uid parent"`
+	Name string `yaml:"name" validate:"required,task_field"`
 
 	// Format specifies the display format for this field
 	// Available formats depend on the field type (see FieldDefinition)
@@ -114,6 +118,9 @@ type DisplayOptions struct {
 
 	// SortOrder specifies ascending or descending order
 	SortOrder string `yaml:"sort_order,omitempty" validate:"omitempty,oneof=asc desc"`
+
+	// NoColorSemantics shows urgency and priority as text instead of color
+	NoColorSemantics bool `yaml:"no_color_semantics,omitempty"`
 }
 
 // FieldDefinition describes a task field's available formats
@@ -132,4 +139,10 @@ type FieldDefinition struct {
 
 	// RequiresBackend indicates if this field needs backend-specific rendering
 	RequiresBackend bool
+
+	// Sortable indicates tasks can be sorted by this field
+	Sortable bool
+
+	// Value returns the field of a task for machine-readable output
+	Value func(task backend.Task) any
 }
@@ -190,16 +190,7 @@ func ValidateField(field *FieldConfig) error {
 	// Validate field name exists in registry
 	def, ok := GetFieldDefinition(field.Name)
 	if !ok {
-		validFields := []string{}
-		for name := range FieldRegistry {
-			validFields = append(validFields, name)
-		}
-		return &ValidationError{
-			Field:   "name",
-			Message: fmt.Sprintf("unknown field '%s'", field.Name),
-			Value:   field.Name,
-			Hint:    fmt.Sprintf("Valid fields: %s", strings.Join(validFields, ", ")),
-		}
+		return unknownFieldError(field.Name)
 	}
 
 	// Validate format if specified
@@ -260,7 +251,7 @@ func ValidateDisplayOptions(opts *DisplayOptions) error {
 
 	// Validate sort_by if specified
 	if opts.SortBy != "" {
-		validSortFields := []string{"status", "summary", "priority", "due_date", "start_date", "created", "modified"}
+		validSortFields := SortFieldNames()
 		valid := false
 		for _, validField := range validSortFields {
 			if opts.SortBy == validField {